@@ -0,0 +1,148 @@
+// Package alerts implements the app operator alerts for operational
+// events like 5xx errors spikes, low disk space and TLS certificate failures.
+//
+// The disk space and data size alerts are sent by the guardrails package.
+package alerts
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/alert"
+	"github.com/pocketbase/pocketbase/tools/routine"
+	"github.com/spf13/cast"
+)
+
+// list with the built-in alert types
+const (
+	TypeErrorsSpike        string = "errorsSpike"
+	TypeLowDiskSpace       string = "lowDiskSpace"
+	TypeDataSizeLimit      string = "dataSizeLimit"
+	TypeCertificateFailure string = "certificateFailure"
+)
+
+const (
+	lastSentCacheKeyPrefix = "alertsLastSent_"
+	errorsCounterCacheKey  = "alertsErrorsCounter"
+)
+
+var mux sync.Mutex
+
+// Send sends the provided alert to the configured app alerts channels.
+//
+// The alert is silently skipped if the alerts are disabled or if
+// another alert of the same type was sent within the configured cooldown.
+//
+// It could be used also for custom alert types, eg.:
+//
+//	alerts.Send(app, alert.New("myJobFailure", "Job failure", err.Error()))
+func Send(app core.App, a *alert.Alert) error {
+	config := app.Settings().Alerts
+	if !config.Enabled {
+		return nil
+	}
+
+	if !acquireCooldown(app, a.Type, time.Duration(config.Cooldown)*time.Minute) {
+		return nil
+	}
+
+	event := &core.AlertEvent{
+		Notifier: app.NewAlertNotifier(),
+		Alert:    a,
+	}
+
+	return app.OnBeforeAlertSend().Trigger(event, func(e *core.AlertEvent) error {
+		if e.Notifier == nil {
+			return nil
+		}
+
+		return e.Notifier.Notify(e.Alert)
+	})
+}
+
+// TrackServerError registers a new 5xx API error and sends (in a separate
+// go routine) an errors spike alert if the configured threshold is reached.
+func TrackServerError(app core.App) {
+	config := app.Settings().Alerts
+	if !config.Enabled || config.ErrorsThreshold <= 0 || config.ErrorsWindow <= 0 {
+		return
+	}
+
+	window := time.Duration(config.ErrorsWindow) * time.Minute
+
+	mux.Lock()
+	counter, _ := app.Cache().Get(errorsCounterCacheKey).(*alert.WindowCounter)
+	if counter == nil || counter.Window() != window {
+		counter = alert.NewWindowCounter(window)
+		app.Cache().Set(errorsCounterCacheKey, counter)
+	}
+	mux.Unlock()
+
+	total := counter.Hit()
+	if total < config.ErrorsThreshold {
+		return
+	}
+	counter.Reset()
+
+	a := alert.New(
+		TypeErrorsSpike,
+		Subject(app, "Server errors spike"),
+		fmt.Sprintf("%d server errors (5xx) within the last %d minute(s).", total, config.ErrorsWindow),
+	)
+	a.Data["total"] = total
+	a.Data["window"] = config.ErrorsWindow
+
+	sendInBackground(app, a)
+}
+
+// CertificateFailure sends (in a separate go routine) a TLS
+// certificate obtain/renewal failure alert for the specified host.
+func CertificateFailure(app core.App, host string, err error) {
+	a := alert.New(
+		TypeCertificateFailure,
+		Subject(app, "TLS certificate failure"),
+		fmt.Sprintf("Failed to obtain or renew the %q TLS certificate: %v", host, err),
+	)
+	a.Data["host"] = host
+
+	sendInBackground(app, a)
+}
+
+// -------------------------------------------------------------------
+
+func sendInBackground(app core.App, a *alert.Alert) {
+	routine.FireAndForget(func() {
+		if err := Send(app, a); err != nil && app.IsDebug() {
+			log.Println("Alert send failed:", err)
+		}
+	})
+}
+
+// acquireCooldown checks whether an alert of the specified type could be
+// sent and if so, marks the current time as the type's last sent date.
+func acquireCooldown(app core.App, alertType string, cooldown time.Duration) bool {
+	mux.Lock()
+	defer mux.Unlock()
+
+	key := lastSentCacheKeyPrefix + alertType
+
+	if cooldown > 0 {
+		lastSent := cast.ToTime(app.Cache().Get(key))
+		if time.Since(lastSent) < cooldown {
+			return false
+		}
+	}
+
+	app.Cache().Set(key, time.Now())
+
+	return true
+}
+
+// Subject returns the alert subject for the specified
+// title, prefixed with the app name (eg. "[Acme] title").
+func Subject(app core.App, title string) string {
+	return fmt.Sprintf("[%s] %s", app.Settings().Meta.AppName, title)
+}
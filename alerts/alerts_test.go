@@ -0,0 +1,162 @@
+package alerts_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/alerts"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/alert"
+)
+
+func TestSend(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	sent := tests.CaptureAlerts(app)
+
+	// disabled
+	if err := alerts.Send(app, alert.New("test", "test", "test")); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 0 {
+		t.Fatalf("Expected no sent alerts, got %d", len(sent))
+	}
+
+	tests.EnableAlerts(app)
+	app.Settings().Alerts.Cooldown = 10
+
+	// enabled
+	if err := alerts.Send(app, alert.New("test1", "test", "test")); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("Expected 1 sent alert, got %d", len(sent))
+	}
+
+	// same type within the cooldown
+	if err := alerts.Send(app, alert.New("test1", "test", "test")); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("Expected the cooldown to skip the alert, got %d sent alerts", len(sent))
+	}
+
+	// different type
+	if err := alerts.Send(app, alert.New("test2", "test", "test")); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 2 {
+		t.Fatalf("Expected 2 sent alerts, got %d", len(sent))
+	}
+
+	// no cooldown
+	app.Settings().Alerts.Cooldown = 0
+	if err := alerts.Send(app, alert.New("test1", "test", "test")); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 3 {
+		t.Fatalf("Expected 3 sent alerts, got %d", len(sent))
+	}
+}
+
+func TestSendWithDefaultNotifier(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	var body map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		json.Unmarshal(raw, &body)
+	}))
+	defer server.Close()
+
+	app.Settings().Alerts.Enabled = true
+	app.Settings().Alerts.WebhookUrl = server.URL
+
+	if err := alerts.Send(app, alert.New("test", "test_subject", "test_message")); err != nil {
+		t.Fatal(err)
+	}
+
+	if body["subject"] != "test_subject" {
+		t.Fatalf("Expected subject %q, got %v", "test_subject", body["subject"])
+	}
+
+	if app.EventCalls["OnBeforeAlertSend"] != 1 {
+		t.Fatalf("Expected OnBeforeAlertSend to be called once, got %d", app.EventCalls["OnBeforeAlertSend"])
+	}
+}
+
+func TestTrackServerError(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	sent := tests.CaptureAlerts(app)
+
+	tests.EnableAlerts(app)
+	app.Settings().Alerts.Cooldown = 0
+	app.Settings().Alerts.ErrorsThreshold = 3
+	app.Settings().Alerts.ErrorsWindow = 1
+
+	for i := 0; i < 2; i++ {
+		alerts.TrackServerError(app)
+	}
+
+	select {
+	case a := <-sent:
+		t.Fatalf("Didn't expect alert before reaching the threshold, got %v", a)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	alerts.TrackServerError(app)
+
+	select {
+	case a := <-sent:
+		if a.Type != alerts.TypeErrorsSpike {
+			t.Fatalf("Expected %q alert, got %q", alerts.TypeErrorsSpike, a.Type)
+		}
+		if a.Data["total"] != 3 {
+			t.Fatalf("Expected 3 total errors, got %v", a.Data["total"])
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected errors spike alert")
+	}
+
+	// the counter should be reset after the alert
+	alerts.TrackServerError(app)
+
+	select {
+	case a := <-sent:
+		t.Fatalf("Didn't expect alert after the counter reset, got %v", a)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestCertificateFailure(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	sent := tests.CaptureAlerts(app)
+
+	tests.EnableAlerts(app)
+
+	alerts.CertificateFailure(app, "example.com", errors.New("test"))
+
+	select {
+	case a := <-sent:
+		if a.Type != alerts.TypeCertificateFailure {
+			t.Fatalf("Expected %q alert, got %q", alerts.TypeCertificateFailure, a.Type)
+		}
+		if a.Data["host"] != "example.com" {
+			t.Fatalf("Expected example.com host, got %v", a.Data["host"])
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected certificate failure alert")
+	}
+}
@@ -67,10 +67,11 @@ func (api *adminApi) authWithPassword(c echo.Context) error {
 	if readErr := c.Bind(form); readErr != nil {
 		return NewBadRequestError("An error occurred while loading the submitted data.", readErr)
 	}
+	form.SetIp(loginRequestIp(api.app, c))
 
 	admin, submitErr := form.Submit()
 	if submitErr != nil {
-		return NewBadRequestError("Failed to authenticate.", submitErr)
+		return loginSubmitError(c, submitErr)
 	}
 
 	return api.authResponse(c, admin)
@@ -175,7 +175,7 @@ func TestAdminConfirmPasswordReset(t *testing.T) {
 				"passwordConfirm":"1234567890"
 			}`),
 			ExpectedStatus:  400,
-			ExpectedContent: []string{`"data":{"token":{"code":"validation_invalid_token","message":"Invalid or expired token."}}}`},
+			ExpectedContent: []string{`"data":{"token":{"code":"validation_invalid_token","message":"Invalid or expired token."}}`},
 		},
 		{
 			Name:   "valid token + invalid password",
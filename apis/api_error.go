@@ -14,6 +14,9 @@ type ApiError struct {
 	Message string         `json:"message"`
 	Data    map[string]any `json:"data"`
 
+	// RequestId is the tracing id of the failed request (if any).
+	RequestId string `json:"requestId,omitempty"`
+
 	// stores unformatted error data (could be an internal error, text, etc.)
 	rawData any
 }
@@ -64,6 +67,15 @@ func NewUnauthorizedError(message string, data any) *ApiError {
 	return NewApiError(http.StatusUnauthorized, message, data)
 }
 
+// NewConflictError creates and returns 409 `ApiError`.
+func NewConflictError(message string, data any) *ApiError {
+	if message == "" {
+		message = "The resource was modified in the meantime."
+	}
+
+	return NewApiError(http.StatusConflict, message, data)
+}
+
 // NewApiError creates and returns new normalized `ApiError` instance.
 func NewApiError(status int, message string, data any) *ApiError {
 	message = inflector.Sentenize(message)
@@ -0,0 +1,113 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// bindApiKeyApi registers the api keys api endpoints and the corresponding handlers.
+func bindApiKeyApi(app core.App, rg *echo.Group) {
+	api := apiKeyApi{app: app}
+
+	subGroup := rg.Group("/api-keys", ActivityLogger(app), RequireAdminAuth())
+	subGroup.GET("", api.list)
+	subGroup.POST("", api.create)
+	subGroup.GET("/:id", api.view)
+	subGroup.PATCH("/:id", api.update)
+	subGroup.DELETE("/:id", api.delete)
+}
+
+type apiKeyApi struct {
+	app core.App
+}
+
+// apiKeyResponse is the api key upsert response that
+// also contains the newly generated plain key (if any).
+type apiKeyResponse struct {
+	*models.ApiKey
+	Key string `json:"key,omitempty"`
+}
+
+func (api *apiKeyApi) list(c echo.Context) error {
+	apiKeys, err := api.app.Dao().FindAllApiKeys()
+	if err != nil {
+		return NewBadRequestError("Failed to load the api keys.", err)
+	}
+
+	return c.JSON(http.StatusOK, apiKeys)
+}
+
+func (api *apiKeyApi) view(c echo.Context) error {
+	apiKey, err := api.app.Dao().FindApiKeyById(c.PathParam("id"))
+	if err != nil || apiKey == nil {
+		return NewNotFoundError("", err)
+	}
+
+	return c.JSON(http.StatusOK, apiKey)
+}
+
+func (api *apiKeyApi) create(c echo.Context) error {
+	apiKey := &models.ApiKey{}
+
+	form := forms.NewApiKeyUpsert(api.app, apiKey)
+
+	// load request
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	submitErr := form.Submit(func(next forms.InterceptorNextFunc) forms.InterceptorNextFunc {
+		return func() error {
+			if err := next(); err != nil {
+				return NewBadRequestError("Failed to create api key.", err)
+			}
+
+			return c.JSON(http.StatusOK, apiKeyResponse{apiKey, form.PlainKey()})
+		}
+	})
+
+	return submitErr
+}
+
+func (api *apiKeyApi) update(c echo.Context) error {
+	apiKey, err := api.app.Dao().FindApiKeyById(c.PathParam("id"))
+	if err != nil || apiKey == nil {
+		return NewNotFoundError("", err)
+	}
+
+	form := forms.NewApiKeyUpsert(api.app, apiKey)
+
+	// load request
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	submitErr := form.Submit(func(next forms.InterceptorNextFunc) forms.InterceptorNextFunc {
+		return func() error {
+			if err := next(); err != nil {
+				return NewBadRequestError("Failed to update api key.", err)
+			}
+
+			return c.JSON(http.StatusOK, apiKeyResponse{apiKey, form.PlainKey()})
+		}
+	})
+
+	return submitErr
+}
+
+func (api *apiKeyApi) delete(c echo.Context) error {
+	apiKey, err := api.app.Dao().FindApiKeyById(c.PathParam("id"))
+	if err != nil || apiKey == nil {
+		return NewNotFoundError("", err)
+	}
+
+	if err := api.app.Dao().DeleteApiKey(apiKey); err != nil {
+		return NewBadRequestError("Failed to delete api key.", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
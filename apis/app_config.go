@@ -0,0 +1,76 @@
+package apis
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tokens"
+)
+
+// AppConfigTokenHeader is the app-config response header with the
+// signature token of the response body (see [tokens.NewAppConfigToken]).
+const AppConfigTokenHeader = "X-PocketBase-Config-Token"
+
+// bindAppConfigApi registers the public app-config api endpoint.
+func bindAppConfigApi(app core.App, rg *echo.Group) {
+	api := appConfigApi{app: app}
+
+	rg.GET("/app-config", api.view)
+}
+
+type appConfigApi struct {
+	app core.App
+}
+
+// view returns the admin curated public client configuration.
+//
+// The response body is signed with the active token signing key
+// (verifiable with the published JWKS) if token signing is enabled.
+func (api *appConfigApi) view(c echo.Context) error {
+	settings := api.app.Settings()
+
+	providers := []string{}
+	for name, config := range settings.NamedAuthProviderConfigs() {
+		if config.Enabled {
+			providers = append(providers, name)
+		}
+	}
+	sort.Strings(providers)
+
+	flags := settings.Client.Flags
+	if flags == nil {
+		flags = map[string]bool{}
+	}
+
+	public := settings.Client.Public
+	if public == nil {
+		public = map[string]any{}
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"appName":          settings.Meta.AppName,
+		"appUrl":           settings.Meta.AppUrl,
+		"authProviders":    providers,
+		"flags":            flags,
+		"minClientVersion": settings.Client.MinVersion,
+		"public":           public,
+	})
+	if err != nil {
+		return NewBadRequestError("Failed to load the app config.", err)
+	}
+
+	if settings.TokenSigning.Enabled {
+		token, err := tokens.NewAppConfigToken(api.app, body)
+		if err != nil {
+			return NewBadRequestError("Failed to sign the app config.", err)
+		}
+		c.Response().Header().Set(AppConfigTokenHeader, token)
+	}
+
+	c.Response().Header().Set("Cache-Control", "no-cache")
+
+	return c.JSONBlob(http.StatusOK, body)
+}
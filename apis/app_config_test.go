@@ -0,0 +1,105 @@
+package apis_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tokens"
+	"github.com/pocketbase/pocketbase/tools/signature"
+)
+
+func TestAppConfig(t *testing.T) {
+	scenarios := []tests.ApiScenario{
+		{
+			Name:           "default config",
+			Method:         http.MethodGet,
+			Url:            "/api/app-config",
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"appName":"acme_test"`,
+				`"appUrl":"http://localhost:8090"`,
+				`"authProviders":[`,
+				`"flags":{}`,
+				`"minClientVersion":""`,
+				`"public":{}`,
+			},
+		},
+		{
+			Name:   "curated config",
+			Method: http.MethodGet,
+			Url:    "/api/app-config",
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				app.Settings().GoogleAuth.Enabled = true
+				app.Settings().GithubAuth.Enabled = true
+				app.Settings().Client.MinVersion = "1.2.0"
+				app.Settings().Client.Flags = map[string]bool{"newCheckout": true, "darkMode": false}
+				app.Settings().Client.Public = map[string]any{"supportEmail": "support@example.com"}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"flags":{"darkMode":false,"newCheckout":true}`,
+				`"minClientVersion":"1.2.0"`,
+				`"public":{"supportEmail":"support@example.com"}`,
+				`"github"`,
+				`"google"`,
+			},
+			NotExpectedContent: []string{
+				`"secret"`,
+				`"clientSecret"`,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestAppConfigSignatures(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	e, err := apis.InitApi(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/app-config", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d (%s)", rec.Code, rec.Body.String())
+		}
+		return rec
+	}
+
+	// disabled signing
+	rec := request()
+	if v := rec.Header().Get(apis.AppConfigTokenHeader); v != "" {
+		t.Fatalf("Expected no config token header, got %q", v)
+	}
+	if v := rec.Header().Get(signature.Header); v != "" {
+		t.Fatalf("Expected no signature header, got %q", v)
+	}
+
+	// enabled signing
+	tests.EnableTokenSigning(t, app)
+	app.Settings().Signing.Enabled = true
+
+	rec = request()
+	body := rec.Body.Bytes()
+
+	if err := tokens.VerifyAppConfigToken(app, rec.Header().Get(apis.AppConfigTokenHeader), body); err != nil {
+		t.Fatalf("Expected valid config token, got %v", err)
+	}
+
+	// the payload signing secret is server-only and must never be used
+	// to sign the public config
+	if v := rec.Header().Get(signature.Header); v != "" {
+		t.Fatalf("Expected no signature header, got %q", v)
+	}
+}
@@ -0,0 +1,47 @@
+package apis
+
+import (
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+)
+
+// bindAppleApi registers the Sign in with Apple api endpoints.
+func bindAppleApi(app core.App, rg *echo.Group) {
+	api := appleApi{app: app}
+
+	subGroup := rg.Group("/apple")
+	subGroup.POST("/notifications", api.notifications)
+}
+
+type appleApi struct {
+	app core.App
+}
+
+// notifications handles the Apple server-to-server notifications
+// (the url has to be registered in the Apple Developer account).
+func (api *appleApi) notifications(c echo.Context) error {
+	if !api.app.Settings().AppleAuth.Enabled {
+		return NewNotFoundError("", nil)
+	}
+
+	form := forms.NewAppleNotification(api.app)
+
+	// load request
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", err)
+	}
+
+	if _, err := form.Submit(); err != nil {
+		if fErr, ok := err.(validation.Errors); ok {
+			return NewBadRequestError("Invalid notification payload.", fErr)
+		}
+
+		return NewBadRequestError("Failed to process the notification.", err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
@@ -0,0 +1,111 @@
+package apis_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/auth"
+)
+
+func TestAppleNotifications(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+
+	keysServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"keys":[{"kty":"RSA","kid":"test","n":"` + n + `","e":"` + e + `"}]}`))
+	}))
+	defer keysServer.Close()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":    auth.AppleIssuer,
+		"aud":    "test_client",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"events": `{"type":"consent-revoked","sub":"apple_123"}`,
+	})
+	token.Header["kid"] = "test"
+	payload, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enableAppleAuth := func(t *testing.T, app *tests.TestApp) {
+		app.Settings().AppleAuth.Enabled = true
+		app.Settings().AppleAuth.ClientId = "test_client"
+		app.Settings().AppleAuth.ClientSecret = "test_secret"
+		app.Settings().AppleAuth.UserApiUrl = keysServer.URL
+
+		rel := &models.ExternalAuth{
+			CollectionId: "_pb_users_auth_",
+			RecordId:     "4q1xlclmfloku33",
+			Provider:     auth.NameApple,
+			ProviderId:   "apple_123",
+		}
+		if err := daos.New(app.Dao().DB()).SaveExternalAuth(rel); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "disabled Apple auth",
+			Method:          http.MethodPost,
+			Url:             "/api/apple/notifications",
+			Body:            strings.NewReader(`{"payload":"` + payload + `"}`),
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "invalid payload",
+			Method: http.MethodPost,
+			Url:    "/api/apple/notifications",
+			Body:   strings.NewReader(`{"payload":"invalid"}`),
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				enableAppleAuth(t, app)
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "consent revoked",
+			Method: http.MethodPost,
+			Url:    "/api/apple/notifications",
+			Body:   strings.NewReader(`{"payload":"` + payload + `"}`),
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				enableAppleAuth(t, app)
+			},
+			ExpectedStatus: 200,
+			ExpectedEvents: map[string]int{
+				"OnModelBeforeDelete": 1,
+				"OnModelAfterDelete":  1,
+				"OnModelBeforeUpdate": 1,
+				"OnModelAfterUpdate":  1,
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				if _, err := app.Dao().FindExternalAuthByProvider(auth.NameApple, "apple_123"); err == nil {
+					t.Fatal("Expected the Apple external auth to be deleted")
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
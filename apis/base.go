@@ -13,13 +13,41 @@ import (
 
 	"github.com/labstack/echo/v5"
 	"github.com/labstack/echo/v5/middleware"
+	"github.com/pocketbase/pocketbase/alerts"
 	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/guardrails"
+	"github.com/pocketbase/pocketbase/tools/reporter"
 	"github.com/pocketbase/pocketbase/ui"
 	"github.com/spf13/cast"
 )
 
 const trailedAdminPath = "/_/"
 
+// Ids of the built-in api middlewares registered in [core.ServeEvent.Middlewares].
+const (
+	MiddlewareIdRecover           = "pbRecover"
+	MiddlewareIdErrorReporter     = "pbErrorReporter"
+	MiddlewareIdSecure            = "pbSecure"
+	MiddlewareIdSecurityHeaders   = "pbSecurityHeaders"
+	MiddlewareIdLoadAuthContext   = "pbLoadAuthContext"
+	MiddlewareIdLoadApiKeyContext = "pbLoadApiKeyContext"
+	MiddlewareIdReadOnlyGuard     = "pbReadOnlyGuard"
+)
+
+// Priorities of the built-in api middlewares.
+//
+// The custom middlewares are registered by default with 0 priority
+// (aka. they are executed after all built-in ones).
+const (
+	MiddlewarePriorityRecover           = -700
+	MiddlewarePriorityErrorReporter     = -600
+	MiddlewarePrioritySecure            = -500
+	MiddlewarePrioritySecurityHeaders   = -400
+	MiddlewarePriorityLoadAuthContext   = -300
+	MiddlewarePriorityLoadApiKeyContext = -200
+	MiddlewarePriorityReadOnlyGuard     = -100
+)
+
 // InitApi creates a configured echo instance with registered
 // system and app specific routes and middlewares.
 func InitApi(app core.App) (*echo.Echo, error) {
@@ -27,15 +55,31 @@ func InitApi(app core.App) (*echo.Echo, error) {
 	e.Debug = app.IsDebug()
 
 	// default middlewares
+	e.Pre(LoadRequestId())
 	e.Pre(middleware.RemoveTrailingSlashWithConfig(middleware.RemoveTrailingSlashConfig{
 		Skipper: func(c echo.Context) bool {
 			// ignore Admin UI route(s)
 			return strings.HasPrefix(c.Request().URL.Path, trailedAdminPath)
 		},
 	}))
-	e.Use(middleware.Recover())
-	e.Use(middleware.Secure())
-	e.Use(LoadAuthContext(app))
+
+	// prioritized middlewares (could be adjusted in the OnBeforeServe hook)
+	middlewares := core.NewMiddlewaresRegistry()
+	defaultMiddlewares := []*core.Middleware{
+		{Id: MiddlewareIdRecover, Priority: MiddlewarePriorityRecover, Func: middleware.Recover()},
+		{Id: MiddlewareIdErrorReporter, Priority: MiddlewarePriorityErrorReporter, Func: ErrorReporter(app)},
+		{Id: MiddlewareIdSecure, Priority: MiddlewarePrioritySecure, Func: middleware.Secure()},
+		{Id: MiddlewareIdSecurityHeaders, Priority: MiddlewarePrioritySecurityHeaders, Func: SecurityHeaders(app)},
+		{Id: MiddlewareIdLoadAuthContext, Priority: MiddlewarePriorityLoadAuthContext, Func: LoadAuthContext(app)},
+		{Id: MiddlewareIdLoadApiKeyContext, Priority: MiddlewarePriorityLoadApiKeyContext, Func: LoadApiKeyContext(app)},
+		{Id: MiddlewareIdReadOnlyGuard, Priority: MiddlewarePriorityReadOnlyGuard, Func: ReadOnlyGuard(app)},
+	}
+	for _, m := range defaultMiddlewares {
+		if err := middlewares.Add(m); err != nil {
+			return nil, err
+		}
+	}
+	e.Use(middlewares.Middleware(core.MiddlewareScopeAll))
 
 	// custom error handler
 	e.HTTPErrorHandler = func(c echo.Context, err error) {
@@ -64,6 +108,20 @@ func InitApi(app core.App) (*echo.Echo, error) {
 			apiErr = NewBadRequestError("", err)
 		}
 
+		if requestId, _ := c.Get(ContextRequestIdKey).(string); requestId != "" {
+			apiErr.RequestId = requestId
+		}
+
+		// the read-only mode rejections are expected and are not reported
+		if apiErr.Code >= 500 && !(apiErr.Code == http.StatusServiceUnavailable && guardrails.IsReadOnly(app)) {
+			report := reporter.NewEvent(reporter.LevelError, apiErr.Message)
+			report.Error = err
+			report.Stacktrace = reporter.NewStacktrace(1)
+			reportError(app, c, report)
+
+			alerts.TrackServerError(app)
+		}
+
 		event := &core.ApiErrorEvent{
 			HttpContext: c,
 			Error:       apiErr,
@@ -90,23 +148,44 @@ func InitApi(app core.App) (*echo.Echo, error) {
 	// admin ui routes
 	bindStaticAdminUI(app, e)
 
+	// openid connect discovery routes
+	bindJwksApi(app, e)
+
 	// default routes
 	api := e.Group("/api")
+
+	// route groups with the scoped registry middlewares
+	recordsApi := api.Group("", middlewares.Middleware(core.MiddlewareScopeRecords))
+	adminsApi := api.Group("", middlewares.Middleware(core.MiddlewareScopeAdmins))
+	filesApi := api.Group("", middlewares.Middleware(core.MiddlewareScopeFiles))
+
 	bindSettingsApi(app, api)
-	bindAdminApi(app, api)
+	bindAdminApi(app, adminsApi)
 	bindCollectionApi(app, api)
-	bindRecordCrudApi(app, api)
-	bindRecordAuthApi(app, api)
-	bindFileApi(app, api)
+	bindBlueprintApi(app, api)
+	bindRecordCrudApi(app, recordsApi)
+	bindSavedFilterApi(app, api)
+	bindRecordPreferencesApi(app, api)
+	bindRoleApi(app, api)
+	bindApiKeyApi(app, api)
+	bindReportApi(app, api)
+	bindStorageApi(app, api)
+	bindBatchApi(app, api)
+	bindRecordAuthApi(app, recordsApi)
+	bindAppleApi(app, api)
+	bindFileApi(app, filesApi)
 	bindRealtimeApi(app, api)
 	bindLogsApi(app, api)
 	bindHealthApi(app, api)
+	bindAppConfigApi(app, api)
 
 	// trigger the custom BeforeServe hook for the created api router
-	// allowing users to further adjust its options or register new routes
+	// allowing users to further adjust its options, register new routes
+	// or middlewares
 	serveEvent := &core.ServeEvent{
-		App:    app,
-		Router: e,
+		App:         app,
+		Router:      e,
+		Middlewares: middlewares,
 	}
 	if err := app.OnBeforeServe().Trigger(serveEvent); err != nil {
 		return nil, err
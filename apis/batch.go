@@ -0,0 +1,447 @@
+package apis
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/resolvers"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/spf13/cast"
+)
+
+// Supported batch operation actions.
+const (
+	BatchActionCreate = "create"
+	BatchActionUpdate = "update"
+	BatchActionDelete = "delete"
+)
+
+// BatchMaxOperations is the max allowed number of operations in a single batch request.
+const BatchMaxOperations = 50
+
+// bindBatchApi registers the batch api endpoint.
+func bindBatchApi(app core.App, rg *echo.Group) {
+	api := batchApi{app: app}
+
+	subGroup := rg.Group("/batch", ActivityLogger(app))
+	subGroup.POST("", api.submit)
+}
+
+type batchApi struct {
+	app core.App
+}
+
+// batchRequest defines the batch api request body.
+type batchRequest struct {
+	Operations []*batchOperation `json:"operations"`
+}
+
+// Validate implements [validation.Validatable] interface.
+func (r batchRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Operations, validation.Required, validation.Length(1, BatchMaxOperations)),
+	)
+}
+
+// batchOperation defines a single batch record create, update or delete operation.
+type batchOperation struct {
+	Action     string         `json:"action"`
+	Collection string         `json:"collection"`
+	Id         string         `json:"id"`
+	Data       map[string]any `json:"data"`
+}
+
+// Validate implements [validation.Validatable] interface.
+func (o batchOperation) Validate() error {
+	return validation.ValidateStruct(&o,
+		validation.Field(
+			&o.Action,
+			validation.Required,
+			validation.In(BatchActionCreate, BatchActionUpdate, BatchActionDelete),
+		),
+		validation.Field(&o.Collection, validation.Required),
+		validation.Field(&o.Id, validation.When(o.Action != BatchActionCreate, validation.Required)),
+	)
+}
+
+// submit executes the submitted records operations in a single
+// transaction (aka. either all of them succeed or none of them is applied).
+//
+// Each operation is checked against its collection API rule and
+// triggers the same record request hooks as the regular records crud api.
+// Note that the before request hooks are executed inside the batch transaction.
+//
+// Returns the created/updated records (or null for the deleted ones)
+// in the same order as the submitted operations.
+func (api *batchApi) submit(c echo.Context) error {
+	body := &batchRequest{}
+	if err := c.Bind(body); err != nil {
+		return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	if err := body.Validate(); err != nil {
+		return NewBadRequestError("Invalid batch request.", err)
+	}
+
+	results := make([]*models.Record, len(body.Operations))
+
+	// executed only after successful commit
+	afterCalls := []func(){}
+
+	// keep the realtime request id tracking until the transaction
+	// is committed since the model after hooks are deferred
+	untrackCalls := []func(){}
+	defer func() {
+		for _, untrack := range untrackCalls {
+			untrack()
+		}
+	}()
+
+	txErr := api.app.Dao().RunInTransaction(func(txDao *daos.Dao) error {
+		for i, op := range body.Operations {
+			record, afterCall, err := api.execOperation(c, txDao, op)
+			if err != nil {
+				return batchOperationError(i, err)
+			}
+
+			if record != nil {
+				untrackCalls = append(untrackCalls, trackRecordRequestId(c, record))
+			}
+
+			results[i] = record
+			afterCalls = append(afterCalls, afterCall)
+		}
+
+		return nil
+	})
+
+	if txErr != nil {
+		return txErr
+	}
+
+	for _, afterCall := range afterCalls {
+		afterCall()
+	}
+
+	for _, record := range results {
+		if record == nil {
+			continue // deleted
+		}
+
+		if err := EnrichRecord(c, api.app.Dao(), record); err != nil && api.app.IsDebug() {
+			log.Println(err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, results)
+}
+
+// execOperation executes a single batch operation with the provided
+// transaction dao and returns the affected record (nil for delete)
+// and a function that triggers the after request hooks.
+func (api *batchApi) execOperation(c echo.Context, txDao *daos.Dao, op *batchOperation) (*models.Record, func(), error) {
+	collection, err := txDao.FindCollectionByNameOrId(op.Collection)
+	if err != nil || collection == nil {
+		return nil, nil, NewNotFoundError("Missing collection context.", err)
+	}
+
+	if err := checkApiKeyScope(c, collection); err != nil {
+		return nil, nil, err
+	}
+
+	switch op.Action {
+	case BatchActionCreate:
+		// update the record with the same external id (if any)
+		// to allow idempotent imports from external systems
+		if externalId := cast.ToString(op.Data[schema.FieldNameExternalId]); externalId != "" && collection.ExternalIdOptions().Enabled {
+			if existing, err := txDao.FindRecordByExternalId(collection.Id, externalId); err == nil {
+				op.Id = existing.Id
+				return api.execUpdate(c, txDao, collection, op)
+			}
+		}
+
+		return api.execCreate(c, txDao, collection, op)
+	case BatchActionUpdate:
+		return api.execUpdate(c, txDao, collection, op)
+	default:
+		return api.execDelete(c, txDao, collection, op)
+	}
+}
+
+func (api *batchApi) execCreate(
+	c echo.Context,
+	txDao *daos.Dao,
+	collection *models.Collection,
+	op *batchOperation,
+) (*models.Record, func(), error) {
+	requestData := batchRequestData(c, op)
+
+	if requestData.Admin == nil && collection.CreateRule == nil {
+		// only admins can access if the rule is nil
+		return nil, nil, NewForbiddenError("Only admins can perform this action.", nil)
+	}
+
+	// the bot protection checks rely on the plain request body fields
+	if requestData.Admin == nil {
+		botOptions := collection.BotProtectionOptions()
+		if len(botOptions.HoneypotFields) > 0 || botOptions.MinSubmitTime > 0 {
+			return nil, nil, NewForbiddenError("The collection records can't be created with a batch request.", nil)
+		}
+	}
+
+	// check the collection records quotas
+	registerQuotaHit, err := checkRecordCreateQuotas(api.app, c, collection)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	record := models.NewRecord(collection)
+	setRecordHistoryAuth(record, requestData)
+	form := forms.NewRecordUpsert(api.app, record)
+	form.SetDao(txDao)
+	form.SetFullManageAccess(requestData.Admin != nil)
+	form.SetAuthRecord(requestData.AuthRecord)
+	form.SetApiKey(requestData.ApiKey)
+	form.SetCheckFieldWriteRules(requestData.Admin == nil)
+	form.SetDeferFilesCommit(true) // committed together with the batch transaction
+
+	if err := form.LoadData(op.Data); err != nil {
+		return nil, nil, NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	event := &core.RecordCreateEvent{
+		HttpContext: c,
+		Record:      record,
+	}
+
+	submitErr := form.Submit(func(next forms.InterceptorNextFunc) forms.InterceptorNextFunc {
+		return func() error {
+			if err := moderateRecordContent(api.app, c, record); err != nil {
+				return err
+			}
+
+			return api.app.OnRecordBeforeCreateRequest().Trigger(event, func(e *core.RecordCreateEvent) error {
+				if err := next(); err != nil {
+					return NewBadRequestError("Failed to create record.", err)
+				}
+
+				// check the persisted record against the create rule
+				// (the transaction is rolled back on failure)
+				if requestData.Admin == nil && *collection.CreateRule != "" {
+					ruleFunc := batchRuleFunc(txDao, collection, *collection.CreateRule, requestData)
+					if _, err := txDao.FindRecordById(collection.Id, e.Record.Id, ruleFunc); err != nil {
+						return NewBadRequestError("Failed to create record.", fmt.Errorf("create rule failure: %w", err))
+					}
+				}
+
+				return nil
+			})
+		}
+	})
+	if submitErr != nil {
+		return nil, nil, submitErr
+	}
+
+	return record, func() {
+		api.commitFiles(form)
+		registerQuotaHit()
+		api.app.OnRecordAfterCreateRequest().Trigger(event)
+	}, nil
+}
+
+func (api *batchApi) execUpdate(
+	c echo.Context,
+	txDao *daos.Dao,
+	collection *models.Collection,
+	op *batchOperation,
+) (*models.Record, func(), error) {
+	requestData := batchRequestData(c, op)
+
+	if requestData.Admin == nil && collection.UpdateRule == nil {
+		// only admins can access if the rule is nil
+		return nil, nil, NewForbiddenError("Only admins can perform this action.", nil)
+	}
+
+	var ruleFunc func(q *dbx.SelectQuery) error
+	if requestData.Admin == nil && *collection.UpdateRule != "" {
+		ruleFunc = batchRuleFunc(txDao, collection, *collection.UpdateRule, requestData)
+	}
+
+	record, fetchErr := txDao.FindRecordById(collection.Id, op.Id, ruleFunc)
+	if fetchErr != nil || record == nil {
+		return nil, nil, NewNotFoundError("", fetchErr)
+	}
+
+	setRecordHistoryAuth(record, requestData)
+	form := forms.NewRecordUpsert(api.app, record)
+	form.SetDao(txDao)
+	form.SetFullManageAccess(requestData.Admin != nil || hasAuthManageAccess(txDao, record, requestData))
+	form.SetAuthRecord(requestData.AuthRecord)
+	form.SetApiKey(requestData.ApiKey)
+	form.SetCheckFieldWriteRules(requestData.Admin == nil)
+	form.SetDeferFilesCommit(true) // committed together with the batch transaction
+
+	if err := form.LoadData(op.Data); err != nil {
+		return nil, nil, NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	event := &core.RecordUpdateEvent{
+		HttpContext: c,
+		Record:      record,
+	}
+
+	submitErr := form.Submit(func(next forms.InterceptorNextFunc) forms.InterceptorNextFunc {
+		return func() error {
+			if err := moderateRecordContent(api.app, c, record); err != nil {
+				return err
+			}
+
+			return api.app.OnRecordBeforeUpdateRequest().Trigger(event, func(e *core.RecordUpdateEvent) error {
+				err := next()
+				if errors.Is(err, daos.ErrRecordVersionConflict) {
+					return NewConflictError("The record was modified in the meantime.", err)
+				}
+				if err != nil {
+					return NewBadRequestError("Failed to update record.", err)
+				}
+
+				return nil
+			})
+		}
+	})
+	if submitErr != nil {
+		return nil, nil, submitErr
+	}
+
+	return record, func() {
+		api.commitFiles(form)
+		api.app.OnRecordAfterUpdateRequest().Trigger(event)
+	}, nil
+}
+
+// commitFiles moves the staged files of the submitted
+// batch operation form to their record location.
+func (api *batchApi) commitFiles(form *forms.RecordUpsert) {
+	if err := commitRecordFiles(api.app, form); err != nil && api.app.IsDebug() {
+		log.Println(err)
+	}
+}
+
+func (api *batchApi) execDelete(
+	c echo.Context,
+	txDao *daos.Dao,
+	collection *models.Collection,
+	op *batchOperation,
+) (*models.Record, func(), error) {
+	requestData := batchRequestData(c, op)
+
+	if requestData.Admin == nil && collection.DeleteRule == nil {
+		// only admins can access if the rule is nil
+		return nil, nil, NewForbiddenError("Only admins can perform this action.", nil)
+	}
+
+	var ruleFunc func(q *dbx.SelectQuery) error
+	if requestData.Admin == nil && *collection.DeleteRule != "" {
+		ruleFunc = batchRuleFunc(txDao, collection, *collection.DeleteRule, requestData)
+	}
+
+	record, fetchErr := txDao.FindRecordById(collection.Id, op.Id, ruleFunc)
+	if fetchErr != nil || record == nil {
+		return nil, nil, NewNotFoundError("", fetchErr)
+	}
+
+	setRecordHistoryAuth(record, requestData)
+
+	event := &core.RecordDeleteEvent{
+		HttpContext: c,
+		Record:      record,
+	}
+
+	handlerErr := api.app.OnRecordBeforeDeleteRequest().Trigger(event, func(e *core.RecordDeleteEvent) error {
+		// delete (or only mark as deleted) the record
+		var err error
+		if e.Record.Collection().SoftDeleteOptions().Enabled {
+			err = txDao.SoftDeleteRecord(e.Record)
+		} else {
+			err = txDao.DeleteRecord(e.Record)
+		}
+		if err != nil {
+			return NewBadRequestError("Failed to delete record. Make sure that the record is not part of a required relation reference.", err)
+		}
+
+		return nil
+	})
+	if handlerErr != nil {
+		return nil, nil, handlerErr
+	}
+
+	return nil, func() {
+		api.app.OnRecordAfterDeleteRequest().Trigger(event)
+	}, nil
+}
+
+// batchRequestData returns a copy of the request data with the
+// operation data as body (aka. the `@request.data.*` rule fields
+// are resolved against the operation fields and not the batch body).
+func batchRequestData(c echo.Context, op *batchOperation) *models.RequestData {
+	requestData := *RequestData(c)
+
+	requestData.Data = op.Data
+	if requestData.Data == nil {
+		requestData.Data = map[string]any{}
+	}
+
+	return &requestData
+}
+
+// batchRuleFunc returns a records query modifier that applies
+// the provided collection API rule using the transaction dao
+// (aka. the rule "sees" the changes of the previous batch operations).
+func batchRuleFunc(
+	txDao *daos.Dao,
+	collection *models.Collection,
+	rule string,
+	requestData *models.RequestData,
+) func(q *dbx.SelectQuery) error {
+	return func(q *dbx.SelectQuery) error {
+		resolver := resolvers.NewRecordFieldResolver(txDao, collection, requestData, true)
+		expr, err := search.FilterData(rule).BuildExpr(resolver)
+		if err != nil {
+			return err
+		}
+		resolver.UpdateQuery(q)
+		q.AndWhere(expr)
+		return nil
+	}
+}
+
+// batchOperationError wraps the provided error into an ApiError
+// with the index of the failed batch operation.
+//
+// The status code of the failed operation error is preserved (400 by default).
+func batchOperationError(index int, err error) *ApiError {
+	opErr, ok := err.(*ApiError)
+	if !ok {
+		opErr = NewBadRequestError("", err)
+	}
+
+	result := NewApiError(opErr.Code, fmt.Sprintf("Batch operation %d failed.", index), opErr.RawData())
+	result.Data = map[string]any{
+		"operations": map[string]any{
+			strconv.Itoa(index): opErr,
+		},
+	}
+
+	return result
+}
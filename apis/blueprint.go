@@ -0,0 +1,49 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/blueprints"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+)
+
+// bindBlueprintApi registers the collection blueprints api
+// endpoints and the corresponding handlers.
+func bindBlueprintApi(app core.App, rg *echo.Group) {
+	api := blueprintApi{app: app}
+
+	subGroup := rg.Group("/blueprints", ActivityLogger(app), RequireAdminAuth())
+	subGroup.GET("", api.list)
+	subGroup.POST("/:blueprint/instantiate", api.instantiate)
+}
+
+type blueprintApi struct {
+	app core.App
+}
+
+func (api *blueprintApi) list(c echo.Context) error {
+	return c.JSON(http.StatusOK, blueprints.List())
+}
+
+func (api *blueprintApi) instantiate(c echo.Context) error {
+	blueprint, err := blueprints.Find(c.PathParam("blueprint"))
+	if err != nil {
+		return NewNotFoundError("", err)
+	}
+
+	form := forms.NewCollectionsBlueprint(api.app, blueprint)
+
+	// load request data
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	result, err := form.Submit()
+	if err != nil {
+		return NewBadRequestError("Failed to instantiate the blueprint.", err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
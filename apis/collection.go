@@ -1,15 +1,30 @@
 package apis
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
 	"github.com/pocketbase/pocketbase/forms"
 	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/resolvers"
 	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/types"
 )
 
+var viewNameRegex = regexp.MustCompile(`^\w+$`)
+
+// sqlMarkersRegex matches the dbx param placeholders and quote markers
+// (eg. "{:param}", "{{table}}" and "[[column]]").
+var sqlMarkersRegex = regexp.MustCompile(`\{:\w+\}|\{\{[\w\-\. ]+\}\}|\[\[[\w\-\. ]+\]\]`)
+
 // bindCollectionApi registers the collection api endpoints and the corresponding handlers.
 func bindCollectionApi(app core.App, rg *echo.Group) {
 	api := collectionApi{app: app}
@@ -20,7 +35,16 @@ func bindCollectionApi(app core.App, rg *echo.Group) {
 	subGroup.GET("/:collection", api.view)
 	subGroup.PATCH("/:collection", api.update)
 	subGroup.DELETE("/:collection", api.delete)
+	subGroup.GET("/:collection/lock", api.viewLock)
+	subGroup.POST("/:collection/lock", api.acquireLock)
+	subGroup.DELETE("/:collection/lock", api.releaseLock)
+	subGroup.GET("/:collection/list-rule-view", api.listRuleView)
+	subGroup.POST("/:collection/rotate-encryption-key", api.rotateEncryptionKey)
+	subGroup.POST("/:collection/purge-deleted", api.purgeDeleted)
+	subGroup.POST("/:collection/clone", api.clone)
+	subGroup.POST("/:collection/rules/test", api.testRule)
 	subGroup.PUT("/import", api.bulkImport)
+	subGroup.POST("/diff", api.diff)
 }
 
 type collectionApi struct {
@@ -65,6 +89,8 @@ func (api *collectionApi) view(c echo.Context) error {
 	}
 
 	return api.app.OnCollectionViewRequest().Trigger(event, func(e *core.CollectionViewEvent) error {
+		setCollectionETag(e.HttpContext, e.Collection)
+
 		return e.HttpContext.JSON(http.StatusOK, e.Collection)
 	})
 }
@@ -110,6 +136,10 @@ func (api *collectionApi) update(c echo.Context) error {
 		return NewNotFoundError("", err)
 	}
 
+	if lockedErr := collectionLockedError(api.app, c, collection); lockedErr != nil {
+		return lockedErr
+	}
+
 	form := forms.NewCollectionUpsert(api.app, collection)
 
 	// load request
@@ -117,6 +147,15 @@ func (api *collectionApi) update(c echo.Context) error {
 		return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
 	}
 
+	// the If-Match header has precedence over the submitted version
+	if c.Request().Header.Get("If-Match") != "" {
+		version, err := parseRecordETag(c.Request().Header.Get("If-Match"))
+		if err != nil {
+			return NewBadRequestError("Invalid If-Match header.", err)
+		}
+		form.Version = version
+	}
+
 	event := &core.CollectionUpdateEvent{
 		HttpContext: c,
 		Collection:  collection,
@@ -126,10 +165,16 @@ func (api *collectionApi) update(c echo.Context) error {
 	submitErr := form.Submit(func(next forms.InterceptorNextFunc) forms.InterceptorNextFunc {
 		return func() error {
 			return api.app.OnCollectionBeforeUpdateRequest().Trigger(event, func(e *core.CollectionUpdateEvent) error {
-				if err := next(); err != nil {
+				err := next()
+				if errors.Is(err, daos.ErrCollectionVersionConflict) {
+					return api.versionConflictError(e.HttpContext, e.Collection.Id)
+				}
+				if err != nil {
 					return NewBadRequestError("Failed to update the collection.", err)
 				}
 
+				setCollectionETag(e.HttpContext, e.Collection)
+
 				return e.HttpContext.JSON(http.StatusOK, e.Collection)
 			})
 		}
@@ -148,6 +193,10 @@ func (api *collectionApi) delete(c echo.Context) error {
 		return NewNotFoundError("", err)
 	}
 
+	if lockedErr := collectionLockedError(api.app, c, collection); lockedErr != nil {
+		return lockedErr
+	}
+
 	event := &core.CollectionDeleteEvent{
 		HttpContext: c,
 		Collection:  collection,
@@ -158,6 +207,8 @@ func (api *collectionApi) delete(c echo.Context) error {
 			return NewBadRequestError("Failed to delete collection. Make sure that the collection is not referenced by other collections.", err)
 		}
 
+		api.app.Cache().Remove(collectionLockCachePrefix + e.Collection.Id)
+
 		return e.HttpContext.NoContent(http.StatusNoContent)
 	})
 
@@ -168,6 +219,109 @@ func (api *collectionApi) delete(c echo.Context) error {
 	return handlerErr
 }
 
+// rotateEncryptionKey creates a new collection records encryption key
+// version and reencrypts all collection records with it.
+func (api *collectionApi) rotateEncryptionKey(c echo.Context) error {
+	collection, err := api.app.Dao().FindCollectionByNameOrId(c.PathParam("collection"))
+	if err != nil || collection == nil {
+		return NewNotFoundError("", err)
+	}
+
+	if !daos.HasEncryptedRecordFields(collection) {
+		return NewBadRequestError("The collection doesn't have encrypted fields.", nil)
+	}
+
+	if err := api.app.Dao().RotateRecordsEncryptionKey(collection); err != nil {
+		return NewBadRequestError("Failed to rotate the collection records encryption key.", err)
+	}
+
+	key, err := api.app.Dao().FindRecordsEncryptionKey(collection)
+	if err != nil || key == nil {
+		return NewBadRequestError("Failed to load the collection records encryption key.", err)
+	}
+
+	return c.JSON(http.StatusOK, key)
+}
+
+// purgeDeleted permanently deletes the soft deleted collection records
+// (optionally only the ones deleted before the "before" body date).
+func (api *collectionApi) purgeDeleted(c echo.Context) error {
+	collection, err := api.app.Dao().FindCollectionByNameOrId(c.PathParam("collection"))
+	if err != nil || collection == nil {
+		return NewNotFoundError("", err)
+	}
+
+	if !collection.SoftDeleteOptions().Enabled {
+		return NewBadRequestError("The collection soft delete is not enabled.", nil)
+	}
+
+	data := struct {
+		Before string `json:"before"`
+	}{}
+	if err := c.Bind(&data); err != nil {
+		return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	before, err := types.ParseDateTime(data.Before)
+	if err != nil || (data.Before != "" && before.IsZero()) {
+		return NewBadRequestError("Invalid before date.", err)
+	}
+
+	total, err := api.app.Dao().PurgeDeletedRecords(collection, before)
+	if err != nil {
+		return NewBadRequestError("Failed to purge the deleted records.", err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]int{"total": total})
+}
+
+// clone duplicates the collection under a new name
+// (optionally together with its records and files).
+func (api *collectionApi) clone(c echo.Context) error {
+	collection, err := api.app.Dao().FindCollectionByNameOrId(c.PathParam("collection"))
+	if err != nil || collection == nil {
+		return NewNotFoundError("", err)
+	}
+
+	form := forms.NewCollectionClone(api.app, collection)
+
+	// load request data
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	clone, err := form.Submit()
+	if err != nil {
+		return NewBadRequestError("Failed to clone the collection.", err)
+	}
+
+	return c.JSON(http.StatusOK, clone)
+}
+
+// testRule evaluates a candidate rule against a single collection
+// record with simulated request data and reports whether the access
+// would be granted together with the generated SQL.
+func (api *collectionApi) testRule(c echo.Context) error {
+	collection, err := api.app.Dao().FindCollectionByNameOrId(c.PathParam("collection"))
+	if err != nil || collection == nil {
+		return NewNotFoundError("", err)
+	}
+
+	form := forms.NewTestCollectionRule(api.app, collection)
+
+	// load request data
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	result, err := form.Submit()
+	if err != nil {
+		return NewBadRequestError("Failed to test the collection rule.", err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
 func (api *collectionApi) bulkImport(c echo.Context) error {
 	form := forms.NewCollectionsImport(api.app)
 
@@ -202,3 +356,206 @@ func (api *collectionApi) bulkImport(c echo.Context) error {
 
 	return submitErr
 }
+
+// diff returns the changes (and the SQL statements) that
+// the import of the submitted collections would apply.
+func (api *collectionApi) diff(c echo.Context) error {
+	form := forms.NewCollectionsImport(api.app)
+
+	// load request data
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	diff, err := form.Diff()
+	if err != nil {
+		return NewBadRequestError("Failed to diff the submitted collections.", err)
+	}
+
+	return c.JSON(http.StatusOK, diff)
+}
+
+// listRuleView returns a standalone SQL view statement that encodes
+// the collection list rule evaluated for the specified auth record
+// (or for a guest if no auth record is specified).
+//
+// This allows tools that read the SQLite database file directly
+// (eg. BI dashboards) to respect the collection access rules.
+//
+// Note that the view is a snapshot for a single auth record and not
+// a generic encoding of the rule since the "@request.auth.*" values are
+// inlined in its statement (it should be regenerated if the rule or the
+// auth record change). The response "authCollectionId" and "authId"
+// fields (empty for guests) identify the auth record of the snapshot.
+func (api *collectionApi) listRuleView(c echo.Context) error {
+	collection, err := api.app.Dao().FindCollectionByNameOrId(c.PathParam("collection"))
+	if err != nil || collection == nil {
+		return NewNotFoundError("", err)
+	}
+
+	if collection.ListRule == nil {
+		return NewBadRequestError("The collection list rule is admin only and cannot be exported as a view.", nil)
+	}
+
+	requestData := &models.RequestData{Method: http.MethodGet}
+
+	authCollection := c.QueryParam("authCollection")
+	authId := c.QueryParam("authId")
+	if authCollection != "" || authId != "" {
+		authRecord, err := api.app.Dao().FindRecordById(authCollection, authId)
+		if err != nil || !authRecord.Collection().IsAuth() {
+			return NewBadRequestError("Missing or invalid auth record.", err)
+		}
+		requestData.AuthRecord = authRecord
+	}
+
+	viewName := c.QueryParam("name")
+	if viewName == "" {
+		viewName = collection.Name + "_list_view"
+		if requestData.AuthRecord != nil {
+			viewName += "_" + requestData.AuthRecord.Id
+		}
+	} else if !viewNameRegex.MatchString(viewName) {
+		return NewBadRequestError("Invalid view name.", nil)
+	}
+
+	sql, err := listRuleViewSQL(api.app.Dao(), collection, requestData, viewName)
+	if err != nil {
+		return NewBadRequestError("Failed to generate the list rule view.", err)
+	}
+
+	result := map[string]string{
+		"name":             viewName,
+		"sql":              sql,
+		"authCollectionId": "",
+		"authId":           "",
+	}
+	if requestData.AuthRecord != nil {
+		result["authCollectionId"] = requestData.AuthRecord.Collection().Id
+		result["authId"] = requestData.AuthRecord.Id
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// listRuleViewSQL builds a "CREATE VIEW" statement with the records
+// query of the provided collection filtered by its list rule.
+//
+// All query parameters (incl. the request auth record values) are inlined
+// since views cannot have bound params, aka. the view is a snapshot for the
+// provided request data and it is labeled as such with a leading comment.
+//
+// The dbx markers are replaced in a single pass the same way as dbx does
+// when executing the query (the inlined values are not rescanned).
+func listRuleViewSQL(
+	dao *daos.Dao,
+	collection *models.Collection,
+	requestData *models.RequestData,
+	viewName string,
+) (string, error) {
+	if collection.ListRule == nil {
+		return "", errors.New("Cannot export an admin only list rule.")
+	}
+
+	query := dao.RecordQuery(collection)
+
+	if *collection.ListRule != "" {
+		resolver := resolvers.NewRecordFieldResolver(dao, collection, requestData, false)
+
+		expr, err := search.FilterData(*collection.ListRule).BuildExpr(resolver)
+		if err != nil {
+			return "", err
+		}
+
+		if err := resolver.UpdateQuery(query); err != nil {
+			return "", err
+		}
+
+		query.AndWhere(expr)
+	}
+
+	built := query.Build()
+
+	var inlineErr error
+	selectSQL := sqlMarkersRegex.ReplaceAllStringFunc(built.SQL(), func(m string) string {
+		if strings.HasPrefix(m, "{:") {
+			literal, err := sqlLiteral(dao.DB(), built.Params()[m[2:len(m)-1]])
+			if err != nil {
+				inlineErr = err
+			}
+			return literal
+		}
+
+		// {{table}} or [[table.column]]
+		parts := strings.Split(m[2:len(m)-2], ".")
+		for i, part := range parts {
+			if i == len(parts)-1 && strings.HasPrefix(m, "[[") {
+				parts[i] = dao.DB().QuoteSimpleColumnName(part)
+			} else {
+				parts[i] = dao.DB().QuoteSimpleTableName(part)
+			}
+		}
+		return strings.Join(parts, ".")
+	})
+	if inlineErr != nil {
+		return "", inlineErr
+	}
+
+	snapshotFor := "guests"
+	if requestData.AuthRecord != nil {
+		snapshotFor = fmt.Sprintf(
+			"auth record %s/%s",
+			requestData.AuthRecord.Collection().Id,
+			requestData.AuthRecord.Id,
+		)
+	}
+
+	return fmt.Sprintf(
+		"CREATE VIEW %s AS /* %s list rule snapshot for %s */ %s",
+		dao.DB().QuoteSimpleTableName(viewName),
+		collection.Name,
+		strings.ReplaceAll(snapshotFor, "*/", ""),
+		selectSQL,
+	), nil
+}
+
+// sqlLiteral returns the SQL literal representation of a plain value.
+func sqlLiteral(builder dbx.Builder, value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "NULL", nil
+	case string:
+		return builder.Quote(v), nil
+	case bool:
+		if v {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprintf("%v", v), nil
+	default:
+		return "", fmt.Errorf("Unsupported view param value %v.", v)
+	}
+}
+
+// versionConflictError returns a 409 error with the current
+// state of the outdated collection (if still exists).
+func (api *collectionApi) versionConflictError(c echo.Context, collectionId string) error {
+	conflictErr := NewConflictError("The collection was modified in the meantime.", daos.ErrCollectionVersionConflict)
+
+	current, err := api.app.Dao().FindCollectionByNameOrId(collectionId)
+	if err != nil {
+		return conflictErr
+	}
+
+	setCollectionETag(c, current)
+
+	conflictErr.Data["current"] = current
+
+	return conflictErr
+}
+
+// setCollectionETag sets the ETag response header with the version of the provided collection.
+func setCollectionETag(c echo.Context, collection *models.Collection) {
+	c.Response().Header().Set("ETag", strconv.Quote(strconv.Itoa(collection.Version)))
+}
@@ -0,0 +1,137 @@
+package apis
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/spf13/cast"
+)
+
+// CollectionLockDuration is the max duration of an acquired
+// collection lock before it has to be refreshed.
+const CollectionLockDuration = 5 * time.Minute
+
+const collectionLockCachePrefix = "collectionLock_"
+
+// serializes the collection locks acquire/release operations
+var collectionLocksMux sync.Mutex
+
+// CollectionLock defines an advisory (soft) collection lock that admins
+// could acquire to prevent concurrent edits of the same collection.
+type CollectionLock struct {
+	CollectionId string         `json:"collectionId"`
+	AdminId      string         `json:"adminId"`
+	AdminEmail   string         `json:"adminEmail"`
+	Expires      types.DateTime `json:"expires"`
+}
+
+// IsExpired checks whether the lock has expired.
+func (l *CollectionLock) IsExpired() bool {
+	return l.Expires.Time().Before(time.Now())
+}
+
+// FindCollectionLock returns the active lock of the specified collection
+// or nil if the collection is not locked.
+func FindCollectionLock(app core.App, collectionId string) *CollectionLock {
+	lock, _ := app.Cache().Get(collectionLockCachePrefix + collectionId).(*CollectionLock)
+	if lock == nil || lock.IsExpired() {
+		return nil
+	}
+
+	return lock
+}
+
+// collectionLockedError returns a 409 error if the collection is
+// locked by another admin than the request one, otherwise - nil.
+func collectionLockedError(app core.App, c echo.Context, collection *models.Collection) *ApiError {
+	lock := FindCollectionLock(app, collection.Id)
+	if lock == nil {
+		return nil
+	}
+
+	admin, _ := c.Get(ContextAdminKey).(*models.Admin)
+	if admin != nil && admin.Id == lock.AdminId {
+		return nil
+	}
+
+	lockedErr := NewConflictError("The collection is locked by another admin.", nil)
+	lockedErr.Data["lock"] = lock
+
+	return lockedErr
+}
+
+func (api *collectionApi) viewLock(c echo.Context) error {
+	collection, err := api.app.Dao().FindCollectionByNameOrId(c.PathParam("collection"))
+	if err != nil || collection == nil {
+		return NewNotFoundError("", err)
+	}
+
+	lock := FindCollectionLock(api.app, collection.Id)
+	if lock == nil {
+		return NewNotFoundError("The collection is not locked.", nil)
+	}
+
+	return c.JSON(http.StatusOK, lock)
+}
+
+// acquireLock locks the collection for the authorized admin
+// (or extends the lock expiration if already acquired by the same admin).
+func (api *collectionApi) acquireLock(c echo.Context) error {
+	collection, err := api.app.Dao().FindCollectionByNameOrId(c.PathParam("collection"))
+	if err != nil || collection == nil {
+		return NewNotFoundError("", err)
+	}
+
+	admin, _ := c.Get(ContextAdminKey).(*models.Admin)
+	if admin == nil {
+		return NewUnauthorizedError("", nil)
+	}
+
+	collectionLocksMux.Lock()
+	defer collectionLocksMux.Unlock()
+
+	if lockedErr := collectionLockedError(api.app, c, collection); lockedErr != nil {
+		return lockedErr
+	}
+
+	expires, _ := types.ParseDateTime(time.Now().Add(CollectionLockDuration))
+
+	lock := &CollectionLock{
+		CollectionId: collection.Id,
+		AdminId:      admin.Id,
+		AdminEmail:   admin.Email,
+		Expires:      expires,
+	}
+
+	api.app.Cache().Set(collectionLockCachePrefix+collection.Id, lock)
+
+	return c.JSON(http.StatusOK, lock)
+}
+
+// releaseLock removes the collection lock.
+//
+// Locks acquired by other admins could be released only with the "force" query parameter.
+func (api *collectionApi) releaseLock(c echo.Context) error {
+	collection, err := api.app.Dao().FindCollectionByNameOrId(c.PathParam("collection"))
+	if err != nil || collection == nil {
+		return NewNotFoundError("", err)
+	}
+
+	collectionLocksMux.Lock()
+	defer collectionLocksMux.Unlock()
+
+	if !cast.ToBool(c.QueryParam("force")) {
+		if lockedErr := collectionLockedError(api.app, c, collection); lockedErr != nil {
+			return lockedErr
+		}
+	}
+
+	api.app.Cache().Remove(collectionLockCachePrefix + collection.Id)
+
+	return c.NoContent(http.StatusNoContent)
+}
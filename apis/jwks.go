@@ -0,0 +1,103 @@
+package apis
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+// bindJwksApi registers the OpenID Connect discovery and JWKS endpoints
+// that publish the auth tokens signing keys (if token signing is enabled).
+func bindJwksApi(app core.App, e *echo.Echo) {
+	api := jwksApi{app: app}
+
+	e.GET("/.well-known/openid-configuration", api.discovery)
+	e.GET("/.well-known/jwks.json", api.jwks)
+}
+
+type jwksApi struct {
+	app core.App
+}
+
+func (api *jwksApi) discovery(c echo.Context) error {
+	signing := api.app.Settings().TokenSigning
+	if !signing.Enabled {
+		return NewNotFoundError("", nil)
+	}
+
+	algs := []string{}
+	for _, jwk := range api.publicKeys() {
+		algs = append(algs, jwk["alg"])
+	}
+
+	appUrl := strings.TrimSuffix(api.app.Settings().Meta.AppUrl, "/")
+
+	// the issuer must match the "iss" claim of the issued tokens
+	issuer := api.app.Settings().AuthTokenClaims.Issuer
+	if issuer == "" {
+		issuer = appUrl
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"issuer":                                issuer,
+		"jwks_uri":                              appUrl + "/.well-known/jwks.json",
+		"id_token_signing_alg_values_supported": list.ToUniqueStringSlice(algs),
+	})
+}
+
+// jwks returns the public parts of all active and retired (but not
+// yet removed) token signing keys so that the tokens issued before a
+// key rotation remain verifiable until their expiration.
+func (api *jwksApi) jwks(c echo.Context) error {
+	if !api.app.Settings().TokenSigning.Enabled {
+		return NewNotFoundError("", nil)
+	}
+
+	c.Response().Header().Set("Cache-Control", "public, max-age=300")
+
+	return c.JSON(http.StatusOK, map[string]any{"keys": api.publicKeys()})
+}
+
+// publicKeys returns the JWK representation of the token signing keys.
+func (api *jwksApi) publicKeys() []map[string]string {
+	signing := api.app.Settings().TokenSigning
+
+	keys := make([]map[string]string, 0, len(signing.Keys))
+
+	for _, key := range signing.Keys {
+		signer, err := key.Signer()
+		if err != nil {
+			continue
+		}
+
+		switch publicKey := signer.Public().(type) {
+		case *rsa.PublicKey:
+			keys = append(keys, map[string]string{
+				"kty": "RSA",
+				"use": "sig",
+				"alg": "RS256",
+				"kid": key.Id,
+				"n":   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			keys = append(keys, map[string]string{
+				"kty": "OKP",
+				"crv": "Ed25519",
+				"use": "sig",
+				"alg": "EdDSA",
+				"kid": key.Id,
+				"x":   base64.RawURLEncoding.EncodeToString(publicKey),
+			})
+		}
+	}
+
+	return keys
+}
@@ -0,0 +1,114 @@
+package apis_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/models/settings"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestJwksDiscovery(t *testing.T) {
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "disabled token signing",
+			Method:          http.MethodGet,
+			Url:             "/.well-known/openid-configuration",
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "enabled token signing",
+			Method: http.MethodGet,
+			Url:    "/.well-known/openid-configuration",
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				tests.EnableTokenSigning(t, app)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"issuer":"http://localhost:8090"`,
+				`"jwks_uri":"http://localhost:8090/.well-known/jwks.json"`,
+				`"id_token_signing_alg_values_supported":["RS256"]`,
+			},
+		},
+		{
+			Name:   "enabled token signing with custom issuer",
+			Method: http.MethodGet,
+			Url:    "/.well-known/openid-configuration",
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				tests.EnableTokenSigning(t, app)
+				app.Settings().AuthTokenClaims.Issuer = "test_issuer"
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"issuer":"test_issuer"`,
+				`"jwks_uri":"http://localhost:8090/.well-known/jwks.json"`,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestJwks(t *testing.T) {
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "disabled token signing",
+			Method:          http.MethodGet,
+			Url:             "/.well-known/jwks.json",
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:   "enabled token signing with rotated keys",
+			Method: http.MethodGet,
+			Url:    "/.well-known/jwks.json",
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				tests.EnableTokenSigning(t, app)
+				app.Settings().TokenSigning.Keys[0].Id = "retired_key"
+				if err := app.Settings().TokenSigning.Rotate(time.Hour); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"kid":"retired_key"`,
+				`"kty":"RSA"`,
+				`"alg":"RS256"`,
+				`"use":"sig"`,
+				`"e":"AQAB"`,
+			},
+			NotExpectedContent: []string{
+				`privateKey`,
+			},
+		},
+		{
+			Name:   "enabled token signing with mixed algorithms",
+			Method: http.MethodGet,
+			Url:    "/.well-known/jwks.json",
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				tests.EnableTokenSigning(t, app)
+				app.Settings().TokenSigning.Algorithm = settings.TokenSigningAlgorithmEdDSA
+				if err := app.Settings().TokenSigning.Rotate(time.Hour); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"kty":"RSA"`,
+				`"alg":"RS256"`,
+				`"kty":"OKP"`,
+				`"crv":"Ed25519"`,
+				`"alg":"EdDSA"`,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
@@ -0,0 +1,38 @@
+package apis
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+)
+
+// loginRequestIp returns the client ip of the login request
+// that is used for the failed login attempts throttling.
+//
+// The proxy headers are considered only if they are configured in the
+// app settings TrustedProxy options, otherwise any client could bypass
+// (or trigger for someone else) the ip lockout with spoofed headers.
+func loginRequestIp(app core.App, c echo.Context) string {
+	return trustedUserIp(app, c.Request())
+}
+
+// loginSubmitError converts the provided password login form submit
+// error into an api error.
+//
+// [forms.LoginLockedError] is returned as 429 response with
+// Retry-After header and any other error as 400 response.
+func loginSubmitError(c echo.Context, err error) *ApiError {
+	var lockedErr *forms.LoginLockedError
+	if !errors.As(err, &lockedErr) {
+		return NewBadRequestError("Failed to authenticate.", err)
+	}
+
+	c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(lockedErr.RetryAfter.Seconds()))))
+
+	return NewApiError(http.StatusTooManyRequests, lockedErr.Error(), nil)
+}
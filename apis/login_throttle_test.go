@@ -0,0 +1,179 @@
+package apis_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func enableLoginThrottle(app *tests.TestApp) {
+	app.Settings().LoginThrottle.Enabled = true
+	app.Settings().LoginThrottle.MaxIdentityFailures = 2
+	app.Settings().LoginThrottle.MaxIpFailures = 10
+	app.Settings().LoginThrottle.Window = 600
+	app.Settings().LoginThrottle.LockoutDuration = 60
+}
+
+// saveLoginAttempt stores the provided login attempt without triggering model events.
+func saveLoginAttempt(t *testing.T, app *tests.TestApp, kind string, key string, failures int, lockedFor time.Duration) {
+	attempt := &models.LoginAttempt{Kind: kind, Key: key, Failures: failures}
+	if lockedFor > 0 {
+		attempt.LockedUntil, _ = types.ParseDateTime(time.Now().Add(lockedFor))
+	}
+
+	if err := daos.New(app.Dao().DB()).SaveLoginAttempt(attempt); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoginThrottle(t *testing.T) {
+	scenarios := []tests.ApiScenario{
+		{
+			Name:   "record auth with locked identity",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/auth-with-password",
+			Body:   strings.NewReader(`{"identity":"test@example.com","password":"1234567890"}`),
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				enableLoginThrottle(app)
+				saveLoginAttempt(t, app, models.LoginAttemptKindIdentity, "_pb_users_auth_:test@example.com", 0, time.Minute)
+			},
+			ExpectedStatus: 429,
+			ExpectedContent: []string{
+				`"code":429`,
+				`"message":"Too many failed login attempts. Please try again later."`,
+			},
+		},
+		{
+			Name:   "record auth with locked identity but disabled throttling",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/auth-with-password",
+			Body:   strings.NewReader(`{"identity":"test@example.com","password":"1234567890"}`),
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				saveLoginAttempt(t, app, models.LoginAttemptKindIdentity, "_pb_users_auth_:test@example.com", 0, time.Minute)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"token":`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordAuthRequest": 1,
+			},
+		},
+		{
+			Name:   "record auth with locked client ip",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/auth-with-password",
+			Body:   strings.NewReader(`{"identity":"test@example.com","password":"1234567890"}`),
+			RequestHeaders: map[string]string{
+				"X-Real-IP": "1.2.3.4",
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				enableLoginThrottle(app)
+				app.Settings().TrustedProxy.Headers = []string{"X-Real-IP"}
+				saveLoginAttempt(t, app, models.LoginAttemptKindIp, "1.2.3.4", 0, time.Minute)
+			},
+			ExpectedStatus: 429,
+			ExpectedContent: []string{
+				`"code":429`,
+			},
+		},
+		{
+			Name:   "record auth with locked client ip and untrusted proxy header",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/auth-with-password",
+			Body:   strings.NewReader(`{"identity":"test@example.com","password":"1234567890"}`),
+			RequestHeaders: map[string]string{
+				"X-Real-IP": "1.2.3.4",
+			},
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				enableLoginThrottle(app)
+				// the httptest request remote address
+				saveLoginAttempt(t, app, models.LoginAttemptKindIp, "192.0.2.1", 0, time.Minute)
+			},
+			ExpectedStatus: 429,
+			ExpectedContent: []string{
+				`"code":429`,
+			},
+		},
+		{
+			Name:   "record auth failure triggering lockout",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/auth-with-password",
+			Body:   strings.NewReader(`{"identity":"test@example.com","password":"invalid"}`),
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				enableLoginThrottle(app)
+				saveLoginAttempt(t, app, models.LoginAttemptKindIdentity, "_pb_users_auth_:test@example.com", 1, 0)
+			},
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"data":{}`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnModelBeforeCreate": 1,
+				"OnModelAfterCreate":  1,
+				"OnModelBeforeUpdate": 1,
+				"OnModelAfterUpdate":  1,
+				"OnLoginLockout":      1,
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				attempt, err := app.Dao().FindLoginAttempt(models.LoginAttemptKindIdentity, "_pb_users_auth_:test@example.com")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if attempt.Lockouts != 1 || !attempt.IsLocked(time.Now()) {
+					t.Fatalf("Expected locked login attempt, got %v", attempt)
+				}
+			},
+		},
+		{
+			Name:   "record auth success resetting the identity counter",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/auth-with-password",
+			Body:   strings.NewReader(`{"identity":"test@example.com","password":"1234567890"}`),
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				enableLoginThrottle(app)
+				saveLoginAttempt(t, app, models.LoginAttemptKindIdentity, "_pb_users_auth_:test@example.com", 1, 0)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"token":`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnModelBeforeDelete": 1,
+				"OnModelAfterDelete":  1,
+				"OnRecordAuthRequest": 1,
+			},
+			AfterTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				if _, err := app.Dao().FindLoginAttempt(models.LoginAttemptKindIdentity, "_pb_users_auth_:test@example.com"); err == nil {
+					t.Fatal("Expected the identity login attempt to be deleted")
+				}
+			},
+		},
+		{
+			Name:   "admin auth with locked identity",
+			Method: http.MethodPost,
+			Url:    "/api/admins/auth-with-password",
+			Body:   strings.NewReader(`{"identity":"test@example.com","password":"1234567890"}`),
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				enableLoginThrottle(app)
+				saveLoginAttempt(t, app, models.LoginAttemptKindIdentity, "_admins:test@example.com", 0, time.Minute)
+			},
+			ExpectedStatus: 429,
+			ExpectedContent: []string{
+				`"code":429`,
+				`"message":"Too many failed login attempts. Please try again later."`,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
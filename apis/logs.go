@@ -2,6 +2,7 @@ package apis
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v5"
 	"github.com/pocketbase/dbx"
@@ -17,6 +18,7 @@ func bindLogsApi(app core.App, rg *echo.Group) {
 	subGroup := rg.Group("/logs", RequireAdminAuth())
 	subGroup.GET("/requests", api.requestsList)
 	subGroup.GET("/requests/stats", api.requestsStats)
+	subGroup.GET("/requests/aggregate", api.requestsAggregate)
 	subGroup.GET("/requests/:id", api.requestView)
 }
 
@@ -26,10 +28,14 @@ type logsApi struct {
 
 var requestFilterFields = []string{
 	"rowid", "id", "created", "updated",
-	"url", "method", "status", "auth",
+	"url", "method", "status", "auth", "authId", "requestId",
 	"remoteIp", "userIp", "referer", "userAgent",
 }
 
+// requestAggregateSortFields is a list with the additional sort fields
+// of the requests aggregate endpoint (aka. the aggregated item props).
+var requestAggregateSortFields = []string{"key", "total", "errors"}
+
 func (api *logsApi) requestsList(c echo.Context) error {
 	fieldResolver := search.NewSimpleFieldResolver(requestFilterFields...)
 
@@ -47,12 +53,14 @@ func (api *logsApi) requestsList(c echo.Context) error {
 func (api *logsApi) requestsStats(c echo.Context) error {
 	fieldResolver := search.NewSimpleFieldResolver(requestFilterFields...)
 
-	filter := c.QueryParam(search.FilterQueryParam)
+	filter, err := search.ParseFilterFromQuery(c.QueryParams())
+	if err != nil {
+		return NewBadRequestError("Invalid filter format.", err)
+	}
 
 	var expr dbx.Expression
 	if filter != "" {
-		var err error
-		expr, err = search.FilterData(filter).BuildExpr(fieldResolver)
+		expr, err = filter.BuildExpr(fieldResolver)
 		if err != nil {
 			return NewBadRequestError("Invalid filter format.", err)
 		}
@@ -66,6 +74,74 @@ func (api *logsApi) requestsStats(c echo.Context) error {
 	return c.JSON(http.StatusOK, stats)
 }
 
+// requestsAggregate returns grouped requests logs statistics.
+//
+// Example usages:
+//
+//	?groupBy=path(url)&sort=-total                       // requests by route
+//	?groupBy=status                                      // requests by status
+//	?groupBy=day(created)&sort=key                       // requests (and errors) per day
+//	?groupBy=authId&filter=auth='auth_record'&sort=-total // top auth records
+//	?groupBy=userIp&sort=-total&limit=10                 // top ips
+func (api *logsApi) requestsAggregate(c echo.Context) error {
+	groupBy := c.QueryParam("groupBy")
+	if groupBy == "" {
+		return NewBadRequestError("Missing groupBy query parameter.", nil)
+	}
+
+	fieldResolver := search.NewAggregateFieldResolver(requestFilterFields...)
+
+	groupExpr, params, err := fieldResolver.Resolve(groupBy)
+	if err != nil || len(params) > 0 {
+		return NewBadRequestError("Invalid groupBy format.", err)
+	}
+
+	filter, err := search.ParseFilterFromQuery(c.QueryParams())
+	if err != nil {
+		return NewBadRequestError("Invalid filter format.", err)
+	}
+
+	var expr dbx.Expression
+	if filter != "" {
+		expr, err = filter.BuildExpr(fieldResolver)
+		if err != nil {
+			return NewBadRequestError("Invalid filter format.", err)
+		}
+	}
+
+	orderBy := []string{}
+	if rawSort := c.QueryParam(search.SortQueryParam); rawSort != "" {
+		sortResolver := search.NewAggregateFieldResolver(
+			append(requestAggregateSortFields, requestFilterFields...)...,
+		)
+		for _, sortField := range search.ParseSortFromString(rawSort) {
+			sortExpr, err := sortField.BuildExpr(sortResolver)
+			if err != nil {
+				return NewBadRequestError("Invalid sort format.", err)
+			}
+			orderBy = append(orderBy, sortExpr)
+		}
+	}
+
+	limit := search.DefaultPerPage
+	if rawLimit := c.QueryParam("limit"); rawLimit != "" {
+		limit, err = strconv.Atoi(rawLimit)
+		if err != nil || limit <= 0 {
+			return NewBadRequestError("Invalid limit value.", err)
+		}
+		if limit > search.MaxPerPage {
+			limit = search.MaxPerPage
+		}
+	}
+
+	stats, err := api.app.LogsDao().RequestsAggregate(groupExpr, expr, orderBy, limit)
+	if err != nil {
+		return NewBadRequestError("Failed to generate requests stats.", err)
+	}
+
+	return c.JSON(http.StatusOK, stats)
+}
+
 func (api *logsApi) requestView(c echo.Context) error {
 	id := c.PathParam("id")
 	if id == "" {
@@ -5,11 +5,13 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/labstack/echo/v5"
 	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/guardrails"
 	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/tokens"
 	"github.com/pocketbase/pocketbase/tools/list"
@@ -23,9 +25,26 @@ import (
 const (
 	ContextAdminKey      string = "admin"
 	ContextAuthRecordKey string = "authRecord"
+	ContextApiKeyKey     string = "apiKey"
 	ContextCollectionKey string = "collection"
+	ContextRequestIdKey  string = "requestId"
 )
 
+// requestIdRegex validates the incoming request tracing ids
+// (prevents log injection and unbounded ids).
+var requestIdRegex = regexp.MustCompile(`^[\w\-\.:]{1,100}$`)
+
+// readOnlyAllowedRoutes is a list with the write routes that are
+// still allowed when the instance is in read-only mode
+// (they don't modify the app data db).
+var readOnlyAllowedRoutes = []string{
+	"/api/realtime",
+	"/api/admins/auth-with-password",
+	"/api/admins/auth-refresh",
+	"/api/collections/:collection/auth-with-password",
+	"/api/collections/:collection/auth-refresh",
+}
+
 // RequireGuestOnly middleware requires a request to NOT have a valid
 // Authorization header.
 //
@@ -203,6 +222,30 @@ func RequireAdminOrOwnerAuth(ownerIdParam string) echo.MiddlewareFunc {
 	}
 }
 
+// LoadRequestId middleware generates a new request tracing id (or honors
+// the valid incoming "X-Request-Id" header) and stores it in the request's
+// context, allowing the correlation of the request logs, hooks, realtime
+// events and error responses.
+//
+// The request id is also sent back with the "X-Request-Id" response header.
+//
+// This middleware is expected to be already registered by default for all routes.
+func LoadRequestId() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			requestId := c.Request().Header.Get(echo.HeaderXRequestID)
+			if !requestIdRegex.MatchString(requestId) {
+				requestId = security.RandomString(20)
+			}
+
+			c.Set(ContextRequestIdKey, requestId)
+			c.Response().Header().Set(echo.HeaderXRequestID, requestId)
+
+			return next(c)
+		}
+	}
+}
+
 // LoadAuthContext middleware reads the Authorization request header
 // and loads the token related record or admin instance into the
 // request's context.
@@ -225,18 +268,12 @@ func LoadAuthContext(app core.App) echo.MiddlewareFunc {
 
 			switch tokenType {
 			case tokens.TypeAdmin:
-				admin, err := app.Dao().FindAdminByToken(
-					token,
-					app.Settings().AdminAuthToken.Secret,
-				)
+				admin, err := tokens.FindAdminByAuthToken(app, token)
 				if err == nil && admin != nil {
 					c.Set(ContextAdminKey, admin)
 				}
 			case tokens.TypeAuthRecord:
-				record, err := app.Dao().FindAuthRecordByToken(
-					token,
-					app.Settings().RecordAuthToken.Secret,
-				)
+				record, err := tokens.FindAuthRecordByAuthToken(app, token)
 				if err == nil && record != nil {
 					c.Set(ContextAuthRecordKey, record)
 				}
@@ -247,9 +284,34 @@ func LoadAuthContext(app core.App) echo.MiddlewareFunc {
 	}
 }
 
+// LoadApiKeyContext middleware reads the X-Api-Key request header
+// and loads the related api key instance into the request's context.
+//
+// This middleware is expected to be already registered by default for all routes.
+func LoadApiKeyContext(app core.App) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get("X-Api-Key")
+			if key == "" {
+				return next(c)
+			}
+
+			apiKey, err := app.Dao().FindApiKeyByKey(key)
+			if err == nil && apiKey != nil {
+				c.Set(ContextApiKeyKey, apiKey)
+			}
+
+			return next(c)
+		}
+	}
+}
+
 // LoadCollectionContext middleware finds the collection with related
 // path identifier and loads it into the request context.
 //
+// Requests authenticated with an api key that is not scoped
+// for the found collection are rejected (unless an admin is also authenticated).
+//
 // Set optCollectionTypes to further filter the found collection by its type.
 func LoadCollectionContext(app core.App, optCollectionTypes ...string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
@@ -264,6 +326,10 @@ func LoadCollectionContext(app core.App, optCollectionTypes ...string) echo.Midd
 					return NewBadRequestError("Invalid collection type.", nil)
 				}
 
+				if err := checkApiKeyScope(c, collection); err != nil {
+					return err
+				}
+
 				c.Set(ContextCollectionKey, collection)
 			}
 
@@ -272,6 +338,120 @@ func LoadCollectionContext(app core.App, optCollectionTypes ...string) echo.Midd
 	}
 }
 
+// checkApiKeyScope returns a forbidden error if the request is
+// authenticated with an api key that is not scoped for the provided collection.
+func checkApiKeyScope(c echo.Context, collection *models.Collection) error {
+	apiKey, _ := c.Get(ContextApiKeyKey).(*models.ApiKey)
+	if apiKey == nil {
+		return nil
+	}
+
+	if admin, _ := c.Get(ContextAdminKey).(*models.Admin); admin != nil {
+		return nil
+	}
+
+	if _, ok := apiKey.Scope(collection.Id); !ok {
+		return NewForbiddenError("The api key is not allowed to access the collection.", nil)
+	}
+
+	return nil
+}
+
+// ReadOnlyGuard middleware rejects the write requests (aka. all
+// except GET, HEAD and OPTIONS) while the instance is in read-only mode
+// (see [guardrails.Check]).
+//
+// Admins are still allowed to update the app settings
+// (eg. to adjust the guardrails thresholds).
+//
+// This middleware is expected to be already registered by default for all routes.
+func ReadOnlyGuard(app core.App) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !guardrails.IsReadOnly(app) {
+				return next(c)
+			}
+
+			switch c.Request().Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				return next(c)
+			}
+
+			if list.ExistInSlice(c.Path(), readOnlyAllowedRoutes) {
+				return next(c)
+			}
+
+			admin, _ := c.Get(ContextAdminKey).(*models.Admin)
+			if admin != nil && c.Path() == "/api/settings" {
+				return next(c)
+			}
+
+			return NewApiError(
+				http.StatusServiceUnavailable,
+				"The instance is temporarily in read-only mode. Please try again later.",
+				nil,
+			)
+		}
+	}
+}
+
+// SecurityHeaders middleware sets the security response headers
+// (Content-Security-Policy, Strict-Transport-Security, X-Frame-Options
+// and Referrer-Policy) based on the app security headers settings.
+//
+// The admin UI and the configured routes path prefixes could have
+// their own headers policy (otherwise the default one is used).
+//
+// The middleware does nothing if the security headers settings are not enabled.
+//
+// This middleware is expected to be already registered by default for all routes.
+func SecurityHeaders(app core.App) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			config := app.Settings().SecurityHeaders
+			if !config.Enabled {
+				return next(c)
+			}
+
+			path := c.Request().URL.Path
+
+			policy := config.Default
+			if path == strings.TrimRight(trailedAdminPath, "/") || strings.HasPrefix(path, trailedAdminPath) {
+				policy = config.AdminUI
+			} else if route := config.FindRoute(path); route != nil {
+				policy = route.Policy
+			}
+
+			header := c.Response().Header()
+
+			setHeader := func(key string, value string) {
+				if value == "" {
+					header.Del(key)
+				} else {
+					header.Set(key, value)
+				}
+			}
+
+			setHeader(echo.HeaderContentSecurityPolicy, policy.ContentSecurityPolicy)
+			setHeader(echo.HeaderXFrameOptions, policy.XFrameOptions)
+			setHeader(echo.HeaderReferrerPolicy, policy.ReferrerPolicy)
+
+			isHttps := c.IsTLS() || c.Request().Header.Get(echo.HeaderXForwardedProto) == "https"
+			if policy.HstsMaxAge > 0 && isHttps {
+				hsts := fmt.Sprintf("max-age=%d", policy.HstsMaxAge)
+				if policy.HstsIncludeSubdomains {
+					hsts += "; includeSubdomains"
+				}
+				header.Set(echo.HeaderStrictTransportSecurity, hsts)
+			} else {
+				header.Del(echo.HeaderStrictTransportSecurity)
+			}
+
+			return next(c)
+		}
+	}
+}
+
 // ActivityLogger middleware takes care to save the request information
 // into the logs database.
 //
@@ -309,12 +489,20 @@ func ActivityLogger(app core.App) echo.MiddlewareFunc {
 			}
 
 			requestAuth := models.RequestAuthGuest
-			if c.Get(ContextAuthRecordKey) != nil {
+			requestAuthId := ""
+			if record, _ := c.Get(ContextAuthRecordKey).(*models.Record); record != nil {
 				requestAuth = models.RequestAuthRecord
-			} else if c.Get(ContextAdminKey) != nil {
+				requestAuthId = record.Id
+			} else if admin, _ := c.Get(ContextAdminKey).(*models.Admin); admin != nil {
 				requestAuth = models.RequestAuthAdmin
+				requestAuthId = admin.Id
+			} else if apiKey, _ := c.Get(ContextApiKeyKey).(*models.ApiKey); apiKey != nil {
+				requestAuth = models.RequestAuthApiKey
+				requestAuthId = apiKey.Id
 			}
 
+			requestId, _ := c.Get(ContextRequestIdKey).(string)
+
 			ip, _, _ := net.SplitHostPort(httpRequest.RemoteAddr)
 
 			model := &models.Request{
@@ -322,6 +510,8 @@ func ActivityLogger(app core.App) echo.MiddlewareFunc {
 				Method:    strings.ToLower(httpRequest.Method),
 				Status:    status,
 				Auth:      requestAuth,
+				AuthId:    requestAuthId,
+				RequestId: requestId,
 				UserIp:    realUserIp(httpRequest, ip),
 				RemoteIp:  ip,
 				Referer:   httpRequest.Referer(),
@@ -358,6 +548,36 @@ func ActivityLogger(app core.App) echo.MiddlewareFunc {
 	}
 }
 
+// trustedUserIp returns the client IP of the request.
+//
+// The IP is read from the first nonempty app settings trusted proxy
+// header (if any), otherwise the request remote address is used.
+func trustedUserIp(app core.App, r *http.Request) string {
+	remoteIp, _, _ := net.SplitHostPort(r.RemoteAddr)
+
+	config := app.Settings().TrustedProxy
+
+	for _, header := range config.Headers {
+		value := r.Header.Get(header)
+		if value == "" {
+			continue
+		}
+
+		ips := strings.Split(value, ",")
+
+		ip := ips[len(ips)-1]
+		if config.UseLeftmostIp {
+			ip = ips[0]
+		}
+
+		if ip = strings.TrimSpace(ip); net.ParseIP(ip) != nil {
+			return ip
+		}
+	}
+
+	return remoteIp
+}
+
 // Returns the "real" user IP from common proxy headers (or fallbackIp if none is found).
 //
 // The returned IP value shouldn't be trusted if not behind a trusted reverse proxy!
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/labstack/echo/v5"
@@ -179,6 +181,13 @@ func (api *realtimeApi) setSubscriptions(c echo.Context) error {
 		return NewForbiddenError("The current and the previous request authorization don't match.", nil)
 	}
 
+	// check the collections subscribe rules
+	for _, subscription := range form.Subscriptions {
+		if err := api.checkSubscribeRule(c, subscription); err != nil {
+			return err
+		}
+	}
+
 	event := &core.RealtimeSubscribeEvent{
 		HttpContext:   c,
 		Client:        client,
@@ -206,6 +215,72 @@ func (api *realtimeApi) setSubscriptions(c echo.Context) error {
 	return handlerErr
 }
 
+// checkSubscribeRule checks whether the current request is allowed
+// to subscribe to the provided topic based on the subscribe rule of
+// the topic collection (the custom, non collection topics are always allowed).
+//
+// For record topics (eg. "demo/RECORD_ID") the rule is evaluated
+// against the subscribed record, while for the collection topics
+// (eg. "demo/*") only against the request data.
+func (api *realtimeApi) checkSubscribeRule(c echo.Context, subscription string) error {
+	if admin, _ := c.Get(ContextAdminKey).(*models.Admin); admin != nil {
+		return nil // admins can subscribe to everything
+	}
+
+	collectionNameOrId, recordId, _ := strings.Cut(subscription, "/")
+
+	collection, err := api.app.Dao().FindCollectionByNameOrId(collectionNameOrId)
+	if err != nil || collection == nil {
+		return nil // not a collection topic
+	}
+
+	forbiddenErr := NewForbiddenError(fmt.Sprintf("You are not allowed to subscribe to %q.", subscription), nil)
+
+	if collection.SubscribeRule == nil {
+		return forbiddenErr // only admins can subscribe
+	}
+
+	if *collection.SubscribeRule == "" {
+		return nil // empty public rule
+	}
+
+	resolver := resolvers.NewRecordFieldResolver(api.app.Dao(), collection, RequestData(c), true)
+	expr, err := search.FilterData(*collection.SubscribeRule).BuildExpr(resolver)
+	if err != nil {
+		return forbiddenErr
+	}
+
+	if recordId != "" && recordId != "*" {
+		ruleFunc := func(q *dbx.SelectQuery) error {
+			resolver.UpdateQuery(q)
+			q.AndWhere(expr)
+			return nil
+		}
+
+		if _, err := api.app.Dao().FindRecordById(collection.Id, recordId, ruleFunc); err != nil {
+			return forbiddenErr
+		}
+
+		return nil
+	}
+
+	// use the collection model row as single row source for the request only rule
+	// (the rules with record fields will fail because there is no records table to join)
+	var allowed int
+	query := api.app.Dao().DB().Select("(1)").
+		From(collection.TableName()).
+		AndWhere(dbx.HashExp{collection.TableName() + ".id": collection.Id}).
+		AndWhere(expr).
+		Limit(1)
+	resolver.UpdateQuery(query)
+
+	if err := query.Row(&allowed); err != nil || allowed != 1 {
+		return forbiddenErr
+	}
+
+	return nil
+}
+
 // updateClientsAuthModel updates the existing clients auth model with the new one (matched by ID).
 func (api *realtimeApi) updateClientsAuthModel(contextKey string, newModel models.Model) error {
 	for _, client := range api.app.SubscriptionsBroker().Clients() {
@@ -257,6 +332,15 @@ func (api *realtimeApi) bindEvents() {
 		return nil
 	})
 
+	// evaluate the record visibility before its update so that the
+	// subscribers could be notified if it starts or stops matching their access rules
+	api.app.OnModelBeforeUpdate().Add(func(e *core.ModelEvent) error {
+		if record, ok := e.Model.(*models.Record); ok {
+			api.trackRecordVisibility(record)
+		}
+		return nil
+	})
+
 	api.app.OnModelAfterCreate().PreAdd(func(e *core.ModelEvent) error {
 		if record, ok := e.Model.(*models.Record); ok {
 			api.broadcastRecord("create", record)
@@ -321,67 +405,216 @@ func (api *realtimeApi) canAccessRecord(client subscriptions.Client, record *mod
 	return false
 }
 
+// restrictedFields returns the names of the record schema
+// fields whose read rule is not satisfied by the client.
+func (api *realtimeApi) restrictedFields(client subscriptions.Client, record *models.Record) []string {
+	result := []string{}
+
+	for _, field := range record.Collection().Schema.Fields() {
+		if field.ReadRule == "" {
+			continue
+		}
+
+		rule := field.ReadRule
+		if !api.canAccessRecord(client, record, &rule) {
+			result = append(result, field.Name)
+		}
+	}
+
+	return result
+}
+
 type recordData struct {
-	Action string         `json:"action"`
-	Record *models.Record `json:"record"`
+	Action    string         `json:"action"`
+	Record    *models.Record `json:"record"`
+	RequestId string         `json:"requestId,omitempty"`
 }
 
-func (api *realtimeApi) broadcastRecord(action string, record *models.Record) error {
+// recordRequestIds stores the tracing ids of the requests that are
+// currently modifying a record (keyed by the record model pointer).
+var recordRequestIds sync.Map
+
+// trackRecordRequestId associates the record model with the request
+// tracing id (if any) so that it could be attached to the realtime
+// record events until the returned untrack function is called.
+func trackRecordRequestId(c echo.Context, record *models.Record) (untrack func()) {
+	requestId, _ := c.Get(ContextRequestIdKey).(string)
+	if requestId == "" {
+		return func() {}
+	}
+
+	recordRequestIds.Store(record, requestId)
+
+	return func() {
+		recordRequestIds.Delete(record)
+	}
+}
+
+// recordVisibilities stores the subscriptions visibility of the records
+// that are currently being updated (keyed by the record model pointer).
+var recordVisibilities sync.Map
+
+type recordVisibility struct {
+	// original is the record state before the update
+	original *models.Record
+
+	// visible stores whether the record was accessible
+	// for the client subscription (keyed by "clientId/subscription")
+	visible map[string]bool
+}
+
+// trackRecordVisibility evaluates the access rules of the current
+// subscribers against the stored (aka. not updated yet) record state.
+func (api *realtimeApi) trackRecordVisibility(record *models.Record) {
 	collection := record.Collection()
-	if collection == nil {
-		return errors.New("Record collection not set.")
+	if collection == nil || record.IsNew() {
+		return
 	}
 
 	clients := api.app.SubscriptionsBroker().Clients()
 	if len(clients) == 0 {
-		return nil // no subscribers
+		return // no subscribers
 	}
 
-	// remove the expand from the broadcasted record because we don't
-	// know if the clients have access to view the expanded records
-	cleanRecord := *record
-	cleanRecord.SetExpand(nil)
-	cleanRecord.WithUnkownData(false)
-	cleanRecord.IgnoreEmailVisibility(false)
+	state := &recordVisibility{visible: map[string]bool{}}
+
+	for _, client := range clients {
+		for subscription, rule := range recordSubscriptionRules(collection, record.Id) {
+			if client.HasSubscription(subscription) {
+				state.visible[client.Id()+"/"+subscription] = api.canAccessRecord(client, record, rule)
+			}
+		}
+	}
+
+	if len(state.visible) == 0 {
+		return // no matching subscriptions
+	}
 
-	subscriptionRuleMap := map[string]*string{
-		(collection.Name + "/" + cleanRecord.Id): collection.ViewRule,
-		(collection.Id + "/" + cleanRecord.Id):   collection.ViewRule,
-		(collection.Name + "/*"):                 collection.ListRule,
-		(collection.Id + "/*"):                   collection.ListRule,
+	original, err := api.app.Dao().FindRecordById(collection.Id, record.Id)
+	if err != nil {
+		return
+	}
+	state.original = cleanBroadcastRecord(original)
+
+	recordVisibilities.Store(record, state)
+}
+
+// recordSubscriptionRules returns the access rules of
+// each subscription topic related to the provided record.
+func recordSubscriptionRules(collection *models.Collection, recordId string) map[string]*string {
+	return map[string]*string{
+		(collection.Name + "/" + recordId): collection.ViewRule,
+		(collection.Id + "/" + recordId):   collection.ViewRule,
+		(collection.Name + "/*"):           collection.ListRule,
+		(collection.Id + "/*"):             collection.ListRule,
 		// @deprecated: the same as the wildcard topic but kept for backward compatibility
 		collection.Name: collection.ListRule,
 		collection.Id:   collection.ListRule,
 	}
+}
+
+// cleanBroadcastRecord returns a shallow copy of the provided record
+// without the expand and the unknown data.
+//
+// The expand is removed because we don't know if the clients
+// have access to view the expanded records.
+func cleanBroadcastRecord(record *models.Record) *models.Record {
+	cleanRecord := *record
+	cleanRecord.SetExpand(nil)
+	cleanRecord.WithUnkownData(false)
+	cleanRecord.IgnoreEmailVisibility(false)
+	cleanRecord.SetHiddenFields()
+
+	return &cleanRecord
+}
 
-	data := &recordData{
-		Action: action,
-		Record: &cleanRecord,
+func (api *realtimeApi) broadcastRecord(action string, record *models.Record) error {
+	collection := record.Collection()
+	if collection == nil {
+		return errors.New("Record collection not set.")
 	}
 
-	dataBytes, err := json.Marshal(data)
-	if err != nil {
-		if api.app.IsDebug() {
-			log.Println(err)
+	// the record visibility before the update (if tracked)
+	var prevVisibility *recordVisibility
+	if action == "update" {
+		if v, ok := recordVisibilities.LoadAndDelete(record); ok {
+			prevVisibility, _ = v.(*recordVisibility)
 		}
-		return err
 	}
 
-	encodedData := string(dataBytes)
+	clients := api.app.SubscriptionsBroker().Clients()
+	if len(clients) == 0 {
+		return nil // no subscribers
+	}
+
+	cleanRecord := cleanBroadcastRecord(record)
+
+	var requestId string
+	if v, ok := recordRequestIds.Load(record); ok {
+		requestId, _ = v.(string)
+	}
+
+	// the encoded messages data (keyed by action)
+	encodedData := map[string]string{}
 
 	for _, client := range clients {
-		for subscription, rule := range subscriptionRuleMap {
+		for subscription, rule := range recordSubscriptionRules(collection, cleanRecord.Id) {
 			if !client.HasSubscription(subscription) {
 				continue
 			}
 
-			if !api.canAccessRecord(client, data.Record, rule) {
+			data := &recordData{
+				Action:    action,
+				Record:    cleanRecord,
+				RequestId: requestId,
+			}
+
+			visible := api.canAccessRecord(client, data.Record, rule)
+
+			// emit a synthetic create or delete event if
+			// the update changed the record visibility
+			if prevVisibility != nil {
+				wasVisible, tracked := prevVisibility.visible[client.Id()+"/"+subscription]
+				switch {
+				case tracked && wasVisible && !visible:
+					// send the original record because the client
+					// is no longer allowed to access the updated one
+					data.Action = "delete"
+					data.Record = prevVisibility.original
+					visible = true
+				case tracked && !wasVisible && visible:
+					data.Action = "create"
+				}
+			}
+
+			if !visible {
 				continue
 			}
 
+			if _, ok := encodedData[data.Action]; !ok {
+				dataBytes, err := json.Marshal(data)
+				if err != nil {
+					if api.app.IsDebug() {
+						log.Println(err)
+					}
+					return err
+				}
+				encodedData[data.Action] = string(dataBytes)
+			}
+
 			msg := subscriptions.Message{
 				Name: subscription,
-				Data: encodedData,
+				Data: encodedData[data.Action],
+			}
+
+			// hide the record fields whose read rule is not satisfied by the client
+			if hiddenFields := api.restrictedFields(client, data.Record); len(hiddenFields) > 0 {
+				restricted := *data.Record
+				restricted.SetHiddenFields(hiddenFields...)
+				data.Record = &restricted
+				if newData, err := json.Marshal(data); err == nil {
+					msg.Data = string(newData)
+				}
 			}
 
 			// ignore the auth record email visibility checks for
@@ -34,9 +34,23 @@ func bindRecordAuthApi(app core.App, rg *echo.Group) {
 	)
 
 	subGroup.GET("/auth-methods", api.authMethods)
-	subGroup.POST("/auth-refresh", api.authRefresh, RequireSameContextRecordAuth())
+	subGroup.POST("/auth-refresh", api.authRefresh)
 	subGroup.POST("/auth-with-oauth2", api.authWithOAuth2) // allow anyone so that we can link the OAuth2 profile with the authenticated record
 	subGroup.POST("/auth-with-password", api.authWithPassword, RequireGuestOnly())
+	subGroup.POST("/auth-anonymous", api.authAnonymous, RequireGuestOnly())
+	subGroup.POST("/upgrade-anonymous-auth", api.upgradeAnonymousAuth, RequireSameContextRecordAuth())
+	subGroup.POST("/request-login-link", api.requestLoginLink, RequireGuestOnly())
+	subGroup.POST("/confirm-login-link", api.confirmLoginLink, RequireGuestOnly())
+	subGroup.POST("/request-phone-otp", api.requestPhoneOtp, RequireGuestOnly())
+	subGroup.POST("/auth-with-phone-otp", api.authWithPhoneOtp, RequireGuestOnly())
+	subGroup.POST("/request-webauthn-auth", api.requestWebauthnAuth, RequireGuestOnly())
+	subGroup.POST("/auth-with-webauthn", api.authWithWebauthn, RequireGuestOnly())
+	subGroup.POST("/request-webauthn-registration", api.requestWebauthnRegistration, RequireSameContextRecordAuth())
+	subGroup.POST("/confirm-webauthn-registration", api.confirmWebauthnRegistration, RequireSameContextRecordAuth())
+	subGroup.GET("/saml/metadata", api.samlMetadata)
+	subGroup.GET("/saml/login", api.samlLogin)
+	subGroup.POST("/saml/acs", api.samlAcs)
+	subGroup.POST("/auth-with-saml", api.authWithSaml) // allow anyone so that we can link the SAML account with the authenticated record
 	subGroup.POST("/request-password-reset", api.requestPasswordReset)
 	subGroup.POST("/confirm-password-reset", api.confirmPasswordReset)
 	subGroup.POST("/request-verification", api.requestVerification)
@@ -45,6 +59,10 @@ func bindRecordAuthApi(app core.App, rg *echo.Group) {
 	subGroup.POST("/confirm-email-change", api.confirmEmailChange)
 	subGroup.GET("/records/:id/external-auths", api.listExternalAuths, RequireAdminOrOwnerAuth("id"))
 	subGroup.DELETE("/records/:id/external-auths/:provider", api.unlinkExternalAuth, RequireAdminOrOwnerAuth("id"))
+	subGroup.GET("/records/:id/webauthn-credentials", api.listWebauthnCredentials, RequireAdminOrOwnerAuth("id"))
+	subGroup.DELETE("/records/:id/webauthn-credentials/:credentialId", api.deleteWebauthnCredential, RequireAdminOrOwnerAuth("id"))
+	subGroup.GET("/records/:id/sessions", api.listSessions, RequireAdminOrOwnerAuth("id"))
+	subGroup.DELETE("/records/:id/sessions/:sessionId", api.revokeSession, RequireAdminOrOwnerAuth("id"))
 }
 
 type recordAuthApi struct {
@@ -52,11 +70,32 @@ type recordAuthApi struct {
 }
 
 func (api *recordAuthApi) authResponse(c echo.Context, authRecord *models.Record, meta any) error {
-	token, tokenErr := tokens.NewRecordAuthToken(api.app, authRecord)
+	var token, refreshToken string
+	var tokenErr error
+
+	if authRecord.Collection().AuthOptions().Sessions.Enabled {
+		token, refreshToken, tokenErr = api.newSessionTokens(c, api.app.Dao(), authRecord, nil)
+	} else {
+		token, tokenErr = tokens.NewRecordAuthToken(api.app, authRecord)
+	}
+
 	if tokenErr != nil {
 		return NewBadRequestError("Failed to create auth token.", tokenErr)
 	}
 
+	return api.authResponseWithTokens(c, authRecord, token, refreshToken, meta)
+}
+
+// authResponseWithTokens triggers the auth request hook and responds
+// with the provided auth record and tokens (the refreshToken is
+// returned only if not empty).
+func (api *recordAuthApi) authResponseWithTokens(
+	c echo.Context,
+	authRecord *models.Record,
+	token string,
+	refreshToken string,
+	meta any,
+) error {
 	event := &core.RecordAuthEvent{
 		HttpContext: c,
 		Record:      authRecord,
@@ -68,13 +107,18 @@ func (api *recordAuthApi) authResponse(c echo.Context, authRecord *models.Record
 		// allow always returning the email address of the authenticated account
 		e.Record.IgnoreEmailVisibility(true)
 
+		// create a copy of the cached request data and adjust it to the current auth record
+		requestData := *RequestData(e.HttpContext)
+		requestData.Admin = nil
+		requestData.AuthRecord = e.Record
+
+		if err := hideRecordsRestrictedFields(api.app.Dao(), []*models.Record{e.Record}, &requestData); err != nil && api.app.IsDebug() {
+			log.Println(err)
+		}
+
 		// expand record relations
 		expands := strings.Split(c.QueryParam(expandQueryParam), ",")
 		if len(expands) > 0 {
-			// create a copy of the cached request data and adjust it to the current auth record
-			requestData := *RequestData(e.HttpContext)
-			requestData.Admin = nil
-			requestData.AuthRecord = e.Record
 			failed := api.app.Dao().ExpandRecord(
 				e.Record,
 				expands,
@@ -90,6 +134,10 @@ func (api *recordAuthApi) authResponse(c echo.Context, authRecord *models.Record
 			"record": e.Record,
 		}
 
+		if refreshToken != "" {
+			result["refreshToken"] = refreshToken
+		}
+
 		if e.Meta != nil {
 			result["meta"] = e.Meta
 		}
@@ -99,12 +147,24 @@ func (api *recordAuthApi) authResponse(c echo.Context, authRecord *models.Record
 }
 
 func (api *recordAuthApi) authRefresh(c echo.Context) error {
-	record, _ := c.Get(ContextAuthRecordKey).(*models.Record)
-	if record == nil {
-		return NewNotFoundError("Missing auth record context.", nil)
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("Missing collection context.", nil)
 	}
 
-	return api.authResponse(c, record, nil)
+	// the session access tokens could be refreshed only with a refresh token
+	if collection.AuthOptions().Sessions.Enabled {
+		return api.authRefreshSession(c, collection)
+	}
+
+	return RequireSameContextRecordAuth()(func(c echo.Context) error {
+		record, _ := c.Get(ContextAuthRecordKey).(*models.Record)
+		if record == nil {
+			return NewNotFoundError("Missing auth record context.", nil)
+		}
+
+		return api.authResponse(c, record, nil)
+	})(c)
 }
 
 type providerInfo struct {
@@ -127,10 +187,22 @@ func (api *recordAuthApi) authMethods(c echo.Context) error {
 	result := struct {
 		UsernamePassword bool           `json:"usernamePassword"`
 		EmailPassword    bool           `json:"emailPassword"`
+		Webauthn         bool           `json:"webauthn"`
+		Anonymous        bool           `json:"anonymous"`
+		LoginLink        bool           `json:"loginLink"`
+		Phone            bool           `json:"phone"`
+		Saml             bool           `json:"saml"`
+		Ldap             bool           `json:"ldap"`
 		AuthProviders    []providerInfo `json:"authProviders"`
 	}{
 		UsernamePassword: authOptions.AllowUsernameAuth,
 		EmailPassword:    authOptions.AllowEmailAuth,
+		Webauthn:         authOptions.AllowWebauthnAuth,
+		Anonymous:        authOptions.AllowAnonymousAuth,
+		LoginLink:        authOptions.AllowLoginLinkAuth,
+		Phone:            authOptions.AllowPhoneAuth,
+		Saml:             authOptions.Saml.Enabled,
+		Ldap:             authOptions.Ldap.Enabled,
 		AuthProviders:    []providerInfo{},
 	}
 
@@ -254,10 +326,11 @@ func (api *recordAuthApi) authWithPassword(c echo.Context) error {
 	if readErr := c.Bind(form); readErr != nil {
 		return NewBadRequestError("An error occurred while loading the submitted data.", readErr)
 	}
+	form.SetIp(loginRequestIp(api.app, c))
 
 	record, submitErr := form.Submit()
 	if submitErr != nil {
-		return NewBadRequestError("Failed to authenticate.", submitErr)
+		return loginSubmitError(c, submitErr)
 	}
 
 	return api.authResponse(c, record, nil)
@@ -0,0 +1,45 @@
+package apis
+
+import (
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+func (api *recordAuthApi) authAnonymous(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("Missing collection context.", nil)
+	}
+
+	form := forms.NewRecordAnonymousLogin(api.app, collection)
+	if readErr := c.Bind(form); readErr != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", readErr)
+	}
+
+	record, submitErr := form.Submit()
+	if submitErr != nil {
+		return NewBadRequestError("Failed to authenticate.", submitErr)
+	}
+
+	return api.authResponse(c, record, map[string]any{"anonymous": true})
+}
+
+func (api *recordAuthApi) upgradeAnonymousAuth(c echo.Context) error {
+	record, _ := c.Get(ContextAuthRecordKey).(*models.Record)
+	if record == nil {
+		return NewNotFoundError("Missing auth record context.", nil)
+	}
+
+	form := forms.NewRecordAnonymousUpgrade(api.app, record)
+	if readErr := c.Bind(form); readErr != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", readErr)
+	}
+
+	if err := form.Submit(); err != nil {
+		return NewBadRequestError("Failed to upgrade the anonymous auth record.", err)
+	}
+
+	// the password change has invalidated the old auth token
+	return api.authResponse(c, record, nil)
+}
@@ -0,0 +1,74 @@
+package apis
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/routine"
+)
+
+func (api *recordAuthApi) requestLoginLink(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("Missing collection context.", nil)
+	}
+
+	if !collection.AuthOptions().AllowLoginLinkAuth {
+		return NewBadRequestError("The collection is not configured to allow login link authentication.", nil)
+	}
+
+	form := forms.NewRecordLoginLinkRequest(api.app, collection)
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", err)
+	}
+
+	if err := form.Validate(); err != nil {
+		return NewBadRequestError("An error occurred while validating the form.", err)
+	}
+
+	submitErr := form.Submit(func(next forms.InterceptorWithRecordNextFunc) forms.InterceptorWithRecordNextFunc {
+		return func(record *models.Record) error {
+			// run in background because we don't need to show the result to the client
+			routine.FireAndForget(func() {
+				if err := next(record); err != nil && api.app.IsDebug() {
+					log.Println(err)
+				}
+			})
+
+			return nil
+		}
+	})
+
+	if submitErr != nil && api.app.IsDebug() {
+		log.Println(submitErr)
+	}
+
+	// don't return the response error to prevent emails enumeration
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (api *recordAuthApi) confirmLoginLink(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("Missing collection context.", nil)
+	}
+
+	if !collection.AuthOptions().AllowLoginLinkAuth {
+		return NewBadRequestError("The collection is not configured to allow login link authentication.", nil)
+	}
+
+	form := forms.NewRecordLoginLinkConfirm(api.app, collection)
+	if readErr := c.Bind(form); readErr != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", readErr)
+	}
+
+	record, submitErr := form.Submit()
+	if submitErr != nil {
+		return NewBadRequestError("Failed to authenticate.", submitErr)
+	}
+
+	return api.authResponse(c, record, nil)
+}
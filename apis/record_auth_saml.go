@@ -0,0 +1,348 @@
+package apis
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/saml"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+// SamlRequestDuration is the max duration of a pending
+// SAML authentication request (aka. the IdP login).
+const SamlRequestDuration = 10 * time.Minute
+
+// SamlCodeDuration is the max duration of the one-time code
+// returned to the login redirect url.
+const SamlCodeDuration = 2 * time.Minute
+
+// samlMaxPendingItems is the max number of not expired
+// requests and codes that could be stored at the same time.
+const samlMaxPendingItems = 10000
+
+const samlStoreCacheKey = "@samlStore"
+
+var samlStoreMux sync.Mutex
+
+// saml store item kinds
+const (
+	samlKindRequest = "request"
+	samlKindCode    = "code"
+)
+
+// samlItem defines a single pending SAML authentication request or code.
+type samlItem struct {
+	kind          string
+	collectionId  string
+	redirectUrl   string
+	codeChallenge string
+	assertion     *saml.Assertion // only for codes
+	expires       time.Time
+}
+
+// samlStore is a concurrent safe single use SAML requests and codes store.
+type samlStore struct {
+	mux   sync.Mutex
+	items map[string]*samlItem
+}
+
+// add stores a new item (removing the expired ones).
+func (s *samlStore) add(key string, item *samlItem) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	now := time.Now()
+	for k, v := range s.items {
+		if now.After(v.expires) {
+			delete(s.items, k)
+		}
+	}
+
+	if len(s.items) >= samlMaxPendingItems {
+		return errors.New("too many pending SAML requests")
+	}
+
+	s.items[key] = item
+
+	return nil
+}
+
+// consume removes the specified item from the store and returns it
+// (nil if it is missing, expired or from a different kind).
+func (s *samlStore) consume(key string, kind string) *samlItem {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	item := s.items[key]
+	if item == nil || item.kind != kind {
+		return nil
+	}
+
+	delete(s.items, key)
+
+	if time.Now().After(item.expires) {
+		return nil
+	}
+
+	return item
+}
+
+// samlItems returns the app SAML requests and codes store
+// (it is created and stored in the app cache on first use).
+func samlItems(app core.App) *samlStore {
+	samlStoreMux.Lock()
+	defer samlStoreMux.Unlock()
+
+	store, _ := app.Cache().Get(samlStoreCacheKey).(*samlStore)
+	if store == nil {
+		store = &samlStore{items: map[string]*samlItem{}}
+		app.Cache().Set(samlStoreCacheKey, store)
+	}
+
+	return store
+}
+
+// samlServiceProvider returns the SAML service provider of the collection
+// based on the application url from the settings.
+func samlServiceProvider(app core.App, collection *models.Collection) (*saml.ServiceProvider, error) {
+	options := collection.AuthOptions().Saml
+	if !options.Enabled {
+		return nil, errors.New("SAML authentication is not allowed for the auth collection.")
+	}
+
+	appUrl, err := url.Parse(app.Settings().Meta.AppUrl)
+	if err != nil || appUrl.Scheme == "" || appUrl.Host == "" {
+		return nil, errors.New("invalid application url")
+	}
+
+	cert, err := saml.ParseCertificate(options.IdpCertificate)
+	if err != nil {
+		return nil, err
+	}
+
+	baseUrl := strings.TrimRight(app.Settings().Meta.AppUrl, "/") + "/api/collections/" + url.PathEscape(collection.Id) + "/saml"
+
+	return &saml.ServiceProvider{
+		EntityId:       baseUrl + "/metadata",
+		AcsUrl:         baseUrl + "/acs",
+		IdpEntityId:    options.IdpEntityId,
+		IdpSsoUrl:      options.IdpSsoUrl,
+		IdpCertificate: cert,
+	}, nil
+}
+
+// isAllowedSamlRedirectUrl checks whether the login redirect url
+// matches with one of the collection allowed redirect urls
+// (or with the application url origin if none are set).
+func isAllowedSamlRedirectUrl(app core.App, collection *models.Collection, redirectUrl string) bool {
+	redirect, err := url.Parse(redirectUrl)
+	if err != nil || redirect.Scheme == "" || redirect.Host == "" || redirect.User != nil {
+		return false
+	}
+
+	allowed := collection.AuthOptions().Saml.RedirectUrls
+	if len(allowed) == 0 {
+		allowed = []string{app.Settings().Meta.AppUrl}
+	}
+
+	for _, raw := range allowed {
+		u, err := url.Parse(raw)
+		if err != nil || !strings.EqualFold(u.Scheme, redirect.Scheme) || !strings.EqualFold(u.Host, redirect.Host) {
+			continue
+		}
+
+		// the path prefix must match on a path segment boundary
+		prefix := strings.TrimRight(u.Path, "/")
+		if prefix == "" || redirect.Path == prefix || strings.HasPrefix(redirect.Path, prefix+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// samlRedirectUrl appends the provided query params to the redirect url.
+func samlRedirectUrl(redirectUrl string, params map[string]string) string {
+	u, err := url.Parse(redirectUrl)
+	if err != nil {
+		return redirectUrl
+	}
+
+	query := u.Query()
+	for k, v := range params {
+		query.Set(k, v)
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+func (api *recordAuthApi) samlMetadata(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("Missing collection context.", nil)
+	}
+
+	sp, err := samlServiceProvider(api.app, collection)
+	if err != nil {
+		return NewBadRequestError("Failed to initialize the SAML service provider.", err)
+	}
+
+	metadata, err := sp.Metadata()
+	if err != nil {
+		return NewBadRequestError("Failed to generate the SAML metadata.", err)
+	}
+
+	return c.Blob(http.StatusOK, echo.MIMEApplicationXMLCharsetUTF8, metadata)
+}
+
+func (api *recordAuthApi) samlLogin(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("Missing collection context.", nil)
+	}
+
+	sp, err := samlServiceProvider(api.app, collection)
+	if err != nil {
+		return NewBadRequestError("Failed to initialize the SAML service provider.", err)
+	}
+
+	redirectUrl := c.QueryParam("redirectUrl")
+	codeChallenge := c.QueryParam("codeChallenge")
+
+	validationErr := validation.Errors{
+		"redirectUrl": validation.Validate(
+			redirectUrl,
+			validation.Required,
+			is.URL,
+			validation.By(func(value any) error {
+				if !isAllowedSamlRedirectUrl(api.app, collection, redirectUrl) {
+					return validation.NewError("validation_invalid_redirect_url", "The redirect url is not allowed.")
+				}
+				return nil
+			}),
+		),
+		"codeChallenge": validation.Validate(codeChallenge, validation.Required, validation.Length(43, 128)),
+	}.Filter()
+	if validationErr != nil {
+		return NewBadRequestError("Failed to initialize the SAML login.", validationErr)
+	}
+
+	requestId := saml.NewRequestId()
+
+	err = samlItems(api.app).add(requestId, &samlItem{
+		kind:          samlKindRequest,
+		collectionId:  collection.Id,
+		redirectUrl:   redirectUrl,
+		codeChallenge: codeChallenge,
+		expires:       time.Now().Add(SamlRequestDuration),
+	})
+	if err != nil {
+		return NewBadRequestError("Failed to initialize the SAML login.", err)
+	}
+
+	// the request id is also used as RelayState to find the pending request
+	authUrl, err := sp.AuthnRequestUrl(requestId, requestId)
+	if err != nil {
+		return NewBadRequestError("Failed to initialize the SAML login.", err)
+	}
+
+	return c.Redirect(http.StatusFound, authUrl)
+}
+
+func (api *recordAuthApi) samlAcs(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("Missing collection context.", nil)
+	}
+
+	requestId := c.FormValue("RelayState")
+
+	request := samlItems(api.app).consume(requestId, samlKindRequest)
+	if request == nil || request.collectionId != collection.Id {
+		return NewBadRequestError("Invalid or expired SAML request.", nil)
+	}
+
+	sp, err := samlServiceProvider(api.app, collection)
+	if err != nil {
+		return NewBadRequestError("Failed to initialize the SAML service provider.", err)
+	}
+
+	assertion, err := sp.ParseResponse(c.FormValue("SAMLResponse"), requestId)
+	if err != nil {
+		if api.app.IsDebug() {
+			log.Println(err)
+		}
+		return c.Redirect(http.StatusSeeOther, samlRedirectUrl(request.redirectUrl, map[string]string{
+			"error": "Failed to verify the SAML response.",
+		}))
+	}
+
+	code := security.RandomString(40)
+
+	err = samlItems(api.app).add(code, &samlItem{
+		kind:          samlKindCode,
+		collectionId:  collection.Id,
+		codeChallenge: request.codeChallenge,
+		assertion:     assertion,
+		expires:       time.Now().Add(SamlCodeDuration),
+	})
+	if err != nil {
+		return c.Redirect(http.StatusSeeOther, samlRedirectUrl(request.redirectUrl, map[string]string{
+			"error": "Failed to store the SAML authentication code.",
+		}))
+	}
+
+	return c.Redirect(http.StatusSeeOther, samlRedirectUrl(request.redirectUrl, map[string]string{
+		"code": code,
+	}))
+}
+
+func (api *recordAuthApi) authWithSaml(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("Missing collection context.", nil)
+	}
+
+	// load the logged auth record (if any)
+	var fallbackAuthRecord *models.Record
+	loggedAuthRecord, _ := c.Get(ContextAuthRecordKey).(*models.Record)
+	if loggedAuthRecord != nil && loggedAuthRecord.Collection().Id == collection.Id {
+		fallbackAuthRecord = loggedAuthRecord
+	}
+
+	form := forms.NewRecordSamlLogin(api.app, collection, fallbackAuthRecord)
+	if readErr := c.Bind(form); readErr != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", readErr)
+	}
+
+	if err := form.Validate(); err != nil {
+		return NewBadRequestError("Failed to authenticate.", err)
+	}
+
+	item := samlItems(api.app).consume(form.Code, samlKindCode)
+	if item == nil ||
+		item.collectionId != collection.Id ||
+		subtle.ConstantTimeCompare([]byte(security.S256Challenge(form.CodeVerifier)), []byte(item.codeChallenge)) != 1 {
+		return NewBadRequestError("Invalid or expired SAML code.", nil)
+	}
+
+	record, submitErr := form.Submit(item.assertion)
+	if submitErr != nil {
+		return NewBadRequestError("Failed to authenticate.", submitErr)
+	}
+
+	return api.authResponse(c, record, item.assertion)
+}
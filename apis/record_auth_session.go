@@ -0,0 +1,174 @@
+package apis
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tokens"
+	"github.com/pocketbase/pocketbase/tools/routine"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/spf13/cast"
+)
+
+// authSessionMaxUserAgentLength is the max stored session user agent length.
+const authSessionMaxUserAgentLength = 300
+
+// newSessionTokens creates a new auth record session (or rotates the
+// refresh token of the provided one) and returns its new access and
+// refresh tokens.
+func (api *recordAuthApi) newSessionTokens(
+	c echo.Context,
+	dao *daos.Dao,
+	record *models.Record,
+	session *models.AuthSession,
+) (string, string, error) {
+	if session == nil {
+		ip, _, _ := net.SplitHostPort(c.Request().RemoteAddr)
+
+		userAgent := c.Request().UserAgent()
+		if len(userAgent) > authSessionMaxUserAgentLength {
+			userAgent = userAgent[:authSessionMaxUserAgentLength]
+		}
+
+		session = &models.AuthSession{
+			UserAgent: userAgent,
+			Ip:        realUserIp(c.Request(), ip),
+		}
+		session.SetAuthRecord(record)
+	}
+
+	duration := time.Duration(record.Collection().AuthOptions().Sessions.RefreshTokenDuration) * time.Second
+	expires, err := types.ParseDateTime(time.Now().Add(duration))
+	if err != nil {
+		return "", "", err
+	}
+
+	refreshToken := session.RefreshToken()
+	session.Expires = expires
+
+	if err := dao.SaveAuthSession(session); err != nil {
+		return "", "", err
+	}
+
+	token, err := tokens.NewRecordSessionAuthToken(api.app, record, session)
+	if err != nil {
+		return "", "", err
+	}
+
+	api.deleteExpiredSessions()
+
+	return token, refreshToken, nil
+}
+
+// deleteExpiredSessions deletes in the background
+// the expired auth sessions (at most once per day).
+func (api *recordAuthApi) deleteExpiredSessions() {
+	now := time.Now()
+	lastDeletedAt := cast.ToTime(api.app.Cache().Get("lastAuthSessionsDeletedAt"))
+	if now.Sub(lastDeletedAt) < 24*time.Hour {
+		return
+	}
+
+	api.app.Cache().Set("lastAuthSessionsDeletedAt", now)
+
+	dao := api.app.Dao()
+
+	routine.FireAndForget(func() {
+		if err := dao.DeleteExpiredAuthSessions(now); err != nil && api.app.IsDebug() {
+			log.Println("Auth sessions delete failed:", err)
+		}
+	})
+}
+
+// authRefreshSession rotates the session refresh token
+// and responds with the new access and refresh tokens.
+func (api *recordAuthApi) authRefreshSession(c echo.Context, collection *models.Collection) error {
+	form := forms.NewRecordSessionRefresh(api.app, collection)
+	if readErr := c.Bind(form); readErr != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", readErr)
+	}
+
+	var record *models.Record
+	var token, refreshToken string
+
+	// the refresh token is valid only for a single rotation
+	txErr := api.app.Dao().RunInTransaction(func(txDao *daos.Dao) error {
+		form.SetDao(txDao)
+
+		var session *models.AuthSession
+		var err error
+
+		record, session, err = form.Submit()
+		if err != nil {
+			return err
+		}
+
+		token, refreshToken, err = api.newSessionTokens(c, txDao, record, session)
+
+		return err
+	})
+	if txErr != nil {
+		return NewUnauthorizedError("Failed to refresh the auth session.", txErr)
+	}
+
+	return api.authResponseWithTokens(c, record, token, refreshToken, nil)
+}
+
+func (api *recordAuthApi) listSessions(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("Missing collection context.", nil)
+	}
+
+	id := c.PathParam("id")
+	if id == "" {
+		return NewNotFoundError("", nil)
+	}
+
+	record, err := api.app.Dao().FindRecordById(collection.Id, id)
+	if err != nil || record == nil {
+		return NewNotFoundError("", err)
+	}
+
+	sessions, err := api.app.Dao().FindAllAuthSessionsByRecord(record)
+	if err != nil {
+		return NewBadRequestError("Failed to fetch the sessions for the specified auth record.", err)
+	}
+
+	return c.JSON(http.StatusOK, sessions)
+}
+
+func (api *recordAuthApi) revokeSession(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("Missing collection context.", nil)
+	}
+
+	id := c.PathParam("id")
+	sessionId := c.PathParam("sessionId")
+	if id == "" || sessionId == "" {
+		return NewNotFoundError("", nil)
+	}
+
+	record, err := api.app.Dao().FindRecordById(collection.Id, id)
+	if err != nil || record == nil {
+		return NewNotFoundError("", err)
+	}
+
+	session, err := api.app.Dao().FindAuthSessionByRecordAndId(record, sessionId)
+	if err != nil {
+		return NewNotFoundError("Missing auth session.", err)
+	}
+
+	if err := api.app.Dao().DeleteAuthSession(session); err != nil {
+		return NewBadRequestError("Failed to revoke the auth session.", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
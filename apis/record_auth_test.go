@@ -8,6 +8,7 @@ import (
 
 	"github.com/labstack/echo/v5"
 	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/tests"
 	"github.com/pocketbase/pocketbase/tools/types"
 )
@@ -54,6 +55,12 @@ func TestRecordAuthMethodsList(t *testing.T) {
 			ExpectedContent: []string{
 				`"usernamePassword":false`,
 				`"emailPassword":true`,
+				`"webauthn":false`,
+				`"anonymous":false`,
+				`"loginLink":false`,
+				`"phone":false`,
+				`"saml":false`,
+				`"ldap":false`,
 				`"authProviders":[]`,
 			},
 		},
@@ -233,6 +240,107 @@ func TestRecordAuthWithPassword(t *testing.T) {
 	}
 }
 
+func TestRecordAuthWithPasswordLdap(t *testing.T) {
+	server, err := tests.NewTestLdapServer(
+		&tests.TestLdapEntry{
+			Dn:       "uid=jdoe,ou=people,dc=example,dc=com",
+			Password: "ldap_pass",
+			Attributes: map[string][]string{
+				"uid":  {"jdoe"},
+				"mail": {"ldap_new@example.com"},
+				"cn":   {"John Doe"},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	enableLdap := func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+		users, err := app.Dao().FindCollectionByNameOrId("users")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		options := users.AuthOptions()
+		options.Ldap = models.CollectionLdapOptions{
+			Enabled:        true,
+			Url:            server.Url,
+			BaseDn:         "ou=people,dc=example,dc=com",
+			UserFilter:     "(uid={identity})",
+			EmailAttribute: "mail",
+			FieldsMapping:  map[string]string{"cn": "name"},
+		}
+		users.SetOptions(options)
+
+		if err := daos.New(app.Dao().DB()).SaveCollection(users); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:   "directory user with invalid password",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/auth-with-password",
+			Body: strings.NewReader(`{
+				"identity":"jdoe",
+				"password":"1234567890"
+			}`),
+			BeforeTestFunc: enableLdap,
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"data":{}`,
+			},
+		},
+		{
+			Name:   "missing directory user with valid local password",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/auth-with-password",
+			Body: strings.NewReader(`{
+				"identity":"test@example.com",
+				"password":"1234567890"
+			}`),
+			BeforeTestFunc: enableLdap,
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"token":"`,
+				`"id":"4q1xlclmfloku33"`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordAuthRequest": 1,
+			},
+		},
+		{
+			Name:   "directory user with valid password",
+			Method: http.MethodPost,
+			Url:    "/api/collections/users/auth-with-password",
+			Body: strings.NewReader(`{
+				"identity":"jdoe",
+				"password":"ldap_pass"
+			}`),
+			BeforeTestFunc: enableLdap,
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"token":"`,
+				`"email":"ldap_new@example.com"`,
+				`"name":"John Doe"`,
+				`"verified":true`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordAuthRequest": 1,
+				"OnModelBeforeCreate": 2, // the record and its external auth
+				"OnModelAfterCreate":  2,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
 func TestRecordAuthRefresh(t *testing.T) {
 	scenarios := []tests.ApiScenario{
 		{
@@ -0,0 +1,359 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/webauthn"
+)
+
+// WebauthnChallengeDuration is the max duration of a WebAuthn
+// registration or authentication ceremony.
+const WebauthnChallengeDuration = 5 * time.Minute
+
+// webauthnMaxPendingChallenges is the max number of not expired
+// challenges that could be stored at the same time.
+const webauthnMaxPendingChallenges = 10000
+
+const webauthnChallengesCacheKey = "@webauthnChallenges"
+
+var webauthnChallengesMux sync.Mutex
+
+// webauthn ceremony actions
+const (
+	webauthnActionRegister = "register"
+	webauthnActionAuth     = "auth"
+)
+
+// webauthnChallenge defines a single pending ceremony challenge.
+type webauthnChallenge struct {
+	action       string
+	collectionId string
+	recordId     string // only for registration
+	expires      time.Time
+}
+
+// webauthnChallengeStore is a concurrent safe single use challenges store.
+type webauthnChallengeStore struct {
+	mux   sync.Mutex
+	items map[string]*webauthnChallenge
+}
+
+// add stores a new challenge (removing the expired ones).
+func (s *webauthnChallengeStore) add(challenge string, item *webauthnChallenge) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	now := time.Now()
+	for k, v := range s.items {
+		if now.After(v.expires) {
+			delete(s.items, k)
+		}
+	}
+
+	if len(s.items) >= webauthnMaxPendingChallenges {
+		return errors.New("too many pending challenges")
+	}
+
+	s.items[challenge] = item
+
+	return nil
+}
+
+// consume removes the specified challenge from the store and returns it
+// (nil if it is missing or expired).
+func (s *webauthnChallengeStore) consume(challenge string) *webauthnChallenge {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	item := s.items[challenge]
+	if item == nil {
+		return nil
+	}
+
+	delete(s.items, challenge)
+
+	if time.Now().After(item.expires) {
+		return nil
+	}
+
+	return item
+}
+
+// webauthnChallenges returns the app WebAuthn challenges store
+// (it is created and stored in the app cache on first use).
+func webauthnChallenges(app core.App) *webauthnChallengeStore {
+	webauthnChallengesMux.Lock()
+	defer webauthnChallengesMux.Unlock()
+
+	store, _ := app.Cache().Get(webauthnChallengesCacheKey).(*webauthnChallengeStore)
+	if store == nil {
+		store = &webauthnChallengeStore{items: map[string]*webauthnChallenge{}}
+		app.Cache().Set(webauthnChallengesCacheKey, store)
+	}
+
+	return store
+}
+
+// webauthnRelyingParty returns the app WebAuthn relying party
+// based on the application url from the settings.
+func webauthnRelyingParty(app core.App) (*webauthn.RelyingParty, error) {
+	appUrl, err := url.Parse(app.Settings().Meta.AppUrl)
+	if err != nil || appUrl.Hostname() == "" {
+		return nil, errors.New("invalid application url")
+	}
+
+	return &webauthn.RelyingParty{
+		Id:      appUrl.Hostname(),
+		Origins: []string{appUrl.Scheme + "://" + appUrl.Host},
+	}, nil
+}
+
+// newWebauthnChallenge generates and stores a new ceremony challenge.
+func (api *recordAuthApi) newWebauthnChallenge(action string, collection *models.Collection, recordId string) (string, error) {
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return "", err
+	}
+
+	err = webauthnChallenges(api.app).add(challenge, &webauthnChallenge{
+		action:       action,
+		collectionId: collection.Id,
+		recordId:     recordId,
+		expires:      time.Now().Add(WebauthnChallengeDuration),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return challenge, nil
+}
+
+type webauthnCredentialDescriptor struct {
+	Type string `json:"type"`
+	Id   string `json:"id"`
+}
+
+func (api *recordAuthApi) requestWebauthnRegistration(c echo.Context) error {
+	record, _ := c.Get(ContextAuthRecordKey).(*models.Record)
+	if record == nil {
+		return NewNotFoundError("Missing auth record context.", nil)
+	}
+
+	if !record.Collection().AuthOptions().AllowWebauthnAuth {
+		return NewBadRequestError("The collection is not configured to allow WebAuthn authentication.", nil)
+	}
+
+	rp, err := webauthnRelyingParty(api.app)
+	if err != nil {
+		return NewBadRequestError("Failed to initialize the WebAuthn relying party.", err)
+	}
+
+	credentials, err := api.app.Dao().FindAllWebauthnCredentialsByRecord(record)
+	if err != nil {
+		return NewBadRequestError("Failed to fetch the auth record credentials.", err)
+	}
+
+	challenge, err := api.newWebauthnChallenge(webauthnActionRegister, record.Collection(), record.Id)
+	if err != nil {
+		return NewBadRequestError("Failed to create WebAuthn challenge.", err)
+	}
+
+	exclude := make([]webauthnCredentialDescriptor, len(credentials))
+	for i, credential := range credentials {
+		exclude[i] = webauthnCredentialDescriptor{Type: "public-key", Id: credential.CredentialId}
+	}
+
+	params := make([]map[string]any, len(webauthn.SupportedAlgorithms))
+	for i, alg := range webauthn.SupportedAlgorithms {
+		params[i] = map[string]any{"type": "public-key", "alg": alg}
+	}
+
+	userName := record.Email()
+	if userName == "" {
+		userName = record.Username()
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"challenge": challenge,
+		"rp": map[string]any{
+			"id":   rp.Id,
+			"name": api.app.Settings().Meta.AppName,
+		},
+		"user": map[string]any{
+			"id":          webauthn.EncodeBase64([]byte(record.Id)),
+			"name":        userName,
+			"displayName": record.Username(),
+		},
+		"pubKeyCredParams":   params,
+		"timeout":            WebauthnChallengeDuration.Milliseconds(),
+		"attestation":        "none",
+		"excludeCredentials": exclude,
+		"authenticatorSelection": map[string]any{
+			"residentKey":        "required",
+			"requireResidentKey": true,
+			"userVerification":   "required",
+		},
+	})
+}
+
+func (api *recordAuthApi) confirmWebauthnRegistration(c echo.Context) error {
+	record, _ := c.Get(ContextAuthRecordKey).(*models.Record)
+	if record == nil {
+		return NewNotFoundError("Missing auth record context.", nil)
+	}
+
+	rp, err := webauthnRelyingParty(api.app)
+	if err != nil {
+		return NewBadRequestError("Failed to initialize the WebAuthn relying party.", err)
+	}
+
+	form := forms.NewRecordWebauthnRegistration(api.app, record, rp)
+	if readErr := c.Bind(form); readErr != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", readErr)
+	}
+
+	if err := form.Validate(); err != nil {
+		return NewBadRequestError("An error occurred while validating the form.", err)
+	}
+
+	challenge := webauthnChallenges(api.app).consume(form.Challenge)
+	if challenge == nil ||
+		challenge.action != webauthnActionRegister ||
+		challenge.collectionId != record.Collection().Id ||
+		challenge.recordId != record.Id {
+		return NewBadRequestError("Invalid or expired WebAuthn challenge.", nil)
+	}
+
+	credential, submitErr := form.Submit()
+	if submitErr != nil {
+		return NewBadRequestError("Failed to register the WebAuthn credential.", submitErr)
+	}
+
+	return c.JSON(http.StatusOK, credential)
+}
+
+func (api *recordAuthApi) requestWebauthnAuth(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("Missing collection context.", nil)
+	}
+
+	if !collection.AuthOptions().AllowWebauthnAuth {
+		return NewBadRequestError("The collection is not configured to allow WebAuthn authentication.", nil)
+	}
+
+	rp, err := webauthnRelyingParty(api.app)
+	if err != nil {
+		return NewBadRequestError("Failed to initialize the WebAuthn relying party.", err)
+	}
+
+	challenge, err := api.newWebauthnChallenge(webauthnActionAuth, collection, "")
+	if err != nil {
+		return NewBadRequestError("Failed to create WebAuthn challenge.", err)
+	}
+
+	// the credentials are discoverable so that there is no need to specify them
+	return c.JSON(http.StatusOK, map[string]any{
+		"challenge":        challenge,
+		"rpId":             rp.Id,
+		"timeout":          WebauthnChallengeDuration.Milliseconds(),
+		"userVerification": "required",
+		"allowCredentials": []webauthnCredentialDescriptor{},
+	})
+}
+
+func (api *recordAuthApi) authWithWebauthn(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("Missing collection context.", nil)
+	}
+
+	rp, err := webauthnRelyingParty(api.app)
+	if err != nil {
+		return NewBadRequestError("Failed to initialize the WebAuthn relying party.", err)
+	}
+
+	form := forms.NewRecordWebauthnLogin(api.app, collection, rp)
+	if readErr := c.Bind(form); readErr != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", readErr)
+	}
+
+	if err := form.Validate(); err != nil {
+		return NewBadRequestError("Failed to authenticate.", err)
+	}
+
+	challenge := webauthnChallenges(api.app).consume(form.Challenge)
+	if challenge == nil ||
+		challenge.action != webauthnActionAuth ||
+		challenge.collectionId != collection.Id {
+		return NewBadRequestError("Failed to authenticate.", errors.New("Invalid or expired WebAuthn challenge."))
+	}
+
+	record, submitErr := form.Submit()
+	if submitErr != nil {
+		return NewBadRequestError("Failed to authenticate.", submitErr)
+	}
+
+	return api.authResponse(c, record, nil)
+}
+
+func (api *recordAuthApi) listWebauthnCredentials(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("Missing collection context.", nil)
+	}
+
+	id := c.PathParam("id")
+	if id == "" {
+		return NewNotFoundError("", nil)
+	}
+
+	record, err := api.app.Dao().FindRecordById(collection.Id, id)
+	if err != nil || record == nil {
+		return NewNotFoundError("", err)
+	}
+
+	credentials, err := api.app.Dao().FindAllWebauthnCredentialsByRecord(record)
+	if err != nil {
+		return NewBadRequestError("Failed to fetch the WebAuthn credentials for the specified auth record.", err)
+	}
+
+	return c.JSON(http.StatusOK, credentials)
+}
+
+func (api *recordAuthApi) deleteWebauthnCredential(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("Missing collection context.", nil)
+	}
+
+	id := c.PathParam("id")
+	credentialId := c.PathParam("credentialId")
+	if id == "" || credentialId == "" {
+		return NewNotFoundError("", nil)
+	}
+
+	record, err := api.app.Dao().FindRecordById(collection.Id, id)
+	if err != nil || record == nil {
+		return NewNotFoundError("", err)
+	}
+
+	credential, err := api.app.Dao().FindWebauthnCredentialByRecordAndId(record, credentialId)
+	if err != nil {
+		return NewNotFoundError("Missing WebAuthn credential.", err)
+	}
+
+	if err := api.app.Dao().DeleteWebauthnCredential(credential); err != nil {
+		return NewBadRequestError("Failed to delete the WebAuthn credential.", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
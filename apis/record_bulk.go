@@ -0,0 +1,283 @@
+package apis
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/resolvers"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+// recordsBulkRequest defines the records bulk update and delete request body.
+type recordsBulkRequest struct {
+	Filter string         `json:"filter"`
+	Data   map[string]any `json:"data"`
+}
+
+// bulkUpdate updates all collection records matching the body filter
+// (and the collection update rule) with the body data in a single transaction.
+//
+// Note that the records version (if versioning is enabled) is not checked.
+func (api *recordApi) bulkUpdate(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("", "Missing collection context.")
+	}
+
+	requestData := RequestData(c)
+
+	if requestData.Admin == nil && collection.UpdateRule == nil {
+		// only admins can access if the rule is nil
+		return NewForbiddenError("Only admins can perform this action.", nil)
+	}
+
+	body, err := api.loadBulkRequest(c, true)
+	if err != nil {
+		return err
+	}
+
+	// the same version can't be matched by multiple records
+	delete(body.Data, schema.FieldNameVersion)
+
+	// resolve the @request.data.* rule fields against the update data
+	// and not against the whole bulk request body (aka. {filter, data})
+	updateRequestData := *requestData
+	updateRequestData.Data = body.Data
+	requestData = &updateRequestData
+
+	filterFunc, err := api.bulkFilterFunc(requestData, collection, body.Filter, collection.UpdateRule)
+	if err != nil {
+		return err
+	}
+
+	// executed only after successful commit
+	events := []*core.RecordUpdateEvent{}
+	submittedForms := []*forms.RecordUpsert{}
+
+	total, err := api.app.Dao().UpdateRecordsByFilter(collection, func(txDao *daos.Dao, record *models.Record) error {
+		setRecordHistoryAuth(record, requestData)
+		form := forms.NewRecordUpsert(api.app, record)
+		form.SetDao(txDao)
+		form.SetFullManageAccess(requestData.Admin != nil || hasAuthManageAccess(txDao, record, requestData))
+		form.SetAuthRecord(requestData.AuthRecord)
+		form.SetApiKey(requestData.ApiKey)
+		form.SetCheckFieldWriteRules(requestData.Admin == nil)
+		form.SetDeferFilesCommit(true) // committed together with the bulk transaction
+
+		if err := form.LoadData(body.Data); err != nil {
+			return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+		}
+
+		event := &core.RecordUpdateEvent{
+			HttpContext: c,
+			Record:      record,
+		}
+
+		submitErr := form.Submit(func(next forms.InterceptorNextFunc) forms.InterceptorNextFunc {
+			return func() error {
+				if err := moderateRecordContent(api.app, c, record); err != nil {
+					return err
+				}
+
+				return api.app.OnRecordBeforeUpdateRequest().Trigger(event, func(e *core.RecordUpdateEvent) error {
+					return next()
+				})
+			}
+		})
+		if submitErr != nil {
+			return submitErr
+		}
+
+		events = append(events, event)
+		submittedForms = append(submittedForms, form)
+
+		return nil
+	}, filterFunc)
+
+	if err != nil {
+		return bulkError("Failed to update the records.", err)
+	}
+
+	for _, form := range submittedForms {
+		if err := commitRecordFiles(api.app, form); err != nil && api.app.IsDebug() {
+			log.Println(err)
+		}
+	}
+
+	for _, event := range events {
+		api.app.OnRecordAfterUpdateRequest().Trigger(event)
+	}
+
+	return c.JSON(http.StatusOK, map[string]int{"total": total})
+}
+
+// bulkDelete deletes (or only marks as deleted if soft delete is enabled)
+// all collection records matching the body filter (and the collection
+// delete rule) in a single transaction.
+func (api *recordApi) bulkDelete(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("", "Missing collection context.")
+	}
+
+	requestData := RequestData(c)
+
+	if requestData.Admin == nil && collection.DeleteRule == nil {
+		// only admins can access if the rule is nil
+		return NewForbiddenError("Only admins can perform this action.", nil)
+	}
+
+	body, err := api.loadBulkRequest(c, false)
+	if err != nil {
+		return err
+	}
+
+	filterFunc, err := api.bulkFilterFunc(requestData, collection, body.Filter, collection.DeleteRule)
+	if err != nil {
+		return err
+	}
+
+	// executed only after successful commit
+	events := []*core.RecordDeleteEvent{}
+
+	total, err := api.app.Dao().DeleteRecordsByFilter(collection, func(txDao *daos.Dao, record *models.Record) error {
+		setRecordHistoryAuth(record, requestData)
+
+		event := &core.RecordDeleteEvent{
+			HttpContext: c,
+			Record:      record,
+		}
+
+		handlerErr := api.app.OnRecordBeforeDeleteRequest().Trigger(event, func(e *core.RecordDeleteEvent) error {
+			if e.Record.Collection().SoftDeleteOptions().Enabled {
+				return txDao.SoftDeleteRecord(e.Record)
+			}
+			return txDao.DeleteRecord(e.Record)
+		})
+		if handlerErr != nil {
+			return handlerErr
+		}
+
+		events = append(events, event)
+
+		return nil
+	}, filterFunc)
+
+	if err != nil {
+		return bulkError("Failed to delete the records. Make sure that the records are not part of a required relation reference.", err)
+	}
+
+	for _, event := range events {
+		api.app.OnRecordAfterDeleteRequest().Trigger(event)
+	}
+
+	return c.JSON(http.StatusOK, map[string]int{"total": total})
+}
+
+// loadBulkRequest loads and validates the records bulk request body.
+func (api *recordApi) loadBulkRequest(c echo.Context, requireData bool) (*recordsBulkRequest, error) {
+	body := &recordsBulkRequest{}
+	if err := c.Bind(body); err != nil {
+		return nil, NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	err := validation.ValidateStruct(body,
+		validation.Field(&body.Filter, validation.Required),
+		validation.Field(&body.Data, validation.When(requireData, validation.Required)),
+	)
+	if err != nil {
+		return nil, NewBadRequestError("", err)
+	}
+
+	return body, nil
+}
+
+// bulkFilterFunc returns a records query modifier that applies the
+// bulk request filter and the provided collection API rule (for non-admins).
+//
+// Similar to the records list, non-admins are not allowed to filter
+// by schema fields with read rule (see checkForRestrictedQueryFields).
+// They are also not allowed to bulk update or delete the records
+// of a presets only collection (see checkForPresetsOnlyFilter).
+func (api *recordApi) bulkFilterFunc(
+	requestData *models.RequestData,
+	collection *models.Collection,
+	filter string,
+	rule *string,
+) (func(q *dbx.SelectQuery) error, error) {
+	if requestData.Admin == nil && collection.PresetsOptions().Only {
+		return nil, NewForbiddenError("Only admins can filter the collection records without a preset.", nil)
+	}
+
+	if requestData.Admin == nil &&
+		(strings.Contains(filter, "@collection.") || strings.Contains(filter, "@request.")) {
+		return nil, NewForbiddenError("Only admins can filter by @collection and @request fields.", nil)
+	}
+
+	resolver := resolvers.NewRecordFieldResolver(
+		api.app.Dao(),
+		collection,
+		requestData,
+		// hidden fields are searchable only by admins
+		requestData.Admin != nil,
+	)
+
+	// limit the filter complexity for non-admins
+	if requestData.Admin == nil {
+		resolver.
+			SetMaxNestedRels(api.app.Settings().Filter.MaxNestedRels).
+			SetMaxJoins(api.app.Settings().Filter.MaxJoins)
+	}
+
+	exprs := []dbx.Expression{}
+
+	filterExpr, err := search.FilterData(filter).BuildExpr(resolver)
+	if err != nil {
+		return nil, NewBadRequestError("Invalid filter.", err)
+	}
+	exprs = append(exprs, filterExpr)
+
+	// forbid filtering by schema fields with read rule since the
+	// returned total could be used to guess their hidden values
+	// (checked before resolving the collection rule fields)
+	if requestData.Admin == nil {
+		if restricted := resolver.RestrictedFields(); len(restricted) > 0 {
+			return nil, NewForbiddenError(fmt.Sprintf("Only admins can filter by the restricted %q field.", restricted[0]), nil)
+		}
+	}
+
+	if requestData.Admin == nil && rule != nil && *rule != "" {
+		ruleExpr, err := search.FilterData(*rule).BuildExpr(resolver)
+		if err != nil {
+			return nil, NewBadRequestError("Invalid collection rule.", err)
+		}
+		exprs = append(exprs, ruleExpr)
+	}
+
+	return func(q *dbx.SelectQuery) error {
+		resolver.UpdateQuery(q)
+		q.AndWhere(dbx.And(exprs...))
+		return nil
+	}, nil
+}
+
+// bulkError normalizes the error of a failed records bulk operation.
+func bulkError(message string, err error) *ApiError {
+	var apiErr *ApiError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	return NewBadRequestError(message, err)
+}
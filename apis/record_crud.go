@@ -1,9 +1,11 @@
 package apis
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/labstack/echo/v5"
@@ -12,11 +14,21 @@ import (
 	"github.com/pocketbase/pocketbase/daos"
 	"github.com/pocketbase/pocketbase/forms"
 	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
 	"github.com/pocketbase/pocketbase/resolvers"
+	"github.com/pocketbase/pocketbase/tools/list"
 	"github.com/pocketbase/pocketbase/tools/search"
 )
 
-const expandQueryParam = "expand"
+const (
+	expandQueryParam   = "expand"
+	fieldsQueryParam   = "fields"
+	distinctQueryParam = "distinct"
+	facetsQueryParam   = "facets"
+	presetQueryParam   = "preset"
+
+	withDeletedQueryParam = "withDeleted"
+)
 
 // bindRecordCrudApi registers the record crud api endpoints and
 // the corresponding handlers.
@@ -29,11 +41,18 @@ func bindRecordCrudApi(app core.App, rg *echo.Group) {
 		LoadCollectionContext(app),
 	)
 
-	subGroup.GET("/records", api.list)
-	subGroup.POST("/records", api.create)
-	subGroup.GET("/records/:id", api.view)
-	subGroup.PATCH("/records/:id", api.update)
-	subGroup.DELETE("/records/:id", api.delete)
+	subGroup.GET("/records", api.list, LoadRulesDebugContext(app))
+	subGroup.GET("/records/aggregate", api.aggregate)
+	subGroup.GET("/records/explain", api.explain, RequireAdminAuth())
+	subGroup.POST("/records", api.create, LoadRulesDebugContext(app))
+	subGroup.PATCH("/records", api.bulkUpdate)
+	subGroup.DELETE("/records", api.bulkDelete)
+	subGroup.GET("/records/:id", api.view, LoadRulesDebugContext(app))
+	subGroup.PATCH("/records/:id", api.update, LoadRulesDebugContext(app))
+	subGroup.DELETE("/records/:id", api.delete, LoadRulesDebugContext(app))
+	subGroup.POST("/records/:id/restore", api.restore, RequireAdminAuth())
+	subGroup.POST("/records/:id/duplicate", api.duplicate, RequireAdminAuth())
+	subGroup.GET("/records/:id/history", api.history, RequireAdminAuth())
 }
 
 type recordApi struct {
@@ -51,11 +70,21 @@ func (api *recordApi) list(c echo.Context) error {
 		return err
 	}
 
+	// forbid users and guests to submit a raw filter for presets only collections
+	if err := api.checkForPresetsOnlyFilter(c, collection); err != nil {
+		return err
+	}
+
+	// forbid users and guests to filter/sort by read restricted fields
+	if err := api.checkForRestrictedQueryFields(c, collection); err != nil {
+		return err
+	}
+
 	requestData := RequestData(c)
 
 	if requestData.Admin == nil && collection.ListRule == nil {
 		// only admins can access if the rule is nil
-		return NewForbiddenError("Only admins can perform this action.", nil)
+		return api.ruleFailureError(c, collection, "listRule", nil, NewForbiddenError("Only admins can perform this action.", nil))
 	}
 
 	fieldsResolver := resolvers.NewRecordFieldResolver(
@@ -66,8 +95,52 @@ func (api *recordApi) list(c echo.Context) error {
 		requestData.Admin != nil,
 	)
 
+	// limit the filter and sort complexity for non-admins
+	if requestData.Admin == nil {
+		fieldsResolver.
+			SetMaxNestedRels(api.app.Settings().Filter.MaxNestedRels).
+			SetMaxJoins(api.app.Settings().Filter.MaxJoins)
+	}
+
+	if distinctField := c.QueryParam(distinctQueryParam); distinctField != "" {
+		return api.listDistinct(c, collection, fieldsResolver, distinctField)
+	}
+
+	if facetFields := c.QueryParam(facetsQueryParam); facetFields != "" {
+		return api.listFacets(c, collection, fieldsResolver, facetFields)
+	}
+
+	withDeleted, err := api.withDeleted(c)
+	if err != nil {
+		return err
+	}
+
+	query := api.app.Dao().RecordQuery(collection)
+	if withDeleted {
+		query = api.app.Dao().RecordQueryWithDeleted(collection)
+	}
+
+	// load only the columns of the requested fields (if any)
+	if columns := recordProjectionColumns(
+		api.app.Dao(),
+		collection,
+		c.QueryParam(fieldsQueryParam),
+		c.QueryParam(expandQueryParam),
+	); len(columns) > 0 {
+		query.Select(columns...)
+	}
+
+	// apply the saved filter preset (if any)
+	if presetName := c.QueryParam(presetQueryParam); presetName != "" {
+		presetExpr, err := api.presetFilterExpr(c, collection, fieldsResolver, presetName)
+		if err != nil {
+			return err
+		}
+		query.AndWhere(presetExpr)
+	}
+
 	searchProvider := search.NewProvider(fieldsResolver).
-		Query(api.app.Dao().RecordQuery(collection))
+		Query(query)
 
 	if requestData.Admin == nil && collection.ListRule != nil {
 		searchProvider.AddFilter(search.FilterData(*collection.ListRule))
@@ -79,6 +152,10 @@ func (api *recordApi) list(c echo.Context) error {
 		return NewBadRequestError("Invalid filter parameters.", err)
 	}
 
+	if err := api.app.Dao().DecryptRecordRows(collection, rawRecords...); err != nil {
+		return NewBadRequestError("Failed to load the records.", err)
+	}
+
 	records := models.NewRecordsFromNullStringMaps(collection, rawRecords)
 
 	result.Items = records
@@ -95,10 +172,522 @@ func (api *recordApi) list(c echo.Context) error {
 			log.Println(err)
 		}
 
-		return e.HttpContext.JSON(http.StatusOK, e.Result)
+		result, err := PickRecordFields(e.HttpContext, e.Result)
+		if err != nil {
+			return NewBadRequestError("Failed to pick the requested fields.", err)
+		}
+
+		return e.HttpContext.JSON(http.StatusOK, result)
 	})
 }
 
+// presetFilterExpr loads the named collection saved filter and
+// builds its db expression with the params values from the
+// "params[name]" query params.
+//
+// Example usage:
+//
+//	?preset=recentActive&params[days]=7
+func (api *recordApi) presetFilterExpr(
+	c echo.Context,
+	collection *models.Collection,
+	fieldsResolver *resolvers.RecordFieldResolver,
+	presetName string,
+) (dbx.Expression, error) {
+	savedFilter, err := api.app.Dao().FindSavedFilterByName(collection.Id, presetName)
+	if err != nil {
+		return nil, NewBadRequestError(fmt.Sprintf("Missing or invalid preset %q.", presetName), err)
+	}
+
+	queryParams := c.QueryParams()
+
+	params := dbx.Params{}
+	for _, p := range savedFilter.Params {
+		key := "params[" + p.Name + "]"
+		if !queryParams.Has(key) {
+			return nil, NewBadRequestError(fmt.Sprintf("Missing required preset param %q.", p.Name), nil)
+		}
+
+		value, err := p.ParseValue(queryParams.Get(key))
+		if err != nil {
+			return nil, NewBadRequestError(err.Error(), nil)
+		}
+
+		params[p.Name] = value
+	}
+
+	expr, err := search.FilterData(savedFilter.Filter).BuildExprWithParams(fieldsResolver, params)
+	if err != nil {
+		return nil, NewBadRequestError("Invalid preset filter.", err)
+	}
+
+	return expr, nil
+}
+
+// publicAuthDistinctFields is a list with the auth system fields
+// whose distinct values are allowed to be listed by non-admins.
+var publicAuthDistinctFields = []string{
+	schema.FieldNameUsername,
+	schema.FieldNameEmailVisibility,
+	schema.FieldNameVerified,
+}
+
+// listDistinct responds with the unique values of the specified field
+// for the records matching the collection list API rule and the
+// optional filter query param.
+//
+// Example usages:
+//
+//	?distinct=status
+//	?distinct=tags&filter=active=true&perPage=100
+func (api *recordApi) listDistinct(
+	c echo.Context,
+	collection *models.Collection,
+	fieldsResolver *resolvers.RecordFieldResolver,
+	fieldName string,
+) error {
+	if isAdminOnlyValuesField(c, collection, fieldName) {
+		return NewForbiddenError(fmt.Sprintf("Only admins can list the distinct %q values.", fieldName), nil)
+	}
+
+	filter, err := api.fieldValuesFilter(c, collection, fieldsResolver)
+	if err != nil {
+		return err
+	}
+
+	values, err := api.app.Dao().FindDistinctRecordValues(collection, fieldName, filter)
+	if err != nil {
+		return NewBadRequestError("Failed to load the distinct field values.", err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"field": fieldName,
+		"items": values,
+	})
+}
+
+// maxFacetFields is the max number of fields that could be
+// requested with a single facets list request.
+const maxFacetFields = 10
+
+// listFacets responds with the values and their records count of each
+// of the comma separated fields for the records matching the collection
+// list API rule and the optional filter query param.
+//
+// The perPage query param limits the number of values per field.
+//
+// Example usages:
+//
+//	?facets=status,tags
+//	?facets=tags&filter=active=true&perPage=5
+func (api *recordApi) listFacets(
+	c echo.Context,
+	collection *models.Collection,
+	fieldsResolver *resolvers.RecordFieldResolver,
+	rawFields string,
+) error {
+	fieldNames := list.NonzeroUniques(strings.Split(strings.ReplaceAll(rawFields, " ", ""), ","))
+	if len(fieldNames) == 0 {
+		return NewBadRequestError("Missing facets fields.", nil)
+	}
+	if len(fieldNames) > maxFacetFields {
+		return NewBadRequestError(fmt.Sprintf("Only up to %d facets fields are allowed.", maxFacetFields), nil)
+	}
+
+	for _, fieldName := range fieldNames {
+		if isAdminOnlyValuesField(c, collection, fieldName) {
+			return NewForbiddenError(fmt.Sprintf("Only admins can list the %q facets.", fieldName), nil)
+		}
+	}
+
+	result := make(map[string][]*daos.RecordFacetItem, len(fieldNames))
+
+	for _, fieldName := range fieldNames {
+		filter, err := api.fieldValuesFilter(c, collection, fieldsResolver)
+		if err != nil {
+			return err
+		}
+
+		items, err := api.app.Dao().FindRecordFacetCounts(collection, fieldName, filter)
+		if err != nil {
+			return NewBadRequestError(fmt.Sprintf("Failed to load the %q facets.", fieldName), err)
+		}
+
+		result[fieldName] = items
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"facets": result,
+	})
+}
+
+// isAdminOnlyValuesField checks whether the values of the specified
+// collection field could be listed only by admins.
+func isAdminOnlyValuesField(c echo.Context, collection *models.Collection, fieldName string) bool {
+	if RequestData(c).Admin != nil {
+		return false
+	}
+
+	// the field values are visible only for the records satisfying its read rule
+	if field := collection.Schema.GetFieldByName(fieldName); field != nil && field.ReadRule != "" {
+		return true
+	}
+
+	return collection.IsAuth() &&
+		list.ExistInSlice(fieldName, schema.AuthFieldNames()) &&
+		!list.ExistInSlice(fieldName, publicAuthDistinctFields)
+}
+
+// fieldValuesFilter returns a field values query filter func with the
+// collection list API rule (for non-admins), the filter query params
+// and the perPage query param limit.
+func (api *recordApi) fieldValuesFilter(
+	c echo.Context,
+	collection *models.Collection,
+	fieldsResolver *resolvers.RecordFieldResolver,
+) (func(q *dbx.SelectQuery) error, error) {
+	limit := search.DefaultPerPage
+	if rawPerPage := c.QueryParam(search.PerPageQueryParam); rawPerPage != "" {
+		limit, _ = strconv.Atoi(rawPerPage)
+		if limit <= 0 {
+			limit = search.DefaultPerPage
+		} else if limit > search.MaxPerPage {
+			limit = search.MaxPerPage
+		}
+	}
+
+	filters := []search.FilterData{}
+	if RequestData(c).Admin == nil && collection.ListRule != nil && *collection.ListRule != "" {
+		filters = append(filters, search.FilterData(*collection.ListRule))
+	}
+	rawFilter, err := search.ParseFilterFromQuery(c.QueryParams())
+	if err != nil {
+		return nil, NewBadRequestError("Invalid filter format.", err)
+	}
+	if rawFilter != "" {
+		filters = append(filters, rawFilter)
+	}
+
+	return func(q *dbx.SelectQuery) error {
+		for _, f := range filters {
+			expr, err := f.BuildExpr(fieldsResolver)
+			if err != nil {
+				return err
+			}
+			q.AndWhere(expr)
+		}
+
+		q.Limit(int64(limit))
+
+		return fieldsResolver.UpdateQuery(q)
+	}, nil
+}
+
+// explain responds with the SQLite query plan of the records query
+// built from the optional collection API rule, filter and sort query
+// params (aka. which tables are joined, scanned, the used indexes, etc.).
+//
+// Example usages:
+//
+//	?filter=author.name='test'&sort=-created
+//	?rule=list&filter=active=true
+func (api *recordApi) explain(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("", "Missing collection context.")
+	}
+
+	filters := []search.FilterData{}
+
+	if ruleName := c.QueryParam("rule"); ruleName != "" {
+		var rule *string
+
+		switch ruleName {
+		case "list":
+			rule = collection.ListRule
+		case "view":
+			rule = collection.ViewRule
+		case "update":
+			rule = collection.UpdateRule
+		case "delete":
+			rule = collection.DeleteRule
+		default:
+			return NewBadRequestError(
+				"Invalid rule format.",
+				fmt.Errorf("Expected list, view, update or delete rule, got %q.", ruleName),
+			)
+		}
+
+		if rule == nil {
+			return NewBadRequestError(fmt.Sprintf("The collection %s rule is not set (aka. admins only).", ruleName), nil)
+		}
+
+		if *rule != "" {
+			filters = append(filters, search.FilterData(*rule))
+		}
+	}
+
+	rawFilter, err := search.ParseFilterFromQuery(c.QueryParams())
+	if err != nil {
+		return NewBadRequestError("Invalid filter format.", err)
+	}
+	if rawFilter != "" {
+		filters = append(filters, rawFilter)
+	}
+
+	fieldsResolver := resolvers.NewRecordFieldResolver(
+		api.app.Dao(),
+		collection,
+		RequestData(c),
+		true,
+	)
+
+	plan, err := api.app.Dao().ExplainRecordsFilter(collection, func(q *dbx.SelectQuery) error {
+		for _, f := range filters {
+			expr, err := f.BuildExpr(fieldsResolver)
+			if err != nil {
+				return err
+			}
+			q.AndWhere(expr)
+		}
+
+		if rawSort := c.QueryParam(search.SortQueryParam); rawSort != "" {
+			for _, sortField := range search.ParseSortFromString(rawSort) {
+				expr, err := sortField.BuildExpr(fieldsResolver)
+				if err != nil {
+					return err
+				}
+				if expr != "" {
+					q.AndOrderBy(expr)
+				}
+			}
+		}
+
+		return fieldsResolver.UpdateQuery(q)
+	})
+	if err != nil {
+		return NewBadRequestError("Failed to explain the records filter.", err)
+	}
+
+	return c.JSON(http.StatusOK, plan)
+}
+
+// recordAggregateFunctions is a list with the functions
+// allowed in the records aggregate "aggregate" query param.
+var recordAggregateFunctions = []string{"count", "sum", "avg", "min", "max"}
+
+// aggregate returns grouped records statistics (count, sum, avg, etc.)
+// for the records matching the collection list API rule and the
+// optional filter query param.
+//
+// Example usages:
+//
+//	?groupBy=status                                     // records count by status
+//	?groupBy=day(created)&sort=day(created)             // records count per day
+//	?groupBy=author.name&aggregate=count(*),sum(total)  // totals by relation field
+//	?aggregate=avg(price),max(price)&filter=active=true // single group stats
+func (api *recordApi) aggregate(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("", "Missing collection context.")
+	}
+
+	// forbid users and guests to query special filter/sort fields
+	if err := api.checkForForbiddenQueryFields(c); err != nil {
+		return err
+	}
+
+	// forbid users and guests to submit a raw filter for presets only collections
+	if err := api.checkForPresetsOnlyFilter(c, collection); err != nil {
+		return err
+	}
+
+	// forbid users and guests to filter/group/aggregate by read restricted fields
+	aggregateFields := append(
+		splitAggregateFields(c.QueryParam("groupBy")),
+		splitAggregateFields(c.QueryParam("aggregate"))...,
+	)
+	if err := api.checkForRestrictedQueryFields(c, collection, aggregateFields...); err != nil {
+		return err
+	}
+
+	requestData := RequestData(c)
+
+	if requestData.Admin == nil && collection.ListRule == nil {
+		// only admins can access if the rule is nil
+		return NewForbiddenError("Only admins can perform this action.", nil)
+	}
+
+	newFieldsResolver := func() *resolvers.RecordFieldResolver {
+		resolver := resolvers.NewRecordFieldResolver(
+			api.app.Dao(),
+			collection,
+			requestData,
+			// hidden fields are searchable only by admins
+			requestData.Admin != nil,
+		)
+
+		// limit the query complexity for non-admins
+		if requestData.Admin == nil {
+			resolver.
+				SetMaxNestedRels(api.app.Settings().Filter.MaxNestedRels).
+				SetMaxJoins(api.app.Settings().Filter.MaxJoins)
+		}
+
+		return resolver
+	}
+
+	tableAlias := api.app.Dao().Columnify(collection.Name)
+
+	// filter the records in a subquery to prevent the filter joins
+	// from duplicating the aggregated rows
+	// ---
+	filterResolver := newFieldsResolver()
+
+	filterQuery := api.app.Dao().RecordQuery(collection).Select(tableAlias + ".id")
+
+	filters := []search.FilterData{}
+	if requestData.Admin == nil && collection.ListRule != nil && *collection.ListRule != "" {
+		filters = append(filters, search.FilterData(*collection.ListRule))
+	}
+	rawFilter, err := search.ParseFilterFromQuery(c.QueryParams())
+	if err != nil {
+		return NewBadRequestError("Invalid filter format.", err)
+	}
+	if rawFilter != "" {
+		filters = append(filters, rawFilter)
+	}
+
+	for _, f := range filters {
+		expr, err := f.BuildExpr(filterResolver)
+		if err != nil {
+			return NewBadRequestError("Invalid filter format.", err)
+		}
+		filterQuery.AndWhere(expr)
+	}
+
+	if err := filterResolver.UpdateQuery(filterQuery); err != nil {
+		return NewBadRequestError("Invalid filter format.", err)
+	}
+
+	builtFilterQuery := filterQuery.Build()
+	// ---
+
+	aggregateResolver := newFieldsResolver()
+
+	selects := []string{}
+	groupBy := []string{}
+	params := dbx.Params{}
+
+	// aliases of the groupBy and aggregate expressions
+	// (the raw expressions are used as result item keys)
+	aliases := map[string]string{}
+	keys := []string{}
+
+	addSelect := func(rawField string, alias string) error {
+		if _, exists := aliases[rawField]; exists {
+			return fmt.Errorf("Duplicated field %q.", rawField)
+		}
+
+		// forbid @request and @collection fields
+		if strings.Contains(rawField, "@") {
+			return fmt.Errorf("Invalid field %q.", rawField)
+		}
+
+		expr, exprParams, err := search.ResolveAggregateField(aggregateResolver, rawField)
+		if err != nil {
+			return err
+		}
+
+		for k, v := range exprParams {
+			params[k] = v
+		}
+
+		selects = append(selects, fmt.Sprintf("%s AS [[%s]]", expr, alias))
+		aliases[rawField] = alias
+		keys = append(keys, rawField)
+
+		return nil
+	}
+
+	for i, rawField := range splitAggregateFields(c.QueryParam("groupBy")) {
+		if isRecordAggregateFunction(rawField) {
+			return NewBadRequestError("Invalid groupBy format.", fmt.Errorf("Aggregate functions are not allowed in %q.", rawField))
+		}
+
+		alias := "g" + strconv.Itoa(i)
+
+		if err := addSelect(rawField, alias); err != nil {
+			return NewBadRequestError("Invalid groupBy format.", err)
+		}
+
+		groupBy = append(groupBy, alias)
+	}
+
+	rawAggregate := c.QueryParam("aggregate")
+	if rawAggregate == "" {
+		rawAggregate = "count(*)"
+	}
+
+	for i, rawField := range splitAggregateFields(rawAggregate) {
+		if !isRecordAggregateFunction(rawField) {
+			return NewBadRequestError("Invalid aggregate format.", fmt.Errorf("Missing aggregate function in %q.", rawField))
+		}
+
+		if err := addSelect(rawField, "a"+strconv.Itoa(i)); err != nil {
+			return NewBadRequestError("Invalid aggregate format.", err)
+		}
+	}
+
+	orderBy := []string{}
+	if rawSort := c.QueryParam(search.SortQueryParam); rawSort != "" {
+		for _, sortField := range search.ParseSortFromString(rawSort) {
+			alias, ok := aliases[sortField.Name]
+			if !ok {
+				return NewBadRequestError(
+					"Invalid sort format.",
+					fmt.Errorf("Only the groupBy and aggregate fields are sortable, got %q.", sortField.Name),
+				)
+			}
+			orderBy = append(orderBy, fmt.Sprintf("[[%s]] %s", alias, sortField.Direction))
+		}
+	}
+
+	limit := search.DefaultPerPage
+	if rawLimit := c.QueryParam("limit"); rawLimit != "" {
+		var err error
+		limit, err = strconv.Atoi(rawLimit)
+		if err != nil || limit <= 0 {
+			return NewBadRequestError("Invalid limit value.", err)
+		}
+		if limit > search.MaxPerPage {
+			limit = search.MaxPerPage
+		}
+	}
+
+	query := api.app.Dao().RecordQuery(collection).
+		Select(selects...).
+		AndWhere(dbx.NewExp(
+			fmt.Sprintf("[[%s.id]] IN (%s)", tableAlias, builtFilterQuery.SQL()),
+			builtFilterQuery.Params(),
+		)).
+		GroupBy(groupBy...).
+		OrderBy(orderBy...).
+		Limit(int64(limit)).
+		Bind(params)
+
+	if err := aggregateResolver.UpdateQuery(query); err != nil {
+		return NewBadRequestError("Failed to aggregate the records.", err)
+	}
+
+	items, err := scanAggregateRows(query, keys)
+	if err != nil {
+		return NewBadRequestError("Failed to aggregate the records.", err)
+	}
+
+	return c.JSON(http.StatusOK, items)
+}
+
 func (api *recordApi) view(c echo.Context) error {
 	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
 	if collection == nil {
@@ -114,7 +703,7 @@ func (api *recordApi) view(c echo.Context) error {
 
 	if requestData.Admin == nil && collection.ViewRule == nil {
 		// only admins can access if the rule is nil
-		return NewForbiddenError("Only admins can perform this action.", nil)
+		return api.ruleFailureError(c, collection, "viewRule", nil, NewForbiddenError("Only admins can perform this action.", nil))
 	}
 
 	ruleFunc := func(q *dbx.SelectQuery) error {
@@ -130,8 +719,22 @@ func (api *recordApi) view(c echo.Context) error {
 		return nil
 	}
 
-	record, fetchErr := api.app.Dao().FindRecordById(collection.Id, recordId, ruleFunc)
+	withDeleted, err := api.withDeleted(c)
+	if err != nil {
+		return err
+	}
+
+	var record *models.Record
+	var fetchErr error
+	if withDeleted {
+		record, fetchErr = api.app.Dao().FindRecordByIdWithDeleted(collection.Id, recordId)
+	} else {
+		record, fetchErr = api.app.Dao().FindRecordById(collection.Id, recordId, ruleFunc)
+	}
 	if fetchErr != nil || record == nil {
+		if isRulesDebug(c) && api.existsRecord(collection, recordId) {
+			return api.ruleFailureError(c, collection, "viewRule", collection.ViewRule, nil)
+		}
 		return NewNotFoundError("", fetchErr)
 	}
 
@@ -145,7 +748,14 @@ func (api *recordApi) view(c echo.Context) error {
 			log.Println(err)
 		}
 
-		return e.HttpContext.JSON(http.StatusOK, e.Record)
+		result, err := PickRecordFields(e.HttpContext, e.Record)
+		if err != nil {
+			return NewBadRequestError("Failed to pick the requested fields.", err)
+		}
+
+		setRecordETag(e.HttpContext, e.Record)
+
+		return e.HttpContext.JSON(http.StatusOK, result)
 	})
 }
 
@@ -155,11 +765,38 @@ func (api *recordApi) create(c echo.Context) error {
 		return NewNotFoundError("", "Missing collection context.")
 	}
 
+	if collection.IsExternal() {
+		return NewBadRequestError("The external collection records are read-only.", nil)
+	}
+
+	// update the matching existing record (if any) on upsert
+	if isUpsertRequest(c) {
+		existingId, err := api.findUpsertRecordId(c, collection)
+		if err != nil {
+			return err
+		}
+		if existingId != "" {
+			return api.updateRecord(c, collection, existingId)
+		}
+	}
+
 	requestData := RequestData(c)
 
 	if requestData.Admin == nil && collection.CreateRule == nil {
 		// only admins can access if the rule is nil
-		return NewForbiddenError("Only admins can perform this action.", nil)
+		return api.ruleFailureError(c, collection, "createRule", nil, NewForbiddenError("Only admins can perform this action.", nil))
+	}
+
+	// check the collection records quotas
+	registerQuotaHit, err := checkRecordCreateQuotas(api.app, c, collection)
+	if err != nil {
+		return err
+	}
+
+	// check the collection bot protection rules
+	registerCooldownHit, err := checkRecordCreateBotProtection(api.app, c, collection)
+	if err != nil {
+		return err
 	}
 
 	hasFullManageAccess := requestData.Admin != nil
@@ -184,13 +821,18 @@ func (api *recordApi) create(c echo.Context) error {
 		testRecord := models.NewRecord(collection)
 		testForm := forms.NewRecordUpsert(api.app, testRecord)
 		testForm.SetFullManageAccess(true)
+		testForm.SetAuthRecord(requestData.AuthRecord)
+		testForm.SetApiKey(requestData.ApiKey)
 		if err := testForm.LoadRequest(c.Request(), ""); err != nil {
 			return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
 		}
 
+		createRuleFailed := false
+
 		testErr := testForm.DrySubmit(func(txDao *daos.Dao) error {
 			foundRecord, err := txDao.FindRecordById(collection.Id, testRecord.Id, createRuleFunc)
 			if err != nil {
+				createRuleFailed = true
 				return fmt.Errorf("DrySubmit create rule failure: %w", err)
 			}
 			hasFullManageAccess = hasAuthManageAccess(txDao, foundRecord, requestData)
@@ -198,13 +840,21 @@ func (api *recordApi) create(c echo.Context) error {
 		})
 
 		if testErr != nil {
-			return NewBadRequestError("Failed to create record.", testErr)
+			fallbackErr := NewBadRequestError("Failed to create record.", testErr)
+			if createRuleFailed {
+				return api.ruleFailureError(c, collection, "createRule", collection.CreateRule, fallbackErr)
+			}
+			return fallbackErr
 		}
 	}
 
 	record := models.NewRecord(collection)
+	setRecordHistoryAuth(record, requestData)
 	form := forms.NewRecordUpsert(api.app, record)
 	form.SetFullManageAccess(hasFullManageAccess)
+	form.SetAuthRecord(requestData.AuthRecord)
+	form.SetApiKey(requestData.ApiKey)
+	form.SetCheckFieldWriteRules(requestData.Admin == nil)
 
 	// load request
 	if err := form.LoadRequest(c.Request(), ""); err != nil {
@@ -216,28 +866,88 @@ func (api *recordApi) create(c echo.Context) error {
 		Record:      record,
 	}
 
+	respond := func(e *core.RecordCreateEvent) error {
+		registerQuotaHit()
+		registerCooldownHit()
+
+		if err := EnrichRecord(e.HttpContext, api.app.Dao(), e.Record); err != nil && api.app.IsDebug() {
+			log.Println(err)
+		}
+
+		setRecordETag(e.HttpContext, e.Record)
+
+		return e.HttpContext.JSON(http.StatusOK, e.Record)
+	}
+
+	// the transactional requests are responded after the commit
+	// (the realtime request id is tracked until then too since
+	// the model after hooks are deferred)
+	transactional := collection.TransactionOptions().Enabled
+	form.SetDeferFilesCommit(transactional)
+	untrack := func() {}
+	defer func() { untrack() }()
+
 	// create the record
-	submitErr := form.Submit(func(next forms.InterceptorNextFunc) forms.InterceptorNextFunc {
-		return func() error {
-			return api.app.OnRecordBeforeCreateRequest().Trigger(event, func(e *core.RecordCreateEvent) error {
-				if err := next(); err != nil {
-					return NewBadRequestError("Failed to create record.", err)
-				}
+	submitErr := runRecordRequest(api.app, c, collection, func(dao *daos.Dao) error {
+		form.SetDao(dao)
 
-				if err := EnrichRecord(e.HttpContext, api.app.Dao(), e.Record); err != nil && api.app.IsDebug() {
-					log.Println(err)
+		err := form.Submit(func(next forms.InterceptorNextFunc) forms.InterceptorNextFunc {
+			return func() error {
+				if err := moderateRecordContent(api.app, c, record); err != nil {
+					return err
 				}
 
-				return e.HttpContext.JSON(http.StatusOK, e.Record)
-			})
+				return api.app.OnRecordBeforeCreateRequest().Trigger(event, func(e *core.RecordCreateEvent) error {
+					untrack = trackRecordRequestId(e.HttpContext, e.Record)
+					err := next()
+					if !transactional {
+						untrack()
+					}
+					if err != nil {
+						return NewBadRequestError("Failed to create record.", err)
+					}
+
+					if transactional {
+						return nil
+					}
+
+					return respond(e)
+				})
+			}
+		})
+		if err != nil || !transactional {
+			return err
+		}
+
+		if err := api.app.OnRecordAfterCreateRequest().Trigger(event); err != nil {
+			return NewBadRequestError("Failed to create record.", err)
 		}
+
+		return nil
 	})
 
-	if submitErr == nil {
-		api.app.OnRecordAfterCreateRequest().Trigger(event)
+	if submitErr != nil {
+		if transactional {
+			// try to cleanup the staged files of the reverted record
+			if err := form.RollbackFiles(); err != nil && api.app.IsDebug() {
+				log.Println(err)
+			}
+		}
+
+		return submitErr
 	}
 
-	return submitErr
+	if transactional {
+		if err := commitRecordFiles(api.app, form); err != nil {
+			return NewBadRequestError("Failed to commit the uploaded record files.", err)
+		}
+
+		return respond(event)
+	}
+
+	api.app.OnRecordAfterCreateRequest().Trigger(event)
+
+	return nil
 }
 
 func (api *recordApi) update(c echo.Context) error {
@@ -251,11 +961,21 @@ func (api *recordApi) update(c echo.Context) error {
 		return NewNotFoundError("", nil)
 	}
 
+	return api.updateRecord(c, collection, recordId)
+}
+
+// updateRecord updates the specified collection record
+// with the request data (checking the collection update rule).
+func (api *recordApi) updateRecord(c echo.Context, collection *models.Collection, recordId string) error {
+	if collection.IsExternal() {
+		return NewBadRequestError("The external collection records are read-only.", nil)
+	}
+
 	requestData := RequestData(c)
 
 	if requestData.Admin == nil && collection.UpdateRule == nil {
 		// only admins can access if the rule is nil
-		return NewForbiddenError("Only admins can perform this action.", nil)
+		return api.ruleFailureError(c, collection, "updateRule", nil, NewForbiddenError("Only admins can perform this action.", nil))
 	}
 
 	ruleFunc := func(q *dbx.SelectQuery) error {
@@ -274,44 +994,119 @@ func (api *recordApi) update(c echo.Context) error {
 	// fetch record
 	record, fetchErr := api.app.Dao().FindRecordById(collection.Id, recordId, ruleFunc)
 	if fetchErr != nil || record == nil {
+		if isRulesDebug(c) && api.existsRecord(collection, recordId) {
+			return api.ruleFailureError(c, collection, "updateRule", collection.UpdateRule, nil)
+		}
 		return NewNotFoundError("", fetchErr)
 	}
 
+	setRecordHistoryAuth(record, requestData)
 	form := forms.NewRecordUpsert(api.app, record)
 	form.SetFullManageAccess(requestData.Admin != nil || hasAuthManageAccess(api.app.Dao(), record, requestData))
+	form.SetAuthRecord(requestData.AuthRecord)
+	form.SetApiKey(requestData.ApiKey)
+	form.SetCheckFieldWriteRules(requestData.Admin == nil)
 
 	// load request
 	if err := form.LoadRequest(c.Request(), ""); err != nil {
 		return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
 	}
 
+	// the If-Match header has precedence over the submitted version
+	if collection.VersioningOptions().Enabled && c.Request().Header.Get("If-Match") != "" {
+		version, err := parseRecordETag(c.Request().Header.Get("If-Match"))
+		if err != nil {
+			return NewBadRequestError("Invalid If-Match header.", err)
+		}
+		form.Version = version
+	}
+
 	event := &core.RecordUpdateEvent{
 		HttpContext: c,
 		Record:      record,
 	}
 
+	respond := func(e *core.RecordUpdateEvent) error {
+		if err := EnrichRecord(e.HttpContext, api.app.Dao(), e.Record); err != nil && api.app.IsDebug() {
+			log.Println(err)
+		}
+
+		setRecordETag(e.HttpContext, e.Record)
+
+		return e.HttpContext.JSON(http.StatusOK, e.Record)
+	}
+
+	// the transactional requests are responded after the commit
+	// (the uploaded files are also moved in place only after that)
+	transactional := collection.TransactionOptions().Enabled
+	form.SetDeferFilesCommit(transactional)
+	untrack := func() {}
+	defer func() { untrack() }()
+
 	// update the record
-	submitErr := form.Submit(func(next forms.InterceptorNextFunc) forms.InterceptorNextFunc {
-		return func() error {
-			return api.app.OnRecordBeforeUpdateRequest().Trigger(event, func(e *core.RecordUpdateEvent) error {
-				if err := next(); err != nil {
-					return NewBadRequestError("Failed to update record.", err)
-				}
+	submitErr := runRecordRequest(api.app, c, collection, func(dao *daos.Dao) error {
+		form.SetDao(dao)
 
-				if err := EnrichRecord(e.HttpContext, api.app.Dao(), e.Record); err != nil && api.app.IsDebug() {
-					log.Println(err)
+		err := form.Submit(func(next forms.InterceptorNextFunc) forms.InterceptorNextFunc {
+			return func() error {
+				if err := moderateRecordContent(api.app, c, record); err != nil {
+					return err
 				}
 
-				return e.HttpContext.JSON(http.StatusOK, e.Record)
-			})
+				return api.app.OnRecordBeforeUpdateRequest().Trigger(event, func(e *core.RecordUpdateEvent) error {
+					untrack = trackRecordRequestId(e.HttpContext, e.Record)
+					err := next()
+					if !transactional {
+						untrack()
+					}
+					if errors.Is(err, daos.ErrRecordVersionConflict) {
+						return api.versionConflictError(e.HttpContext, collection, e.Record.Id)
+					}
+					if err != nil {
+						return NewBadRequestError("Failed to update record.", err)
+					}
+
+					if transactional {
+						return nil
+					}
+
+					return respond(e)
+				})
+			}
+		})
+		if err != nil || !transactional {
+			return err
 		}
+
+		if err := api.app.OnRecordAfterUpdateRequest().Trigger(event); err != nil {
+			return NewBadRequestError("Failed to update record.", err)
+		}
+
+		return nil
 	})
 
-	if submitErr == nil {
-		api.app.OnRecordAfterUpdateRequest().Trigger(event)
+	if submitErr != nil {
+		if transactional {
+			// try to cleanup the staged files of the reverted update
+			if err := form.RollbackFiles(); err != nil && api.app.IsDebug() {
+				log.Println(err)
+			}
+		}
+
+		return submitErr
 	}
 
-	return submitErr
+	if transactional {
+		if err := commitRecordFiles(api.app, form); err != nil {
+			return NewBadRequestError("Failed to commit the uploaded record files.", err)
+		}
+
+		return respond(event)
+	}
+
+	api.app.OnRecordAfterUpdateRequest().Trigger(event)
+
+	return nil
 }
 
 func (api *recordApi) delete(c echo.Context) error {
@@ -325,11 +1120,15 @@ func (api *recordApi) delete(c echo.Context) error {
 		return NewNotFoundError("", nil)
 	}
 
+	if collection.IsExternal() {
+		return NewBadRequestError("The external collection records are read-only.", nil)
+	}
+
 	requestData := RequestData(c)
 
 	if requestData.Admin == nil && collection.DeleteRule == nil {
 		// only admins can access if the rule is nil
-		return NewForbiddenError("Only admins can perform this action.", nil)
+		return api.ruleFailureError(c, collection, "deleteRule", nil, NewForbiddenError("Only admins can perform this action.", nil))
 	}
 
 	ruleFunc := func(q *dbx.SelectQuery) error {
@@ -347,28 +1146,163 @@ func (api *recordApi) delete(c echo.Context) error {
 
 	record, fetchErr := api.app.Dao().FindRecordById(collection.Id, recordId, ruleFunc)
 	if fetchErr != nil || record == nil {
+		if isRulesDebug(c) && api.existsRecord(collection, recordId) {
+			return api.ruleFailureError(c, collection, "deleteRule", collection.DeleteRule, nil)
+		}
 		return NewNotFoundError("", fetchErr)
 	}
 
+	setRecordHistoryAuth(record, requestData)
+
 	event := &core.RecordDeleteEvent{
 		HttpContext: c,
 		Record:      record,
 	}
 
-	handlerErr := api.app.OnRecordBeforeDeleteRequest().Trigger(event, func(e *core.RecordDeleteEvent) error {
-		// delete the record
-		if err := api.app.Dao().DeleteRecord(e.Record); err != nil {
-			return NewBadRequestError("Failed to delete record. Make sure that the record is not part of a required relation reference.", err)
+	// the transactional requests are responded after the commit
+	// (the realtime request id is tracked until then too since
+	// the model after hooks are deferred)
+	transactional := collection.TransactionOptions().Enabled
+	untrack := func() {}
+	defer func() { untrack() }()
+
+	handlerErr := runRecordRequest(api.app, c, collection, func(dao *daos.Dao) error {
+		err := api.app.OnRecordBeforeDeleteRequest().Trigger(event, func(e *core.RecordDeleteEvent) error {
+			// delete (or only mark as deleted) the record
+			untrack = trackRecordRequestId(e.HttpContext, e.Record)
+			var err error
+			if e.Record.Collection().SoftDeleteOptions().Enabled {
+				err = dao.SoftDeleteRecord(e.Record)
+			} else {
+				err = dao.DeleteRecord(e.Record)
+			}
+			if !transactional {
+				untrack()
+			}
+			if err != nil {
+				return NewBadRequestError("Failed to delete record. Make sure that the record is not part of a required relation reference.", err)
+			}
+
+			if transactional {
+				return nil
+			}
+
+			return e.HttpContext.NoContent(http.StatusNoContent)
+		})
+		if err != nil || !transactional {
+			return err
+		}
+
+		if err := api.app.OnRecordAfterDeleteRequest().Trigger(event); err != nil {
+			return NewBadRequestError("Failed to delete record.", err)
 		}
 
-		return e.HttpContext.NoContent(http.StatusNoContent)
+		return nil
 	})
 
-	if handlerErr == nil {
-		api.app.OnRecordAfterDeleteRequest().Trigger(event)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	if transactional {
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	api.app.OnRecordAfterDeleteRequest().Trigger(event)
+
+	return nil
+}
+
+// restore unmarks a soft deleted record.
+func (api *recordApi) restore(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("", "Missing collection context.")
+	}
+
+	if !collection.SoftDeleteOptions().Enabled {
+		return NewBadRequestError("The collection soft delete is not enabled.", nil)
+	}
+
+	record, err := api.app.Dao().FindRecordByIdWithDeleted(collection.Id, c.PathParam("id"))
+	if err != nil || record == nil {
+		return NewNotFoundError("", err)
+	}
+
+	if !record.IsDeleted() {
+		return NewBadRequestError("The record is not deleted.", nil)
+	}
+
+	if err := api.app.Dao().RestoreRecord(record); err != nil {
+		return NewBadRequestError("Failed to restore the record.", err)
+	}
+
+	if err := EnrichRecord(c, api.app.Dao(), record); err != nil && api.app.IsDebug() {
+		log.Println(err)
+	}
+
+	return c.JSON(http.StatusOK, record)
+}
+
+// versionConflictError returns a 409 error with the current
+// state of the outdated record (if still exists).
+func (api *recordApi) versionConflictError(c echo.Context, collection *models.Collection, recordId string) error {
+	conflictErr := NewConflictError("The record was modified in the meantime.", daos.ErrRecordVersionConflict)
+
+	current, err := api.app.Dao().FindRecordById(collection.Id, recordId)
+	if err != nil {
+		return conflictErr
+	}
+
+	if err := EnrichRecord(c, api.app.Dao(), current); err != nil && api.app.IsDebug() {
+		log.Println(err)
+	}
+
+	setRecordETag(c, current)
+
+	conflictErr.Data["current"] = current
+
+	return conflictErr
+}
+
+// setRecordETag sets the ETag response header with the version
+// of the provided record (only if its collection has enabled versioning).
+func setRecordETag(c echo.Context, record *models.Record) {
+	if !record.Collection().VersioningOptions().Enabled {
+		return
+	}
+
+	c.Response().Header().Set("ETag", strconv.Quote(strconv.Itoa(record.Version())))
+}
+
+// parseRecordETag extracts the record version from the provided
+// ETag or If-Match header value (eg. `"3"` or `W/"3"`).
+func parseRecordETag(etag string) (int, error) {
+	etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+
+	unquoted, err := strconv.Unquote(etag)
+	if err != nil {
+		unquoted = etag
+	}
+
+	return strconv.Atoi(unquoted)
+}
+
+// withDeleted checks whether the request should include the soft
+// deleted records (allowed only for admins).
+func (api *recordApi) withDeleted(c echo.Context) (bool, error) {
+	raw := c.QueryParam(withDeletedQueryParam)
+	if raw == "" {
+		return false, nil
+	}
+
+	if admin, _ := c.Get(ContextAdminKey).(*models.Admin); admin == nil {
+		return false, NewForbiddenError("Only admins can access the deleted records.", nil)
 	}
 
-	return handlerErr
+	result, _ := strconv.ParseBool(raw)
+
+	return result, nil
 }
 
 func (api *recordApi) checkForForbiddenQueryFields(c echo.Context) error {
@@ -377,7 +1311,11 @@ func (api *recordApi) checkForForbiddenQueryFields(c echo.Context) error {
 		return nil // admins are allowed to query everything
 	}
 
-	decodedQuery := c.QueryParam(search.FilterQueryParam) + c.QueryParam(search.SortQueryParam)
+	// check all filter values (the filter query param could be repeated)
+	params := c.QueryParams()
+	decodedQuery := strings.Join(params[search.FilterQueryParam], "") +
+		strings.Join(params[search.FiltersQueryParam], "") +
+		c.QueryParam(search.SortQueryParam)
 	forbiddenFields := []string{"@collection.", "@request."}
 
 	for _, field := range forbiddenFields {
@@ -388,3 +1326,116 @@ func (api *recordApi) checkForForbiddenQueryFields(c echo.Context) error {
 
 	return nil
 }
+
+// checkForPresetsOnlyFilter forbids users and guests to submit a raw
+// filter query param if the collection allows filtering only by its
+// saved filter presets (see [models.CollectionPresetsOptions]).
+func (api *recordApi) checkForPresetsOnlyFilter(c echo.Context, collection *models.Collection) error {
+	if RequestData(c).Admin != nil || !collection.PresetsOptions().Only {
+		return nil
+	}
+
+	params := c.QueryParams()
+	if params.Has(search.FilterQueryParam) || params.Has(search.FiltersQueryParam) {
+		return NewForbiddenError("Only admins can filter the collection records without a preset.", nil)
+	}
+
+	return nil
+}
+
+// checkForRestrictedQueryFields forbids users and guests to filter, sort
+// or aggregate (the optional extra fields) by schema fields with read rule
+// since their values are visible only for the records satisfying the rule.
+//
+// The expressions are resolved only to collect their fields
+// (any other error is reported by the regular query build).
+func (api *recordApi) checkForRestrictedQueryFields(
+	c echo.Context,
+	collection *models.Collection,
+	aggregateFields ...string,
+) error {
+	requestData := RequestData(c)
+	if requestData.Admin != nil {
+		return nil // admins are allowed to query everything
+	}
+
+	resolver := resolvers.NewRecordFieldResolver(api.app.Dao(), collection, requestData, false)
+
+	if rawFilter, err := search.ParseFilterFromQuery(c.QueryParams()); err == nil && rawFilter != "" {
+		rawFilter.BuildExpr(resolver)
+	}
+
+	for _, sortField := range search.ParseSortFromString(c.QueryParam(search.SortQueryParam)) {
+		sortField.BuildExpr(resolver)
+	}
+
+	for _, field := range aggregateFields {
+		search.ResolveAggregateField(resolver, field)
+	}
+
+	if restricted := resolver.RestrictedFields(); len(restricted) > 0 {
+		return NewForbiddenError(fmt.Sprintf("Only admins can query by the restricted %q field.", restricted[0]), nil)
+	}
+
+	return nil
+}
+
+// splitAggregateFields splits the comma separated raw aggregate
+// query param fields, skipping the empty ones.
+func splitAggregateFields(raw string) []string {
+	result := []string{}
+
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field != "" {
+			result = append(result, field)
+		}
+	}
+
+	return result
+}
+
+// isRecordAggregateFunction checks whether the raw field is a call
+// to one of the recordAggregateFunctions (eg. "sum(total)").
+func isRecordAggregateFunction(rawField string) bool {
+	fn, _, ok := strings.Cut(rawField, "(")
+
+	return ok && list.ExistInSlice(strings.ToLower(fn), recordAggregateFunctions)
+}
+
+// scanAggregateRows executes the aggregate query and returns its rows
+// as list of maps with the provided keys (in the selected columns order).
+func scanAggregateRows(query *dbx.SelectQuery, keys []string) ([]map[string]any, error) {
+	rows, err := query.Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []map[string]any{}
+
+	for rows.Next() {
+		values := make([]any, len(keys))
+		pointers := make([]any, len(keys))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		item := make(map[string]any, len(keys))
+		for i, key := range keys {
+			if v, ok := values[i].([]byte); ok {
+				item[key] = string(v)
+			} else {
+				item[key] = values[i]
+			}
+		}
+
+		result = append(result, item)
+	}
+
+	return result, rows.Err()
+}
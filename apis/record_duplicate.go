@@ -0,0 +1,60 @@
+package apis
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/rest"
+)
+
+// duplicate creates a copy of the specified record (including its files),
+// optionally overriding the copied field values with the request body data.
+func (api *recordApi) duplicate(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("", "Missing collection context.")
+	}
+
+	if collection.IsAuth() {
+		return NewBadRequestError("Auth collection records cannot be duplicated.", nil)
+	}
+
+	record, err := api.app.Dao().FindRecordById(collection.Id, c.PathParam("id"))
+	if err != nil || record == nil {
+		return NewNotFoundError("", err)
+	}
+
+	overrides := map[string]any{}
+	if err := rest.BindBody(c, &overrides); err != nil {
+		return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	fs, err := api.app.NewFilesystem()
+	if err != nil {
+		return NewBadRequestError("Failed to initialize the filesystem.", err)
+	}
+	defer fs.Close()
+
+	duplicate, err := api.app.Dao().DuplicateRecord(record, fs, func(duplicate *models.Record) error {
+		form := forms.NewRecordUpsert(api.app, duplicate)
+		form.SetFullManageAccess(true)
+
+		if err := form.LoadData(overrides); err != nil {
+			return err
+		}
+
+		return form.ValidateAndFill()
+	})
+	if err != nil {
+		return NewBadRequestError("Failed to duplicate record.", err)
+	}
+
+	if err := EnrichRecord(c, api.app.Dao(), duplicate); err != nil && api.app.IsDebug() {
+		log.Println(err)
+	}
+
+	return c.JSON(http.StatusOK, duplicate)
+}
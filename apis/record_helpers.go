@@ -2,19 +2,37 @@ package apis
 
 import (
 	"fmt"
+	"math"
+	"net/http"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/labstack/echo/v5"
 	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/daos"
 	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
 	"github.com/pocketbase/pocketbase/resolvers"
+	"github.com/pocketbase/pocketbase/tools/inflector"
+	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/moderation"
+	"github.com/pocketbase/pocketbase/tools/picker"
+	"github.com/pocketbase/pocketbase/tools/ratelimit"
 	"github.com/pocketbase/pocketbase/tools/rest"
 	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/spf13/cast"
 )
 
 const ContextRequestDataKey = "requestData"
 
+const recordsCreateLimiterCacheKey = "recordsCreateLimiter"
+
+var recordsCreateLimiterMux sync.Mutex
+
 // Deprecated: Will be removed after v0.9. Use apis.RequestData(c) instead.
 func GetRequestData(c echo.Context) *models.RequestData {
 	return RequestData(c)
@@ -36,8 +54,10 @@ func RequestData(c echo.Context) *models.RequestData {
 		Data:   map[string]any{},
 	}
 
+	result.RequestId, _ = c.Get(ContextRequestIdKey).(string)
 	result.AuthRecord, _ = c.Get(ContextAuthRecordKey).(*models.Record)
 	result.Admin, _ = c.Get(ContextAdminKey).(*models.Admin)
+	result.ApiKey, _ = c.Get(ContextApiKeyKey).(*models.ApiKey)
 	echo.BindQueryParams(c, &result.Query)
 	rest.BindBody(c, &result.Data)
 
@@ -50,6 +70,7 @@ func RequestData(c echo.Context) *models.RequestData {
 // - expands relations (if defaultExpands and/or ?expand query param is set)
 // - ensures that the emails of the auth record and its expanded auth relations
 //   are visibe only for the current logged admin, record owner or record with manage access
+// - hides the record fields whose read rule is not satisfied by the request
 func EnrichRecord(c echo.Context, dao *daos.Dao, record *models.Record, defaultExpands ...string) error {
 	return EnrichRecords(c, dao, []*models.Record{record}, defaultExpands...)
 }
@@ -58,9 +79,14 @@ func EnrichRecord(c echo.Context, dao *daos.Dao, record *models.Record, defaultE
 // - expands relations (if defaultExpands and/or ?expand query param is set)
 // - ensures that the emails of the auth records and their expanded auth relations
 //   are visibe only for the current logged admin, record owner or record with manage access
+// - hides the records fields whose read rule is not satisfied by the request
 func EnrichRecords(c echo.Context, dao *daos.Dao, records []*models.Record, defaultExpands ...string) error {
 	requestData := RequestData(c)
 
+	if err := hideRecordsRestrictedFields(dao, records, requestData); err != nil {
+		return fmt.Errorf("Failed to resolve fields read rules: %w", err)
+	}
+
 	if err := autoIgnoreAuthRecordsEmailVisibility(dao, records, requestData); err != nil {
 		return fmt.Errorf("Failed to resolve email visibility: %w", err)
 	}
@@ -79,6 +105,87 @@ func EnrichRecords(c echo.Context, dao *daos.Dao, records []*models.Record, defa
 	return nil
 }
 
+// PickRecordFields returns a generic copy of the provided data
+// (single record or records list result) containing only the record
+// fields from the ?fields query param (eg. "id,title,expand.author.name").
+//
+// For list results the fields are applied to each of the result items.
+//
+// Returns the data as it is if the ?fields query param is not set.
+func PickRecordFields(c echo.Context, data any) (any, error) {
+	rawFields := c.QueryParam(fieldsQueryParam)
+	if strings.TrimSpace(rawFields) == "" {
+		return data, nil
+	}
+
+	if result, ok := data.(*search.Result); ok {
+		items, err := picker.Pick(result.Items, rawFields)
+		if err != nil {
+			return nil, err
+		}
+
+		clone := *result
+		clone.Items = items
+
+		return &clone, nil
+	}
+
+	return picker.Pick(data, rawFields)
+}
+
+// recordProjectionColumns returns the collection table columns that
+// are required to serve the provided ?fields projection
+// (nil if all columns are required).
+//
+// The base and auth system fields and the expanded relation fields
+// are always loaded because they are used by the records enrichment.
+func recordProjectionColumns(dao *daos.Dao, collection *models.Collection, rawFields string, rawExpand string) []string {
+	fields := picker.ParseFields(rawFields)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	if _, ok := fields[picker.Wildcard]; ok {
+		return nil
+	}
+
+	names := schema.BaseModelFieldNames()
+	if collection.IsAuth() {
+		names = append(names, schema.AuthFieldNames()...)
+	}
+
+	expands := map[string]struct{}{}
+	for _, expand := range strings.Split(rawExpand, ",") {
+		expands[strings.TrimSpace(strings.SplitN(expand, ".", 2)[0])] = struct{}{}
+	}
+
+	for _, field := range collection.Schema.Fields() {
+		_, isPicked := fields[field.Name]
+		_, isExpanded := expands[field.Name]
+		if isPicked || isExpanded {
+			names = append(names, field.Name)
+		}
+	}
+
+	tableName := dao.DB().QuoteSimpleColumnName(collection.Name)
+
+	columns := make([]string, len(names))
+	for i, name := range names {
+		if field := collection.Schema.GetFieldByName(name); field != nil && field.Type == schema.FieldTypeComputed {
+			expr, err := collection.ComputedFieldExpr(field, collection.Name)
+			if err != nil {
+				expr = "NULL"
+			}
+			columns[i] = fmt.Sprintf("(%s) AS [[%s]]", expr, name)
+			continue
+		}
+
+		columns[i] = tableName + "." + dao.DB().QuoteSimpleColumnName(name)
+	}
+
+	return columns
+}
+
 // expandFetch is the records fetch function that is used to expand related records.
 func expandFetch(
 	dao *daos.Dao,
@@ -108,6 +215,7 @@ func expandFetch(
 		})
 
 		if err == nil && len(records) > 0 {
+			hideRecordsRestrictedFields(dao, records, requestData)
 			autoIgnoreAuthRecordsEmailVisibility(dao, records, requestData)
 		}
 
@@ -184,6 +292,71 @@ func autoIgnoreAuthRecordsEmailVisibility(
 	return nil
 }
 
+// hideRecordsRestrictedFields hides the schema fields of the provided
+// records whose read rule is not satisfied by the current request.
+//
+// The fields are hidden for all records if their read rule
+// couldn't be resolved (the first rule error is returned).
+//
+// Note: Expects all records to be from the same collection!
+func hideRecordsRestrictedFields(
+	dao *daos.Dao,
+	records []*models.Record,
+	requestData *models.RequestData,
+) error {
+	if len(records) == 0 || (requestData != nil && requestData.Admin != nil) {
+		return nil // nothing to check
+	}
+
+	collection := records[0].Collection()
+
+	recordIds := make([]any, len(records))
+	for i, rec := range records {
+		recordIds[i] = rec.Id
+	}
+
+	var firstErr error
+
+	hidden := map[string][]string{} // record id => hidden field names
+
+	for _, field := range collection.Schema.Fields() {
+		if field.ReadRule == "" {
+			continue
+		}
+
+		// fetch the ids of the records that satisfy the field read rule
+		allowedIds := []string{}
+
+		query := dao.RecordQuery(collection).
+			Select(dao.DB().QuoteSimpleColumnName(collection.Name) + ".id").
+			AndWhere(dbx.In(dao.DB().QuoteSimpleColumnName(collection.Name)+".id", recordIds...))
+
+		resolver := resolvers.NewRecordFieldResolver(dao, collection, requestData, true)
+		expr, err := search.FilterData(field.ReadRule).BuildExpr(resolver)
+		if err == nil {
+			resolver.UpdateQuery(query)
+			query.AndWhere(expr)
+			err = query.Column(&allowedIds)
+		}
+
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+
+		for _, rec := range records {
+			if err != nil || !list.ExistInSlice(rec.Id, allowedIds) {
+				hidden[rec.Id] = append(hidden[rec.Id], field.Name)
+			}
+		}
+	}
+
+	for _, rec := range records {
+		rec.SetHiddenFields(hidden[rec.Id]...)
+	}
+
+	return firstErr
+}
+
 // hasAuthManageAccess checks whether the client is allowed to have full
 // [forms.RecordUpsert] auth management permissions
 // (aka. allowing to change system auth fields without oldPassword).
@@ -221,3 +394,256 @@ func hasAuthManageAccess(
 
 	return findErr == nil
 }
+
+// recordsCreateLimiter returns the app records create rate limiter
+// (it is created and stored in the app cache on first use).
+func recordsCreateLimiter(app core.App) *ratelimit.Limiter {
+	recordsCreateLimiterMux.Lock()
+	defer recordsCreateLimiterMux.Unlock()
+
+	limiter, _ := app.Cache().Get(recordsCreateLimiterCacheKey).(*ratelimit.Limiter)
+	if limiter == nil {
+		limiter = ratelimit.New()
+		app.Cache().Set(recordsCreateLimiterCacheKey, limiter)
+	}
+
+	return limiter
+}
+
+// checkRecordCreateQuotas checks whether the current request could create
+// a new record in the provided collection according to its quota options.
+//
+// On success it returns a function that should be called after the record
+// is created to register the new record in the auth record create rate limit.
+//
+// Admins are not restricted by the collection quotas.
+func checkRecordCreateQuotas(app core.App, c echo.Context, collection *models.Collection) (func(), error) {
+	noop := func() {}
+
+	requestData := RequestData(c)
+	if requestData.Admin != nil {
+		return noop, nil
+	}
+
+	quotas := collection.QuotaOptions()
+
+	if quotas.MaxRecords > 0 {
+		total, err := app.Dao().TotalRecords(collection)
+		if err != nil {
+			return nil, NewBadRequestError("Failed to check the collection records quota.", err)
+		}
+
+		if total >= quotas.MaxRecords {
+			return nil, NewForbiddenError(
+				"The collection records limit is reached.",
+				validation.Errors{"maxRecords": validation.NewError(
+					"quota_max_records",
+					fmt.Sprintf("The collection could have max %d records.", quotas.MaxRecords),
+				)},
+			)
+		}
+	}
+
+	if quotas.CreateRateLimit <= 0 || requestData.AuthRecord == nil {
+		return noop, nil
+	}
+
+	limiter := recordsCreateLimiter(app)
+	window := time.Duration(quotas.CreateRateWindow) * time.Second
+	key := collection.Id + "_" + requestData.AuthRecord.Collection().Id + "_" + requestData.AuthRecord.Id
+
+	if ok, retryAfter := limiter.Check(key, quotas.CreateRateLimit, window); !ok {
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+
+		return nil, NewApiError(
+			http.StatusTooManyRequests,
+			"Too many created records. Please try again later.",
+			validation.Errors{"createRateLimit": validation.NewError(
+				"quota_create_rate_limit",
+				fmt.Sprintf("You could create max %d records per %d seconds.", quotas.CreateRateLimit, quotas.CreateRateWindow),
+			)},
+		)
+	}
+
+	return func() {
+		limiter.Hit(key, window)
+	}, nil
+}
+
+// checkRecordCreateBotProtection checks the create request against
+// the collection bot protection options (honeypot fields, min submit
+// time and IP cooldown) before loading and validating the record data.
+//
+// On success it returns a function that should be called after
+// the record is successfully created to register the IP cooldown hit.
+func checkRecordCreateBotProtection(app core.App, c echo.Context, collection *models.Collection) (func(), error) {
+	noop := func() {}
+
+	if admin, _ := c.Get(ContextAdminKey).(*models.Admin); admin != nil {
+		return noop, nil // admins are not restricted
+	}
+
+	options := collection.BotProtectionOptions()
+
+	if len(options.HoneypotFields) > 0 || options.MinSubmitTime > 0 {
+		// use a generic error message to avoid giving hints to the bots
+		botErr := NewBadRequestError("Failed to create record.", nil)
+
+		data := readRequestDataValues(c.Request())
+
+		for _, field := range options.HoneypotFields {
+			if cast.ToString(data[field]) != "" {
+				return nil, botErr
+			}
+		}
+
+		if options.MinSubmitTime > 0 {
+			startedAt := cast.ToInt64(data[options.SubmitTimeField])
+			minDuration := time.Duration(options.MinSubmitTime) * time.Second
+
+			if startedAt <= 0 || time.Since(time.UnixMilli(startedAt)) < minDuration {
+				return nil, botErr
+			}
+		}
+	}
+
+	if options.IpCooldown <= 0 {
+		return noop, nil
+	}
+
+	ip := trustedUserIp(app, c.Request())
+
+	limiter := recordsCreateLimiter(app)
+	window := time.Duration(options.IpCooldown) * time.Second
+	key := collection.Id + "_ip_" + ip
+
+	if ok, retryAfter := limiter.Check(key, 1, window); !ok {
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+
+		return nil, NewApiError(
+			http.StatusTooManyRequests,
+			"Too many created records. Please try again later.",
+			validation.Errors{"ipCooldown": validation.NewError(
+				"bot_ip_cooldown",
+				fmt.Sprintf("You could create max 1 record per %d seconds.", options.IpCooldown),
+			)},
+		)
+	}
+
+	return func() {
+		limiter.Hit(key, window)
+	}, nil
+}
+
+// readRequestDataValues returns the raw json or multipart/form-data
+// request body values without consuming the request body.
+//
+// Returns an empty map for unsupported content types or invalid body.
+func readRequestDataValues(r *http.Request) map[string]any {
+	result := map[string]any{}
+
+	contentType := r.Header.Get("Content-Type")
+
+	switch {
+	case strings.HasPrefix(contentType, "application/json"):
+		rest.CopyJsonBody(r, &result)
+	case strings.HasPrefix(contentType, "multipart/form-data"):
+		if err := r.ParseMultipartForm(rest.DefaultMaxMemory); err == nil {
+			for key, values := range r.MultipartForm.Value {
+				if len(values) > 0 {
+					result[key] = values[0]
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// moderateRecordContent runs the collection content moderation checks
+// against the submitted record data and triggers the
+// OnRecordContentModeration app hook.
+//
+// Returns an error if the record was rejected, otherwise the moderation
+// verdict is stored in the collection verdict field (if any).
+func moderateRecordContent(app core.App, c echo.Context, record *models.Record) error {
+	collection := record.Collection()
+
+	options := collection.ModerationOptions()
+	if !options.Enabled {
+		return nil
+	}
+
+	if admin, _ := c.Get(ContextAdminKey).(*models.Admin); admin != nil {
+		return nil // admins are not moderated
+	}
+
+	verdict := moderation.NewVerdict()
+
+	excludeIds := []string{}
+	if !record.IsNew() {
+		excludeIds = append(excludeIds, record.Id)
+	}
+
+	for _, field := range collection.Schema.Fields() {
+		if field.Type != schema.FieldTypeText ||
+			(len(options.Fields) > 0 && !list.ExistInSlice(field.Name, options.Fields)) {
+			continue
+		}
+
+		text := record.GetString(field.Name)
+		if text == "" {
+			continue
+		}
+
+		if options.MaxLinks > 0 {
+			if total := moderation.CountLinks(text); total > options.MaxLinks {
+				verdict.Flag(
+					moderation.ReasonTooManyLinks,
+					field.Name,
+					fmt.Sprintf("Too many links (%d, max %d).", total, options.MaxLinks),
+				)
+			}
+		}
+
+		if words := moderation.FindBannedWords(text, options.BannedWords); len(words) > 0 {
+			verdict.Flag(
+				moderation.ReasonBannedWords,
+				field.Name,
+				fmt.Sprintf("Contains banned words (%s).", strings.Join(words, ", ")),
+			)
+		}
+
+		if options.CheckDuplicates && !app.Dao().IsRecordValueUnique(collection.Id, field.Name, text, excludeIds...) {
+			verdict.Flag(moderation.ReasonDuplicate, field.Name, "The same content was already submitted.")
+		}
+	}
+
+	verdict.Rejected = verdict.Flagged && options.Action == models.ModerationActionReject
+
+	event := &core.RecordContentModerationEvent{
+		HttpContext: c,
+		Record:      record,
+		Verdict:     verdict,
+	}
+
+	return app.OnRecordContentModeration().Trigger(event, func(e *core.RecordContentModerationEvent) error {
+		if e.Verdict.Rejected {
+			errs := validation.Errors{}
+			for _, reason := range e.Verdict.Reasons {
+				if reason.Field != "" && errs[reason.Field] == nil {
+					errs[reason.Field] = validation.NewError("moderation_"+inflector.Snakecase(reason.Code), reason.Message)
+				}
+			}
+
+			return NewBadRequestError("The submitted content was rejected by the moderation checks.", errs)
+		}
+
+		if options.VerdictField != "" {
+			e.Record.Set(options.VerdictField, e.Verdict)
+		}
+
+		return nil
+	})
+}
+
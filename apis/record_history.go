@@ -0,0 +1,50 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+var recordHistoryFilterFields = []string{
+	"id", "created", "updated", "version", "action", "authType", "authId",
+}
+
+// history returns a paginated list with the change history
+// entries of a single record (incl. the entries of deleted records).
+func (api *recordApi) history(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("", "Missing collection context.")
+	}
+
+	fieldResolver := search.NewSimpleFieldResolver(recordHistoryFilterFields...)
+
+	query := api.app.Dao().RecordHistoryQuery().AndWhere(dbx.HashExp{
+		"collectionId": collection.Id,
+		"recordId":     c.PathParam("id"),
+	})
+
+	result, err := search.NewProvider(fieldResolver).
+		Query(query).
+		ParseAndExec(c.QueryParams().Encode(), &[]*models.RecordHistory{})
+
+	if err != nil {
+		return NewBadRequestError("", err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// setRecordHistoryAuth sets the request auth model as
+// author of the record change history entries.
+func setRecordHistoryAuth(record *models.Record, requestData *models.RequestData) {
+	if requestData.Admin != nil {
+		record.SetHistoryAuth(models.RecordHistoryAuthAdmin, requestData.Admin.Id)
+	} else if requestData.AuthRecord != nil {
+		record.SetHistoryAuth(models.RecordHistoryAuthRecord, requestData.AuthRecord.Id)
+	}
+}
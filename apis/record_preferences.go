@@ -0,0 +1,83 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// bindRecordPreferencesApi registers the self-service auth record
+// preferences api endpoints and the corresponding handlers.
+func bindRecordPreferencesApi(app core.App, rg *echo.Group) {
+	api := recordPreferencesApi{app: app}
+
+	subGroup := rg.Group(
+		"/preferences",
+		ActivityLogger(app),
+		RequireRecordAuth(),
+	)
+	subGroup.GET("", api.view)
+	subGroup.PATCH("", api.update)
+}
+
+type recordPreferencesApi struct {
+	app core.App
+}
+
+// view returns the resolved preferences of the request auth record
+// (the stored values merged with the declared keys defaults).
+func (api *recordPreferencesApi) view(c echo.Context) error {
+	authRecord, _ := c.Get(ContextAuthRecordKey).(*models.Record)
+	if authRecord == nil {
+		return NewUnauthorizedError("", nil)
+	}
+
+	prefs, err := api.app.Dao().FindResolvedRecordPreferences(authRecord)
+	if err != nil {
+		return NewBadRequestError("Failed to load the preferences.", err)
+	}
+
+	return c.JSON(http.StatusOK, prefs)
+}
+
+// update partially updates the stored preferences of the request auth record.
+//
+// A null value resets the preference to its default.
+func (api *recordPreferencesApi) update(c echo.Context) error {
+	authRecord, _ := c.Get(ContextAuthRecordKey).(*models.Record)
+	if authRecord == nil {
+		return NewUnauthorizedError("", nil)
+	}
+
+	preferences, err := api.app.Dao().FindRecordPreferences(authRecord)
+	if err != nil {
+		return NewBadRequestError("Failed to load the preferences.", err)
+	}
+
+	form := forms.NewRecordPreferencesUpdate(api.app, preferences)
+
+	// load request
+	if err := c.Bind(&form.Data); err != nil {
+		return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	submitErr := form.Submit(func(next forms.InterceptorNextFunc) forms.InterceptorNextFunc {
+		return func() error {
+			if err := next(); err != nil {
+				return NewBadRequestError("Failed to update the preferences.", err)
+			}
+
+			prefs, err := api.app.Dao().FindResolvedRecordPreferences(authRecord)
+			if err != nil {
+				return NewBadRequestError("Failed to load the preferences.", err)
+			}
+
+			return c.JSON(http.StatusOK, prefs)
+		}
+	})
+
+	return submitErr
+}
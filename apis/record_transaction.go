@@ -0,0 +1,71 @@
+package apis
+
+import (
+	"log"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/routine"
+)
+
+// ContextTransactionDaoKey is the request context key of the records
+// write request transaction dao (see [RequestDao]).
+const ContextTransactionDaoKey = "transactionDao"
+
+// RequestDao returns the dao that should be used for the db operations
+// of the current request (eg. in the record request hooks).
+//
+// For the records create, update and delete requests of collections
+// with enabled request transaction it returns the request transaction dao,
+// so that the hooks changes are committed or reverted together with
+// the record ones. Otherwise it returns the default app dao.
+func RequestDao(app core.App, c echo.Context) *daos.Dao {
+	if dao, _ := c.Get(ContextTransactionDaoKey).(*daos.Dao); dao != nil {
+		return dao
+	}
+
+	return app.Dao()
+}
+
+// runRecordRequest executes fn with the default app dao or, if the
+// collection has enabled request transaction, with a new transaction dao
+// that is also accessible in the request hooks via [RequestDao].
+func runRecordRequest(
+	app core.App,
+	c echo.Context,
+	collection *models.Collection,
+	fn func(dao *daos.Dao) error,
+) error {
+	if !collection.TransactionOptions().Enabled {
+		return fn(app.Dao())
+	}
+
+	return app.Dao().RunInTransaction(func(txDao *daos.Dao) error {
+		c.Set(ContextTransactionDaoKey, txDao)
+		defer c.Set(ContextTransactionDaoKey, nil)
+
+		return fn(txDao)
+	})
+}
+
+// commitRecordFiles moves the staged uploaded files of a form submitted
+// with deferred files commit to their record location and deletes
+// the replaced record files in the background.
+//
+// It should be called only after the form transaction is committed.
+func commitRecordFiles(app core.App, form *forms.RecordUpsert) error {
+	if err := form.CommitFiles(); err != nil {
+		return err
+	}
+
+	routine.FireAndForget(func() {
+		if err := form.DeleteOldFiles(); err != nil && app.IsDebug() {
+			log.Println(err)
+		}
+	})
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package apis
+
+import (
+	"strconv"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/spf13/cast"
+)
+
+// upsertQueryParam is the record create query parameter that enables
+// the upsert mode (aka. update the existing record with the same unique value).
+const upsertQueryParam = "upsert"
+
+// isUpsertRequest checks whether the upsert mode is enabled for the current request.
+func isUpsertRequest(c echo.Context) bool {
+	upsert, _ := strconv.ParseBool(c.QueryParam(upsertQueryParam))
+
+	return upsert
+}
+
+// findUpsertRecordId returns the id of the existing collection record that
+// has the same id, external id or unique field value as the request data.
+//
+// Returns empty string if there is no matching record and an error if more
+// than one record matches (eg. different records for the id and a unique field)
+// or if the matching record is soft deleted.
+func (api *recordApi) findUpsertRecordId(c echo.Context, collection *models.Collection) (string, error) {
+	form := forms.NewRecordUpsert(api.app, models.NewRecord(collection))
+	form.SetFullManageAccess(true)
+	if err := form.LoadRequest(c.Request(), ""); err != nil {
+		return "", NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	dao := api.app.Dao()
+
+	tableName := dao.DB().QuoteSimpleColumnName(collection.Name)
+	column := func(name string) string {
+		return tableName + "." + dao.DB().QuoteSimpleColumnName(name)
+	}
+
+	exprs := []dbx.Expression{}
+
+	if form.Id != "" {
+		exprs = append(exprs, dbx.HashExp{column("id"): form.Id})
+	}
+
+	if form.ExternalId != "" {
+		exprs = append(exprs, dbx.HashExp{column(schema.FieldNameExternalId): form.ExternalId})
+	}
+
+	if collection.IsAuth() {
+		if form.Username != "" {
+			exprs = append(exprs, dbx.HashExp{column(schema.FieldNameUsername): form.Username})
+		}
+		if form.Email != "" {
+			exprs = append(exprs, dbx.HashExp{column(schema.FieldNameEmail): form.Email})
+		}
+	}
+
+	data := form.Data()
+	for _, field := range collection.Schema.Fields() {
+		if !field.Unique {
+			continue
+		}
+
+		// only single value fields could be matched
+		if value, ok := data[field.Name]; ok && cast.ToString(value) != "" {
+			exprs = append(exprs, dbx.HashExp{column(field.Name): value})
+		}
+	}
+
+	if len(exprs) == 0 {
+		return "", nil // nothing to match
+	}
+
+	// the soft deleted records still reserve their unique values
+	// so they are also matched but explicitly rejected below
+	isSoftDelete := collection.SoftDeleteOptions().Enabled
+
+	query := dao.RecordQueryWithDeleted(collection).
+		Select(column("id")).
+		AndWhere(dbx.Or(exprs...)).
+		Limit(2)
+
+	if isSoftDelete {
+		query.AndSelect(column(schema.FieldNameDeleted))
+	}
+
+	rows := []dbx.NullStringMap{}
+	if err := query.All(&rows); err != nil {
+		return "", NewBadRequestError("Failed to find the upsert record.", err)
+	}
+
+	switch len(rows) {
+	case 0:
+		return "", nil
+	case 1:
+		if isSoftDelete && rows[0][schema.FieldNameDeleted].String != "" {
+			return "", NewBadRequestError("The submitted unique values match a deleted record. Restore or purge it first.", nil)
+		}
+		return rows[0]["id"].String, nil
+	default:
+		return "", NewBadRequestError("The submitted unique values match more than one record.", nil)
+	}
+}
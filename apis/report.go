@@ -0,0 +1,138 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/reports"
+)
+
+// bindReportApi registers the scheduled reports api endpoints and the corresponding handlers.
+func bindReportApi(app core.App, rg *echo.Group) {
+	api := reportApi{app: app}
+
+	subGroup := rg.Group("/reports", ActivityLogger(app), RequireAdminAuth())
+	subGroup.GET("", api.list)
+	subGroup.POST("", api.create)
+	subGroup.GET("/:id", api.view)
+	subGroup.PATCH("/:id", api.update)
+	subGroup.DELETE("/:id", api.delete)
+	subGroup.POST("/:id/run", api.run)
+	subGroup.GET("/:id/runs", api.runs)
+}
+
+type reportApi struct {
+	app core.App
+}
+
+func (api *reportApi) list(c echo.Context) error {
+	items, err := api.app.Dao().FindAllReports()
+	if err != nil {
+		return NewBadRequestError("Failed to load the reports.", err)
+	}
+
+	return c.JSON(http.StatusOK, items)
+}
+
+func (api *reportApi) view(c echo.Context) error {
+	report, err := api.app.Dao().FindReportById(c.PathParam("id"))
+	if err != nil || report == nil {
+		return NewNotFoundError("", err)
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
+func (api *reportApi) create(c echo.Context) error {
+	report := &models.Report{}
+
+	form := forms.NewReportUpsert(api.app, report)
+
+	// load request
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	submitErr := form.Submit(func(next forms.InterceptorNextFunc) forms.InterceptorNextFunc {
+		return func() error {
+			if err := next(); err != nil {
+				return NewBadRequestError("Failed to create report.", err)
+			}
+
+			return c.JSON(http.StatusOK, report)
+		}
+	})
+
+	return submitErr
+}
+
+func (api *reportApi) update(c echo.Context) error {
+	report, err := api.app.Dao().FindReportById(c.PathParam("id"))
+	if err != nil || report == nil {
+		return NewNotFoundError("", err)
+	}
+
+	form := forms.NewReportUpsert(api.app, report)
+
+	// load request
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	submitErr := form.Submit(func(next forms.InterceptorNextFunc) forms.InterceptorNextFunc {
+		return func() error {
+			if err := next(); err != nil {
+				return NewBadRequestError("Failed to update report.", err)
+			}
+
+			return c.JSON(http.StatusOK, report)
+		}
+	})
+
+	return submitErr
+}
+
+func (api *reportApi) delete(c echo.Context) error {
+	report, err := api.app.Dao().FindReportById(c.PathParam("id"))
+	if err != nil || report == nil {
+		return NewNotFoundError("", err)
+	}
+
+	if err := api.app.Dao().DeleteReport(report); err != nil {
+		return NewBadRequestError("Failed to delete report.", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// run generates and delivers the report immediately (regardless of
+// its schedule and enabled state) and returns the stored report run.
+//
+// The delivery failures are returned as part of the report run.
+func (api *reportApi) run(c echo.Context) error {
+	report, err := api.app.Dao().FindReportById(c.PathParam("id"))
+	if err != nil || report == nil {
+		return NewNotFoundError("", err)
+	}
+
+	run, _ := reports.Run(api.app, report)
+
+	return c.JSON(http.StatusOK, run)
+}
+
+func (api *reportApi) runs(c echo.Context) error {
+	report, err := api.app.Dao().FindReportById(c.PathParam("id"))
+	if err != nil || report == nil {
+		return NewNotFoundError("", err)
+	}
+
+	runs, err := api.app.Dao().FindReportRuns(report.Id, reports.MaxRunsHistory)
+	if err != nil {
+		return NewBadRequestError("Failed to load the report runs.", err)
+	}
+
+	return c.JSON(http.StatusOK, runs)
+}
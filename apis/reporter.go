@@ -0,0 +1,133 @@
+package apis
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/reporter"
+	"github.com/pocketbase/pocketbase/tools/routine"
+)
+
+// ErrorReporter middleware reports the recovered handler panics and
+// the slow requests (if enabled) to the app error reporter.
+//
+// The recovered panics are reported and rethrown so that they could
+// be further handled by the router Recover middleware.
+func ErrorReporter(app core.App) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			config := app.Settings().ErrorReporting
+			if !config.Enabled {
+				return next(c)
+			}
+
+			defer func() {
+				if r := recover(); r != nil {
+					event := reporter.NewEvent(reporter.LevelFatal, fmt.Sprintf("panic: %v", r))
+					if err, ok := r.(error); ok {
+						event.Error = err
+					}
+					event.Stacktrace = reporter.NewStacktrace(2)
+
+					reportError(app, c, event)
+
+					panic(r)
+				}
+			}()
+
+			start := time.Now()
+
+			err := next(c)
+
+			duration := time.Since(start)
+
+			if config.SlowThreshold > 0 && duration >= time.Duration(config.SlowThreshold)*time.Millisecond {
+				event := reporter.NewEvent(
+					reporter.LevelWarning,
+					fmt.Sprintf("Slow request %s %s", c.Request().Method, c.Path()),
+				)
+				event.Extra["duration"] = duration.Milliseconds()
+
+				reportError(app, c, event)
+			}
+
+			return err
+		}
+	}
+}
+
+// reportError enriches the provided event with the request info,
+// scrubs its personally identifiable information (if not allowed)
+// and sends it in a separate go routine to the app error reporter.
+func reportError(app core.App, c echo.Context, event *reporter.Event) {
+	config := app.Settings().ErrorReporting
+	if !config.Enabled {
+		return
+	}
+
+	httpRequest := c.Request()
+
+	ip, _, _ := net.SplitHostPort(httpRequest.RemoteAddr)
+	ip = realUserIp(httpRequest, ip)
+
+	headers := make(map[string]string, len(httpRequest.Header))
+	for k := range httpRequest.Header {
+		headers[k] = httpRequest.Header.Get(k)
+	}
+
+	event.Request = &reporter.Request{
+		Method:  httpRequest.Method,
+		Url:     httpRequest.URL.RequestURI(),
+		Headers: headers,
+	}
+
+	if route := c.Path(); route != "" {
+		event.Tags["route"] = route
+	}
+
+	if requestId, _ := c.Get(ContextRequestIdKey).(string); requestId != "" {
+		event.Tags["requestId"] = requestId
+	}
+
+	if record, _ := c.Get(ContextAuthRecordKey).(*models.Record); record != nil {
+		event.Tags["authId"] = record.Id
+	} else if admin, _ := c.Get(ContextAdminKey).(*models.Admin); admin != nil {
+		event.Tags["authId"] = admin.Id
+	}
+
+	if config.SendPii {
+		event.Scrub(config.ScrubFields...)
+		event.Request.Ip = ip
+	} else {
+		event.Scrub(append(append([]string{}, reporter.DefaultScrubFields...), config.ScrubFields...)...)
+	}
+
+	reportEvent := &core.ErrorReportEvent{
+		HttpContext: c,
+		Reporter:    app.NewErrorReporter(),
+		Event:       event,
+	}
+
+	hookErr := app.OnBeforeErrorReport().Trigger(reportEvent, func(e *core.ErrorReportEvent) error {
+		if e.Reporter == nil {
+			return nil
+		}
+
+		routine.FireAndForget(func() {
+			if err := e.Reporter.Report(e.Event); err != nil && app.IsDebug() {
+				log.Println("Error report failed:", err)
+			}
+		})
+
+		return nil
+	})
+
+	if hookErr != nil && app.IsDebug() {
+		log.Println(hookErr)
+	}
+}
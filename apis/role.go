@@ -0,0 +1,218 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// bindRoleApi registers the roles and the auth records role
+// assignments api endpoints and the corresponding handlers.
+func bindRoleApi(app core.App, rg *echo.Group) {
+	api := roleApi{app: app}
+
+	subGroup := rg.Group("/roles", ActivityLogger(app), RequireAdminAuth())
+	subGroup.GET("", api.list)
+	subGroup.POST("", api.create)
+	subGroup.GET("/:id", api.view)
+	subGroup.PATCH("/:id", api.update)
+	subGroup.DELETE("/:id", api.delete)
+
+	recordGroup := rg.Group(
+		"/collections/:collection/records/:id/roles",
+		ActivityLogger(app),
+		LoadCollectionContext(app, models.CollectionTypeAuth),
+	)
+	recordGroup.GET("", api.listRecordRoles, RequireAdminOrOwnerAuth("id"))
+	recordGroup.POST("", api.assignRecordRole, RequireAdminAuth())
+	recordGroup.DELETE("/:role", api.unassignRecordRole, RequireAdminAuth())
+}
+
+type roleApi struct {
+	app core.App
+}
+
+func (api *roleApi) list(c echo.Context) error {
+	roles, err := api.app.Dao().FindAllRoles()
+	if err != nil {
+		return NewBadRequestError("Failed to load the roles.", err)
+	}
+
+	return c.JSON(http.StatusOK, roles)
+}
+
+func (api *roleApi) view(c echo.Context) error {
+	role, err := api.app.Dao().FindRoleById(c.PathParam("id"))
+	if err != nil || role == nil {
+		return NewNotFoundError("", err)
+	}
+
+	return c.JSON(http.StatusOK, role)
+}
+
+func (api *roleApi) create(c echo.Context) error {
+	role := &models.Role{}
+
+	form := forms.NewRoleUpsert(api.app, role)
+
+	// load request
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	submitErr := form.Submit(func(next forms.InterceptorNextFunc) forms.InterceptorNextFunc {
+		return func() error {
+			if err := next(); err != nil {
+				return NewBadRequestError("Failed to create role.", err)
+			}
+
+			return c.JSON(http.StatusOK, role)
+		}
+	})
+
+	return submitErr
+}
+
+func (api *roleApi) update(c echo.Context) error {
+	role, err := api.app.Dao().FindRoleById(c.PathParam("id"))
+	if err != nil || role == nil {
+		return NewNotFoundError("", err)
+	}
+
+	form := forms.NewRoleUpsert(api.app, role)
+
+	// load request
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	submitErr := form.Submit(func(next forms.InterceptorNextFunc) forms.InterceptorNextFunc {
+		return func() error {
+			if err := next(); err != nil {
+				return NewBadRequestError("Failed to update role.", err)
+			}
+
+			return c.JSON(http.StatusOK, role)
+		}
+	})
+
+	return submitErr
+}
+
+func (api *roleApi) delete(c echo.Context) error {
+	role, err := api.app.Dao().FindRoleById(c.PathParam("id"))
+	if err != nil || role == nil {
+		return NewNotFoundError("", err)
+	}
+
+	if err := api.app.Dao().DeleteRole(role); err != nil {
+		return NewBadRequestError("Failed to delete role.", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// listRecordRoles returns the roles assigned to the path auth record.
+func (api *roleApi) listRecordRoles(c echo.Context) error {
+	record, err := api.findRecord(c)
+	if err != nil {
+		return err
+	}
+
+	roles, err := api.app.Dao().FindAuthRecordRoles(record)
+	if err != nil {
+		return NewBadRequestError("Failed to fetch the roles of the specified auth record.", err)
+	}
+
+	return c.JSON(http.StatusOK, roles)
+}
+
+// assignRecordRole assigns the submitted role (name or id)
+// to the path auth record and returns all of its roles.
+func (api *roleApi) assignRecordRole(c echo.Context) error {
+	record, err := api.findRecord(c)
+	if err != nil {
+		return err
+	}
+
+	data := struct {
+		Role string `json:"role"`
+	}{}
+	if err := c.Bind(&data); err != nil {
+		return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	role, err := api.findRole(data.Role)
+	if err != nil {
+		return NewBadRequestError("Missing or invalid role.", err)
+	}
+
+	if err := api.app.Dao().AssignAuthRecordRole(record, role); err != nil {
+		return NewBadRequestError("Failed to assign the role.", err)
+	}
+
+	roles, err := api.app.Dao().FindAuthRecordRoles(record)
+	if err != nil {
+		return NewBadRequestError("Failed to fetch the roles of the specified auth record.", err)
+	}
+
+	return c.JSON(http.StatusOK, roles)
+}
+
+// unassignRecordRole removes the path role (name or id)
+// assignment from the path auth record.
+func (api *roleApi) unassignRecordRole(c echo.Context) error {
+	record, err := api.findRecord(c)
+	if err != nil {
+		return err
+	}
+
+	role, err := api.findRole(c.PathParam("role"))
+	if err != nil {
+		return NewNotFoundError("", err)
+	}
+
+	if _, err := api.app.Dao().FindAuthRole(record, role); err != nil {
+		return NewNotFoundError("The role is not assigned to the auth record.", err)
+	}
+
+	if err := api.app.Dao().UnassignAuthRecordRole(record, role); err != nil {
+		return NewBadRequestError("Failed to unassign the role.", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// findRecord loads the auth record matching the request ":id"
+// path param and the collection from the request context.
+func (api *roleApi) findRecord(c echo.Context) (*models.Record, error) {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return nil, NewNotFoundError("Missing collection context.", nil)
+	}
+
+	id := c.PathParam("id")
+	if id == "" {
+		return nil, NewNotFoundError("", nil)
+	}
+
+	record, err := api.app.Dao().FindRecordById(collection.Id, id)
+	if err != nil || record == nil {
+		return nil, NewNotFoundError("", err)
+	}
+
+	return record, nil
+}
+
+// findRole finds a single role by its name or id.
+func (api *roleApi) findRole(nameOrId string) (*models.Role, error) {
+	role, err := api.app.Dao().FindRoleByName(nameOrId)
+	if err == nil {
+		return role, nil
+	}
+
+	return api.app.Dao().FindRoleById(nameOrId)
+}
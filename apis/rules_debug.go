@@ -0,0 +1,165 @@
+package apis
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/resolvers"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+const (
+	// RulesDebugHeader is the request header that admins could use to
+	// evaluate the collection API rules on behalf of a guest
+	// ("guest") or an auth record ("collectionNameOrId/recordId").
+	RulesDebugHeader = "X-Debug-Rules"
+
+	// ContextRulesDebugKey is the request context key that marks
+	// the rules debug mode as enabled.
+	ContextRulesDebugKey = "rulesDebug"
+
+	rulesDebugGuest = "guest"
+)
+
+var whitespaceRegex = regexp.MustCompile(`\s+`)
+
+// LoadRulesDebugContext middleware replaces the request admin auth
+// with the guest or auth record specified in the [RulesDebugHeader]
+// so that the collection API rules could be evaluated as them.
+//
+// In debug mode the failed rules checks respond with 403 error
+// containing the failed rule and its normalized expression.
+//
+// The header is ignored for non-admin requests.
+func LoadRulesDebugContext(app core.App) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			value := strings.TrimSpace(c.Request().Header.Get(RulesDebugHeader))
+			if value == "" {
+				return next(c)
+			}
+
+			if admin, _ := c.Get(ContextAdminKey).(*models.Admin); admin == nil {
+				return next(c)
+			}
+
+			var authRecord *models.Record
+
+			if value != rulesDebugGuest {
+				parts := strings.SplitN(value, "/", 2)
+				if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+					return NewBadRequestError(fmt.Sprintf(
+						"Invalid %s header value - expected %q or \"collectionNameOrId/recordId\".",
+						RulesDebugHeader,
+						rulesDebugGuest,
+					), nil)
+				}
+
+				record, err := app.Dao().FindRecordById(parts[0], parts[1])
+				if err != nil || !record.Collection().IsAuth() {
+					return NewBadRequestError("Missing or invalid rules debug auth record.", err)
+				}
+
+				authRecord = record
+			}
+
+			c.Set(ContextAdminKey, nil)
+			c.Set(ContextAuthRecordKey, authRecord)
+			c.Set(ContextRulesDebugKey, true)
+
+			// reset the cached request data (if already loaded)
+			c.Set(ContextRequestDataKey, nil)
+
+			return next(c)
+		}
+	}
+}
+
+// isRulesDebug checks whether the rules debug mode is enabled for the request.
+func isRulesDebug(c echo.Context) bool {
+	v, _ := c.Get(ContextRulesDebugKey).(bool)
+	return v
+}
+
+// ruleFailureError returns the provided fallback error or, if the rules
+// debug mode is enabled, a 403 error describing the failed collection rule.
+func (api *recordApi) ruleFailureError(
+	c echo.Context,
+	collection *models.Collection,
+	ruleName string,
+	rule *string,
+	fallback *ApiError,
+) *ApiError {
+	if !isRulesDebug(c) {
+		return fallback
+	}
+
+	data := map[string]any{
+		"rule":       ruleName,
+		"expression": nil,
+	}
+
+	message := fmt.Sprintf("The %s failed.", ruleName)
+
+	if rule == nil {
+		message = fmt.Sprintf("The %s is locked and only admins can perform this action.", ruleName)
+	} else {
+		data["expression"] = whitespaceRegex.ReplaceAllString(strings.TrimSpace(*rule), " ")
+
+		if sql, err := api.resolveRuleSql(c, collection, *rule); err == nil {
+			data["sql"] = sql
+		}
+	}
+
+	apiErr := NewForbiddenError(message, nil)
+	apiErr.Data = data
+
+	return apiErr
+}
+
+// resolveRuleSql builds the collection records query filtered by the
+// specified rule, inlining its resolved placeholder params.
+func (api *recordApi) resolveRuleSql(c echo.Context, collection *models.Collection, rule string) (string, error) {
+	query := api.app.Dao().RecordQuery(collection)
+
+	if rule != "" {
+		resolver := resolvers.NewRecordFieldResolver(api.app.Dao(), collection, RequestData(c), true)
+
+		expr, err := search.FilterData(rule).BuildExpr(resolver)
+		if err != nil {
+			return "", err
+		}
+
+		resolver.UpdateQuery(query)
+		query.AndWhere(expr)
+	}
+
+	built := query.Build()
+	sql := built.SQL()
+
+	for name, value := range built.Params() {
+		var replacement string
+		switch v := value.(type) {
+		case string:
+			replacement = "'" + strings.ReplaceAll(v, "'", "''") + "'"
+		case nil:
+			replacement = "NULL"
+		default:
+			replacement = fmt.Sprintf("%v", v)
+		}
+		sql = strings.ReplaceAll(sql, "{:"+name+"}", replacement)
+	}
+
+	return sql, nil
+}
+
+// existsRecord checks whether the specified record exists
+// (used to distinguish between missing records and rule failures).
+func (api *recordApi) existsRecord(collection *models.Collection, recordId string) bool {
+	record, err := api.app.Dao().FindRecordById(collection.Id, recordId)
+	return err == nil && record != nil
+}
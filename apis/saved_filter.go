@@ -0,0 +1,145 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// bindSavedFilterApi registers the collection saved filters api
+// endpoints and the corresponding handlers.
+func bindSavedFilterApi(app core.App, rg *echo.Group) {
+	api := savedFilterApi{app: app}
+
+	subGroup := rg.Group(
+		"/collections/:collection/saved-filters",
+		ActivityLogger(app),
+		RequireAdminAuth(),
+		LoadCollectionContext(app),
+	)
+	subGroup.GET("", api.list)
+	subGroup.POST("", api.create)
+	subGroup.GET("/:id", api.view)
+	subGroup.PATCH("/:id", api.update)
+	subGroup.DELETE("/:id", api.delete)
+}
+
+type savedFilterApi struct {
+	app core.App
+}
+
+func (api *savedFilterApi) list(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("", "Missing collection context.")
+	}
+
+	savedFilters, err := api.app.Dao().FindSavedFiltersByCollection(collection.Id)
+	if err != nil {
+		return NewBadRequestError("Failed to load the collection saved filters.", err)
+	}
+
+	return c.JSON(http.StatusOK, savedFilters)
+}
+
+func (api *savedFilterApi) view(c echo.Context) error {
+	savedFilter, err := api.findSavedFilter(c)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, savedFilter)
+}
+
+func (api *savedFilterApi) create(c echo.Context) error {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return NewNotFoundError("", "Missing collection context.")
+	}
+
+	savedFilter := &models.SavedFilter{}
+
+	form := forms.NewSavedFilterUpsert(api.app, collection, savedFilter)
+
+	// load request
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	submitErr := form.Submit(func(next forms.InterceptorNextFunc) forms.InterceptorNextFunc {
+		return func() error {
+			if err := next(); err != nil {
+				return NewBadRequestError("Failed to create saved filter.", err)
+			}
+
+			return c.JSON(http.StatusOK, savedFilter)
+		}
+	})
+
+	return submitErr
+}
+
+func (api *savedFilterApi) update(c echo.Context) error {
+	savedFilter, err := api.findSavedFilter(c)
+	if err != nil {
+		return err
+	}
+
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+
+	form := forms.NewSavedFilterUpsert(api.app, collection, savedFilter)
+
+	// load request
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	submitErr := form.Submit(func(next forms.InterceptorNextFunc) forms.InterceptorNextFunc {
+		return func() error {
+			if err := next(); err != nil {
+				return NewBadRequestError("Failed to update saved filter.", err)
+			}
+
+			return c.JSON(http.StatusOK, savedFilter)
+		}
+	})
+
+	return submitErr
+}
+
+func (api *savedFilterApi) delete(c echo.Context) error {
+	savedFilter, err := api.findSavedFilter(c)
+	if err != nil {
+		return err
+	}
+
+	if err := api.app.Dao().DeleteSavedFilter(savedFilter); err != nil {
+		return NewBadRequestError("Failed to delete saved filter.", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// findSavedFilter loads the saved filter matching the request
+// ":id" path param and the collection from the request context.
+func (api *savedFilterApi) findSavedFilter(c echo.Context) (*models.SavedFilter, error) {
+	collection, _ := c.Get(ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return nil, NewNotFoundError("", "Missing collection context.")
+	}
+
+	id := c.PathParam("id")
+	if id == "" {
+		return nil, NewNotFoundError("", nil)
+	}
+
+	savedFilter, err := api.app.Dao().FindSavedFilterById(id)
+	if err != nil || savedFilter == nil || savedFilter.CollectionId != collection.Id {
+		return nil, NewNotFoundError("", err)
+	}
+
+	return savedFilter, nil
+}
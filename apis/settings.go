@@ -1,12 +1,15 @@
 package apis
 
 import (
+	"log"
 	"net/http"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/labstack/echo/v5"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/guardrails"
+	"github.com/pocketbase/pocketbase/tools/routine"
 	"github.com/pocketbase/pocketbase/tools/security"
 )
 
@@ -19,6 +22,8 @@ func bindSettingsApi(app core.App, rg *echo.Group) {
 	subGroup.PATCH("", api.set)
 	subGroup.POST("/test/s3", api.testS3)
 	subGroup.POST("/test/email", api.testEmail)
+	subGroup.POST("/token-signing/rotate", api.rotateTokenSigningKeys)
+	subGroup.POST("/apple/generate-client-secret", api.generateAppleClientSecret)
 }
 
 type settingsApi struct {
@@ -75,6 +80,13 @@ func (api *settingsApi) set(c echo.Context) error {
 
 	if submitErr == nil {
 		api.app.OnSettingsAfterUpdateRequest().Trigger(event)
+
+		// re-evaluate the guardrails with the new thresholds
+		routine.FireAndForget(func() {
+			if err := guardrails.Check(api.app); err != nil && api.app.IsDebug() {
+				log.Println("Guardrails check failed:", err)
+			}
+		})
 	}
 
 	return submitErr
@@ -125,3 +137,47 @@ func (api *settingsApi) testEmail(c echo.Context) error {
 
 	return c.NoContent(http.StatusNoContent)
 }
+
+// rotateTokenSigningKeys retires the active auth tokens signing key
+// and generates a new one.
+//
+// The retired keys remain published for the max auth tokens
+// duration so that the already issued tokens could still be verified.
+func (api *settingsApi) rotateTokenSigningKeys(c echo.Context) error {
+	form := forms.NewSettingsUpsert(api.app)
+
+	if err := form.RotateTokenSigningKeys(); err != nil {
+		return NewBadRequestError("Failed to generate a new token signing key.", err)
+	}
+
+	if err := form.Submit(); err != nil {
+		return NewBadRequestError("Failed to save the token signing keys.", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// generateAppleClientSecret generates a new Sign in with Apple
+// client secret JWT (it is not stored in the settings).
+func (api *settingsApi) generateAppleClientSecret(c echo.Context) error {
+	form := forms.NewAppleClientSecretCreate(api.app)
+
+	// load request
+	if err := c.Bind(form); err != nil {
+		return NewBadRequestError("An error occurred while loading the submitted data.", err)
+	}
+
+	secret, err := form.Submit()
+	if err != nil {
+		if fErr, ok := err.(validation.Errors); ok {
+			// form error
+			return NewBadRequestError("Invalid client secret data.", fErr)
+		}
+
+		return NewBadRequestError("Failed to generate the client secret.", err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"secret": secret,
+	})
+}
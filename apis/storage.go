@@ -0,0 +1,313 @@
+package apis
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/spf13/cast"
+)
+
+// bindStorageApi registers the admin storage file manager
+// api endpoints and the corresponding handlers.
+func bindStorageApi(app core.App, rg *echo.Group) {
+	api := storageApi{app: app}
+
+	subGroup := rg.Group("/storage", ActivityLogger(app), RequireAdminAuth())
+	subGroup.GET("", api.list)
+	subGroup.GET("/*", api.download)
+	subGroup.POST("/*", api.upload)
+	subGroup.PATCH("/*", api.rename)
+	subGroup.DELETE("/*", api.delete)
+}
+
+type storageApi struct {
+	app core.App
+}
+
+// storageFile defines a single storage file list item.
+type storageFile struct {
+	Key      string         `json:"key"`
+	Size     int64          `json:"size"`
+	Modified types.DateTime `json:"modified"`
+
+	// Orphan indicates that the file is in a record files dir
+	// but it is not referenced by the record anymore.
+	Orphan bool `json:"orphan"`
+}
+
+// list returns the storage files starting with the "prefix" query param.
+//
+// Set the "orphans" query param to list only the orphan record files.
+func (api *storageApi) list(c echo.Context) error {
+	prefix := c.QueryParam("prefix")
+	if strings.Contains(prefix, "..") || strings.Contains(prefix, "\\") {
+		return NewBadRequestError("Invalid prefix.", nil)
+	}
+
+	onlyOrphans := cast.ToBool(c.QueryParam("orphans"))
+
+	fs, err := api.app.NewFilesystem()
+	if err != nil {
+		return NewBadRequestError("Filesystem initialization failure.", err)
+	}
+	defer fs.Close()
+
+	objs, err := fs.List(prefix)
+	if err != nil {
+		return NewBadRequestError("Failed to list the storage files.", err)
+	}
+
+	keys := make([]string, len(objs))
+	for i, obj := range objs {
+		keys[i] = obj.Key
+	}
+
+	orphans, err := api.app.Dao().FindOrphanRecordFileKeys(keys)
+	if err != nil {
+		return NewBadRequestError("Failed to check the storage files references.", err)
+	}
+
+	result := make([]*storageFile, 0, len(objs))
+	for _, obj := range objs {
+		file := &storageFile{
+			Key:    obj.Key,
+			Size:   obj.Size,
+			Orphan: list.ExistInSlice(obj.Key, orphans),
+		}
+		file.Modified, _ = types.ParseDateTime(obj.ModTime)
+
+		if onlyOrphans && !file.Orphan {
+			continue
+		}
+
+		result = append(result, file)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (api *storageApi) download(c echo.Context) error {
+	key, err := storageKeyParam(c)
+	if err != nil {
+		return NewNotFoundError("", err)
+	}
+
+	fs, err := api.app.NewFilesystem()
+	if err != nil {
+		return NewBadRequestError("Filesystem initialization failure.", err)
+	}
+	defer fs.Close()
+
+	if err := fs.Serve(c.Response(), c.Request(), key, path.Base(key)); err != nil {
+		return NewNotFoundError("", err)
+	}
+
+	return nil
+}
+
+// upload stores the multipart "file" at the path key
+// (existing files are not overwritten).
+func (api *storageApi) upload(c echo.Context) error {
+	key, err := storageKeyParam(c)
+	if err != nil {
+		return NewBadRequestError("Invalid file key.", err)
+	}
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return NewBadRequestError("Missing or invalid file.", err)
+	}
+
+	fs, err := api.app.NewFilesystem()
+	if err != nil {
+		return NewBadRequestError("Filesystem initialization failure.", err)
+	}
+	defer fs.Close()
+
+	if exists, _ := fs.Exists(key); exists {
+		return NewBadRequestError("File with the specified key already exists.", nil)
+	}
+
+	if err := fs.UploadMultipart(fh, key); err != nil {
+		return NewBadRequestError("Failed to upload the file.", err)
+	}
+
+	return api.view(c, key)
+}
+
+// rename moves the path key file to the submitted "newKey" location.
+//
+// The files that are still referenced by a record cannot be renamed.
+func (api *storageApi) rename(c echo.Context) error {
+	key, err := storageKeyParam(c)
+	if err != nil {
+		return NewNotFoundError("", err)
+	}
+
+	data := struct {
+		NewKey string `json:"newKey"`
+	}{}
+	if err := c.Bind(&data); err != nil {
+		return NewBadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	newKey, err := normalizeStorageKey(data.NewKey)
+	if err != nil {
+		return NewBadRequestError("Invalid new file key.", err)
+	}
+
+	fs, err := api.app.NewFilesystem()
+	if err != nil {
+		return NewBadRequestError("Filesystem initialization failure.", err)
+	}
+	defer fs.Close()
+
+	if exists, _ := fs.Exists(key); !exists {
+		return NewNotFoundError("", nil)
+	}
+
+	if exists, _ := fs.Exists(newKey); exists {
+		return NewBadRequestError("File with the specified new key already exists.", nil)
+	}
+
+	if err := api.ensureNotReferenced(key); err != nil {
+		return err
+	}
+
+	if err := fs.Rename(key, newKey); err != nil {
+		return NewBadRequestError("Failed to rename the file.", err)
+	}
+
+	return api.view(c, newKey)
+}
+
+// delete deletes the path key file.
+//
+// The files that are still referenced by a record cannot be deleted.
+func (api *storageApi) delete(c echo.Context) error {
+	key, err := storageKeyParam(c)
+	if err != nil {
+		return NewNotFoundError("", err)
+	}
+
+	fs, err := api.app.NewFilesystem()
+	if err != nil {
+		return NewBadRequestError("Filesystem initialization failure.", err)
+	}
+	defer fs.Close()
+
+	if exists, _ := fs.Exists(key); !exists {
+		return NewNotFoundError("", nil)
+	}
+
+	if err := api.ensureNotReferenced(key); err != nil {
+		return err
+	}
+
+	if err := fs.Delete(key); err != nil {
+		return NewBadRequestError("Failed to delete the file.", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// view responds with the storage file list item of the provided key.
+func (api *storageApi) view(c echo.Context, key string) error {
+	fs, err := api.app.NewFilesystem()
+	if err != nil {
+		return NewBadRequestError("Filesystem initialization failure.", err)
+	}
+	defer fs.Close()
+
+	attrs, err := fs.Attributes(key)
+	if err != nil {
+		return NewNotFoundError("", err)
+	}
+
+	orphans, err := api.app.Dao().FindOrphanRecordFileKeys([]string{key})
+	if err != nil {
+		return NewBadRequestError("Failed to check the storage file references.", err)
+	}
+
+	file := &storageFile{
+		Key:    key,
+		Size:   attrs.Size,
+		Orphan: len(orphans) > 0,
+	}
+	file.Modified, _ = types.ParseDateTime(attrs.ModTime)
+
+	return c.JSON(http.StatusOK, file)
+}
+
+// ensureNotReferenced returns an error if the provided
+// storage key is a record file that is still in use.
+func (api *storageApi) ensureNotReferenced(key string) error {
+	parts := strings.Split(key, "/")
+	if len(parts) != 3 && (len(parts) != 4 || !strings.HasPrefix(parts[2], "thumbs_")) {
+		return nil // not a record file or thumb
+	}
+
+	// the record files are stored in "collectionId/recordId/filename"
+	collection, err := api.app.Dao().FindCollectionByNameOrId(parts[0])
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return NewBadRequestError("Failed to check the storage file references.", err)
+	}
+	if collection == nil || collection.Id != parts[0] {
+		return nil // not a collection files dir
+	}
+
+	_, err = api.app.Dao().FindRecordByIdWithDeleted(collection.Id, parts[1])
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil // not a record files dir
+	}
+	if err != nil {
+		return NewBadRequestError("Failed to check the storage file references.", err)
+	}
+
+	orphans, err := api.app.Dao().FindOrphanRecordFileKeys([]string{key})
+	if err != nil {
+		return NewBadRequestError("Failed to check the storage file references.", err)
+	}
+
+	if len(orphans) == 0 {
+		return NewBadRequestError("The file is still referenced by a record.", nil)
+	}
+
+	return nil
+}
+
+// storageKeyParam extracts and normalizes the request path wildcard storage key.
+func storageKeyParam(c echo.Context) (string, error) {
+	key, err := url.PathUnescape(c.PathParam("*"))
+	if err != nil {
+		return "", err
+	}
+
+	return normalizeStorageKey(key)
+}
+
+// normalizeStorageKey validates the provided storage file key
+// and ensures that it doesn't point outside of the storage root.
+func normalizeStorageKey(key string) (string, error) {
+	key = strings.TrimSpace(key)
+
+	if key == "" ||
+		key != path.Clean(key) ||
+		path.IsAbs(key) ||
+		key == ".." ||
+		strings.HasPrefix(key, "../") ||
+		strings.Contains(key, "\\") ||
+		strings.HasSuffix(key, ".attrs") {
+		return "", errors.New("invalid storage file key")
+	}
+
+	return key, nil
+}
@@ -0,0 +1,227 @@
+// Package benchmarks implements a set of representative record rules
+// benchmarks for the record fields resolver and the search provider
+// that could be used to evaluate the SQL generation changes and to
+// detect performance regressions against a previously saved baseline.
+package benchmarks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/resolvers"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+// Case defines a single benchmark rule case.
+type Case struct {
+	// Name is the unique case identifier.
+	Name string `json:"name"`
+
+	// Collection is the name of the fixture collection to query.
+	Collection string `json:"collection"`
+
+	// Filter is the filter/rule expression to benchmark.
+	Filter string `json:"filter"`
+}
+
+// DefaultCases returns the default benchmark cases
+// executed against the [SeedFixtures] dataset.
+func DefaultCases() []Case {
+	return []Case{
+		{
+			Name:       "plain_field",
+			Collection: CollectionComments,
+			Filter:     `message ~ "ipsum 1"`,
+		},
+		{
+			Name:       "deep_relation",
+			Collection: CollectionComments,
+			Filter:     `post.author.name = "author1"`,
+		},
+		{
+			Name:       "multi_match",
+			Collection: CollectionComments,
+			Filter:     `post.tags.name = "tag1"`,
+		},
+		{
+			Name:       "collection_join",
+			Collection: CollectionComments,
+			Filter:     `@collection.bench_posts.author = author && @collection.bench_posts.title = "post1"`,
+		},
+		{
+			Name:       "mixed",
+			Collection: CollectionComments,
+			Filter:     `(post.title ~ "1" || author.name = "author2") && post.tags.name != "tag3"`,
+		},
+	}
+}
+
+// Result defines a single benchmark case result.
+type Result struct {
+	Case       string `json:"case"`
+	Size       int    `json:"size"`
+	Iterations int    `json:"iterations"`
+
+	// BuildNsPerOp is the average duration (in nanoseconds) of the
+	// rule resolving and SQL query generation.
+	BuildNsPerOp int64 `json:"buildNsPerOp"`
+
+	// QueryNsPerOp is the average duration (in nanoseconds) of a
+	// single search provider list request (incl. the query generation).
+	QueryNsPerOp int64 `json:"queryNsPerOp"`
+}
+
+// BuildQuery resolves the case filter and returns the generated records query.
+func BuildQuery(dao *daos.Dao, c Case) (*dbx.SelectQuery, error) {
+	collection, err := dao.FindCollectionByNameOrId(c.Collection)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := resolvers.NewRecordFieldResolver(dao, collection, &models.RequestData{}, true)
+
+	expr, err := search.FilterData(c.Filter).BuildExpr(resolver)
+	if err != nil {
+		return nil, err
+	}
+
+	query := dao.RecordQuery(collection).AndWhere(expr)
+
+	if err := resolver.UpdateQuery(query); err != nil {
+		return nil, err
+	}
+
+	return query, nil
+}
+
+// ExecQuery executes a paginated search provider request
+// for the case filter (similar to the records list api).
+func ExecQuery(dao *daos.Dao, c Case) (*search.Result, error) {
+	collection, err := dao.FindCollectionByNameOrId(c.Collection)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := resolvers.NewRecordFieldResolver(dao, collection, &models.RequestData{}, true)
+
+	rows := []dbx.NullStringMap{}
+
+	return search.NewProvider(resolver).
+		Query(dao.RecordQuery(collection)).
+		Page(1).
+		PerPage(30).
+		AddFilter(search.FilterData(c.Filter)).
+		Exec(&rows)
+}
+
+// Run executes the provided benchmark case the specified number of
+// iterations and returns its averaged result.
+//
+// The dataset size is only informational and it is expected to
+// match the size of the already seeded fixtures (see [SeedFixtures]).
+func Run(dao *daos.Dao, c Case, size int, iterations int) (*Result, error) {
+	if iterations <= 0 {
+		return nil, fmt.Errorf("Invalid iterations number %d.", iterations)
+	}
+
+	result := &Result{
+		Case:       c.Name,
+		Size:       size,
+		Iterations: iterations,
+	}
+
+	start := time.Now()
+	for i := 0; i < iterations; i++ {
+		query, err := BuildQuery(dao, c)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", c.Name, err)
+		}
+		query.Build()
+	}
+	result.BuildNsPerOp = time.Since(start).Nanoseconds() / int64(iterations)
+
+	start = time.Now()
+	for i := 0; i < iterations; i++ {
+		if _, err := ExecQuery(dao, c); err != nil {
+			return nil, fmt.Errorf("%s: %w", c.Name, err)
+		}
+	}
+	result.QueryNsPerOp = time.Since(start).Nanoseconds() / int64(iterations)
+
+	return result, nil
+}
+
+// Regression defines a single benchmark result metric
+// that is slower than its baseline above the allowed threshold.
+type Regression struct {
+	Case     string `json:"case"`
+	Size     int    `json:"size"`
+	Metric   string `json:"metric"`
+	Baseline int64  `json:"baseline"`
+	Current  int64  `json:"current"`
+}
+
+// String implements the [fmt.Stringer] interface.
+func (r Regression) String() string {
+	return fmt.Sprintf(
+		"%s (size %d): %s %s -> %s (+%.1f%%)",
+		r.Case,
+		r.Size,
+		r.Metric,
+		time.Duration(r.Baseline),
+		time.Duration(r.Current),
+		float64(r.Current-r.Baseline)/float64(r.Baseline)*100,
+	)
+}
+
+// Compare compares the current results with the baseline ones and
+// returns the metrics that are slower than the baseline with more
+// than the specified threshold (eg. 0.2 for 20%).
+//
+// Results without a matching baseline (by case name and size) are ignored.
+func Compare(baseline []*Result, current []*Result, threshold float64) []Regression {
+	regressions := []Regression{}
+
+	for _, c := range current {
+		var base *Result
+		for _, b := range baseline {
+			if b.Case == c.Case && b.Size == c.Size {
+				base = b
+				break
+			}
+		}
+		if base == nil {
+			continue
+		}
+
+		metrics := []struct {
+			name     string
+			baseline int64
+			current  int64
+		}{
+			{"buildNsPerOp", base.BuildNsPerOp, c.BuildNsPerOp},
+			{"queryNsPerOp", base.QueryNsPerOp, c.QueryNsPerOp},
+		}
+
+		for _, m := range metrics {
+			if m.baseline <= 0 {
+				continue
+			}
+
+			if float64(m.current) > float64(m.baseline)*(1+threshold) {
+				regressions = append(regressions, Regression{
+					Case:     c.Case,
+					Size:     c.Size,
+					Metric:   m.name,
+					Baseline: m.baseline,
+					Current:  m.current,
+				})
+			}
+		}
+	}
+
+	return regressions
+}
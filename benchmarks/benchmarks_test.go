@@ -0,0 +1,206 @@
+package benchmarks_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/benchmarks"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+// benchmarkSizes is the list with the benchmark fixture dataset sizes.
+var benchmarkSizes = []int{100, 1000}
+
+func TestSeedFixtures(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	if err := benchmarks.SeedFixtures(app.Dao(), 0); err == nil {
+		t.Fatal("Expected error for zero fixtures size")
+	}
+
+	if err := benchmarks.SeedFixtures(app.Dao(), 50); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedTotals := map[string]int{
+		benchmarks.CollectionTags:     20,
+		benchmarks.CollectionAuthors:  5,
+		benchmarks.CollectionPosts:    50,
+		benchmarks.CollectionComments: 50,
+	}
+
+	for name, expected := range expectedTotals {
+		var total int
+		if err := app.Dao().DB().Select("count(*)").From(name).Row(&total); err != nil {
+			t.Fatal(err)
+		}
+
+		if total != expected {
+			t.Errorf("Expected %d %s records, got %d", expected, name, total)
+		}
+	}
+
+	// the relations must be resolvable through the dao
+	record, err := app.Dao().FindRecordById(benchmarks.CollectionPosts, "p00000000000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindRecordById(benchmarks.CollectionAuthors, record.GetString("author")); err != nil {
+		t.Fatalf("Failed to find the post author: %v", err)
+	}
+
+	tags, err := app.Dao().FindRecordsByIds(benchmarks.CollectionTags, record.GetStringSlice("tags"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 3 {
+		t.Fatalf("Expected 3 post tags, got %d", len(tags))
+	}
+}
+
+func TestDefaultCases(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	if err := benchmarks.SeedFixtures(app.Dao(), 50); err != nil {
+		t.Fatal(err)
+	}
+
+	names := map[string]struct{}{}
+
+	for _, c := range benchmarks.DefaultCases() {
+		if _, ok := names[c.Name]; ok {
+			t.Fatalf("Duplicated case name %q", c.Name)
+		}
+		names[c.Name] = struct{}{}
+
+		if _, err := benchmarks.BuildQuery(app.Dao(), c); err != nil {
+			t.Errorf("[%s] Failed to build query: %v", c.Name, err)
+			continue
+		}
+
+		result, err := benchmarks.ExecQuery(app.Dao(), c)
+		if err != nil {
+			t.Errorf("[%s] Failed to execute query: %v", c.Name, err)
+			continue
+		}
+
+		if result.TotalItems == 0 {
+			t.Errorf("[%s] Expected at least 1 matching record", c.Name)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	if err := benchmarks.SeedFixtures(app.Dao(), 10); err != nil {
+		t.Fatal(err)
+	}
+
+	c := benchmarks.DefaultCases()[0]
+
+	if _, err := benchmarks.Run(app.Dao(), c, 10, 0); err == nil {
+		t.Fatal("Expected error for zero iterations")
+	}
+
+	if _, err := benchmarks.Run(app.Dao(), benchmarks.Case{Name: "invalid", Collection: "missing"}, 10, 1); err == nil {
+		t.Fatal("Expected error for missing case collection")
+	}
+
+	result, err := benchmarks.Run(app.Dao(), c, 10, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Case != c.Name || result.Size != 10 || result.Iterations != 3 {
+		t.Fatalf("Unexpected result %v", result)
+	}
+
+	if result.BuildNsPerOp <= 0 || result.QueryNsPerOp <= 0 {
+		t.Fatalf("Expected positive result durations, got %v", result)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	baseline := []*benchmarks.Result{
+		{Case: "a", Size: 10, BuildNsPerOp: 100, QueryNsPerOp: 1000},
+		{Case: "b", Size: 10, BuildNsPerOp: 100, QueryNsPerOp: 1000},
+		{Case: "a", Size: 100, BuildNsPerOp: 100, QueryNsPerOp: 0},
+	}
+
+	current := []*benchmarks.Result{
+		{Case: "a", Size: 10, BuildNsPerOp: 119, QueryNsPerOp: 1300}, // query regression
+		{Case: "b", Size: 10, BuildNsPerOp: 200, QueryNsPerOp: 500},  // build regression
+		{Case: "a", Size: 100, BuildNsPerOp: 50, QueryNsPerOp: 5000}, // missing query baseline
+		{Case: "c", Size: 10, BuildNsPerOp: 999, QueryNsPerOp: 9999}, // missing baseline
+	}
+
+	regressions := benchmarks.Compare(baseline, current, 0.2)
+
+	result := make([]string, len(regressions))
+	for i, r := range regressions {
+		result[i] = fmt.Sprintf("%s:%d:%s", r.Case, r.Size, r.Metric)
+	}
+
+	expected := "a:10:queryNsPerOp,b:10:buildNsPerOp"
+	if str := strings.Join(result, ","); str != expected {
+		t.Fatalf("Expected regressions %q, got %q", expected, str)
+	}
+
+	if str := regressions[0].String(); !strings.Contains(str, "+30.0%") {
+		t.Fatalf("Expected the regression string to contain the difference percentage, got %q", str)
+	}
+}
+
+func BenchmarkResolver(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		app, _ := tests.NewTestApp()
+
+		if err := benchmarks.SeedFixtures(app.Dao(), size); err != nil {
+			app.Cleanup()
+			b.Fatal(err)
+		}
+
+		for _, c := range benchmarks.DefaultCases() {
+			b.Run(fmt.Sprintf("%s/%d", c.Name, size), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					query, err := benchmarks.BuildQuery(app.Dao(), c)
+					if err != nil {
+						b.Fatal(err)
+					}
+					query.Build()
+				}
+			})
+		}
+
+		app.Cleanup()
+	}
+}
+
+func BenchmarkSearchProvider(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		app, _ := tests.NewTestApp()
+
+		if err := benchmarks.SeedFixtures(app.Dao(), size); err != nil {
+			app.Cleanup()
+			b.Fatal(err)
+		}
+
+		for _, c := range benchmarks.DefaultCases() {
+			b.Run(fmt.Sprintf("%s/%d", c.Name, size), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := benchmarks.ExecQuery(app.Dao(), c); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+
+		app.Cleanup()
+	}
+}
@@ -0,0 +1,204 @@
+package benchmarks
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// Fixture collection names.
+const (
+	CollectionTags     = "bench_tags"
+	CollectionAuthors  = "bench_authors"
+	CollectionPosts    = "bench_posts"
+	CollectionComments = "bench_comments"
+)
+
+// fixtureTotalTags is the fixed number of the generated tags
+// (independent from the dataset size to have predictable matches).
+const fixtureTotalTags = 20
+
+// fixtureTagsPerPost is the number of tags of each generated post.
+const fixtureTagsPerPost = 3
+
+// SeedFixtures creates the benchmark fixture collections and populates
+// them with a deterministic dataset for the specified size:
+//   - bench_tags     - 20 records
+//   - bench_authors  - size/10 records (min 1)
+//   - bench_posts    - size records (single author and multiple tags relations)
+//   - bench_comments - size records (single post and author relations)
+//
+// The records are inserted directly in the db (without triggering
+// the model hooks) and it is expected to be called on an empty data db.
+func SeedFixtures(dao *daos.Dao, size int) error {
+	if size <= 0 {
+		return fmt.Errorf("Invalid fixtures size %d.", size)
+	}
+
+	return dao.RunInTransaction(func(txDao *daos.Dao) error {
+		tags, err := createFixtureCollection(txDao, CollectionTags, &schema.SchemaField{
+			Name: "name",
+			Type: schema.FieldTypeText,
+		})
+		if err != nil {
+			return err
+		}
+
+		authors, err := createFixtureCollection(txDao, CollectionAuthors, &schema.SchemaField{
+			Name: "name",
+			Type: schema.FieldTypeText,
+		})
+		if err != nil {
+			return err
+		}
+
+		posts, err := createFixtureCollection(txDao, CollectionPosts,
+			&schema.SchemaField{
+				Name: "title",
+				Type: schema.FieldTypeText,
+			},
+			&schema.SchemaField{
+				Name: "author",
+				Type: schema.FieldTypeRelation,
+				Options: &schema.RelationOptions{
+					CollectionId: authors.Id,
+					MaxSelect:    types.Pointer(1),
+				},
+			},
+			&schema.SchemaField{
+				Name: "tags",
+				Type: schema.FieldTypeRelation,
+				Options: &schema.RelationOptions{
+					CollectionId: tags.Id,
+				},
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		comments, err := createFixtureCollection(txDao, CollectionComments,
+			&schema.SchemaField{
+				Name: "message",
+				Type: schema.FieldTypeText,
+			},
+			&schema.SchemaField{
+				Name: "post",
+				Type: schema.FieldTypeRelation,
+				Options: &schema.RelationOptions{
+					CollectionId: posts.Id,
+					MaxSelect:    types.Pointer(1),
+				},
+			},
+			&schema.SchemaField{
+				Name: "author",
+				Type: schema.FieldTypeRelation,
+				Options: &schema.RelationOptions{
+					CollectionId: authors.Id,
+					MaxSelect:    types.Pointer(1),
+				},
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		totalAuthors := size / 10
+		if totalAuthors < 1 {
+			totalAuthors = 1
+		}
+
+		for i := 0; i < fixtureTotalTags; i++ {
+			err := insertFixtureRecord(txDao, tags, fixtureId('t', i), dbx.Params{
+				"name": fmt.Sprintf("tag%d", i),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < totalAuthors; i++ {
+			err := insertFixtureRecord(txDao, authors, fixtureId('a', i), dbx.Params{
+				"name": fmt.Sprintf("author%d", i),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < size; i++ {
+			postTags := make([]string, fixtureTagsPerPost)
+			for j := range postTags {
+				postTags[j] = fixtureId('t', (i+j)%fixtureTotalTags)
+			}
+
+			rawTags, err := json.Marshal(postTags)
+			if err != nil {
+				return err
+			}
+
+			err = insertFixtureRecord(txDao, posts, fixtureId('p', i), dbx.Params{
+				"title":  fmt.Sprintf("post%d", i),
+				"author": fixtureId('a', i%totalAuthors),
+				"tags":   string(rawTags),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < size; i++ {
+			err := insertFixtureRecord(txDao, comments, fixtureId('c', i), dbx.Params{
+				"message": fmt.Sprintf("lorem ipsum %d", i),
+				"post":    fixtureId('p', (i*7)%size),
+				"author":  fixtureId('a', (i*3)%totalAuthors),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func createFixtureCollection(dao *daos.Dao, name string, fields ...*schema.SchemaField) (*models.Collection, error) {
+	collection := &models.Collection{
+		Name:       name,
+		Type:       models.CollectionTypeBase,
+		Schema:     schema.NewSchema(fields...),
+		ListRule:   types.Pointer(""),
+		ViewRule:   types.Pointer(""),
+		CreateRule: types.Pointer(""),
+		UpdateRule: types.Pointer(""),
+		DeleteRule: types.Pointer(""),
+	}
+
+	if err := dao.SaveCollection(collection); err != nil {
+		return nil, err
+	}
+
+	return collection, nil
+}
+
+func insertFixtureRecord(dao *daos.Dao, collection *models.Collection, id string, data dbx.Params) error {
+	now := types.NowDateTime().String()
+
+	data[schema.FieldNameId] = id
+	data[schema.FieldNameCreated] = now
+	data[schema.FieldNameUpdated] = now
+
+	_, err := dao.DB().Insert(collection.Name, data).Execute()
+
+	return err
+}
+
+// fixtureId generates a deterministic 15 characters fixture record id.
+func fixtureId(prefix byte, i int) string {
+	return fmt.Sprintf("%c%0*d", prefix, models.DefaultIdLength-1, i)
+}
@@ -0,0 +1,227 @@
+// Package blueprints implements predefined collection bundles
+// (eg. "blog" with posts, categories and comments) that could be
+// instantiated in a single step to speed up the project bootstrapping.
+//
+// The default blueprints are loaded from the embedded data/*.json files
+// and custom ones could be added programmatically with [Register].
+package blueprints
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+)
+
+//go:embed data/*.json
+var dataDir embed.FS
+
+var nameRegex = regexp.MustCompile(`^\w+$`)
+
+var (
+	mux      sync.RWMutex
+	registry = map[string]*Blueprint{}
+)
+
+func init() {
+	entries, err := dataDir.ReadDir("data")
+	if err != nil {
+		panic(err)
+	}
+
+	for _, entry := range entries {
+		raw, err := dataDir.ReadFile(path.Join("data", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+
+		blueprint, err := Parse(raw)
+		if err != nil {
+			panic(fmt.Errorf("Invalid blueprint %s: %w", entry.Name(), err))
+		}
+
+		if err := Register(blueprint); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Blueprint defines a named bundle of collections.
+//
+// The relation fields of the blueprint collections reference their
+// related collection by name - either another collection from the same
+// blueprint (that must be listed before the referencing one) or an
+// already existing collection (eg. "users").
+type Blueprint struct {
+	Name        string               `json:"name"`
+	Title       string               `json:"title"`
+	Description string               `json:"description"`
+	Collections []*models.Collection `json:"collections"`
+}
+
+// Conflict defines a single blueprint collection that
+// collides with an existing collection or db table.
+type Conflict struct {
+	Collection string `json:"collection"`
+	Reason     string `json:"reason"`
+}
+
+// Conflict reasons.
+const (
+	ConflictCollectionExists = "collection_exists"
+	ConflictTableExists      = "table_exists"
+)
+
+// Parse parses and validates a single JSON encoded blueprint.
+func Parse(raw []byte) (*Blueprint, error) {
+	blueprint := &Blueprint{}
+
+	if err := json.Unmarshal(raw, blueprint); err != nil {
+		return nil, err
+	}
+
+	if !nameRegex.MatchString(blueprint.Name) {
+		return nil, fmt.Errorf("Invalid blueprint name %q.", blueprint.Name)
+	}
+
+	if len(blueprint.Collections) == 0 {
+		return nil, errors.New("The blueprint must have at least 1 collection.")
+	}
+
+	names := map[string]struct{}{}
+	for _, c := range blueprint.Collections {
+		key := strings.ToLower(c.Name)
+		if c.Name == "" {
+			return nil, errors.New("The blueprint collections must have a name.")
+		}
+		if _, ok := names[key]; ok {
+			return nil, fmt.Errorf("Duplicated blueprint collection %q.", c.Name)
+		}
+		names[key] = struct{}{}
+	}
+
+	return blueprint, nil
+}
+
+// Register registers the provided blueprint
+// (it replaces any existing blueprint with the same name).
+func Register(blueprint *Blueprint) error {
+	if blueprint == nil || !nameRegex.MatchString(blueprint.Name) {
+		return errors.New("Invalid or missing blueprint name.")
+	}
+
+	mux.Lock()
+	defer mux.Unlock()
+
+	registry[blueprint.Name] = blueprint
+
+	return nil
+}
+
+// Find returns the registered blueprint with the specified name.
+func Find(name string) (*Blueprint, error) {
+	mux.RLock()
+	defer mux.RUnlock()
+
+	blueprint, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("Missing blueprint %q.", name)
+	}
+
+	return blueprint, nil
+}
+
+// List returns all registered blueprints sorted by their name.
+func List() []*Blueprint {
+	mux.RLock()
+	defer mux.RUnlock()
+
+	result := make([]*Blueprint, 0, len(registry))
+	for _, blueprint := range registry {
+		result = append(result, blueprint)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// Conflicts returns the blueprint collections that collide
+// with the existing collections or db tables.
+func (b *Blueprint) Conflicts(dao *daos.Dao) []Conflict {
+	result := []Conflict{}
+
+	for _, c := range b.Collections {
+		if !dao.IsCollectionNameUnique(c.Name) {
+			result = append(result, Conflict{Collection: c.Name, Reason: ConflictCollectionExists})
+		} else if dao.HasTable(c.Name) {
+			result = append(result, Conflict{Collection: c.Name, Reason: ConflictTableExists})
+		}
+	}
+
+	return result
+}
+
+// Build returns new (not persisted) copies of the blueprint
+// collections with generated ids and with their relation fields
+// pointing to the ids of the related collections.
+func (b *Blueprint) Build(dao *daos.Dao) ([]*models.Collection, error) {
+	// deep copy to prevent modifying the registered blueprint
+	raw, err := json.Marshal(b.Collections)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*models.Collection{}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, err
+	}
+
+	ids := map[string]string{}
+
+	for _, c := range result {
+		c.MarkAsNew()
+		c.RefreshId()
+
+		if c.Type == "" {
+			c.Type = models.CollectionTypeBase
+		}
+
+		for _, field := range c.Schema.Fields() {
+			if field.Type != schema.FieldTypeRelation {
+				continue
+			}
+
+			options, _ := field.Options.(*schema.RelationOptions)
+			if options == nil {
+				continue
+			}
+
+			if id, ok := ids[strings.ToLower(options.CollectionId)]; ok {
+				options.CollectionId = id
+				continue
+			}
+
+			related, err := dao.FindCollectionByNameOrId(options.CollectionId)
+			if err != nil {
+				return nil, fmt.Errorf("Missing %s.%s relation collection %q.", c.Name, field.Name, options.CollectionId)
+			}
+			options.CollectionId = related.Id
+		}
+
+		ids[strings.ToLower(c.Name)] = c.Id
+	}
+
+	return result, nil
+}
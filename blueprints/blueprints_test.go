@@ -0,0 +1,181 @@
+package blueprints_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/blueprints"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestParse(t *testing.T) {
+	scenarios := []struct {
+		name        string
+		raw         string
+		expectError bool
+	}{
+		{"invalid json", `{`, true},
+		{"invalid name", `{"name":"a b","collections":[{"name":"test"}]}`, true},
+		{"no collections", `{"name":"test","collections":[]}`, true},
+		{"missing collection name", `{"name":"test","collections":[{"name":""}]}`, true},
+		{"duplicated collection names", `{"name":"test","collections":[{"name":"a"},{"name":"A"}]}`, true},
+		{"valid", `{"name":"test","collections":[{"name":"a"},{"name":"b"}]}`, false},
+	}
+
+	for _, s := range scenarios {
+		blueprint, err := blueprints.Parse([]byte(s.raw))
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if !hasErr && len(blueprint.Collections) != 2 {
+			t.Errorf("[%s] Expected 2 collections, got %d", s.name, len(blueprint.Collections))
+		}
+	}
+}
+
+func TestRegisterAndFind(t *testing.T) {
+	if _, err := blueprints.Find("missing"); err == nil {
+		t.Fatal("Expected error for missing blueprint")
+	}
+
+	if err := blueprints.Register(nil); err == nil {
+		t.Fatal("Expected error for nil blueprint")
+	}
+
+	if err := blueprints.Register(&blueprints.Blueprint{Name: "a-b"}); err == nil {
+		t.Fatal("Expected error for invalid blueprint name")
+	}
+
+	custom, err := blueprints.Parse([]byte(`{"name":"zz_custom","collections":[{"name":"custom1"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := blueprints.Register(custom); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := blueprints.Find("zz_custom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found != custom {
+		t.Fatalf("Expected the registered blueprint, got %v", found)
+	}
+
+	list := blueprints.List()
+	if len(list) < 2 || list[0].Name != "blog" || list[len(list)-1].Name != "zz_custom" {
+		t.Fatalf("Expected the blueprints to be sorted by name, got %v", list)
+	}
+}
+
+func TestBlueprintConflicts(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	blueprint, err := blueprints.Parse([]byte(`{
+		"name": "test",
+		"collections": [
+			{"name": "new1"},
+			{"name": "DEMO1"},
+			{"name": "_admins"}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conflicts := blueprint.Conflicts(app.Dao())
+
+	expected := []blueprints.Conflict{
+		{Collection: "DEMO1", Reason: blueprints.ConflictCollectionExists},
+		{Collection: "_admins", Reason: blueprints.ConflictTableExists},
+	}
+
+	if len(conflicts) != len(expected) {
+		t.Fatalf("Expected %v conflicts, got %v", expected, conflicts)
+	}
+
+	for i, c := range expected {
+		if conflicts[i] != c {
+			t.Errorf("Expected conflict %v, got %v", c, conflicts[i])
+		}
+	}
+}
+
+func TestBlueprintBuild(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	blueprint, err := blueprints.Find("blog")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collections, err := blueprint.Build(app.Dao())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(collections) != len(blueprint.Collections) {
+		t.Fatalf("Expected %d collections, got %d", len(blueprint.Collections), len(collections))
+	}
+
+	ids := map[string]string{}
+	for _, c := range collections {
+		if !c.IsNew() || c.Id == "" {
+			t.Fatalf("Expected %s to be new with generated id", c.Name)
+		}
+		ids[c.Name] = c.Id
+	}
+
+	users, err := app.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedRelations := map[string]string{
+		"posts.category": ids["categories"],
+		"posts.author":   users.Id,
+		"comments.post":  ids["posts"],
+	}
+
+	for _, c := range collections {
+		for _, f := range c.Schema.Fields() {
+			expectedId, ok := expectedRelations[c.Name+"."+f.Name]
+			if !ok {
+				continue
+			}
+
+			options := f.Options.(*schema.RelationOptions)
+			if options.CollectionId != expectedId {
+				t.Errorf("Expected %s.%s to reference %q, got %q", c.Name, f.Name, expectedId, options.CollectionId)
+			}
+		}
+	}
+
+	// the registered blueprint must remain unchanged
+	posts := blueprint.Collections[1]
+	if posts.Id != "" || posts.Schema.GetFieldByName("category").Options.(*schema.RelationOptions).CollectionId != "categories" {
+		t.Fatal("Expected the registered blueprint to be unchanged")
+	}
+
+	// missing relation collection
+	invalid, err := blueprints.Parse([]byte(`{
+		"name": "invalid",
+		"collections": [
+			{"name": "a", "schema": [{"name": "rel", "type": "relation", "options": {"collectionId": "missing"}}]}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := invalid.Build(app.Dao()); err == nil {
+		t.Fatal("Expected error for missing relation collection")
+	}
+}
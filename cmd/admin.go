@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/spf13/cobra"
+)
+
+// NewAdminCommand creates and returns new command for managing
+// the admin accounts (create, delete, list) directly from the data dir.
+func NewAdminCommand(app core.App) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "admin",
+		Short: "Manages the admin accounts",
+		PersistentPreRunE: func(command *cobra.Command, args []string) error {
+			return prepareDataDir(app)
+		},
+	}
+
+	command.AddCommand(adminCreateCommand(app))
+	command.AddCommand(adminDeleteCommand(app))
+	command.AddCommand(adminListCommand(app))
+
+	return command
+}
+
+func adminCreateCommand(app core.App) *cobra.Command {
+	return &cobra.Command{
+		Use:     "create",
+		Example: "admin create test@example.com 1234567890",
+		Short:   "Creates a new admin account",
+		// prevents printing the error log twice
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("Missing email and password arguments.")
+			}
+
+			admin := &models.Admin{}
+
+			form := forms.NewAdminUpsert(app, admin)
+			form.Email = args[0]
+			form.Password = args[1]
+			form.PasswordConfirm = args[1]
+
+			if err := form.Submit(); err != nil {
+				return fmt.Errorf("Failed to create new admin account: %v", err)
+			}
+
+			color.Green("Successfully created new admin %s!", admin.Email)
+
+			return nil
+		},
+	}
+}
+
+func adminDeleteCommand(app core.App) *cobra.Command {
+	return &cobra.Command{
+		Use:     "delete",
+		Example: "admin delete test@example.com",
+		Short:   "Deletes an existing admin account",
+		// prevents printing the error log twice
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("Missing admin email argument.")
+			}
+
+			admin, err := app.Dao().FindAdminByEmail(args[0])
+			if err != nil {
+				return fmt.Errorf("Missing admin with email %q.", args[0])
+			}
+
+			if err := app.Dao().DeleteAdmin(admin); err != nil {
+				return fmt.Errorf("Failed to delete admin %s: %v", admin.Email, err)
+			}
+
+			color.Green("Successfully deleted admin %s!", admin.Email)
+
+			return nil
+		},
+	}
+}
+
+func adminListCommand(app core.App) *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Example: "admin list",
+		Short:   "Lists all admin accounts",
+		// prevents printing the error log twice
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(command *cobra.Command, args []string) error {
+			admins := []*models.Admin{}
+			if err := app.Dao().AdminQuery().OrderBy("created ASC").All(&admins); err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(command.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tEMAIL\tCREATED")
+			for _, admin := range admins {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", admin.Id, admin.Email, admin.Created.String())
+			}
+
+			return w.Flush()
+		},
+	}
+}
@@ -0,0 +1,118 @@
+package cmd_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/cmd"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestAdminCreateCommand(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name        string
+		args        []string
+		expectError bool
+	}{
+		{"missing args", []string{"create"}, true},
+		{"missing password", []string{"create", "new@example.com"}, true},
+		{"invalid email", []string{"create", "invalid", "1234567890"}, true},
+		{"short password", []string{"create", "new@example.com", "123"}, true},
+		{"existing email", []string{"create", "test@example.com", "1234567890"}, true},
+		{"valid", []string{"create", "new@example.com", "1234567890"}, false},
+	}
+
+	for _, s := range scenarios {
+		command := cmd.NewAdminCommand(app)
+		command.SetArgs(s.args)
+
+		err := command.Execute()
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		admin, err := app.Dao().FindAdminByEmail(s.args[1])
+		if err != nil {
+			t.Errorf("[%s] Failed to fetch the created admin: %v", s.name, err)
+			continue
+		}
+
+		if !admin.ValidatePassword(s.args[2]) {
+			t.Errorf("[%s] Expected the admin password to match", s.name)
+		}
+	}
+}
+
+func TestAdminDeleteCommand(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name        string
+		args        []string
+		expectError bool
+	}{
+		{"missing email", []string{"delete"}, true},
+		{"missing admin", []string{"delete", "missing@example.com"}, true},
+		{"existing admin", []string{"delete", "test2@example.com"}, false},
+	}
+
+	for _, s := range scenarios {
+		command := cmd.NewAdminCommand(app)
+		command.SetArgs(s.args)
+
+		err := command.Execute()
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		if admin, _ := app.Dao().FindAdminByEmail(s.args[1]); admin != nil {
+			t.Errorf("[%s] Expected the admin to be deleted", s.name)
+		}
+	}
+}
+
+func TestAdminListCommand(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	out := &bytes.Buffer{}
+
+	command := cmd.NewAdminCommand(app)
+	command.SetOut(out)
+	command.SetArgs([]string{"list"})
+
+	if err := command.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"ID", "EMAIL", "CREATED",
+		"sywbhecnh46rhm0", "test@example.com",
+		"sbmbsdb40jyxf7h", "test2@example.com",
+		"9q2trqumvlyr3bd", "test3@example.com",
+	}
+	for _, v := range expected {
+		if !strings.Contains(out.String(), v) {
+			t.Errorf("Expected %q in\n%s", v, out.String())
+		}
+	}
+}
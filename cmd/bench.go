@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/pocketbase/pocketbase/benchmarks"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cobra"
+)
+
+// NewBenchCommand creates and returns new command for running
+// the app performance benchmarks.
+func NewBenchCommand(app core.App) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "bench",
+		Short: "Runs performance benchmarks against generated fixture datasets",
+	}
+
+	command.AddCommand(benchResolverCommand(app))
+
+	return command
+}
+
+func benchResolverCommand(app core.App) *cobra.Command {
+	var sizes []int
+	var iterations int
+	var out string
+	var baseline string
+	var threshold float64
+
+	command := &cobra.Command{
+		Use:     "resolver",
+		Example: "bench resolver --sizes=100,1000 --baseline=bench.json",
+		Short:   "Benchmarks the record rules resolver and search provider",
+		Long: `
+Benchmarks the record rules resolver and search provider with a set of
+representative rules (deep relations, @collection joins, multi-match, etc.).
+
+Each dataset size is generated in a separate temporary data dir
+(the app data dir is not modified).
+
+If --baseline is set, the command fails when any of the results
+is slower than its baseline with more than the --threshold ratio.
+`,
+		// prevents printing the error log twice
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if len(sizes) == 0 {
+				return errors.New("Missing dataset sizes.")
+			}
+
+			var baselineResults []*benchmarks.Result
+			if baseline != "" {
+				raw, err := os.ReadFile(baseline)
+				if err != nil {
+					return err
+				}
+
+				if err := json.Unmarshal(raw, &baselineResults); err != nil {
+					return fmt.Errorf("Failed to parse the baseline file: %w", err)
+				}
+			}
+
+			results := []*benchmarks.Result{}
+
+			for _, size := range sizes {
+				sizeResults, err := runResolverBenchmarks(app, size, iterations)
+				if err != nil {
+					return err
+				}
+
+				results = append(results, sizeResults...)
+			}
+
+			printBenchResults(command, results)
+
+			if out != "" {
+				encoded, err := json.MarshalIndent(results, "", "  ")
+				if err != nil {
+					return err
+				}
+
+				if err := os.WriteFile(out, encoded, 0644); err != nil {
+					return err
+				}
+			}
+
+			if baseline == "" {
+				return nil
+			}
+
+			regressions := benchmarks.Compare(baselineResults, results, threshold)
+			if len(regressions) == 0 {
+				color.Green("No regressions compared to %s.", baseline)
+				return nil
+			}
+
+			lines := make([]string, len(regressions))
+			for i, r := range regressions {
+				lines[i] = r.String()
+			}
+
+			return fmt.Errorf("Detected %d regression(s):\n%s", len(regressions), strings.Join(lines, "\n"))
+		},
+	}
+
+	command.Flags().IntSliceVar(&sizes, "sizes", []int{100, 1000}, "the fixture dataset sizes")
+	command.Flags().IntVar(&iterations, "iterations", 50, "the number of iterations of each case")
+	command.Flags().StringVar(&out, "out", "", "the JSON file to save the results (eg. to be used later as baseline)")
+	command.Flags().StringVar(&baseline, "baseline", "", "the JSON file with the baseline results to compare with")
+	command.Flags().Float64Var(&threshold, "threshold", 0.2, "the allowed slowdown ratio compared to the baseline")
+
+	return command
+}
+
+// runResolverBenchmarks runs the default benchmark cases against
+// a new temporary app instance seeded with the specified dataset size.
+func runResolverBenchmarks(app core.App, size int, iterations int) ([]*benchmarks.Result, error) {
+	tempDir, err := os.MkdirTemp("", "pb_bench_")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	benchApp := core.NewBaseApp(&core.BaseAppConfig{
+		DataDir:       filepath.Join(tempDir, "pb_data"),
+		EncryptionEnv: app.EncryptionEnv(),
+	})
+
+	if err := benchApp.Bootstrap(); err != nil {
+		return nil, err
+	}
+	defer benchApp.ResetBootstrapState()
+
+	if err := runMigrations(benchApp); err != nil {
+		return nil, err
+	}
+
+	if err := benchmarks.SeedFixtures(benchApp.Dao(), size); err != nil {
+		return nil, err
+	}
+
+	results := []*benchmarks.Result{}
+
+	for _, c := range benchmarks.DefaultCases() {
+		result, err := benchmarks.Run(benchApp.Dao(), c, size, iterations)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func printBenchResults(command *cobra.Command, results []*benchmarks.Result) {
+	w := tabwriter.NewWriter(command.OutOrStdout(), 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "CASE\tSIZE\tBUILD/OP\tQUERY/OP")
+
+	for _, r := range results {
+		fmt.Fprintf(
+			w,
+			"%s\t%d\t%s\t%s\n",
+			r.Case,
+			r.Size,
+			time.Duration(r.BuildNsPerOp),
+			time.Duration(r.QueryNsPerOp),
+		)
+	}
+
+	w.Flush()
+}
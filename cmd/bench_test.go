@@ -0,0 +1,88 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/benchmarks"
+	"github.com/pocketbase/pocketbase/cmd"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestBenchResolverCommand(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	tempDir := t.TempDir()
+
+	outFile := filepath.Join(tempDir, "out.json")
+	fastBaselineFile := filepath.Join(tempDir, "fast.json")
+	slowBaselineFile := filepath.Join(tempDir, "slow.json")
+
+	fastBaseline := []*benchmarks.Result{}
+	slowBaseline := []*benchmarks.Result{}
+	for _, c := range benchmarks.DefaultCases() {
+		fastBaseline = append(fastBaseline, &benchmarks.Result{Case: c.Name, Size: 10, BuildNsPerOp: 1, QueryNsPerOp: 1})
+		slowBaseline = append(slowBaseline, &benchmarks.Result{Case: c.Name, Size: 10, BuildNsPerOp: 1e12, QueryNsPerOp: 1e12})
+	}
+	for file, data := range map[string]any{fastBaselineFile: fastBaseline, slowBaselineFile: slowBaseline} {
+		raw, _ := json.Marshal(data)
+		if err := os.WriteFile(file, raw, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scenarios := []struct {
+		name        string
+		args        []string
+		expectError bool
+		expected    []string
+	}{
+		{"missing sizes", []string{"resolver", "--sizes="}, true, nil},
+		{"missing baseline file", []string{"resolver", "--sizes=10", "--iterations=1", "--baseline=" + filepath.Join(tempDir, "missing.json")}, true, nil},
+		{"without baseline", []string{"resolver", "--sizes=10", "--iterations=1", "--out=" + outFile}, false, []string{"CASE", "deep_relation", "collection_join"}},
+		{"with regressions", []string{"resolver", "--sizes=10", "--iterations=1", "--baseline=" + fastBaselineFile}, true, nil},
+		{"without regressions", []string{"resolver", "--sizes=10", "--iterations=1", "--baseline=" + slowBaselineFile}, false, []string{"multi_match"}},
+	}
+
+	for _, s := range scenarios {
+		out := &bytes.Buffer{}
+
+		command := cmd.NewBenchCommand(app)
+		command.SetOut(out)
+		command.SetArgs(s.args)
+
+		err := command.Execute()
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		for _, v := range s.expected {
+			if !strings.Contains(out.String(), v) {
+				t.Errorf("[%s] Expected %q in\n%s", s.name, v, out.String())
+			}
+		}
+	}
+
+	// check the saved results
+	raw, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := []*benchmarks.Result{}
+	if err := json.Unmarshal(raw, &results); err != nil {
+		t.Fatal(err)
+	}
+
+	if total := len(benchmarks.DefaultCases()); len(results) != total {
+		t.Fatalf("Expected %d saved results, got %d", total, len(results))
+	}
+}
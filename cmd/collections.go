@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/spf13/cobra"
+)
+
+// NewCollectionsCommand creates and returns new command for listing,
+// exporting and importing the collections directly from the data dir.
+func NewCollectionsCommand(app core.App) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "collections",
+		Short: "Manages the collections schema",
+		PersistentPreRunE: func(command *cobra.Command, args []string) error {
+			return prepareDataDir(app)
+		},
+	}
+
+	command.AddCommand(collectionsListCommand(app))
+	command.AddCommand(collectionsExportCommand(app))
+	command.AddCommand(collectionsImportCommand(app))
+
+	return command
+}
+
+func collectionsListCommand(app core.App) *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Example: "collections list",
+		Short:   "Lists all collections",
+		// prevents printing the error log twice
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(command *cobra.Command, args []string) error {
+			collections, err := findAllCollections(app)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(command.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tNAME\tTYPE\tSYSTEM")
+			for _, c := range collections {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%v\n", c.Id, c.Name, c.Type, c.System)
+			}
+
+			return w.Flush()
+		},
+	}
+}
+
+func collectionsExportCommand(app core.App) *cobra.Command {
+	return &cobra.Command{
+		Use:     "export",
+		Example: "collections export pb_schema.json",
+		Short:   "Exports all collections as JSON to the specified file (default to stdout)",
+		// prevents printing the error log twice
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return errors.New("Too many arguments (expects only an optional file path).")
+			}
+
+			collections, err := findAllCollections(app)
+			if err != nil {
+				return err
+			}
+
+			encoded, err := json.MarshalIndent(collections, "", "  ")
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 0 {
+				fmt.Fprintln(command.OutOrStdout(), string(encoded))
+				return nil
+			}
+
+			if err := os.WriteFile(args[0], encoded, 0644); err != nil {
+				return err
+			}
+
+			color.Green("Successfully exported %d collection(s) to %s!", len(collections), args[0])
+
+			return nil
+		},
+	}
+}
+
+func collectionsImportCommand(app core.App) *cobra.Command {
+	var deleteMissing bool
+
+	command := &cobra.Command{
+		Use:     "import",
+		Example: "collections import pb_schema.json",
+		Short:   "Imports the collections from the specified JSON file",
+		// prevents printing the error log twice
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("Missing file path argument.")
+			}
+
+			raw, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+
+			form := forms.NewCollectionsImport(app)
+			form.DeleteMissing = deleteMissing
+			if err := json.Unmarshal(raw, &form.Collections); err != nil {
+				return fmt.Errorf("Failed to load the collections from %s: %v", args[0], err)
+			}
+
+			if err := form.Submit(); err != nil {
+				return fmt.Errorf("Failed to import the collections: %v", err)
+			}
+
+			color.Green("Successfully imported %d collection(s)!", len(form.Collections))
+
+			return nil
+		},
+	}
+
+	command.Flags().BoolVar(&deleteMissing, "delete-missing", false, "delete the existing collections that are not in the imported file")
+
+	return command
+}
+
+func findAllCollections(app core.App) ([]*models.Collection, error) {
+	collections := []*models.Collection{}
+
+	err := app.Dao().CollectionQuery().OrderBy("created ASC").All(&collections)
+
+	return collections, err
+}
@@ -0,0 +1,101 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/cmd"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestCollectionsListCommand(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	out := &bytes.Buffer{}
+
+	command := cmd.NewCollectionsCommand(app)
+	command.SetOut(out)
+	command.SetArgs([]string{"list"})
+
+	if err := command.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"ID", "NAME", "TYPE", "_pb_users_auth_", "users", "demo1", "demo2"}
+	for _, v := range expected {
+		if !strings.Contains(out.String(), v) {
+			t.Errorf("Expected %q in\n%s", v, out.String())
+		}
+	}
+}
+
+func TestCollectionsExportAndImportCommands(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	// export to stdout
+	out := &bytes.Buffer{}
+	exportCmd := cmd.NewCollectionsCommand(app)
+	exportCmd.SetOut(out)
+	exportCmd.SetArgs([]string{"export"})
+	if err := exportCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	collections := []*models.Collection{}
+	if err := json.Unmarshal(out.Bytes(), &collections); err != nil {
+		t.Fatalf("Failed to decode the exported collections: %v", err)
+	}
+	total := len(collections)
+	if total == 0 {
+		t.Fatal("Expected at least one exported collection")
+	}
+
+	// export to file
+	file := filepath.Join(t.TempDir(), "schema.json")
+	exportCmd = cmd.NewCollectionsCommand(app)
+	exportCmd.SetArgs([]string{"export", file})
+	if err := exportCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	// modify the exported file
+	for _, c := range collections {
+		if c.Name == "demo2" {
+			c.Name = "demo2_renamed"
+		}
+	}
+	raw, _ := json.Marshal(collections)
+	if err := os.WriteFile(file, raw, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// missing file
+	importCmd := cmd.NewCollectionsCommand(app)
+	importCmd.SetArgs([]string{"import", filepath.Join(t.TempDir(), "missing.json")})
+	if err := importCmd.Execute(); err == nil {
+		t.Fatal("Expected error for missing import file")
+	}
+
+	importCmd = cmd.NewCollectionsCommand(app)
+	importCmd.SetArgs([]string{"import", file})
+	if err := importCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindCollectionByNameOrId("demo2_renamed"); err != nil {
+		t.Fatalf("Expected the collection to be renamed: %v", err)
+	}
+
+	var count int
+	app.Dao().CollectionQuery().Select("count(*)").Row(&count)
+	if count != total {
+		t.Fatalf("Expected %d collections, got %d", total, count)
+	}
+}
@@ -0,0 +1,282 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/resolvers"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/spf13/cobra"
+)
+
+// NewRecordsCommand creates and returns new command for listing and
+// managing the collection records directly from the data dir.
+//
+// All record commands operate with full (aka. admin) access and
+// print their results as JSON to allow easier scripting.
+func NewRecordsCommand(app core.App) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "records",
+		Short: "Manages the collection records",
+		PersistentPreRunE: func(command *cobra.Command, args []string) error {
+			return prepareDataDir(app)
+		},
+	}
+
+	command.AddCommand(recordsListCommand(app))
+	command.AddCommand(recordsGetCommand(app))
+	command.AddCommand(recordsCreateCommand(app))
+	command.AddCommand(recordsUpdateCommand(app))
+	command.AddCommand(recordsDeleteCommand(app))
+
+	return command
+}
+
+func recordsListCommand(app core.App) *cobra.Command {
+	var filter string
+	var sort string
+	var page int
+	var perPage int
+
+	command := &cobra.Command{
+		Use:     "list",
+		Example: `records list posts --filter="created > '2023-01-01'" --sort="-created"`,
+		Short:   "Prints a paginated JSON list with the collection records",
+		// prevents printing the error log twice
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("Missing collection name or id argument.")
+			}
+
+			collection, err := app.Dao().FindCollectionByNameOrId(args[0])
+			if err != nil {
+				return fmt.Errorf("Missing collection %q.", args[0])
+			}
+
+			resolver := resolvers.NewRecordFieldResolver(app.Dao(), collection, &models.RequestData{}, true)
+
+			params := url.Values{}
+			params.Set(search.FilterQueryParam, filter)
+			params.Set(search.SortQueryParam, sort)
+			params.Set(search.PageQueryParam, strconv.Itoa(page))
+			params.Set(search.PerPageQueryParam, strconv.Itoa(perPage))
+
+			rows := []dbx.NullStringMap{}
+			result, err := search.NewProvider(resolver).
+				Query(app.Dao().RecordQuery(collection)).
+				ParseAndExec(params.Encode(), &rows)
+			if err != nil {
+				return fmt.Errorf("Invalid filter or sort parameters: %v", err)
+			}
+
+			if err := app.Dao().DecryptRecordRows(collection, rows...); err != nil {
+				return err
+			}
+
+			records := models.NewRecordsFromNullStringMaps(collection, rows)
+			for _, record := range records {
+				record.IgnoreEmailVisibility(true)
+			}
+			result.Items = records
+
+			return writeJson(command.OutOrStdout(), result)
+		},
+	}
+
+	command.Flags().StringVar(&filter, "filter", "", "the records filter expression (same as the ?filter query param)")
+	command.Flags().StringVar(&sort, "sort", "", "the records sort expression (same as the ?sort query param)")
+	command.Flags().IntVar(&page, "page", 1, "the page of the records to list")
+	command.Flags().IntVar(&perPage, "perPage", search.DefaultPerPage, "the max number of the listed records")
+
+	return command
+}
+
+func recordsGetCommand(app core.App) *cobra.Command {
+	return &cobra.Command{
+		Use:     "get",
+		Example: "records get posts RECORD_ID",
+		Short:   "Prints a single collection record as JSON",
+		// prevents printing the error log twice
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("Missing collection and record id arguments.")
+			}
+
+			record, err := findRecord(app, args[0], args[1])
+			if err != nil {
+				return err
+			}
+
+			return writeJson(command.OutOrStdout(), record)
+		},
+	}
+}
+
+func recordsCreateCommand(app core.App) *cobra.Command {
+	return &cobra.Command{
+		Use:     "create",
+		Example: `records create posts '{"title":"Lorem ipsum"}'`,
+		Short:   "Creates a new collection record from the JSON data argument (or stdin)",
+		// prevents printing the error log twice
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if len(args) < 1 || len(args) > 2 {
+				return errors.New("Missing collection argument (expects also an optional JSON data argument).")
+			}
+
+			collection, err := app.Dao().FindCollectionByNameOrId(args[0])
+			if err != nil {
+				return fmt.Errorf("Missing collection %q.", args[0])
+			}
+
+			data, err := readRecordData(command.InOrStdin(), args[1:])
+			if err != nil {
+				return err
+			}
+
+			record := models.NewRecord(collection)
+			if err := submitRecordData(app, record, data); err != nil {
+				return fmt.Errorf("Failed to create record: %v", err)
+			}
+
+			return writeJson(command.OutOrStdout(), record)
+		},
+	}
+}
+
+func recordsUpdateCommand(app core.App) *cobra.Command {
+	return &cobra.Command{
+		Use:     "update",
+		Example: `records update posts RECORD_ID '{"title":"Lorem ipsum"}'`,
+		Short:   "Updates a single collection record with the JSON data argument (or stdin)",
+		// prevents printing the error log twice
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if len(args) < 2 || len(args) > 3 {
+				return errors.New("Missing collection and record id arguments (expects also an optional JSON data argument).")
+			}
+
+			record, err := findRecord(app, args[0], args[1])
+			if err != nil {
+				return err
+			}
+
+			data, err := readRecordData(command.InOrStdin(), args[2:])
+			if err != nil {
+				return err
+			}
+
+			if err := submitRecordData(app, record, data); err != nil {
+				return fmt.Errorf("Failed to update record: %v", err)
+			}
+
+			return writeJson(command.OutOrStdout(), record)
+		},
+	}
+}
+
+func recordsDeleteCommand(app core.App) *cobra.Command {
+	return &cobra.Command{
+		Use:     "delete",
+		Example: "records delete posts RECORD_ID",
+		Short:   "Deletes (or only marks as deleted if soft delete is enabled) a single collection record",
+		// prevents printing the error log twice
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("Missing collection and record id arguments.")
+			}
+
+			record, err := findRecord(app, args[0], args[1])
+			if err != nil {
+				return err
+			}
+
+			if record.Collection().SoftDeleteOptions().Enabled {
+				err = app.Dao().SoftDeleteRecord(record)
+			} else {
+				err = app.Dao().DeleteRecord(record)
+			}
+			if err != nil {
+				return fmt.Errorf("Failed to delete record: %v", err)
+			}
+
+			color.Green("Successfully deleted record %q!", record.Id)
+
+			return nil
+		},
+	}
+}
+
+func findRecord(app core.App, collectionNameOrId string, recordId string) (*models.Record, error) {
+	record, err := app.Dao().FindRecordById(collectionNameOrId, recordId)
+	if err != nil {
+		return nil, fmt.Errorf("Missing record %q in collection %q.", recordId, collectionNameOrId)
+	}
+
+	record.IgnoreEmailVisibility(true)
+
+	return record, nil
+}
+
+// readRecordData decodes the record JSON data from the
+// first argument (if any) or from the provided reader.
+func readRecordData(stdin io.Reader, args []string) (map[string]any, error) {
+	var raw []byte
+
+	if len(args) > 0 && args[0] != "-" {
+		raw = []byte(args[0])
+	} else {
+		var err error
+		if raw, err = io.ReadAll(stdin); err != nil {
+			return nil, err
+		}
+	}
+
+	data := map[string]any{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("Invalid JSON record data: %v", err)
+	}
+
+	return data, nil
+}
+
+// submitRecordData loads the provided data into the record
+// upsert form and submits it with full manage access.
+func submitRecordData(app core.App, record *models.Record, data map[string]any) error {
+	form := forms.NewRecordUpsert(app, record)
+	form.SetFullManageAccess(true)
+
+	if err := form.LoadData(data); err != nil {
+		return err
+	}
+
+	return form.Submit()
+}
+
+func writeJson(w io.Writer, value any) error {
+	encoded, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, string(encoded))
+
+	return err
+}
@@ -0,0 +1,183 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/cmd"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestRecordsListCommand(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name        string
+		args        []string
+		expectError bool
+		expectedIds []string
+	}{
+		{"missing collection arg", []string{"list"}, true, nil},
+		{"missing collection", []string{"list", "missing"}, true, nil},
+		{"invalid filter", []string{"list", "demo2", "--filter", "title ~"}, true, nil},
+		{
+			"all",
+			[]string{"list", "demo2", "--sort", "title"},
+			false,
+			[]string{"llvuca81nly1qls", "achvryl401bhse3", "0yxhwia2amd8gec"},
+		},
+		{
+			"with filter, sort and pagination",
+			[]string{"list", "demo2", "--filter", "title != 'test1'", "--sort", "-title", "--perPage", "1", "--page", "2"},
+			false,
+			[]string{"achvryl401bhse3"},
+		},
+	}
+
+	for _, s := range scenarios {
+		out := &bytes.Buffer{}
+
+		command := cmd.NewRecordsCommand(app)
+		command.SetOut(out)
+		command.SetArgs(s.args)
+
+		err := command.Execute()
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		result := struct {
+			Items []struct {
+				Id string `json:"id"`
+			} `json:"items"`
+		}{}
+		if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+			t.Errorf("[%s] Failed to decode the output: %v", s.name, err)
+			continue
+		}
+
+		ids := []string{}
+		for _, item := range result.Items {
+			ids = append(ids, item.Id)
+		}
+		if strings.Join(ids, ",") != strings.Join(s.expectedIds, ",") {
+			t.Errorf("[%s] Expected ids %v, got %v", s.name, s.expectedIds, ids)
+		}
+	}
+}
+
+func TestRecordsGetCommand(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name        string
+		args        []string
+		expectError bool
+		expected    []string
+	}{
+		{"missing args", []string{"get", "demo2"}, true, nil},
+		{"missing record", []string{"get", "demo2", "missing"}, true, nil},
+		{"existing record", []string{"get", "demo2", "llvuca81nly1qls"}, false, []string{`"id": "llvuca81nly1qls"`, `"title": "test1"`}},
+		{"auth record with hidden email", []string{"get", "users", "4q1xlclmfloku33"}, false, []string{`"email": "test@example.com"`}},
+	}
+
+	for _, s := range scenarios {
+		out := &bytes.Buffer{}
+
+		command := cmd.NewRecordsCommand(app)
+		command.SetOut(out)
+		command.SetArgs(s.args)
+
+		err := command.Execute()
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		for _, v := range s.expected {
+			if !strings.Contains(out.String(), v) {
+				t.Errorf("[%s] Expected %q in\n%s", s.name, v, out.String())
+			}
+		}
+	}
+}
+
+func TestRecordsCreateUpdateAndDeleteCommands(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	execute := func(stdin string, args ...string) (string, error) {
+		out := &bytes.Buffer{}
+
+		command := cmd.NewRecordsCommand(app)
+		command.SetOut(out)
+		command.SetIn(strings.NewReader(stdin))
+		command.SetArgs(args)
+
+		err := command.Execute()
+
+		return out.String(), err
+	}
+
+	// create
+	if _, err := execute("", "create", "demo2", `{"title":"a"}`); err == nil {
+		t.Fatal("Expected validation error")
+	}
+	if _, err := execute("", "create", "demo2", `invalid`); err == nil {
+		t.Fatal("Expected invalid JSON error")
+	}
+	if _, err := execute("", "create", "demo2", `{"title":"cli1"}`); err != nil {
+		t.Fatal(err)
+	}
+	out, err := execute(`{"title":"cli2","active":true}`, "create", "demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := map[string]any{}
+	if err := json.Unmarshal([]byte(out), &created); err != nil {
+		t.Fatal(err)
+	}
+	id, _ := created["id"].(string)
+	if id == "" || created["title"] != "cli2" || created["active"] != true {
+		t.Fatalf("Unexpected created record %v", created)
+	}
+
+	// update
+	if _, err := execute("", "update", "demo2", "missing", `{"title":"cli3"}`); err == nil {
+		t.Fatal("Expected missing record error")
+	}
+	if _, err := execute(`{"title":"cli3"}`, "update", "demo2", id, "-"); err != nil {
+		t.Fatal(err)
+	}
+	record, err := app.Dao().FindRecordById("demo2", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.GetString("title") != "cli3" || !record.GetBool("active") {
+		t.Fatalf("Unexpected updated record %v", record)
+	}
+
+	// delete
+	if _, err := execute("", "delete", "demo2", "missing"); err == nil {
+		t.Fatal("Expected missing record error")
+	}
+	if _, err := execute("", "delete", "demo2", id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.Dao().FindRecordById("demo2", id); err == nil {
+		t.Fatal("Expected the record to be deleted")
+	}
+}
@@ -0,0 +1,391 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tools/inflector"
+	"github.com/spf13/cobra"
+)
+
+var scaffoldVersionRegex = regexp.MustCompile(`^v?\d+\.\d+\.\d+`)
+var scaffoldIdentifierSplitRegex = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// NewScaffoldCommand creates and returns new command for generating
+// a new Go project that embeds PocketBase as a framework.
+//
+// The generated project contains example hooks, a typed models package
+// for the existing collections, test scaffolding and a Makefile.
+//
+// pbVersion is the PocketBase module version to require in the new project
+// (if not a valid semantic version, the latest release is used).
+func NewScaffoldCommand(app core.App, pbVersion string) *cobra.Command {
+	var module string
+
+	command := &cobra.Command{
+		Use:     "scaffold",
+		Example: "scaffold ./myapp --module=example.com/myapp",
+		Short:   "Generates a new Go project that extends PocketBase with custom hooks",
+		// prevents printing the error log twice
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		PreRunE: func(command *cobra.Command, args []string) error {
+			return prepareDataDir(app)
+		},
+		RunE: func(command *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("Missing the project directory argument.")
+			}
+
+			dir := args[0]
+
+			if module == "" {
+				module = filepath.Base(dir)
+			}
+
+			files, err := scaffoldFiles(app, module, pbVersion)
+			if err != nil {
+				return err
+			}
+
+			if err := writeScaffoldFiles(dir, files); err != nil {
+				return err
+			}
+
+			color.Green("Successfully generated project %q in %s!", module, dir)
+			fmt.Fprintf(command.OutOrStdout(), "Run \"cd %s && make deps && make serve\" to start the app.\n", dir)
+
+			return nil
+		},
+	}
+
+	command.Flags().StringVar(&module, "module", "", "the Go module path of the new project (default to the directory name)")
+
+	return command
+}
+
+// writeScaffoldFiles writes the scaffold files (path => content)
+// into dir (the directory must be missing or empty).
+func writeScaffoldFiles(dir string, files map[string]string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(entries) > 0 {
+		return fmt.Errorf("The project directory %q is not empty.", dir)
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scaffoldFiles generates the new project files as path => content map.
+func scaffoldFiles(app core.App, module string, pbVersion string) (map[string]string, error) {
+	version := "latest"
+	if scaffoldVersionRegex.MatchString(pbVersion) {
+		version = "v" + strings.TrimPrefix(pbVersion, "v")
+	}
+
+	files := map[string]string{
+		"go.mod":              fmt.Sprintf(scaffoldGoModTemplate, module),
+		"Makefile":            fmt.Sprintf(scaffoldMakefileTemplate, version),
+		".gitignore":          scaffoldGitignoreTemplate,
+		"main.go":             fmt.Sprintf(scaffoldMainTemplate, module),
+		"hooks/hooks.go":      scaffoldHooksTemplate,
+		"hooks/hooks_test.go": fmt.Sprintf(scaffoldHooksTestTemplate, module),
+		"models/doc.go":       scaffoldModelsDocTemplate,
+	}
+
+	collections := []*models.Collection{}
+	if err := app.Dao().CollectionQuery().OrderBy("name ASC").All(&collections); err != nil {
+		return nil, err
+	}
+
+	for _, collection := range collections {
+		files["models/"+inflector.Snakecase(collection.Name)+".go"] = scaffoldModel(collection)
+	}
+
+	// normalize the generated Go files format
+	for name, content := range files {
+		if filepath.Ext(name) != ".go" {
+			continue
+		}
+
+		formatted, err := format.Source([]byte(content))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to format %s: %w", name, err)
+		}
+
+		files[name] = string(formatted)
+	}
+
+	return files, nil
+}
+
+// scaffoldModel generates a typed record wrapper for the provided collection.
+func scaffoldModel(collection *models.Collection) string {
+	typeName := scaffoldIdentifier(collection.Name)
+
+	var b strings.Builder
+
+	b.WriteString("package models\n\n")
+
+	imports := []string{`"github.com/pocketbase/pocketbase/models"`}
+	for _, field := range collection.Schema.Fields() {
+		if field.Type == schema.FieldTypeDate ||
+			field.Type == schema.FieldTypeGeoPoint ||
+			field.Type == schema.FieldTypeDocument {
+			imports = append(imports, `"github.com/pocketbase/pocketbase/tools/types"`)
+			break
+		}
+	}
+	b.WriteString("import (\n" + strings.Join(imports, "\n") + "\n)\n\n")
+
+	fmt.Fprintf(&b, "// %sCollectionName is the name of the %q collection.\n", typeName, collection.Name)
+	fmt.Fprintf(&b, "const %sCollectionName = %q\n\n", typeName, collection.Name)
+
+	fmt.Fprintf(&b, "// %s is a typed wrapper of a %q collection record.\n", typeName, collection.Name)
+	fmt.Fprintf(&b, "type %s struct {\n*models.Record\n}\n\n", typeName)
+
+	fmt.Fprintf(&b, "// New%s wraps the provided %q collection record.\n", typeName, collection.Name)
+	fmt.Fprintf(&b, "func New%s(record *models.Record) *%s {\nreturn &%s{Record: record}\n}\n", typeName, typeName, typeName)
+
+	for _, field := range collection.Schema.Fields() {
+		goType, getter := scaffoldFieldType(field)
+
+		name := scaffoldIdentifier(field.Name)
+		setterName := "Set" + name
+
+		// avoid shadowing the embedded record methods
+		if scaffoldRecordHasMethod(name) || scaffoldRecordHasMethod(setterName) {
+			name += "Field"
+			setterName += "Field"
+		}
+
+		fmt.Fprintf(&b, "\n// %s returns the %q field value.\n", name, field.Name)
+		fmt.Fprintf(&b, "func (m *%s) %s() %s {\n", typeName, name, goType)
+		fmt.Fprintf(&b, "return m.%s(%q)\n}\n", getter, field.Name)
+
+		if field.IsReadOnly() {
+			continue
+		}
+
+		fmt.Fprintf(&b, "\n// %s sets the %q field value.\n", setterName, field.Name)
+		fmt.Fprintf(&b, "func (m *%s) %s(value %s) {\n", typeName, setterName, goType)
+		fmt.Fprintf(&b, "m.Set(%q, value)\n}\n", field.Name)
+	}
+
+	return b.String()
+}
+
+// scaffoldFieldType returns the Go type and the related
+// record getter method name of the provided schema field.
+func scaffoldFieldType(field *schema.SchemaField) (goType string, getter string) {
+	switch field.Type {
+	case schema.FieldTypeNumber:
+		return "float64", "GetFloat"
+	case schema.FieldTypeBool:
+		return "bool", "GetBool"
+	case schema.FieldTypeSequence:
+		return "int", "GetInt"
+	case schema.FieldTypeDate:
+		return "types.DateTime", "GetDateTime"
+	case schema.FieldTypeGeoPoint:
+		return "types.GeoPoint", "GetGeoPoint"
+	case schema.FieldTypeDocument:
+		return "types.Document", "GetDocument"
+	case schema.FieldTypeJson, schema.FieldTypeComputed:
+		return "any", "Get"
+	case schema.FieldTypeSelect, schema.FieldTypeFile, schema.FieldTypeRelation:
+		if _, isMultiple := field.PrepareValue(nil).([]string); isMultiple {
+			return "[]string", "GetStringSlice"
+		}
+	}
+
+	return "string", "GetString"
+}
+
+// scaffoldIdentifier converts the provided name to an exported Go identifier
+// (eg. "blog_posts" -> "BlogPosts").
+func scaffoldIdentifier(name string) string {
+	var result strings.Builder
+
+	for _, part := range scaffoldIdentifierSplitRegex.Split(name, -1) {
+		result.WriteString(inflector.UcFirst(part))
+	}
+
+	identifier := result.String()
+	if identifier == "" || (identifier[0] >= '0' && identifier[0] <= '9') {
+		identifier = "X" + identifier
+	}
+
+	return identifier
+}
+
+func scaffoldRecordHasMethod(name string) bool {
+	_, ok := reflect.TypeOf(&models.Record{}).MethodByName(name)
+	return ok
+}
+
+// -------------------------------------------------------------------
+// Templates
+// -------------------------------------------------------------------
+
+const scaffoldModelsDocTemplate = `// Package models contains typed wrappers of the app collections records.
+//
+// The wrappers are generated from the collections schema at the time of
+// the project scaffolding and should be updated manually on schema change.
+package models
+`
+
+const scaffoldGoModTemplate = `module %s
+
+go 1.18
+`
+
+const scaffoldGitignoreTemplate = `/pb_data/
+/test_pb_data/
+/app
+`
+
+const scaffoldMakefileTemplate = `PB_VERSION ?= %s
+
+# the data directory to copy as tests data
+PB_DATA ?= ./pb_data
+
+.PHONY: deps build serve test
+
+deps:
+	go get github.com/pocketbase/pocketbase@$(PB_VERSION)
+	go mod tidy
+
+build:
+	go build -o app .
+
+serve:
+	go run . serve
+
+test_pb_data:
+	cp -r $(PB_DATA) test_pb_data
+
+test: test_pb_data
+	go test ./...
+`
+
+const scaffoldMainTemplate = `package main
+
+import (
+	"log"
+
+	"github.com/pocketbase/pocketbase"
+
+	"%s/hooks"
+)
+
+func main() {
+	app := pocketbase.New()
+
+	hooks.Register(app)
+
+	if err := app.Start(); err != nil {
+		log.Fatal(err)
+	}
+}
+`
+
+const scaffoldHooksTemplate = `// Package hooks contains the app event hooks and custom routes.
+package hooks
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// Register registers the app event hooks and custom routes.
+func Register(app core.App) {
+	// example custom route
+	app.OnBeforeServe().Add(func(e *core.ServeEvent) error {
+		e.Router.GET("/api/hello", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]string{"message": "Hello world!"})
+		})
+
+		return nil
+	})
+
+	// example record hook
+	app.OnRecordBeforeCreateRequest().Add(func(e *core.RecordCreateEvent) error {
+		if app.IsDebug() {
+			log.Printf("Creating a new %q record.\n", e.Record.Collection().Name)
+		}
+
+		return nil
+	})
+}
+`
+
+const scaffoldHooksTestTemplate = `package hooks_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tests"
+
+	"%s/hooks"
+)
+
+// testDataDir is a copy of the app data directory (see "make test_pb_data").
+const testDataDir = "../test_pb_data"
+
+func testAppFactory() (*tests.TestApp, error) {
+	app, err := tests.NewTestApp(testDataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	hooks.Register(app)
+
+	return app, nil
+}
+
+func TestHelloRoute(t *testing.T) {
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "hello route",
+			Method:          http.MethodGet,
+			Url:             "/api/hello",
+			TestAppFactory:  testAppFactory,
+			ExpectedStatus:  200,
+			ExpectedContent: []string{` + "`" + `"message":"Hello world!"` + "`" + `},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+`
@@ -0,0 +1,94 @@
+package cmd_test
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/cmd"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestScaffoldCommand(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dir := filepath.Join(t.TempDir(), "myapp")
+
+	execute := func(args ...string) error {
+		command := cmd.NewScaffoldCommand(app, "0.11.0")
+		command.SetOut(&bytes.Buffer{})
+		command.SetArgs(args)
+		return command.Execute()
+	}
+
+	if err := execute(); err == nil {
+		t.Fatal("Expected missing dir error")
+	}
+
+	if err := execute(dir, "--module", "example.com/myapp"); err != nil {
+		t.Fatal(err)
+	}
+
+	// non-empty dir
+	if err := execute(dir); err == nil {
+		t.Fatal("Expected non-empty dir error")
+	}
+
+	expectations := map[string][]string{
+		"go.mod":   {"module example.com/myapp"},
+		"Makefile": {"PB_VERSION ?= v0.11.0", "go test ./..."},
+		"main.go":  {`"example.com/myapp/hooks"`, "hooks.Register(app)"},
+		"hooks/hooks.go": {
+			"func Register(app core.App) {",
+			`e.Router.GET("/api/hello"`,
+			`log.Printf("Creating a new %q record.\n"`,
+		},
+		"hooks/hooks_test.go": {`"example.com/myapp/hooks"`, "tests.NewTestApp(testDataDir)"},
+		"models/doc.go":       {"package models"},
+		"models/demo2.go": {
+			"type Demo2 struct {",
+			"func NewDemo2(record *models.Record) *Demo2 {",
+			"func (m *Demo2) Title() string {",
+			"func (m *Demo2) SetTitle(value string) {",
+			"func (m *Demo2) Active() bool {",
+		},
+		"models/demo1.go": {
+			"func (m *Demo1) SelectMany() []string {",
+			"func (m *Demo1) SelectOne() string {",
+			"func (m *Demo1) Datetime() types.DateTime {",
+			"func (m *Demo1) Number() float64 {",
+			"func (m *Demo1) Json() any {",
+			"func (m *Demo1) RelMany() []string {",
+		},
+		"models/users.go": {
+			"const UsersCollectionName = \"users\"",
+			"func (m *Users) Avatar() string {",
+			"func (m *Users) File() []string {",
+		},
+	}
+
+	for name, expected := range expectations {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Errorf("Failed to read %s: %v", name, err)
+			continue
+		}
+
+		for _, v := range expected {
+			if !strings.Contains(string(content), v) {
+				t.Errorf("Expected %q in %s:\n%s", v, name, content)
+			}
+		}
+
+		if filepath.Ext(name) == ".go" {
+			if _, err := parser.ParseFile(token.NewFileSet(), name, content, 0); err != nil {
+				t.Errorf("Failed to parse %s: %v", name, err)
+			}
+		}
+	}
+}
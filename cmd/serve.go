@@ -11,16 +11,33 @@ import (
 	"github.com/fatih/color"
 	"github.com/labstack/echo/v5/middleware"
 	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/alerts"
 	"github.com/pocketbase/pocketbase/apis"
 	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/guardrails"
+	"github.com/pocketbase/pocketbase/mails"
 	"github.com/pocketbase/pocketbase/migrations"
 	"github.com/pocketbase/pocketbase/migrations/logs"
+	"github.com/pocketbase/pocketbase/reports"
 	"github.com/pocketbase/pocketbase/tools/migrate"
+	"github.com/pocketbase/pocketbase/tools/routine"
 	"github.com/spf13/cobra"
 	"golang.org/x/crypto/acme"
 	"golang.org/x/crypto/acme/autocert"
 )
 
+// guardrailsCheckInterval specifies how often to check the free disk
+// space and the data db size of the app (see [guardrails.Check]).
+const guardrailsCheckInterval = 1 * time.Minute
+
+// digestsCheckInterval specifies how often to check for
+// queued email digests that are ready to be sent.
+const digestsCheckInterval = 1 * time.Minute
+
+// reportsCheckInterval specifies how often to check for
+// scheduled reports that are due to run (see [reports.RunDue]).
+const reportsCheckInterval = 1 * time.Minute
+
 // NewServeCommand creates and returns new command responsible for
 // starting the default PocketBase web server.
 func NewServeCommand(app core.App, showStartBanner bool) *cobra.Command {
@@ -75,8 +92,17 @@ func NewServeCommand(app core.App, showStartBanner bool) *cobra.Command {
 
 			serverConfig := &http.Server{
 				TLSConfig: &tls.Config{
-					GetCertificate: certManager.GetCertificate,
-					NextProtos:     []string{acme.ALPNProto},
+					GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+						cert, err := certManager.GetCertificate(hello)
+
+						// alert only for the allowed hosts certificate failures
+						if err != nil && certManager.HostPolicy(hello.Context(), hello.ServerName) == nil {
+							alerts.CertificateFailure(app, hello.ServerName, err)
+						}
+
+						return cert, err
+					},
+					NextProtos: []string{acme.ALPNProto},
 				},
 				ReadTimeout: 60 * time.Second,
 				// WriteTimeout: 60 * time.Second, // breaks sse!
@@ -96,6 +122,48 @@ func NewServeCommand(app core.App, showStartBanner bool) *cobra.Command {
 				regular.Printf("  - Admin UI: %s\n", color.CyanString("%s://%s/_/", schema, serverConfig.Addr))
 			}
 
+			// periodically check the free disk space and data db size
+			routine.FireAndForget(func() {
+				checkGuardrails := func() {
+					if err := guardrails.Check(app); err != nil && app.IsDebug() {
+						log.Println("Guardrails check failed:", err)
+					}
+				}
+
+				checkGuardrails()
+
+				ticker := time.NewTicker(guardrailsCheckInterval)
+				defer ticker.Stop()
+
+				for range ticker.C {
+					checkGuardrails()
+				}
+			})
+
+			// periodically send the ready queued email digests
+			routine.FireAndForget(func() {
+				ticker := time.NewTicker(digestsCheckInterval)
+				defer ticker.Stop()
+
+				for range ticker.C {
+					if err := mails.SendRecordDigests(app); err != nil && app.IsDebug() {
+						log.Println("Email digests send failed:", err)
+					}
+				}
+			})
+
+			// periodically run the due scheduled reports
+			routine.FireAndForget(func() {
+				ticker := time.NewTicker(reportsCheckInterval)
+				defer ticker.Stop()
+
+				for now := range ticker.C {
+					if err := reports.RunDue(app, now); err != nil && app.IsDebug() {
+						log.Println("Scheduled reports run failed:", err)
+					}
+				}
+			})
+
 			var serveErr error
 			if httpsAddr != "" {
 				// if httpAddr is set, start an HTTP server to redirect the traffic to the HTTPS version
@@ -170,3 +238,13 @@ func runMigrations(app core.App) error {
 
 	return nil
 }
+
+// prepareDataDir applies the latest migrations and reloads the app
+// settings (used by the commands that operate directly on the data dir).
+func prepareDataDir(app core.App) error {
+	if err := runMigrations(app); err != nil {
+		return err
+	}
+
+	return app.RefreshSettings()
+}
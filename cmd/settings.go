@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models/settings"
+	"github.com/spf13/cobra"
+)
+
+// NewSettingsCommand creates and returns new command for reading
+// and updating the app settings directly from the data dir.
+func NewSettingsCommand(app core.App) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "settings",
+		Short: "Manages the app settings",
+		PersistentPreRunE: func(command *cobra.Command, args []string) error {
+			return prepareDataDir(app)
+		},
+	}
+
+	command.AddCommand(settingsGetCommand(app))
+	command.AddCommand(settingsSetCommand(app))
+	command.AddCommand(settingsTokenSigningCommand(app))
+
+	return command
+}
+
+func settingsGetCommand(app core.App) *cobra.Command {
+	var unmasked bool
+
+	command := &cobra.Command{
+		Use:     "get",
+		Example: "settings get meta.appName",
+		Short:   "Prints the settings (or only the value at the specified dot-separated path) as JSON",
+		// prevents printing the error log twice
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return errors.New("Too many arguments (expects only an optional settings path).")
+			}
+
+			s := app.Settings()
+			if !unmasked {
+				redacted, err := s.RedactClone()
+				if err != nil {
+					return err
+				}
+				s = redacted
+			}
+
+			var path string
+			if len(args) == 1 {
+				path = args[0]
+			}
+
+			value, err := settingsValue(s, path)
+			if err != nil {
+				return err
+			}
+
+			encoded, err := json.MarshalIndent(value, "", "  ")
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(command.OutOrStdout(), string(encoded))
+
+			return nil
+		},
+	}
+
+	command.Flags().BoolVar(&unmasked, "unmasked", false, "print the secret values as they are (default to ******)")
+
+	return command
+}
+
+func settingsSetCommand(app core.App) *cobra.Command {
+	return &cobra.Command{
+		Use:     "set",
+		Example: "settings set smtp.port 587",
+		Short:   "Updates a single settings value at the specified dot-separated path",
+		Long: `
+Updates a single settings value at the specified dot-separated path.
+The value is parsed as JSON and fallbacks to a plain string if it is not valid JSON.
+`,
+		// prevents printing the error log twice
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("Missing settings path and value arguments.")
+			}
+
+			// ensures that the path exists to prevent silently ignoring typos
+			if _, err := settingsValue(app.Settings(), args[0]); err != nil || args[0] == "" {
+				return fmt.Errorf("Missing settings path %q.", args[0])
+			}
+
+			var value any
+			if err := json.Unmarshal([]byte(args[1]), &value); err != nil {
+				value = args[1]
+			}
+
+			// build the nested settings patch from the path in reverse order
+			// (eg. "smtp.port" -> {"smtp": {"port": value}})
+			keys := strings.Split(args[0], ".")
+			for i := len(keys) - 1; i >= 0; i-- {
+				value = map[string]any{keys[i]: value}
+			}
+
+			patch, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+
+			form := forms.NewSettingsUpsert(app)
+			if err := json.Unmarshal(patch, form); err != nil {
+				return fmt.Errorf("Invalid %q value: %v", args[0], err)
+			}
+
+			if err := form.Submit(); err != nil {
+				return fmt.Errorf("Failed to update the settings: %v", err)
+			}
+
+			color.Green("Successfully updated %s!", args[0])
+
+			return nil
+		},
+	}
+}
+
+// settingsValue returns the JSON decoded value of the provided
+// settings at the specified dot-separated path (empty path
+// returns the entire settings).
+func settingsValue(s *settings.Settings, path string) (any, error) {
+	raw, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+
+	if path == "" {
+		return value, nil
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("Missing settings path %q.", path)
+		}
+
+		if value, ok = m[key]; !ok {
+			return nil, fmt.Errorf("Missing settings path %q.", path)
+		}
+	}
+
+	return value, nil
+}
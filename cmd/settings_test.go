@@ -0,0 +1,101 @@
+package cmd_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/cmd"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestSettingsGetCommand(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name        string
+		args        []string
+		expectError bool
+		expected    []string
+	}{
+		{"too many args", []string{"get", "meta", "smtp"}, true, nil},
+		{"missing path", []string{"get", "meta.missing"}, true, nil},
+		{"nested missing path", []string{"get", "meta.appName.missing"}, true, nil},
+		{"all", []string{"get"}, false, []string{`"meta": {`, `"smtp": {`, `"secret": "******"`}},
+		{"object path", []string{"get", "adminAuthToken"}, false, []string{`"duration": `, `"secret": "******"`}},
+		{"single value path", []string{"get", "meta.appName"}, false, []string{`"acme_test"`}},
+		{"unmasked", []string{"get", "adminAuthToken.secret", "--unmasked"}, false, []string{`"` + app.Settings().AdminAuthToken.Secret + `"`}},
+	}
+
+	for _, s := range scenarios {
+		out := &bytes.Buffer{}
+
+		command := cmd.NewSettingsCommand(app)
+		command.SetOut(out)
+		command.SetArgs(s.args)
+
+		err := command.Execute()
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		for _, v := range s.expected {
+			if !strings.Contains(out.String(), v) {
+				t.Errorf("[%s] Expected %q in\n%s", s.name, v, out.String())
+			}
+		}
+	}
+}
+
+func TestSettingsSetCommand(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name        string
+		args        []string
+		expectError bool
+	}{
+		{"missing args", []string{"set"}, true},
+		{"missing value", []string{"set", "meta.appName"}, true},
+		{"missing path", []string{"set", "meta.missing", "test"}, true},
+		{"empty path", []string{"set", "", "test"}, true},
+		{"invalid value type", []string{"set", "smtp.port", `"abc"`}, true},
+		{"failed validation", []string{"set", "logs.maxDays", "-1"}, true},
+		{"string value", []string{"set", "meta.appName", "new_name"}, false},
+		{"json value", []string{"set", "smtp.port", "1025"}, false},
+	}
+
+	for _, s := range scenarios {
+		command := cmd.NewSettingsCommand(app)
+		command.SetArgs(s.args)
+
+		err := command.Execute()
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+		}
+	}
+
+	// reload the stored settings
+	if err := app.RefreshSettings(); err != nil {
+		t.Fatal(err)
+	}
+
+	if app.Settings().Meta.AppName != "new_name" {
+		t.Fatalf("Expected appName %q, got %q", "new_name", app.Settings().Meta.AppName)
+	}
+
+	if app.Settings().Smtp.Port != 1025 {
+		t.Fatalf("Expected smtp port %d, got %d", 1025, app.Settings().Smtp.Port)
+	}
+
+	if app.Settings().Logs.MaxDays < 0 {
+		t.Fatalf("Expected the invalid logs maxDays to not be saved, got %d", app.Settings().Logs.MaxDays)
+	}
+}
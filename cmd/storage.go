@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"github.com/spf13/cobra"
+)
+
+// Local storage directory layouts.
+const (
+	storageLayoutFlat    = "flat"
+	storageLayoutSharded = "sharded"
+)
+
+// NewStorageCommand creates and returns new command for managing
+// the app local storage (aka. the "pb_data/storage" files).
+func NewStorageCommand(app core.App) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "storage",
+		Short: "Manages the app local storage",
+		PersistentPreRunE: func(command *cobra.Command, args []string) error {
+			return prepareDataDir(app)
+		},
+	}
+
+	command.AddCommand(storageLayoutCommand(app))
+
+	return command
+}
+
+func storageLayoutCommand(app core.App) *cobra.Command {
+	command := &cobra.Command{
+		Use:       "layout",
+		Example:   "storage layout sharded",
+		Short:     "Moves the local storage record dirs to the flat or sharded layout",
+		ValidArgs: []string{storageLayoutFlat, storageLayoutSharded},
+		Long: `
+Moves the local storage record directories to the specified layout:
+  - flat    - "collectionId/recordId" (default)
+  - sharded - "collectionId/ab/cd/recordId" (recommended for large file counts)
+
+Stop the app before running the command and make sure to start it
+afterwards with the matching ShardedStorage config option.
+
+It is safe to rerun the command after an interruption.
+`,
+		// prevents printing the error log twice
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if len(args) != 1 || (args[0] != storageLayoutFlat && args[0] != storageLayoutSharded) {
+				return fmt.Errorf("Missing or invalid layout argument (%s or %s).", storageLayoutFlat, storageLayoutSharded)
+			}
+
+			if app.Settings().S3.Enabled {
+				return errors.New("The storage layout is applicable only for the local storage (S3 is enabled).")
+			}
+
+			moved, err := filesystem.MigrateLocalLayout(
+				filepath.Join(app.DataDir(), "storage"),
+				args[0] == storageLayoutSharded,
+			)
+			if err != nil {
+				return fmt.Errorf("Failed to migrate the storage layout (%d record dirs were moved): %w", moved, err)
+			}
+
+			color.Green("Successfully moved %d record dir(s) to the %s layout.", moved, args[0])
+
+			return nil
+		},
+	}
+
+	return command
+}
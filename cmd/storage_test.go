@@ -0,0 +1,54 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/cmd"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+)
+
+func TestStorageLayoutCommand(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	storageDir := filepath.Join(app.DataDir(), "storage")
+
+	// existing test data record file
+	fileKey := "_pb_users_auth_/4q1xlclmfloku33/300_1SEi6Q6U72.png"
+	if _, err := os.Stat(filepath.Join(storageDir, fileKey)); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name         string
+		args         []string
+		expectError  bool
+		expectedPath string
+	}{
+		{"missing layout", []string{"layout"}, true, fileKey},
+		{"invalid layout", []string{"layout", "invalid"}, true, fileKey},
+		{"sharded", []string{"layout", "sharded"}, false, filesystem.ShardedKey(fileKey)},
+		{"sharded (rerun)", []string{"layout", "sharded"}, false, filesystem.ShardedKey(fileKey)},
+		{"flat", []string{"layout", "flat"}, false, fileKey},
+	}
+
+	for _, s := range scenarios {
+		command := cmd.NewStorageCommand(app)
+		command.SetArgs(s.args)
+
+		err := command.Execute()
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if _, err := os.Stat(filepath.Join(storageDir, s.expectedPath)); err != nil {
+			t.Errorf("[%s] Expected file %s to exist: %v", s.name, s.expectedPath, err)
+		}
+	}
+}
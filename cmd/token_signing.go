@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models/settings"
+	"github.com/spf13/cobra"
+)
+
+// settingsTokenSigningCommand creates and returns new command for
+// migrating the auth tokens from HMAC to asymmetric signing (and back).
+func settingsTokenSigningCommand(app core.App) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "token-signing",
+		Short: "Manages the asymmetric auth tokens signing",
+	}
+
+	command.AddCommand(tokenSigningEnableCommand(app))
+	command.AddCommand(tokenSigningRotateCommand(app))
+	command.AddCommand(tokenSigningDisableCommand(app))
+
+	return command
+}
+
+func tokenSigningEnableCommand(app core.App) *cobra.Command {
+	var algorithm string
+
+	command := &cobra.Command{
+		Use:     "enable",
+		Example: "settings token-signing enable --alg=EdDSA",
+		Short:   "Enables the asymmetric auth tokens signing",
+		Long: `
+Enables the asymmetric auth tokens signing (generating a new signing key if necessary).
+
+The already issued HMAC auth tokens remain valid until their expiration,
+so the existing sessions are not affected by the migration.
+`,
+		// prevents printing the error log twice
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(command *cobra.Command, args []string) error {
+			form := forms.NewSettingsUpsert(app)
+
+			if algorithm != "" {
+				form.TokenSigning.Algorithm = algorithm
+			}
+
+			if err := form.TokenSigning.Validate(); err != nil {
+				return fmt.Errorf("Invalid token signing settings: %v", err)
+			}
+
+			// generate a new active key if missing or with different algorithm
+			if !hasActiveKeyWithAlgorithm(form.TokenSigning) {
+				if err := form.RotateTokenSigningKeys(); err != nil {
+					return fmt.Errorf("Failed to generate a new token signing key: %v", err)
+				}
+			}
+
+			form.TokenSigning.Enabled = true
+
+			if err := form.Submit(); err != nil {
+				return fmt.Errorf("Failed to enable the token signing: %v", err)
+			}
+
+			color.Green("Successfully enabled the %s auth tokens signing!", form.TokenSigning.Algorithm)
+
+			return nil
+		},
+	}
+
+	command.Flags().StringVar(&algorithm, "alg", "", "the signing algorithm of the new keys (RS256 or EdDSA)")
+
+	return command
+}
+
+func tokenSigningRotateCommand(app core.App) *cobra.Command {
+	return &cobra.Command{
+		Use:     "rotate",
+		Example: "settings token-signing rotate",
+		Short:   "Retires the active auth tokens signing key and generates a new one",
+		Long: `
+Retires the active auth tokens signing key and generates a new one.
+
+The retired key remains published (and valid) for the max auth tokens duration.
+`,
+		// prevents printing the error log twice
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(command *cobra.Command, args []string) error {
+			form := forms.NewSettingsUpsert(app)
+
+			if err := form.RotateTokenSigningKeys(); err != nil {
+				return fmt.Errorf("Failed to generate a new token signing key: %v", err)
+			}
+
+			if err := form.Submit(); err != nil {
+				return fmt.Errorf("Failed to save the token signing keys: %v", err)
+			}
+
+			color.Green("Successfully rotated the auth tokens signing keys!")
+
+			return nil
+		},
+	}
+}
+
+func tokenSigningDisableCommand(app core.App) *cobra.Command {
+	return &cobra.Command{
+		Use:     "disable",
+		Example: "settings token-signing disable",
+		Short:   "Disables the asymmetric auth tokens signing",
+		Long: `
+Disables the asymmetric auth tokens signing and fallbacks to the HMAC signed tokens.
+
+The signing keys are preserved, but the already issued asymmetric
+auth tokens will be rejected while the token signing is disabled.
+`,
+		// prevents printing the error log twice
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(command *cobra.Command, args []string) error {
+			form := forms.NewSettingsUpsert(app)
+			form.TokenSigning.Enabled = false
+
+			if err := form.Submit(); err != nil {
+				return fmt.Errorf("Failed to disable the token signing: %v", err)
+			}
+
+			color.Green("Successfully disabled the auth tokens signing!")
+
+			return nil
+		},
+	}
+}
+
+// hasActiveKeyWithAlgorithm checks whether the token signing config
+// has an active key matching the configured algorithm.
+func hasActiveKeyWithAlgorithm(config settings.TokenSigningConfig) bool {
+	algorithm := config.Algorithm
+	if algorithm == "" {
+		algorithm = settings.TokenSigningAlgorithmRS256
+	}
+
+	key := config.ActiveKey()
+
+	return key != nil && key.Algorithm() == algorithm
+}
@@ -0,0 +1,64 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/cmd"
+	"github.com/pocketbase/pocketbase/models/settings"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestSettingsTokenSigningCommand(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name            string
+		args            []string
+		expectError     bool
+		expectEnabled   bool
+		expectKeys      int
+		expectAlgorithm string
+	}{
+		{"invalid algorithm", []string{"token-signing", "enable", "--alg=HS256"}, true, false, 0, ""},
+		{"enable with the default algorithm", []string{"token-signing", "enable"}, false, true, 1, settings.TokenSigningAlgorithmRS256},
+		{"enable again with the same algorithm", []string{"token-signing", "enable"}, false, true, 1, settings.TokenSigningAlgorithmRS256},
+		{"enable with different algorithm", []string{"token-signing", "enable", "--alg=EdDSA"}, false, true, 2, settings.TokenSigningAlgorithmEdDSA},
+		{"rotate", []string{"token-signing", "rotate"}, false, true, 3, settings.TokenSigningAlgorithmEdDSA},
+		{"disable", []string{"token-signing", "disable"}, false, false, 3, settings.TokenSigningAlgorithmEdDSA},
+	}
+
+	for _, s := range scenarios {
+		command := cmd.NewSettingsCommand(app)
+		command.SetArgs(s.args)
+
+		err := command.Execute()
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		// reload the stored settings
+		if err := app.RefreshSettings(); err != nil {
+			t.Fatal(err)
+		}
+
+		signing := app.Settings().TokenSigning
+
+		if signing.Enabled != s.expectEnabled {
+			t.Errorf("[%s] Expected enabled %v, got %v", s.name, s.expectEnabled, signing.Enabled)
+		}
+
+		if len(signing.Keys) != s.expectKeys {
+			t.Errorf("[%s] Expected %d keys, got %d", s.name, s.expectKeys, len(signing.Keys))
+		}
+
+		if s.expectKeys > 0 {
+			if active := signing.ActiveKey(); active == nil || active.Algorithm() != s.expectAlgorithm {
+				t.Errorf("[%s] Expected active %s key, got %v", s.name, s.expectAlgorithm, active)
+			}
+		}
+	}
+}
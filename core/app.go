@@ -7,9 +7,12 @@ import (
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/daos"
 	"github.com/pocketbase/pocketbase/models/settings"
+	"github.com/pocketbase/pocketbase/tools/alert"
 	"github.com/pocketbase/pocketbase/tools/filesystem"
 	"github.com/pocketbase/pocketbase/tools/hook"
 	"github.com/pocketbase/pocketbase/tools/mailer"
+	"github.com/pocketbase/pocketbase/tools/reporter"
+	"github.com/pocketbase/pocketbase/tools/sms"
 	"github.com/pocketbase/pocketbase/tools/store"
 	"github.com/pocketbase/pocketbase/tools/subscriptions"
 )
@@ -66,15 +69,35 @@ type App interface {
 	// SubscriptionsBroker returns the app realtime subscriptions broker instance.
 	SubscriptionsBroker() *subscriptions.Broker
 
+	// Realtime returns the app realtime instance that could be used
+	// to send custom messages to the realtime subscribers (eg. from hooks).
+	Realtime() *Realtime
+
 	// NewMailClient creates and returns a configured app mail client.
 	NewMailClient() mailer.Mailer
 
+	// NewSmsClient creates and returns a configured app SMS gateway client.
+	//
+	// Returns nil if the SMS gateway is not enabled in the app settings.
+	NewSmsClient() sms.SMSSender
+
 	// NewFilesystem creates and returns a configured filesystem.System instance.
 	//
 	// NB! Make sure to call `Close()` on the returned result
 	// after you are done working with it.
 	NewFilesystem() (*filesystem.System, error)
 
+	// NewErrorReporter creates and returns a configured app error reporter.
+	//
+	// Returns nil if the error reporting is disabled.
+	NewErrorReporter() reporter.Reporter
+
+	// NewAlertNotifier creates and returns a configured app operator
+	// alerts notifier.
+	//
+	// Returns nil if the alerts are disabled.
+	NewAlertNotifier() alert.Notifier
+
 	// RefreshSettings reinitializes and reloads the stored application settings.
 	RefreshSettings() error
 
@@ -118,6 +141,18 @@ type App interface {
 	// It could be used to log the final API error in external services.
 	OnAfterApiError() *hook.Hook[*ApiErrorEvent]
 
+	// OnBeforeErrorReport hook is triggered right before sending a panic,
+	// 5xx API error or slow request event to the configured error reporter,
+	// allowing you to modify the event data or to send it to a completely
+	// different service (using [hook.StopPropagation]).
+	OnBeforeErrorReport() *hook.Hook[*ErrorReportEvent]
+
+	// OnBeforeAlertSend hook is triggered right before sending an operator
+	// alert (eg. on 5xx errors spike or low disk space), allowing you to
+	// modify the alert data or to send it to a completely different
+	// channel (using [hook.StopPropagation]).
+	OnBeforeAlertSend() *hook.Hook[*AlertEvent]
+
 	// ---------------------------------------------------------------
 	// Dao event hooks
 	// ---------------------------------------------------------------
@@ -194,6 +229,45 @@ type App interface {
 	// verification email was successfully sent to an auth record.
 	OnMailerAfterRecordChangeEmailSend() *hook.Hook[*MailerRecordEvent]
 
+	// OnMailerBeforeRecordLoginLinkSend hook is triggered right before
+	// sending a passwordless login link email to an auth record.
+	//
+	// Could be used to send your own custom email template if
+	// [hook.StopPropagation] is returned in one of its listeners.
+	OnMailerBeforeRecordLoginLinkSend() *hook.Hook[*MailerRecordEvent]
+
+	// OnMailerAfterRecordLoginLinkSend hook is triggered after a
+	// passwordless login link email was successfully sent to an auth record.
+	OnMailerAfterRecordLoginLinkSend() *hook.Hook[*MailerRecordEvent]
+
+	// ---------------------------------------------------------------
+	// SMS event hooks
+	// ---------------------------------------------------------------
+
+	// OnSmsBeforeRecordOtpSend hook is triggered right before
+	// sending a phone auth one-time password SMS to an auth record.
+	//
+	// Could be used to plug a custom SMS gateway by replacing the event
+	// SmsClient or to send the message yourself if [hook.StopPropagation]
+	// is returned in one of its listeners.
+	OnSmsBeforeRecordOtpSend() *hook.Hook[*SmsRecordEvent]
+
+	// OnSmsAfterRecordOtpSend hook is triggered after a phone auth
+	// one-time password SMS was successfully sent to an auth record.
+	OnSmsAfterRecordOtpSend() *hook.Hook[*SmsRecordEvent]
+
+	// ---------------------------------------------------------------
+	// Login throttle event hooks
+	// ---------------------------------------------------------------
+
+	// OnLoginLockout hook is triggered after a login identity or client ip
+	// was temporary locked due to too many failed password login attempts
+	// (see the app settings LoginThrottle options).
+	//
+	// Could be used for example to notify the account owner or to
+	// block the offending client ip in an external firewall.
+	OnLoginLockout() *hook.Hook[*LoginLockoutEvent]
+
 	// ---------------------------------------------------------------
 	// Realtime API event hooks
 	// ---------------------------------------------------------------
@@ -453,6 +527,15 @@ type App interface {
 	// successful API Record delete request.
 	OnRecordAfterDeleteRequest() *hook.Hook[*RecordDeleteEvent]
 
+	// OnRecordContentModeration hook is triggered after the built-in
+	// content moderation checks of each API Record create and update
+	// request of a collection with enabled moderation (admins are skipped).
+	//
+	// Could be used to run additional checks (eg. with an external
+	// service) and to flag, reject or approve the record by modifying
+	// the event verdict.
+	OnRecordContentModeration() *hook.Hook[*RecordContentModerationEvent]
+
 	// ---------------------------------------------------------------
 	// Collection API event hooks
 	// ---------------------------------------------------------------
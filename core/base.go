@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"log"
+	"net/mail"
 	"os"
 	"path/filepath"
 	"time"
@@ -14,9 +15,14 @@ import (
 	"github.com/pocketbase/pocketbase/daos"
 	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/models/settings"
+	"github.com/pocketbase/pocketbase/tools/alert"
 	"github.com/pocketbase/pocketbase/tools/filesystem"
 	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/inflector"
 	"github.com/pocketbase/pocketbase/tools/mailer"
+	"github.com/pocketbase/pocketbase/tools/reporter"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/sms"
 	"github.com/pocketbase/pocketbase/tools/store"
 	"github.com/pocketbase/pocketbase/tools/subscriptions"
 )
@@ -40,6 +46,11 @@ type BaseApp struct {
 	dataMaxIdleConns int
 	logsMaxOpenConns int
 	logsMaxIdleConns int
+	randomSource     security.RandomSource
+	columnNamer      inflector.ColumnNamer
+
+	attachedDatabases []string
+	shardedStorage    bool
 
 	// internals
 	cache               *store.Store[any]
@@ -47,6 +58,7 @@ type BaseApp struct {
 	dao                 *daos.Dao
 	logsDao             *daos.Dao
 	subscriptionsBroker *subscriptions.Broker
+	realtime            *Realtime
 
 	// app event hooks
 	onBeforeBootstrap *hook.Hook[*BootstrapEvent]
@@ -55,6 +67,9 @@ type BaseApp struct {
 	onBeforeApiError  *hook.Hook[*ApiErrorEvent]
 	onAfterApiError   *hook.Hook[*ApiErrorEvent]
 
+	onBeforeErrorReport *hook.Hook[*ErrorReportEvent]
+	onBeforeAlertSend   *hook.Hook[*AlertEvent]
+
 	// dao event hooks
 	onModelBeforeCreate *hook.Hook[*ModelEvent]
 	onModelAfterCreate  *hook.Hook[*ModelEvent]
@@ -72,6 +87,15 @@ type BaseApp struct {
 	onMailerAfterRecordVerificationSend   *hook.Hook[*MailerRecordEvent]
 	onMailerBeforeRecordChangeEmailSend   *hook.Hook[*MailerRecordEvent]
 	onMailerAfterRecordChangeEmailSend    *hook.Hook[*MailerRecordEvent]
+	onMailerBeforeRecordLoginLinkSend     *hook.Hook[*MailerRecordEvent]
+	onMailerAfterRecordLoginLinkSend      *hook.Hook[*MailerRecordEvent]
+
+	// sms event hooks
+	onSmsBeforeRecordOtpSend *hook.Hook[*SmsRecordEvent]
+	onSmsAfterRecordOtpSend  *hook.Hook[*SmsRecordEvent]
+
+	// login throttle event hooks
+	onLoginLockout *hook.Hook[*LoginLockoutEvent]
 
 	// realtime api event hooks
 	onRealtimeConnectRequest         *hook.Hook[*RealtimeConnectEvent]
@@ -127,6 +151,7 @@ type BaseApp struct {
 	onRecordAfterUpdateRequest  *hook.Hook[*RecordUpdateEvent]
 	onRecordBeforeDeleteRequest *hook.Hook[*RecordDeleteEvent]
 	onRecordAfterDeleteRequest  *hook.Hook[*RecordDeleteEvent]
+	onRecordContentModeration   *hook.Hook[*RecordContentModerationEvent]
 
 	// collection API event hooks
 	onCollectionsListRequest         *hook.Hook[*CollectionsListEvent]
@@ -150,6 +175,26 @@ type BaseAppConfig struct {
 	DataMaxIdleConns int // default 20
 	LogsMaxOpenConns int // default to 100
 	LogsMaxIdleConns int // default to 5
+
+	// RandomSource is an optional source for the auto generated
+	// record ids and query placeholders (eg. for deterministic tests).
+	RandomSource security.RandomSource
+
+	// ColumnNamer is an optional db identifiers normalization strategy
+	// used in the generated record queries (default to [inflector.Columnify]).
+	ColumnNamer inflector.ColumnNamer
+
+	// AttachedDatabases is an optional list with names of additional
+	// "pb_data/{name}.db" databases that are attached to the main data db
+	// (could be used to store the records of some collections in a separate file).
+	AttachedDatabases []string
+
+	// ShardedStorage enables the sharded "collectionId/ab/cd/recordId"
+	// local storage directory layout (see [filesystem.NewLocalSharded]).
+	//
+	// Existing files could be moved between the layouts with the
+	// "storage layout" console command.
+	ShardedStorage bool
 }
 
 // NewBaseApp creates and returns a new BaseApp instance
@@ -165,6 +210,10 @@ func NewBaseApp(config *BaseAppConfig) *BaseApp {
 		dataMaxIdleConns:    config.DataMaxIdleConns,
 		logsMaxOpenConns:    config.LogsMaxOpenConns,
 		logsMaxIdleConns:    config.LogsMaxIdleConns,
+		randomSource:        config.RandomSource,
+		columnNamer:         config.ColumnNamer,
+		attachedDatabases:   config.AttachedDatabases,
+		shardedStorage:      config.ShardedStorage,
 		cache:               store.New[any](nil),
 		settings:            settings.New(),
 		subscriptionsBroker: subscriptions.NewBroker(),
@@ -176,6 +225,9 @@ func NewBaseApp(config *BaseAppConfig) *BaseApp {
 		onBeforeApiError:  &hook.Hook[*ApiErrorEvent]{},
 		onAfterApiError:   &hook.Hook[*ApiErrorEvent]{},
 
+		onBeforeErrorReport: &hook.Hook[*ErrorReportEvent]{},
+		onBeforeAlertSend:   &hook.Hook[*AlertEvent]{},
+
 		// dao event hooks
 		onModelBeforeCreate: &hook.Hook[*ModelEvent]{},
 		onModelAfterCreate:  &hook.Hook[*ModelEvent]{},
@@ -193,6 +245,15 @@ func NewBaseApp(config *BaseAppConfig) *BaseApp {
 		onMailerAfterRecordVerificationSend:   &hook.Hook[*MailerRecordEvent]{},
 		onMailerBeforeRecordChangeEmailSend:   &hook.Hook[*MailerRecordEvent]{},
 		onMailerAfterRecordChangeEmailSend:    &hook.Hook[*MailerRecordEvent]{},
+		onMailerBeforeRecordLoginLinkSend:     &hook.Hook[*MailerRecordEvent]{},
+		onMailerAfterRecordLoginLinkSend:      &hook.Hook[*MailerRecordEvent]{},
+
+		// sms event hooks
+		onSmsBeforeRecordOtpSend: &hook.Hook[*SmsRecordEvent]{},
+		onSmsAfterRecordOtpSend:  &hook.Hook[*SmsRecordEvent]{},
+
+		// login throttle event hooks
+		onLoginLockout: &hook.Hook[*LoginLockoutEvent]{},
 
 		// realtime API event hooks
 		onRealtimeConnectRequest:         &hook.Hook[*RealtimeConnectEvent]{},
@@ -248,6 +309,7 @@ func NewBaseApp(config *BaseAppConfig) *BaseApp {
 		onRecordAfterUpdateRequest:  &hook.Hook[*RecordUpdateEvent]{},
 		onRecordBeforeDeleteRequest: &hook.Hook[*RecordDeleteEvent]{},
 		onRecordAfterDeleteRequest:  &hook.Hook[*RecordDeleteEvent]{},
+		onRecordContentModeration:   &hook.Hook[*RecordContentModerationEvent]{},
 
 		// collection API event hooks
 		onCollectionsListRequest:         &hook.Hook[*CollectionsListEvent]{},
@@ -262,6 +324,8 @@ func NewBaseApp(config *BaseAppConfig) *BaseApp {
 		onCollectionsAfterImportRequest:  &hook.Hook[*CollectionsImportEvent]{},
 	}
 
+	app.realtime = NewRealtime(app)
+
 	app.registerDefaultHooks()
 
 	return app
@@ -420,6 +484,12 @@ func (app *BaseApp) SubscriptionsBroker() *subscriptions.Broker {
 	return app.subscriptionsBroker
 }
 
+// Realtime returns the app realtime instance that could be used
+// to send custom messages to the realtime subscribers.
+func (app *BaseApp) Realtime() *Realtime {
+	return app.realtime
+}
+
 // NewMailClient creates and returns a new SMTP or Sendmail client
 // based on the current app settings.
 func (app *BaseApp) NewMailClient() mailer.Mailer {
@@ -437,6 +507,30 @@ func (app *BaseApp) NewMailClient() mailer.Mailer {
 	return &mailer.Sendmail{}
 }
 
+// NewSmsClient creates and returns a new SMS gateway client
+// based on the current app settings.
+//
+// Returns nil if the SMS gateway is not enabled.
+func (app *BaseApp) NewSmsClient() sms.SMSSender {
+	config := app.Settings().Sms
+	if !config.Enabled {
+		return nil
+	}
+
+	switch config.Provider {
+	case sms.ProviderVonage:
+		return &sms.Vonage{
+			ApiKey:    config.ApiKey,
+			ApiSecret: config.ApiSecret,
+		}
+	default:
+		return &sms.Twilio{
+			AccountSid: config.ApiKey,
+			AuthToken:  config.ApiSecret,
+		}
+	}
+}
+
 // NewFilesystem creates a new local or S3 filesystem instance
 // based on the current app settings.
 //
@@ -455,9 +549,72 @@ func (app *BaseApp) NewFilesystem() (*filesystem.System, error) {
 	}
 
 	// fallback to local filesystem
+	if app.shardedStorage {
+		return filesystem.NewLocalSharded(filepath.Join(app.DataDir(), "storage"))
+	}
+
 	return filesystem.NewLocal(filepath.Join(app.DataDir(), "storage"))
 }
 
+// NewErrorReporter creates a new Sentry compatible error reporter
+// based on the current app settings.
+//
+// Returns nil if the error reporting is disabled.
+func (app *BaseApp) NewErrorReporter() reporter.Reporter {
+	if !app.Settings().ErrorReporting.Enabled {
+		return nil
+	}
+
+	hostname, _ := os.Hostname()
+
+	return &reporter.SentryClient{
+		Dsn:         app.Settings().ErrorReporting.Dsn,
+		Environment: app.Settings().ErrorReporting.Environment,
+		ServerName:  hostname,
+	}
+}
+
+// NewAlertNotifier creates a new operator alerts notifier (email, webhook
+// and/or Slack compatible) based on the current app settings.
+//
+// Returns nil if the alerts are disabled.
+func (app *BaseApp) NewAlertNotifier() alert.Notifier {
+	config := app.Settings().Alerts
+	if !config.Enabled {
+		return nil
+	}
+
+	notifiers := alert.MultiNotifier{}
+
+	if len(config.Emails) > 0 {
+		notifiers = append(notifiers, &alert.MailClient{
+			Mailer: app.NewMailClient(),
+			From: mail.Address{
+				Name:    app.Settings().Meta.SenderName,
+				Address: app.Settings().Meta.SenderAddress,
+			},
+			To: config.Emails,
+		})
+	}
+
+	if config.WebhookUrl != "" {
+		webhook := &alert.WebhookClient{
+			Url:      config.WebhookUrl,
+			Template: config.WebhookTemplate,
+		}
+		if app.Settings().Signing.Enabled {
+			webhook.Secret = app.Settings().Signing.Secret
+		}
+		notifiers = append(notifiers, webhook)
+	}
+
+	if config.SlackWebhookUrl != "" {
+		notifiers = append(notifiers, &alert.SlackClient{Url: config.SlackWebhookUrl})
+	}
+
+	return notifiers
+}
+
 // RefreshSettings reinitializes and reloads the stored application settings.
 func (app *BaseApp) RefreshSettings() error {
 	if app.settings == nil {
@@ -508,6 +665,14 @@ func (app *BaseApp) OnAfterApiError() *hook.Hook[*ApiErrorEvent] {
 	return app.onAfterApiError
 }
 
+func (app *BaseApp) OnBeforeErrorReport() *hook.Hook[*ErrorReportEvent] {
+	return app.onBeforeErrorReport
+}
+
+func (app *BaseApp) OnBeforeAlertSend() *hook.Hook[*AlertEvent] {
+	return app.onBeforeAlertSend
+}
+
 // -------------------------------------------------------------------
 // Dao event hooks
 // -------------------------------------------------------------------
@@ -572,6 +737,34 @@ func (app *BaseApp) OnMailerAfterRecordChangeEmailSend() *hook.Hook[*MailerRecor
 	return app.onMailerAfterRecordChangeEmailSend
 }
 
+func (app *BaseApp) OnMailerBeforeRecordLoginLinkSend() *hook.Hook[*MailerRecordEvent] {
+	return app.onMailerBeforeRecordLoginLinkSend
+}
+
+func (app *BaseApp) OnMailerAfterRecordLoginLinkSend() *hook.Hook[*MailerRecordEvent] {
+	return app.onMailerAfterRecordLoginLinkSend
+}
+
+// -------------------------------------------------------------------
+// SMS event hooks
+// -------------------------------------------------------------------
+
+func (app *BaseApp) OnSmsBeforeRecordOtpSend() *hook.Hook[*SmsRecordEvent] {
+	return app.onSmsBeforeRecordOtpSend
+}
+
+func (app *BaseApp) OnSmsAfterRecordOtpSend() *hook.Hook[*SmsRecordEvent] {
+	return app.onSmsAfterRecordOtpSend
+}
+
+// -------------------------------------------------------------------
+// Login throttle event hooks
+// -------------------------------------------------------------------
+
+func (app *BaseApp) OnLoginLockout() *hook.Hook[*LoginLockoutEvent] {
+	return app.onLoginLockout
+}
+
 // -------------------------------------------------------------------
 // Realtime API event hooks
 // -------------------------------------------------------------------
@@ -768,6 +961,10 @@ func (app *BaseApp) OnRecordAfterDeleteRequest() *hook.Hook[*RecordDeleteEvent]
 	return app.onRecordAfterDeleteRequest
 }
 
+func (app *BaseApp) OnRecordContentModeration() *hook.Hook[*RecordContentModerationEvent] {
+	return app.onRecordContentModeration
+}
+
 // -------------------------------------------------------------------
 // Collection API event hooks
 // -------------------------------------------------------------------
@@ -826,7 +1023,7 @@ func (app *BaseApp) initLogsDB() error {
 		maxIdleConns = app.logsMaxIdleConns
 	}
 
-	concurrentDB, err := connectDB(filepath.Join(app.DataDir(), "logs.db"))
+	concurrentDB, err := connectDB(filepath.Join(app.DataDir(), "logs.db"), nil)
 	if err != nil {
 		return err
 	}
@@ -834,7 +1031,7 @@ func (app *BaseApp) initLogsDB() error {
 	concurrentDB.DB().SetMaxIdleConns(maxIdleConns)
 	concurrentDB.DB().SetConnMaxIdleTime(5 * time.Minute)
 
-	nonconcurrentDB, err := connectDB(filepath.Join(app.DataDir(), "logs.db"))
+	nonconcurrentDB, err := connectDB(filepath.Join(app.DataDir(), "logs.db"), nil)
 	if err != nil {
 		return err
 	}
@@ -843,6 +1040,7 @@ func (app *BaseApp) initLogsDB() error {
 	nonconcurrentDB.DB().SetConnMaxIdleTime(5 * time.Minute)
 
 	app.logsDao = daos.NewMultiDB(concurrentDB, nonconcurrentDB)
+	app.logsDao.SetRandomSource(app.randomSource)
 
 	return nil
 }
@@ -857,7 +1055,12 @@ func (app *BaseApp) initDataDB() error {
 		maxIdleConns = app.dataMaxIdleConns
 	}
 
-	concurrentDB, err := connectDB(filepath.Join(app.DataDir(), "data.db"))
+	attached, err := attachedDatabasePaths(app.DataDir(), app.attachedDatabases)
+	if err != nil {
+		return err
+	}
+
+	concurrentDB, err := connectDB(filepath.Join(app.DataDir(), "data.db"), attached)
 	if err != nil {
 		return err
 	}
@@ -865,7 +1068,7 @@ func (app *BaseApp) initDataDB() error {
 	concurrentDB.DB().SetMaxIdleConns(maxIdleConns)
 	concurrentDB.DB().SetConnMaxIdleTime(5 * time.Minute)
 
-	nonconcurrentDB, err := connectDB(filepath.Join(app.DataDir(), "data.db"))
+	nonconcurrentDB, err := connectDB(filepath.Join(app.DataDir(), "data.db"), attached)
 	if err != nil {
 		return err
 	}
@@ -893,6 +1096,25 @@ func (app *BaseApp) initDataDB() error {
 func (app *BaseApp) createDaoWithHooks(concurrentDB, nonconcurrentDB dbx.Builder) *daos.Dao {
 	dao := daos.NewMultiDB(concurrentDB, nonconcurrentDB)
 
+	// used for the collections with enabled records encryption
+	dao.SetEncryptionKey(os.Getenv(app.EncryptionEnv()))
+
+	// used for the auto generated ids and query placeholders
+	dao.SetRandomSource(app.randomSource)
+
+	// used for the field and table names in the record queries
+	dao.SetColumnNamer(app.columnNamer)
+
+	// used for the @request.auth.prefs.* filter fields
+	dao.SetPreferenceKeysFunc(func() []settings.PreferenceKey {
+		return app.Settings().Preferences.Keys
+	})
+
+	// used for the "@macro.*" API rules references
+	dao.SetRuleMacrosFunc(func() map[string]string {
+		return app.Settings().RuleMacros.Map()
+	})
+
 	dao.BeforeCreateFunc = func(eventDao *daos.Dao, m models.Model) error {
 		return app.OnModelBeforeCreate().Trigger(&ModelEvent{eventDao, m})
 	}
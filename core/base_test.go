@@ -2,19 +2,29 @@ package core
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/alert"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
 	"github.com/pocketbase/pocketbase/tools/mailer"
+	"github.com/pocketbase/pocketbase/tools/reporter"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/sms"
 )
 
 func TestNewBaseApp(t *testing.T) {
 	const testDataDir = "./pb_base_app_test_data_dir/"
 	defer os.RemoveAll(testDataDir)
 
+	randomSource := security.NewSeededRandomSource(1)
+
 	app := NewBaseApp(&BaseAppConfig{
 		DataDir:       testDataDir,
 		EncryptionEnv: "test_env",
 		IsDebug:       true,
+		RandomSource:  randomSource,
 	})
 
 	if app.dataDir != testDataDir {
@@ -29,6 +39,10 @@ func TestNewBaseApp(t *testing.T) {
 		t.Fatalf("expected isDebug true, got %v", app.isDebug)
 	}
 
+	if app.randomSource != randomSource {
+		t.Fatalf("expected randomSource %v, got %v", randomSource, app.randomSource)
+	}
+
 	if app.cache == nil {
 		t.Fatal("expected cache to be set, got nil")
 	}
@@ -124,6 +138,54 @@ func TestBaseAppBootstrap(t *testing.T) {
 	}
 }
 
+func TestBaseAppAttachedDatabases(t *testing.T) {
+	const testDataDir = "./pb_base_app_test_data_dir/"
+	defer os.RemoveAll(testDataDir)
+
+	// invalid attached database name
+	invalidApp := NewBaseApp(&BaseAppConfig{
+		DataDir:           testDataDir,
+		AttachedDatabases: []string{"main"},
+	})
+	if err := invalidApp.Bootstrap(); err == nil {
+		invalidApp.ResetBootstrapState()
+		t.Fatal("Expected bootstrap error for invalid attached database name")
+	}
+
+	app := NewBaseApp(&BaseAppConfig{
+		DataDir:           testDataDir,
+		AttachedDatabases: []string{"analytics"},
+	})
+	defer app.ResetBootstrapState()
+
+	if err := app.Bootstrap(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(testDataDir, "analytics.db")); err != nil {
+		t.Fatalf("Expected the attached database file to be created, got %v", err)
+	}
+
+	// hold a connection to ensure that the database
+	// is attached to the other pool connections too
+	err := app.DB().Transactional(func(tx *dbx.Tx) error {
+		for _, db := range []dbx.Builder{tx, app.DB()} {
+			var total int
+			err := db.NewQuery("SELECT count(*) FROM pragma_database_list WHERE name = 'analytics'").Row(&total)
+			if err != nil {
+				return err
+			}
+			if total != 1 {
+				t.Fatalf("Expected the analytics database to be attached, got %d", total)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestBaseAppGetters(t *testing.T) {
 	const testDataDir = "./pb_base_app_test_data_dir/"
 	defer os.RemoveAll(testDataDir)
@@ -239,6 +301,26 @@ func TestBaseAppGetters(t *testing.T) {
 		t.Fatalf("Getter app.OnMailerAfterRecordChangeEmailSend does not match or nil (%v vs %v)", app.OnMailerAfterRecordChangeEmailSend(), app.onMailerAfterRecordChangeEmailSend)
 	}
 
+	if app.onMailerBeforeRecordLoginLinkSend != app.OnMailerBeforeRecordLoginLinkSend() || app.OnMailerBeforeRecordLoginLinkSend() == nil {
+		t.Fatalf("Getter app.OnMailerBeforeRecordLoginLinkSend does not match or nil (%v vs %v)", app.OnMailerBeforeRecordLoginLinkSend(), app.onMailerBeforeRecordLoginLinkSend)
+	}
+
+	if app.onMailerAfterRecordLoginLinkSend != app.OnMailerAfterRecordLoginLinkSend() || app.OnMailerAfterRecordLoginLinkSend() == nil {
+		t.Fatalf("Getter app.OnMailerAfterRecordLoginLinkSend does not match or nil (%v vs %v)", app.OnMailerAfterRecordLoginLinkSend(), app.onMailerAfterRecordLoginLinkSend)
+	}
+
+	if app.onSmsBeforeRecordOtpSend != app.OnSmsBeforeRecordOtpSend() || app.OnSmsBeforeRecordOtpSend() == nil {
+		t.Fatalf("Getter app.OnSmsBeforeRecordOtpSend does not match or nil (%v vs %v)", app.OnSmsBeforeRecordOtpSend(), app.onSmsBeforeRecordOtpSend)
+	}
+
+	if app.onSmsAfterRecordOtpSend != app.OnSmsAfterRecordOtpSend() || app.OnSmsAfterRecordOtpSend() == nil {
+		t.Fatalf("Getter app.OnSmsAfterRecordOtpSend does not match or nil (%v vs %v)", app.OnSmsAfterRecordOtpSend(), app.onSmsAfterRecordOtpSend)
+	}
+
+	if app.onLoginLockout != app.OnLoginLockout() || app.OnLoginLockout() == nil {
+		t.Fatalf("Getter app.OnLoginLockout does not match or nil (%v vs %v)", app.OnLoginLockout(), app.onLoginLockout)
+	}
+
 	if app.onRealtimeConnectRequest != app.OnRealtimeConnectRequest() || app.OnRealtimeConnectRequest() == nil {
 		t.Fatalf("Getter app.OnRealtimeConnectRequest does not match or nil (%v vs %v)", app.OnRealtimeConnectRequest(), app.onRealtimeConnectRequest)
 	}
@@ -335,6 +417,10 @@ func TestBaseAppGetters(t *testing.T) {
 		t.Fatalf("Getter app.OnRecordAfterDeleteRequest does not match or nil (%v vs %v)", app.OnRecordAfterDeleteRequest(), app.onRecordAfterDeleteRequest)
 	}
 
+	if app.onRecordContentModeration != app.OnRecordContentModeration() || app.OnRecordContentModeration() == nil {
+		t.Fatalf("Getter app.OnRecordContentModeration does not match or nil (%v vs %v)", app.OnRecordContentModeration(), app.onRecordContentModeration)
+	}
+
 	if app.onRecordAuthRequest != app.OnRecordAuthRequest() || app.OnRecordAuthRequest() == nil {
 		t.Fatalf("Getter app.OnRecordAuthRequest does not match or nil (%v vs %v)", app.OnRecordAuthRequest(), app.onRecordAuthRequest)
 	}
@@ -439,6 +525,112 @@ func TestBaseAppNewMailClient(t *testing.T) {
 	}
 }
 
+func TestBaseAppNewSmsClient(t *testing.T) {
+	const testDataDir = "./pb_base_app_test_data_dir/"
+	defer os.RemoveAll(testDataDir)
+
+	app := NewBaseApp(&BaseAppConfig{
+		DataDir:       testDataDir,
+		EncryptionEnv: "pb_test_env",
+		IsDebug:       false,
+	})
+
+	if client := app.NewSmsClient(); client != nil {
+		t.Fatalf("Expected nil client for disabled sms gateway, got %v", client)
+	}
+
+	app.Settings().Sms.Enabled = true
+	app.Settings().Sms.Provider = sms.ProviderTwilio
+	app.Settings().Sms.ApiKey = "test_key"
+	app.Settings().Sms.ApiSecret = "test_secret"
+
+	client1 := app.NewSmsClient()
+	if val, ok := client1.(*sms.Twilio); !ok || val.AccountSid != "test_key" || val.AuthToken != "test_secret" {
+		t.Fatalf("Expected sms.Twilio instance, got %v", client1)
+	}
+
+	app.Settings().Sms.Provider = sms.ProviderVonage
+
+	client2 := app.NewSmsClient()
+	if val, ok := client2.(*sms.Vonage); !ok || val.ApiKey != "test_key" || val.ApiSecret != "test_secret" {
+		t.Fatalf("Expected sms.Vonage instance, got %v", client2)
+	}
+}
+
+func TestBaseAppNewErrorReporter(t *testing.T) {
+	const testDataDir = "./pb_base_app_test_data_dir/"
+	defer os.RemoveAll(testDataDir)
+
+	app := NewBaseApp(&BaseAppConfig{
+		DataDir:       testDataDir,
+		EncryptionEnv: "pb_test_env",
+		IsDebug:       false,
+	})
+
+	if client := app.NewErrorReporter(); client != nil {
+		t.Fatalf("Expected nil reporter, got %v", client)
+	}
+
+	app.Settings().ErrorReporting.Enabled = true
+	app.Settings().ErrorReporting.Dsn = "https://key@example.com/123"
+	app.Settings().ErrorReporting.Environment = "test"
+
+	client := app.NewErrorReporter()
+	val, ok := client.(*reporter.SentryClient)
+	if !ok {
+		t.Fatalf("Expected reporter.SentryClient instance, got %v", client)
+	}
+	if val.Dsn != "https://key@example.com/123" || val.Environment != "test" {
+		t.Fatalf("Expected the settings dsn and environment, got %v", val)
+	}
+}
+
+func TestBaseAppNewAlertNotifier(t *testing.T) {
+	const testDataDir = "./pb_base_app_test_data_dir/"
+	defer os.RemoveAll(testDataDir)
+
+	app := NewBaseApp(&BaseAppConfig{
+		DataDir:       testDataDir,
+		EncryptionEnv: "pb_test_env",
+		IsDebug:       false,
+	})
+
+	if notifier := app.NewAlertNotifier(); notifier != nil {
+		t.Fatalf("Expected nil notifier, got %v", notifier)
+	}
+
+	app.Settings().Alerts.Enabled = true
+	app.Settings().Alerts.Emails = []string{"test@example.com"}
+	app.Settings().Alerts.WebhookUrl = "https://example.com/hook"
+	app.Settings().Alerts.SlackWebhookUrl = "https://example.com/slack"
+
+	notifiers, ok := app.NewAlertNotifier().(alert.MultiNotifier)
+	if !ok {
+		t.Fatalf("Expected alert.MultiNotifier instance, got %v", notifiers)
+	}
+	if len(notifiers) != 3 {
+		t.Fatalf("Expected 3 notifiers, got %d", len(notifiers))
+	}
+	if _, ok := notifiers[0].(*alert.MailClient); !ok {
+		t.Fatalf("Expected alert.MailClient instance, got %v", notifiers[0])
+	}
+	if webhook, ok := notifiers[1].(*alert.WebhookClient); !ok || webhook.Secret != "" {
+		t.Fatalf("Expected alert.WebhookClient instance without secret, got %v", notifiers[1])
+	}
+	if _, ok := notifiers[2].(*alert.SlackClient); !ok {
+		t.Fatalf("Expected alert.SlackClient instance, got %v", notifiers[2])
+	}
+
+	// enabled signing
+	app.Settings().Signing.Enabled = true
+	app.Settings().Signing.Secret = "test_secret"
+
+	notifiers, _ = app.NewAlertNotifier().(alert.MultiNotifier)
+	if webhook, ok := notifiers[1].(*alert.WebhookClient); !ok || webhook.Secret != "test_secret" {
+		t.Fatalf("Expected alert.WebhookClient instance with secret, got %v", notifiers[1])
+	}
+}
+
 func TestBaseAppNewFilesystem(t *testing.T) {
 	const testDataDir = "./pb_base_app_test_data_dir/"
 	defer os.RemoveAll(testDataDir)
@@ -468,3 +660,29 @@ func TestBaseAppNewFilesystem(t *testing.T) {
 		t.Fatalf("Expected nil s3 filesystem, got %v", s3)
 	}
 }
+
+func TestBaseAppNewFilesystemSharded(t *testing.T) {
+	const testDataDir = "./pb_base_app_test_data_dir/"
+	defer os.RemoveAll(testDataDir)
+
+	app := NewBaseApp(&BaseAppConfig{
+		DataDir:        testDataDir,
+		EncryptionEnv:  "pb_test_env",
+		ShardedStorage: true,
+	})
+
+	fs, err := app.NewFilesystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	if err := fs.Upload([]byte("test"), "col/rec/test.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	shardedPath := filepath.Join(testDataDir, "storage", filesystem.ShardedKey("col/rec/test.txt"))
+	if _, err := os.Stat(shardedPath); err != nil {
+		t.Fatalf("Expected the file to be stored at %s: %v", shardedPath, err)
+	}
+}
@@ -1,6 +1,16 @@
 package core
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
 	"github.com/pocketbase/dbx"
 )
 
@@ -18,3 +28,102 @@ func initPragmas(db *dbx.DB) error {
 
 	return err
 }
+
+var attachedDatabaseNameRegex = regexp.MustCompile(`^\w+$`)
+
+// attachedDatabasePaths validates the provided attached database
+// names and returns their data dir file paths (name => file path).
+func attachedDatabasePaths(dataDir string, names []string) (map[string]string, error) {
+	result := make(map[string]string, len(names))
+
+	for _, name := range names {
+		if !attachedDatabaseNameRegex.MatchString(name) ||
+			strings.EqualFold(name, "main") ||
+			strings.EqualFold(name, "temp") ||
+			strings.EqualFold(name, "data") ||
+			strings.EqualFold(name, "logs") {
+			return nil, fmt.Errorf("Invalid attached database name %q.", name)
+		}
+
+		result[name] = filepath.Join(dataDir, name+".db")
+	}
+
+	return result, nil
+}
+
+// openDB opens a new db connections pool for the specified driver.
+//
+// The provided attached databases (name => file path) are attached
+// to each new pool connection since the sqlite ATTACH statement
+// applies only to the connection that executed it.
+func openDB(driverName string, dbPath string, attached map[string]string) (*dbx.DB, error) {
+	if len(attached) == 0 {
+		return dbx.Open(driverName, dbPath)
+	}
+
+	// resolve the registered driver (doesn't open a connection)
+	tempDB, err := sql.Open(driverName, dbPath)
+	if err != nil {
+		return nil, err
+	}
+	sqlDriver := tempDB.Driver()
+	tempDB.Close()
+
+	sqlDB := sql.OpenDB(&attachConnector{
+		driver:   sqlDriver,
+		dsn:      dbPath,
+		attached: attached,
+	})
+
+	return dbx.NewFromDB(sqlDB, driverName), nil
+}
+
+// attachConnector is a [driver.Connector] that attaches
+// the configured databases to each new connection.
+type attachConnector struct {
+	driver   driver.Driver
+	dsn      string
+	attached map[string]string
+}
+
+// Connect implements the [driver.Connector] interface.
+func (c *attachConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.driver.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	execer, ok := conn.(driver.ExecerContext)
+	if !ok {
+		conn.Close()
+		return nil, errors.New("The db driver doesn't support attaching databases.")
+	}
+
+	names := make([]string, 0, len(c.attached))
+	for name := range c.attached {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		_, err := execer.ExecContext(
+			ctx,
+			fmt.Sprintf("ATTACH DATABASE ? AS `%s`", name),
+			[]driver.NamedValue{{Ordinal: 1, Value: c.attached[name]}},
+		)
+		if err == nil {
+			_, err = execer.ExecContext(ctx, fmt.Sprintf("PRAGMA `%s`.journal_mode = WAL", name), nil)
+		}
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("Failed to attach database %q: %w", name, err)
+		}
+	}
+
+	return conn, nil
+}
+
+// Driver implements the [driver.Connector] interface.
+func (c *attachConnector) Driver() driver.Driver {
+	return c.driver
+}
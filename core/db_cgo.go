@@ -3,12 +3,29 @@
 package core
 
 import (
+	"database/sql"
+
+	"github.com/mattn/go-sqlite3"
 	"github.com/pocketbase/dbx"
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/pocketbase/pocketbase/tools/search"
 )
 
-func connectDB(dbPath string) (*dbx.DB, error) {
-	db, err := dbx.Open("sqlite3", dbPath)
+// cgoDriverName is the name of the mattn/go-sqlite3 driver
+// registered with the app custom db functions.
+const cgoDriverName = "pb_sqlite3"
+
+func init() {
+	sql.Register(cgoDriverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc(search.TimezoneFuncName, search.ConvertTimezone, true)
+		},
+	})
+
+	dbx.BuilderFuncMap[cgoDriverName] = dbx.NewSqliteBuilder
+}
+
+func connectDB(dbPath string, attached map[string]string) (*dbx.DB, error) {
+	db, err := openDB(cgoDriverName, dbPath, attached)
 	if err != nil {
 		return nil, err
 	}
@@ -3,12 +3,26 @@
 package core
 
 import (
+	"database/sql/driver"
+
 	"github.com/pocketbase/dbx"
-	_ "modernc.org/sqlite"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"modernc.org/sqlite"
 )
 
-func connectDB(dbPath string) (*dbx.DB, error) {
-	db, err := dbx.Open("sqlite", dbPath)
+func init() {
+	sqlite.MustRegisterDeterministicScalarFunction(
+		search.TimezoneFuncName,
+		2,
+		func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+			timezone, _ := args[1].(string)
+			return search.ConvertTimezone(args[0], timezone)
+		},
+	)
+}
+
+func connectDB(dbPath string, attached map[string]string) (*dbx.DB, error) {
+	db, err := openDB("sqlite", dbPath, attached)
 	if err != nil {
 		return nil, err
 	}
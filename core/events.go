@@ -5,8 +5,12 @@ import (
 	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/models/schema"
 	"github.com/pocketbase/pocketbase/models/settings"
+	"github.com/pocketbase/pocketbase/tools/alert"
 	"github.com/pocketbase/pocketbase/tools/mailer"
+	"github.com/pocketbase/pocketbase/tools/moderation"
+	"github.com/pocketbase/pocketbase/tools/reporter"
 	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/sms"
 	"github.com/pocketbase/pocketbase/tools/subscriptions"
 
 	"github.com/labstack/echo/v5"
@@ -23,6 +27,11 @@ type BootstrapEvent struct {
 type ServeEvent struct {
 	App    App
 	Router *echo.Echo
+
+	// Middlewares is the api middlewares registry that allows registering
+	// custom middlewares with priorities (relative to the built-in ones)
+	// and route group scoping.
+	Middlewares *MiddlewaresRegistry
 }
 
 type ApiErrorEvent struct {
@@ -30,6 +39,17 @@ type ApiErrorEvent struct {
 	Error       error
 }
 
+type ErrorReportEvent struct {
+	HttpContext echo.Context
+	Reporter    reporter.Reporter
+	Event       *reporter.Event
+}
+
+type AlertEvent struct {
+	Notifier alert.Notifier
+	Alert    *alert.Alert
+}
+
 // -------------------------------------------------------------------
 // Model DAO events data
 // -------------------------------------------------------------------
@@ -57,6 +77,35 @@ type MailerAdminEvent struct {
 	Meta       map[string]any
 }
 
+// -------------------------------------------------------------------
+// SMS events data
+// -------------------------------------------------------------------
+
+type SmsRecordEvent struct {
+	// SmsClient is the gateway that will be used to send the message
+	// (nil if there is no configured SMS gateway in the app settings).
+	SmsClient sms.SMSSender
+	Message   *sms.Message
+	Record    *models.Record
+	Meta      map[string]any
+}
+
+// -------------------------------------------------------------------
+// Login throttle events data
+// -------------------------------------------------------------------
+
+type LoginLockoutEvent struct {
+	// Collection is the auth collection of the failed password login
+	// (nil for the admin logins).
+	Collection *models.Collection
+
+	Identity string
+	Ip       string
+
+	// LoginAttempt is the just locked identity or ip failed login attempts counter.
+	LoginAttempt *models.LoginAttempt
+}
+
 // -------------------------------------------------------------------
 // Realtime API events data
 // -------------------------------------------------------------------
@@ -129,6 +178,12 @@ type RecordDeleteEvent struct {
 	Record      *models.Record
 }
 
+type RecordContentModerationEvent struct {
+	HttpContext echo.Context
+	Record      *models.Record
+	Verdict     *moderation.Verdict
+}
+
 // -------------------------------------------------------------------
 // Auth Record API events data
 // -------------------------------------------------------------------
@@ -0,0 +1,217 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/labstack/echo/v5"
+)
+
+// MiddlewareScope defines the routes group that a registered
+// api middleware is applied to.
+type MiddlewareScope string
+
+// List with the supported api middleware scopes.
+const (
+	// MiddlewareScopeAll applies the middleware to all routes.
+	MiddlewareScopeAll MiddlewareScope = ""
+
+	// MiddlewareScopeRecords applies the middleware only to the
+	// record crud and record auth routes (/api/collections/:collection/...).
+	MiddlewareScopeRecords MiddlewareScope = "records"
+
+	// MiddlewareScopeAdmins applies the middleware only to the admin routes (/api/admins/...).
+	MiddlewareScopeAdmins MiddlewareScope = "admins"
+
+	// MiddlewareScopeFiles applies the middleware only to the files routes (/api/files/...).
+	MiddlewareScopeFiles MiddlewareScope = "files"
+)
+
+// MiddlewareScopes returns all supported api middleware scopes.
+func MiddlewareScopes() []MiddlewareScope {
+	return []MiddlewareScope{
+		MiddlewareScopeAll,
+		MiddlewareScopeRecords,
+		MiddlewareScopeAdmins,
+		MiddlewareScopeFiles,
+	}
+}
+
+// Middleware defines a single registered api middleware.
+type Middleware struct {
+	// Id is an optional middleware identifier that could be used
+	// to replace or remove the middleware after its registration.
+	Id string
+
+	// Priority defines the middleware execution order in its scope
+	// (the lower priority middlewares are executed first).
+	//
+	// Middlewares with the same priority are executed in their registration order.
+	Priority int
+
+	// Scope restricts the middleware to a specific routes group.
+	//
+	// The scoped middlewares are executed after all MiddlewareScopeAll ones.
+	Scope MiddlewareScope
+
+	// Func is the middleware handler function.
+	Func echo.MiddlewareFunc
+}
+
+// MiddlewaresRegistry is a concurrent safe registry of api middlewares
+// with priority based ordering and route group scoping.
+type MiddlewaresRegistry struct {
+	mux    sync.RWMutex
+	items  []*Middleware
+	sorted map[MiddlewareScope][]echo.MiddlewareFunc
+}
+
+// NewMiddlewaresRegistry creates a new empty api middlewares registry.
+func NewMiddlewaresRegistry() *MiddlewaresRegistry {
+	return &MiddlewaresRegistry{}
+}
+
+// Add registers the provided middleware.
+//
+// If a middleware with the same non-empty Id is already
+// registered, it will be replaced with the new one.
+func (r *MiddlewaresRegistry) Add(m *Middleware) error {
+	if m == nil || m.Func == nil {
+		return errors.New("missing middleware handler function")
+	}
+
+	if !isValidMiddlewareScope(m.Scope) {
+		return fmt.Errorf("unknown middleware scope %q", m.Scope)
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.sorted = nil
+
+	if m.Id != "" {
+		for i, item := range r.items {
+			if item.Id == m.Id {
+				r.items[i] = m
+				return nil
+			}
+		}
+	}
+
+	r.items = append(r.items, m)
+
+	return nil
+}
+
+// Remove removes the middleware with the specified id (if registered).
+func (r *MiddlewaresRegistry) Remove(id string) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	for i := len(r.items) - 1; i >= 0; i-- {
+		if r.items[i].Id == id {
+			r.items = append(r.items[:i], r.items[i+1:]...)
+			r.sorted = nil
+		}
+	}
+}
+
+// Get returns the middleware with the specified id (or nil if missing).
+func (r *MiddlewaresRegistry) Get(id string) *Middleware {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	for _, item := range r.items {
+		if item.Id == id {
+			return item
+		}
+	}
+
+	return nil
+}
+
+// List returns the registered middlewares of the specified scope
+// sorted in their execution order.
+func (r *MiddlewaresRegistry) List(scope MiddlewareScope) []*Middleware {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	return r.list(scope)
+}
+
+// list returns the sorted scope middlewares (the caller must hold the registry lock).
+func (r *MiddlewaresRegistry) list(scope MiddlewareScope) []*Middleware {
+	result := make([]*Middleware, 0, len(r.items))
+	for _, item := range r.items {
+		if item.Scope == scope {
+			result = append(result, item)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Priority < result[j].Priority
+	})
+
+	return result
+}
+
+// Middleware returns a single echo middleware that executes on request
+// the registered middlewares of the specified scope.
+//
+// The registry is checked on every request so that the middlewares
+// could be registered even after the returned middleware is attached
+// to the router (eg. in [App.OnBeforeServe] hook).
+func (r *MiddlewaresRegistry) Middleware(scope MiddlewareScope) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			funcs := r.sortedFuncs(scope)
+
+			h := next
+			for i := len(funcs) - 1; i >= 0; i-- {
+				h = funcs[i](h)
+			}
+
+			return h(c)
+		}
+	}
+}
+
+// sortedFuncs returns the cached sorted handler functions of the scope middlewares.
+func (r *MiddlewaresRegistry) sortedFuncs(scope MiddlewareScope) []echo.MiddlewareFunc {
+	r.mux.RLock()
+	funcs, ok := r.sorted[scope]
+	r.mux.RUnlock()
+
+	if ok {
+		return funcs
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	list := r.list(scope)
+
+	funcs = make([]echo.MiddlewareFunc, len(list))
+	for i, m := range list {
+		funcs[i] = m.Func
+	}
+
+	if r.sorted == nil {
+		r.sorted = map[MiddlewareScope][]echo.MiddlewareFunc{}
+	}
+	r.sorted[scope] = funcs
+
+	return funcs
+}
+
+func isValidMiddlewareScope(scope MiddlewareScope) bool {
+	for _, s := range MiddlewareScopes() {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,95 @@
+package core_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func testMiddleware(name string, calls *[]string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			*calls = append(*calls, name)
+			return next(c)
+		}
+	}
+}
+
+func TestMiddlewaresRegistryAdd(t *testing.T) {
+	r := core.NewMiddlewaresRegistry()
+
+	if err := r.Add(&core.Middleware{Id: "test"}); err == nil {
+		t.Fatal("Expected error for missing middleware func")
+	}
+
+	calls := []string{}
+
+	if err := r.Add(&core.Middleware{Scope: "missing", Func: testMiddleware("a", &calls)}); err == nil {
+		t.Fatal("Expected error for unknown middleware scope")
+	}
+
+	if err := r.Add(&core.Middleware{Id: "test", Func: testMiddleware("a", &calls)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// replace
+	if err := r.Add(&core.Middleware{Id: "test", Priority: 10, Func: testMiddleware("b", &calls)}); err != nil {
+		t.Fatal(err)
+	}
+
+	list := r.List(core.MiddlewareScopeAll)
+	if len(list) != 1 {
+		t.Fatalf("Expected 1 middleware, got %d", len(list))
+	}
+
+	if m := r.Get("test"); m == nil || m.Priority != 10 {
+		t.Fatalf("Expected the middleware to be replaced, got %v", m)
+	}
+
+	r.Remove("test")
+
+	if m := r.Get("test"); m != nil {
+		t.Fatalf("Expected the middleware to be removed, got %v", m)
+	}
+}
+
+func TestMiddlewaresRegistryMiddleware(t *testing.T) {
+	r := core.NewMiddlewaresRegistry()
+
+	calls := []string{}
+
+	r.Add(&core.Middleware{Id: "a", Priority: 10, Func: testMiddleware("a", &calls)})
+	r.Add(&core.Middleware{Id: "b", Priority: -10, Func: testMiddleware("b", &calls)})
+	r.Add(&core.Middleware{Id: "c", Priority: 10, Func: testMiddleware("c", &calls)})
+	r.Add(&core.Middleware{Id: "d", Scope: core.MiddlewareScopeFiles, Func: testMiddleware("d", &calls)})
+
+	e := echo.New()
+	e.Use(r.Middleware(core.MiddlewareScopeAll))
+	e.GET("/test", func(c echo.Context) error {
+		calls = append(calls, "handler")
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	expected := "b,a,c,handler"
+	if v := strings.Join(calls, ","); v != expected {
+		t.Fatalf("Expected calls %q, got %q", expected, v)
+	}
+
+	// registered after the router setup
+	calls = []string{}
+	r.Add(&core.Middleware{Id: "e", Priority: 0, Func: testMiddleware("e", &calls)})
+	r.Remove("a")
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	expected = "b,e,c,handler"
+	if v := strings.Join(calls, ","); v != expected {
+		t.Fatalf("Expected calls %q, got %q", expected, v)
+	}
+}
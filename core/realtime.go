@@ -0,0 +1,132 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/resolvers"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/subscriptions"
+)
+
+// RealtimeClientAuthRecordKey is the subscriptions client store key
+// of the client auth record (the same as apis.ContextAuthRecordKey).
+const RealtimeClientAuthRecordKey = "authRecord"
+
+// Realtime sends server initiated messages to the app realtime subscribers.
+type Realtime struct {
+	app App
+}
+
+// NewRealtime creates and returns a new Realtime instance bound to the provided app.
+func NewRealtime(app App) *Realtime {
+	return &Realtime{app: app}
+}
+
+// Send sends a custom realtime message with the json encoded payload
+// to the clients subscribed to the provided topic.
+//
+// If targetFilter is not empty, the message is sent only to the clients
+// whose auth record matches the filter expression
+// (eg. `verified = true && role = "manager"`).
+// Guests and admins are not considered a match for non-empty filters.
+//
+// Returns the number of clients the message was sent to.
+func (r *Realtime) Send(topic string, payload any, targetFilter string) (int, error) {
+	if topic == "" {
+		return 0, errors.New("Missing realtime message topic.")
+	}
+
+	rawPayload, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	clients := []subscriptions.Client{}
+	for _, client := range r.app.SubscriptionsBroker().Clients() {
+		if client.HasSubscription(topic) {
+			clients = append(clients, client)
+		}
+	}
+
+	if targetFilter != "" && len(clients) > 0 {
+		clients, err = r.filterClients(clients, targetFilter)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	msg := subscriptions.Message{
+		Name: topic,
+		Data: string(rawPayload),
+	}
+
+	for _, client := range clients {
+		client.Channel() <- msg
+	}
+
+	return len(clients), nil
+}
+
+// filterClients returns only the clients whose
+// auth record matches the provided filter expression.
+func (r *Realtime) filterClients(clients []subscriptions.Client, filter string) ([]subscriptions.Client, error) {
+	// group the clients by their auth record collection
+	collections := map[string]*models.Collection{}
+	grouped := map[string][]subscriptions.Client{}
+	for _, client := range clients {
+		record, _ := client.Get(RealtimeClientAuthRecordKey).(*models.Record)
+		if record == nil || record.Collection() == nil {
+			continue
+		}
+
+		collection := record.Collection()
+		collections[collection.Id] = collection
+		grouped[collection.Id] = append(grouped[collection.Id], client)
+	}
+
+	result := make([]subscriptions.Client, 0, len(clients))
+
+	for collectionId, collectionClients := range grouped {
+		collection := collections[collectionId]
+
+		ids := make([]any, len(collectionClients))
+		for i, client := range collectionClients {
+			ids[i] = client.Get(RealtimeClientAuthRecordKey).(*models.Record).Id
+		}
+
+		resolver := resolvers.NewRecordFieldResolver(r.app.Dao(), collection, nil, true)
+
+		expr, err := search.FilterData(filter).BuildExpr(resolver)
+		if err != nil {
+			return nil, err
+		}
+
+		query := r.app.Dao().RecordQuery(collection).
+			Distinct(true).
+			AndWhere(dbx.In(collection.Name+".id", ids...)).
+			AndWhere(expr)
+
+		resolver.UpdateQuery(query)
+
+		rows := []dbx.NullStringMap{}
+		if err := query.All(&rows); err != nil {
+			return nil, err
+		}
+
+		matched := make(map[string]struct{}, len(rows))
+		for _, row := range rows {
+			matched[row["id"].String] = struct{}{}
+		}
+
+		for i, client := range collectionClients {
+			if _, ok := matched[ids[i].(string)]; ok {
+				result = append(result, client)
+			}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,113 @@
+package core_test
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/subscriptions"
+)
+
+func TestRealtimeSend(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	admin, err := app.Dao().FindAdminByEmail("test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// name -> auth model
+	clientsAuth := map[string]models.Model{
+		"guest": nil,
+		"admin": admin,
+	}
+	for _, id := range []string{"4q1xlclmfloku33", "oap640cot4yru2s", "bgs820n361vj1qd"} {
+		record, err := app.Dao().FindRecordById("users", id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		clientsAuth[id] = record
+	}
+
+	received := make(chan string, 10)
+
+	for name, auth := range clientsAuth {
+		client := subscriptions.NewDefaultClient()
+
+		switch v := auth.(type) {
+		case *models.Admin:
+			client.Set("admin", v)
+		case *models.Record:
+			client.Set(core.RealtimeClientAuthRecordKey, v)
+		}
+
+		// subscribe all clients except one to the test topic
+		if name == "bgs820n361vj1qd" {
+			client.Subscribe("other")
+		} else {
+			client.Subscribe("test")
+		}
+
+		app.SubscriptionsBroker().Register(client)
+
+		go func(name string, client subscriptions.Client) {
+			for msg := range client.Channel() {
+				if msg.Name != "test" || msg.Data != `{"progress":50}` {
+					received <- "invalid message " + msg.Name + " " + msg.Data
+					continue
+				}
+				received <- name
+			}
+		}(name, client)
+	}
+
+	scenarios := []struct {
+		name          string
+		topic         string
+		filter        string
+		expectError   bool
+		expectedNames []string
+	}{
+		{"missing topic", "", "", true, nil},
+		{"invalid filter", "test", "verified ~~ 1", true, nil},
+		{"topic without subscribers", "missing", "", false, nil},
+		{"without filter", "test", "", false, []string{"4q1xlclmfloku33", "admin", "guest", "oap640cot4yru2s"}},
+		{"with filter", "test", "verified = true", false, []string{"oap640cot4yru2s"}},
+		{"with filter without matches", "test", "email = 'missing@example.com'", false, nil},
+	}
+
+	for _, s := range scenarios {
+		total, err := app.Realtime().Send(s.topic, map[string]any{"progress": 50}, s.filter)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if total != len(s.expectedNames) {
+			t.Errorf("[%s] Expected %d sent messages, got %d", s.name, len(s.expectedNames), total)
+			continue
+		}
+
+		names := []string{}
+		for i := 0; i < total; i++ {
+			select {
+			case name := <-received:
+				names = append(names, name)
+			case <-time.After(time.Second):
+				t.Fatalf("[%s] Timeout waiting for message %d", s.name, i)
+			}
+		}
+		sort.Strings(names)
+
+		if strings.Join(names, ",") != strings.Join(s.expectedNames, ",") {
+			t.Errorf("[%s] Expected receivers %v, got %v", s.name, s.expectedNames, names)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package daos
+
+import (
+	"errors"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// AnonymousAuthQuery returns a new AnonymousAuth select query.
+func (dao *Dao) AnonymousAuthQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.AnonymousAuth{})
+}
+
+// FindAnonymousAuthByRecord returns the AnonymousAuth model
+// of the provided auth record.
+//
+// Returns [sql.ErrNoRows] if the auth record is not anonymous.
+func (dao *Dao) FindAnonymousAuthByRecord(authRecord *models.Record) (*models.AnonymousAuth, error) {
+	model := &models.AnonymousAuth{}
+
+	err := dao.AnonymousAuthQuery().
+		AndWhere(dbx.HashExp{
+			"collectionId": authRecord.Collection().Id,
+			"recordId":     authRecord.Id,
+		}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// IsAnonymousAuthRecord checks whether the provided auth record is anonymous.
+func (dao *Dao) IsAnonymousAuthRecord(authRecord *models.Record) bool {
+	var exists bool
+
+	err := dao.AnonymousAuthQuery().
+		Select("count(*)").
+		AndWhere(dbx.HashExp{
+			"collectionId": authRecord.Collection().Id,
+			"recordId":     authRecord.Id,
+		}).
+		Limit(1).
+		Row(&exists)
+
+	return err == nil && exists
+}
+
+// SaveAnonymousAuth upserts the provided AnonymousAuth model.
+func (dao *Dao) SaveAnonymousAuth(model *models.AnonymousAuth) error {
+	if model.CollectionId == "" || model.RecordId == "" {
+		return errors.New("Missing anonymous auth record.")
+	}
+
+	return dao.Save(model)
+}
+
+// DeleteAnonymousAuth deletes the provided AnonymousAuth model.
+func (dao *Dao) DeleteAnonymousAuth(model *models.AnonymousAuth) error {
+	return dao.Delete(model)
+}
+
+// DeleteAuthRecordAnonymousAuth deletes the anonymous mark
+// of the provided auth record (if any).
+func (dao *Dao) DeleteAuthRecordAnonymousAuth(authRecord *models.Record) error {
+	_, err := dao.DB().Delete((&models.AnonymousAuth{}).TableName(), dbx.HashExp{
+		"collectionId": authRecord.Collection().Id,
+		"recordId":     authRecord.Id,
+	}).Execute()
+
+	return err
+}
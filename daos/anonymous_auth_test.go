@@ -0,0 +1,94 @@
+package daos_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestAnonymousAuthQuery(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	expected := "SELECT {{_anonymousAuths}}.* FROM `_anonymousAuths`"
+
+	sql := app.Dao().AnonymousAuthQuery().Build().SQL()
+	if sql != expected {
+		t.Errorf("Expected sql %s, got %s", expected, sql)
+	}
+}
+
+func TestAnonymousAuthSaveFindAndDelete(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.Dao().FindRecordById("users", "bgs820n361vj1qd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if app.Dao().IsAnonymousAuthRecord(record) {
+		t.Fatal("Expected the record to not be anonymous")
+	}
+
+	if err := app.Dao().SaveAnonymousAuth(&models.AnonymousAuth{}); err == nil {
+		t.Fatal("Expected error for missing auth record")
+	}
+
+	model := &models.AnonymousAuth{
+		CollectionId: record.Collection().Id,
+		RecordId:     record.Id,
+	}
+	if err := app.Dao().SaveAnonymousAuth(model); err != nil {
+		t.Fatal(err)
+	}
+
+	if !app.Dao().IsAnonymousAuthRecord(record) {
+		t.Fatal("Expected the record to be anonymous")
+	}
+
+	found, err := app.Dao().FindAnonymousAuthByRecord(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Id != model.Id {
+		t.Fatalf("Expected anonymous auth %q, got %q", model.Id, found.Id)
+	}
+
+	if err := app.Dao().DeleteAnonymousAuth(found); err != nil {
+		t.Fatal(err)
+	}
+
+	if app.Dao().IsAnonymousAuthRecord(record) {
+		t.Fatal("Expected the record to not be anonymous after the delete")
+	}
+}
+
+func TestDeleteAuthRecordAnonymousAuth(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.Dao().FindRecordById("users", "bgs820n361vj1qd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().SaveAnonymousAuth(&models.AnonymousAuth{
+		CollectionId: record.Collection().Id,
+		RecordId:     record.Id,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the anonymous mark should be removed together with the record
+	if err := app.Dao().DeleteRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	var total int
+	app.Dao().AnonymousAuthQuery().Select("count(*)").Row(&total)
+	if total != 0 {
+		t.Fatalf("Expected the anonymous auth to be deleted, found %d", total)
+	}
+}
@@ -0,0 +1,76 @@
+package daos
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// ApiKeyQuery returns a new ApiKey select query.
+func (dao *Dao) ApiKeyQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.ApiKey{})
+}
+
+// FindApiKeyById finds a single ApiKey model by its id.
+func (dao *Dao) FindApiKeyById(id string) (*models.ApiKey, error) {
+	model := &models.ApiKey{}
+
+	err := dao.ApiKeyQuery().
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// FindApiKeyByKey finds a single ApiKey model by its plain key value.
+func (dao *Dao) FindApiKeyByKey(key string) (*models.ApiKey, error) {
+	key = strings.TrimSpace(key)
+	if !strings.HasPrefix(key, models.ApiKeyPrefix) {
+		return nil, errors.New("Invalid api key format.")
+	}
+
+	model := &models.ApiKey{}
+
+	err := dao.ApiKeyQuery().
+		AndWhere(dbx.HashExp{"keyHash": models.HashApiKey(key)}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// FindAllApiKeys returns all ApiKey models (ordered by their name).
+func (dao *Dao) FindAllApiKeys() ([]*models.ApiKey, error) {
+	keys := []*models.ApiKey{}
+
+	err := dao.ApiKeyQuery().
+		OrderBy("name ASC").
+		All(&keys)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// SaveApiKey upserts the provided ApiKey model.
+func (dao *Dao) SaveApiKey(model *models.ApiKey) error {
+	return dao.Save(model)
+}
+
+// DeleteApiKey deletes the provided ApiKey model.
+func (dao *Dao) DeleteApiKey(model *models.ApiKey) error {
+	return dao.Delete(model)
+}
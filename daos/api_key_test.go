@@ -0,0 +1,65 @@
+package daos_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestApiKeys(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dao := app.Dao()
+
+	apiKey1 := &models.ApiKey{Name: "test1", Scopes: types.JsonMap{"sz5l5z67tg7gku0": "read"}}
+	key1 := apiKey1.RefreshKey()
+	if err := dao.SaveApiKey(apiKey1); err != nil {
+		t.Fatal(err)
+	}
+
+	apiKey2 := &models.ApiKey{Name: "test0"}
+	apiKey2.RefreshKey()
+	if err := dao.SaveApiKey(apiKey2); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := dao.FindApiKeyById(apiKey1.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := dao.FindApiKeyById("missing"); err == nil {
+		t.Fatal("Expected error for missing api key id")
+	}
+
+	found, err := dao.FindApiKeyByKey(key1)
+	if err != nil || found.Id != apiKey1.Id {
+		t.Fatalf("Expected to find the api key by its plain key, got %v (%v)", found, err)
+	}
+	if scope, _ := found.Scope("sz5l5z67tg7gku0"); scope != "read" {
+		t.Fatalf("Expected the read scope to be loaded, got %q", scope)
+	}
+
+	for _, key := range []string{"", apiKey1.KeyHash, key1[len(models.ApiKeyPrefix):], models.ApiKeyPrefix + "missing"} {
+		if _, err := dao.FindApiKeyByKey(key); err == nil {
+			t.Fatalf("Expected error for key %q", key)
+		}
+	}
+
+	all, err := dao.FindAllApiKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[0].Id != apiKey2.Id || all[1].Id != apiKey1.Id {
+		t.Fatalf("Expected the api keys ordered by name, got %v", all)
+	}
+
+	if err := dao.DeleteApiKey(apiKey1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dao.FindApiKeyByKey(key1); err == nil {
+		t.Fatal("Expected the api key to be deleted")
+	}
+}
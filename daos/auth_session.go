@@ -0,0 +1,130 @@
+package daos
+
+import (
+	"errors"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// AuthSessionQuery returns a new AuthSession select query.
+func (dao *Dao) AuthSessionQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.AuthSession{})
+}
+
+// FindAuthSessionById finds a single AuthSession model by its id.
+func (dao *Dao) FindAuthSessionById(id string) (*models.AuthSession, error) {
+	model := &models.AuthSession{}
+
+	err := dao.AuthSessionQuery().
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// FindAuthSessionByToken finds a single AuthSession model
+// by its plain refresh token value.
+func (dao *Dao) FindAuthSessionByToken(token string) (*models.AuthSession, error) {
+	if token == "" {
+		return nil, errors.New("Missing session refresh token.")
+	}
+
+	model := &models.AuthSession{}
+
+	err := dao.AuthSessionQuery().
+		AndWhere(dbx.HashExp{"tokenHash": models.HashAuthSessionToken(token)}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// FindAllAuthSessionsByRecord returns all not expired AuthSession
+// models of the provided auth record (ordered by their last use).
+func (dao *Dao) FindAllAuthSessionsByRecord(authRecord *models.Record) ([]*models.AuthSession, error) {
+	sessions := []*models.AuthSession{}
+
+	err := dao.AuthSessionQuery().
+		AndWhere(dbx.HashExp{
+			"collectionId": authRecord.Collection().Id,
+			"recordId":     authRecord.Id,
+		}).
+		AndWhere(dbx.NewExp("[[expires]] > {:now}", dbx.Params{"now": types.NowDateTime().String()})).
+		OrderBy("updated DESC").
+		All(&sessions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// FindAuthSessionByRecordAndId returns the AuthSession model
+// with the specified id of the provided auth record.
+func (dao *Dao) FindAuthSessionByRecordAndId(authRecord *models.Record, id string) (*models.AuthSession, error) {
+	model := &models.AuthSession{}
+
+	err := dao.AuthSessionQuery().
+		AndWhere(dbx.HashExp{
+			"id":           id,
+			"collectionId": authRecord.Collection().Id,
+			"recordId":     authRecord.Id,
+		}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// SaveAuthSession upserts the provided AuthSession model.
+func (dao *Dao) SaveAuthSession(model *models.AuthSession) error {
+	if model.CollectionId == "" || model.RecordId == "" || model.TokenHash == "" || model.KeyHash == "" {
+		return errors.New("Missing required AuthSession fields.")
+	}
+
+	return dao.Save(model)
+}
+
+// DeleteAuthSession deletes the provided AuthSession model.
+func (dao *Dao) DeleteAuthSession(model *models.AuthSession) error {
+	return dao.Delete(model)
+}
+
+// DeleteAuthRecordSessions deletes all AuthSession
+// models of the provided auth record.
+func (dao *Dao) DeleteAuthRecordSessions(authRecord *models.Record) error {
+	_, err := dao.DB().Delete((&models.AuthSession{}).TableName(), dbx.HashExp{
+		"collectionId": authRecord.Collection().Id,
+		"recordId":     authRecord.Id,
+	}).Execute()
+
+	return err
+}
+
+// DeleteExpiredAuthSessions deletes all AuthSession models
+// that have expired before expiredBefore.
+func (dao *Dao) DeleteExpiredAuthSessions(expiredBefore time.Time) error {
+	formattedDate := expiredBefore.UTC().Format(types.DefaultDateLayout)
+	expr := dbx.NewExp("[[expires]] <= {:date}", dbx.Params{"date": formattedDate})
+
+	_, err := dao.DB().Delete((&models.AuthSession{}).TableName(), expr).Execute()
+
+	return err
+}
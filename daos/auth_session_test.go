@@ -0,0 +1,251 @@
+package daos_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestAuthSessionQuery(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	expected := "SELECT {{_authSessions}}.* FROM `_authSessions`"
+
+	sql := app.Dao().AuthSessionQuery().Build().SQL()
+	if sql != expected {
+		t.Errorf("Expected sql %s, got %s", expected, sql)
+	}
+}
+
+func TestFindAuthSessionById(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		id          string
+		expectError bool
+	}{
+		{"", true},
+		{"missing", true},
+		{"authsess0000001", false},
+		{"authsess0000003", false}, // expired
+	}
+
+	for i, s := range scenarios {
+		session, err := app.Dao().FindAuthSessionById(s.id)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if !hasErr && session.Id != s.id {
+			t.Errorf("(%d) Expected session %q, got %q", i, s.id, session.Id)
+		}
+	}
+}
+
+func TestFindAuthSessionByToken(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		token      string
+		expectedId string
+	}{
+		{"", ""},
+		{"missing", ""},
+		{"test_refresh_token_1", "authsess0000001"},
+		{" test_refresh_token_2 ", "authsess0000002"},
+	}
+
+	for i, s := range scenarios {
+		session, err := app.Dao().FindAuthSessionByToken(s.token)
+
+		hasErr := err != nil
+		expectErr := s.expectedId == ""
+		if hasErr != expectErr {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, expectErr, hasErr, err)
+			continue
+		}
+
+		if !hasErr && session.Id != s.expectedId {
+			t.Errorf("(%d) Expected session %q, got %q", i, s.expectedId, session.Id)
+		}
+	}
+}
+
+func TestFindAllAuthSessionsByRecord(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		userId      string
+		expectedIds []string
+	}{
+		{"bgs820n361vj1qd", []string{}},
+		{"4q1xlclmfloku33", []string{"authsess0000001"}}, // authsess0000003 is expired
+		{"oap640cot4yru2s", []string{"authsess0000002"}},
+	}
+
+	for i, s := range scenarios {
+		record, err := app.Dao().FindRecordById("users", s.userId)
+		if err != nil {
+			t.Errorf("(%d) Unexpected record fetch error %v", i, err)
+			continue
+		}
+
+		sessions, err := app.Dao().FindAllAuthSessionsByRecord(record)
+		if err != nil {
+			t.Errorf("(%d) Unexpected sessions fetch error %v", i, err)
+			continue
+		}
+
+		if len(sessions) != len(s.expectedIds) {
+			t.Errorf("(%d) Expected %d sessions, got %d", i, len(s.expectedIds), len(sessions))
+			continue
+		}
+
+		for j, session := range sessions {
+			if session.Id != s.expectedIds[j] {
+				t.Errorf("(%d) Expected session %q, got %q", i, s.expectedIds[j], session.Id)
+			}
+		}
+	}
+}
+
+func TestFindAuthSessionByRecordAndId(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		id          string
+		expectError bool
+	}{
+		{"", true},
+		{"missing", true},
+		{"authsess0000002", true}, // other user
+		{"authsess0000001", false},
+	}
+
+	for i, s := range scenarios {
+		session, err := app.Dao().FindAuthSessionByRecordAndId(user, s.id)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if !hasErr && session.Id != s.id {
+			t.Errorf("(%d) Expected session %q, got %q", i, s.id, session.Id)
+		}
+	}
+}
+
+func TestSaveAuthSession(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.Dao().FindRecordById("users", "bgs820n361vj1qd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// save with missing required fields
+	invalid := &models.AuthSession{CollectionId: user.Collection().Id, RecordId: user.Id}
+	if err := app.Dao().SaveAuthSession(invalid); err == nil {
+		t.Fatal("Expected error for missing required fields, got nil")
+	}
+
+	session := &models.AuthSession{}
+	session.SetAuthRecord(user)
+	token := session.RefreshToken()
+
+	if err := app.Dao().SaveAuthSession(session); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := app.Dao().FindAuthSessionByToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if found.Id != session.Id || !found.HasAuthRecord(user) {
+		t.Fatalf("Expected the saved session, got %v", found)
+	}
+}
+
+func TestDeleteAuthSession(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	session, err := app.Dao().FindAuthSessionById("authsess0000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().DeleteAuthSession(session); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindAuthSessionById("authsess0000001"); err == nil {
+		t.Fatal("Expected the session to be deleted")
+	}
+}
+
+func TestDeleteAuthRecordSessions(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().DeleteRecord(user); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"authsess0000001", "authsess0000003"} {
+		if _, err := app.Dao().FindAuthSessionById(id); err == nil {
+			t.Fatalf("Expected session %q to be deleted", id)
+		}
+	}
+
+	// the other records sessions should remain
+	if _, err := app.Dao().FindAuthSessionById("authsess0000002"); err != nil {
+		t.Fatalf("Expected the other user session to remain, got %v", err)
+	}
+}
+
+func TestDeleteExpiredAuthSessions(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	if err := app.Dao().DeleteExpiredAuthSessions(time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	total := 0
+	if err := app.Dao().AuthSessionQuery().Select("count(*)").Row(&total); err != nil {
+		t.Fatal(err)
+	}
+
+	if total != 2 {
+		t.Fatalf("Expected 2 remaining sessions, got %d", total)
+	}
+
+	if _, err := app.Dao().FindAuthSessionById("authsess0000003"); err == nil {
+		t.Fatal("Expected the expired session to be deleted")
+	}
+}
@@ -12,6 +12,10 @@ import (
 
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/models/settings"
+	"github.com/pocketbase/pocketbase/tools/inflector"
+	"github.com/pocketbase/pocketbase/tools/security"
 	"golang.org/x/sync/semaphore"
 )
 
@@ -39,6 +43,21 @@ type Dao struct {
 	concurrentDB    dbx.Builder
 	nonconcurrentDB dbx.Builder
 
+	// master key used to derive the collections records encryption keys
+	encryptionKey string
+
+	// returns the declared auth records preference keys
+	preferenceKeysFunc func() []settings.PreferenceKey
+
+	// returns the app level rule macros (name => rule)
+	ruleMacrosFunc func() map[string]string
+
+	// optional source used for the generated ids and query placeholders
+	randomSource security.RandomSource
+
+	// optional db identifiers normalization strategy
+	columnNamer inflector.ColumnNamer
+
 	// @todo delete after removing Block and Continue
 	sem *semaphore.Weighted
 	mux sync.RWMutex
@@ -74,6 +93,100 @@ func (dao *Dao) NonconcurrentDB() dbx.Builder {
 	return dao.nonconcurrentDB
 }
 
+// EncryptionKey returns the dao records encryption master key.
+func (dao *Dao) EncryptionKey() string {
+	return dao.encryptionKey
+}
+
+// PreferenceKeys returns the declared auth records preference keys
+// (see [Dao.SetPreferenceKeysFunc]).
+func (dao *Dao) PreferenceKeys() []settings.PreferenceKey {
+	if dao.preferenceKeysFunc == nil {
+		return nil
+	}
+
+	return dao.preferenceKeysFunc()
+}
+
+// SetPreferenceKeysFunc sets the function that returns the declared
+// auth records preference keys (usually loaded from the app settings).
+func (dao *Dao) SetPreferenceKeysFunc(fn func() []settings.PreferenceKey) {
+	dao.preferenceKeysFunc = fn
+}
+
+// RuleMacros returns the app level rule macros (name => rule)
+// (see [Dao.SetRuleMacrosFunc]).
+func (dao *Dao) RuleMacros() map[string]string {
+	if dao.ruleMacrosFunc == nil {
+		return nil
+	}
+
+	return dao.ruleMacrosFunc()
+}
+
+// SetRuleMacrosFunc sets the function that returns the app level
+// rule macros (usually loaded from the app settings).
+func (dao *Dao) SetRuleMacrosFunc(fn func() map[string]string) {
+	dao.ruleMacrosFunc = fn
+}
+
+// RandomSource returns the dao random source (if any) used for
+// the auto generated model ids and query placeholders.
+func (dao *Dao) RandomSource() security.RandomSource {
+	return dao.randomSource
+}
+
+// SetRandomSource sets the random source used for the auto generated
+// model ids and query placeholders (eg. a seeded source for deterministic tests).
+//
+// Set to nil to restore the default random generators.
+func (dao *Dao) SetRandomSource(source security.RandomSource) {
+	dao.randomSource = source
+}
+
+// ColumnNamer returns the dao db identifiers normalization strategy
+// (fallbacks to [inflector.DefaultColumnNamer]).
+func (dao *Dao) ColumnNamer() inflector.ColumnNamer {
+	if dao.columnNamer == nil {
+		return inflector.DefaultColumnNamer
+	}
+
+	return dao.columnNamer
+}
+
+// SetColumnNamer sets the strategy used to normalize the field
+// and table names in the generated record queries.
+//
+// Set to nil to restore the default [inflector.Columnify] normalization.
+func (dao *Dao) SetColumnNamer(namer inflector.ColumnNamer) {
+	dao.columnNamer = namer
+}
+
+// Columnify normalizes str into a db identifier
+// using the dao column naming strategy.
+func (dao *Dao) Columnify(str string) string {
+	return dao.ColumnNamer().Columnify(str)
+}
+
+// RandomString generates a random string with the specified length
+// using the dao random source (fallbacks to security.PseudorandomString).
+//
+// It is intended to be used for non-secret values like query placeholders.
+func (dao *Dao) RandomString(length int) string {
+	return security.PseudorandomStringWithSource(dao.randomSource, length)
+}
+
+// SetEncryptionKey sets the master key used to derive the records
+// encryption keys of the collections with enabled encryption
+// (must be 32 chars, usually loaded from the app encryption env).
+//
+// Only the length of the key is checked, so it is expected to be
+// generated with a cryptographically secure random source
+// (eg. "openssl rand -base64 24").
+func (dao *Dao) SetEncryptionKey(key string) {
+	dao.encryptionKey = key
+}
+
 // Deprecated: Will be removed in the next releases. Use [Dao.NonconcurrentDB()] instead.
 //
 // Block acquires a lock and blocks all other go routines that uses
@@ -148,6 +261,11 @@ func (dao *Dao) RunInTransaction(fn func(txDao *Dao) error) error {
 		// ---
 		// create a new dao with the same hooks to avoid semaphore deadlock when nesting
 		txDao := New(txOrDB)
+		txDao.encryptionKey = dao.encryptionKey
+		txDao.preferenceKeysFunc = dao.preferenceKeysFunc
+		txDao.ruleMacrosFunc = dao.ruleMacrosFunc
+		txDao.randomSource = dao.randomSource
+		txDao.columnNamer = dao.columnNamer
 		txDao.BeforeCreateFunc = dao.BeforeCreateFunc
 		txDao.BeforeUpdateFunc = dao.BeforeUpdateFunc
 		txDao.BeforeDeleteFunc = dao.BeforeDeleteFunc
@@ -161,6 +279,11 @@ func (dao *Dao) RunInTransaction(fn func(txDao *Dao) error) error {
 
 		txError := txOrDB.Transactional(func(tx *dbx.Tx) error {
 			txDao := New(tx)
+			txDao.encryptionKey = dao.encryptionKey
+			txDao.preferenceKeysFunc = dao.preferenceKeysFunc
+			txDao.ruleMacrosFunc = dao.ruleMacrosFunc
+			txDao.randomSource = dao.randomSource
+			txDao.columnNamer = dao.columnNamer
 
 			if dao.BeforeCreateFunc != nil {
 				txDao.BeforeCreateFunc = func(eventDao *Dao, m models.Model) error {
@@ -276,15 +399,37 @@ func (dao *Dao) update(m models.Model) error {
 	if v, ok := any(m).(models.ColumnValueMapper); ok {
 		dataMap := v.ColumnValueMap()
 
-		_, err := dao.NonconcurrentDB().Update(
+		if record, ok := m.(*models.Record); ok {
+			if err := dao.encryptRecordColumns(record, dataMap); err != nil {
+				return err
+			}
+		}
+
+		where := dbx.HashExp{"id": m.GetId()}
+
+		// compare-and-swap the record version
+		record, versioned := isVersionedRecord(m)
+		if versioned {
+			where[schema.FieldNameVersion] = record.Version()
+			dataMap[schema.FieldNameVersion] = record.Version() + 1
+		}
+
+		result, err := dao.NonconcurrentDB().Update(
 			m.TableName(),
 			dataMap,
-			dbx.HashExp{"id": m.GetId()},
+			where,
 		).Execute()
 
 		if err != nil {
 			return err
 		}
+
+		if versioned {
+			if affected, _ := result.RowsAffected(); affected == 0 {
+				return ErrRecordVersionConflict
+			}
+			record.SetVersion(record.Version() + 1)
+		}
 	} else {
 		if err := dao.NonconcurrentDB().Model(m).Update(); err != nil {
 			return err
@@ -298,10 +443,21 @@ func (dao *Dao) update(m models.Model) error {
 	return nil
 }
 
+// refreshModelId generates and sets a new model id
+// using the dao random source (if any).
+func (dao *Dao) refreshModelId(m models.Model) {
+	if dao.randomSource == nil {
+		m.RefreshId()
+		return
+	}
+
+	m.SetId(security.RandomStringWithSource(dao.randomSource, models.DefaultIdLength, models.DefaultIdAlphabet))
+}
+
 func (dao *Dao) create(m models.Model) error {
 	if !m.HasId() {
 		// auto generate id
-		m.RefreshId()
+		dao.refreshModelId(m)
 	}
 
 	// mark the model as "new" since the model now always has an ID
@@ -327,10 +483,25 @@ func (dao *Dao) create(m models.Model) error {
 			dataMap["id"] = m.GetId()
 		}
 
+		if record, ok := m.(*models.Record); ok {
+			if err := dao.encryptRecordColumns(record, dataMap); err != nil {
+				return err
+			}
+		}
+
+		record, versioned := isVersionedRecord(m)
+		if versioned {
+			dataMap[schema.FieldNameVersion] = 1
+		}
+
 		_, err := dao.NonconcurrentDB().Insert(m.TableName(), dataMap).Execute()
 		if err != nil {
 			return err
 		}
+
+		if versioned {
+			record.SetVersion(1)
+		}
 	} else {
 		if err := dao.NonconcurrentDB().Model(m).Insert(); err != nil {
 			return err
@@ -356,6 +527,11 @@ Retry:
 		// assign new Dao without the before hooks to avoid triggering
 		// the already fired before callbacks multiple times
 		retryDao = NewMultiDB(dao.concurrentDB, dao.nonconcurrentDB)
+		retryDao.encryptionKey = dao.encryptionKey
+		retryDao.preferenceKeysFunc = dao.preferenceKeysFunc
+		retryDao.ruleMacrosFunc = dao.ruleMacrosFunc
+		retryDao.randomSource = dao.randomSource
+		retryDao.columnNamer = dao.columnNamer
 		retryDao.AfterCreateFunc = dao.AfterCreateFunc
 		retryDao.AfterUpdateFunc = dao.AfterUpdateFunc
 		retryDao.AfterDeleteFunc = dao.AfterDeleteFunc
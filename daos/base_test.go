@@ -2,11 +2,14 @@ package daos_test
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/pocketbase/pocketbase/daos"
 	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/inflector"
+	"github.com/pocketbase/pocketbase/tools/security"
 )
 
 func TestNew(t *testing.T) {
@@ -177,6 +180,93 @@ func TestDaoSaveCreate(t *testing.T) {
 	}
 }
 
+func TestDaoColumnNamer(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	dao := testApp.Dao()
+
+	if result := dao.Columnify("test!abc"); result != "testabc" {
+		t.Fatalf("Expected the default Columnify normalization, got %q", result)
+	}
+
+	// map the "name" identifier to the demo2 "title" column
+	dao.SetColumnNamer(inflector.ColumnNamerFunc(func(str string) string {
+		if str == "name" {
+			return "title"
+		}
+		return inflector.Columnify(str)
+	}))
+
+	record, err := dao.FindFirstRecordByData("demo2", "name", "test1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Id != "llvuca81nly1qls" {
+		t.Fatalf("Expected record llvuca81nly1qls, got %q", record.Id)
+	}
+
+	// the namer should be inherited by the transaction dao
+	txErr := dao.RunInTransaction(func(txDao *daos.Dao) error {
+		if result := txDao.Columnify("name"); result != "title" {
+			t.Fatalf("Expected the tx dao to use the custom namer, got %q", result)
+		}
+		return nil
+	})
+	if txErr != nil {
+		t.Fatal(txErr)
+	}
+
+	// restore the default namer
+	dao.SetColumnNamer(nil)
+
+	if result := dao.Columnify("name"); result != "name" {
+		t.Fatalf("Expected the default namer to be restored, got %q", result)
+	}
+}
+
+func TestDaoSaveCreateWithRandomSource(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	ids := make([]string, 2)
+
+	for i := range ids {
+		testApp.Dao().SetRandomSource(security.NewSeededRandomSource(123))
+
+		model := &models.Param{Key: fmt.Sprintf("test_random_source_%d", i)}
+		if err := testApp.Dao().Save(model); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(model.Id) != models.DefaultIdLength {
+			t.Fatalf("Expected id with length %d, got %q", models.DefaultIdLength, model.Id)
+		}
+
+		if err := testApp.Dao().Delete(model); err != nil {
+			t.Fatal(err)
+		}
+
+		ids[i] = model.Id
+	}
+
+	if ids[0] != ids[1] {
+		t.Fatalf("Expected the same seeded source to generate the same ids, got %v", ids)
+	}
+
+	// restore the default generator
+	testApp.Dao().SetRandomSource(nil)
+
+	model := &models.Param{Key: "test_random_source_default"}
+	if err := testApp.Dao().Save(model); err != nil {
+		t.Fatal(err)
+	}
+
+	if model.Id == ids[0] {
+		t.Fatalf("Expected a different id than %q", ids[0])
+	}
+}
+
 func TestDaoSaveWithInsertId(t *testing.T) {
 	testApp, _ := tests.NewTestApp()
 	defer testApp.Cleanup()
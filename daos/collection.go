@@ -11,6 +11,10 @@ import (
 	"github.com/pocketbase/pocketbase/tools/list"
 )
 
+// ErrCollectionVersionConflict is returned when trying to update a collection
+// whose version doesn't match with the stored one (aka. it was modified in the meantime).
+var ErrCollectionVersionConflict = errors.New("the collection was modified in the meantime (version conflict)")
+
 // CollectionQuery returns a new Collection select query.
 func (dao *Dao) CollectionQuery() *dbx.SelectQuery {
 	return dao.ModelQuery(&models.Collection{})
@@ -111,7 +115,8 @@ func (dao *Dao) FindCollectionReferences(collection *models.Collection, excludeI
 }
 
 // DeleteCollection deletes the provided Collection model.
-// This method automatically deletes the related collection records table.
+// This method automatically deletes the related collection records table
+// (the externally managed tables are preserved).
 //
 // NB! The collection cannot be deleted, if:
 // - is system collection (aka. collection.System is true)
@@ -133,7 +138,14 @@ func (dao *Dao) DeleteCollection(collection *models.Collection) error {
 
 	return dao.RunInTransaction(func(txDao *Dao) error {
 		// delete the related records table
-		if err := txDao.DeleteTable(collection.Name); err != nil {
+		if !collection.IsExternal() {
+			if err := txDao.DeleteTable(collection.Name); err != nil {
+				return err
+			}
+		}
+
+		// delete the collection records encryption key metadata (if any)
+		if err := txDao.deleteRecordsEncryptionKey(collection); err != nil {
 			return err
 		}
 
@@ -162,13 +174,29 @@ func (dao *Dao) SaveCollection(collection *models.Collection) error {
 			collection.Type = models.CollectionTypeBase
 		}
 
+		// compare-and-swap the collection version
+		if oldCollection != nil {
+			if err := txDao.bumpCollectionVersion(collection); err != nil {
+				return err
+			}
+		}
+
 		// persist the collection model
 		if err := txDao.Save(collection); err != nil {
 			return err
 		}
 
 		// sync the changes with the related records table
-		return txDao.SyncRecordTableSchema(collection, oldCollection)
+		if err := txDao.SyncRecordTableSchema(collection, oldCollection); err != nil {
+			return err
+		}
+
+		// reencrypt or decrypt the stored records values
+		if recordsEncryptionChanged(oldCollection, collection) {
+			return txDao.syncRecordsEncryption(collection)
+		}
+
+		return nil
 	})
 }
 
@@ -219,6 +247,14 @@ func (dao *Dao) ImportCollections(
 					imported.Created = existing.Created
 				}
 
+				// the imported configuration always overwrites the existing one
+				imported.Version = existing.Version + 1
+
+				// keep the write-only LDAP bind password if not set
+				if err := imported.RestoreLdapBindPassword(existing); err != nil {
+					return err
+				}
+
 				// extend existing schema
 				if !deleteMissing {
 					schema, _ := existing.Schema.Clone()
@@ -269,8 +305,8 @@ func (dao *Dao) ImportCollections(
 		// delete the record tables of the deleted collections
 		if deleteMissing {
 			for _, existing := range existingCollections {
-				if mappedImported[existing.GetId()] != nil {
-					continue // exist
+				if mappedImported[existing.GetId()] != nil || existing.IsExternal() {
+					continue // exist or externally managed
 				}
 
 				if err := txDao.DeleteTable(existing.Name); err != nil {
@@ -285,8 +321,37 @@ func (dao *Dao) ImportCollections(
 			if err := txDao.SyncRecordTableSchema(imported, existing); err != nil {
 				return err
 			}
+
+			if recordsEncryptionChanged(existing, imported) {
+				if err := txDao.syncRecordsEncryption(imported); err != nil {
+					return err
+				}
+			}
 		}
 
 		return nil
 	})
 }
+
+// bumpCollectionVersion increments the stored version of the provided
+// collection only if it matches with the collection model version.
+//
+// Returns [ErrCollectionVersionConflict] on version mismatch.
+func (dao *Dao) bumpCollectionVersion(collection *models.Collection) error {
+	result, err := dao.NonconcurrentDB().Update(
+		collection.TableName(),
+		dbx.Params{"version": collection.Version + 1},
+		dbx.HashExp{"id": collection.Id, "version": collection.Version},
+	).Execute()
+	if err != nil {
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrCollectionVersionConflict
+	}
+
+	collection.Version++
+
+	return nil
+}
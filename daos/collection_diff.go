@@ -0,0 +1,336 @@
+package daos
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+)
+
+// Collections diff actions.
+const (
+	DiffActionCreate = "create"
+	DiffActionUpdate = "update"
+	DiffActionDelete = "delete"
+)
+
+// errDiffRollback is used to rollback the diff import transaction.
+var errDiffRollback = errors.New("collections diff rollback")
+
+// CollectionsDiff defines the changes that a collections import would apply.
+type CollectionsDiff struct {
+	// Collections is the list with the created, updated and deleted collections
+	// (the unchanged collections are not included).
+	Collections []*CollectionDiff `json:"collections"`
+
+	// Destructive indicates whether any of the changes could result in data loss.
+	Destructive bool `json:"destructive"`
+
+	// Sql is the list with the SQL statements that the import would execute.
+	Sql []string `json:"sql"`
+}
+
+// CollectionDiff defines the changes of a single collection.
+type CollectionDiff struct {
+	Id          string       `json:"id"`
+	Name        string       `json:"name"`
+	Action      string       `json:"action"`
+	Destructive bool         `json:"destructive"`
+	Changes     []string     `json:"changes"`
+	Fields      []*FieldDiff `json:"fields"`
+}
+
+// FieldDiff defines the changes of a single collection schema field.
+type FieldDiff struct {
+	Id          string   `json:"id"`
+	Name        string   `json:"name"`
+	Action      string   `json:"action"`
+	Destructive bool     `json:"destructive"`
+	Changes     []string `json:"changes"`
+}
+
+// DiffCollections compares the provided collections with the existing ones
+// and returns the changes (and the SQL statements) that
+// [Dao.ImportCollections] would apply for the same arguments.
+//
+// The import is executed in a transaction that is always rollbacked,
+// so neither the collections nor the record tables are modified.
+//
+// NB! The method cannot be used with a transactional Dao.
+func (dao *Dao) DiffCollections(
+	importedCollections []*models.Collection,
+	deleteMissing bool,
+	beforeRecordsSync func(txDao *Dao, mappedImported, mappedExisting map[string]*models.Collection) error,
+) (*CollectionsDiff, error) {
+	db, ok := dao.NonconcurrentDB().(*dbx.DB)
+	if !ok {
+		return nil, errors.New("The collections diff cannot be executed in a transaction.")
+	}
+
+	// work with copies to prevent modifying the provided collections
+	imported, err := cloneCollections(importedCollections)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := []*models.Collection{}
+	if err := dao.CollectionQuery().OrderBy("created ASC").All(&existing); err != nil {
+		return nil, err
+	}
+
+	result := &CollectionsDiff{
+		Collections: diffCollections(existing, imported, deleteMissing),
+		Sql:         []string{},
+	}
+
+	for _, c := range result.Collections {
+		if c.Destructive {
+			result.Destructive = true
+			break
+		}
+	}
+
+	// execute the import with a cloned db to capture the executed statements
+	logDB := db.Clone()
+	logDB.ExecLogFunc = func(ctx context.Context, t time.Duration, sql string, res sql.Result, err error) {
+		if err == nil {
+			result.Sql = append(result.Sql, sql)
+		}
+
+		if db.ExecLogFunc != nil {
+			db.ExecLogFunc(ctx, t, sql, res, err)
+		}
+	}
+
+	logDao := New(logDB)
+	logDao.encryptionKey = dao.encryptionKey
+	logDao.preferenceKeysFunc = dao.preferenceKeysFunc
+	logDao.ruleMacrosFunc = dao.ruleMacrosFunc
+	logDao.randomSource = dao.randomSource
+	logDao.columnNamer = dao.columnNamer
+
+	importErr := logDao.RunInTransaction(func(txDao *Dao) error {
+		if err := txDao.ImportCollections(imported, deleteMissing, beforeRecordsSync); err != nil {
+			return err
+		}
+
+		return errDiffRollback
+	})
+
+	if !errors.Is(importErr, errDiffRollback) {
+		return nil, importErr
+	}
+
+	return result, nil
+}
+
+func cloneCollections(collections []*models.Collection) ([]*models.Collection, error) {
+	raw, err := json.Marshal(collections)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*models.Collection{}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, err
+	}
+
+	// the write-only LDAP bind password is not serialized
+	for i, c := range result {
+		if err := c.RestoreLdapBindPassword(collections[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func diffCollections(existing, imported []*models.Collection, deleteMissing bool) []*CollectionDiff {
+	result := []*CollectionDiff{}
+
+	mappedImported := make(map[string]*models.Collection, len(imported))
+	for _, c := range imported {
+		if c.Id != "" {
+			mappedImported[c.Id] = c
+		}
+	}
+
+	mappedExisting := make(map[string]*models.Collection, len(existing))
+	for _, c := range existing {
+		mappedExisting[c.Id] = c
+	}
+
+	for _, c := range imported {
+		// set the same default type as in the import
+		if c.Type == "" {
+			c.Type = models.CollectionTypeBase
+		}
+
+		old, ok := mappedExisting[c.Id]
+		if c.Id == "" || !ok {
+			diff := &CollectionDiff{Id: c.Id, Name: c.Name, Action: DiffActionCreate}
+			for _, f := range c.Schema.Fields() {
+				diff.Fields = append(diff.Fields, &FieldDiff{Id: f.Id, Name: f.Name, Action: DiffActionCreate})
+			}
+			result = append(result, diff)
+			continue
+		}
+
+		diff := &CollectionDiff{
+			Id:      c.Id,
+			Name:    c.Name,
+			Action:  DiffActionUpdate,
+			Changes: collectionChanges(old, c),
+			Fields:  diffFields(old.Schema, c.Schema, deleteMissing),
+		}
+
+		for _, f := range diff.Fields {
+			if f.Destructive {
+				diff.Destructive = true
+				break
+			}
+		}
+
+		if len(diff.Changes) > 0 || len(diff.Fields) > 0 {
+			result = append(result, diff)
+		}
+	}
+
+	if deleteMissing {
+		for _, c := range existing {
+			if _, ok := mappedImported[c.Id]; ok {
+				continue
+			}
+
+			result = append(result, &CollectionDiff{
+				Id:          c.Id,
+				Name:        c.Name,
+				Action:      DiffActionDelete,
+				Destructive: true,
+			})
+		}
+	}
+
+	return result
+}
+
+func diffFields(oldSchema, newSchema schema.Schema, deleteMissing bool) []*FieldDiff {
+	result := []*FieldDiff{}
+
+	for _, f := range newSchema.Fields() {
+		old := oldSchema.GetFieldById(f.Id)
+		if old == nil {
+			result = append(result, &FieldDiff{Id: f.Id, Name: f.Name, Action: DiffActionCreate})
+			continue
+		}
+
+		changes := fieldChanges(old, f)
+		if len(changes) == 0 {
+			continue
+		}
+
+		result = append(result, &FieldDiff{
+			Id:          f.Id,
+			Name:        f.Name,
+			Action:      DiffActionUpdate,
+			Destructive: old.Type != f.Type,
+			Changes:     changes,
+		})
+	}
+
+	// missing fields are removed only when replacing the entire schema
+	if deleteMissing {
+		for _, old := range oldSchema.Fields() {
+			if newSchema.GetFieldById(old.Id) != nil {
+				continue
+			}
+
+			result = append(result, &FieldDiff{
+				Id:          old.Id,
+				Name:        old.Name,
+				Action:      DiffActionDelete,
+				Destructive: old.Type != schema.FieldTypeComputed, // no stored data
+			})
+		}
+	}
+
+	return result
+}
+
+// collectionChanges returns the names of the changed
+// collection properties (excluding the schema and the system timestamps).
+func collectionChanges(old, new *models.Collection) []string {
+	return jsonChanges(old, new, "id", "created", "updated", "version", "schema")
+}
+
+// fieldChanges returns the names of the changed field properties.
+func fieldChanges(old, new *schema.SchemaField) []string {
+	old.InitOptions()
+	new.InitOptions()
+
+	return jsonChanges(old, new, "id")
+}
+
+func jsonChanges(old, new any, excludeKeys ...string) []string {
+	oldMap := map[string]any{}
+	newMap := map[string]any{}
+
+	oldRaw, _ := json.Marshal(old)
+	newRaw, _ := json.Marshal(new)
+	json.Unmarshal(oldRaw, &oldMap)
+	json.Unmarshal(newRaw, &newMap)
+
+	for _, key := range excludeKeys {
+		delete(oldMap, key)
+		delete(newMap, key)
+	}
+
+	keys := map[string]struct{}{}
+	for k := range oldMap {
+		keys[k] = struct{}{}
+	}
+	for k := range newMap {
+		keys[k] = struct{}{}
+	}
+
+	result := []string{}
+	for k := range keys {
+		if isEmptyJsonValue(oldMap[k]) && isEmptyJsonValue(newMap[k]) {
+			continue // eg. null and {} options
+		}
+
+		if !reflect.DeepEqual(oldMap[k], newMap[k]) {
+			result = append(result, k)
+		}
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+func isEmptyJsonValue(v any) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !val
+	case string:
+		return val == ""
+	case float64:
+		return val == 0
+	case map[string]any:
+		return len(val) == 0
+	case []any:
+		return len(val) == 0
+	}
+
+	return false
+}
@@ -0,0 +1,171 @@
+package daos_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestDiffCollections(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	imported := []*models.Collection{}
+	err := json.Unmarshal([]byte(`[
+		{
+			"id": "sz5l5z67tg7gku0",
+			"name": "demo2",
+			"schema": [
+				{"id":"mkrguaaf","name":"title_new","type":"text","required":true,"unique":true,"options":{"min":2}},
+				{"id":"izkl5z2s","name":"active","type":"number"},
+				{"name":"extra","type":"text"}
+			]
+		},
+		{"name": "diff_test", "schema": [{"name":"test", "type": "text"}]}
+	]`), &imported)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := app.Dao().DiffCollections(imported, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !diff.Destructive {
+		t.Fatal("Expected the diff to be destructive")
+	}
+
+	if len(diff.Collections) != 2 {
+		t.Fatalf("Expected 2 changed collections, got %d", len(diff.Collections))
+	}
+
+	demo2 := diff.Collections[0]
+	if demo2.Name != "demo2" || demo2.Action != daos.DiffActionUpdate || !demo2.Destructive {
+		t.Fatalf("Expected destructive demo2 update, got %v", demo2)
+	}
+
+	if len(demo2.Changes) != 0 {
+		t.Fatalf("Expected no demo2 collection property changes, got %v", demo2.Changes)
+	}
+
+	expectedFields := []struct {
+		name        string
+		action      string
+		destructive bool
+		changes     string
+	}{
+		{"title_new", daos.DiffActionUpdate, false, "name"},
+		{"active", daos.DiffActionUpdate, true, "options,type"},
+		{"extra", daos.DiffActionCreate, false, ""},
+	}
+	if len(demo2.Fields) != len(expectedFields) {
+		t.Fatalf("Expected %d demo2 field changes, got %d", len(expectedFields), len(demo2.Fields))
+	}
+	for i, expected := range expectedFields {
+		f := demo2.Fields[i]
+		if f.Name != expected.name ||
+			f.Action != expected.action ||
+			f.Destructive != expected.destructive ||
+			strings.Join(f.Changes, ",") != expected.changes {
+			t.Errorf("(%d) Expected field diff %v, got %v", i, expected, f)
+		}
+	}
+
+	created := diff.Collections[1]
+	if created.Name != "diff_test" || created.Action != daos.DiffActionCreate || created.Destructive {
+		t.Fatalf("Expected non destructive diff_test create, got %v", created)
+	}
+
+	sql := strings.Join(diff.Sql, "\n")
+	for _, expected := range []string{
+		"RENAME COLUMN `title`",
+		"ADD `extra",
+		"CREATE TABLE `diff_test`",
+	} {
+		if !strings.Contains(sql, expected) {
+			t.Errorf("Expected %q in\n%s", expected, sql)
+		}
+	}
+
+	// nothing should be persisted
+	if _, err := app.Dao().FindCollectionByNameOrId("diff_test"); err == nil {
+		t.Fatal("Expected diff_test collection to not be created")
+	}
+	if app.Dao().HasTable("diff_test") {
+		t.Fatal("Expected diff_test table to not be created")
+	}
+	demo2Collection, err := app.Dao().FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if demo2Collection.Schema.GetFieldByName("title") == nil {
+		t.Fatal("Expected demo2 title field to be unchanged")
+	}
+
+	// the provided collections should not be modified
+	if imported[1].Id != "" {
+		t.Fatalf("Expected the imported collection id to be unchanged, got %q", imported[1].Id)
+	}
+}
+
+func TestDiffCollectionsDeleteMissing(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	// the "nologin" system collection cannot be deleted
+	_, err := app.Dao().DiffCollections([]*models.Collection{{Name: "diff_test"}}, true, nil)
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	existing := []*models.Collection{}
+	if err := app.Dao().CollectionQuery().All(&existing); err != nil {
+		t.Fatal(err)
+	}
+
+	imported := []*models.Collection{}
+	for _, c := range existing {
+		if c.Name == "demo4" {
+			continue
+		}
+		imported = append(imported, c)
+	}
+
+	diff, err := app.Dao().DiffCollections(imported, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diff.Collections) != 1 {
+		t.Fatalf("Expected 1 changed collection, got %d", len(diff.Collections))
+	}
+
+	if c := diff.Collections[0]; c.Name != "demo4" || c.Action != daos.DiffActionDelete || !c.Destructive || !diff.Destructive {
+		t.Fatalf("Expected destructive demo4 delete, got %v", c)
+	}
+
+	if !strings.Contains(strings.Join(diff.Sql, "\n"), "DROP TABLE") {
+		t.Fatalf("Expected DROP TABLE statement, got %v", diff.Sql)
+	}
+
+	if !app.Dao().HasTable("demo4") {
+		t.Fatal("Expected demo4 table to not be deleted")
+	}
+}
+
+func TestDiffCollectionsInTransaction(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Dao().RunInTransaction(func(txDao *daos.Dao) error {
+		if _, err := txDao.DiffCollections([]*models.Collection{{Name: "diff_test"}}, false, nil); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+		return nil
+	})
+}
@@ -281,6 +281,119 @@ func TestSaveCollectionUpdate(t *testing.T) {
 	}
 }
 
+func TestExternalCollection(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	_, err := app.Dao().DB().NewQuery(`
+		CREATE TABLE ext_items (id INTEGER PRIMARY KEY, title TEXT);
+		INSERT INTO ext_items (id, title) VALUES (1, 'a'), (2, 'b');
+	`).Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection := &models.Collection{
+		Name: "ext_items",
+		Type: models.CollectionTypeBase,
+		Schema: schema.NewSchema(&schema.SchemaField{
+			Type: schema.FieldTypeText,
+			Name: "title",
+		}),
+	}
+	collection.SetOptions(models.CollectionBaseOptions{External: true})
+
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	// update the schema
+	collection.Schema.AddField(&schema.SchemaField{
+		Type: schema.FieldTypeText,
+		Name: "missing",
+	})
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	// the existing table must not be altered
+	columns, err := app.Dao().GetTableColumns(collection.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(columns) != 2 {
+		t.Fatalf("Expected the table columns to be unchanged, got %v", columns)
+	}
+
+	record, err := app.Dao().FindRecordById(collection.Id, "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.GetString("title") != "b" {
+		t.Fatalf("Expected title %q, got %q", "b", record.GetString("title"))
+	}
+
+	// the records are read-only
+	record.Set("title", "c")
+	if err := app.Dao().SaveRecord(record); err == nil {
+		t.Fatal("Expected the external record save to fail")
+	}
+	if err := app.Dao().DeleteRecord(record); err == nil {
+		t.Fatal("Expected the external record delete to fail")
+	}
+
+	// the existing table must be preserved
+	if err := app.Dao().DeleteCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+	if !app.Dao().HasTable(collection.Name) {
+		t.Fatal("Expected the external table to be preserved")
+	}
+}
+
+func TestSaveCollectionVersionConflict(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection1, err := app.Dao().FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// simulate concurrent edit
+	collection2, err := app.Dao().FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection1.ListRule = nil
+	if err := app.Dao().SaveCollection(collection1); err != nil {
+		t.Fatal(err)
+	}
+	if collection1.Version != 1 {
+		t.Fatalf("Expected collection version 1, got %d", collection1.Version)
+	}
+
+	collection2.Name = "demo1_new"
+	if err := app.Dao().SaveCollection(collection2); !errors.Is(err, daos.ErrCollectionVersionConflict) {
+		t.Fatalf("Expected ErrCollectionVersionConflict, got %v", err)
+	}
+
+	// the outdated changes shouldn't be persisted
+	if _, err := app.Dao().FindCollectionByNameOrId("demo1"); err != nil {
+		t.Fatalf("Expected the collection name to be unchanged, got %v", err)
+	}
+
+	// retry with the fresh collection version
+	collection2.Version = collection1.Version
+	if err := app.Dao().SaveCollection(collection2); err != nil {
+		t.Fatal(err)
+	}
+	if collection2.Version != 2 {
+		t.Fatalf("Expected collection version 2, got %d", collection2.Version)
+	}
+}
+
 func TestImportCollections(t *testing.T) {
 	scenarios := []struct {
 		name                   string
@@ -553,3 +666,42 @@ func TestImportCollections(t *testing.T) {
 		}
 	}
 }
+
+func TestImportCollectionsKeepLdapBindPassword(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := collection.AuthOptions()
+	options.Ldap.BindDn = "cn=admin,dc=example,dc=com"
+	options.Ldap.BindPassword = "secret"
+	collection.SetOptions(options)
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	// import the serialized (aka. without bind password) collection
+	raw, err := json.Marshal(collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported := &models.Collection{}
+	if err := json.Unmarshal(raw, imported); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().ImportCollections([]*models.Collection{imported}, false, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := app.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := updated.AuthOptions().Ldap.BindPassword; v != "secret" {
+		t.Fatalf("Expected the bind password to remain %q, got %q", "secret", v)
+	}
+}
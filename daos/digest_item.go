@@ -0,0 +1,64 @@
+package daos
+
+import (
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+// DigestItemQuery returns a new DigestItem select query.
+func (dao *Dao) DigestItemQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.DigestItem{})
+}
+
+// FindDigestItems returns all queued digest items of a single
+// auth record recipient (ordered by their creation date).
+func (dao *Dao) FindDigestItems(collectionId string, recordId string) ([]*models.DigestItem, error) {
+	items := []*models.DigestItem{}
+
+	err := dao.DigestItemQuery().
+		AndWhere(dbx.HashExp{
+			"collectionId": collectionId,
+			"recordId":     recordId,
+		}).
+		OrderBy("created ASC").
+		All(&items)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// FindAllDigestItems returns all queued digest items
+// (ordered by their creation date).
+func (dao *Dao) FindAllDigestItems() ([]*models.DigestItem, error) {
+	items := []*models.DigestItem{}
+
+	err := dao.DigestItemQuery().OrderBy("created ASC").All(&items)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// SaveDigestItem upserts the provided DigestItem model.
+func (dao *Dao) SaveDigestItem(item *models.DigestItem) error {
+	return dao.Save(item)
+}
+
+// DeleteDigestItems deletes the digest items with the provided ids.
+func (dao *Dao) DeleteDigestItems(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := dao.NonconcurrentDB().Delete(
+		(&models.DigestItem{}).TableName(),
+		dbx.In("id", list.ToInterfaceSlice(ids)...),
+	).Execute()
+
+	return err
+}
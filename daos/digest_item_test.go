@@ -0,0 +1,66 @@
+package daos_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestDigestItems(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dao := app.Dao()
+
+	user, err := dao.FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recipients := []string{user.Id, user.Id, "oap640cot4yru2s"}
+	for i, recordId := range recipients {
+		item := &models.DigestItem{
+			CollectionId: user.Collection().Id,
+			RecordId:     recordId,
+			Subject:      "test",
+			Message:      "message",
+		}
+		if err := dao.SaveDigestItem(item); err != nil {
+			t.Fatalf("(%d) Failed to save item: %v", i, err)
+		}
+	}
+
+	all, err := dao.FindAllDigestItems()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("Expected 3 items, got %d", len(all))
+	}
+
+	items, err := dao.FindDigestItems(user.Collection().Id, user.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 user items, got %d", len(items))
+	}
+
+	if err := dao.DeleteDigestItems([]string{items[0].Id, items[1].Id}); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err = dao.FindAllDigestItems()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 || all[0].RecordId != "oap640cot4yru2s" {
+		t.Fatalf("Expected only the other recipient item to remain, got %v", all)
+	}
+
+	// nothing to delete
+	if err := dao.DeleteDigestItems(nil); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,64 @@
+package daos
+
+import (
+	"errors"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// LoginAttemptQuery returns a new LoginAttempt select query.
+func (dao *Dao) LoginAttemptQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.LoginAttempt{})
+}
+
+// FindLoginAttempt finds a single LoginAttempt model by its kind and key.
+func (dao *Dao) FindLoginAttempt(kind string, key string) (*models.LoginAttempt, error) {
+	model := &models.LoginAttempt{}
+
+	err := dao.LoginAttemptQuery().
+		AndWhere(dbx.HashExp{
+			"kind": kind,
+			"key":  key,
+		}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// SaveLoginAttempt upserts the provided LoginAttempt model.
+func (dao *Dao) SaveLoginAttempt(model *models.LoginAttempt) error {
+	if model.Kind == "" || model.Key == "" {
+		return errors.New("Missing login attempt kind or key.")
+	}
+
+	return dao.Save(model)
+}
+
+// DeleteLoginAttempt deletes the provided LoginAttempt model.
+func (dao *Dao) DeleteLoginAttempt(model *models.LoginAttempt) error {
+	return dao.Delete(model)
+}
+
+// DeleteStaleLoginAttempts deletes all not locked LoginAttempt
+// models that were last updated before updatedBefore.
+func (dao *Dao) DeleteStaleLoginAttempts(updatedBefore time.Time) error {
+	formattedDate := updatedBefore.UTC().Format(types.DefaultDateLayout)
+	formattedNow := types.NowDateTime().String()
+
+	expr := dbx.NewExp(
+		"[[updated]] < {:date} AND [[lockedUntil]] < {:now}",
+		dbx.Params{"date": formattedDate, "now": formattedNow},
+	)
+
+	_, err := dao.DB().Delete((&models.LoginAttempt{}).TableName(), expr).Execute()
+
+	return err
+}
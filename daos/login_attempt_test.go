@@ -0,0 +1,101 @@
+package daos_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestLoginAttemptQuery(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	expected := "SELECT {{_loginAttempts}}.* FROM `_loginAttempts`"
+
+	sql := app.Dao().LoginAttemptQuery().Build().SQL()
+	if sql != expected {
+		t.Errorf("Expected sql %s, got %s", expected, sql)
+	}
+}
+
+func TestLoginAttemptSaveFindAndDelete(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	if _, err := app.Dao().FindLoginAttempt(models.LoginAttemptKindIp, "127.0.0.1"); err == nil {
+		t.Fatal("Expected no login attempts")
+	}
+
+	if err := app.Dao().SaveLoginAttempt(&models.LoginAttempt{Kind: models.LoginAttemptKindIp}); err == nil {
+		t.Fatal("Expected error for missing key")
+	}
+
+	attempt := &models.LoginAttempt{Kind: models.LoginAttemptKindIp, Key: "127.0.0.1", Failures: 2}
+	if err := app.Dao().SaveLoginAttempt(attempt); err != nil {
+		t.Fatal(err)
+	}
+
+	// same key but different kind
+	if _, err := app.Dao().FindLoginAttempt(models.LoginAttemptKindIdentity, "127.0.0.1"); err == nil {
+		t.Fatal("Expected no identity login attempt")
+	}
+
+	found, err := app.Dao().FindLoginAttempt(models.LoginAttemptKindIp, "127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Id != attempt.Id || found.Failures != 2 {
+		t.Fatalf("Expected login attempt %v, got %v", attempt, found)
+	}
+
+	if err := app.Dao().DeleteLoginAttempt(found); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindLoginAttempt(models.LoginAttemptKindIp, "127.0.0.1"); err == nil {
+		t.Fatal("Expected the login attempt to be deleted")
+	}
+}
+
+func TestDeleteStaleLoginAttempts(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	stale := &models.LoginAttempt{Kind: models.LoginAttemptKindIp, Key: "stale"}
+	locked := &models.LoginAttempt{Kind: models.LoginAttemptKindIp, Key: "locked"}
+	locked.LockedUntil, _ = types.ParseDateTime(time.Now().Add(time.Hour))
+	recent := &models.LoginAttempt{Kind: models.LoginAttemptKindIp, Key: "recent"}
+
+	for _, m := range []*models.LoginAttempt{stale, locked, recent} {
+		if err := app.Dao().SaveLoginAttempt(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// mark the first 2 as stale
+	staleDate, _ := types.ParseDateTime(time.Now().Add(-2 * time.Hour))
+	_, err := app.Dao().DB().Update(
+		stale.TableName(),
+		dbx.Params{"updated": staleDate.String()},
+		dbx.In("id", stale.Id, locked.Id),
+	).Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().DeleteStaleLoginAttempts(time.Now().Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := map[string]bool{"stale": false, "locked": true, "recent": true}
+	for key, exists := range scenarios {
+		_, err := app.Dao().FindLoginAttempt(models.LoginAttemptKindIp, key)
+		if (err == nil) != exists {
+			t.Errorf("Expected %q exists %v, got error %v", key, exists, err)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package daos
+
+import (
+	"errors"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// ErrLoginLinkUnavailable is returned when trying to use a LoginLink
+// that was already consumed (eg. by a concurrent request).
+var ErrLoginLinkUnavailable = errors.New("the login link was already used")
+
+// LoginLinkQuery returns a new LoginLink select query.
+func (dao *Dao) LoginLinkQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.LoginLink{})
+}
+
+// FindLoginLinkById finds a single LoginLink model by its id.
+func (dao *Dao) FindLoginLinkById(id string) (*models.LoginLink, error) {
+	model := &models.LoginLink{}
+
+	err := dao.LoginLinkQuery().
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// FindLastLoginLinkByRecord returns the most recently created
+// LoginLink model of the provided auth record.
+func (dao *Dao) FindLastLoginLinkByRecord(authRecord *models.Record) (*models.LoginLink, error) {
+	model := &models.LoginLink{}
+
+	err := dao.LoginLinkQuery().
+		AndWhere(dbx.HashExp{
+			"collectionId": authRecord.Collection().Id,
+			"recordId":     authRecord.Id,
+		}).
+		OrderBy("created DESC", "rowid DESC").
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// SaveLoginLink upserts the provided LoginLink model.
+func (dao *Dao) SaveLoginLink(model *models.LoginLink) error {
+	if model.CollectionId == "" || model.RecordId == "" {
+		return errors.New("Missing login link auth record.")
+	}
+
+	return dao.Save(model)
+}
+
+// DeleteLoginLink deletes the provided LoginLink model.
+func (dao *Dao) DeleteLoginLink(model *models.LoginLink) error {
+	return dao.Delete(model)
+}
+
+// ConsumeLoginLink deletes the provided LoginLink model and
+// ensures that it wasn't already consumed by another request.
+//
+// Returns [ErrLoginLinkUnavailable] if the model was already deleted.
+func (dao *Dao) ConsumeLoginLink(model *models.LoginLink) error {
+	result, err := dao.NonconcurrentDB().Delete(model.TableName(), dbx.HashExp{"id": model.Id}).Execute()
+	if err != nil {
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected != 1 {
+		return ErrLoginLinkUnavailable
+	}
+
+	return nil
+}
+
+// DeleteAuthRecordLoginLinks deletes all LoginLink models
+// associated with the provided auth record.
+func (dao *Dao) DeleteAuthRecordLoginLinks(authRecord *models.Record) error {
+	_, err := dao.DB().Delete((&models.LoginLink{}).TableName(), dbx.HashExp{
+		"collectionId": authRecord.Collection().Id,
+		"recordId":     authRecord.Id,
+	}).Execute()
+
+	return err
+}
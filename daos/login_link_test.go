@@ -0,0 +1,92 @@
+package daos_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestLoginLinkQuery(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	expected := "SELECT {{_loginLinks}}.* FROM `_loginLinks`"
+
+	sql := app.Dao().LoginLinkQuery().Build().SQL()
+	if sql != expected {
+		t.Errorf("Expected sql %s, got %s", expected, sql)
+	}
+}
+
+func TestLoginLinkSaveFindAndDelete(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindLastLoginLinkByRecord(record); err == nil {
+		t.Fatal("Expected no login links")
+	}
+
+	if err := app.Dao().SaveLoginLink(&models.LoginLink{}); err == nil {
+		t.Fatal("Expected error for missing auth record")
+	}
+
+	link1 := &models.LoginLink{CollectionId: record.Collection().Id, RecordId: record.Id}
+	if err := app.Dao().SaveLoginLink(link1); err != nil {
+		t.Fatal(err)
+	}
+
+	link2 := &models.LoginLink{CollectionId: record.Collection().Id, RecordId: record.Id}
+	if err := app.Dao().SaveLoginLink(link2); err != nil {
+		t.Fatal(err)
+	}
+
+	last, err := app.Dao().FindLastLoginLinkByRecord(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last.Id != link2.Id {
+		t.Fatalf("Expected the last login link to be %q, got %q", link2.Id, last.Id)
+	}
+
+	found, err := app.Dao().FindLoginLinkById(link1.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().DeleteLoginLink(found); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindLoginLinkById(link1.Id); err == nil {
+		t.Fatal("Expected the login link to be deleted")
+	}
+
+	if err := app.Dao().ConsumeLoginLink(link2); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().ConsumeLoginLink(link2); !errors.Is(err, daos.ErrLoginLinkUnavailable) {
+		t.Fatalf("Expected ErrLoginLinkUnavailable for already consumed login link, got %v", err)
+	}
+
+	link3 := &models.LoginLink{CollectionId: record.Collection().Id, RecordId: record.Id}
+	if err := app.Dao().SaveLoginLink(link3); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().DeleteAuthRecordLoginLinks(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindLoginLinkById(link3.Id); err == nil {
+		t.Fatal("Expected the auth record login links to be deleted")
+	}
+}
@@ -0,0 +1,124 @@
+package daos
+
+import (
+	"errors"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// ErrPhoneOtpUnavailable is returned when trying to use a PhoneOtp
+// that was already consumed or has exhausted its verification attempts
+// (eg. by a concurrent request).
+var ErrPhoneOtpUnavailable = errors.New("the phone otp was already used or has too many failed attempts")
+
+// PhoneOtpQuery returns a new PhoneOtp select query.
+func (dao *Dao) PhoneOtpQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.PhoneOtp{})
+}
+
+// FindPhoneOtpById finds a single PhoneOtp model by its id.
+func (dao *Dao) FindPhoneOtpById(id string) (*models.PhoneOtp, error) {
+	model := &models.PhoneOtp{}
+
+	err := dao.PhoneOtpQuery().
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// FindLastPhoneOtpByRecord returns the most recently created
+// PhoneOtp model of the provided auth record.
+func (dao *Dao) FindLastPhoneOtpByRecord(authRecord *models.Record) (*models.PhoneOtp, error) {
+	model := &models.PhoneOtp{}
+
+	err := dao.PhoneOtpQuery().
+		AndWhere(dbx.HashExp{
+			"collectionId": authRecord.Collection().Id,
+			"recordId":     authRecord.Id,
+		}).
+		OrderBy("created DESC", "rowid DESC").
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// SavePhoneOtp upserts the provided PhoneOtp model.
+func (dao *Dao) SavePhoneOtp(model *models.PhoneOtp) error {
+	if model.CollectionId == "" || model.RecordId == "" {
+		return errors.New("Missing phone otp auth record.")
+	}
+
+	return dao.Save(model)
+}
+
+// DeletePhoneOtp deletes the provided PhoneOtp model.
+func (dao *Dao) DeletePhoneOtp(model *models.PhoneOtp) error {
+	return dao.Delete(model)
+}
+
+// IncrementPhoneOtpAttempts atomically increments the stored attempts
+// counter of the provided PhoneOtp model, but only if it is still below
+// [models.PhoneOtpMaxAttempts].
+//
+// Returns [ErrPhoneOtpUnavailable] if the model was already
+// deleted or has reached the max allowed attempts.
+func (dao *Dao) IncrementPhoneOtpAttempts(model *models.PhoneOtp) error {
+	result, err := dao.NonconcurrentDB().NewQuery(
+		"UPDATE {{" + model.TableName() + "}} SET [[attempts]] = [[attempts]] + 1 " +
+			"WHERE [[id]] = {:id} AND [[attempts]] < {:max}",
+	).Bind(dbx.Params{
+		"id":  model.Id,
+		"max": models.PhoneOtpMaxAttempts,
+	}).Execute()
+	if err != nil {
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected != 1 {
+		return ErrPhoneOtpUnavailable
+	}
+
+	model.Attempts++
+
+	return nil
+}
+
+// ConsumePhoneOtp deletes the provided PhoneOtp model and
+// ensures that it wasn't already consumed by another request.
+//
+// Returns [ErrPhoneOtpUnavailable] if the model was already deleted.
+func (dao *Dao) ConsumePhoneOtp(model *models.PhoneOtp) error {
+	result, err := dao.NonconcurrentDB().Delete(model.TableName(), dbx.HashExp{"id": model.Id}).Execute()
+	if err != nil {
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected != 1 {
+		return ErrPhoneOtpUnavailable
+	}
+
+	return nil
+}
+
+// DeleteAuthRecordPhoneOtps deletes all PhoneOtp models
+// associated with the provided auth record.
+func (dao *Dao) DeleteAuthRecordPhoneOtps(authRecord *models.Record) error {
+	_, err := dao.DB().Delete((&models.PhoneOtp{}).TableName(), dbx.HashExp{
+		"collectionId": authRecord.Collection().Id,
+		"recordId":     authRecord.Id,
+	}).Execute()
+
+	return err
+}
@@ -0,0 +1,134 @@
+package daos_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestPhoneOtpQuery(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	expected := "SELECT {{_phoneOtps}}.* FROM `_phoneOtps`"
+
+	sql := app.Dao().PhoneOtpQuery().Build().SQL()
+	if sql != expected {
+		t.Errorf("Expected sql %s, got %s", expected, sql)
+	}
+}
+
+func TestPhoneOtpSaveFindAndDelete(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindLastPhoneOtpByRecord(record); err == nil {
+		t.Fatal("Expected no phone otps")
+	}
+
+	if err := app.Dao().SavePhoneOtp(&models.PhoneOtp{}); err == nil {
+		t.Fatal("Expected error for missing auth record")
+	}
+
+	otp1 := &models.PhoneOtp{CollectionId: record.Collection().Id, RecordId: record.Id}
+	if err := app.Dao().SavePhoneOtp(otp1); err != nil {
+		t.Fatal(err)
+	}
+
+	otp2 := &models.PhoneOtp{CollectionId: record.Collection().Id, RecordId: record.Id}
+	if err := app.Dao().SavePhoneOtp(otp2); err != nil {
+		t.Fatal(err)
+	}
+
+	last, err := app.Dao().FindLastPhoneOtpByRecord(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last.Id != otp2.Id {
+		t.Fatalf("Expected the last phone otp to be %q, got %q", otp2.Id, last.Id)
+	}
+
+	found, err := app.Dao().FindPhoneOtpById(otp1.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().DeletePhoneOtp(found); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindPhoneOtpById(otp1.Id); err == nil {
+		t.Fatal("Expected the phone otp to be deleted")
+	}
+
+	if err := app.Dao().DeleteAuthRecordPhoneOtps(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindPhoneOtpById(otp2.Id); err == nil {
+		t.Fatal("Expected the auth record phone otps to be deleted")
+	}
+}
+
+func TestIncrementPhoneOtpAttemptsAndConsume(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otp := &models.PhoneOtp{
+		CollectionId: record.Collection().Id,
+		RecordId:     record.Id,
+		Attempts:     models.PhoneOtpMaxAttempts - 2,
+	}
+	if err := app.Dao().SavePhoneOtp(otp); err != nil {
+		t.Fatal(err)
+	}
+
+	// simulate a stale copy loaded by a concurrent request
+	stale, err := app.Dao().FindPhoneOtpById(otp.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := app.Dao().IncrementPhoneOtpAttempts(otp); err != nil {
+			t.Fatalf("(%d) Expected nil error, got %v", i, err)
+		}
+	}
+
+	if err := app.Dao().IncrementPhoneOtpAttempts(stale); !errors.Is(err, daos.ErrPhoneOtpUnavailable) {
+		t.Fatalf("Expected ErrPhoneOtpUnavailable for exhausted attempts, got %v", err)
+	}
+
+	found, err := app.Dao().FindPhoneOtpById(otp.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Attempts != models.PhoneOtpMaxAttempts || otp.Attempts != models.PhoneOtpMaxAttempts {
+		t.Fatalf("Expected %d stored attempts, got %d (model %d)", models.PhoneOtpMaxAttempts, found.Attempts, otp.Attempts)
+	}
+
+	if err := app.Dao().ConsumePhoneOtp(otp); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().ConsumePhoneOtp(stale); !errors.Is(err, daos.ErrPhoneOtpUnavailable) {
+		t.Fatalf("Expected ErrPhoneOtpUnavailable for already consumed otp, got %v", err)
+	}
+
+	if err := app.Dao().IncrementPhoneOtpAttempts(stale); !errors.Is(err, daos.ErrPhoneOtpUnavailable) {
+		t.Fatalf("Expected ErrPhoneOtpUnavailable for deleted otp, got %v", err)
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/models/schema"
-	"github.com/pocketbase/pocketbase/tools/inflector"
 	"github.com/pocketbase/pocketbase/tools/list"
 	"github.com/pocketbase/pocketbase/tools/security"
 	"github.com/pocketbase/pocketbase/tools/types"
@@ -17,11 +16,59 @@ import (
 )
 
 // RecordQuery returns a new Record select query.
+//
+// For collections with enabled soft delete the query excludes
+// the soft deleted records (see [Dao.RecordQueryWithDeleted]).
 func (dao *Dao) RecordQuery(collection *models.Collection) *dbx.SelectQuery {
+	query := dao.RecordQueryWithDeleted(collection)
+
+	if collection.SoftDeleteOptions().Enabled {
+		query.AndWhere(dbx.NewExp(fmt.Sprintf("[[%s.%s]] = ''", collection.Name, schema.FieldNameDeleted)))
+	}
+
+	return query
+}
+
+// RecordQueryWithDeleted returns a new Record select query
+// including the soft deleted records (if any).
+func (dao *Dao) RecordQueryWithDeleted(collection *models.Collection) *dbx.SelectQuery {
 	tableName := collection.Name
-	selectCols := fmt.Sprintf("%s.*", dao.DB().QuoteSimpleColumnName(tableName))
+	selectCols := []string{fmt.Sprintf("%s.*", dao.DB().QuoteSimpleColumnName(tableName))}
 
-	return dao.DB().Select(selectCols).From(tableName)
+	return dao.DB().Select(append(selectCols, recordComputedColumns(collection)...)...).
+		From(collection.QualifiedTableName())
+}
+
+// recordComputedColumns returns the select expressions of the
+// collection computed fields (eg. "(expr) AS [[name]]").
+//
+// Computed fields with invalid expression are selected as NULL.
+func recordComputedColumns(collection *models.Collection) []string {
+	result := []string{}
+
+	for _, field := range collection.Schema.Fields() {
+		if field.Type != schema.FieldTypeComputed {
+			continue
+		}
+
+		expr, err := collection.ComputedFieldExpr(field, collection.Name)
+		if err != nil {
+			expr = "NULL"
+		}
+
+		result = append(result, fmt.Sprintf("(%s) AS [[%s]]", expr, field.Name))
+	}
+
+	return result
+}
+
+// TotalRecords returns the number of existing records in the provided collection.
+func (dao *Dao) TotalRecords(collection *models.Collection) (int, error) {
+	var total int
+
+	err := dao.RecordQuery(collection).Select("count(*)").Row(&total)
+
+	return total, err
 }
 
 // FindRecordById finds the Record model by its id.
@@ -54,6 +101,10 @@ func (dao *Dao) FindRecordById(
 		return nil, err
 	}
 
+	if err := dao.DecryptRecordRows(collection, row); err != nil {
+		return nil, err
+	}
+
 	return models.NewRecordFromNullStringMap(collection, row), nil
 }
 
@@ -89,6 +140,10 @@ func (dao *Dao) FindRecordsByIds(
 		return nil, err
 	}
 
+	if err := dao.DecryptRecordRows(collection, rows...); err != nil {
+		return nil, err
+	}
+
 	return models.NewRecordsFromNullStringMaps(collection, rows), nil
 }
 
@@ -123,9 +178,232 @@ func (dao *Dao) FindRecordsByExpr(collectionNameOrId string, exprs ...dbx.Expres
 		return nil, err
 	}
 
+	if err := dao.DecryptRecordRows(collection, rows...); err != nil {
+		return nil, err
+	}
+
 	return models.NewRecordsFromNullStringMaps(collection, rows), nil
 }
 
+// FindDistinctRecordValues returns the unique non-null values of the
+// specified collection field, sorted in ascending order (eg. to build
+// faceted filters).
+//
+// The items of the multiple select, file and relation fields
+// are returned as separate values.
+//
+// Example:
+//
+//	dao.FindDistinctRecordValues(collection, "status", func(q *dbx.SelectQuery) error {
+//		q.AndWhere(dbx.HashExp{"active": true}).Limit(10)
+//		return nil
+//	})
+func (dao *Dao) FindDistinctRecordValues(
+	collection *models.Collection,
+	fieldName string,
+	optFilters ...func(q *dbx.SelectQuery) error,
+) ([]any, error) {
+	query := dao.RecordQuery(collection).Distinct(true)
+
+	field, column, err := dao.recordFieldValuesColumn(query, collection, fieldName)
+	if err != nil {
+		return nil, err
+	}
+
+	isMultiple := field != nil && isMultiValueField(field)
+
+	query.
+		Select(column + " AS [[value]]").
+		AndWhere(dbx.NewExp(column + " IS NOT NULL")).
+		OrderBy("[[value]] ASC")
+
+	for _, filter := range optFilters {
+		if filter == nil {
+			continue
+		}
+		if err := filter(query); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := query.Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []any{}
+
+	for rows.Next() {
+		var value any
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+
+		if raw, ok := value.([]byte); ok {
+			value = string(raw)
+		}
+
+		if field != nil && !isMultiple {
+			value = field.PrepareValue(value)
+		}
+
+		result = append(result, value)
+	}
+
+	return result, rows.Err()
+}
+
+// RecordFacetItem defines a single record field value with
+// the number of records that have it.
+type RecordFacetItem struct {
+	Value any `json:"value"`
+	Count int `json:"count"`
+}
+
+// FindRecordFacetCounts returns the non-null values of the specified
+// collection field together with the number of records having them,
+// sorted by the count in descending order (eg. to build filter sidebars).
+//
+// The items of the multiple select, file and relation fields
+// are counted as separate values.
+//
+// Example:
+//
+//	dao.FindRecordFacetCounts(collection, "tags", func(q *dbx.SelectQuery) error {
+//		q.AndWhere(dbx.HashExp{"active": true}).Limit(10)
+//		return nil
+//	})
+func (dao *Dao) FindRecordFacetCounts(
+	collection *models.Collection,
+	fieldName string,
+	optFilters ...func(q *dbx.SelectQuery) error,
+) ([]*RecordFacetItem, error) {
+	query := dao.RecordQuery(collection)
+
+	field, column, err := dao.recordFieldValuesColumn(query, collection, fieldName)
+	if err != nil {
+		return nil, err
+	}
+
+	isMultiple := field != nil && isMultiValueField(field)
+
+	// count each record only once regardless of the filter joins
+	// or the duplicated multiple field items
+	query.
+		Select(
+			column+" AS [[facetValue]]",
+			fmt.Sprintf("COUNT(DISTINCT [[%s.id]]) AS [[facetCount]]", collection.Name),
+		).
+		AndWhere(dbx.NewExp(column+" IS NOT NULL")).
+		GroupBy("facetValue").
+		OrderBy("[[facetCount]] DESC", "[[facetValue]] ASC")
+
+	for _, filter := range optFilters {
+		if filter == nil {
+			continue
+		}
+		if err := filter(query); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := query.Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*RecordFacetItem{}
+
+	for rows.Next() {
+		item := &RecordFacetItem{}
+		if err := rows.Scan(&item.Value, &item.Count); err != nil {
+			return nil, err
+		}
+
+		if raw, ok := item.Value.([]byte); ok {
+			item.Value = string(raw)
+		}
+
+		if field != nil && !isMultiple {
+			item.Value = field.PrepareValue(item.Value)
+		}
+
+		result = append(result, item)
+	}
+
+	return result, rows.Err()
+}
+
+// recordFieldValuesColumn returns the schema field (nil for the system
+// fields) and the query column with the values of the specified
+// collection field.
+//
+// The items of the multiple fields are unnested by joining them
+// to the provided query.
+func (dao *Dao) recordFieldValuesColumn(
+	query *dbx.SelectQuery,
+	collection *models.Collection,
+	fieldName string,
+) (*schema.SchemaField, string, error) {
+	var field *schema.SchemaField
+
+	isBaseField := list.ExistInSlice(fieldName, schema.BaseModelFieldNames()) ||
+		(collection.IsAuth() && list.ExistInSlice(fieldName, schema.AuthFieldNames()))
+
+	if !isBaseField {
+		field = collection.Schema.GetFieldByName(fieldName)
+		if field == nil {
+			return nil, "", fmt.Errorf("Missing collection field %q.", fieldName)
+		}
+
+		if IsEncryptedRecordField(collection, field) {
+			return nil, "", fmt.Errorf("The collection field %q is encrypted.", fieldName)
+		}
+	}
+
+	tableName := collection.Name
+	column := fmt.Sprintf("[[%s.%s]]", tableName, dao.Columnify(fieldName))
+
+	if field != nil && field.Type == schema.FieldTypeComputed {
+		expr, err := collection.ComputedFieldExpr(field, tableName)
+		if err != nil {
+			return nil, "", err
+		}
+		column = "(" + expr + ")"
+	}
+
+	if field != nil && isMultiValueField(field) {
+		// unnest the field items (normalizing the non-array values)
+		query.InnerJoin(fmt.Sprintf(
+			"json_each(CASE WHEN json_valid(%s) THEN %s ELSE json_array(%s) END) __values__",
+			column,
+			column,
+			column,
+		), nil)
+		column = "[[__values__.value]]"
+		query.AndWhere(dbx.NewExp(column + " != ''"))
+	}
+
+	return field, column, nil
+}
+
+// isMultiValueField checks whether the provided field could store
+// multiple values (aka. a multiple select, file or relation field).
+func isMultiValueField(field *schema.SchemaField) bool {
+	switch options := field.Options.(type) {
+	case *schema.SelectOptions:
+		return options.MaxSelect > 1
+	case *schema.FileOptions:
+		return options.MaxSelect > 1
+	case *schema.RelationOptions:
+		return options.MaxSelect == nil || *options.MaxSelect > 1
+	}
+
+	return false
+}
+
 // FindFirstRecordByData returns the first found record matching
 // the provided key-value pair.
 func (dao *Dao) FindFirstRecordByData(collectionNameOrId string, key string, value any) (*models.Record, error) {
@@ -137,7 +415,7 @@ func (dao *Dao) FindFirstRecordByData(collectionNameOrId string, key string, val
 	row := dbx.NullStringMap{}
 
 	err = dao.RecordQuery(collection).
-		AndWhere(dbx.HashExp{inflector.Columnify(key): value}).
+		AndWhere(dbx.HashExp{dao.Columnify(key): value}).
 		Limit(1).
 		One(row)
 
@@ -145,6 +423,10 @@ func (dao *Dao) FindFirstRecordByData(collectionNameOrId string, key string, val
 		return nil, err
 	}
 
+	if err := dao.DecryptRecordRows(collection, row); err != nil {
+		return nil, err
+	}
+
 	return models.NewRecordFromNullStringMap(collection, row), nil
 }
 
@@ -184,10 +466,12 @@ func (dao *Dao) IsRecordValueUnique(
 			normalizedVal = val
 		}
 
-		expr = dbx.HashExp{inflector.Columnify(key): normalizedVal}
+		expr = dbx.HashExp{dao.Columnify(key): normalizedVal}
 	}
 
-	query := dao.RecordQuery(collection).
+	// note: the soft deleted records are also checked since
+	// they are still part of the table unique indexes
+	query := dao.RecordQueryWithDeleted(collection).
 		Select("count(*)").
 		AndWhere(expr).
 		Limit(1)
@@ -260,6 +544,10 @@ func (dao *Dao) FindAuthRecordByEmail(collectionNameOrId string, email string) (
 		return nil, err
 	}
 
+	if err := dao.DecryptRecordRows(collection, row); err != nil {
+		return nil, err
+	}
+
 	return models.NewRecordFromNullStringMap(collection, row), nil
 }
 
@@ -288,6 +576,10 @@ func (dao *Dao) FindAuthRecordByUsername(collectionNameOrId string, username str
 		return nil, err
 	}
 
+	if err := dao.DecryptRecordRows(collection, row); err != nil {
+		return nil, err
+	}
+
 	return models.NewRecordFromNullStringMap(collection, row), nil
 }
 
@@ -320,7 +612,33 @@ func (dao *Dao) SuggestUniqueAuthRecordUsername(
 }
 
 // SaveRecord upserts the provided Record model.
+//
+// For collections with enabled history the record is saved
+// in a transaction together with its change history entry.
+//
+// The external collections records cannot be saved.
 func (dao *Dao) SaveRecord(record *models.Record) error {
+	if record.Collection().IsExternal() {
+		return fmt.Errorf("The records of the external collection %q are read-only.", record.Collection().Name)
+	}
+
+	if record.Collection().HistoryOptions().Enabled {
+		return dao.RunInTransaction(func(txDao *Dao) error {
+			return txDao.saveRecordWithHistory(record)
+		})
+	}
+
+	// the descendants paths must be updated together with the record
+	if record.Collection().TreeField() != nil {
+		return dao.RunInTransaction(func(txDao *Dao) error {
+			return txDao.saveRecord(record)
+		})
+	}
+
+	return dao.saveRecord(record)
+}
+
+func (dao *Dao) saveRecord(record *models.Record) error {
 	if record.Collection().IsAuth() {
 		if record.Username() == "" {
 			return errors.New("unable to save auth record without username")
@@ -343,7 +661,65 @@ func (dao *Dao) SaveRecord(record *models.Record) error {
 		}
 	}
 
-	return dao.Save(record)
+	isNew := record.IsNew()
+
+	oldTreePath, err := dao.prepareRecordTreePath(record)
+	if err != nil {
+		return err
+	}
+
+	if err := dao.Save(record); err != nil {
+		return err
+	}
+
+	if err := dao.syncRecordTreeDescendantPaths(record, oldTreePath); err != nil {
+		return err
+	}
+
+	// load the db assigned sequence values
+	if isNew {
+		if err := dao.refreshRecordSequenceFields(record); err != nil {
+			return err
+		}
+	}
+
+	return dao.refreshRecordComputedFields(record)
+}
+
+// refreshRecordComputedFields reloads the computed fields values
+// of the provided (already persisted) record.
+func (dao *Dao) refreshRecordComputedFields(record *models.Record) error {
+	collection := record.Collection()
+
+	columns := recordComputedColumns(collection)
+	if len(columns) == 0 {
+		return nil // no computed fields
+	}
+
+	row := dbx.NullStringMap{}
+
+	err := dao.DB().Select(columns...).
+		From(collection.Name).
+		AndWhere(dbx.HashExp{collection.Name + ".id": record.Id}).
+		Limit(1).
+		One(row)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range collection.Schema.Fields() {
+		if field.Type != schema.FieldTypeComputed {
+			continue
+		}
+
+		if v := row[field.Name]; v.Valid {
+			record.Set(field.Name, v.String)
+		} else {
+			record.Set(field.Name, nil)
+		}
+	}
+
+	return nil
 }
 
 // DeleteRecord deletes the provided Record model.
@@ -352,8 +728,13 @@ func (dao *Dao) SaveRecord(record *models.Record) error {
 // relational records (delete or unset, depending on the rel settings).
 //
 // The delete operation may fail if the record is part of a required
-// reference in another record (aka. cannot be deleted or unset).
+// reference in another record (aka. cannot be deleted or unset)
+// or if the record is from an external collection.
 func (dao *Dao) DeleteRecord(record *models.Record) error {
+	if record.Collection().IsExternal() {
+		return fmt.Errorf("The records of the external collection %q are read-only.", record.Collection().Name)
+	}
+
 	// fetch rel references (if any)
 	//
 	// note: the select is outside of the transaction to minimize
@@ -378,6 +759,34 @@ func (dao *Dao) DeleteRecord(record *models.Record) error {
 					return err
 				}
 			}
+
+			if err := txDao.DeleteRecordPreferences(record); err != nil {
+				return err
+			}
+
+			if err := txDao.DeleteAuthRecordRoles(record); err != nil {
+				return err
+			}
+
+			if err := txDao.DeleteAuthRecordWebauthnCredentials(record); err != nil {
+				return err
+			}
+
+			if err := txDao.DeleteAuthRecordSessions(record); err != nil {
+				return err
+			}
+
+			if err := txDao.DeleteAuthRecordAnonymousAuth(record); err != nil {
+				return err
+			}
+
+			if err := txDao.DeleteAuthRecordLoginLinks(record); err != nil {
+				return err
+			}
+
+			if err := txDao.DeleteAuthRecordPhoneOtps(record); err != nil {
+				return err
+			}
 		}
 
 		// delete the record before the relation references to ensure that there
@@ -386,6 +795,12 @@ func (dao *Dao) DeleteRecord(record *models.Record) error {
 			return err
 		}
 
+		if record.Collection().HistoryOptions().Enabled {
+			if err := txDao.saveRecordHistory(models.RecordHistoryActionDelete, record, nil); err != nil {
+				return err
+			}
+		}
+
 		return txDao.cascadeRecordDelete(record, refs)
 	})
 }
@@ -395,13 +810,13 @@ func (dao *Dao) DeleteRecord(record *models.Record) error {
 //
 // NB! This method is expected to be called inside a transaction.
 func (dao *Dao) cascadeRecordDelete(mainRecord *models.Record, refs map[*models.Collection][]*schema.SchemaField) error {
-	uniqueJsonEachAlias := "__je__" + security.PseudorandomString(4)
+	uniqueJsonEachAlias := "__je__" + dao.RandomString(4)
 
 	for refCollection, fields := range refs {
 		for _, field := range fields {
-			recordTableName := inflector.Columnify(refCollection.Name)
-			prefixedFieldName := recordTableName + "." + inflector.Columnify(field.Name)
-			query := dao.RecordQuery(refCollection).
+			recordTableName := dao.Columnify(refCollection.Name)
+			prefixedFieldName := recordTableName + "." + dao.Columnify(field.Name)
+			query := dao.RecordQueryWithDeleted(refCollection).
 				Distinct(true).
 				LeftJoin(fmt.Sprintf(
 					// note: the case is used to normalize value access for single and multiple relations.
@@ -442,6 +857,10 @@ func (dao *Dao) cascadeRecordDelete(mainRecord *models.Record, refs map[*models.
 						}
 
 						go func() {
+							if err := dao.DecryptRecordRows(refCollection, chunks...); err != nil {
+								ch <- err
+								return
+							}
 							refRecords := models.NewRecordsFromNullStringMaps(refCollection, chunks)
 							ch <- dao.deleteRefRecords(mainRecord, refRecords, field)
 						}()
@@ -470,9 +889,10 @@ func (dao *Dao) cascadeRecordDelete(mainRecord *models.Record, refs map[*models.
 	return nil
 }
 
-// deleteRefRecords checks if related records has to be deleted (if `CascadeDelete` is set)
-// OR
-// just unset the record id from any relation field values (if they are not required).
+// deleteRefRecords applies the relation field delete policy to the provided references:
+//   - restrict - fails the main record delete
+//   - cascade - deletes the related records
+//   - setNull - just unset the record id from the relation field values (if they are not required)
 //
 // NB! This method is expected to be called inside a transaction.
 func (dao *Dao) deleteRefRecords(mainRecord *models.Record, refRecords []*models.Record, field *schema.SchemaField) error {
@@ -481,7 +901,13 @@ func (dao *Dao) deleteRefRecords(mainRecord *models.Record, refRecords []*models
 		return errors.New("relation field options are not initialized")
 	}
 
+	onDelete := options.OnDeleteAction()
+
 	for _, refRecord := range refRecords {
+		if onDelete == schema.RelationOnDeleteRestrict {
+			return fmt.Errorf("the record cannot be deleted because it is referenced by record %s (%s collection)", refRecord.Id, refRecord.Collection().Name)
+		}
+
 		ids := refRecord.GetStringSlice(field.Name)
 
 		// unset the record id
@@ -494,7 +920,7 @@ func (dao *Dao) deleteRefRecords(mainRecord *models.Record, refRecords []*models
 
 		// cascade delete the reference
 		// (only if there are no other active references in case of multiple select)
-		if options.CascadeDelete && len(ids) == 0 {
+		if onDelete == schema.RelationOnDeleteCascade && len(ids) == 0 {
 			if err := dao.DeleteRecord(refRecord); err != nil {
 				return err
 			}
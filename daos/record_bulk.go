@@ -0,0 +1,90 @@
+package daos
+
+import (
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// UpdateRecordsByFilter calls the update callback for each collection
+// record matching the provided query filters in a single transaction
+// (aka. if one of the callbacks fails, none of the changes is applied).
+//
+// The update callback is expected to modify and persist the record
+// with the provided txDao (eg. by calling txDao.SaveRecord(record)).
+//
+// Returns the number of the updated records.
+func (dao *Dao) UpdateRecordsByFilter(
+	collection *models.Collection,
+	update func(txDao *Dao, record *models.Record) error,
+	filters ...func(q *dbx.SelectQuery) error,
+) (int, error) {
+	return dao.eachRecordByFilter(collection, update, filters...)
+}
+
+// DeleteRecordsByFilter deletes all collection records matching
+// the provided query filters in a single transaction.
+//
+// If deleteFunc is nil, the records are permanently deleted
+// (incl. their cascade relations) with [Dao.DeleteRecord].
+//
+// Returns the number of the deleted records.
+func (dao *Dao) DeleteRecordsByFilter(
+	collection *models.Collection,
+	deleteFunc func(txDao *Dao, record *models.Record) error,
+	filters ...func(q *dbx.SelectQuery) error,
+) (int, error) {
+	if deleteFunc == nil {
+		deleteFunc = func(txDao *Dao, record *models.Record) error {
+			return txDao.DeleteRecord(record)
+		}
+	}
+
+	return dao.eachRecordByFilter(collection, deleteFunc, filters...)
+}
+
+// eachRecordByFilter loads all collection records matching the provided
+// query filters and calls fn for each one of them in a single transaction.
+func (dao *Dao) eachRecordByFilter(
+	collection *models.Collection,
+	fn func(txDao *Dao, record *models.Record) error,
+	filters ...func(q *dbx.SelectQuery) error,
+) (int, error) {
+	var total int
+
+	err := dao.RunInTransaction(func(txDao *Dao) error {
+		query := txDao.RecordQuery(collection)
+
+		for _, filter := range filters {
+			if filter == nil {
+				continue
+			}
+			if err := filter(query); err != nil {
+				return err
+			}
+		}
+
+		rows := []dbx.NullStringMap{}
+		if err := query.All(&rows); err != nil {
+			return err
+		}
+
+		if err := txDao.DecryptRecordRows(collection, rows...); err != nil {
+			return err
+		}
+
+		for _, record := range models.NewRecordsFromNullStringMaps(collection, rows) {
+			if err := fn(txDao, record); err != nil {
+				return err
+			}
+			total++
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
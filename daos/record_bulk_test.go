@@ -0,0 +1,112 @@
+package daos_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestUpdateRecordsByFilter(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filter := func(q *dbx.SelectQuery) error {
+		q.AndWhere(dbx.NewExp("[[title]] != 'test3'"))
+		return nil
+	}
+
+	// failure rollback
+	calls := 0
+	total, err := app.Dao().UpdateRecordsByFilter(collection, func(txDao *daos.Dao, record *models.Record) error {
+		calls++
+		if calls == 2 {
+			return errors.New("test")
+		}
+		record.Set("title", record.GetString("title")+"_updated")
+		return txDao.SaveRecord(record)
+	}, filter)
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if total != 0 {
+		t.Fatalf("Expected total 0, got %d", total)
+	}
+	if _, err := app.Dao().FindFirstRecordByData(collection.Id, "title", "test1_updated"); err == nil {
+		t.Fatal("Expected the changes to be rolled back")
+	}
+
+	// successful update
+	total, err = app.Dao().UpdateRecordsByFilter(collection, func(txDao *daos.Dao, record *models.Record) error {
+		record.Set("title", record.GetString("title")+"_updated")
+		return txDao.SaveRecord(record)
+	}, nil, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 {
+		t.Fatalf("Expected total 2, got %d", total)
+	}
+	for _, title := range []string{"test1_updated", "test2_updated", "test3"} {
+		if _, err := app.Dao().FindFirstRecordByData(collection.Id, "title", title); err != nil {
+			t.Errorf("Expected record with title %q to exist", title)
+		}
+	}
+}
+
+func TestDeleteRecordsByFilter(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filter := func(q *dbx.SelectQuery) error {
+		q.AndWhere(dbx.In("id", "achvryl401bhse3", "0yxhwia2amd8gec"))
+		return nil
+	}
+
+	// failure rollback
+	total, err := app.Dao().DeleteRecordsByFilter(collection, func(txDao *daos.Dao, record *models.Record) error {
+		if err := txDao.DeleteRecord(record); err != nil {
+			return err
+		}
+		return errors.New("test")
+	}, filter)
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if total != 0 {
+		t.Fatalf("Expected total 0, got %d", total)
+	}
+	if _, err := app.Dao().FindRecordById(collection.Id, "achvryl401bhse3"); err != nil {
+		t.Fatal("Expected the deletion to be rolled back")
+	}
+
+	// successful delete with the default delete func
+	total, err = app.Dao().DeleteRecordsByFilter(collection, nil, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 {
+		t.Fatalf("Expected total 2, got %d", total)
+	}
+
+	records, err := app.Dao().FindRecordsByExpr(collection.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Id != "llvuca81nly1qls" {
+		t.Fatalf("Expected only llvuca81nly1qls to remain, got %v", records)
+	}
+}
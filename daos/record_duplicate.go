@@ -0,0 +1,85 @@
+package daos
+
+import (
+	"errors"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+// DuplicateRecord creates and persists a copy of the provided record
+// with a new id, copying its stored files to the new record storage path.
+//
+// The fsys argument could be nil only if the record doesn't have any files.
+//
+// The optional modify callback is invoked before persisting the
+// duplicate and could be used to change its field values
+// (eg. the values of unique fields).
+//
+// Auth collection records cannot be duplicated because of their
+// unique and secret system fields.
+func (dao *Dao) DuplicateRecord(
+	record *models.Record,
+	fsys *filesystem.System,
+	modify func(duplicate *models.Record) error,
+) (*models.Record, error) {
+	if record.Collection().IsAuth() {
+		return nil, errors.New("Auth collection records cannot be duplicated.")
+	}
+
+	duplicate := models.NewRecord(record.Collection())
+	duplicate.Load(record.SchemaData())
+	duplicate.RefreshId()
+	duplicate.MarkAsNew()
+
+	if modify != nil {
+		if err := modify(duplicate); err != nil {
+			return nil, err
+		}
+	}
+
+	// copy only the files that are still assigned to the duplicate
+	copied := []string{}
+	for _, field := range record.Collection().Schema.Fields() {
+		if field.Type != schema.FieldTypeFile {
+			continue
+		}
+
+		names := duplicate.GetStringSlice(field.Name)
+
+		for _, name := range record.GetStringSlice(field.Name) {
+			if !list.ExistInSlice(name, names) {
+				continue
+			}
+
+			if fsys == nil {
+				return nil, errors.New("Missing filesystem to copy the record files.")
+			}
+
+			dstKey := duplicate.BaseFilesPath() + "/" + name
+			if err := fsys.Copy(record.BaseFilesPath()+"/"+name, dstKey); err != nil {
+				deleteCopiedFiles(fsys, copied)
+				return nil, err
+			}
+
+			copied = append(copied, dstKey)
+		}
+	}
+
+	if err := dao.SaveRecord(duplicate); err != nil {
+		deleteCopiedFiles(fsys, copied)
+		return nil, err
+	}
+
+	return duplicate, nil
+}
+
+// deleteCopiedFiles removes the provided file keys
+// (used to cleanup after a failed duplication).
+func deleteCopiedFiles(fsys *filesystem.System, fileKeys []string) {
+	for _, key := range fileKeys {
+		fsys.Delete(key)
+	}
+}
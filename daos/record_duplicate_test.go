@@ -0,0 +1,90 @@
+package daos_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestDuplicateRecord(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	fs, err := app.NewFilesystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	// auth record
+	user, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.Dao().DuplicateRecord(user, fs, nil); err == nil {
+		t.Fatal("Expected auth record duplication error, got nil")
+	}
+
+	record, err := app.Dao().FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// missing filesystem
+	if _, err := app.Dao().DuplicateRecord(record, nil, nil); err == nil {
+		t.Fatal("Expected missing filesystem error, got nil")
+	}
+
+	// modify error
+	if _, err := app.Dao().DuplicateRecord(record, fs, func(duplicate *models.Record) error {
+		return errors.New("test")
+	}); err == nil {
+		t.Fatal("Expected modify error, got nil")
+	}
+
+	duplicate, err := app.Dao().DuplicateRecord(record, fs, func(duplicate *models.Record) error {
+		duplicate.Set("text", "duplicated")
+		duplicate.Set("file_many", []string{"test_QZFjKjXchk.txt"})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if duplicate.Id == record.Id {
+		t.Fatal("Expected the duplicate to have a new id")
+	}
+
+	stored, err := app.Dao().FindRecordById("demo1", duplicate.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stored.GetString("text") != "duplicated" {
+		t.Fatalf("Expected text %q, got %q", "duplicated", stored.GetString("text"))
+	}
+
+	if stored.GetString("url") != record.GetString("url") {
+		t.Fatalf("Expected url %q, got %q", record.GetString("url"), stored.GetString("url"))
+	}
+
+	expectedFiles := map[string]bool{
+		"test_QZFjKjXchk.txt": true,
+		"300_WlbFWSGmW9.png":  false, // removed from the duplicate
+		"logo_vcfJJG5TAh.svg": false, // removed from the duplicate
+		"test_d61b33QdDU.txt": true,  // file_one
+	}
+	for name, expected := range expectedFiles {
+		exists, _ := fs.Exists(stored.BaseFilesPath() + "/" + name)
+		if exists != expected {
+			t.Errorf("Expected %s to exist %v, got %v", name, expected, exists)
+		}
+
+		// the original files should remain untouched
+		if exists, _ := fs.Exists(record.BaseFilesPath() + "/" + name); !exists {
+			t.Errorf("Expected original %s to exist", name)
+		}
+	}
+}
@@ -0,0 +1,453 @@
+package daos
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/spf13/cast"
+)
+
+// encryptedValuePrefix is the prefix of all encrypted record column
+// values (the full format is "enc:v{keyVersion}:{cipherText}").
+const encryptedValuePrefix = "enc:v"
+
+// IsEncryptedRecordField checks whether the provided collection field
+// values are stored encrypted.
+//
+// The encrypted type fields are always encrypted. The other schema fields
+// are encrypted only if they are encryptable and the collection has
+// enabled records encryption (see [IsEncryptableRecordField]).
+func IsEncryptedRecordField(collection *models.Collection, field *schema.SchemaField) bool {
+	if field.Type == schema.FieldTypeEncrypted {
+		return true
+	}
+
+	return collection.EncryptionOptions().Enabled && IsEncryptableRecordField(field)
+}
+
+// HasEncryptedRecordFields checks whether the provided collection
+// has at least one field with encrypted values.
+func HasEncryptedRecordFields(collection *models.Collection) bool {
+	for _, field := range collection.Schema.Fields() {
+		if IsEncryptedRecordField(collection, field) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsEncryptableRecordField checks whether the provided field values
+// could be encrypted (aka. is not unique, relation, file, computed or sequence field).
+func IsEncryptableRecordField(field *schema.SchemaField) bool {
+	if field.Unique {
+		return false
+	}
+
+	switch field.Type {
+	case schema.FieldTypeRelation, schema.FieldTypeFile, schema.FieldTypeComputed, schema.FieldTypeSequence:
+		return false
+	}
+
+	return true
+}
+
+// FindRecordsEncryptionKeys returns the records encryption keys
+// metadata of all collections (mapped by their collection id).
+func (dao *Dao) FindRecordsEncryptionKeys() (map[string]*models.RecordsEncryptionKey, error) {
+	result := map[string]*models.RecordsEncryptionKey{}
+
+	param, err := dao.FindParamByKey(models.ParamRecordsEncryptionKeys)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return result, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(param.Value, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindRecordsEncryptionKey returns the records encryption key
+// metadata of the provided collection (nil if there is no key yet).
+func (dao *Dao) FindRecordsEncryptionKey(collection *models.Collection) (*models.RecordsEncryptionKey, error) {
+	keys, err := dao.FindRecordsEncryptionKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	return keys[collection.Id], nil
+}
+
+// RotateRecordsEncryptionKey creates a new version of the collection
+// records encryption key and reencrypts all collection records with it.
+func (dao *Dao) RotateRecordsEncryptionKey(collection *models.Collection) error {
+	if !HasEncryptedRecordFields(collection) {
+		return fmt.Errorf("the %q collection doesn't have encrypted fields", collection.Name)
+	}
+
+	return dao.RunInTransaction(func(txDao *Dao) error {
+		key, err := txDao.FindRecordsEncryptionKey(collection)
+		if err != nil {
+			return err
+		}
+
+		version := 1
+		if key != nil {
+			version = key.Version + 1
+		}
+
+		if _, err := txDao.saveRecordsEncryptionKey(collection, version); err != nil {
+			return err
+		}
+
+		return txDao.syncRecordsEncryption(collection)
+	})
+}
+
+// DecryptRecordRows decrypts in place the encrypted column values of
+// the provided raw collection records rows.
+//
+// Plain (aka. not encrypted) values are left untouched.
+func (dao *Dao) DecryptRecordRows(collection *models.Collection, rows ...dbx.NullStringMap) error {
+	if !HasEncryptedRecordFields(collection) {
+		return nil
+	}
+
+	return dao.decryptRecordRows(collection, true, rows...)
+}
+
+// decryptRecordRows decrypts in place the encrypted column values of
+// the provided raw records rows regardless of the collection
+// encryption state.
+//
+// If onlyEncryptable is false, the values of all schema fields are checked
+// (eg. to handle fields that were encrypted before becoming unique).
+func (dao *Dao) decryptRecordRows(collection *models.Collection, onlyEncryptable bool, rows ...dbx.NullStringMap) error {
+	keys := map[int]string{}
+
+	var meta *models.RecordsEncryptionKey
+	var metaLoaded bool
+
+	for _, row := range rows {
+		for name, value := range row {
+			if !value.Valid || !strings.HasPrefix(value.String, encryptedValuePrefix) {
+				continue
+			}
+
+			field := collection.Schema.GetFieldByName(name)
+			if field == nil || (onlyEncryptable && !IsEncryptableRecordField(field)) {
+				continue
+			}
+
+			version, cipherText, err := splitEncryptedValue(value.String)
+			if err != nil {
+				return err
+			}
+
+			if _, ok := keys[version]; !ok {
+				if !metaLoaded {
+					if meta, err = dao.FindRecordsEncryptionKey(collection); err != nil {
+						return err
+					}
+					metaLoaded = true
+				}
+
+				legacy := meta != nil && meta.IsLegacyVersion(version)
+
+				if keys[version], err = dao.recordsEncryptionKey(collection, version, legacy); err != nil {
+					return err
+				}
+			}
+
+			plain, err := security.Decrypt(cipherText, keys[version])
+			if err != nil {
+				return fmt.Errorf("failed to decrypt %s.%s: %w", collection.Name, name, err)
+			}
+
+			value.String = string(plain)
+			row[name] = value
+		}
+	}
+
+	return nil
+}
+
+// encryptRecordColumns encrypts in place the dataMap values of the
+// record encrypted fields.
+//
+// The dataMap is expected to be the record [models.Record.ColumnValueMap] result.
+func (dao *Dao) encryptRecordColumns(record *models.Record, dataMap map[string]any) error {
+	collection := record.Collection()
+
+	if !HasEncryptedRecordFields(collection) {
+		return nil
+	}
+
+	key, err := dao.FindRecordsEncryptionKey(collection)
+	if err != nil {
+		return err
+	}
+
+	var derivedKey string
+	var version int
+	if key == nil {
+		version = 1
+		if derivedKey, err = dao.saveRecordsEncryptionKey(collection, version); err != nil {
+			return err
+		}
+	} else {
+		version = key.Version
+		if derivedKey, err = dao.recordsEncryptionKey(collection, version, key.IsLegacyVersion(version)); err != nil {
+			return err
+		}
+		if recordsEncryptionKeyFingerprint(derivedKey) != key.Fingerprint {
+			return fmt.Errorf("the records encryption master key doesn't match with the %q collection key", collection.Name)
+		}
+	}
+
+	for _, field := range collection.Schema.Fields() {
+		if !IsEncryptedRecordField(collection, field) {
+			continue
+		}
+
+		plain, ok, err := recordColumnString(dataMap[field.Name])
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue // null
+		}
+
+		cipherText, err := security.Encrypt([]byte(plain), derivedKey)
+		if err != nil {
+			return err
+		}
+
+		dataMap[field.Name] = encryptedValuePrefix + strconv.Itoa(version) + ":" + cipherText
+	}
+
+	return nil
+}
+
+// syncRecordsEncryption reencrypts (or decrypts if the encryption
+// is disabled) the stored values of all collection records.
+//
+// Note that the records are updated directly, without triggering
+// the model hooks and without changing the records "updated" date.
+func (dao *Dao) syncRecordsEncryption(collection *models.Collection) error {
+	rows := []dbx.NullStringMap{}
+	if err := dao.RecordQueryWithDeleted(collection).All(&rows); err != nil {
+		return err
+	}
+
+	columns := []string{}
+	for _, field := range collection.Schema.Fields() {
+		if field.Type != schema.FieldTypeComputed {
+			columns = append(columns, field.Name)
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil
+	}
+
+	for _, row := range rows {
+		if err := dao.decryptRecordRows(collection, false, row); err != nil {
+			return err
+		}
+
+		record := models.NewRecordFromNullStringMap(collection, row)
+
+		dataMap := record.ColumnValueMap()
+		if err := dao.encryptRecordColumns(record, dataMap); err != nil {
+			return err
+		}
+
+		params := dbx.Params{}
+		for _, name := range columns {
+			params[name] = dataMap[name]
+		}
+
+		_, err := dao.NonconcurrentDB().Update(
+			collection.Name,
+			params,
+			dbx.HashExp{"id": record.Id},
+		).Execute()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recordsEncryptionChanged checks whether the stored records values
+// of newCollection need to be reencrypted or decrypted after an update
+// (eg. on encryption toggle or on change of the encrypted fields).
+func recordsEncryptionChanged(oldCollection, newCollection *models.Collection) bool {
+	if oldCollection == nil {
+		return false // new collection without records
+	}
+
+	oldEnabled := oldCollection.EncryptionOptions().Enabled
+	newEnabled := newCollection.EncryptionOptions().Enabled
+
+	if oldEnabled != newEnabled {
+		return true
+	}
+
+	if !newEnabled {
+		return false
+	}
+
+	encryptedFields := func(collection *models.Collection) string {
+		names := []string{}
+		for _, field := range collection.Schema.Fields() {
+			if IsEncryptableRecordField(field) {
+				names = append(names, field.Id)
+			}
+		}
+		return strings.Join(names, ",")
+	}
+
+	return encryptedFields(oldCollection) != encryptedFields(newCollection)
+}
+
+// saveRecordsEncryptionKey creates or updates the collection records
+// encryption key metadata with the provided version and returns
+// the new derived key.
+//
+// The new key is always derived with HKDF-SHA256 and the previous
+// legacy key versions (if any) are tracked to be able to decrypt
+// the not yet reencrypted values.
+func (dao *Dao) saveRecordsEncryptionKey(collection *models.Collection, version int) (string, error) {
+	derivedKey, err := dao.recordsEncryptionKey(collection, version, false)
+	if err != nil {
+		return "", err
+	}
+
+	keys, err := dao.FindRecordsEncryptionKeys()
+	if err != nil {
+		return "", err
+	}
+
+	var legacyVersion int
+	if old := keys[collection.Id]; old != nil {
+		if old.Kdf == "" {
+			legacyVersion = old.Version
+		} else {
+			legacyVersion = old.LegacyVersion
+		}
+	}
+
+	keys[collection.Id] = &models.RecordsEncryptionKey{
+		Version:       version,
+		Fingerprint:   recordsEncryptionKeyFingerprint(derivedKey),
+		Rotated:       types.NowDateTime(),
+		Kdf:           models.RecordsEncryptionKdfHkdf,
+		LegacyVersion: legacyVersion,
+	}
+
+	if err := dao.SaveParam(models.ParamRecordsEncryptionKeys, keys); err != nil {
+		return "", err
+	}
+
+	return derivedKey, nil
+}
+
+// deleteRecordsEncryptionKey removes the collection records
+// encryption key metadata (if exists).
+func (dao *Dao) deleteRecordsEncryptionKey(collection *models.Collection) error {
+	keys, err := dao.FindRecordsEncryptionKeys()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := keys[collection.Id]; !ok {
+		return nil
+	}
+
+	delete(keys, collection.Id)
+
+	return dao.SaveParam(models.ParamRecordsEncryptionKeys, keys)
+}
+
+// recordsEncryptionKey derives the collection records encryption key
+// with the specified version from the dao master key.
+//
+// Set legacy to derive the key with the deprecated
+// [security.DeriveLegacyKey] (for the keys created before HKDF).
+func (dao *Dao) recordsEncryptionKey(collection *models.Collection, version int, legacy bool) (string, error) {
+	if len(dao.encryptionKey) != 32 {
+		return "", fmt.Errorf("missing or invalid records encryption master key (the %q collection has enabled encryption)", collection.Name)
+	}
+
+	info := collection.Id + ":" + strconv.Itoa(version)
+
+	if legacy {
+		return security.DeriveLegacyKey(dao.encryptionKey, info), nil
+	}
+
+	return security.DeriveKey(dao.encryptionKey, info), nil
+}
+
+func recordsEncryptionKeyFingerprint(derivedKey string) string {
+	h := sha256.Sum256([]byte(derivedKey))
+	return hex.EncodeToString(h[:])
+}
+
+// splitEncryptedValue extracts the key version and the cipher text
+// from the provided encrypted column value.
+func splitEncryptedValue(value string) (int, string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(value, encryptedValuePrefix), ":", 2)
+	if len(parts) != 2 {
+		return 0, "", errors.New("invalid encrypted value format")
+	}
+
+	version, err := strconv.Atoi(parts[0])
+	if err != nil || version <= 0 {
+		return 0, "", errors.New("invalid encrypted value key version")
+	}
+
+	return version, parts[1], nil
+}
+
+// recordColumnString returns the raw string representation of the
+// provided record column value (as it would be stored in the db).
+//
+// Returns false for null values.
+func recordColumnString(value any) (string, bool, error) {
+	if v, ok := value.(driver.Valuer); ok {
+		raw, err := v.Value()
+		if err != nil {
+			return "", false, err
+		}
+		value = raw
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "", false, nil
+	case []byte:
+		return string(v), true, nil
+	}
+
+	return cast.ToString(value), true, nil
+}
@@ -0,0 +1,369 @@
+package daos_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+const testRecordsEncryptionKey = "abcdabcdabcdabcdabcdabcdabcdabcd"
+
+func rawRecordColumn(t *testing.T, dao *daos.Dao, table, id, column string) string {
+	var result string
+
+	err := dao.DB().Select(column).From(table).AndWhere(dbx.HashExp{"id": id}).Row(&result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return result
+}
+
+func TestIsEncryptedRecordField(t *testing.T) {
+	collection := &models.Collection{}
+
+	scenarios := []struct {
+		field    *schema.SchemaField
+		enabled  bool
+		expected bool
+	}{
+		{&schema.SchemaField{Type: schema.FieldTypeText}, false, false},
+		{&schema.SchemaField{Type: schema.FieldTypeText}, true, true},
+		{&schema.SchemaField{Type: schema.FieldTypeJson}, true, true},
+		{&schema.SchemaField{Type: schema.FieldTypeText, Unique: true}, true, false},
+		{&schema.SchemaField{Type: schema.FieldTypeRelation}, true, false},
+		{&schema.SchemaField{Type: schema.FieldTypeFile}, true, false},
+		{&schema.SchemaField{Type: schema.FieldTypeComputed}, true, false},
+		{&schema.SchemaField{Type: schema.FieldTypeEncrypted}, false, true},
+		{&schema.SchemaField{Type: schema.FieldTypeEncrypted}, true, true},
+	}
+
+	for i, s := range scenarios {
+		collection.SetOptions(map[string]any{"encryption": map[string]any{"enabled": s.enabled}})
+
+		if result := daos.IsEncryptedRecordField(collection, s.field); result != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+}
+
+func TestRecordsEncryption(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dao := app.Dao()
+	dao.SetEncryptionKey(testRecordsEncryptionKey)
+
+	before, err := dao.FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection := tests.ToggleCollectionOption(t, dao, "demo1", "encryption", true)
+
+	// existing records should be encrypted
+	for _, column := range []string{"text", "bool", "number", "select_many", "json"} {
+		raw := rawRecordColumn(t, dao, "demo1", "84nmscqy84lsi1t", column)
+		if !strings.HasPrefix(raw, "enc:v1:") {
+			t.Fatalf("Expected %s to be encrypted, got %q", column, raw)
+		}
+	}
+
+	// non-encryptable fields should be left untouched
+	if raw := rawRecordColumn(t, dao, "demo1", "84nmscqy84lsi1t", "rel_one"); strings.HasPrefix(raw, "enc:") {
+		t.Fatalf("Expected rel_one to not be encrypted, got %q", raw)
+	}
+
+	key, err := dao.FindRecordsEncryptionKey(collection)
+	if err != nil || key == nil {
+		t.Fatalf("Expected the collection key metadata to be created, got %v (%v)", key, err)
+	}
+	if key.Version != 1 || key.Fingerprint == "" || key.Kdf != models.RecordsEncryptionKdfHkdf {
+		t.Fatalf("Unexpected key metadata %v", key)
+	}
+
+	// the records should be transparently decrypted
+	after, err := dao.FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	beforeJson, _ := before.MarshalJSON()
+	afterJson, _ := after.MarshalJSON()
+	if string(beforeJson) != string(afterJson) {
+		t.Fatalf("Expected the decrypted record to be\n%s\ngot\n%s", beforeJson, afterJson)
+	}
+
+	// save new record
+	record := models.NewRecord(collection)
+	record.Set("text", "new")
+	record.Set("number", 12.5)
+	if err := dao.SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+	if raw := rawRecordColumn(t, dao, "demo1", record.Id, "text"); !strings.HasPrefix(raw, "enc:v1:") {
+		t.Fatalf("Expected the new record text to be encrypted, got %q", raw)
+	}
+	records, err := dao.FindRecordsByExpr("demo1", dbx.HashExp{"id": record.Id})
+	if err != nil || len(records) != 1 {
+		t.Fatalf("Failed to load the new record: %v", err)
+	}
+	if records[0].GetString("text") != "new" || records[0].GetFloat("number") != 12.5 {
+		t.Fatalf("Unexpected new record data %v", records[0])
+	}
+
+	// encrypted fields can't be used for distinct values
+	if _, err := dao.FindDistinctRecordValues(collection, "text"); err == nil {
+		t.Fatal("Expected distinct values error for encrypted field")
+	}
+
+	// rotate
+	if err := dao.RotateRecordsEncryptionKey(collection); err != nil {
+		t.Fatal(err)
+	}
+	if raw := rawRecordColumn(t, dao, "demo1", "84nmscqy84lsi1t", "text"); !strings.HasPrefix(raw, "enc:v2:") {
+		t.Fatalf("Expected the text to be reencrypted with the new key version, got %q", raw)
+	}
+	key, _ = dao.FindRecordsEncryptionKey(collection)
+	if key == nil || key.Version != 2 {
+		t.Fatalf("Expected key version 2, got %v", key)
+	}
+	rotated, err := dao.FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated.GetString("text") != "test" || rotated.GetFloat("number") != 123456 {
+		t.Fatalf("Unexpected rotated record data %v", rotated)
+	}
+
+	// disable
+	tests.ToggleCollectionOption(t, dao, "demo1", "encryption", false)
+	if raw := rawRecordColumn(t, dao, "demo1", "84nmscqy84lsi1t", "text"); raw != "test" {
+		t.Fatalf("Expected the text to be decrypted, got %q", raw)
+	}
+	disabled, err := dao.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dao.RotateRecordsEncryptionKey(disabled); err == nil {
+		t.Fatal("Expected rotation error for collection with disabled encryption")
+	}
+}
+
+func TestEncryptedFieldType(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dao := app.Dao()
+	dao.SetEncryptionKey(testRecordsEncryptionKey)
+
+	collection, err := dao.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if daos.HasEncryptedRecordFields(collection) {
+		t.Fatal("Expected the collection to not have encrypted fields")
+	}
+
+	collection.Schema.AddField(&schema.SchemaField{
+		Name: "secret",
+		Type: schema.FieldTypeEncrypted,
+	})
+	if err := dao.SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+	if !daos.HasEncryptedRecordFields(collection) {
+		t.Fatal("Expected the collection to have encrypted fields")
+	}
+
+	record := models.NewRecord(collection)
+	record.Set("title", "new")
+	record.Set("secret", "test_secret")
+	if err := dao.SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	// only the encrypted type field should be encrypted
+	if raw := rawRecordColumn(t, dao, "demo2", record.Id, "secret"); !strings.HasPrefix(raw, "enc:v1:") {
+		t.Fatalf("Expected the secret to be encrypted, got %q", raw)
+	}
+	if raw := rawRecordColumn(t, dao, "demo2", record.Id, "title"); raw != "new" {
+		t.Fatalf("Expected the title to not be encrypted, got %q", raw)
+	}
+
+	found, err := dao.FindRecordById("demo2", record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := found.GetString("secret"); v != "test_secret" {
+		t.Fatalf("Expected the decrypted secret %q, got %q", "test_secret", v)
+	}
+
+	// encrypted fields can't be used for distinct values
+	if _, err := dao.FindDistinctRecordValues(collection, "secret"); err == nil {
+		t.Fatal("Expected distinct values error for encrypted field")
+	}
+
+	// rotate
+	if err := dao.RotateRecordsEncryptionKey(collection); err != nil {
+		t.Fatal(err)
+	}
+	if raw := rawRecordColumn(t, dao, "demo2", record.Id, "secret"); !strings.HasPrefix(raw, "enc:v2:") {
+		t.Fatalf("Expected the secret to be reencrypted with the new key version, got %q", raw)
+	}
+	if raw := rawRecordColumn(t, dao, "demo2", record.Id, "title"); raw != "new" {
+		t.Fatalf("Expected the title to remain unencrypted after rotation, got %q", raw)
+	}
+
+	// toggling the collection encryption shouldn't decrypt the field
+	tests.ToggleCollectionOption(t, dao, "demo2", "encryption", true)
+	tests.ToggleCollectionOption(t, dao, "demo2", "encryption", false)
+	if raw := rawRecordColumn(t, dao, "demo2", record.Id, "secret"); !strings.HasPrefix(raw, "enc:") {
+		t.Fatalf("Expected the secret to remain encrypted, got %q", raw)
+	}
+	if raw := rawRecordColumn(t, dao, "demo2", record.Id, "title"); raw != "new" {
+		t.Fatalf("Expected the title to not be encrypted, got %q", raw)
+	}
+}
+
+func TestRecordsEncryptionInvalidMasterKey(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dao := app.Dao()
+
+	// missing master key
+	collection, err := dao.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	collection.Options["encryption"] = map[string]any{"enabled": true}
+	if err := dao.SaveCollection(collection); err == nil {
+		t.Fatal("Expected error when enabling encryption without master key")
+	}
+
+	dao.SetEncryptionKey(testRecordsEncryptionKey)
+	collection = tests.ToggleCollectionOption(t, dao, "demo1", "encryption", true)
+
+	// different master key
+	dao.SetEncryptionKey("bcdabcdabcdabcdabcdabcdabcdabcda")
+
+	if _, err := dao.FindRecordById("demo1", "84nmscqy84lsi1t"); err == nil {
+		t.Fatal("Expected decryption error")
+	}
+
+	record := models.NewRecord(collection)
+	record.Set("text", "new")
+	if err := dao.SaveRecord(record); err == nil {
+		t.Fatal("Expected encryption error due to fingerprint mismatch")
+	}
+}
+
+func TestRecordsEncryptionLegacyKey(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dao := app.Dao()
+	dao.SetEncryptionKey(testRecordsEncryptionKey)
+
+	collection, err := dao.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// key metadata created with the legacy derivation
+	legacyKey := security.DeriveLegacyKey(testRecordsEncryptionKey, collection.Id+":1")
+	fingerprint := sha256.Sum256([]byte(legacyKey))
+	err = dao.SaveParam(models.ParamRecordsEncryptionKeys, map[string]*models.RecordsEncryptionKey{
+		collection.Id: {Version: 1, Fingerprint: hex.EncodeToString(fingerprint[:])},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection = tests.ToggleCollectionOption(t, dao, "demo1", "encryption", true)
+
+	raw := rawRecordColumn(t, dao, "demo1", "84nmscqy84lsi1t", "text")
+	plain, err := security.Decrypt(strings.TrimPrefix(raw, "enc:v1:"), legacyKey)
+	if err != nil || string(plain) != "test" {
+		t.Fatalf("Expected the text to be encrypted with the legacy key, got %q (%v)", plain, err)
+	}
+
+	record, err := dao.FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := record.GetString("text"); v != "test" {
+		t.Fatalf("Expected the legacy encrypted text to be decrypted, got %q", v)
+	}
+
+	// the legacy key is still used until rotation
+	record.Set("text", "updated")
+	if err := dao.SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dao.RotateRecordsEncryptionKey(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	key, _ := dao.FindRecordsEncryptionKey(collection)
+	if key == nil || key.Version != 2 || key.Kdf != models.RecordsEncryptionKdfHkdf || key.LegacyVersion != 1 {
+		t.Fatalf("Expected upgraded key metadata, got %v", key)
+	}
+	if raw := rawRecordColumn(t, dao, "demo1", "84nmscqy84lsi1t", "text"); !strings.HasPrefix(raw, "enc:v2:") {
+		t.Fatalf("Expected the text to be reencrypted with the new key version, got %q", raw)
+	}
+
+	rotated, err := dao.FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := rotated.GetString("text"); v != "updated" {
+		t.Fatalf("Expected the rotated text %q, got %q", "updated", v)
+	}
+}
+
+func TestDeleteCollectionRecordsEncryptionKey(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dao := app.Dao()
+	dao.SetEncryptionKey(testRecordsEncryptionKey)
+
+	collection := &models.Collection{
+		Name: "encrypted",
+		Schema: schema.NewSchema(
+			&schema.SchemaField{Name: "title", Type: schema.FieldTypeText},
+		),
+	}
+	collection.SetOptions(map[string]any{"encryption": map[string]any{"enabled": true}})
+	if err := dao.SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	record := models.NewRecord(collection)
+	record.Set("title", "test")
+	if err := dao.SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if key, _ := dao.FindRecordsEncryptionKey(collection); key == nil {
+		t.Fatal("Expected the collection key metadata to be created")
+	}
+
+	if err := dao.DeleteCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	if key, _ := dao.FindRecordsEncryptionKey(collection); key != nil {
+		t.Fatalf("Expected the collection key metadata to be deleted, got %v", key)
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/models/schema"
-	"github.com/pocketbase/pocketbase/tools/inflector"
 	"github.com/pocketbase/pocketbase/tools/list"
 	"github.com/pocketbase/pocketbase/tools/security"
 	"github.com/pocketbase/pocketbase/tools/types"
@@ -99,7 +98,7 @@ func (dao *Dao) expandRecords(records []*models.Record, expandPath string, fetch
 
 		indirectRecords, err := dao.FindRecordsByExpr(
 			indirectRel.Id,
-			dbx.In(inflector.Columnify(matches[2]), recordIds...),
+			dbx.In(dao.Columnify(matches[2]), recordIds...),
 		)
 		if err != nil {
 			return err
@@ -0,0 +1,128 @@
+package daos
+
+import (
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+// RecordsFilterPlanItem defines a single SQLite "EXPLAIN QUERY PLAN" row.
+type RecordsFilterPlanItem struct {
+	Id     int    `db:"id" json:"id"`
+	Parent int    `db:"parent" json:"parent"`
+	Detail string `db:"detail" json:"detail"`
+}
+
+// RecordsFilterPlan defines the query plan of a records filter query.
+type RecordsFilterPlan struct {
+	// Sql is the raw explained records query.
+	Sql string `json:"sql"`
+
+	// Plan is the list with the raw "EXPLAIN QUERY PLAN" rows.
+	Plan []*RecordsFilterPlanItem `json:"plan"`
+
+	// Tables is the list with the scanned or searched tables
+	// (and their aliases) in the order of the query loops.
+	Tables []string `json:"tables"`
+
+	// FullScans is the list with the tables (and their aliases)
+	// that are fully scanned, aka. without using an index.
+	FullScans []string `json:"fullScans"`
+
+	// Indexes is the list with the unique names of the used indexes.
+	Indexes []string `json:"indexes"`
+
+	// TempBTrees is the list with the plan details that require a
+	// temporary b-tree (usually for ORDER BY, GROUP BY and DISTINCT).
+	TempBTrees []string `json:"tempBTrees"`
+}
+
+// ExplainRecordsFilter runs the collection records query through
+// SQLite "EXPLAIN QUERY PLAN" and returns the parsed query plan.
+//
+// optFilters could be used to apply the filter conditions, joins, etc.
+// to the explained records query (usually via a search.FieldResolver).
+func (dao *Dao) ExplainRecordsFilter(
+	collection *models.Collection,
+	optFilters ...func(q *dbx.SelectQuery) error,
+) (*RecordsFilterPlan, error) {
+	query := dao.RecordQuery(collection)
+
+	for _, filter := range optFilters {
+		if filter == nil {
+			continue
+		}
+		if err := filter(query); err != nil {
+			return nil, err
+		}
+	}
+
+	builtQuery := query.Build()
+
+	result := &RecordsFilterPlan{
+		Sql:        builtQuery.SQL(),
+		Plan:       []*RecordsFilterPlanItem{},
+		Tables:     []string{},
+		FullScans:  []string{},
+		Indexes:    []string{},
+		TempBTrees: []string{},
+	}
+
+	err := dao.DB().NewQuery("EXPLAIN QUERY PLAN " + builtQuery.SQL()).
+		Bind(builtQuery.Params()).
+		All(&result.Plan)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range result.Plan {
+		parts := strings.Fields(item.Detail)
+		if len(parts) < 2 {
+			continue
+		}
+
+		switch parts[0] {
+		case "SCAN", "SEARCH":
+			// older SQLite versions prefix the table name with "TABLE"
+			if parts[1] == "TABLE" && len(parts) > 2 {
+				parts = parts[1:]
+			}
+
+			// constant rows and subqueries are not tables
+			if parts[1] == "CONSTANT" || parts[1] == "SUBQUERY" {
+				continue
+			}
+
+			table := parts[1]
+			result.Tables = append(result.Tables, table)
+
+			usingIndex := false
+			for i := 2; i < len(parts); i++ {
+				switch parts[i] {
+				case "PRIMARY", "ROWID", "VIRTUAL":
+					usingIndex = true
+				case "INDEX":
+					usingIndex = true
+					if i+1 < len(parts) && !strings.HasPrefix(parts[i+1], "(") && !list.ExistInSlice(parts[i+1], result.Indexes) {
+						result.Indexes = append(result.Indexes, parts[i+1])
+					}
+				}
+				if usingIndex {
+					break
+				}
+			}
+
+			if parts[0] == "SCAN" && !usingIndex {
+				result.FullScans = append(result.FullScans, table)
+			}
+		case "USE":
+			if strings.Contains(item.Detail, "TEMP B-TREE") {
+				result.TempBTrees = append(result.TempBTrees, item.Detail)
+			}
+		}
+	}
+
+	return result, nil
+}
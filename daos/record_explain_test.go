@@ -0,0 +1,125 @@
+package daos_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestExplainRecordsFilter(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name              string
+		filter            func(q *dbx.SelectQuery) error
+		expectError       bool
+		expectedTables    []string
+		expectedFullScans []string
+		expectedIndexes   []string
+		expectedTempBTree bool
+	}{
+		{
+			"filter error",
+			func(q *dbx.SelectQuery) error {
+				return errors.New("test")
+			},
+			true,
+			nil,
+			nil,
+			nil,
+			false,
+		},
+		{
+			"invalid query",
+			func(q *dbx.SelectQuery) error {
+				q.AndWhere(dbx.NewExp("[[missing]] = 1"))
+				return nil
+			},
+			true,
+			nil,
+			nil,
+			nil,
+			false,
+		},
+		{
+			"no filter",
+			nil,
+			false,
+			[]string{"demo2"},
+			[]string{"demo2"},
+			[]string{},
+			false,
+		},
+		{
+			"non-indexed column with sort",
+			func(q *dbx.SelectQuery) error {
+				q.AndWhere(dbx.HashExp{"title": "test1"}).OrderBy("active ASC")
+				return nil
+			},
+			false,
+			[]string{"demo2"},
+			[]string{"demo2"},
+			[]string{},
+			true,
+		},
+		{
+			"indexed column",
+			func(q *dbx.SelectQuery) error {
+				q.AndWhere(dbx.NewExp("[[demo2.created]] > {:date}", dbx.Params{"date": "2022-01-01"}))
+				return nil
+			},
+			false,
+			[]string{"demo2"},
+			[]string{},
+			[]string{"_sz5l5z67tg7gku0_created_idx"},
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		plan, err := app.Dao().ExplainRecordsFilter(collection, s.filter)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		if !strings.Contains(plan.Sql, "FROM `demo2`") {
+			t.Errorf("[%s] Expected the records query sql, got %s", s.name, plan.Sql)
+		}
+
+		if len(plan.Plan) == 0 {
+			t.Errorf("[%s] Expected at least 1 plan item", s.name)
+		}
+
+		if strings.Join(plan.Tables, ",") != strings.Join(s.expectedTables, ",") {
+			t.Errorf("[%s] Expected tables %v, got %v", s.name, s.expectedTables, plan.Tables)
+		}
+
+		if strings.Join(plan.FullScans, ",") != strings.Join(s.expectedFullScans, ",") {
+			t.Errorf("[%s] Expected full scans %v, got %v", s.name, s.expectedFullScans, plan.FullScans)
+		}
+
+		if strings.Join(plan.Indexes, ",") != strings.Join(s.expectedIndexes, ",") {
+			t.Errorf("[%s] Expected indexes %v, got %v", s.name, s.expectedIndexes, plan.Indexes)
+		}
+
+		if hasTempBTree := len(plan.TempBTrees) > 0; hasTempBTree != s.expectedTempBTree {
+			t.Errorf("[%s] Expected temp b-tree %v, got %v", s.name, s.expectedTempBTree, plan.TempBTrees)
+		}
+	}
+}
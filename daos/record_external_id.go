@@ -0,0 +1,117 @@
+package daos
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+// FindRecordByExternalId finds the collection record with the
+// provided external system primary key.
+//
+// Returns an error if the collection doesn't have enabled external ids.
+func (dao *Dao) FindRecordByExternalId(collectionNameOrId string, externalId string) (*models.Record, error) {
+	collection, err := dao.FindCollectionByNameOrId(collectionNameOrId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !collection.ExternalIdOptions().Enabled {
+		return nil, errors.New("The collection doesn't have enabled external ids.")
+	}
+
+	if externalId == "" {
+		return nil, errors.New("Missing external id.")
+	}
+
+	row := dbx.NullStringMap{}
+
+	err = dao.RecordQuery(collection).
+		AndWhere(dbx.HashExp{collection.Name + "." + schema.FieldNameExternalId: externalId}).
+		Limit(1).
+		One(row)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dao.DecryptRecordRows(collection, row); err != nil {
+		return nil, err
+	}
+
+	return models.NewRecordFromNullStringMap(collection, row), nil
+}
+
+// UpsertRecordByExternalId updates the collection record with the
+// provided external id or creates a new one if there is no such record.
+//
+// The data is loaded on top of the existing record fields, allowing
+// repeated syncs from an external system to be idempotent.
+//
+// Note that this method doesn't perform any data validation.
+func (dao *Dao) UpsertRecordByExternalId(
+	collection *models.Collection,
+	externalId string,
+	data map[string]any,
+) (*models.Record, error) {
+	var record *models.Record
+
+	err := dao.RunInTransaction(func(txDao *Dao) error {
+		var findErr error
+		record, findErr = txDao.FindRecordByExternalId(collection.Id, externalId)
+		if findErr != nil {
+			if !errors.Is(findErr, sql.ErrNoRows) {
+				return findErr
+			}
+
+			record = models.NewRecord(collection)
+		}
+
+		record.Load(data)
+		record.SetExternalId(externalId) // prevent overwriting from the data
+
+		return txDao.SaveRecord(record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// syncRecordExternalIdColumn adds the external id column and its
+// unique index to the collection records table (if missing).
+//
+// Similar to the soft delete column, the external id column is never dropped.
+func (dao *Dao) syncRecordExternalIdColumn(collection *models.Collection) error {
+	if !collection.ExternalIdOptions().Enabled {
+		return nil
+	}
+
+	columns, err := dao.GetTableColumns(collection.Name)
+	if err != nil {
+		return err
+	}
+
+	if list.ExistInSlice(schema.FieldNameExternalId, columns) {
+		return nil // already exists
+	}
+
+	_, err = dao.DB().AddColumn(collection.Name, schema.FieldNameExternalId, "TEXT DEFAULT '' NOT NULL").Execute()
+	if err != nil {
+		return err
+	}
+
+	_, err = dao.DB().NewQuery(
+		"CREATE UNIQUE INDEX {{" + recordSchemaObjectName(collection, "_"+collection.Id+"_"+schema.FieldNameExternalId+"_idx") + "}} " +
+			"ON {{" + collection.Name + "}} ([[" + schema.FieldNameExternalId + "]]) " +
+			"WHERE [[" + schema.FieldNameExternalId + "]] != ''",
+	).Execute()
+
+	return err
+}
@@ -0,0 +1,83 @@
+package daos_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+func TestRecordExternalId(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dao := app.Dao()
+
+	collection, err := dao.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// disabled external ids
+	if _, err := dao.FindRecordByExternalId(collection.Id, "ext1"); err == nil {
+		t.Fatal("Expected error for collection without enabled external ids")
+	}
+
+	collection.Options["externalId"] = map[string]any{"enabled": true}
+	if err := dao.SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	columns, err := dao.GetTableColumns(collection.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !list.ExistInSlice(schema.FieldNameExternalId, columns) {
+		t.Fatalf("Expected the %q column to be added, got %v", schema.FieldNameExternalId, columns)
+	}
+
+	// the existing records have empty external id
+	// and shouldn't conflict with the unique index
+	if _, err := dao.FindRecordByExternalId(collection.Id, ""); err == nil {
+		t.Fatal("Expected error for empty external id")
+	}
+
+	// create
+	created, err := dao.UpsertRecordByExternalId(collection, "ext1", map[string]any{"title": "new"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ExternalId() != "ext1" {
+		t.Fatalf("Expected external id %q, got %q", "ext1", created.ExternalId())
+	}
+
+	// update (the external id from the data should be ignored)
+	updated, err := dao.UpsertRecordByExternalId(collection, "ext1", map[string]any{
+		"title":                    "new2",
+		schema.FieldNameExternalId: "ext2",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Id != created.Id {
+		t.Fatalf("Expected the record %q to be updated, got %q", created.Id, updated.Id)
+	}
+
+	found, err := dao.FindRecordByExternalId(collection.Id, "ext1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Id != created.Id || found.GetString("title") != "new2" || found.ExternalId() != "ext1" {
+		t.Fatalf("Expected the updated record, got %v", found)
+	}
+
+	// duplicated external id
+	duplicate := models.NewRecord(collection)
+	duplicate.Set("title", "duplicate")
+	duplicate.SetExternalId("ext1")
+	if err := dao.SaveRecord(duplicate); err == nil {
+		t.Fatal("Expected unique constraint error for duplicated external id")
+	}
+}
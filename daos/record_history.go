@@ -0,0 +1,217 @@
+package daos
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// RecordHistoryQuery returns a new RecordHistory select query.
+func (dao *Dao) RecordHistoryQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.RecordHistory{})
+}
+
+// FindRecordHistory returns all history entries of a single
+// collection record (ordered by their version).
+func (dao *Dao) FindRecordHistory(collectionId string, recordId string) ([]*models.RecordHistory, error) {
+	entries := []*models.RecordHistory{}
+
+	err := dao.RecordHistoryQuery().
+		AndWhere(dbx.HashExp{
+			"collectionId": collectionId,
+			"recordId":     recordId,
+		}).
+		OrderBy("version ASC").
+		All(&entries)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// FindRecordHistoryByVersion finds a single record history entry
+// by its collection id, record id and version.
+func (dao *Dao) FindRecordHistoryByVersion(collectionId string, recordId string, version int) (*models.RecordHistory, error) {
+	model := &models.RecordHistory{}
+
+	err := dao.RecordHistoryQuery().
+		AndWhere(dbx.HashExp{
+			"collectionId": collectionId,
+			"recordId":     recordId,
+			"version":      version,
+		}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// RevertRecordToVersion restores the record schema fields to their
+// state from the specified record history version and persists the record
+// (which creates a new "update" history entry).
+//
+// Schema fields that didn't exist at the time of the history entry are left untouched.
+// Note that the files of the reverted file fields are not restored
+// if they were already deleted from the storage.
+func (dao *Dao) RevertRecordToVersion(record *models.Record, version int) error {
+	collection := record.Collection()
+
+	if !collection.HistoryOptions().Enabled {
+		return fmt.Errorf("the %q collection history is not enabled", collection.Name)
+	}
+
+	entry, err := dao.FindRecordHistoryByVersion(collection.Id, record.Id, version)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range recordHistoryFields(collection) {
+		if value, ok := entry.Data[field.Name]; ok {
+			record.Set(field.Name, value)
+		}
+	}
+
+	return dao.SaveRecord(record)
+}
+
+// saveRecordWithHistory persists the provided record and stores
+// a history entry with its changed schema fields.
+//
+// NB! This method is expected to be called inside a transaction.
+func (dao *Dao) saveRecordWithHistory(record *models.Record) error {
+	action := models.RecordHistoryActionCreate
+
+	var oldRecord *models.Record
+	if !record.IsNew() {
+		action = models.RecordHistoryActionUpdate
+
+		var err error
+		oldRecord, err = dao.FindRecordByIdWithDeleted(record.Collection().Id, record.Id)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := dao.saveRecord(record); err != nil {
+		return err
+	}
+
+	return dao.saveRecordHistory(action, oldRecord, record)
+}
+
+// saveRecordHistory creates a new history entry with the schema fields
+// changes between the oldRecord and newRecord states.
+//
+// oldRecord is expected to be nil for the "create" action and newRecord - for "delete".
+// Updates without schema field changes (eg. password change) are not stored.
+func (dao *Dao) saveRecordHistory(action string, oldRecord, newRecord *models.Record) error {
+	record := newRecord
+	if record == nil {
+		record = oldRecord
+	}
+
+	collection := record.Collection()
+
+	changes := types.JsonMap{}
+	data := types.JsonMap{}
+
+	for _, field := range recordHistoryFields(collection) {
+		// normalize the values since the not loaded fields are nil
+		var oldValue, newValue any
+		if oldRecord != nil {
+			oldValue = field.PrepareValue(oldRecord.Get(field.Name))
+		}
+		if newRecord != nil {
+			newValue = field.PrepareValue(newRecord.Get(field.Name))
+			data[field.Name] = newValue
+		} else {
+			data[field.Name] = oldValue
+		}
+
+		equal, err := recordHistoryValuesEqual(oldValue, newValue)
+		if err != nil {
+			return err
+		}
+
+		if !equal {
+			changes[field.Name] = map[string]any{"old": oldValue, "new": newValue}
+		}
+	}
+
+	if action == models.RecordHistoryActionUpdate && len(changes) == 0 {
+		return nil
+	}
+
+	var version int
+	err := dao.RecordHistoryQuery().
+		Select("COALESCE(MAX([[version]]), 0)").
+		AndWhere(dbx.HashExp{
+			"collectionId": collection.Id,
+			"recordId":     record.Id,
+		}).
+		Row(&version)
+	if err != nil {
+		return err
+	}
+
+	authType, authId := record.HistoryAuth()
+
+	return dao.Save(&models.RecordHistory{
+		CollectionId: collection.Id,
+		RecordId:     record.Id,
+		Version:      version + 1,
+		Action:       action,
+		AuthType:     authType,
+		AuthId:       authId,
+		Changes:      changes,
+		Data:         data,
+	})
+}
+
+// IsRecordHistoryField checks whether the changes of the provided
+// schema field are tracked by the records history (aka. all non-computed fields).
+//
+// The encrypted type fields are also excluded because
+// the history entries are stored as plain json.
+func IsRecordHistoryField(field *schema.SchemaField) bool {
+	return field.Type != schema.FieldTypeComputed && field.Type != schema.FieldTypeEncrypted
+}
+
+// recordHistoryFields returns the collection schema fields
+// tracked by the records history.
+func recordHistoryFields(collection *models.Collection) []*schema.SchemaField {
+	result := []*schema.SchemaField{}
+
+	for _, field := range collection.Schema.Fields() {
+		if IsRecordHistoryField(field) {
+			result = append(result, field)
+		}
+	}
+
+	return result
+}
+
+func recordHistoryValuesEqual(a, b any) (bool, error) {
+	rawA, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+
+	rawB, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(rawA, rawB), nil
+}
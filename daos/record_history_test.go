@@ -0,0 +1,155 @@
+package daos_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestRecordHistoryDisabled(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dao := app.Dao()
+
+	record, err := dao.FindRecordById("demo2", "achvryl401bhse3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record.Set("title", "changed")
+	if err := dao.SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := dao.FindRecordHistory(record.Collection().Id, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("Expected no history entries, got %d", len(entries))
+	}
+
+	if err := dao.RevertRecordToVersion(record, 1); err == nil {
+		t.Fatal("Expected error for collection with disabled history")
+	}
+}
+
+func TestRecordHistory(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dao := app.Dao()
+
+	collection := tests.ToggleCollectionOption(t, dao, "demo2", "history", true)
+
+	// create
+	record := models.NewRecord(collection)
+	record.Set("title", "history1")
+	record.SetHistoryAuth(models.RecordHistoryAuthAdmin, "sywbhecnh46rhm0")
+	if err := dao.SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	// update
+	record.Set("title", "history2")
+	if err := dao.SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	// update without changes
+	if err := dao.SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := dao.FindRecordHistory(collection.Id, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 history entries, got %d", len(entries))
+	}
+
+	scenarios := []struct {
+		action       string
+		oldTitle     any
+		newTitle     any
+		totalChanges int
+	}{
+		{models.RecordHistoryActionCreate, nil, "history1", 2},
+		{models.RecordHistoryActionUpdate, "history1", "history2", 1},
+	}
+
+	for i, s := range scenarios {
+		entry := entries[i]
+
+		if entry.Version != i+1 {
+			t.Errorf("(%d) Expected version %d, got %d", i, i+1, entry.Version)
+		}
+		if entry.Action != s.action {
+			t.Errorf("(%d) Expected action %q, got %q", i, s.action, entry.Action)
+		}
+		if entry.AuthType != models.RecordHistoryAuthAdmin || entry.AuthId != "sywbhecnh46rhm0" {
+			t.Errorf("(%d) Unexpected history auth %q %q", i, entry.AuthType, entry.AuthId)
+		}
+
+		change, _ := entry.Changes["title"].(map[string]any)
+		if change["old"] != s.oldTitle || change["new"] != s.newTitle {
+			t.Errorf("(%d) Unexpected title change %v", i, entry.Changes["title"])
+		}
+		if len(entry.Changes) != s.totalChanges {
+			t.Errorf("(%d) Expected %d changed fields, got %v", i, s.totalChanges, entry.Changes)
+		}
+		if entry.Data["title"] != s.newTitle {
+			t.Errorf("(%d) Expected title %v in the data snapshot, got %v", i, s.newTitle, entry.Data["title"])
+		}
+	}
+
+	// revert
+	if err := dao.RevertRecordToVersion(record, 1); err != nil {
+		t.Fatal(err)
+	}
+	reverted, err := dao.FindRecordById(collection.Id, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reverted.GetString("title") != "history1" {
+		t.Fatalf("Expected the reverted title to be history1, got %q", reverted.GetString("title"))
+	}
+	if err := dao.RevertRecordToVersion(record, 10); err == nil {
+		t.Fatal("Expected error for missing history version")
+	}
+
+	// delete
+	if err := dao.DeleteRecord(reverted); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err = dao.FindRecordHistory(collection.Id, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("Expected 4 history entries, got %d", len(entries))
+	}
+
+	deleted := entries[3]
+	if deleted.Action != models.RecordHistoryActionDelete || deleted.Version != 4 {
+		t.Fatalf("Expected delete entry with version 4, got %q %d", deleted.Action, deleted.Version)
+	}
+	if deleted.Data["title"] != "history1" {
+		t.Fatalf("Expected the delete entry to have the last record data, got %v", deleted.Data)
+	}
+	if deleted.AuthType != "" || deleted.AuthId != "" {
+		t.Fatalf("Expected empty delete history auth, got %q %q", deleted.AuthType, deleted.AuthId)
+	}
+
+	found, err := dao.FindRecordHistoryByVersion(collection.Id, record.Id, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Id != entries[1].Id {
+		t.Fatalf("Expected entry %q, got %q", entries[1].Id, found.Id)
+	}
+}
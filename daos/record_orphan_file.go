@@ -0,0 +1,77 @@
+package daos
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// FindOrphanRecordFileKeys returns the subset of the provided storage
+// file keys that are not referenced by any existing record.
+//
+// A "collectionId/recordId/filename" file key (or its
+// "collectionId/recordId/thumbs_filename/..." thumbs) is orphan if the
+// collection or the record are missing, or if none of the record file
+// fields contains the filename.
+//
+// Keys that don't match the records file layout are never orphan.
+func (dao *Dao) FindOrphanRecordFileKeys(fileKeys []string) ([]string, error) {
+	result := []string{}
+
+	collections := map[string]*models.Collection{}
+	records := map[string]*models.Record{}
+
+	for _, key := range fileKeys {
+		parts := strings.SplitN(key, "/", 4)
+		if len(parts) < 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			continue // not a record file
+		}
+
+		collectionId, recordId, filename := parts[0], parts[1], parts[2]
+		if len(parts) == 4 {
+			if !strings.HasPrefix(filename, "thumbs_") {
+				continue // not a record file or thumb
+			}
+			filename = strings.TrimPrefix(filename, "thumbs_")
+		}
+
+		collection, ok := collections[collectionId]
+		if !ok {
+			var err error
+			collection, err = dao.FindCollectionByNameOrId(collectionId)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return nil, err
+			}
+			// the collection directories are always named after their id
+			if collection != nil && collection.Id != collectionId {
+				collection = nil
+			}
+			collections[collectionId] = collection
+		}
+
+		if collection == nil {
+			result = append(result, key)
+			continue
+		}
+
+		recordKey := collectionId + "/" + recordId
+
+		record, ok := records[recordKey]
+		if !ok {
+			var err error
+			record, err = dao.FindRecordByIdWithDeleted(collection.Id, recordId)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return nil, err
+			}
+			records[recordKey] = record
+		}
+
+		if record == nil || record.FindFileFieldByFile(filename) == nil {
+			result = append(result, key)
+		}
+	}
+
+	return result, nil
+}
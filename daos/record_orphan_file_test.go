@@ -0,0 +1,43 @@
+package daos_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestFindOrphanRecordFileKeys(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	keys := []string{
+		"test.txt",
+		"a/b",
+		"wsmn24bux7wo113/84nmscqy84lsi1t/test_d61b33QdDU.txt",
+		"wsmn24bux7wo113/84nmscqy84lsi1t/thumbs_300_WlbFWSGmW9.png/100x100_300_WlbFWSGmW9.png",
+		"wsmn24bux7wo113/84nmscqy84lsi1t/other/test.txt",
+		"wsmn24bux7wo113/84nmscqy84lsi1t/missing.txt",
+		"wsmn24bux7wo113/84nmscqy84lsi1t/thumbs_missing.png/100x100_missing.png",
+		"wsmn24bux7wo113/missing/test_d61b33QdDU.txt",
+		"demo1/84nmscqy84lsi1t/test_d61b33QdDU.txt",
+		"missing/84nmscqy84lsi1t/test_d61b33QdDU.txt",
+	}
+
+	orphans, err := app.Dao().FindOrphanRecordFileKeys(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"wsmn24bux7wo113/84nmscqy84lsi1t/missing.txt",
+		"wsmn24bux7wo113/84nmscqy84lsi1t/thumbs_missing.png/100x100_missing.png",
+		"wsmn24bux7wo113/missing/test_d61b33QdDU.txt",
+		"demo1/84nmscqy84lsi1t/test_d61b33QdDU.txt",
+		"missing/84nmscqy84lsi1t/test_d61b33QdDU.txt",
+	}
+
+	if strings.Join(orphans, ",") != strings.Join(expected, ",") {
+		t.Fatalf("Expected orphans \n%v, \ngot \n%v", expected, orphans)
+	}
+}
@@ -0,0 +1,81 @@
+package daos
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+// FindAuthRecordByPhone finds the auth record associated with the provided phone number.
+//
+// Returns an error if it is not an auth collection with enabled
+// phone auth or the record is not found.
+func (dao *Dao) FindAuthRecordByPhone(collectionNameOrId string, phone string) (*models.Record, error) {
+	collection, err := dao.FindCollectionByNameOrId(collectionNameOrId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch auth collection %q (%w)", collectionNameOrId, err)
+	}
+	if !collection.IsAuth() {
+		return nil, fmt.Errorf("%q is not an auth collection", collectionNameOrId)
+	}
+	if !collection.AuthOptions().AllowPhoneAuth {
+		return nil, fmt.Errorf("%q doesn't have enabled phone auth", collectionNameOrId)
+	}
+
+	if phone == "" {
+		return nil, errors.New("Missing phone number.")
+	}
+
+	row := dbx.NullStringMap{}
+
+	err = dao.RecordQuery(collection).
+		AndWhere(dbx.HashExp{collection.Name + "." + schema.FieldNamePhone: phone}).
+		Limit(1).
+		One(row)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dao.DecryptRecordRows(collection, row); err != nil {
+		return nil, err
+	}
+
+	return models.NewRecordFromNullStringMap(collection, row), nil
+}
+
+// syncRecordPhoneColumn adds the phone column and its unique
+// index to the auth collection records table (if missing).
+//
+// Similar to the external id column, the phone column is never dropped.
+func (dao *Dao) syncRecordPhoneColumn(collection *models.Collection) error {
+	if !collection.IsAuth() || !collection.AuthOptions().AllowPhoneAuth {
+		return nil
+	}
+
+	columns, err := dao.GetTableColumns(collection.Name)
+	if err != nil {
+		return err
+	}
+
+	if list.ExistInSlice(schema.FieldNamePhone, columns) {
+		return nil // already exists
+	}
+
+	_, err = dao.DB().AddColumn(collection.Name, schema.FieldNamePhone, "TEXT DEFAULT '' NOT NULL").Execute()
+	if err != nil {
+		return err
+	}
+
+	_, err = dao.DB().NewQuery(
+		"CREATE UNIQUE INDEX {{" + recordSchemaObjectName(collection, "_"+collection.Id+"_"+schema.FieldNamePhone+"_idx") + "}} " +
+			"ON {{" + collection.Name + "}} ([[" + schema.FieldNamePhone + "]]) " +
+			"WHERE [[" + schema.FieldNamePhone + "]] != ''",
+	).Execute()
+
+	return err
+}
@@ -0,0 +1,91 @@
+package daos_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+func TestFindAuthRecordByPhone(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dao := app.Dao()
+
+	collection, err := dao.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// non-auth collection
+	if _, err := dao.FindAuthRecordByPhone("demo2", "+359888123456"); err == nil {
+		t.Fatal("Expected error for non-auth collection")
+	}
+
+	// disabled phone auth
+	if _, err := dao.FindAuthRecordByPhone(collection.Id, "+359888123456"); err == nil {
+		t.Fatal("Expected error for collection without enabled phone auth")
+	}
+
+	collection.Options["allowPhoneAuth"] = true
+	if err := dao.SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	columns, err := dao.GetTableColumns(collection.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !list.ExistInSlice(schema.FieldNamePhone, columns) {
+		t.Fatalf("Expected the %q column to be added, got %v", schema.FieldNamePhone, columns)
+	}
+
+	record, err := dao.FindRecordById(collection.Id, "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+	record.SetPhone("+359888123456")
+	if err := dao.SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	// empty phone
+	if _, err := dao.FindAuthRecordByPhone(collection.Id, ""); err == nil {
+		t.Fatal("Expected error for empty phone")
+	}
+
+	// missing phone
+	if _, err := dao.FindAuthRecordByPhone(collection.Id, "+359888000000"); err == nil {
+		t.Fatal("Expected error for missing phone")
+	}
+
+	found, err := dao.FindAuthRecordByPhone(collection.Id, "+359888123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Id != record.Id || found.Phone() != "+359888123456" {
+		t.Fatalf("Expected record %q, got %v", record.Id, found)
+	}
+
+	// duplicated phone
+	duplicate, err := dao.FindRecordById(collection.Id, "oap640cot4yru2s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	duplicate.SetPhone("+359888123456")
+	if err := dao.SaveRecord(duplicate); err == nil {
+		t.Fatal("Expected unique constraint error for duplicated phone")
+	}
+
+	// the other records with empty phone shouldn't conflict
+	empty := models.NewRecord(collection)
+	empty.SetEmail("new_phone@example.com")
+	empty.SetUsername("new_phone")
+	empty.SetPassword("1234567890")
+	if err := dao.SaveRecord(empty); err != nil {
+		t.Fatalf("Expected the record with empty phone to be saved, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package daos
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/settings"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// RecordPreferencesQuery returns a new RecordPreferences select query.
+func (dao *Dao) RecordPreferencesQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.RecordPreferences{})
+}
+
+// FindRecordPreferences returns the stored preferences of the provided
+// auth record or a new unsaved RecordPreferences model if there are none.
+func (dao *Dao) FindRecordPreferences(authRecord *models.Record) (*models.RecordPreferences, error) {
+	model := &models.RecordPreferences{}
+
+	err := dao.RecordPreferencesQuery().
+		AndWhere(dbx.HashExp{
+			"collectionId": authRecord.Collection().Id,
+			"recordId":     authRecord.Id,
+		}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		model = &models.RecordPreferences{
+			CollectionId: authRecord.Collection().Id,
+			RecordId:     authRecord.Id,
+		}
+	}
+
+	if model.Data == nil {
+		model.Data = types.JsonMap{}
+	}
+
+	return model, nil
+}
+
+// FindResolvedRecordPreferences returns the provided auth record
+// preferences merged with the defaults of the declared preference keys.
+func (dao *Dao) FindResolvedRecordPreferences(authRecord *models.Record) (map[string]any, error) {
+	model, err := dao.FindRecordPreferences(authRecord)
+	if err != nil {
+		return nil, err
+	}
+
+	config := settings.PreferencesConfig{Keys: dao.PreferenceKeys()}
+
+	return config.Resolve(model.Data), nil
+}
+
+// SaveRecordPreferences upserts the provided RecordPreferences model.
+func (dao *Dao) SaveRecordPreferences(preferences *models.RecordPreferences) error {
+	return dao.Save(preferences)
+}
+
+// DeleteRecordPreferences deletes the stored preferences
+// of the provided auth record (if any).
+func (dao *Dao) DeleteRecordPreferences(authRecord *models.Record) error {
+	_, err := dao.DB().Delete((&models.RecordPreferences{}).TableName(), dbx.HashExp{
+		"collectionId": authRecord.Collection().Id,
+		"recordId":     authRecord.Id,
+	}).Execute()
+
+	return err
+}
@@ -0,0 +1,81 @@
+package daos_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models/settings"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestRecordPreferences(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().Preferences.Keys = []settings.PreferenceKey{
+		{Key: "theme", Type: settings.PreferenceTypeSelect, Values: []string{"light", "dark"}, Default: "light"},
+		{Key: "newsletter", Type: settings.PreferenceTypeBool, Default: false},
+	}
+
+	dao := app.Dao()
+
+	if keys := dao.PreferenceKeys(); len(keys) != 2 {
+		t.Fatalf("Expected 2 preference keys, got %v", keys)
+	}
+
+	user, err := dao.FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// new preferences
+	prefs, err := dao.FindRecordPreferences(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !prefs.IsNew() || prefs.RecordId != user.Id || prefs.CollectionId != user.Collection().Id {
+		t.Fatalf("Expected new user preferences model, got %v", prefs)
+	}
+
+	resolved, err := dao.FindResolvedRecordPreferences(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJson(t, resolved, `{"newsletter":false,"theme":"light"}`)
+
+	// save
+	prefs.Data["theme"] = "dark"
+	if err := dao.SaveRecordPreferences(prefs); err != nil {
+		t.Fatal(err)
+	}
+
+	resolved, err = dao.FindResolvedRecordPreferences(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJson(t, resolved, `{"newsletter":false,"theme":"dark"}`)
+
+	// delete together with the auth record
+	if err := dao.DeleteRecord(user); err != nil {
+		t.Fatal(err)
+	}
+
+	total := 0
+	if err := dao.RecordPreferencesQuery().Select("count(*)").Row(&total); err != nil {
+		t.Fatal(err)
+	}
+	if total != 0 {
+		t.Fatalf("Expected the preferences to be deleted, found %d", total)
+	}
+}
+
+func assertJson(t *testing.T, value any, expected string) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(encoded) != expected {
+		t.Fatalf("Expected %s, got %s", expected, encoded)
+	}
+}
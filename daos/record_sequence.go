@@ -0,0 +1,191 @@
+package daos
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+)
+
+// syncRecordSequenceFields creates the unique index and the insert
+// trigger of each collection sequence field and assigns values to the
+// existing records of the newly added sequence fields.
+//
+// The values are assigned by the trigger (aka. at db level) from a
+// per collection field counter stored in the `_sequences` table,
+// so they are never reused even after a record deletion.
+func (dao *Dao) syncRecordSequenceFields(newCollection *models.Collection, oldCollection *models.Collection) error {
+	tableName := newCollection.Name
+
+	for _, field := range newCollection.Schema.Fields() {
+		if field.Type != schema.FieldTypeSequence {
+			continue
+		}
+
+		if oldCollection == nil || oldCollection.Schema.GetFieldById(field.Id) == nil {
+			if err := dao.initRecordSequenceField(newCollection, field); err != nil {
+				return err
+			}
+		}
+
+		_, err := dao.DB().NewQuery(fmt.Sprintf(
+			"CREATE UNIQUE INDEX IF NOT EXISTS %s ON {{%s}} ([[%s]])",
+			recordSequenceIndexName(newCollection, field),
+			tableName,
+			field.Name,
+		)).Execute()
+		if err != nil {
+			return err
+		}
+
+		// (re)create the trigger to ensure that it references
+		// the current table and column names
+		_, err = dao.DB().NewQuery(fmt.Sprintf(
+			`
+			DROP TRIGGER IF EXISTS %[1]s;
+			CREATE TRIGGER %[1]s AFTER INSERT ON {{%[2]s}} FOR EACH ROW WHEN NEW.[[%[3]s]] IS NULL
+			BEGIN
+				INSERT INTO {{_sequences}} ([[collectionId]], [[fieldId]], [[value]]) VALUES (%[4]s, %[5]s, 1)
+					ON CONFLICT ([[collectionId]], [[fieldId]]) DO UPDATE SET [[value]] = [[value]] + 1;
+				UPDATE {{%[2]s}} SET [[%[3]s]] = (
+					SELECT [[value]] FROM {{_sequences}} WHERE [[collectionId]] = %[4]s AND [[fieldId]] = %[5]s
+				) WHERE [[rowid]] = NEW.[[rowid]];
+			END;
+			`,
+			recordSequenceTriggerName(newCollection, field),
+			tableName,
+			field.Name,
+			quoteSqlString(newCollection.Id),
+			quoteSqlString(field.Id),
+		)).Execute()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// initRecordSequenceField numbers the existing collection records
+// (ordered by their creation date) and initializes the field counter.
+func (dao *Dao) initRecordSequenceField(collection *models.Collection, field *schema.SchemaField) error {
+	_, err := dao.DB().NewQuery(fmt.Sprintf(
+		`
+		UPDATE {{%[1]s}} SET [[%[2]s]] = (
+			SELECT COUNT(*) FROM {{%[1]s}} AS [[prev]]
+			WHERE [[prev.created]] < {{%[1]s}}.[[created]] OR (
+				[[prev.created]] = {{%[1]s}}.[[created]] AND [[prev.rowid]] <= {{%[1]s}}.[[rowid]]
+			)
+		)
+		`,
+		collection.Name,
+		field.Name,
+	)).Execute()
+	if err != nil {
+		return err
+	}
+
+	_, err = dao.DB().NewQuery(fmt.Sprintf(
+		`
+		INSERT INTO {{_sequences}} ([[collectionId]], [[fieldId]], [[value]])
+		SELECT {:collectionId}, {:fieldId}, COUNT(*) FROM {{%s}} WHERE TRUE
+		ON CONFLICT ([[collectionId]], [[fieldId]]) DO UPDATE SET [[value]] = excluded.[[value]]
+		`,
+		collection.Name,
+	)).Bind(dbx.Params{
+		"collectionId": collection.Id,
+		"fieldId":      field.Id,
+	}).Execute()
+
+	return err
+}
+
+// dropRecordSequenceTriggers drops the insert triggers
+// of all collection sequence fields.
+//
+// The triggers must be dropped before renaming or dropping
+// the columns they reference.
+func (dao *Dao) dropRecordSequenceTriggers(collection *models.Collection) error {
+	for _, field := range collection.Schema.Fields() {
+		if field.Type != schema.FieldTypeSequence {
+			continue
+		}
+
+		_, err := dao.DB().NewQuery("DROP TRIGGER IF EXISTS " + recordSequenceTriggerName(collection, field)).Execute()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dropRecordSequenceField drops the unique index and
+// the counter of the provided collection sequence field.
+func (dao *Dao) dropRecordSequenceField(collection *models.Collection, field *schema.SchemaField) error {
+	_, err := dao.DB().NewQuery("DROP INDEX IF EXISTS " + recordSequenceIndexName(collection, field)).Execute()
+	if err != nil {
+		return err
+	}
+
+	_, err = dao.DB().Delete("_sequences", dbx.HashExp{
+		"collectionId": collection.Id,
+		"fieldId":      field.Id,
+	}).Execute()
+
+	return err
+}
+
+// refreshRecordSequenceFields reloads the db assigned sequence
+// fields values of the provided (already persisted) record.
+func (dao *Dao) refreshRecordSequenceFields(record *models.Record) error {
+	collection := record.Collection()
+
+	columns := []string{}
+	for _, field := range collection.Schema.Fields() {
+		if field.Type == schema.FieldTypeSequence {
+			columns = append(columns, field.Name)
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil // no sequence fields
+	}
+
+	row := dbx.NullStringMap{}
+
+	err := dao.DB().Select(columns...).
+		From(collection.Name).
+		AndWhere(dbx.HashExp{"id": record.Id}).
+		Limit(1).
+		One(row)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range columns {
+		if v := row[name]; v.Valid {
+			record.Set(name, v.String)
+		} else {
+			record.Set(name, nil)
+		}
+	}
+
+	return nil
+}
+
+func recordSequenceIndexName(collection *models.Collection, field *schema.SchemaField) string {
+	return fmt.Sprintf("_%s_%s_seq_idx", collection.Id, field.Id)
+}
+
+func recordSequenceTriggerName(collection *models.Collection, field *schema.SchemaField) string {
+	return fmt.Sprintf("_%s_%s_seq_trigger", collection.Id, field.Id)
+}
+
+// quoteSqlString quotes the provided string as sql string literal
+// (bind params are not allowed in the triggers body).
+func quoteSqlString(str string) string {
+	return "'" + strings.ReplaceAll(str, "'", "''") + "'"
+}
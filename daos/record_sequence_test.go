@@ -0,0 +1,148 @@
+package daos_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+func TestRecordSequenceField(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dao := app.Dao()
+
+	collection, err := dao.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	field := &schema.SchemaField{
+		Id:   "seq_field",
+		Name: "number",
+		Type: schema.FieldTypeSequence,
+	}
+	collection.Schema.AddField(field)
+	if err := dao.SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	// the existing records should be numbered by their created date
+	existing := []struct {
+		Id     string
+		Number int
+	}{}
+	err = dao.DB().Select("id", "number").
+		From(collection.Name).
+		OrderBy("created ASC").
+		All(&existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(existing) == 0 {
+		t.Fatal("Expected existing records")
+	}
+	for i, row := range existing {
+		if row.Number != i+1 {
+			t.Fatalf("Expected existing record %q to have number %d, got %d", row.Id, i+1, row.Number)
+		}
+	}
+
+	assertNextNumber := func(title string, expected int) *models.Record {
+		record := models.NewRecord(collection)
+		record.Set("title", title)
+		record.Set("number", 100) // should be ignored
+		if err := dao.SaveRecord(record); err != nil {
+			t.Fatal(err)
+		}
+
+		if v := record.GetInt("number"); v != expected {
+			t.Fatalf("Expected record %q number %d, got %d", title, expected, v)
+		}
+
+		stored, err := dao.FindRecordById(collection.Id, record.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v := stored.GetInt("number"); v != expected {
+			t.Fatalf("Expected stored record %q number %d, got %d", title, expected, v)
+		}
+
+		return record
+	}
+
+	lastRecord := assertNextNumber("new1", len(existing)+1)
+
+	// the number shouldn't change on update
+	lastRecord.Set("title", "new1_updated")
+	if err := dao.SaveRecord(lastRecord); err != nil {
+		t.Fatal(err)
+	}
+	if v := lastRecord.GetInt("number"); v != len(existing)+1 {
+		t.Fatalf("Expected the number to remain %d after update, got %d", len(existing)+1, v)
+	}
+
+	// the numbers shouldn't be reused after delete
+	if err := dao.DeleteRecord(lastRecord); err != nil {
+		t.Fatal(err)
+	}
+	assertNextNumber("new2", len(existing)+2)
+
+	// the sequence should continue after renaming the collection and the field
+	collection.Name = "demo2_renamed"
+	collection.Schema.GetFieldById(field.Id).Name = "number_renamed"
+	if err := dao.SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+	collection.Schema.GetFieldById(field.Id).Name = "number"
+	if err := dao.SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+	assertNextNumber("new3", len(existing)+3)
+
+	// the numbers should be unique at db level
+	_, err = dao.DB().Update(collection.Name, dbx.Params{"number": 1}, dbx.HashExp{"title": "new3"}).Execute()
+	if err == nil {
+		t.Fatal("Expected duplicated number error, got nil")
+	}
+
+	// remove the field
+	collection.Schema.RemoveField(field.Id)
+	if err := dao.SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	columns, err := dao.GetTableColumns(collection.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.ExistInSlice("number", columns) {
+		t.Fatalf("Expected the number column to be dropped, got %v", columns)
+	}
+
+	var total int
+	dao.DB().Select("count(*)").From("_sequences").AndWhere(dbx.HashExp{"fieldId": field.Id}).Row(&total)
+	if total != 0 {
+		t.Fatalf("Expected the field counter to be deleted, got %d", total)
+	}
+
+	// records should be still creatable
+	record := models.NewRecord(collection)
+	record.Set("title", "new4")
+	if err := dao.SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecordSequenceFieldNotEncryptable(t *testing.T) {
+	field := &schema.SchemaField{Type: schema.FieldTypeSequence}
+
+	if daos.IsEncryptableRecordField(field) {
+		t.Fatal("Expected the sequence field to not be encryptable")
+	}
+}
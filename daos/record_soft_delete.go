@@ -0,0 +1,164 @@
+package daos
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// FindRecordByIdWithDeleted finds the Record model by its id
+// no matter whether it is soft deleted or not.
+func (dao *Dao) FindRecordByIdWithDeleted(collectionNameOrId string, recordId string) (*models.Record, error) {
+	collection, err := dao.FindCollectionByNameOrId(collectionNameOrId)
+	if err != nil {
+		return nil, err
+	}
+
+	row := dbx.NullStringMap{}
+
+	err = dao.RecordQueryWithDeleted(collection).
+		AndWhere(dbx.HashExp{collection.Name + ".id": recordId}).
+		Limit(1).
+		One(row)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dao.DecryptRecordRows(collection, row); err != nil {
+		return nil, err
+	}
+
+	return models.NewRecordFromNullStringMap(collection, row), nil
+}
+
+// SoftDeleteRecord marks the provided record as deleted without
+// removing it from the db (aka. it could be restored later with [Dao.RestoreRecord]).
+//
+// Returns an error if the record collection doesn't have enabled soft delete.
+//
+// Note that the record is updated directly without triggering the
+// model hooks and without cascade deleting its relation references.
+func (dao *Dao) SoftDeleteRecord(record *models.Record) error {
+	return dao.setRecordDeleted(record, types.NowDateTime())
+}
+
+// RestoreRecord unmarks the provided soft deleted record.
+//
+// Returns an error if the record collection doesn't have enabled soft delete.
+func (dao *Dao) RestoreRecord(record *models.Record) error {
+	return dao.setRecordDeleted(record, types.DateTime{})
+}
+
+// PurgeDeletedRecords permanently deletes (incl. their files and
+// cascade relations) all soft deleted collection records marked
+// before the specified date (zero date means all soft deleted records).
+//
+// Returns the number of the purged records.
+func (dao *Dao) PurgeDeletedRecords(collection *models.Collection, before types.DateTime) (int, error) {
+	if !collection.SoftDeleteOptions().Enabled {
+		return 0, fmt.Errorf("the %q collection soft delete is not enabled", collection.Name)
+	}
+
+	deletedColumn := fmt.Sprintf("[[%s.%s]]", collection.Name, schema.FieldNameDeleted)
+
+	query := dao.RecordQueryWithDeleted(collection).
+		Select(collection.Name + ".id").
+		AndWhere(dbx.NewExp(deletedColumn + " != ''"))
+
+	if !before.IsZero() {
+		query.AndWhere(dbx.NewExp(deletedColumn+" <= {:before}", dbx.Params{"before": before.String()}))
+	}
+
+	ids := []string{}
+	if err := query.Column(&ids); err != nil {
+		return 0, err
+	}
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	rows := []dbx.NullStringMap{}
+	err := dao.RecordQueryWithDeleted(collection).
+		AndWhere(dbx.In(collection.Name+".id", list.ToInterfaceSlice(ids)...)).
+		All(&rows)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := dao.DecryptRecordRows(collection, rows...); err != nil {
+		return 0, err
+	}
+
+	var total int
+	for _, record := range models.NewRecordsFromNullStringMaps(collection, rows) {
+		if err := dao.DeleteRecord(record); err != nil {
+			return total, err
+		}
+		total++
+	}
+
+	return total, nil
+}
+
+func (dao *Dao) setRecordDeleted(record *models.Record, deleted types.DateTime) error {
+	collection := record.Collection()
+
+	if !collection.SoftDeleteOptions().Enabled {
+		return fmt.Errorf("the %q collection soft delete is not enabled", collection.Name)
+	}
+
+	if !record.HasId() {
+		return errors.New("ID is not set")
+	}
+
+	_, err := dao.NonconcurrentDB().Update(
+		collection.Name,
+		dbx.Params{schema.FieldNameDeleted: deleted.String()},
+		dbx.HashExp{"id": record.Id},
+	).Execute()
+	if err != nil {
+		return err
+	}
+
+	record.Set(schema.FieldNameDeleted, deleted)
+
+	return nil
+}
+
+// syncRecordSoftDeleteColumn adds the hidden soft delete column
+// to the collection records table (if missing).
+//
+// The column is never dropped to prevent accidental data loss.
+func (dao *Dao) syncRecordSoftDeleteColumn(collection *models.Collection) error {
+	if !collection.SoftDeleteOptions().Enabled {
+		return nil
+	}
+
+	columns, err := dao.GetTableColumns(collection.Name)
+	if err != nil {
+		return err
+	}
+
+	if list.ExistInSlice(schema.FieldNameDeleted, columns) {
+		return nil // already exists
+	}
+
+	_, err = dao.DB().AddColumn(collection.Name, schema.FieldNameDeleted, "TEXT DEFAULT '' NOT NULL").Execute()
+	if err != nil {
+		return err
+	}
+
+	_, err = dao.DB().CreateIndex(
+		collection.Name,
+		recordSchemaObjectName(collection, "_"+collection.Id+"_"+schema.FieldNameDeleted+"_idx"),
+		schema.FieldNameDeleted,
+	).Execute()
+
+	return err
+}
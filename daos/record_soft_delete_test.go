@@ -0,0 +1,181 @@
+package daos_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestSoftDeleteColumnSync(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := tests.ToggleCollectionOption(t, app.Dao(), "demo2", "softDelete", true)
+
+	columns, err := app.Dao().GetTableColumns(collection.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !list.ExistInSlice(schema.FieldNameDeleted, columns) {
+		t.Fatalf("Expected the %q column to be added, got %v", schema.FieldNameDeleted, columns)
+	}
+
+	// new collection
+	newCollection := &models.Collection{
+		Name: "new_soft_delete",
+		Schema: schema.NewSchema(
+			&schema.SchemaField{Name: "title", Type: schema.FieldTypeText},
+		),
+	}
+	newCollection.SetOptions(map[string]any{"softDelete": map[string]any{"enabled": true}})
+	if err := app.Dao().SaveCollection(newCollection); err != nil {
+		t.Fatal(err)
+	}
+
+	columns, err = app.Dao().GetTableColumns(newCollection.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !list.ExistInSlice(schema.FieldNameDeleted, columns) {
+		t.Fatalf("Expected the %q column to be created, got %v", schema.FieldNameDeleted, columns)
+	}
+}
+
+func TestSoftDeleteAndRestoreRecord(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dao := app.Dao()
+
+	// soft delete disabled
+	record, err := dao.FindRecordById("demo2", "llvuca81nly1qls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dao.SoftDeleteRecord(record); err == nil {
+		t.Fatal("Expected error for collection with disabled soft delete")
+	}
+
+	collection := tests.ToggleCollectionOption(t, dao, "demo2", "softDelete", true)
+
+	record, err = dao.FindRecordById(collection.Id, "llvuca81nly1qls")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dao.SoftDeleteRecord(record); err != nil {
+		t.Fatal(err)
+	}
+	if !record.IsDeleted() {
+		t.Fatal("Expected the record to be marked as deleted")
+	}
+
+	// excluded from the regular queries
+	if _, err := dao.FindRecordById(collection.Id, record.Id); err == nil {
+		t.Fatal("Expected the soft deleted record to not be found")
+	}
+	records, err := dao.FindRecordsByExpr(collection.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	if total, _ := dao.TotalRecords(collection); total != 2 {
+		t.Fatalf("Expected 2 total records, got %d", total)
+	}
+
+	// the unique checks should still include the soft deleted records
+	if dao.IsRecordValueUnique(collection.Id, "title", "test1") {
+		t.Fatal("Expected the soft deleted record title to not be unique")
+	}
+
+	deleted, err := dao.FindRecordByIdWithDeleted(collection.Id, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !deleted.IsDeleted() {
+		t.Fatal("Expected the loaded record to be marked as deleted")
+	}
+	if _, ok := deleted.PublicExport()[schema.FieldNameDeleted]; !ok {
+		t.Fatalf("Expected the %q field to be exported", schema.FieldNameDeleted)
+	}
+
+	if err := dao.RestoreRecord(deleted); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := dao.FindRecordById(collection.Id, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.IsDeleted() {
+		t.Fatal("Expected the record to be restored")
+	}
+}
+
+func TestPurgeDeletedRecords(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dao := app.Dao()
+
+	disabled, err := dao.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dao.PurgeDeletedRecords(disabled, types.DateTime{}); err == nil {
+		t.Fatal("Expected error for collection with disabled soft delete")
+	}
+
+	collection := tests.ToggleCollectionOption(t, dao, "demo2", "softDelete", true)
+
+	for _, id := range []string{"llvuca81nly1qls", "achvryl401bhse3"} {
+		record, err := dao.FindRecordById(collection.Id, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := dao.SoftDeleteRecord(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// deleted after the before date
+	before, _ := types.ParseDateTime(time.Now().Add(-1 * time.Hour))
+	total, err := dao.PurgeDeletedRecords(collection, before)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 0 {
+		t.Fatalf("Expected 0 purged records, got %d", total)
+	}
+
+	total, err = dao.PurgeDeletedRecords(collection, types.DateTime{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 {
+		t.Fatalf("Expected 2 purged records, got %d", total)
+	}
+
+	var remaining int
+	dao.RecordQueryWithDeleted(collection).Select("count(*)").Row(&remaining)
+	if remaining != 1 {
+		t.Fatalf("Expected 1 remaining record, got %d", remaining)
+	}
+
+	if _, err := dao.FindRecordById(collection.Id, "0yxhwia2amd8gec"); err != nil {
+		t.Fatalf("Expected the not deleted record to be preserved, got %v", err)
+	}
+
+	rows := []dbx.NullStringMap{}
+	dao.RecordQueryWithDeleted(collection).AndWhere(dbx.HashExp{"id": "llvuca81nly1qls"}).All(&rows)
+	if len(rows) != 0 {
+		t.Fatal("Expected the purged record to be permanently deleted")
+	}
+}
@@ -13,7 +13,14 @@ import (
 // and applies the necessary related record table changes.
 //
 // If `oldCollection` is null, then only `newCollection` is used to create the record table.
+//
+// The table of an external collection is never changed.
 func (dao *Dao) SyncRecordTableSchema(newCollection *models.Collection, oldCollection *models.Collection) error {
+	// the external collections table is managed outside of the app
+	if newCollection.IsExternal() {
+		return nil
+	}
+
 	// create
 	if oldCollection == nil {
 		cols := map[string]string{
@@ -43,16 +50,21 @@ func (dao *Dao) SyncRecordTableSchema(newCollection *models.Collection, oldColle
 
 		// add schema field definitions
 		for _, field := range newCollection.Schema.Fields() {
+			if field.Type == schema.FieldTypeComputed {
+				continue // computed fields don't have a db column
+			}
+
 			cols[field.Name] = field.ColDefinition()
 		}
 
-		// create table
-		if _, err := dao.DB().CreateTable(tableName, cols).Execute(); err != nil {
+		// create table (in the collection attached database, if any)
+		if _, err := dao.DB().CreateTable(newCollection.QualifiedTableName(), cols).Execute(); err != nil {
 			return err
 		}
 
 		// add named index on the base `created` column
-		if _, err := dao.DB().CreateIndex(tableName, "_"+newCollection.Id+"_created_idx", "created").Execute(); err != nil {
+		createdIndexName := recordSchemaObjectName(newCollection, "_"+newCollection.Id+"_created_idx")
+		if _, err := dao.DB().CreateIndex(tableName, createdIndexName, "created").Execute(); err != nil {
 			return err
 		}
 
@@ -73,7 +85,31 @@ func (dao *Dao) SyncRecordTableSchema(newCollection *models.Collection, oldColle
 			}
 		}
 
-		return nil
+		if err := dao.syncRecordSoftDeleteColumn(newCollection); err != nil {
+			return err
+		}
+
+		if err := dao.syncRecordVersionColumn(newCollection); err != nil {
+			return err
+		}
+
+		if err := dao.syncRecordExternalIdColumn(newCollection); err != nil {
+			return err
+		}
+
+		if err := dao.syncRecordPhoneColumn(newCollection); err != nil {
+			return err
+		}
+
+		if err := dao.syncRecordTreePathColumn(newCollection, nil); err != nil {
+			return err
+		}
+
+		if err := dao.createRecordUniqueConstraintIndexes(newCollection); err != nil {
+			return err
+		}
+
+		return dao.syncRecordSequenceFields(newCollection, nil)
 	}
 
 	// update
@@ -83,6 +119,16 @@ func (dao *Dao) SyncRecordTableSchema(newCollection *models.Collection, oldColle
 		oldSchema := oldCollection.Schema
 		newSchema := newCollection.Schema
 
+		// the sequence triggers are recreated after the columns sync
+		if err := txDao.dropRecordSequenceTriggers(oldCollection); err != nil {
+			return err
+		}
+
+		// the unique constraint indexes are also recreated after the columns sync
+		if err := txDao.dropRecordUniqueConstraintIndexes(oldCollection); err != nil {
+			return err
+		}
+
 		// check for renamed table
 		if !strings.EqualFold(oldTableName, newTableName) {
 			_, err := txDao.DB().RenameTable(oldTableName, newTableName).Execute()
@@ -97,6 +143,16 @@ func (dao *Dao) SyncRecordTableSchema(newCollection *models.Collection, oldColle
 				continue // exist
 			}
 
+			if oldField.Type == schema.FieldTypeComputed {
+				continue // no db column
+			}
+
+			if oldField.Type == schema.FieldTypeSequence {
+				if err := txDao.dropRecordSequenceField(oldCollection, oldField); err != nil {
+					return err
+				}
+			}
+
 			_, err := txDao.DB().DropColumn(newTableName, oldField.Name).Execute()
 			if err != nil {
 				return err
@@ -106,6 +162,10 @@ func (dao *Dao) SyncRecordTableSchema(newCollection *models.Collection, oldColle
 		// check for new or renamed columns
 		toRename := map[string]string{}
 		for _, field := range newSchema.Fields() {
+			if field.Type == schema.FieldTypeComputed {
+				continue // no db column
+			}
+
 			oldField := oldSchema.GetFieldById(field.Id)
 			// Note:
 			// We are using a temporary column name when adding or renaming columns
@@ -142,6 +202,30 @@ func (dao *Dao) SyncRecordTableSchema(newCollection *models.Collection, oldColle
 			}
 		}
 
-		return nil
+		if err := txDao.syncRecordSoftDeleteColumn(newCollection); err != nil {
+			return err
+		}
+
+		if err := txDao.syncRecordVersionColumn(newCollection); err != nil {
+			return err
+		}
+
+		if err := txDao.syncRecordExternalIdColumn(newCollection); err != nil {
+			return err
+		}
+
+		if err := txDao.syncRecordPhoneColumn(newCollection); err != nil {
+			return err
+		}
+
+		if err := txDao.syncRecordTreePathColumn(newCollection, oldCollection); err != nil {
+			return err
+		}
+
+		if err := txDao.createRecordUniqueConstraintIndexes(newCollection); err != nil {
+			return err
+		}
+
+		return txDao.syncRecordSequenceFields(newCollection, oldCollection)
 	})
 }
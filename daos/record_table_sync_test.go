@@ -29,6 +29,13 @@ func TestSyncRecordTableSchema(t *testing.T) {
 			Type: schema.FieldTypeEmail,
 		},
 	)
+	updatedCollection.Schema.AddField(
+		&schema.SchemaField{
+			Name:    "new_computed",
+			Type:    schema.FieldTypeComputed,
+			Options: &schema.ComputedOptions{Expression: "created"},
+		},
+	)
 	updatedCollection.Schema.AddField(
 		&schema.SchemaField{
 			Id:   updatedCollection.Schema.GetFieldByName("title").Id,
@@ -52,6 +59,11 @@ func TestSyncRecordTableSchema(t *testing.T) {
 						Name: "test",
 						Type: schema.FieldTypeText,
 					},
+					&schema.SchemaField{
+						Name:    "test_computed",
+						Type:    schema.FieldTypeComputed,
+						Options: &schema.ComputedOptions{Expression: "test * 2"},
+					},
 				),
 			},
 			nil,
@@ -3,6 +3,7 @@ package daos_test
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
@@ -36,6 +37,90 @@ func TestRecordQuery(t *testing.T) {
 	}
 }
 
+func TestRecordQueryComputedFields(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection.Schema.AddField(&schema.SchemaField{
+		Name:    "computed1",
+		Type:    schema.FieldTypeComputed,
+		Options: &schema.ComputedOptions{Expression: "number / 2 + 1"},
+	})
+	collection.Schema.AddField(&schema.SchemaField{
+		Name:    "computed2",
+		Type:    schema.FieldTypeComputed,
+		Options: &schema.ComputedOptions{Expression: "missing * 2"},
+	})
+
+	expectedSql := fmt.Sprintf(
+		"SELECT `%s`.*, ((([[demo1.number]] * 1.0 / 2) + 1)) AS [[computed1]], (NULL) AS [[computed2]] FROM `%s`",
+		collection.Name,
+		collection.Name,
+	)
+
+	query := app.Dao().RecordQuery(collection)
+
+	if sql := query.Build().SQL(); sql != expectedSql {
+		t.Fatalf("Expected sql \n%s, \ngot \n%s", expectedSql, sql)
+	}
+
+	rows := []dbx.NullStringMap{}
+	if err := query.OrderBy("id ASC").All(&rows); err != nil {
+		t.Fatal(err)
+	}
+
+	records := models.NewRecordsFromNullStringMaps(collection, rows)
+
+	expected := []float64{61729, 229, 1}
+	if len(records) != len(expected) {
+		t.Fatalf("Expected %d records, got %d", len(expected), len(records))
+	}
+
+	for i, record := range records {
+		if v := record.Get("computed1"); v != expected[i] {
+			t.Errorf("(%d) Expected computed1 %v, got %v (%T)", i, expected[i], v, v)
+		}
+
+		if v := record.Get("computed2"); v != nil {
+			t.Errorf("(%d) Expected nil computed2, got %v", i, v)
+		}
+	}
+}
+
+func TestTotalRecords(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result1, err := app.Dao().TotalRecords(collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result1 != 3 {
+		t.Fatalf("Expected 3 records, got %d", result1)
+	}
+
+	// delete all
+	app.Dao().DB().NewQuery("delete from {{demo2}}").Execute()
+
+	result2, err := app.Dao().TotalRecords(collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result2 != 0 {
+		t.Fatalf("Expected 0 records, got %d", result2)
+	}
+}
+
 func TestFindRecordById(t *testing.T) {
 	app, _ := tests.NewTestApp()
 	defer app.Cleanup()
@@ -257,6 +342,158 @@ func TestFindRecordsByExpr(t *testing.T) {
 	}
 }
 
+func TestFindDistinctRecordValues(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	demo1, err := app.Dao().FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demo1.Schema.AddField(&schema.SchemaField{
+		Name:    "computed",
+		Type:    schema.FieldTypeComputed,
+		Options: &schema.ComputedOptions{Expression: "number % 2"},
+	})
+
+	users, err := app.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		collection   *models.Collection
+		field        string
+		filter       func(q *dbx.SelectQuery) error
+		expectResult string
+		expectError  bool
+	}{
+		{demo1, "missing", nil, `null`, true},
+		{demo1, "tokenKey", nil, `null`, true},
+		{demo1, "text", nil, `["lorem ipsum","test","test2"]`, false},
+		{demo1, "bool", nil, `[false,true]`, false},
+		{demo1, "number", nil, `[0,456,123456]`, false},
+		{demo1, "computed", nil, `[0]`, false},
+		{demo1, "select_one", nil, `["","optionB"]`, false},
+		{demo1, "select_many", nil, `["optionB","optionC"]`, false},
+		{demo1, "rel_many", nil, `["4q1xlclmfloku33","bgs820n361vj1qd","oap640cot4yru2s"]`, false},
+		{
+			demo1,
+			"rel_many",
+			func(q *dbx.SelectQuery) error {
+				q.AndWhere(dbx.HashExp{"demo1.id": "84nmscqy84lsi1t"})
+				return nil
+			},
+			`["oap640cot4yru2s"]`,
+			false,
+		},
+		{
+			demo1,
+			"text",
+			func(q *dbx.SelectQuery) error {
+				q.Limit(1)
+				return nil
+			},
+			`["lorem ipsum"]`,
+			false,
+		},
+		{
+			demo1,
+			"text",
+			func(q *dbx.SelectQuery) error {
+				return errors.New("test error")
+			},
+			`null`,
+			true,
+		},
+		{users, "verified", nil, `[false,true]`, false},
+	}
+
+	for i, s := range scenarios {
+		values, err := app.Dao().FindDistinctRecordValues(s.collection, s.field, s.filter)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr to be %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		encoded, _ := json.Marshal(values)
+		if string(encoded) != s.expectResult {
+			t.Errorf("(%d) Expected %s, got %s", i, s.expectResult, encoded)
+		}
+	}
+}
+
+func TestFindRecordFacetCounts(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	demo1, err := app.Dao().FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		field        string
+		filter       func(q *dbx.SelectQuery) error
+		expectResult string
+		expectError  bool
+	}{
+		{"missing", nil, `null`, true},
+		{"bool", nil, `[{"value":false,"count":2},{"value":true,"count":1}]`, false},
+		{"select_many", nil, `[{"value":"optionB","count":2},{"value":"optionC","count":1}]`, false},
+		{
+			"rel_many",
+			nil,
+			`[{"value":"oap640cot4yru2s","count":2},{"value":"4q1xlclmfloku33","count":1},{"value":"bgs820n361vj1qd","count":1}]`,
+			false,
+		},
+		{
+			"rel_many",
+			func(q *dbx.SelectQuery) error {
+				q.AndWhere(dbx.HashExp{"demo1.id": "84nmscqy84lsi1t"})
+				return nil
+			},
+			`[{"value":"oap640cot4yru2s","count":1}]`,
+			false,
+		},
+		{
+			"text",
+			func(q *dbx.SelectQuery) error {
+				q.Limit(1)
+				return nil
+			},
+			`[{"value":"lorem ipsum","count":1}]`,
+			false,
+		},
+		{
+			"text",
+			func(q *dbx.SelectQuery) error {
+				return errors.New("test error")
+			},
+			`null`,
+			true,
+		},
+	}
+
+	for i, s := range scenarios {
+		items, err := app.Dao().FindRecordFacetCounts(demo1, s.field, s.filter)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr to be %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		encoded, _ := json.Marshal(items)
+		if string(encoded) != s.expectResult {
+			t.Errorf("(%d) Expected %s, got %s", i, s.expectResult, encoded)
+		}
+	}
+}
+
 func TestFindFirstRecordByData(t *testing.T) {
 	app, _ := tests.NewTestApp()
 	defer app.Cleanup()
@@ -564,6 +801,50 @@ func TestSaveRecord(t *testing.T) {
 	}
 }
 
+func TestSaveRecordComputedFields(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, _ := app.Dao().FindCollectionByNameOrId("demo1")
+	collection.Schema.AddField(&schema.SchemaField{
+		Name:    "computed",
+		Type:    schema.FieldTypeComputed,
+		Options: &schema.ComputedOptions{Expression: "number * 2"},
+	})
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	record := models.NewRecord(collection)
+	record.Set("number", 10)
+	record.Set("computed", 1) // should be ignored
+	if err := app.Dao().SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := record.Get("computed"); v != 20.0 {
+		t.Fatalf("Expected the computed value to be refreshed on create, got %v", v)
+	}
+
+	record.Set("number", 5)
+	if err := app.Dao().SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := record.Get("computed"); v != 10.0 {
+		t.Fatalf("Expected the computed value to be refreshed on update, got %v", v)
+	}
+
+	found, err := app.Dao().FindRecordById(collection.Id, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := found.Get("computed"); v != 10.0 {
+		t.Fatalf("Expected the found record computed value to be 10, got %v", v)
+	}
+}
+
 func TestSaveRecordWithIdFromOtherCollection(t *testing.T) {
 	app, _ := tests.NewTestApp()
 	defer app.Cleanup()
@@ -673,6 +954,88 @@ func TestDeleteRecord(t *testing.T) {
 	}
 }
 
+func TestDeleteRecordOnDeletePolicies(t *testing.T) {
+	scenarios := []struct {
+		onDelete      string
+		cascadeDelete bool
+		expectError   bool
+		expectDeleted bool
+	}{
+		{"", false, false, false},
+		{"", true, false, true},
+		{schema.RelationOnDeleteSetNull, true, false, false},
+		{schema.RelationOnDeleteCascade, false, false, true},
+		{schema.RelationOnDeleteRestrict, true, true, false},
+	}
+
+	for i, s := range scenarios {
+		func() {
+			app, _ := tests.NewTestApp()
+			defer app.Cleanup()
+
+			parents, err := app.Dao().FindCollectionByNameOrId("demo2")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			children := &models.Collection{Name: "children", Type: models.CollectionTypeBase}
+			children.Schema.AddField(&schema.SchemaField{
+				Name: "parent",
+				Type: schema.FieldTypeRelation,
+				Options: &schema.RelationOptions{
+					CollectionId:  parents.Id,
+					MaxSelect:     types.Pointer(1),
+					OnDelete:      s.onDelete,
+					CascadeDelete: s.cascadeDelete,
+				},
+			})
+			if err := app.Dao().SaveCollection(children); err != nil {
+				t.Fatal(err)
+			}
+
+			child := models.NewRecord(children)
+			child.Set("parent", "llvuca81nly1qls")
+			if err := app.Dao().SaveRecord(child); err != nil {
+				t.Fatal(err)
+			}
+
+			parent, err := app.Dao().FindRecordById(parents.Id, "llvuca81nly1qls")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			deleteErr := app.Dao().DeleteRecord(parent)
+			if hasErr := deleteErr != nil; hasErr != s.expectError {
+				t.Fatalf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, deleteErr)
+			}
+
+			// the parent delete should be rolled back on error
+			if _, err := app.Dao().FindRecordById(parents.Id, parent.Id); (err == nil) != s.expectError {
+				t.Fatalf("(%d) Expected the parent to be deleted only on success, got %v", i, err)
+			}
+
+			refreshed, err := app.Dao().FindRecordById(children.Id, child.Id)
+			if s.expectDeleted {
+				if err == nil {
+					t.Fatalf("(%d) Expected the child record to be deleted", i)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("(%d) Expected the child record to be preserved, got %v", i, err)
+			}
+
+			expectedParent := ""
+			if s.expectError {
+				expectedParent = parent.Id
+			}
+			if v := refreshed.GetString("parent"); v != expectedParent {
+				t.Fatalf("(%d) Expected parent %q, got %q", i, expectedParent, v)
+			}
+		}()
+	}
+}
+
 func TestDeleteRecordBatchProcessing(t *testing.T) {
 	app, _ := tests.NewTestApp()
 	defer app.Cleanup()
@@ -0,0 +1,287 @@
+package daos
+
+import (
+	"database/sql"
+	"errors"
+	"unicode/utf8"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+// treePathSeparator is the separator of the materialized path ids.
+const treePathSeparator = "/"
+
+// FindDescendants returns all descendant records of the provided
+// tree record (children, grandchildren, etc.) ordered by their path.
+//
+// Returns an error if the record collection doesn't have a tree relation field.
+func (dao *Dao) FindDescendants(record *models.Record) ([]*models.Record, error) {
+	collection := record.Collection()
+
+	if collection.TreeField() == nil {
+		return nil, errors.New("The collection doesn't have a tree relation field.")
+	}
+
+	if record.Path() == "" {
+		return []*models.Record{}, nil
+	}
+
+	rows := []dbx.NullStringMap{}
+
+	err := dao.RecordQuery(collection).
+		AndWhere(treePathPrefixExpr(collection, record.Path())).
+		OrderBy(collection.Name + "." + schema.FieldNamePath + " ASC").
+		All(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dao.DecryptRecordRows(collection, rows...); err != nil {
+		return nil, err
+	}
+
+	return models.NewRecordsFromNullStringMaps(collection, rows), nil
+}
+
+// FindAncestors returns all ancestor records of the provided
+// tree record ordered from the root to the direct parent.
+//
+// Returns an error if the record collection doesn't have a tree relation field.
+func (dao *Dao) FindAncestors(record *models.Record) ([]*models.Record, error) {
+	collection := record.Collection()
+
+	if collection.TreeField() == nil {
+		return nil, errors.New("The collection doesn't have a tree relation field.")
+	}
+
+	ids := record.PathIds()
+	if len(ids) <= 1 {
+		return []*models.Record{}, nil // root or not persisted record
+	}
+	ids = ids[:len(ids)-1]
+
+	records, err := dao.FindRecordsByIds(collection.Id, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// restore the path order
+	result := make([]*models.Record, 0, len(records))
+	for _, id := range ids {
+		for _, r := range records {
+			if r.Id == id {
+				result = append(result, r)
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// prepareRecordTreePath resolves and sets the materialized path of the
+// provided record (if its collection has a tree field) based on its parent.
+//
+// Returns the old (previously persisted) record path.
+func (dao *Dao) prepareRecordTreePath(record *models.Record) (string, error) {
+	collection := record.Collection()
+
+	treeField := collection.TreeField()
+	if treeField == nil {
+		return "", nil
+	}
+
+	// ensure that the record has an id so that it could be part of its path
+	if !record.HasId() {
+		dao.refreshModelId(record)
+	}
+
+	// load the persisted path because the record could be saved multiple times
+	var oldPath string
+	if !record.IsNew() {
+		err := dao.DB().Select(schema.FieldNamePath).
+			From(collection.Name).
+			AndWhere(dbx.HashExp{schema.FieldNameId: record.Id}).
+			Limit(1).
+			Row(&oldPath)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return "", err
+		}
+	}
+
+	path := record.Id
+
+	if parentId := record.GetString(treeField.Name); parentId != "" {
+		if parentId == record.Id {
+			return "", errors.New("The tree record cannot be its own parent.")
+		}
+
+		parent, err := dao.FindRecordById(collection.Id, parentId)
+		if err != nil {
+			return "", err
+		}
+
+		parentPath := parent.Path()
+		if parentPath == "" {
+			parentPath = parent.Id
+		}
+
+		if list.ExistInSlice(record.Id, parent.PathIds()) {
+			return "", errors.New("The tree record cannot be a descendant of itself.")
+		}
+
+		path = parentPath + treePathSeparator + record.Id
+	}
+
+	record.Set(schema.FieldNamePath, path)
+
+	return oldPath, nil
+}
+
+// syncRecordTreeDescendantPaths replaces the oldPath prefix of the
+// record descendants paths with the current record path.
+//
+// Note that the descendants are updated with a single db statement
+// and no model hooks are triggered for them.
+func (dao *Dao) syncRecordTreeDescendantPaths(record *models.Record, oldPath string) error {
+	if oldPath == "" || oldPath == record.Path() || record.Collection().TreeField() == nil {
+		return nil
+	}
+
+	collection := record.Collection()
+
+	_, err := dao.DB().NewQuery(
+		"UPDATE {{" + collection.Name + "}} " +
+			"SET [[" + schema.FieldNamePath + "]] = {:newPath} || substr([[" + schema.FieldNamePath + "]], {:offset}) " +
+			"WHERE substr([[" + schema.FieldNamePath + "]], 1, {:prefixLength}) = {:prefix}",
+	).Bind(dbx.Params{
+		"newPath":      record.Path(),
+		"offset":       utf8.RuneCountInString(oldPath) + 1,
+		"prefix":       oldPath + treePathSeparator,
+		"prefixLength": utf8.RuneCountInString(oldPath + treePathSeparator),
+	}).Execute()
+
+	return err
+}
+
+// syncRecordTreePathColumn adds the tree path column and its index to
+// the collection records table (if missing) and rebuilds the paths of
+// all existing records when the tree field was just enabled or changed.
+//
+// Similar to the external id column, the path column is never dropped.
+func (dao *Dao) syncRecordTreePathColumn(newCollection *models.Collection, oldCollection *models.Collection) error {
+	treeField := newCollection.TreeField()
+	if treeField == nil {
+		return nil
+	}
+
+	columns, err := dao.GetTableColumns(newCollection.Name)
+	if err != nil {
+		return err
+	}
+
+	if !list.ExistInSlice(schema.FieldNamePath, columns) {
+		_, err = dao.DB().AddColumn(newCollection.Name, schema.FieldNamePath, "TEXT DEFAULT '' NOT NULL").Execute()
+		if err != nil {
+			return err
+		}
+
+		_, err = dao.DB().CreateIndex(
+			newCollection.Name,
+			recordSchemaObjectName(newCollection, "_"+newCollection.Id+"_"+schema.FieldNamePath+"_idx"),
+			schema.FieldNamePath,
+		).Execute()
+		if err != nil {
+			return err
+		}
+	}
+
+	if oldCollection != nil {
+		if oldTreeField := oldCollection.TreeField(); oldTreeField != nil && oldTreeField.Name == treeField.Name {
+			return nil // nothing has changed
+		}
+	}
+
+	return dao.rebuildRecordTreePaths(newCollection)
+}
+
+// rebuildRecordTreePaths recalculates the materialized paths of all
+// collection records based on their current tree field parent.
+//
+// Records with missing parent are treated as roots and the
+// cycles (if any) are broken at the first revisited record.
+func (dao *Dao) rebuildRecordTreePaths(collection *models.Collection) error {
+	treeField := collection.TreeField()
+	if treeField == nil {
+		return nil
+	}
+
+	rows := []dbx.NullStringMap{}
+
+	err := dao.DB().Select(schema.FieldNameId, treeField.Name).
+		From(collection.Name).
+		All(&rows)
+	if err != nil {
+		return err
+	}
+
+	parents := make(map[string]string, len(rows))
+	for _, row := range rows {
+		parents[row[schema.FieldNameId].String] = row[treeField.Name].String
+	}
+
+	paths := make(map[string]string, len(parents))
+
+	var resolvePath func(id string, visited map[string]struct{}) string
+	resolvePath = func(id string, visited map[string]struct{}) string {
+		if path, ok := paths[id]; ok {
+			return path
+		}
+
+		path := id
+
+		parentId := parents[id]
+		if _, exists := parents[parentId]; exists && parentId != "" {
+			if _, isCycle := visited[parentId]; !isCycle {
+				visited[id] = struct{}{}
+				path = resolvePath(parentId, visited) + treePathSeparator + id
+			}
+		}
+
+		paths[id] = path
+
+		return path
+	}
+
+	for id := range parents {
+		path := resolvePath(id, map[string]struct{}{})
+
+		_, err := dao.DB().Update(
+			collection.Name,
+			dbx.Params{schema.FieldNamePath: path},
+			dbx.HashExp{schema.FieldNameId: id},
+		).Execute()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// treePathPrefixExpr returns a db expression that matches
+// the records with paths starting with "path/".
+func treePathPrefixExpr(collection *models.Collection, path string) dbx.Expression {
+	prefix := path + treePathSeparator
+
+	return dbx.NewExp(
+		"substr([["+collection.Name+"."+schema.FieldNamePath+"]], 1, {:prefixLength}) = {:prefix}",
+		dbx.Params{
+			"prefix":       prefix,
+			"prefixLength": utf8.RuneCountInString(prefix),
+		},
+	)
+}
@@ -0,0 +1,272 @@
+package daos_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/resolvers"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// createTestTreeCollection creates a new "categories" collection with
+// the provided records (id => parent id) and enables its tree field.
+func createTestTreeCollection(t *testing.T, dao *daos.Dao, parents [][2]string) *models.Collection {
+	collection := &models.Collection{
+		Name: "categories",
+		Type: models.CollectionTypeBase,
+		Schema: schema.NewSchema(
+			&schema.SchemaField{
+				Name: "title",
+				Type: schema.FieldTypeText,
+			},
+		),
+	}
+	if err := dao.SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	collection.Schema.AddField(&schema.SchemaField{
+		Name: "parent",
+		Type: schema.FieldTypeRelation,
+		Options: &schema.RelationOptions{
+			CollectionId: collection.Id,
+			MaxSelect:    types.Pointer(1),
+		},
+	})
+	if err := dao.SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, item := range parents {
+		record := models.NewRecord(collection)
+		record.SetId(item[0])
+		record.Set("title", item[0])
+		record.Set("parent", item[1])
+		if err := dao.SaveRecord(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// enable the tree option
+	collection.Schema.GetFieldByName("parent").Options.(*schema.RelationOptions).Tree = true
+	if err := dao.SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	return collection
+}
+
+func assertRecordPaths(t *testing.T, dao *daos.Dao, collection *models.Collection, expected map[string]string) {
+	for id, path := range expected {
+		record, err := dao.FindRecordById(collection.Id, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if record.Path() != path {
+			t.Fatalf("Expected record %q to have path %q, got %q", id, path, record.Path())
+		}
+	}
+}
+
+func recordIds(records []*models.Record) string {
+	ids := make([]string, len(records))
+	for i, r := range records {
+		ids[i] = r.Id
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestRecordTreePathsRebuild(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := createTestTreeCollection(t, app.Dao(), [][2]string{
+		{"root", ""},
+		{"child", "root"},
+		{"grandchild", "child"},
+		{"other", ""},
+	})
+
+	if collection.TreeField() == nil {
+		t.Fatal("Expected the collection to have a tree field")
+	}
+
+	assertRecordPaths(t, app.Dao(), collection, map[string]string{
+		"root":       "root",
+		"child":      "root/child",
+		"grandchild": "root/child/grandchild",
+		"other":      "other",
+	})
+}
+
+func TestRecordTreePathsSave(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dao := app.Dao()
+
+	collection := createTestTreeCollection(t, dao, nil)
+
+	for _, item := range [][2]string{
+		{"root", ""},
+		{"child", "root"},
+		{"grandchild", "child"},
+		{"other", ""},
+	} {
+		record := models.NewRecord(collection)
+		record.SetId(item[0])
+		record.Set("parent", item[1])
+		if err := dao.SaveRecord(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	assertRecordPaths(t, dao, collection, map[string]string{
+		"root":       "root",
+		"child":      "root/child",
+		"grandchild": "root/child/grandchild",
+		"other":      "other",
+	})
+
+	// move the child subtree
+	child, err := dao.FindRecordById(collection.Id, "child")
+	if err != nil {
+		t.Fatal(err)
+	}
+	child.Set("parent", "other")
+	if err := dao.SaveRecord(child); err != nil {
+		t.Fatal(err)
+	}
+
+	assertRecordPaths(t, dao, collection, map[string]string{
+		"root":       "root",
+		"child":      "other/child",
+		"grandchild": "other/child/grandchild",
+		"other":      "other",
+	})
+
+	// save the same record model again
+	child.Set("parent", "")
+	if err := dao.SaveRecord(child); err != nil {
+		t.Fatal(err)
+	}
+
+	assertRecordPaths(t, dao, collection, map[string]string{
+		"child":      "child",
+		"grandchild": "child/grandchild",
+	})
+
+	// cycles
+	child.Set("parent", "child")
+	if err := dao.SaveRecord(child); err == nil {
+		t.Fatal("Expected the record to not be its own parent")
+	}
+
+	child.Set("parent", "grandchild")
+	if err := dao.SaveRecord(child); err == nil {
+		t.Fatal("Expected the record to not be a descendant of itself")
+	}
+}
+
+func TestFindDescendantsAndAncestors(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dao := app.Dao()
+
+	collection := createTestTreeCollection(t, dao, [][2]string{
+		{"a", ""},
+		{"b", "a"},
+		{"c", "b"},
+		{"d", "a"},
+		{"e", ""},
+	})
+
+	scenarios := []struct {
+		id                  string
+		expectedDescendants string
+		expectedAncestors   string
+	}{
+		{"a", "b,c,d", ""},
+		{"b", "c", "a"},
+		{"c", "", "a,b"},
+		{"d", "", "a"},
+		{"e", "", ""},
+	}
+
+	for _, s := range scenarios {
+		record, err := dao.FindRecordById(collection.Id, s.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		descendants, err := dao.FindDescendants(record)
+		if err != nil {
+			t.Fatalf("[%s] Failed to find descendants: %v", s.id, err)
+		}
+		if ids := recordIds(descendants); ids != s.expectedDescendants {
+			t.Errorf("[%s] Expected descendants %q, got %q", s.id, s.expectedDescendants, ids)
+		}
+
+		ancestors, err := dao.FindAncestors(record)
+		if err != nil {
+			t.Fatalf("[%s] Failed to find ancestors: %v", s.id, err)
+		}
+		if ids := recordIds(ancestors); ids != s.expectedAncestors {
+			t.Errorf("[%s] Expected ancestors %q, got %q", s.id, s.expectedAncestors, ids)
+		}
+	}
+
+	// non-tree collection
+	record, err := dao.FindRecordById("demo2", "llvuca81nly1qls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dao.FindDescendants(record); err == nil {
+		t.Fatal("Expected FindDescendants error for non-tree collection")
+	}
+	if _, err := dao.FindAncestors(record); err == nil {
+		t.Fatal("Expected FindAncestors error for non-tree collection")
+	}
+}
+
+func TestRecordTreePathFilter(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dao := app.Dao()
+
+	collection := createTestTreeCollection(t, dao, [][2]string{
+		{"a", ""},
+		{"b", "a"},
+		{"c", "b"},
+		{"d", ""},
+	})
+
+	resolver := resolvers.NewRecordFieldResolver(dao, collection, nil, false)
+
+	expr, err := search.FilterData(`path ~ "a/%"`).BuildExpr(resolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := dao.RecordQuery(collection).AndWhere(expr).OrderBy("path ASC")
+	if err := resolver.UpdateQuery(query); err != nil {
+		t.Fatal(err)
+	}
+
+	records := []*models.Record{}
+	if err := query.All(&records); err != nil {
+		t.Fatal(err)
+	}
+
+	if ids := recordIds(records); ids != "b,c" {
+		t.Fatalf("Expected records %q, got %q", "b,c", ids)
+	}
+}
@@ -0,0 +1,128 @@
+package daos
+
+import (
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// IsRecordValuesUnique checks if the provided combination of field
+// values is unique for the collection records (aka. there is no other
+// record with the same values for all of the specified fields).
+//
+// Similar to [Dao.IsRecordValueUnique], the array values are matched
+// as serialized json strings.
+func (dao *Dao) IsRecordValuesUnique(
+	collection *models.Collection,
+	values map[string]any,
+	excludeIds ...string,
+) bool {
+	if len(values) == 0 {
+		return true
+	}
+
+	expr := dbx.HashExp{}
+	for key, value := range values {
+		var normalizedVal any
+		switch val := value.(type) {
+		case []string:
+			normalizedVal = append(types.JsonArray{}, list.ToInterfaceSlice(val)...)
+		case []any:
+			normalizedVal = append(types.JsonArray{}, val...)
+		default:
+			normalizedVal = val
+		}
+
+		expr[collection.Name+"."+dao.Columnify(key)] = normalizedVal
+	}
+
+	// note: the soft deleted records are also checked since
+	// they are still part of the table unique indexes
+	query := dao.RecordQueryWithDeleted(collection).
+		Select("count(*)").
+		AndWhere(expr).
+		Limit(1)
+
+	if len(excludeIds) > 0 {
+		uniqueExcludeIds := list.NonzeroUniques(excludeIds)
+		query.AndWhere(dbx.NotIn(collection.Name+".id", list.ToInterfaceSlice(uniqueExcludeIds)...))
+	}
+
+	var exists bool
+
+	return query.Row(&exists) == nil && !exists
+}
+
+// HasRecordUniqueConstraintDuplicates checks whether the existing
+// collection records violate the provided unique constraint
+// (aka. whether its unique index could be created).
+func (dao *Dao) HasRecordUniqueConstraintDuplicates(
+	collection *models.Collection,
+	constraint models.CollectionUniqueConstraint,
+) (bool, error) {
+	if len(constraint.Fields) == 0 {
+		return false, nil
+	}
+
+	columns := make([]string, len(constraint.Fields))
+	for i, name := range constraint.Fields {
+		columns[i] = "[[" + dao.Columnify(name) + "]]"
+	}
+
+	var exists bool
+
+	err := dao.DB().NewQuery(
+		"SELECT EXISTS (SELECT 1 FROM {{" + collection.Name + "}} " +
+			"GROUP BY " + strings.Join(columns, ", ") + " HAVING COUNT(*) > 1)",
+	).Row(&exists)
+
+	return exists, err
+}
+
+// recordUniqueConstraintIndexName returns the unique index name
+// of the provided collection constraint.
+func recordUniqueConstraintIndexName(collection *models.Collection, constraint models.CollectionUniqueConstraint) string {
+	return "_" + collection.Id + "_" + constraint.Name + "_unique_idx"
+}
+
+// dropRecordUniqueConstraintIndexes drops the unique indexes
+// of all collection multi-column unique constraints.
+//
+// The indexes are dropped before the records table columns sync since
+// SQLite doesn't allow dropping indexed columns.
+func (dao *Dao) dropRecordUniqueConstraintIndexes(collection *models.Collection) error {
+	for _, constraint := range collection.UniqueConstraints() {
+		_, err := dao.DB().NewQuery(
+			"DROP INDEX IF EXISTS {{" + recordUniqueConstraintIndexName(collection, constraint) + "}}",
+		).Execute()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createRecordUniqueConstraintIndexes creates the unique indexes
+// of all collection multi-column unique constraints.
+func (dao *Dao) createRecordUniqueConstraintIndexes(collection *models.Collection) error {
+	for _, constraint := range collection.UniqueConstraints() {
+		columns := make([]string, len(constraint.Fields))
+		for i, name := range constraint.Fields {
+			columns[i] = "[[" + dao.Columnify(name) + "]]"
+		}
+
+		_, err := dao.DB().NewQuery(
+			"CREATE UNIQUE INDEX {{" + recordSchemaObjectName(collection, recordUniqueConstraintIndexName(collection, constraint)) + "}} " +
+				"ON {{" + collection.Name + "}} (" + strings.Join(columns, ", ") + ")",
+		).Execute()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
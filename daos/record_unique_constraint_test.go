@@ -0,0 +1,149 @@
+package daos_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestRecordUniqueConstraints(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dao := app.Dao()
+
+	collection, err := dao.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	indexName := "_" + collection.Id + "_title_active_unique_idx"
+
+	collection.Options["uniqueConstraints"] = []map[string]any{
+		{"name": "title_active", "fields": []string{"title", "active"}},
+	}
+	if err := dao.SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	if !hasIndex(dao, indexName) {
+		t.Fatalf("Expected index %q to be created", indexName)
+	}
+
+	// db level constraint
+	duplicate := models.NewRecord(collection)
+	duplicate.Set("title", "test1")
+	duplicate.Set("active", false)
+	if err := dao.SaveRecord(duplicate); err == nil {
+		t.Fatal("Expected the duplicated values combination to fail")
+	}
+
+	nonDuplicate := models.NewRecord(collection)
+	nonDuplicate.Set("title", "test1")
+	nonDuplicate.Set("active", true)
+	if err := dao.SaveRecord(nonDuplicate); err != nil {
+		t.Fatalf("Expected the record to be saved, got %v", err)
+	}
+
+	// rename a constrained field
+	collection.Schema.GetFieldByName("active").Name = "enabled"
+	collection.Options["uniqueConstraints"] = []map[string]any{
+		{"name": "title_active", "fields": []string{"title", "enabled"}},
+	}
+	if err := dao.SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	if !hasIndex(dao, indexName) {
+		t.Fatalf("Expected index %q to be recreated", indexName)
+	}
+
+	// remove the constraint
+	delete(collection.Options, "uniqueConstraints")
+	if err := dao.SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	if hasIndex(dao, indexName) {
+		t.Fatalf("Expected index %q to be dropped", indexName)
+	}
+}
+
+func TestIsRecordValuesUnique(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		values     map[string]any
+		excludeIds []string
+		expected   bool
+	}{
+		{nil, nil, true},
+		{map[string]any{"title": "test1", "active": false}, nil, false},
+		{map[string]any{"title": "test1", "active": true}, nil, true},
+		{map[string]any{"title": "test1", "active": false}, []string{"llvuca81nly1qls"}, true},
+		{map[string]any{"active": true}, []string{"achvryl401bhse3"}, false},
+		{map[string]any{"active": true}, []string{"achvryl401bhse3", "0yxhwia2amd8gec"}, true},
+	}
+
+	for i, s := range scenarios {
+		result := app.Dao().IsRecordValuesUnique(collection, s.values, s.excludeIds...)
+		if result != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+}
+
+func TestHasRecordUniqueConstraintDuplicates(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		fields   []string
+		expected bool
+	}{
+		{nil, false},
+		{[]string{"title"}, false},
+		{[]string{"title", "active"}, false},
+		{[]string{"active"}, true},
+	}
+
+	for i, s := range scenarios {
+		result, err := app.Dao().HasRecordUniqueConstraintDuplicates(collection, models.CollectionUniqueConstraint{
+			Name:   "test",
+			Fields: s.fields,
+		})
+		if err != nil {
+			t.Errorf("(%d) Unexpected error %v", i, err)
+			continue
+		}
+		if result != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+}
+
+func hasIndex(dao *daos.Dao, name string) bool {
+	var exists bool
+
+	err := dao.DB().Select("count(*)").
+		From("sqlite_master").
+		AndWhere(dbx.HashExp{"type": "index", "name": name}).
+		Limit(1).
+		Row(&exists)
+
+	return err == nil && exists
+}
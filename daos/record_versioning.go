@@ -0,0 +1,48 @@
+package daos
+
+import (
+	"errors"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+// ErrRecordVersionConflict is returned when trying to update a record
+// of a collection with enabled versioning and the record version
+// doesn't match with the stored one (aka. it was modified in the meantime).
+var ErrRecordVersionConflict = errors.New("the record was modified in the meantime (version conflict)")
+
+// syncRecordVersionColumn adds the hidden version column
+// to the collection records table (if missing).
+//
+// Similar to the soft delete column, the version column is never dropped.
+func (dao *Dao) syncRecordVersionColumn(collection *models.Collection) error {
+	if !collection.VersioningOptions().Enabled {
+		return nil
+	}
+
+	columns, err := dao.GetTableColumns(collection.Name)
+	if err != nil {
+		return err
+	}
+
+	if list.ExistInSlice(schema.FieldNameVersion, columns) {
+		return nil // already exists
+	}
+
+	_, err = dao.DB().AddColumn(collection.Name, schema.FieldNameVersion, "INTEGER DEFAULT 0 NOT NULL").Execute()
+
+	return err
+}
+
+// isVersionedRecord checks whether the provided model is a record
+// from a collection with enabled versioning.
+func isVersionedRecord(m models.Model) (*models.Record, bool) {
+	record, ok := m.(*models.Record)
+	if !ok || !record.Collection().VersioningOptions().Enabled {
+		return nil, false
+	}
+
+	return record, true
+}
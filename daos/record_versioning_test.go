@@ -0,0 +1,81 @@
+package daos_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+func TestRecordVersioning(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dao := app.Dao()
+
+	collection, err := dao.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	collection.Options["versioning"] = map[string]any{"enabled": true}
+	if err := dao.SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	columns, err := dao.GetTableColumns(collection.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !list.ExistInSlice(schema.FieldNameVersion, columns) {
+		t.Fatalf("Expected the %q column to be added, got %v", schema.FieldNameVersion, columns)
+	}
+
+	// create
+	record := models.NewRecord(collection)
+	record.Set("title", "new")
+	if err := dao.SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Version() != 1 {
+		t.Fatalf("Expected version 1, got %d", record.Version())
+	}
+
+	// update
+	record.Set("title", "new2")
+	if err := dao.SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Version() != 2 {
+		t.Fatalf("Expected version 2, got %d", record.Version())
+	}
+
+	// update an outdated copy
+	outdated, err := dao.FindRecordById(collection.Id, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outdated.Version() != 2 {
+		t.Fatalf("Expected the loaded record version to be 2, got %d", outdated.Version())
+	}
+	record.Set("title", "new3")
+	if err := dao.SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	outdated.Set("title", "outdated")
+	if err := dao.SaveRecord(outdated); !errors.Is(err, daos.ErrRecordVersionConflict) {
+		t.Fatalf("Expected version conflict error, got %v", err)
+	}
+
+	current, err := dao.FindRecordById(collection.Id, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.GetString("title") != "new3" || current.Version() != 3 {
+		t.Fatalf("Expected the record to be left unchanged, got %q (version %d)", current.GetString("title"), current.Version())
+	}
+}
@@ -0,0 +1,146 @@
+package daos
+
+import (
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+// ReportQuery returns a new Report select query.
+func (dao *Dao) ReportQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.Report{})
+}
+
+// FindReportById finds a single Report model by its id.
+func (dao *Dao) FindReportById(id string) (*models.Report, error) {
+	model := &models.Report{}
+
+	err := dao.ReportQuery().
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// FindAllReports returns all Report models (ordered by their name).
+func (dao *Dao) FindAllReports() ([]*models.Report, error) {
+	reports := []*models.Report{}
+
+	err := dao.ReportQuery().OrderBy("name ASC").All(&reports)
+	if err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// FindEnabledReports returns all enabled Report models (ordered by their name).
+func (dao *Dao) FindEnabledReports() ([]*models.Report, error) {
+	reports := []*models.Report{}
+
+	err := dao.ReportQuery().
+		AndWhere(dbx.HashExp{"enabled": true}).
+		OrderBy("name ASC").
+		All(&reports)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// IsReportNameUnique checks if the provided name is unique.
+//
+// Optionally you can specify a list of ids to exclude from the check.
+func (dao *Dao) IsReportNameUnique(name string, excludeIds ...string) bool {
+	if name == "" {
+		return false
+	}
+
+	query := dao.ReportQuery().Select("count(*)").
+		AndWhere(dbx.HashExp{"name": name}).
+		Limit(1)
+
+	if len(excludeIds) > 0 {
+		query.AndWhere(dbx.NotIn("id", list.ToInterfaceSlice(excludeIds)...))
+	}
+
+	var exists bool
+
+	return query.Row(&exists) == nil && !exists
+}
+
+// SaveReport upserts the provided Report model.
+func (dao *Dao) SaveReport(model *models.Report) error {
+	return dao.Save(model)
+}
+
+// DeleteReport deletes the provided Report model
+// (its run history is deleted on cascade).
+func (dao *Dao) DeleteReport(model *models.Report) error {
+	return dao.Delete(model)
+}
+
+// -------------------------------------------------------------------
+
+// ReportRunQuery returns a new ReportRun select query.
+func (dao *Dao) ReportRunQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.ReportRun{})
+}
+
+// FindReportRuns returns the latest runs of the specified report
+// (ordered by their creation date DESC).
+//
+// Set limit to 0 to return all runs.
+func (dao *Dao) FindReportRuns(reportId string, limit int) ([]*models.ReportRun, error) {
+	runs := []*models.ReportRun{}
+
+	query := dao.ReportRunQuery().
+		AndWhere(dbx.HashExp{"reportId": reportId}).
+		OrderBy("created DESC", "rowid DESC")
+
+	if limit > 0 {
+		query.Limit(int64(limit))
+	}
+
+	if err := query.All(&runs); err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+// SaveReportRun upserts the provided ReportRun model.
+func (dao *Dao) SaveReportRun(model *models.ReportRun) error {
+	return dao.Save(model)
+}
+
+// DeleteOldReportRuns deletes the runs of the specified report
+// except the latest `keep` ones.
+func (dao *Dao) DeleteOldReportRuns(reportId string, keep int) error {
+	ids := []string{}
+
+	err := dao.ReportRunQuery().
+		Select("id").
+		AndWhere(dbx.HashExp{"reportId": reportId}).
+		OrderBy("created DESC", "rowid DESC").
+		Offset(int64(keep)).
+		Column(&ids)
+
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+
+	_, err = dao.NonconcurrentDB().Delete(
+		(&models.ReportRun{}).TableName(),
+		dbx.In("id", list.ToInterfaceSlice(ids)...),
+	).Execute()
+
+	return err
+}
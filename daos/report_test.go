@@ -0,0 +1,183 @@
+package daos_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestReportQuery(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	expected := "SELECT {{_reports}}.* FROM `_reports`"
+
+	sql := app.Dao().ReportQuery().Build().SQL()
+	if sql != expected {
+		t.Errorf("Expected sql %s, got %s", expected, sql)
+	}
+}
+
+func TestFindReportById(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		id          string
+		expectError bool
+	}{
+		{"", true},
+		{"missing", true},
+		{"rp1activedemo20", false},
+	}
+
+	for i, s := range scenarios {
+		report, err := app.Dao().FindReportById(s.id)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if report != nil && report.Id != s.id {
+			t.Errorf("(%d) Expected report with id %q, got %q", i, s.id, report.Id)
+		}
+	}
+}
+
+func TestFindAllAndEnabledReports(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	all, err := app.Dao().FindAllReports()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[0].Name != "activeDemo2" || all[1].Name != "countsDemo2" {
+		t.Fatalf("Unexpected reports %v", all)
+	}
+
+	enabled, err := app.Dao().FindEnabledReports()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(enabled) != 1 || enabled[0].Id != "rp1activedemo20" {
+		t.Fatalf("Unexpected enabled reports %v", enabled)
+	}
+}
+
+func TestIsReportNameUnique(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name      string
+		excludeId string
+		expected  bool
+	}{
+		{"", "", false},
+		{"activeDemo2", "", false},
+		{"activeDemo2", "rp1activedemo20", true},
+		{"new", "", true},
+	}
+
+	for i, s := range scenarios {
+		result := app.Dao().IsReportNameUnique(s.name, s.excludeId)
+		if result != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+}
+
+func TestSaveAndDeleteReport(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	report := &models.Report{
+		Name:         "new",
+		CollectionId: "sz5l5z67tg7gku0",
+		Format:       models.ReportFormatJson,
+		Schedule:     "@daily",
+	}
+
+	if err := app.Dao().SaveReport(report); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindReportById(report.Id); err != nil {
+		t.Fatalf("Expected the report to be saved, got %v", err)
+	}
+
+	existing, err := app.Dao().FindReportById("rp1activedemo20")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().DeleteReport(existing); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindReportById(existing.Id); err == nil {
+		t.Fatal("Expected the report to be deleted")
+	}
+
+	// the report runs should be deleted on cascade
+	runs, err := app.Dao().FindReportRuns(existing.Id, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 0 {
+		t.Fatalf("Expected the report runs to be deleted, got %d", len(runs))
+	}
+}
+
+func TestReportRuns(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	runs, err := app.Dao().FindReportRuns("rp1activedemo20", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || runs[0].Id != "rr2activedemo20" || runs[1].Id != "rr1activedemo20" {
+		t.Fatalf("Unexpected runs %v", runs)
+	}
+
+	for i := 0; i < 3; i++ {
+		run := &models.ReportRun{
+			ReportId: "rp1activedemo20",
+			Status:   models.ReportRunStatusSuccess,
+			Rows:     i,
+		}
+		if err := app.Dao().SaveReportRun(run); err != nil {
+			t.Fatalf("(%d) Failed to save run: %v", i, err)
+		}
+	}
+
+	limited, err := app.Dao().FindReportRuns("rp1activedemo20", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(limited) != 2 {
+		t.Fatalf("Expected 2 runs, got %d", len(limited))
+	}
+
+	if err := app.Dao().DeleteOldReportRuns("rp1activedemo20", 3); err != nil {
+		t.Fatal(err)
+	}
+
+	remaining, err := app.Dao().FindReportRuns("rp1activedemo20", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remaining) != 3 {
+		t.Fatalf("Expected 3 runs, got %d", len(remaining))
+	}
+	for _, run := range remaining {
+		if run.Id == "rr1activedemo20" || run.Id == "rr2activedemo20" {
+			t.Fatalf("Expected the old run %q to be deleted", run.Id)
+		}
+	}
+}
@@ -51,6 +51,51 @@ func (dao *Dao) RequestsStats(expr dbx.Expression) ([]*RequestsStatsItem, error)
 	return result, err
 }
 
+// RequestsAggregateItem defines a single grouped requests logs statistics item.
+type RequestsAggregateItem struct {
+	Key    string `db:"key" json:"key"`
+	Total  int    `db:"total" json:"total"`
+	Errors int    `db:"errors" json:"errors"`
+}
+
+// RequestsAggregate returns the requests logs statistics grouped by
+// the provided db expression (eg. "[[url]]", "strftime(...)", etc.).
+//
+// Each group item contains the total number of requests and the number
+// of the failed ones (aka. with status >= 400).
+//
+// The result could be optionally filtered (expr), sorted (orderBy) and
+// limited (limit <= 0 means no limit).
+func (dao *Dao) RequestsAggregate(
+	groupExpr string,
+	expr dbx.Expression,
+	orderBy []string,
+	limit int,
+) ([]*RequestsAggregateItem, error) {
+	result := []*RequestsAggregateItem{}
+
+	query := dao.RequestQuery().
+		Select(
+			"COALESCE("+groupExpr+", '') as key",
+			"count(id) as total",
+			"sum(CASE WHEN [[status]] >= 400 THEN 1 ELSE 0 END) as errors",
+		).
+		GroupBy("key").
+		OrderBy(orderBy...)
+
+	if expr != nil {
+		query.AndWhere(expr)
+	}
+
+	if limit > 0 {
+		query.Limit(int64(limit))
+	}
+
+	err := query.All(&result)
+
+	return result, err
+}
+
 // DeleteOldRequests delete all requests that are created before createdBefore.
 func (dao *Dao) DeleteOldRequests(createdBefore time.Time) error {
 	m := models.Request{}
@@ -74,6 +74,56 @@ func TestRequestsStats(t *testing.T) {
 	}
 }
 
+func TestRequestsAggregate(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	tests.MockRequestLogsData(app)
+
+	scenarios := []struct {
+		groupExpr string
+		expr      dbx.Expression
+		orderBy   []string
+		limit     int
+		expected  string
+	}{
+		{
+			"[[auth]]",
+			nil,
+			[]string{"key ASC"},
+			0,
+			`[{"key":"admin","total":1,"errors":1},{"key":"guest","total":1,"errors":0}]`,
+		},
+		{
+			"[[authId]]",
+			nil,
+			[]string{"key DESC"},
+			1,
+			`[{"key":"sywbhecnh46rhm0","total":1,"errors":1}]`,
+		},
+		{
+			"strftime('%Y-%m', [[created]])",
+			dbx.NewExp("[[status]] < 400"),
+			nil,
+			0,
+			`[{"key":"2022-05","total":1,"errors":0}]`,
+		},
+	}
+
+	for i, s := range scenarios {
+		result, err := app.LogsDao().RequestsAggregate(s.groupExpr, s.expr, s.orderBy, s.limit)
+		if err != nil {
+			t.Errorf("(%d) Unexpected error %v", i, err)
+			continue
+		}
+
+		encoded, _ := json.Marshal(result)
+		if string(encoded) != s.expected {
+			t.Errorf("(%d) Expected %s, got %s", i, s.expected, string(encoded))
+		}
+	}
+}
+
 func TestDeleteOldRequests(t *testing.T) {
 	app, _ := tests.NewTestApp()
 	defer app.Cleanup()
@@ -0,0 +1,209 @@
+package daos
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+// RoleQuery returns a new Role select query.
+func (dao *Dao) RoleQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.Role{})
+}
+
+// AuthRoleQuery returns a new AuthRole select query.
+func (dao *Dao) AuthRoleQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.AuthRole{})
+}
+
+// FindRoleById finds a single Role model by its id.
+func (dao *Dao) FindRoleById(id string) (*models.Role, error) {
+	model := &models.Role{}
+
+	err := dao.RoleQuery().
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// FindRoleByName finds a single Role model by its name (case-sensitive).
+func (dao *Dao) FindRoleByName(name string) (*models.Role, error) {
+	model := &models.Role{}
+
+	err := dao.RoleQuery().
+		AndWhere(dbx.HashExp{"name": name}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// FindAllRoles returns all Role models (ordered by their name).
+func (dao *Dao) FindAllRoles() ([]*models.Role, error) {
+	roles := []*models.Role{}
+
+	err := dao.RoleQuery().
+		OrderBy("name ASC").
+		All(&roles)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// IsRoleNameUnique checks if the provided role name is unique.
+//
+// Optionally you can specify a list of ids to exclude from the check.
+func (dao *Dao) IsRoleNameUnique(name string, excludeIds ...string) bool {
+	if name == "" {
+		return false
+	}
+
+	query := dao.RoleQuery().Select("count(*)").
+		AndWhere(dbx.HashExp{"name": name}).
+		Limit(1)
+
+	if len(excludeIds) > 0 {
+		query.AndWhere(dbx.NotIn("id", list.ToInterfaceSlice(excludeIds)...))
+	}
+
+	var exists bool
+
+	return query.Row(&exists) == nil && !exists
+}
+
+// SaveRole upserts the provided Role model.
+func (dao *Dao) SaveRole(model *models.Role) error {
+	return dao.Save(model)
+}
+
+// DeleteRole deletes the provided Role model
+// together with all of its auth records assignments.
+func (dao *Dao) DeleteRole(model *models.Role) error {
+	return dao.RunInTransaction(func(txDao *Dao) error {
+		assignments := []*models.AuthRole{}
+
+		err := txDao.AuthRoleQuery().
+			AndWhere(dbx.HashExp{"roleId": model.Id}).
+			All(&assignments)
+		if err != nil {
+			return err
+		}
+
+		for _, assignment := range assignments {
+			if err := txDao.Delete(assignment); err != nil {
+				return err
+			}
+		}
+
+		return txDao.Delete(model)
+	})
+}
+
+// FindAuthRecordRoles returns all Role models assigned
+// to the provided auth record (ordered by their name).
+func (dao *Dao) FindAuthRecordRoles(authRecord *models.Record) ([]*models.Role, error) {
+	roles := []*models.Role{}
+
+	err := dao.RoleQuery().
+		InnerJoin(
+			"{{_authRoles}} ar",
+			dbx.NewExp("[[ar.roleId]] = [[_roles.id]]"),
+		).
+		AndWhere(dbx.HashExp{
+			"ar.collectionId": authRecord.Collection().Id,
+			"ar.recordId":     authRecord.Id,
+		}).
+		OrderBy("_roles.name ASC").
+		All(&roles)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// FindAuthRole returns the AuthRole model that assigns
+// the provided role to the specified auth record.
+func (dao *Dao) FindAuthRole(authRecord *models.Record, role *models.Role) (*models.AuthRole, error) {
+	model := &models.AuthRole{}
+
+	err := dao.AuthRoleQuery().
+		AndWhere(dbx.HashExp{
+			"collectionId": authRecord.Collection().Id,
+			"recordId":     authRecord.Id,
+			"roleId":       role.Id,
+		}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// AssignAuthRecordRole assigns the provided role to the specified auth record
+// (it is a no-op if the role is already assigned).
+func (dao *Dao) AssignAuthRecordRole(authRecord *models.Record, role *models.Role) error {
+	if !authRecord.Collection().IsAuth() {
+		return errors.New("Roles could be assigned only to auth records.")
+	}
+
+	if role.Id == "" || authRecord.Id == "" {
+		return errors.New("Missing role or auth record id.")
+	}
+
+	if _, err := dao.FindAuthRole(authRecord, role); err == nil {
+		return nil // already assigned
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	return dao.Save(&models.AuthRole{
+		CollectionId: authRecord.Collection().Id,
+		RecordId:     authRecord.Id,
+		RoleId:       role.Id,
+	})
+}
+
+// UnassignAuthRecordRole removes the provided role assignment
+// from the specified auth record (if any).
+func (dao *Dao) UnassignAuthRecordRole(authRecord *models.Record, role *models.Role) error {
+	model, err := dao.FindAuthRole(authRecord, role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil // not assigned
+		}
+		return err
+	}
+
+	return dao.Delete(model)
+}
+
+// DeleteAuthRecordRoles deletes all role assignments of the provided auth record.
+func (dao *Dao) DeleteAuthRecordRoles(authRecord *models.Record) error {
+	_, err := dao.DB().Delete((&models.AuthRole{}).TableName(), dbx.HashExp{
+		"collectionId": authRecord.Collection().Id,
+		"recordId":     authRecord.Id,
+	}).Execute()
+
+	return err
+}
@@ -0,0 +1,155 @@
+package daos_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func createTestRole(t *testing.T, dao *daos.Dao, name string) *models.Role {
+	role := &models.Role{Name: name}
+
+	if err := dao.SaveRole(role); err != nil {
+		t.Fatalf("Failed to create role %q: %v", name, err)
+	}
+
+	return role
+}
+
+func TestRoles(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dao := app.Dao()
+
+	editor := createTestRole(t, dao, "editor")
+	createTestRole(t, dao, "admin")
+
+	if dao.IsRoleNameUnique("editor") {
+		t.Fatal("Expected the editor role name to be taken")
+	}
+	if !dao.IsRoleNameUnique("editor", editor.Id) {
+		t.Fatal("Expected the editor role name to be unique when excluding its id")
+	}
+	if !dao.IsRoleNameUnique("viewer") {
+		t.Fatal("Expected the viewer role name to be unique")
+	}
+
+	found, err := dao.FindRoleByName("editor")
+	if err != nil || found.Id != editor.Id {
+		t.Fatalf("Expected to find the editor role, got %v (%v)", found, err)
+	}
+
+	if _, err := dao.FindRoleById(editor.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	roles, err := dao.FindAllRoles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roles) != 2 || roles[0].Name != "admin" || roles[1].Name != "editor" {
+		t.Fatalf("Expected the roles ordered by name, got %v", roles)
+	}
+
+	if err := dao.DeleteRole(editor); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dao.FindRoleByName("editor"); err == nil {
+		t.Fatal("Expected the editor role to be deleted")
+	}
+}
+
+func TestAuthRecordRoles(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dao := app.Dao()
+
+	editor := createTestRole(t, dao, "editor")
+	viewer := createTestRole(t, dao, "viewer")
+
+	user1, err := dao.FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user2, err := dao.FindRecordById("users", "oap640cot4yru2s")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demo, err := dao.FindRecordById("demo2", "llvuca81nly1qls")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dao.AssignAuthRecordRole(demo, editor); err == nil {
+		t.Fatal("Expected the role assignment of a non auth record to fail")
+	}
+
+	for _, role := range []*models.Role{viewer, editor, editor} {
+		if err := dao.AssignAuthRecordRole(user1, role); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := dao.AssignAuthRecordRole(user2, editor); err != nil {
+		t.Fatal(err)
+	}
+
+	assertRoleNames := func(record *models.Record, expected ...string) {
+		t.Helper()
+
+		roles, err := dao.FindAuthRecordRoles(record)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		names := make([]string, len(roles))
+		for i, role := range roles {
+			names[i] = role.Name
+		}
+
+		if len(names) != len(expected) {
+			t.Fatalf("Expected %q roles %v, got %v", record.Id, expected, names)
+		}
+		for i, name := range expected {
+			if names[i] != name {
+				t.Fatalf("Expected %q roles %v, got %v", record.Id, expected, names)
+			}
+		}
+	}
+
+	assertRoleNames(user1, "editor", "viewer")
+	assertRoleNames(user2, "editor")
+
+	// unassign
+	if err := dao.UnassignAuthRecordRole(user1, viewer); err != nil {
+		t.Fatal(err)
+	}
+	if err := dao.UnassignAuthRecordRole(user1, viewer); err != nil {
+		t.Fatalf("Expected unassigning a missing role to be a no-op, got %v", err)
+	}
+	assertRoleNames(user1, "editor")
+
+	// delete together with the auth record
+	if err := dao.DeleteRecord(user1); err != nil {
+		t.Fatal(err)
+	}
+	var total int
+	if err := dao.AuthRoleQuery().Select("count(*)").AndWhere(dbx.HashExp{"recordId": user1.Id}).Row(&total); err != nil {
+		t.Fatal(err)
+	}
+	if total != 0 {
+		t.Fatalf("Expected the deleted record roles to be removed, got %d", total)
+	}
+
+	// delete together with the role
+	if err := dao.DeleteRole(editor); err != nil {
+		t.Fatal(err)
+	}
+	assertRoleNames(user2)
+}
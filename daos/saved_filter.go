@@ -0,0 +1,100 @@
+package daos
+
+import (
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+// SavedFilterQuery returns a new SavedFilter select query.
+func (dao *Dao) SavedFilterQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.SavedFilter{})
+}
+
+// FindSavedFilterById finds a single SavedFilter model by its id.
+func (dao *Dao) FindSavedFilterById(id string) (*models.SavedFilter, error) {
+	model := &models.SavedFilter{}
+
+	err := dao.SavedFilterQuery().
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// FindSavedFiltersByCollection returns all SavedFilter models
+// of the provided collection id (ordered by their name).
+func (dao *Dao) FindSavedFiltersByCollection(collectionId string) ([]*models.SavedFilter, error) {
+	filters := []*models.SavedFilter{}
+
+	err := dao.SavedFilterQuery().
+		AndWhere(dbx.HashExp{"collectionId": collectionId}).
+		OrderBy("name ASC").
+		All(&filters)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return filters, nil
+}
+
+// FindSavedFilterByName finds a single SavedFilter model
+// by its collection id and name (case-sensitive).
+func (dao *Dao) FindSavedFilterByName(collectionId string, name string) (*models.SavedFilter, error) {
+	model := &models.SavedFilter{}
+
+	err := dao.SavedFilterQuery().
+		AndWhere(dbx.HashExp{
+			"collectionId": collectionId,
+			"name":         name,
+		}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// IsSavedFilterNameUnique checks if the provided name is unique
+// within the saved filters of the specified collection.
+//
+// Optionally you can specify a list of ids to exclude from the check.
+func (dao *Dao) IsSavedFilterNameUnique(collectionId string, name string, excludeIds ...string) bool {
+	if name == "" {
+		return false
+	}
+
+	query := dao.SavedFilterQuery().Select("count(*)").
+		AndWhere(dbx.HashExp{
+			"collectionId": collectionId,
+			"name":         name,
+		}).
+		Limit(1)
+
+	if len(excludeIds) > 0 {
+		query.AndWhere(dbx.NotIn("id", list.ToInterfaceSlice(excludeIds)...))
+	}
+
+	var exists bool
+
+	return query.Row(&exists) == nil && !exists
+}
+
+// SaveSavedFilter upserts the provided SavedFilter model.
+func (dao *Dao) SaveSavedFilter(model *models.SavedFilter) error {
+	return dao.Save(model)
+}
+
+// DeleteSavedFilter deletes the provided SavedFilter model.
+func (dao *Dao) DeleteSavedFilter(model *models.SavedFilter) error {
+	return dao.Delete(model)
+}
@@ -0,0 +1,197 @@
+package daos_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestSavedFilterQuery(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	expected := "SELECT {{_savedFilters}}.* FROM `_savedFilters`"
+
+	sql := app.Dao().SavedFilterQuery().Build().SQL()
+	if sql != expected {
+		t.Errorf("Expected sql %s, got %s", expected, sql)
+	}
+}
+
+func TestFindSavedFilterById(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		id          string
+		expectError bool
+	}{
+		{"", true},
+		{"missing", true},
+		{"sf1activetitle0", false},
+	}
+
+	for i, s := range scenarios {
+		model, err := app.Dao().FindSavedFilterById(s.id)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr to be %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if model != nil && model.Id != s.id {
+			t.Errorf("(%d) Expected model with id %s, got %s", i, s.id, model.Id)
+		}
+	}
+}
+
+func TestFindSavedFiltersByCollection(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		collectionId string
+		expectedIds  []string
+	}{
+		{"", []string{}},
+		{"missing", []string{}},
+		{"sz5l5z67tg7gku0", []string{"sf1activetitle0", "sf2recentcreat0"}},
+		{"wsmn24bux7wo113", []string{"sf3demo1text000"}},
+	}
+
+	for i, s := range scenarios {
+		filters, err := app.Dao().FindSavedFiltersByCollection(s.collectionId)
+		if err != nil {
+			t.Errorf("(%d) Unexpected error %v", i, err)
+			continue
+		}
+
+		if len(filters) != len(s.expectedIds) {
+			t.Errorf("(%d) Expected %d filters, got %d", i, len(s.expectedIds), len(filters))
+			continue
+		}
+
+		for j, id := range s.expectedIds {
+			if filters[j].Id != id {
+				t.Errorf("(%d) Expected filter %d to be %s, got %s", i, j, id, filters[j].Id)
+			}
+		}
+	}
+}
+
+func TestFindSavedFilterByName(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		collectionId string
+		name         string
+		expectedId   string
+	}{
+		{"", "", ""},
+		{"sz5l5z67tg7gku0", "missing", ""},
+		{"sz5l5z67tg7gku0", "ACTIVEBYTITLE", ""},
+		{"wsmn24bux7wo113", "activeByTitle", ""},
+		{"sz5l5z67tg7gku0", "activeByTitle", "sf1activetitle0"},
+	}
+
+	for i, s := range scenarios {
+		model, err := app.Dao().FindSavedFilterByName(s.collectionId, s.name)
+
+		hasErr := err != nil
+		expectErr := s.expectedId == ""
+		if hasErr != expectErr {
+			t.Errorf("(%d) Expected hasErr to be %v, got %v (%v)", i, expectErr, hasErr, err)
+			continue
+		}
+
+		if model != nil && model.Id != s.expectedId {
+			t.Errorf("(%d) Expected model with id %s, got %s", i, s.expectedId, model.Id)
+		}
+	}
+}
+
+func TestIsSavedFilterNameUnique(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		collectionId string
+		name         string
+		excludeId    string
+		expected     bool
+	}{
+		{"sz5l5z67tg7gku0", "", "", false},
+		{"sz5l5z67tg7gku0", "activeByTitle", "", false},
+		{"sz5l5z67tg7gku0", "activeByTitle", "sf1activetitle0", true},
+		{"sz5l5z67tg7gku0", "new", "", true},
+		{"wsmn24bux7wo113", "activeByTitle", "", true},
+	}
+
+	for i, s := range scenarios {
+		result := app.Dao().IsSavedFilterNameUnique(s.collectionId, s.name, s.excludeId)
+		if result != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+}
+
+func TestSaveSavedFilter(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	model := &models.SavedFilter{
+		CollectionId: "sz5l5z67tg7gku0",
+		Name:         "test",
+		Filter:       "title = {:title}",
+		Params: models.SavedFilterParams{
+			{Name: "title", Type: models.SavedFilterParamTypeText},
+		},
+	}
+
+	if err := app.Dao().SaveSavedFilter(model); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := app.Dao().FindSavedFilterByName("sz5l5z67tg7gku0", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if found.Id != model.Id || found.Filter != model.Filter {
+		t.Fatalf("Expected saved filter %v, got %v", model, found)
+	}
+
+	if len(found.Params) != 1 || found.Params[0].Name != "title" {
+		t.Fatalf("Expected the params to be saved, got %v", found.Params)
+	}
+
+	// duplicated name
+	duplicated := &models.SavedFilter{
+		CollectionId: "sz5l5z67tg7gku0",
+		Name:         "test",
+	}
+	if err := app.Dao().SaveSavedFilter(duplicated); err == nil {
+		t.Fatal("Expected duplicated name error, got nil")
+	}
+}
+
+func TestDeleteSavedFilter(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	model, err := app.Dao().FindSavedFilterById("sf1activetitle0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().DeleteSavedFilter(model); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindSavedFilterById("sf1activetitle0"); err == nil {
+		t.Fatal("Expected the saved filter to be deleted")
+	}
+}
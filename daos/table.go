@@ -2,20 +2,43 @@ package daos
 
 import (
 	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
 )
 
 // HasTable checks if a table with the provided name exists (case insensitive).
+//
+// The tables of the attached databases are also checked.
 func (dao *Dao) HasTable(tableName string) bool {
-	var exists bool
+	// the attached databases errors are ignored to preserve the main db check
+	attached, _ := dao.AttachedDatabases()
 
-	err := dao.DB().Select("count(*)").
-		From("sqlite_schema").
-		AndWhere(dbx.HashExp{"type": "table"}).
-		AndWhere(dbx.NewExp("LOWER([[name]])=LOWER({:tableName})", dbx.Params{"tableName": tableName})).
-		Limit(1).
-		Row(&exists)
+	for _, db := range append([]string{"main"}, attached...) {
+		var exists bool
 
-	return err == nil && exists
+		err := dao.DB().Select("count(*)").
+			From(db + ".sqlite_schema").
+			AndWhere(dbx.HashExp{"type": "table"}).
+			AndWhere(dbx.NewExp("LOWER([[name]])=LOWER({:tableName})", dbx.Params{"tableName": tableName})).
+			Limit(1).
+			Row(&exists)
+
+		if err == nil && exists {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AttachedDatabases returns the names of the databases attached
+// to the dao db connection (excluding the "main" and "temp" ones).
+func (dao *Dao) AttachedDatabases() ([]string, error) {
+	names := []string{}
+
+	err := dao.DB().NewQuery("SELECT name FROM pragma_database_list WHERE name NOT IN ('main', 'temp') ORDER BY seq").
+		Column(&names)
+
+	return names, err
 }
 
 // GetTableColumns returns all column names of a single table by its name.
@@ -36,10 +59,36 @@ func (dao *Dao) DeleteTable(tableName string) error {
 	return err
 }
 
-// Vacuum executes VACUUM on the current dao.DB() instance in order to
-// reclaim unused db disk space.
+// Vacuum executes VACUUM on the current dao.DB() instance
+// (including its attached databases) in order to reclaim unused db disk space.
 func (dao *Dao) Vacuum() error {
-	_, err := dao.DB().NewQuery("VACUUM").Execute()
+	if _, err := dao.DB().NewQuery("VACUUM").Execute(); err != nil {
+		return err
+	}
 
-	return err
+	attached, err := dao.AttachedDatabases()
+	if err != nil {
+		return err
+	}
+
+	for _, db := range attached {
+		if _, err := dao.DB().NewQuery("VACUUM " + dao.DB().QuoteSimpleTableName(db)).Execute(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recordSchemaObjectName returns the provided collection index (or trigger)
+// name prefixed with the collection attached database (if any).
+//
+// This is necessary because the sqlite indexes are always
+// created in the same database as their table.
+func recordSchemaObjectName(collection *models.Collection, name string) string {
+	if db := collection.Database(); db != "" {
+		return db + "." + name
+	}
+
+	return name
 }
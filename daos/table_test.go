@@ -3,11 +3,21 @@ package daos_test
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/migrations"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/resolvers"
 	"github.com/pocketbase/pocketbase/tests"
 	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/migrate"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/types"
 )
 
 func TestHasTable(t *testing.T) {
@@ -99,11 +109,179 @@ func TestVacuum(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if total := len(calledQueries); total != 1 {
-		t.Fatalf("Expected 1 query, got %d", total)
+	// VACUUM + attached databases lookup
+	if total := len(calledQueries); total != 2 {
+		t.Fatalf("Expected 2 queries, got %d", total)
 	}
 
 	if calledQueries[0] != "VACUUM" {
 		t.Fatalf("Expected VACUUM query, got %s", calledQueries[0])
 	}
 }
+
+// newAttachedDatabaseTestApp creates a new empty app instance
+// with an attached "analytics" database.
+func newAttachedDatabaseTestApp(t *testing.T) *core.BaseApp {
+	app := core.NewBaseApp(&core.BaseAppConfig{
+		DataDir:           t.TempDir(),
+		AttachedDatabases: []string{"analytics"},
+	})
+
+	if err := app.Bootstrap(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		app.ResetBootstrapState()
+	})
+
+	runner, err := migrate.NewRunner(app.DB(), migrations.AppMigrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runner.Up(); err != nil {
+		t.Fatal(err)
+	}
+
+	return app
+}
+
+func TestAttachedDatabases(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	attached, err := app.Dao().AttachedDatabases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attached) != 0 {
+		t.Fatalf("Expected no attached databases, got %v", attached)
+	}
+
+	attachedApp := newAttachedDatabaseTestApp(t)
+
+	attached, err = attachedApp.Dao().AttachedDatabases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if str := strings.Join(attached, ","); str != "analytics" {
+		t.Fatalf("Expected attached databases %q, got %q", "analytics", str)
+	}
+}
+
+func TestAttachedDatabaseCollection(t *testing.T) {
+	app := newAttachedDatabaseTestApp(t)
+	dao := app.Dao()
+
+	events := &models.Collection{
+		Name: "events",
+		Type: models.CollectionTypeBase,
+		Schema: schema.NewSchema(&schema.SchemaField{
+			Name: "title",
+			Type: schema.FieldTypeText,
+		}),
+	}
+	events.SetOptions(models.CollectionBaseOptions{
+		Database:   "analytics",
+		SoftDelete: models.CollectionSoftDeleteOptions{Enabled: true},
+	})
+	if err := dao.SaveCollection(events); err != nil {
+		t.Fatal(err)
+	}
+
+	posts := &models.Collection{
+		Name: "posts",
+		Type: models.CollectionTypeBase,
+		Schema: schema.NewSchema(&schema.SchemaField{
+			Name: "event",
+			Type: schema.FieldTypeRelation,
+			Options: &schema.RelationOptions{
+				CollectionId: events.Id,
+				MaxSelect:    types.Pointer(1),
+			},
+		}),
+	}
+	if err := dao.SaveCollection(posts); err != nil {
+		t.Fatal(err)
+	}
+
+	// the table and its indexes must be stored only in the attached db
+	objectsScenarios := []struct {
+		db       string
+		expected int
+	}{
+		{"main", 0},
+		{"analytics", 3}, // table + created and deleted indexes
+	}
+	for _, s := range objectsScenarios {
+		var total int
+		err := dao.DB().Select("count(*)").
+			From(s.db + ".sqlite_schema").
+			AndWhere(dbx.NewExp("[[tbl_name]] = 'events' AND [[sql]] IS NOT NULL")).
+			Row(&total)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != s.expected {
+			t.Fatalf("Expected %d %s objects, got %d", s.expected, s.db, total)
+		}
+	}
+
+	if !dao.HasTable("events") {
+		t.Fatal("Expected the attached db table to exist")
+	}
+
+	event := models.NewRecord(events)
+	event.Set("title", "test")
+	if err := dao.SaveRecord(event); err != nil {
+		t.Fatal(err)
+	}
+
+	post := models.NewRecord(posts)
+	post.Set("event", event.Id)
+	if err := dao.SaveRecord(post); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := dao.FindRecordById(events.Name, event.Id); err != nil {
+		t.Fatalf("Failed to find the attached db record: %v", err)
+	}
+
+	filterScenarios := []struct {
+		collection *models.Collection
+		filter     string
+		expected   int
+	}{
+		{posts, `event.title = "test"`, 1},
+		{posts, `event.title = "missing"`, 0},
+		{events, `@collection.posts.event = id`, 1},
+	}
+	for _, s := range filterScenarios {
+		resolver := resolvers.NewRecordFieldResolver(dao, s.collection, nil, true)
+
+		records := []*models.Record{}
+		_, err := search.NewProvider(resolver).
+			Query(dao.RecordQuery(s.collection)).
+			AddFilter(search.FilterData(s.filter)).
+			Exec(&records)
+		if err != nil {
+			t.Fatalf("[%s] %v", s.filter, err)
+		}
+		if len(records) != s.expected {
+			t.Fatalf("[%s] Expected %d records, got %d", s.filter, s.expected, len(records))
+		}
+	}
+
+	if err := dao.Vacuum(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dao.DeleteCollection(posts); err != nil {
+		t.Fatal(err)
+	}
+	if err := dao.DeleteCollection(events); err != nil {
+		t.Fatal(err)
+	}
+	if dao.HasTable("events") {
+		t.Fatal("Expected the attached db table to be deleted")
+	}
+}
@@ -0,0 +1,100 @@
+package daos
+
+import (
+	"errors"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// WebauthnCredentialQuery returns a new WebauthnCredential select query.
+func (dao *Dao) WebauthnCredentialQuery() *dbx.SelectQuery {
+	return dao.ModelQuery(&models.WebauthnCredential{})
+}
+
+// FindAllWebauthnCredentialsByRecord returns all WebauthnCredential
+// models registered for the provided auth record.
+func (dao *Dao) FindAllWebauthnCredentialsByRecord(authRecord *models.Record) ([]*models.WebauthnCredential, error) {
+	credentials := []*models.WebauthnCredential{}
+
+	err := dao.WebauthnCredentialQuery().
+		AndWhere(dbx.HashExp{
+			"collectionId": authRecord.Collection().Id,
+			"recordId":     authRecord.Id,
+		}).
+		OrderBy("created ASC").
+		All(&credentials)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials, nil
+}
+
+// FindWebauthnCredentialByRecordAndId returns the WebauthnCredential
+// model with the specified id registered for the provided auth record.
+func (dao *Dao) FindWebauthnCredentialByRecordAndId(authRecord *models.Record, id string) (*models.WebauthnCredential, error) {
+	model := &models.WebauthnCredential{}
+
+	err := dao.WebauthnCredentialQuery().
+		AndWhere(dbx.HashExp{
+			"id":           id,
+			"collectionId": authRecord.Collection().Id,
+			"recordId":     authRecord.Id,
+		}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// FindWebauthnCredentialByCredentialId returns the WebauthnCredential model
+// with the specified authenticator credential id from the auth collection.
+func (dao *Dao) FindWebauthnCredentialByCredentialId(collectionId string, credentialId string) (*models.WebauthnCredential, error) {
+	model := &models.WebauthnCredential{}
+
+	err := dao.WebauthnCredentialQuery().
+		AndWhere(dbx.Not(dbx.HashExp{"credentialId": ""})).
+		AndWhere(dbx.HashExp{
+			"collectionId": collectionId,
+			"credentialId": credentialId,
+		}).
+		Limit(1).
+		One(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// SaveWebauthnCredential upserts the provided WebauthnCredential model.
+func (dao *Dao) SaveWebauthnCredential(model *models.WebauthnCredential) error {
+	if model.CollectionId == "" || model.RecordId == "" || model.CredentialId == "" || model.PublicKey == "" {
+		return errors.New("Missing required WebauthnCredential fields.")
+	}
+
+	return dao.Save(model)
+}
+
+// DeleteWebauthnCredential deletes the provided WebauthnCredential model.
+func (dao *Dao) DeleteWebauthnCredential(model *models.WebauthnCredential) error {
+	return dao.Delete(model)
+}
+
+// DeleteAuthRecordWebauthnCredentials deletes all WebauthnCredential
+// models registered for the provided auth record.
+func (dao *Dao) DeleteAuthRecordWebauthnCredentials(authRecord *models.Record) error {
+	_, err := dao.DB().Delete((&models.WebauthnCredential{}).TableName(), dbx.HashExp{
+		"collectionId": authRecord.Collection().Id,
+		"recordId":     authRecord.Id,
+	}).Execute()
+
+	return err
+}
@@ -0,0 +1,208 @@
+package daos_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestWebauthnCredentialQuery(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	expected := "SELECT {{_webauthnCredentials}}.* FROM `_webauthnCredentials`"
+
+	sql := app.Dao().WebauthnCredentialQuery().Build().SQL()
+	if sql != expected {
+		t.Errorf("Expected sql %s, got %s", expected, sql)
+	}
+}
+
+func TestFindAllWebauthnCredentialsByRecord(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		userId        string
+		expectedCount int
+	}{
+		{"bgs820n361vj1qd", 0},
+		{"4q1xlclmfloku33", 1},
+	}
+
+	for i, s := range scenarios {
+		record, err := app.Dao().FindRecordById("users", s.userId)
+		if err != nil {
+			t.Errorf("(%d) Unexpected record fetch error %v", i, err)
+			continue
+		}
+
+		credentials, err := app.Dao().FindAllWebauthnCredentialsByRecord(record)
+		if err != nil {
+			t.Errorf("(%d) Unexpected credentials fetch error %v", i, err)
+			continue
+		}
+
+		if len(credentials) != s.expectedCount {
+			t.Errorf("(%d) Expected %d credentials, got %d", i, s.expectedCount, len(credentials))
+		}
+
+		for _, credential := range credentials {
+			if credential.RecordId != record.Id {
+				t.Errorf("(%d) Expected all credentials to be linked to record id %s, got %v", i, record.Id, credential)
+			}
+		}
+	}
+}
+
+func TestFindWebauthnCredentialByRecordAndId(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		id          string
+		expectError bool
+	}{
+		{"", true},
+		{"missing", true},
+		{"wacred000000002", true}, // other user
+		{"wacred000000001", false},
+	}
+
+	for i, s := range scenarios {
+		credential, err := app.Dao().FindWebauthnCredentialByRecordAndId(user, s.id)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if !hasErr && credential.Id != s.id {
+			t.Errorf("(%d) Expected credential %q, got %q", i, s.id, credential.Id)
+		}
+	}
+}
+
+func TestFindWebauthnCredentialByCredentialId(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		collectionId string
+		credentialId string
+		expectedId   string
+	}{
+		{"", "", ""},
+		{"_pb_users_auth_", "", ""},
+		{"_pb_users_auth_", "missing", ""},
+		{"v851q4r790rhknl", "dGVzdF9jcmVkXzE", ""},
+		{"_pb_users_auth_", "dGVzdF9jcmVkXzE", "wacred000000001"},
+		{"_pb_users_auth_", "dGVzdF9jcmVkXzI", "wacred000000002"},
+	}
+
+	for i, s := range scenarios {
+		credential, err := app.Dao().FindWebauthnCredentialByCredentialId(s.collectionId, s.credentialId)
+
+		hasErr := err != nil
+		expectErr := s.expectedId == ""
+		if hasErr != expectErr {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, expectErr, hasErr, err)
+			continue
+		}
+
+		if !hasErr && credential.Id != s.expectedId {
+			t.Errorf("(%d) Expected credential %q, got %q", i, s.expectedId, credential.Id)
+		}
+	}
+}
+
+func TestSaveWebauthnCredential(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	// save with missing required fields
+	invalid := &models.WebauthnCredential{CollectionId: "_pb_users_auth_", RecordId: "bgs820n361vj1qd"}
+	if err := app.Dao().SaveWebauthnCredential(invalid); err == nil {
+		t.Fatal("Expected error for missing required fields, got nil")
+	}
+
+	credential := &models.WebauthnCredential{
+		CollectionId: "_pb_users_auth_",
+		RecordId:     "bgs820n361vj1qd",
+		Name:         "test",
+		CredentialId: "new_credential",
+		PublicKey:    "new_key",
+		Algorithm:    -7,
+	}
+
+	if err := app.Dao().SaveWebauthnCredential(credential); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := app.Dao().FindWebauthnCredentialByCredentialId("_pb_users_auth_", "new_credential")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if found.Id != credential.Id || found.PublicKey != "new_key" {
+		t.Fatalf("Expected the saved credential, got %v", found)
+	}
+}
+
+func TestDeleteWebauthnCredential(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	credential, err := app.Dao().FindWebauthnCredentialByRecordAndId(user, "wacred000000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().DeleteWebauthnCredential(credential); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.Dao().FindWebauthnCredentialByRecordAndId(user, "wacred000000001"); err == nil {
+		t.Fatal("Expected the credential to be deleted")
+	}
+}
+
+func TestDeleteAuthRecordWebauthnCredentials(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Dao().DeleteRecord(user); err != nil {
+		t.Fatal(err)
+	}
+
+	credentials, err := app.Dao().FindAllWebauthnCredentialsByRecord(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(credentials) != 0 {
+		t.Fatalf("Expected the record credentials to be deleted, got %d", len(credentials))
+	}
+
+	// the other records credentials should remain
+	if _, err := app.Dao().FindWebauthnCredentialByCredentialId("_pb_users_auth_", "dGVzdF9jcmVkXzI"); err != nil {
+		t.Fatalf("Expected the other user credential to remain, got %v", err)
+	}
+}
@@ -5,10 +5,14 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/pocketbase/pocketbase"
 	"github.com/pocketbase/pocketbase/apis"
 	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/plugins/devmode"
+	"github.com/pocketbase/pocketbase/plugins/docgen"
+	"github.com/pocketbase/pocketbase/plugins/exportcmd"
 	"github.com/pocketbase/pocketbase/plugins/jsvm"
 	"github.com/pocketbase/pocketbase/plugins/migratecmd"
 )
@@ -36,6 +40,30 @@ func main() {
 		"enable/disable auto migrations",
 	)
 
+	var exportsDir string
+	app.RootCmd.PersistentFlags().StringVar(
+		&exportsDir,
+		"exportsDir",
+		"",
+		"the directory to store the Parquet collections exports",
+	)
+
+	var exportsInterval time.Duration
+	app.RootCmd.PersistentFlags().DurationVar(
+		&exportsInterval,
+		"exportsInterval",
+		0,
+		"auto export the collections as Parquet files on the specified interval while serving (eg. 1h)",
+	)
+
+	var documentsCommand string
+	app.RootCmd.PersistentFlags().StringVar(
+		&documentsCommand,
+		"documentsCommand",
+		"",
+		"the command to convert the records document templates into PDF (eg. \"wkhtmltopdf --quiet - -\"); default to plain HTML documents",
+	)
+
 	var publicDir string
 	app.RootCmd.PersistentFlags().StringVar(
 		&publicDir,
@@ -52,6 +80,14 @@ func main() {
 		"fallback the request to index.html on missing static path (eg. when pretty urls are used with SPA)",
 	)
 
+	var dev bool
+	app.RootCmd.PersistentFlags().BoolVar(
+		&dev,
+		"dev",
+		false,
+		"enable the local dev mode (hot reload on pb_hooks, pb_migrations and templates change and a request/hook logs console)",
+	)
+
 	app.RootCmd.ParseFlags(os.Args[1:])
 
 	// ---------------------------------------------------------------
@@ -70,6 +106,24 @@ func main() {
 		Dir:          migrationsDir,
 	})
 
+	// export command (Parquet files)
+	exportcmd.MustRegister(app, app.RootCmd, &exportcmd.Options{
+		Dir:      exportsDir,
+		Interval: exportsInterval,
+	})
+
+	// records documents generation (from the templates dir)
+	docgen.MustRegister(app, &docgen.Options{
+		Renderer: documentsRenderer(documentsCommand),
+	})
+
+	// local dev mode (hot reload and logs console)
+	if dev {
+		devmode.MustRegister(app, &devmode.Options{
+			WatchDirs: devWatchDirs(app, migrationsDir),
+		})
+	}
+
 	app.OnBeforeServe().Add(func(e *core.ServeEvent) error {
 		// serves static files from the provided public dir (if exists)
 		e.Router.GET("/*", apis.StaticDirectoryHandler(os.DirFS(publicDir), indexFallback))
@@ -89,3 +143,28 @@ func defaultPublicDir() string {
 	}
 	return filepath.Join(os.Args[0], "../pb_public")
 }
+
+// devWatchDirs returns the dev mode watched directories
+// (the migrations dir could be customized with --migrationsDir).
+func devWatchDirs(app *pocketbase.PocketBase, migrationsDir string) []string {
+	if migrationsDir == "" {
+		migrationsDir = filepath.Join(app.DataDir(), "../pb_migrations")
+	}
+
+	return []string{
+		filepath.Join(app.DataDir(), "../pb_hooks"),
+		migrationsDir,
+		filepath.Join(app.DataDir(), "../templates"),
+	}
+}
+
+// documentsRenderer returns the records documents renderer
+// for the provided command (if any).
+func documentsRenderer(command string) docgen.Renderer {
+	parts := strings.Fields(command)
+	if len(parts) == 0 {
+		return nil // fallback to the default html renderer
+	}
+
+	return &docgen.CommandRenderer{Command: parts[0], Args: parts[1:]}
+}
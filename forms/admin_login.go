@@ -1,7 +1,7 @@
 package forms
 
 import (
-	"errors"
+	"log"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
@@ -14,6 +14,7 @@ import (
 type AdminLogin struct {
 	app core.App
 	dao *daos.Dao
+	ip  string
 
 	Identity string `form:"identity" json:"identity"`
 	Password string `form:"password" json:"password"`
@@ -36,6 +37,12 @@ func (form *AdminLogin) SetDao(dao *daos.Dao) {
 	form.dao = dao
 }
 
+// SetIp sets the client ip of the login request
+// (used for the failed login attempts throttling).
+func (form *AdminLogin) SetIp(ip string) {
+	form.ip = ip
+}
+
 // Validate makes the form validatable by implementing [validation.Validatable] interface.
 func (form *AdminLogin) Validate() error {
 	return validation.ValidateStruct(form,
@@ -45,20 +52,43 @@ func (form *AdminLogin) Validate() error {
 }
 
 // Submit validates and submits the admin form.
+//
+// If the app settings login throttling is enabled, returns
+// [LoginLockedError] while the identity or the client ip is locked.
+//
 // On success returns the authorized admin model.
 func (form *AdminLogin) Submit() (*models.Admin, error) {
 	if err := form.Validate(); err != nil {
 		return nil, err
 	}
 
-	admin, err := form.dao.FindAdminByEmail(form.Identity)
-	if err != nil {
+	throttle := &loginThrottle{
+		app:      form.app,
+		dao:      form.dao,
+		identity: form.Identity,
+		ip:       form.ip,
+	}
+
+	if err := throttle.check(); err != nil {
 		return nil, err
 	}
 
-	if admin.ValidatePassword(form.Password) {
-		return admin, nil
+	admin, err := form.dao.FindAdminByEmail(form.Identity)
+	if err != nil || !admin.ValidatePassword(form.Password) {
+		if throttleErr := throttle.registerFailure(); throttleErr != nil && form.app.IsDebug() {
+			log.Println("Failed to register the failed login attempt:", throttleErr)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, errInvalidLoginCredentials
+	}
+
+	if throttleErr := throttle.reset(); throttleErr != nil && form.app.IsDebug() {
+		log.Println("Failed to reset the failed login attempts:", throttleErr)
 	}
 
-	return nil, errors.New("Invalid login credentials.")
+	return admin, nil
 }
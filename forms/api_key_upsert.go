@@ -0,0 +1,137 @@
+package forms
+
+import (
+	"regexp"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+var apiKeyScopeRegex = regexp.MustCompile(`^\w*$`)
+
+// ApiKeyUpsert is a [models.ApiKey] upsert (create/update) form.
+type ApiKeyUpsert struct {
+	app      core.App
+	dao      *daos.Dao
+	apiKey   *models.ApiKey
+	plainKey string
+
+	Name string `form:"name" json:"name"`
+
+	// Scopes maps collection names or ids to the
+	// api key scope for the related collection.
+	Scopes map[string]string `form:"scopes" json:"scopes"`
+
+	// RegenerateKey replaces the key of an existing api key
+	// (new api keys always have their key generated).
+	RegenerateKey bool `form:"regenerateKey" json:"regenerateKey"`
+}
+
+// NewApiKeyUpsert creates a new [ApiKeyUpsert] form with initializer
+// config created from the provided [core.App] and [models.ApiKey] instances
+// (for create you could pass a pointer to an empty ApiKey - `&models.ApiKey{}`).
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewApiKeyUpsert(app core.App, apiKey *models.ApiKey) *ApiKeyUpsert {
+	form := &ApiKeyUpsert{
+		app:    app,
+		dao:    app.Dao(),
+		apiKey: apiKey,
+	}
+
+	// load defaults
+	form.Name = apiKey.Name
+	form.Scopes = make(map[string]string, len(apiKey.Scopes))
+	for collectionId := range apiKey.Scopes {
+		form.Scopes[collectionId], _ = apiKey.Scope(collectionId)
+	}
+
+	return form
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *ApiKeyUpsert) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// PlainKey returns the plain api key generated
+// by the last successful form submission (if any).
+func (form *ApiKeyUpsert) PlainKey() string {
+	return form.plainKey
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+func (form *ApiKeyUpsert) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&form.Scopes, validation.By(form.checkScopes)),
+	)
+}
+
+func (form *ApiKeyUpsert) checkScopes(value any) error {
+	v, _ := value.(map[string]string)
+
+	errs := validation.Errors{}
+
+	for collectionNameOrId, scope := range v {
+		if _, err := form.dao.FindCollectionByNameOrId(collectionNameOrId); err != nil {
+			errs[collectionNameOrId] = validation.NewError("validation_invalid_collection", "Missing or invalid collection.")
+			continue
+		}
+
+		if len(scope) > 100 || !apiKeyScopeRegex.MatchString(scope) {
+			errs[collectionNameOrId] = validation.NewError("validation_invalid_scope", "Invalid api key scope.")
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// Submit validates the form and upserts the form api key model.
+//
+// A new key is generated for new api keys or when RegenerateKey is set,
+// and its plain value is accessible after that via [PlainKey()].
+//
+// You can optionally provide a list of InterceptorFunc to further
+// modify the form behavior before persisting it.
+func (form *ApiKeyUpsert) Submit(interceptors ...InterceptorFunc) error {
+	if err := form.Validate(); err != nil {
+		return err
+	}
+
+	// normalize the scope keys to collection ids
+	scopes := make(types.JsonMap, len(form.Scopes))
+	for collectionNameOrId, scope := range form.Scopes {
+		collection, err := form.dao.FindCollectionByNameOrId(collectionNameOrId)
+		if err != nil {
+			return err
+		}
+		scopes[collection.Id] = scope
+	}
+
+	form.apiKey.Name = form.Name
+	form.apiKey.Scopes = scopes
+
+	var plainKey string
+	if form.apiKey.IsNew() || form.RegenerateKey {
+		plainKey = form.apiKey.RefreshKey()
+	}
+
+	return runInterceptors(func() error {
+		if err := form.dao.SaveApiKey(form.apiKey); err != nil {
+			return err
+		}
+
+		form.plainKey = plainKey
+
+		return nil
+	}, interceptors...)
+}
@@ -0,0 +1,168 @@
+package forms_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestNewApiKeyUpsert(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	apiKey := &models.ApiKey{Name: "test", Scopes: types.JsonMap{"sz5l5z67tg7gku0": "read"}}
+
+	form := forms.NewApiKeyUpsert(app, apiKey)
+
+	// test defaults
+	if form.Name != apiKey.Name {
+		t.Errorf("Expected Name %q, got %q", apiKey.Name, form.Name)
+	}
+	if len(form.Scopes) != 1 || form.Scopes["sz5l5z67tg7gku0"] != "read" {
+		t.Errorf("Expected Scopes %v, got %v", apiKey.Scopes, form.Scopes)
+	}
+	if form.PlainKey() != "" {
+		t.Errorf("Expected empty PlainKey, got %q", form.PlainKey())
+	}
+}
+
+func TestApiKeyUpsertValidateAndSubmit(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	existing := &models.ApiKey{Name: "existing"}
+	existingKey := existing.RefreshKey()
+	if err := app.Dao().SaveApiKey(existing); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name                string
+		update              bool
+		jsonData            string
+		expectedErrors      []string
+		expectedNewKey      bool
+		expectedScopeLength int
+	}{
+		{
+			"create with empty data",
+			false,
+			`{}`,
+			[]string{"name"},
+			false,
+			0,
+		},
+		{
+			"create with invalid data",
+			false,
+			`{"name": "` + strings.Repeat("a", 101) + `", "scopes": {"missing": "read", "demo2": "a b"}}`,
+			[]string{"name", "scopes"},
+			false,
+			0,
+		},
+		{
+			"create success",
+			false,
+			`{"name": "test", "scopes": {"demo2": "read", "sz5l5z67tg7gku0": "write", "users": ""}}`,
+			[]string{},
+			true,
+			2,
+		},
+		{
+			"update without key regeneration",
+			true,
+			`{"name": "test_new", "scopes": {"demo2": "read"}}`,
+			[]string{},
+			false,
+			1,
+		},
+		{
+			"update with key regeneration",
+			true,
+			`{"name": "test_new", "regenerateKey": true}`,
+			[]string{},
+			true,
+			1,
+		},
+	}
+
+	for _, s := range scenarios {
+		apiKey := &models.ApiKey{}
+		if s.update {
+			apiKey, _ = app.Dao().FindApiKeyById(existing.Id)
+		}
+
+		form := forms.NewApiKeyUpsert(app, apiKey)
+
+		// load data
+		loadErr := json.Unmarshal([]byte(s.jsonData), form)
+		if loadErr != nil {
+			t.Errorf("[%s] Failed to load form data: %v", s.name, loadErr)
+			continue
+		}
+
+		interceptorCalls := 0
+
+		err := form.Submit(func(next forms.InterceptorNextFunc) forms.InterceptorNextFunc {
+			return func() error {
+				interceptorCalls++
+				return next()
+			}
+		})
+
+		// parse errors
+		errs, ok := err.(validation.Errors)
+		if !ok && err != nil {
+			t.Errorf("[%s] Failed to parse errors %v", s.name, err)
+			continue
+		}
+
+		// check errors
+		if len(errs) > len(s.expectedErrors) {
+			t.Errorf("[%s] Expected error keys %v, got %v", s.name, s.expectedErrors, errs)
+		}
+		for _, k := range s.expectedErrors {
+			if _, ok := errs[k]; !ok {
+				t.Errorf("[%s] Missing expected error key %q in %v", s.name, k, errs)
+			}
+		}
+
+		if len(s.expectedErrors) > 0 {
+			if interceptorCalls != 0 {
+				t.Errorf("[%s] Expected the interceptor to not be called", s.name)
+			}
+			continue
+		}
+
+		if interceptorCalls != 1 {
+			t.Errorf("[%s] Expected the interceptor to be called once, got %d", s.name, interceptorCalls)
+		}
+
+		if len(apiKey.Scopes) != s.expectedScopeLength {
+			t.Errorf("[%s] Expected %d scopes, got %v", s.name, s.expectedScopeLength, apiKey.Scopes)
+		}
+		for collectionId := range apiKey.Scopes {
+			if collectionId == "demo2" || collectionId == "users" {
+				t.Errorf("[%s] Expected the scope keys to be normalized to collection ids, got %q", s.name, collectionId)
+			}
+		}
+
+		if s.expectedNewKey {
+			if form.PlainKey() == "" || apiKey.KeyHash != models.HashApiKey(form.PlainKey()) {
+				t.Errorf("[%s] Expected a new plain key matching the stored hash, got %q", s.name, form.PlainKey())
+			}
+		} else if form.PlainKey() != "" {
+			t.Errorf("[%s] Expected no new plain key, got %q", s.name, form.PlainKey())
+		}
+
+		if s.update && !s.expectedNewKey && apiKey.KeyHash != models.HashApiKey(existingKey) {
+			t.Errorf("[%s] Expected the existing key to be preserved", s.name)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package forms
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/auth"
+)
+
+// AppleClientSecretCreate is a form that generates the client secret
+// JWT required by the Sign in with Apple OAuth2 provider.
+type AppleClientSecretCreate struct {
+	app core.App
+
+	// ClientId is the Apple Services ID.
+	ClientId string `form:"clientId" json:"clientId"`
+
+	// TeamId is the 10-character Apple Developer account team id.
+	TeamId string `form:"teamId" json:"teamId"`
+
+	// KeyId is the 10-character Sign in with Apple private key id.
+	KeyId string `form:"keyId" json:"keyId"`
+
+	// PrivateKey is the Sign in with Apple PEM encoded private key (the .p8 file content).
+	PrivateKey string `form:"privateKey" json:"privateKey"`
+
+	// Duration specifies how long the generated JWT should be
+	// considered valid (in seconds, max ~6 months).
+	Duration int `form:"duration" json:"duration"`
+}
+
+// NewAppleClientSecretCreate creates a new [AppleClientSecretCreate] form with
+// initializer fields based on the provided [core.App] instance.
+func NewAppleClientSecretCreate(app core.App) *AppleClientSecretCreate {
+	return &AppleClientSecretCreate{app: app}
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+func (form *AppleClientSecretCreate) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.ClientId, validation.Required),
+		validation.Field(&form.TeamId, validation.Required, validation.Length(10, 10)),
+		validation.Field(&form.KeyId, validation.Required, validation.Length(10, 10)),
+		validation.Field(&form.PrivateKey, validation.Required, validation.By(checkApplePrivateKey)),
+		validation.Field(&form.Duration, validation.Required, validation.Min(1), validation.Max(15777000)),
+	)
+}
+
+func checkApplePrivateKey(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	if _, err := jwt.ParseECPrivateKeyFromPEM([]byte(v)); err != nil {
+		return validation.NewError("validation_invalid_private_key", "Invalid PEM encoded EC private key.")
+	}
+
+	return nil
+}
+
+// Submit validates the form and returns a new Apple client secret JWT.
+func (form *AppleClientSecretCreate) Submit() (string, error) {
+	if err := form.Validate(); err != nil {
+		return "", err
+	}
+
+	return auth.NewAppleClientSecret(
+		form.ClientId,
+		form.TeamId,
+		form.KeyId,
+		form.PrivateKey,
+		time.Duration(form.Duration)*time.Second,
+	)
+}
@@ -0,0 +1,94 @@
+package forms_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestAppleClientSecretCreateSubmit(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encodedKey, _ := json.Marshal(string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})))
+
+	scenarios := []struct {
+		name           string
+		data           string
+		expectedErrors []string
+	}{
+		{
+			"empty data",
+			`{}`,
+			[]string{"clientId", "teamId", "keyId", "privateKey", "duration"},
+		},
+		{
+			"invalid data",
+			`{"clientId":"test","teamId":"123","keyId":"123","privateKey":"invalid","duration":15777001}`,
+			[]string{"teamId", "keyId", "privateKey", "duration"},
+		},
+		{
+			"valid data",
+			`{"clientId":"test","teamId":"1234567890","keyId":"1234567891","privateKey":` + string(encodedKey) + `,"duration":3600}`,
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		form := forms.NewAppleClientSecretCreate(app)
+
+		if err := json.Unmarshal([]byte(s.data), form); err != nil {
+			t.Errorf("[%s] Failed to load form data: %v", s.name, err)
+			continue
+		}
+
+		secret, err := form.Submit()
+
+		errs, ok := err.(validation.Errors)
+		if !ok && err != nil {
+			t.Errorf("[%s] Failed to parse errors %v", s.name, err)
+			continue
+		}
+
+		if len(errs) != len(s.expectedErrors) {
+			t.Errorf("[%s] Expected error keys %v, got %v", s.name, s.expectedErrors, errs)
+		}
+		for _, k := range s.expectedErrors {
+			if _, ok := errs[k]; !ok {
+				t.Errorf("[%s] Missing expected error key %q in %v", s.name, k, errs)
+			}
+		}
+
+		if len(s.expectedErrors) > 0 {
+			continue
+		}
+
+		claims := jwt.MapClaims{}
+		if _, err := jwt.ParseWithClaims(secret, claims, func(t *jwt.Token) (any, error) {
+			return &key.PublicKey, nil
+		}); err != nil {
+			t.Errorf("[%s] Failed to verify the generated secret: %v", s.name, err)
+		}
+
+		if claims["iss"] != "1234567890" || claims["sub"] != "test" {
+			t.Errorf("[%s] Unexpected secret claims %v", s.name, claims)
+		}
+	}
+}
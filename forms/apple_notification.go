@@ -0,0 +1,121 @@
+package forms
+
+import (
+	"encoding/json"
+	"errors"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/auth"
+)
+
+// Apple server-to-server notification event types that revoke the app access.
+const (
+	appleEventConsentRevoked = "consent-revoked"
+	appleEventAccountDelete  = "account-delete"
+)
+
+// AppleNotification is a Sign in with Apple server-to-server notification form.
+//
+// The "consent-revoked" and "account-delete" events disable the
+// linked auth record by unlinking its Apple account and invalidating
+// all of its issued auth tokens and sessions.
+type AppleNotification struct {
+	app core.App
+	dao *daos.Dao
+
+	// Payload is the Apple signed notification JWT.
+	Payload string `form:"payload" json:"payload"`
+}
+
+// NewAppleNotification creates a new [AppleNotification] form
+// initialized with from the provided [core.App] instance.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewAppleNotification(app core.App) *AppleNotification {
+	return &AppleNotification{
+		app: app,
+		dao: app.Dao(),
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *AppleNotification) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+func (form *AppleNotification) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Payload, validation.Required),
+	)
+}
+
+// Submit validates and verifies the notification payload and applies its event.
+//
+// On success returns the disabled auth record or nil if the
+// event doesn't revoke the app access or there is no linked auth record.
+func (form *AppleNotification) Submit() (*models.Record, error) {
+	if err := form.Validate(); err != nil {
+		return nil, err
+	}
+
+	provider := auth.NewAppleProvider()
+	if err := form.app.Settings().AppleAuth.SetupProvider(provider); err != nil {
+		return nil, err
+	}
+
+	claims, err := provider.VerifyToken(form.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	// the events claim is a JSON encoded string
+	rawEvents, _ := claims["events"].(string)
+
+	event := struct {
+		Type string `json:"type"`
+		Sub  string `json:"sub"`
+	}{}
+	if err := json.Unmarshal([]byte(rawEvents), &event); err != nil {
+		return nil, errors.New("Invalid Apple notification events claim.")
+	}
+
+	if event.Type != appleEventConsentRevoked && event.Type != appleEventAccountDelete {
+		return nil, nil // nothing to revoke
+	}
+
+	rel, err := form.dao.FindExternalAuthByProvider(auth.NameApple, event.Sub)
+	if err != nil {
+		return nil, nil // no linked auth record
+	}
+
+	authRecord, err := form.dao.FindRecordById(rel.CollectionId, rel.RecordId)
+	if err != nil {
+		return nil, err
+	}
+
+	txErr := form.dao.RunInTransaction(func(txDao *daos.Dao) error {
+		if err := txDao.DeleteExternalAuth(rel); err != nil {
+			return err
+		}
+
+		// invalidate the issued auth tokens
+		if err := authRecord.RefreshTokenKey(); err != nil {
+			return err
+		}
+		if err := txDao.SaveRecord(authRecord); err != nil {
+			return err
+		}
+
+		return txDao.DeleteAuthRecordSessions(authRecord)
+	})
+	if txErr != nil {
+		return nil, txErr
+	}
+
+	return authRecord, nil
+}
@@ -0,0 +1,124 @@
+package forms_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/auth"
+)
+
+func TestAppleNotificationSubmit(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"keys":[{"kty":"RSA","kid":"test","n":"` + n + `","e":"` + e + `"}]}`))
+	}))
+	defer server.Close()
+
+	payload := func(eventType string, sub string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":    auth.AppleIssuer,
+			"aud":    "test_client",
+			"iat":    time.Now().Unix(),
+			"exp":    time.Now().Add(time.Hour).Unix(),
+			"events": `{"type":"` + eventType + `","sub":"` + sub + `","event_time":1}`,
+		})
+		token.Header["kid"] = "test"
+
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return signed
+	}
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().AppleAuth.Enabled = true
+	app.Settings().AppleAuth.ClientId = "test_client"
+	app.Settings().AppleAuth.ClientSecret = "test_secret"
+	app.Settings().AppleAuth.UserApiUrl = server.URL
+
+	authRecord, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldTokenKey := authRecord.TokenKey()
+
+	rel := &models.ExternalAuth{
+		CollectionId: authRecord.Collection().Id,
+		RecordId:     authRecord.Id,
+		Provider:     auth.NameApple,
+		ProviderId:   "apple_123",
+	}
+	if err := app.Dao().SaveExternalAuth(rel); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name           string
+		payload        string
+		expectError    bool
+		expectedRecord string
+	}{
+		{"empty payload", "", true, ""},
+		{"invalid payload", "invalid", true, ""},
+		{"not revoking event", payload("email-disabled", "apple_123"), false, ""},
+		{"missing linked auth record", payload("consent-revoked", "missing"), false, ""},
+		{"consent revoked", payload("consent-revoked", "apple_123"), false, authRecord.Id},
+		{"already revoked", payload("account-delete", "apple_123"), false, ""},
+	}
+
+	for _, s := range scenarios {
+		form := forms.NewAppleNotification(app)
+		form.Payload = s.payload
+
+		record, err := form.Submit()
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		var recordId string
+		if record != nil {
+			recordId = record.Id
+		}
+		if recordId != s.expectedRecord {
+			t.Errorf("[%s] Expected disabled record %q, got %q", s.name, s.expectedRecord, recordId)
+		}
+	}
+
+	if _, err := app.Dao().FindExternalAuthByProvider(auth.NameApple, "apple_123"); err == nil {
+		t.Fatal("Expected the Apple external auth to be deleted")
+	}
+
+	refreshed, _ := app.Dao().FindRecordById(authRecord.Collection().Id, authRecord.Id)
+	if refreshed.TokenKey() == oldTokenKey {
+		t.Fatal("Expected the auth record token key to be refreshed")
+	}
+
+	// the other external auths shouldn't be affected
+	if auths, _ := app.Dao().FindAllExternalAuthsByRecord(refreshed); len(auths) != 2 {
+		t.Fatalf("Expected 2 remaining external auths, got %d", len(auths))
+	}
+}
@@ -0,0 +1,262 @@
+package forms
+
+import (
+	"encoding/json"
+	"fmt"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+)
+
+// cloneRecordsBatchSize is the max number of source records
+// that are loaded in memory at once while cloning.
+const cloneRecordsBatchSize = 500
+
+// CollectionClone is a form model to duplicate an existing collection
+// (schema, rules, macros and options) under a new name,
+// optionally together with its records and files.
+type CollectionClone struct {
+	app        core.App
+	dao        *daos.Dao
+	collection *models.Collection
+
+	Name string `form:"name" json:"name"`
+
+	// CopyRecords copies the (non soft deleted) collection records
+	// preserving their ids (not supported for auth collections).
+	CopyRecords bool `form:"copyRecords" json:"copyRecords"`
+
+	// CopyFiles copies the files of the cloned records (requires CopyRecords).
+	CopyFiles bool `form:"copyFiles" json:"copyFiles"`
+}
+
+// NewCollectionClone creates a new [CollectionClone] form
+// initialized with the provided [core.App] and source [models.Collection].
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewCollectionClone(app core.App, collection *models.Collection) *CollectionClone {
+	return &CollectionClone{
+		app:        app,
+		dao:        app.Dao(),
+		collection: collection,
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *CollectionClone) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+func (form *CollectionClone) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Name, validation.Required),
+		validation.Field(&form.CopyRecords, validation.By(form.checkCopyRecords)),
+		validation.Field(&form.CopyFiles, validation.By(form.checkCopyFiles)),
+	)
+}
+
+func (form *CollectionClone) checkCopyFiles(value any) error {
+	v, _ := value.(bool)
+	if v && !form.CopyRecords {
+		return validation.NewError("validation_clone_files_without_records", "The files could be copied only together with the records.")
+	}
+
+	return nil
+}
+
+func (form *CollectionClone) checkCopyRecords(value any) error {
+	v, _ := value.(bool)
+	if !v {
+		return nil
+	}
+
+	if form.collection.IsAuth() {
+		return validation.NewError(
+			"validation_clone_auth_records",
+			"The auth records cannot be cloned because their ids, emails and usernames must be unique.",
+		)
+	}
+
+	return nil
+}
+
+// Submit validates the form and creates the collection clone
+// (and its records and files if requested) in a single transaction.
+//
+// The clone is created with the same validations as the regular
+// collection create and its self-referencing relation fields are
+// updated to point to the clone.
+//
+// You can optionally provide a list of InterceptorFunc to further
+// modify the form behavior before persisting it.
+func (form *CollectionClone) Submit(interceptors ...InterceptorFunc) (*models.Collection, error) {
+	if err := form.Validate(); err != nil {
+		return nil, err
+	}
+
+	// deep copy the source collection to prevent sharing
+	// the schema, macros and options with the clone
+	raw, err := json.Marshal(form.collection)
+	if err != nil {
+		return nil, err
+	}
+	source := &models.Collection{}
+	if err := json.Unmarshal(raw, source); err != nil {
+		return nil, err
+	}
+	if err := source.RestoreLdapBindPassword(form.collection); err != nil {
+		return nil, err
+	}
+
+	clone := &models.Collection{}
+
+	submitErr := runInterceptors(func() error {
+		txErr := form.dao.RunInTransaction(func(txDao *daos.Dao) error {
+			upsertForm := NewCollectionUpsert(form.app, clone)
+			upsertForm.SetDao(txDao)
+			upsertForm.Name = form.Name
+			upsertForm.Type = source.Type
+			upsertForm.Schema = source.Schema
+			upsertForm.ListRule = source.ListRule
+			upsertForm.ViewRule = source.ViewRule
+			upsertForm.CreateRule = source.CreateRule
+			upsertForm.UpdateRule = source.UpdateRule
+			upsertForm.DeleteRule = source.DeleteRule
+			upsertForm.SubscribeRule = source.SubscribeRule
+			upsertForm.RuleMacros = source.RuleMacros
+			upsertForm.Options = source.Options
+
+			if err := upsertForm.Submit(); err != nil {
+				return err
+			}
+
+			if form.remapSelfRelations(clone) {
+				if err := txDao.SaveCollection(clone); err != nil {
+					return err
+				}
+			}
+
+			if form.CopyRecords {
+				return form.copyRecords(txDao, clone)
+			}
+
+			return nil
+		})
+
+		// cleanup the already copied files
+		if txErr != nil && form.CopyFiles && clone.Id != "" {
+			if fs, err := form.app.NewFilesystem(); err == nil {
+				fs.DeletePrefix(clone.BaseFilesPath() + "/")
+				fs.Close()
+			}
+		}
+
+		return txErr
+	}, interceptors...)
+
+	if submitErr != nil {
+		return nil, submitErr
+	}
+
+	return clone, nil
+}
+
+// remapSelfRelations updates the clone relation fields that reference
+// the source collection to reference the clone itself.
+//
+// Returns whether any of the fields was changed.
+func (form *CollectionClone) remapSelfRelations(clone *models.Collection) bool {
+	var changed bool
+
+	for _, field := range clone.Schema.Fields() {
+		if field.Type != schema.FieldTypeRelation {
+			continue
+		}
+
+		field.InitOptions()
+		options, _ := field.Options.(*schema.RelationOptions)
+		if options != nil && options.CollectionId == form.collection.Id {
+			options.CollectionId = clone.Id
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+func (form *CollectionClone) copyRecords(txDao *daos.Dao, clone *models.Collection) error {
+	var fs *filesystem.System
+	if form.CopyFiles {
+		var err error
+		if fs, err = form.app.NewFilesystem(); err != nil {
+			return err
+		}
+		defer fs.Close()
+	}
+
+	var lastId string
+
+	for {
+		rows := []dbx.NullStringMap{}
+
+		err := txDao.RecordQuery(form.collection).
+			AndWhere(dbx.NewExp("[[id]] > {:lastId}", dbx.Params{"lastId": lastId})).
+			OrderBy("id ASC").
+			Limit(cloneRecordsBatchSize).
+			All(&rows)
+		if err != nil {
+			return err
+		}
+
+		if err := txDao.DecryptRecordRows(form.collection, rows...); err != nil {
+			return err
+		}
+
+		records := models.NewRecordsFromNullStringMaps(form.collection, rows)
+
+		for _, record := range records {
+			cloneRecord := models.NewRecord(clone)
+			cloneRecord.Load(record.ColumnValueMap())
+
+			if err := txDao.SaveRecord(cloneRecord); err != nil {
+				return fmt.Errorf("Failed to clone record %q: %w", record.Id, err)
+			}
+
+			if fs != nil {
+				if err := copyRecordFiles(fs, record, cloneRecord); err != nil {
+					return err
+				}
+			}
+
+			lastId = record.Id
+		}
+
+		if len(records) < cloneRecordsBatchSize {
+			return nil
+		}
+	}
+}
+
+// copyRecordFiles copies the files of the source record to the clone record files dir.
+func copyRecordFiles(fs *filesystem.System, record *models.Record, cloneRecord *models.Record) error {
+	for _, field := range record.Collection().Schema.Fields() {
+		if field.Type != schema.FieldTypeFile {
+			continue
+		}
+
+		for _, name := range record.GetStringSlice(field.Name) {
+			if err := fs.Copy(record.BaseFilesPath()+"/"+name, cloneRecord.BaseFilesPath()+"/"+name); err != nil {
+				return fmt.Errorf("Failed to clone file %q of record %q: %w", name, record.Id, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package forms_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestCollectionCloneValidate(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		collection     string
+		jsonData       string
+		expectedErrors []string
+	}{
+		{"demo1", `{}`, []string{"name"}},
+		{"demo1", `{"name":"demo1_clone","copyFiles":true}`, []string{"copyFiles"}},
+		{"users", `{"name":"users_clone","copyRecords":true}`, []string{"copyRecords"}},
+		{"users", `{"name":"users_clone"}`, []string{}},
+		{"demo1", `{"name":"demo1_clone","copyRecords":true,"copyFiles":true}`, []string{}},
+	}
+
+	for i, s := range scenarios {
+		collection, err := app.Dao().FindCollectionByNameOrId(s.collection)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		form := forms.NewCollectionClone(app, collection)
+
+		if err := json.Unmarshal([]byte(s.jsonData), form); err != nil {
+			t.Errorf("(%d) Failed to load form data: %v", i, err)
+			continue
+		}
+
+		result := form.Validate()
+
+		// parse errors
+		errs, ok := result.(validation.Errors)
+		if !ok && result != nil {
+			t.Errorf("(%d) Failed to parse errors %v", i, result)
+			continue
+		}
+
+		// check errors
+		if len(errs) > len(s.expectedErrors) {
+			t.Errorf("(%d) Expected error keys %v, got %v", i, s.expectedErrors, errs)
+		}
+		for _, k := range s.expectedErrors {
+			if _, ok := errs[k]; !ok {
+				t.Errorf("(%d) Missing expected error key %q in %v", i, k, errs)
+			}
+		}
+	}
+}
+
+func TestCollectionCloneSubmitExistingName(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewCollectionClone(app, collection)
+	form.Name = "demo2"
+
+	_, submitErr := form.Submit()
+
+	errs, ok := submitErr.(validation.Errors)
+	if !ok {
+		t.Fatalf("Expected validation errors, got %v", submitErr)
+	}
+	if _, ok := errs["name"]; !ok {
+		t.Fatalf("Expected name error, got %v", errs)
+	}
+}
+
+func TestCollectionCloneSubmitSchemaOnly(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo4")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewCollectionClone(app, collection)
+	form.Name = "demo4_clone"
+
+	clone, err := form.Submit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if clone.Id == "" || clone.Id == collection.Id {
+		t.Fatalf("Expected new clone id, got %q", clone.Id)
+	}
+
+	if clone.Name != form.Name {
+		t.Fatalf("Expected clone name %q, got %q", form.Name, clone.Name)
+	}
+
+	if clone.ListRule == nil || collection.ListRule == nil || *clone.ListRule != *collection.ListRule {
+		t.Fatalf("Expected the list rule to be copied, got %v", clone.ListRule)
+	}
+
+	for _, name := range []string{"self_rel_one", "self_rel_many"} {
+		field := clone.Schema.GetFieldByName(name)
+		if field == nil {
+			t.Fatalf("Missing clone field %q", name)
+		}
+		field.InitOptions()
+		if options, _ := field.Options.(*schema.RelationOptions); options == nil || options.CollectionId != clone.Id {
+			t.Fatalf("Expected %q to reference the clone, got %v", name, field.Options)
+		}
+	}
+
+	// the source collection should be unchanged
+	source, err := app.Dao().FindCollectionByNameOrId("demo4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	field := source.Schema.GetFieldByName("self_rel_one")
+	field.InitOptions()
+	if options, _ := field.Options.(*schema.RelationOptions); options == nil || options.CollectionId != source.Id {
+		t.Fatalf("Expected the source self_rel_one to be unchanged, got %v", field.Options)
+	}
+
+	records, err := app.Dao().FindRecordsByExpr(clone.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 0 {
+		t.Fatalf("Expected no clone records, got %d", len(records))
+	}
+}
+
+func TestCollectionCloneSubmitWithRecordsAndFiles(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewCollectionClone(app, collection)
+	form.Name = "demo1_clone"
+	form.CopyRecords = true
+	form.CopyFiles = true
+
+	interceptorCalls := 0
+	clone, err := form.Submit(func(next forms.InterceptorNextFunc) forms.InterceptorNextFunc {
+		return func() error {
+			interceptorCalls++
+			return next()
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if interceptorCalls != 1 {
+		t.Fatalf("Expected the interceptor to be called once, got %d", interceptorCalls)
+	}
+
+	sourceRecords, err := app.Dao().FindRecordsByExpr(collection.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cloneRecords, err := app.Dao().FindRecordsByExpr(clone.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cloneRecords) != len(sourceRecords) || len(cloneRecords) != 3 {
+		t.Fatalf("Expected %d clone records, got %d", len(sourceRecords), len(cloneRecords))
+	}
+
+	record, err := app.Dao().FindRecordById(clone.Id, "al1h9ijdeojtsjy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := record.GetString("rel_one"); v != "84nmscqy84lsi1t" {
+		t.Fatalf("Expected rel_one 84nmscqy84lsi1t, got %q", v)
+	}
+
+	fs, err := app.NewFilesystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	for _, name := range []string{"test_d61b33QdDU.txt", "300_WlbFWSGmW9.png", "logo_vcfJJG5TAh.svg"} {
+		if exists, _ := fs.Exists(clone.Id + "/84nmscqy84lsi1t/" + name); !exists {
+			t.Errorf("Missing cloned file %q", name)
+		}
+		if exists, _ := fs.Exists(collection.Id + "/84nmscqy84lsi1t/" + name); !exists {
+			t.Errorf("Missing source file %q", name)
+		}
+	}
+}
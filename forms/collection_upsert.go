@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
@@ -19,23 +20,31 @@ import (
 
 var collectionNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_]*$`)
 
+var ruleMacroNameRegex = regexp.MustCompile(`^\w+$`)
+
 // CollectionUpsert is a [models.Collection] upsert (create/update) form.
 type CollectionUpsert struct {
 	app        core.App
 	dao        *daos.Dao
 	collection *models.Collection
 
-	Id         string        `form:"id" json:"id"`
-	Type       string        `form:"type" json:"type"`
-	Name       string        `form:"name" json:"name"`
-	System     bool          `form:"system" json:"system"`
-	Schema     schema.Schema `form:"schema" json:"schema"`
-	ListRule   *string       `form:"listRule" json:"listRule"`
-	ViewRule   *string       `form:"viewRule" json:"viewRule"`
-	CreateRule *string       `form:"createRule" json:"createRule"`
-	UpdateRule *string       `form:"updateRule" json:"updateRule"`
-	DeleteRule *string       `form:"deleteRule" json:"deleteRule"`
-	Options    types.JsonMap `form:"options" json:"options"`
+	Id            string        `form:"id" json:"id"`
+	Type          string        `form:"type" json:"type"`
+	Name          string        `form:"name" json:"name"`
+	System        bool          `form:"system" json:"system"`
+	Schema        schema.Schema `form:"schema" json:"schema"`
+	ListRule      *string       `form:"listRule" json:"listRule"`
+	ViewRule      *string       `form:"viewRule" json:"viewRule"`
+	CreateRule    *string       `form:"createRule" json:"createRule"`
+	UpdateRule    *string       `form:"updateRule" json:"updateRule"`
+	DeleteRule    *string       `form:"deleteRule" json:"deleteRule"`
+	SubscribeRule *string       `form:"subscribeRule" json:"subscribeRule"`
+	RuleMacros    types.JsonMap `form:"ruleMacros" json:"ruleMacros"`
+	Options       types.JsonMap `form:"options" json:"options"`
+
+	// Version is the expected current version of the updated collection
+	// (defaults to the version of the loaded collection model).
+	Version int `form:"version" json:"version"`
 }
 
 // NewCollectionUpsert creates a new [CollectionUpsert] form with initializer
@@ -61,12 +70,21 @@ func NewCollectionUpsert(app core.App, collection *models.Collection) *Collectio
 	form.CreateRule = form.collection.CreateRule
 	form.UpdateRule = form.collection.UpdateRule
 	form.DeleteRule = form.collection.DeleteRule
-	form.Options = form.collection.Options
+	form.SubscribeRule = form.collection.SubscribeRule
+	form.RuleMacros = form.collection.RuleMacros
+	form.Version = form.collection.Version
 
 	if form.Type == "" {
 		form.Type = models.CollectionTypeBase
 	}
 
+	// copy the options to preserve the original collection state
+	// for the validations that compare the old and new options
+	form.Options = make(types.JsonMap, len(form.collection.Options))
+	for k, v := range form.collection.Options {
+		form.Options[k] = v
+	}
+
 	clone, _ := form.collection.Schema.Clone()
 	if clone != nil {
 		form.Schema = *clone
@@ -111,6 +129,7 @@ func (form *CollectionUpsert) Validate() error {
 			validation.Length(1, 255),
 			validation.Match(collectionNameRegex),
 			validation.By(form.ensureNoSystemNameChange),
+			validation.By(form.ensureNoExternalNameChange),
 			validation.By(form.checkUniqueName),
 		),
 		// validates using the type's own validation rules + some collection's specific
@@ -119,7 +138,12 @@ func (form *CollectionUpsert) Validate() error {
 			validation.By(form.checkMinSchemaFields),
 			validation.By(form.ensureNoSystemFieldsChange),
 			validation.By(form.ensureNoFieldsTypeChange),
+			validation.By(form.ensureNoBigIntChange),
 			validation.By(form.ensureExistingRelationCollectionId),
+			validation.By(form.checkComputedFields),
+			validation.By(form.checkEncryptedFields),
+			validation.By(form.checkFieldValidationRules),
+			validation.By(form.checkTreeFields),
 			validation.When(
 				isAuth,
 				validation.By(form.ensureNoAuthFieldName),
@@ -130,6 +154,8 @@ func (form *CollectionUpsert) Validate() error {
 		validation.Field(&form.CreateRule, validation.By(form.checkRule)),
 		validation.Field(&form.UpdateRule, validation.By(form.checkRule)),
 		validation.Field(&form.DeleteRule, validation.By(form.checkRule)),
+		validation.Field(&form.SubscribeRule, validation.By(form.checkRule)),
+		validation.Field(&form.RuleMacros, validation.By(form.checkRuleMacros)),
 		validation.Field(&form.Options, validation.By(form.checkOptions)),
 	)
 }
@@ -148,7 +174,8 @@ func (form *CollectionUpsert) checkUniqueName(value any) error {
 	}
 
 	// ensure that there is no existing table name with the same name
-	if (form.collection.IsNew() || !strings.EqualFold(v, form.collection.Name)) && form.dao.HasTable(v) {
+	// (the external collections are expected to have one)
+	if !form.isExternal() && (form.collection.IsNew() || !strings.EqualFold(v, form.collection.Name)) && form.dao.HasTable(v) {
 		return validation.NewError("validation_collection_name_table_exists", "The collection name must be also unique table name.")
 	}
 
@@ -165,6 +192,17 @@ func (form *CollectionUpsert) ensureNoSystemNameChange(value any) error {
 	return nil
 }
 
+func (form *CollectionUpsert) ensureNoExternalNameChange(value any) error {
+	v, _ := value.(string)
+
+	// the external collection name is also the name of its existing table
+	if !form.collection.IsNew() && form.collection.IsExternal() && v != form.collection.Name {
+		return validation.NewError("validation_collection_external_name_change", "External collections cannot be renamed.")
+	}
+
+	return nil
+}
+
 func (form *CollectionUpsert) ensureNoSystemFlagChange(value any) error {
 	v, _ := value.(bool)
 
@@ -202,6 +240,32 @@ func (form *CollectionUpsert) ensureNoFieldsTypeChange(value any) error {
 	return nil
 }
 
+func (form *CollectionUpsert) ensureNoBigIntChange(value any) error {
+	v, _ := value.(schema.Schema)
+
+	for i, field := range v.Fields() {
+		oldField := form.collection.Schema.GetFieldById(field.Id)
+		if oldField == nil || oldField.Type != schema.FieldTypeNumber || field.Type != schema.FieldTypeNumber {
+			continue
+		}
+
+		oldField.InitOptions()
+		field.InitOptions()
+
+		oldOptions, _ := oldField.Options.(*schema.NumberOptions)
+		newOptions, _ := field.Options.(*schema.NumberOptions)
+
+		if oldOptions != nil && newOptions != nil && oldOptions.BigInt != newOptions.BigInt {
+			return validation.Errors{fmt.Sprint(i): validation.NewError(
+				"validation_field_big_int_change",
+				"The number field big integer option cannot be changed.",
+			)}
+		}
+	}
+
+	return nil
+}
+
 func (form *CollectionUpsert) ensureExistingRelationCollectionId(value any) error {
 	v, _ := value.(schema.Schema)
 
@@ -226,6 +290,148 @@ func (form *CollectionUpsert) ensureExistingRelationCollectionId(value any) erro
 	return nil
 }
 
+func (form *CollectionUpsert) checkComputedFields(value any) error {
+	v, _ := value.(schema.Schema)
+
+	dummy := models.Collection{Schema: v}
+
+	for i, field := range v.Fields() {
+		if field.Type != schema.FieldTypeComputed {
+			continue
+		}
+
+		options, _ := field.Options.(*schema.ComputedOptions)
+		if options == nil || options.Expression == "" {
+			continue // validated by the field options
+		}
+
+		if _, err := dummy.ComputedFieldExpr(field, "dummy"); err != nil {
+			return validation.Errors{fmt.Sprint(i): validation.NewError(
+				"validation_field_invalid_computed_expression",
+				"The computed expression must reference only existing non-computed fields.",
+			)}
+		}
+	}
+
+	return nil
+}
+
+func (form *CollectionUpsert) checkFieldValidationRules(value any) error {
+	v, _ := value.(schema.Schema)
+
+	dummy := *form.collection
+	dummy.Type = form.Type
+	dummy.Schema = v
+	dummy.System = form.System
+	dummy.Options = form.Options
+	dummy.RuleMacros = form.RuleMacros
+
+	errs := validation.Errors{}
+	for i, field := range v.Fields() {
+		rules := map[string]string{
+			"validation": field.Validation,
+			"readRule":   field.ReadRule,
+			"writeRule":  field.WriteRule,
+		}
+
+		fieldErrs := validation.Errors{}
+		for key, rule := range rules {
+			if rule == "" {
+				continue
+			}
+
+			r := resolvers.NewRecordFieldResolver(form.dao, &dummy, nil, true)
+
+			if _, err := search.FilterData(rule).BuildExpr(r); err != nil {
+				fieldErrs[key] = validation.NewError(
+					"validation_invalid_rule",
+					"Invalid field rule.",
+				)
+			}
+		}
+
+		if len(fieldErrs) > 0 {
+			errs[fmt.Sprint(i)] = fieldErrs
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// checkTreeFields checks whether the relation fields with enabled
+// tree option are valid single self-relations.
+func (form *CollectionUpsert) checkTreeFields(value any) error {
+	v, _ := value.(schema.Schema)
+
+	var hasTree bool
+
+	for i, field := range v.Fields() {
+		if field.Type != schema.FieldTypeRelation {
+			continue
+		}
+
+		options, _ := field.Options.(*schema.RelationOptions)
+		if options == nil || !options.Tree {
+			continue
+		}
+
+		if form.collection.IsNew() || options.CollectionId != form.collection.Id {
+			return validation.Errors{fmt.Sprint(i): validation.NewError(
+				"validation_invalid_tree_relation",
+				"The tree option is allowed only for self-relation fields of existing collections.",
+			)}
+		}
+
+		if options.MaxSelect == nil || *options.MaxSelect != 1 {
+			return validation.Errors{fmt.Sprint(i): validation.NewError(
+				"validation_invalid_tree_relation",
+				"The tree relation field must be a single relation (maxSelect 1).",
+			)}
+		}
+
+		if hasTree {
+			return validation.Errors{fmt.Sprint(i): validation.NewError(
+				"validation_multiple_tree_relations",
+				"The collection can have only one tree relation field.",
+			)}
+		}
+
+		if v.GetFieldByName(schema.FieldNamePath) != nil {
+			return validation.Errors{fmt.Sprint(i): validation.NewError(
+				"validation_tree_path_field_conflict",
+				fmt.Sprintf("The tree relation requires the collection to not have a %q schema field.", schema.FieldNamePath),
+			)}
+		}
+
+		hasTree = true
+	}
+
+	return nil
+}
+
+func (form *CollectionUpsert) checkEncryptedFields(value any) error {
+	v, _ := value.(schema.Schema)
+
+	if len(form.dao.EncryptionKey()) == 32 {
+		return nil
+	}
+
+	for i, field := range v.Fields() {
+		if field.Type == schema.FieldTypeEncrypted {
+			return validation.Errors{fmt.Sprint(i): validation.NewError(
+				"validation_missing_encryption_key",
+				"The encrypted fields require a valid 32 characters app encryption key.",
+			)}
+		}
+	}
+
+	return nil
+}
+
 func (form *CollectionUpsert) ensureNoAuthFieldName(value any) error {
 	v, _ := value.(schema.Schema)
 
@@ -298,6 +504,7 @@ func (form *CollectionUpsert) checkRule(value any) error {
 	dummy.Schema = form.Schema
 	dummy.System = form.System
 	dummy.Options = form.Options
+	dummy.RuleMacros = form.RuleMacros
 
 	r := resolvers.NewRecordFieldResolver(form.dao, &dummy, nil, true)
 
@@ -309,15 +516,55 @@ func (form *CollectionUpsert) checkRule(value any) error {
 	return nil
 }
 
-func (form *CollectionUpsert) checkOptions(value any) error {
+func (form *CollectionUpsert) checkRuleMacros(value any) error {
 	v, _ := value.(types.JsonMap)
+	if len(v) == 0 {
+		return nil // nothing to check
+	}
 
-	if form.Type == models.CollectionTypeAuth {
-		raw, err := v.MarshalJSON()
-		if err != nil {
-			return validation.NewError("validation_invalid_options", "Invalid options.")
+	dummy := *form.collection
+	dummy.Type = form.Type
+	dummy.Schema = form.Schema
+	dummy.System = form.System
+	dummy.Options = form.Options
+	dummy.RuleMacros = v
+
+	errs := validation.Errors{}
+	for name, rule := range v {
+		if !ruleMacroNameRegex.MatchString(name) {
+			errs[name] = validation.NewError("validation_invalid_macro_name", "Invalid macro name.")
+			continue
+		}
+
+		if str, ok := rule.(string); !ok || strings.TrimSpace(str) == "" {
+			errs[name] = validation.NewError("validation_invalid_macro_rule", "The macro rule must be a non-empty string.")
+			continue
+		}
+
+		// the macro is checked via a reference so that the
+		// nested, unknown and circular macros are also detected
+		r := resolvers.NewRecordFieldResolver(form.dao, &dummy, nil, true)
+		if _, err := search.FilterData("@macro." + name).BuildExpr(r); err != nil {
+			errs[name] = validation.NewError("validation_invalid_macro_rule", "Invalid macro rule.")
 		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
 
+	return nil
+}
+
+func (form *CollectionUpsert) checkOptions(value any) error {
+	v, _ := value.(types.JsonMap)
+
+	raw, err := v.MarshalJSON()
+	if err != nil {
+		return validation.NewError("validation_invalid_options", "Invalid options.")
+	}
+
+	if form.Type == models.CollectionTypeAuth {
 		options := models.CollectionAuthOptions{}
 		if err := json.Unmarshal(raw, &options); err != nil {
 			return validation.NewError("validation_invalid_options", "Invalid options.")
@@ -332,6 +579,482 @@ func (form *CollectionUpsert) checkOptions(value any) error {
 		if err := form.checkRule(options.ManageRule); err != nil {
 			return validation.Errors{"manageRule": err}
 		}
+
+		if err := form.checkPhoneAuthOption(options.AllowPhoneAuth); err != nil {
+			return validation.Errors{"allowPhoneAuth": err}
+		}
+
+		if err := form.checkModerationOptions(options.Moderation); err != nil {
+			return validation.Errors{"moderation": err}
+		}
+
+		if err := form.checkBotProtectionOptions(options.BotProtection); err != nil {
+			return validation.Errors{"botProtection": err}
+		}
+
+		if err := form.checkEncryptionOptions(options.Encryption); err != nil {
+			return validation.Errors{"encryption": err}
+		}
+
+		if err := form.checkSoftDeleteOptions(options.SoftDelete); err != nil {
+			return validation.Errors{"softDelete": err}
+		}
+
+		if err := form.checkVersioningOptions(options.Versioning); err != nil {
+			return validation.Errors{"versioning": err}
+		}
+
+		if err := form.checkExternalIdOptions(options.ExternalId); err != nil {
+			return validation.Errors{"externalId": err}
+		}
+
+		if err := form.checkHistoryOptions(options.History, options.Encryption); err != nil {
+			return validation.Errors{"history": err}
+		}
+
+		if err := form.checkSamlOptions(options.Saml); err != nil {
+			return validation.Errors{"saml": err}
+		}
+
+		if err := form.checkFieldsMapping(options.Ldap.FieldsMapping); err != nil {
+			return validation.Errors{"ldap": err}
+		}
+
+		if err := form.checkUniqueConstraints(options.UniqueConstraints, options.Encryption); err != nil {
+			return validation.Errors{"uniqueConstraints": err}
+		}
+	} else {
+		options := models.CollectionBaseOptions{}
+		if err := json.Unmarshal(raw, &options); err != nil {
+			return validation.NewError("validation_invalid_options", "Invalid options.")
+		}
+
+		if err := options.Validate(); err != nil {
+			return err
+		}
+
+		if err := form.checkDatabaseOption(options.Database); err != nil {
+			return validation.Errors{"database": err}
+		}
+
+		if err := form.checkExternalOption(options); err != nil {
+			return validation.Errors{"external": err}
+		}
+
+		if err := form.checkModerationOptions(options.Moderation); err != nil {
+			return validation.Errors{"moderation": err}
+		}
+
+		if err := form.checkBotProtectionOptions(options.BotProtection); err != nil {
+			return validation.Errors{"botProtection": err}
+		}
+
+		if err := form.checkEncryptionOptions(options.Encryption); err != nil {
+			return validation.Errors{"encryption": err}
+		}
+
+		if err := form.checkSoftDeleteOptions(options.SoftDelete); err != nil {
+			return validation.Errors{"softDelete": err}
+		}
+
+		if err := form.checkVersioningOptions(options.Versioning); err != nil {
+			return validation.Errors{"versioning": err}
+		}
+
+		if err := form.checkExternalIdOptions(options.ExternalId); err != nil {
+			return validation.Errors{"externalId": err}
+		}
+
+		if err := form.checkHistoryOptions(options.History, options.Encryption); err != nil {
+			return validation.Errors{"history": err}
+		}
+
+		if err := form.checkUniqueConstraints(options.UniqueConstraints, options.Encryption); err != nil {
+			return validation.Errors{"uniqueConstraints": err}
+		}
+	}
+
+	return nil
+}
+
+// checkDatabaseOption checks whether the collection records table
+// could be stored in the specified attached database.
+func (form *CollectionUpsert) checkDatabaseOption(database string) error {
+	// moving the records table between databases is not supported
+	if !form.collection.IsNew() && !strings.EqualFold(database, form.collection.Database()) {
+		return validation.NewError(
+			"validation_database_change",
+			"The collection database cannot be changed.",
+		)
+	}
+
+	if database == "" {
+		return nil
+	}
+
+	attached, _ := form.dao.AttachedDatabases()
+
+	isAttached := false
+	for _, name := range attached {
+		if strings.EqualFold(name, database) {
+			isAttached = true
+			break
+		}
+	}
+
+	if !isAttached {
+		return validation.NewError(
+			"validation_unknown_database",
+			fmt.Sprintf("%q is not an attached database.", database),
+		)
+	}
+
+	// the sequence fields triggers reference the main db _sequences table
+	for _, field := range form.Schema.Fields() {
+		if field.Type == schema.FieldTypeSequence {
+			return validation.NewError(
+				"validation_database_sequence_field",
+				"The sequence fields are not supported in attached database collections.",
+			)
+		}
+	}
+
+	return nil
+}
+
+// isExternal checks whether the form options register an external collection.
+func (form *CollectionUpsert) isExternal() bool {
+	return (&models.Collection{Type: form.Type, Options: form.Options}).IsExternal()
+}
+
+// checkExternalOption checks whether the form collection could be
+// registered as external collection for an existing table.
+func (form *CollectionUpsert) checkExternalOption(options models.CollectionBaseOptions) error {
+	if !form.collection.IsNew() && options.External != form.collection.IsExternal() {
+		return validation.NewError(
+			"validation_external_change",
+			"The collection external state cannot be changed.",
+		)
+	}
+
+	if !options.External {
+		return nil
+	}
+
+	// the name of an existing collection table cannot be changed
+	tableName := form.Name
+	if !form.collection.IsNew() {
+		tableName = form.collection.Name
+	}
+
+	// the external table is not managed by the app so the
+	// options that require extra table columns or triggers are not supported
+	if options.Encryption.Enabled ||
+		options.SoftDelete.Enabled ||
+		options.Versioning.Enabled ||
+		options.ExternalId.Enabled ||
+		options.History.Enabled ||
+		len(options.UniqueConstraints) > 0 {
+		return validation.NewError(
+			"validation_external_unsupported_options",
+			"The encryption, soft delete, versioning, external id, history and unique constraints options are not supported for external collections.",
+		)
+	}
+
+	if !form.dao.HasTable(tableName) {
+		return validation.NewError(
+			"validation_external_missing_table",
+			fmt.Sprintf("Missing %q table.", tableName),
+		)
+	}
+
+	columns, err := form.dao.GetTableColumns(tableName)
+	if err != nil {
+		return validation.NewError("validation_external_invalid_table", "Failed to load the table columns.")
+	}
+
+	missing := []string{}
+
+	if !list.ExistInSlice(schema.FieldNameId, columns) {
+		missing = append(missing, schema.FieldNameId)
+	}
+
+	for _, field := range form.Schema.Fields() {
+		if field.Type == schema.FieldTypeComputed {
+			continue // computed fields don't have a db column
+		}
+
+		if !list.ExistInSlice(field.Name, columns) {
+			missing = append(missing, field.Name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return validation.NewError(
+			"validation_external_missing_columns",
+			fmt.Sprintf("The table is missing the following schema columns: %s.", strings.Join(missing, ", ")),
+		)
+	}
+
+	return nil
+}
+
+// checkBotProtectionOptions checks whether the bot protection
+// options request data fields don't conflict with the form schema fields.
+func (form *CollectionUpsert) checkBotProtectionOptions(options models.CollectionBotProtectionOptions) error {
+	isSchemaField := func(name string) bool {
+		return form.Schema.GetFieldByName(name) != nil ||
+			list.ExistInSlice(name, schema.BaseModelFieldNames()) ||
+			list.ExistInSlice(name, schema.AuthFieldNames())
+	}
+
+	for i, name := range options.HoneypotFields {
+		if isSchemaField(name) {
+			return validation.Errors{"honeypotFields": validation.Errors{
+				strconv.Itoa(i): validation.NewError("validation_schema_field", "Honeypot fields must not be collection fields."),
+			}}
+		}
+	}
+
+	if options.SubmitTimeField != "" && isSchemaField(options.SubmitTimeField) {
+		return validation.Errors{"submitTimeField": validation.NewError(
+			"validation_schema_field",
+			"The submit time field must not be a collection field.",
+		)}
+	}
+
+	return nil
+}
+
+// checkEncryptionOptions checks whether the records encryption
+// can be enabled for the form collection.
+func (form *CollectionUpsert) checkEncryptionOptions(options models.CollectionEncryptionOptions) error {
+	if !options.Enabled {
+		return nil
+	}
+
+	if len(form.dao.EncryptionKey()) != 32 {
+		return validation.Errors{"enabled": validation.NewError(
+			"validation_missing_encryption_key",
+			"The records encryption requires a valid 32 characters app encryption key.",
+		)}
+	}
+
+	// the computed expressions are evaluated at db level
+	// and can't reference the encrypted field values
+	for _, field := range form.Schema.Fields() {
+		if field.Type != schema.FieldTypeComputed {
+			continue
+		}
+
+		options, _ := field.Options.(*schema.ComputedOptions)
+		if options == nil {
+			continue
+		}
+
+		identifiers, _ := search.ComputedExpr(options.Expression).Identifiers()
+		for _, name := range identifiers {
+			ref := form.Schema.GetFieldByName(name)
+			if ref != nil && daos.IsEncryptableRecordField(ref) {
+				return validation.Errors{"enabled": validation.NewError(
+					"validation_encrypted_computed_field",
+					fmt.Sprintf("The computed field %q references the encrypted field %q.", field.Name, name),
+				)}
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkSoftDeleteOptions checks whether the records soft delete
+// hidden column doesn't conflict with the form schema fields.
+func (form *CollectionUpsert) checkSoftDeleteOptions(options models.CollectionSoftDeleteOptions) error {
+	if options.Enabled && form.Schema.GetFieldByName(schema.FieldNameDeleted) != nil {
+		return validation.Errors{"enabled": validation.NewError(
+			"validation_soft_delete_field_conflict",
+			fmt.Sprintf("The soft delete requires the collection to not have a %q schema field.", schema.FieldNameDeleted),
+		)}
+	}
+
+	return nil
+}
+
+// checkVersioningOptions checks whether the records versioning
+// hidden column doesn't conflict with the form schema fields.
+func (form *CollectionUpsert) checkVersioningOptions(options models.CollectionVersioningOptions) error {
+	if options.Enabled && form.Schema.GetFieldByName(schema.FieldNameVersion) != nil {
+		return validation.Errors{"enabled": validation.NewError(
+			"validation_versioning_field_conflict",
+			fmt.Sprintf("The records versioning requires the collection to not have a %q schema field.", schema.FieldNameVersion),
+		)}
+	}
+
+	return nil
+}
+
+// checkExternalIdOptions checks whether the records external id
+// column doesn't conflict with the form schema fields.
+func (form *CollectionUpsert) checkExternalIdOptions(options models.CollectionExternalIdOptions) error {
+	if options.Enabled && form.Schema.GetFieldByName(schema.FieldNameExternalId) != nil {
+		return validation.Errors{"enabled": validation.NewError(
+			"validation_external_id_field_conflict",
+			fmt.Sprintf("The external ids require the collection to not have a %q schema field.", schema.FieldNameExternalId),
+		)}
+	}
+
+	return nil
+}
+
+// checkPhoneAuthOption checks whether the auth records phone
+// column doesn't conflict with the form schema fields.
+func (form *CollectionUpsert) checkPhoneAuthOption(allowPhoneAuth bool) error {
+	if allowPhoneAuth && form.Schema.GetFieldByName(schema.FieldNamePhone) != nil {
+		return validation.NewError(
+			"validation_phone_field_conflict",
+			fmt.Sprintf("The phone auth requires the collection to not have a %q schema field.", schema.FieldNamePhone),
+		)
+	}
+
+	return nil
+}
+
+// checkHistoryOptions checks whether the records change history
+// could be enabled together with the other collection options.
+func (form *CollectionUpsert) checkHistoryOptions(
+	options models.CollectionHistoryOptions,
+	encryption models.CollectionEncryptionOptions,
+) error {
+	// the history entries are stored as plain json
+	if options.Enabled && encryption.Enabled {
+		return validation.Errors{"enabled": validation.NewError(
+			"validation_history_encryption_conflict",
+			"The records history can't be enabled for collections with encrypted records.",
+		)}
+	}
+
+	return nil
+}
+
+// checkUniqueConstraints checks whether the multi-column unique
+// constraints reference existing indexable form schema fields and
+// whether the existing collection records satisfy the constraints.
+func (form *CollectionUpsert) checkUniqueConstraints(
+	constraints []models.CollectionUniqueConstraint,
+	encryption models.CollectionEncryptionOptions,
+) error {
+	names := map[string]struct{}{}
+
+	for i, constraint := range constraints {
+		key := strconv.Itoa(i)
+
+		if _, ok := names[strings.ToLower(constraint.Name)]; ok {
+			return validation.Errors{key: validation.Errors{"name": validation.NewError(
+				"validation_duplicated_unique_constraint_name",
+				"The unique constraint name must be unique.",
+			)}}
+		}
+		names[strings.ToLower(constraint.Name)] = struct{}{}
+
+		for j, name := range constraint.Fields {
+			field := form.Schema.GetFieldByName(name)
+			if field == nil ||
+				field.Type == schema.FieldTypeComputed ||
+				field.Type == schema.FieldTypeEncrypted ||
+				(encryption.Enabled && daos.IsEncryptableRecordField(field)) {
+				return validation.Errors{key: validation.Errors{"fields": validation.Errors{
+					strconv.Itoa(j): validation.NewError(
+						"validation_invalid_unique_constraint_field",
+						"Missing, computed or encrypted schema field.",
+					),
+				}}}
+			}
+
+			if list.ExistInSlice(name, constraint.Fields[:j]) {
+				return validation.Errors{key: validation.Errors{"fields": validation.Errors{
+					strconv.Itoa(j): validation.NewError(
+						"validation_duplicated_unique_constraint_field",
+						"Duplicated unique constraint field.",
+					),
+				}}}
+			}
+		}
+
+		if form.collection.IsNew() || !form.hasExistingFieldNames(constraint.Fields) {
+			continue // no existing records to check
+		}
+
+		hasDuplicates, err := form.dao.HasRecordUniqueConstraintDuplicates(form.collection, constraint)
+		if err != nil || hasDuplicates {
+			return validation.Errors{key: validation.Errors{"fields": validation.NewError(
+				"validation_unique_constraint_violation",
+				"The existing collection records have duplicated values for the constraint fields.",
+			)}}
+		}
+	}
+
+	return nil
+}
+
+// hasExistingFieldNames checks whether all of the provided field names
+// exist in the stored collection schema and are not renamed by the form.
+func (form *CollectionUpsert) hasExistingFieldNames(names []string) bool {
+	for _, name := range names {
+		oldField := form.collection.Schema.GetFieldByName(name)
+		if oldField == nil || oldField.Type == schema.FieldTypeComputed {
+			return false
+		}
+
+		newField := form.Schema.GetFieldById(oldField.Id)
+		if newField == nil || newField.Name != name {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkSamlOptions checks whether the SAML fields mapping
+// targets existing form schema fields.
+func (form *CollectionUpsert) checkSamlOptions(options models.CollectionSamlOptions) error {
+	return form.checkFieldsMapping(options.FieldsMapping)
+}
+
+// checkFieldsMapping checks whether the external attributes
+// fields mapping targets existing non-file form schema fields.
+func (form *CollectionUpsert) checkFieldsMapping(mapping map[string]string) error {
+	for attr, name := range mapping {
+		field := form.Schema.GetFieldByName(name)
+		if field == nil || field.Type == schema.FieldTypeFile {
+			return validation.Errors{"fieldsMapping": validation.Errors{
+				attr: validation.NewError("validation_invalid_field", "Missing or file schema field."),
+			}}
+		}
+	}
+
+	return nil
+}
+
+// checkModerationOptions checks whether the moderation options
+// fields exist in the form schema and are from the expected type.
+func (form *CollectionUpsert) checkModerationOptions(options models.CollectionModerationOptions) error {
+	for i, name := range options.Fields {
+		field := form.Schema.GetFieldByName(name)
+		if field == nil || field.Type != schema.FieldTypeText {
+			return validation.Errors{"fields": validation.Errors{
+				strconv.Itoa(i): validation.NewError("validation_invalid_text_field", "Missing or non-text schema field."),
+			}}
+		}
+	}
+
+	if options.VerdictField != "" {
+		field := form.Schema.GetFieldByName(options.VerdictField)
+		if field == nil || field.Type != schema.FieldTypeJson {
+			return validation.Errors{"verdictField": validation.NewError(
+				"validation_invalid_json_field",
+				"Missing or non-json schema field.",
+			)}
+		}
 	}
 
 	return nil
@@ -344,6 +1067,15 @@ func (form *CollectionUpsert) checkOptions(value any) error {
 // You can optionally provide a list of InterceptorFunc to further
 // modify the form behavior before persisting it.
 func (form *CollectionUpsert) Submit(interceptors ...InterceptorFunc) error {
+	// keep the stored write-only LDAP bind password if not changed
+	if !form.collection.IsNew() {
+		dummy := &models.Collection{Type: form.Type, Options: form.Options}
+		if err := dummy.RestoreLdapBindPassword(form.collection); err != nil {
+			return err
+		}
+		form.Options = dummy.Options
+	}
+
 	if err := form.Validate(); err != nil {
 		return err
 	}
@@ -360,6 +1092,9 @@ func (form *CollectionUpsert) Submit(interceptors ...InterceptorFunc) error {
 			form.collection.MarkAsNew()
 			form.collection.SetId(form.Id)
 		}
+	} else {
+		// checked on save against the stored collection version
+		form.collection.Version = form.Version
 	}
 
 	// system collections cannot be renamed
@@ -373,6 +1108,8 @@ func (form *CollectionUpsert) Submit(interceptors ...InterceptorFunc) error {
 	form.collection.CreateRule = form.CreateRule
 	form.collection.UpdateRule = form.UpdateRule
 	form.collection.DeleteRule = form.DeleteRule
+	form.collection.SubscribeRule = form.SubscribeRule
+	form.collection.RuleMacros = form.RuleMacros
 	form.collection.SetOptions(form.Options)
 
 	return runInterceptors(func() error {
@@ -11,6 +11,7 @@ import (
 	"github.com/pocketbase/pocketbase/models/schema"
 	"github.com/pocketbase/pocketbase/tests"
 	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
 	"github.com/spf13/cast"
 )
 
@@ -32,6 +33,9 @@ func TestNewCollectionUpsert(t *testing.T) {
 	collection.UpdateRule = &updateRule
 	deleteRule := "test_delete"
 	collection.DeleteRule = &deleteRule
+	subscribeRule := "test_subscribe"
+	collection.SubscribeRule = &subscribeRule
+	collection.RuleMacros = types.JsonMap{"test": "test_macro"}
 	collection.Schema = schema.NewSchema(&schema.SchemaField{
 		Name: "test",
 		Type: schema.FieldTypeText,
@@ -71,6 +75,14 @@ func TestNewCollectionUpsert(t *testing.T) {
 		t.Errorf("Expected DeleteRule %v, got %v", collection.DeleteRule, form.DeleteRule)
 	}
 
+	if form.SubscribeRule != collection.SubscribeRule {
+		t.Errorf("Expected SubscribeRule %v, got %v", collection.SubscribeRule, form.SubscribeRule)
+	}
+
+	if form.RuleMacros["test"] != collection.RuleMacros["test"] {
+		t.Errorf("Expected RuleMacros %v, got %v", collection.RuleMacros, form.RuleMacros)
+	}
+
 	// store previous state and modify the collection schema to verify
 	// that the form.Schema is a deep clone
 	loadedSchema, _ := collection.Schema.MarshalJSON()
@@ -113,9 +125,10 @@ func TestCollectionUpsertValidateAndSubmit(t *testing.T) {
 				"viewRule": "missing = '123'",
 				"createRule": "missing = '123'",
 				"updateRule": "missing = '123'",
-				"deleteRule": "missing = '123'"
+				"deleteRule": "missing = '123'",
+				"subscribeRule": "missing = '123'"
 			}`,
-			[]string{"name", "type", "schema", "listRule", "viewRule", "createRule", "updateRule", "deleteRule"},
+			[]string{"name", "type", "schema", "listRule", "viewRule", "createRule", "updateRule", "deleteRule", "subscribeRule"},
 		},
 		{
 			"create failure - existing name",
@@ -168,6 +181,46 @@ func TestCollectionUpsertValidateAndSubmit(t *testing.T) {
 			}`,
 			[]string{"schema"},
 		},
+		{
+			"create failure - invalid computed field reference",
+			"",
+			`{
+				"name": "test_new",
+				"schema": [
+					{"name":"price","type":"number"},
+					{"name":"total","type":"computed","options":{"expression":"price * missing"}}
+				]
+			}`,
+			[]string{"schema"},
+		},
+		{
+			"create failure - computed field referencing another computed field",
+			"",
+			`{
+				"name": "test_new",
+				"schema": [
+					{"name":"price","type":"number"},
+					{"name":"total","type":"computed","options":{"expression":"price * 2"}},
+					{"name":"total2","type":"computed","options":{"expression":"total * 2"}}
+				]
+			}`,
+			[]string{"schema"},
+		},
+		{
+			"create success - computed field",
+			"",
+			`{
+				"name": "test_new_computed",
+				"schema": [
+					{"name":"price","type":"number"},
+					{"name":"quantity","type":"number"},
+					{"name":"total","type":"computed","options":{"expression":"price * quantity - 1"}}
+				],
+				"listRule": "total > 10",
+				"viewRule": ""
+			}`,
+			[]string{},
+		},
 		{
 			"create failure - check type options validators",
 			"",
@@ -181,6 +234,310 @@ func TestCollectionUpsertValidateAndSubmit(t *testing.T) {
 			}`,
 			[]string{"options"},
 		},
+		{
+			"create failure - check base type options validators",
+			"",
+			`{
+				"name": "test_new",
+				"type": "base",
+				"schema": [
+					{"name":"test","type":"text"}
+				],
+				"options": { "createRateLimit": 10 }
+			}`,
+			[]string{"options"},
+		},
+		{
+			"create failure - check moderation options fields",
+			"",
+			`{
+				"name": "test_new",
+				"type": "base",
+				"schema": [
+					{"name":"test1","type":"text"},
+					{"name":"test2","type":"bool"}
+				],
+				"options": { "moderation": {"enabled":true,"action":"flag","fields":["test1","test2"]} }
+			}`,
+			[]string{"options"},
+		},
+		{
+			"create failure - check moderation options verdict field",
+			"",
+			`{
+				"name": "test_new",
+				"type": "auth",
+				"schema": [
+					{"name":"test1","type":"text"}
+				],
+				"options": { "moderation": {"enabled":true,"action":"flag","verdictField":"test1"} }
+			}`,
+			[]string{"options"},
+		},
+		{
+			"create failure - check saml options fields mapping",
+			"",
+			`{
+				"name": "test_new",
+				"type": "auth",
+				"schema": [
+					{"name":"test1","type":"text"},
+					{"name":"test2","type":"file","options":{"maxSelect":1,"maxSize":100}}
+				],
+				"options": { "saml": {"fieldsMapping":{"a":"test1","b":"test2","c":"missing"}} }
+			}`,
+			[]string{"options"},
+		},
+		{
+			"create failure - check ldap options fields mapping",
+			"",
+			`{
+				"name": "test_new",
+				"type": "auth",
+				"schema": [
+					{"name":"test1","type":"file","options":{"maxSelect":1,"maxSize":100}}
+				],
+				"options": { "ldap": {"fieldsMapping":{"cn":"test1"}} }
+			}`,
+			[]string{"options"},
+		},
+		{
+			"create failure - soft delete with deleted field",
+			"",
+			`{
+				"name": "test_new",
+				"type": "base",
+				"schema": [
+					{"name":"deleted","type":"bool"}
+				],
+				"options": { "softDelete": {"enabled":true} }
+			}`,
+			[]string{"options"},
+		},
+		{
+			"create failure - versioning with version field",
+			"",
+			`{
+				"name": "test_new",
+				"type": "auth",
+				"schema": [
+					{"name":"version","type":"number"}
+				],
+				"options": { "versioning": {"enabled":true} }
+			}`,
+			[]string{"options"},
+		},
+		{
+			"create failure - invalid field validation rule",
+			"",
+			`{
+				"name": "test_new",
+				"type": "base",
+				"schema": [
+					{"name":"test1","type":"number","validation":"missing > 0"}
+				]
+			}`,
+			[]string{"schema"},
+		},
+		{
+			"create failure - invalid field read and write rules",
+			"",
+			`{
+				"name": "test_new",
+				"type": "base",
+				"schema": [
+					{"name":"test1","type":"number","readRule":"missing > 0"},
+					{"name":"test2","type":"number","writeRule":"@request.auth.missing = 1"}
+				]
+			}`,
+			[]string{"schema"},
+		},
+		{
+			"create success - field read and write rules",
+			"",
+			`{
+				"name": "test_field_rules",
+				"type": "base",
+				"schema": [
+					{"name":"test1","type":"number","readRule":"test1 > 0 && @request.auth.id != ''"},
+					{"name":"test2","type":"number","writeRule":"@request.auth.id != '' && test2 < 10"}
+				]
+			}`,
+			[]string{},
+		},
+		{
+			"create failure - external id with externalId field",
+			"",
+			`{
+				"name": "test_new",
+				"type": "base",
+				"schema": [
+					{"name":"externalId","type":"text"}
+				],
+				"options": { "externalId": {"enabled":true} }
+			}`,
+			[]string{"options"},
+		},
+		{
+			"create failure - phone auth with phone field",
+			"",
+			`{
+				"name": "test_new",
+				"type": "auth",
+				"schema": [
+					{"name":"phone","type":"text"}
+				],
+				"options": { "allowPhoneAuth": true }
+			}`,
+			[]string{"options"},
+		},
+		{
+			"create failure - unique constraint with missing and computed fields",
+			"",
+			`{
+				"name": "test_new",
+				"type": "base",
+				"schema": [
+					{"name":"test1","type":"text"},
+					{"name":"test2","type":"computed","options":{"expression":"test1"}}
+				],
+				"options": { "uniqueConstraints": [{"name":"test","fields":["test1","test2","missing"]}] }
+			}`,
+			[]string{"options"},
+		},
+		{
+			"create failure - duplicated unique constraint names",
+			"",
+			`{
+				"name": "test_new",
+				"type": "base",
+				"schema": [
+					{"name":"test1","type":"text"},
+					{"name":"test2","type":"text"}
+				],
+				"options": { "uniqueConstraints": [
+					{"name":"test","fields":["test1","test2"]},
+					{"name":"TEST","fields":["test2"]}
+				] }
+			}`,
+			[]string{"options"},
+		},
+		{
+			"create failure - history with encryption",
+			"",
+			`{
+				"name": "test_new",
+				"type": "base",
+				"schema": [
+					{"name":"test1","type":"text"}
+				],
+				"options": { "history": {"enabled":true}, "encryption": {"enabled":true} }
+			}`,
+			[]string{"options"},
+		},
+		{
+			"create failure - check bot protection options fields",
+			"",
+			`{
+				"name": "test_new",
+				"type": "base",
+				"schema": [
+					{"name":"test1","type":"text"}
+				],
+				"options": { "botProtection": {"honeypotFields":["website","test1"]} }
+			}`,
+			[]string{"options"},
+		},
+		{
+			"create failure - check bot protection options submit time field",
+			"",
+			`{
+				"name": "test_new",
+				"type": "auth",
+				"schema": [
+					{"name":"test1","type":"text"}
+				],
+				"options": { "botProtection": {"minSubmitTime":3,"submitTimeField":"username"} }
+			}`,
+			[]string{"options"},
+		},
+		{
+			"create failure - encryption without master key",
+			"",
+			`{
+				"name": "test_new",
+				"type": "base",
+				"schema": [
+					{"name":"test1","type":"text"}
+				],
+				"options": { "encryption": {"enabled":true} }
+			}`,
+			[]string{"options"},
+		},
+		{
+			"create failure - encrypted field without master key",
+			"",
+			`{
+				"name": "test_new",
+				"type": "base",
+				"schema": [
+					{"name":"test1","type":"encrypted"}
+				]
+			}`,
+			[]string{"schema"},
+		},
+		{
+			"create success - with moderation and bot protection options",
+			"",
+			`{
+				"name": "test_moderation",
+				"type": "base",
+				"schema": [
+					{"name":"test1","type":"text"},
+					{"name":"test2","type":"json"}
+				],
+				"options": {
+					"moderation": {"enabled":true,"action":"reject","fields":["test1"],"verdictField":"test2"},
+					"botProtection": {"honeypotFields":["website"],"minSubmitTime":3,"submitTimeField":"startedAt","ipCooldown":60}
+				}
+			}`,
+			[]string{},
+		},
+		{
+			"create success - with saml options fields mapping",
+			"",
+			`{
+				"name": "test_saml",
+				"type": "auth",
+				"schema": [
+					{"name":"test1","type":"text"}
+				],
+				"options": { "saml": {"fieldsMapping":{"displayName":"test1"}} }
+			}`,
+			[]string{},
+		},
+		{
+			"create success - with ldap options",
+			"",
+			`{
+				"name": "test_ldap",
+				"type": "auth",
+				"schema": [
+					{"name":"test1","type":"text"}
+				],
+				"options": {
+					"minPasswordLength": 8,
+					"ldap": {
+						"enabled": true,
+						"url": "ldap://127.0.0.1:389",
+						"baseDn": "dc=example,dc=com",
+						"userFilter": "(uid={identity})",
+						"fieldsMapping": {"cn":"test1"}
+					}
+				}
+			}`,
+			[]string{},
+		},
 		{
 			"create success",
 			"",
@@ -222,6 +579,102 @@ func TestCollectionUpsertValidateAndSubmit(t *testing.T) {
 			}`,
 			[]string{},
 		},
+		{
+			"update failure - unique constraint violated by the existing records",
+			"demo2",
+			`{"options": { "uniqueConstraints": [{"name":"test","fields":["active"]}] }}`,
+			[]string{"options"},
+		},
+		{
+			"create failure - invalid database name",
+			"",
+			`{"name":"new","schema":[{"name":"test","type":"text"}],"options":{"database":"main"}}`,
+			[]string{"options"},
+		},
+		{
+			"create failure - unknown attached database",
+			"",
+			`{"name":"new","schema":[{"name":"test","type":"text"}],"options":{"database":"analytics"}}`,
+			[]string{"options"},
+		},
+		{
+			"update failure - changing the collection database",
+			"demo2",
+			`{"options":{"database":"analytics"}}`,
+			[]string{"options"},
+		},
+		{
+			"update failure - tree relation to another collection",
+			"demo2",
+			`{
+				"schema": [
+					{"id":"mkrguaaf","name":"title","type":"text"},
+					{"id":"izkl5z2s","name":"active","type":"bool"},
+					{"name":"parent","type":"relation","options":{"collectionId":"wzlqyes4orhoygb","maxSelect":1,"tree":true}}
+				]
+			}`,
+			[]string{"schema"},
+		},
+		{
+			"update failure - tree multiple self relation",
+			"demo2",
+			`{
+				"schema": [
+					{"id":"mkrguaaf","name":"title","type":"text"},
+					{"id":"izkl5z2s","name":"active","type":"bool"},
+					{"name":"parent","type":"relation","options":{"collectionId":"sz5l5z67tg7gku0","tree":true}}
+				]
+			}`,
+			[]string{"schema"},
+		},
+		{
+			"update failure - multiple tree relations",
+			"demo2",
+			`{
+				"schema": [
+					{"id":"mkrguaaf","name":"title","type":"text"},
+					{"id":"izkl5z2s","name":"active","type":"bool"},
+					{"name":"parent","type":"relation","options":{"collectionId":"sz5l5z67tg7gku0","maxSelect":1,"tree":true}},
+					{"name":"parent2","type":"relation","options":{"collectionId":"sz5l5z67tg7gku0","maxSelect":1,"tree":true}}
+				]
+			}`,
+			[]string{"schema"},
+		},
+		{
+			"update failure - tree path field conflict",
+			"demo2",
+			`{
+				"schema": [
+					{"id":"mkrguaaf","name":"title","type":"text"},
+					{"id":"izkl5z2s","name":"active","type":"bool"},
+					{"name":"path","type":"text"},
+					{"name":"parent","type":"relation","options":{"collectionId":"sz5l5z67tg7gku0","maxSelect":1,"tree":true}}
+				]
+			}`,
+			[]string{"schema"},
+		},
+		{
+			"update success - tree single self relation",
+			"demo2",
+			`{
+				"schema": [
+					{"id":"mkrguaaf","name":"title","type":"text"},
+					{"id":"izkl5z2s","name":"active","type":"bool"},
+					{"name":"parent","type":"relation","options":{"collectionId":"sz5l5z67tg7gku0","maxSelect":1,"tree":true}}
+				]
+			}`,
+			[]string{},
+		},
+		{
+			"update failure - number field big int change",
+			"demo1",
+			`{
+				"schema": [
+					{"id":"1z1ld0i5","name":"number","type":"number","options":{"bigInt":true}}
+				]
+			}`,
+			[]string{"schema"},
+		},
 		{
 			"update failure - existing name",
 			"demo2",
@@ -317,6 +770,43 @@ func TestCollectionUpsertValidateAndSubmit(t *testing.T) {
 			}`,
 			[]string{},
 		},
+		{
+			"update failure - invalid rule macros",
+			"demo2",
+			`{
+				"ruleMacros": {
+					"invalid-name": "title = 'test'",
+					"empty": "",
+					"nonString": 123,
+					"unknownField": "missing = 'test'",
+					"unknownMacro": "@macro.missing",
+					"circularA": "@macro.circularB",
+					"circularB": "@macro.circularA"
+				}
+			}`,
+			[]string{"ruleMacros"},
+		},
+		{
+			"update failure - rule with unknown macro",
+			"demo2",
+			`{
+				"ruleMacros": {"isActive": "active = true"},
+				"listRule": "@macro.isActive && @macro.missing"
+			}`,
+			[]string{"listRule"},
+		},
+		{
+			"update success - rule macros",
+			"demo2",
+			`{
+				"ruleMacros": {
+					"isActive": "active = true",
+					"isActiveTest": "@macro.isActive && title ~ 'test'"
+				},
+				"listRule": "@macro.isActiveTest || @request.auth.id != ''"
+			}`,
+			[]string{},
+		},
 		{
 			"update success - system collection",
 			"nologin",
@@ -325,7 +815,8 @@ func TestCollectionUpsertValidateAndSubmit(t *testing.T) {
 				"viewRule": "name='123'",
 				"createRule": "name='123'",
 				"updateRule": "name='123'",
-				"deleteRule": "name='123'"
+				"deleteRule": "name='123'",
+				"subscribeRule": "name='123'"
 			}`,
 			[]string{},
 		},
@@ -427,6 +918,10 @@ func TestCollectionUpsertValidateAndSubmit(t *testing.T) {
 			t.Errorf("[%s] Expected DeleteRule %v, got %v", s.testName, collection.DeleteRule, form.DeleteRule)
 		}
 
+		if cast.ToString(form.SubscribeRule) != cast.ToString(collection.SubscribeRule) {
+			t.Errorf("[%s] Expected SubscribeRule %v, got %v", s.testName, collection.SubscribeRule, form.SubscribeRule)
+		}
+
 		formSchema, _ := form.Schema.MarshalJSON()
 		collectionSchema, _ := collection.Schema.MarshalJSON()
 		if string(formSchema) != string(collectionSchema) {
@@ -435,6 +930,241 @@ func TestCollectionUpsertValidateAndSubmit(t *testing.T) {
 	}
 }
 
+func TestCollectionUpsertEncryptionOptions(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Dao().SetEncryptionKey("abcdabcdabcdabcdabcdabcdabcdabcd")
+
+	scenarios := []struct {
+		name        string
+		jsonData    string
+		expectError bool
+	}{
+		{
+			"computed field referencing an encrypted field",
+			`{
+				"name": "test_encrypted1",
+				"schema": [
+					{"name":"price","type":"number"},
+					{"name":"total","type":"computed","options":{"expression":"price * 2"}}
+				],
+				"options": {"encryption": {"enabled":true}}
+			}`,
+			true,
+		},
+		{
+			"computed field referencing a unique field",
+			`{
+				"name": "test_encrypted2",
+				"schema": [
+					{"name":"price","type":"number","unique":true},
+					{"name":"title","type":"text"},
+					{"name":"total","type":"computed","options":{"expression":"price * 2"}}
+				],
+				"options": {"encryption": {"enabled":true}}
+			}`,
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		form := forms.NewCollectionUpsert(app, &models.Collection{})
+
+		if err := json.Unmarshal([]byte(s.jsonData), form); err != nil {
+			t.Fatalf("[%s] Failed to load form data: %v", s.name, err)
+		}
+
+		err := form.Submit()
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			errs, ok := err.(validation.Errors)
+			if !ok || errs["options"] == nil {
+				t.Errorf("[%s] Expected options error, got %v", s.name, err)
+			}
+		}
+	}
+}
+
+func TestCollectionUpsertLdapBindPassword(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := collection.AuthOptions()
+	options.Ldap.BindDn = "cn=admin,dc=example,dc=com"
+	options.Ldap.BindPassword = "secret"
+	collection.SetOptions(options)
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	// submit the serialized (aka. without bind password) collection
+	raw, err := json.Marshal(collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewCollectionUpsert(app, collection)
+	if err := json.Unmarshal(raw, form); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := form.Submit(); err != nil {
+		t.Fatalf("Expected nil, got error %v", err)
+	}
+
+	updated, err := app.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := updated.AuthOptions().Ldap.BindPassword; v != "secret" {
+		t.Fatalf("Expected the bind password to remain %q, got %q", "secret", v)
+	}
+}
+
+func TestCollectionUpsertExternalOptions(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	_, err := app.Dao().DB().NewQuery(`
+		CREATE TABLE ext_items (id INTEGER PRIMARY KEY, title TEXT, price REAL);
+		CREATE TABLE ext_noid (code TEXT);
+	`).Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name           string
+		existingName   string
+		jsonData       string
+		expectedErrors []string
+	}{
+		{
+			"create failure - missing table",
+			"",
+			`{"name":"ext_missing","schema":[{"name":"title","type":"text"}],"options":{"external":true}}`,
+			[]string{"options"},
+		},
+		{
+			"create failure - table without id column",
+			"",
+			`{"name":"ext_noid","schema":[{"name":"code","type":"text"}],"options":{"external":true}}`,
+			[]string{"options"},
+		},
+		{
+			"create failure - schema field without table column",
+			"",
+			`{"name":"ext_items","schema":[{"name":"title","type":"text"},{"name":"missing","type":"text"}],"options":{"external":true}}`,
+			[]string{"options"},
+		},
+		{
+			"create failure - unsupported options",
+			"",
+			`{"name":"ext_items","schema":[{"name":"title","type":"text"}],"options":{"external":true,"softDelete":{"enabled":true}}}`,
+			[]string{"options"},
+		},
+		{
+			"create failure - existing table without the external option",
+			"",
+			`{"name":"ext_items","schema":[{"name":"title","type":"text"}]}`,
+			[]string{"name"},
+		},
+		{
+			"create success",
+			"",
+			`{
+				"name": "ext_items",
+				"schema": [
+					{"name":"title","type":"text"},
+					{"name":"price","type":"number"},
+					{"name":"total","type":"computed","options":{"expression":"price * 2"}}
+				],
+				"listRule": "",
+				"options": {"external":true}
+			}`,
+			[]string{},
+		},
+		{
+			"update failure - rename",
+			"ext_items",
+			`{"name":"ext_items_new"}`,
+			[]string{"name"},
+		},
+		{
+			"update failure - unset the external option",
+			"ext_items",
+			`{"options":{"external":false}}`,
+			[]string{"options"},
+		},
+		{
+			"update failure - set the external option",
+			"demo2",
+			`{"options":{"external":true}}`,
+			[]string{"options"},
+		},
+		{
+			"update success",
+			"ext_items",
+			`{"viewRule":"","options":{"external":true}}`,
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		collection := &models.Collection{}
+		if s.existingName != "" {
+			var err error
+			collection, err = app.Dao().FindCollectionByNameOrId(s.existingName)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		form := forms.NewCollectionUpsert(app, collection)
+
+		if err := json.Unmarshal([]byte(s.jsonData), form); err != nil {
+			t.Fatalf("[%s] Failed to load form data: %v", s.name, err)
+		}
+
+		err := form.Submit()
+
+		errs, ok := err.(validation.Errors)
+		if !ok && err != nil {
+			t.Errorf("[%s] Failed to parse errors %v", s.name, err)
+			continue
+		}
+
+		if len(errs) != len(s.expectedErrors) {
+			t.Errorf("[%s] Expected error keys %v, got %v", s.name, s.expectedErrors, errs)
+		}
+		for _, k := range s.expectedErrors {
+			if _, ok := errs[k]; !ok {
+				t.Errorf("[%s] Missing expected error key %q in %v", s.name, k, errs)
+			}
+		}
+	}
+
+	// the external table must not be altered
+	columns, err := app.Dao().GetTableColumns("ext_items")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(columns) != 3 {
+		t.Fatalf("Expected the ext_items table columns to be unchanged, got %v", columns)
+	}
+}
+
 func TestCollectionUpsertSubmitInterceptors(t *testing.T) {
 	app, _ := tests.NewTestApp()
 	defer app.Cleanup()
@@ -0,0 +1,127 @@
+package forms
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/blueprints"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// errBlueprintDryRun is used to rollback the dry run transaction.
+var errBlueprintDryRun = errors.New("blueprint dry run")
+
+// CollectionsBlueprint is a form model to instantiate
+// the collections of a single [blueprints.Blueprint].
+type CollectionsBlueprint struct {
+	app       core.App
+	dao       *daos.Dao
+	blueprint *blueprints.Blueprint
+
+	// DryRun checks and validates the blueprint collections
+	// without persisting them.
+	DryRun bool `form:"dryRun" json:"dryRun"`
+}
+
+// CollectionsBlueprintResult defines the [CollectionsBlueprint] submit result.
+type CollectionsBlueprintResult struct {
+	DryRun      bool                  `json:"dryRun"`
+	Collections []*models.Collection  `json:"collections"`
+	Conflicts   []blueprints.Conflict `json:"conflicts"`
+}
+
+// NewCollectionsBlueprint creates a new [CollectionsBlueprint] form
+// initialized with the provided [core.App] and [blueprints.Blueprint] instances.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewCollectionsBlueprint(app core.App, blueprint *blueprints.Blueprint) *CollectionsBlueprint {
+	return &CollectionsBlueprint{
+		app:       app,
+		dao:       app.Dao(),
+		blueprint: blueprint,
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *CollectionsBlueprint) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Submit creates the blueprint collections in a single transaction,
+// using the same validations as the regular collection create.
+//
+// If any of the blueprint collections conflicts with an existing
+// collection or db table, the submit fails with a validation error
+// (or in case of [form.DryRun] - the conflicts are returned as part of the result).
+//
+// You can optionally provide a list of InterceptorFunc to further
+// modify the form behavior before persisting it.
+func (form *CollectionsBlueprint) Submit(interceptors ...InterceptorFunc) (*CollectionsBlueprintResult, error) {
+	result := &CollectionsBlueprintResult{
+		DryRun:      form.DryRun,
+		Collections: []*models.Collection{},
+		Conflicts:   form.blueprint.Conflicts(form.dao),
+	}
+
+	if len(result.Conflicts) > 0 {
+		if form.DryRun {
+			return result, nil
+		}
+
+		names := make([]string, len(result.Conflicts))
+		for i, c := range result.Conflicts {
+			names[i] = c.Collection
+		}
+
+		return nil, validation.Errors{"blueprint": validation.NewError(
+			"validation_blueprint_conflict",
+			fmt.Sprintf("The blueprint collections conflict with existing collections or tables: %s.", strings.Join(names, ", ")),
+		)}
+	}
+
+	collections, err := form.blueprint.Build(form.dao)
+	if err != nil {
+		return nil, validation.Errors{"blueprint": validation.NewError(
+			"validation_blueprint_invalid",
+			err.Error(),
+		)}
+	}
+
+	submitErr := runInterceptors(func() error {
+		txErr := form.dao.RunInTransaction(func(txDao *daos.Dao) error {
+			for _, collection := range collections {
+				upsertForm := NewCollectionUpsert(form.app, collection)
+				upsertForm.SetDao(txDao)
+
+				if err := upsertForm.Submit(); err != nil {
+					return validation.Errors{"collections": validation.Errors{collection.Name: err}}
+				}
+			}
+
+			if form.DryRun {
+				return errBlueprintDryRun
+			}
+
+			return nil
+		})
+
+		if errors.Is(txErr, errBlueprintDryRun) {
+			return nil
+		}
+
+		return txErr
+	}, interceptors...)
+
+	if submitErr != nil {
+		return nil, submitErr
+	}
+
+	result.Collections = collections
+
+	return result, nil
+}
@@ -0,0 +1,153 @@
+package forms_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/blueprints"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestCollectionsBlueprintSubmit(t *testing.T) {
+	blog, err := blueprints.Find("blog")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conflicting, err := blueprints.Parse([]byte(`{"name":"conflicting","collections":[{"name":"test1","schema":[{"name":"title","type":"text"}]},{"name":"demo1"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invalid, err := blueprints.Parse([]byte(`{"name":"invalid","collections":[{"name":"test1","schema":[{"name":"title","type":"text"}]},{"name":"test2","listRule":"missing = 1","schema":[{"name":"title","type":"text"}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name                string
+		blueprint           *blueprints.Blueprint
+		dryRun              bool
+		expectError         string
+		expectConflicts     int
+		expectCollections   int
+		expectedPersisted   []string
+		expectedUnpersisted []string
+	}{
+		{
+			name:                "conflicts",
+			blueprint:           conflicting,
+			expectError:         "blueprint: ",
+			expectedUnpersisted: []string{"test1"},
+		},
+		{
+			name:                "conflicts (dry run)",
+			blueprint:           conflicting,
+			dryRun:              true,
+			expectConflicts:     1,
+			expectedUnpersisted: []string{"test1"},
+		},
+		{
+			name:                "invalid collection",
+			blueprint:           invalid,
+			expectError:         "test2: (listRule: ",
+			expectedUnpersisted: []string{"test1", "test2"},
+		},
+		{
+			name:                "valid (dry run)",
+			blueprint:           blog,
+			dryRun:              true,
+			expectCollections:   3,
+			expectedUnpersisted: []string{"categories", "posts", "comments"},
+		},
+		{
+			name:              "valid",
+			blueprint:         blog,
+			expectCollections: 3,
+			expectedPersisted: []string{"categories", "posts", "comments"},
+		},
+	}
+
+	for _, s := range scenarios {
+		app, _ := tests.NewTestApp()
+
+		form := forms.NewCollectionsBlueprint(app, s.blueprint)
+		form.DryRun = s.dryRun
+
+		result, err := form.Submit()
+
+		if s.expectError != "" {
+			if err == nil || !strings.Contains(err.Error(), s.expectError) {
+				t.Errorf("[%s] Expected %q error, got %v", s.name, s.expectError, err)
+			}
+		} else if err != nil {
+			t.Errorf("[%s] Expected nil error, got %v", s.name, err)
+		} else {
+			if result.DryRun != s.dryRun {
+				t.Errorf("[%s] Expected dryRun %v, got %v", s.name, s.dryRun, result.DryRun)
+			}
+			if len(result.Conflicts) != s.expectConflicts {
+				t.Errorf("[%s] Expected %d conflicts, got %v", s.name, s.expectConflicts, result.Conflicts)
+			}
+			if len(result.Collections) != s.expectCollections {
+				t.Errorf("[%s] Expected %d collections, got %d", s.name, s.expectCollections, len(result.Collections))
+			}
+		}
+
+		for _, name := range s.expectedPersisted {
+			if _, err := app.Dao().FindCollectionByNameOrId(name); err != nil {
+				t.Errorf("[%s] Expected collection %q to be created", s.name, name)
+			}
+			if !app.Dao().HasTable(name) {
+				t.Errorf("[%s] Expected table %q to be created", s.name, name)
+			}
+		}
+
+		for _, name := range s.expectedUnpersisted {
+			if _, err := app.Dao().FindCollectionByNameOrId(name); err == nil {
+				t.Errorf("[%s] Expected collection %q to not be created", s.name, name)
+			}
+			if app.Dao().HasTable(name) {
+				t.Errorf("[%s] Expected table %q to not be created", s.name, name)
+			}
+		}
+
+		app.Cleanup()
+	}
+}
+
+func TestCollectionsBlueprintSubmitInterceptors(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	blog, err := blueprints.Find("blog")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewCollectionsBlueprint(app, blog)
+
+	testErr := errors.New("test_error")
+
+	interceptorCalls := 0
+	interceptor := func(next forms.InterceptorNextFunc) forms.InterceptorNextFunc {
+		return func() error {
+			interceptorCalls++
+			return testErr
+		}
+	}
+
+	if _, err := form.Submit(interceptor); err != testErr {
+		t.Fatalf("Expected error %v, got %v", testErr, err)
+	}
+
+	if interceptorCalls != 1 {
+		t.Fatalf("Expected the interceptor to be called once, got %d", interceptorCalls)
+	}
+
+	if app.Dao().HasTable("posts") {
+		t.Fatal("Expected the blueprint collections to not be created")
+	}
+}
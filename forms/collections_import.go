@@ -89,6 +89,37 @@ func (form *CollectionsImport) Submit(interceptors ...InterceptorFunc) error {
 	}, interceptors...)
 }
 
+// Diff validates the form and returns the changes (and the SQL statements)
+// that [CollectionsImport.Submit] would apply, without persisting them.
+func (form *CollectionsImport) Diff() (*daos.CollectionsDiff, error) {
+	if err := form.Validate(); err != nil {
+		return nil, err
+	}
+
+	diff, diffErr := form.dao.DiffCollections(
+		form.Collections,
+		form.DeleteMissing,
+		form.beforeRecordsSync,
+	)
+	if diffErr == nil {
+		return diff, nil
+	}
+
+	// validation failure
+	if err, ok := diffErr.(validation.Errors); ok {
+		return nil, err
+	}
+
+	// generic/db failure
+	if form.app.IsDebug() {
+		log.Println("Internal import diff failure:", diffErr)
+	}
+	return nil, validation.Errors{"collections": validation.NewError(
+		"collections_diff_failure",
+		"Failed to diff the collections configuration.",
+	)}
+}
+
 func (form *CollectionsImport) beforeRecordsSync(txDao *daos.Dao, mappedNew, mappedOld map[string]*models.Collection) error {
 	// refresh the actual persisted collections list
 	refreshedCollections := []*models.Collection{}
@@ -118,6 +149,8 @@ func (form *CollectionsImport) beforeRecordsSync(txDao *daos.Dao, mappedNew, map
 		upsertForm.CreateRule = collection.CreateRule
 		upsertForm.UpdateRule = collection.UpdateRule
 		upsertForm.DeleteRule = collection.DeleteRule
+		upsertForm.SubscribeRule = collection.SubscribeRule
+		upsertForm.RuleMacros = collection.RuleMacros
 		upsertForm.Schema = collection.Schema
 		upsertForm.Options = collection.Options
 
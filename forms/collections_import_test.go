@@ -432,3 +432,47 @@ func TestCollectionsImportSubmitInterceptors(t *testing.T) {
 		t.Fatalf("Expected interceptor2 to be called")
 	}
 }
+
+func TestCollectionsImportDiff(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	form := forms.NewCollectionsImport(app)
+
+	// empty collections
+	if _, err := form.Diff(); err == nil {
+		t.Fatal("Expected validation error, got nil")
+	}
+
+	// invalid collection (field type change)
+	form.Collections = []*models.Collection{}
+	json.Unmarshal([]byte(`[
+		{"id":"sz5l5z67tg7gku0","name":"demo2","schema":[{"id":"izkl5z2s","name":"active","type":"text"}]}
+	]`), &form.Collections)
+	if _, err := form.Diff(); err == nil {
+		t.Fatal("Expected validation error, got nil")
+	}
+
+	// valid collections
+	form.Collections = []*models.Collection{}
+	json.Unmarshal([]byte(`[
+		{"name":"diff_test","schema":[{"name":"test","type":"text"}]}
+	]`), &form.Collections)
+
+	diff, err := form.Diff()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diff.Collections) != 1 || diff.Collections[0].Name != "diff_test" || diff.Destructive {
+		t.Fatalf("Expected non destructive diff_test create, got %v", diff.Collections)
+	}
+
+	if len(diff.Sql) == 0 {
+		t.Fatal("Expected non-empty sql statements")
+	}
+
+	if _, err := app.Dao().FindCollectionByNameOrId("diff_test"); err == nil {
+		t.Fatal("Expected diff_test collection to not be created")
+	}
+}
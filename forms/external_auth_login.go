@@ -0,0 +1,214 @@
+package forms
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+// externalAuthLogin defines the already verified identity of
+// an external (aka. not OAuth2) authentication provider.
+type externalAuthLogin struct {
+	app        core.App
+	dao        *daos.Dao
+	collection *models.Collection
+
+	// optional auth record that will be used if no external
+	// auth relation is found (if it is from the same collection)
+	loggedAuthRecord *models.Record
+
+	provider   string
+	providerId string
+
+	// the optional verified identity email
+	email string
+
+	// marks the auth record as verified even if neither the identity
+	// nor the auth record have email (eg. for OAuth2 identities)
+	verifyWithoutEmail bool
+
+	// the optional identity username used as base
+	// for the new auth record username suggestion
+	username string
+
+	// the mapped identity attributes data (synced with each login)
+	mappedData map[string]any
+
+	// the optional additional data used for creating a new auth record
+	createData map[string]any
+
+	// the optional callback executed in the login transaction
+	// after the auth record create or sync (eg. to sync its roles)
+	afterSave func(txDao *daos.Dao, authRecord *models.Record) error
+}
+
+// submit finds or creates the auth record linked to the external identity
+// and syncs its mapped fields.
+//
+// The auth record is searched in the following order:
+//   - by its existing external auth relation
+//   - the logged auth record (if any)
+//   - by the identity email
+func (l *externalAuthLogin) submit(beforeCreate func(createForm *RecordUpsert, authRecord *models.Record) error) (*models.Record, error) {
+	var authRecord *models.Record
+	var err error
+
+	// check for existing relation with the auth record
+	rel, _ := l.dao.FindExternalAuthByProvider(l.provider, l.providerId)
+	switch {
+	case rel != nil:
+		authRecord, err = l.dao.FindRecordById(l.collection.Id, rel.RecordId)
+		if err != nil {
+			return nil, err
+		}
+	case l.loggedAuthRecord != nil && l.loggedAuthRecord.Collection().Id == l.collection.Id:
+		// fallback to the logged auth record (if any)
+		authRecord = l.loggedAuthRecord
+	case l.email != "":
+		// look for an existing auth record by the identity email
+		authRecord, _ = l.dao.FindAuthRecordByEmail(l.collection.Id, l.email)
+	}
+
+	saveErr := l.dao.RunInTransaction(func(txDao *daos.Dao) error {
+		if authRecord == nil {
+			authRecord = models.NewRecord(l.collection)
+			authRecord.RefreshId()
+			authRecord.MarkAsNew()
+			createForm := NewRecordUpsert(l.app, authRecord)
+			createForm.SetFullManageAccess(true)
+			createForm.SetDao(txDao)
+			if l.username != "" && usernameRegex.MatchString(l.username) {
+				createForm.Username = l.dao.SuggestUniqueAuthRecordUsername(l.collection.Id, l.username)
+			}
+
+			// load custom data
+			createForm.LoadData(l.createData)
+
+			// the mapped identity attributes have priority over the custom data
+			createForm.LoadData(l.mappedData)
+
+			// load the identity email as fallback
+			if createForm.Email == "" {
+				createForm.Email = l.email
+			}
+			// mark as verified as long as it matches the provider data
+			createForm.Verified = createForm.Email == l.email && (l.email != "" || l.verifyWithoutEmail)
+			if createForm.Password == "" {
+				createForm.Password = security.RandomString(30)
+				createForm.PasswordConfirm = createForm.Password
+			}
+
+			if beforeCreate != nil {
+				if err := beforeCreate(createForm, authRecord); err != nil {
+					return err
+				}
+			}
+
+			// create the new auth record
+			if err := createForm.Submit(); err != nil {
+				return err
+			}
+		} else {
+			var changed bool
+
+			// update the existing auth record empty email if the identity has one
+			if authRecord.Email() == "" && l.email != "" {
+				authRecord.SetEmail(l.email)
+				changed = true
+			}
+
+			// update the existing auth record verified state
+			if !authRecord.Verified() && authRecord.Email() == l.email && (l.email != "" || l.verifyWithoutEmail) {
+				authRecord.SetVerified(true)
+				changed = true
+			}
+
+			// sync the mapped fields
+			for name, value := range l.mappedData {
+				old := authRecord.Get(name)
+				authRecord.Set(name, value)
+				if !reflect.DeepEqual(old, authRecord.Get(name)) {
+					changed = true
+				}
+			}
+
+			if changed {
+				if err := txDao.SaveRecord(authRecord); err != nil {
+					return err
+				}
+			}
+		}
+
+		if l.afterSave != nil {
+			if err := l.afterSave(txDao, authRecord); err != nil {
+				return err
+			}
+		}
+
+		// create ExternalAuth relation if missing
+		if rel == nil {
+			rel = &models.ExternalAuth{
+				CollectionId: authRecord.Collection().Id,
+				RecordId:     authRecord.Id,
+				Provider:     l.provider,
+				ProviderId:   l.providerId,
+			}
+			if err := txDao.SaveExternalAuth(rel); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if saveErr != nil {
+		return nil, saveErr
+	}
+
+	return authRecord, nil
+}
+
+// mapAttributesData returns the collection schema fields data of
+// the mapped identity attributes (the attribute names are case-insensitive).
+//
+// The json fields receive all attribute values and the others - only the first one.
+func mapAttributesData(
+	collection *models.Collection,
+	fieldsMapping map[string]string,
+	attributes map[string][]string,
+) map[string]any {
+	result := map[string]any{}
+
+	for attr, name := range fieldsMapping {
+		values, ok := attributes[attr]
+		if !ok {
+			for k, v := range attributes {
+				if strings.EqualFold(k, attr) {
+					values, ok = v, true
+					break
+				}
+			}
+		}
+		if !ok || len(values) == 0 {
+			continue
+		}
+
+		field := collection.Schema.GetFieldByName(name)
+		if field == nil || field.Type == schema.FieldTypeFile {
+			continue
+		}
+
+		if field.Type == schema.FieldTypeJson {
+			result[name] = values
+		} else {
+			result[name] = values[0]
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,215 @@
+package forms
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// errInvalidLoginCredentials is returned by the password login forms
+// on wrong identity or password (aka. a failed login attempt).
+var errInvalidLoginCredentials = errors.New("Invalid login credentials.")
+
+// adminsLoginScope is the identity counters scope of the admin logins.
+const adminsLoginScope = "_admins"
+
+// LoginLockedError is returned by the password login forms when the
+// login identity or the client ip is temporary locked due to too many
+// failed login attempts.
+type LoginLockedError struct {
+	// RetryAfter is the remaining lockout duration.
+	RetryAfter time.Duration
+}
+
+// Error implements the [error] interface.
+func (e *LoginLockedError) Error() string {
+	return "Too many failed login attempts. Please try again later."
+}
+
+// loginThrottle tracks the failed password login attempts of a
+// single login identity and client ip based on the app settings
+// LoginThrottle options.
+type loginThrottle struct {
+	app        core.App
+	dao        *daos.Dao
+	collection *models.Collection // nil for the admin logins
+	identity   string
+	ip         string
+}
+
+type loginThrottleCounter struct {
+	kind        string
+	key         string
+	maxFailures int
+}
+
+// counters returns the enabled failed login attempts counters.
+func (t *loginThrottle) counters() []loginThrottleCounter {
+	config := t.app.Settings().LoginThrottle
+
+	result := make([]loginThrottleCounter, 0, 2)
+
+	if config.MaxIdentityFailures > 0 && t.identity != "" {
+		scope := adminsLoginScope
+		if t.collection != nil {
+			scope = t.collection.Id
+		}
+
+		result = append(result, loginThrottleCounter{
+			kind:        models.LoginAttemptKindIdentity,
+			key:         scope + ":" + strings.ToLower(t.identity),
+			maxFailures: config.MaxIdentityFailures,
+		})
+	}
+
+	if config.MaxIpFailures > 0 && t.ip != "" {
+		result = append(result, loginThrottleCounter{
+			kind:        models.LoginAttemptKindIp,
+			key:         t.ip,
+			maxFailures: config.MaxIpFailures,
+		})
+	}
+
+	return result
+}
+
+// check returns [LoginLockedError] if the login identity or the client ip is locked.
+func (t *loginThrottle) check() error {
+	if !t.app.Settings().LoginThrottle.Enabled {
+		return nil
+	}
+
+	now := time.Now()
+
+	var retryAfter time.Duration
+
+	for _, c := range t.counters() {
+		attempt, err := t.dao.FindLoginAttempt(c.kind, c.key)
+		if err != nil {
+			continue // no failed attempts
+		}
+
+		if v := attempt.RetryAfter(now); v > retryAfter {
+			retryAfter = v
+		}
+	}
+
+	if retryAfter > 0 {
+		return &LoginLockedError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+// registerFailure increments the failed login attempts counters and
+// locks the ones that have reached their max failures limit.
+//
+// The counters are reset if there were no failed attempts (and
+// lockouts) within the configured window.
+//
+// The counters are updated within a single transaction so that
+// concurrent failed attempts can't overwrite each other increments.
+func (t *loginThrottle) registerFailure() error {
+	config := t.app.Settings().LoginThrottle
+	if !config.Enabled {
+		return nil
+	}
+
+	now := time.Now()
+	window := time.Duration(config.Window) * time.Second
+
+	lockedAttempts := []*models.LoginAttempt{}
+
+	txErr := t.dao.RunInTransaction(func(txDao *daos.Dao) error {
+		for _, c := range t.counters() {
+			attempt, err := txDao.FindLoginAttempt(c.kind, c.key)
+			if err != nil {
+				attempt = &models.LoginAttempt{Kind: c.kind, Key: c.key}
+
+				// opportunistically cleanup the no longer relevant counters
+				if err := txDao.DeleteStaleLoginAttempts(now.Add(-window)); err != nil && t.app.IsDebug() {
+					log.Println("Failed to delete the stale login attempts:", err)
+				}
+			} else {
+				lastActivity := attempt.Updated.Time()
+				if attempt.LockedUntil.Time().After(lastActivity) {
+					lastActivity = attempt.LockedUntil.Time()
+				}
+
+				if lastActivity.Add(window).Before(now) {
+					attempt.Failures = 0
+					attempt.Lockouts = 0
+				}
+			}
+
+			attempt.Failures++
+
+			locked := attempt.Failures >= c.maxFailures
+			if locked {
+				attempt.Failures = 0
+				attempt.Lockouts++
+				attempt.LockedUntil, _ = types.ParseDateTime(now.Add(config.LockoutDurationFor(attempt.Lockouts)))
+			}
+
+			if err := txDao.SaveLoginAttempt(attempt); err != nil {
+				return err
+			}
+
+			if locked {
+				lockedAttempts = append(lockedAttempts, attempt)
+			}
+		}
+
+		return nil
+	})
+	if txErr != nil {
+		return txErr
+	}
+
+	for _, attempt := range lockedAttempts {
+		event := &core.LoginLockoutEvent{
+			Collection:   t.collection,
+			Identity:     t.identity,
+			Ip:           t.ip,
+			LoginAttempt: attempt,
+		}
+
+		if err := t.app.OnLoginLockout().Trigger(event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reset deletes the login identity failed attempts counter
+// (the client ip counter is preserved to prevent bypassing
+// the ip lockout with a known valid account).
+func (t *loginThrottle) reset() error {
+	if !t.app.Settings().LoginThrottle.Enabled {
+		return nil
+	}
+
+	for _, c := range t.counters() {
+		if c.kind != models.LoginAttemptKindIdentity {
+			continue
+		}
+
+		attempt, err := t.dao.FindLoginAttempt(c.kind, c.key)
+		if err != nil {
+			continue // nothing to reset
+		}
+
+		if err := t.dao.DeleteLoginAttempt(attempt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
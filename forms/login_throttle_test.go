@@ -0,0 +1,248 @@
+package forms_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func enableLoginThrottle(app *tests.TestApp, maxIdentityFailures int, maxIpFailures int) {
+	app.Settings().LoginThrottle.Enabled = true
+	app.Settings().LoginThrottle.MaxIdentityFailures = maxIdentityFailures
+	app.Settings().LoginThrottle.MaxIpFailures = maxIpFailures
+	app.Settings().LoginThrottle.Window = 600
+	app.Settings().LoginThrottle.LockoutDuration = 60
+	app.Settings().LoginThrottle.MaxLockoutDuration = 600
+}
+
+func TestRecordPasswordLoginThrottleDisabled(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	enableLoginThrottle(app, 1, 1)
+	app.Settings().LoginThrottle.Enabled = false
+
+	collection, _ := app.Dao().FindCollectionByNameOrId("users")
+
+	for i := 0; i < 2; i++ {
+		form := forms.NewRecordPasswordLogin(app, collection)
+		form.SetIp("127.0.0.1")
+		form.Identity = "test@example.com"
+		form.Password = "invalid"
+		if _, err := form.Submit(); err == nil {
+			t.Fatalf("(%d) Expected invalid credentials error", i)
+		}
+	}
+
+	total := 0
+	app.Dao().LoginAttemptQuery().Select("count(*)").Row(&total)
+	if total != 0 {
+		t.Fatalf("Expected no stored login attempts, got %d", total)
+	}
+}
+
+func TestRecordPasswordLoginThrottleIdentity(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	enableLoginThrottle(app, 3, 0)
+
+	var lockoutEvent *core.LoginLockoutEvent
+	app.OnLoginLockout().Add(func(e *core.LoginLockoutEvent) error {
+		lockoutEvent = e
+		return nil
+	})
+
+	collection, _ := app.Dao().FindCollectionByNameOrId("users")
+
+	submit := func(identity string, password string) error {
+		form := forms.NewRecordPasswordLogin(app, collection)
+		form.SetIp("127.0.0.1")
+		form.Identity = identity
+		form.Password = password
+		_, err := form.Submit()
+		return err
+	}
+
+	// failures followed by a successful login should reset the identity counter
+	for i := 0; i < 2; i++ {
+		if err := submit("test@example.com", "invalid"); err == nil {
+			t.Fatalf("(%d) Expected invalid credentials error", i)
+		}
+	}
+	if err := submit("test@example.com", "1234567890"); err != nil {
+		t.Fatalf("Expected successful login, got %v", err)
+	}
+
+	// the identity should be matched case-insensitively
+	for i, identity := range []string{"test@example.com", "TEST@example.com", "test@EXAMPLE.com"} {
+		err := submit(identity, "invalid")
+
+		var lockedErr *forms.LoginLockedError
+		if err == nil || errors.As(err, &lockedErr) {
+			t.Fatalf("(%d) Expected invalid credentials error, got %v", i, err)
+		}
+	}
+
+	if app.EventCalls["OnLoginLockout"] != 1 {
+		t.Fatalf("Expected OnLoginLockout to be called once, got %d", app.EventCalls["OnLoginLockout"])
+	}
+	if lockoutEvent.Collection.Id != collection.Id || lockoutEvent.Ip != "127.0.0.1" || lockoutEvent.LoginAttempt.Kind != models.LoginAttemptKindIdentity {
+		t.Fatalf("Unexpected lockout event %v", lockoutEvent)
+	}
+
+	// locked even with valid credentials
+	err := submit("test@example.com", "1234567890")
+	var lockedErr *forms.LoginLockedError
+	if !errors.As(err, &lockedErr) {
+		t.Fatalf("Expected LoginLockedError, got %v", err)
+	}
+	if lockedErr.RetryAfter <= 50*time.Second || lockedErr.RetryAfter > 60*time.Second {
+		t.Fatalf("Expected ~60s RetryAfter, got %v", lockedErr.RetryAfter)
+	}
+
+	// other identities shouldn't be affected
+	if err := submit("test2@example.com", "invalid"); errors.As(err, &lockedErr) {
+		t.Fatalf("Didn't expect the other identity to be locked, got %v", err)
+	}
+
+	// expire the lock and trigger a second lockout (exponential backoff)
+	attempt := lockoutEvent.LoginAttempt
+	attempt.LockedUntil, _ = types.ParseDateTime(time.Now().Add(-time.Second))
+	if err := app.Dao().SaveLoginAttempt(attempt); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		submit("test@example.com", "invalid")
+	}
+
+	err = submit("test@example.com", "1234567890")
+	if !errors.As(err, &lockedErr) {
+		t.Fatalf("Expected LoginLockedError, got %v", err)
+	}
+	if lockedErr.RetryAfter <= 110*time.Second || lockedErr.RetryAfter > 120*time.Second {
+		t.Fatalf("Expected ~120s RetryAfter, got %v", lockedErr.RetryAfter)
+	}
+	if app.EventCalls["OnLoginLockout"] != 2 {
+		t.Fatalf("Expected OnLoginLockout to be called twice, got %d", app.EventCalls["OnLoginLockout"])
+	}
+}
+
+func TestRecordPasswordLoginThrottleIp(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	enableLoginThrottle(app, 0, 2)
+
+	collection, _ := app.Dao().FindCollectionByNameOrId("users")
+
+	submit := func(ip string, identity string, password string) error {
+		form := forms.NewRecordPasswordLogin(app, collection)
+		form.SetIp(ip)
+		form.Identity = identity
+		form.Password = password
+		_, err := form.Submit()
+		return err
+	}
+
+	submit("127.0.0.1", "test@example.com", "invalid")
+	submit("127.0.0.1", "test2@example.com", "invalid")
+
+	var lockedErr *forms.LoginLockedError
+
+	if err := submit("127.0.0.1", "test3@example.com", "1234567890"); !errors.As(err, &lockedErr) {
+		t.Fatalf("Expected LoginLockedError, got %v", err)
+	}
+
+	// other ips shouldn't be affected
+	if err := submit("127.0.0.2", "test@example.com", "1234567890"); err != nil {
+		t.Fatalf("Expected successful login from another ip, got %v", err)
+	}
+}
+
+func TestRecordPasswordLoginThrottleConcurrentFailures(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	enableLoginThrottle(app, 0, 100)
+
+	collection, _ := app.Dao().FindCollectionByNameOrId("users")
+
+	const total = 20
+
+	var wg sync.WaitGroup
+	wg.Add(total)
+
+	for i := 0; i < total; i++ {
+		go func() {
+			defer wg.Done()
+
+			form := forms.NewRecordPasswordLogin(app, collection)
+			form.SetIp("127.0.0.1")
+			form.Identity = "missing@example.com"
+			form.Password = "invalid"
+			form.Submit()
+		}()
+	}
+
+	wg.Wait()
+
+	attempt, err := app.Dao().FindLoginAttempt(models.LoginAttemptKindIp, "127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if attempt.Failures != total {
+		t.Fatalf("Expected %d failures, got %d", total, attempt.Failures)
+	}
+}
+
+func TestAdminLoginThrottle(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	enableLoginThrottle(app, 2, 0)
+
+	var lockoutEvent *core.LoginLockoutEvent
+	app.OnLoginLockout().Add(func(e *core.LoginLockoutEvent) error {
+		lockoutEvent = e
+		return nil
+	})
+
+	submit := func(password string) error {
+		form := forms.NewAdminLogin(app)
+		form.SetIp("127.0.0.1")
+		form.Identity = "test@example.com"
+		form.Password = password
+		_, err := form.Submit()
+		return err
+	}
+
+	submit("invalid")
+	submit("invalid")
+
+	if lockoutEvent == nil || lockoutEvent.Collection != nil || lockoutEvent.Identity != "test@example.com" {
+		t.Fatalf("Unexpected lockout event %v", lockoutEvent)
+	}
+
+	var lockedErr *forms.LoginLockedError
+	if err := submit("1234567890"); !errors.As(err, &lockedErr) {
+		t.Fatalf("Expected LoginLockedError, got %v", err)
+	}
+
+	// the admins identities shouldn't share counters with the auth records
+	collection, _ := app.Dao().FindCollectionByNameOrId("users")
+	recordForm := forms.NewRecordPasswordLogin(app, collection)
+	recordForm.Identity = "test@example.com"
+	recordForm.Password = "1234567890"
+	if _, err := recordForm.Submit(); err != nil {
+		t.Fatalf("Expected successful record login, got %v", err)
+	}
+}
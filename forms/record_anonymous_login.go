@@ -0,0 +1,108 @@
+package forms
+
+import (
+	"errors"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+// RecordAnonymousLogin is an auth record anonymous (aka. guest) login form.
+//
+// Each submit creates a new anonymous auth record that could be
+// later upgraded with email/password (see [RecordAnonymousUpgrade])
+// or OAuth2 credentials (see [RecordOAuth2Login]).
+type RecordAnonymousLogin struct {
+	app        core.App
+	dao        *daos.Dao
+	collection *models.Collection
+
+	// Additional data that will be used for creating the anonymous auth record
+	// (the email, password and verified auth fields are ignored).
+	CreateData map[string]any `form:"createData" json:"createData"`
+}
+
+// NewRecordAnonymousLogin creates a new [RecordAnonymousLogin] form
+// initialized with from the provided [core.App] and [models.Collection] instances.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewRecordAnonymousLogin(app core.App, collection *models.Collection) *RecordAnonymousLogin {
+	return &RecordAnonymousLogin{
+		app:        app,
+		dao:        app.Dao(),
+		collection: collection,
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *RecordAnonymousLogin) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Submit creates a new anonymous auth record.
+//
+// You can intercept/modify the create form by setting the optional beforeCreateFuncs argument.
+//
+// On success returns the created auth record model.
+func (form *RecordAnonymousLogin) Submit(
+	beforeCreateFuncs ...func(createForm *RecordUpsert, authRecord *models.Record) error,
+) (*models.Record, error) {
+	options := form.collection.AuthOptions()
+
+	if !options.AllowAnonymousAuth {
+		return nil, errors.New("Anonymous authentication is not allowed for the auth collection.")
+	}
+
+	if options.RequireEmail {
+		return nil, errors.New("Anonymous authentication is not supported for auth collections with required email.")
+	}
+
+	authRecord := models.NewRecord(form.collection)
+	authRecord.RefreshId()
+	authRecord.MarkAsNew()
+
+	saveErr := form.dao.RunInTransaction(func(txDao *daos.Dao) error {
+		createForm := NewRecordUpsert(form.app, authRecord)
+		createForm.SetFullManageAccess(true)
+		createForm.SetDao(txDao)
+
+		// load custom data
+		if err := createForm.LoadData(form.CreateData); err != nil {
+			return err
+		}
+
+		// the anonymous records don't have credentials until upgraded
+		createForm.Email = ""
+		createForm.EmailVisibility = false
+		createForm.Verified = false
+		createForm.Password = security.RandomString(30)
+		createForm.PasswordConfirm = createForm.Password
+
+		for _, f := range beforeCreateFuncs {
+			if f == nil {
+				continue
+			}
+			if err := f(createForm, authRecord); err != nil {
+				return err
+			}
+		}
+
+		if err := createForm.Submit(); err != nil {
+			return err
+		}
+
+		return txDao.SaveAnonymousAuth(&models.AnonymousAuth{
+			CollectionId: form.collection.Id,
+			RecordId:     authRecord.Id,
+		})
+	})
+
+	if saveErr != nil {
+		return nil, saveErr
+	}
+
+	return authRecord, nil
+}
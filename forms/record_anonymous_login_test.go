@@ -0,0 +1,64 @@
+package forms_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestRecordAnonymousLoginSubmit(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	// disabled anonymous auth
+	users, _ := app.Dao().FindCollectionByNameOrId("users")
+	if _, err := forms.NewRecordAnonymousLogin(app, users).Submit(); err == nil {
+		t.Fatal("Expected error for disabled anonymous auth")
+	}
+
+	// required email
+	clients, _ := app.Dao().FindCollectionByNameOrId("clients")
+	clientsOptions := clients.AuthOptions()
+	clientsOptions.AllowAnonymousAuth = true
+	clients.SetOptions(clientsOptions)
+	if _, err := forms.NewRecordAnonymousLogin(app, clients).Submit(); err == nil {
+		t.Fatal("Expected error for auth collection with required email")
+	}
+
+	usersOptions := users.AuthOptions()
+	usersOptions.AllowAnonymousAuth = true
+	users.SetOptions(usersOptions)
+	if err := daos.New(app.Dao().DB()).SaveCollection(users); err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewRecordAnonymousLogin(app, users)
+	form.CreateData = map[string]any{
+		"name":     "guest",
+		"email":    "guest@example.com",
+		"verified": true,
+	}
+
+	record, err := form.Submit()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if record.Id == "" || record.Username() == "" {
+		t.Fatalf("Expected the anonymous record to have id and username, got %v", record)
+	}
+
+	if record.Email() != "" || record.Verified() {
+		t.Fatalf("Expected the anonymous record to not have email and to be unverified, got %q (%v)", record.Email(), record.Verified())
+	}
+
+	if v := record.GetString("name"); v != "guest" {
+		t.Fatalf("Expected the create data name to be loaded, got %q", v)
+	}
+
+	if !app.Dao().IsAnonymousAuthRecord(record) {
+		t.Fatal("Expected the record to be marked as anonymous")
+	}
+}
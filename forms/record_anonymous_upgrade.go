@@ -0,0 +1,98 @@
+package forms
+
+import (
+	"errors"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/forms/validators"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+)
+
+// RecordAnonymousUpgrade is an anonymous auth record upgrade form.
+//
+// It assigns email/password credentials to the anonymous auth record
+// and converts it to a regular one (preserving its id and data).
+type RecordAnonymousUpgrade struct {
+	app    core.App
+	dao    *daos.Dao
+	record *models.Record
+
+	Email           string `form:"email" json:"email"`
+	Password        string `form:"password" json:"password"`
+	PasswordConfirm string `form:"passwordConfirm" json:"passwordConfirm"`
+
+	// Username is optional and when empty the current
+	// (autogenerated) auth record username is preserved.
+	Username string `form:"username" json:"username"`
+}
+
+// NewRecordAnonymousUpgrade creates a new [RecordAnonymousUpgrade] form
+// initialized with from the provided [core.App] and anonymous [models.Record] instances.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewRecordAnonymousUpgrade(app core.App, record *models.Record) *RecordAnonymousUpgrade {
+	return &RecordAnonymousUpgrade{
+		app:    app,
+		dao:    app.Dao(),
+		record: record,
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *RecordAnonymousUpgrade) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+//
+// The email uniqueness, domain and password length checks
+// are performed by the [RecordUpsert] form on submit.
+func (form *RecordAnonymousUpgrade) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Email, validation.Required, validation.Length(1, 255), is.EmailFormat),
+		validation.Field(&form.Password, validation.Required, validation.Length(1, 72)),
+		validation.Field(&form.PasswordConfirm, validation.Required, validation.By(validators.Compare(form.Password))),
+	)
+}
+
+// Submit validates and submits the form.
+func (form *RecordAnonymousUpgrade) Submit() error {
+	if err := form.Validate(); err != nil {
+		return err
+	}
+
+	anonymousAuth, err := form.dao.FindAnonymousAuthByRecord(form.record)
+	if err != nil {
+		return errors.New("The auth record is not anonymous.")
+	}
+
+	return form.dao.RunInTransaction(func(txDao *daos.Dao) error {
+		upsertForm := NewRecordUpsert(form.app, form.record)
+		upsertForm.SetFullManageAccess(true)
+		upsertForm.SetDao(txDao)
+
+		data := map[string]any{
+			schema.FieldNameEmail: form.Email,
+			"password":            form.Password,
+			"passwordConfirm":     form.PasswordConfirm,
+		}
+		if form.Username != "" {
+			data[schema.FieldNameUsername] = form.Username
+		}
+
+		if err := upsertForm.LoadData(data); err != nil {
+			return err
+		}
+
+		if err := upsertForm.Submit(); err != nil {
+			return err
+		}
+
+		return txDao.DeleteAnonymousAuth(anonymousAuth)
+	})
+}
@@ -0,0 +1,137 @@
+package forms_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestRecordAnonymousUpgradeSubmit(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		userId         string
+		anonymous      bool
+		data           string
+		expectedErrors []string
+	}{
+		{
+			"empty data",
+			"bgs820n361vj1qd",
+			true,
+			`{}`,
+			[]string{"email", "password", "passwordConfirm"},
+		},
+		{
+			"invalid data",
+			"bgs820n361vj1qd",
+			true,
+			`{"email":"invalid","password":"1234567890","passwordConfirm":"123"}`,
+			[]string{"email", "passwordConfirm"},
+		},
+		{
+			"not anonymous auth record",
+			"bgs820n361vj1qd",
+			false,
+			`{"email":"new@example.com","password":"1234567890","passwordConfirm":"1234567890"}`,
+			[]string{},
+		},
+		{
+			"existing email and short password",
+			"bgs820n361vj1qd",
+			true,
+			`{"email":"test2@example.com","password":"123","passwordConfirm":"123"}`,
+			[]string{"email", "password"},
+		},
+		{
+			"valid data",
+			"bgs820n361vj1qd",
+			true,
+			`{"email":"new@example.com","password":"1234567890","passwordConfirm":"1234567890","username":"new_username"}`,
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		app, _ := tests.NewTestApp()
+
+		record, err := app.Dao().FindRecordById("users", s.userId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		oldTokenKey := record.TokenKey()
+
+		if s.anonymous {
+			if err := app.Dao().SaveAnonymousAuth(&models.AnonymousAuth{
+				CollectionId: record.Collection().Id,
+				RecordId:     record.Id,
+			}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		form := forms.NewRecordAnonymousUpgrade(app, record)
+
+		if err := json.Unmarshal([]byte(s.data), form); err != nil {
+			t.Fatalf("[%s] Failed to load form data: %v", s.name, err)
+		}
+
+		submitErr := form.Submit()
+
+		if !s.anonymous {
+			if submitErr == nil {
+				t.Errorf("[%s] Expected error for not anonymous auth record", s.name)
+			}
+			app.Cleanup()
+			continue
+		}
+
+		// parse errors
+		errs, ok := submitErr.(validation.Errors)
+		if !ok && submitErr != nil {
+			t.Errorf("[%s] Failed to parse errors %v", s.name, submitErr)
+			app.Cleanup()
+			continue
+		}
+
+		// check errors
+		if len(errs) != len(s.expectedErrors) {
+			t.Errorf("[%s] Expected error keys %v, got %v", s.name, s.expectedErrors, errs)
+		}
+		for _, k := range s.expectedErrors {
+			if _, ok := errs[k]; !ok {
+				t.Errorf("[%s] Missing expected error key %q in %v", s.name, k, errs)
+			}
+		}
+
+		if len(s.expectedErrors) > 0 {
+			if !app.Dao().IsAnonymousAuthRecord(record) {
+				t.Errorf("[%s] Expected the record to remain anonymous", s.name)
+			}
+			app.Cleanup()
+			continue
+		}
+
+		upgraded, _ := app.Dao().FindRecordById("users", s.userId)
+		if upgraded.Email() != form.Email {
+			t.Errorf("[%s] Expected email %q, got %q", s.name, form.Email, upgraded.Email())
+		}
+		if upgraded.Username() != form.Username {
+			t.Errorf("[%s] Expected username %q, got %q", s.name, form.Username, upgraded.Username())
+		}
+		if !upgraded.ValidatePassword(form.Password) {
+			t.Errorf("[%s] Expected the password to be changed", s.name)
+		}
+		if upgraded.TokenKey() == oldTokenKey {
+			t.Errorf("[%s] Expected the token key to be refreshed", s.name)
+		}
+		if app.Dao().IsAnonymousAuthRecord(upgraded) {
+			t.Errorf("[%s] Expected the record to be no longer anonymous", s.name)
+		}
+
+		app.Cleanup()
+	}
+}
@@ -0,0 +1,150 @@
+package forms
+
+import (
+	"errors"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+// RecordLoginLinkConfirm is an auth record passwordless login link confirmation form.
+type RecordLoginLinkConfirm struct {
+	app        core.App
+	collection *models.Collection
+	dao        *daos.Dao
+
+	Token string `form:"token" json:"token"`
+}
+
+// NewRecordLoginLinkConfirm creates a new [RecordLoginLinkConfirm]
+// form initialized with from the provided [core.App] instance.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewRecordLoginLinkConfirm(app core.App, collection *models.Collection) *RecordLoginLinkConfirm {
+	return &RecordLoginLinkConfirm{
+		app:        app,
+		dao:        app.Dao(),
+		collection: collection,
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *RecordLoginLinkConfirm) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+func (form *RecordLoginLinkConfirm) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Token, validation.Required, validation.By(form.checkToken)),
+	)
+}
+
+func (form *RecordLoginLinkConfirm) checkToken(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	record, link, err := form.findRecordAndLink(v)
+	if err != nil || record == nil || link == nil {
+		return validation.NewError("validation_invalid_token", "Invalid or expired token.")
+	}
+
+	if record.Collection().Id != form.collection.Id {
+		return validation.NewError("validation_token_collection_mismatch", "The provided token is for different auth collection.")
+	}
+
+	return nil
+}
+
+// findRecordAndLink returns the auth record and the still
+// unused login link associated with the provided token.
+func (form *RecordLoginLinkConfirm) findRecordAndLink(token string) (*models.Record, *models.LoginLink, error) {
+	record, err := form.dao.FindAuthRecordByToken(
+		token,
+		form.app.Settings().RecordLoginLinkToken.Secret,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	claims, _ := security.ParseUnverifiedJWT(token)
+	email, _ := claims["email"].(string)
+	linkId, _ := claims["linkId"].(string)
+
+	// the email has been changed after the link was sent
+	if email == "" || email != record.Email() {
+		return nil, nil, errors.New("The login link email doesn't match with the auth record email.")
+	}
+
+	link, err := form.dao.FindLoginLinkById(linkId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if link.CollectionId != record.Collection().Id || link.RecordId != record.Id {
+		return nil, nil, errors.New("The login link is for a different auth record.")
+	}
+
+	return record, link, nil
+}
+
+// Submit validates and submits the form.
+// On success returns the auth record associated to `form.Token`
+// and invalidates the used login link.
+//
+// Because the login link proves the ownership of the email address,
+// the auth record is also marked as verified (if not already).
+//
+// You can optionally provide a list of InterceptorWithRecordFunc to
+// further modify the form behavior before persisting it.
+func (form *RecordLoginLinkConfirm) Submit(interceptors ...InterceptorWithRecordFunc) (*models.Record, error) {
+	if err := form.Validate(); err != nil {
+		return nil, err
+	}
+
+	if !form.collection.AuthOptions().AllowLoginLinkAuth {
+		return nil, errors.New("Login link authentication is not allowed for the auth collection.")
+	}
+
+	authRecord, link, err := form.findRecordAndLink(form.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	interceptorsErr := runInterceptorsWithRecord(authRecord, func(m *models.Record) error {
+		return form.dao.RunInTransaction(func(txDao *daos.Dao) error {
+			// the login link could be used only once
+			if err := txDao.ConsumeLoginLink(link); err != nil {
+				if errors.Is(err, daos.ErrLoginLinkUnavailable) {
+					return validation.Errors{"token": validation.NewError(
+						"validation_invalid_token",
+						"Invalid or expired token.",
+					)}
+				}
+				return err
+			}
+
+			if m.Verified() {
+				return nil
+			}
+
+			if err := m.SetVerified(true); err != nil {
+				return err
+			}
+
+			return txDao.SaveRecord(m)
+		})
+	}, interceptors...)
+
+	if interceptorsErr != nil {
+		return nil, interceptorsErr
+	}
+
+	return authRecord, nil
+}
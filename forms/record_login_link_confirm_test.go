@@ -0,0 +1,261 @@
+package forms_test
+
+import (
+	"errors"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tokens"
+)
+
+// newTestLoginLinkToken creates a new login link for the specified
+// auth record and returns its login link token.
+func newTestLoginLinkToken(t *testing.T, app *tests.TestApp, authRecord *models.Record) string {
+	link := &models.LoginLink{
+		CollectionId: authRecord.Collection().Id,
+		RecordId:     authRecord.Id,
+	}
+	if err := app.Dao().SaveLoginLink(link); err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := tokens.NewRecordLoginLinkToken(app, authRecord, link)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+func TestRecordLoginLinkConfirmValidateAndSubmit(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	authCollection, err := testApp.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := authCollection.AuthOptions()
+	options.AllowLoginLinkAuth = true
+	authCollection.SetOptions(options)
+
+	authRecord, err := testApp.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clientRecord, err := testApp.Dao().FindAuthRecordByEmail("clients", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validToken := newTestLoginLinkToken(t, testApp, authRecord)
+
+	// token for a deleted (aka. already used) login link
+	deletedLinkToken := newTestLoginLinkToken(t, testApp, authRecord)
+	lastLink, _ := testApp.Dao().FindLastLoginLinkByRecord(authRecord)
+	if err := testApp.Dao().DeleteLoginLink(lastLink); err != nil {
+		t.Fatal(err)
+	}
+
+	// valid token signed with a different secret
+	verifyToken, err := tokens.NewRecordVerifyToken(testApp, authRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name           string
+		token          string
+		expectedErrors []string
+	}{
+		{"empty token", "", []string{"token"}},
+		{"invalid token", "invalid", []string{"token"}},
+		{"token with different secret", verifyToken, []string{"token"}},
+		{"already used login link", deletedLinkToken, []string{"token"}},
+		{"different auth collection", newTestLoginLinkToken(t, testApp, clientRecord), []string{"token"}},
+		{"valid token", validToken, []string{}},
+		{"reused valid token", validToken, []string{"token"}},
+	}
+
+	for _, s := range scenarios {
+		form := forms.NewRecordLoginLinkConfirm(testApp, authCollection)
+		form.Token = s.token
+
+		interceptorCalls := 0
+		interceptor := func(next forms.InterceptorWithRecordNextFunc) forms.InterceptorWithRecordNextFunc {
+			return func(r *models.Record) error {
+				interceptorCalls++
+				return next(r)
+			}
+		}
+
+		record, submitErr := form.Submit(interceptor)
+
+		// parse errors
+		errs, ok := submitErr.(validation.Errors)
+		if !ok && submitErr != nil {
+			t.Errorf("[%s] Failed to parse errors %v", s.name, submitErr)
+			continue
+		}
+
+		// check interceptor calls
+		expectInterceptorCalls := 1
+		if len(s.expectedErrors) > 0 {
+			expectInterceptorCalls = 0
+		}
+		if interceptorCalls != expectInterceptorCalls {
+			t.Errorf("[%s] Expected interceptor to be called %d, got %d", s.name, expectInterceptorCalls, interceptorCalls)
+		}
+
+		// check errors
+		if len(errs) > len(s.expectedErrors) {
+			t.Errorf("[%s] Expected error keys %v, got %v", s.name, s.expectedErrors, errs)
+		}
+		for _, k := range s.expectedErrors {
+			if _, ok := errs[k]; !ok {
+				t.Errorf("[%s] Missing expected error key %q in %v", s.name, k, errs)
+			}
+		}
+
+		if len(errs) > 0 || len(s.expectedErrors) > 0 {
+			continue
+		}
+
+		if record.Id != authRecord.Id {
+			t.Errorf("[%s] Expected record with id %s, got %v", s.name, authRecord.Id, record)
+		}
+
+		if !record.Verified() {
+			t.Errorf("[%s] Expected the record to be marked as verified", s.name)
+		}
+	}
+}
+
+func TestRecordLoginLinkConfirmDisabled(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	authCollection, err := testApp.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authRecord, err := testApp.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewRecordLoginLinkConfirm(testApp, authCollection)
+	form.Token = newTestLoginLinkToken(t, testApp, authRecord)
+
+	if _, err := form.Submit(); err == nil {
+		t.Fatal("Expected error for disabled login link auth")
+	}
+}
+
+func TestRecordLoginLinkConfirmConcurrentSubmit(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	authCollection, err := testApp.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := authCollection.AuthOptions()
+	options.AllowLoginLinkAuth = true
+	authCollection.SetOptions(options)
+
+	authRecord, err := testApp.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := newTestLoginLinkToken(t, testApp, authRecord)
+
+	form1 := forms.NewRecordLoginLinkConfirm(testApp, authCollection)
+	form1.Token = token
+
+	form2 := forms.NewRecordLoginLinkConfirm(testApp, authCollection)
+	form2.Token = token
+
+	// confirm the same link with form2 after form1 has been validated
+	// but before it has consumed the link
+	var form2Err error
+	interceptor := func(next forms.InterceptorWithRecordNextFunc) forms.InterceptorWithRecordNextFunc {
+		return func(r *models.Record) error {
+			_, form2Err = form2.Submit()
+			return next(r)
+		}
+	}
+
+	_, form1Err := form1.Submit(interceptor)
+
+	if form2Err != nil {
+		t.Fatalf("Expected the first confirmation to succeed, got %v", form2Err)
+	}
+
+	errs, ok := form1Err.(validation.Errors)
+	if !ok || errs["token"] == nil {
+		t.Fatalf("Expected token validation error for the second confirmation, got %v", form1Err)
+	}
+}
+
+func TestRecordLoginLinkConfirmInterceptors(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	authCollection, err := testApp.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := authCollection.AuthOptions()
+	options.AllowLoginLinkAuth = true
+	authCollection.SetOptions(options)
+
+	authRecord, err := testApp.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewRecordLoginLinkConfirm(testApp, authCollection)
+	form.Token = newTestLoginLinkToken(t, testApp, authRecord)
+	testErr := errors.New("test_error")
+
+	interceptor1Called := false
+	interceptor1 := func(next forms.InterceptorWithRecordNextFunc) forms.InterceptorWithRecordNextFunc {
+		return func(record *models.Record) error {
+			interceptor1Called = true
+			return next(record)
+		}
+	}
+
+	interceptor2Called := false
+	interceptor2 := func(next forms.InterceptorWithRecordNextFunc) forms.InterceptorWithRecordNextFunc {
+		return func(record *models.Record) error {
+			interceptor2Called = true
+			return testErr
+		}
+	}
+
+	_, submitErr := form.Submit(interceptor1, interceptor2)
+	if submitErr != testErr {
+		t.Fatalf("Expected submitError %v, got %v", testErr, submitErr)
+	}
+
+	if !interceptor1Called {
+		t.Fatalf("Expected interceptor1 to be called")
+	}
+
+	if !interceptor2Called {
+		t.Fatalf("Expected interceptor2 to be called")
+	}
+
+	// the login link shouldn't be used on failure
+	if _, err := testApp.Dao().FindLastLoginLinkByRecord(authRecord); err != nil {
+		t.Fatalf("Expected the login link to remain, got %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package forms
+
+import (
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/mails"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// RecordLoginLinkRequest is an auth record passwordless login link request form.
+type RecordLoginLinkRequest struct {
+	app             core.App
+	dao             *daos.Dao
+	collection      *models.Collection
+	resendThreshold float64 // in seconds
+
+	Email string `form:"email" json:"email"`
+}
+
+// NewRecordLoginLinkRequest creates a new [RecordLoginLinkRequest]
+// form initialized with from the provided [core.App] instance.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewRecordLoginLinkRequest(app core.App, collection *models.Collection) *RecordLoginLinkRequest {
+	return &RecordLoginLinkRequest{
+		app:             app,
+		dao:             app.Dao(),
+		collection:      collection,
+		resendThreshold: 120, // 2 min
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *RecordLoginLinkRequest) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+//
+// This method doesn't checks whether auth record with `form.Email` exists (this is done on Submit).
+func (form *RecordLoginLinkRequest) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(
+			&form.Email,
+			validation.Required,
+			validation.Length(1, 255),
+			is.EmailFormat,
+		),
+	)
+}
+
+// Submit validates and submits the form.
+// On success, replaces the previous login links of the `form.Email`
+// auth record with a new one and sends it as a login link email.
+//
+// You can optionally provide a list of InterceptorWithRecordFunc to
+// further modify the form behavior before persisting it.
+func (form *RecordLoginLinkRequest) Submit(interceptors ...InterceptorWithRecordFunc) error {
+	if err := form.Validate(); err != nil {
+		return err
+	}
+
+	if !form.collection.AuthOptions().AllowLoginLinkAuth {
+		return errors.New("Login link authentication is not allowed for the auth collection.")
+	}
+
+	authRecord, err := form.dao.FindAuthRecordByEmail(form.collection.Id, form.Email)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	lastLink, _ := form.dao.FindLastLoginLinkByRecord(authRecord)
+	if lastLink != nil && now.Sub(lastLink.Created.Time()).Seconds() < form.resendThreshold {
+		return errors.New("You've already requested a login link.")
+	}
+
+	return runInterceptorsWithRecord(authRecord, func(m *models.Record) error {
+		link := &models.LoginLink{
+			CollectionId: m.Collection().Id,
+			RecordId:     m.Id,
+		}
+
+		saveErr := form.dao.RunInTransaction(func(txDao *daos.Dao) error {
+			// only the latest requested link is valid
+			if err := txDao.DeleteAuthRecordLoginLinks(m); err != nil {
+				return err
+			}
+
+			return txDao.SaveLoginLink(link)
+		})
+		if saveErr != nil {
+			return saveErr
+		}
+
+		return mails.SendRecordLoginLink(form.app, m, link)
+	}, interceptors...)
+}
@@ -0,0 +1,121 @@
+package forms_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestRecordLoginLinkRequestSubmit(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	authCollection, err := testApp.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// disabled login link auth
+	disabledForm := forms.NewRecordLoginLinkRequest(testApp, authCollection)
+	disabledForm.Email = "test@example.com"
+	if err := disabledForm.Submit(); err == nil {
+		t.Fatal("Expected error for disabled login link auth")
+	}
+
+	options := authCollection.AuthOptions()
+	options.AllowLoginLinkAuth = true
+	authCollection.SetOptions(options)
+
+	scenarios := []struct {
+		jsonData    string
+		expectError bool
+	}{
+		// empty field (Validate call check)
+		{
+			`{"email":""}`,
+			true,
+		},
+		// invalid email field (Validate call check)
+		{
+			`{"email":"invalid"}`,
+			true,
+		},
+		// nonexisting user
+		{
+			`{"email":"missing@example.com"}`,
+			true,
+		},
+		// existing user
+		{
+			`{"email":"test@example.com"}`,
+			false,
+		},
+		// existing user - reached send threshod
+		{
+			`{"email":"test@example.com"}`,
+			true,
+		},
+	}
+
+	for i, s := range scenarios {
+		testApp.TestMailer.TotalSend = 0 // reset
+		form := forms.NewRecordLoginLinkRequest(testApp, authCollection)
+
+		// load data
+		loadErr := json.Unmarshal([]byte(s.jsonData), form)
+		if loadErr != nil {
+			t.Errorf("(%d) Failed to load form data: %v", i, loadErr)
+			continue
+		}
+
+		interceptorCalls := 0
+		interceptor := func(next forms.InterceptorWithRecordNextFunc) forms.InterceptorWithRecordNextFunc {
+			return func(r *models.Record) error {
+				interceptorCalls++
+				return next(r)
+			}
+		}
+
+		err := form.Submit(interceptor)
+
+		// check interceptor calls
+		expectInterceptorCalls := 1
+		if s.expectError {
+			expectInterceptorCalls = 0
+		}
+		if interceptorCalls != expectInterceptorCalls {
+			t.Errorf("[%d] Expected interceptor to be called %d, got %d", i, expectInterceptorCalls, interceptorCalls)
+		}
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr to be %v, got %v (%v)", i, s.expectError, hasErr, err)
+		}
+
+		expectedMails := 1
+		if s.expectError {
+			expectedMails = 0
+		}
+		if testApp.TestMailer.TotalSend != expectedMails {
+			t.Errorf("(%d) Expected %d mail(s) to be sent, got %d", i, expectedMails, testApp.TestMailer.TotalSend)
+		}
+
+		if s.expectError {
+			continue
+		}
+
+		// check whether the login link was created
+		user, err := testApp.Dao().FindAuthRecordByEmail(authCollection.Id, form.Email)
+		if err != nil {
+			t.Errorf("(%d) Expected user with email %q to exist, got nil", i, form.Email)
+			continue
+		}
+
+		if _, err := testApp.Dao().FindLastLoginLinkByRecord(user); err != nil {
+			t.Errorf("(%d) Expected a login link to be created, got %v", i, err)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package forms
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
@@ -10,7 +11,7 @@ import (
 	"github.com/pocketbase/pocketbase/daos"
 	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/tools/auth"
-	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/spf13/cast"
 	"golang.org/x/oauth2"
 )
 
@@ -39,6 +40,10 @@ type RecordOAuth2Login struct {
 	// Additional data that will be used for creating a new auth record
 	// if an existing OAuth2 account doesn't exist.
 	CreateData map[string]any `form:"createData" json:"createData"`
+
+	// Optional user data relayed from the provider authorization response
+	// (eg. the Apple `user` param that is sent only with the first authorization).
+	User map[string]any `form:"user" json:"user"`
 }
 
 // NewRecordOAuth2Login creates a new [RecordOAuth2Login] form with
@@ -89,6 +94,8 @@ func (form *RecordOAuth2Login) checkProviderName(value any) error {
 // based on the fetched OAuth2 profile data via a local [RecordUpsert] form.
 // You can intercept/modify the create form by setting the optional beforeCreateFuncs argument.
 //
+// The provider config mapped claims and roles are synced with each login.
+//
 // On success returns the authorized record model and the fetched provider's data.
 func (form *RecordOAuth2Login) Submit(
 	beforeCreateFuncs ...func(createForm *RecordUpsert, authRecord *models.Record, authUser *auth.AuthUser) error,
@@ -114,6 +121,10 @@ func (form *RecordOAuth2Login) Submit(
 
 	provider.SetRedirectUrl(form.RedirectUrl)
 
+	if p, ok := provider.(interface{ SetRelayedUser(user map[string]any) }); ok && len(form.User) > 0 {
+		p.SetRelayedUser(form.User)
+	}
+
 	// fetch token
 	token, err := provider.FetchToken(
 		form.Code,
@@ -129,106 +140,161 @@ func (form *RecordOAuth2Login) Submit(
 		return nil, nil, err
 	}
 
-	var authRecord *models.Record
-
-	// check for existing relation with the auth record
-	rel, _ := form.dao.FindExternalAuthByProvider(form.Provider, authUser.Id)
-	switch {
-	case rel != nil:
-		authRecord, err = form.dao.FindRecordById(form.collection.Id, rel.RecordId)
-		if err != nil {
-			return nil, authUser, err
-		}
-	case form.loggedAuthRecord != nil && form.loggedAuthRecord.Collection().Id == form.collection.Id:
-		// fallback to the logged auth record (if any)
-		authRecord = form.loggedAuthRecord
-	case authUser.Email != "":
-		// look for an existing auth record by the external auth record's email
-		authRecord, _ = form.dao.FindAuthRecordByEmail(form.collection.Id, authUser.Email)
-	}
-
-	saveErr := form.dao.RunInTransaction(func(txDao *daos.Dao) error {
-		if authRecord == nil {
-			authRecord = models.NewRecord(form.collection)
-			authRecord.RefreshId()
-			authRecord.MarkAsNew()
-			createForm := NewRecordUpsert(form.app, authRecord)
-			createForm.SetFullManageAccess(true)
-			createForm.SetDao(txDao)
-			if authUser.Username != "" && usernameRegex.MatchString(authUser.Username) {
-				createForm.Username = form.dao.SuggestUniqueAuthRecordUsername(form.collection.Id, authUser.Username)
+	login := &externalAuthLogin{
+		app:                form.app,
+		dao:                form.dao,
+		collection:         form.collection,
+		loggedAuthRecord:   form.loggedAuthRecord,
+		provider:           form.Provider,
+		providerId:         authUser.Id,
+		email:              authUser.Email,
+		verifyWithoutEmail: true,
+		username:           authUser.Username,
+		mappedData: mapAttributesData(
+			form.collection,
+			providerConfig.FieldsMapping,
+			claimsAttributes(authUser.RawUser, providerConfig.FieldsMapping),
+		),
+		createData: form.CreateData,
+		afterSave: func(txDao *daos.Dao, authRecord *models.Record) error {
+			// the linked OAuth2 account upgrades the anonymous auth record (if it is one)
+			if err := txDao.DeleteAuthRecordAnonymousAuth(authRecord); err != nil {
+				return err
 			}
 
-			// load custom data
-			createForm.LoadData(form.CreateData)
-
-			// load the OAuth2 profile data as fallback
-			if createForm.Email == "" {
-				createForm.Email = authUser.Email
-			}
-			createForm.Verified = false
-			if createForm.Email == authUser.Email {
-				// mark as verified as long as it matches the OAuth2 data (even if the email is empty)
-				createForm.Verified = true
-			}
-			if createForm.Password == "" {
-				createForm.Password = security.RandomString(30)
-				createForm.PasswordConfirm = createForm.Password
+			if providerConfig.RolesClaim == "" {
+				return nil
 			}
 
-			for _, f := range beforeCreateFuncs {
-				if f == nil {
-					continue
-				}
-				if err := f(createForm, authRecord, authUser); err != nil {
-					return err
-				}
+			roles, ok := claimValue(authUser.RawUser, providerConfig.RolesClaim)
+			if !ok {
+				return nil
 			}
 
-			// create the new auth record
-			if err := createForm.Submit(); err != nil {
-				return err
+			return syncAuthRecordRoles(txDao, authRecord, claimStrings(roles))
+		},
+	}
+
+	authRecord, err := login.submit(func(createForm *RecordUpsert, authRecord *models.Record) error {
+		for _, f := range beforeCreateFuncs {
+			if f == nil {
+				continue
 			}
-		} else {
-			// update the existing auth record empty email if the authUser has one
-			// (this is in case previously the auth record was created
-			// with an OAuth2 provider that didn't return an email address)
-			if authRecord.Email() == "" && authUser.Email != "" {
-				authRecord.SetEmail(authUser.Email)
-				if err := txDao.SaveRecord(authRecord); err != nil {
-					return err
-				}
+			if err := f(createForm, authRecord, authUser); err != nil {
+				return err
 			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, authUser, err
+	}
 
-			// update the existing auth record verified state
-			// (only if the auth record doesn't have an email or the auth record email match with the one in authUser)
-			if !authRecord.Verified() && (authRecord.Email() == "" || authRecord.Email() == authUser.Email) {
-				authRecord.SetVerified(true)
-				if err := txDao.SaveRecord(authRecord); err != nil {
-					return err
-				}
-			}
+	return authRecord, authUser, nil
+}
+
+// claimsAttributes returns the string values of the mapped
+// OAuth2 user claims in the format expected by [mapAttributesData]
+// (aka. an array claim is returned as it is and all other as single item).
+func claimsAttributes(claims map[string]any, fieldsMapping map[string]string) map[string][]string {
+	result := map[string][]string{}
+
+	for claim := range fieldsMapping {
+		value, ok := claimValue(claims, claim)
+		if !ok {
+			continue
 		}
 
-		// create ExternalAuth relation if missing
-		if rel == nil {
-			rel = &models.ExternalAuth{
-				CollectionId: authRecord.Collection().Id,
-				RecordId:     authRecord.Id,
-				Provider:     form.Provider,
-				ProviderId:   authUser.Id,
-			}
-			if err := txDao.SaveExternalAuth(rel); err != nil {
-				return err
+		items, isArray := value.([]any)
+		if !isArray {
+			items = []any{value}
+		}
+
+		values := make([]string, 0, len(items))
+		for _, item := range items {
+			if item != nil {
+				values = append(values, cast.ToString(item))
 			}
 		}
 
+		result[claim] = values
+	}
+
+	return result
+}
+
+// claimValue returns the value of the specified claim
+// (nested claims could be accessed with dot-notation).
+func claimValue(claims map[string]any, path string) (any, bool) {
+	if v, ok := claims[path]; ok {
+		return v, v != nil
+	}
+
+	parts := strings.SplitN(path, ".", 2)
+	if len(parts) != 2 {
+		return nil, false
+	}
+
+	nested, ok := claims[parts[0]].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+
+	return claimValue(nested, parts[1])
+}
+
+// claimStrings normalizes a string or array claim value into a slice of strings.
+func claimStrings(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return strings.Fields(v)
+	case []any:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if str, ok := item.(string); ok && str != "" {
+				result = append(result, str)
+			}
+		}
+		return result
+	default:
 		return nil
-	})
+	}
+}
 
-	if saveErr != nil {
-		return nil, authUser, saveErr
+// syncAuthRecordRoles assigns the existing roles with the provided
+// names to the auth record and unassigns all of its other roles.
+//
+// Unknown role names are ignored.
+func syncAuthRecordRoles(dao *daos.Dao, authRecord *models.Record, names []string) error {
+	current, err := dao.FindAuthRecordRoles(authRecord)
+	if err != nil {
+		return err
 	}
 
-	return authRecord, authUser, nil
+	keep := map[string]bool{}
+
+	for _, name := range names {
+		role, err := dao.FindRoleByName(name)
+		if err != nil {
+			continue // missing role
+		}
+
+		keep[role.Id] = true
+
+		if err := dao.AssignAuthRecordRole(authRecord, role); err != nil {
+			return err
+		}
+	}
+
+	for _, role := range current {
+		if keep[role.Id] {
+			continue
+		}
+
+		if err := dao.UnassignAuthRecordRole(authRecord, role); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
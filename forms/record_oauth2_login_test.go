@@ -2,11 +2,17 @@ package forms_test
 
 import (
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"testing"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/settings"
 	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/auth"
 )
 
 func TestUserOauth2LoginValidate(t *testing.T) {
@@ -87,4 +93,113 @@ func TestUserOauth2LoginValidate(t *testing.T) {
 	}
 }
 
-// @todo consider mocking a Oauth2 provider to test Submit
+func TestRecordOAuth2LoginSubmitClaimsSync(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	userinfo := `{
+		"sub":"oidc_123",
+		"email":"test@example.com",
+		"email_verified":true,
+		"given_name":"mapped",
+		"realm_access":{"roles":["editor","missing"]}
+	}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/token":
+			w.Write([]byte(`{"access_token":"test_token","token_type":"Bearer"}`))
+		case "/userinfo":
+			w.Write([]byte(userinfo))
+		}
+	}))
+	defer server.Close()
+
+	app.Settings().OIDCAuth = settings.AuthProviderConfig{
+		Enabled:       true,
+		ClientId:      "test",
+		ClientSecret:  "test",
+		AuthUrl:       server.URL + "/auth",
+		TokenUrl:      server.URL + "/token",
+		UserApiUrl:    server.URL + "/userinfo",
+		FieldsMapping: map[string]string{"given_name": "name", "missing": "name", "sub": "avatar"},
+		RolesClaim:    "realm_access.roles",
+	}
+
+	editor := &models.Role{Name: "editor"}
+	viewer := &models.Role{Name: "viewer"}
+	for _, role := range []*models.Role{editor, viewer} {
+		if err := app.Dao().SaveRole(role); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	collection, _ := app.Dao().FindCollectionByNameOrId("users")
+
+	existing, _ := app.Dao().FindAuthRecordByEmail(collection.Id, "test@example.com")
+	if err := app.Dao().AssignAuthRecordRole(existing, viewer); err != nil {
+		t.Fatal(err)
+	}
+
+	submit := func() *models.Record {
+		form := forms.NewRecordOAuth2Login(app, collection, nil)
+		form.Provider = auth.NameOIDC
+		form.Code = "test"
+		form.CodeVerifier = "test"
+		form.RedirectUrl = "https://example.com"
+
+		record, _, err := form.Submit()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return record
+	}
+
+	checkRoles := func(record *models.Record, expected ...string) {
+		roles, err := app.Dao().FindAuthRecordRoles(record)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		names := []string{}
+		for _, r := range roles {
+			names = append(names, r.Name)
+		}
+
+		if !reflect.DeepEqual(names, expected) {
+			t.Fatalf("Expected roles %v, got %v", expected, names)
+		}
+	}
+
+	// existing auth record (matched by email)
+	record := submit()
+	if record.Id != existing.Id {
+		t.Fatalf("Expected auth record %q, got %q", existing.Id, record.Id)
+	}
+	if record.GetString("name") != "mapped" {
+		t.Fatalf("Expected the name field to be synced, got %q", record.GetString("name"))
+	}
+	checkRoles(record, "editor")
+
+	// new auth record
+	userinfo = `{"sub":"oidc_456","email":"new@example.com","given_name":"new","realm_access":{"roles":"viewer editor"}}`
+	record = submit()
+	if record.Email() != "new@example.com" || !record.Verified() {
+		t.Fatalf("Expected new verified auth record, got %v", record)
+	}
+	if record.GetString("name") != "new" {
+		t.Fatalf("Expected the name field to be mapped, got %q", record.GetString("name"))
+	}
+	checkRoles(record, "editor", "viewer")
+
+	// missing roles claim shouldn't change the assigned roles
+	userinfo = `{"sub":"oidc_456","email":"new@example.com","given_name":"new2"}`
+	record = submit()
+	if record.GetString("name") != "new2" {
+		t.Fatalf("Expected the name field to be synced, got %q", record.GetString("name"))
+	}
+	checkRoles(record, "editor", "viewer")
+}
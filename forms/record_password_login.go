@@ -2,19 +2,31 @@ package forms
 
 import (
 	"errors"
+	"fmt"
+	"log"
+	"strings"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/daos"
 	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/ldap"
 )
 
+// LdapExternalAuthProvider is the [models.ExternalAuth] provider name
+// of the LDAP linked auth records.
+const LdapExternalAuthProvider = "ldap"
+
+// errLdapEntryNotFound is returned when no LDAP user entry matches the login identity.
+var errLdapEntryNotFound = errors.New("missing LDAP user entry")
+
 // RecordPasswordLogin is record username/email + password login form.
 type RecordPasswordLogin struct {
 	app        core.App
 	dao        *daos.Dao
 	collection *models.Collection
+	ip         string
 
 	Identity string `form:"identity" json:"identity"`
 	Password string `form:"password" json:"password"`
@@ -38,6 +50,12 @@ func (form *RecordPasswordLogin) SetDao(dao *daos.Dao) {
 	form.dao = dao
 }
 
+// SetIp sets the client ip of the login request
+// (used for the failed login attempts throttling).
+func (form *RecordPasswordLogin) SetIp(ip string) {
+	form.ip = ip
+}
+
 // Validate makes the form validatable by implementing [validation.Validatable] interface.
 func (form *RecordPasswordLogin) Validate() error {
 	return validation.ValidateStruct(form,
@@ -47,14 +65,57 @@ func (form *RecordPasswordLogin) Validate() error {
 }
 
 // Submit validates and submits the form.
+//
+// If the collection LDAP authentication is enabled, the credentials are
+// verified first against the directory server and the local password
+// is checked only if no matching directory user entry is found.
+//
+// If the app settings login throttling is enabled, returns
+// [LoginLockedError] while the identity or the client ip is locked.
+//
 // On success returns the authorized record model.
 func (form *RecordPasswordLogin) Submit() (*models.Record, error) {
 	if err := form.Validate(); err != nil {
 		return nil, err
 	}
 
+	throttle := &loginThrottle{
+		app:        form.app,
+		dao:        form.dao,
+		collection: form.collection,
+		identity:   form.Identity,
+		ip:         form.ip,
+	}
+
+	if err := throttle.check(); err != nil {
+		return nil, err
+	}
+
+	record, err := form.submit()
+
+	if errors.Is(err, errInvalidLoginCredentials) {
+		if throttleErr := throttle.registerFailure(); throttleErr != nil && form.app.IsDebug() {
+			log.Println("Failed to register the failed login attempt:", throttleErr)
+		}
+	} else if err == nil {
+		if throttleErr := throttle.reset(); throttleErr != nil && form.app.IsDebug() {
+			log.Println("Failed to reset the failed login attempts:", throttleErr)
+		}
+	}
+
+	return record, err
+}
+
+func (form *RecordPasswordLogin) submit() (*models.Record, error) {
 	authOptions := form.collection.AuthOptions()
 
+	if authOptions.Ldap.Enabled {
+		record, err := form.submitLdap(authOptions.Ldap)
+		if !errors.Is(err, errLdapEntryNotFound) {
+			return record, err
+		}
+	}
+
 	if !authOptions.AllowEmailAuth && !authOptions.AllowUsernameAuth {
 		return nil, errors.New("Password authentication is not allowed for the collection.")
 	}
@@ -70,8 +131,90 @@ func (form *RecordPasswordLogin) Submit() (*models.Record, error) {
 	}
 
 	if fetchErr != nil || !record.ValidatePassword(form.Password) {
-		return nil, errors.New("Invalid login credentials.")
+		return nil, errInvalidLoginCredentials
 	}
 
 	return record, nil
 }
+
+// LdapProviderId returns the [models.ExternalAuth] provider id of
+// the LDAP user entry (the external auths are unique per provider,
+// so the id is scoped to the collection).
+func LdapProviderId(collection *models.Collection, entry *ldap.Entry) string {
+	return collection.Id + ":" + strings.ToLower(entry.Dn)
+}
+
+// submitLdap verifies the form credentials with a bind as the matching
+// LDAP user entry and returns its linked auth record
+// (it is created if missing and the mapped fields are synced).
+//
+// Returns errLdapEntryNotFound if there is no user entry matching the identity.
+func (form *RecordPasswordLogin) submitLdap(options models.CollectionLdapOptions) (*models.Record, error) {
+	conn, err := ldap.Dial(options.Url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to connect to the LDAP server: %w", err)
+	}
+	defer conn.Close()
+
+	if options.BindDn != "" {
+		err = conn.Bind(options.BindDn, options.BindPassword)
+	} else {
+		err = conn.AnonymousBind()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to bind to the LDAP server: %w", err)
+	}
+
+	attributes := make([]string, 0, len(options.FieldsMapping)+1)
+	if options.EmailAttribute != "" {
+		attributes = append(attributes, options.EmailAttribute)
+	}
+	for attr := range options.FieldsMapping {
+		attributes = append(attributes, attr)
+	}
+
+	entries, err := conn.Search(&ldap.SearchRequest{
+		BaseDn:     options.BaseDn,
+		Scope:      ldap.ScopeWholeSubtree,
+		Filter:     strings.ReplaceAll(options.UserFilter, models.LdapIdentityPlaceholder, ldap.EscapeFilter(form.Identity)),
+		Attributes: attributes,
+		SizeLimit:  2,
+	})
+	if ldap.IsResultCode(err, ldap.ResultSizeLimitExceeded) || len(entries) > 1 {
+		// ambiguous identity
+		return nil, errInvalidLoginCredentials
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to search for the LDAP user entry: %w", err)
+	}
+
+	if len(entries) == 0 {
+		return nil, errLdapEntryNotFound
+	}
+
+	entry := entries[0]
+
+	if err := conn.Bind(entry.Dn, form.Password); err != nil {
+		return nil, errInvalidLoginCredentials
+	}
+
+	var email string
+	if options.EmailAttribute != "" {
+		email = entry.Attribute(options.EmailAttribute)
+		if is.EmailFormat.Validate(email) != nil {
+			email = ""
+		}
+	}
+
+	login := &externalAuthLogin{
+		app:        form.app,
+		dao:        form.dao,
+		collection: form.collection,
+		provider:   LdapExternalAuthProvider,
+		providerId: LdapProviderId(form.collection, entry),
+		email:      email,
+		mappedData: mapAttributesData(form.collection, options.FieldsMapping, entry.Attributes),
+	}
+
+	return login.submit(nil)
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/tests"
 )
 
@@ -128,3 +129,147 @@ func TestRecordEmailLoginValidateAndSubmit(t *testing.T) {
 		}
 	}
 }
+
+func TestRecordPasswordLoginSubmitWithLdap(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	server, err := tests.NewTestLdapServer(
+		&tests.TestLdapEntry{
+			Dn:       "cn=admin,dc=example,dc=com",
+			Password: "admin_pass",
+		},
+		&tests.TestLdapEntry{
+			Dn:       "uid=jdoe,ou=people,dc=example,dc=com",
+			Password: "ldap_pass",
+			Attributes: map[string][]string{
+				"uid":  {"jdoe"},
+				"mail": {"ldap_new@example.com"},
+				"cn":   {"John Doe"},
+			},
+		},
+		&tests.TestLdapEntry{
+			Dn:       "uid=existing,ou=people,dc=example,dc=com",
+			Password: "ldap_pass",
+			Attributes: map[string][]string{
+				"uid":  {"existing"},
+				"mail": {"test@example.com"},
+			},
+		},
+		&tests.TestLdapEntry{
+			Dn:         "uid=dup1,ou=people,dc=example,dc=com",
+			Password:   "ldap_pass",
+			Attributes: map[string][]string{"uid": {"dup1"}, "mail": {"dup@example.com"}},
+		},
+		&tests.TestLdapEntry{
+			Dn:         "uid=dup2,ou=people,dc=example,dc=com",
+			Password:   "ldap_pass",
+			Attributes: map[string][]string{"uid": {"dup2"}, "mail": {"dup@example.com"}},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	users, err := testApp.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := users.AuthOptions()
+	options.Ldap = models.CollectionLdapOptions{
+		Enabled:        true,
+		Url:            server.Url,
+		BindDn:         "cn=admin,dc=example,dc=com",
+		BindPassword:   "invalid",
+		BaseDn:         "ou=people,dc=example,dc=com",
+		UserFilter:     "(|(uid={identity})(mail={identity}))",
+		EmailAttribute: "mail",
+		FieldsMapping:  map[string]string{"cn": "name"},
+	}
+	users.SetOptions(options)
+
+	submit := func(identity, password string) (*models.Record, error) {
+		form := forms.NewRecordPasswordLogin(testApp, users)
+		form.Identity = identity
+		form.Password = password
+		return form.Submit()
+	}
+
+	// invalid service account credentials
+	if _, err := submit("jdoe", "ldap_pass"); err == nil {
+		t.Fatal("Expected error for invalid service account credentials")
+	}
+
+	options.Ldap.BindPassword = "admin_pass"
+	users.SetOptions(options)
+
+	scenarios := []struct {
+		name        string
+		identity    string
+		password    string
+		expectError bool
+	}{
+		{"directory user with invalid password", "jdoe", "invalid", true},
+		{"ambiguous directory user", "dup@example.com", "ldap_pass", true},
+		{"escaped filter identity", "*", "ldap_pass", true},
+		{"missing directory user with invalid local password", "test2@example.com", "invalid", true},
+		{"missing directory user with valid local password", "test2@example.com", "1234567890", false},
+		{"directory user with local password", "existing", "1234567890", true},
+	}
+
+	for _, s := range scenarios {
+		_, err := submit(s.identity, s.password)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr to be %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+		}
+	}
+
+	// new linked auth record
+	record, err := submit("jdoe", "ldap_pass")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if record.Email() != "ldap_new@example.com" || !record.Verified() {
+		t.Fatalf("Expected verified ldap_new@example.com record, got %q (%v)", record.Email(), record.Verified())
+	}
+
+	if v := record.GetString("name"); v != "John Doe" {
+		t.Fatalf("Expected the mapped name to be loaded, got %q", v)
+	}
+
+	// existing linked auth record (by the directory user email)
+	existing, err := submit("existing", "ldap_pass")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if existing.Id != "4q1xlclmfloku33" {
+		t.Fatalf("Expected the existing auth record to be linked, got %q", existing.Id)
+	}
+
+	rels, err := testApp.Dao().FindAllExternalAuthsByRecord(existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var linked bool
+	for _, rel := range rels {
+		if rel.Provider == forms.LdapExternalAuthProvider {
+			linked = true
+		}
+	}
+	if !linked {
+		t.Fatalf("Expected the ldap external auth to be linked to %q", existing.Id)
+	}
+
+	// unavailable directory server
+	server.Close()
+	if _, err := submit("test2@example.com", "1234567890"); err == nil {
+		t.Fatal("Expected error for unavailable LDAP server")
+	}
+}
@@ -0,0 +1,128 @@
+package forms
+
+import (
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// RecordPhoneOtpConfirm is an auth record phone one-time password confirmation form.
+type RecordPhoneOtpConfirm struct {
+	app        core.App
+	collection *models.Collection
+	dao        *daos.Dao
+
+	Phone string `form:"phone" json:"phone"`
+	Code  string `form:"code" json:"code"`
+}
+
+// NewRecordPhoneOtpConfirm creates a new [RecordPhoneOtpConfirm]
+// form initialized with from the provided [core.App] instance.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewRecordPhoneOtpConfirm(app core.App, collection *models.Collection) *RecordPhoneOtpConfirm {
+	return &RecordPhoneOtpConfirm{
+		app:        app,
+		dao:        app.Dao(),
+		collection: collection,
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *RecordPhoneOtpConfirm) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+//
+// This method doesn't verify the code (this is done on Submit).
+func (form *RecordPhoneOtpConfirm) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(
+			&form.Phone,
+			validation.Required,
+			validation.Match(phoneRegex).Error("Must be a valid E.164 phone number (eg. +359888123456)."),
+		),
+		validation.Field(&form.Code, validation.Required, validation.Length(1, 20)),
+	)
+}
+
+// Submit validates and submits the form.
+// On success returns the auth record associated to `form.Phone`
+// and invalidates the used one-time password.
+//
+// Each code verification is atomically counted and the one-time password
+// is invalidated after [models.PhoneOtpMaxAttempts] attempts.
+//
+// You can optionally provide a list of InterceptorWithRecordFunc to
+// further modify the form behavior before persisting it.
+func (form *RecordPhoneOtpConfirm) Submit(interceptors ...InterceptorWithRecordFunc) (*models.Record, error) {
+	if err := form.Validate(); err != nil {
+		return nil, err
+	}
+
+	if !form.collection.AuthOptions().AllowPhoneAuth {
+		return nil, errors.New("Phone authentication is not allowed for the auth collection.")
+	}
+
+	invalidCodeErr := validation.Errors{"code": validation.NewError(
+		"validation_invalid_code",
+		"Invalid or expired one-time password.",
+	)}
+
+	authRecord, err := form.dao.FindAuthRecordByPhone(form.collection.Id, form.Phone)
+	if err != nil {
+		return nil, invalidCodeErr
+	}
+
+	otp, err := form.dao.FindLastPhoneOtpByRecord(authRecord)
+	if err != nil {
+		return nil, invalidCodeErr
+	}
+
+	duration := time.Duration(form.app.Settings().Sms.OtpDuration) * time.Second
+
+	// the phone number has been changed after the code was sent
+	// or the code has expired or was already guessed too many times
+	if otp.Phone != authRecord.Phone() ||
+		time.Now().UTC().Sub(otp.Created.Time()) > duration ||
+		otp.Attempts >= models.PhoneOtpMaxAttempts {
+		return nil, invalidCodeErr
+	}
+
+	// reserve the attempt before verifying the code so that
+	// concurrent requests can't exceed the max allowed attempts
+	if err := form.dao.IncrementPhoneOtpAttempts(otp); err != nil {
+		if errors.Is(err, daos.ErrPhoneOtpUnavailable) {
+			return nil, invalidCodeErr
+		}
+		return nil, err
+	}
+
+	if !otp.ValidateCode(form.Code) {
+		return nil, invalidCodeErr
+	}
+
+	interceptorsErr := runInterceptorsWithRecord(authRecord, func(m *models.Record) error {
+		// the code could be used only once
+		if err := form.dao.ConsumePhoneOtp(otp); err != nil {
+			if errors.Is(err, daos.ErrPhoneOtpUnavailable) {
+				return invalidCodeErr
+			}
+			return err
+		}
+
+		return nil
+	}, interceptors...)
+
+	if interceptorsErr != nil {
+		return nil, interceptorsErr
+	}
+
+	return authRecord, nil
+}
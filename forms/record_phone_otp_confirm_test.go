@@ -0,0 +1,153 @@
+package forms_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestRecordPhoneOtpConfirmSubmit(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	authCollection, user := enableUsersPhoneAuth(t, testApp)
+
+	newOtp := func(code string) *models.PhoneOtp {
+		otp := &models.PhoneOtp{
+			CollectionId: authCollection.Id,
+			RecordId:     user.Id,
+			Phone:        user.Phone(),
+		}
+		if err := otp.SetCode(code); err != nil {
+			t.Fatal(err)
+		}
+		if err := testApp.Dao().SavePhoneOtp(otp); err != nil {
+			t.Fatal(err)
+		}
+		return otp
+	}
+
+	submit := func(jsonData string) (*models.Record, int, error) {
+		form := forms.NewRecordPhoneOtpConfirm(testApp, authCollection)
+
+		if err := json.Unmarshal([]byte(jsonData), form); err != nil {
+			t.Fatalf("Failed to load form data: %v", err)
+		}
+
+		interceptorCalls := 0
+		interceptor := func(next forms.InterceptorWithRecordNextFunc) forms.InterceptorWithRecordNextFunc {
+			return func(r *models.Record) error {
+				interceptorCalls++
+				return next(r)
+			}
+		}
+
+		record, err := form.Submit(interceptor)
+
+		return record, interceptorCalls, err
+	}
+
+	// validation errors
+	if _, calls, err := submit(`{"phone":"invalid","code":""}`); err == nil || calls != 0 {
+		t.Fatalf("Expected validation error, got %v (%d interceptor calls)", err, calls)
+	}
+
+	// no otp
+	if _, calls, err := submit(`{"phone":"+359888123456","code":"123456"}`); err == nil || calls != 0 {
+		t.Fatalf("Expected error for missing otp, got %v (%d interceptor calls)", err, calls)
+	}
+
+	otp := newOtp("123456")
+
+	// nonexisting phone
+	if _, calls, err := submit(`{"phone":"+359888000000","code":"123456"}`); err == nil || calls != 0 {
+		t.Fatalf("Expected error for nonexisting phone, got %v (%d interceptor calls)", err, calls)
+	}
+
+	// invalid code
+	_, calls, err := submit(`{"phone":"+359888123456","code":"000000"}`)
+	if errs, ok := err.(validation.Errors); !ok || errs["code"] == nil || calls != 0 {
+		t.Fatalf("Expected code validation error, got %v (%d interceptor calls)", err, calls)
+	}
+	if found, _ := testApp.Dao().FindPhoneOtpById(otp.Id); found == nil || found.Attempts != 1 {
+		t.Fatalf("Expected the failed attempt to be counted, got %v", found)
+	}
+
+	// valid code
+	record, calls, err := submit(`{"phone":"+359888123456","code":"123456"}`)
+	if err != nil || calls != 1 {
+		t.Fatalf("Expected successful confirmation, got %v (%d interceptor calls)", err, calls)
+	}
+	if record.Id != user.Id {
+		t.Fatalf("Expected user %q, got %q", user.Id, record.Id)
+	}
+	if _, err := testApp.Dao().FindPhoneOtpById(otp.Id); err == nil {
+		t.Fatal("Expected the used otp to be deleted")
+	}
+
+	// already used code
+	if _, calls, err := submit(`{"phone":"+359888123456","code":"123456"}`); err == nil || calls != 0 {
+		t.Fatalf("Expected error for already used otp, got %v (%d interceptor calls)", err, calls)
+	}
+
+	// code consumed by a concurrent request
+	otp = newOtp("123456")
+	form := forms.NewRecordPhoneOtpConfirm(testApp, authCollection)
+	form.Phone = user.Phone()
+	form.Code = "123456"
+	_, err = form.Submit(func(next forms.InterceptorWithRecordNextFunc) forms.InterceptorWithRecordNextFunc {
+		return func(r *models.Record) error {
+			if err := testApp.Dao().DeletePhoneOtp(otp); err != nil {
+				t.Fatal(err)
+			}
+			return next(r)
+		}
+	})
+	if errs, ok := err.(validation.Errors); !ok || errs["code"] == nil {
+		t.Fatalf("Expected code validation error for already consumed otp, got %v", err)
+	}
+
+	// too many failed attempts
+	otp = newOtp("123456")
+	otp.Attempts = models.PhoneOtpMaxAttempts
+	if err := testApp.Dao().SavePhoneOtp(otp); err != nil {
+		t.Fatal(err)
+	}
+	if _, calls, err := submit(`{"phone":"+359888123456","code":"123456"}`); err == nil || calls != 0 {
+		t.Fatalf("Expected error for otp with too many failed attempts, got %v (%d interceptor calls)", err, calls)
+	}
+
+	// expired code
+	otp = newOtp("123456")
+	otp.Created, _ = types.ParseDateTime("2022-01-01 00:00:00.000Z")
+	if err := testApp.Dao().SavePhoneOtp(otp); err != nil {
+		t.Fatal(err)
+	}
+	if _, calls, err := submit(`{"phone":"+359888123456","code":"123456"}`); err == nil || calls != 0 {
+		t.Fatalf("Expected error for expired otp, got %v (%d interceptor calls)", err, calls)
+	}
+
+	// changed phone number
+	otp = newOtp("123456")
+	otp.Phone = "+359888000000"
+	if err := testApp.Dao().SavePhoneOtp(otp); err != nil {
+		t.Fatal(err)
+	}
+	if _, calls, err := submit(`{"phone":"+359888123456","code":"123456"}`); err == nil || calls != 0 {
+		t.Fatalf("Expected error for otp sent to another phone, got %v (%d interceptor calls)", err, calls)
+	}
+
+	// disabled phone auth
+	options := authCollection.AuthOptions()
+	options.AllowPhoneAuth = false
+	authCollection.SetOptions(options)
+	newOtp("123456")
+	if _, calls, err := submit(`{"phone":"+359888123456","code":"123456"}`); err == nil || calls != 0 {
+		t.Fatalf("Expected error for disabled phone auth, got %v (%d interceptor calls)", err, calls)
+	}
+}
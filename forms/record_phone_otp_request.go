@@ -0,0 +1,112 @@
+package forms
+
+import (
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/mails"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+// PhoneOtpCodeLength is the number of digits of the generated phone otp codes.
+const PhoneOtpCodeLength = 6
+
+// RecordPhoneOtpRequest is an auth record phone one-time password request form.
+type RecordPhoneOtpRequest struct {
+	app             core.App
+	dao             *daos.Dao
+	collection      *models.Collection
+	resendThreshold float64 // in seconds
+
+	Phone string `form:"phone" json:"phone"`
+}
+
+// NewRecordPhoneOtpRequest creates a new [RecordPhoneOtpRequest]
+// form initialized with from the provided [core.App] instance.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewRecordPhoneOtpRequest(app core.App, collection *models.Collection) *RecordPhoneOtpRequest {
+	return &RecordPhoneOtpRequest{
+		app:             app,
+		dao:             app.Dao(),
+		collection:      collection,
+		resendThreshold: 60, // 1 min
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *RecordPhoneOtpRequest) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+//
+// This method doesn't checks whether auth record with `form.Phone` exists (this is done on Submit).
+func (form *RecordPhoneOtpRequest) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(
+			&form.Phone,
+			validation.Required,
+			validation.Match(phoneRegex).Error("Must be a valid E.164 phone number (eg. +359888123456)."),
+		),
+	)
+}
+
+// Submit validates and submits the form.
+// On success, replaces the previous one-time passwords of the `form.Phone`
+// auth record with a new one and sends its code as SMS message.
+//
+// You can optionally provide a list of InterceptorWithRecordFunc to
+// further modify the form behavior before persisting it.
+func (form *RecordPhoneOtpRequest) Submit(interceptors ...InterceptorWithRecordFunc) error {
+	if err := form.Validate(); err != nil {
+		return err
+	}
+
+	if !form.collection.AuthOptions().AllowPhoneAuth {
+		return errors.New("Phone authentication is not allowed for the auth collection.")
+	}
+
+	authRecord, err := form.dao.FindAuthRecordByPhone(form.collection.Id, form.Phone)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	lastOtp, _ := form.dao.FindLastPhoneOtpByRecord(authRecord)
+	if lastOtp != nil && now.Sub(lastOtp.Created.Time()).Seconds() < form.resendThreshold {
+		return errors.New("You've already requested a one-time password.")
+	}
+
+	return runInterceptorsWithRecord(authRecord, func(m *models.Record) error {
+		code := security.RandomStringWithAlphabet(PhoneOtpCodeLength, "0123456789")
+
+		otp := &models.PhoneOtp{
+			CollectionId: m.Collection().Id,
+			RecordId:     m.Id,
+			Phone:        m.Phone(),
+		}
+		if err := otp.SetCode(code); err != nil {
+			return err
+		}
+
+		saveErr := form.dao.RunInTransaction(func(txDao *daos.Dao) error {
+			// only the latest requested code is valid
+			if err := txDao.DeleteAuthRecordPhoneOtps(m); err != nil {
+				return err
+			}
+
+			return txDao.SavePhoneOtp(otp)
+		})
+		if saveErr != nil {
+			return saveErr
+		}
+
+		return mails.SendRecordPhoneOtp(form.app, m, otp, code)
+	}, interceptors...)
+}
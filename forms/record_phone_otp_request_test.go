@@ -0,0 +1,154 @@
+package forms_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+// enableUsersPhoneAuth enables the phone auth of the test "users"
+// collection and assigns a phone number to the test@example.com user.
+func enableUsersPhoneAuth(t *testing.T, app *tests.TestApp) (*models.Collection, *models.Record) {
+	collection, err := app.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := collection.AuthOptions()
+	options.AllowPhoneAuth = true
+	collection.SetOptions(options)
+
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := app.Dao().FindRecordById(collection.Id, "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user.SetPhone("+359888123456")
+	if err := app.Dao().SaveRecord(user); err != nil {
+		t.Fatal(err)
+	}
+
+	return collection, user
+}
+
+func TestRecordPhoneOtpRequestSubmit(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	authCollection, err := testApp.Dao().FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// disabled phone auth
+	disabledForm := forms.NewRecordPhoneOtpRequest(testApp, authCollection)
+	disabledForm.Phone = "+359888123456"
+	if err := disabledForm.Submit(); err == nil {
+		t.Fatal("Expected error for disabled phone auth")
+	}
+
+	authCollection, _ = enableUsersPhoneAuth(t, testApp)
+
+	scenarios := []struct {
+		jsonData    string
+		expectError bool
+	}{
+		// empty field (Validate call check)
+		{
+			`{"phone":""}`,
+			true,
+		},
+		// invalid phone field (Validate call check)
+		{
+			`{"phone":"0888123456"}`,
+			true,
+		},
+		// nonexisting user
+		{
+			`{"phone":"+359888000000"}`,
+			true,
+		},
+		// existing user
+		{
+			`{"phone":"+359888123456"}`,
+			false,
+		},
+		// existing user - reached send threshod
+		{
+			`{"phone":"+359888123456"}`,
+			true,
+		},
+	}
+
+	for i, s := range scenarios {
+		testApp.TestSmsSender.TotalSend = 0 // reset
+		form := forms.NewRecordPhoneOtpRequest(testApp, authCollection)
+
+		// load data
+		loadErr := json.Unmarshal([]byte(s.jsonData), form)
+		if loadErr != nil {
+			t.Errorf("(%d) Failed to load form data: %v", i, loadErr)
+			continue
+		}
+
+		interceptorCalls := 0
+		interceptor := func(next forms.InterceptorWithRecordNextFunc) forms.InterceptorWithRecordNextFunc {
+			return func(r *models.Record) error {
+				interceptorCalls++
+				return next(r)
+			}
+		}
+
+		err := form.Submit(interceptor)
+
+		// check interceptor calls
+		expectInterceptorCalls := 1
+		if s.expectError {
+			expectInterceptorCalls = 0
+		}
+		if interceptorCalls != expectInterceptorCalls {
+			t.Errorf("[%d] Expected interceptor to be called %d, got %d", i, expectInterceptorCalls, interceptorCalls)
+		}
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr to be %v, got %v (%v)", i, s.expectError, hasErr, err)
+		}
+
+		expectedMessages := 1
+		if s.expectError {
+			expectedMessages = 0
+		}
+		if testApp.TestSmsSender.TotalSend != expectedMessages {
+			t.Errorf("(%d) Expected %d SMS message(s) to be sent, got %d", i, expectedMessages, testApp.TestSmsSender.TotalSend)
+		}
+
+		if s.expectError {
+			continue
+		}
+
+		// check whether the otp was created
+		user, err := testApp.Dao().FindAuthRecordByPhone(authCollection.Id, form.Phone)
+		if err != nil {
+			t.Errorf("(%d) Expected user with phone %q to exist, got nil", i, form.Phone)
+			continue
+		}
+
+		otp, err := testApp.Dao().FindLastPhoneOtpByRecord(user)
+		if err != nil {
+			t.Errorf("(%d) Expected a phone otp to be created, got %v", i, err)
+			continue
+		}
+
+		if otp.Phone != form.Phone || otp.CodeHash == "" {
+			t.Errorf("(%d) Unexpected phone otp %v", i, otp)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package forms
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// RecordPreferencesUpdate is a [models.RecordPreferences] update form.
+//
+// The Data keys must be declared preference keys (see
+// [settings.PreferencesConfig]) and a nil value resets the
+// preference to its default.
+type RecordPreferencesUpdate struct {
+	app         core.App
+	dao         *daos.Dao
+	preferences *models.RecordPreferences
+
+	Data map[string]any
+}
+
+// NewRecordPreferencesUpdate creates a new [RecordPreferencesUpdate] form
+// with initializer config created from the provided [core.App] and
+// [models.RecordPreferences] instances (see [daos.Dao.FindRecordPreferences]).
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewRecordPreferencesUpdate(app core.App, preferences *models.RecordPreferences) *RecordPreferencesUpdate {
+	return &RecordPreferencesUpdate{
+		app:         app,
+		dao:         app.Dao(),
+		preferences: preferences,
+		Data:        map[string]any{},
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *RecordPreferencesUpdate) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+func (form *RecordPreferencesUpdate) Validate() error {
+	config := form.app.Settings().Preferences
+
+	errs := validation.Errors{}
+
+	for name, value := range form.Data {
+		key := config.FindKey(name)
+		if key == nil {
+			errs[name] = validation.NewError("validation_unknown_preference", "Unknown preference.")
+			continue
+		}
+
+		if value == nil {
+			continue // reset
+		}
+
+		if _, err := key.NormalizeValue(value); err != nil {
+			errs[name] = validation.NewError("validation_invalid_preference_value", err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// Submit validates and submits the form.
+// On success the stored auth record preferences are updated.
+//
+// You can optionally provide a list of InterceptorFunc to further
+// modify the form behavior before persisting it.
+func (form *RecordPreferencesUpdate) Submit(interceptors ...InterceptorFunc) error {
+	if err := form.Validate(); err != nil {
+		return err
+	}
+
+	config := form.app.Settings().Preferences
+
+	if form.preferences.Data == nil {
+		form.preferences.Data = map[string]any{}
+	}
+
+	for name, value := range form.Data {
+		if value == nil {
+			delete(form.preferences.Data, name)
+			continue
+		}
+
+		form.preferences.Data[name], _ = config.FindKey(name).NormalizeValue(value)
+	}
+
+	return runInterceptors(func() error {
+		return form.dao.SaveRecordPreferences(form.preferences)
+	}, interceptors...)
+}
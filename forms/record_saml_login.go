@@ -0,0 +1,139 @@
+package forms
+
+import (
+	"errors"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/saml"
+)
+
+// SamlExternalAuthProvider is the [models.ExternalAuth] provider name
+// of the SAML linked auth records.
+const SamlExternalAuthProvider = "saml"
+
+// RecordSamlLogin is an auth record SAML login form.
+type RecordSamlLogin struct {
+	app        core.App
+	dao        *daos.Dao
+	collection *models.Collection
+
+	// Optional auth record that will be used if no external
+	// auth relation is found (if it is from the same collection)
+	loggedAuthRecord *models.Record
+
+	// The one-time code returned to the login redirect url.
+	Code string `form:"code" json:"code"`
+
+	// The code verifier of the code challenge sent with the login request.
+	CodeVerifier string `form:"codeVerifier" json:"codeVerifier"`
+
+	// Additional data that will be used for creating a new auth record
+	// if an existing SAML linked account doesn't exist.
+	CreateData map[string]any `form:"createData" json:"createData"`
+}
+
+// NewRecordSamlLogin creates a new [RecordSamlLogin] form
+// initialized with from the provided [core.App] instance.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewRecordSamlLogin(app core.App, collection *models.Collection, optAuthRecord *models.Record) *RecordSamlLogin {
+	return &RecordSamlLogin{
+		app:              app,
+		dao:              app.Dao(),
+		collection:       collection,
+		loggedAuthRecord: optAuthRecord,
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *RecordSamlLogin) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+func (form *RecordSamlLogin) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Code, validation.Required, validation.Length(1, 255)),
+		validation.Field(&form.CodeVerifier, validation.Required, validation.Length(43, 128)),
+	)
+}
+
+// SamlProviderId returns the [models.ExternalAuth] provider id of
+// the assertion subject (the external auths are unique per provider,
+// so the id is scoped to the collection).
+func SamlProviderId(collection *models.Collection, assertion *saml.Assertion) string {
+	return collection.Id + ":" + assertion.NameId
+}
+
+// Submit validates and submits the form with the already verified
+// SAML assertion of the form code.
+//
+// If an auth record doesn't exist, it will make an attempt to create it
+// based on the assertion data via a local [RecordUpsert] form.
+// You can intercept/modify the create form by setting the optional beforeCreateFuncs argument.
+//
+// The collection SAML fields mapping is synced with each login.
+//
+// On success returns the authorized record model.
+func (form *RecordSamlLogin) Submit(
+	assertion *saml.Assertion,
+	beforeCreateFuncs ...func(createForm *RecordUpsert, authRecord *models.Record, assertion *saml.Assertion) error,
+) (*models.Record, error) {
+	if err := form.Validate(); err != nil {
+		return nil, err
+	}
+
+	options := form.collection.AuthOptions().Saml
+	if !options.Enabled {
+		return nil, errors.New("SAML authentication is not allowed for the auth collection.")
+	}
+
+	if assertion == nil || assertion.NameId == "" {
+		return nil, errors.New("Missing SAML assertion subject.")
+	}
+
+	login := &externalAuthLogin{
+		app:              form.app,
+		dao:              form.dao,
+		collection:       form.collection,
+		loggedAuthRecord: form.loggedAuthRecord,
+		provider:         SamlExternalAuthProvider,
+		providerId:       SamlProviderId(form.collection, assertion),
+		email:            form.assertionEmail(assertion),
+		mappedData:       mapAttributesData(form.collection, options.FieldsMapping, assertion.Attributes),
+		createData:       form.CreateData,
+	}
+
+	return login.submit(func(createForm *RecordUpsert, authRecord *models.Record) error {
+		for _, f := range beforeCreateFuncs {
+			if f == nil {
+				continue
+			}
+			if err := f(createForm, authRecord, assertion); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// assertionEmail returns the configured email attribute value or
+// the subject NameID if it is a valid email address.
+func (form *RecordSamlLogin) assertionEmail(assertion *saml.Assertion) string {
+	email := assertion.NameId
+
+	if attr := form.collection.AuthOptions().Saml.EmailAttribute; attr != "" {
+		email = assertion.Attribute(attr)
+	}
+
+	if is.EmailFormat.Validate(email) != nil {
+		return ""
+	}
+
+	return email
+}
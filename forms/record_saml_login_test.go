@@ -0,0 +1,136 @@
+package forms_test
+
+import (
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/saml"
+)
+
+func TestRecordSamlLoginValidate(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	users, _ := app.Dao().FindCollectionByNameOrId("users")
+
+	scenarios := []struct {
+		name           string
+		code           string
+		codeVerifier   string
+		expectedErrors []string
+	}{
+		{"empty data", "", "", []string{"code", "codeVerifier"}},
+		{"too short code verifier", "test", "short", []string{"codeVerifier"}},
+		{"valid data", "test", "test_code_verifier_0123456789_0123456789_0123456789", []string{}},
+	}
+
+	for _, s := range scenarios {
+		form := forms.NewRecordSamlLogin(app, users, nil)
+		form.Code = s.code
+		form.CodeVerifier = s.codeVerifier
+
+		err := form.Validate()
+
+		// parse errors
+		errs, ok := err.(validation.Errors)
+		if !ok && err != nil {
+			t.Errorf("[%s] Failed to parse errors %v", s.name, err)
+			continue
+		}
+
+		if len(errs) != len(s.expectedErrors) {
+			t.Errorf("[%s] Expected error keys %v, got %v", s.name, s.expectedErrors, errs)
+			continue
+		}
+
+		for k := range errs {
+			if !list.ExistInSlice(k, s.expectedErrors) {
+				t.Errorf("[%s] Missing expected error key %q in %v", s.name, k, errs)
+			}
+		}
+	}
+}
+
+func TestRecordSamlLoginSubmit(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	users, _ := app.Dao().FindCollectionByNameOrId("users")
+
+	newForm := func() *forms.RecordSamlLogin {
+		form := forms.NewRecordSamlLogin(app, users, nil)
+		form.Code = "test"
+		form.CodeVerifier = "test_code_verifier_0123456789_0123456789_0123456789"
+		return form
+	}
+
+	assertion := &saml.Assertion{
+		NameId: "saml_subject",
+		Attributes: map[string][]string{
+			"mail":        {"saml@example.com"},
+			"displayName": {"SAML user"},
+		},
+	}
+
+	// disabled saml auth
+	if _, err := newForm().Submit(assertion); err == nil {
+		t.Fatal("Expected error for disabled SAML auth")
+	}
+
+	options := users.AuthOptions()
+	options.Saml = models.CollectionSamlOptions{
+		Enabled:        true,
+		EmailAttribute: "mail",
+		FieldsMapping:  map[string]string{"displayName": "name"},
+	}
+	users.SetOptions(options)
+
+	// missing assertion
+	if _, err := newForm().Submit(nil); err == nil {
+		t.Fatal("Expected error for missing assertion")
+	}
+
+	// new auth record
+	form := newForm()
+	form.CreateData = map[string]any{"name": "overwritten", "username": "saml_username"}
+	record, err := form.Submit(assertion)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if record.Email() != "saml@example.com" || !record.Verified() {
+		t.Fatalf("Expected verified saml@example.com record, got %q (%v)", record.Email(), record.Verified())
+	}
+
+	if record.Username() != "saml_username" {
+		t.Fatalf("Expected the create data username to be loaded, got %q", record.Username())
+	}
+
+	if v := record.GetString("name"); v != "SAML user" {
+		t.Fatalf("Expected the mapped name to have priority over the create data, got %q", v)
+	}
+
+	rel, err := app.Dao().FindExternalAuthByProvider(forms.SamlExternalAuthProvider, forms.SamlProviderId(users, assertion))
+	if err != nil || rel.RecordId != record.Id {
+		t.Fatalf("Expected external auth linked to %q, got %v (%v)", record.Id, rel, err)
+	}
+
+	// existing linked auth record with synced mapped fields
+	assertion.Attributes["displayName"] = []string{"Updated name"}
+	linked, err := newForm().Submit(assertion)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if linked.Id != record.Id {
+		t.Fatalf("Expected the linked record %q, got %q", record.Id, linked.Id)
+	}
+
+	if v := linked.GetString("name"); v != "Updated name" {
+		t.Fatalf("Expected the mapped name to be synced, got %q", v)
+	}
+}
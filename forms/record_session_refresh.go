@@ -0,0 +1,72 @@
+package forms
+
+import (
+	"errors"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// RecordSessionRefresh is an auth record session refresh token form.
+type RecordSessionRefresh struct {
+	app        core.App
+	dao        *daos.Dao
+	collection *models.Collection
+
+	RefreshToken string `form:"refreshToken" json:"refreshToken"`
+}
+
+// NewRecordSessionRefresh creates a new [RecordSessionRefresh] form initialized
+// with from the provided [core.App] and [models.Collection] instance.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewRecordSessionRefresh(app core.App, collection *models.Collection) *RecordSessionRefresh {
+	return &RecordSessionRefresh{
+		app:        app,
+		dao:        app.Dao(),
+		collection: collection,
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *RecordSessionRefresh) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+func (form *RecordSessionRefresh) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.RefreshToken, validation.Required, validation.Length(1, 255)),
+	)
+}
+
+// Submit validates and submits the form.
+// On success returns the active session of the refresh token and its auth record.
+//
+// Note that the session refresh token is not rotated.
+func (form *RecordSessionRefresh) Submit() (*models.Record, *models.AuthSession, error) {
+	if err := form.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	if !form.collection.AuthOptions().Sessions.Enabled {
+		return nil, nil, errors.New("The collection sessions are not enabled.")
+	}
+
+	invalidErr := errors.New("Invalid or expired refresh token.")
+
+	session, err := form.dao.FindAuthSessionByToken(form.RefreshToken)
+	if err != nil || session.CollectionId != form.collection.Id || session.IsExpired() {
+		return nil, nil, invalidErr
+	}
+
+	record, err := form.dao.FindRecordById(form.collection.Id, session.RecordId)
+	if err != nil || !session.HasAuthRecord(record) {
+		return nil, nil, invalidErr
+	}
+
+	return record, session, nil
+}
@@ -0,0 +1,121 @@
+package forms_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestRecordSessionRefreshValidateAndSubmit(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	// changes the token key of the oap640cot4yru2s user
+	user2, err := testApp.Dao().FindRecordById("users", "oap640cot4yru2s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user2.RefreshTokenKey()
+	if err := testApp.Dao().SaveRecord(user2); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		testName          string
+		collectionName    string
+		enableSessions    bool
+		refreshToken      string
+		expectedSessionId string
+	}{
+		{
+			"empty data",
+			"users",
+			true,
+			"",
+			"",
+		},
+		{
+			"valid token with disabled sessions",
+			"users",
+			false,
+			"test_refresh_token_1",
+			"",
+		},
+		{
+			"missing token",
+			"users",
+			true,
+			"missing",
+			"",
+		},
+		{
+			"valid token from different collection",
+			"clients",
+			true,
+			"test_refresh_token_1",
+			"",
+		},
+		{
+			"expired session token",
+			"users",
+			true,
+			"test_refresh_token_3",
+			"",
+		},
+		{
+			"session token of record with changed token key",
+			"users",
+			true,
+			"test_refresh_token_2",
+			"",
+		},
+		{
+			"valid token",
+			"users",
+			true,
+			"test_refresh_token_1",
+			"authsess0000001",
+		},
+	}
+
+	for _, s := range scenarios {
+		authCollection, err := testApp.Dao().FindCollectionByNameOrId(s.collectionName)
+		if err != nil {
+			t.Errorf("[%s] Failed to fetch auth collection: %v", s.testName, err)
+		}
+
+		options := authCollection.AuthOptions()
+		options.Sessions = models.CollectionSessionsOptions{
+			Enabled:              s.enableSessions,
+			AccessTokenDuration:  100,
+			RefreshTokenDuration: 1000,
+		}
+		authCollection.SetOptions(options)
+
+		form := forms.NewRecordSessionRefresh(testApp, authCollection)
+		form.RefreshToken = s.refreshToken
+
+		record, session, err := form.Submit()
+
+		hasErr := err != nil
+		expectErr := s.expectedSessionId == ""
+		if hasErr != expectErr {
+			t.Errorf("[%s] Expected hasErr to be %v, got %v (%v)", s.testName, expectErr, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		if session.Id != s.expectedSessionId {
+			t.Errorf("[%s] Expected session %q, got %q", s.testName, s.expectedSessionId, session.Id)
+		}
+
+		if record.Id != session.RecordId {
+			t.Errorf("[%s] Expected session record %q, got %q", s.testName, session.RecordId, record.Id)
+		}
+	}
+}
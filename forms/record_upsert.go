@@ -1,15 +1,19 @@
 package forms
 
 import (
+	"bytes"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
 
+	"github.com/gabriel-vasile/mimetype"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/pocketbase/dbx"
@@ -18,35 +22,67 @@ import (
 	"github.com/pocketbase/pocketbase/forms/validators"
 	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/resolvers"
 	"github.com/pocketbase/pocketbase/tools/filesystem"
 	"github.com/pocketbase/pocketbase/tools/list"
 	"github.com/pocketbase/pocketbase/tools/rest"
+	"github.com/pocketbase/pocketbase/tools/search"
 	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
 	"github.com/spf13/cast"
 )
 
 // username value regex pattern
 var usernameRegex = regexp.MustCompile(`^[\w][\w\.]*$`)
 
+// phone number value regex pattern (E.164 format)
+var phoneRegex = regexp.MustCompile(`^\+[1-9]\d{6,14}$`)
+
+// stagedFilePrefix is the name prefix of the uploaded
+// record files that are not committed yet.
+const stagedFilePrefix = "staged_"
+
 // RecordUpsert is a [models.Record] upsert (create/update) form.
 type RecordUpsert struct {
 	app          core.App
 	dao          *daos.Dao
 	manageAccess bool
 	record       *models.Record
+	authRecord   *models.Record
+	apiKey       *models.ApiKey
 
 	filesToUpload map[string][]*filesystem.File
+	filesToCommit []string // staged uploads names list
 	filesToDelete []string // names list
 
+	// postpones the staged uploads swap and the old files deletion after submit
+	deferFilesCommit bool
+
+	// multi-value fields modifiers to reapply on submit
+	modifiers map[string]*fieldModifier
+
+	// the schema fields write rules check state and submitted fields
+	checkWriteRules bool
+	submittedFields map[string]struct{}
+
 	// base model fields
 	Id string `json:"id"`
 
+	// the expected record version
+	// (used only with enabled collection records versioning)
+	Version int `json:"version"`
+
+	// the external system primary key
+	// (used only with enabled collection external ids)
+	ExternalId string `json:"externalId"`
+
 	// auth collection fields
 	// ---
 	Username        string `json:"username"`
 	Email           string `json:"email"`
 	EmailVisibility bool   `json:"emailVisibility"`
 	Verified        bool   `json:"verified"`
+	Phone           string `json:"phone"` // used only with enabled phone auth
 	Password        string `json:"password"`
 	PasswordConfirm string `json:"passwordConfirm"`
 	OldPassword     string `json:"oldPassword"`
@@ -66,8 +102,9 @@ func NewRecordUpsert(app core.App, record *models.Record) *RecordUpsert {
 		app:           app,
 		dao:           app.Dao(),
 		record:        record,
-		filesToDelete: []string{},
-		filesToUpload: map[string][]*filesystem.File{},
+		filesToDelete:   []string{},
+		filesToUpload:   map[string][]*filesystem.File{},
+		submittedFields: map[string]struct{}{},
 	}
 
 	form.loadFormDefaults()
@@ -87,19 +124,79 @@ func (form *RecordUpsert) SetFullManageAccess(fullManageAccess bool) {
 	form.manageAccess = fullManageAccess
 }
 
+// SetCheckFieldWriteRules enables/disables the check of the schema
+// fields write rules on submit (disabled by default, eg. for admins).
+//
+// When enabled, the submit fails if a field with write rule was changed
+// and the saved record doesn't satisfy the rule.
+func (form *RecordUpsert) SetCheckFieldWriteRules(check bool) {
+	form.checkWriteRules = check
+}
+
 // SetDao replaces the default form Dao instance with the provided one.
 func (form *RecordUpsert) SetDao(dao *daos.Dao) {
 	form.dao = dao
 }
 
+// SetDeferFilesCommit enables/disables postponing the move of the
+// staged uploaded files to their record location and the deletion of
+// the replaced and removed record files on submit (eg. when the form is
+// submitted with the dao of an outer transaction that could be still reverted).
+//
+// When enabled, after the submit you have to call either
+// [RecordUpsert.CommitFiles] and [RecordUpsert.DeleteOldFiles]
+// (on successful commit) or [RecordUpsert.RollbackFiles] (on rollback).
+func (form *RecordUpsert) SetDeferFilesCommit(deferCommit bool) {
+	form.deferFilesCommit = deferCommit
+}
+
+// CommitFiles moves the staged uploaded files of an already
+// submitted form to their final record location.
+//
+// It is safe to call it multiple times (eg. to retry a partially failed commit).
+func (form *RecordUpsert) CommitFiles() (err error) {
+	form.filesToCommit, err = form.commitStagedFiles(form.filesToCommit)
+	return
+}
+
+// RollbackFiles deletes the staged uploaded files of an already
+// submitted form (eg. when the outer transaction of the submit was reverted).
+//
+// It is safe to call it multiple times.
+func (form *RecordUpsert) RollbackFiles() (err error) {
+	form.filesToCommit, err = form.deleteStagedFiles(form.filesToCommit)
+	return
+}
+
+// DeleteOldFiles deletes the replaced and removed record files
+// of an already submitted form.
+func (form *RecordUpsert) DeleteOldFiles() error {
+	return form.processFilesToDelete()
+}
+
+// SetAuthRecord sets the auth record of the request that is used
+// to resolve the "@request.auth.*" schema field default values.
+func (form *RecordUpsert) SetAuthRecord(authRecord *models.Record) {
+	form.authRecord = authRecord
+}
+
+// SetApiKey sets the api key of the request that is used
+// to resolve the "@request.apiKey.*" fields in the record validation rules.
+func (form *RecordUpsert) SetApiKey(apiKey *models.ApiKey) {
+	form.apiKey = apiKey
+}
+
 func (form *RecordUpsert) loadFormDefaults() {
 	form.Id = form.record.Id
+	form.Version = form.record.Version()
+	form.ExternalId = form.record.ExternalId()
 
 	if form.record.Collection().IsAuth() {
 		form.Username = form.record.Username()
 		form.Email = form.record.Email()
 		form.EmailVisibility = form.record.EmailVisibility()
 		form.Verified = form.record.Verified()
+		form.Phone = form.record.Phone()
 	}
 
 	form.data = map[string]any{}
@@ -138,7 +235,8 @@ func (form *RecordUpsert) extractJsonData(
 ) (map[string]any, map[string][]*filesystem.File, error) {
 	data := map[string]any{}
 
-	err := rest.CopyJsonBody(r, &data)
+	// preserve the big integers precision
+	err := rest.CopyJsonBodyUseNumber(r, &data)
 
 	if keyPrefix != "" {
 		parts := strings.Split(keyPrefix, ".")
@@ -179,7 +277,9 @@ func (form *RecordUpsert) extractMultipartFormData(
 			continue
 		}
 
-		field := form.record.Collection().Schema.GetFieldByName(key)
+		fieldName := strings.TrimSuffix(strings.TrimSuffix(key, AppendModifier), RemoveModifier)
+
+		field := form.record.Collection().Schema.GetFieldByName(fieldName)
 		if field != nil && list.ExistInSlice(field.Type, arrayValueSupportTypes) {
 			data[key] = values
 		} else {
@@ -193,23 +293,25 @@ func (form *RecordUpsert) extractMultipartFormData(
 			continue // not a file field
 		}
 
-		key := field.Name
-		fullKey := key
-		if keyPrefix != "" {
-			fullKey = keyPrefix + "." + key
-		}
+		// check also for the `field+` append modifier uploads
+		for _, key := range []string{field.Name, field.Name + AppendModifier} {
+			fullKey := key
+			if keyPrefix != "" {
+				fullKey = keyPrefix + "." + key
+			}
+
+			files, err := rest.FindUploadedFiles(r, fullKey)
+			if err != nil || len(files) == 0 {
+				if err != nil && err != http.ErrMissingFile && form.app.IsDebug() {
+					log.Printf("%q uploaded file error: %v\n", fullKey, err)
+				}
 
-		files, err := rest.FindUploadedFiles(r, fullKey)
-		if err != nil || len(files) == 0 {
-			if err != nil && err != http.ErrMissingFile && form.app.IsDebug() {
-				log.Printf("%q uploaded file error: %v\n", fullKey, err)
+				// skip invalid or missing file(s)
+				continue
 			}
 
-			// skip invalid or missing file(s)
-			continue
+			filesToUpload[key] = append(filesToUpload[key], files...)
 		}
-
-		filesToUpload[key] = append(filesToUpload[key], files...)
 	}
 
 	return data, filesToUpload, nil
@@ -224,6 +326,9 @@ func (form *RecordUpsert) extractMultipartFormData(
 // with the file index or filename (eg. `myfile.0`) and set it to null or empty string.
 // For single file upload fields, you can skip the index and directly
 // reset the field using its field name (eg. `myfile = null`).
+//
+// Items could be also appended or removed from a multi-value field
+// by suffixing its name with "+" or "-" (eg. `tags+`, `documents-`).
 func (form *RecordUpsert) LoadRequest(r *http.Request, keyPrefix string) error {
 	requestData, uploadedFiles, err := form.extractRequestData(r, keyPrefix)
 	if err != nil {
@@ -235,7 +340,15 @@ func (form *RecordUpsert) LoadRequest(r *http.Request, keyPrefix string) error {
 	}
 
 	for key, files := range uploadedFiles {
-		form.AddFiles(key, files...)
+		fieldKey := strings.TrimSuffix(key, AppendModifier)
+
+		if err := form.AddFiles(fieldKey, files...); err != nil {
+			return err
+		}
+
+		if fieldKey != key {
+			form.modifierFor(fieldKey)
+		}
 	}
 
 	return nil
@@ -270,10 +383,24 @@ func (form *RecordUpsert) AddFiles(key string, files ...*filesystem.File) error
 		return nil // nothing to upload
 	}
 
+	if options.SanitizeSvg {
+		sanitizedFiles := make([]*filesystem.File, len(files))
+		for i, f := range files {
+			sanitized, err := sanitizeSvgFile(f, options.MaxSize)
+			if err != nil {
+				return fmt.Errorf("failed to sanitize svg file %q: %w", f.OriginalName, err)
+			}
+			sanitizedFiles[i] = sanitized
+		}
+		files = sanitizedFiles
+	}
+
 	if form.filesToUpload == nil {
 		form.filesToUpload = map[string][]*filesystem.File{}
 	}
 
+	form.submittedFields[key] = struct{}{}
+
 	oldNames := list.ToUniqueStringSlice(form.data[key])
 
 	if options.MaxSelect == 1 {
@@ -317,6 +444,8 @@ func (form *RecordUpsert) RemoveFiles(key string, toDelete ...string) error {
 
 	existing := list.ToUniqueStringSlice(form.data[key])
 
+	form.submittedFields[key] = struct{}{}
+
 	// mark all files for deletion
 	if len(toDelete) == 0 {
 		toDelete = make([]string, len(existing))
@@ -350,11 +479,22 @@ func (form *RecordUpsert) RemoveFiles(key string, toDelete ...string) error {
 // with the file index or filename (eg. `myfile.0`) and set it to null or empty string.
 // For single file upload fields, you can skip the index and directly
 // reset the field using its field name (eg. `myfile = null`).
+//
+// To append or remove items from a multi-value field without replacing
+// its other items, you can suffix the field name with "+" or "-"
+// (eg. `tags+ = ["a", "b"]`, `tags- = "c"`). The modifiers are
+// reapplied on the latest persisted field value during submit.
 func (form *RecordUpsert) LoadData(requestData map[string]any) error {
 	// load base system fields
 	if v, ok := requestData["id"]; ok {
 		form.Id = cast.ToString(v)
 	}
+	if v, ok := requestData[schema.FieldNameVersion]; ok && form.record.Collection().VersioningOptions().Enabled {
+		form.Version = cast.ToInt(v)
+	}
+	if v, ok := requestData[schema.FieldNameExternalId]; ok && form.record.Collection().ExternalIdOptions().Enabled {
+		form.ExternalId = cast.ToString(v)
+	}
 
 	// load auth system fields
 	if form.record.Collection().IsAuth() {
@@ -370,6 +510,9 @@ func (form *RecordUpsert) LoadData(requestData map[string]any) error {
 		if v, ok := requestData["verified"]; ok {
 			form.Verified = cast.ToBool(v)
 		}
+		if v, ok := requestData[schema.FieldNamePhone]; ok && form.record.Collection().AuthOptions().AllowPhoneAuth {
+			form.Phone = cast.ToString(v)
+		}
 		if v, ok := requestData["password"]; ok {
 			form.Password = cast.ToString(v)
 		}
@@ -387,15 +530,48 @@ func (form *RecordUpsert) LoadData(requestData map[string]any) error {
 	if err != nil {
 		return err
 	}
-	if err := json.Unmarshal(rawData, &extendedData); err != nil {
+	decoder := json.NewDecoder(bytes.NewReader(rawData))
+	decoder.UseNumber() // preserve the big integers precision
+	if err := decoder.Decode(&extendedData); err != nil {
 		return err
 	}
 
 	for _, field := range form.record.Collection().Schema.Fields() {
+		if field.IsReadOnly() {
+			continue
+		}
+
 		key := field.Name
 		value := extendedData[key]
+
+		if isFieldSubmitted(requestData, key) {
+			form.submittedFields[key] = struct{}{}
+		}
+
+		// apply the field default value on create if not submitted
+		if _, ok := requestData[key]; !ok && form.record.IsNew() && field.Default != nil {
+			value = form.resolveDefault(field.Default)
+		}
+
 		value = field.PrepareValue(value)
 
+		// convert the json field big integers to strings (if globally enabled)
+		if raw, ok := value.(types.JsonRaw); ok && form.app.Settings().Json.BigIntStrings {
+			if normalized, err := types.StringifyBigInts(raw); err == nil {
+				value = normalized
+			}
+		}
+
+		// sanitize the document html content on write
+		if doc, ok := value.(types.Document); ok {
+			options, _ := field.Options.(*schema.DocumentOptions)
+			doc, err = options.Normalize(doc)
+			if err != nil {
+				return fmt.Errorf("failed to sanitize document field %q: %w", key, err)
+			}
+			value = field.PrepareValue(doc)
+		}
+
 		if field.Type != schema.FieldTypeFile {
 			form.data[key] = value
 			continue
@@ -438,6 +614,8 @@ func (form *RecordUpsert) LoadData(requestData map[string]any) error {
 		}
 	}
 
+	form.loadModifiers(requestData)
+
 	return nil
 }
 
@@ -453,6 +631,11 @@ func (form *RecordUpsert) Validate() error {
 				validation.Match(idRegex),
 			).Else(validation.In(form.record.Id)),
 		),
+		validation.Field(
+			&form.ExternalId,
+			validation.Length(1, 255),
+			validation.By(form.checkUniqueExternalId),
+		),
 	}
 
 	// auth fields validators
@@ -483,6 +666,17 @@ func (form *RecordUpsert) Validate() error {
 				validation.By(form.checkEmailDomain),
 				validation.By(form.checkUniqueEmail),
 			),
+			validation.Field(
+				&form.Phone,
+				// don't allow direct phone change (or unset) if the form doesn't have manage access permissions
+				// (aka. allow only admin or authorized auth models to directly update the field)
+				validation.When(
+					!form.record.IsNew() && !form.manageAccess,
+					validation.In(form.record.Phone()),
+				),
+				validation.Match(phoneRegex).Error("Must be a valid E.164 phone number (eg. +359888123456)."),
+				validation.By(form.checkUniquePhone),
+			),
 			validation.Field(
 				&form.Verified,
 				// don't allow changing verified if the form doesn't have manage access permissions
@@ -553,6 +747,44 @@ func (form *RecordUpsert) checkUniqueUsername(value any) error {
 	return nil
 }
 
+func (form *RecordUpsert) checkUniqueExternalId(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil
+	}
+
+	isUnique := form.dao.IsRecordValueUnique(
+		form.record.Collection().Id,
+		schema.FieldNameExternalId,
+		v,
+		form.record.Id,
+	)
+	if !isUnique {
+		return validation.NewError("validation_external_id_not_unique", "The external id is already in use.")
+	}
+
+	return nil
+}
+
+func (form *RecordUpsert) checkUniquePhone(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil
+	}
+
+	isUnique := form.dao.IsRecordValueUnique(
+		form.record.Collection().Id,
+		schema.FieldNamePhone,
+		v,
+		form.record.Id,
+	)
+	if !isUnique {
+		return validation.NewError("validation_invalid_phone", "The phone number is invalid or already in use.")
+	}
+
+	return nil
+}
+
 func (form *RecordUpsert) checkUniqueEmail(value any) error {
 	v, _ := value.(string)
 	if v == "" {
@@ -621,6 +853,15 @@ func (form *RecordUpsert) ValidateAndFill() error {
 		form.record.MarkAsNew()
 	}
 
+	// the version is auto assigned on create
+	if !isNew && form.record.Collection().VersioningOptions().Enabled {
+		form.record.SetVersion(form.Version)
+	}
+
+	if form.record.Collection().ExternalIdOptions().Enabled {
+		form.record.SetExternalId(form.ExternalId)
+	}
+
 	// set auth fields
 	if form.record.Collection().IsAuth() {
 		// generate a default username during create (if missing)
@@ -645,6 +886,12 @@ func (form *RecordUpsert) ValidateAndFill() error {
 			return err
 		}
 
+		if (isNew || form.manageAccess) && form.record.Collection().AuthOptions().AllowPhoneAuth {
+			if err := form.record.SetPhone(form.Phone); err != nil {
+				return err
+			}
+		}
+
 		if form.manageAccess {
 			if err := form.record.SetVerified(form.Verified); err != nil {
 				return err
@@ -684,6 +931,7 @@ func (form *RecordUpsert) DrySubmit(callback func(txDao *daos.Dao) error) error
 		// otherwise use the form noncurrent dao db pool
 		dryDao = daos.New(form.dao.NonconcurrentDB())
 	}
+	dryDao.SetEncryptionKey(form.dao.EncryptionKey())
 
 	return dryDao.RunInTransaction(func(txDao *daos.Dao) error {
 		tx, ok := txDao.DB().(*dbx.Tx)
@@ -724,21 +972,36 @@ func (form *RecordUpsert) Submit(interceptors ...InterceptorFunc) error {
 			form.record.MarkAsNew()
 		}
 
-		// upload new files (if any)
+		// upload the new files (if any) to their staging location
 		if err := form.processFilesToUpload(); err != nil {
 			return fmt.Errorf("failed to process the uploaded files: %w", err)
 		}
 
 		// persist the record model
-		if saveErr := form.dao.SaveRecord(form.record); saveErr != nil {
-			// try to cleanup the successfully uploaded files
-			if _, err := form.deleteFilesByNamesList(form.getFilesToUploadNames()); err != nil && form.app.IsDebug() {
+		if saveErr := form.saveRecord(); saveErr != nil {
+			// try to cleanup the staged files
+			if err := form.RollbackFiles(); err != nil && form.app.IsDebug() {
 				log.Println(err)
 			}
 
+			// return the failed field validation rules as regular validation errors
+			var rulesErr validation.Errors
+			if errors.As(saveErr, &rulesErr) {
+				return rulesErr
+			}
+
 			return fmt.Errorf("failed to save the record: %w", saveErr)
 		}
 
+		if form.deferFilesCommit {
+			return nil // processed with CommitFiles() and DeleteOldFiles()
+		}
+
+		// move the staged files to their record location
+		if err := form.CommitFiles(); err != nil {
+			return fmt.Errorf("failed to commit the uploaded files: %w", err)
+		}
+
 		// delete old files (if any)
 		//
 		// for now fail silently to avoid reupload when `form.Submit()`
@@ -751,16 +1014,114 @@ func (form *RecordUpsert) Submit(interceptors ...InterceptorFunc) error {
 	}, interceptors...)
 }
 
-func (form *RecordUpsert) getFilesToUploadNames() []string {
-	names := []string{}
+// saveRecord persists the form record model.
+//
+// If there are registered field modifiers, they are reapplied on the
+// latest record field values within the same save transaction.
+//
+// The schema fields validation and write rules (if any) are checked
+// against the saved record and the transaction is reverted on failure.
+func (form *RecordUpsert) saveRecord() error {
+	isNew := form.record.IsNew()
+	writtenFields := form.writtenFieldsWithRules()
+	hasRules := hasFieldValidationRules(form.record.Collection()) || len(writtenFields) > 0
+
+	if !hasRules && (isNew || len(form.modifiers) == 0) {
+		return form.dao.SaveRecord(form.record)
+	}
 
-	for fieldKey := range form.filesToUpload {
-		for _, file := range form.filesToUpload[fieldKey] {
-			names = append(names, file.Name)
+	version := form.record.Version()
+
+	err := form.dao.RunInTransaction(func(txDao *daos.Dao) error {
+		if !isNew && len(form.modifiers) > 0 {
+			if err := form.applyModifiersOnLatest(txDao); err != nil {
+				return err
+			}
+		}
+
+		if err := txDao.SaveRecord(form.record); err != nil {
+			return err
+		}
+
+		if err := form.checkFieldWriteRules(txDao, writtenFields); err != nil {
+			return err
+		}
+
+		return form.checkFieldValidationRules(txDao)
+	})
+
+	// restore the record state after the reverted save
+	if err != nil {
+		if isNew {
+			form.record.MarkAsNew()
+		}
+		if form.record.Collection().VersioningOptions().Enabled {
+			form.record.SetVersion(version)
 		}
 	}
 
-	return names
+	return err
+}
+
+// checkFieldValidationRules checks the persisted form record
+// against its collection schema fields validation rules.
+func (form *RecordUpsert) checkFieldValidationRules(txDao *daos.Dao) error {
+	collection := form.record.Collection()
+
+	requestData := &models.RequestData{
+		Data:       form.data,
+		AuthRecord: form.authRecord,
+		ApiKey:     form.apiKey,
+	}
+
+	errs := validation.Errors{}
+
+	for _, field := range collection.Schema.Fields() {
+		if field.Validation == "" {
+			continue
+		}
+
+		ruleFunc := func(q *dbx.SelectQuery) error {
+			resolver := resolvers.NewRecordFieldResolver(txDao, collection, requestData, true)
+			expr, err := search.FilterData(field.Validation).BuildExpr(resolver)
+			if err != nil {
+				return err
+			}
+			resolver.UpdateQuery(q)
+			q.AndWhere(expr)
+			return nil
+		}
+
+		_, err := txDao.FindRecordById(collection.Id, form.record.Id, ruleFunc)
+		if err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+
+			errs[field.Name] = validation.NewError(
+				"validation_rule_failed",
+				"The value doesn't satisfy the field validation rule.",
+			)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// hasFieldValidationRules checks whether the collection has
+// at least one schema field with validation rule.
+func hasFieldValidationRules(collection *models.Collection) bool {
+	for _, field := range collection.Schema.Fields() {
+		if field.Validation != "" {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (form *RecordUpsert) processFilesToUpload() error {
@@ -779,14 +1140,14 @@ func (form *RecordUpsert) processFilesToUpload() error {
 	defer fs.Close()
 
 	var uploadErrors []error // list of upload errors
-	var uploaded []string    // list of uploaded file paths
 
 	for fieldKey := range form.filesToUpload {
 		for i, file := range form.filesToUpload[fieldKey] {
-			path := form.record.BaseFilesPath() + "/" + file.Name
-			if err := fs.UploadFile(file, path); err == nil {
-				// keep track of the already uploaded file
-				uploaded = append(uploaded, path)
+			// the file is moved to its record location only after
+			// the record is persisted (see CommitFiles())
+			if err := fs.UploadFile(file, form.stagedFilePath(file.Name)); err == nil {
+				// keep track of the already staged file
+				form.filesToCommit = append(form.filesToCommit, file.Name)
 			} else {
 				// store the upload error
 				uploadErrors = append(uploadErrors, fmt.Errorf("file %d: %v", i, err))
@@ -795,8 +1156,8 @@ func (form *RecordUpsert) processFilesToUpload() error {
 	}
 
 	if len(uploadErrors) > 0 {
-		// cleanup - try to delete the successfully uploaded files (if any)
-		form.deleteFilesByNamesList(uploaded)
+		// cleanup - try to delete the successfully staged files (if any)
+		form.RollbackFiles()
 
 		return fmt.Errorf("failed to upload all files: %v", uploadErrors)
 	}
@@ -804,6 +1165,102 @@ func (form *RecordUpsert) processFilesToUpload() error {
 	return nil
 }
 
+// stagedFilePath returns the storage path of the specified staged record file.
+//
+// The staged files are stored next to the record files so that the
+// leftovers of interrupted submits are cleaned as regular orphan files.
+func (form *RecordUpsert) stagedFilePath(filename string) string {
+	return form.record.BaseFilesPath() + "/" + stagedFilePrefix + filename
+}
+
+// commitStagedFiles moves a list of staged record files to their
+// record location. Returns the failed/remaining files.
+//
+// Files that were already moved (eg. from a previous partially
+// failed commit) are skipped.
+func (form *RecordUpsert) commitStagedFiles(filenames []string) ([]string, error) {
+	if len(filenames) == 0 {
+		return filenames, nil // nothing to commit
+	}
+
+	fs, err := form.app.NewFilesystem()
+	if err != nil {
+		return filenames, err
+	}
+	defer fs.Close()
+
+	var commitErrors []error
+
+	for i := len(filenames) - 1; i >= 0; i-- {
+		filename := filenames[i]
+		stagedPath := form.stagedFilePath(filename)
+		path := form.record.BaseFilesPath() + "/" + filename
+
+		exists, err := fs.Exists(stagedPath)
+		if err == nil && !exists {
+			// already committed
+			if ok, _ := fs.Exists(path); !ok {
+				err = errors.New("missing staged file")
+			}
+		} else if err == nil {
+			err = fs.Rename(stagedPath, path)
+		}
+
+		if err == nil {
+			// remove the committed file from the list
+			filenames = append(filenames[:i], filenames[i+1:]...)
+		} else {
+			// store the commit error
+			commitErrors = append(commitErrors, fmt.Errorf("file %d: %v", i, err))
+		}
+	}
+
+	if len(commitErrors) > 0 {
+		return filenames, fmt.Errorf("failed to commit all files: %v", commitErrors)
+	}
+
+	return filenames, nil
+}
+
+// deleteStagedFiles deletes a list of staged record files.
+// Returns the failed/remaining files.
+func (form *RecordUpsert) deleteStagedFiles(filenames []string) ([]string, error) {
+	if len(filenames) == 0 {
+		return filenames, nil // nothing to delete
+	}
+
+	fs, err := form.app.NewFilesystem()
+	if err != nil {
+		return filenames, err
+	}
+	defer fs.Close()
+
+	var deleteErrors []error
+
+	for i := len(filenames) - 1; i >= 0; i-- {
+		stagedPath := form.stagedFilePath(filenames[i])
+
+		exists, err := fs.Exists(stagedPath)
+		if err == nil && exists {
+			err = fs.Delete(stagedPath)
+		}
+
+		if err == nil {
+			// remove the deleted file from the list
+			filenames = append(filenames[:i], filenames[i+1:]...)
+		} else {
+			// store the delete error
+			deleteErrors = append(deleteErrors, fmt.Errorf("file %d: %v", i, err))
+		}
+	}
+
+	if len(deleteErrors) > 0 {
+		return filenames, fmt.Errorf("failed to delete all staged files: %v", deleteErrors)
+	}
+
+	return filenames, nil
+}
+
 func (form *RecordUpsert) processFilesToDelete() (err error) {
 	form.filesToDelete, err = form.deleteFilesByNamesList(form.filesToDelete)
 	return
@@ -850,3 +1307,71 @@ func (form *RecordUpsert) deleteFilesByNamesList(filenames []string) ([]string,
 
 	return filenames, nil
 }
+
+// sanitizeSvgFile returns a new sanitized copy of the provided file
+// if it is an svg (otherwise the file is returned as it is).
+//
+// Files larger than maxSize are not sanitized since they will be
+// rejected by the form validations anyway.
+func sanitizeSvgFile(file *filesystem.File, maxSize int) (*filesystem.File, error) {
+	if int(file.Size) > maxSize {
+		return file, nil
+	}
+
+	r, err := file.Reader.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	filetype, err := mimetype.DetectReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !filetype.Is("image/svg+xml") {
+		return file, nil
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	sanitized, err := filesystem.SanitizeSvg(content)
+	if err != nil {
+		return nil, err
+	}
+
+	return &filesystem.File{
+		Name:         file.Name,
+		OriginalName: file.OriginalName,
+		Size:         int64(len(sanitized)),
+		Reader:       &filesystem.BytesReader{Bytes: sanitized},
+	}, nil
+}
+
+// resolveDefault returns the value of the provided schema field
+// default value (replacing the supported macros).
+func (form *RecordUpsert) resolveDefault(defaultValue any) any {
+	switch defaultValue {
+	case schema.DefaultValueNow:
+		return types.NowDateTime()
+	case schema.DefaultValueRequestAuthId:
+		if form.authRecord == nil {
+			return nil
+		}
+		return form.authRecord.Id
+	case schema.DefaultValueRequestAuthCollection:
+		if form.authRecord == nil {
+			return nil
+		}
+		return form.authRecord.Collection().Name
+	}
+
+	return defaultValue
+}
@@ -0,0 +1,143 @@
+package forms
+
+import (
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/forms/validators"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+const (
+	// AppendModifier is the multi-value field key suffix for appending
+	// new items to the existing field value (eg. `tags+ = ["a", "b"]`).
+	AppendModifier = "+"
+
+	// RemoveModifier is the multi-value field key suffix for removing
+	// items from the existing field value (eg. `tags- = ["a"]`).
+	RemoveModifier = "-"
+)
+
+// fieldModifier stores the items to append or remove from a multi-value field.
+type fieldModifier struct {
+	append []string
+	remove []string
+}
+
+// apply appends and removes the modifier items from the provided value.
+func (m *fieldModifier) apply(value any) []string {
+	result := list.ToUniqueStringSlice(value)
+	result = append(result, m.append...)
+
+	filtered := make([]string, 0, len(result))
+	for _, item := range result {
+		if !list.ExistInSlice(item, m.remove) {
+			filtered = append(filtered, item)
+		}
+	}
+
+	return list.ToUniqueStringSlice(filtered)
+}
+
+// isMultiValueField checks whether the provided field could store more than one item.
+func isMultiValueField(field *schema.SchemaField) bool {
+	switch options := field.Options.(type) {
+	case *schema.SelectOptions:
+		return options.MaxSelect > 1
+	case *schema.FileOptions:
+		return options.MaxSelect > 1
+	case *schema.RelationOptions:
+		return options.MaxSelect == nil || *options.MaxSelect > 1
+	}
+
+	return false
+}
+
+// modifierFor returns the registered field modifier (initializing it if missing).
+func (form *RecordUpsert) modifierFor(key string) *fieldModifier {
+	if form.modifiers == nil {
+		form.modifiers = map[string]*fieldModifier{}
+	}
+
+	if form.modifiers[key] == nil {
+		form.modifiers[key] = &fieldModifier{}
+	}
+
+	return form.modifiers[key]
+}
+
+// loadModifiers loads the `field+` and `field-` multi-value fields
+// modifiers from the provided request data.
+//
+// The modifiers of fields that are not directly set in the request data
+// are also registered to be reapplied on the latest persisted field value
+// during submit (see [RecordUpsert.applyModifiersOnLatest]).
+func (form *RecordUpsert) loadModifiers(requestData map[string]any) {
+	for _, field := range form.record.Collection().Schema.Fields() {
+		if !isMultiValueField(field) {
+			continue
+		}
+
+		key := field.Name
+
+		appendValue, hasAppend := requestData[key+AppendModifier]
+		removeValue, hasRemove := requestData[key+RemoveModifier]
+		if !hasAppend && !hasRemove {
+			continue
+		}
+
+		modifier := &fieldModifier{
+			remove: list.ToUniqueStringSlice(removeValue),
+		}
+
+		if field.Type == schema.FieldTypeFile {
+			// new files could be appended only by uploading them
+			if len(modifier.remove) > 0 {
+				form.RemoveFiles(key, modifier.remove...)
+			}
+		} else {
+			modifier.append = list.ToUniqueStringSlice(appendValue)
+			form.data[key] = field.PrepareValue(modifier.apply(form.data[key]))
+		}
+
+		if _, isReplaced := requestData[key]; !isReplaced {
+			registered := form.modifierFor(key)
+			registered.append = append(registered.append, modifier.append...)
+			registered.remove = append(registered.remove, modifier.remove...)
+		}
+	}
+}
+
+// applyModifiersOnLatest reapplies the registered field modifiers
+// on the latest persisted record field values.
+//
+// This method is expected to be called within the record save transaction
+// to avoid overwriting concurrent changes of the same multi-value fields.
+func (form *RecordUpsert) applyModifiersOnLatest(txDao *daos.Dao) error {
+	latest, err := txDao.FindRecordById(form.record.Collection().Id, form.record.Id)
+	if err != nil {
+		return err
+	}
+
+	for key, modifier := range form.modifiers {
+		field := form.record.Collection().Schema.GetFieldByName(key)
+		if field == nil {
+			continue
+		}
+
+		value := list.ToUniqueStringSlice(latest.Get(key))
+
+		if field.Type == schema.FieldTypeFile {
+			for _, f := range form.filesToUpload[key] {
+				value = append(value, f.Name)
+			}
+		}
+
+		form.record.Set(key, field.PrepareValue(modifier.apply(value)))
+	}
+
+	return validators.NewRecordDataValidator(
+		txDao,
+		form.record,
+		form.filesToUpload,
+	).Validate(form.record.SchemaData())
+}
@@ -11,14 +11,20 @@ import (
 	"strings"
 	"testing"
 
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/daos"
 	"github.com/pocketbase/pocketbase/forms"
 	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/resolvers"
 	"github.com/pocketbase/pocketbase/tests"
 	"github.com/pocketbase/pocketbase/tools/filesystem"
 	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/types"
 )
 
 func hasRecordFile(app core.App, record *models.Record, filename string) bool {
@@ -225,6 +231,615 @@ func TestRecordUpsertLoadData(t *testing.T) {
 	}
 }
 
+func TestRecordUpsertLoadDataComputedField(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	collection.Schema.AddField(&schema.SchemaField{
+		Name:    "computed",
+		Type:    schema.FieldTypeComputed,
+		Options: &schema.ComputedOptions{Expression: "number + 1"},
+	})
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := app.Dao().FindRecordById(collection.Id, "al1h9ijdeojtsjy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewRecordUpsert(app, record)
+
+	loadErr := form.LoadData(map[string]any{
+		"number":   10,
+		"computed": 100,
+	})
+	if loadErr != nil {
+		t.Fatal(loadErr)
+	}
+
+	if v := form.Data()["computed"]; v != 457.0 {
+		t.Fatalf("Expected the computed field value to be unchanged, got %v", v)
+	}
+
+	if err := form.Submit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := record.Get("computed"); v != 11.0 {
+		t.Fatalf("Expected the computed field value to be refreshed, got %v", v)
+	}
+}
+
+func TestRecordUpsertLoadDataDocumentField(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	collection.Schema.AddField(&schema.SchemaField{
+		Name:    "content",
+		Type:    schema.FieldTypeDocument,
+		Options: &schema.DocumentOptions{PlainText: true},
+	})
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := app.Dao().FindRecordById(collection.Id, "al1h9ijdeojtsjy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewRecordUpsert(app, record)
+
+	// only denied content
+	if err := form.LoadData(map[string]any{"content": "<script>alert(1)</script>"}); err != nil {
+		t.Fatal(err)
+	}
+	if v := form.Data()["content"]; v != nil {
+		t.Fatalf("Expected the empty document to be normalized to nil, got %v", v)
+	}
+
+	loadErr := form.LoadData(map[string]any{
+		"content": map[string]any{
+			"html": `<p onclick="alert(1)">Hello <script>alert(2)</script><b>world</b></p>`,
+			"text": "invalid",
+		},
+	})
+	if loadErr != nil {
+		t.Fatal(loadErr)
+	}
+
+	expected := types.Document{Html: "<p>Hello <b>world</b></p>", Text: "Hello world"}
+
+	if v := form.Data()["content"]; v != expected {
+		t.Fatalf("Expected document %v, got %v", expected, v)
+	}
+
+	if err := form.Submit(); err != nil {
+		t.Fatal(err)
+	}
+
+	// select explicitly the column to avoid the stale prepared statements columns
+	stored := struct {
+		Text string `db:"text"`
+	}{}
+	err = app.Dao().DB().
+		Select("JSON_EXTRACT([[content]], '$.text') as text").
+		From(collection.Name).
+		Where(dbx.HashExp{"id": record.Id}).
+		One(&stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Text != expected.Text {
+		t.Fatalf("Expected stored plain text %q, got %q", expected.Text, stored.Text)
+	}
+}
+
+func TestRecordUpsertLoadRequestBigInts(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := &models.Collection{
+		Name: "bigints",
+		Type: models.CollectionTypeBase,
+		Schema: schema.NewSchema(
+			&schema.SchemaField{
+				Name:    "big",
+				Type:    schema.FieldTypeNumber,
+				Options: &schema.NumberOptions{BigInt: true},
+			},
+			&schema.SchemaField{
+				Name: "meta",
+				Type: schema.FieldTypeJson,
+			},
+		),
+	}
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	submit := func(body string) *models.Record {
+		record := models.NewRecord(collection)
+
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		form := forms.NewRecordUpsert(app, record)
+		if err := form.LoadRequest(req, ""); err != nil {
+			t.Fatal(err)
+		}
+		if err := form.Submit(); err != nil {
+			t.Fatal(err)
+		}
+
+		return record
+	}
+
+	// exact values without any setting
+	record := submit(`{"big":1234567890123456789,"meta":{"id":1234567890123456789}}`)
+
+	if v := record.Get("big"); v != types.BigInt(1234567890123456789) {
+		t.Fatalf("Expected the exact big int value, got %v", v)
+	}
+
+	if v := record.GetString("meta"); v != `{"id":1234567890123456789}` {
+		t.Fatalf("Expected the exact json value, got %v", v)
+	}
+
+	encoded, _ := json.Marshal(record)
+	if !strings.Contains(string(encoded), `"big":"1234567890123456789"`) {
+		t.Fatalf("Expected the big int to be serialized as string, got %s", encoded)
+	}
+
+	// filter with the exact value
+	expr, err := search.FilterData("big = 1234567890123456789").BuildExpr(
+		resolvers.NewRecordFieldResolver(app.Dao(), collection, nil, false),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := []*models.Record{}
+	if err := app.Dao().RecordQuery(collection).AndWhere(expr).All(&found); err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].Id != record.Id {
+		t.Fatalf("Expected only record %q to be found, got %v", record.Id, found)
+	}
+
+	// stringified json big ints
+	app.Settings().Json.BigIntStrings = true
+
+	record = submit(`{"big":"42","meta":{"id":1234567890123456789,"small":1}}`)
+
+	if v := record.Get("big"); v != types.BigInt(42) {
+		t.Fatalf("Expected big to be 42, got %v", v)
+	}
+
+	if v := record.GetString("meta"); v != `{"id":"1234567890123456789","small":1}` {
+		t.Fatalf("Expected the json big ints to be stringified, got %v", v)
+	}
+}
+
+func TestRecordUpsertLoadDataDefaults(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	authRecord, err := app.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection := &models.Collection{}
+	collection.Name = "test"
+	collection.Schema.AddField(&schema.SchemaField{
+		Name:    "title",
+		Type:    schema.FieldTypeText,
+		Default: "untitled",
+	})
+	collection.Schema.AddField(&schema.SchemaField{
+		Name:    "published",
+		Type:    schema.FieldTypeDate,
+		Default: schema.DefaultValueNow,
+	})
+	collection.Schema.AddField(&schema.SchemaField{
+		Name:    "author",
+		Type:    schema.FieldTypeText,
+		Default: schema.DefaultValueRequestAuthId,
+	})
+	collection.Schema.AddField(&schema.SchemaField{
+		Name:    "authorCollection",
+		Type:    schema.FieldTypeText,
+		Default: schema.DefaultValueRequestAuthCollection,
+	})
+
+	// new record
+	// ---
+	form := forms.NewRecordUpsert(app, models.NewRecord(collection))
+	form.SetAuthRecord(authRecord)
+
+	if err := form.LoadData(map[string]any{"title": ""}); err != nil {
+		t.Fatal(err)
+	}
+
+	data := form.Data()
+
+	if v := data["title"]; v != "" {
+		t.Fatalf("Expected the submitted empty title to be preserved, got %v", v)
+	}
+	if v, _ := data["published"].(types.DateTime); v.IsZero() {
+		t.Fatalf("Expected published to be set to the current date, got %v", data["published"])
+	}
+	if v := data["author"]; v != authRecord.Id {
+		t.Fatalf("Expected author %q, got %v", authRecord.Id, v)
+	}
+	if v := data["authorCollection"]; v != "users" {
+		t.Fatalf("Expected authorCollection %q, got %v", "users", v)
+	}
+
+	// new record without auth and submitted title
+	// ---
+	guestForm := forms.NewRecordUpsert(app, models.NewRecord(collection))
+
+	if err := guestForm.LoadData(map[string]any{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := guestForm.Data()["title"]; v != "untitled" {
+		t.Fatalf("Expected the default title, got %v", v)
+	}
+	if v := guestForm.Data()["author"]; v != "" {
+		t.Fatalf("Expected empty author, got %v", v)
+	}
+
+	// existing record
+	// ---
+	existing := models.NewRecord(collection)
+	existing.MarkAsNotNew()
+
+	updateForm := forms.NewRecordUpsert(app, existing)
+	updateForm.SetAuthRecord(authRecord)
+
+	if err := updateForm.LoadData(map[string]any{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := updateForm.Data()["title"]; v != "" {
+		t.Fatalf("Expected the default to not be applied on update, got %v", v)
+	}
+	if v := updateForm.Data()["author"]; v != "" {
+		t.Fatalf("Expected the default to not be applied on update, got %v", v)
+	}
+}
+
+func TestRecordUpsertFieldValidationRules(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := &models.Collection{}
+	collection.Name = "prices"
+	collection.Type = models.CollectionTypeBase
+	collection.Schema.AddField(&schema.SchemaField{
+		Name:       "minPrice",
+		Type:       schema.FieldTypeNumber,
+		Validation: "minPrice >= 0",
+	})
+	collection.Schema.AddField(&schema.SchemaField{
+		Name:       "maxPrice",
+		Type:       schema.FieldTypeNumber,
+		Validation: "maxPrice >= minPrice",
+	})
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name           string
+		data           map[string]any
+		expectedErrors []string
+	}{
+		{
+			"failed single rule",
+			map[string]any{"minPrice": 10, "maxPrice": 5},
+			[]string{"maxPrice"},
+		},
+		{
+			"failed multiple rules",
+			map[string]any{"minPrice": -10, "maxPrice": -20},
+			[]string{"minPrice", "maxPrice"},
+		},
+		{
+			"satisfied rules",
+			map[string]any{"minPrice": 5, "maxPrice": 10},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		record := models.NewRecord(collection)
+
+		form := forms.NewRecordUpsert(app, record)
+		if err := form.LoadData(s.data); err != nil {
+			t.Errorf("[%s] Failed to load the form data: %v", s.name, err)
+			continue
+		}
+
+		result := form.Submit()
+
+		// parse errors
+		errs, ok := result.(validation.Errors)
+		if !ok && result != nil {
+			t.Errorf("[%s] Failed to parse errors %v", s.name, result)
+			continue
+		}
+
+		// check errors
+		if len(errs) != len(s.expectedErrors) {
+			t.Errorf("[%s] Expected error keys %v, got %v", s.name, s.expectedErrors, errs)
+		}
+		for _, k := range s.expectedErrors {
+			if _, ok := errs[k]; !ok {
+				t.Errorf("[%s] Missing expected error key %q in %v", s.name, k, errs)
+			}
+		}
+
+		_, findErr := app.Dao().FindRecordById(collection.Id, record.Id)
+		if len(s.expectedErrors) > 0 {
+			if findErr == nil {
+				t.Errorf("[%s] Expected the record to not be persisted", s.name)
+			}
+			if !record.IsNew() {
+				t.Errorf("[%s] Expected the record to be restored as new", s.name)
+			}
+		} else if findErr != nil {
+			t.Errorf("[%s] Expected the record to be persisted, got %v", s.name, findErr)
+		}
+	}
+}
+
+func TestRecordUpsertFieldWriteRules(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := &models.Collection{}
+	collection.Name = "salaries"
+	collection.Type = models.CollectionTypeBase
+	collection.Schema.AddField(&schema.SchemaField{
+		Name: "title",
+		Type: schema.FieldTypeText,
+	})
+	collection.Schema.AddField(&schema.SchemaField{
+		Name:      "salary",
+		Type:      schema.FieldTypeNumber,
+		WriteRule: `@request.auth.username = "users75657"`,
+	})
+	collection.Schema.AddField(&schema.SchemaField{
+		Name:      "status",
+		Type:      schema.FieldTypeText,
+		Default:   "pending",
+		WriteRule: `@request.auth.id = "missing"`,
+	})
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := app.Dao().FindAuthRecordByUsername("users", "users75657")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherUser, err := app.Dao().FindAuthRecordByUsername("users", "test2_username")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	existing := models.NewRecord(collection)
+	existing.Set("title", "test")
+	existing.Set("salary", 10)
+	existing.Set("status", "active")
+	if err := app.Dao().SaveRecord(existing); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name           string
+		recordId       string
+		check          bool
+		authRecord     *models.Record
+		data           map[string]any
+		expectedErrors []string
+	}{
+		{
+			"disabled check",
+			"",
+			false,
+			nil,
+			map[string]any{"salary": 100, "status": "active"},
+			[]string{},
+		},
+		{
+			"create with unsatisfied rule",
+			"",
+			true,
+			otherUser,
+			map[string]any{"title": "test", "salary": 100},
+			[]string{"salary"},
+		},
+		{
+			"create without restricted fields (the default value is not checked)",
+			"",
+			true,
+			nil,
+			map[string]any{"title": "test"},
+			[]string{},
+		},
+		{
+			"create with satisfied rule",
+			"",
+			true,
+			user,
+			map[string]any{"title": "test", "salary": 100},
+			[]string{},
+		},
+		{
+			"create with explicit default value",
+			"",
+			true,
+			user,
+			map[string]any{"status": "pending"},
+			[]string{"status"},
+		},
+		{
+			"update with unchanged restricted fields",
+			existing.Id,
+			true,
+			otherUser,
+			map[string]any{"title": "new", "salary": 10, "status": "active"},
+			[]string{},
+		},
+		{
+			"update with changed restricted fields",
+			existing.Id,
+			true,
+			otherUser,
+			map[string]any{"salary": 20, "status": "draft"},
+			[]string{"salary", "status"},
+		},
+	}
+
+	for _, s := range scenarios {
+		record := models.NewRecord(collection)
+		if s.recordId != "" {
+			record, err = app.Dao().FindRecordById(collection.Id, s.recordId)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		form := forms.NewRecordUpsert(app, record)
+		form.SetAuthRecord(s.authRecord)
+		form.SetCheckFieldWriteRules(s.check)
+		if err := form.LoadData(s.data); err != nil {
+			t.Errorf("[%s] Failed to load the form data: %v", s.name, err)
+			continue
+		}
+
+		result := form.Submit()
+
+		// parse errors
+		errs, ok := result.(validation.Errors)
+		if !ok && result != nil {
+			t.Errorf("[%s] Failed to parse errors %v", s.name, result)
+			continue
+		}
+
+		// check errors
+		if len(errs) != len(s.expectedErrors) {
+			t.Errorf("[%s] Expected error keys %v, got %v", s.name, s.expectedErrors, errs)
+		}
+		for _, k := range s.expectedErrors {
+			if _, ok := errs[k]; !ok {
+				t.Errorf("[%s] Missing expected error key %q in %v", s.name, k, errs)
+			}
+		}
+	}
+
+	// the failed update should be reverted
+	existing, err = app.Dao().FindRecordById(collection.Id, existing.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := existing.GetInt("salary"); v != 10 {
+		t.Fatalf("Expected the salary to be unchanged, got %d", v)
+	}
+}
+
+func TestRecordUpsertUniqueConstraints(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := &models.Collection{}
+	collection.Name = "pairs"
+	collection.Type = models.CollectionTypeBase
+	collection.Schema.AddField(&schema.SchemaField{Name: "a", Type: schema.FieldTypeText})
+	collection.Schema.AddField(&schema.SchemaField{Name: "b", Type: schema.FieldTypeText})
+	collection.Schema.AddField(&schema.SchemaField{Name: "c", Type: schema.FieldTypeText})
+	collection.Options = types.JsonMap{
+		"uniqueConstraints": []map[string]any{{"name": "a_b", "fields": []string{"a", "b"}}},
+	}
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	existing := models.NewRecord(collection)
+	existing.Load(map[string]any{"a": "1", "b": "2"})
+	if err := app.Dao().SaveRecord(existing); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name           string
+		record         *models.Record
+		data           map[string]any
+		expectedErrors []string
+	}{
+		{
+			"create with existing values combination",
+			models.NewRecord(collection),
+			map[string]any{"a": "1", "b": "2", "c": "3"},
+			[]string{"a", "b"},
+		},
+		{
+			"create with new values combination",
+			models.NewRecord(collection),
+			map[string]any{"a": "1", "b": "3"},
+			[]string{},
+		},
+		{
+			"update with unchanged values combination",
+			existing,
+			map[string]any{"c": "3"},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		form := forms.NewRecordUpsert(app, s.record)
+		if err := form.LoadData(s.data); err != nil {
+			t.Errorf("[%s] Failed to load the form data: %v", s.name, err)
+			continue
+		}
+
+		result := form.Validate()
+
+		// parse errors
+		errs, ok := result.(validation.Errors)
+		if !ok && result != nil {
+			t.Errorf("[%s] Failed to parse errors %v", s.name, result)
+			continue
+		}
+
+		// check errors
+		if len(errs) != len(s.expectedErrors) {
+			t.Errorf("[%s] Expected error keys %v, got %v", s.name, s.expectedErrors, errs)
+		}
+		for _, k := range s.expectedErrors {
+			if _, ok := errs[k]; !ok {
+				t.Errorf("[%s] Missing expected error key %q in %v", s.name, k, errs)
+			}
+		}
+	}
+}
+
 func TestRecordUpsertDrySubmitFailure(t *testing.T) {
 	app, _ := tests.NewTestApp()
 	defer app.Cleanup()
@@ -942,6 +1557,75 @@ func TestRecordUpsertAuthRecord(t *testing.T) {
 	}
 }
 
+func TestRecordUpsertAuthRecordPhone(t *testing.T) {
+	scenarios := []struct {
+		testName      string
+		existingId    string
+		phone         string
+		manageAccess  bool
+		expectError   bool
+		expectedPhone string
+	}{
+		{"create with invalid phone", "", "0888123456", false, true, ""},
+		{"create with duplicated phone", "", "+359888123456", false, true, ""},
+		{"create with valid phone", "", "+359888000000", false, false, "+359888000000"},
+		{"update phone without managed access", "oap640cot4yru2s", "+359888000000", false, true, ""},
+		{"update phone with managed access", "oap640cot4yru2s", "+359888000000", true, false, "+359888000000"},
+		{"unset phone with managed access", "4q1xlclmfloku33", "", true, false, ""},
+	}
+
+	for _, s := range scenarios {
+		app, _ := tests.NewTestApp()
+		defer app.Cleanup()
+
+		collection, _ := enableUsersPhoneAuth(t, app)
+
+		record := models.NewRecord(collection)
+		if s.existingId != "" {
+			var err error
+			record, err = app.Dao().FindRecordById(collection.Id, s.existingId)
+			if err != nil {
+				t.Errorf("[%s] Failed to fetch auth record with id %s", s.testName, s.existingId)
+				continue
+			}
+		}
+
+		form := forms.NewRecordUpsert(app, record)
+		form.SetFullManageAccess(s.manageAccess)
+		data := map[string]any{"phone": s.phone}
+		if record.IsNew() {
+			data["password"] = "12345678"
+			data["passwordConfirm"] = "12345678"
+		}
+		if err := form.LoadData(data); err != nil {
+			t.Errorf("[%s] Failed to load form data", s.testName)
+			continue
+		}
+
+		submitErr := form.Submit()
+
+		hasErr := submitErr != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.testName, s.expectError, hasErr, submitErr)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		found, err := app.Dao().FindRecordById(collection.Id, record.Id)
+		if err != nil {
+			t.Errorf("[%s] Failed to fetch the saved record: %v", s.testName, err)
+			continue
+		}
+
+		if found.Phone() != s.expectedPhone {
+			t.Errorf("[%s] Expected phone %q, got %q", s.testName, s.expectedPhone, found.Phone())
+		}
+	}
+}
+
 func TestRecordUpsertAddAndRemoveFiles(t *testing.T) {
 	app, _ := tests.NewTestApp()
 	defer app.Cleanup()
@@ -1013,3 +1697,296 @@ func TestRecordUpsertAddAndRemoveFiles(t *testing.T) {
 		t.Fatalf("Expected file_many to be 3, got %v", fileMany)
 	}
 }
+
+func TestRecordUpsertDeferFilesCommit(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.Dao().FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewRecordUpsert(app, record)
+	form.SetDeferFilesCommit(true)
+	form.RemoveFiles("file_many", "300_WlbFWSGmW9.png")
+
+	f, _ := filesystem.NewFileFromBytes([]byte("test"), "new.txt")
+	form.AddFiles("file_many", f)
+
+	if err := form.Submit(); err != nil {
+		t.Fatalf("Failed to submit the RecordUpsert form, got %v", err)
+	}
+
+	if !hasRecordFile(app, record, "300_WlbFWSGmW9.png") {
+		t.Fatal("Expected 300_WlbFWSGmW9.png to be deleted only after DeleteOldFiles()")
+	}
+
+	if hasRecordFile(app, record, f.Name) {
+		t.Fatalf("Expected %s to be moved in place only after CommitFiles()", f.Name)
+	}
+
+	if !hasRecordFile(app, record, "staged_"+f.Name) {
+		t.Fatalf("Expected %s to be staged", f.Name)
+	}
+
+	// commit multiple times to ensure that it is idempotent
+	for i := 0; i < 2; i++ {
+		if err := form.CommitFiles(); err != nil {
+			t.Fatalf("[%d] Failed to commit the files: %v", i, err)
+		}
+
+		if !hasRecordFile(app, record, f.Name) {
+			t.Fatalf("[%d] Expected %s to be committed", i, f.Name)
+		}
+
+		if hasRecordFile(app, record, "staged_"+f.Name) {
+			t.Fatalf("[%d] Expected the staged %s to be removed", i, f.Name)
+		}
+	}
+
+	if err := form.DeleteOldFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	if hasRecordFile(app, record, "300_WlbFWSGmW9.png") {
+		t.Fatal("Expected 300_WlbFWSGmW9.png to be deleted")
+	}
+}
+
+func TestRecordUpsertDeferFilesRollback(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.Dao().FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewRecordUpsert(app, record)
+	form.SetDeferFilesCommit(true)
+	form.RemoveFiles("file_many", "300_WlbFWSGmW9.png")
+
+	f, _ := filesystem.NewFileFromBytes([]byte("test"), "new.txt")
+	form.AddFiles("file_many", f)
+
+	if err := form.Submit(); err != nil {
+		t.Fatalf("Failed to submit the RecordUpsert form, got %v", err)
+	}
+
+	// rollback multiple times to ensure that it is idempotent
+	for i := 0; i < 2; i++ {
+		if err := form.RollbackFiles(); err != nil {
+			t.Fatalf("[%d] Failed to rollback the files: %v", i, err)
+		}
+
+		if hasRecordFile(app, record, f.Name) || hasRecordFile(app, record, "staged_"+f.Name) {
+			t.Fatalf("[%d] Expected %s to be deleted", i, f.Name)
+		}
+
+		if !hasRecordFile(app, record, "300_WlbFWSGmW9.png") {
+			t.Fatalf("[%d] Expected 300_WlbFWSGmW9.png to be kept", i)
+		}
+	}
+
+	// nothing to commit after rollback
+	if err := form.CommitFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	if hasRecordFile(app, record, f.Name) {
+		t.Fatalf("Expected %s to not be committed", f.Name)
+	}
+}
+
+func TestRecordUpsertAddFilesSanitizeSvg(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.Dao().FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	field := record.Collection().Schema.GetFieldByName("file_many")
+	field.Options.(*schema.FileOptions).SanitizeSvg = true
+
+	form := forms.NewRecordUpsert(app, record)
+
+	// invalid svg
+	invalidSvg, _ := filesystem.NewFileFromBytes([]byte(`<svg xmlns="http://www.w3.org/2000/svg"><g></svg>`), "invalid.svg")
+	if err := form.AddFiles("file_many", invalidSvg); err == nil {
+		t.Fatal("Expected the invalid svg to be rejected")
+	}
+
+	// not an svg
+	txt, _ := filesystem.NewFileFromBytes([]byte(`<script>alert(1)</script>`), "test.txt")
+
+	// dangerous svg
+	svg, _ := filesystem.NewFileFromBytes(
+		[]byte(`<svg xmlns="http://www.w3.org/2000/svg" onload="alert(1)"><script>alert(2)</script><circle r="1"/></svg>`),
+		"test.svg",
+	)
+
+	if err := form.AddFiles("file_many", txt, svg); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := form.Submit(); err != nil {
+		t.Fatalf("Failed to submit the RecordUpsert form, got %v", err)
+	}
+
+	scenarios := []struct {
+		name     string
+		expected string
+	}{
+		{txt.Name, `<script>alert(1)</script>`},
+		{svg.Name, `<svg xmlns="http://www.w3.org/2000/svg"><circle r="1"></circle></svg>`},
+	}
+
+	for _, s := range scenarios {
+		content, err := os.ReadFile(filepath.Join(app.DataDir(), "storage", record.BaseFilesPath(), s.name))
+		if err != nil {
+			t.Errorf("Failed to read %s: %v", s.name, err)
+			continue
+		}
+
+		if string(content) != s.expected {
+			t.Errorf("Expected %s content \n%s, \ngot \n%s", s.name, s.expected, content)
+		}
+	}
+}
+
+func TestRecordUpsertLoadDataModifiers(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.Dao().FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewRecordUpsert(app, record)
+
+	loadErr := form.LoadData(map[string]any{
+		"select_many":  []string{"optionA"},
+		"select_many+": "optionB",
+		"rel_many+":    []string{"4q1xlclmfloku33", "oap640cot4yru2s"},
+		"rel_many-":    "oap640cot4yru2s",
+		"file_many-":   []string{"300_WlbFWSGmW9.png"},
+		"select_one+":  "optionA", // single value fields are not modifiable
+	})
+	if loadErr != nil {
+		t.Fatal(loadErr)
+	}
+
+	scenarios := map[string]string{
+		"select_many": `["optionA","optionB"]`,
+		"rel_many":    `["4q1xlclmfloku33"]`,
+		"file_many":   `["test_QZFjKjXchk.txt","logo_vcfJJG5TAh.svg"]`,
+		"select_one":  `"optionB"`,
+	}
+
+	for key, expected := range scenarios {
+		raw, _ := json.Marshal(form.Data()[key])
+		if string(raw) != expected {
+			t.Errorf("Expected %s to be %s, got %s", key, expected, raw)
+		}
+	}
+}
+
+func TestRecordUpsertSubmitModifiersOnLatest(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.Dao().FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewRecordUpsert(app, record)
+
+	loadErr := form.LoadData(map[string]any{
+		"select_many-": "optionB",
+		"rel_many+":    "4q1xlclmfloku33",
+		"file_many-":   "300_WlbFWSGmW9.png",
+	})
+	if loadErr != nil {
+		t.Fatal(loadErr)
+	}
+
+	// concurrent change of the same fields after the form load
+	concurrent, err := app.Dao().FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	concurrent.Set("select_many", []string{"optionA", "optionB", "optionC"})
+	concurrent.Set("rel_many", []string{"oap640cot4yru2s", "bgs820n361vj1qd"})
+	if err := app.Dao().SaveRecord(concurrent); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := form.Submit(); err != nil {
+		t.Fatalf("Failed to submit the RecordUpsert form, got %v", err)
+	}
+
+	recordAfter, err := app.Dao().FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := map[string]string{
+		"select_many": `["optionA","optionC"]`,
+		"rel_many":    `["oap640cot4yru2s","bgs820n361vj1qd","4q1xlclmfloku33"]`,
+		"file_many":   `["test_QZFjKjXchk.txt","logo_vcfJJG5TAh.svg"]`,
+	}
+
+	for key, expected := range scenarios {
+		raw, _ := json.Marshal(recordAfter.Get(key))
+		if string(raw) != expected {
+			t.Errorf("Expected %s to be %s, got %s", key, expected, raw)
+		}
+	}
+
+	if hasRecordFile(app, recordAfter, "300_WlbFWSGmW9.png") {
+		t.Fatal("Expected 300_WlbFWSGmW9.png to be deleted")
+	}
+}
+
+func TestRecordUpsertLoadRequestMultipartAppendModifier(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.Dao().FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	formData, mp, err := tests.MockMultipartData(map[string]string{
+		"file_many-": "300_WlbFWSGmW9.png",
+	}, "file_many+")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewRecordUpsert(app, record)
+	req := httptest.NewRequest(http.MethodGet, "/", formData)
+	req.Header.Set(echo.HeaderContentType, mp.FormDataContentType())
+	if err := form.LoadRequest(req, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := form.Submit(); err != nil {
+		t.Fatalf("Failed to submit the RecordUpsert form, got %v", err)
+	}
+
+	recordAfter, err := app.Dao().FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fileMany := recordAfter.GetStringSlice("file_many")
+	if len(fileMany) != 3 || list.ExistInSlice("300_WlbFWSGmW9.png", fileMany) {
+		t.Fatalf("Expected 3 file_many files with appended upload, got %v", fileMany)
+	}
+}
@@ -0,0 +1,119 @@
+package forms
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/resolvers"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+// isFieldSubmitted checks whether the request data contains a value,
+// a modifier (eg. "tags+") or an indexed key (eg. "files.0") of the field.
+func isFieldSubmitted(requestData map[string]any, key string) bool {
+	for k := range requestData {
+		if k == key ||
+			k == key+AppendModifier ||
+			k == key+RemoveModifier ||
+			strings.HasPrefix(k, key+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writtenFieldsWithRules returns the submitted schema fields with
+// write rule whose value differs from the original record value.
+//
+// Returns nil if the write rules check is disabled.
+func (form *RecordUpsert) writtenFieldsWithRules() []*schema.SchemaField {
+	if !form.checkWriteRules {
+		return nil
+	}
+
+	// compare new records with an empty one because their
+	// original data is the first loaded (aka. submitted) state
+	original := models.NewRecord(form.record.Collection())
+	if !form.record.IsNew() {
+		original = form.record.OriginalCopy()
+	}
+
+	result := []*schema.SchemaField{}
+
+	for _, field := range form.record.Collection().Schema.Fields() {
+		if field.WriteRule == "" {
+			continue
+		}
+
+		if _, ok := form.submittedFields[field.Name]; !ok {
+			continue
+		}
+
+		oldValue, _ := json.Marshal(field.PrepareValue(original.Get(field.Name)))
+		newValue, _ := json.Marshal(field.PrepareValue(form.record.Get(field.Name)))
+		if string(oldValue) != string(newValue) {
+			result = append(result, field)
+		}
+	}
+
+	return result
+}
+
+// checkFieldWriteRules checks the persisted form record
+// against the write rules of the provided changed fields.
+func (form *RecordUpsert) checkFieldWriteRules(txDao *daos.Dao, fields []*schema.SchemaField) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	collection := form.record.Collection()
+
+	requestData := &models.RequestData{
+		Data:       form.data,
+		AuthRecord: form.authRecord,
+		ApiKey:     form.apiKey,
+	}
+
+	errs := validation.Errors{}
+
+	for _, field := range fields {
+		rule := field.WriteRule
+
+		ruleFunc := func(q *dbx.SelectQuery) error {
+			resolver := resolvers.NewRecordFieldResolver(txDao, collection, requestData, true)
+			expr, err := search.FilterData(rule).BuildExpr(resolver)
+			if err != nil {
+				return err
+			}
+			resolver.UpdateQuery(q)
+			q.AndWhere(expr)
+			return nil
+		}
+
+		_, err := txDao.FindRecordById(collection.Id, form.record.Id, ruleFunc)
+		if err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+
+			errs[field.Name] = validation.NewError(
+				"validation_field_write_forbidden",
+				"You are not allowed to change the field value.",
+			)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package forms
+
+import (
+	"errors"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/pocketbase/pocketbase/tools/webauthn"
+)
+
+// RecordWebauthnLogin is an auth record WebAuthn (passkey) login form.
+type RecordWebauthnLogin struct {
+	app        core.App
+	dao        *daos.Dao
+	collection *models.Collection
+	rp         *webauthn.RelyingParty
+
+	// Challenge is the challenge of the authentication options.
+	Challenge string `form:"challenge" json:"challenge"`
+
+	// Id is the base64url encoded credential id.
+	Id string `form:"id" json:"id"`
+
+	// ClientDataJSON, AuthenticatorData and Signature are the base64url
+	// encoded fields of the authenticator assertion response.
+	ClientDataJSON    string `form:"clientDataJSON" json:"clientDataJSON"`
+	AuthenticatorData string `form:"authenticatorData" json:"authenticatorData"`
+	Signature         string `form:"signature" json:"signature"`
+}
+
+// NewRecordWebauthnLogin creates a new [RecordWebauthnLogin] form
+// initialized with from the provided [core.App], [models.Collection]
+// and [webauthn.RelyingParty] instances.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewRecordWebauthnLogin(app core.App, collection *models.Collection, rp *webauthn.RelyingParty) *RecordWebauthnLogin {
+	return &RecordWebauthnLogin{
+		app:        app,
+		dao:        app.Dao(),
+		collection: collection,
+		rp:         rp,
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *RecordWebauthnLogin) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+func (form *RecordWebauthnLogin) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Challenge, validation.Required, validation.Length(1, 255)),
+		validation.Field(&form.Id, validation.Required, validation.Length(1, 2000), validation.By(checkBase64Url)),
+		validation.Field(&form.ClientDataJSON, validation.Required, validation.By(checkBase64Url)),
+		validation.Field(&form.AuthenticatorData, validation.Required, validation.By(checkBase64Url)),
+		validation.Field(&form.Signature, validation.Required, validation.By(checkBase64Url)),
+	)
+}
+
+// Submit validates and submits the form.
+// On success returns the authorized record model.
+func (form *RecordWebauthnLogin) Submit() (*models.Record, error) {
+	if err := form.Validate(); err != nil {
+		return nil, err
+	}
+
+	if !form.collection.AuthOptions().AllowWebauthnAuth {
+		return nil, errors.New("WebAuthn authentication is not allowed for the collection.")
+	}
+
+	invalidErr := errors.New("Invalid login credentials.")
+
+	// the fields are already validated
+	rawId, _ := webauthn.DecodeBase64(form.Id)
+	clientDataJSON, _ := webauthn.DecodeBase64(form.ClientDataJSON)
+	authenticatorData, _ := webauthn.DecodeBase64(form.AuthenticatorData)
+	signature, _ := webauthn.DecodeBase64(form.Signature)
+
+	// normalize the credential id encoding (eg. padding)
+	model, err := form.dao.FindWebauthnCredentialByCredentialId(form.collection.Id, webauthn.EncodeBase64(rawId))
+	if err != nil {
+		return nil, invalidErr
+	}
+
+	record, err := form.dao.FindRecordById(form.collection.Id, model.RecordId)
+	if err != nil {
+		return nil, invalidErr
+	}
+
+	publicKey, err := webauthn.DecodeBase64(model.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	credential := &webauthn.Credential{
+		PublicKey: publicKey,
+		Algorithm: model.Algorithm,
+		SignCount: uint32(model.SignCount),
+	}
+
+	signCount, err := form.rp.VerifyAssertion(credential, form.Challenge, clientDataJSON, authenticatorData, signature)
+	if err != nil {
+		return nil, invalidErr
+	}
+
+	model.SignCount = int64(signCount)
+	model.LastUsed = types.NowDateTime()
+	if err := form.dao.SaveWebauthnCredential(model); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
@@ -0,0 +1,119 @@
+package forms
+
+import (
+	"errors"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/webauthn"
+)
+
+// RecordWebauthnRegistration is an auth record WebAuthn (passkey)
+// credential registration form.
+type RecordWebauthnRegistration struct {
+	app    core.App
+	dao    *daos.Dao
+	record *models.Record
+	rp     *webauthn.RelyingParty
+
+	// Challenge is the challenge of the registration options.
+	Challenge string `form:"challenge" json:"challenge"`
+
+	// Name is an optional user friendly credential name (eg. "Laptop").
+	Name string `form:"name" json:"name"`
+
+	// ClientDataJSON and AttestationObject are the base64url encoded
+	// fields of the authenticator attestation response.
+	ClientDataJSON    string `form:"clientDataJSON" json:"clientDataJSON"`
+	AttestationObject string `form:"attestationObject" json:"attestationObject"`
+}
+
+// NewRecordWebauthnRegistration creates a new [RecordWebauthnRegistration]
+// form initialized with from the provided [core.App], [models.Record]
+// and [webauthn.RelyingParty] instances.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewRecordWebauthnRegistration(app core.App, record *models.Record, rp *webauthn.RelyingParty) *RecordWebauthnRegistration {
+	return &RecordWebauthnRegistration{
+		app:    app,
+		dao:    app.Dao(),
+		record: record,
+		rp:     rp,
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *RecordWebauthnRegistration) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+func (form *RecordWebauthnRegistration) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Challenge, validation.Required, validation.Length(1, 255)),
+		validation.Field(&form.Name, validation.Length(0, 100)),
+		validation.Field(&form.ClientDataJSON, validation.Required, validation.By(checkBase64Url)),
+		validation.Field(&form.AttestationObject, validation.Required, validation.By(checkBase64Url)),
+	)
+}
+
+// Submit validates and submits the form.
+// On success returns the new registered credential model.
+func (form *RecordWebauthnRegistration) Submit() (*models.WebauthnCredential, error) {
+	if err := form.Validate(); err != nil {
+		return nil, err
+	}
+
+	if !form.record.Collection().AuthOptions().AllowWebauthnAuth {
+		return nil, errors.New("WebAuthn authentication is not allowed for the collection.")
+	}
+
+	// the fields are already validated
+	clientDataJSON, _ := webauthn.DecodeBase64(form.ClientDataJSON)
+	attestationObject, _ := webauthn.DecodeBase64(form.AttestationObject)
+
+	credential, err := form.rp.VerifyRegistration(form.Challenge, clientDataJSON, attestationObject)
+	if err != nil {
+		return nil, err
+	}
+
+	credentialId := webauthn.EncodeBase64(credential.Id)
+
+	existing, _ := form.dao.FindWebauthnCredentialByCredentialId(form.record.Collection().Id, credentialId)
+	if existing != nil {
+		return nil, errors.New("The credential is already registered.")
+	}
+
+	model := &models.WebauthnCredential{
+		CollectionId: form.record.Collection().Id,
+		RecordId:     form.record.Id,
+		Name:         form.Name,
+		CredentialId: credentialId,
+		PublicKey:    webauthn.EncodeBase64(credential.PublicKey),
+		Algorithm:    credential.Algorithm,
+		SignCount:    int64(credential.SignCount),
+	}
+
+	if err := form.dao.SaveWebauthnCredential(model); err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// checkBase64Url checks whether the value is a valid base64url string.
+func checkBase64Url(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	if _, err := webauthn.DecodeBase64(v); err != nil {
+		return validation.NewError("validation_invalid_base64url", "Must be a valid base64url string.")
+	}
+
+	return nil
+}
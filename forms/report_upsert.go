@@ -0,0 +1,216 @@
+package forms
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/reports"
+	"github.com/pocketbase/pocketbase/tools/cron"
+	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// maxReportEmails is the max number of the report email recipients.
+const maxReportEmails = 50
+
+// ReportUpsert is a [models.Report] upsert (create/update) form.
+type ReportUpsert struct {
+	app    core.App
+	dao    *daos.Dao
+	report *models.Report
+
+	Name string `form:"name" json:"name"`
+
+	// Collection is the report collection name or id.
+	Collection string `form:"collection" json:"collection"`
+
+	Filter        string   `form:"filter" json:"filter"`
+	GroupBy       string   `form:"groupBy" json:"groupBy"`
+	Aggregate     string   `form:"aggregate" json:"aggregate"`
+	Sort          string   `form:"sort" json:"sort"`
+	Format        string   `form:"format" json:"format"`
+	Schedule      string   `form:"schedule" json:"schedule"`
+	Emails        []string `form:"emails" json:"emails"`
+	WebhookUrl    string   `form:"webhookUrl" json:"webhookUrl"`
+	WebhookSecret string   `form:"webhookSecret" json:"webhookSecret"`
+	Enabled       bool     `form:"enabled" json:"enabled"`
+}
+
+// NewReportUpsert creates a new [ReportUpsert] form with initializer
+// config created from the provided [core.App] and [models.Report] instances
+// (for create you could pass a pointer to an empty Report - `&models.Report{}`).
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewReportUpsert(app core.App, report *models.Report) *ReportUpsert {
+	form := &ReportUpsert{
+		app:    app,
+		dao:    app.Dao(),
+		report: report,
+	}
+
+	// load defaults
+	form.Name = report.Name
+	form.Collection = report.CollectionId
+	form.Filter = report.Filter
+	form.GroupBy = report.GroupBy
+	form.Aggregate = report.Aggregate
+	form.Sort = report.Sort
+	form.Format = report.Format
+	if form.Format == "" {
+		form.Format = models.ReportFormatCsv
+	}
+	form.Schedule = report.Schedule
+	form.Emails = list.ToUniqueStringSlice(report.Emails)
+	form.WebhookUrl = report.WebhookUrl
+	form.WebhookSecret = report.WebhookSecret
+	form.Enabled = report.Enabled
+
+	return form
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *ReportUpsert) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+func (form *ReportUpsert) Validate() error {
+	collection, _ := form.dao.FindCollectionByNameOrId(form.Collection)
+
+	return validation.ValidateStruct(form,
+		validation.Field(
+			&form.Name,
+			validation.Required,
+			validation.Length(1, 100),
+			validation.By(form.checkUniqueName),
+		),
+		validation.Field(
+			&form.Collection,
+			validation.Required,
+			validation.By(func(value any) error {
+				if collection == nil {
+					return validation.NewError("validation_missing_collection", "Missing or invalid collection.")
+				}
+				return nil
+			}),
+		),
+		validation.Field(
+			&form.Filter,
+			validation.By(form.checkQuery(collection, &models.Report{Filter: form.Filter})),
+		),
+		validation.Field(
+			&form.GroupBy,
+			validation.By(form.checkQuery(collection, &models.Report{GroupBy: form.GroupBy})),
+		),
+		validation.Field(
+			&form.Aggregate,
+			validation.By(form.checkQuery(collection, &models.Report{Aggregate: form.Aggregate})),
+		),
+		validation.Field(
+			&form.Sort,
+			validation.By(form.checkQuery(collection, &models.Report{
+				GroupBy:   form.GroupBy,
+				Aggregate: form.Aggregate,
+				Sort:      form.Sort,
+			})),
+		),
+		validation.Field(
+			&form.Format,
+			validation.Required,
+			validation.In(models.ReportFormatCsv, models.ReportFormatJson),
+		),
+		validation.Field(
+			&form.Schedule,
+			validation.Required,
+			validation.By(form.checkSchedule),
+		),
+		validation.Field(
+			&form.Emails,
+			validation.When(form.WebhookUrl == "", validation.Required),
+			validation.Length(0, maxReportEmails),
+			validation.Each(validation.Required, is.EmailFormat),
+		),
+		validation.Field(&form.WebhookUrl, is.URL),
+		validation.Field(&form.WebhookSecret, validation.Length(0, 255)),
+	)
+}
+
+func (form *ReportUpsert) checkUniqueName(value any) error {
+	v, _ := value.(string)
+
+	if form.dao.IsReportNameUnique(v, form.report.Id) {
+		return nil
+	}
+
+	return validation.NewError("validation_report_name_exists", "Report name already exists.")
+}
+
+// checkQuery returns a validation rule func that checks
+// whether the candidate report query could be built.
+func (form *ReportUpsert) checkQuery(collection *models.Collection, candidate *models.Report) validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(string)
+		if v == "" || collection == nil {
+			return nil // nothing to check
+		}
+
+		if err := reports.ValidateQuery(form.dao, collection, candidate); err != nil {
+			return validation.NewError("validation_invalid_report_query", err.Error())
+		}
+
+		return nil
+	}
+}
+
+func (form *ReportUpsert) checkSchedule(value any) error {
+	v, _ := value.(string)
+
+	if _, err := cron.NewSchedule(v); err != nil {
+		return validation.NewError("validation_invalid_cron_expression", "Invalid cron expression.")
+	}
+
+	return nil
+}
+
+// Submit validates the form and upserts the form report model.
+//
+// You can optionally provide a list of InterceptorFunc to further
+// modify the form behavior before persisting it.
+func (form *ReportUpsert) Submit(interceptors ...InterceptorFunc) error {
+	if err := form.Validate(); err != nil {
+		return err
+	}
+
+	collection, err := form.dao.FindCollectionByNameOrId(form.Collection)
+	if err != nil {
+		return err
+	}
+
+	emails := make(types.JsonArray, 0, len(form.Emails))
+	for _, email := range list.ToUniqueStringSlice(form.Emails) {
+		emails = append(emails, email)
+	}
+
+	form.report.Name = form.Name
+	form.report.CollectionId = collection.Id
+	form.report.Filter = form.Filter
+	form.report.GroupBy = form.GroupBy
+	form.report.Aggregate = form.Aggregate
+	form.report.Sort = form.Sort
+	form.report.Format = form.Format
+	form.report.Schedule = form.Schedule
+	form.report.Emails = emails
+	form.report.WebhookUrl = form.WebhookUrl
+	// keep the stored secret if the masked one is resubmitted
+	if form.WebhookSecret != models.ReportWebhookSecretMask {
+		form.report.WebhookSecret = form.WebhookSecret
+	}
+	form.report.Enabled = form.Enabled
+
+	return runInterceptors(func() error {
+		return form.dao.SaveReport(form.report)
+	}, interceptors...)
+}
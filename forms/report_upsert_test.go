@@ -0,0 +1,269 @@
+package forms_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestNewReportUpsert(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	report, err := app.Dao().FindReportById("rp1activedemo20")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewReportUpsert(app, report)
+
+	// test defaults
+	if form.Name != report.Name {
+		t.Errorf("Expected Name %q, got %q", report.Name, form.Name)
+	}
+	if form.Collection != report.CollectionId {
+		t.Errorf("Expected Collection %q, got %q", report.CollectionId, form.Collection)
+	}
+	if form.Filter != report.Filter {
+		t.Errorf("Expected Filter %q, got %q", report.Filter, form.Filter)
+	}
+	if form.Schedule != report.Schedule {
+		t.Errorf("Expected Schedule %q, got %q", report.Schedule, form.Schedule)
+	}
+	if len(form.Emails) != 2 || form.Emails[0] != "team@example.com" {
+		t.Errorf("Expected Emails %v, got %v", report.Emails, form.Emails)
+	}
+	if !form.Enabled {
+		t.Error("Expected Enabled to be true")
+	}
+
+	// empty report
+	emptyForm := forms.NewReportUpsert(app, &models.Report{})
+	if emptyForm.Format != models.ReportFormatCsv {
+		t.Errorf("Expected the default Format to be %q, got %q", models.ReportFormatCsv, emptyForm.Format)
+	}
+}
+
+func TestReportUpsertValidateAndSubmit(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name           string
+		id             string
+		jsonData       string
+		expectedErrors []string
+	}{
+		{
+			"create with empty data",
+			"",
+			`{}`,
+			[]string{"name", "collection", "schedule", "emails"},
+		},
+		{
+			"create with invalid data",
+			"",
+			`{
+				"name": "activeDemo2",
+				"collection": "missing",
+				"format": "xml",
+				"schedule": "* * *",
+				"emails": ["invalid"],
+				"webhookUrl": "invalid"
+			}`,
+			[]string{"name", "collection", "format", "schedule", "emails", "webhookUrl"},
+		},
+		{
+			"create with invalid query fields",
+			"",
+			`{
+				"name": "test",
+				"collection": "demo2",
+				"filter": "missing = 1",
+				"groupBy": "count(*)",
+				"aggregate": "title",
+				"sort": "missing",
+				"schedule": "@daily",
+				"webhookUrl": "https://example.com"
+			}`,
+			[]string{"filter", "groupBy", "aggregate", "sort"},
+		},
+		{
+			"create with sort of non grouped field",
+			"",
+			`{
+				"name": "test",
+				"collection": "demo2",
+				"groupBy": "active",
+				"sort": "title",
+				"schedule": "@daily",
+				"webhookUrl": "https://example.com"
+			}`,
+			[]string{"sort"},
+		},
+		{
+			"create success (records report)",
+			"",
+			`{
+				"name": "test1",
+				"collection": "demo2",
+				"filter": "active = true",
+				"sort": "-title",
+				"schedule": "0 9 * * 1-5",
+				"emails": ["a@example.com", "b@example.com", "a@example.com"]
+			}`,
+			[]string{},
+		},
+		{
+			"create success (aggregate report)",
+			"",
+			`{
+				"name": "test2",
+				"collection": "sz5l5z67tg7gku0",
+				"groupBy": "active, day(created)",
+				"aggregate": "count(*)",
+				"sort": "-count(*)",
+				"format": "json",
+				"schedule": "@weekly",
+				"webhookUrl": "https://example.com",
+				"webhookSecret": "test",
+				"enabled": true
+			}`,
+			[]string{},
+		},
+		{
+			"update with existing name",
+			"rp1activedemo20",
+			`{"name": "countsDemo2"}`,
+			[]string{"name"},
+		},
+		{
+			"update success",
+			"rp1activedemo20",
+			`{
+				"name": "activeDemo2",
+				"filter": "active = false",
+				"emails": ["team@example.com"]
+			}`,
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		report := &models.Report{}
+		if s.id != "" {
+			report, _ = app.Dao().FindReportById(s.id)
+		}
+
+		form := forms.NewReportUpsert(app, report)
+
+		// load data
+		loadErr := json.Unmarshal([]byte(s.jsonData), form)
+		if loadErr != nil {
+			t.Errorf("[%s] Failed to load form data: %v", s.name, loadErr)
+			continue
+		}
+
+		interceptorCalls := 0
+
+		err := form.Submit(func(next forms.InterceptorNextFunc) forms.InterceptorNextFunc {
+			return func() error {
+				interceptorCalls++
+				return next()
+			}
+		})
+
+		// parse errors
+		errs, ok := err.(validation.Errors)
+		if !ok && err != nil {
+			t.Errorf("[%s] Failed to parse errors %v", s.name, err)
+			continue
+		}
+
+		// check errors
+		if len(errs) > len(s.expectedErrors) {
+			t.Errorf("[%s] Expected error keys %v, got %v", s.name, s.expectedErrors, errs)
+		}
+		for _, k := range s.expectedErrors {
+			if _, ok := errs[k]; !ok {
+				t.Errorf("[%s] Missing expected error key %q in %v", s.name, k, errs)
+			}
+		}
+
+		expectInterceptorCalls := 1
+		if len(s.expectedErrors) > 0 {
+			expectInterceptorCalls = 0
+		}
+		if interceptorCalls != expectInterceptorCalls {
+			t.Errorf("[%s] Expected interceptor to be called %d, got %d", s.name, expectInterceptorCalls, interceptorCalls)
+		}
+
+		if len(s.expectedErrors) > 0 {
+			continue
+		}
+
+		// check if the model was saved
+		found, err := app.Dao().FindReportById(report.Id)
+		if err != nil {
+			t.Errorf("[%s] Expected the report to be persisted, got %v", s.name, err)
+			continue
+		}
+
+		if found.Name != form.Name ||
+			found.CollectionId != "sz5l5z67tg7gku0" ||
+			found.Filter != form.Filter ||
+			found.Format != form.Format ||
+			found.Enabled != form.Enabled {
+			t.Errorf("[%s] Unexpected report %v", s.name, found)
+		}
+
+		if len(found.Emails) != len(form.Emails) && len(found.Emails) != 2 {
+			t.Errorf("[%s] Unexpected report emails %v", s.name, found.Emails)
+		}
+	}
+}
+
+func TestReportUpsertSubmitMaskedWebhookSecret(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	report, err := app.Dao().FindReportById("rp1activedemo20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report.WebhookSecret = "test_secret"
+	if err := app.Dao().SaveReport(report); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		submitted string
+		expected  string
+	}{
+		{models.ReportWebhookSecretMask, "test_secret"},
+		{"new_secret", "new_secret"},
+		{"", ""},
+	}
+
+	for i, s := range scenarios {
+		form := forms.NewReportUpsert(app, report)
+		form.WebhookSecret = s.submitted
+
+		if err := form.Submit(); err != nil {
+			t.Fatalf("(%d) Failed to submit the form: %v", i, err)
+		}
+
+		saved, err := app.Dao().FindReportById(report.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if saved.WebhookSecret != s.expected {
+			t.Fatalf("(%d) Expected WebhookSecret %q, got %q", i, s.expected, saved.WebhookSecret)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package forms_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestNewRoleUpsert(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	role := &models.Role{Name: "editor", Description: "test"}
+
+	form := forms.NewRoleUpsert(app, role)
+
+	// test defaults
+	if form.Name != role.Name {
+		t.Errorf("Expected Name %q, got %q", role.Name, form.Name)
+	}
+	if form.Description != role.Description {
+		t.Errorf("Expected Description %q, got %q", role.Description, form.Description)
+	}
+}
+
+func TestRoleUpsertValidateAndSubmit(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	for _, name := range []string{"editor", "viewer"} {
+		if err := app.Dao().SaveRole(&models.Role{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scenarios := []struct {
+		name           string
+		roleName       string
+		jsonData       string
+		expectedErrors []string
+	}{
+		{
+			"create with empty data",
+			"",
+			`{}`,
+			[]string{"name"},
+		},
+		{
+			"create with invalid data",
+			"",
+			`{"name": "a b", "description": "` + strings.Repeat("a", 256) + `"}`,
+			[]string{"name", "description"},
+		},
+		{
+			"create with existing name",
+			"",
+			`{"name": "editor"}`,
+			[]string{"name"},
+		},
+		{
+			"create success",
+			"",
+			`{"name": "moderator", "description": "test"}`,
+			[]string{},
+		},
+		{
+			"update with existing name",
+			"editor",
+			`{"name": "viewer"}`,
+			[]string{"name"},
+		},
+		{
+			"update success",
+			"editor",
+			`{"name": "editor_new", "description": "new"}`,
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		role := &models.Role{}
+		if s.roleName != "" {
+			role, _ = app.Dao().FindRoleByName(s.roleName)
+		}
+
+		form := forms.NewRoleUpsert(app, role)
+
+		// load data
+		loadErr := json.Unmarshal([]byte(s.jsonData), form)
+		if loadErr != nil {
+			t.Errorf("[%s] Failed to load form data: %v", s.name, loadErr)
+			continue
+		}
+
+		interceptorCalls := 0
+
+		err := form.Submit(func(next forms.InterceptorNextFunc) forms.InterceptorNextFunc {
+			return func() error {
+				interceptorCalls++
+				return next()
+			}
+		})
+
+		// parse errors
+		errs, ok := err.(validation.Errors)
+		if !ok && err != nil {
+			t.Errorf("[%s] Failed to parse errors %v", s.name, err)
+			continue
+		}
+
+		// check errors
+		if len(errs) > len(s.expectedErrors) {
+			t.Errorf("[%s] Expected error keys %v, got %v", s.name, s.expectedErrors, errs)
+		}
+		for _, k := range s.expectedErrors {
+			if _, ok := errs[k]; !ok {
+				t.Errorf("[%s] Missing expected error key %q in %v", s.name, k, errs)
+			}
+		}
+
+		expectInterceptorCalls := 1
+		if len(s.expectedErrors) > 0 {
+			expectInterceptorCalls = 0
+		}
+		if interceptorCalls != expectInterceptorCalls {
+			t.Errorf("[%s] Expected interceptor to be called %d, got %d", s.name, expectInterceptorCalls, interceptorCalls)
+		}
+
+		if len(s.expectedErrors) > 0 {
+			continue
+		}
+
+		// check if the model was saved
+		found, err := app.Dao().FindRoleByName(form.Name)
+		if err != nil {
+			t.Errorf("[%s] Expected the role to be persisted, got %v", s.name, err)
+			continue
+		}
+
+		if found.Description != form.Description {
+			t.Errorf("[%s] Expected description %q, got %q", s.name, form.Description, found.Description)
+		}
+	}
+}
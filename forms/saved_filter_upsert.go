@@ -0,0 +1,153 @@
+package forms
+
+import (
+	"regexp"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/resolvers"
+	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+var savedFilterNameRegex = regexp.MustCompile(`^\w+$`)
+
+// SavedFilterUpsert is a [models.SavedFilter] upsert (create/update) form.
+type SavedFilterUpsert struct {
+	app         core.App
+	dao         *daos.Dao
+	collection  *models.Collection
+	savedFilter *models.SavedFilter
+
+	Name   string                   `form:"name" json:"name"`
+	Filter string                   `form:"filter" json:"filter"`
+	Params models.SavedFilterParams `form:"params" json:"params"`
+}
+
+// NewSavedFilterUpsert creates a new [SavedFilterUpsert] form with initializer
+// config created from the provided [core.App], [models.Collection] and
+// [models.SavedFilter] instances
+// (for create you could pass a pointer to an empty SavedFilter - `&models.SavedFilter{}`).
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewSavedFilterUpsert(app core.App, collection *models.Collection, savedFilter *models.SavedFilter) *SavedFilterUpsert {
+	form := &SavedFilterUpsert{
+		app:         app,
+		dao:         app.Dao(),
+		collection:  collection,
+		savedFilter: savedFilter,
+	}
+
+	// load defaults
+	form.Name = savedFilter.Name
+	form.Filter = savedFilter.Filter
+	form.Params = savedFilter.Params
+	if form.Params == nil {
+		form.Params = models.SavedFilterParams{}
+	}
+
+	return form
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *SavedFilterUpsert) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+func (form *SavedFilterUpsert) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(
+			&form.Name,
+			validation.Required,
+			validation.Length(1, 100),
+			validation.Match(savedFilterNameRegex),
+			validation.By(form.checkUniqueName),
+		),
+		validation.Field(
+			&form.Params,
+			validation.By(form.checkUniqueParams),
+		),
+		validation.Field(
+			&form.Filter,
+			validation.Required,
+			validation.By(form.checkFilter),
+		),
+	)
+}
+
+func (form *SavedFilterUpsert) checkUniqueName(value any) error {
+	v, _ := value.(string)
+
+	if form.dao.IsSavedFilterNameUnique(form.collection.Id, v, form.savedFilter.Id) {
+		return nil
+	}
+
+	return validation.NewError("validation_saved_filter_name_exists", "Saved filter name already exists.")
+}
+
+func (form *SavedFilterUpsert) checkUniqueParams(value any) error {
+	v, _ := value.(models.SavedFilterParams)
+
+	names := make([]string, 0, len(v))
+	for _, p := range v {
+		if list.ExistInSlice(p.Name, names) {
+			return validation.NewError("validation_duplicated_param", "The saved filter params must have unique names.")
+		}
+		names = append(names, p.Name)
+	}
+
+	return nil
+}
+
+func (form *SavedFilterUpsert) checkFilter(value any) error {
+	v, _ := value.(string)
+
+	filter := search.FilterData(v)
+
+	// dummy params values
+	params := dbx.Params{}
+	for _, p := range form.Params {
+		params[p.Name] = ""
+	}
+
+	for _, name := range filter.Placeholders() {
+		if _, ok := params[name]; !ok {
+			return validation.NewError(
+				"validation_undeclared_param",
+				"The filter placeholder {:"+name+"} doesn't have a matching param.",
+			)
+		}
+	}
+
+	r := resolvers.NewRecordFieldResolver(form.dao, form.collection, nil, true)
+
+	if _, err := filter.BuildExprWithParams(r, params); err != nil {
+		return validation.NewError("validation_invalid_filter", "Invalid filter expression.")
+	}
+
+	return nil
+}
+
+// Submit validates the form and upserts the form saved filter model.
+//
+// You can optionally provide a list of InterceptorFunc to further
+// modify the form behavior before persisting it.
+func (form *SavedFilterUpsert) Submit(interceptors ...InterceptorFunc) error {
+	if err := form.Validate(); err != nil {
+		return err
+	}
+
+	form.savedFilter.CollectionId = form.collection.Id
+	form.savedFilter.Name = form.Name
+	form.savedFilter.Filter = form.Filter
+	form.savedFilter.Params = form.Params
+
+	return runInterceptors(func() error {
+		return form.dao.SaveSavedFilter(form.savedFilter)
+	}, interceptors...)
+}
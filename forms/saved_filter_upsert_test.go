@@ -0,0 +1,191 @@
+package forms_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestNewSavedFilterUpsert(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, _ := app.Dao().FindCollectionByNameOrId("demo2")
+
+	savedFilter, err := app.Dao().FindSavedFilterById("sf1activetitle0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewSavedFilterUpsert(app, collection, savedFilter)
+
+	// test defaults
+	if form.Name != savedFilter.Name {
+		t.Errorf("Expected Name %q, got %q", savedFilter.Name, form.Name)
+	}
+	if form.Filter != savedFilter.Filter {
+		t.Errorf("Expected Filter %q, got %q", savedFilter.Filter, form.Filter)
+	}
+	if len(form.Params) != len(savedFilter.Params) {
+		t.Errorf("Expected Params %v, got %v", savedFilter.Params, form.Params)
+	}
+}
+
+func TestSavedFilterUpsertValidateAndSubmit(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, _ := app.Dao().FindCollectionByNameOrId("demo2")
+
+	scenarios := []struct {
+		name           string
+		id             string
+		jsonData       string
+		expectedErrors []string
+	}{
+		{
+			"create with empty data",
+			"",
+			`{}`,
+			[]string{"name", "filter"},
+		},
+		{
+			"create with invalid data",
+			"",
+			`{
+				"name": "a b",
+				"filter": "missing = 1",
+				"params": [{"name": "a-b", "type": "unknown"}]
+			}`,
+			[]string{"name", "filter", "params"},
+		},
+		{
+			"create with existing name",
+			"",
+			`{
+				"name": "activeByTitle",
+				"filter": "active = true"
+			}`,
+			[]string{"name"},
+		},
+		{
+			"create with duplicated params",
+			"",
+			`{
+				"name": "test",
+				"filter": "title = {:a}",
+				"params": [{"name": "a", "type": "text"}, {"name": "a", "type": "number"}]
+			}`,
+			[]string{"params"},
+		},
+		{
+			"create with undeclared placeholder",
+			"",
+			`{
+				"name": "test",
+				"filter": "title = {:a} && active = {:b}",
+				"params": [{"name": "a", "type": "text"}]
+			}`,
+			[]string{"filter"},
+		},
+		{
+			"create success",
+			"",
+			`{
+				"name": "test",
+				"filter": "title = {:a} && active = {:b} && created >= {:c}",
+				"params": [
+					{"name": "a", "type": "text"},
+					{"name": "b", "type": "bool"},
+					{"name": "c", "type": "daysAgo"}
+				]
+			}`,
+			[]string{},
+		},
+		{
+			"update with existing name",
+			"sf1activetitle0",
+			`{"name": "recent"}`,
+			[]string{"name"},
+		},
+		{
+			"update success",
+			"sf1activetitle0",
+			`{
+				"name": "activeByTitle",
+				"filter": "active = {:active}",
+				"params": [{"name": "active", "type": "bool"}]
+			}`,
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		savedFilter := &models.SavedFilter{}
+		if s.id != "" {
+			savedFilter, _ = app.Dao().FindSavedFilterById(s.id)
+		}
+
+		form := forms.NewSavedFilterUpsert(app, collection, savedFilter)
+
+		// load data
+		loadErr := json.Unmarshal([]byte(s.jsonData), form)
+		if loadErr != nil {
+			t.Errorf("[%s] Failed to load form data: %v", s.name, loadErr)
+			continue
+		}
+
+		interceptorCalls := 0
+
+		err := form.Submit(func(next forms.InterceptorNextFunc) forms.InterceptorNextFunc {
+			return func() error {
+				interceptorCalls++
+				return next()
+			}
+		})
+
+		// parse errors
+		errs, ok := err.(validation.Errors)
+		if !ok && err != nil {
+			t.Errorf("[%s] Failed to parse errors %v", s.name, err)
+			continue
+		}
+
+		// check errors
+		if len(errs) > len(s.expectedErrors) {
+			t.Errorf("[%s] Expected error keys %v, got %v", s.name, s.expectedErrors, errs)
+		}
+		for _, k := range s.expectedErrors {
+			if _, ok := errs[k]; !ok {
+				t.Errorf("[%s] Missing expected error key %q in %v", s.name, k, errs)
+			}
+		}
+
+		expectInterceptorCalls := 1
+		if len(s.expectedErrors) > 0 {
+			expectInterceptorCalls = 0
+		}
+		if interceptorCalls != expectInterceptorCalls {
+			t.Errorf("[%s] Expected interceptor to be called %d, got %d", s.name, expectInterceptorCalls, interceptorCalls)
+		}
+
+		if len(s.expectedErrors) > 0 {
+			continue
+		}
+
+		// check if the model was saved
+		found, err := app.Dao().FindSavedFilterByName(collection.Id, form.Name)
+		if err != nil {
+			t.Errorf("[%s] Expected the saved filter to be persisted, got %v", s.name, err)
+			continue
+		}
+
+		if found.CollectionId != collection.Id || found.Filter != form.Filter || len(found.Params) != len(form.Params) {
+			t.Errorf("[%s] Unexpected saved filter %v", s.name, found)
+		}
+	}
+}
@@ -46,11 +46,16 @@ func (form *SettingsUpsert) Validate() error {
 
 // Submit validates the form and upserts the loaded settings.
 //
+// The submitted `******` masked secrets (eg. from a redacted settings
+// response) are replaced with the current app settings values.
+//
 // On success the app settings will be refreshed with the form ones.
 //
 // You can optionally provide a list of InterceptorFunc to further
 // modify the form behavior before persisting it.
 func (form *SettingsUpsert) Submit(interceptors ...InterceptorFunc) error {
+	form.Settings.RestoreRedacted(form.app.Settings())
+
 	if err := form.Validate(); err != nil {
 		return err
 	}
@@ -163,3 +163,48 @@ func TestSettingsUpsertSubmitInterceptors(t *testing.T) {
 		t.Fatalf("Expected interceptor2 to be called")
 	}
 }
+
+func TestSettingsUpsertSubmitRedactedSecrets(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dsn := "https://key@sentry.example.com/1"
+	app.Settings().ErrorReporting.Dsn = dsn
+
+	webhookUrl := "https://hooks.slack.com/services/T000/B000/XXXX"
+	app.Settings().Alerts.SlackWebhookUrl = webhookUrl
+
+	tests.EnableTokenSigning(t, app)
+	signingKey := app.Settings().TokenSigning.Keys[0]
+
+	redacted, err := app.Settings().RedactClone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(redacted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms.NewSettingsUpsert(app)
+	if err := json.Unmarshal(data, form); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := form.Submit(); err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+
+	if v := app.Settings().ErrorReporting.Dsn; v != dsn {
+		t.Fatalf("Expected ErrorReporting.Dsn %q, got %q", dsn, v)
+	}
+
+	if v := app.Settings().Alerts.SlackWebhookUrl; v != webhookUrl {
+		t.Fatalf("Expected Alerts.SlackWebhookUrl %q, got %q", webhookUrl, v)
+	}
+
+	keys := app.Settings().TokenSigning.Keys
+	if len(keys) != 1 || keys[0].Id != signingKey.Id || keys[0].PrivateKey != signingKey.PrivateKey {
+		t.Fatalf("Expected the original token signing key %q to be kept, got %v", signingKey.Id, keys)
+	}
+}
@@ -0,0 +1,170 @@
+package forms
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/resolvers"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+// TestCollectionRule is a collection rule test request form.
+//
+// It evaluates a candidate rule against a single collection record
+// with simulated request data, without changing the collection.
+type TestCollectionRule struct {
+	app        core.App
+	dao        *daos.Dao
+	collection *models.Collection
+
+	// Rule is the candidate filter rule (empty string means public access).
+	Rule     string `form:"rule" json:"rule"`
+	RecordId string `form:"recordId" json:"recordId"`
+
+	// AuthCollection and AuthId specify the simulated request auth
+	// record (leave empty to simulate a guest request).
+	AuthCollection string `form:"authCollection" json:"authCollection"`
+	AuthId         string `form:"authId" json:"authId"`
+
+	// Data and Query are the simulated "@request.data.*"
+	// and "@request.query.*" values.
+	Data  map[string]any `form:"data" json:"data"`
+	Query map[string]any `form:"query" json:"query"`
+}
+
+// TestCollectionRuleResult defines the result of a submitted [TestCollectionRule] form.
+type TestCollectionRuleResult struct {
+	// Granted indicates whether the record access would be allowed.
+	Granted bool `json:"granted"`
+
+	// SQL is the generated record query with its bound params.
+	SQL    string     `json:"sql"`
+	Params dbx.Params `json:"params"`
+}
+
+// NewTestCollectionRule creates and initializes new TestCollectionRule form.
+//
+// If you want to submit the form as part of a transaction,
+// you can change the default Dao via [SetDao()].
+func NewTestCollectionRule(app core.App, collection *models.Collection) *TestCollectionRule {
+	return &TestCollectionRule{
+		app:        app,
+		dao:        app.Dao(),
+		collection: collection,
+	}
+}
+
+// SetDao replaces the default form Dao instance with the provided one.
+func (form *TestCollectionRule) SetDao(dao *daos.Dao) {
+	form.dao = dao
+}
+
+// Validate makes the form validatable by implementing [validation.Validatable] interface.
+func (form *TestCollectionRule) Validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.RecordId, validation.Required, validation.By(form.checkRecordId)),
+		validation.Field(&form.AuthId, validation.By(form.checkAuthId)),
+	)
+}
+
+func (form *TestCollectionRule) checkRecordId(value any) error {
+	v, _ := value.(string)
+
+	if _, err := form.dao.FindRecordById(form.collection.Id, v); err != nil {
+		return validation.NewError("validation_invalid_record", "Missing or invalid record.")
+	}
+
+	return nil
+}
+
+func (form *TestCollectionRule) checkAuthId(value any) error {
+	if _, err := form.findAuthRecord(); err != nil {
+		return validation.NewError("validation_invalid_auth_record", "Missing or invalid auth record.")
+	}
+
+	return nil
+}
+
+// findAuthRecord returns the simulated request auth record
+// (nil if no auth collection and id are specified).
+func (form *TestCollectionRule) findAuthRecord() (*models.Record, error) {
+	if form.AuthCollection == "" && form.AuthId == "" {
+		return nil, nil
+	}
+
+	authRecord, err := form.dao.FindRecordById(form.AuthCollection, form.AuthId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !authRecord.Collection().IsAuth() {
+		return nil, errors.New("The record is not from an auth collection.")
+	}
+
+	return authRecord, nil
+}
+
+// Submit validates the form and evaluates the candidate rule
+// against the form record and simulated request data.
+func (form *TestCollectionRule) Submit() (*TestCollectionRuleResult, error) {
+	if err := form.Validate(); err != nil {
+		return nil, err
+	}
+
+	authRecord, err := form.findAuthRecord()
+	if err != nil {
+		return nil, err
+	}
+
+	requestData := &models.RequestData{
+		Method:     http.MethodGet,
+		Query:      form.Query,
+		Data:       form.Data,
+		AuthRecord: authRecord,
+	}
+
+	query := form.dao.RecordQuery(form.collection).
+		AndWhere(dbx.HashExp{form.collection.Name + ".id": form.RecordId})
+
+	if form.Rule != "" {
+		resolver := resolvers.NewRecordFieldResolver(form.dao, form.collection, requestData, true)
+
+		expr, err := search.FilterData(form.Rule).BuildExpr(resolver)
+		if err != nil {
+			return nil, validation.Errors{"rule": validation.NewError(
+				"validation_invalid_rule",
+				"Invalid filter rule.",
+			)}
+		}
+
+		if err := resolver.UpdateQuery(query); err != nil {
+			return nil, err
+		}
+
+		query.AndWhere(expr)
+	}
+
+	built := query.Limit(1).Build()
+
+	result := &TestCollectionRuleResult{
+		SQL:    built.SQL(),
+		Params: built.Params(),
+	}
+
+	row := dbx.NullStringMap{}
+	if err := built.One(row); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+	} else {
+		result.Granted = true
+	}
+
+	return result, nil
+}
@@ -0,0 +1,149 @@
+package forms_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestTestCollectionRuleValidate(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		jsonData       string
+		expectedErrors []string
+	}{
+		{`{}`, []string{"recordId"}},
+		{`{"recordId":"missing"}`, []string{"recordId"}},
+		{`{"recordId":"achvryl401bhse3","authCollection":"users"}`, []string{"authId"}},
+		{`{"recordId":"achvryl401bhse3","authCollection":"demo2","authId":"achvryl401bhse3"}`, []string{"authId"}},
+		{`{"recordId":"achvryl401bhse3","authCollection":"users","authId":"4q1xlclmfloku33"}`, []string{}},
+		{`{"recordId":"achvryl401bhse3"}`, []string{}},
+	}
+
+	for i, s := range scenarios {
+		form := forms.NewTestCollectionRule(app, collection)
+
+		if err := json.Unmarshal([]byte(s.jsonData), form); err != nil {
+			t.Errorf("(%d) Failed to load form data: %v", i, err)
+			continue
+		}
+
+		result := form.Validate()
+
+		// parse errors
+		errs, ok := result.(validation.Errors)
+		if !ok && result != nil {
+			t.Errorf("(%d) Failed to parse errors %v", i, result)
+			continue
+		}
+
+		// check errors
+		if len(errs) > len(s.expectedErrors) {
+			t.Errorf("(%d) Expected error keys %v, got %v", i, s.expectedErrors, errs)
+		}
+		for _, k := range s.expectedErrors {
+			if _, ok := errs[k]; !ok {
+				t.Errorf("(%d) Missing expected error key %q in %v", i, k, errs)
+			}
+		}
+	}
+}
+
+func TestTestCollectionRuleSubmit(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name          string
+		jsonData      string
+		expectError   bool
+		expectGranted bool
+	}{
+		{
+			"invalid rule",
+			`{"rule":"missing = 1","recordId":"achvryl401bhse3"}`,
+			true,
+			false,
+		},
+		{
+			"empty rule",
+			`{"recordId":"llvuca81nly1qls"}`,
+			false,
+			true,
+		},
+		{
+			"rule not matching the record",
+			`{"rule":"active = true","recordId":"llvuca81nly1qls"}`,
+			false,
+			false,
+		},
+		{
+			"guest request",
+			`{"rule":"active = true && @request.auth.id != ''","recordId":"achvryl401bhse3"}`,
+			false,
+			false,
+		},
+		{
+			"auth request",
+			`{"rule":"active = true && @request.auth.id = 'oap640cot4yru2s'","recordId":"achvryl401bhse3","authCollection":"users","authId":"oap640cot4yru2s"}`,
+			false,
+			true,
+		},
+		{
+			"request data and query",
+			`{"rule":"@request.data.a = 1 && @request.query.b = 'test'","recordId":"achvryl401bhse3","data":{"a":1},"query":{"b":"test"}}`,
+			false,
+			true,
+		},
+		{
+			"request data mismatch",
+			`{"rule":"@request.data.a = 1","recordId":"achvryl401bhse3","data":{"a":2}}`,
+			false,
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		form := forms.NewTestCollectionRule(app, collection)
+
+		if err := json.Unmarshal([]byte(s.jsonData), form); err != nil {
+			t.Errorf("[%s] Failed to load form data: %v", s.name, err)
+			continue
+		}
+
+		result, err := form.Submit()
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		if result.Granted != s.expectGranted {
+			t.Errorf("[%s] Expected granted %v, got %v", s.name, s.expectGranted, result.Granted)
+		}
+
+		if result.SQL == "" {
+			t.Errorf("[%s] Expected non-empty SQL", s.name)
+		}
+	}
+}
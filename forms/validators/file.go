@@ -2,6 +2,8 @@ package validators
 
 import (
 	"fmt"
+	"mime"
+	"path/filepath"
 	"strings"
 
 	"github.com/gabriel-vasile/mimetype"
@@ -69,3 +71,111 @@ func UploadedFileMimeType(validTypes []string) validation.RuleFunc {
 		))
 	}
 }
+
+// UploadedFileDeniedMimeType checks whether the validated `rest.UploadedFile`
+// detected mimetype (or any of its parent types, eg. "application/zip" for ".docx")
+// is not within the provided denied mime types.
+//
+// Example:
+//	deniedMimeTypes := []string{"text/html","application/zip"}
+//	validation.Field(&form.File, validation.By(validators.UploadedFileDeniedMimeType(deniedMimeTypes)))
+func UploadedFileDeniedMimeType(deniedTypes []string) validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(*filesystem.File)
+		if v == nil || len(deniedTypes) == 0 {
+			return nil // nothing to validate
+		}
+
+		filetype, err := detectUploadedFileMimeType(v)
+		if err != nil {
+			return validation.NewError("validation_invalid_mime_type", "Unsupported file type.")
+		}
+
+		for m := filetype; m != nil; m = m.Parent() {
+			for _, t := range deniedTypes {
+				if m.Is(t) {
+					return validation.NewError("validation_denied_mime_type", fmt.Sprintf(
+						"The following mime types are not allowed: %s.",
+						strings.Join(deniedTypes, ","),
+					))
+				}
+			}
+		}
+
+		return nil
+	}
+}
+
+// UploadedFileMimeTypeMatch checks whether the validated `rest.UploadedFile`
+// detected mimetype matches with its client declared content type (if any)
+// and with the mime type associated with its original file extension (if known).
+//
+// Example:
+//	validation.Field(&form.File, validation.By(validators.UploadedFileMimeTypeMatch()))
+func UploadedFileMimeTypeMatch() validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(*filesystem.File)
+		if v == nil {
+			return nil // nothing to validate
+		}
+
+		filetype, err := detectUploadedFileMimeType(v)
+		if err != nil {
+			return validation.NewError("validation_invalid_mime_type", "Unsupported file type.")
+		}
+
+		// the multipart part content type
+		if mr, ok := v.Reader.(*filesystem.MultipartReader); ok && mr.Header != nil {
+			declared := mr.Header.Header.Get("Content-Type")
+			if declared != "" && !isMimeTypeMatch(filetype, declared) {
+				return validation.NewError("validation_mime_type_mismatch", fmt.Sprintf(
+					"The file content type %s doesn't match with the declared %s.",
+					filetype.String(),
+					declared,
+				))
+			}
+		}
+
+		// the file extension
+		if ext := strings.ToLower(filepath.Ext(v.OriginalName)); ext != "" {
+			extType := mime.TypeByExtension(ext)
+			if extType != "" && !isMimeTypeMatch(filetype, extType) {
+				return validation.NewError("validation_mime_type_extension_mismatch", fmt.Sprintf(
+					"The file content type %s doesn't match with the %s file extension.",
+					filetype.String(),
+					ext,
+				))
+			}
+		}
+
+		return nil
+	}
+}
+
+func detectUploadedFileMimeType(file *filesystem.File) (*mimetype.MIME, error) {
+	f, err := file.Reader.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return mimetype.DetectReader(f)
+}
+
+// isMimeTypeMatch checks whether the detected mime type (or any of its
+// parents) matches with the provided raw mime type string.
+func isMimeTypeMatch(detected *mimetype.MIME, rawType string) bool {
+	mediaType, _, err := mime.ParseMediaType(rawType)
+	if err != nil {
+		return false
+	}
+
+	for m := detected; m != nil; m = m.Parent() {
+		if m.Is(mediaType) {
+			return true
+		}
+	}
+
+	// the different text formats can't be reliably detected from the content
+	return strings.HasPrefix(mediaType, "text/") && detected.Is("text/plain")
+}
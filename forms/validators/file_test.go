@@ -1,8 +1,12 @@
 package validators_test
 
 import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/textproto"
 	"testing"
 
 	"github.com/pocketbase/pocketbase/forms/validators"
@@ -91,3 +95,95 @@ func TestUploadedFileMimeType(t *testing.T) {
 		}
 	}
 }
+
+func TestUploadedFileDeniedMimeType(t *testing.T) {
+	txtFile := mockUploadedFile(t, "test.txt", "text/plain", []byte("test"))
+	zipFile := mockUploadedFile(t, "test.docx", "", []byte("PK\x03\x04test"))
+
+	scenarios := []struct {
+		types       []string
+		file        *filesystem.File
+		expectError bool
+	}{
+		{nil, nil, false},
+		{[]string{"text/plain"}, nil, false},
+		{nil, txtFile, false},
+		{[]string{"image/jpeg"}, txtFile, false},
+		{[]string{"image/jpeg", "text/plain"}, txtFile, true},
+		{[]string{"text/html"}, zipFile, false},
+		{[]string{"application/zip"}, zipFile, true},
+	}
+
+	for i, s := range scenarios {
+		err := validators.UploadedFileDeniedMimeType(s.types)(s.file)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr to be %v, got %v (%v)", i, s.expectError, hasErr, err)
+		}
+	}
+}
+
+func TestUploadedFileMimeTypeMatch(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	html := []byte("<html><body><script>alert(1)</script></body></html>")
+
+	scenarios := []struct {
+		name        string
+		file        *filesystem.File
+		expectError bool
+	}{
+		{"nil file", nil, false},
+		{"matching declared type and extension", mockUploadedFile(t, "test.png", "image/png", png), false},
+		{"generic declared type", mockUploadedFile(t, "test.png", "application/octet-stream", png), false},
+		{"missing declared type and extension", mockUploadedFile(t, "test", "", png), false},
+		{"unknown extension", mockUploadedFile(t, "test.unknown123", "", png), false},
+		{"text format", mockUploadedFile(t, "test.css", "text/css", []byte("body { color: red }")), false},
+		{"declared type with params", mockUploadedFile(t, "test.html", "text/html; charset=utf-8", html), false},
+		{"mismatching declared type", mockUploadedFile(t, "test.png", "image/jpeg", png), true},
+		{"html disguised as png", mockUploadedFile(t, "test.png", "image/png", html), true},
+		{"mismatching extension", mockUploadedFile(t, "test.jpg", "", png), true},
+		{"invalid declared type", mockUploadedFile(t, "test.png", "invalid", png), true},
+	}
+
+	for _, s := range scenarios {
+		err := validators.UploadedFileMimeTypeMatch()(s.file)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr to be %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+		}
+	}
+}
+
+// mockUploadedFile creates a new multipart uploaded file
+// with the provided name, part content type and content.
+func mockUploadedFile(t *testing.T, filename string, contentType string, content []byte) *filesystem.File {
+	body := new(bytes.Buffer)
+	mp := multipart.NewWriter(body)
+
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, filename))
+	if contentType != "" {
+		h.Set("Content-Type", contentType)
+	}
+
+	w, err := mp.CreatePart(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	mp.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/", body)
+	req.Header.Add("Content-Type", mp.FormDataContentType())
+
+	files, err := rest.FindUploadedFiles(req, "file")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return files[0]
+}
@@ -74,6 +74,21 @@ func (validator *RecordDataValidator) Validate(data map[string]any) error {
 		// when fetching or persisting the record model
 		value := field.PrepareValue(data[key])
 
+		// the invalid geo points are normalized to nil
+		// so we need to check the raw value format
+		if field.Type == schema.FieldTypeGeoPoint && value == nil && !validation.IsEmpty(data[key]) {
+			errs[key] = validation.NewError("validation_invalid_geo_point", "Must be a valid geo point object with lon and lat")
+			continue
+		}
+
+		// the invalid documents are also normalized to nil
+		if field.Type == schema.FieldTypeDocument && value == nil && !validation.IsEmpty(data[key]) {
+			if _, err := types.ParseDocument(data[key]); err != nil {
+				errs[key] = validation.NewError("validation_invalid_document", "Must be a valid html string or document object with html key")
+				continue
+			}
+		}
+
 		// check required constraint
 		if field.Required && validation.Required.Validate(value) != nil {
 			errs[key] = requiredErr
@@ -98,6 +113,10 @@ func (validator *RecordDataValidator) Validate(data map[string]any) error {
 		}
 	}
 
+	if len(errs) == 0 {
+		validator.checkUniqueConstraints(keyedSchema, data, errs)
+	}
+
 	if len(errs) == 0 {
 		return nil
 	}
@@ -105,6 +124,36 @@ func (validator *RecordDataValidator) Validate(data map[string]any) error {
 	return errs
 }
 
+// checkUniqueConstraints checks the collection multi-column unique
+// constraints and registers the errors for each of the constrained fields.
+func (validator *RecordDataValidator) checkUniqueConstraints(
+	keyedSchema map[string]*schema.SchemaField,
+	data map[string]any,
+	errs validation.Errors,
+) {
+	collection := validator.record.Collection()
+
+	for _, constraint := range collection.UniqueConstraints() {
+		values := make(map[string]any, len(constraint.Fields))
+		for _, name := range constraint.Fields {
+			if field, ok := keyedSchema[name]; ok {
+				values[name] = field.PrepareValue(data[name])
+			}
+		}
+
+		if validator.dao.IsRecordValuesUnique(collection, values, validator.record.GetId()) {
+			continue
+		}
+
+		for name := range values {
+			errs[name] = validation.NewError(
+				"validation_not_unique_together",
+				fmt.Sprintf("The combination of %s values must be unique", strings.Join(constraint.Fields, ", ")),
+			)
+		}
+	}
+}
+
 func (validator *RecordDataValidator) checkFieldValue(field *schema.SchemaField, value any) error {
 	switch field.Type {
 	case schema.FieldTypeText:
@@ -127,6 +176,10 @@ func (validator *RecordDataValidator) checkFieldValue(field *schema.SchemaField,
 		return validator.checkFileValue(field, value)
 	case schema.FieldTypeRelation:
 		return validator.checkRelationValue(field, value)
+	case schema.FieldTypeEncrypted:
+		return validator.checkEncryptedValue(field, value)
+	case schema.FieldTypeGeoPoint:
+		return validator.checkGeoPointValue(field, value)
 	}
 
 	return nil
@@ -158,8 +211,50 @@ func (validator *RecordDataValidator) checkTextValue(field *schema.SchemaField,
 	return nil
 }
 
+func (validator *RecordDataValidator) checkEncryptedValue(field *schema.SchemaField, value any) error {
+	val, _ := value.(string)
+	if val == "" {
+		return nil // nothing to check (skip zero-defaults)
+	}
+
+	options, _ := field.Options.(*schema.EncryptedOptions)
+
+	if options.Min != nil && len(val) < *options.Min {
+		return validation.NewError("validation_min_text_constraint", fmt.Sprintf("Must be at least %d character(s)", *options.Min))
+	}
+
+	if options.Max != nil && len(val) > *options.Max {
+		return validation.NewError("validation_max_text_constraint", fmt.Sprintf("Must be less than %d character(s)", *options.Max))
+	}
+
+	return nil
+}
+
+func (validator *RecordDataValidator) checkGeoPointValue(field *schema.SchemaField, value any) error {
+	point, ok := value.(types.GeoPoint)
+	if !ok {
+		return nil // nothing to check
+	}
+
+	if point.Lon < -180 || point.Lon > 180 {
+		return validation.Errors{"lon": validation.NewError("validation_invalid_longitude", "Longitude must be between -180 and 180")}
+	}
+
+	if point.Lat < -90 || point.Lat > 90 {
+		return validation.Errors{"lat": validation.NewError("validation_invalid_latitude", "Latitude must be between -90 and 90")}
+	}
+
+	return nil
+}
+
 func (validator *RecordDataValidator) checkNumberValue(field *schema.SchemaField, value any) error {
-	val, _ := value.(float64)
+	var val float64
+	switch v := value.(type) {
+	case float64:
+		val = v
+	case types.BigInt:
+		val = float64(v)
+	}
 	if val == 0 {
 		return nil // nothing to check (skip zero-defaults)
 	}
@@ -333,6 +428,16 @@ func (validator *RecordDataValidator) checkFileValue(field *schema.SchemaField,
 				return err
 			}
 		}
+
+		if err := UploadedFileDeniedMimeType(options.DeniedMimeTypes)(file); err != nil {
+			return err
+		}
+
+		if options.StrictMimeType {
+			if err := UploadedFileMimeTypeMatch()(file); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -370,5 +475,19 @@ func (validator *RecordDataValidator) checkRelationValue(field *schema.SchemaFie
 	}
 	// ---
 
+	// prevent tree cycles
+	// ---
+	if treeField := validator.record.Collection().TreeField(); treeField != nil && treeField.Name == field.Name {
+		if validator.record.HasId() && ids[0] == validator.record.Id {
+			return validation.NewError("validation_invalid_tree_parent", "The record cannot be its own parent.")
+		}
+
+		parent, _ := validator.dao.FindRecordById(relCollection.Id, ids[0])
+		if parent != nil && validator.record.HasId() && list.ExistInSlice(validator.record.Id, parent.PathIds()) {
+			return validation.NewError("validation_invalid_tree_parent", "The record cannot be a descendant of itself.")
+		}
+	}
+	// ---
+
 	return nil
 }
@@ -1020,6 +1020,143 @@ func TestRecordDataValidatorValidateJson(t *testing.T) {
 	checkValidatorErrors(t, app.Dao(), models.NewRecord(collection), scenarios)
 }
 
+func TestRecordDataValidatorValidateGeoPoint(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	// create new test collection
+	collection := &models.Collection{}
+	collection.Name = "validate_test"
+	collection.Schema = schema.NewSchema(
+		&schema.SchemaField{
+			Name: "field1",
+			Type: schema.FieldTypeGeoPoint,
+		},
+		&schema.SchemaField{
+			Name:     "field2",
+			Required: true,
+			Type:     schema.FieldTypeGeoPoint,
+		},
+	)
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []testDataFieldScenario{
+		{
+			"(geoPoint) check required constraint - nil",
+			map[string]any{
+				"field1": nil,
+				"field2": nil,
+			},
+			nil,
+			[]string{"field2"},
+		},
+		{
+			"(geoPoint) check required constraint - zero coordinates",
+			map[string]any{
+				"field1": nil,
+				"field2": types.GeoPoint{},
+			},
+			nil,
+			[]string{},
+		},
+		{
+			"(geoPoint) check invalid format",
+			map[string]any{
+				"field1": `{"lon":1}`,
+				"field2": "invalid",
+			},
+			nil,
+			[]string{"field1", "field2"},
+		},
+		{
+			"(geoPoint) check coordinates range",
+			map[string]any{
+				"field1": map[string]any{"lon": 180.1, "lat": 0},
+				"field2": map[string]any{"lon": 0, "lat": -90.1},
+			},
+			nil,
+			[]string{"field1", "field2"},
+		},
+		{
+			"(geoPoint) valid data",
+			map[string]any{
+				"field1": `{"lon":-180,"lat":90}`,
+				"field2": map[string]any{"lon": 23.32, "lat": 42.69},
+			},
+			nil,
+			[]string{},
+		},
+	}
+
+	checkValidatorErrors(t, app.Dao(), models.NewRecord(collection), scenarios)
+}
+
+func TestRecordDataValidatorValidateDocument(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	// create new test collection
+	collection := &models.Collection{}
+	collection.Name = "validate_test"
+	collection.Schema = schema.NewSchema(
+		&schema.SchemaField{
+			Name: "field1",
+			Type: schema.FieldTypeDocument,
+		},
+		&schema.SchemaField{
+			Name:     "field2",
+			Required: true,
+			Type:     schema.FieldTypeDocument,
+		},
+	)
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []testDataFieldScenario{
+		{
+			"(document) check required constraint - nil",
+			map[string]any{
+				"field1": nil,
+				"field2": nil,
+			},
+			nil,
+			[]string{"field2"},
+		},
+		{
+			"(document) check required constraint - empty html",
+			map[string]any{
+				"field1": nil,
+				"field2": types.Document{Html: " ", Text: "test"},
+			},
+			nil,
+			[]string{"field2"},
+		},
+		{
+			"(document) check invalid format",
+			map[string]any{
+				"field1": map[string]any{"text": "test"},
+				"field2": "<p>test</p>",
+			},
+			nil,
+			[]string{"field1"},
+		},
+		{
+			"(document) valid data",
+			map[string]any{
+				"field1": `{"html":"<b>test</b>"}`,
+				"field2": map[string]any{"html": "<p>test</p>"},
+			},
+			nil,
+			[]string{},
+		},
+	}
+
+	checkValidatorErrors(t, app.Dao(), models.NewRecord(collection), scenarios)
+}
+
 func TestRecordDataValidatorValidateFile(t *testing.T) {
 	app, _ := tests.NewTestApp()
 	defer app.Cleanup()
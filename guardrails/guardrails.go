@@ -0,0 +1,127 @@
+// Package guardrails implements the app disk space and data db size
+// guardrails that notify the operators and optionally switch the
+// instance to read-only mode before the db writes start failing.
+package guardrails
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/pocketbase/pocketbase/alerts"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/alert"
+)
+
+const readOnlyCacheKey = "guardrailsReadOnly"
+
+// dataDbFiles is a list with the app data db files
+// (including the SQLite write-ahead log).
+var dataDbFiles = []string{"data.db", "data.db-wal"}
+
+// IsReadOnly reports whether the instance is currently in read-only mode
+// (aka. a guardrails threshold is exceeded and the read-only mode is enabled).
+func IsReadOnly(app core.App) bool {
+	readOnly, _ := app.Cache().Get(readOnlyCacheKey).(bool)
+
+	return readOnly
+}
+
+// DataSize returns the total size in bytes of the app data db files.
+func DataSize(app core.App) (int64, error) {
+	var total int64
+
+	for _, name := range dataDbFiles {
+		info, err := os.Stat(filepath.Join(app.DataDir(), name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return 0, err
+		}
+
+		total += info.Size()
+	}
+
+	return total, nil
+}
+
+// Check checks the free disk space and the data db size against
+// the configured thresholds and sends an alert for each exceeded one.
+//
+// If the read-only mode is enabled, the instance is switched to
+// read-only mode while any of the thresholds is exceeded and
+// back to normal mode once all thresholds are satisfied.
+func Check(app core.App) error {
+	config := app.Settings().Guardrails
+
+	exceeded := []*alert.Alert{}
+
+	if config.MinFreeDisk > 0 {
+		free, err := alert.FreeDiskSpace(app.DataDir())
+		if err != nil {
+			return err
+		}
+
+		freeMB := free / 1024 / 1024
+		if freeMB < uint64(config.MinFreeDisk) {
+			a := alert.New(
+				alerts.TypeLowDiskSpace,
+				alerts.Subject(app, "Low disk space"),
+				fmt.Sprintf("Only %d MB free disk space left for the data directory (min %d MB).", freeMB, config.MinFreeDisk),
+			)
+			a.Data["free"] = freeMB
+			a.Data["min"] = config.MinFreeDisk
+
+			exceeded = append(exceeded, a)
+		}
+	}
+
+	if config.MaxDataSize > 0 {
+		size, err := DataSize(app)
+		if err != nil {
+			return err
+		}
+
+		sizeMB := size / 1024 / 1024
+		if size >= int64(config.MaxDataSize)*1024*1024 {
+			a := alert.New(
+				alerts.TypeDataSizeLimit,
+				alerts.Subject(app, "Data size limit"),
+				fmt.Sprintf("The data db size is %d MB (max %d MB).", sizeMB, config.MaxDataSize),
+			)
+			a.Data["size"] = sizeMB
+			a.Data["max"] = config.MaxDataSize
+
+			exceeded = append(exceeded, a)
+		}
+	}
+
+	readOnly := config.ReadOnly && len(exceeded) > 0
+
+	if readOnly != IsReadOnly(app) {
+		app.Cache().Set(readOnlyCacheKey, readOnly)
+
+		if readOnly {
+			log.Println("Guardrails threshold exceeded - the instance was switched to read-only mode.")
+		} else {
+			log.Println("Guardrails thresholds satisfied - the instance was switched back to normal mode.")
+		}
+	}
+
+	var sendErr error
+
+	for _, a := range exceeded {
+		if readOnly {
+			a.Message += " The instance is in read-only mode."
+			a.Data["readOnly"] = true
+		}
+
+		if err := alerts.Send(app, a); err != nil && sendErr == nil {
+			sendErr = err
+		}
+	}
+
+	return sendErr
+}
@@ -0,0 +1,135 @@
+package guardrails_test
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/alerts"
+	"github.com/pocketbase/pocketbase/guardrails"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestDataSize(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	info, err := os.Stat(filepath.Join(app.DataDir(), "data.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	size, err := guardrails.DataSize(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if size < info.Size() {
+		t.Fatalf("Expected size to be at least %d, got %d", info.Size(), size)
+	}
+}
+
+func TestCheckWithinThresholds(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	tests.EnableAlerts(app)
+	app.Settings().Alerts.Cooldown = 0
+	sent := tests.CaptureAlerts(app)
+
+	app.Settings().Guardrails.MinFreeDisk = 1
+	app.Settings().Guardrails.MaxDataSize = math.MaxInt32
+	app.Settings().Guardrails.ReadOnly = true
+
+	if err := guardrails.Check(app); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sent) != 0 {
+		t.Fatalf("Expected no sent alerts, got %d", len(sent))
+	}
+
+	if guardrails.IsReadOnly(app) {
+		t.Fatal("Expected the instance to not be in read-only mode")
+	}
+}
+
+func TestCheckLowDiskSpace(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	tests.EnableAlerts(app)
+	app.Settings().Alerts.Cooldown = 0
+	sent := tests.CaptureAlerts(app)
+
+	app.Settings().Guardrails.MinFreeDisk = math.MaxInt32
+	app.Settings().Guardrails.MaxDataSize = 0
+	app.Settings().Guardrails.ReadOnly = false
+
+	if err := guardrails.Check(app); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sent) != 1 {
+		t.Fatalf("Expected 1 sent alert, got %d", len(sent))
+	}
+
+	if a := <-sent; a.Type != alerts.TypeLowDiskSpace {
+		t.Fatalf("Expected %q alert, got %q", alerts.TypeLowDiskSpace, a.Type)
+	}
+
+	if guardrails.IsReadOnly(app) {
+		t.Fatal("Expected the instance to not be in read-only mode (the mode is disabled)")
+	}
+}
+
+func TestCheckDataSizeLimitAndReadOnly(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	tests.EnableAlerts(app)
+	app.Settings().Alerts.Cooldown = 0
+	sent := tests.CaptureAlerts(app)
+
+	// ensure that the data db is larger than 1MB
+	wal := filepath.Join(app.DataDir(), "data.db-wal")
+	if err := os.WriteFile(wal, make([]byte, 1024*1024), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	app.Settings().Guardrails.MinFreeDisk = 0
+	app.Settings().Guardrails.MaxDataSize = 1
+	app.Settings().Guardrails.ReadOnly = true
+
+	if err := guardrails.Check(app); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sent) != 1 {
+		t.Fatalf("Expected 1 sent alert, got %d", len(sent))
+	}
+
+	a := <-sent
+	if a.Type != alerts.TypeDataSizeLimit {
+		t.Fatalf("Expected %q alert, got %q", alerts.TypeDataSizeLimit, a.Type)
+	}
+	if a.Data["readOnly"] != true {
+		t.Fatalf("Expected the alert to contain the read-only flag, got %v", a.Data)
+	}
+
+	if !guardrails.IsReadOnly(app) {
+		t.Fatal("Expected the instance to be in read-only mode")
+	}
+
+	// raise the threshold
+	app.Settings().Guardrails.MaxDataSize = math.MaxInt32
+
+	if err := guardrails.Check(app); err != nil {
+		t.Fatal(err)
+	}
+
+	if guardrails.IsReadOnly(app) {
+		t.Fatal("Expected the instance to be switched back to normal mode")
+	}
+}
@@ -1,5 +1,5 @@
 // Package mails implements various helper methods for sending user and admin
-// emails like forgotten password, verification, etc.
+// emails like forgotten password, verification, etc. (and the auth SMS messages).
 package mails
 
 import (
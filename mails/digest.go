@@ -0,0 +1,189 @@
+package mails
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"html/template"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/mails/templates"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/settings"
+	"github.com/pocketbase/pocketbase/tools/mailer"
+)
+
+// QueueRecordDigestItem queues a new digest item for the specified auth
+// record that will be sent together with the other queued items of the
+// same record as a single digest email (see [SendRecordDigests]).
+//
+// The item is silently skipped if the auth record
+// digest frequency preference is set to "never".
+func QueueRecordDigestItem(
+	app core.App,
+	authRecord *models.Record,
+	subject string,
+	message string,
+	data map[string]any,
+) error {
+	config := app.Settings().Digest
+	if !config.Enabled {
+		return errors.New("The email digests are not enabled.")
+	}
+
+	if !authRecord.Collection().IsAuth() {
+		return errors.New("The digest recipient must be an auth record.")
+	}
+
+	if _, ok := config.FrequencyWindow(recordDigestFrequency(config, authRecord)); !ok {
+		return nil // opted out
+	}
+
+	item := &models.DigestItem{
+		CollectionId: authRecord.Collection().Id,
+		RecordId:     authRecord.Id,
+		Subject:      subject,
+		Message:      message,
+		Data:         data,
+	}
+
+	return app.Dao().SaveDigestItem(item)
+}
+
+// SendRecordDigests sends a single digest email to each auth record
+// recipient whose oldest queued item is older than the recipient
+// digest frequency window and removes the sent items from the queue.
+//
+// The queued items of deleted (or opted out) recipients are discarded.
+func SendRecordDigests(app core.App) error {
+	config := app.Settings().Digest
+	if !config.Enabled {
+		return nil
+	}
+
+	items, err := app.Dao().FindAllDigestItems()
+	if err != nil {
+		return err
+	}
+
+	// group the items by their recipient (preserving the creation order)
+	keys := []string{}
+	groups := map[string][]*models.DigestItem{}
+	for _, item := range items {
+		key := item.CollectionId + "/" + item.RecordId
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], item)
+	}
+
+	var errs []string
+
+	for _, key := range keys {
+		group := groups[key]
+
+		ids := make([]string, len(group))
+		for i, item := range group {
+			ids[i] = item.Id
+		}
+
+		record, err := app.Dao().FindRecordById(group[0].CollectionId, group[0].RecordId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				err = app.Dao().DeleteDigestItems(ids)
+			}
+			if err != nil {
+				errs = append(errs, err.Error())
+			}
+			continue
+		}
+
+		window, ok := config.FrequencyWindow(recordDigestFrequency(config, record))
+		if !ok {
+			if err := app.Dao().DeleteDigestItems(ids); err != nil {
+				errs = append(errs, err.Error())
+			}
+			continue
+		}
+
+		if time.Since(group[0].Created.Time()) < window {
+			continue // not ready yet
+		}
+
+		if err := sendRecordDigest(app, record, group); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+
+		if err := app.Dao().DeleteDigestItems(ids); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+func sendRecordDigest(app core.App, authRecord *models.Record, items []*models.DigestItem) error {
+	if authRecord.Email() == "" {
+		return nil // nowhere to send
+	}
+
+	config := app.Settings().Digest
+
+	placeholders := strings.NewReplacer(
+		settings.EmailPlaceholderAppName, app.Settings().Meta.AppName,
+		settings.EmailPlaceholderAppUrl, app.Settings().Meta.AppUrl,
+	)
+
+	bodyTemplate, err := template.New("digest").Parse(placeholders.Replace(config.Body))
+	if err != nil {
+		return err
+	}
+
+	var rawBody bytes.Buffer
+
+	bodyErr := bodyTemplate.Execute(&rawBody, map[string]any{
+		"Record": authRecord,
+		"Items":  items,
+	})
+	if bodyErr != nil {
+		return bodyErr
+	}
+
+	body, err := resolveTemplateContent(
+		struct{ HtmlContent template.HTML }{template.HTML(rawBody.String())},
+		templates.Layout,
+		templates.HtmlBody,
+	)
+	if err != nil {
+		return err
+	}
+
+	message := &mailer.Message{
+		From: mail.Address{
+			Name:    app.Settings().Meta.SenderName,
+			Address: app.Settings().Meta.SenderAddress,
+		},
+		To:      mail.Address{Address: authRecord.Email()},
+		Subject: placeholders.Replace(config.Subject),
+		HTML:    body,
+	}
+
+	return app.NewMailClient().Send(message)
+}
+
+// recordDigestFrequency returns the auth record digest frequency preference.
+func recordDigestFrequency(config settings.DigestConfig, authRecord *models.Record) string {
+	if config.FrequencyField == "" {
+		return ""
+	}
+
+	return authRecord.GetString(config.FrequencyField)
+}
@@ -0,0 +1,118 @@
+package mails_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/mails"
+	"github.com/pocketbase/pocketbase/models/settings"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestQueueRecordDigestItem(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	user, err := testApp.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// disabled digests
+	if err := mails.QueueRecordDigestItem(testApp, user, "test", "message", nil); err == nil {
+		t.Fatal("Expected error for disabled digests")
+	}
+
+	testApp.Settings().Digest.Enabled = true
+	testApp.Settings().Digest.FrequencyField = "name"
+
+	// non-auth record
+	demo, err := testApp.Dao().FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mails.QueueRecordDigestItem(testApp, demo, "test", "message", nil); err == nil {
+		t.Fatal("Expected error for non-auth record")
+	}
+
+	// opted out
+	user.Set("name", settings.DigestFrequencyNever)
+	if err := mails.QueueRecordDigestItem(testApp, user, "test", "message", nil); err != nil {
+		t.Fatal(err)
+	}
+	if items, _ := testApp.Dao().FindAllDigestItems(); len(items) != 0 {
+		t.Fatalf("Expected no queued items, got %d", len(items))
+	}
+
+	// queued
+	user.Set("name", settings.DigestFrequencyDaily)
+	if err := mails.QueueRecordDigestItem(testApp, user, "test", "message", map[string]any{"a": 1}); err != nil {
+		t.Fatal(err)
+	}
+	items, _ := testApp.Dao().FindDigestItems(user.Collection().Id, user.Id)
+	if len(items) != 1 || items[0].Subject != "test" || items[0].Message != "message" {
+		t.Fatalf("Expected one queued item, got %v", items)
+	}
+}
+
+func TestSendRecordDigests(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	testApp.Settings().Digest.Enabled = true
+	testApp.Settings().Digest.FrequencyField = "name"
+	testApp.Settings().Digest.Window = 0
+
+	user1, _ := testApp.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	user2, _ := testApp.Dao().FindRecordById("users", "oap640cot4yru2s")
+
+	// user1 has a non frequency "name" value and fallbacks to the default window
+	for _, subject := range []string{"<b>first</b>", "second"} {
+		if err := mails.QueueRecordDigestItem(testApp, user1, subject, "message "+subject, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// user2 is with daily frequency and the item is not ready yet
+	user2.Set("name", settings.DigestFrequencyDaily)
+	if err := testApp.Dao().SaveRecord(user2); err != nil {
+		t.Fatal(err)
+	}
+	if err := mails.QueueRecordDigestItem(testApp, user2, "third", "message third", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mails.SendRecordDigests(testApp); err != nil {
+		t.Fatal(err)
+	}
+
+	if testApp.TestMailer.TotalSend != 1 {
+		t.Fatalf("Expected one digest email to be sent, got %d", testApp.TestMailer.TotalSend)
+	}
+
+	message := testApp.TestMailer.LastMessage
+
+	if message.To.Address != user1.Email() {
+		t.Fatalf("Expected the digest to be sent to %q, got %q", user1.Email(), message.To.Address)
+	}
+
+	expectedSubject := "Your " + testApp.Settings().Meta.AppName + " updates"
+	if message.Subject != expectedSubject {
+		t.Fatalf("Expected subject %q, got %q", expectedSubject, message.Subject)
+	}
+
+	expectedParts := []string{"&lt;b&gt;first&lt;/b&gt;", "message second", testApp.Settings().Meta.AppName + " team"}
+	for _, part := range expectedParts {
+		if !strings.Contains(message.HTML, part) {
+			t.Fatalf("Couldn't find %s \nin\n %s", part, message.HTML)
+		}
+	}
+
+	items, err := testApp.Dao().FindAllDigestItems()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].RecordId != user2.Id {
+		t.Fatalf("Expected only the not ready user2 item to remain, got %v", items)
+	}
+}
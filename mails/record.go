@@ -148,6 +148,54 @@ func SendRecordChangeEmail(app core.App, record *models.Record, newEmail string)
 	return sendErr
 }
 
+// SendRecordLoginLink sends a one-time passwordless login link email
+// associated with the provided login link to the specified auth record.
+func SendRecordLoginLink(app core.App, authRecord *models.Record, link *models.LoginLink) error {
+	token, tokenErr := tokens.NewRecordLoginLinkToken(app, authRecord, link)
+	if tokenErr != nil {
+		return tokenErr
+	}
+
+	mailClient := app.NewMailClient()
+
+	subject, body, err := resolveEmailTemplate(app, token, app.Settings().Meta.LoginLinkTemplate)
+	if err != nil {
+		return err
+	}
+
+	message := &mailer.Message{
+		From: mail.Address{
+			Name:    app.Settings().Meta.SenderName,
+			Address: app.Settings().Meta.SenderAddress,
+		},
+		To:      mail.Address{Address: authRecord.Email()},
+		Subject: subject,
+		HTML:    body,
+	}
+
+	event := &core.MailerRecordEvent{
+		MailClient: mailClient,
+		Message:    message,
+		Record:     authRecord,
+		Meta: map[string]any{
+			"token":  token,
+			"linkId": link.Id,
+		},
+	}
+
+	sendErr := app.OnMailerBeforeRecordLoginLinkSend().Trigger(event, func(e *core.MailerRecordEvent) error {
+		return e.MailClient.Send(e.Message)
+	})
+
+	if sendErr == nil {
+		if err := app.OnMailerAfterRecordLoginLinkSend().Trigger(event); err != nil && app.IsDebug() {
+			log.Println(err)
+		}
+	}
+
+	return sendErr
+}
+
 func resolveEmailTemplate(
 	app core.App,
 	token string,
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/pocketbase/pocketbase/mails"
+	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/tests"
 )
 
@@ -85,3 +86,32 @@ func TestSendRecordChangeEmail(t *testing.T) {
 		}
 	}
 }
+
+func TestSendRecordLoginLink(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	user, _ := testApp.Dao().FindFirstRecordByData("users", "email", "test@example.com")
+
+	link := &models.LoginLink{CollectionId: user.Collection().Id, RecordId: user.Id}
+	link.RefreshId()
+
+	err := mails.SendRecordLoginLink(testApp, user, link)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if testApp.TestMailer.TotalSend != 1 {
+		t.Fatalf("Expected one email to be sent, got %d", testApp.TestMailer.TotalSend)
+	}
+
+	expectedParts := []string{
+		"http://localhost:8090/_/#/auth/confirm-login-link/eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.",
+		"<code>eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.",
+	}
+	for _, part := range expectedParts {
+		if !strings.Contains(testApp.TestMailer.LastMessage.HTML, part) {
+			t.Fatalf("Couldn't find %s \nin\n %s", part, testApp.TestMailer.LastMessage.HTML)
+		}
+	}
+}
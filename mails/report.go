@@ -0,0 +1,75 @@
+package mails
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/mails/templates"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/mailer"
+	"github.com/spf13/cast"
+)
+
+// SendReport sends the rendered report content as email attachment
+// to each of the report email recipients.
+//
+// rows is the number of the rendered report rows (used only in the email body).
+func SendReport(app core.App, report *models.Report, filename string, content []byte, rows int) error {
+	params := struct {
+		AppName  string
+		AppUrl   string
+		Report   *models.Report
+		Filename string
+		Rows     int
+	}{
+		AppName:  app.Settings().Meta.AppName,
+		AppUrl:   app.Settings().Meta.AppUrl,
+		Report:   report,
+		Filename: filename,
+		Rows:     rows,
+	}
+
+	body, err := resolveTemplateContent(params, templates.Layout, templates.ReportBody)
+	if err != nil {
+		return err
+	}
+
+	mailClient := app.NewMailClient()
+
+	var errs []string
+
+	for _, raw := range report.Emails {
+		email := cast.ToString(raw)
+		if email == "" {
+			continue
+		}
+
+		message := &mailer.Message{
+			From: mail.Address{
+				Name:    app.Settings().Meta.SenderName,
+				Address: app.Settings().Meta.SenderAddress,
+			},
+			To:      mail.Address{Address: email},
+			Subject: fmt.Sprintf("[%s] %s report", app.Settings().Meta.AppName, report.Name),
+			HTML:    body,
+			Attachments: map[string]io.Reader{
+				filename: bytes.NewReader(content),
+			},
+		}
+
+		if err := mailClient.Send(message); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", email, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New("Failed to send the report email to " + strings.Join(errs, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package mails_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/mails"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestSendReport(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	report := &models.Report{
+		Name:   "weekly",
+		Emails: types.JsonArray{"a@example.com", "", "b@example.com"},
+	}
+
+	err := mails.SendReport(testApp, report, "weekly.csv", []byte("a,b\n1,2\n"), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if testApp.TestMailer.TotalSend != 2 {
+		t.Fatalf("Expected 2 sent emails, got %d", testApp.TestMailer.TotalSend)
+	}
+
+	message := testApp.TestMailer.LastMessage
+
+	if message.To.Address != "b@example.com" {
+		t.Fatalf("Expected the last email to be sent to b@example.com, got %q", message.To.Address)
+	}
+
+	if !strings.Contains(message.Subject, "weekly report") {
+		t.Fatalf("Unexpected email subject %q", message.Subject)
+	}
+
+	expectedParts := []string{"<strong>weekly</strong>", "(1 rows)", "weekly.csv"}
+	for _, part := range expectedParts {
+		if !strings.Contains(message.HTML, part) {
+			t.Fatalf("Missing %q in\n%v", part, message.HTML)
+		}
+	}
+
+	attachment, ok := message.Attachments["weekly.csv"]
+	if !ok {
+		t.Fatalf("Missing weekly.csv attachment in %v", message.Attachments)
+	}
+
+	content, _ := io.ReadAll(attachment)
+	if string(content) != "a,b\n1,2\n" {
+		t.Fatalf("Unexpected attachment content %q", content)
+	}
+}
@@ -0,0 +1,55 @@
+package mails
+
+import (
+	"errors"
+	"log"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/sms"
+)
+
+// SendRecordPhoneOtp sends the plain one-time password code of the
+// provided phone otp as SMS message to the otp phone number.
+//
+// The message is sent through the app settings SMS gateway or through
+// the custom one assigned in the [core.App.OnSmsBeforeRecordOtpSend] hook.
+func SendRecordPhoneOtp(app core.App, authRecord *models.Record, otp *models.PhoneOtp, code string) error {
+	if otp.Phone == "" {
+		return errors.New("Missing phone otp phone number.")
+	}
+
+	settings := app.Settings()
+
+	message := &sms.Message{
+		From: settings.Sms.From,
+		To:   otp.Phone,
+		Body: settings.Sms.ResolveOtpTemplate(settings.Meta.AppName, code),
+	}
+
+	event := &core.SmsRecordEvent{
+		SmsClient: app.NewSmsClient(),
+		Message:   message,
+		Record:    authRecord,
+		Meta: map[string]any{
+			"code":  code,
+			"otpId": otp.Id,
+		},
+	}
+
+	sendErr := app.OnSmsBeforeRecordOtpSend().Trigger(event, func(e *core.SmsRecordEvent) error {
+		if e.SmsClient == nil {
+			return errors.New("There is no configured SMS gateway.")
+		}
+
+		return e.SmsClient.Send(e.Message)
+	})
+
+	if sendErr == nil {
+		if err := app.OnSmsAfterRecordOtpSend().Trigger(event); err != nil && app.IsDebug() {
+			log.Println(err)
+		}
+	}
+
+	return sendErr
+}
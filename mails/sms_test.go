@@ -0,0 +1,93 @@
+package mails_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/mails"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/sms"
+)
+
+type customSmsGateway struct {
+	messages []sms.Message
+}
+
+func (g *customSmsGateway) Send(m *sms.Message) error {
+	g.messages = append(g.messages, *m)
+	return nil
+}
+
+func TestSendRecordPhoneOtp(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	testApp.Settings().Sms.From = "+15005550006"
+
+	user, _ := testApp.Dao().FindFirstRecordByData("users", "email", "test@example.com")
+
+	// missing phone
+	if err := mails.SendRecordPhoneOtp(testApp, user, &models.PhoneOtp{}, "123456"); err == nil {
+		t.Fatal("Expected error for otp without phone")
+	}
+
+	otp := &models.PhoneOtp{Phone: "+359888123456"}
+
+	if err := mails.SendRecordPhoneOtp(testApp, user, otp, "123456"); err != nil {
+		t.Fatal(err)
+	}
+
+	if testApp.TestSmsSender.TotalSend != 1 {
+		t.Fatalf("Expected one SMS to be sent, got %d", testApp.TestSmsSender.TotalSend)
+	}
+
+	message := testApp.TestSmsSender.LastMessage
+	if message.From != "+15005550006" || message.To != "+359888123456" {
+		t.Fatalf("Unexpected message sender or recipient %v", message)
+	}
+	if !strings.Contains(message.Body, "acme_test verification code is 123456") {
+		t.Fatalf("Unexpected message body %q", message.Body)
+	}
+
+	// custom gateway
+	gateway := &customSmsGateway{}
+	testApp.OnSmsBeforeRecordOtpSend().Add(func(e *core.SmsRecordEvent) error {
+		e.SmsClient = gateway
+		return nil
+	})
+
+	if err := mails.SendRecordPhoneOtp(testApp, user, otp, "654321"); err != nil {
+		t.Fatal(err)
+	}
+
+	if testApp.TestSmsSender.TotalSend != 0 {
+		t.Fatalf("Expected the default sender to be replaced, got %d sent messages", testApp.TestSmsSender.TotalSend)
+	}
+
+	if len(gateway.messages) != 1 || !strings.Contains(gateway.messages[0].Body, "654321") {
+		t.Fatalf("Expected the message to be sent through the custom gateway, got %v", gateway.messages)
+	}
+}
+
+func TestSendRecordPhoneOtpWithoutGateway(t *testing.T) {
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	user, _ := testApp.Dao().FindFirstRecordByData("users", "email", "test@example.com")
+
+	testApp.OnSmsBeforeRecordOtpSend().Add(func(e *core.SmsRecordEvent) error {
+		e.SmsClient = nil // eg. disabled sms settings
+		return nil
+	})
+
+	err := mails.SendRecordPhoneOtp(testApp, user, &models.PhoneOtp{Phone: "+359888123456"}, "123456")
+	if err == nil {
+		t.Fatal("Expected error for missing SMS gateway")
+	}
+
+	if testApp.EventCalls["OnSmsAfterRecordOtpSend"] != 0 {
+		t.Fatal("Didn't expect OnSmsAfterRecordOtpSend to be triggered")
+	}
+}
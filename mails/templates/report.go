@@ -0,0 +1,18 @@
+package templates
+
+// Available variables:
+//
+// ```
+// AppName  string
+// AppUrl   string
+// Report   *models.Report
+// Filename string
+// Rows     int
+// ```
+const ReportBody = `
+{{define "content"}}
+	<p>Hello,</p>
+	<p>The <strong>{{.Report.Name}}</strong> report of {{.AppName}} is ready ({{.Rows}} rows).</p>
+	<p>You can find it attached as <i>{{.Filename}}</i>.</p>
+{{end}}
+`
@@ -47,19 +47,22 @@ func init() {
 			);
 
 			CREATE TABLE {{_collections}} (
-				[[id]]         TEXT PRIMARY KEY NOT NULL,
-				[[system]]     BOOLEAN DEFAULT FALSE NOT NULL,
-				[[type]]       TEXT DEFAULT "base" NOT NULL,
-				[[name]]       TEXT UNIQUE NOT NULL,
-				[[schema]]     JSON DEFAULT "[]" NOT NULL,
-				[[listRule]]   TEXT DEFAULT NULL,
-				[[viewRule]]   TEXT DEFAULT NULL,
-				[[createRule]] TEXT DEFAULT NULL,
-				[[updateRule]] TEXT DEFAULT NULL,
-				[[deleteRule]] TEXT DEFAULT NULL,
-				[[options]]    JSON DEFAULT "{}" NOT NULL,
-				[[created]]    TEXT DEFAULT "" NOT NULL,
-				[[updated]]    TEXT DEFAULT "" NOT NULL
+				[[id]]            TEXT PRIMARY KEY NOT NULL,
+				[[system]]        BOOLEAN DEFAULT FALSE NOT NULL,
+				[[type]]          TEXT DEFAULT "base" NOT NULL,
+				[[name]]          TEXT UNIQUE NOT NULL,
+				[[schema]]        JSON DEFAULT "[]" NOT NULL,
+				[[listRule]]      TEXT DEFAULT NULL,
+				[[viewRule]]      TEXT DEFAULT NULL,
+				[[createRule]]    TEXT DEFAULT NULL,
+				[[updateRule]]    TEXT DEFAULT NULL,
+				[[deleteRule]]    TEXT DEFAULT NULL,
+				[[subscribeRule]] TEXT DEFAULT NULL,
+				[[ruleMacros]]    JSON DEFAULT "{}" NOT NULL,
+				[[options]]       JSON DEFAULT "{}" NOT NULL,
+				[[version]]       INTEGER DEFAULT 0 NOT NULL,
+				[[created]]       TEXT DEFAULT "" NOT NULL,
+				[[updated]]       TEXT DEFAULT "" NOT NULL
 			);
 
 			CREATE TABLE {{_params}} (
@@ -101,6 +104,7 @@ func init() {
 		usersCollection.CreateRule = types.Pointer("")
 		usersCollection.UpdateRule = types.Pointer("id = @request.auth.id")
 		usersCollection.DeleteRule = types.Pointer("id = @request.auth.id")
+		usersCollection.SubscribeRule = types.Pointer("")
 
 		// set auth options
 		usersCollection.SetOptions(models.CollectionAuthOptions{
@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE {{_savedFilters}} (
+				[[id]]           TEXT PRIMARY KEY NOT NULL,
+				[[collectionId]] TEXT NOT NULL,
+				[[name]]         TEXT NOT NULL,
+				[[filter]]       TEXT DEFAULT "" NOT NULL,
+				[[params]]       JSON DEFAULT "[]" NOT NULL,
+				[[created]]      TEXT DEFAULT "" NOT NULL,
+				[[updated]]      TEXT DEFAULT "" NOT NULL,
+				---
+				FOREIGN KEY ([[collectionId]]) REFERENCES {{_collections}} ([[id]]) ON UPDATE CASCADE ON DELETE CASCADE
+			);
+
+			CREATE UNIQUE INDEX _savedFilters_collection_name_idx on {{_savedFilters}} ([[collectionId]], [[name]]);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_savedFilters").Execute()
+
+		return err
+	})
+}
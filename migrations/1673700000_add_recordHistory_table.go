@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE {{_recordHistory}} (
+				[[id]]           TEXT PRIMARY KEY NOT NULL,
+				[[collectionId]] TEXT NOT NULL,
+				[[recordId]]     TEXT NOT NULL,
+				[[version]]      INTEGER DEFAULT 0 NOT NULL,
+				[[action]]       TEXT NOT NULL,
+				[[authType]]     TEXT DEFAULT "" NOT NULL,
+				[[authId]]       TEXT DEFAULT "" NOT NULL,
+				[[changes]]      JSON DEFAULT "{}" NOT NULL,
+				[[data]]         JSON DEFAULT "{}" NOT NULL,
+				[[created]]      TEXT DEFAULT "" NOT NULL,
+				[[updated]]      TEXT DEFAULT "" NOT NULL,
+				---
+				FOREIGN KEY ([[collectionId]]) REFERENCES {{_collections}} ([[id]]) ON UPDATE CASCADE ON DELETE CASCADE
+			);
+
+			CREATE UNIQUE INDEX _recordHistory_record_version_idx on {{_recordHistory}} ([[collectionId]], [[recordId]], [[version]]);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_recordHistory").Execute()
+
+		return err
+	})
+}
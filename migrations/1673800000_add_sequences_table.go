@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE {{_sequences}} (
+				[[collectionId]] TEXT NOT NULL,
+				[[fieldId]]      TEXT NOT NULL,
+				[[value]]        INTEGER DEFAULT 0 NOT NULL,
+				---
+				PRIMARY KEY ([[collectionId]], [[fieldId]]),
+				FOREIGN KEY ([[collectionId]]) REFERENCES {{_collections}} ([[id]]) ON UPDATE CASCADE ON DELETE CASCADE
+			);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_sequences").Execute()
+
+		return err
+	})
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+// Adds the collections version column used for detecting
+// concurrent collection modifications.
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		columns, err := daos.New(db).GetTableColumns("_collections")
+		if err != nil {
+			return err
+		}
+
+		// the new installations already have the column
+		if list.ExistInSlice("version", columns) {
+			return nil
+		}
+
+		_, err = db.AddColumn("_collections", "version", "INTEGER DEFAULT 0 NOT NULL").Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropColumn("_collections", "version").Execute()
+
+		return err
+	})
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE {{_digestItems}} (
+				[[id]]           TEXT PRIMARY KEY NOT NULL,
+				[[collectionId]] TEXT NOT NULL,
+				[[recordId]]     TEXT NOT NULL,
+				[[subject]]      TEXT DEFAULT "" NOT NULL,
+				[[message]]      TEXT DEFAULT "" NOT NULL,
+				[[data]]         JSON DEFAULT "{}" NOT NULL,
+				[[created]]      TEXT DEFAULT "" NOT NULL,
+				[[updated]]      TEXT DEFAULT "" NOT NULL,
+				---
+				FOREIGN KEY ([[collectionId]]) REFERENCES {{_collections}} ([[id]]) ON UPDATE CASCADE ON DELETE CASCADE
+			);
+
+			CREATE INDEX _digestItems_recipient_idx on {{_digestItems}} ([[collectionId]], [[recordId]]);
+			CREATE INDEX _digestItems_created_idx on {{_digestItems}} ([[created]]);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_digestItems").Execute()
+
+		return err
+	})
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE {{_recordPreferences}} (
+				[[id]]           TEXT PRIMARY KEY NOT NULL,
+				[[collectionId]] TEXT NOT NULL,
+				[[recordId]]     TEXT NOT NULL,
+				[[data]]         JSON DEFAULT "{}" NOT NULL,
+				[[created]]      TEXT DEFAULT "" NOT NULL,
+				[[updated]]      TEXT DEFAULT "" NOT NULL,
+				---
+				FOREIGN KEY ([[collectionId]]) REFERENCES {{_collections}} ([[id]]) ON UPDATE CASCADE ON DELETE CASCADE
+			);
+
+			CREATE UNIQUE INDEX _recordPreferences_record_idx on {{_recordPreferences}} ([[collectionId]], [[recordId]]);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_recordPreferences").Execute()
+
+		return err
+	})
+}
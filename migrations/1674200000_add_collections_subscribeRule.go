@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+// Adds the collections realtime subscribe rule column.
+//
+// The rule of the existing collections is set to an empty string
+// to preserve their current realtime behavior.
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		columns, err := daos.New(db).GetTableColumns("_collections")
+		if err != nil {
+			return err
+		}
+
+		// the new installations already have the column
+		if list.ExistInSlice("subscribeRule", columns) {
+			return nil
+		}
+
+		if _, err := db.AddColumn("_collections", "subscribeRule", "TEXT DEFAULT NULL").Execute(); err != nil {
+			return err
+		}
+
+		_, err = db.Update("_collections", dbx.Params{"subscribeRule": ""}, nil).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropColumn("_collections", "subscribeRule").Execute()
+
+		return err
+	})
+}
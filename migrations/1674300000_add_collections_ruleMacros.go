@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+// Adds the collections rule macros column.
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		columns, err := daos.New(db).GetTableColumns("_collections")
+		if err != nil {
+			return err
+		}
+
+		// the new installations already have the column
+		if list.ExistInSlice("ruleMacros", columns) {
+			return nil
+		}
+
+		_, err = db.AddColumn("_collections", "ruleMacros", `JSON DEFAULT "{}" NOT NULL`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropColumn("_collections", "ruleMacros").Execute()
+
+		return err
+	})
+}
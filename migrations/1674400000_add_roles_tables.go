@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE {{_roles}} (
+				[[id]]          TEXT PRIMARY KEY NOT NULL,
+				[[name]]        TEXT NOT NULL,
+				[[description]] TEXT DEFAULT "" NOT NULL,
+				[[created]]     TEXT DEFAULT "" NOT NULL,
+				[[updated]]     TEXT DEFAULT "" NOT NULL
+			);
+
+			CREATE UNIQUE INDEX _roles_name_idx on {{_roles}} ([[name]]);
+
+			CREATE TABLE {{_authRoles}} (
+				[[id]]           TEXT PRIMARY KEY NOT NULL,
+				[[collectionId]] TEXT NOT NULL,
+				[[recordId]]     TEXT NOT NULL,
+				[[roleId]]       TEXT NOT NULL,
+				[[created]]      TEXT DEFAULT "" NOT NULL,
+				[[updated]]      TEXT DEFAULT "" NOT NULL,
+				---
+				FOREIGN KEY ([[collectionId]]) REFERENCES {{_collections}} ([[id]]) ON UPDATE CASCADE ON DELETE CASCADE,
+				FOREIGN KEY ([[roleId]]) REFERENCES {{_roles}} ([[id]]) ON UPDATE CASCADE ON DELETE CASCADE
+			);
+
+			CREATE UNIQUE INDEX _authRoles_record_role_idx on {{_authRoles}} ([[collectionId]], [[recordId]], [[roleId]]);
+			CREATE INDEX _authRoles_role_idx on {{_authRoles}} ([[roleId]]);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		if _, err := db.DropTable("_authRoles").Execute(); err != nil {
+			return err
+		}
+
+		_, err := db.DropTable("_roles").Execute()
+
+		return err
+	})
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE {{_apiKeys}} (
+				[[id]]      TEXT PRIMARY KEY NOT NULL,
+				[[name]]    TEXT NOT NULL,
+				[[keyHash]] TEXT NOT NULL,
+				[[keyHint]] TEXT DEFAULT "" NOT NULL,
+				[[scopes]]  JSON DEFAULT "{}" NOT NULL,
+				[[created]] TEXT DEFAULT "" NOT NULL,
+				[[updated]] TEXT DEFAULT "" NOT NULL
+			);
+
+			CREATE UNIQUE INDEX _apiKeys_keyHash_idx on {{_apiKeys}} ([[keyHash]]);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_apiKeys").Execute()
+
+		return err
+	})
+}
@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE {{_webauthnCredentials}} (
+				[[id]]           TEXT PRIMARY KEY NOT NULL,
+				[[collectionId]] TEXT NOT NULL,
+				[[recordId]]     TEXT NOT NULL,
+				[[name]]         TEXT DEFAULT "" NOT NULL,
+				[[credentialId]] TEXT NOT NULL,
+				[[publicKey]]    TEXT NOT NULL,
+				[[algorithm]]    INTEGER DEFAULT 0 NOT NULL,
+				[[signCount]]    INTEGER DEFAULT 0 NOT NULL,
+				[[lastUsed]]     TEXT DEFAULT "" NOT NULL,
+				[[created]]      TEXT DEFAULT "" NOT NULL,
+				[[updated]]      TEXT DEFAULT "" NOT NULL,
+				---
+				FOREIGN KEY ([[collectionId]]) REFERENCES {{_collections}} ([[id]]) ON UPDATE CASCADE ON DELETE CASCADE
+			);
+
+			CREATE UNIQUE INDEX _webauthnCredentials_collection_credentialId_idx on {{_webauthnCredentials}} ([[collectionId]], [[credentialId]]);
+			CREATE INDEX _webauthnCredentials_record_idx on {{_webauthnCredentials}} ([[collectionId]], [[recordId]]);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_webauthnCredentials").Execute()
+
+		return err
+	})
+}
@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE {{_authSessions}} (
+				[[id]]           TEXT PRIMARY KEY NOT NULL,
+				[[collectionId]] TEXT NOT NULL,
+				[[recordId]]     TEXT NOT NULL,
+				[[userAgent]]    TEXT DEFAULT "" NOT NULL,
+				[[ip]]           TEXT DEFAULT "" NOT NULL,
+				[[tokenHash]]    TEXT NOT NULL,
+				[[keyHash]]      TEXT NOT NULL,
+				[[rotation]]     INTEGER DEFAULT 0 NOT NULL,
+				[[expires]]      TEXT DEFAULT "" NOT NULL,
+				[[created]]      TEXT DEFAULT "" NOT NULL,
+				[[updated]]      TEXT DEFAULT "" NOT NULL,
+				---
+				FOREIGN KEY ([[collectionId]]) REFERENCES {{_collections}} ([[id]]) ON UPDATE CASCADE ON DELETE CASCADE
+			);
+
+			CREATE UNIQUE INDEX _authSessions_tokenHash_idx on {{_authSessions}} ([[tokenHash]]);
+			CREATE INDEX _authSessions_record_idx on {{_authSessions}} ([[collectionId]], [[recordId]]);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_authSessions").Execute()
+
+		return err
+	})
+}
@@ -0,0 +1,58 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE {{_reports}} (
+				[[id]]            TEXT PRIMARY KEY NOT NULL,
+				[[name]]          TEXT UNIQUE NOT NULL,
+				[[collectionId]]  TEXT NOT NULL,
+				[[filter]]        TEXT DEFAULT "" NOT NULL,
+				[[groupBy]]       TEXT DEFAULT "" NOT NULL,
+				[[aggregate]]     TEXT DEFAULT "" NOT NULL,
+				[[sort]]          TEXT DEFAULT "" NOT NULL,
+				[[format]]        TEXT DEFAULT "csv" NOT NULL,
+				[[schedule]]      TEXT NOT NULL,
+				[[emails]]        JSON DEFAULT "[]" NOT NULL,
+				[[webhookUrl]]    TEXT DEFAULT "" NOT NULL,
+				[[webhookSecret]] TEXT DEFAULT "" NOT NULL,
+				[[enabled]]       BOOLEAN DEFAULT FALSE NOT NULL,
+				[[lastRunAt]]     TEXT DEFAULT "" NOT NULL,
+				[[created]]       TEXT DEFAULT "" NOT NULL,
+				[[updated]]       TEXT DEFAULT "" NOT NULL,
+				---
+				FOREIGN KEY ([[collectionId]]) REFERENCES {{_collections}} ([[id]]) ON UPDATE CASCADE ON DELETE CASCADE
+			);
+
+			CREATE TABLE {{_reportRuns}} (
+				[[id]]       TEXT PRIMARY KEY NOT NULL,
+				[[reportId]] TEXT NOT NULL,
+				[[status]]   TEXT NOT NULL,
+				[[rows]]     INTEGER DEFAULT 0 NOT NULL,
+				[[error]]    TEXT DEFAULT "" NOT NULL,
+				[[created]]  TEXT DEFAULT "" NOT NULL,
+				[[updated]]  TEXT DEFAULT "" NOT NULL,
+				---
+				FOREIGN KEY ([[reportId]]) REFERENCES {{_reports}} ([[id]]) ON UPDATE CASCADE ON DELETE CASCADE
+			);
+
+			CREATE INDEX _reportRuns_report_idx on {{_reportRuns}} ([[reportId]], [[created]]);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		tables := []string{"_reportRuns", "_reports"}
+
+		for _, name := range tables {
+			if _, err := db.DropTable(name).Execute(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE {{_loginLinks}} (
+				[[id]]           TEXT PRIMARY KEY NOT NULL,
+				[[collectionId]] TEXT NOT NULL,
+				[[recordId]]     TEXT NOT NULL,
+				[[created]]      TEXT DEFAULT "" NOT NULL,
+				[[updated]]      TEXT DEFAULT "" NOT NULL,
+				---
+				FOREIGN KEY ([[collectionId]]) REFERENCES {{_collections}} ([[id]]) ON UPDATE CASCADE ON DELETE CASCADE
+			);
+
+			CREATE INDEX _loginLinks_record_idx on {{_loginLinks}} ([[collectionId]], [[recordId]]);
+		`).Execute()
+		if err != nil {
+			return err
+		}
+
+		// persist the new login link token secret so that it doesn't change on restart
+		// (encrypted settings will be updated on the next settings save)
+		_, err = db.NewQuery(`
+			UPDATE {{_params}}
+			SET [[value]] = json_set([[value]], '$.recordLoginLinkToken', json({:config}))
+			WHERE [[key]] = 'settings' AND json_valid([[value]]) AND json_type([[value]], '$.recordLoginLinkToken') IS NULL
+		`).Bind(dbx.Params{
+			"config": fmt.Sprintf(`{"secret":%q,"duration":600}`, security.RandomString(50)),
+		}).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_loginLinks").Execute()
+
+		return err
+	})
+}
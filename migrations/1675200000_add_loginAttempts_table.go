@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+func init() {
+	AppMigrations.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery(`
+			CREATE TABLE {{_loginAttempts}} (
+				[[id]]          TEXT PRIMARY KEY NOT NULL,
+				[[kind]]        TEXT NOT NULL,
+				[[key]]         TEXT NOT NULL,
+				[[failures]]    INTEGER DEFAULT 0 NOT NULL,
+				[[lockouts]]    INTEGER DEFAULT 0 NOT NULL,
+				[[lockedUntil]] TEXT DEFAULT "" NOT NULL,
+				[[created]]     TEXT DEFAULT "" NOT NULL,
+				[[updated]]     TEXT DEFAULT "" NOT NULL
+			);
+
+			CREATE UNIQUE INDEX _loginAttempts_kind_key_idx on {{_loginAttempts}} ([[kind]], [[key]]);
+		`).Execute()
+
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.DropTable("_loginAttempts").Execute()
+
+		return err
+	})
+}
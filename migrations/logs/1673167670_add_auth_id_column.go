@@ -0,0 +1,30 @@
+package logs
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+func init() {
+	LogsMigrations.Register(func(db dbx.Builder) error {
+		// add new authId column (stores the admin or auth record id of the request)
+		if _, err := db.AddColumn("_requests", "authId", `TEXT DEFAULT "" NOT NULL`).Execute(); err != nil {
+			return err
+		}
+
+		if _, err := db.CreateIndex("_requests", "_request_auth_id_idx", "authId").Execute(); err != nil {
+			return err
+		}
+
+		return nil
+	}, func(db dbx.Builder) error {
+		if _, err := db.DropIndex("_requests", "_request_auth_id_idx").Execute(); err != nil {
+			return err
+		}
+
+		if _, err := db.DropColumn("_requests", "authId").Execute(); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,30 @@
+package logs
+
+import (
+	"github.com/pocketbase/dbx"
+)
+
+func init() {
+	LogsMigrations.Register(func(db dbx.Builder) error {
+		// add new requestId column (stores the request tracing id)
+		if _, err := db.AddColumn("_requests", "requestId", `TEXT DEFAULT "" NOT NULL`).Execute(); err != nil {
+			return err
+		}
+
+		if _, err := db.CreateIndex("_requests", "_request_request_id_idx", "requestId").Execute(); err != nil {
+			return err
+		}
+
+		return nil
+	}, func(db dbx.Builder) error {
+		if _, err := db.DropIndex("_requests", "_request_request_id_idx").Execute(); err != nil {
+			return err
+		}
+
+		if _, err := db.DropColumn("_requests", "requestId").Execute(); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
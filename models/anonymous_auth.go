@@ -0,0 +1,16 @@
+package models
+
+var _ Model = (*AnonymousAuth)(nil)
+
+// AnonymousAuth marks an auth record as anonymous (aka. guest)
+// until it is upgraded with email/password or OAuth2 credentials.
+type AnonymousAuth struct {
+	BaseModel
+
+	CollectionId string `db:"collectionId" json:"collectionId"`
+	RecordId     string `db:"recordId" json:"recordId"`
+}
+
+func (m *AnonymousAuth) TableName() string {
+	return "_anonymousAuths"
+}
@@ -0,0 +1,14 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+)
+
+func TestAnonymousAuthTableName(t *testing.T) {
+	m := models.AnonymousAuth{}
+	if m.TableName() != "_anonymousAuths" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
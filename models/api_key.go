@@ -0,0 +1,68 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+var _ Model = (*ApiKey)(nil)
+
+// ApiKeyPrefix is the common prefix of all generated plain api keys.
+const ApiKeyPrefix = "pbk_"
+
+// ApiKey defines an admin managed service token that grants
+// access to the collections listed in its scopes.
+//
+// Only the hash of the key is stored. The plain key is
+// available once, right after calling [ApiKey.RefreshKey()].
+type ApiKey struct {
+	BaseModel
+
+	Name    string `db:"name" json:"name"`
+	KeyHash string `db:"keyHash" json:"-"`
+	KeyHint string `db:"keyHint" json:"keyHint"`
+
+	// Scopes maps collection ids to a free form scope string
+	// (eg. "read", "write") accessible in the API rules
+	// with `@request.apiKey.scope`.
+	Scopes types.JsonMap `db:"scopes" json:"scopes"`
+}
+
+func (m *ApiKey) TableName() string {
+	return "_apiKeys"
+}
+
+// Scope returns the api key scope for the provided collection id
+// and whether the collection is accessible with the api key.
+func (m *ApiKey) Scope(collectionId string) (string, bool) {
+	raw, ok := m.Scopes[collectionId]
+	if !ok {
+		return "", false
+	}
+
+	scope, _ := raw.(string)
+
+	return scope, true
+}
+
+// RefreshKey generates and sets a new random api key hash
+// and returns its plain value.
+func (m *ApiKey) RefreshKey() string {
+	key := ApiKeyPrefix + security.RandomString(40)
+
+	m.KeyHash = HashApiKey(key)
+	m.KeyHint = key[len(key)-4:]
+
+	return key
+}
+
+// HashApiKey returns the stored hash representation of a plain api key.
+func HashApiKey(key string) string {
+	h := sha256.Sum256([]byte(strings.TrimSpace(key)))
+
+	return hex.EncodeToString(h[:])
+}
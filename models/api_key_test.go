@@ -0,0 +1,80 @@
+package models_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestApiKeyTableName(t *testing.T) {
+	m := models.ApiKey{}
+	if m.TableName() != "_apiKeys" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
+
+func TestApiKeyScope(t *testing.T) {
+	m := models.ApiKey{
+		Scopes: types.JsonMap{
+			"c1": "read",
+			"c2": "",
+		},
+	}
+
+	scenarios := []struct {
+		collectionId  string
+		expectedScope string
+		expectedOk    bool
+	}{
+		{"", "", false},
+		{"missing", "", false},
+		{"c1", "read", true},
+		{"c2", "", true},
+	}
+
+	for i, s := range scenarios {
+		scope, ok := m.Scope(s.collectionId)
+
+		if scope != s.expectedScope {
+			t.Errorf("(%d) Expected scope %q, got %q", i, s.expectedScope, scope)
+		}
+
+		if ok != s.expectedOk {
+			t.Errorf("(%d) Expected ok %v, got %v", i, s.expectedOk, ok)
+		}
+	}
+}
+
+func TestApiKeyRefreshKey(t *testing.T) {
+	m := models.ApiKey{}
+
+	key := m.RefreshKey()
+
+	if !strings.HasPrefix(key, models.ApiKeyPrefix) {
+		t.Fatalf("Expected the key to start with %q, got %q", models.ApiKeyPrefix, key)
+	}
+
+	if m.KeyHash != models.HashApiKey(key) {
+		t.Fatalf("Expected key hash %q, got %q", models.HashApiKey(key), m.KeyHash)
+	}
+
+	if !strings.HasSuffix(key, m.KeyHint) || len(m.KeyHint) != 4 {
+		t.Fatalf("Invalid key hint %q for key %q", m.KeyHint, key)
+	}
+
+	if key2 := m.RefreshKey(); key2 == key {
+		t.Fatalf("Expected a different key after refresh, got %q", key2)
+	}
+}
+
+func TestHashApiKey(t *testing.T) {
+	if models.HashApiKey("test") == models.HashApiKey("test2") {
+		t.Fatal("Expected different hashes for different keys")
+	}
+
+	if models.HashApiKey("test") != models.HashApiKey(" test ") {
+		t.Fatal("Expected the surrounding whitespaces to be ignored")
+	}
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+var _ Model = (*AuthSession)(nil)
+
+// AuthSession defines a server-side stored auth record session
+// (aka. a single signed in device) of a collection with enabled sessions.
+//
+// Only the hash of the session refresh token is stored. The plain token
+// is available once, right after calling [AuthSession.RefreshToken()].
+type AuthSession struct {
+	BaseModel
+
+	CollectionId string `db:"collectionId" json:"collectionId"`
+	RecordId     string `db:"recordId" json:"recordId"`
+	UserAgent    string `db:"userAgent" json:"userAgent"`
+	Ip           string `db:"ip" json:"ip"`
+
+	// TokenHash is the hash of the current session refresh token.
+	TokenHash string `db:"tokenHash" json:"-"`
+
+	// KeyHash is the hash of the auth record token key at the time of the
+	// session creation (the session is invalidated when the key changes).
+	KeyHash string `db:"keyHash" json:"-"`
+
+	// Rotation is the number of the session refresh token rotations
+	// (the access tokens issued before the last rotation are invalid).
+	Rotation int `db:"rotation" json:"-"`
+
+	// Expires is the session expiration datetime (it is
+	// extended with each refresh token rotation).
+	Expires types.DateTime `db:"expires" json:"expires"`
+}
+
+func (m *AuthSession) TableName() string {
+	return "_authSessions"
+}
+
+// SetAuthRecord binds the session to the provided auth record
+// (incl. its current token key).
+func (m *AuthSession) SetAuthRecord(record *Record) {
+	m.CollectionId = record.Collection().Id
+	m.RecordId = record.Id
+	m.KeyHash = hashAuthSessionKey(record.TokenKey())
+}
+
+// HasAuthRecord checks whether the session is bound to the
+// provided auth record and its current token key.
+func (m *AuthSession) HasAuthRecord(record *Record) bool {
+	return m.CollectionId == record.Collection().Id &&
+		m.RecordId == record.Id &&
+		subtle.ConstantTimeCompare([]byte(m.KeyHash), []byte(hashAuthSessionKey(record.TokenKey()))) == 1
+}
+
+// IsExpired checks whether the session has already expired.
+func (m *AuthSession) IsExpired() bool {
+	return m.Expires.IsZero() || !m.Expires.Time().After(time.Now())
+}
+
+// RefreshToken generates and sets a new random session refresh token hash,
+// increments the session rotation and returns the plain token value.
+func (m *AuthSession) RefreshToken() string {
+	token := security.RandomString(50)
+
+	m.TokenHash = HashAuthSessionToken(token)
+	m.Rotation++
+
+	return token
+}
+
+// HashAuthSessionToken returns the stored hash representation of a plain session refresh token.
+func HashAuthSessionToken(token string) string {
+	h := sha256.Sum256([]byte(strings.TrimSpace(token)))
+
+	return hex.EncodeToString(h[:])
+}
+
+func hashAuthSessionKey(tokenKey string) string {
+	h := sha256.Sum256([]byte(tokenKey))
+
+	return hex.EncodeToString(h[:])
+}
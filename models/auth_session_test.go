@@ -0,0 +1,105 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestAuthSessionTableName(t *testing.T) {
+	m := models.AuthSession{}
+	if m.TableName() != "_authSessions" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
+
+func TestAuthSessionSetAuthRecord(t *testing.T) {
+	collection := &models.Collection{Type: models.CollectionTypeAuth}
+	collection.Id = "test_collection"
+
+	record := models.NewRecord(collection)
+	record.Id = "test_record"
+	record.SetTokenKey("test_key")
+
+	m := models.AuthSession{}
+
+	if m.HasAuthRecord(record) {
+		t.Fatal("Expected the session to not be bound to the record")
+	}
+
+	m.SetAuthRecord(record)
+
+	if m.CollectionId != collection.Id || m.RecordId != record.Id {
+		t.Fatalf("Expected collectionId %q and recordId %q, got %q and %q", collection.Id, record.Id, m.CollectionId, m.RecordId)
+	}
+
+	if !m.HasAuthRecord(record) {
+		t.Fatal("Expected the session to be bound to the record")
+	}
+
+	record.RefreshTokenKey()
+
+	if m.HasAuthRecord(record) {
+		t.Fatal("Expected the session to be invalidated after the token key change")
+	}
+}
+
+func TestAuthSessionIsExpired(t *testing.T) {
+	past, _ := types.ParseDateTime(time.Now().Add(-1 * time.Minute))
+	future, _ := types.ParseDateTime(time.Now().Add(1 * time.Minute))
+
+	scenarios := []struct {
+		expires  types.DateTime
+		expected bool
+	}{
+		{types.DateTime{}, true},
+		{past, true},
+		{future, false},
+	}
+
+	for i, s := range scenarios {
+		m := models.AuthSession{Expires: s.expires}
+
+		if v := m.IsExpired(); v != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, v)
+		}
+	}
+}
+
+func TestAuthSessionRefreshToken(t *testing.T) {
+	m := models.AuthSession{}
+
+	token := m.RefreshToken()
+
+	if token == "" {
+		t.Fatal("Expected non-empty token")
+	}
+
+	if m.TokenHash != models.HashAuthSessionToken(token) {
+		t.Fatalf("Expected token hash %q, got %q", models.HashAuthSessionToken(token), m.TokenHash)
+	}
+
+	if m.Rotation != 1 {
+		t.Fatalf("Expected rotation 1, got %d", m.Rotation)
+	}
+
+	if token2 := m.RefreshToken(); token2 == token {
+		t.Fatalf("Expected a different token after refresh, got %q", token2)
+	}
+
+	if m.Rotation != 2 {
+		t.Fatalf("Expected rotation 2, got %d", m.Rotation)
+	}
+}
+
+func TestHashAuthSessionToken(t *testing.T) {
+	if models.HashAuthSessionToken("test") == models.HashAuthSessionToken("test2") {
+		t.Fatal("Expected different hashes for different tokens")
+	}
+
+	if models.HashAuthSessionToken("test") != models.HashAuthSessionToken(" test ") {
+		t.Fatal("Expected the surrounding whitespaces to be ignored")
+	}
+}
@@ -2,10 +2,19 @@ package models
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tools/ldap"
+	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/saml"
+	"github.com/pocketbase/pocketbase/tools/search"
 	"github.com/pocketbase/pocketbase/tools/types"
 )
 
@@ -19,6 +28,15 @@ const (
 	CollectionTypeAuth = "auth"
 )
 
+var uniqueConstraintNameRegex = regexp.MustCompile(`^\w+$`)
+
+var databaseNameRegex = regexp.MustCompile(`^\w+$`)
+
+const (
+	ModerationActionFlag   = "flag"
+	ModerationActionReject = "reject"
+)
+
 type Collection struct {
 	BaseModel
 
@@ -34,7 +52,21 @@ type Collection struct {
 	UpdateRule *string `db:"updateRule" json:"updateRule"`
 	DeleteRule *string `db:"deleteRule" json:"deleteRule"`
 
+	// SubscribeRule is evaluated when registering a realtime subscription
+	// to the collection records (the sent record events are still
+	// filtered with the ListRule and ViewRule).
+	SubscribeRule *string `db:"subscribeRule" json:"subscribeRule"`
+
+	// RuleMacros defines the collection level named rule fragments
+	// (name => rule) that could be referenced in the collection
+	// API rules as "@macro.name" (they take precedence over the app ones).
+	RuleMacros types.JsonMap `db:"ruleMacros" json:"ruleMacros"`
+
 	Options types.JsonMap `db:"options" json:"options"`
+
+	// Version is incremented on each collection update and it is used
+	// to detect concurrent modifications (aka. optimistic locking).
+	Version int `db:"version" json:"version"`
 }
 
 // TableName returns the Collection model SQL table name.
@@ -57,15 +89,134 @@ func (m *Collection) IsAuth() bool {
 	return m.Type == CollectionTypeAuth
 }
 
+// IsExternal checks if the current collection is a read-only
+// "base" collection for an externally managed table.
+func (m *Collection) IsExternal() bool {
+	return m.IsBase() && m.BaseOptions().External
+}
+
+// Database returns the name of the attached database where the
+// collection records table is stored (empty string for the main one).
+//
+// Only the "base" collections could be stored in an attached database.
+func (m *Collection) Database() string {
+	if !m.IsBase() {
+		return ""
+	}
+
+	return m.BaseOptions().Database
+}
+
+// QualifiedTableName returns the collection records table name
+// prefixed with its attached database name (if any), eg. "analytics.events".
+func (m *Collection) QualifiedTableName() string {
+	if db := m.Database(); db != "" {
+		return db + "." + m.Name
+	}
+
+	return m.Name
+}
+
+// TreeField returns the collection self-relation field with enabled
+// tree option or nil if the collection doesn't have such field.
+func (m *Collection) TreeField() *schema.SchemaField {
+	for _, field := range m.Schema.Fields() {
+		if field.Type != schema.FieldTypeRelation {
+			continue
+		}
+
+		options, _ := field.Options.(*schema.RelationOptions)
+		if options == nil || !options.Tree {
+			continue
+		}
+
+		if options.CollectionId != "" &&
+			options.CollectionId == m.Id &&
+			options.MaxSelect != nil &&
+			*options.MaxSelect == 1 {
+			return field
+		}
+	}
+
+	return nil
+}
+
+// ComputedFieldExpr builds the raw db expression of the provided
+// computed field with its referenced columns prefixed by tableAlias.
+//
+// Only the base model fields and the non-computed schema fields
+// could be referenced in the field expression.
+func (m *Collection) ComputedFieldExpr(field *schema.SchemaField, tableAlias string) (string, error) {
+	if field == nil || field.Type != schema.FieldTypeComputed {
+		return "", errors.New("Not a computed field.")
+	}
+
+	if err := field.InitOptions(); err != nil {
+		return "", err
+	}
+
+	options, ok := field.Options.(*schema.ComputedOptions)
+	if !ok {
+		return "", errors.New("Failed to initialize the computed field options.")
+	}
+
+	return search.ComputedExpr(options.Expression).Build(func(identifier string) (string, error) {
+		if !list.ExistInSlice(identifier, schema.BaseModelFieldNames()) {
+			f := m.Schema.GetFieldByName(identifier)
+			// the encrypted values can't be evaluated at db level
+			if f == nil || f.Type == schema.FieldTypeComputed || f.Type == schema.FieldTypeEncrypted {
+				return "", fmt.Errorf("Invalid computed field reference %q.", identifier)
+			}
+		}
+
+		return fmt.Sprintf("[[%s.%s]]", tableAlias, identifier), nil
+	})
+}
+
 // MarshalJSON implements the [json.Marshaler] interface.
+//
+// The auth collection LDAP bind password is write-only and
+// it is always serialized as empty string.
 func (m Collection) MarshalJSON() ([]byte, error) {
 	type alias Collection // prevent recursion
 
 	m.NormalizeOptions()
 
+	// note: NormalizeOptions creates a new options map so
+	// the original collection options are not affected
+	if ldap, ok := m.Options["ldap"].(map[string]any); ok && ldap["bindPassword"] != "" {
+		ldap["bindPassword"] = ""
+	}
+
 	return json.Marshal(alias(m))
 }
 
+// RestoreLdapBindPassword sets the LDAP bind password of the current
+// auth collection from the existing one if it is blank.
+//
+// Because the bind password is not serialized, this is used
+// to keep the stored value when the collection is updated
+// or imported from its json representation.
+func (m *Collection) RestoreLdapBindPassword(existing *Collection) error {
+	if existing == nil || !m.IsAuth() || !existing.IsAuth() {
+		return nil
+	}
+
+	options := m.AuthOptions()
+	if options.Ldap.BindPassword != "" {
+		return nil
+	}
+
+	existingPassword := existing.AuthOptions().Ldap.BindPassword
+	if existingPassword == "" {
+		return nil
+	}
+
+	options.Ldap.BindPassword = existingPassword
+
+	return m.SetOptions(options)
+}
+
 // BaseOptions decodes the current collection options and returns them
 // as new [CollectionBaseOptions] instance.
 func (m *Collection) BaseOptions() CollectionBaseOptions {
@@ -82,6 +233,114 @@ func (m *Collection) AuthOptions() CollectionAuthOptions {
 	return result
 }
 
+// QuotaOptions decodes the current collection options and returns
+// the records quota ones as new [CollectionQuotaOptions] instance.
+func (m *Collection) QuotaOptions() CollectionQuotaOptions {
+	result := CollectionQuotaOptions{}
+	m.DecodeOptions(&result)
+	return result
+}
+
+// ModerationOptions decodes the current collection options and returns
+// the content moderation ones as new [CollectionModerationOptions] instance.
+func (m *Collection) ModerationOptions() CollectionModerationOptions {
+	result := struct {
+		Moderation CollectionModerationOptions `json:"moderation"`
+	}{}
+	m.DecodeOptions(&result)
+	return result.Moderation
+}
+
+// BotProtectionOptions decodes the current collection options and returns
+// the bot protection ones as new [CollectionBotProtectionOptions] instance.
+func (m *Collection) BotProtectionOptions() CollectionBotProtectionOptions {
+	result := struct {
+		BotProtection CollectionBotProtectionOptions `json:"botProtection"`
+	}{}
+	m.DecodeOptions(&result)
+	return result.BotProtection
+}
+
+// EncryptionOptions decodes the current collection options and returns
+// the records encryption ones as new [CollectionEncryptionOptions] instance.
+func (m *Collection) EncryptionOptions() CollectionEncryptionOptions {
+	result := struct {
+		Encryption CollectionEncryptionOptions `json:"encryption"`
+	}{}
+	m.DecodeOptions(&result)
+	return result.Encryption
+}
+
+// SoftDeleteOptions decodes the current collection options and returns
+// the records soft delete ones as new [CollectionSoftDeleteOptions] instance.
+func (m *Collection) SoftDeleteOptions() CollectionSoftDeleteOptions {
+	result := struct {
+		SoftDelete CollectionSoftDeleteOptions `json:"softDelete"`
+	}{}
+	m.DecodeOptions(&result)
+	return result.SoftDelete
+}
+
+// VersioningOptions decodes the current collection options and returns
+// the records versioning ones as new [CollectionVersioningOptions] instance.
+func (m *Collection) VersioningOptions() CollectionVersioningOptions {
+	result := struct {
+		Versioning CollectionVersioningOptions `json:"versioning"`
+	}{}
+	m.DecodeOptions(&result)
+	return result.Versioning
+}
+
+// ExternalIdOptions decodes the current collection options and returns
+// the records external id ones as new [CollectionExternalIdOptions] instance.
+func (m *Collection) ExternalIdOptions() CollectionExternalIdOptions {
+	result := struct {
+		ExternalId CollectionExternalIdOptions `json:"externalId"`
+	}{}
+	m.DecodeOptions(&result)
+	return result.ExternalId
+}
+
+// PresetsOptions decodes the current collection options and returns
+// the records saved filter presets ones as new [CollectionPresetsOptions] instance.
+func (m *Collection) PresetsOptions() CollectionPresetsOptions {
+	result := struct {
+		Presets CollectionPresetsOptions `json:"presets"`
+	}{}
+	m.DecodeOptions(&result)
+	return result.Presets
+}
+
+// HistoryOptions decodes the current collection options and returns
+// the records change history ones as new [CollectionHistoryOptions] instance.
+func (m *Collection) HistoryOptions() CollectionHistoryOptions {
+	result := struct {
+		History CollectionHistoryOptions `json:"history"`
+	}{}
+	m.DecodeOptions(&result)
+	return result.History
+}
+
+// TransactionOptions decodes the current collection options and returns
+// the records request transaction ones as new [CollectionTransactionOptions] instance.
+func (m *Collection) TransactionOptions() CollectionTransactionOptions {
+	result := struct {
+		Transaction CollectionTransactionOptions `json:"transaction"`
+	}{}
+	m.DecodeOptions(&result)
+	return result.Transaction
+}
+
+// UniqueConstraints decodes the current collection options and returns
+// the records multi-column unique constraints.
+func (m *Collection) UniqueConstraints() []CollectionUniqueConstraint {
+	result := struct {
+		UniqueConstraints []CollectionUniqueConstraint `json:"uniqueConstraints"`
+	}{}
+	m.DecodeOptions(&result)
+	return result.UniqueConstraints
+}
+
 // NormalizeOptions updates the current collection options with a
 // new normalized state based on the collection type.
 func (m *Collection) NormalizeOptions() error {
@@ -143,13 +402,443 @@ func (m *Collection) SetOptions(typedOptions any) error {
 
 // -------------------------------------------------------------------
 
-// CollectionAuthOptions defines the "base" Collection.Options fields.
+// CollectionQuotaOptions defines the records quota Collection.Options
+// fields (available for all collection types).
+type CollectionQuotaOptions struct {
+	// MaxRecords is the max number of records that could be created
+	// in the collection via the records create API (0 means no limit).
+	MaxRecords int `form:"maxRecords" json:"maxRecords"`
+
+	// CreateRateLimit is the max number of records that a single auth
+	// record could create within CreateRateWindow (0 means no limit).
+	CreateRateLimit int `form:"createRateLimit" json:"createRateLimit"`
+
+	// CreateRateWindow is the CreateRateLimit time window in seconds
+	// (eg. 86400 for max CreateRateLimit records per day).
+	CreateRateWindow int `form:"createRateWindow" json:"createRateWindow"`
+}
+
+// Validate implements [validation.Validatable] interface.
+func (o CollectionQuotaOptions) Validate() error {
+	return validation.ValidateStruct(&o,
+		validation.Field(&o.MaxRecords, validation.Min(0)),
+		validation.Field(&o.CreateRateLimit, validation.Min(0)),
+		validation.Field(
+			&o.CreateRateWindow,
+			validation.When(o.CreateRateLimit > 0, validation.Required),
+			validation.Min(0),
+		),
+	)
+}
+
+// CollectionModerationOptions defines the records content moderation
+// Collection.Options fields.
+type CollectionModerationOptions struct {
+	// Enabled enables the content moderation of the records create
+	// and update API requests (admins are never moderated).
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// Action specifies what happens with the flagged records
+	// (ModerationActionFlag or ModerationActionReject).
+	Action string `form:"action" json:"action"`
+
+	// Fields is a list with the moderated text fields
+	// (empty means all collection text fields).
+	Fields []string `form:"fields" json:"fields"`
+
+	// MaxLinks is the max number of links allowed in a single
+	// moderated field (0 means no limit).
+	MaxLinks int `form:"maxLinks" json:"maxLinks"`
+
+	// BannedWords is a list with case-insensitive words and phrases
+	// that are not allowed in the moderated fields.
+	BannedWords []string `form:"bannedWords" json:"bannedWords"`
+
+	// CheckDuplicates flags the records whose moderated field value
+	// is the same as the one of another collection record.
+	CheckDuplicates bool `form:"checkDuplicates" json:"checkDuplicates"`
+
+	// VerdictField is the name of an optional json field
+	// where to store the moderation verdict.
+	VerdictField string `form:"verdictField" json:"verdictField"`
+}
+
+// Validate implements [validation.Validatable] interface.
+func (o CollectionModerationOptions) Validate() error {
+	return validation.ValidateStruct(&o,
+		validation.Field(
+			&o.Action,
+			validation.When(o.Enabled, validation.Required),
+			validation.In(ModerationActionFlag, ModerationActionReject),
+		),
+		validation.Field(&o.Fields, validation.Each(validation.Required)),
+		validation.Field(&o.MaxLinks, validation.Min(0)),
+		validation.Field(&o.BannedWords, validation.Each(validation.Required)),
+	)
+}
+
+// CollectionBotProtectionOptions defines the records create API
+// bot mitigation Collection.Options fields (usually used with public
+// create rule collections, eg. contact forms).
+type CollectionBotProtectionOptions struct {
+	// HoneypotFields is a list with non-schema request data fields
+	// that must be left empty (aka. hidden form inputs that only bots fill).
+	HoneypotFields []string `form:"honeypotFields" json:"honeypotFields"`
+
+	// MinSubmitTime is the min number of seconds between the form
+	// render and its submit (0 means no limit).
+	MinSubmitTime int `form:"minSubmitTime" json:"minSubmitTime"`
+
+	// SubmitTimeField is the non-schema request data field with
+	// the form render unix timestamp in milliseconds.
+	SubmitTimeField string `form:"submitTimeField" json:"submitTimeField"`
+
+	// IpCooldown is the min number of seconds between two records
+	// created from the same IP address (0 means no limit).
+	IpCooldown int `form:"ipCooldown" json:"ipCooldown"`
+}
+
+// Validate implements [validation.Validatable] interface.
+func (o CollectionBotProtectionOptions) Validate() error {
+	return validation.ValidateStruct(&o,
+		validation.Field(&o.HoneypotFields, validation.Each(validation.Required)),
+		validation.Field(&o.MinSubmitTime, validation.Min(0)),
+		validation.Field(&o.SubmitTimeField, validation.When(o.MinSubmitTime > 0, validation.Required)),
+		validation.Field(&o.IpCooldown, validation.Min(0)),
+	)
+}
+
+// CollectionEncryptionOptions defines the records encryption-at-rest
+// Collection.Options fields.
+type CollectionEncryptionOptions struct {
+	// Enabled transparently encrypts the values of all non-indexed
+	// schema fields (aka. all except the unique, relation, file and
+	// computed ones) with a collection key derived from the app master key.
+	//
+	// Note that the encrypted fields can't be filtered or sorted.
+	Enabled bool `form:"enabled" json:"enabled"`
+}
+
+// CollectionSoftDeleteOptions defines the records soft delete
+// Collection.Options fields.
+type CollectionSoftDeleteOptions struct {
+	// Enabled changes the records delete API to only mark the records
+	// as deleted (see [schema.FieldNameDeleted]) and excludes them
+	// from the records queries.
+	//
+	// Note that disabling the option makes the already soft deleted
+	// records visible again.
+	Enabled bool `form:"enabled" json:"enabled"`
+}
+
+// CollectionVersioningOptions defines the records optimistic
+// concurrency control Collection.Options fields.
+type CollectionVersioningOptions struct {
+	// Enabled auto increments the records version on each update
+	// (see [schema.FieldNameVersion]) and rejects the updates
+	// of records with outdated version.
+	Enabled bool `form:"enabled" json:"enabled"`
+}
+
+// CollectionExternalIdOptions defines the records external
+// primary key mapping Collection.Options fields.
+type CollectionExternalIdOptions struct {
+	// Enabled adds a unique indexed column (see [schema.FieldNameExternalId])
+	// that could be used to store and upsert records by the primary key
+	// of an external system (eg. when syncing data from another db).
+	Enabled bool `form:"enabled" json:"enabled"`
+}
+
+// CollectionHistoryOptions defines the records change history
+// Collection.Options fields.
+type CollectionHistoryOptions struct {
+	// Enabled stores a [RecordHistory] entry with the changed
+	// schema fields on each record create, update and delete.
+	Enabled bool `form:"enabled" json:"enabled"`
+}
+
+// CollectionPresetsOptions defines the records saved filter
+// presets Collection.Options fields.
+type CollectionPresetsOptions struct {
+	// Only restricts the users and guests records filtering to the
+	// collection saved filter presets (see [SavedFilter]), aka. their
+	// raw records list/aggregate "filter" query param and bulk request
+	// filter are rejected.
+	//
+	// Note that without it the presets are only a client convenience
+	// and not an access control since they are combined with any
+	// submitted filter (the collection API rules still apply).
+	Only bool `form:"only" json:"only"`
+}
+
+// CollectionTransactionOptions defines the records request
+// transaction Collection.Options fields.
+type CollectionTransactionOptions struct {
+	// Enabled executes the whole records create, update and delete API
+	// request (incl. its before and after request hooks) in a single
+	// db transaction, aka. any hook error reverts all request changes.
+	//
+	// The request hooks must use [apis.RequestDao] for their db operations
+	// in order to be part of the request transaction.
+	Enabled bool `form:"enabled" json:"enabled"`
+}
+
+// CollectionSessionsOptions defines the auth records refresh
+// token sessions Collection.Options fields.
+type CollectionSessionsOptions struct {
+	// Enabled issues a server-side stored session refresh token together
+	// with each short-lived auth (aka. access) token.
+	//
+	// The issued access tokens are invalidated with the session revocation
+	// or with the rotation of the session refresh token.
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// AccessTokenDuration is the session access tokens duration in seconds.
+	AccessTokenDuration int64 `form:"accessTokenDuration" json:"accessTokenDuration"`
+
+	// RefreshTokenDuration is the max session inactivity duration in seconds
+	// (the session expiration is extended with each refresh token rotation).
+	RefreshTokenDuration int64 `form:"refreshTokenDuration" json:"refreshTokenDuration"`
+}
+
+// Validate implements [validation.Validatable] interface.
+func (o CollectionSessionsOptions) Validate() error {
+	return validation.ValidateStruct(&o,
+		validation.Field(
+			&o.AccessTokenDuration,
+			validation.When(o.Enabled, validation.Required),
+			validation.Min(int64(10)),
+		),
+		validation.Field(
+			&o.RefreshTokenDuration,
+			validation.When(o.Enabled, validation.Required),
+			validation.Min(o.AccessTokenDuration),
+		),
+	)
+}
+
+// CollectionSamlOptions defines the auth records SAML 2.0
+// authentication Collection.Options fields.
+type CollectionSamlOptions struct {
+	// Enabled allows the auth records to authenticate with the configured
+	// SAML identity provider (SP initiated Web Browser SSO).
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// IdpEntityId is the identity provider entity id (aka. issuer).
+	IdpEntityId string `form:"idpEntityId" json:"idpEntityId"`
+
+	// IdpSsoUrl is the identity provider HTTP-Redirect Single Sign-On url.
+	IdpSsoUrl string `form:"idpSsoUrl" json:"idpSsoUrl"`
+
+	// IdpCertificate is the identity provider PEM (or base64 DER)
+	// encoded signing certificate.
+	IdpCertificate string `form:"idpCertificate" json:"idpCertificate"`
+
+	// EmailAttribute is the optional assertion attribute with the user email
+	// (if empty, an email formatted assertion subject NameID is used instead).
+	EmailAttribute string `form:"emailAttribute" json:"emailAttribute"`
+
+	// FieldsMapping maps assertion attribute names to collection schema
+	// field names that are synced with each SAML authentication.
+	FieldsMapping map[string]string `form:"fieldsMapping" json:"fieldsMapping"`
+
+	// RedirectUrls is an optional list with the allowed login redirect url
+	// prefixes (default to the application url origin).
+	RedirectUrls []string `form:"redirectUrls" json:"redirectUrls"`
+}
+
+// Validate implements [validation.Validatable] interface.
+func (o CollectionSamlOptions) Validate() error {
+	return validation.ValidateStruct(&o,
+		validation.Field(&o.IdpEntityId, validation.When(o.Enabled, validation.Required), validation.Length(0, 1000)),
+		validation.Field(&o.IdpSsoUrl, validation.When(o.Enabled, validation.Required), is.URL),
+		validation.Field(
+			&o.IdpCertificate,
+			validation.When(o.Enabled, validation.Required),
+			validation.By(checkSamlCertificate),
+		),
+		validation.Field(&o.EmailAttribute, validation.Length(0, 255)),
+		validation.Field(&o.RedirectUrls, validation.Each(validation.Required, is.URL)),
+	)
+}
+
+func checkSamlCertificate(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	if _, err := saml.ParseCertificate(v); err != nil {
+		return validation.NewError("validation_invalid_certificate", "Invalid X.509 certificate.")
+	}
+
+	return nil
+}
+
+// LdapIdentityPlaceholder is the [CollectionLdapOptions.UserFilter]
+// placeholder that is replaced with the escaped login identity.
+const LdapIdentityPlaceholder = "{identity}"
+
+// CollectionLdapOptions defines the auth records LDAP (eg. Active Directory)
+// password authentication Collection.Options fields.
+type CollectionLdapOptions struct {
+	// Enabled allows the auth records to authenticate with their
+	// directory credentials via the auth-with-password endpoint.
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// Url is the "ldap://host[:port]" or "ldaps://host[:port]" directory server url.
+	Url string `form:"url" json:"url"`
+
+	// BindDn and BindPassword are the optional service account
+	// credentials used to search for the user entry
+	// (if empty, an anonymous bind is used).
+	//
+	// BindPassword is write-only and it is not exposed
+	// in the collection json (see [Collection.MarshalJSON]).
+	BindDn       string `form:"bindDn" json:"bindDn"`
+	BindPassword string `form:"bindPassword" json:"bindPassword"`
+
+	// BaseDn is the search base of the user entries (eg. "ou=people,dc=example,dc=com").
+	BaseDn string `form:"baseDn" json:"baseDn"`
+
+	// UserFilter is the user entry search filter with
+	// the {identity} placeholder (eg. "(uid={identity})").
+	UserFilter string `form:"userFilter" json:"userFilter"`
+
+	// EmailAttribute is the optional user entry attribute with the user email.
+	EmailAttribute string `form:"emailAttribute" json:"emailAttribute"`
+
+	// FieldsMapping maps user entry attribute names to collection schema
+	// field names that are synced with each LDAP authentication.
+	FieldsMapping map[string]string `form:"fieldsMapping" json:"fieldsMapping"`
+}
+
+// Validate implements [validation.Validatable] interface.
+func (o CollectionLdapOptions) Validate() error {
+	return validation.ValidateStruct(&o,
+		validation.Field(&o.Url, validation.When(o.Enabled, validation.Required), validation.By(checkLdapUrl)),
+		validation.Field(&o.BindDn, validation.Length(0, 1000)),
+		validation.Field(&o.BindPassword, validation.When(o.BindDn != "", validation.Required), validation.Length(0, 255)),
+		validation.Field(&o.BaseDn, validation.When(o.Enabled, validation.Required), validation.Length(0, 1000)),
+		validation.Field(
+			&o.UserFilter,
+			validation.When(o.Enabled, validation.Required),
+			validation.Length(0, 1000),
+			validation.By(checkLdapUserFilter),
+		),
+		validation.Field(&o.EmailAttribute, validation.Length(0, 255)),
+	)
+}
+
+func checkLdapUrl(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	u, err := url.Parse(v)
+	if err != nil || (u.Scheme != "ldap" && u.Scheme != "ldaps") || u.Hostname() == "" {
+		return validation.NewError("validation_invalid_ldap_url", "Must be a valid ldap:// or ldaps:// url.")
+	}
+
+	return nil
+}
+
+func checkLdapUserFilter(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	if !strings.Contains(v, LdapIdentityPlaceholder) {
+		return validation.NewError(
+			"validation_missing_identity_placeholder",
+			fmt.Sprintf("The filter must contain the %s placeholder.", LdapIdentityPlaceholder),
+		)
+	}
+
+	if _, err := ldap.CompileFilter(strings.ReplaceAll(v, LdapIdentityPlaceholder, "test")); err != nil {
+		return validation.NewError("validation_invalid_ldap_filter", "Invalid LDAP search filter.")
+	}
+
+	return nil
+}
+
+// CollectionUniqueConstraint defines a single multi-column records
+// unique constraint (backed by a unique index of the records table).
+type CollectionUniqueConstraint struct {
+	// Name is the constraint identifier used to name its unique index.
+	Name string `form:"name" json:"name"`
+
+	// Fields is a list with the names of the constrained schema fields.
+	Fields []string `form:"fields" json:"fields"`
+}
+
+// Validate implements [validation.Validatable] interface.
+func (c CollectionUniqueConstraint) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(
+			&c.Name,
+			validation.Required,
+			validation.Length(1, 100),
+			validation.Match(uniqueConstraintNameRegex),
+		),
+		validation.Field(&c.Fields, validation.Required, validation.Each(validation.Required)),
+	)
+}
+
+// CollectionBaseOptions defines the "base" Collection.Options fields.
 type CollectionBaseOptions struct {
+	// Database is the name of an attached database where to store
+	// the collection records table (empty string for the main one).
+	Database string `form:"database" json:"database"`
+
+	// External registers an existing table (not created by the app)
+	// with the same name as the collection.
+	//
+	// The app doesn't create, alter or drop the table of an external
+	// collection and its schema fields only describe the existing table
+	// columns (the table must also have an "id" column).
+	// The external collection records are read-only.
+	External bool `form:"external" json:"external"`
+
+	CollectionQuotaOptions
+
+	Moderation CollectionModerationOptions `form:"moderation" json:"moderation"`
+
+	BotProtection CollectionBotProtectionOptions `form:"botProtection" json:"botProtection"`
+
+	Encryption CollectionEncryptionOptions `form:"encryption" json:"encryption"`
+
+	SoftDelete CollectionSoftDeleteOptions `form:"softDelete" json:"softDelete"`
+
+	Versioning CollectionVersioningOptions `form:"versioning" json:"versioning"`
+
+	ExternalId CollectionExternalIdOptions `form:"externalId" json:"externalId"`
+
+	History CollectionHistoryOptions `form:"history" json:"history"`
+
+	Transaction CollectionTransactionOptions `form:"transaction" json:"transaction"`
+
+	Presets CollectionPresetsOptions `form:"presets" json:"presets"`
+
+	UniqueConstraints []CollectionUniqueConstraint `form:"uniqueConstraints" json:"uniqueConstraints"`
 }
 
 // Validate implements [validation.Validatable] interface.
 func (o CollectionBaseOptions) Validate() error {
-	return nil
+	return validation.ValidateStruct(&o,
+		validation.Field(
+			&o.Database,
+			validation.Length(1, 100),
+			validation.Match(databaseNameRegex),
+			validation.NotIn("main", "temp"),
+		),
+		validation.Field(&o.CollectionQuotaOptions),
+		validation.Field(&o.Moderation),
+		validation.Field(&o.BotProtection),
+		validation.Field(&o.UniqueConstraints),
+	)
 }
 
 // CollectionAuthOptions defines the "auth" Collection.Options fields.
@@ -158,10 +847,42 @@ type CollectionAuthOptions struct {
 	AllowOAuth2Auth    bool     `form:"allowOAuth2Auth" json:"allowOAuth2Auth"`
 	AllowUsernameAuth  bool     `form:"allowUsernameAuth" json:"allowUsernameAuth"`
 	AllowEmailAuth     bool     `form:"allowEmailAuth" json:"allowEmailAuth"`
+	AllowWebauthnAuth  bool     `form:"allowWebauthnAuth" json:"allowWebauthnAuth"`
+	AllowAnonymousAuth bool     `form:"allowAnonymousAuth" json:"allowAnonymousAuth"`
+	AllowLoginLinkAuth bool     `form:"allowLoginLinkAuth" json:"allowLoginLinkAuth"`
+	AllowPhoneAuth     bool     `form:"allowPhoneAuth" json:"allowPhoneAuth"`
 	RequireEmail       bool     `form:"requireEmail" json:"requireEmail"`
 	ExceptEmailDomains []string `form:"exceptEmailDomains" json:"exceptEmailDomains"`
 	OnlyEmailDomains   []string `form:"onlyEmailDomains" json:"onlyEmailDomains"`
 	MinPasswordLength  int      `form:"minPasswordLength" json:"minPasswordLength"`
+
+	CollectionQuotaOptions
+
+	Moderation CollectionModerationOptions `form:"moderation" json:"moderation"`
+
+	BotProtection CollectionBotProtectionOptions `form:"botProtection" json:"botProtection"`
+
+	Encryption CollectionEncryptionOptions `form:"encryption" json:"encryption"`
+
+	SoftDelete CollectionSoftDeleteOptions `form:"softDelete" json:"softDelete"`
+
+	Versioning CollectionVersioningOptions `form:"versioning" json:"versioning"`
+
+	ExternalId CollectionExternalIdOptions `form:"externalId" json:"externalId"`
+
+	History CollectionHistoryOptions `form:"history" json:"history"`
+
+	Transaction CollectionTransactionOptions `form:"transaction" json:"transaction"`
+
+	Presets CollectionPresetsOptions `form:"presets" json:"presets"`
+
+	Sessions CollectionSessionsOptions `form:"sessions" json:"sessions"`
+
+	Saml CollectionSamlOptions `form:"saml" json:"saml"`
+
+	Ldap CollectionLdapOptions `form:"ldap" json:"ldap"`
+
+	UniqueConstraints []CollectionUniqueConstraint `form:"uniqueConstraints" json:"uniqueConstraints"`
 }
 
 // Validate implements [validation.Validatable] interface.
@@ -182,5 +903,12 @@ func (o CollectionAuthOptions) Validate() error {
 			validation.Min(5),
 			validation.Max(72),
 		),
+		validation.Field(&o.CollectionQuotaOptions),
+		validation.Field(&o.Moderation),
+		validation.Field(&o.BotProtection),
+		validation.Field(&o.Sessions),
+		validation.Field(&o.Saml),
+		validation.Field(&o.Ldap),
+		validation.Field(&o.UniqueConstraints),
 	)
 }
@@ -6,6 +6,7 @@ import (
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
 	"github.com/pocketbase/pocketbase/tools/list"
 	"github.com/pocketbase/pocketbase/tools/types"
 )
@@ -66,6 +67,76 @@ func TestCollectionIsAuth(t *testing.T) {
 	}
 }
 
+func TestCollectionIsExternal(t *testing.T) {
+	scenarios := []struct {
+		collection models.Collection
+		expected   bool
+	}{
+		{models.Collection{}, false},
+		{models.Collection{Type: models.CollectionTypeBase}, false},
+		{models.Collection{Type: models.CollectionTypeBase, Options: types.JsonMap{"external": false}}, false},
+		{models.Collection{Type: models.CollectionTypeBase, Options: types.JsonMap{"external": true}}, true},
+		{models.Collection{Type: models.CollectionTypeAuth, Options: types.JsonMap{"external": true}}, false},
+	}
+
+	for i, s := range scenarios {
+		result := s.collection.IsExternal()
+		if result != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+}
+
+func TestCollectionComputedFieldExpr(t *testing.T) {
+	collection := &models.Collection{
+		Schema: schema.NewSchema(
+			&schema.SchemaField{Name: "price", Type: schema.FieldTypeNumber},
+			&schema.SchemaField{Name: "quantity", Type: schema.FieldTypeNumber},
+			&schema.SchemaField{
+				Name:    "total",
+				Type:    schema.FieldTypeComputed,
+				Options: &schema.ComputedOptions{Expression: "price * quantity"},
+			},
+			&schema.SchemaField{
+				Name:    "invalid",
+				Type:    schema.FieldTypeComputed,
+				Options: &schema.ComputedOptions{Expression: "total + missing"},
+			},
+			&schema.SchemaField{
+				Name:    "nested",
+				Type:    schema.FieldTypeComputed,
+				Options: &schema.ComputedOptions{Expression: "total * 2"},
+			},
+		),
+	}
+
+	scenarios := []struct {
+		field       *schema.SchemaField
+		expectError bool
+		expected    string
+	}{
+		{nil, true, ""},
+		{collection.Schema.GetFieldByName("price"), true, ""},
+		{collection.Schema.GetFieldByName("invalid"), true, ""},
+		{collection.Schema.GetFieldByName("nested"), true, ""},
+		{collection.Schema.GetFieldByName("total"), false, "([[demo.price]] * [[demo.quantity]])"},
+	}
+
+	for i, s := range scenarios {
+		result, err := collection.ComputedFieldExpr(s.field, "demo")
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if result != s.expected {
+			t.Errorf("(%d) Expected %q, got %q", i, s.expected, result)
+		}
+	}
+}
+
 func TestCollectionMarshalJSON(t *testing.T) {
 	scenarios := []struct {
 		name       string
@@ -75,22 +146,22 @@ func TestCollectionMarshalJSON(t *testing.T) {
 		{
 			"no type",
 			models.Collection{Name: "test"},
-			`{"id":"","created":"","updated":"","name":"test","type":"","system":false,"schema":[],"listRule":null,"viewRule":null,"createRule":null,"updateRule":null,"deleteRule":null,"options":{}}`,
+			`{"id":"","created":"","updated":"","name":"test","type":"","system":false,"schema":[],"listRule":null,"viewRule":null,"createRule":null,"updateRule":null,"deleteRule":null,"subscribeRule":null,"ruleMacros":{},"options":{"botProtection":{"honeypotFields":null,"ipCooldown":0,"minSubmitTime":0,"submitTimeField":""},"createRateLimit":0,"createRateWindow":0,"database":"","encryption":{"enabled":false},"external":false,"externalId":{"enabled":false},"history":{"enabled":false},"maxRecords":0,"moderation":{"action":"","bannedWords":null,"checkDuplicates":false,"enabled":false,"fields":null,"maxLinks":0,"verdictField":""},"presets":{"only":false},"softDelete":{"enabled":false},"transaction":{"enabled":false},"uniqueConstraints":null,"versioning":{"enabled":false}},"version":0}`,
 		},
 		{
 			"unknown type + non empty options",
 			models.Collection{Name: "test", Type: "unknown", ListRule: types.Pointer("test_list"), Options: types.JsonMap{"test": 123}},
-			`{"id":"","created":"","updated":"","name":"test","type":"unknown","system":false,"schema":[],"listRule":"test_list","viewRule":null,"createRule":null,"updateRule":null,"deleteRule":null,"options":{}}`,
+			`{"id":"","created":"","updated":"","name":"test","type":"unknown","system":false,"schema":[],"listRule":"test_list","viewRule":null,"createRule":null,"updateRule":null,"deleteRule":null,"subscribeRule":null,"ruleMacros":{},"options":{"botProtection":{"honeypotFields":null,"ipCooldown":0,"minSubmitTime":0,"submitTimeField":""},"createRateLimit":0,"createRateWindow":0,"database":"","encryption":{"enabled":false},"external":false,"externalId":{"enabled":false},"history":{"enabled":false},"maxRecords":0,"moderation":{"action":"","bannedWords":null,"checkDuplicates":false,"enabled":false,"fields":null,"maxLinks":0,"verdictField":""},"presets":{"only":false},"softDelete":{"enabled":false},"transaction":{"enabled":false},"uniqueConstraints":null,"versioning":{"enabled":false}},"version":0}`,
 		},
 		{
 			"base type + non empty options",
 			models.Collection{Name: "test", Type: models.CollectionTypeBase, ListRule: types.Pointer("test_list"), Options: types.JsonMap{"test": 123}},
-			`{"id":"","created":"","updated":"","name":"test","type":"base","system":false,"schema":[],"listRule":"test_list","viewRule":null,"createRule":null,"updateRule":null,"deleteRule":null,"options":{}}`,
+			`{"id":"","created":"","updated":"","name":"test","type":"base","system":false,"schema":[],"listRule":"test_list","viewRule":null,"createRule":null,"updateRule":null,"deleteRule":null,"subscribeRule":null,"ruleMacros":{},"options":{"botProtection":{"honeypotFields":null,"ipCooldown":0,"minSubmitTime":0,"submitTimeField":""},"createRateLimit":0,"createRateWindow":0,"database":"","encryption":{"enabled":false},"external":false,"externalId":{"enabled":false},"history":{"enabled":false},"maxRecords":0,"moderation":{"action":"","bannedWords":null,"checkDuplicates":false,"enabled":false,"fields":null,"maxLinks":0,"verdictField":""},"presets":{"only":false},"softDelete":{"enabled":false},"transaction":{"enabled":false},"uniqueConstraints":null,"versioning":{"enabled":false}},"version":0}`,
 		},
 		{
 			"auth type + non empty options",
 			models.Collection{BaseModel: models.BaseModel{Id: "test"}, Type: models.CollectionTypeAuth, Options: types.JsonMap{"test": 123, "allowOAuth2Auth": true, "minPasswordLength": 4}},
-			`{"id":"test","created":"","updated":"","name":"","type":"auth","system":false,"schema":[],"listRule":null,"viewRule":null,"createRule":null,"updateRule":null,"deleteRule":null,"options":{"allowEmailAuth":false,"allowOAuth2Auth":true,"allowUsernameAuth":false,"exceptEmailDomains":null,"manageRule":null,"minPasswordLength":4,"onlyEmailDomains":null,"requireEmail":false}}`,
+			`{"id":"test","created":"","updated":"","name":"","type":"auth","system":false,"schema":[],"listRule":null,"viewRule":null,"createRule":null,"updateRule":null,"deleteRule":null,"subscribeRule":null,"ruleMacros":{},"options":{"allowAnonymousAuth":false,"allowEmailAuth":false,"allowLoginLinkAuth":false,"allowOAuth2Auth":true,"allowPhoneAuth":false,"allowUsernameAuth":false,"allowWebauthnAuth":false,"botProtection":{"honeypotFields":null,"ipCooldown":0,"minSubmitTime":0,"submitTimeField":""},"createRateLimit":0,"createRateWindow":0,"encryption":{"enabled":false},"exceptEmailDomains":null,"externalId":{"enabled":false},"history":{"enabled":false},"ldap":{"baseDn":"","bindDn":"","bindPassword":"","emailAttribute":"","enabled":false,"fieldsMapping":null,"url":"","userFilter":""},"manageRule":null,"maxRecords":0,"minPasswordLength":4,"moderation":{"action":"","bannedWords":null,"checkDuplicates":false,"enabled":false,"fields":null,"maxLinks":0,"verdictField":""},"onlyEmailDomains":null,"presets":{"only":false},"requireEmail":false,"saml":{"emailAttribute":"","enabled":false,"fieldsMapping":null,"idpCertificate":"","idpEntityId":"","idpSsoUrl":"","redirectUrls":null},"sessions":{"accessTokenDuration":0,"enabled":false,"refreshTokenDuration":0},"softDelete":{"enabled":false},"transaction":{"enabled":false},"uniqueConstraints":null,"versioning":{"enabled":false}},"version":0}`,
 		},
 	}
 
@@ -106,6 +177,69 @@ func TestCollectionMarshalJSON(t *testing.T) {
 	}
 }
 
+func TestCollectionMarshalJSONLdapBindPassword(t *testing.T) {
+	collection := &models.Collection{Type: models.CollectionTypeAuth}
+	collection.SetOptions(models.CollectionAuthOptions{
+		Ldap: models.CollectionLdapOptions{BindDn: "cn=admin", BindPassword: "secret"},
+	})
+
+	raw, err := json.Marshal(collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := &models.Collection{}
+	if err := json.Unmarshal(raw, decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := decoded.AuthOptions().Ldap.BindPassword; v != "" {
+		t.Fatalf("Expected the serialized bind password to be empty, got %q", v)
+	}
+
+	if v := decoded.AuthOptions().Ldap.BindDn; v != "cn=admin" {
+		t.Fatalf("Expected the serialized bind dn %q, got %q", "cn=admin", v)
+	}
+
+	// the original options shouldn't be affected
+	if v := collection.AuthOptions().Ldap.BindPassword; v != "secret" {
+		t.Fatalf("Expected the original bind password to remain %q, got %q", "secret", v)
+	}
+}
+
+func TestCollectionRestoreLdapBindPassword(t *testing.T) {
+	newAuth := func(password string) *models.Collection {
+		c := &models.Collection{Type: models.CollectionTypeAuth}
+		c.SetOptions(models.CollectionAuthOptions{
+			Ldap: models.CollectionLdapOptions{BindDn: "cn=admin", BindPassword: password},
+		})
+		return c
+	}
+
+	scenarios := []struct {
+		name       string
+		collection *models.Collection
+		existing   *models.Collection
+		expected   string
+	}{
+		{"nil existing", newAuth(""), nil, ""},
+		{"non-auth existing", newAuth(""), &models.Collection{Type: models.CollectionTypeBase}, ""},
+		{"blank password", newAuth(""), newAuth("old"), "old"},
+		{"changed password", newAuth("new"), newAuth("old"), "new"},
+	}
+
+	for _, s := range scenarios {
+		if err := s.collection.RestoreLdapBindPassword(s.existing); err != nil {
+			t.Errorf("[%s] Unexpected error %v", s.name, err)
+			continue
+		}
+
+		if v := s.collection.AuthOptions().Ldap.BindPassword; v != s.expected {
+			t.Errorf("[%s] Expected bind password %q, got %q", s.name, s.expected, v)
+		}
+	}
+}
+
 func TestCollectionBaseOptions(t *testing.T) {
 	scenarios := []struct {
 		name       string
@@ -115,22 +249,22 @@ func TestCollectionBaseOptions(t *testing.T) {
 		{
 			"no type",
 			models.Collection{Options: types.JsonMap{"test": 123}},
-			"{}",
+			`{"database":"","external":false,"maxRecords":0,"createRateLimit":0,"createRateWindow":0,"moderation":{"enabled":false,"action":"","fields":null,"maxLinks":0,"bannedWords":null,"checkDuplicates":false,"verdictField":""},"botProtection":{"honeypotFields":null,"minSubmitTime":0,"submitTimeField":"","ipCooldown":0},"encryption":{"enabled":false},"softDelete":{"enabled":false},"versioning":{"enabled":false},"externalId":{"enabled":false},"history":{"enabled":false},"transaction":{"enabled":false},"presets":{"only":false},"uniqueConstraints":null}`,
 		},
 		{
 			"unknown type",
 			models.Collection{Type: "anything", Options: types.JsonMap{"test": 123}},
-			"{}",
+			`{"database":"","external":false,"maxRecords":0,"createRateLimit":0,"createRateWindow":0,"moderation":{"enabled":false,"action":"","fields":null,"maxLinks":0,"bannedWords":null,"checkDuplicates":false,"verdictField":""},"botProtection":{"honeypotFields":null,"minSubmitTime":0,"submitTimeField":"","ipCooldown":0},"encryption":{"enabled":false},"softDelete":{"enabled":false},"versioning":{"enabled":false},"externalId":{"enabled":false},"history":{"enabled":false},"transaction":{"enabled":false},"presets":{"only":false},"uniqueConstraints":null}`,
 		},
 		{
 			"different type",
 			models.Collection{Type: models.CollectionTypeAuth, Options: types.JsonMap{"test": 123, "minPasswordLength": 4}},
-			"{}",
+			`{"database":"","external":false,"maxRecords":0,"createRateLimit":0,"createRateWindow":0,"moderation":{"enabled":false,"action":"","fields":null,"maxLinks":0,"bannedWords":null,"checkDuplicates":false,"verdictField":""},"botProtection":{"honeypotFields":null,"minSubmitTime":0,"submitTimeField":"","ipCooldown":0},"encryption":{"enabled":false},"softDelete":{"enabled":false},"versioning":{"enabled":false},"externalId":{"enabled":false},"history":{"enabled":false},"transaction":{"enabled":false},"presets":{"only":false},"uniqueConstraints":null}`,
 		},
 		{
 			"base type",
 			models.Collection{Type: models.CollectionTypeBase, Options: types.JsonMap{"test": 123}},
-			"{}",
+			`{"database":"","external":false,"maxRecords":0,"createRateLimit":0,"createRateWindow":0,"moderation":{"enabled":false,"action":"","fields":null,"maxLinks":0,"bannedWords":null,"checkDuplicates":false,"verdictField":""},"botProtection":{"honeypotFields":null,"minSubmitTime":0,"submitTimeField":"","ipCooldown":0},"encryption":{"enabled":false},"softDelete":{"enabled":false},"versioning":{"enabled":false},"externalId":{"enabled":false},"history":{"enabled":false},"transaction":{"enabled":false},"presets":{"only":false},"uniqueConstraints":null}`,
 		},
 	}
 
@@ -150,7 +284,7 @@ func TestCollectionBaseOptions(t *testing.T) {
 
 func TestCollectionAuthOptions(t *testing.T) {
 	options := types.JsonMap{"test": 123, "minPasswordLength": 4}
-	expectedSerialization := `{"manageRule":null,"allowOAuth2Auth":false,"allowUsernameAuth":false,"allowEmailAuth":false,"requireEmail":false,"exceptEmailDomains":null,"onlyEmailDomains":null,"minPasswordLength":4}`
+	expectedSerialization := `{"manageRule":null,"allowOAuth2Auth":false,"allowUsernameAuth":false,"allowEmailAuth":false,"allowWebauthnAuth":false,"allowAnonymousAuth":false,"allowLoginLinkAuth":false,"allowPhoneAuth":false,"requireEmail":false,"exceptEmailDomains":null,"onlyEmailDomains":null,"minPasswordLength":4,"maxRecords":0,"createRateLimit":0,"createRateWindow":0,"moderation":{"enabled":false,"action":"","fields":null,"maxLinks":0,"bannedWords":null,"checkDuplicates":false,"verdictField":""},"botProtection":{"honeypotFields":null,"minSubmitTime":0,"submitTimeField":"","ipCooldown":0},"encryption":{"enabled":false},"softDelete":{"enabled":false},"versioning":{"enabled":false},"externalId":{"enabled":false},"history":{"enabled":false},"transaction":{"enabled":false},"presets":{"only":false},"sessions":{"enabled":false,"accessTokenDuration":0,"refreshTokenDuration":0},"saml":{"enabled":false,"idpEntityId":"","idpSsoUrl":"","idpCertificate":"","emailAttribute":"","fieldsMapping":null,"redirectUrls":null},"ldap":{"enabled":false,"url":"","bindDn":"","bindPassword":"","baseDn":"","userFilter":"","emailAttribute":"","fieldsMapping":null},"uniqueConstraints":null}`
 
 	scenarios := []struct {
 		name       string
@@ -193,6 +327,166 @@ func TestCollectionAuthOptions(t *testing.T) {
 	}
 }
 
+func TestCollectionQuotaOptions(t *testing.T) {
+	options := types.JsonMap{"test": 123, "maxRecords": 10, "createRateLimit": 2, "createRateWindow": 60}
+	expectedSerialization := `{"maxRecords":10,"createRateLimit":2,"createRateWindow":60}`
+
+	collections := []models.Collection{
+		{Options: options},
+		{Type: models.CollectionTypeBase, Options: options},
+		{Type: models.CollectionTypeAuth, Options: options},
+	}
+
+	for i, c := range collections {
+		encoded, err := json.Marshal(c.QuotaOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strEncoded := string(encoded); strEncoded != expectedSerialization {
+			t.Errorf("(%d) Expected \n%v \ngot \n%v", i, expectedSerialization, strEncoded)
+		}
+	}
+}
+
+func TestCollectionModerationOptions(t *testing.T) {
+	options := types.JsonMap{
+		"test":       123,
+		"maxRecords": 10,
+		"moderation": map[string]any{"enabled": true, "action": "flag", "bannedWords": []string{"a", "b"}},
+	}
+	expectedSerialization := `{"enabled":true,"action":"flag","fields":null,"maxLinks":0,"bannedWords":["a","b"],"checkDuplicates":false,"verdictField":""}`
+
+	collections := []models.Collection{
+		{Options: options},
+		{Type: models.CollectionTypeBase, Options: options},
+		{Type: models.CollectionTypeAuth, Options: options},
+	}
+
+	for i, c := range collections {
+		encoded, err := json.Marshal(c.ModerationOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strEncoded := string(encoded); strEncoded != expectedSerialization {
+			t.Errorf("(%d) Expected \n%v \ngot \n%v", i, expectedSerialization, strEncoded)
+		}
+	}
+}
+
+func TestCollectionBotProtectionOptions(t *testing.T) {
+	options := types.JsonMap{
+		"test":          123,
+		"maxRecords":    10,
+		"botProtection": map[string]any{"honeypotFields": []string{"a"}, "ipCooldown": 60},
+	}
+	expectedSerialization := `{"honeypotFields":["a"],"minSubmitTime":0,"submitTimeField":"","ipCooldown":60}`
+
+	collections := []models.Collection{
+		{Options: options},
+		{Type: models.CollectionTypeBase, Options: options},
+		{Type: models.CollectionTypeAuth, Options: options},
+	}
+
+	for i, c := range collections {
+		encoded, err := json.Marshal(c.BotProtectionOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strEncoded := string(encoded); strEncoded != expectedSerialization {
+			t.Errorf("(%d) Expected \n%v \ngot \n%v", i, expectedSerialization, strEncoded)
+		}
+	}
+}
+
+func TestCollectionUniqueConstraints(t *testing.T) {
+	options := types.JsonMap{
+		"test":              123,
+		"uniqueConstraints": []map[string]any{{"name": "a_b", "fields": []string{"a", "b"}}},
+	}
+	expectedSerialization := `[{"name":"a_b","fields":["a","b"]}]`
+
+	collections := []models.Collection{
+		{Options: options},
+		{Type: models.CollectionTypeBase, Options: options},
+		{Type: models.CollectionTypeAuth, Options: options},
+	}
+
+	for i, c := range collections {
+		encoded, err := json.Marshal(c.UniqueConstraints())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strEncoded := string(encoded); strEncoded != expectedSerialization {
+			t.Errorf("(%d) Expected \n%v \ngot \n%v", i, expectedSerialization, strEncoded)
+		}
+	}
+}
+
+func TestCollectionTreeField(t *testing.T) {
+	newRelation := func(name string, collectionId string, maxSelect *int, tree bool) *schema.SchemaField {
+		return &schema.SchemaField{
+			Name: name,
+			Type: schema.FieldTypeRelation,
+			Options: &schema.RelationOptions{
+				CollectionId: collectionId,
+				MaxSelect:    maxSelect,
+				Tree:         tree,
+			},
+		}
+	}
+
+	scenarios := []struct {
+		name     string
+		fields   []*schema.SchemaField
+		expected string
+	}{
+		{"no fields", nil, ""},
+		{
+			"non-tree self relation",
+			[]*schema.SchemaField{newRelation("parent", "test", types.Pointer(1), false)},
+			"",
+		},
+		{
+			"tree relation to another collection",
+			[]*schema.SchemaField{newRelation("parent", "other", types.Pointer(1), true)},
+			"",
+		},
+		{
+			"tree multiple self relation",
+			[]*schema.SchemaField{newRelation("parent", "test", nil, true)},
+			"",
+		},
+		{
+			"tree single self relation",
+			[]*schema.SchemaField{
+				newRelation("other", "test", types.Pointer(1), false),
+				newRelation("parent", "test", types.Pointer(1), true),
+			},
+			"parent",
+		},
+	}
+
+	for _, s := range scenarios {
+		collection := &models.Collection{Schema: schema.NewSchema(s.fields...)}
+		collection.Id = "test"
+
+		field := collection.TreeField()
+
+		var name string
+		if field != nil {
+			name = field.Name
+		}
+
+		if name != s.expected {
+			t.Errorf("[%s] Expected tree field %q, got %q", s.name, s.expected, name)
+		}
+	}
+}
+
 func TestNormalizeOptions(t *testing.T) {
 	scenarios := []struct {
 		name       string
@@ -202,17 +496,17 @@ func TestNormalizeOptions(t *testing.T) {
 		{
 			"unknown type",
 			models.Collection{Type: "unknown", Options: types.JsonMap{"test": 123, "minPasswordLength": 4}},
-			"{}",
+			`{"botProtection":{"honeypotFields":null,"ipCooldown":0,"minSubmitTime":0,"submitTimeField":""},"createRateLimit":0,"createRateWindow":0,"database":"","encryption":{"enabled":false},"external":false,"externalId":{"enabled":false},"history":{"enabled":false},"maxRecords":0,"moderation":{"action":"","bannedWords":null,"checkDuplicates":false,"enabled":false,"fields":null,"maxLinks":0,"verdictField":""},"presets":{"only":false},"softDelete":{"enabled":false},"transaction":{"enabled":false},"uniqueConstraints":null,"versioning":{"enabled":false}}`,
 		},
 		{
 			"base type",
 			models.Collection{Type: models.CollectionTypeBase, Options: types.JsonMap{"test": 123, "minPasswordLength": 4}},
-			"{}",
+			`{"botProtection":{"honeypotFields":null,"ipCooldown":0,"minSubmitTime":0,"submitTimeField":""},"createRateLimit":0,"createRateWindow":0,"database":"","encryption":{"enabled":false},"external":false,"externalId":{"enabled":false},"history":{"enabled":false},"maxRecords":0,"moderation":{"action":"","bannedWords":null,"checkDuplicates":false,"enabled":false,"fields":null,"maxLinks":0,"verdictField":""},"presets":{"only":false},"softDelete":{"enabled":false},"transaction":{"enabled":false},"uniqueConstraints":null,"versioning":{"enabled":false}}`,
 		},
 		{
 			"auth type",
 			models.Collection{Type: models.CollectionTypeAuth, Options: types.JsonMap{"test": 123, "minPasswordLength": 4}},
-			`{"allowEmailAuth":false,"allowOAuth2Auth":false,"allowUsernameAuth":false,"exceptEmailDomains":null,"manageRule":null,"minPasswordLength":4,"onlyEmailDomains":null,"requireEmail":false}`,
+			`{"allowAnonymousAuth":false,"allowEmailAuth":false,"allowLoginLinkAuth":false,"allowOAuth2Auth":false,"allowPhoneAuth":false,"allowUsernameAuth":false,"allowWebauthnAuth":false,"botProtection":{"honeypotFields":null,"ipCooldown":0,"minSubmitTime":0,"submitTimeField":""},"createRateLimit":0,"createRateWindow":0,"encryption":{"enabled":false},"exceptEmailDomains":null,"externalId":{"enabled":false},"history":{"enabled":false},"ldap":{"baseDn":"","bindDn":"","bindPassword":"","emailAttribute":"","enabled":false,"fieldsMapping":null,"url":"","userFilter":""},"manageRule":null,"maxRecords":0,"minPasswordLength":4,"moderation":{"action":"","bannedWords":null,"checkDuplicates":false,"enabled":false,"fields":null,"maxLinks":0,"verdictField":""},"onlyEmailDomains":null,"presets":{"only":false},"requireEmail":false,"saml":{"emailAttribute":"","enabled":false,"fieldsMapping":null,"idpCertificate":"","idpEntityId":"","idpSsoUrl":"","redirectUrls":null},"sessions":{"accessTokenDuration":0,"enabled":false,"refreshTokenDuration":0},"softDelete":{"enabled":false},"transaction":{"enabled":false},"uniqueConstraints":null,"versioning":{"enabled":false}}`,
 		},
 	}
 
@@ -262,25 +556,25 @@ func TestSetOptions(t *testing.T) {
 			"no type",
 			models.Collection{},
 			map[string]any{},
-			"{}",
+			`{"botProtection":{"honeypotFields":null,"ipCooldown":0,"minSubmitTime":0,"submitTimeField":""},"createRateLimit":0,"createRateWindow":0,"database":"","encryption":{"enabled":false},"external":false,"externalId":{"enabled":false},"history":{"enabled":false},"maxRecords":0,"moderation":{"action":"","bannedWords":null,"checkDuplicates":false,"enabled":false,"fields":null,"maxLinks":0,"verdictField":""},"presets":{"only":false},"softDelete":{"enabled":false},"transaction":{"enabled":false},"uniqueConstraints":null,"versioning":{"enabled":false}}`,
 		},
 		{
 			"unknown type + non empty options",
 			models.Collection{Type: "unknown", Options: types.JsonMap{"test": 123}},
 			map[string]any{"test": 456, "minPasswordLength": 4},
-			"{}",
+			`{"botProtection":{"honeypotFields":null,"ipCooldown":0,"minSubmitTime":0,"submitTimeField":""},"createRateLimit":0,"createRateWindow":0,"database":"","encryption":{"enabled":false},"external":false,"externalId":{"enabled":false},"history":{"enabled":false},"maxRecords":0,"moderation":{"action":"","bannedWords":null,"checkDuplicates":false,"enabled":false,"fields":null,"maxLinks":0,"verdictField":""},"presets":{"only":false},"softDelete":{"enabled":false},"transaction":{"enabled":false},"uniqueConstraints":null,"versioning":{"enabled":false}}`,
 		},
 		{
 			"base type",
 			models.Collection{Type: models.CollectionTypeBase, Options: types.JsonMap{"test": 123}},
 			map[string]any{"test": 456, "minPasswordLength": 4},
-			"{}",
+			`{"botProtection":{"honeypotFields":null,"ipCooldown":0,"minSubmitTime":0,"submitTimeField":""},"createRateLimit":0,"createRateWindow":0,"database":"","encryption":{"enabled":false},"external":false,"externalId":{"enabled":false},"history":{"enabled":false},"maxRecords":0,"moderation":{"action":"","bannedWords":null,"checkDuplicates":false,"enabled":false,"fields":null,"maxLinks":0,"verdictField":""},"presets":{"only":false},"softDelete":{"enabled":false},"transaction":{"enabled":false},"uniqueConstraints":null,"versioning":{"enabled":false}}`,
 		},
 		{
 			"auth type",
 			models.Collection{Type: models.CollectionTypeAuth, Options: types.JsonMap{"test": 123}},
 			map[string]any{"test": 456, "minPasswordLength": 4},
-			`{"allowEmailAuth":false,"allowOAuth2Auth":false,"allowUsernameAuth":false,"exceptEmailDomains":null,"manageRule":null,"minPasswordLength":4,"onlyEmailDomains":null,"requireEmail":false}`,
+			`{"allowAnonymousAuth":false,"allowEmailAuth":false,"allowLoginLinkAuth":false,"allowOAuth2Auth":false,"allowPhoneAuth":false,"allowUsernameAuth":false,"allowWebauthnAuth":false,"botProtection":{"honeypotFields":null,"ipCooldown":0,"minSubmitTime":0,"submitTimeField":""},"createRateLimit":0,"createRateWindow":0,"encryption":{"enabled":false},"exceptEmailDomains":null,"externalId":{"enabled":false},"history":{"enabled":false},"ldap":{"baseDn":"","bindDn":"","bindPassword":"","emailAttribute":"","enabled":false,"fieldsMapping":null,"url":"","userFilter":""},"manageRule":null,"maxRecords":0,"minPasswordLength":4,"moderation":{"action":"","bannedWords":null,"checkDuplicates":false,"enabled":false,"fields":null,"maxLinks":0,"verdictField":""},"onlyEmailDomains":null,"presets":{"only":false},"requireEmail":false,"saml":{"emailAttribute":"","enabled":false,"fieldsMapping":null,"idpCertificate":"","idpEntityId":"","idpSsoUrl":"","redirectUrls":null},"sessions":{"accessTokenDuration":0,"enabled":false,"refreshTokenDuration":0},"softDelete":{"enabled":false},"transaction":{"enabled":false},"uniqueConstraints":null,"versioning":{"enabled":false}}`,
 		},
 	}
 
@@ -306,6 +600,386 @@ func TestCollectionBaseOptionsValidate(t *testing.T) {
 	if err := opt.Validate(); err != nil {
 		t.Fatal(err)
 	}
+
+	invalidOpt := models.CollectionBaseOptions{
+		CollectionQuotaOptions: models.CollectionQuotaOptions{MaxRecords: -1},
+	}
+	if err := invalidOpt.Validate(); err == nil {
+		t.Fatal("Expected the quota options to be validated")
+	}
+}
+
+func TestCollectionQuotaOptionsValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		options        models.CollectionQuotaOptions
+		expectedErrors []string
+	}{
+		{
+			"empty",
+			models.CollectionQuotaOptions{},
+			nil,
+		},
+		{
+			"negative values",
+			models.CollectionQuotaOptions{
+				MaxRecords:       -1,
+				CreateRateLimit:  -1,
+				CreateRateWindow: -1,
+			},
+			[]string{"maxRecords", "createRateLimit", "createRateWindow"},
+		},
+		{
+			"createRateLimit without createRateWindow",
+			models.CollectionQuotaOptions{CreateRateLimit: 10},
+			[]string{"createRateWindow"},
+		},
+		{
+			"all fields with valid data",
+			models.CollectionQuotaOptions{
+				MaxRecords:       100,
+				CreateRateLimit:  10,
+				CreateRateWindow: 86400,
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		result := s.options.Validate()
+
+		// parse errors
+		errs, ok := result.(validation.Errors)
+		if !ok && result != nil {
+			t.Errorf("(%s) Failed to parse errors %v", s.name, result)
+			continue
+		}
+
+		if len(errs) != len(s.expectedErrors) {
+			t.Errorf("(%s) Expected error keys %v, got errors \n%v", s.name, s.expectedErrors, result)
+			continue
+		}
+
+		for key := range errs {
+			if !list.ExistInSlice(key, s.expectedErrors) {
+				t.Errorf("(%s) Unexpected error key %q in \n%v", s.name, key, errs)
+			}
+		}
+	}
+}
+
+func TestCollectionModerationOptionsValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		options        models.CollectionModerationOptions
+		expectedErrors []string
+	}{
+		{
+			"empty",
+			models.CollectionModerationOptions{},
+			nil,
+		},
+		{
+			"enabled without action",
+			models.CollectionModerationOptions{Enabled: true},
+			[]string{"action"},
+		},
+		{
+			"invalid data",
+			models.CollectionModerationOptions{
+				Action:      "invalid",
+				Fields:      []string{"test", ""},
+				MaxLinks:    -1,
+				BannedWords: []string{""},
+			},
+			[]string{"action", "fields", "maxLinks", "bannedWords"},
+		},
+		{
+			"all fields with valid data",
+			models.CollectionModerationOptions{
+				Enabled:         true,
+				Action:          models.ModerationActionReject,
+				Fields:          []string{"title"},
+				MaxLinks:        2,
+				BannedWords:     []string{"spam"},
+				CheckDuplicates: true,
+				VerdictField:    "verdict",
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		result := s.options.Validate()
+
+		// parse errors
+		errs, ok := result.(validation.Errors)
+		if !ok && result != nil {
+			t.Errorf("(%s) Failed to parse errors %v", s.name, result)
+			continue
+		}
+
+		if len(errs) != len(s.expectedErrors) {
+			t.Errorf("(%s) Expected error keys %v, got errors \n%v", s.name, s.expectedErrors, result)
+			continue
+		}
+
+		for key := range errs {
+			if !list.ExistInSlice(key, s.expectedErrors) {
+				t.Errorf("(%s) Unexpected error key %q in \n%v", s.name, key, errs)
+			}
+		}
+	}
+}
+
+func TestCollectionBotProtectionOptionsValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		options        models.CollectionBotProtectionOptions
+		expectedErrors []string
+	}{
+		{
+			"empty",
+			models.CollectionBotProtectionOptions{},
+			nil,
+		},
+		{
+			"invalid data",
+			models.CollectionBotProtectionOptions{
+				HoneypotFields: []string{"test", ""},
+				MinSubmitTime:  -1,
+				IpCooldown:     -1,
+			},
+			[]string{"honeypotFields", "minSubmitTime", "ipCooldown"},
+		},
+		{
+			"minSubmitTime without submitTimeField",
+			models.CollectionBotProtectionOptions{MinSubmitTime: 3},
+			[]string{"submitTimeField"},
+		},
+		{
+			"all fields with valid data",
+			models.CollectionBotProtectionOptions{
+				HoneypotFields:  []string{"website"},
+				MinSubmitTime:   3,
+				SubmitTimeField: "startedAt",
+				IpCooldown:      60,
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		result := s.options.Validate()
+
+		// parse errors
+		errs, ok := result.(validation.Errors)
+		if !ok && result != nil {
+			t.Errorf("(%s) Failed to parse errors %v", s.name, result)
+			continue
+		}
+
+		if len(errs) != len(s.expectedErrors) {
+			t.Errorf("(%s) Expected error keys %v, got errors \n%v", s.name, s.expectedErrors, result)
+			continue
+		}
+
+		for key := range errs {
+			if !list.ExistInSlice(key, s.expectedErrors) {
+				t.Errorf("(%s) Unexpected error key %q in \n%v", s.name, key, errs)
+			}
+		}
+	}
+}
+
+func TestCollectionUniqueConstraintValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		constraint     models.CollectionUniqueConstraint
+		expectedErrors []string
+	}{
+		{
+			"empty",
+			models.CollectionUniqueConstraint{},
+			[]string{"name", "fields"},
+		},
+		{
+			"invalid data",
+			models.CollectionUniqueConstraint{
+				Name:   "test-1",
+				Fields: []string{"a", ""},
+			},
+			[]string{"name", "fields"},
+		},
+		{
+			"valid data",
+			models.CollectionUniqueConstraint{
+				Name:   "test_1",
+				Fields: []string{"a", "b"},
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		result := s.constraint.Validate()
+
+		// parse errors
+		errs, ok := result.(validation.Errors)
+		if !ok && result != nil {
+			t.Errorf("(%s) Failed to parse errors %v", s.name, result)
+			continue
+		}
+
+		if len(errs) != len(s.expectedErrors) {
+			t.Errorf("(%s) Expected error keys %v, got errors \n%v", s.name, s.expectedErrors, result)
+			continue
+		}
+
+		for key := range errs {
+			if !list.ExistInSlice(key, s.expectedErrors) {
+				t.Errorf("(%s) Unexpected error key %q in \n%v", s.name, key, errs)
+			}
+		}
+	}
+}
+
+func TestCollectionSamlOptionsValidate(t *testing.T) {
+	// base64 DER encoded self-signed certificate
+	cert := "MIIDCTCCAfGgAwIBAgIUdwMBc6f1h1HPp+rfnv79GNYR8S4wDQYJKoZIhvcNAQELBQAwEzERMA8GA1UEAwwIdGVzdCBpZHAwIBcNMjYxMDE2MTkxMzU5WhgPMjEyNjA5MjIxOTEzNTlaMBMxETAPBgNVBAMMCHRlc3QgaWRwMIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEA1x6ADg+k7hV6bNDMq9WJ1qfXqvZECYORPETKeAPcFoZRTM5AghT/SGVdE9nCxGIjyMu2CCTIVeolKk9wi8DQZRf8GdHb8InkEfnYuBXtl7KtkphQ46BQswMxAm8FATZFB9UAOWf0rWKhmA5kec4k7mJv6PnjuH1ezf/JX5tNlHGPI1gwIz1gngD+R6AkITKvzfdxxwkbScLp3J/Pce0OkUaf3RFHq/XYBJkmveL5fDzlig9g2V+moS3Dce26RQ108qBUnxwWI5iODY5gtEwTK9KMgSHogybLoy2g+UQE6ZydN1pSU0QmvKoNlWjKtesWPHm5ZnR+0U3teKXZ/ikh0QIDAQABo1MwUTAdBgNVHQ4EFgQUnaLt3Ihvkv1o2c+w17Jf9y2nkCUwHwYDVR0jBBgwFoAUnaLt3Ihvkv1o2c+w17Jf9y2nkCUwDwYDVR0TAQH/BAUwAwEB/zANBgkqhkiG9w0BAQsFAAOCAQEAezGfitXr0PHPdSsAmbNQdOfPp1IOKCbrtmW6Ms47RgK+EPwqICRKcSVn1I4csss6n6H6BIUKYW36al6ylAV9Sqk1pFwNKWiQTe6OKJgppWD32qDJXcogyrZeC+PKvWuEmz0lPueRgckoYYmUpoJKJctjkbq4QUyrZCgOJvnGqCH5NGJV4s3yNQhwDV0/qmcGGzHTLowO821XkyJCc4UEQLQ0jeYMLbkMGAqkKnJg9bYnd3UFFPfyUx4uFnM7RXf4GQq2m7+euEMZ5dCXXWPDhXWYTgRhvawmyt1trifhL/eFT4XBGOKAbM0w6Bl97/D57XW2MMDASDECxTYKKkufMA=="
+
+	scenarios := []struct {
+		name           string
+		options        models.CollectionSamlOptions
+		expectedErrors []string
+	}{
+		{
+			"empty",
+			models.CollectionSamlOptions{},
+			nil,
+		},
+		{
+			"enabled with empty data",
+			models.CollectionSamlOptions{Enabled: true},
+			[]string{"idpEntityId", "idpSsoUrl", "idpCertificate"},
+		},
+		{
+			"invalid data",
+			models.CollectionSamlOptions{
+				IdpSsoUrl:      "invalid",
+				IdpCertificate: "invalid",
+				RedirectUrls:   []string{"https://example.com", "invalid"},
+			},
+			[]string{"idpSsoUrl", "idpCertificate", "redirectUrls"},
+		},
+		{
+			"enabled with valid data",
+			models.CollectionSamlOptions{
+				Enabled:        true,
+				IdpEntityId:    "https://idp.example.com",
+				IdpSsoUrl:      "https://idp.example.com/sso",
+				IdpCertificate: cert,
+				EmailAttribute: "email",
+				FieldsMapping:  map[string]string{"displayName": "name"},
+				RedirectUrls:   []string{"https://example.com/auth"},
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		result := s.options.Validate()
+
+		// parse errors
+		errs, ok := result.(validation.Errors)
+		if !ok && result != nil {
+			t.Errorf("(%s) Failed to parse errors %v", s.name, result)
+			continue
+		}
+
+		if len(errs) != len(s.expectedErrors) {
+			t.Errorf("(%s) Expected error keys %v, got errors \n%v", s.name, s.expectedErrors, result)
+			continue
+		}
+
+		for key := range errs {
+			if !list.ExistInSlice(key, s.expectedErrors) {
+				t.Errorf("(%s) Unexpected error key %q in \n%v", s.name, key, errs)
+			}
+		}
+	}
+}
+
+func TestCollectionLdapOptionsValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		options        models.CollectionLdapOptions
+		expectedErrors []string
+	}{
+		{
+			"empty",
+			models.CollectionLdapOptions{},
+			nil,
+		},
+		{
+			"enabled with empty data",
+			models.CollectionLdapOptions{Enabled: true},
+			[]string{"url", "baseDn", "userFilter"},
+		},
+		{
+			"invalid data",
+			models.CollectionLdapOptions{
+				Url:        "https://example.com",
+				BindDn:     "cn=admin,dc=example,dc=com",
+				UserFilter: "(uid=test)",
+			},
+			[]string{"url", "bindPassword", "userFilter"},
+		},
+		{
+			"invalid filter",
+			models.CollectionLdapOptions{
+				Url:        "ldap://",
+				UserFilter: "(uid={identity}",
+			},
+			[]string{"url", "userFilter"},
+		},
+		{
+			"enabled with valid data",
+			models.CollectionLdapOptions{
+				Enabled:        true,
+				Url:            "ldaps://ldap.example.com",
+				BindDn:         "cn=admin,dc=example,dc=com",
+				BindPassword:   "123456",
+				BaseDn:         "ou=people,dc=example,dc=com",
+				UserFilter:     "(&(objectClass=person)(|(uid={identity})(mail={identity})))",
+				EmailAttribute: "mail",
+				FieldsMapping:  map[string]string{"cn": "name"},
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		result := s.options.Validate()
+
+		// parse errors
+		errs, ok := result.(validation.Errors)
+		if !ok && result != nil {
+			t.Errorf("(%s) Failed to parse errors %v", s.name, result)
+			continue
+		}
+
+		if len(errs) != len(s.expectedErrors) {
+			t.Errorf("(%s) Expected error keys %v, got errors \n%v", s.name, s.expectedErrors, result)
+			continue
+		}
+
+		for key := range errs {
+			if !list.ExistInSlice(key, s.expectedErrors) {
+				t.Errorf("(%s) Unexpected error key %q in \n%v", s.name, key, errs)
+			}
+		}
+	}
 }
 
 func TestCollectionAuthOptionsValidate(t *testing.T) {
@@ -356,6 +1030,13 @@ func TestCollectionAuthOptionsValidate(t *testing.T) {
 			},
 			[]string{},
 		},
+		{
+			"invalid quota options",
+			models.CollectionAuthOptions{
+				CollectionQuotaOptions: models.CollectionQuotaOptions{MaxRecords: -1},
+			},
+			[]string{"maxRecords"},
+		},
 		{
 			"all fields with valid data",
 			models.CollectionAuthOptions{
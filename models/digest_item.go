@@ -0,0 +1,25 @@
+package models
+
+import (
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+var _ Model = (*DigestItem)(nil)
+
+// DigestItem defines a single queued email digest entry
+// of an auth record recipient.
+type DigestItem struct {
+	BaseModel
+
+	// CollectionId and RecordId identify the auth record recipient.
+	CollectionId string `db:"collectionId" json:"collectionId"`
+	RecordId     string `db:"recordId" json:"recordId"`
+
+	Subject string        `db:"subject" json:"subject"`
+	Message string        `db:"message" json:"message"`
+	Data    types.JsonMap `db:"data" json:"data"`
+}
+
+func (m *DigestItem) TableName() string {
+	return "_digestItems"
+}
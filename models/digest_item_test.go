@@ -0,0 +1,14 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+)
+
+func TestDigestItemTableName(t *testing.T) {
+	m := models.DigestItem{}
+	if m.TableName() != "_digestItems" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
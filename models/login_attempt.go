@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+var _ Model = (*LoginAttempt)(nil)
+
+// Supported LoginAttempt kinds.
+const (
+	LoginAttemptKindIdentity = "identity"
+	LoginAttemptKindIp       = "ip"
+)
+
+// LoginAttempt holds the consecutive failed password login
+// attempts counters of a single login identity or client ip.
+type LoginAttempt struct {
+	BaseModel
+
+	Kind        string         `db:"kind" json:"kind"`
+	Key         string         `db:"key" json:"key"`
+	Failures    int            `db:"failures" json:"failures"`
+	Lockouts    int            `db:"lockouts" json:"lockouts"`
+	LockedUntil types.DateTime `db:"lockedUntil" json:"lockedUntil"`
+}
+
+func (m *LoginAttempt) TableName() string {
+	return "_loginAttempts"
+}
+
+// IsLocked checks whether the login attempt is locked at the specified time.
+func (m *LoginAttempt) IsLocked(now time.Time) bool {
+	return !m.LockedUntil.IsZero() && m.LockedUntil.Time().After(now)
+}
+
+// RetryAfter returns the remaining lockout duration
+// at the specified time (0 if not locked).
+func (m *LoginAttempt) RetryAfter(now time.Time) time.Duration {
+	if !m.IsLocked(now) {
+		return 0
+	}
+
+	return m.LockedUntil.Time().Sub(now)
+}
@@ -0,0 +1,47 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestLoginAttemptTableName(t *testing.T) {
+	m := models.LoginAttempt{}
+	if m.TableName() != "_loginAttempts" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
+
+func TestLoginAttemptIsLockedAndRetryAfter(t *testing.T) {
+	now := time.Now()
+
+	scenarios := []struct {
+		lockedUntil   time.Time
+		expectLocked  bool
+		expectedAfter time.Duration
+	}{
+		{time.Time{}, false, 0},
+		{now.Add(-time.Minute), false, 0},
+		{now, false, 0},
+		{now.Add(time.Minute), true, time.Minute},
+	}
+
+	for i, s := range scenarios {
+		m := models.LoginAttempt{}
+		if !s.lockedUntil.IsZero() {
+			m.LockedUntil, _ = types.ParseDateTime(s.lockedUntil)
+		}
+
+		if v := m.IsLocked(now); v != s.expectLocked {
+			t.Errorf("(%d) Expected IsLocked %v, got %v", i, s.expectLocked, v)
+		}
+
+		// the DateTime is stored with millisecond precision
+		if v := m.RetryAfter(now); v.Round(time.Second) != s.expectedAfter {
+			t.Errorf("(%d) Expected RetryAfter %v, got %v", i, s.expectedAfter, v)
+		}
+	}
+}
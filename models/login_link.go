@@ -0,0 +1,16 @@
+package models
+
+var _ Model = (*LoginLink)(nil)
+
+// LoginLink holds a pending one-time passwordless login link
+// sent to the email address of an auth record.
+type LoginLink struct {
+	BaseModel
+
+	CollectionId string `db:"collectionId" json:"collectionId"`
+	RecordId     string `db:"recordId" json:"recordId"`
+}
+
+func (m *LoginLink) TableName() string {
+	return "_loginLinks"
+}
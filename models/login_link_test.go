@@ -0,0 +1,14 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+)
+
+func TestLoginLinkTableName(t *testing.T) {
+	m := models.LoginLink{}
+	if m.TableName() != "_loginLinks" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var _ Model = (*PhoneOtp)(nil)
+
+// PhoneOtpMaxAttempts is the max number of allowed
+// failed verification attempts of a single PhoneOtp.
+const PhoneOtpMaxAttempts = 5
+
+// PhoneOtp holds a pending one-time password sent
+// as SMS to the phone number of an auth record.
+type PhoneOtp struct {
+	BaseModel
+
+	CollectionId string `db:"collectionId" json:"collectionId"`
+	RecordId     string `db:"recordId" json:"recordId"`
+	Phone        string `db:"phone" json:"phone"`
+	CodeHash     string `db:"codeHash" json:"-"`
+	Attempts     int    `db:"attempts" json:"attempts"`
+}
+
+func (m *PhoneOtp) TableName() string {
+	return "_phoneOtps"
+}
+
+// SetCode stores the bcrypt hash of the provided plain one-time password.
+func (m *PhoneOtp) SetCode(code string) error {
+	if code == "" {
+		return errors.New("The provided plain code is empty")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	m.CodeHash = string(hash)
+
+	return nil
+}
+
+// ValidateCode validates a plain one-time password against the model's code hash.
+func (m *PhoneOtp) ValidateCode(code string) bool {
+	err := bcrypt.CompareHashAndPassword([]byte(m.CodeHash), []byte(code))
+
+	// nil means it is a match
+	return err == nil
+}
@@ -0,0 +1,42 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+)
+
+func TestPhoneOtpTableName(t *testing.T) {
+	m := models.PhoneOtp{}
+	if m.TableName() != "_phoneOtps" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
+
+func TestPhoneOtpSetAndValidateCode(t *testing.T) {
+	m := models.PhoneOtp{}
+
+	if err := m.SetCode(""); err == nil {
+		t.Fatal("Expected empty code error")
+	}
+
+	if m.ValidateCode("") {
+		t.Fatal("Didn't expect empty code hash to be valid")
+	}
+
+	if err := m.SetCode("123456"); err != nil {
+		t.Fatal(err)
+	}
+
+	if m.CodeHash == "" || m.CodeHash == "123456" {
+		t.Fatalf("Expected the code to be hashed, got %q", m.CodeHash)
+	}
+
+	if m.ValidateCode("123457") {
+		t.Fatal("Didn't expect the code 123457 to be valid")
+	}
+
+	if !m.ValidateCode("123456") {
+		t.Fatal("Expected the code 123456 to be valid")
+	}
+}
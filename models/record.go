@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/pocketbase/dbx"
@@ -26,12 +27,15 @@ type Record struct {
 
 	collection *Collection
 
-	exportUnknown         bool           // whether to export unknown fields
-	ignoreEmailVisibility bool           // whether to ignore the emailVisibility flag for auth collections
-	data                  map[string]any // any custom data in addition to the base model fields
-	expand                map[string]any // expanded relations
+	exportUnknown         bool                // whether to export unknown fields
+	ignoreEmailVisibility bool                // whether to ignore the emailVisibility flag for auth collections
+	hiddenFields          map[string]struct{} // schema fields excluded from the public export
+	data                  map[string]any      // any custom data in addition to the base model fields
+	expand                map[string]any      // expanded relations
 	loaded                bool
 	originalData          map[string]any // the original (aka. first loaded) model data
+	historyAuthType       string         // the record change history author type
+	historyAuthId         string         // the record change history author id
 }
 
 // NewRecord initializes a new empty Record model.
@@ -76,6 +80,31 @@ func NewRecordFromNullStringMap(collection *Collection, data dbx.NullStringMap)
 		}
 	}
 
+	// load the soft delete column (if exists)
+	if _, ok := data[schema.FieldNameDeleted]; ok && collection.Schema.GetFieldByName(schema.FieldNameDeleted) == nil {
+		resultMap[schema.FieldNameDeleted], _ = types.ParseDateTime(nullStringMapValue(data, schema.FieldNameDeleted))
+	}
+
+	// load the version column (if exists)
+	if _, ok := data[schema.FieldNameVersion]; ok && collection.Schema.GetFieldByName(schema.FieldNameVersion) == nil {
+		resultMap[schema.FieldNameVersion] = cast.ToInt(nullStringMapValue(data, schema.FieldNameVersion))
+	}
+
+	// load the external id column (if exists)
+	if _, ok := data[schema.FieldNameExternalId]; ok && collection.Schema.GetFieldByName(schema.FieldNameExternalId) == nil {
+		resultMap[schema.FieldNameExternalId] = cast.ToString(nullStringMapValue(data, schema.FieldNameExternalId))
+	}
+
+	// load the phone column (if exists)
+	if _, ok := data[schema.FieldNamePhone]; ok && collection.IsAuth() && collection.Schema.GetFieldByName(schema.FieldNamePhone) == nil {
+		resultMap[schema.FieldNamePhone] = cast.ToString(nullStringMapValue(data, schema.FieldNamePhone))
+	}
+
+	// load the tree path column (if exists)
+	if _, ok := data[schema.FieldNamePath]; ok && collection.Schema.GetFieldByName(schema.FieldNamePath) == nil {
+		resultMap[schema.FieldNamePath] = cast.ToString(nullStringMapValue(data, schema.FieldNamePath))
+	}
+
 	record := NewRecord(collection)
 
 	record.Load(resultMap)
@@ -227,6 +256,32 @@ func (m *Record) IgnoreEmailVisibility(state bool) {
 	m.ignoreEmailVisibility = state
 }
 
+// SetHiddenFields replaces the list of schema fields that are excluded
+// from the public export/serialization of the record
+// (eg. because of the field read rules).
+//
+// Call it without arguments to export again all schema fields.
+func (m *Record) SetHiddenFields(names ...string) {
+	// always create a new map because the record could be a shallow copy
+	hidden := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		hidden[name] = struct{}{}
+	}
+
+	m.hiddenFields = hidden
+}
+
+// HiddenFields returns the names of the schema fields that are
+// excluded from the public export/serialization of the record.
+func (m *Record) HiddenFields() []string {
+	result := make([]string, 0, len(m.hiddenFields))
+	for name := range m.hiddenFields {
+		result = append(result, name)
+	}
+
+	return result
+}
+
 // WithUnkownData toggles the export/serialization of unknown data fields
 // (false by default).
 func (m *Record) WithUnkownData(state bool) {
@@ -321,6 +376,18 @@ func (m *Record) GetDateTime(key string) types.DateTime {
 	return d
 }
 
+// GetGeoPoint returns the data value for "key" as a GeoPoint instance.
+func (m *Record) GetGeoPoint(key string) types.GeoPoint {
+	p, _ := types.ParseGeoPoint(m.Get(key))
+	return p
+}
+
+// GetDocument returns the data value for "key" as a Document instance.
+func (m *Record) GetDocument(key string) types.Document {
+	d, _ := types.ParseDocument(m.Get(key))
+	return d
+}
+
 // GetStringSlice returns the data value for "key" as a slice of unique strings.
 func (m *Record) GetStringSlice(key string) []string {
 	return list.ToUniqueStringSlice(m.Get(key))
@@ -374,6 +441,12 @@ func (m *Record) ColumnValueMap() map[string]any {
 
 	// export schema field values
 	for _, field := range m.collection.Schema.Fields() {
+		// computed fields don't have a db column and
+		// the sequence fields values are assigned by the db
+		if field.IsReadOnly() {
+			continue
+		}
+
 		result[field.Name] = m.getNormalizeDataValueForDB(field.Name)
 	}
 
@@ -382,6 +455,20 @@ func (m *Record) ColumnValueMap() map[string]any {
 		for _, name := range schema.AuthFieldNames() {
 			result[name] = m.getNormalizeDataValueForDB(name)
 		}
+
+		if m.collection.AuthOptions().AllowPhoneAuth {
+			result[schema.FieldNamePhone] = m.Phone()
+		}
+	}
+
+	// export the external id
+	if m.collection.ExternalIdOptions().Enabled {
+		result[schema.FieldNameExternalId] = m.ExternalId()
+	}
+
+	// export the tree path
+	if m.collection.TreeField() != nil {
+		result[schema.FieldNamePath] = m.Path()
 	}
 
 	// export base model fields
@@ -395,6 +482,8 @@ func (m *Record) ColumnValueMap() map[string]any {
 // PublicExport exports only the record fields that are safe to be public.
 //
 // Fields marked as hidden will be exported only if `m.IgnoreEmailVisibility(true)` is set.
+//
+// Schema fields registered with `m.SetHiddenFields()` are never exported.
 func (m *Record) PublicExport() map[string]any {
 	result := map[string]any{}
 
@@ -407,6 +496,9 @@ func (m *Record) PublicExport() map[string]any {
 
 	// export schema field values
 	for _, field := range m.collection.Schema.Fields() {
+		if _, ok := m.hiddenFields[field.Name]; ok {
+			continue
+		}
 		result[field.Name] = m.Get(field.Name)
 	}
 
@@ -418,6 +510,10 @@ func (m *Record) PublicExport() map[string]any {
 		if m.ignoreEmailVisibility || m.EmailVisibility() {
 			result[schema.FieldNameEmail] = m.Email()
 		}
+		// the phone number is always private
+		if m.ignoreEmailVisibility && m.collection.AuthOptions().AllowPhoneAuth {
+			result[schema.FieldNamePhone] = m.Phone()
+		}
 	}
 
 	// export base model fields
@@ -425,6 +521,26 @@ func (m *Record) PublicExport() map[string]any {
 	result[schema.FieldNameCreated] = m.GetCreated()
 	result[schema.FieldNameUpdated] = m.GetUpdated()
 
+	// export the soft delete date
+	if m.collection.SoftDeleteOptions().Enabled {
+		result[schema.FieldNameDeleted] = m.Deleted()
+	}
+
+	// export the record version
+	if m.collection.VersioningOptions().Enabled {
+		result[schema.FieldNameVersion] = m.Version()
+	}
+
+	// export the external id
+	if m.collection.ExternalIdOptions().Enabled {
+		result[schema.FieldNameExternalId] = m.ExternalId()
+	}
+
+	// export the tree path
+	if m.collection.TreeField() != nil {
+		result[schema.FieldNamePath] = m.Path()
+	}
+
 	// add helper collection reference fields
 	result[schema.FieldNameCollectionId] = m.collection.Id
 	result[schema.FieldNameCollectionName] = m.collection.Name
@@ -523,6 +639,26 @@ func (m *Record) extractUnknownData(data map[string]any) map[string]any {
 		for _, name := range schema.AuthFieldNames() {
 			knownFields[name] = struct{}{}
 		}
+
+		if m.collection.AuthOptions().AllowPhoneAuth {
+			knownFields[schema.FieldNamePhone] = struct{}{}
+		}
+	}
+
+	if m.collection.SoftDeleteOptions().Enabled {
+		knownFields[schema.FieldNameDeleted] = struct{}{}
+	}
+
+	if m.collection.VersioningOptions().Enabled {
+		knownFields[schema.FieldNameVersion] = struct{}{}
+	}
+
+	if m.collection.ExternalIdOptions().Enabled {
+		knownFields[schema.FieldNameExternalId] = struct{}{}
+	}
+
+	if m.collection.TreeField() != nil {
+		knownFields[schema.FieldNamePath] = struct{}{}
 	}
 
 	result := map[string]any{}
@@ -536,6 +672,92 @@ func (m *Record) extractUnknownData(data map[string]any) map[string]any {
 	return result
 }
 
+// -------------------------------------------------------------------
+// Soft delete helpers
+// -------------------------------------------------------------------
+
+// Deleted returns the record soft delete date
+// (zero if the record is not soft deleted).
+func (m *Record) Deleted() types.DateTime {
+	return m.GetDateTime(schema.FieldNameDeleted)
+}
+
+// IsDeleted checks whether the record is marked as soft deleted.
+func (m *Record) IsDeleted() bool {
+	return !m.Deleted().IsZero()
+}
+
+// -------------------------------------------------------------------
+// Versioning helpers
+// -------------------------------------------------------------------
+
+// Version returns the record version
+// (always 0 if the collection records versioning is not enabled).
+func (m *Record) Version() int {
+	return m.GetInt(schema.FieldNameVersion)
+}
+
+// SetVersion sets the record version that is expected to be
+// stored in the db on the next record update.
+//
+// Note that the version is auto incremented on successful save.
+func (m *Record) SetVersion(version int) {
+	m.Set(schema.FieldNameVersion, version)
+}
+
+// -------------------------------------------------------------------
+// External id helpers
+// -------------------------------------------------------------------
+
+// ExternalId returns the record external system primary key
+// (always empty if the collection external ids are not enabled).
+func (m *Record) ExternalId() string {
+	return m.GetString(schema.FieldNameExternalId)
+}
+
+// SetExternalId sets the record external system primary key.
+func (m *Record) SetExternalId(externalId string) {
+	m.Set(schema.FieldNameExternalId, externalId)
+}
+
+// Path returns the record materialized tree path in the format
+// "rootId/.../parentId/recordId" (always empty if the collection
+// doesn't have a tree relation field).
+//
+// The path is maintained automatically on record save.
+func (m *Record) Path() string {
+	return m.GetString(schema.FieldNamePath)
+}
+
+// PathIds returns the record tree path ids (from the root to the record itself).
+func (m *Record) PathIds() []string {
+	path := m.Path()
+	if path == "" {
+		return []string{}
+	}
+
+	return strings.Split(path, "/")
+}
+
+// -------------------------------------------------------------------
+// History helpers
+// -------------------------------------------------------------------
+
+// SetHistoryAuth sets the author (admin or auth record) of the next
+// record change history entries (see [RecordHistory]).
+//
+// authType is expected to be one of the RecordHistoryAuth* constants.
+func (m *Record) SetHistoryAuth(authType string, authId string) {
+	m.historyAuthType = authType
+	m.historyAuthId = authId
+}
+
+// HistoryAuth returns the record change history author type and id
+// (both are empty if not set).
+func (m *Record) HistoryAuth() (string, string) {
+	return m.historyAuthType, m.historyAuthId
+}
+
 // -------------------------------------------------------------------
 // Auth helpers
 // -------------------------------------------------------------------
@@ -582,6 +804,27 @@ func (m *Record) SetEmail(email string) error {
 	return nil
 }
 
+// Phone returns the "phone" auth record data value
+// (always empty if the collection phone auth is not enabled).
+func (m *Record) Phone() string {
+	return m.GetString(schema.FieldNamePhone)
+}
+
+// SetPhone sets the "phone" auth record data value.
+//
+// This method doesn't check whether the provided value is a valid phone number.
+//
+// Returns an error if the record is not from an auth collection.
+func (m *Record) SetPhone(phone string) error {
+	if !m.collection.IsAuth() {
+		return notAuthRecordErr
+	}
+
+	m.Set(schema.FieldNamePhone, phone)
+
+	return nil
+}
+
 // Verified returns the "emailVisibility" auth record data value.
 func (m *Record) EmailVisibility() bool {
 	return m.GetBool(schema.FieldNameEmailVisibility)
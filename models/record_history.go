@@ -0,0 +1,51 @@
+package models
+
+import (
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+var _ Model = (*RecordHistory)(nil)
+
+// Supported record history actions.
+const (
+	RecordHistoryActionCreate = "create"
+	RecordHistoryActionUpdate = "update"
+	RecordHistoryActionDelete = "delete"
+)
+
+// Supported record history author types.
+const (
+	RecordHistoryAuthAdmin  = "admin"
+	RecordHistoryAuthRecord = "authRecord"
+)
+
+// RecordHistory defines a single record change entry from the
+// history log of a collection with enabled history.
+type RecordHistory struct {
+	BaseModel
+
+	CollectionId string `db:"collectionId" json:"collectionId"`
+	RecordId     string `db:"recordId" json:"recordId"`
+
+	// Version is the record history entry sequence number
+	// (starts from 1 for each record).
+	Version int    `db:"version" json:"version"`
+	Action  string `db:"action" json:"action"`
+
+	// AuthType and AuthId identify the author of the change
+	// (both are empty for changes made outside of the api, eg. in hooks).
+	AuthType string `db:"authType" json:"authType"`
+	AuthId   string `db:"authId" json:"authId"`
+
+	// Changes holds the changed record schema fields in the format
+	// {"fieldName": {"old": oldValue, "new": newValue}}.
+	Changes types.JsonMap `db:"changes" json:"changes"`
+
+	// Data holds a snapshot of the record schema fields after the change
+	// (or before the change for the "delete" action).
+	Data types.JsonMap `db:"data" json:"data"`
+}
+
+func (m *RecordHistory) TableName() string {
+	return "_recordHistory"
+}
@@ -0,0 +1,14 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+)
+
+func TestRecordHistoryTableName(t *testing.T) {
+	m := models.RecordHistory{}
+	if m.TableName() != "_recordHistory" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+var _ Model = (*RecordPreferences)(nil)
+
+// RecordPreferences defines the explicitly stored preferences
+// of a single auth record.
+type RecordPreferences struct {
+	BaseModel
+
+	// CollectionId and RecordId identify the preferences auth record.
+	CollectionId string `db:"collectionId" json:"collectionId"`
+	RecordId     string `db:"recordId" json:"recordId"`
+
+	Data types.JsonMap `db:"data" json:"data"`
+}
+
+func (m *RecordPreferences) TableName() string {
+	return "_recordPreferences"
+}
@@ -0,0 +1,14 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+)
+
+func TestRecordPreferencesTableName(t *testing.T) {
+	m := models.RecordPreferences{}
+	if m.TableName() != "_recordPreferences" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
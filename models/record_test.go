@@ -1136,6 +1136,13 @@ func TestRecordColumnValueMap(t *testing.T) {
 					MaxSelect: types.Pointer(2),
 				},
 			},
+			&schema.SchemaField{
+				Name: "field5",
+				Type: schema.FieldTypeComputed,
+				Options: &schema.ComputedOptions{
+					Expression: "field1 * 2",
+				},
+			},
 		),
 	}
 
@@ -1162,6 +1169,7 @@ func TestRecordColumnValueMap(t *testing.T) {
 		"field2":          "test.png",
 		"field3":          []string{"test1", "test2"},
 		"field4":          []string{"test11", "test12", "test11"}, // strip duplicate,
+		"field5":          10,                                     // computed (not exported)
 		"unknown":         "test_unknown",
 		"passwordHash":    "test_passwordHash",
 		"username":        "test_username",
@@ -1337,6 +1345,55 @@ func TestRecordPublicExportAndMarshalJSON(t *testing.T) {
 	}
 }
 
+func TestRecordSetHiddenFields(t *testing.T) {
+	collection := &models.Collection{
+		Name: "c_name",
+		Schema: schema.NewSchema(
+			&schema.SchemaField{Name: "field1", Type: schema.FieldTypeText},
+			&schema.SchemaField{Name: "field2", Type: schema.FieldTypeText},
+		),
+	}
+	collection.Id = "c_id"
+
+	m := models.NewRecord(collection)
+	m.Id = "test_id"
+	m.Set("field1", "a")
+	m.Set("field2", "b")
+
+	m.SetHiddenFields("field1", "missing")
+
+	if total := len(m.HiddenFields()); total != 2 {
+		t.Fatalf("Expected 2 hidden fields, got %d", total)
+	}
+
+	// shallow copies should not share the hidden fields
+	clone := *m
+	clone.SetHiddenFields()
+
+	raw, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"collectionId":"c_id","collectionName":"c_name","created":"","field2":"b","id":"test_id","updated":""}`
+	if string(raw) != expected {
+		t.Fatalf("Expected json \n%v \ngot \n%v", expected, string(raw))
+	}
+
+	cloneRaw, err := json.Marshal(clone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = `{"collectionId":"c_id","collectionName":"c_name","created":"","field1":"a","field2":"b","id":"test_id","updated":""}`
+	if string(cloneRaw) != expected {
+		t.Fatalf("Expected json \n%v \ngot \n%v", expected, string(cloneRaw))
+	}
+
+	// the hidden fields should be still accessible with Get
+	if v := m.GetString("field1"); v != "a" {
+		t.Fatalf("Expected field1 value %q, got %q", "a", v)
+	}
+}
+
 func TestRecordUnmarshalJSON(t *testing.T) {
 	collection := &models.Collection{
 		Schema: schema.NewSchema(
@@ -1429,6 +1486,20 @@ func TestRecordUnmarshalJSON(t *testing.T) {
 // Auth helpers:
 // -------------------------------------------------------------------
 
+func TestRecordHistoryAuth(t *testing.T) {
+	m := models.NewRecord(&models.Collection{})
+
+	if authType, authId := m.HistoryAuth(); authType != "" || authId != "" {
+		t.Fatalf("Expected empty history auth, got %q %q", authType, authId)
+	}
+
+	m.SetHistoryAuth(models.RecordHistoryAuthAdmin, "test")
+
+	if authType, authId := m.HistoryAuth(); authType != models.RecordHistoryAuthAdmin || authId != "test" {
+		t.Fatalf("Expected admin test history auth, got %q %q", authType, authId)
+	}
+}
+
 func TestRecordUsername(t *testing.T) {
 	scenarios := []struct {
 		collectionType string
@@ -1511,6 +1582,77 @@ func TestRecordEmail(t *testing.T) {
 	}
 }
 
+func TestRecordPhone(t *testing.T) {
+	scenarios := []struct {
+		collectionType string
+		expectError    bool
+	}{
+		{models.CollectionTypeBase, true},
+		{models.CollectionTypeAuth, false},
+	}
+
+	testValue := "test 1232 !@#%" // formatting isn't checked
+
+	for i, s := range scenarios {
+		collection := &models.Collection{Type: s.collectionType}
+		m := models.NewRecord(collection)
+
+		if s.expectError {
+			if err := m.SetPhone(testValue); err == nil {
+				t.Errorf("(%d) Expected error, got nil", i)
+			}
+			if v := m.Phone(); v != "" {
+				t.Fatalf("(%d) Expected empty string, got %q", i, v)
+			}
+			// verify that nothing is stored in the record data slice
+			if v := m.Get(schema.FieldNamePhone); v != nil {
+				t.Fatalf("(%d) Didn't expect data field %q: %v", i, schema.FieldNamePhone, v)
+			}
+		} else {
+			if err := m.SetPhone(testValue); err != nil {
+				t.Fatalf("(%d) Expected nil, got error %v", i, err)
+			}
+			if v := m.Phone(); v != testValue {
+				t.Fatalf("(%d) Expected %q, got %q", i, testValue, v)
+			}
+			// verify that the field is stored in the record data slice
+			if v := m.Get(schema.FieldNamePhone); v != testValue {
+				t.Fatalf("(%d) Expected data field value %q, got %q", i, testValue, v)
+			}
+		}
+	}
+}
+
+func TestRecordPhoneExport(t *testing.T) {
+	collection := &models.Collection{Type: models.CollectionTypeAuth}
+
+	m := models.NewRecord(collection)
+	m.SetPhone("+359888123456")
+
+	// phone auth disabled
+	if _, ok := m.ColumnValueMap()[schema.FieldNamePhone]; ok {
+		t.Fatal("Didn't expect the phone column to be exported")
+	}
+	m.IgnoreEmailVisibility(true)
+	if _, ok := m.PublicExport()[schema.FieldNamePhone]; ok {
+		t.Fatal("Didn't expect the phone to be public exported")
+	}
+
+	// phone auth enabled
+	collection.SetOptions(models.CollectionAuthOptions{AllowPhoneAuth: true})
+	if v := m.ColumnValueMap()[schema.FieldNamePhone]; v != "+359888123456" {
+		t.Fatalf("Expected the phone column to be exported, got %v", v)
+	}
+	if v := m.PublicExport()[schema.FieldNamePhone]; v != "+359888123456" {
+		t.Fatalf("Expected the phone to be public exported, got %v", v)
+	}
+	m.IgnoreEmailVisibility(false)
+	m.SetEmailVisibility(true)
+	if _, ok := m.PublicExport()[schema.FieldNamePhone]; ok {
+		t.Fatal("Didn't expect the phone to be public exported with only email visibility")
+	}
+}
+
 func TestRecordEmailVisibility(t *testing.T) {
 	scenarios := []struct {
 		collectionType string
@@ -0,0 +1,45 @@
+package models
+
+import "github.com/pocketbase/pocketbase/tools/types"
+
+// RecordsEncryptionKdfHkdf is the [RecordsEncryptionKey.Kdf]
+// identifier of the HKDF-SHA256 derived keys.
+const RecordsEncryptionKdfHkdf = "hkdf-sha256"
+
+// RecordsEncryptionKey defines the records encryption key metadata of
+// a single collection (stored in the [ParamRecordsEncryptionKeys] param).
+//
+// The key itself is never stored and it is always derived from
+// the app master key, the collection id and the key version.
+type RecordsEncryptionKey struct {
+	// Version is the current collection key version
+	// (incremented on each key rotation).
+	Version int `json:"version"`
+
+	// Fingerprint is a hash of the current collection key used to
+	// detect a changed or missing app master key.
+	Fingerprint string `json:"fingerprint"`
+
+	// Rotated is the date of the last key creation or rotation.
+	Rotated types.DateTime `json:"rotated"`
+
+	// Kdf is the key derivation function of the current key version.
+	//
+	// It is empty for the keys created with the legacy truncated
+	// hex HMAC-SHA256 derivation (they are upgraded on rotation).
+	Kdf string `json:"kdf,omitempty"`
+
+	// LegacyVersion is the last key version that was derived with
+	// the legacy function before switching to Kdf (0 if none).
+	LegacyVersion int `json:"legacyVersion,omitempty"`
+}
+
+// IsLegacyVersion checks whether the specified key version
+// was derived with the legacy truncated hex HMAC-SHA256 function.
+func (k *RecordsEncryptionKey) IsLegacyVersion(version int) bool {
+	if k.Kdf == "" {
+		return true
+	}
+
+	return version <= k.LegacyVersion
+}
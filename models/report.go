@@ -0,0 +1,108 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+var (
+	_ Model = (*Report)(nil)
+	_ Model = (*ReportRun)(nil)
+)
+
+// Supported report output formats.
+const (
+	ReportFormatCsv  = "csv"
+	ReportFormatJson = "json"
+)
+
+// ReportWebhookSecretMask is the placeholder of the non-empty
+// report webhook secret in the report json serialization.
+const ReportWebhookSecretMask = "******"
+
+// Supported report run statuses.
+const (
+	ReportRunStatusSuccess = "success"
+	ReportRunStatusFailure = "failure"
+)
+
+// Report defines an admin managed collection records query
+// that is periodically rendered and delivered via email and/or webhook
+// (eg. a weekly export of the new orders to the sales team).
+type Report struct {
+	BaseModel
+
+	Name         string `db:"name" json:"name"`
+	CollectionId string `db:"collectionId" json:"collectionId"`
+
+	// Filter is an optional records filter expression.
+	Filter string `db:"filter" json:"filter"`
+
+	// GroupBy and Aggregate are optional comma separated lists with the
+	// group and aggregate fields (eg. "day(created)" and "count(*),sum(total)").
+	//
+	// If both are empty, the report contains the plain filtered records.
+	GroupBy   string `db:"groupBy" json:"groupBy"`
+	Aggregate string `db:"aggregate" json:"aggregate"`
+
+	// Sort is an optional sort expression (eg. "-created,title").
+	Sort string `db:"sort" json:"sort"`
+
+	Format string `db:"format" json:"format"`
+
+	// Schedule is a cron expression (eg. "0 9 * * 1") of the report runs.
+	Schedule string `db:"schedule" json:"schedule"`
+
+	// Emails is a list with the report email recipients.
+	Emails types.JsonArray `db:"emails" json:"emails"`
+
+	// WebhookUrl is an optional url that receives the rendered report
+	// as POST request body.
+	WebhookUrl string `db:"webhookUrl" json:"webhookUrl"`
+
+	// WebhookSecret is an optional HMAC secret used to sign the webhook payload.
+	//
+	// It is masked in the report json (see [Report.MarshalJSON]).
+	WebhookSecret string `db:"webhookSecret" json:"webhookSecret"`
+
+	Enabled   bool           `db:"enabled" json:"enabled"`
+	LastRunAt types.DateTime `db:"lastRunAt" json:"lastRunAt"`
+}
+
+func (m *Report) TableName() string {
+	return "_reports"
+}
+
+// MarshalJSON implements the [json.Marshaler] interface.
+//
+// The non-empty webhook secret is always serialized as [ReportWebhookSecretMask].
+func (m Report) MarshalJSON() ([]byte, error) {
+	type alias Report // prevent recursion
+
+	if m.WebhookSecret != "" {
+		m.WebhookSecret = ReportWebhookSecretMask
+	}
+
+	return json.Marshal(alias(m))
+}
+
+// -------------------------------------------------------------------
+
+// ReportRun defines a single report run history entry.
+type ReportRun struct {
+	BaseModel
+
+	ReportId string `db:"reportId" json:"reportId"`
+	Status   string `db:"status" json:"status"`
+
+	// Rows is the number of the rendered report rows.
+	Rows int `db:"rows" json:"rows"`
+
+	// Error is the run failure message (if any).
+	Error string `db:"error" json:"error"`
+}
+
+func (m *ReportRun) TableName() string {
+	return "_reportRuns"
+}
@@ -0,0 +1,50 @@
+package models_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+)
+
+func TestReportTableName(t *testing.T) {
+	m := models.Report{}
+	if m.TableName() != "_reports" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
+
+func TestReportRunTableName(t *testing.T) {
+	m := models.ReportRun{}
+	if m.TableName() != "_reportRuns" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
+
+func TestReportMarshalJSON(t *testing.T) {
+	scenarios := []struct {
+		secret   string
+		expected string
+	}{
+		{"", `"webhookSecret":""`},
+		{"test", `"webhookSecret":"******"`},
+	}
+
+	for i, s := range scenarios {
+		m := models.Report{WebhookSecret: s.secret}
+
+		raw, err := json.Marshal(m)
+		if err != nil {
+			t.Fatalf("(%d) %v", i, err)
+		}
+
+		if !strings.Contains(string(raw), s.expected) {
+			t.Errorf("(%d) Expected %s in\n%s", i, s.expected, raw)
+		}
+
+		if m.WebhookSecret != s.secret {
+			t.Errorf("(%d) Expected the model secret to remain %q, got %q", i, s.secret, m.WebhookSecret)
+		}
+	}
+}
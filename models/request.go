@@ -9,6 +9,7 @@ const (
 	RequestAuthGuest  = "guest"
 	RequestAuthAdmin  = "admin"
 	RequestAuthRecord = "auth_record"
+	RequestAuthApiKey = "api_key"
 )
 
 type Request struct {
@@ -18,6 +19,8 @@ type Request struct {
 	Method    string        `db:"method" json:"method"`
 	Status    int           `db:"status" json:"status"`
 	Auth      string        `db:"auth" json:"auth"`
+	AuthId    string        `db:"authId" json:"authId"`
+	RequestId string        `db:"requestId" json:"requestId"`
 	UserIp    string        `db:"userIp" json:"userIp"`
 	RemoteIp  string        `db:"remoteIp" json:"remoteIp"`
 	Referer   string        `db:"referer" json:"referer"`
@@ -3,9 +3,11 @@ package models
 // RequestData defines a HTTP request data struct, usually used
 // as part of the `@request.*` filter resolver.
 type RequestData struct {
+	RequestId  string         `json:"requestId"`
 	Method     string         `json:"method"`
 	Query      map[string]any `json:"query"`
 	Data       map[string]any `json:"data"`
 	AuthRecord *Record        `json:"authRecord"`
 	Admin      *Admin         `json:"admin"`
+	ApiKey     *ApiKey        `json:"apiKey"`
 }
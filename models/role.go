@@ -0,0 +1,33 @@
+package models
+
+var (
+	_ Model = (*Role)(nil)
+	_ Model = (*AuthRole)(nil)
+)
+
+// Role defines a named app level role that could be assigned
+// to auth records and checked in the API rules
+// with `@request.auth.hasRole("name")`.
+type Role struct {
+	BaseModel
+
+	Name        string `db:"name" json:"name"`
+	Description string `db:"description" json:"description"`
+}
+
+func (m *Role) TableName() string {
+	return "_roles"
+}
+
+// AuthRole defines a single role assignment of an auth record.
+type AuthRole struct {
+	BaseModel
+
+	CollectionId string `db:"collectionId" json:"collectionId"`
+	RecordId     string `db:"recordId" json:"recordId"`
+	RoleId       string `db:"roleId" json:"roleId"`
+}
+
+func (m *AuthRole) TableName() string {
+	return "_authRoles"
+}
@@ -0,0 +1,21 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+)
+
+func TestRoleTableName(t *testing.T) {
+	m := models.Role{}
+	if m.TableName() != "_roles" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
+
+func TestAuthRoleTableName(t *testing.T) {
+	m := models.AuthRole{}
+	if m.TableName() != "_authRoles" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+var _ Model = (*SavedFilter)(nil)
+
+var savedFilterParamNameRegex = regexp.MustCompile(`^\w+$`)
+
+// Supported saved filter param types.
+const (
+	SavedFilterParamTypeText    = "text"
+	SavedFilterParamTypeNumber  = "number"
+	SavedFilterParamTypeBool    = "bool"
+	SavedFilterParamTypeDate    = "date"
+	SavedFilterParamTypeDaysAgo = "daysAgo"
+)
+
+// SavedFilter defines a named and parameterized collection records
+// filter that clients could use as a list "preset" (eg. "?preset=recentActive&params[days]=7").
+//
+// The preset is combined with the client filter (if any) unless the
+// collection allows only presets (see [CollectionPresetsOptions]).
+type SavedFilter struct {
+	BaseModel
+
+	CollectionId string            `db:"collectionId" json:"collectionId"`
+	Name         string            `db:"name" json:"name"`
+	Filter       string            `db:"filter" json:"filter"`
+	Params       SavedFilterParams `db:"params" json:"params"`
+}
+
+func (m *SavedFilter) TableName() string {
+	return "_savedFilters"
+}
+
+// -------------------------------------------------------------------
+
+// SavedFilterParam defines a single saved filter "{:name}" placeholder param.
+type SavedFilterParam struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Validate makes SavedFilterParam validatable by implementing [validation.Validatable] interface.
+func (p SavedFilterParam) Validate() error {
+	return validation.ValidateStruct(&p,
+		validation.Field(
+			&p.Name,
+			validation.Required,
+			validation.Length(1, 100),
+			validation.Match(savedFilterParamNameRegex),
+		),
+		validation.Field(
+			&p.Type,
+			validation.Required,
+			validation.In(
+				SavedFilterParamTypeText,
+				SavedFilterParamTypeNumber,
+				SavedFilterParamTypeBool,
+				SavedFilterParamTypeDate,
+				SavedFilterParamTypeDaysAgo,
+			),
+		),
+	)
+}
+
+// ParseValue converts the provided raw (usually query) param value
+// based on the param type.
+//
+// The "daysAgo" param value is expected to be a non-negative integer and
+// it is converted to the datetime string of N days before the current time.
+func (p SavedFilterParam) ParseValue(raw string) (any, error) {
+	switch p.Type {
+	case SavedFilterParamTypeText:
+		return raw, nil
+	case SavedFilterParamTypeNumber:
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("The %q param must be a valid number.", p.Name)
+		}
+		return v, nil
+	case SavedFilterParamTypeBool:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("The %q param must be a valid boolean.", p.Name)
+		}
+		return v, nil
+	case SavedFilterParamTypeDate:
+		v, err := types.ParseDateTime(raw)
+		if err != nil || v.IsZero() {
+			return nil, fmt.Errorf("The %q param must be a valid date.", p.Name)
+		}
+		return v.String(), nil
+	case SavedFilterParamTypeDaysAgo:
+		days, err := strconv.Atoi(raw)
+		if err != nil || days < 0 {
+			return nil, fmt.Errorf("The %q param must be a non-negative integer.", p.Name)
+		}
+		v, _ := types.ParseDateTime(time.Now().UTC().AddDate(0, 0, -days))
+		return v.String(), nil
+	}
+
+	return nil, fmt.Errorf("Unsupported param type %q.", p.Type)
+}
+
+// SavedFilterParams defines the list of the saved filter params.
+type SavedFilterParams []SavedFilterParam
+
+// Value implements the [driver.Valuer] interface.
+func (p SavedFilterParams) Value() (driver.Value, error) {
+	if p == nil {
+		// initialize an empty slice to ensure that `[]` is returned
+		p = SavedFilterParams{}
+	}
+
+	data, err := json.Marshal([]SavedFilterParam(p))
+
+	return string(data), err
+}
+
+// Scan implements [sql.Scanner] interface to scan the provided value
+// into the current SavedFilterParams instance.
+func (p *SavedFilterParams) Scan(value any) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		// no cast needed
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("Failed to unmarshal SavedFilterParams value.")
+	}
+
+	if len(data) == 0 {
+		data = []byte("[]")
+	}
+
+	return json.Unmarshal(data, (*[]SavedFilterParam)(p))
+}
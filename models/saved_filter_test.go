@@ -0,0 +1,116 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestSavedFilterTableName(t *testing.T) {
+	m := models.SavedFilter{}
+	if m.TableName() != "_savedFilters" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
+
+func TestSavedFilterParamValidate(t *testing.T) {
+	scenarios := []struct {
+		param       models.SavedFilterParam
+		expectError bool
+	}{
+		{models.SavedFilterParam{}, true},
+		{models.SavedFilterParam{Name: "test"}, true},
+		{models.SavedFilterParam{Name: "a-b", Type: models.SavedFilterParamTypeText}, true},
+		{models.SavedFilterParam{Name: "test", Type: "unknown"}, true},
+		{models.SavedFilterParam{Name: "test", Type: models.SavedFilterParamTypeText}, false},
+		{models.SavedFilterParam{Name: "test_2", Type: models.SavedFilterParamTypeDaysAgo}, false},
+	}
+
+	for i, s := range scenarios {
+		err := s.param.Validate()
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+		}
+	}
+}
+
+func TestSavedFilterParamParseValue(t *testing.T) {
+	scenarios := []struct {
+		paramType   string
+		raw         string
+		expectError bool
+		expected    any
+	}{
+		{"unknown", "test", true, nil},
+		{models.SavedFilterParamTypeText, "", false, ""},
+		{models.SavedFilterParamTypeText, "' OR 1=1", false, "' OR 1=1"},
+		{models.SavedFilterParamTypeNumber, "", true, nil},
+		{models.SavedFilterParamTypeNumber, "abc", true, nil},
+		{models.SavedFilterParamTypeNumber, "NaN", true, nil},
+		{models.SavedFilterParamTypeNumber, "-1.5", false, -1.5},
+		{models.SavedFilterParamTypeBool, "abc", true, nil},
+		{models.SavedFilterParamTypeBool, "true", false, true},
+		{models.SavedFilterParamTypeBool, "0", false, false},
+		{models.SavedFilterParamTypeDate, "", true, nil},
+		{models.SavedFilterParamTypeDate, "abc", true, nil},
+		{models.SavedFilterParamTypeDate, "2022-01-01 10:00:00", false, "2022-01-01 10:00:00.000Z"},
+		{models.SavedFilterParamTypeDaysAgo, "abc", true, nil},
+		{models.SavedFilterParamTypeDaysAgo, "-1", true, nil},
+	}
+
+	for i, s := range scenarios {
+		p := models.SavedFilterParam{Name: "test", Type: s.paramType}
+
+		result, err := p.ParseValue(s.raw)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if result != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+
+	// daysAgo
+	p := models.SavedFilterParam{Name: "test", Type: models.SavedFilterParamTypeDaysAgo}
+	result, err := p.ParseValue("7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	date, _ := types.ParseDateTime(result)
+	expected := time.Now().UTC().AddDate(0, 0, -7)
+	if diff := expected.Sub(date.Time()); diff < 0 || diff > time.Minute {
+		t.Fatalf("Expected %v to be ~7 days ago, got %v", result, expected)
+	}
+}
+
+func TestSavedFilterParamsValueAndScan(t *testing.T) {
+	var nilParams models.SavedFilterParams
+
+	v, err := nilParams.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "[]" {
+		t.Fatalf("Expected empty json array, got %v", v)
+	}
+
+	params := models.SavedFilterParams{}
+	if err := params.Scan(`[{"name":"days","type":"daysAgo"}]`); err != nil {
+		t.Fatal(err)
+	}
+	if len(params) != 1 || params[0].Name != "days" || params[0].Type != models.SavedFilterParamTypeDaysAgo {
+		t.Fatalf("Unexpected scanned params %v", params)
+	}
+
+	if err := params.Scan(123); err == nil {
+		t.Fatal("Expected error for unsupported scan value")
+	}
+}
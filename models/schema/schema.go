@@ -6,6 +6,7 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -35,6 +36,28 @@ func (s *Schema) Fields() []*SchemaField {
 	return s.fields
 }
 
+// LayoutFields returns a new slice with the registered schema fields
+// stably sorted by their layout order (the fields without layout
+// metadata are treated as order 0).
+func (s *Schema) LayoutFields() []*SchemaField {
+	result := make([]*SchemaField, len(s.fields))
+	copy(result, s.fields)
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return layoutOrder(result[i]) < layoutOrder(result[j])
+	})
+
+	return result
+}
+
+func layoutOrder(field *SchemaField) int {
+	if field.Layout == nil {
+		return 0
+	}
+
+	return field.Layout.Order
+}
+
 // InitFieldsOptions calls `InitOptions()` for all schema fields.
 func (s *Schema) InitFieldsOptions() error {
 	for _, field := range s.Fields() {
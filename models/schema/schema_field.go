@@ -3,18 +3,24 @@ package schema
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"regexp"
+	"strings"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/pocketbase/pocketbase/tools/filesystem"
 	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/sanitizer"
+	"github.com/pocketbase/pocketbase/tools/search"
 	"github.com/pocketbase/pocketbase/tools/types"
 	"github.com/spf13/cast"
 )
 
 var schemaFieldNameRegex = regexp.MustCompile(`^\w+$`)
 
+var htmlNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9\-]*$`)
+
 // commonly used field names
 const (
 	FieldNameId                     = "id"
@@ -31,6 +37,25 @@ const (
 	FieldNamePasswordHash           = "passwordHash"
 	FieldNameLastResetSentAt        = "lastResetSentAt"
 	FieldNameLastVerificationSentAt = "lastVerificationSentAt"
+
+	// hidden column of the collections with enabled soft delete
+	FieldNameDeleted = "deleted"
+
+	// hidden column of the collections with enabled records versioning
+	FieldNameVersion = "version"
+
+	// column of the collections with enabled external ids
+	FieldNameExternalId = "externalId"
+
+	// column of the auth collections with enabled phone auth
+	FieldNamePhone = "phone"
+
+	// column of the collections with a tree self-relation field
+	FieldNamePath = "path"
+
+	// virtual fields of the collections with enabled records history
+	FieldNameCreatedBy = "createdBy"
+	FieldNameUpdatedBy = "updatedBy"
 )
 
 // BaseModelFieldNames returns the field names that all models have (id, created, updated).
@@ -67,21 +92,49 @@ func AuthFieldNames() []string {
 
 // All valid field types
 const (
-	FieldTypeText     string = "text"
-	FieldTypeNumber   string = "number"
-	FieldTypeBool     string = "bool"
-	FieldTypeEmail    string = "email"
-	FieldTypeUrl      string = "url"
-	FieldTypeDate     string = "date"
-	FieldTypeSelect   string = "select"
-	FieldTypeJson     string = "json"
-	FieldTypeFile     string = "file"
-	FieldTypeRelation string = "relation"
+	FieldTypeText      string = "text"
+	FieldTypeNumber    string = "number"
+	FieldTypeBool      string = "bool"
+	FieldTypeEmail     string = "email"
+	FieldTypeUrl       string = "url"
+	FieldTypeDate      string = "date"
+	FieldTypeSelect    string = "select"
+	FieldTypeJson      string = "json"
+	FieldTypeFile      string = "file"
+	FieldTypeRelation  string = "relation"
+	FieldTypeComputed  string = "computed"
+	FieldTypeSequence  string = "sequence"
+	FieldTypeEncrypted string = "encrypted"
+	FieldTypeGeoPoint  string = "geoPoint"
+	FieldTypeDocument  string = "document"
 
 	// Deprecated: Will be removed in v0.9+
 	FieldTypeUser string = "user"
 )
 
+// supported field default value macros
+const (
+	DefaultValueNow                   string = "@now"
+	DefaultValueRequestAuthId         string = "@request.auth.id"
+	DefaultValueRequestAuthCollection string = "@request.auth.collectionName"
+)
+
+// supported relation field delete policies
+const (
+	RelationOnDeleteCascade  string = "cascade"
+	RelationOnDeleteSetNull  string = "setNull"
+	RelationOnDeleteRestrict string = "restrict"
+)
+
+// DefaultValueMacros returns slice with all supported field default value macros.
+func DefaultValueMacros() []string {
+	return []string{
+		DefaultValueNow,
+		DefaultValueRequestAuthId,
+		DefaultValueRequestAuthCollection,
+	}
+}
+
 // FieldTypes returns slice with all supported field types.
 func FieldTypes() []string {
 	return []string{
@@ -95,6 +148,11 @@ func FieldTypes() []string {
 		FieldTypeJson,
 		FieldTypeFile,
 		FieldTypeRelation,
+		FieldTypeComputed,
+		FieldTypeSequence,
+		FieldTypeEncrypted,
+		FieldTypeGeoPoint,
+		FieldTypeDocument,
 	}
 }
 
@@ -116,22 +174,84 @@ type SchemaField struct {
 	Required bool   `form:"required" json:"required"`
 	Unique   bool   `form:"unique" json:"unique"`
 	Options  any    `form:"options" json:"options"`
+
+	// Default is an optional static value or macro (see [DefaultValueMacros])
+	// that is applied on record create when the field value is not submitted.
+	Default any `form:"default" json:"default,omitempty"`
+
+	// Validation is an optional filter expression (eg. "endDate > startDate")
+	// that the submitted record must satisfy in order to be saved.
+	Validation string `form:"validation" json:"validation,omitempty"`
+
+	// ReadRule is an optional filter expression (eg. `@request.auth.role = "hr"`)
+	// that the record must satisfy in order the field value to be
+	// returned to the client (empty means no field level restriction).
+	ReadRule string `form:"readRule" json:"readRule,omitempty"`
+
+	// WriteRule is an optional filter expression that the saved record
+	// must satisfy in order the client to be allowed to submit
+	// a value for the field (empty means no field level restriction).
+	WriteRule string `form:"writeRule" json:"writeRule,omitempty"`
+
+	// Layout is an optional presentational metadata of the field
+	// (eg. used by custom admin frontends to arrange the record forms).
+	Layout *SchemaFieldLayout `form:"layout" json:"layout,omitempty"`
+}
+
+// LayoutMaxWidth is the max number of grid columns that
+// a schema field could span (see [SchemaFieldLayout.Width]).
+const LayoutMaxWidth = 12
+
+// SchemaFieldLayout defines the schema field presentational metadata.
+//
+// The layout doesn't affect the records storage and validation.
+type SchemaFieldLayout struct {
+	// Order is the field position (lower first) in its group.
+	Order int `form:"order" json:"order"`
+
+	// Group is an optional group/section name of the field.
+	Group string `form:"group" json:"group"`
+
+	// Width is the number of grid columns (1-12) that the field
+	// spans (0 means the consumer default).
+	Width int `form:"width" json:"width"`
+}
+
+// Validate makes `SchemaFieldLayout` validatable by implementing [validation.Validatable] interface.
+func (l SchemaFieldLayout) Validate() error {
+	return validation.ValidateStruct(&l,
+		validation.Field(&l.Order, validation.Min(0)),
+		validation.Field(&l.Group, validation.Length(0, 100)),
+		validation.Field(&l.Width, validation.Min(0), validation.Max(LayoutMaxWidth)),
+	)
 }
 
 // ColDefinition returns the field db column type definition as string.
 func (f *SchemaField) ColDefinition() string {
 	switch f.Type {
 	case FieldTypeNumber:
+		f.InitOptions()
+		if options, _ := f.Options.(*NumberOptions); options != nil && options.BigInt {
+			return "INTEGER DEFAULT 0"
+		}
 		return "REAL DEFAULT 0"
 	case FieldTypeBool:
 		return "BOOLEAN DEFAULT FALSE"
-	case FieldTypeJson:
+	case FieldTypeJson, FieldTypeGeoPoint, FieldTypeDocument:
 		return "JSON DEFAULT NULL"
+	case FieldTypeSequence:
+		return "INTEGER DEFAULT NULL"
 	default:
 		return "TEXT DEFAULT ''"
 	}
 }
 
+// IsReadOnly checks whether the field value is managed by the db
+// and cannot be set by the app (aka. computed and sequence fields).
+func (f *SchemaField) IsReadOnly() bool {
+	return f.Type == FieldTypeComputed || f.Type == FieldTypeSequence
+}
+
 // String serializes and returns the current field as string.
 func (f SchemaField) String() string {
 	data, _ := f.MarshalJSON()
@@ -151,9 +271,9 @@ func (f SchemaField) MarshalJSON() ([]byte, error) {
 //
 // The schema field options are auto initialized on success.
 func (f *SchemaField) UnmarshalJSON(data []byte) error {
-	type alias *SchemaField // alias to prevent recursion
+	type alias SchemaField // alias to prevent recursion
 
-	a := alias(f)
+	a := (*alias)(f)
 
 	if err := json.Unmarshal(data, a); err != nil {
 		return err
@@ -186,10 +306,38 @@ func (f SchemaField) Validate() error {
 		validation.Field(&f.Type, validation.Required, validation.In(list.ToInterfaceSlice(FieldTypes())...)),
 		// currently file fields cannot be unique because a proper
 		// hash/content check could cause performance issues
-		validation.Field(&f.Unique, validation.When(f.Type == FieldTypeFile, validation.Empty)),
+		// (the same applies for the encrypted fields because their
+		// stored values are randomized on each encryption)
+		validation.Field(&f.Unique, validation.When(f.Type == FieldTypeFile || f.Type == FieldTypeEncrypted || f.IsReadOnly(), validation.Empty)),
+		// computed fields don't have a db column and the sequence
+		// fields values are assigned by the db on record create
+		validation.Field(&f.Required, validation.When(f.IsReadOnly(), validation.Empty)),
+		// the file fields values require uploaded files
+		validation.Field(
+			&f.Default,
+			validation.When(f.Type == FieldTypeFile || f.IsReadOnly(), validation.Nil),
+			validation.By(f.checkDefault),
+		),
+		validation.Field(&f.Layout),
 	)
 }
 
+func (f *SchemaField) checkDefault(value any) error {
+	v, ok := value.(string)
+	if !ok || !strings.HasPrefix(v, "@") {
+		return nil // static value
+	}
+
+	if !list.ExistInSlice(v, DefaultValueMacros()) {
+		return validation.NewError(
+			"validation_invalid_default_macro",
+			fmt.Sprintf("Invalid default value macro - expected one of %s", strings.Join(DefaultValueMacros(), ", ")),
+		)
+	}
+
+	return nil
+}
+
 func (f *SchemaField) checkOptions(value any) error {
 	v, ok := value.(FieldOptions)
 	if !ok {
@@ -234,6 +382,16 @@ func (f *SchemaField) InitOptions() error {
 		options = &FileOptions{}
 	case FieldTypeRelation:
 		options = &RelationOptions{}
+	case FieldTypeComputed:
+		options = &ComputedOptions{}
+	case FieldTypeSequence:
+		options = &SequenceOptions{}
+	case FieldTypeEncrypted:
+		options = &EncryptedOptions{}
+	case FieldTypeGeoPoint:
+		options = &GeoPointOptions{}
+	case FieldTypeDocument:
+		options = &DocumentOptions{}
 
 	// Deprecated: Will be removed in v0.9+
 	case FieldTypeUser:
@@ -258,12 +416,21 @@ func (f *SchemaField) PrepareValue(value any) any {
 	f.InitOptions()
 
 	switch f.Type {
-	case FieldTypeText, FieldTypeEmail, FieldTypeUrl:
+	case FieldTypeText, FieldTypeEmail, FieldTypeUrl, FieldTypeEncrypted:
 		return cast.ToString(value)
 	case FieldTypeJson:
 		val, _ := types.ParseJsonRaw(value)
+		if options, _ := f.Options.(*JsonOptions); options != nil && options.BigIntStrings {
+			if normalized, err := types.StringifyBigInts(val); err == nil {
+				return normalized
+			}
+		}
 		return val
 	case FieldTypeNumber:
+		if options, _ := f.Options.(*NumberOptions); options != nil && options.BigInt {
+			val, _ := types.ParseBigInt(value)
+			return val
+		}
 		return cast.ToFloat64(value)
 	case FieldTypeBool:
 		return cast.ToBool(value)
@@ -306,6 +473,31 @@ func (f *SchemaField) PrepareValue(value any) any {
 		}
 
 		return ids
+	case FieldTypeComputed:
+		// the computed values are usually numeric (or NULL)
+		if str, ok := value.(string); ok {
+			if num, err := cast.ToFloat64E(str); err == nil {
+				return num
+			}
+		}
+		return value
+	case FieldTypeSequence:
+		if value == nil {
+			return nil // not assigned yet
+		}
+		return cast.ToInt(value)
+	case FieldTypeGeoPoint:
+		point, err := types.ParseGeoPoint(value)
+		if err != nil {
+			return nil // missing or invalid
+		}
+		return point
+	case FieldTypeDocument:
+		doc, err := types.ParseDocument(value)
+		if err != nil || doc.IsEmpty() {
+			return nil // missing or invalid
+		}
+		return doc
 	default:
 		return value // unmodified
 	}
@@ -355,6 +547,10 @@ func (o *TextOptions) checkRegex(value any) error {
 type NumberOptions struct {
 	Min *float64 `form:"min" json:"min"`
 	Max *float64 `form:"max" json:"max"`
+
+	// BigInt stores the field value as exact 64-bit integer
+	// and serializes it as JSON string (see [types.BigInt]).
+	BigInt bool `form:"bigInt" json:"bigInt,omitempty"`
 }
 
 func (o NumberOptions) Validate() error {
@@ -472,6 +668,9 @@ func (o SelectOptions) Validate() error {
 // -------------------------------------------------------------------
 
 type JsonOptions struct {
+	// BigIntStrings converts the value integers that cannot be
+	// represented exactly as float64 to strings (eg. snowflake ids).
+	BigIntStrings bool `form:"bigIntStrings" json:"bigIntStrings,omitempty"`
 }
 
 func (o JsonOptions) Validate() error {
@@ -485,6 +684,18 @@ type FileOptions struct {
 	MaxSize   int      `form:"maxSize" json:"maxSize"` // in bytes
 	MimeTypes []string `form:"mimeTypes" json:"mimeTypes"`
 	Thumbs    []string `form:"thumbs" json:"thumbs"`
+
+	// DeniedMimeTypes is a list with the not allowed detected
+	// (aka. from the file content) mime types.
+	DeniedMimeTypes []string `form:"deniedMimeTypes" json:"deniedMimeTypes"`
+
+	// StrictMimeType requires the detected mime type to match with
+	// the declared content type and the extension of the uploaded file.
+	StrictMimeType bool `form:"strictMimeType" json:"strictMimeType"`
+
+	// SanitizeSvg removes the scripts, event handlers, etc.
+	// from the uploaded svg files.
+	SanitizeSvg bool `form:"sanitizeSvg" json:"sanitizeSvg"`
 }
 
 func (o FileOptions) Validate() error {
@@ -501,18 +712,197 @@ func (o FileOptions) Validate() error {
 // -------------------------------------------------------------------
 
 type RelationOptions struct {
-	MaxSelect     *int   `form:"maxSelect" json:"maxSelect"`
-	CollectionId  string `form:"collectionId" json:"collectionId"`
-	CascadeDelete bool   `form:"cascadeDelete" json:"cascadeDelete"`
+	MaxSelect    *int   `form:"maxSelect" json:"maxSelect"`
+	CollectionId string `form:"collectionId" json:"collectionId"`
+
+	// OnDelete specifies what happens with the referencing records
+	// when the referenced record is deleted:
+	//  - RelationOnDeleteCascade - deletes the referencing records
+	//    (if there are no other references in case of multiple relation)
+	//  - RelationOnDeleteSetNull - unsets the deleted record id
+	//  - RelationOnDeleteRestrict - prevents the referenced record delete
+	//
+	// If not set, fallbacks to the CascadeDelete flag behavior.
+	OnDelete string `form:"onDelete" json:"onDelete"`
+
+	// Deprecated: Use OnDelete with RelationOnDeleteCascade instead.
+	CascadeDelete bool `form:"cascadeDelete" json:"cascadeDelete"`
+
+	// Tree marks a single self-relation field as the records parent
+	// reference and maintains an auto materialized path column
+	// (see [FieldNamePath]) with the "/" separated ancestor ids.
+	Tree bool `form:"tree" json:"tree"`
 }
 
 func (o RelationOptions) Validate() error {
 	return validation.ValidateStruct(&o,
 		validation.Field(&o.CollectionId, validation.Required),
 		validation.Field(&o.MaxSelect, validation.NilOrNotEmpty, validation.Min(1)),
+		validation.Field(
+			&o.OnDelete,
+			validation.In(RelationOnDeleteCascade, RelationOnDeleteSetNull, RelationOnDeleteRestrict),
+		),
 	)
 }
 
+// OnDeleteAction returns the normalized relation delete policy
+// (with fallback to the deprecated CascadeDelete flag).
+func (o RelationOptions) OnDeleteAction() string {
+	if o.OnDelete != "" {
+		return o.OnDelete
+	}
+
+	if o.CascadeDelete {
+		return RelationOnDeleteCascade
+	}
+
+	return RelationOnDeleteSetNull
+}
+
+// -------------------------------------------------------------------
+
+type ComputedOptions struct {
+	// Expression is an arithmetic expression over the collection fields
+	// (eg. "price * quantity") that is evaluated on each records query.
+	Expression string `form:"expression" json:"expression"`
+}
+
+func (o ComputedOptions) Validate() error {
+	return validation.ValidateStruct(&o,
+		validation.Field(&o.Expression, validation.Required, validation.By(o.checkExpression)),
+	)
+}
+
+func (o *ComputedOptions) checkExpression(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	if _, err := search.ComputedExpr(v).Identifiers(); err != nil {
+		return validation.NewError("validation_invalid_expression", "Invalid computed expression - "+err.Error())
+	}
+
+	return nil
+}
+
+// -------------------------------------------------------------------
+
+type SequenceOptions struct {
+}
+
+func (o SequenceOptions) Validate() error {
+	return nil
+}
+
+// -------------------------------------------------------------------
+
+type EncryptedOptions struct {
+	Min *int `form:"min" json:"min"`
+	Max *int `form:"max" json:"max"`
+}
+
+func (o EncryptedOptions) Validate() error {
+	minVal := 0
+	if o.Min != nil {
+		minVal = *o.Min
+	}
+
+	return validation.ValidateStruct(&o,
+		validation.Field(&o.Min, validation.Min(0)),
+		validation.Field(&o.Max, validation.Min(minVal)),
+	)
+}
+
+// -------------------------------------------------------------------
+
+type GeoPointOptions struct {
+}
+
+func (o GeoPointOptions) Validate() error {
+	return nil
+}
+
+// -------------------------------------------------------------------
+
+type DocumentOptions struct {
+	// AllowedTags is a list with the html elements that are preserved
+	// on write (fallbacks to [sanitizer.DefaultHtmlAllowedTags] if empty).
+	AllowedTags []string `form:"allowedTags" json:"allowedTags"`
+
+	// AllowedAttributes is a list with the html attributes that are preserved
+	// on write (fallbacks to [sanitizer.DefaultHtmlAllowedAttributes] if empty).
+	AllowedAttributes []string `form:"allowedAttributes" json:"allowedAttributes"`
+
+	// PlainText enables the plain text shadow content of the documents
+	// that could be used for filtering and searching (eg. "content.text ~ 'abc'").
+	PlainText bool `form:"plainText" json:"plainText"`
+}
+
+func (o DocumentOptions) Validate() error {
+	return validation.ValidateStruct(&o,
+		validation.Field(&o.AllowedTags, validation.Each(
+			validation.Match(htmlNameRegex),
+			validation.By(checkHtmlDeniedTag),
+		)),
+		validation.Field(&o.AllowedAttributes, validation.Each(
+			validation.Match(htmlNameRegex),
+			validation.By(checkHtmlDeniedAttribute),
+		)),
+	)
+}
+
+// Normalize sanitizes the html content of the provided document and
+// updates its plain text shadow content (if enabled).
+func (o DocumentOptions) Normalize(doc types.Document) (types.Document, error) {
+	tags := o.AllowedTags
+	if len(tags) == 0 {
+		tags = sanitizer.DefaultHtmlAllowedTags
+	}
+
+	attrs := o.AllowedAttributes
+	if len(attrs) == 0 {
+		attrs = sanitizer.DefaultHtmlAllowedAttributes
+	}
+
+	sanitized, err := sanitizer.SanitizeHtml(doc.Html, tags, attrs)
+	if err != nil {
+		return doc, err
+	}
+
+	result := types.Document{Html: sanitized}
+
+	if o.PlainText {
+		result.Text, err = sanitizer.HtmlToText(sanitized)
+		if err != nil {
+			return doc, err
+		}
+	}
+
+	return result, nil
+}
+
+func checkHtmlDeniedTag(value any) error {
+	v, _ := value.(string)
+
+	if list.ExistInSlice(strings.ToLower(v), sanitizer.HtmlDeniedTags) {
+		return validation.NewError("validation_denied_html_tag", "The html element is not allowed")
+	}
+
+	return nil
+}
+
+func checkHtmlDeniedAttribute(value any) error {
+	v, _ := value.(string)
+
+	// event handlers (eg. onload, onclick)
+	if strings.HasPrefix(strings.ToLower(v), "on") {
+		return validation.NewError("validation_denied_html_attribute", "The html attribute is not allowed")
+	}
+
+	return nil
+}
+
 // -------------------------------------------------------------------
 
 // Deprecated: Will be removed in v0.9+
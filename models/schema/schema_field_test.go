@@ -3,6 +3,7 @@ package schema_test
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -40,7 +41,7 @@ func TestAuthFieldNames(t *testing.T) {
 
 func TestFieldTypes(t *testing.T) {
 	result := schema.FieldTypes()
-	expected := 10
+	expected := 15
 
 	if len(result) != expected {
 		t.Fatalf("Expected %d types, got %d (%v)", expected, len(result), result)
@@ -69,6 +70,10 @@ func TestSchemaFieldColDefinition(t *testing.T) {
 			schema.SchemaField{Type: schema.FieldTypeNumber, Name: "test"},
 			"REAL DEFAULT 0",
 		},
+		{
+			schema.SchemaField{Type: schema.FieldTypeNumber, Name: "test", Options: &schema.NumberOptions{BigInt: true}},
+			"INTEGER DEFAULT 0",
+		},
 		{
 			schema.SchemaField{Type: schema.FieldTypeBool, Name: "test"},
 			"BOOLEAN DEFAULT FALSE",
@@ -101,6 +106,22 @@ func TestSchemaFieldColDefinition(t *testing.T) {
 			schema.SchemaField{Type: schema.FieldTypeRelation, Name: "test"},
 			"TEXT DEFAULT ''",
 		},
+		{
+			schema.SchemaField{Type: schema.FieldTypeSequence, Name: "test"},
+			"INTEGER DEFAULT NULL",
+		},
+		{
+			schema.SchemaField{Type: schema.FieldTypeEncrypted, Name: "test"},
+			"TEXT DEFAULT ''",
+		},
+		{
+			schema.SchemaField{Type: schema.FieldTypeGeoPoint, Name: "test"},
+			"JSON DEFAULT NULL",
+		},
+		{
+			schema.SchemaField{Type: schema.FieldTypeDocument, Name: "test"},
+			"JSON DEFAULT NULL",
+		},
 	}
 
 	for i, s := range scenarios {
@@ -167,6 +188,23 @@ func TestSchemaFieldMarshalJSON(t *testing.T) {
 				},
 			},
 			`{"system":true,"id":"","name":"test","type":"text","required":true,"unique":false,"options":{"min":null,"max":null,"pattern":"test"}}`,
+		}, // with default value
+		{
+			schema.SchemaField{
+				Name:    "test",
+				Type:    schema.FieldTypeText,
+				Default: "@now",
+			},
+			`{"system":false,"id":"","name":"test","type":"text","required":false,"unique":false,"options":{"min":null,"max":null,"pattern":""},"default":"@now"}`,
+		},
+		// with layout
+		{
+			schema.SchemaField{
+				Name:   "test",
+				Type:   schema.FieldTypeText,
+				Layout: &schema.SchemaFieldLayout{Order: 2, Group: "details", Width: 6},
+			},
+			`{"system":false,"id":"","name":"test","type":"text","required":false,"unique":false,"options":{"min":null,"max":null,"pattern":""},"layout":{"order":2,"group":"details","width":6}}`,
 		},
 	}
 
@@ -218,6 +256,11 @@ func TestSchemaFieldUnmarshalJSON(t *testing.T) {
 			false,
 			`{"system":false,"id":"","name":"","type":"text","required":false,"unique":false,"options":{"min":null,"max":null,"pattern":"test"}}`,
 		},
+		{
+			[]byte(`{"type":"text","layout":{"group":"details","width":4}}`),
+			false,
+			`{"system":false,"id":"","name":"","type":"text","required":false,"unique":false,"options":{"min":null,"max":null,"pattern":""},"layout":{"order":0,"group":"details","width":4}}`,
+		},
 	}
 
 	for i, s := range scenarios {
@@ -383,6 +426,39 @@ func TestSchemaFieldValidate(t *testing.T) {
 			},
 			[]string{"unique"},
 		},
+		{
+			"unique and required check for type computed",
+			schema.SchemaField{
+				Type:     schema.FieldTypeComputed,
+				Id:       "1234567890",
+				Name:     "test",
+				Unique:   true,
+				Required: true,
+				Options:  &schema.ComputedOptions{Expression: "a * b"},
+			},
+			[]string{"unique", "required"},
+		},
+		{
+			"unique and required check for type sequence",
+			schema.SchemaField{
+				Type:     schema.FieldTypeSequence,
+				Id:       "1234567890",
+				Name:     "test",
+				Unique:   true,
+				Required: true,
+			},
+			[]string{"unique", "required"},
+		},
+		{
+			"unique check for type encrypted",
+			schema.SchemaField{
+				Type:   schema.FieldTypeEncrypted,
+				Id:     "1234567890",
+				Name:   "test",
+				Unique: true,
+			},
+			[]string{"unique"},
+		},
 		{
 			"trigger options validator (auto init)",
 			schema.SchemaField{
@@ -412,6 +488,67 @@ func TestSchemaFieldValidate(t *testing.T) {
 			},
 			[]string{},
 		},
+		{
+			"static default value",
+			schema.SchemaField{
+				Type:    schema.FieldTypeText,
+				Id:      "1234567890",
+				Name:    "test",
+				Default: "abc",
+			},
+			[]string{},
+		},
+		{
+			"valid default value macro",
+			schema.SchemaField{
+				Type:    schema.FieldTypeText,
+				Id:      "1234567890",
+				Name:    "test",
+				Default: schema.DefaultValueRequestAuthId,
+			},
+			[]string{},
+		},
+		{
+			"invalid default value macro",
+			schema.SchemaField{
+				Type:    schema.FieldTypeText,
+				Id:      "1234567890",
+				Name:    "test",
+				Default: "@request.data.title",
+			},
+			[]string{"default"},
+		},
+		{
+			"file field with default value",
+			schema.SchemaField{
+				Type:    schema.FieldTypeFile,
+				Id:      "1234567890",
+				Name:    "test",
+				Options: &schema.FileOptions{MaxSelect: 1, MaxSize: 1},
+				Default: "test.png",
+			},
+			[]string{"default"},
+		},
+		{
+			"invalid layout",
+			schema.SchemaField{
+				Type:   schema.FieldTypeText,
+				Id:     "1234567890",
+				Name:   "test",
+				Layout: &schema.SchemaFieldLayout{Order: -1, Group: strings.Repeat("a", 101), Width: 13},
+			},
+			[]string{"layout"},
+		},
+		{
+			"valid layout",
+			schema.SchemaField{
+				Type:   schema.FieldTypeText,
+				Id:     "1234567890",
+				Name:   "test",
+				Layout: &schema.SchemaFieldLayout{Order: 1, Group: "details", Width: 12},
+			},
+			[]string{},
+		},
 	}
 
 	for _, s := range scenarios {
@@ -495,12 +632,37 @@ func TestSchemaFieldInitOptions(t *testing.T) {
 		{
 			schema.SchemaField{Type: schema.FieldTypeFile},
 			false,
-			`{"system":false,"id":"","name":"","type":"file","required":false,"unique":false,"options":{"maxSelect":0,"maxSize":0,"mimeTypes":null,"thumbs":null}}`,
+			`{"system":false,"id":"","name":"","type":"file","required":false,"unique":false,"options":{"maxSelect":0,"maxSize":0,"mimeTypes":null,"thumbs":null,"deniedMimeTypes":null,"strictMimeType":false,"sanitizeSvg":false}}`,
 		},
 		{
 			schema.SchemaField{Type: schema.FieldTypeRelation},
 			false,
-			`{"system":false,"id":"","name":"","type":"relation","required":false,"unique":false,"options":{"maxSelect":null,"collectionId":"","cascadeDelete":false}}`,
+			`{"system":false,"id":"","name":"","type":"relation","required":false,"unique":false,"options":{"maxSelect":null,"collectionId":"","onDelete":"","cascadeDelete":false,"tree":false}}`,
+		},
+		{
+			schema.SchemaField{Type: schema.FieldTypeComputed},
+			false,
+			`{"system":false,"id":"","name":"","type":"computed","required":false,"unique":false,"options":{"expression":""}}`,
+		},
+		{
+			schema.SchemaField{Type: schema.FieldTypeSequence},
+			false,
+			`{"system":false,"id":"","name":"","type":"sequence","required":false,"unique":false,"options":{}}`,
+		},
+		{
+			schema.SchemaField{Type: schema.FieldTypeEncrypted},
+			false,
+			`{"system":false,"id":"","name":"","type":"encrypted","required":false,"unique":false,"options":{"min":null,"max":null}}`,
+		},
+		{
+			schema.SchemaField{Type: schema.FieldTypeGeoPoint},
+			false,
+			`{"system":false,"id":"","name":"","type":"geoPoint","required":false,"unique":false,"options":{}}`,
+		},
+		{
+			schema.SchemaField{Type: schema.FieldTypeDocument},
+			false,
+			`{"system":false,"id":"","name":"","type":"document","required":false,"unique":false,"options":{"allowedTags":null,"allowedAttributes":null,"plainText":false}}`,
 		},
 		{
 			schema.SchemaField{Type: schema.FieldTypeUser},
@@ -568,6 +730,12 @@ func TestSchemaFieldPrepareValue(t *testing.T) {
 		{schema.SchemaField{Type: schema.FieldTypeJson}, `"test"`, `"test"`},
 		{schema.SchemaField{Type: schema.FieldTypeJson}, map[string]int{"test": 123}, `{"test":123}`},
 		{schema.SchemaField{Type: schema.FieldTypeJson}, []int{1, 2, 1}, `[1,2,1]`},
+		{schema.SchemaField{Type: schema.FieldTypeJson}, `{"a":1234567890123456789}`, `{"a":1234567890123456789}`},
+		{
+			schema.SchemaField{Type: schema.FieldTypeJson, Options: &schema.JsonOptions{BigIntStrings: true}},
+			`{"a":1234567890123456789,"b":[1,-9007199254740993,1.5]}`,
+			`{"a":"1234567890123456789","b":[1,"-9007199254740993",1.5]}`,
+		},
 
 		// number
 		{schema.SchemaField{Type: schema.FieldTypeNumber}, nil, "0"},
@@ -576,6 +744,11 @@ func TestSchemaFieldPrepareValue(t *testing.T) {
 		{schema.SchemaField{Type: schema.FieldTypeNumber}, 1, "1"},
 		{schema.SchemaField{Type: schema.FieldTypeNumber}, 1.5, "1.5"},
 		{schema.SchemaField{Type: schema.FieldTypeNumber}, "1.5", "1.5"},
+		{schema.SchemaField{Type: schema.FieldTypeNumber, Options: &schema.NumberOptions{BigInt: true}}, nil, `"0"`},
+		{schema.SchemaField{Type: schema.FieldTypeNumber, Options: &schema.NumberOptions{BigInt: true}}, "test", `"0"`},
+		{schema.SchemaField{Type: schema.FieldTypeNumber, Options: &schema.NumberOptions{BigInt: true}}, 1.5, `"0"`},
+		{schema.SchemaField{Type: schema.FieldTypeNumber, Options: &schema.NumberOptions{BigInt: true}}, 123, `"123"`},
+		{schema.SchemaField{Type: schema.FieldTypeNumber, Options: &schema.NumberOptions{BigInt: true}}, "1234567890123456789", `"1234567890123456789"`},
 
 		// bool
 		{schema.SchemaField{Type: schema.FieldTypeBool}, nil, "false"},
@@ -846,6 +1019,38 @@ func TestSchemaFieldPrepareValue(t *testing.T) {
 			[]string{"1ba88b4f-e9da-42f0-9764-9a55c953e724", "2ba88b4f-e9da-42f0-9764-9a55c953e724", "1ba88b4f-e9da-42f0-9764-9a55c953e724"},
 			`["1ba88b4f-e9da-42f0-9764-9a55c953e724","2ba88b4f-e9da-42f0-9764-9a55c953e724"]`,
 		},
+
+		// computed
+		{schema.SchemaField{Type: schema.FieldTypeComputed}, nil, "null"},
+		{schema.SchemaField{Type: schema.FieldTypeComputed}, "", `""`},
+		{schema.SchemaField{Type: schema.FieldTypeComputed}, "test", `"test"`},
+		{schema.SchemaField{Type: schema.FieldTypeComputed}, "12.5", "12.5"},
+		{schema.SchemaField{Type: schema.FieldTypeComputed}, 3, "3"},
+
+		// sequence
+		{schema.SchemaField{Type: schema.FieldTypeSequence}, nil, "null"},
+		{schema.SchemaField{Type: schema.FieldTypeSequence}, "", "0"},
+		{schema.SchemaField{Type: schema.FieldTypeSequence}, "12", "12"},
+		{schema.SchemaField{Type: schema.FieldTypeSequence}, 3.0, "3"},
+
+		// encrypted
+		{schema.SchemaField{Type: schema.FieldTypeEncrypted}, nil, `""`},
+		{schema.SchemaField{Type: schema.FieldTypeEncrypted}, "test", `"test"`},
+		{schema.SchemaField{Type: schema.FieldTypeEncrypted}, 123, `"123"`},
+
+		// geoPoint
+		{schema.SchemaField{Type: schema.FieldTypeGeoPoint}, nil, "null"},
+		{schema.SchemaField{Type: schema.FieldTypeGeoPoint}, "invalid", "null"},
+		{schema.SchemaField{Type: schema.FieldTypeGeoPoint}, `{"lon":1.5,"lat":-2}`, `{"lon":1.5,"lat":-2}`},
+		{schema.SchemaField{Type: schema.FieldTypeGeoPoint}, map[string]any{"lon": 3, "lat": 4}, `{"lon":3,"lat":4}`},
+
+		// document
+		{schema.SchemaField{Type: schema.FieldTypeDocument}, nil, "null"},
+		{schema.SchemaField{Type: schema.FieldTypeDocument}, "", "null"},
+		{schema.SchemaField{Type: schema.FieldTypeDocument}, map[string]any{"text": "test"}, "null"},
+		{schema.SchemaField{Type: schema.FieldTypeDocument}, "<p>test</p>", `{"html":"\u003cp\u003etest\u003c/p\u003e","text":""}`},
+		{schema.SchemaField{Type: schema.FieldTypeDocument}, `{"html":"test","text":"abc"}`, `{"html":"test","text":"abc"}`},
+		{schema.SchemaField{Type: schema.FieldTypeDocument}, map[string]any{"html": "test"}, `{"html":"test","text":""}`},
 	}
 
 	for i, s := range scenarios {
@@ -1313,7 +1518,243 @@ func TestRelationOptionsValidate(t *testing.T) {
 			},
 			[]string{},
 		},
+		{
+			"invalid OnDelete",
+			schema.RelationOptions{
+				CollectionId: "abc",
+				OnDelete:     "missing",
+			},
+			[]string{"onDelete"},
+		},
+		{
+			"valid OnDelete",
+			schema.RelationOptions{
+				CollectionId: "abc",
+				OnDelete:     schema.RelationOnDeleteRestrict,
+			},
+			[]string{},
+		},
+	}
+
+	checkFieldOptionsScenarios(t, scenarios)
+}
+
+func TestRelationOptionsOnDeleteAction(t *testing.T) {
+	scenarios := []struct {
+		options  schema.RelationOptions
+		expected string
+	}{
+		{schema.RelationOptions{}, schema.RelationOnDeleteSetNull},
+		{schema.RelationOptions{CascadeDelete: true}, schema.RelationOnDeleteCascade},
+		{schema.RelationOptions{OnDelete: schema.RelationOnDeleteRestrict}, schema.RelationOnDeleteRestrict},
+		{schema.RelationOptions{OnDelete: schema.RelationOnDeleteSetNull, CascadeDelete: true}, schema.RelationOnDeleteSetNull},
+	}
+
+	for i, s := range scenarios {
+		if result := s.options.OnDeleteAction(); result != s.expected {
+			t.Errorf("(%d) Expected %q, got %q", i, s.expected, result)
+		}
+	}
+}
+
+func TestSequenceOptionsValidate(t *testing.T) {
+	scenarios := []fieldOptionsScenario{
+		{
+			"empty",
+			schema.SequenceOptions{},
+			[]string{},
+		},
+	}
+
+	checkFieldOptionsScenarios(t, scenarios)
+}
+
+func TestEncryptedOptionsValidate(t *testing.T) {
+	scenarios := []fieldOptionsScenario{
+		{
+			"empty",
+			schema.EncryptedOptions{},
+			[]string{},
+		},
+		{
+			"min - failure",
+			schema.EncryptedOptions{
+				Min: types.Pointer(-1),
+			},
+			[]string{"min"},
+		},
+		{
+			"max - failure with min",
+			schema.EncryptedOptions{
+				Min: types.Pointer(100),
+				Max: types.Pointer(99),
+			},
+			[]string{"max"},
+		},
+		{
+			"max - success with min",
+			schema.EncryptedOptions{
+				Min: types.Pointer(99),
+				Max: types.Pointer(100),
+			},
+			[]string{},
+		},
+	}
+
+	checkFieldOptionsScenarios(t, scenarios)
+}
+
+func TestGeoPointOptionsValidate(t *testing.T) {
+	scenarios := []fieldOptionsScenario{
+		{
+			"empty",
+			schema.GeoPointOptions{},
+			[]string{},
+		},
+	}
+
+	checkFieldOptionsScenarios(t, scenarios)
+}
+
+func TestDocumentOptionsValidate(t *testing.T) {
+	scenarios := []fieldOptionsScenario{
+		{
+			"empty",
+			schema.DocumentOptions{},
+			[]string{},
+		},
+		{
+			"invalid tag and attribute names",
+			schema.DocumentOptions{
+				AllowedTags:       []string{"p", "<b>"},
+				AllowedAttributes: []string{"href", "a b"},
+			},
+			[]string{"allowedTags", "allowedAttributes"},
+		},
+		{
+			"denied tag and attribute",
+			schema.DocumentOptions{
+				AllowedTags:       []string{"p", "Script"},
+				AllowedAttributes: []string{"href", "onclick"},
+			},
+			[]string{"allowedTags", "allowedAttributes"},
+		},
+		{
+			"valid allow lists",
+			schema.DocumentOptions{
+				AllowedTags:       []string{"p", "h1"},
+				AllowedAttributes: []string{"href", "data-id"},
+				PlainText:         true,
+			},
+			[]string{},
+		},
+	}
+
+	checkFieldOptionsScenarios(t, scenarios)
+}
+
+func TestDocumentOptionsNormalize(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		options  schema.DocumentOptions
+		doc      types.Document
+		expected types.Document
+	}{
+		{
+			"default allow lists",
+			schema.DocumentOptions{},
+			types.Document{Html: `<p onclick="a()">Hello <b>world</b><script>alert(1)</script></p>`, Text: "old"},
+			types.Document{Html: `<p>Hello <b>world</b></p>`},
+		},
+		{
+			"custom allow lists with plain text",
+			schema.DocumentOptions{
+				AllowedTags:       []string{"p", "A"},
+				AllowedAttributes: []string{"href"},
+				PlainText:         true,
+			},
+			types.Document{Html: `<h1>Title</h1><p>Hello <a href="javascript:x()" title="t">link</a></p>`},
+			types.Document{Html: `Title<p>Hello <a>link</a></p>`, Text: "Title Hello link"},
+		},
+	}
+
+	for _, s := range scenarios {
+		result, err := s.options.Normalize(s.doc)
+		if err != nil {
+			t.Errorf("[%s] Unexpected error %v", s.name, err)
+			continue
+		}
+
+		if result != s.expected {
+			t.Errorf("[%s] Expected %#v, got %#v", s.name, s.expected, result)
+		}
+	}
+}
+
+func TestComputedOptionsValidate(t *testing.T) {
+	scenarios := []fieldOptionsScenario{
+		{
+			"empty",
+			schema.ComputedOptions{},
+			[]string{"expression"},
+		},
+		{
+			"invalid expression",
+			schema.ComputedOptions{Expression: "a * (b + "},
+			[]string{"expression"},
+		},
+		{
+			"valid expression",
+			schema.ComputedOptions{Expression: "a * (b + 1)"},
+			[]string{},
+		},
 	}
 
 	checkFieldOptionsScenarios(t, scenarios)
 }
+
+func TestSchemaFieldLayoutValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		layout         schema.SchemaFieldLayout
+		expectedErrors []string
+	}{
+		{"zero value", schema.SchemaFieldLayout{}, []string{}},
+		{
+			"invalid values",
+			schema.SchemaFieldLayout{Order: -1, Group: strings.Repeat("a", 101), Width: 13},
+			[]string{"order", "group", "width"},
+		},
+		{
+			"negative width",
+			schema.SchemaFieldLayout{Width: -1},
+			[]string{"width"},
+		},
+		{
+			"valid values",
+			schema.SchemaFieldLayout{Order: 10, Group: "details", Width: 6},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		result := s.layout.Validate()
+
+		// parse errors
+		errs, ok := result.(validation.Errors)
+		if !ok && result != nil {
+			t.Errorf("[%s] Failed to parse errors %v", s.name, result)
+			continue
+		}
+
+		// check errors
+		if len(errs) > len(s.expectedErrors) {
+			t.Errorf("[%s] Expected error keys %v, got %v", s.name, s.expectedErrors, errs)
+		}
+		for _, k := range s.expectedErrors {
+			if _, ok := errs[k]; !ok {
+				t.Errorf("[%s] Missing expected error key %q in %v", s.name, k, errs)
+			}
+		}
+	}
+}
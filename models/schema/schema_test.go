@@ -1,6 +1,7 @@
 package schema_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/pocketbase/pocketbase/models/schema"
@@ -88,6 +89,31 @@ func TestSchemaClone(t *testing.T) {
 	}
 }
 
+func TestSchemaLayoutFields(t *testing.T) {
+	s := schema.NewSchema(
+		&schema.SchemaField{Name: "a", Layout: &schema.SchemaFieldLayout{Order: 2}},
+		&schema.SchemaField{Name: "b"},
+		&schema.SchemaField{Name: "c", Layout: &schema.SchemaFieldLayout{Order: 1, Group: "test"}},
+		&schema.SchemaField{Name: "d", Layout: &schema.SchemaFieldLayout{Order: 1}},
+		&schema.SchemaField{Name: "e", Layout: &schema.SchemaFieldLayout{}},
+	)
+
+	names := []string{}
+	for _, f := range s.LayoutFields() {
+		names = append(names, f.Name)
+	}
+
+	expected := "b,e,c,d,a"
+	if result := strings.Join(names, ","); result != expected {
+		t.Fatalf("Expected fields order %q, got %q", expected, result)
+	}
+
+	// the schema fields order must not change
+	if first := s.Fields()[0].Name; first != "a" {
+		t.Fatalf("Expected the schema fields to not be reordered, got first field %q", first)
+	}
+}
+
 func TestSchemaAsMap(t *testing.T) {
 	f1 := &schema.SchemaField{Name: "test1", Type: schema.FieldTypeText}
 	f2 := &schema.SchemaField{Name: "test2", Type: schema.FieldTypeEmail}
@@ -1,18 +1,30 @@
 package settings
 
 import (
+	"crypto"
 	"encoding/json"
 	"errors"
 	"fmt"
+	htmltemplate "html/template"
+	"math"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/pocketbase/pocketbase/tools/alert"
 	"github.com/pocketbase/pocketbase/tools/auth"
+	"github.com/pocketbase/pocketbase/tools/list"
 	"github.com/pocketbase/pocketbase/tools/mailer"
+	"github.com/pocketbase/pocketbase/tools/reporter"
 	"github.com/pocketbase/pocketbase/tools/rest"
 	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/sms"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/spf13/cast"
 )
 
 // Settings defines common app configuration options.
@@ -24,12 +36,45 @@ type Settings struct {
 	Smtp SmtpConfig `form:"smtp" json:"smtp"`
 	S3   S3Config   `form:"s3" json:"s3"`
 
+	Sms SmsConfig `form:"sms" json:"sms"`
+
+	Filter FilterConfig `form:"filter" json:"filter"`
+
+	RuleMacros RuleMacrosConfig `form:"ruleMacros" json:"ruleMacros"`
+
+	Json JsonConfig `form:"json" json:"json"`
+
+	ErrorReporting ErrorReportingConfig `form:"errorReporting" json:"errorReporting"`
+
+	Alerts AlertsConfig `form:"alerts" json:"alerts"`
+
+	Digest DigestConfig `form:"digest" json:"digest"`
+
+	Preferences PreferencesConfig `form:"preferences" json:"preferences"`
+
+	Client ClientConfig `form:"client" json:"client"`
+
+	Guardrails GuardrailsConfig `form:"guardrails" json:"guardrails"`
+
+	TrustedProxy TrustedProxyConfig `form:"trustedProxy" json:"trustedProxy"`
+
+	LoginThrottle LoginThrottleConfig `form:"loginThrottle" json:"loginThrottle"`
+
+	SecurityHeaders SecurityHeadersConfig `form:"securityHeaders" json:"securityHeaders"`
+
+	Signing SigningConfig `form:"signing" json:"signing"`
+
+	TokenSigning TokenSigningConfig `form:"tokenSigning" json:"tokenSigning"`
+
+	AuthTokenClaims AuthTokenClaimsConfig `form:"authTokenClaims" json:"authTokenClaims"`
+
 	AdminAuthToken           TokenConfig `form:"adminAuthToken" json:"adminAuthToken"`
 	AdminPasswordResetToken  TokenConfig `form:"adminPasswordResetToken" json:"adminPasswordResetToken"`
 	RecordAuthToken          TokenConfig `form:"recordAuthToken" json:"recordAuthToken"`
 	RecordPasswordResetToken TokenConfig `form:"recordPasswordResetToken" json:"recordPasswordResetToken"`
 	RecordEmailChangeToken   TokenConfig `form:"recordEmailChangeToken" json:"recordEmailChangeToken"`
 	RecordVerificationToken  TokenConfig `form:"recordVerificationToken" json:"recordVerificationToken"`
+	RecordLoginLinkToken     TokenConfig `form:"recordLoginLinkToken" json:"recordLoginLinkToken"`
 
 	// Deprecated: Will be removed in v0.9+
 	EmailAuth EmailAuthConfig `form:"emailAuth" json:"emailAuth"`
@@ -46,6 +91,8 @@ type Settings struct {
 	TwitchAuth    AuthProviderConfig `form:"twitchAuth" json:"twitchAuth"`
 	StravaAuth    AuthProviderConfig `form:"stravaAuth" json:"stravaAuth"`
 	GiteeAuth     AuthProviderConfig `form:"giteeAuth" json:"giteeAuth"`
+	OIDCAuth      AuthProviderConfig `form:"oidcAuth" json:"oidcAuth"`
+	AppleAuth     AuthProviderConfig `form:"appleAuth" json:"appleAuth"`
 }
 
 // New creates and returns a new default Settings instance.
@@ -60,10 +107,83 @@ func New() *Settings {
 			VerificationTemplate:       defaultVerificationTemplate,
 			ResetPasswordTemplate:      defaultResetPasswordTemplate,
 			ConfirmEmailChangeTemplate: defaultConfirmEmailChangeTemplate,
+			LoginLinkTemplate:          defaultLoginLinkTemplate,
 		},
 		Logs: LogsConfig{
 			MaxDays: 5,
 		},
+		Filter: FilterConfig{
+			MaxNestedRels: 6,
+			MaxJoins:      50,
+		},
+		RuleMacros: RuleMacrosConfig{
+			Macros: []RuleMacro{},
+		},
+		ErrorReporting: ErrorReportingConfig{
+			Enabled:       false,
+			SlowThreshold: 0,
+			SendPii:       false,
+		},
+		Alerts: AlertsConfig{
+			Enabled:         false,
+			Cooldown:        60,
+			ErrorsThreshold: 50,
+			ErrorsWindow:    5,
+		},
+		Digest: DigestConfig{
+			Enabled: false,
+			Window:  60,
+			Subject: defaultDigestTemplate.Subject,
+			Body:    defaultDigestTemplate.Body,
+		},
+		Preferences: PreferencesConfig{
+			Keys: []PreferenceKey{},
+		},
+		Client: ClientConfig{
+			MinVersion: "",
+			Flags:      map[string]bool{},
+			Public:     map[string]any{},
+		},
+		Guardrails: GuardrailsConfig{
+			MinFreeDisk: 500,
+			MaxDataSize: 0,
+			ReadOnly:    false,
+		},
+		TrustedProxy: TrustedProxyConfig{
+			Headers: []string{},
+		},
+		LoginThrottle: LoginThrottleConfig{
+			Enabled:             false,
+			MaxIdentityFailures: 5,
+			MaxIpFailures:       50,
+			Window:              900,  // 15 minutes
+			LockoutDuration:     60,   // 1 minute
+			MaxLockoutDuration:  3600, // 1 hour
+		},
+		SecurityHeaders: SecurityHeadersConfig{
+			Enabled: false,
+			Default: SecurityHeadersPolicy{
+				XFrameOptions:  "SAMEORIGIN",
+				ReferrerPolicy: "strict-origin-when-cross-origin",
+			},
+			AdminUI: SecurityHeadersPolicy{
+				XFrameOptions:  "DENY",
+				ReferrerPolicy: "same-origin",
+			},
+		},
+		Signing: SigningConfig{
+			Enabled: false,
+			Secret:  security.RandomString(50),
+		},
+		TokenSigning: TokenSigningConfig{
+			Enabled:   false,
+			Algorithm: TokenSigningAlgorithmRS256,
+			Keys:      []TokenSigningKey{},
+		},
+		AuthTokenClaims: AuthTokenClaimsConfig{
+			Issuer:   "",
+			Audience: []string{},
+		},
 		Smtp: SmtpConfig{
 			Enabled:  false,
 			Host:     "smtp.example.com",
@@ -72,6 +192,12 @@ func New() *Settings {
 			Password: "",
 			Tls:      false,
 		},
+		Sms: SmsConfig{
+			Enabled:     false,
+			Provider:    sms.ProviderTwilio,
+			OtpTemplate: defaultSmsOtpTemplate,
+			OtpDuration: 300, // 5 minutes
+		},
 		AdminAuthToken: TokenConfig{
 			Secret:   security.RandomString(50),
 			Duration: 1209600, // 14 days,
@@ -96,6 +222,10 @@ func New() *Settings {
 			Secret:   security.RandomString(50),
 			Duration: 1800, // 30 minutes,
 		},
+		RecordLoginLinkToken: TokenConfig{
+			Secret:   security.RandomString(50),
+			Duration: 600, // 10 minutes,
+		},
 		GoogleAuth: AuthProviderConfig{
 			Enabled: false,
 		},
@@ -132,6 +262,12 @@ func New() *Settings {
 		GiteeAuth: AuthProviderConfig{
 			Enabled: false,
 		},
+		OIDCAuth: AuthProviderConfig{
+			Enabled: false,
+		},
+		AppleAuth: AuthProviderConfig{
+			Enabled: false,
+		},
 	}
 }
 
@@ -149,8 +285,24 @@ func (s *Settings) Validate() error {
 		validation.Field(&s.RecordPasswordResetToken),
 		validation.Field(&s.RecordEmailChangeToken),
 		validation.Field(&s.RecordVerificationToken),
+		validation.Field(&s.RecordLoginLinkToken),
 		validation.Field(&s.Smtp),
+		validation.Field(&s.Sms),
 		validation.Field(&s.S3),
+		validation.Field(&s.Filter),
+		validation.Field(&s.RuleMacros),
+		validation.Field(&s.ErrorReporting),
+		validation.Field(&s.Alerts),
+		validation.Field(&s.Digest),
+		validation.Field(&s.Preferences),
+		validation.Field(&s.Client),
+		validation.Field(&s.Guardrails),
+		validation.Field(&s.TrustedProxy),
+		validation.Field(&s.LoginThrottle),
+		validation.Field(&s.SecurityHeaders),
+		validation.Field(&s.Signing),
+		validation.Field(&s.TokenSigning),
+		validation.Field(&s.AuthTokenClaims),
 		validation.Field(&s.GoogleAuth),
 		validation.Field(&s.FacebookAuth),
 		validation.Field(&s.GithubAuth),
@@ -163,6 +315,8 @@ func (s *Settings) Validate() error {
 		validation.Field(&s.TwitchAuth),
 		validation.Field(&s.StravaAuth),
 		validation.Field(&s.GiteeAuth),
+		validation.Field(&s.OIDCAuth, validation.By(checkOIDCAuthConfig)),
+		validation.Field(&s.AppleAuth),
 	)
 }
 
@@ -188,6 +342,9 @@ func (s *Settings) Clone() (*Settings, error) {
 	return clone, nil
 }
 
+// redactMask is the placeholder of the redacted secret values.
+const redactMask = "******"
+
 // RedactClone creates a new deep copy of the current settings,
 // while replacing the secret values with `******`.
 func (s *Settings) RedactClone() (*Settings, error) {
@@ -196,41 +353,90 @@ func (s *Settings) RedactClone() (*Settings, error) {
 		return nil, err
 	}
 
-	mask := "******"
-
-	sensitiveFields := []*string{
-		&clone.Smtp.Password,
-		&clone.S3.Secret,
-		&clone.AdminAuthToken.Secret,
-		&clone.AdminPasswordResetToken.Secret,
-		&clone.RecordAuthToken.Secret,
-		&clone.RecordPasswordResetToken.Secret,
-		&clone.RecordEmailChangeToken.Secret,
-		&clone.RecordVerificationToken.Secret,
-		&clone.GoogleAuth.ClientSecret,
-		&clone.FacebookAuth.ClientSecret,
-		&clone.GithubAuth.ClientSecret,
-		&clone.GitlabAuth.ClientSecret,
-		&clone.DiscordAuth.ClientSecret,
-		&clone.TwitterAuth.ClientSecret,
-		&clone.MicrosoftAuth.ClientSecret,
-		&clone.SpotifyAuth.ClientSecret,
-		&clone.KakaoAuth.ClientSecret,
-		&clone.TwitchAuth.ClientSecret,
-		&clone.StravaAuth.ClientSecret,
-		&clone.GiteeAuth.ClientSecret,
+	sensitiveFields := clone.redactableFields()
+
+	for i := range clone.TokenSigning.Keys {
+		sensitiveFields = append(sensitiveFields, &clone.TokenSigning.Keys[i].PrivateKey)
 	}
 
 	// mask all sensitive fields
 	for _, v := range sensitiveFields {
 		if v != nil && *v != "" {
-			*v = mask
+			*v = redactMask
 		}
 	}
 
 	return clone, nil
 }
 
+// RestoreRedacted replaces the `******` masked secret values of the
+// current settings with the related ones from the original settings.
+//
+// This allows resubmitting a redacted settings copy (see [RedactClone])
+// without overwriting the stored secrets.
+func (s *Settings) RestoreRedacted(original *Settings) {
+	original.mux.RLock()
+	defer original.mux.RUnlock()
+
+	originalFields := original.redactableFields()
+
+	for i, v := range s.redactableFields() {
+		if *v == redactMask {
+			*v = *originalFields[i]
+		}
+	}
+
+	// the token signing keys could be added, removed or reordered
+	// so their private keys are matched by the key id
+	for i, key := range s.TokenSigning.Keys {
+		if key.PrivateKey != redactMask {
+			continue
+		}
+
+		for _, originalKey := range original.TokenSigning.Keys {
+			if originalKey.Id == key.Id {
+				s.TokenSigning.Keys[i].PrivateKey = originalKey.PrivateKey
+				break
+			}
+		}
+	}
+}
+
+// redactableFields returns pointers to the secret values of the settings
+// (without the token signing keys since their number could vary).
+func (s *Settings) redactableFields() []*string {
+	return []*string{
+		&s.Smtp.Password,
+		&s.Sms.ApiSecret,
+		&s.S3.Secret,
+		&s.ErrorReporting.Dsn,
+		&s.Alerts.WebhookUrl,
+		&s.Alerts.SlackWebhookUrl,
+		&s.Signing.Secret,
+		&s.AdminAuthToken.Secret,
+		&s.AdminPasswordResetToken.Secret,
+		&s.RecordAuthToken.Secret,
+		&s.RecordPasswordResetToken.Secret,
+		&s.RecordEmailChangeToken.Secret,
+		&s.RecordVerificationToken.Secret,
+		&s.RecordLoginLinkToken.Secret,
+		&s.GoogleAuth.ClientSecret,
+		&s.FacebookAuth.ClientSecret,
+		&s.GithubAuth.ClientSecret,
+		&s.GitlabAuth.ClientSecret,
+		&s.DiscordAuth.ClientSecret,
+		&s.TwitterAuth.ClientSecret,
+		&s.MicrosoftAuth.ClientSecret,
+		&s.SpotifyAuth.ClientSecret,
+		&s.KakaoAuth.ClientSecret,
+		&s.TwitchAuth.ClientSecret,
+		&s.StravaAuth.ClientSecret,
+		&s.GiteeAuth.ClientSecret,
+		&s.OIDCAuth.ClientSecret,
+		&s.AppleAuth.ClientSecret,
+	}
+}
+
 // NamedAuthProviderConfigs returns a map with all registered OAuth2
 // provider configurations (indexed by their name identifier).
 func (s *Settings) NamedAuthProviderConfigs() map[string]AuthProviderConfig {
@@ -250,9 +456,25 @@ func (s *Settings) NamedAuthProviderConfigs() map[string]AuthProviderConfig {
 		auth.NameTwitch:    s.TwitchAuth,
 		auth.NameStrava:    s.StravaAuth,
 		auth.NameGitee:     s.GiteeAuth,
+		auth.NameOIDC:      s.OIDCAuth,
+		auth.NameApple:     s.AppleAuth,
 	}
 }
 
+// RotateTokenSigningKeys rotates the auth tokens signing keys
+// retaining the retired ones for the max auth tokens duration.
+func (s *Settings) RotateTokenSigningKeys() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	retention := s.AdminAuthToken.Duration
+	if s.RecordAuthToken.Duration > retention {
+		retention = s.RecordAuthToken.Duration
+	}
+
+	return s.TokenSigning.Rotate(time.Duration(retention) * time.Second)
+}
+
 // -------------------------------------------------------------------
 
 type TokenConfig struct {
@@ -312,6 +534,63 @@ func (c SmtpConfig) Validate() error {
 
 // -------------------------------------------------------------------
 
+// SmsConfig defines the SMS gateway used to deliver
+// the phone auth one-time passwords.
+type SmsConfig struct {
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// Provider is the SMS gateway provider (twilio or vonage).
+	Provider string `form:"provider" json:"provider"`
+
+	// ApiKey is the Twilio account SID or the Vonage api key.
+	ApiKey string `form:"apiKey" json:"apiKey"`
+
+	// ApiSecret is the Twilio auth token or the Vonage api secret.
+	ApiSecret string `form:"apiSecret" json:"apiSecret"`
+
+	// From is the sender phone number or alphanumeric sender id.
+	From string `form:"from" json:"from"`
+
+	// OtpTemplate is the one-time password message template
+	// (supports the {APP_NAME} and {CODE} placeholders).
+	OtpTemplate string `form:"otpTemplate" json:"otpTemplate"`
+
+	// OtpDuration specifies how long (in seconds) the sent
+	// one-time password is valid.
+	OtpDuration int64 `form:"otpDuration" json:"otpDuration"`
+}
+
+// Validate makes SmsConfig validatable by implementing [validation.Validatable] interface.
+func (c SmsConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(
+			&c.Provider,
+			validation.When(c.Enabled, validation.Required),
+			validation.In(sms.ProviderTwilio, sms.ProviderVonage),
+		),
+		validation.Field(&c.ApiKey, validation.When(c.Enabled, validation.Required)),
+		validation.Field(&c.ApiSecret, validation.When(c.Enabled, validation.Required)),
+		validation.Field(&c.From, validation.When(c.Enabled, validation.Required)),
+		validation.Field(
+			&c.OtpTemplate,
+			validation.Required,
+			validation.By(checkPlaceholderParams(SmsPlaceholderCode)),
+		),
+		validation.Field(&c.OtpDuration, validation.Required, validation.Min(30), validation.Max(86400)),
+	)
+}
+
+// ResolveOtpTemplate replaces the placeholder parameters
+// in the current OtpTemplate with the provided ones.
+func (c SmsConfig) ResolveOtpTemplate(appName string, code string) string {
+	return strings.NewReplacer(
+		EmailPlaceholderAppName, appName,
+		SmsPlaceholderCode, code,
+	).Replace(c.OtpTemplate)
+}
+
+// -------------------------------------------------------------------
+
 type S3Config struct {
 	Enabled        bool   `form:"enabled" json:"enabled"`
 	Bucket         string `form:"bucket" json:"bucket"`
@@ -344,6 +623,7 @@ type MetaConfig struct {
 	VerificationTemplate       EmailTemplate `form:"verificationTemplate" json:"verificationTemplate"`
 	ResetPasswordTemplate      EmailTemplate `form:"resetPasswordTemplate" json:"resetPasswordTemplate"`
 	ConfirmEmailChangeTemplate EmailTemplate `form:"confirmEmailChangeTemplate" json:"confirmEmailChangeTemplate"`
+	LoginLinkTemplate          EmailTemplate `form:"loginLinkTemplate" json:"loginLinkTemplate"`
 }
 
 // Validate makes MetaConfig validatable by implementing [validation.Validatable] interface.
@@ -356,6 +636,7 @@ func (c MetaConfig) Validate() error {
 		validation.Field(&c.VerificationTemplate, validation.Required),
 		validation.Field(&c.ResetPasswordTemplate, validation.Required),
 		validation.Field(&c.ConfirmEmailChangeTemplate, validation.Required),
+		validation.Field(&c.LoginLinkTemplate, validation.Required),
 	)
 }
 
@@ -458,6 +739,936 @@ func (c LogsConfig) Validate() error {
 
 // -------------------------------------------------------------------
 
+type FilterConfig struct {
+	// MaxNestedRels is the max allowed number of nested relations
+	// in a single client filter or sort field (0 means no limit).
+	MaxNestedRels int `form:"maxNestedRels" json:"maxNestedRels"`
+
+	// MaxJoins is the max allowed number of table joins that a single
+	// records list request could generate (0 means no limit).
+	MaxJoins int `form:"maxJoins" json:"maxJoins"`
+}
+
+// Validate makes FilterConfig validatable by implementing [validation.Validatable] interface.
+func (c FilterConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.MaxNestedRels, validation.Min(0)),
+		validation.Field(&c.MaxJoins, validation.Min(0)),
+	)
+}
+
+// -------------------------------------------------------------------
+
+type JsonConfig struct {
+	// BigIntStrings converts the integers of the submitted json field
+	// values that cannot be represented exactly as float64 to strings
+	// (as if the BigIntStrings option of all json fields is enabled).
+	BigIntStrings bool `form:"bigIntStrings" json:"bigIntStrings"`
+}
+
+// -------------------------------------------------------------------
+
+type ErrorReportingConfig struct {
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// Dsn is the Sentry compatible project client key url
+	// (eg. "https://public_key@sentry.example.com/123").
+	Dsn string `form:"dsn" json:"dsn"`
+
+	Environment string `form:"environment" json:"environment"`
+
+	// SlowThreshold is the request duration (in ms) after which the
+	// request is reported as slow (0 means no slow requests reporting).
+	SlowThreshold int `form:"slowThreshold" json:"slowThreshold"`
+
+	// SendPii allows sending the personally identifiable information
+	// (request ip, authorization and cookie headers, auth id, etc.).
+	SendPii bool `form:"sendPii" json:"sendPii"`
+
+	// ScrubFields is a list with additional (case-insensitive) request
+	// headers, query parameters, tags and extra keys to scrub.
+	ScrubFields []string `form:"scrubFields" json:"scrubFields"`
+}
+
+// Validate makes ErrorReportingConfig validatable by implementing [validation.Validatable] interface.
+func (c ErrorReportingConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(
+			&c.Dsn,
+			validation.When(c.Enabled, validation.Required),
+			validation.By(checkSentryDsn),
+		),
+		validation.Field(&c.Environment, validation.Length(0, 64)),
+		validation.Field(&c.SlowThreshold, validation.Min(0)),
+	)
+}
+
+func checkSentryDsn(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	if _, _, err := reporter.ParseSentryDsn(v); err != nil {
+		return validation.NewError("validation_invalid_dsn", "Invalid Sentry compatible DSN.")
+	}
+
+	return nil
+}
+
+// -------------------------------------------------------------------
+
+type AlertsConfig struct {
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// Emails is a list with the alerts email recipients.
+	Emails []string `form:"emails" json:"emails"`
+
+	// WebhookUrl is an optional url where the alerts will be POST-ed as JSON.
+	WebhookUrl string `form:"webhookUrl" json:"webhookUrl"`
+
+	// WebhookTemplate is an optional Go template used to shape the
+	// webhook JSON payload (see [alert.ParseTemplate]).
+	WebhookTemplate string `form:"webhookTemplate" json:"webhookTemplate"`
+
+	// SlackWebhookUrl is an optional Slack compatible incoming webhook url.
+	SlackWebhookUrl string `form:"slackWebhookUrl" json:"slackWebhookUrl"`
+
+	// Cooldown is the min interval (in minutes) between two alerts of the same type.
+	Cooldown int `form:"cooldown" json:"cooldown"`
+
+	// ErrorsThreshold is the number of 5xx API errors within ErrorsWindow
+	// (in minutes) that triggers an alert (0 disables the errors alerts).
+	ErrorsThreshold int `form:"errorsThreshold" json:"errorsThreshold"`
+	ErrorsWindow    int `form:"errorsWindow" json:"errorsWindow"`
+}
+
+// Validate makes AlertsConfig validatable by implementing [validation.Validatable] interface.
+func (c AlertsConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(
+			&c.Emails,
+			validation.When(c.Enabled && c.WebhookUrl == "" && c.SlackWebhookUrl == "", validation.Required),
+			validation.Each(is.EmailFormat),
+		),
+		validation.Field(&c.WebhookUrl, is.URL),
+		validation.Field(&c.WebhookTemplate, validation.By(checkAlertTemplate)),
+		validation.Field(&c.SlackWebhookUrl, is.URL),
+		validation.Field(&c.Cooldown, validation.Min(0)),
+		validation.Field(&c.ErrorsThreshold, validation.Min(0)),
+		validation.Field(
+			&c.ErrorsWindow,
+			validation.When(c.ErrorsThreshold > 0, validation.Required),
+			validation.Min(0),
+		),
+	)
+}
+
+func checkAlertTemplate(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	sample := alert.New("test", "Test subject", "Test message")
+
+	if _, err := alert.RenderTemplate(v, sample); err != nil {
+		return validation.NewError("validation_invalid_template", err.Error())
+	}
+
+	return nil
+}
+
+// -------------------------------------------------------------------
+
+// Supported auth record digest frequency preferences.
+const (
+	DigestFrequencyNever  string = "never"
+	DigestFrequencyHourly string = "hourly"
+	DigestFrequencyDaily  string = "daily"
+	DigestFrequencyWeekly string = "weekly"
+)
+
+type DigestConfig struct {
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// Window is the default interval (in minutes) within which the queued
+	// digest items of a single recipient are coalesced into one email.
+	Window int `form:"window" json:"window"`
+
+	// FrequencyField is an optional auth collections schema field name
+	// that stores the records digest frequency preference
+	// (one of the DigestFrequency* constants; empty fallbacks to Window).
+	FrequencyField string `form:"frequencyField" json:"frequencyField"`
+
+	// Subject is the digest email subject (supports the {APP_NAME} and {APP_URL} placeholders).
+	Subject string `form:"subject" json:"subject"`
+
+	// Body is the digest email html body (supports the {APP_NAME} and {APP_URL}
+	// placeholders) that is executed as Go html template with access to the
+	// recipient .Record and the queued .Items (each with .Subject, .Message, .Data and .Created).
+	Body string `form:"body" json:"body"`
+}
+
+// Validate makes DigestConfig validatable by implementing [validation.Validatable] interface.
+func (c DigestConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Window, validation.When(c.Enabled, validation.Required), validation.Min(0)),
+		validation.Field(&c.FrequencyField, validation.Length(0, 255)),
+		validation.Field(&c.Subject, validation.When(c.Enabled, validation.Required)),
+		validation.Field(&c.Body, validation.When(c.Enabled, validation.Required), validation.By(checkDigestBody)),
+	)
+}
+
+// FrequencyWindow returns the digest window for the specified
+// frequency preference and false if the digests are not wanted.
+func (c DigestConfig) FrequencyWindow(frequency string) (time.Duration, bool) {
+	switch frequency {
+	case DigestFrequencyNever:
+		return 0, false
+	case DigestFrequencyHourly:
+		return time.Hour, true
+	case DigestFrequencyDaily:
+		return 24 * time.Hour, true
+	case DigestFrequencyWeekly:
+		return 7 * 24 * time.Hour, true
+	default:
+		return time.Duration(c.Window) * time.Minute, true
+	}
+}
+
+func checkDigestBody(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	if _, err := htmltemplate.New("digest").Parse(v); err != nil {
+		return validation.NewError("validation_invalid_template", err.Error())
+	}
+
+	return nil
+}
+
+// -------------------------------------------------------------------
+
+var preferenceKeyRegex = regexp.MustCompile(`^\w+$`)
+
+// Supported auth record preference types.
+const (
+	PreferenceTypeText   string = "text"
+	PreferenceTypeNumber string = "number"
+	PreferenceTypeBool   string = "bool"
+	PreferenceTypeSelect string = "select"
+)
+
+// PreferencesConfig defines the typed auth records preferences
+// (eg. theme, locale, notifications toggles) that the auth records
+// could manage themselves via the preferences api.
+type PreferencesConfig struct {
+	Keys []PreferenceKey `form:"keys" json:"keys"`
+}
+
+// Validate makes PreferencesConfig validatable by implementing [validation.Validatable] interface.
+func (c PreferencesConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Keys, validation.By(checkUniquePreferenceKeys)),
+	)
+}
+
+// FindKey returns the preference key declaration with the
+// specified name or nil if there is no such key.
+func (c PreferencesConfig) FindKey(name string) *PreferenceKey {
+	for i := range c.Keys {
+		if c.Keys[i].Key == name {
+			return &c.Keys[i]
+		}
+	}
+
+	return nil
+}
+
+// Resolve returns the resolved preferences of a single auth record
+// by merging the provided stored values with the keys defaults.
+//
+// Unknown or no longer valid stored values are ignored.
+func (c PreferencesConfig) Resolve(stored map[string]any) map[string]any {
+	result := make(map[string]any, len(c.Keys))
+
+	for _, key := range c.Keys {
+		result[key.Key] = key.Default
+
+		if raw, ok := stored[key.Key]; ok && raw != nil {
+			if v, err := key.NormalizeValue(raw); err == nil {
+				result[key.Key] = v
+			}
+		}
+	}
+
+	return result
+}
+
+func checkUniquePreferenceKeys(value any) error {
+	v, _ := value.([]PreferenceKey)
+
+	existing := map[string]struct{}{}
+	for i, key := range v {
+		if _, ok := existing[key.Key]; ok {
+			return validation.Errors{strconv.Itoa(i): validation.Errors{"key": validation.NewError(
+				"validation_duplicated_preference_key",
+				"The preference key must be unique.",
+			)}}
+		}
+		existing[key.Key] = struct{}{}
+	}
+
+	return nil
+}
+
+// PreferenceKey defines a single typed auth record preference.
+type PreferenceKey struct {
+	Key  string `form:"key" json:"key"`
+	Type string `form:"type" json:"type"`
+
+	// Values is the list of the allowed "select" preference values.
+	Values []string `form:"values" json:"values"`
+
+	// Default is the preference value when the auth record
+	// doesn't have explicitly stored one (nil means no default).
+	Default any `form:"default" json:"default"`
+}
+
+// Validate makes PreferenceKey validatable by implementing [validation.Validatable] interface.
+func (k PreferenceKey) Validate() error {
+	return validation.ValidateStruct(&k,
+		validation.Field(
+			&k.Key,
+			validation.Required,
+			validation.Length(1, 100),
+			validation.Match(preferenceKeyRegex),
+		),
+		validation.Field(
+			&k.Type,
+			validation.Required,
+			validation.In(
+				PreferenceTypeText,
+				PreferenceTypeNumber,
+				PreferenceTypeBool,
+				PreferenceTypeSelect,
+			),
+		),
+		validation.Field(
+			&k.Values,
+			validation.When(k.Type == PreferenceTypeSelect, validation.Required),
+			validation.Each(validation.Required),
+		),
+		validation.Field(&k.Default, validation.By(k.checkDefault)),
+	)
+}
+
+func (k PreferenceKey) checkDefault(value any) error {
+	if value == nil {
+		return nil // no default
+	}
+
+	if _, err := k.NormalizeValue(value); err != nil {
+		return validation.NewError("validation_invalid_preference_default", err.Error())
+	}
+
+	return nil
+}
+
+// NormalizeValue checks whether the provided value matches the
+// preference type and returns its normalized form (eg. int -> float64).
+func (k PreferenceKey) NormalizeValue(value any) (any, error) {
+	switch k.Type {
+	case PreferenceTypeText:
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+		return nil, fmt.Errorf("The %q preference must be a string.", k.Key)
+	case PreferenceTypeNumber:
+		switch value.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, json.Number:
+			v, err := cast.ToFloat64E(value)
+			if err == nil && !math.IsNaN(v) && !math.IsInf(v, 0) {
+				return v, nil
+			}
+		}
+		return nil, fmt.Errorf("The %q preference must be a number.", k.Key)
+	case PreferenceTypeBool:
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+		return nil, fmt.Errorf("The %q preference must be a boolean.", k.Key)
+	case PreferenceTypeSelect:
+		if v, ok := value.(string); ok && list.ExistInSlice(v, k.Values) {
+			return v, nil
+		}
+		return nil, fmt.Errorf("The %q preference must be one of %v.", k.Key, k.Values)
+	}
+
+	return nil, fmt.Errorf("Unsupported preference type %q.", k.Type)
+}
+
+// -------------------------------------------------------------------
+
+var clientConfigKeyRegex = regexp.MustCompile(`^\w+$`)
+
+var clientVersionRegex = regexp.MustCompile(`^v?\d+(\.\d+){0,2}([-+][\w.-]+)?$`)
+
+// ClientConfig defines the admin curated public configuration that
+// the client applications could load with a single app-config api request.
+//
+// NB! All of its values are publicly readable so it must not contain secrets.
+type ClientConfig struct {
+	// MinVersion is the min supported client application version
+	// (eg. "1.2.0"; empty value means that all versions are supported).
+	MinVersion string `form:"minVersion" json:"minVersion"`
+
+	// Flags is a map with the client feature flags toggles.
+	Flags map[string]bool `form:"flags" json:"flags"`
+
+	// Public is a map with arbitrary public client settings
+	// (eg. support email, terms url, etc.).
+	Public map[string]any `form:"public" json:"public"`
+}
+
+// Validate makes ClientConfig validatable by implementing [validation.Validatable] interface.
+func (c ClientConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.MinVersion, validation.Length(0, 50), validation.Match(clientVersionRegex)),
+		validation.Field(&c.Flags, validation.By(checkClientConfigKeys)),
+		validation.Field(&c.Public, validation.By(checkClientConfigKeys)),
+	)
+}
+
+func checkClientConfigKeys(value any) error {
+	var keys []string
+
+	switch v := value.(type) {
+	case map[string]bool:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]any:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	}
+
+	for _, k := range keys {
+		if len(k) > 100 || !clientConfigKeyRegex.MatchString(k) {
+			return validation.NewError(
+				"validation_invalid_client_config_key",
+				fmt.Sprintf("Invalid key %q - only alphanumeric and underscore characters are allowed (max 100).", k),
+			)
+		}
+	}
+
+	return nil
+}
+
+// -------------------------------------------------------------------
+
+var ruleMacroNameRegex = regexp.MustCompile(`^\w+$`)
+
+// RuleMacrosConfig defines the app level named rule fragments that
+// could be referenced in the collections API rules as "@macro.name".
+//
+// The collection level macros with the same name take precedence.
+type RuleMacrosConfig struct {
+	Macros []RuleMacro `form:"macros" json:"macros"`
+}
+
+// Validate makes RuleMacrosConfig validatable by implementing [validation.Validatable] interface.
+func (c RuleMacrosConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Macros, validation.By(checkUniqueRuleMacros)),
+	)
+}
+
+// Map returns the macros as name => rule map.
+func (c RuleMacrosConfig) Map() map[string]string {
+	result := make(map[string]string, len(c.Macros))
+
+	for _, macro := range c.Macros {
+		result[macro.Name] = macro.Rule
+	}
+
+	return result
+}
+
+func checkUniqueRuleMacros(value any) error {
+	v, _ := value.([]RuleMacro)
+
+	existing := map[string]struct{}{}
+	for i, macro := range v {
+		if _, ok := existing[macro.Name]; ok {
+			return validation.Errors{strconv.Itoa(i): validation.Errors{"name": validation.NewError(
+				"validation_duplicated_macro_name",
+				"The macro name must be unique.",
+			)}}
+		}
+		existing[macro.Name] = struct{}{}
+	}
+
+	return nil
+}
+
+// RuleMacro defines a single named rule fragment
+// (eg. Name: "isOwner", Rule: "author = @request.auth.id").
+type RuleMacro struct {
+	Name string `form:"name" json:"name"`
+	Rule string `form:"rule" json:"rule"`
+}
+
+// Validate makes RuleMacro validatable by implementing [validation.Validatable] interface.
+func (m RuleMacro) Validate() error {
+	return validation.ValidateStruct(&m,
+		validation.Field(
+			&m.Name,
+			validation.Required,
+			validation.Length(1, 100),
+			validation.Match(ruleMacroNameRegex),
+		),
+		validation.Field(&m.Rule, validation.Required),
+	)
+}
+
+// -------------------------------------------------------------------
+
+type GuardrailsConfig struct {
+	// MinFreeDisk is the min free disk space (in MB) of the app
+	// data directory (0 disables the free disk space check).
+	MinFreeDisk int `form:"minFreeDisk" json:"minFreeDisk"`
+
+	// MaxDataSize is the max size (in MB) of the app data db
+	// (0 disables the data db size check).
+	MaxDataSize int `form:"maxDataSize" json:"maxDataSize"`
+
+	// ReadOnly switches the instance to read-only mode (aka. rejects all
+	// write API requests) while any of the thresholds is exceeded.
+	ReadOnly bool `form:"readOnly" json:"readOnly"`
+}
+
+// Validate makes GuardrailsConfig validatable by implementing [validation.Validatable] interface.
+func (c GuardrailsConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.MinFreeDisk, validation.Min(0)),
+		validation.Field(&c.MaxDataSize, validation.Min(0)),
+	)
+}
+
+// -------------------------------------------------------------------
+
+// TrustedProxyConfig defines the reverse proxy headers that are trusted
+// to contain the real client ip (used for example by the per ip limits).
+//
+// If no headers are set, the request remote address is used.
+type TrustedProxyConfig struct {
+	// Headers is a list of client ip headers that are set by the
+	// trusted reverse proxy (eg. "X-Real-IP", "X-Forwarded-For").
+	//
+	// The first nonempty header value is used.
+	Headers []string `form:"headers" json:"headers"`
+
+	// UseLeftmostIp specifies to use the leftmost (aka. the original
+	// client) ip of a multi ip header value instead of the rightmost one.
+	UseLeftmostIp bool `form:"useLeftmostIp" json:"useLeftmostIp"`
+}
+
+// Validate makes TrustedProxyConfig validatable by implementing [validation.Validatable] interface.
+func (c TrustedProxyConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Headers, validation.Each(validation.Required, validation.Length(1, 100))),
+	)
+}
+
+// -------------------------------------------------------------------
+
+// LoginThrottleConfig defines the brute-force protection of the
+// admin and auth records password logins.
+type LoginThrottleConfig struct {
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// MaxIdentityFailures is the number of consecutive failed login
+	// attempts for a single login identity (eg. email) before its
+	// temporary lockout (0 disables the per identity lockout).
+	MaxIdentityFailures int `form:"maxIdentityFailures" json:"maxIdentityFailures"`
+
+	// MaxIpFailures is the number of consecutive failed login attempts
+	// from a single client ip before its temporary lockout
+	// (0 disables the per ip lockout).
+	MaxIpFailures int `form:"maxIpFailures" json:"maxIpFailures"`
+
+	// Window is the period in seconds without failed login attempts
+	// after which the failures and lockouts counters are reset.
+	Window int64 `form:"window" json:"window"`
+
+	// LockoutDuration is the duration in seconds of the first lockout.
+	LockoutDuration int64 `form:"lockoutDuration" json:"lockoutDuration"`
+
+	// MaxLockoutDuration is the max duration in seconds of the lockouts.
+	//
+	// If greater than LockoutDuration, the duration of each consecutive
+	// lockout is doubled up to MaxLockoutDuration (aka. exponential backoff).
+	MaxLockoutDuration int64 `form:"maxLockoutDuration" json:"maxLockoutDuration"`
+}
+
+// Validate makes LoginThrottleConfig validatable by implementing [validation.Validatable] interface.
+func (c LoginThrottleConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.MaxIdentityFailures, validation.Min(0)),
+		validation.Field(&c.MaxIpFailures, validation.Min(0)),
+		validation.Field(&c.Window, validation.When(c.Enabled, validation.Required), validation.Min(int64(0))),
+		validation.Field(&c.LockoutDuration, validation.When(c.Enabled, validation.Required), validation.Min(int64(0))),
+		validation.Field(&c.MaxLockoutDuration, validation.Min(int64(0))),
+	)
+}
+
+// LockoutDurationFor returns the duration of the n-th (starting from 1)
+// consecutive lockout.
+func (c LoginThrottleConfig) LockoutDurationFor(n int) time.Duration {
+	seconds := c.LockoutDuration
+
+	for i := 1; i < n && seconds < c.MaxLockoutDuration; i++ {
+		seconds *= 2
+	}
+
+	if c.MaxLockoutDuration > c.LockoutDuration && seconds > c.MaxLockoutDuration {
+		seconds = c.MaxLockoutDuration
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+// -------------------------------------------------------------------
+
+type SigningConfig struct {
+	// Enabled signs the outbound alert webhook payloads and
+	// the export files with the HMAC-SHA256 Secret.
+	//
+	// The signatures could be verified with the tools/signature package.
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	Secret string `form:"secret" json:"secret"`
+}
+
+// Validate makes SigningConfig validatable by implementing [validation.Validatable] interface.
+func (c SigningConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(
+			&c.Secret,
+			validation.When(c.Enabled, validation.Required),
+			validation.Length(30, 300),
+		),
+	)
+}
+
+// -------------------------------------------------------------------
+
+// TokenSigningKeyBits is the size of the generated RS256 token signing keys.
+const TokenSigningKeyBits = 2048
+
+const (
+	TokenSigningAlgorithmRS256 = "RS256"
+	TokenSigningAlgorithmEdDSA = "EdDSA"
+)
+
+type TokenSigningConfig struct {
+	// Enabled signs the admin and record auth tokens with the active
+	// asymmetric key instead of the HMAC token secrets, allowing external
+	// services to verify the tokens with the published JWKS.
+	//
+	// The HMAC signed tokens issued before enabling the option remain valid.
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// Algorithm is the signing algorithm of the newly generated keys
+	// ("RS256" or "EdDSA"; empty value fallbacks to "RS256").
+	Algorithm string `form:"algorithm" json:"algorithm"`
+
+	// Keys is the list with the current and the retired signing keys
+	// (the last not retired key is the active one).
+	Keys []TokenSigningKey `form:"keys" json:"keys"`
+}
+
+// Validate makes TokenSigningConfig validatable by implementing [validation.Validatable] interface.
+func (c TokenSigningConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(
+			&c.Algorithm,
+			validation.In(TokenSigningAlgorithmRS256, TokenSigningAlgorithmEdDSA),
+		),
+		validation.Field(
+			&c.Keys,
+			validation.When(c.Enabled, validation.Required, validation.By(c.checkActiveKey)),
+		),
+	)
+}
+
+func (c TokenSigningConfig) checkActiveKey(value any) error {
+	if c.ActiveKey() == nil {
+		return validation.NewError("validation_missing_active_key", "Missing active (not retired) signing key.")
+	}
+
+	return nil
+}
+
+// ActiveKey returns the key used for signing the new tokens
+// (aka. the last not retired key) or nil if there is no such key.
+func (c TokenSigningConfig) ActiveKey() *TokenSigningKey {
+	for i := len(c.Keys) - 1; i >= 0; i-- {
+		if c.Keys[i].Retired.IsZero() {
+			return &c.Keys[i]
+		}
+	}
+
+	return nil
+}
+
+// FindKey returns the signing key with the specified id or nil if missing.
+func (c TokenSigningConfig) FindKey(id string) *TokenSigningKey {
+	for i := range c.Keys {
+		if c.Keys[i].Id == id {
+			return &c.Keys[i]
+		}
+	}
+
+	return nil
+}
+
+// Rotate retires the current active key, generates a new active one
+// with the configured algorithm and removes the keys retired more
+// than retention time ago.
+//
+// The retention should be at least the max signed tokens duration
+// so that the tokens issued with a retired key remain verifiable
+// until their expiration (aka. overlapping validity).
+func (c *TokenSigningConfig) Rotate(retention time.Duration) error {
+	var privateKey string
+	var err error
+	if c.Algorithm == TokenSigningAlgorithmEdDSA {
+		privateKey, err = security.GenerateEd25519PrivateKeyPEM()
+	} else {
+		privateKey, err = security.GenerateRSAPrivateKeyPEM(TokenSigningKeyBits)
+	}
+	if err != nil {
+		return err
+	}
+
+	now := types.NowDateTime()
+
+	keys := make([]TokenSigningKey, 0, len(c.Keys)+1)
+	for _, key := range c.Keys {
+		if key.Retired.IsZero() {
+			key.Retired = now
+		}
+
+		if key.Retired.Time().Add(retention).After(now.Time()) {
+			keys = append(keys, key)
+		}
+	}
+
+	keys = append(keys, TokenSigningKey{
+		Id:         security.RandomString(15),
+		PrivateKey: privateKey,
+		Created:    now,
+	})
+
+	c.Keys = keys
+
+	return nil
+}
+
+type TokenSigningKey struct {
+	Id         string         `form:"id" json:"id"`
+	PrivateKey string         `form:"privateKey" json:"privateKey"`
+	Created    types.DateTime `form:"created" json:"created"`
+	Retired    types.DateTime `form:"retired" json:"retired"`
+}
+
+// Validate makes TokenSigningKey validatable by implementing [validation.Validatable] interface.
+func (k TokenSigningKey) Validate() error {
+	return validation.ValidateStruct(&k,
+		validation.Field(&k.Id, validation.Required),
+		validation.Field(&k.PrivateKey, validation.Required, validation.By(checkSigningPrivateKey)),
+	)
+}
+
+// Signer parses and returns the key PEM encoded RSA or Ed25519 private key.
+func (k TokenSigningKey) Signer() (crypto.Signer, error) {
+	return security.ParsePrivateKeyPEM(k.PrivateKey)
+}
+
+// Algorithm returns the key signing algorithm ("RS256" or "EdDSA")
+// or empty string if the key is invalid.
+func (k TokenSigningKey) Algorithm() string {
+	signer, err := k.Signer()
+	if err != nil {
+		return ""
+	}
+
+	method, err := security.AsymmetricSigningMethod(signer)
+	if err != nil {
+		return ""
+	}
+
+	return method.Alg()
+}
+
+func checkSigningPrivateKey(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	if _, err := security.ParsePrivateKeyPEM(v); err != nil {
+		return validation.NewError("validation_invalid_private_key", "Must be a valid PEM encoded RSA or Ed25519 private key.")
+	}
+
+	return nil
+}
+
+// -------------------------------------------------------------------
+
+type AuthTokenClaimsConfig struct {
+	// Issuer is the "iss" claim of the issued admin and record auth tokens.
+	//
+	// If set, the auth tokens with different (or missing) "iss" claim are rejected.
+	Issuer string `form:"issuer" json:"issuer"`
+
+	// Audience is the "aud" claim of the issued admin and record auth tokens.
+	//
+	// If set, the auth tokens without at least one of the listed
+	// audiences in their "aud" claim are rejected.
+	Audience []string `form:"audience" json:"audience"`
+}
+
+// Validate makes AuthTokenClaimsConfig validatable by implementing [validation.Validatable] interface.
+func (c AuthTokenClaimsConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Issuer, validation.Length(0, 255)),
+		validation.Field(&c.Audience, validation.Each(validation.Required, validation.Length(1, 255))),
+	)
+}
+
+// -------------------------------------------------------------------
+
+type SecurityHeadersConfig struct {
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// Default is the headers policy of the responses
+	// that don't match the admin UI or any of the Routes.
+	Default SecurityHeadersPolicy `form:"default" json:"default"`
+
+	// AdminUI is the headers policy of the admin UI responses.
+	AdminUI SecurityHeadersPolicy `form:"adminUI" json:"adminUI"`
+
+	// Routes is a list with path prefix specific headers policies
+	// (eg. for the served static directories).
+	Routes []SecurityHeadersRoute `form:"routes" json:"routes"`
+}
+
+// Validate makes SecurityHeadersConfig validatable by implementing [validation.Validatable] interface.
+func (c SecurityHeadersConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Default),
+		validation.Field(&c.AdminUI),
+		validation.Field(&c.Routes),
+	)
+}
+
+// FindRoute returns the route with the longest path prefix
+// matching the provided request path (or nil if there is no match).
+func (c SecurityHeadersConfig) FindRoute(path string) *SecurityHeadersRoute {
+	var result *SecurityHeadersRoute
+
+	for i, route := range c.Routes {
+		if !route.Match(path) {
+			continue
+		}
+
+		if result == nil || len(route.Path) > len(result.Path) {
+			result = &c.Routes[i]
+		}
+	}
+
+	return result
+}
+
+type SecurityHeadersRoute struct {
+	// Path is the route path prefix (eg. "/app").
+	Path string `form:"path" json:"path"`
+
+	Policy SecurityHeadersPolicy `form:"policy" json:"policy"`
+}
+
+// Validate makes SecurityHeadersRoute validatable by implementing [validation.Validatable] interface.
+func (c SecurityHeadersRoute) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Path, validation.Required, validation.Match(securityHeadersPathRegex)),
+		validation.Field(&c.Policy),
+	)
+}
+
+// Match checks whether the route path prefix matches the provided
+// request path (eg. "/app" matches "/app" and "/app/test" but not "/apple").
+func (c SecurityHeadersRoute) Match(path string) bool {
+	prefix := strings.TrimRight(c.Path, "/")
+
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+var securityHeadersPathRegex = regexp.MustCompile(`^/[^\s]*$`)
+
+// headerValueRegex disallows line breaks and other control characters in the header values.
+var headerValueRegex = regexp.MustCompile(`^[^\x00-\x1f\x7f]*$`)
+
+type SecurityHeadersPolicy struct {
+	// ContentSecurityPolicy is the Content-Security-Policy header value
+	// (eg. "default-src 'self'"). Leave empty to not send the header.
+	ContentSecurityPolicy string `form:"contentSecurityPolicy" json:"contentSecurityPolicy"`
+
+	// HstsMaxAge is the Strict-Transport-Security max-age (in seconds).
+	// The header is sent only for https requests and it is disabled if zero.
+	HstsMaxAge            int  `form:"hstsMaxAge" json:"hstsMaxAge"`
+	HstsIncludeSubdomains bool `form:"hstsIncludeSubdomains" json:"hstsIncludeSubdomains"`
+
+	// XFrameOptions is the X-Frame-Options header value ("DENY" or "SAMEORIGIN").
+	// Leave empty to not send the header (eg. to allow embedding).
+	XFrameOptions string `form:"xFrameOptions" json:"xFrameOptions"`
+
+	// ReferrerPolicy is the Referrer-Policy header value.
+	// Leave empty to not send the header.
+	ReferrerPolicy string `form:"referrerPolicy" json:"referrerPolicy"`
+}
+
+// Validate makes SecurityHeadersPolicy validatable by implementing [validation.Validatable] interface.
+func (c SecurityHeadersPolicy) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(
+			&c.ContentSecurityPolicy,
+			validation.Length(0, 4096),
+			validation.Match(headerValueRegex),
+		),
+		validation.Field(&c.HstsMaxAge, validation.Min(0)),
+		validation.Field(&c.XFrameOptions, validation.In("DENY", "SAMEORIGIN")),
+		validation.Field(&c.ReferrerPolicy, validation.In(
+			"no-referrer",
+			"no-referrer-when-downgrade",
+			"origin",
+			"origin-when-cross-origin",
+			"same-origin",
+			"strict-origin",
+			"strict-origin-when-cross-origin",
+			"unsafe-url",
+		)),
+	)
+}
+
+// -------------------------------------------------------------------
+
 type AuthProviderConfig struct {
 	Enabled      bool   `form:"enabled" json:"enabled"`
 	ClientId     string `form:"clientId" json:"clientId,omitempty"`
@@ -465,6 +1676,20 @@ type AuthProviderConfig struct {
 	AuthUrl      string `form:"authUrl" json:"authUrl,omitempty"`
 	TokenUrl     string `form:"tokenUrl" json:"tokenUrl,omitempty"`
 	UserApiUrl   string `form:"userApiUrl" json:"userApiUrl,omitempty"`
+
+	// Issuer is the OpenID Connect issuer url used to discover the
+	// provider endpoints (the explicitly set urls have priority).
+	Issuer string `form:"issuer" json:"issuer,omitempty"`
+
+	// FieldsMapping maps the provider user data keys (aka. claims) to
+	// collection schema field names that are synced with each OAuth2 login.
+	//
+	// Nested claims could be mapped with dot-notation (eg. "address.country").
+	FieldsMapping map[string]string `form:"fieldsMapping" json:"fieldsMapping,omitempty"`
+
+	// RolesClaim is the optional provider user data key (dot-notation is
+	// supported) with the role names that are synced with each OAuth2 login.
+	RolesClaim string `form:"rolesClaim" json:"rolesClaim,omitempty"`
 }
 
 // Validate makes `ProviderConfig` validatable by implementing [validation.Validatable] interface.
@@ -475,9 +1700,38 @@ func (c AuthProviderConfig) Validate() error {
 		validation.Field(&c.AuthUrl, is.URL),
 		validation.Field(&c.TokenUrl, is.URL),
 		validation.Field(&c.UserApiUrl, is.URL),
+		validation.Field(&c.Issuer, is.URL),
+		validation.Field(&c.FieldsMapping, validation.By(checkFieldsMapping)),
+		validation.Field(&c.RolesClaim, validation.Length(0, 255)),
 	)
 }
 
+func checkFieldsMapping(value any) error {
+	v, _ := value.(map[string]string)
+
+	for claim, field := range v {
+		if claim == "" || len(claim) > 255 || field == "" || len(field) > 255 {
+			return validation.NewError("validation_invalid_fields_mapping", "Invalid or empty claim or field name.")
+		}
+	}
+
+	return nil
+}
+
+// checkOIDCAuthConfig checks whether the enabled OpenID Connect
+// provider has an issuer or explicitly set endpoint urls.
+func checkOIDCAuthConfig(value any) error {
+	v, _ := value.(AuthProviderConfig)
+
+	if !v.Enabled || v.Issuer != "" || (v.AuthUrl != "" && v.TokenUrl != "" && v.UserApiUrl != "") {
+		return nil
+	}
+
+	return validation.Errors{
+		"issuer": validation.ErrRequired,
+	}
+}
+
 // SetupProvider loads the current AuthProviderConfig into the specified provider.
 func (c AuthProviderConfig) SetupProvider(provider auth.Provider) error {
 	if !c.Enabled {
@@ -492,6 +1746,14 @@ func (c AuthProviderConfig) SetupProvider(provider auth.Provider) error {
 		provider.SetClientSecret(c.ClientSecret)
 	}
 
+	if c.Issuer != "" {
+		if p, ok := provider.(interface{ Discover(issuer string) error }); ok {
+			if err := p.Discover(c.Issuer); err != nil {
+				return err
+			}
+		}
+	}
+
 	if c.AuthUrl != "" {
 		provider.SetAuthUrl(c.AuthUrl)
 	}
@@ -6,8 +6,11 @@ const (
 	EmailPlaceholderAppUrl    string = "{APP_URL}"
 	EmailPlaceholderToken     string = "{TOKEN}"
 	EmailPlaceholderActionUrl string = "{ACTION_URL}"
+	SmsPlaceholderCode        string = "{CODE}"
 )
 
+var defaultSmsOtpTemplate = "Your " + EmailPlaceholderAppName + " verification code is " + SmsPlaceholderCode + "."
+
 var defaultVerificationTemplate = EmailTemplate{
 	Subject: "Verify your " + EmailPlaceholderAppName + " email",
 	Body: `<p>Hello,</p>
@@ -52,3 +55,36 @@ var defaultConfirmEmailChangeTemplate = EmailTemplate{
 </p>`,
 	ActionUrl: EmailPlaceholderAppUrl + "/_/#/auth/confirm-email-change/" + EmailPlaceholderToken,
 }
+
+var defaultLoginLinkTemplate = EmailTemplate{
+	Subject: "Sign in to " + EmailPlaceholderAppName,
+	Body: `<p>Hello,</p>
+<p>Click on the button below to sign in to ` + EmailPlaceholderAppName + `.</p>
+<p>
+  <a class="btn" href="` + EmailPlaceholderActionUrl + `" target="_blank" rel="noopener">Sign in</a>
+</p>
+<p>Or copy and paste the following one-time code:</p>
+<p><code>` + EmailPlaceholderToken + `</code></p>
+<p><i>If you didn't ask to sign in, you can ignore this email.</i></p>
+<p>
+  Thanks,<br/>
+  ` + EmailPlaceholderAppName + ` team
+</p>`,
+	ActionUrl: EmailPlaceholderAppUrl + "/_/#/auth/confirm-login-link/" + EmailPlaceholderToken,
+}
+
+var defaultDigestTemplate = EmailTemplate{
+	Subject: "Your " + EmailPlaceholderAppName + " updates",
+	Body: `<p>Hello,</p>
+<p>Here is what happened since your last update:</p>
+{{range .Items}}
+<p>
+  <strong>{{.Subject}}</strong><br/>
+  {{.Message}}
+</p>
+{{end}}
+<p>
+  Thanks,<br/>
+  ` + EmailPlaceholderAppName + ` team
+</p>`,
+}
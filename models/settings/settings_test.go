@@ -1,15 +1,22 @@
 package settings_test
 
 import (
+	"crypto/ed25519"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/pocketbase/pocketbase/models/settings"
 	"github.com/pocketbase/pocketbase/tools/auth"
 	"github.com/pocketbase/pocketbase/tools/mailer"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/sms"
+	"github.com/pocketbase/pocketbase/tools/types"
 )
 
 func TestSettingsValidate(t *testing.T) {
@@ -22,12 +29,25 @@ func TestSettingsValidate(t *testing.T) {
 	s.Smtp.Host = ""
 	s.S3.Enabled = true
 	s.S3.Endpoint = "invalid"
+	s.Sms.OtpDuration = -10
+	s.Filter.MaxJoins = -10
+	s.ErrorReporting.Enabled = true
+	s.ErrorReporting.Dsn = ""
+	s.Alerts.Cooldown = -1
+	s.Client.MinVersion = "invalid"
+	s.Guardrails.MaxDataSize = -1
+	s.TrustedProxy.Headers = []string{""}
+	s.LoginThrottle.MaxIpFailures = -1
+	s.SecurityHeaders.Default.XFrameOptions = "invalid"
+	s.Signing.Enabled = true
+	s.Signing.Secret = ""
 	s.AdminAuthToken.Duration = -10
 	s.AdminPasswordResetToken.Duration = -10
 	s.RecordAuthToken.Duration = -10
 	s.RecordPasswordResetToken.Duration = -10
 	s.RecordEmailChangeToken.Duration = -10
 	s.RecordVerificationToken.Duration = -10
+	s.RecordLoginLinkToken.Duration = -10
 	s.GoogleAuth.Enabled = true
 	s.GoogleAuth.ClientId = ""
 	s.FacebookAuth.Enabled = true
@@ -52,6 +72,11 @@ func TestSettingsValidate(t *testing.T) {
 	s.StravaAuth.ClientId = ""
 	s.GiteeAuth.Enabled = true
 	s.GiteeAuth.ClientId = ""
+	s.OIDCAuth.Enabled = true
+	s.OIDCAuth.ClientId = "oidc_test"
+	s.OIDCAuth.ClientSecret = "oidc_test"
+	s.AppleAuth.Enabled = true
+	s.AppleAuth.ClientId = ""
 
 	// check if Validate() is triggering the members validate methods.
 	err := s.Validate()
@@ -64,12 +89,23 @@ func TestSettingsValidate(t *testing.T) {
 		`"logs":{`,
 		`"smtp":{`,
 		`"s3":{`,
+		`"sms":{`,
+		`"filter":{`,
+		`"errorReporting":{`,
+		`"alerts":{`,
+		`"client":{`,
+		`"guardrails":{`,
+		`"trustedProxy":{`,
+		`"loginThrottle":{`,
+		`"securityHeaders":{`,
+		`"signing":{`,
 		`"adminAuthToken":{`,
 		`"adminPasswordResetToken":{`,
 		`"recordAuthToken":{`,
 		`"recordPasswordResetToken":{`,
 		`"recordEmailChangeToken":{`,
 		`"recordVerificationToken":{`,
+		`"recordLoginLinkToken":{`,
 		`"googleAuth":{`,
 		`"facebookAuth":{`,
 		`"githubAuth":{`,
@@ -82,6 +118,8 @@ func TestSettingsValidate(t *testing.T) {
 		`"twitchAuth":{`,
 		`"stravaAuth":{`,
 		`"giteeAuth":{`,
+		`"oidcAuth":{"issuer":`,
+		`"appleAuth":{`,
 	}
 
 	errBytes, _ := json.Marshal(err)
@@ -110,6 +148,7 @@ func TestSettingsMerge(t *testing.T) {
 	s2.RecordPasswordResetToken.Duration = 4
 	s2.RecordEmailChangeToken.Duration = 5
 	s2.RecordVerificationToken.Duration = 6
+	s2.RecordLoginLinkToken.Duration = 7
 	s2.GoogleAuth.Enabled = true
 	s2.GoogleAuth.ClientId = "google_test"
 	s2.FacebookAuth.Enabled = true
@@ -134,6 +173,10 @@ func TestSettingsMerge(t *testing.T) {
 	s2.StravaAuth.ClientId = "strava_test"
 	s2.GiteeAuth.Enabled = true
 	s2.GiteeAuth.ClientId = "gitee_test"
+	s2.OIDCAuth.Enabled = true
+	s2.OIDCAuth.ClientId = "oidc_test"
+	s2.AppleAuth.Enabled = true
+	s2.AppleAuth.ClientId = "apple_test"
 
 	if err := s1.Merge(s2); err != nil {
 		t.Fatal(err)
@@ -189,12 +232,17 @@ func TestSettingsRedactClone(t *testing.T) {
 	s1.Smtp.Password = "test123"
 	s1.Smtp.Tls = true
 	s1.S3.Secret = "test123"
+	s1.Sms.ApiSecret = "test123"
+	s1.ErrorReporting.Dsn = "test123"
+	s1.Alerts.WebhookUrl = "test123"
+	s1.Alerts.SlackWebhookUrl = "test123"
 	s1.AdminAuthToken.Secret = "test123"
 	s1.AdminPasswordResetToken.Secret = "test123"
 	s1.RecordAuthToken.Secret = "test123"
 	s1.RecordPasswordResetToken.Secret = "test123"
 	s1.RecordEmailChangeToken.Secret = "test123"
 	s1.RecordVerificationToken.Secret = "test123"
+	s1.RecordLoginLinkToken.Secret = "test123"
 	s1.GoogleAuth.ClientSecret = "test123"
 	s1.FacebookAuth.ClientSecret = "test123"
 	s1.GithubAuth.ClientSecret = "test123"
@@ -207,6 +255,8 @@ func TestSettingsRedactClone(t *testing.T) {
 	s1.TwitchAuth.ClientSecret = "test123"
 	s1.StravaAuth.ClientSecret = "test123"
 	s1.GiteeAuth.ClientSecret = "test123"
+	s1.OIDCAuth.ClientSecret = "test123"
+	s1.AppleAuth.ClientSecret = "test123"
 
 	s2, err := s1.RedactClone()
 	if err != nil {
@@ -218,13 +268,52 @@ func TestSettingsRedactClone(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	expected := `{"meta":{"appName":"test123","appUrl":"http://localhost:8090","hideControls":false,"senderName":"Support","senderAddress":"support@example.com","verificationTemplate":{"body":"\u003cp\u003eHello,\u003c/p\u003e\n\u003cp\u003eThank you for joining us at {APP_NAME}.\u003c/p\u003e\n\u003cp\u003eClick on the button below to verify your email address.\u003c/p\u003e\n\u003cp\u003e\n  \u003ca class=\"btn\" href=\"{ACTION_URL}\" target=\"_blank\" rel=\"noopener\"\u003eVerify\u003c/a\u003e\n\u003c/p\u003e\n\u003cp\u003e\n  Thanks,\u003cbr/\u003e\n  {APP_NAME} team\n\u003c/p\u003e","subject":"Verify your {APP_NAME} email","actionUrl":"{APP_URL}/_/#/auth/confirm-verification/{TOKEN}"},"resetPasswordTemplate":{"body":"\u003cp\u003eHello,\u003c/p\u003e\n\u003cp\u003eClick on the button below to reset your password.\u003c/p\u003e\n\u003cp\u003e\n  \u003ca class=\"btn\" href=\"{ACTION_URL}\" target=\"_blank\" rel=\"noopener\"\u003eReset password\u003c/a\u003e\n\u003c/p\u003e\n\u003cp\u003e\u003ci\u003eIf you didn't ask to reset your password, you can ignore this email.\u003c/i\u003e\u003c/p\u003e\n\u003cp\u003e\n  Thanks,\u003cbr/\u003e\n  {APP_NAME} team\n\u003c/p\u003e","subject":"Reset your {APP_NAME} password","actionUrl":"{APP_URL}/_/#/auth/confirm-password-reset/{TOKEN}"},"confirmEmailChangeTemplate":{"body":"\u003cp\u003eHello,\u003c/p\u003e\n\u003cp\u003eClick on the button below to confirm your new email address.\u003c/p\u003e\n\u003cp\u003e\n  \u003ca class=\"btn\" href=\"{ACTION_URL}\" target=\"_blank\" rel=\"noopener\"\u003eConfirm new email\u003c/a\u003e\n\u003c/p\u003e\n\u003cp\u003e\u003ci\u003eIf you didn't ask to change your email address, you can ignore this email.\u003c/i\u003e\u003c/p\u003e\n\u003cp\u003e\n  Thanks,\u003cbr/\u003e\n  {APP_NAME} team\n\u003c/p\u003e","subject":"Confirm your {APP_NAME} new email address","actionUrl":"{APP_URL}/_/#/auth/confirm-email-change/{TOKEN}"}},"logs":{"maxDays":5},"smtp":{"enabled":false,"host":"smtp.example.com","port":587,"username":"","password":"******","authMethod":"","tls":true},"s3":{"enabled":false,"bucket":"","region":"","endpoint":"","accessKey":"","secret":"******","forcePathStyle":false},"adminAuthToken":{"secret":"******","duration":1209600},"adminPasswordResetToken":{"secret":"******","duration":1800},"recordAuthToken":{"secret":"******","duration":1209600},"recordPasswordResetToken":{"secret":"******","duration":1800},"recordEmailChangeToken":{"secret":"******","duration":1800},"recordVerificationToken":{"secret":"******","duration":604800},"emailAuth":{"enabled":false,"exceptDomains":null,"onlyDomains":null,"minPasswordLength":0},"googleAuth":{"enabled":false,"clientSecret":"******"},"facebookAuth":{"enabled":false,"clientSecret":"******"},"githubAuth":{"enabled":false,"clientSecret":"******"},"gitlabAuth":{"enabled":false,"clientSecret":"******"},"discordAuth":{"enabled":false,"clientSecret":"******"},"twitterAuth":{"enabled":false,"clientSecret":"******"},"microsoftAuth":{"enabled":false,"clientSecret":"******"},"spotifyAuth":{"enabled":false,"clientSecret":"******"},"kakaoAuth":{"enabled":false,"clientSecret":"******"},"twitchAuth":{"enabled":false,"clientSecret":"******"},"stravaAuth":{"enabled":false,"clientSecret":"******"},"giteeAuth":{"enabled":false,"clientSecret":"******"}}`
+	expected := `{"meta":{"appName":"test123","appUrl":"http://localhost:8090","hideControls":false,"senderName":"Support","senderAddress":"support@example.com","verificationTemplate":{"body":"\u003cp\u003eHello,\u003c/p\u003e\n\u003cp\u003eThank you for joining us at {APP_NAME}.\u003c/p\u003e\n\u003cp\u003eClick on the button below to verify your email address.\u003c/p\u003e\n\u003cp\u003e\n  \u003ca class=\"btn\" href=\"{ACTION_URL}\" target=\"_blank\" rel=\"noopener\"\u003eVerify\u003c/a\u003e\n\u003c/p\u003e\n\u003cp\u003e\n  Thanks,\u003cbr/\u003e\n  {APP_NAME} team\n\u003c/p\u003e","subject":"Verify your {APP_NAME} email","actionUrl":"{APP_URL}/_/#/auth/confirm-verification/{TOKEN}"},"resetPasswordTemplate":{"body":"\u003cp\u003eHello,\u003c/p\u003e\n\u003cp\u003eClick on the button below to reset your password.\u003c/p\u003e\n\u003cp\u003e\n  \u003ca class=\"btn\" href=\"{ACTION_URL}\" target=\"_blank\" rel=\"noopener\"\u003eReset password\u003c/a\u003e\n\u003c/p\u003e\n\u003cp\u003e\u003ci\u003eIf you didn't ask to reset your password, you can ignore this email.\u003c/i\u003e\u003c/p\u003e\n\u003cp\u003e\n  Thanks,\u003cbr/\u003e\n  {APP_NAME} team\n\u003c/p\u003e","subject":"Reset your {APP_NAME} password","actionUrl":"{APP_URL}/_/#/auth/confirm-password-reset/{TOKEN}"},"confirmEmailChangeTemplate":{"body":"\u003cp\u003eHello,\u003c/p\u003e\n\u003cp\u003eClick on the button below to confirm your new email address.\u003c/p\u003e\n\u003cp\u003e\n  \u003ca class=\"btn\" href=\"{ACTION_URL}\" target=\"_blank\" rel=\"noopener\"\u003eConfirm new email\u003c/a\u003e\n\u003c/p\u003e\n\u003cp\u003e\u003ci\u003eIf you didn't ask to change your email address, you can ignore this email.\u003c/i\u003e\u003c/p\u003e\n\u003cp\u003e\n  Thanks,\u003cbr/\u003e\n  {APP_NAME} team\n\u003c/p\u003e","subject":"Confirm your {APP_NAME} new email address","actionUrl":"{APP_URL}/_/#/auth/confirm-email-change/{TOKEN}"},"loginLinkTemplate":{"body":"\u003cp\u003eHello,\u003c/p\u003e\n\u003cp\u003eClick on the button below to sign in to {APP_NAME}.\u003c/p\u003e\n\u003cp\u003e\n  \u003ca class=\"btn\" href=\"{ACTION_URL}\" target=\"_blank\" rel=\"noopener\"\u003eSign in\u003c/a\u003e\n\u003c/p\u003e\n\u003cp\u003eOr copy and paste the following one-time code:\u003c/p\u003e\n\u003cp\u003e\u003ccode\u003e{TOKEN}\u003c/code\u003e\u003c/p\u003e\n\u003cp\u003e\u003ci\u003eIf you didn't ask to sign in, you can ignore this email.\u003c/i\u003e\u003c/p\u003e\n\u003cp\u003e\n  Thanks,\u003cbr/\u003e\n  {APP_NAME} team\n\u003c/p\u003e","subject":"Sign in to {APP_NAME}","actionUrl":"{APP_URL}/_/#/auth/confirm-login-link/{TOKEN}"}},"logs":{"maxDays":5},"smtp":{"enabled":false,"host":"smtp.example.com","port":587,"username":"","password":"******","authMethod":"","tls":true},"s3":{"enabled":false,"bucket":"","region":"","endpoint":"","accessKey":"","secret":"******","forcePathStyle":false},"sms":{"enabled":false,"provider":"twilio","apiKey":"","apiSecret":"******","from":"","otpTemplate":"Your {APP_NAME} verification code is {CODE}.","otpDuration":300},"filter":{"maxNestedRels":6,"maxJoins":50},"ruleMacros":{"macros":[]},"json":{"bigIntStrings":false},"errorReporting":{"enabled":false,"dsn":"******","environment":"","slowThreshold":0,"sendPii":false,"scrubFields":null},"alerts":{"enabled":false,"emails":null,"webhookUrl":"******","webhookTemplate":"","slackWebhookUrl":"******","cooldown":60,"errorsThreshold":50,"errorsWindow":5},"digest":{"enabled":false,"window":60,"frequencyField":"","subject":"Your {APP_NAME} updates","body":"\u003cp\u003eHello,\u003c/p\u003e\n\u003cp\u003eHere is what happened since your last update:\u003c/p\u003e\n{{range .Items}}\n\u003cp\u003e\n  \u003cstrong\u003e{{.Subject}}\u003c/strong\u003e\u003cbr/\u003e\n  {{.Message}}\n\u003c/p\u003e\n{{end}}\n\u003cp\u003e\n  Thanks,\u003cbr/\u003e\n  {APP_NAME} team\n\u003c/p\u003e"},"preferences":{"keys":[]},"client":{"minVersion":"","flags":{},"public":{}},"guardrails":{"minFreeDisk":500,"maxDataSize":0,"readOnly":false},"trustedProxy":{"headers":[],"useLeftmostIp":false},"loginThrottle":{"enabled":false,"maxIdentityFailures":5,"maxIpFailures":50,"window":900,"lockoutDuration":60,"maxLockoutDuration":3600},"securityHeaders":{"enabled":false,"default":{"contentSecurityPolicy":"","hstsMaxAge":0,"hstsIncludeSubdomains":false,"xFrameOptions":"SAMEORIGIN","referrerPolicy":"strict-origin-when-cross-origin"},"adminUI":{"contentSecurityPolicy":"","hstsMaxAge":0,"hstsIncludeSubdomains":false,"xFrameOptions":"DENY","referrerPolicy":"same-origin"},"routes":null},"signing":{"enabled":false,"secret":"******"},"tokenSigning":{"enabled":false,"algorithm":"RS256","keys":[]},"authTokenClaims":{"issuer":"","audience":[]},"adminAuthToken":{"secret":"******","duration":1209600},"adminPasswordResetToken":{"secret":"******","duration":1800},"recordAuthToken":{"secret":"******","duration":1209600},"recordPasswordResetToken":{"secret":"******","duration":1800},"recordEmailChangeToken":{"secret":"******","duration":1800},"recordVerificationToken":{"secret":"******","duration":604800},"recordLoginLinkToken":{"secret":"******","duration":600},"emailAuth":{"enabled":false,"exceptDomains":null,"onlyDomains":null,"minPasswordLength":0},"googleAuth":{"enabled":false,"clientSecret":"******"},"facebookAuth":{"enabled":false,"clientSecret":"******"},"githubAuth":{"enabled":false,"clientSecret":"******"},"gitlabAuth":{"enabled":false,"clientSecret":"******"},"discordAuth":{"enabled":false,"clientSecret":"******"},"twitterAuth":{"enabled":false,"clientSecret":"******"},"microsoftAuth":{"enabled":false,"clientSecret":"******"},"spotifyAuth":{"enabled":false,"clientSecret":"******"},"kakaoAuth":{"enabled":false,"clientSecret":"******"},"twitchAuth":{"enabled":false,"clientSecret":"******"},"stravaAuth":{"enabled":false,"clientSecret":"******"},"giteeAuth":{"enabled":false,"clientSecret":"******"},"oidcAuth":{"enabled":false,"clientSecret":"******"},"appleAuth":{"enabled":false,"clientSecret":"******"}}`
 
 	if encodedStr := string(encoded); encodedStr != expected {
 		t.Fatalf("Expected\n%v\ngot\n%v", expected, encodedStr)
 	}
 }
 
+func TestSettingsRestoreRedacted(t *testing.T) {
+	original := settings.New()
+	original.Smtp.Password = "smtp_secret"
+	original.ErrorReporting.Dsn = "https://key@sentry.example.com/1"
+	original.S3.Secret = "s3_secret"
+	original.Alerts.SlackWebhookUrl = "https://hooks.slack.com/services/T000/B000/XXXX"
+	if err := original.TokenSigning.Rotate(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := original.RedactClone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.S3.Secret = "new_s3_secret"
+
+	s.RestoreRedacted(original)
+
+	if s.Smtp.Password != original.Smtp.Password {
+		t.Fatalf("Expected Smtp.Password %q, got %q", original.Smtp.Password, s.Smtp.Password)
+	}
+
+	if s.ErrorReporting.Dsn != original.ErrorReporting.Dsn {
+		t.Fatalf("Expected ErrorReporting.Dsn %q, got %q", original.ErrorReporting.Dsn, s.ErrorReporting.Dsn)
+	}
+
+	if s.Alerts.SlackWebhookUrl != original.Alerts.SlackWebhookUrl {
+		t.Fatalf("Expected Alerts.SlackWebhookUrl %q, got %q", original.Alerts.SlackWebhookUrl, s.Alerts.SlackWebhookUrl)
+	}
+
+	if s.TokenSigning.Keys[0].PrivateKey != original.TokenSigning.Keys[0].PrivateKey {
+		t.Fatalf("Expected the token signing private key to be restored, got %q", s.TokenSigning.Keys[0].PrivateKey)
+	}
+
+	if s.S3.Secret != "new_s3_secret" {
+		t.Fatalf("Expected the changed S3.Secret to be kept, got %q", s.S3.Secret)
+	}
+}
+
 func TestNamedAuthProviderConfigs(t *testing.T) {
 	s := settings.New()
 
@@ -241,6 +330,8 @@ func TestNamedAuthProviderConfigs(t *testing.T) {
 	s.TwitchAuth.ClientId = "twitch_test"
 	s.StravaAuth.ClientId = "strava_test"
 	s.GiteeAuth.ClientId = "gitee_test"
+	s.OIDCAuth.ClientId = "oidc_test"
+	s.AppleAuth.ClientId = "apple_test"
 
 	result := s.NamedAuthProviderConfigs()
 
@@ -263,6 +354,8 @@ func TestNamedAuthProviderConfigs(t *testing.T) {
 		`"twitch":{"enabled":false,"clientId":"twitch_test"}`,
 		`"strava":{"enabled":false,"clientId":"strava_test"}`,
 		`"gitee":{"enabled":false,"clientId":"gitee_test"}`,
+		`"oidc":{"enabled":false,"clientId":"oidc_test"}`,
+		`"apple":{"enabled":false,"clientId":"apple_test"}`,
 	}
 	for _, p := range expectedParts {
 		if !strings.Contains(encodedStr, p) {
@@ -389,6 +482,91 @@ func TestSmtpConfigValidate(t *testing.T) {
 	}
 }
 
+func TestSmsConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		config      settings.SmsConfig
+		expectError bool
+	}{
+		// zero values (disabled)
+		{
+			settings.SmsConfig{},
+			true,
+		},
+		// default values (disabled)
+		{
+			settings.New().Sms,
+			false,
+		},
+		// enabled with missing credentials
+		{
+			settings.SmsConfig{
+				Enabled:     true,
+				Provider:    sms.ProviderTwilio,
+				OtpTemplate: "{CODE}",
+				OtpDuration: 300,
+			},
+			true,
+		},
+		// invalid provider and template
+		{
+			settings.SmsConfig{
+				Enabled:     true,
+				Provider:    "invalid",
+				ApiKey:      "key",
+				ApiSecret:   "secret",
+				From:        "+15005550006",
+				OtpTemplate: "missing code",
+				OtpDuration: 300,
+			},
+			true,
+		},
+		// too short otp duration
+		{
+			settings.SmsConfig{
+				OtpTemplate: "{CODE}",
+				OtpDuration: 10,
+			},
+			true,
+		},
+		// valid data
+		{
+			settings.SmsConfig{
+				Enabled:     true,
+				Provider:    sms.ProviderVonage,
+				ApiKey:      "key",
+				ApiSecret:   "secret",
+				From:        "Test",
+				OtpTemplate: "{CODE}",
+				OtpDuration: 300,
+			},
+			false,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		result := scenario.config.Validate()
+
+		if result != nil && !scenario.expectError {
+			t.Errorf("(%d) Didn't expect error, got %v", i, result)
+		}
+
+		if result == nil && scenario.expectError {
+			t.Errorf("(%d) Expected error, got nil", i)
+		}
+	}
+}
+
+func TestSmsConfigResolveOtpTemplate(t *testing.T) {
+	config := settings.SmsConfig{OtpTemplate: "{APP_NAME}: {CODE} ({CODE})"}
+
+	result := config.ResolveOtpTemplate("Acme", "123456")
+
+	expected := "Acme: 123456 (123456)"
+	if result != expected {
+		t.Fatalf("Expected %q, got %q", expected, result)
+	}
+}
+
 func TestS3ConfigValidate(t *testing.T) {
 	scenarios := []struct {
 		config      settings.S3Config
@@ -489,6 +667,7 @@ func TestMetaConfigValidate(t *testing.T) {
 				VerificationTemplate:       invalidTemplate,
 				ResetPasswordTemplate:      invalidTemplate,
 				ConfirmEmailChangeTemplate: invalidTemplate,
+				LoginLinkTemplate:          invalidTemplate,
 			},
 			true,
 		},
@@ -502,6 +681,7 @@ func TestMetaConfigValidate(t *testing.T) {
 				VerificationTemplate:       noPlaceholdersTemplate,
 				ResetPasswordTemplate:      noPlaceholdersTemplate,
 				ConfirmEmailChangeTemplate: noPlaceholdersTemplate,
+				LoginLinkTemplate:          noPlaceholdersTemplate,
 			},
 			true,
 		},
@@ -515,6 +695,7 @@ func TestMetaConfigValidate(t *testing.T) {
 				VerificationTemplate:       withPlaceholdersTemplate,
 				ResetPasswordTemplate:      withPlaceholdersTemplate,
 				ConfirmEmailChangeTemplate: withPlaceholdersTemplate,
+				LoginLinkTemplate:          withPlaceholdersTemplate,
 			},
 			false,
 		},
@@ -692,51 +873,177 @@ func TestLogsConfigValidate(t *testing.T) {
 	}
 }
 
-func TestAuthProviderConfigValidate(t *testing.T) {
+func TestFilterConfigValidate(t *testing.T) {
 	scenarios := []struct {
-		config      settings.AuthProviderConfig
+		config      settings.FilterConfig
+		expectError bool
+	}{
+		// zero values
+		{
+			settings.FilterConfig{},
+			false,
+		},
+		// invalid data
+		{
+			settings.FilterConfig{MaxNestedRels: -1, MaxJoins: 10},
+			true,
+		},
+		{
+			settings.FilterConfig{MaxNestedRels: 1, MaxJoins: -1},
+			true,
+		},
+		// valid data
+		{
+			settings.FilterConfig{MaxNestedRels: 1, MaxJoins: 10},
+			false,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		result := scenario.config.Validate()
+
+		if result != nil && !scenario.expectError {
+			t.Errorf("(%d) Didn't expect error, got %v", i, result)
+		}
+
+		if result == nil && scenario.expectError {
+			t.Errorf("(%d) Expected error, got nil", i)
+		}
+	}
+}
+
+func TestErrorReportingConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		config      settings.ErrorReportingConfig
 		expectError bool
 	}{
 		// zero values (disabled)
 		{
-			settings.AuthProviderConfig{},
+			settings.ErrorReportingConfig{},
 			false,
 		},
 		// zero values (enabled)
 		{
-			settings.AuthProviderConfig{Enabled: true},
+			settings.ErrorReportingConfig{Enabled: true},
 			true,
 		},
-		// invalid data
+		// invalid data (disabled)
 		{
-			settings.AuthProviderConfig{
-				Enabled:      true,
-				ClientId:     "",
-				ClientSecret: "",
-				AuthUrl:      "test",
-				TokenUrl:     "test",
-				UserApiUrl:   "test",
+			settings.ErrorReportingConfig{Dsn: "https://example.com/123"},
+			true,
+		},
+		// invalid data (enabled)
+		{
+			settings.ErrorReportingConfig{Enabled: true, Dsn: "https://key@example.com/123", SlowThreshold: -1},
+			true,
+		},
+		{
+			settings.ErrorReportingConfig{Enabled: true, Dsn: "https://key@example.com/123", Environment: strings.Repeat("a", 65)},
+			true,
+		},
+		// valid data
+		{
+			settings.ErrorReportingConfig{
+				Enabled:       true,
+				Dsn:           "https://key@example.com/123",
+				Environment:   "production",
+				SlowThreshold: 1000,
+				ScrubFields:   []string{"test"},
 			},
+			false,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		result := scenario.config.Validate()
+
+		if result != nil && !scenario.expectError {
+			t.Errorf("(%d) Didn't expect error, got %v", i, result)
+		}
+
+		if result == nil && scenario.expectError {
+			t.Errorf("(%d) Expected error, got nil", i)
+		}
+	}
+}
+
+func TestAlertsConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		config      settings.AlertsConfig
+		expectError bool
+	}{
+		// zero values (disabled)
+		{
+			settings.AlertsConfig{},
+			false,
+		},
+		// zero values (enabled)
+		{
+			settings.AlertsConfig{Enabled: true},
 			true,
 		},
-		// valid data (only the required)
+		// invalid data (disabled)
 		{
-			settings.AuthProviderConfig{
-				Enabled:      true,
-				ClientId:     "test",
-				ClientSecret: "test",
+			settings.AlertsConfig{Emails: []string{"invalid"}},
+			true,
+		},
+		{
+			settings.AlertsConfig{WebhookUrl: "invalid"},
+			true,
+		},
+		{
+			settings.AlertsConfig{SlackWebhookUrl: "invalid"},
+			true,
+		},
+		{
+			settings.AlertsConfig{WebhookTemplate: "{{.Missing"},
+			true,
+		},
+		{
+			settings.AlertsConfig{WebhookTemplate: `{"text": {{.Message}}}`},
+			true,
+		},
+		{
+			settings.AlertsConfig{Cooldown: -1},
+			true,
+		},
+		{
+			settings.AlertsConfig{ErrorsThreshold: 10},
+			true,
+		},
+		// valid data (enabled with emails)
+		{
+			settings.AlertsConfig{
+				Enabled:         true,
+				Emails:          []string{"test@example.com"},
+				Cooldown:        10,
+				ErrorsThreshold: 10,
+				ErrorsWindow:    1,
 			},
 			false,
 		},
-		// valid data (fill all fields)
+		// valid data (enabled with webhook only)
 		{
-			settings.AuthProviderConfig{
-				Enabled:      true,
-				ClientId:     "test",
-				ClientSecret: "test",
-				AuthUrl:      "https://example.com",
-				TokenUrl:     "https://example.com",
-				UserApiUrl:   "https://example.com",
+			settings.AlertsConfig{
+				Enabled:    true,
+				WebhookUrl: "https://example.com/hook",
+			},
+			false,
+		},
+		// valid data (enabled with templated webhook)
+		{
+			settings.AlertsConfig{
+				Enabled:         true,
+				WebhookUrl:      "https://example.com/hook",
+				WebhookTemplate: `{"text": {{json .Message}}}`,
+			},
+			false,
+		},
+		// valid data (enabled with slack webhook only)
+		{
+			settings.AlertsConfig{
+				Enabled:         true,
+				SlackWebhookUrl: "https://hooks.slack.com/services/test",
 			},
 			false,
 		},
@@ -755,44 +1062,913 @@ func TestAuthProviderConfigValidate(t *testing.T) {
 	}
 }
 
-func TestAuthProviderConfigSetupProvider(t *testing.T) {
-	provider := auth.NewGithubProvider()
-
-	// disabled config
-	c1 := settings.AuthProviderConfig{Enabled: false}
-	if err := c1.SetupProvider(provider); err == nil {
-		t.Errorf("Expected error, got nil")
+func TestDigestConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		config      settings.DigestConfig
+		expectError bool
+	}{
+		// zero values (disabled)
+		{
+			settings.DigestConfig{},
+			false,
+		},
+		// zero values (enabled)
+		{
+			settings.DigestConfig{Enabled: true},
+			true,
+		},
+		// invalid data (disabled)
+		{
+			settings.DigestConfig{Window: -1},
+			true,
+		},
+		{
+			settings.DigestConfig{Body: "{{range .Items}}"},
+			true,
+		},
+		// valid data (enabled)
+		{
+			settings.DigestConfig{
+				Enabled:        true,
+				Window:         10,
+				FrequencyField: "digest",
+				Subject:        "test",
+				Body:           "{{range .Items}}{{.Subject}}{{end}}",
+			},
+			false,
+		},
 	}
 
-	c2 := settings.AuthProviderConfig{
-		Enabled:      true,
-		ClientId:     "test_ClientId",
-		ClientSecret: "test_ClientSecret",
-		AuthUrl:      "test_AuthUrl",
-		UserApiUrl:   "test_UserApiUrl",
-		TokenUrl:     "test_TokenUrl",
-	}
-	if err := c2.SetupProvider(provider); err != nil {
-		t.Error(err)
-	}
+	for i, scenario := range scenarios {
+		result := scenario.config.Validate()
 
-	if provider.ClientId() != c2.ClientId {
-		t.Fatalf("Expected ClientId %s, got %s", c2.ClientId, provider.ClientId())
-	}
+		if result != nil && !scenario.expectError {
+			t.Errorf("(%d) Didn't expect error, got %v", i, result)
+		}
 
-	if provider.ClientSecret() != c2.ClientSecret {
-		t.Fatalf("Expected ClientSecret %s, got %s", c2.ClientSecret, provider.ClientSecret())
+		if result == nil && scenario.expectError {
+			t.Errorf("(%d) Expected error, got nil", i)
+		}
 	}
+}
 
-	if provider.AuthUrl() != c2.AuthUrl {
-		t.Fatalf("Expected AuthUrl %s, got %s", c2.AuthUrl, provider.AuthUrl())
+func TestDigestConfigFrequencyWindow(t *testing.T) {
+	config := settings.DigestConfig{Window: 10}
+
+	scenarios := []struct {
+		frequency      string
+		expectedWindow time.Duration
+		expectedOk     bool
+	}{
+		{"", 10 * time.Minute, true},
+		{"unknown", 10 * time.Minute, true},
+		{settings.DigestFrequencyNever, 0, false},
+		{settings.DigestFrequencyHourly, time.Hour, true},
+		{settings.DigestFrequencyDaily, 24 * time.Hour, true},
+		{settings.DigestFrequencyWeekly, 7 * 24 * time.Hour, true},
 	}
 
-	if provider.UserApiUrl() != c2.UserApiUrl {
-		t.Fatalf("Expected UserApiUrl %s, got %s", c2.UserApiUrl, provider.UserApiUrl())
+	for _, s := range scenarios {
+		window, ok := config.FrequencyWindow(s.frequency)
+
+		if window != s.expectedWindow || ok != s.expectedOk {
+			t.Errorf("[%s] Expected (%v, %v), got (%v, %v)", s.frequency, s.expectedWindow, s.expectedOk, window, ok)
+		}
 	}
+}
 
-	if provider.TokenUrl() != c2.TokenUrl {
-		t.Fatalf("Expected TokenUrl %s, got %s", c2.TokenUrl, provider.TokenUrl())
+func TestSigningConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		config      settings.SigningConfig
+		expectError bool
+	}{
+		// zero values
+		{
+			settings.SigningConfig{},
+			false,
+		},
+		// invalid data
+		{
+			settings.SigningConfig{Enabled: true},
+			true,
+		},
+		{
+			settings.SigningConfig{Secret: "short"},
+			true,
+		},
+		// valid data
+		{
+			settings.SigningConfig{
+				Enabled: true,
+				Secret:  "abcdefghijklmnopqrstuvwxyz123456",
+			},
+			false,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		result := scenario.config.Validate()
+
+		if result != nil && !scenario.expectError {
+			t.Errorf("(%d) Didn't expect error, got %v", i, result)
+		}
+
+		if result == nil && scenario.expectError {
+			t.Errorf("(%d) Expected error, got nil", i)
+		}
+	}
+}
+
+func TestTokenSigningConfigValidate(t *testing.T) {
+	privateKey, _ := security.GenerateRSAPrivateKeyPEM(1024)
+	edPrivateKey, _ := security.GenerateEd25519PrivateKeyPEM()
+
+	scenarios := []struct {
+		config      settings.TokenSigningConfig
+		expectError bool
+	}{
+		// zero values
+		{
+			settings.TokenSigningConfig{},
+			false,
+		},
+		// invalid data
+		{
+			settings.TokenSigningConfig{Enabled: true},
+			true,
+		},
+		{
+			settings.TokenSigningConfig{Algorithm: "HS256"},
+			true,
+		},
+		{
+			settings.TokenSigningConfig{
+				Keys: []settings.TokenSigningKey{{Id: "test", PrivateKey: "invalid"}},
+			},
+			true,
+		},
+		{
+			settings.TokenSigningConfig{
+				Enabled: true,
+				Keys:    []settings.TokenSigningKey{{Id: "test", PrivateKey: privateKey, Retired: types.NowDateTime()}},
+			},
+			true,
+		},
+		// valid data
+		{
+			settings.TokenSigningConfig{
+				Enabled: true,
+				Keys:    []settings.TokenSigningKey{{Id: "test", PrivateKey: privateKey}},
+			},
+			false,
+		},
+		{
+			settings.TokenSigningConfig{
+				Enabled:   true,
+				Algorithm: settings.TokenSigningAlgorithmEdDSA,
+				Keys: []settings.TokenSigningKey{
+					{Id: "test1", PrivateKey: privateKey, Retired: types.NowDateTime()},
+					{Id: "test2", PrivateKey: edPrivateKey},
+				},
+			},
+			false,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		result := scenario.config.Validate()
+
+		if result != nil && !scenario.expectError {
+			t.Errorf("(%d) Didn't expect error, got %v", i, result)
+		}
+
+		if result == nil && scenario.expectError {
+			t.Errorf("(%d) Expected error, got nil", i)
+		}
+	}
+}
+
+func TestTokenSigningConfigRotate(t *testing.T) {
+	oldRetired, _ := types.ParseDateTime(time.Now().Add(-2 * time.Hour))
+
+	config := settings.TokenSigningConfig{
+		Algorithm: settings.TokenSigningAlgorithmEdDSA,
+		Keys: []settings.TokenSigningKey{
+			{Id: "old", PrivateKey: "test", Retired: oldRetired},
+			{Id: "active", PrivateKey: "test"},
+		},
+	}
+
+	if err := config.Rotate(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(config.Keys) != 2 {
+		t.Fatalf("Expected 2 keys, got %d", len(config.Keys))
+	}
+
+	if config.FindKey("old") != nil {
+		t.Fatal("Expected the expired retired key to be removed")
+	}
+
+	retired := config.FindKey("active")
+	if retired == nil || retired.Retired.IsZero() {
+		t.Fatalf("Expected the previous active key to be retired, got %v", retired)
+	}
+
+	active := config.ActiveKey()
+	if active == nil || active.Id == "active" || active.Created.IsZero() {
+		t.Fatalf("Expected new active key, got %v", active)
+	}
+
+	signer, err := active.Signer()
+	if err != nil {
+		t.Fatalf("Expected valid private key, got %v", err)
+	}
+	if _, ok := signer.(ed25519.PrivateKey); !ok {
+		t.Fatalf("Expected Ed25519 private key, got %T", signer)
+	}
+}
+
+func TestAuthTokenClaimsConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		config      settings.AuthTokenClaimsConfig
+		expectError bool
+	}{
+		// zero values
+		{
+			settings.AuthTokenClaimsConfig{},
+			false,
+		},
+		// invalid data
+		{
+			settings.AuthTokenClaimsConfig{Issuer: strings.Repeat("a", 256)},
+			true,
+		},
+		{
+			settings.AuthTokenClaimsConfig{Audience: []string{"test", ""}},
+			true,
+		},
+		// valid data
+		{
+			settings.AuthTokenClaimsConfig{
+				Issuer:   "https://example.com",
+				Audience: []string{"service1", "service2"},
+			},
+			false,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		result := scenario.config.Validate()
+
+		if result != nil && !scenario.expectError {
+			t.Errorf("(%d) Didn't expect error, got %v", i, result)
+		}
+
+		if result == nil && scenario.expectError {
+			t.Errorf("(%d) Expected error, got nil", i)
+		}
+	}
+}
+
+func TestGuardrailsConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		config      settings.GuardrailsConfig
+		expectError bool
+	}{
+		// zero values
+		{
+			settings.GuardrailsConfig{},
+			false,
+		},
+		// invalid data
+		{
+			settings.GuardrailsConfig{MinFreeDisk: -1},
+			true,
+		},
+		{
+			settings.GuardrailsConfig{MaxDataSize: -1},
+			true,
+		},
+		// valid data
+		{
+			settings.GuardrailsConfig{
+				MinFreeDisk: 100,
+				MaxDataSize: 1000,
+				ReadOnly:    true,
+			},
+			false,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		result := scenario.config.Validate()
+
+		if result != nil && !scenario.expectError {
+			t.Errorf("(%d) Didn't expect error, got %v", i, result)
+		}
+
+		if result == nil && scenario.expectError {
+			t.Errorf("(%d) Expected error, got nil", i)
+		}
+	}
+}
+
+func TestLoginThrottleConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		config      settings.LoginThrottleConfig
+		expectError bool
+	}{
+		// zero values
+		{
+			settings.LoginThrottleConfig{},
+			false,
+		},
+		// invalid data
+		{
+			settings.LoginThrottleConfig{MaxIdentityFailures: -1},
+			true,
+		},
+		{
+			settings.LoginThrottleConfig{MaxLockoutDuration: -1},
+			true,
+		},
+		{
+			settings.LoginThrottleConfig{Enabled: true, MaxIdentityFailures: 5},
+			true,
+		},
+		// valid data
+		{
+			settings.LoginThrottleConfig{
+				Enabled:             true,
+				MaxIdentityFailures: 5,
+				MaxIpFailures:       0,
+				Window:              60,
+				LockoutDuration:     10,
+			},
+			false,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		result := scenario.config.Validate()
+
+		if result != nil && !scenario.expectError {
+			t.Errorf("(%d) Didn't expect error, got %v", i, result)
+		}
+
+		if result == nil && scenario.expectError {
+			t.Errorf("(%d) Expected error, got nil", i)
+		}
+	}
+}
+
+func TestLoginThrottleConfigLockoutDurationFor(t *testing.T) {
+	scenarios := []struct {
+		config   settings.LoginThrottleConfig
+		n        int
+		expected time.Duration
+	}{
+		// fixed duration
+		{settings.LoginThrottleConfig{LockoutDuration: 60}, 1, 60 * time.Second},
+		{settings.LoginThrottleConfig{LockoutDuration: 60}, 5, 60 * time.Second},
+		{settings.LoginThrottleConfig{LockoutDuration: 60, MaxLockoutDuration: 30}, 3, 60 * time.Second},
+		// exponential backoff
+		{settings.LoginThrottleConfig{LockoutDuration: 60, MaxLockoutDuration: 600}, 1, 60 * time.Second},
+		{settings.LoginThrottleConfig{LockoutDuration: 60, MaxLockoutDuration: 600}, 2, 120 * time.Second},
+		{settings.LoginThrottleConfig{LockoutDuration: 60, MaxLockoutDuration: 600}, 4, 480 * time.Second},
+		{settings.LoginThrottleConfig{LockoutDuration: 60, MaxLockoutDuration: 600}, 5, 600 * time.Second},
+		{settings.LoginThrottleConfig{LockoutDuration: 60, MaxLockoutDuration: 600}, 100, 600 * time.Second},
+	}
+
+	for i, s := range scenarios {
+		result := s.config.LockoutDurationFor(s.n)
+		if result != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+}
+
+func TestSecurityHeadersConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		config      settings.SecurityHeadersConfig
+		expectError bool
+	}{
+		// zero values
+		{
+			settings.SecurityHeadersConfig{},
+			false,
+		},
+		// invalid data
+		{
+			settings.SecurityHeadersConfig{
+				Default: settings.SecurityHeadersPolicy{XFrameOptions: "ALLOW"},
+			},
+			true,
+		},
+		{
+			settings.SecurityHeadersConfig{
+				AdminUI: settings.SecurityHeadersPolicy{ReferrerPolicy: "invalid"},
+			},
+			true,
+		},
+		{
+			settings.SecurityHeadersConfig{
+				Default: settings.SecurityHeadersPolicy{HstsMaxAge: -1},
+			},
+			true,
+		},
+		{
+			settings.SecurityHeadersConfig{
+				Default: settings.SecurityHeadersPolicy{ContentSecurityPolicy: "default-src 'self'\r\nX-Test: 1"},
+			},
+			true,
+		},
+		{
+			settings.SecurityHeadersConfig{
+				Routes: []settings.SecurityHeadersRoute{{Path: ""}},
+			},
+			true,
+		},
+		{
+			settings.SecurityHeadersConfig{
+				Routes: []settings.SecurityHeadersRoute{{Path: "app"}},
+			},
+			true,
+		},
+		{
+			settings.SecurityHeadersConfig{
+				Routes: []settings.SecurityHeadersRoute{{
+					Path:   "/app",
+					Policy: settings.SecurityHeadersPolicy{XFrameOptions: "invalid"},
+				}},
+			},
+			true,
+		},
+		// valid data
+		{
+			settings.SecurityHeadersConfig{
+				Enabled: true,
+				Default: settings.SecurityHeadersPolicy{
+					ContentSecurityPolicy: "default-src 'self'",
+					HstsMaxAge:            31536000,
+					HstsIncludeSubdomains: true,
+					XFrameOptions:         "DENY",
+					ReferrerPolicy:        "no-referrer",
+				},
+				AdminUI: settings.SecurityHeadersPolicy{XFrameOptions: "SAMEORIGIN"},
+				Routes: []settings.SecurityHeadersRoute{{
+					Path:   "/app",
+					Policy: settings.SecurityHeadersPolicy{ContentSecurityPolicy: "frame-ancestors *"},
+				}},
+			},
+			false,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		result := scenario.config.Validate()
+
+		if result != nil && !scenario.expectError {
+			t.Errorf("(%d) Didn't expect error, got %v", i, result)
+		}
+
+		if result == nil && scenario.expectError {
+			t.Errorf("(%d) Expected error, got nil", i)
+		}
+	}
+}
+
+func TestSecurityHeadersConfigFindRoute(t *testing.T) {
+	config := settings.SecurityHeadersConfig{
+		Routes: []settings.SecurityHeadersRoute{
+			{Path: "/app"},
+			{Path: "/app/nested/"},
+			{Path: "/"},
+		},
+	}
+
+	scenarios := []struct {
+		path     string
+		expected string
+	}{
+		{"/", "/"},
+		{"/test", "/"},
+		{"/app", "/app"},
+		{"/apple", "/"},
+		{"/app/test", "/app"},
+		{"/app/nested", "/app/nested/"},
+		{"/app/nested/test", "/app/nested/"},
+	}
+
+	for _, s := range scenarios {
+		route := config.FindRoute(s.path)
+
+		var result string
+		if route != nil {
+			result = route.Path
+		}
+
+		if result != s.expected {
+			t.Errorf("(%q) Expected route %q, got %q", s.path, s.expected, result)
+		}
+	}
+}
+
+func TestAuthProviderConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		config      settings.AuthProviderConfig
+		expectError bool
+	}{
+		// zero values (disabled)
+		{
+			settings.AuthProviderConfig{},
+			false,
+		},
+		// zero values (enabled)
+		{
+			settings.AuthProviderConfig{Enabled: true},
+			true,
+		},
+		// invalid data
+		{
+			settings.AuthProviderConfig{
+				Enabled:      true,
+				ClientId:     "",
+				ClientSecret: "",
+				AuthUrl:      "test",
+				TokenUrl:     "test",
+				UserApiUrl:   "test",
+			},
+			true,
+		},
+		// invalid issuer and mappings
+		{
+			settings.AuthProviderConfig{
+				Enabled:       true,
+				ClientId:      "test",
+				ClientSecret:  "test",
+				Issuer:        "test",
+				FieldsMapping: map[string]string{"": "name"},
+				RolesClaim:    strings.Repeat("a", 256),
+			},
+			true,
+		},
+		// valid data (only the required)
+		{
+			settings.AuthProviderConfig{
+				Enabled:      true,
+				ClientId:     "test",
+				ClientSecret: "test",
+			},
+			false,
+		},
+		// valid data (fill all fields)
+		{
+			settings.AuthProviderConfig{
+				Enabled:       true,
+				ClientId:      "test",
+				ClientSecret:  "test",
+				AuthUrl:       "https://example.com",
+				TokenUrl:      "https://example.com",
+				UserApiUrl:    "https://example.com",
+				Issuer:        "https://example.com",
+				FieldsMapping: map[string]string{"given_name": "name"},
+				RolesClaim:    "realm_access.roles",
+			},
+			false,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		result := scenario.config.Validate()
+
+		if result != nil && !scenario.expectError {
+			t.Errorf("(%d) Didn't expect error, got %v", i, result)
+		}
+
+		if result == nil && scenario.expectError {
+			t.Errorf("(%d) Expected error, got nil", i)
+		}
+	}
+}
+
+func TestAuthProviderConfigSetupProvider(t *testing.T) {
+	provider := auth.NewGithubProvider()
+
+	// disabled config
+	c1 := settings.AuthProviderConfig{Enabled: false}
+	if err := c1.SetupProvider(provider); err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+
+	c2 := settings.AuthProviderConfig{
+		Enabled:      true,
+		ClientId:     "test_ClientId",
+		ClientSecret: "test_ClientSecret",
+		AuthUrl:      "test_AuthUrl",
+		UserApiUrl:   "test_UserApiUrl",
+		TokenUrl:     "test_TokenUrl",
+	}
+	if err := c2.SetupProvider(provider); err != nil {
+		t.Error(err)
+	}
+
+	if provider.ClientId() != c2.ClientId {
+		t.Fatalf("Expected ClientId %s, got %s", c2.ClientId, provider.ClientId())
+	}
+
+	if provider.ClientSecret() != c2.ClientSecret {
+		t.Fatalf("Expected ClientSecret %s, got %s", c2.ClientSecret, provider.ClientSecret())
+	}
+
+	if provider.AuthUrl() != c2.AuthUrl {
+		t.Fatalf("Expected AuthUrl %s, got %s", c2.AuthUrl, provider.AuthUrl())
+	}
+
+	if provider.UserApiUrl() != c2.UserApiUrl {
+		t.Fatalf("Expected UserApiUrl %s, got %s", c2.UserApiUrl, provider.UserApiUrl())
+	}
+
+	if provider.TokenUrl() != c2.TokenUrl {
+		t.Fatalf("Expected TokenUrl %s, got %s", c2.TokenUrl, provider.TokenUrl())
+	}
+}
+
+func TestAuthProviderConfigSetupProviderDiscovery(t *testing.T) {
+	var issuer string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"issuer":"` + issuer + `",
+			"authorization_endpoint":"https://example.com/auth",
+			"token_endpoint":"https://example.com/token",
+			"userinfo_endpoint":"https://example.com/userinfo"
+		}`))
+	}))
+	defer server.Close()
+
+	issuer = server.URL
+
+	config := settings.AuthProviderConfig{
+		Enabled:      true,
+		ClientId:     "test_ClientId",
+		ClientSecret: "test_ClientSecret",
+		TokenUrl:     "test_TokenUrl",
+		Issuer:       issuer,
+	}
+
+	provider := auth.NewOIDCProvider()
+	if err := config.SetupProvider(provider); err != nil {
+		t.Fatal(err)
+	}
+
+	if provider.AuthUrl() != "https://example.com/auth" {
+		t.Fatalf("Expected the discovered AuthUrl, got %s", provider.AuthUrl())
+	}
+
+	if provider.UserApiUrl() != "https://example.com/userinfo" {
+		t.Fatalf("Expected the discovered UserApiUrl, got %s", provider.UserApiUrl())
+	}
+
+	// the explicitly set urls have priority
+	if provider.TokenUrl() != config.TokenUrl {
+		t.Fatalf("Expected TokenUrl %s, got %s", config.TokenUrl, provider.TokenUrl())
+	}
+
+	// missing issuer
+	config.Issuer = issuer + "/missing"
+	if err := config.SetupProvider(auth.NewOIDCProvider()); err == nil {
+		t.Fatal("Expected discovery error, got nil")
+	}
+}
+
+func TestPreferencesConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		config      settings.PreferencesConfig
+		expectError bool
+	}{
+		// zero values
+		{
+			settings.PreferencesConfig{},
+			false,
+		},
+		// invalid key data
+		{
+			settings.PreferencesConfig{Keys: []settings.PreferenceKey{
+				{Key: "a-b", Type: settings.PreferenceTypeText},
+			}},
+			true,
+		},
+		{
+			settings.PreferencesConfig{Keys: []settings.PreferenceKey{
+				{Key: "test", Type: "missing"},
+			}},
+			true,
+		},
+		{
+			settings.PreferencesConfig{Keys: []settings.PreferenceKey{
+				{Key: "test", Type: settings.PreferenceTypeSelect},
+			}},
+			true,
+		},
+		{
+			settings.PreferencesConfig{Keys: []settings.PreferenceKey{
+				{Key: "test", Type: settings.PreferenceTypeBool, Default: "true"},
+			}},
+			true,
+		},
+		// duplicated keys
+		{
+			settings.PreferencesConfig{Keys: []settings.PreferenceKey{
+				{Key: "test", Type: settings.PreferenceTypeText},
+				{Key: "test", Type: settings.PreferenceTypeBool},
+			}},
+			true,
+		},
+		// valid data
+		{
+			settings.PreferencesConfig{Keys: []settings.PreferenceKey{
+				{Key: "theme", Type: settings.PreferenceTypeSelect, Values: []string{"light", "dark"}, Default: "light"},
+				{Key: "pageSize", Type: settings.PreferenceTypeNumber, Default: 20},
+				{Key: "newsletter", Type: settings.PreferenceTypeBool},
+			}},
+			false,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		result := scenario.config.Validate()
+
+		if result != nil && !scenario.expectError {
+			t.Errorf("(%d) Didn't expect error, got %v", i, result)
+		}
+
+		if result == nil && scenario.expectError {
+			t.Errorf("(%d) Expected error, got nil", i)
+		}
+	}
+}
+
+func TestRuleMacrosConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		config      settings.RuleMacrosConfig
+		expectError bool
+	}{
+		// zero values
+		{
+			settings.RuleMacrosConfig{},
+			false,
+		},
+		// invalid macro name
+		{
+			settings.RuleMacrosConfig{Macros: []settings.RuleMacro{
+				{Name: "is-owner", Rule: "author = @request.auth.id"},
+			}},
+			true,
+		},
+		// missing rule
+		{
+			settings.RuleMacrosConfig{Macros: []settings.RuleMacro{
+				{Name: "isOwner"},
+			}},
+			true,
+		},
+		// duplicated names
+		{
+			settings.RuleMacrosConfig{Macros: []settings.RuleMacro{
+				{Name: "isOwner", Rule: "author = @request.auth.id"},
+				{Name: "isOwner", Rule: "owner = @request.auth.id"},
+			}},
+			true,
+		},
+		// valid data
+		{
+			settings.RuleMacrosConfig{Macros: []settings.RuleMacro{
+				{Name: "isOwner", Rule: "author = @request.auth.id"},
+				{Name: "isVerified", Rule: "@request.auth.verified = true"},
+			}},
+			false,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		result := scenario.config.Validate()
+
+		if result != nil && !scenario.expectError {
+			t.Errorf("(%d) Didn't expect error, got %v", i, result)
+		}
+
+		if result == nil && scenario.expectError {
+			t.Errorf("(%d) Expected error, got nil", i)
+		}
+	}
+}
+
+func TestPreferenceKeyNormalizeValue(t *testing.T) {
+	scenarios := []struct {
+		key         settings.PreferenceKey
+		value       any
+		expected    any
+		expectError bool
+	}{
+		{settings.PreferenceKey{Type: settings.PreferenceTypeText}, "test", "test", false},
+		{settings.PreferenceKey{Type: settings.PreferenceTypeText}, 123, nil, true},
+		{settings.PreferenceKey{Type: settings.PreferenceTypeNumber}, 123, float64(123), false},
+		{settings.PreferenceKey{Type: settings.PreferenceTypeNumber}, 1.5, 1.5, false},
+		{settings.PreferenceKey{Type: settings.PreferenceTypeNumber}, "123", nil, true},
+		{settings.PreferenceKey{Type: settings.PreferenceTypeBool}, true, true, false},
+		{settings.PreferenceKey{Type: settings.PreferenceTypeBool}, 1, nil, true},
+		{settings.PreferenceKey{Type: settings.PreferenceTypeSelect, Values: []string{"a", "b"}}, "b", "b", false},
+		{settings.PreferenceKey{Type: settings.PreferenceTypeSelect, Values: []string{"a", "b"}}, "c", nil, true},
+		{settings.PreferenceKey{Type: "missing"}, "test", nil, true},
+	}
+
+	for i, s := range scenarios {
+		result, err := s.key.NormalizeValue(s.value)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if result != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+}
+
+func TestPreferencesConfigResolve(t *testing.T) {
+	config := settings.PreferencesConfig{Keys: []settings.PreferenceKey{
+		{Key: "theme", Type: settings.PreferenceTypeSelect, Values: []string{"light", "dark"}, Default: "light"},
+		{Key: "pageSize", Type: settings.PreferenceTypeNumber, Default: 20},
+		{Key: "newsletter", Type: settings.PreferenceTypeBool},
+	}}
+
+	result := config.Resolve(map[string]any{
+		"theme":    "dark",
+		"pageSize": "invalid",
+		"unknown":  123,
+	})
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"newsletter":null,"pageSize":20,"theme":"dark"}`
+	if string(encoded) != expected {
+		t.Fatalf("Expected %s, got %s", expected, encoded)
+	}
+}
+
+func TestClientConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		config      settings.ClientConfig
+		expectError bool
+	}{
+		// zero values
+		{
+			settings.ClientConfig{},
+			false,
+		},
+		// invalid min version
+		{
+			settings.ClientConfig{MinVersion: "1.x"},
+			true,
+		},
+		// invalid flag key
+		{
+			settings.ClientConfig{Flags: map[string]bool{"a-b": true}},
+			true,
+		},
+		// invalid public key
+		{
+			settings.ClientConfig{Public: map[string]any{"": "test"}},
+			true,
+		},
+		// valid data
+		{
+			settings.ClientConfig{
+				MinVersion: "v1.2.3-beta.1",
+				Flags:      map[string]bool{"newCheckout": true, "darkMode": false},
+				Public:     map[string]any{"supportEmail": "support@example.com", "maxUploads": 10},
+			},
+			false,
+		},
+	}
+
+	for i, scenario := range scenarios {
+		result := scenario.config.Validate()
+
+		if result != nil && !scenario.expectError {
+			t.Errorf("(%d) Didn't expect error, got %v", i, result)
+		}
+
+		if result == nil && scenario.expectError {
+			t.Errorf("(%d) Expected error, got nil", i)
+		}
 	}
 }
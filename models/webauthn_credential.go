@@ -0,0 +1,38 @@
+package models
+
+import (
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+var _ Model = (*WebauthnCredential)(nil)
+
+// WebauthnCredential defines a WebAuthn (passkey) public key
+// credential registered for an auth record.
+//
+// An auth record could have multiple credentials (eg. one per device).
+type WebauthnCredential struct {
+	BaseModel
+
+	CollectionId string `db:"collectionId" json:"collectionId"`
+	RecordId     string `db:"recordId" json:"recordId"`
+	Name         string `db:"name" json:"name"`
+
+	// CredentialId is the base64url encoded authenticator credential id.
+	CredentialId string `db:"credentialId" json:"credentialId"`
+
+	// PublicKey is the base64url encoded credential public key (PKIX, ASN.1 DER form).
+	PublicKey string `db:"publicKey" json:"-"`
+
+	// Algorithm is the COSE identifier of the credential signature algorithm.
+	Algorithm int `db:"algorithm" json:"algorithm"`
+
+	// SignCount is the last known authenticator signature counter.
+	SignCount int64 `db:"signCount" json:"-"`
+
+	// LastUsed is the datetime of the last successful authentication.
+	LastUsed types.DateTime `db:"lastUsed" json:"lastUsed"`
+}
+
+func (m *WebauthnCredential) TableName() string {
+	return "_webauthnCredentials"
+}
@@ -0,0 +1,14 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+)
+
+func TestWebauthnCredentialTableName(t *testing.T) {
+	m := models.WebauthnCredential{}
+	if m.TableName() != "_webauthnCredentials" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
@@ -0,0 +1,210 @@
+package devmode
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const consolePath = "/api/dev/console"
+
+const (
+	entryTypeRequest = "request"
+	entryTypeHook    = "hook"
+	entryTypeWatch   = "watch"
+)
+
+// consoleBufferSize is the max number of pending entries per console
+// connection (entries are dropped for slow clients).
+const consoleBufferSize = 100
+
+// ConsoleEntry defines a single dev console log entry.
+type ConsoleEntry struct {
+	Type string         `json:"type"`
+	Time types.DateTime `json:"time"`
+	Data map[string]any `json:"data"`
+}
+
+// console fan-outs the published entries to all connected clients.
+type console struct {
+	mux     sync.RWMutex
+	clients map[chan *ConsoleEntry]struct{}
+}
+
+func newConsole() *console {
+	return &console{
+		clients: map[chan *ConsoleEntry]struct{}{},
+	}
+}
+
+func (c *console) subscribe() chan *ConsoleEntry {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	ch := make(chan *ConsoleEntry, consoleBufferSize)
+	c.clients[ch] = struct{}{}
+
+	return ch
+}
+
+func (c *console) unsubscribe(ch chan *ConsoleEntry) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	delete(c.clients, ch)
+}
+
+func (c *console) publish(entryType string, data map[string]any) {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	if len(c.clients) == 0 {
+		return
+	}
+
+	entry := &ConsoleEntry{
+		Type: entryType,
+		Time: types.NowDateTime(),
+		Data: data,
+	}
+
+	for ch := range c.clients {
+		select {
+		case ch <- entry:
+		default:
+			// slow client
+		}
+	}
+}
+
+// consoleHandler streams the console entries to a local client as SSE messages.
+func (p *plugin) consoleHandler(c echo.Context) error {
+	if !isLoopbackRequest(c.Request()) {
+		return apis.NewForbiddenError("The dev console is available only for local requests.", nil)
+	}
+
+	ch := p.console.subscribe()
+	defer p.console.unsubscribe(ch)
+
+	c.Response().Header().Set("Content-Type", "text/event-stream; charset=UTF-8")
+	c.Response().Header().Set("Cache-Control", "no-store")
+	c.Response().Header().Set("Connection", "keep-alive")
+	c.Response().Header().Set("X-Accel-Buffering", "no")
+
+	w := c.Response()
+	fmt.Fprint(w, "event:PB_DEV_CONNECT\n")
+	fmt.Fprint(w, "data:{}\n\n")
+	w.Flush()
+
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case entry := <-ch:
+			encoded, err := json.Marshal(entry)
+			if err != nil {
+				continue
+			}
+
+			fmt.Fprint(w, "event:"+entry.Type+"\n")
+			fmt.Fprint(w, "data:"+string(encoded)+"\n\n")
+			w.Flush()
+		}
+	}
+}
+
+// requestLogger returns a middleware that publishes
+// a console entry for each handled request.
+func (p *plugin) requestLogger() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			err := next(c)
+
+			if c.Request().URL.Path == consolePath {
+				return err
+			}
+
+			status := c.Response().Status
+			data := map[string]any{
+				"method": strings.ToUpper(c.Request().Method),
+				"url":    c.Request().URL.RequestURI(),
+			}
+
+			if err != nil {
+				switch v := err.(type) {
+				case *echo.HTTPError:
+					status = v.Code
+					data["error"] = fmt.Sprint(v.Message)
+				case *apis.ApiError:
+					status = v.Code
+					data["error"] = v.Message
+				default:
+					status = http.StatusBadRequest
+					data["error"] = v.Error()
+				}
+			}
+
+			data["status"] = status
+			data["duration"] = time.Since(start).Milliseconds()
+
+			if requestId, _ := c.Get(apis.ContextRequestIdKey).(string); requestId != "" {
+				data["requestId"] = requestId
+			}
+
+			p.console.publish(entryTypeRequest, data)
+
+			return err
+		}
+	}
+}
+
+// bindHooks registers the app hook handlers that publish console entries.
+func (p *plugin) bindHooks() {
+	modelHook := func(action string) func(e *core.ModelEvent) error {
+		return func(e *core.ModelEvent) error {
+			p.console.publish(entryTypeHook, map[string]any{
+				"hook":  "model" + action,
+				"table": e.Model.TableName(),
+				"id":    e.Model.GetId(),
+			})
+			return nil
+		}
+	}
+
+	p.app.OnModelAfterCreate().Add(modelHook("AfterCreate"))
+	p.app.OnModelAfterUpdate().Add(modelHook("AfterUpdate"))
+	p.app.OnModelAfterDelete().Add(modelHook("AfterDelete"))
+
+	p.app.OnAfterApiError().Add(func(e *core.ApiErrorEvent) error {
+		p.console.publish(entryTypeHook, map[string]any{
+			"hook":  "apiError",
+			"url":   e.HttpContext.Request().URL.RequestURI(),
+			"error": e.Error.Error(),
+		})
+		return nil
+	})
+}
+
+// isLoopbackRequest checks whether the request is from a loopback
+// address (the proxy headers are intentionally ignored).
+func isLoopbackRequest(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,162 @@
+// Package devmode adds a local development mode to a PocketBase instance.
+//
+// When registered, while serving, the plugin:
+//   - watches the pb_hooks, pb_migrations and templates directories
+//     and restarts the app process on change (aka. hot reload)
+//   - exposes a local-only "GET /api/dev/console" SSE endpoint that
+//     streams structured request, hook and watch log entries
+//
+// Example usage:
+//
+//	var dev bool
+//	app.RootCmd.PersistentFlags().BoolVar(&dev, "dev", false, "enable the dev mode")
+//	app.RootCmd.ParseFlags(os.Args[1:])
+//
+//	if dev {
+//		devmode.MustRegister(app, &devmode.Options{
+//			WatchDirs:    []string{"pb_hooks"}, // optional; default to pb_hooks, pb_migrations and templates next to pb_data
+//			PollInterval: time.Second,          // optional; default to 500ms
+//		})
+//	}
+//
+// The console could be followed with any SSE client, for example:
+//
+//	curl -N http://127.0.0.1:8090/api/dev/console
+//
+// Note: The plugin is intended only for local development and
+// it must not be enabled in production.
+package devmode
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/routine"
+)
+
+// DefaultPollInterval is the default watched directories poll interval.
+const DefaultPollInterval = 500 * time.Millisecond
+
+// Options defines optional struct to customize the default plugin behavior.
+type Options struct {
+	// WatchDirs specifies the directories to watch for changes.
+	//
+	// If not set it fallbacks to the relative "pb_data/../pb_hooks",
+	// "pb_data/../pb_migrations" and "pb_data/../templates" directories.
+	WatchDirs []string
+
+	// PollInterval specifies how often to check the watched directories for changes.
+	//
+	// If not set it fallbacks to [DefaultPollInterval].
+	PollInterval time.Duration
+
+	// OnChange is called with the list of the changed files.
+	//
+	// If not set, the app process is restarted.
+	OnChange func(changed []string)
+}
+
+type plugin struct {
+	app     core.App
+	options *Options
+	console *console
+}
+
+// MustRegister registers the dev mode plugin to
+// the provided app instance and panics if it fails.
+//
+// Internally it calls Register(app, options).
+func MustRegister(app core.App, options *Options) {
+	if err := Register(app, options); err != nil {
+		panic(err)
+	}
+}
+
+// Register registers the dev mode plugin to the provided app instance.
+func Register(app core.App, options *Options) error {
+	p := &plugin{app: app, console: newConsole()}
+
+	if options != nil {
+		p.options = options
+	} else {
+		p.options = &Options{}
+	}
+
+	if len(p.options.WatchDirs) == 0 {
+		p.options.WatchDirs = []string{
+			filepath.Join(app.DataDir(), "../pb_hooks"),
+			filepath.Join(app.DataDir(), "../pb_migrations"),
+			filepath.Join(app.DataDir(), "../templates"),
+		}
+	}
+
+	if p.options.PollInterval <= 0 {
+		p.options.PollInterval = DefaultPollInterval
+	}
+
+	if p.options.OnChange == nil {
+		p.options.OnChange = p.restart
+	}
+
+	p.bindHooks()
+
+	p.app.OnBeforeServe().Add(func(e *core.ServeEvent) error {
+		e.Router.Use(p.requestLogger())
+		e.Router.GET(consolePath, p.consoleHandler)
+
+		w := newWatcher(p.options.WatchDirs)
+
+		routine.FireAndForget(func() {
+			ticker := time.NewTicker(p.options.PollInterval)
+			defer ticker.Stop()
+
+			for range ticker.C {
+				changed := w.check()
+				if len(changed) == 0 {
+					continue
+				}
+
+				p.console.publish(entryTypeWatch, map[string]any{"changed": changed})
+
+				p.options.OnChange(changed)
+			}
+		})
+
+		return nil
+	})
+
+	return nil
+}
+
+// restart replaces the current process with a new instance of the
+// same executable (and args) so that all watched files are reloaded.
+func (p *plugin) restart(changed []string) {
+	// process replacement is not supported on Windows
+	if runtime.GOOS == "windows" {
+		color.Yellow("%s changed, please restart the app.", strings.Join(changed, ", "))
+		return
+	}
+
+	color.Yellow("%s changed, restarting...", strings.Join(changed, ", "))
+
+	execPath, err := os.Executable()
+	if err != nil {
+		log.Printf("Failed to resolve the app executable: %v\n", err)
+		return
+	}
+
+	if err := p.app.ResetBootstrapState(); err != nil {
+		log.Printf("Failed to release the app resources: %v\n", err)
+	}
+
+	if err := syscall.Exec(execPath, os.Args, os.Environ()); err != nil {
+		color.Yellow("Failed to restart the app (%v), please restart it manually.", err)
+	}
+}
@@ -0,0 +1,121 @@
+package devmode_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/plugins/devmode"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestWatchChanges(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dir := t.TempDir()
+
+	changes := make(chan []string, 10)
+
+	devmode.MustRegister(app, &devmode.Options{
+		WatchDirs:    []string{dir, filepath.Join(dir, "missing")},
+		PollInterval: 10 * time.Millisecond,
+		OnChange: func(changed []string) {
+			select {
+			case changes <- changed:
+			default:
+			}
+		},
+	})
+
+	// starts the watcher
+	if _, err := apis.InitApi(app); err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(dir, "sub", "main.pb.js")
+	if err := os.MkdirAll(filepath.Dir(file), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte("test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case changed := <-changes:
+		if len(changed) != 1 || changed[0] != file {
+			t.Fatalf("Expected changed files [%s], got %v", file, changed)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the file change to be detected")
+	}
+}
+
+func TestConsole(t *testing.T) {
+	factory := func() (*tests.TestApp, error) {
+		app, err := tests.NewTestApp()
+		if err != nil {
+			return nil, err
+		}
+
+		devmode.MustRegister(app, &devmode.Options{
+			WatchDirs: []string{filepath.Join(app.DataDir(), "missing")},
+			OnChange:  func(changed []string) {},
+		})
+
+		return app, nil
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "non local request",
+			Method:          http.MethodGet,
+			Url:             "/api/dev/console",
+			TestAppFactory:  factory,
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+		},
+		{
+			Name:           "local request",
+			Method:         http.MethodGet,
+			Url:            "/api/dev/console",
+			TestAppFactory: factory,
+			BeforeTestFunc: func(t *testing.T, app *tests.TestApp, e *echo.Echo) {
+				e.Pre(func(next echo.HandlerFunc) echo.HandlerFunc {
+					return func(c echo.Context) error {
+						c.Request().RemoteAddr = "127.0.0.1:1234"
+
+						// generate a hook entry while the console is connected
+						go func() {
+							time.Sleep(20 * time.Millisecond)
+							record, _ := app.Dao().FindRecordById("demo2", "llvuca81nly1qls")
+							app.Dao().SaveRecord(record)
+						}()
+
+						return next(c)
+					}
+				})
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				"event:PB_DEV_CONNECT",
+				"event:hook",
+				`"hook":"modelAfterUpdate"`,
+				`"table":"demo2"`,
+				`"id":"llvuca81nly1qls"`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnModelBeforeUpdate": 1,
+				"OnModelAfterUpdate":  1,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
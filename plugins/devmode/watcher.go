@@ -0,0 +1,82 @@
+package devmode
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// watcher detects file changes in a set of directories by
+// comparing consecutive snapshots of their files state.
+//
+// Polling is used instead of OS notifications to keep the plugin
+// dependency free and working consistently across platforms.
+type watcher struct {
+	dirs     []string
+	snapshot map[string]fileState
+}
+
+func newWatcher(dirs []string) *watcher {
+	w := &watcher{dirs: dirs}
+
+	w.snapshot = w.scan()
+
+	return w
+}
+
+// check returns the sorted list of the created, modified
+// or deleted files since the previous check.
+func (w *watcher) check() []string {
+	current := w.scan()
+
+	changed := []string{}
+
+	for path, state := range current {
+		if old, ok := w.snapshot[path]; !ok || old != state {
+			changed = append(changed, path)
+		}
+	}
+
+	for path := range w.snapshot {
+		if _, ok := current[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+
+	w.snapshot = current
+
+	sort.Strings(changed)
+
+	return changed
+}
+
+// scan walks the watched directories and returns their files state
+// (missing directories are ignored).
+func (w *watcher) scan() map[string]fileState {
+	result := map[string]fileState{}
+
+	for _, dir := range w.dirs {
+		filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+
+			result[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+
+			return nil
+		})
+	}
+
+	return result
+}
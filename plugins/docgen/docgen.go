@@ -0,0 +1,333 @@
+// Package docgen adds support for generating documents (eg. invoices,
+// certificates, etc.) from records through HTML templates.
+//
+// The templates are regular [html/template] files stored in
+// "{Dir}/{collectionName}/{templateName}.html" and they are rendered
+// with the following data:
+//
+//	AppName string
+//	AppUrl  string
+//	Data    map[string]any // the record public export (including its expand), eg. {{.Data.title}} or {{.Data.expand.customer.name}}
+//	Now     time.Time
+//
+// The raw record model is intentionally not exposed to the templates
+// so that the documents contain only the record fields (and expanded
+// relations) that the current request is allowed to view.
+//
+// The resolved HTML is converted into the final document with the
+// configured [Renderer] ([HTMLRenderer] by default). To generate PDF
+// documents you could use for example a [CommandRenderer]:
+//
+//	docgen.MustRegister(app, &docgen.Options{
+//		Dir:      "templates_dir_path", // optional; default to "pb_data/../templates"
+//		Renderer: &docgen.CommandRenderer{Command: "wkhtmltopdf", Args: []string{"--quiet", "-", "-"}},
+//	})
+//
+// The plugin registers the following endpoints:
+//
+//	// renders and returns the document (accessible by everyone who could view the record)
+//	GET /api/collections/{collection}/records/{id}/documents/{template}?expand=customer&download=1
+//
+//	// renders and stores the document in the specified record file field (admins only)
+//	POST /api/collections/{collection}/records/{id}/documents/{template}
+//	{"field": "invoice"}
+//
+// The same could be also done from a hook with a [Generator] instance:
+//
+//	generator := docgen.NewGenerator(app, options)
+//
+//	app.OnModelAfterCreate("orders").Add(func(e *core.ModelEvent) error {
+//		return generator.Store(e.Model.(*models.Record), "invoice", "invoice", "customer")
+//	})
+package docgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v5"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/resolvers"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+// templateNameRegex specifies the allowed template names
+// (also prevents resolving files outside of the templates dir).
+var templateNameRegex = regexp.MustCompile(`^[\w\-]+$`)
+
+// Options defines optional struct to customize the default plugin behavior.
+type Options struct {
+	// Dir specifies the directory with the collections document templates.
+	//
+	// If not set it fallbacks to a relative "pb_data/../templates" directory.
+	Dir string
+
+	// Renderer specifies the renderer used to convert the
+	// resolved HTML templates into the final documents.
+	//
+	// If not set it fallbacks to [HTMLRenderer].
+	Renderer Renderer
+}
+
+// Document defines a single generated document.
+type Document struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+// Generator generates documents from records.
+type Generator struct {
+	app     core.App
+	options *Options
+}
+
+// NewGenerator creates a new document Generator instance
+// initialized with the provided app and options.
+func NewGenerator(app core.App, options *Options) *Generator {
+	g := &Generator{app: app, options: &Options{}}
+
+	if options != nil {
+		*g.options = *options
+	}
+
+	if g.options.Dir == "" {
+		g.options.Dir = filepath.Join(app.DataDir(), "../templates")
+	}
+
+	if g.options.Renderer == nil {
+		g.options.Renderer = &HTMLRenderer{}
+	}
+
+	return g
+}
+
+// MustRegister registers the docgen plugin to
+// the provided app instance and panics if it fails.
+//
+// Internally it calls Register(app, options).
+func MustRegister(app core.App, options *Options) {
+	if err := Register(app, options); err != nil {
+		panic(err)
+	}
+}
+
+// Register registers the docgen plugin endpoints to the provided app instance.
+func Register(app core.App, options *Options) error {
+	g := NewGenerator(app, options)
+
+	app.OnBeforeServe().Add(func(e *core.ServeEvent) error {
+		subGroup := e.Router.Group(
+			"/api/collections/:collection/records/:id/documents",
+			apis.ActivityLogger(app),
+			apis.LoadCollectionContext(app),
+		)
+		subGroup.GET("/:template", g.viewHandler)
+		subGroup.POST("/:template", g.storeHandler, apis.RequireAdminAuth())
+
+		return nil
+	})
+
+	return nil
+}
+
+// Render resolves the specified collection template with the
+// provided record data and returns the generated document.
+//
+// The optional expands are loaded without the collections API rules check.
+func (g *Generator) Render(record *models.Record, templateName string, expands ...string) (*Document, error) {
+	if len(expands) > 0 {
+		fetchFunc := func(relCollection *models.Collection, relIds []string) ([]*models.Record, error) {
+			return g.app.Dao().FindRecordsByIds(relCollection.Id, relIds)
+		}
+
+		if failed := g.app.Dao().ExpandRecord(record, expands, fetchFunc); len(failed) > 0 && g.app.IsDebug() {
+			log.Println("Failed to expand relations: ", failed)
+		}
+	}
+
+	return g.render(record, templateName)
+}
+
+// Store generates a new document from the specified template and
+// stores it in the provided record file field.
+//
+// If the file field is a single-value field, the new document
+// replaces the old one, otherwise it is appended to the existing files.
+func (g *Generator) Store(record *models.Record, templateName string, fieldName string, expands ...string) error {
+	doc, err := g.Render(record, templateName, expands...)
+	if err != nil {
+		return err
+	}
+
+	file, err := filesystem.NewFileFromBytes(doc.Content, doc.Name)
+	if err != nil {
+		return err
+	}
+
+	form := forms.NewRecordUpsert(g.app, record)
+	form.SetFullManageAccess(true)
+
+	if err := form.AddFiles(fieldName, file); err != nil {
+		return validation.Errors{"field": validation.NewError(
+			"validation_invalid_file_field",
+			"Invalid or missing record file field.",
+		)}
+	}
+
+	return form.Submit()
+}
+
+func (g *Generator) render(record *models.Record, templateName string) (*Document, error) {
+	if !templateNameRegex.MatchString(templateName) {
+		return nil, errors.New("invalid template name")
+	}
+
+	path := filepath.Join(g.options.Dir, record.Collection().Name, templateName+".html")
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load template %q: %w", templateName, err)
+	}
+
+	tmpl, err := template.New(templateName).Parse(string(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %q: %w", templateName, err)
+	}
+
+	// normalize the record public export (including the expanded
+	// relation records) to plain values
+	raw, err = json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	export := map[string]any{}
+	if err := json.Unmarshal(raw, &export); err != nil {
+		return nil, err
+	}
+
+	data := map[string]any{
+		"AppName": g.app.Settings().Meta.AppName,
+		"AppUrl":  g.app.Settings().Meta.AppUrl,
+		"Data":    export,
+		"Now":     time.Now().UTC(),
+	}
+
+	var html bytes.Buffer
+	if err := tmpl.Execute(&html, data); err != nil {
+		return nil, fmt.Errorf("failed to execute template %q: %w", templateName, err)
+	}
+
+	content, err := g.options.Renderer.Render(html.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Document{
+		Name:        templateName + "_" + record.Id + g.options.Renderer.Extension(),
+		ContentType: g.options.Renderer.ContentType(),
+		Content:     content,
+	}, nil
+}
+
+// findViewableRecord returns the context collection record
+// that the current request is allowed to view.
+func (g *Generator) findViewableRecord(c echo.Context) (*models.Record, error) {
+	collection, _ := c.Get(apis.ContextCollectionKey).(*models.Collection)
+	if collection == nil {
+		return nil, apis.NewNotFoundError("Missing collection context.", nil)
+	}
+
+	requestData := apis.RequestData(c)
+
+	if requestData.Admin == nil && collection.ViewRule == nil {
+		// only admins can access if the rule is nil
+		return nil, apis.NewForbiddenError("Only admins can perform this action.", nil)
+	}
+
+	ruleFunc := func(q *dbx.SelectQuery) error {
+		if requestData.Admin == nil && collection.ViewRule != nil && *collection.ViewRule != "" {
+			resolver := resolvers.NewRecordFieldResolver(g.app.Dao(), collection, requestData, true)
+			expr, err := search.FilterData(*collection.ViewRule).BuildExpr(resolver)
+			if err != nil {
+				return err
+			}
+			resolver.UpdateQuery(q)
+			q.AndWhere(expr)
+		}
+		return nil
+	}
+
+	record, err := g.app.Dao().FindRecordById(collection.Id, c.PathParam("id"), ruleFunc)
+	if err != nil || record == nil {
+		return nil, apis.NewNotFoundError("", err)
+	}
+
+	return record, nil
+}
+
+func (g *Generator) viewHandler(c echo.Context) error {
+	record, err := g.findViewableRecord(c)
+	if err != nil {
+		return err
+	}
+
+	// load the requested expands and hide the restricted fields
+	if err := apis.EnrichRecord(c, g.app.Dao(), record); err != nil && g.app.IsDebug() {
+		log.Println(err)
+	}
+
+	doc, err := g.render(record, c.PathParam("template"))
+	if err != nil {
+		return apis.NewBadRequestError("Failed to generate the document.", err)
+	}
+
+	disposition := "inline"
+	if c.QueryParam("download") != "" {
+		disposition = "attachment"
+	}
+
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("%s; filename=%q", disposition, doc.Name))
+
+	return c.Blob(http.StatusOK, doc.ContentType, doc.Content)
+}
+
+func (g *Generator) storeHandler(c echo.Context) error {
+	record, err := g.findViewableRecord(c)
+	if err != nil {
+		return err
+	}
+
+	data := struct {
+		Field string `json:"field" form:"field"`
+	}{}
+	if err := c.Bind(&data); err != nil {
+		return apis.NewBadRequestError("An error occurred while loading the submitted data.", err)
+	}
+
+	if err := apis.EnrichRecord(c, g.app.Dao(), record); err != nil && g.app.IsDebug() {
+		log.Println(err)
+	}
+
+	if err := g.Store(record, c.PathParam("template"), data.Field); err != nil {
+		return apis.NewBadRequestError("Failed to store the document.", err)
+	}
+
+	return c.JSON(http.StatusOK, record)
+}
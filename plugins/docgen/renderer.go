@@ -0,0 +1,129 @@
+package docgen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Renderer converts the resolved HTML template content into the final document.
+type Renderer interface {
+	// Render converts the provided HTML content into the final document.
+	Render(html []byte) ([]byte, error)
+
+	// ContentType returns the mime type of the generated documents.
+	ContentType() string
+
+	// Extension returns the file extension (including the leading dot)
+	// of the generated documents.
+	Extension() string
+}
+
+// -------------------------------------------------------------------
+
+var _ Renderer = (*HTMLRenderer)(nil)
+
+// HTMLRenderer is the default [Renderer] that returns the resolved
+// HTML template content as it is (eg. for printing from the browser).
+type HTMLRenderer struct{}
+
+// Render implements [Renderer.Render] interface method.
+func (r *HTMLRenderer) Render(html []byte) ([]byte, error) {
+	return html, nil
+}
+
+// ContentType implements [Renderer.ContentType] interface method.
+func (r *HTMLRenderer) ContentType() string {
+	return "text/html; charset=utf-8"
+}
+
+// Extension implements [Renderer.Extension] interface method.
+func (r *HTMLRenderer) Extension() string {
+	return ".html"
+}
+
+// -------------------------------------------------------------------
+
+// DefaultCommandTimeout is the default max [CommandRenderer] execution time.
+const DefaultCommandTimeout = 30 * time.Second
+
+var _ Renderer = (*CommandRenderer)(nil)
+
+// CommandRenderer is a [Renderer] that pipes the resolved HTML content
+// to the stdin of an external command and uses its stdout as the
+// generated document (by default a PDF).
+//
+// Example:
+//
+//	&docgen.CommandRenderer{Command: "wkhtmltopdf", Args: []string{"--quiet", "-", "-"}}
+type CommandRenderer struct {
+	// Command is the name or path of the executable to run.
+	Command string
+
+	// Args is the list of the command arguments.
+	Args []string
+
+	// Timeout specifies the max command execution time
+	// (default to [DefaultCommandTimeout]).
+	Timeout time.Duration
+
+	// Mime specifies the generated documents mime type (default to "application/pdf").
+	Mime string
+
+	// Ext specifies the generated documents file extension (default to ".pdf").
+	Ext string
+}
+
+// Render implements [Renderer.Render] interface method.
+func (r *CommandRenderer) Render(html []byte) ([]byte, error) {
+	if r.Command == "" {
+		return nil, errors.New("missing renderer command")
+	}
+
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = DefaultCommandTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, r.Command, r.Args...)
+	cmd.Stdin = bytes.NewReader(html)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to render the document: %w (%s)", err, strings.TrimSpace(stderr.String()))
+	}
+
+	if stdout.Len() == 0 {
+		return nil, errors.New("the renderer command returned an empty document")
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// ContentType implements [Renderer.ContentType] interface method.
+func (r *CommandRenderer) ContentType() string {
+	if r.Mime == "" {
+		return "application/pdf"
+	}
+
+	return r.Mime
+}
+
+// Extension implements [Renderer.Extension] interface method.
+func (r *CommandRenderer) Extension() string {
+	if r.Ext == "" {
+		return ".pdf"
+	}
+
+	return r.Ext
+}
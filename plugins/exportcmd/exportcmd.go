@@ -0,0 +1,461 @@
+// Package exportcmd adds a new "export" command support to a PocketBase instance.
+//
+// The command incrementally exports the collections records as Parquet files
+// (based on the records (updated, id) cursor from the previous export), which is
+// useful for analytics pipelines that shouldn't query the live SQLite database.
+//
+// Example usage:
+//
+//	exportcmd.MustRegister(app, app.RootCmd, &exportcmd.Options{
+//		Dir:         "exports_dir_path", // optional exports path; default to "pb_data/exports"
+//		Collections: []string{"posts"},  // optional; default to all collections
+//		Interval:    time.Hour,          // optional; auto export on the specified interval while serving
+//	})
+//
+// The exported files could be queried directly with DuckDB, for example:
+//
+//	SELECT * FROM read_parquet('pb_data/exports/posts/*.parquet');
+//
+// Note: Deleted records are not tracked and only the new or updated
+// records since the last export are written in a new file.
+//
+// If the app settings signing is enabled, each exported file is accompanied
+// by a detached "{file}.sig" HMAC signature that could be verified
+// with [signature.VerifyFile].
+package exportcmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tools/parquet"
+	"github.com/pocketbase/pocketbase/tools/routine"
+	"github.com/pocketbase/pocketbase/tools/signature"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/spf13/cobra"
+)
+
+// CursorsFile is the name of the file (relative to the exports dir)
+// that stores the last exported record position of each collection.
+const CursorsFile = "cursors.json"
+
+// batchSize is the max number of records loaded at once
+// (each batch is written as a separate Parquet row group).
+const batchSize = 1000
+
+// Options defines optional struct to customize the default plugin behavior.
+type Options struct {
+	// Dir specifies the directory where the Parquet files will be stored.
+	//
+	// If not set it fallbacks to a relative "pb_data/exports" directory.
+	Dir string
+
+	// Collections specifies the names of the collections to export.
+	//
+	// If not set, all collections will be exported.
+	Collections []string
+
+	// Interval specifies the auto export interval while serving.
+	//
+	// Zero or negative value disables the auto export.
+	Interval time.Duration
+}
+
+// Cursor defines the position of the last exported collection record.
+//
+// The records are exported in (updated, id) order, so the cursor
+// stores both values to resume after records sharing the same "updated".
+type Cursor struct {
+	Updated string `json:"updated"`
+	Id      string `json:"id"`
+}
+
+// UnmarshalJSON implements the [json.Unmarshaler] interface.
+//
+// For backward compatibility it also accepts the older plain
+// "updated" string cursor format.
+func (c *Cursor) UnmarshalJSON(data []byte) error {
+	var updated string
+	if err := json.Unmarshal(data, &updated); err == nil {
+		*c = Cursor{Updated: updated}
+		return nil
+	}
+
+	type alias Cursor // alias to prevent recursion
+
+	return json.Unmarshal(data, (*alias)(c))
+}
+
+// IsZero checks whether the cursor is not set.
+func (c Cursor) IsZero() bool {
+	return c.Updated == "" && c.Id == ""
+}
+
+type plugin struct {
+	app     core.App
+	options *Options
+}
+
+func MustRegister(app core.App, rootCmd *cobra.Command, options *Options) {
+	if err := Register(app, rootCmd, options); err != nil {
+		panic(err)
+	}
+}
+
+func Register(app core.App, rootCmd *cobra.Command, options *Options) error {
+	p := &plugin{app: app}
+
+	if options != nil {
+		p.options = options
+	} else {
+		p.options = &Options{}
+	}
+
+	if p.options.Dir == "" {
+		p.options.Dir = filepath.Join(p.app.DataDir(), "exports")
+	}
+
+	// attach the export command
+	if rootCmd != nil {
+		rootCmd.AddCommand(p.createCommand())
+	}
+
+	// schedule the auto export
+	if p.options.Interval > 0 {
+		p.app.OnBeforeServe().Add(func(e *core.ServeEvent) error {
+			routine.FireAndForget(func() {
+				ticker := time.NewTicker(p.options.Interval)
+				defer ticker.Stop()
+
+				for range ticker.C {
+					if err := p.export(p.options.Collections); err != nil {
+						log.Printf("Auto export error: %v\n", err)
+					}
+				}
+			})
+
+			return nil
+		})
+	}
+
+	return nil
+}
+
+func (p *plugin) createCommand() *cobra.Command {
+	var collections []string
+	var interval time.Duration
+
+	command := &cobra.Command{
+		Use:   "export",
+		Short: "Exports the new or updated collections records as Parquet files",
+		Run: func(command *cobra.Command, args []string) {
+			if len(collections) == 0 {
+				collections = p.options.Collections
+			}
+
+			for {
+				err := p.export(collections)
+
+				if interval <= 0 {
+					if err != nil {
+						log.Fatal(err)
+					}
+					return
+				}
+
+				if err != nil {
+					log.Println(err)
+				}
+
+				time.Sleep(interval)
+			}
+		},
+	}
+
+	command.PersistentFlags().StringSliceVar(
+		&collections,
+		"collections",
+		nil,
+		"comma separated names of the collections to export (default to all)",
+	)
+
+	command.PersistentFlags().DurationVar(
+		&interval,
+		"interval",
+		0,
+		"keep running and export on the specified interval (eg. 1h)",
+	)
+
+	return command
+}
+
+func (p *plugin) export(collections []string) error {
+	result, err := Export(p.app, p.options.Dir, collections...)
+
+	for name, total := range result {
+		if total > 0 {
+			log.Printf("Exported %d %q record(s).\n", total, name)
+		}
+	}
+
+	return err
+}
+
+// Export exports the new or updated records since the last export of
+// the specified collections (or all collections if none are specified)
+// as new Parquet files in "dir/[collectionName]/".
+//
+// It returns the number of exported records per collection name.
+func Export(app core.App, dir string, collectionNames ...string) (map[string]int, error) {
+	collections := []*models.Collection{}
+
+	if len(collectionNames) == 0 {
+		if err := app.Dao().CollectionQuery().OrderBy("created ASC").All(&collections); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, name := range collectionNames {
+			collection, err := app.Dao().FindCollectionByNameOrId(name)
+			if err != nil {
+				return nil, fmt.Errorf("Missing collection %q.", name)
+			}
+			collections = append(collections, collection)
+		}
+	}
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	cursors, err := readCursors(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int, len(collections))
+
+	for _, collection := range collections {
+		total, cursor, err := exportCollection(app, dir, collection, cursors[collection.Id])
+		result[collection.Name] = total
+		if err != nil {
+			return result, fmt.Errorf("Failed to export collection %q: %w", collection.Name, err)
+		}
+
+		if total == 0 {
+			continue
+		}
+
+		cursors[collection.Id] = cursor
+
+		if err := writeCursors(dir, cursors); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+func exportCollection(
+	app core.App,
+	dir string,
+	collection *models.Collection,
+	cursor Cursor,
+) (total int, newCursor Cursor, err error) {
+	// the upper bound ensures that the records changed during the export
+	// will be picked up by the next export (their "updated" becomes
+	// greater than any cursor position from the current one)
+	maxUpdated := types.NowDateTime().String()
+
+	columns := collectionColumns(collection)
+
+	collectionDir := filepath.Join(dir, collection.Name)
+	if err := os.MkdirAll(collectionDir, os.ModePerm); err != nil {
+		return 0, Cursor{}, err
+	}
+
+	filePath := filepath.Join(
+		collectionDir,
+		time.Now().UTC().Format("20060102150405.000")+".parquet",
+	)
+	tempPath := filePath + ".tmp"
+
+	file, err := os.Create(tempPath)
+	if err != nil {
+		return 0, Cursor{}, err
+	}
+	defer func() {
+		file.Close()
+		if total == 0 || err != nil {
+			os.Remove(tempPath)
+		}
+	}()
+
+	writer := parquet.NewWriter(file, columns)
+
+	newCursor = cursor
+
+	// keyset paging over (updated, id) so that changes during the export
+	// can't shift the batches and skip records
+	for {
+		query := app.Dao().RecordQuery(collection).
+			AndWhere(dbx.NewExp("[[updated]] <= {:maxUpdated}", dbx.Params{"maxUpdated": maxUpdated})).
+			OrderBy("updated ASC", "id ASC").
+			Limit(batchSize)
+
+		if newCursor.Id != "" {
+			query.AndWhere(dbx.NewExp(
+				"([[updated]] > {:cursorUpdated} OR ([[updated]] = {:cursorUpdated} AND [[id]] > {:cursorId}))",
+				dbx.Params{"cursorUpdated": newCursor.Updated, "cursorId": newCursor.Id},
+			))
+		} else if newCursor.Updated != "" {
+			// legacy cursor without id
+			query.AndWhere(dbx.NewExp("[[updated]] > {:cursorUpdated}", dbx.Params{"cursorUpdated": newCursor.Updated}))
+		}
+
+		rows := []dbx.NullStringMap{}
+		if err := query.All(&rows); err != nil {
+			return 0, Cursor{}, err
+		}
+
+		if err := app.Dao().DecryptRecordRows(collection, rows...); err != nil {
+			return 0, Cursor{}, err
+		}
+
+		for _, record := range models.NewRecordsFromNullStringMaps(collection, rows) {
+			if err := writer.Write(recordRow(record, columns)); err != nil {
+				return 0, Cursor{}, err
+			}
+			newCursor = Cursor{Updated: record.Updated.String(), Id: record.Id}
+		}
+
+		if err := writer.Flush(); err != nil {
+			return 0, Cursor{}, err
+		}
+
+		total += len(rows)
+
+		if len(rows) < batchSize {
+			break
+		}
+	}
+
+	if total == 0 {
+		return 0, cursor, nil
+	}
+
+	if err := writer.Close(); err != nil {
+		return 0, Cursor{}, err
+	}
+
+	if err := file.Close(); err != nil {
+		return 0, Cursor{}, err
+	}
+
+	if err := os.Rename(tempPath, filePath); err != nil {
+		return 0, Cursor{}, err
+	}
+
+	if signing := app.Settings().Signing; signing.Enabled {
+		if err := signature.WriteFileSignature(signing.Secret, filePath); err != nil {
+			return 0, Cursor{}, err
+		}
+	}
+
+	return total, newCursor, nil
+}
+
+// collectionColumns returns the Parquet columns of the collection records.
+//
+// Multiple values and json fields are exported as serialized json strings.
+func collectionColumns(collection *models.Collection) []parquet.Column {
+	columns := []parquet.Column{
+		{Name: schema.FieldNameId, Type: parquet.ColumnTypeString},
+		{Name: schema.FieldNameCreated, Type: parquet.ColumnTypeTimestamp},
+		{Name: schema.FieldNameUpdated, Type: parquet.ColumnTypeTimestamp},
+	}
+
+	if collection.IsAuth() {
+		columns = append(
+			columns,
+			parquet.Column{Name: schema.FieldNameUsername, Type: parquet.ColumnTypeString},
+			parquet.Column{Name: schema.FieldNameEmail, Type: parquet.ColumnTypeString},
+			parquet.Column{Name: schema.FieldNameEmailVisibility, Type: parquet.ColumnTypeBool},
+			parquet.Column{Name: schema.FieldNameVerified, Type: parquet.ColumnTypeBool},
+		)
+	}
+
+	for _, field := range collection.Schema.Fields() {
+		column := parquet.Column{Name: field.Name, Type: parquet.ColumnTypeString}
+
+		switch field.Type {
+		case schema.FieldTypeNumber:
+			column.Type = parquet.ColumnTypeDouble
+		case schema.FieldTypeBool:
+			column.Type = parquet.ColumnTypeBool
+		case schema.FieldTypeDate:
+			column.Type = parquet.ColumnTypeTimestamp
+		}
+
+		columns = append(columns, column)
+	}
+
+	return columns
+}
+
+func recordRow(record *models.Record, columns []parquet.Column) []any {
+	row := make([]any, len(columns))
+
+	for i, column := range columns {
+		switch v := record.Get(column.Name).(type) {
+		case types.DateTime:
+			row[i] = v.Time()
+		case types.JsonRaw:
+			if len(v) > 0 {
+				row[i] = v.String()
+			}
+		case []string:
+			encoded, _ := json.Marshal(v)
+			row[i] = string(encoded)
+		default:
+			row[i] = v
+		}
+	}
+
+	return row
+}
+
+func readCursors(dir string) (map[string]Cursor, error) {
+	cursors := map[string]Cursor{}
+
+	raw, err := os.ReadFile(filepath.Join(dir, CursorsFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cursors, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(raw, &cursors); err != nil {
+		return nil, err
+	}
+
+	return cursors, nil
+}
+
+func writeCursors(dir string, cursors map[string]Cursor) error {
+	raw, err := json.MarshalIndent(cursors, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, CursorsFile), raw, 0644)
+}
@@ -0,0 +1,220 @@
+package exportcmd_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/plugins/exportcmd"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/signature"
+)
+
+func TestExportMissingCollection(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dir := filepath.Join(app.DataDir(), "exports")
+
+	if _, err := exportcmd.Export(app, dir, "demo1", "missing"); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+}
+
+func TestExportIncremental(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dir := filepath.Join(app.DataDir(), "exports")
+
+	// initial export
+	// ---
+	result, err := exportcmd.Export(app, dir, "demo1", "users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedTotals := map[string]int{"demo1": 3, "users": 3}
+	if len(result) != len(expectedTotals) {
+		t.Fatalf("Expected result %v, got %v", expectedTotals, result)
+	}
+	for name, total := range expectedTotals {
+		if result[name] != total {
+			t.Errorf("Expected %d exported %q records, got %d", total, name, result[name])
+		}
+
+		files, _ := filepath.Glob(filepath.Join(dir, name, "*.parquet"))
+		if len(files) != 1 {
+			t.Errorf("Expected 1 %q export file, got %v", name, files)
+		}
+	}
+
+	cursors := map[string]exportcmd.Cursor{}
+	raw, err := os.ReadFile(filepath.Join(dir, exportcmd.CursorsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(raw, &cursors); err != nil {
+		t.Fatal(err)
+	}
+	if len(cursors) != 2 || cursors["wsmn24bux7wo113"].IsZero() || cursors["_pb_users_auth_"].IsZero() {
+		t.Fatalf("Expected demo1 and users cursors, got %v", cursors)
+	}
+
+	// nothing has changed
+	// ---
+	result, err = exportcmd.Export(app, dir, "demo1", "users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result["demo1"] != 0 || result["users"] != 0 {
+		t.Fatalf("Expected no exported records, got %v", result)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "demo1", "*"))
+	if len(files) != 1 {
+		t.Fatalf("Expected only the initial demo1 export file, got %v", files)
+	}
+
+	// export only the updated record
+	// ---
+	record, err := app.Dao().FindRecordById("demo1", "al1h9ijdeojtsjy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	record.Set("text", "updated")
+	if err := app.Dao().SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err = exportcmd.Export(app, dir, "demo1", "users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result["demo1"] != 1 || result["users"] != 0 {
+		t.Fatalf("Expected 1 exported demo1 record, got %v", result)
+	}
+	files, _ = filepath.Glob(filepath.Join(dir, "demo1", "*.parquet"))
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 demo1 export files, got %v", files)
+	}
+}
+
+func TestExportCursorWithSameUpdated(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dir := filepath.Join(app.DataDir(), "exports")
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	// make 2 of the records share the same "updated" value
+	_, err := app.Dao().DB().NewQuery("UPDATE demo1 SET updated = {:updated} WHERE id IN ('84nmscqy84lsi1t', 'imy661ixudk5izi')").
+		Bind(map[string]any{"updated": "2022-10-20 13:17:00.302Z"}).
+		Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// cursor positioned at the first of the 2 records
+	raw, _ := json.Marshal(map[string]exportcmd.Cursor{
+		"wsmn24bux7wo113": {Updated: "2022-10-20 13:17:00.302Z", Id: "84nmscqy84lsi1t"},
+	})
+	if err := os.WriteFile(filepath.Join(dir, exportcmd.CursorsFile), raw, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := exportcmd.Export(app, dir, "demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result["demo1"] != 1 {
+		t.Fatalf("Expected 1 exported demo1 record, got %v", result)
+	}
+
+	raw, err = os.ReadFile(filepath.Join(dir, exportcmd.CursorsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cursors := map[string]exportcmd.Cursor{}
+	if err := json.Unmarshal(raw, &cursors); err != nil {
+		t.Fatal(err)
+	}
+	expected := exportcmd.Cursor{Updated: "2022-10-20 13:17:00.302Z", Id: "imy661ixudk5izi"}
+	if cursors["wsmn24bux7wo113"] != expected {
+		t.Fatalf("Expected cursor %v, got %v", expected, cursors["wsmn24bux7wo113"])
+	}
+}
+
+func TestExportLegacyCursor(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dir := filepath.Join(app.DataDir(), "exports")
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	// plain "updated" string cursor
+	raw := []byte(`{"wsmn24bux7wo113":"2022-10-14 10:36:25.562Z"}`)
+	if err := os.WriteFile(filepath.Join(dir, exportcmd.CursorsFile), raw, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := exportcmd.Export(app, dir, "demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result["demo1"] != 1 {
+		t.Fatalf("Expected 1 exported demo1 record, got %v", result)
+	}
+}
+
+func TestExportAllCollections(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dir := filepath.Join(app.DataDir(), "exports")
+
+	result, err := exportcmd.Export(app, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collections, _ := app.Dao().FindCollectionsByType("base")
+	authCollections, _ := app.Dao().FindCollectionsByType("auth")
+	if expected := len(collections) + len(authCollections); len(result) != expected {
+		t.Fatalf("Expected %d exported collections, got %v", expected, result)
+	}
+}
+
+func TestExportSigned(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dir := filepath.Join(app.DataDir(), "exports")
+
+	// disabled signing
+	if _, err := exportcmd.Export(app, dir, "demo1"); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "demo1", "*"+signature.FileExt)); len(files) != 0 {
+		t.Fatalf("Expected no signature files, got %v", files)
+	}
+
+	// enabled signing
+	app.Settings().Signing.Enabled = true
+	app.Settings().Signing.Secret = "test_secret"
+
+	if _, err := exportcmd.Export(app, dir, "users"); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "users", "*.parquet"))
+	if len(files) != 1 {
+		t.Fatalf("Expected 1 users export file, got %v", files)
+	}
+	if err := signature.VerifyFile("test_secret", files[0]); err != nil {
+		t.Fatalf("Expected valid export file signature, got %v", err)
+	}
+}
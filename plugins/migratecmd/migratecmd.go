@@ -121,14 +121,20 @@ func (p *plugin) createCommand() *cobra.Command {
 	const cmdDesc = `Supported arguments are:
 - up            - runs all available migrations
 - down [number] - reverts the last [number] applied migrations
+- down --to=NAME - reverts all applied migrations after the NAME one
+- restore       - restores the tables snapshot of the last reverted migration
+- verify        - checks whether the applied migrations are reversible
 - create name   - creates new blank migration template file
 - collections   - creates new migration file with snapshot of the local collections configuration
 `
 
+	var downTo string
+	var noSnapshot bool
+
 	command := &cobra.Command{
 		Use:       "migrate",
 		Short:     "Executes app DB migration scripts",
-		ValidArgs: []string{"up", "down", "create", "collections"},
+		ValidArgs: []string{"up", "down", "restore", "verify", "create", "collections"},
 		Long:      cmdDesc,
 		Run: func(command *cobra.Command, args []string) {
 			cmd := ""
@@ -151,6 +157,11 @@ func (p *plugin) createCommand() *cobra.Command {
 					log.Fatal(err)
 				}
 
+				if downTo != "" {
+					args = append(args, "--to="+downTo)
+				}
+				runner.DisableSnapshots(noSnapshot)
+
 				if err := runner.Run(args...); err != nil {
 					log.Fatal(err)
 				}
@@ -158,6 +169,9 @@ func (p *plugin) createCommand() *cobra.Command {
 		},
 	}
 
+	command.Flags().StringVar(&downTo, "to", "", "the migration to revert to with the down command (eg. 1674100000_example)")
+	command.Flags().BoolVar(&noSnapshot, "no-snapshot", false, "disable the pre-rollback snapshots of the affected tables")
+
 	return command
 }
 
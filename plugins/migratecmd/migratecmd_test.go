@@ -36,16 +36,87 @@ migrate((db) => {
     "createRule": null,
     "updateRule": null,
     "deleteRule": null,
+    "subscribeRule": null,
+    "ruleMacros": {},
     "options": {
+      "allowAnonymousAuth": false,
       "allowEmailAuth": false,
+      "allowLoginLinkAuth": false,
       "allowOAuth2Auth": false,
+      "allowPhoneAuth": false,
       "allowUsernameAuth": false,
+      "allowWebauthnAuth": false,
+      "botProtection": {
+        "honeypotFields": null,
+        "ipCooldown": 0,
+        "minSubmitTime": 0,
+        "submitTimeField": ""
+      },
+      "createRateLimit": 0,
+      "createRateWindow": 0,
+      "encryption": {
+        "enabled": false
+      },
       "exceptEmailDomains": null,
+      "externalId": {
+        "enabled": false
+      },
+      "history": {
+        "enabled": false
+      },
+      "ldap": {
+        "baseDn": "",
+        "bindDn": "",
+        "bindPassword": "",
+        "emailAttribute": "",
+        "enabled": false,
+        "fieldsMapping": null,
+        "url": "",
+        "userFilter": ""
+      },
       "manageRule": "created > 0",
+      "maxRecords": 0,
       "minPasswordLength": 20,
+      "moderation": {
+        "action": "",
+        "bannedWords": null,
+        "checkDuplicates": false,
+        "enabled": false,
+        "fields": null,
+        "maxLinks": 0,
+        "verdictField": ""
+      },
       "onlyEmailDomains": null,
-      "requireEmail": false
-    }
+      "presets": {
+        "only": false
+      },
+      "requireEmail": false,
+      "saml": {
+        "emailAttribute": "",
+        "enabled": false,
+        "fieldsMapping": null,
+        "idpCertificate": "",
+        "idpEntityId": "",
+        "idpSsoUrl": "",
+        "redirectUrls": null
+      },
+      "sessions": {
+        "accessTokenDuration": 0,
+        "enabled": false,
+        "refreshTokenDuration": 0
+      },
+      "softDelete": {
+        "enabled": false
+      },
+      "transaction": {
+        "enabled": false
+      },
+      "uniqueConstraints": null,
+      "versioning": {
+        "enabled": false
+      }
+    },
+    "version": 0
   });
 
   return Dao(db).saveCollection(collection);
@@ -86,16 +157,87 @@ func init() {
 			"createRule": null,
 			"updateRule": null,
 			"deleteRule": null,
+			"subscribeRule": null,
+			"ruleMacros": {},
 			"options": {
+				"allowAnonymousAuth": false,
 				"allowEmailAuth": false,
+				"allowLoginLinkAuth": false,
 				"allowOAuth2Auth": false,
+				"allowPhoneAuth": false,
 				"allowUsernameAuth": false,
+				"allowWebauthnAuth": false,
+				"botProtection": {
+					"honeypotFields": null,
+					"ipCooldown": 0,
+					"minSubmitTime": 0,
+					"submitTimeField": ""
+				},
+				"createRateLimit": 0,
+				"createRateWindow": 0,
+				"encryption": {
+					"enabled": false
+				},
 				"exceptEmailDomains": null,
+				"externalId": {
+					"enabled": false
+				},
+				"history": {
+					"enabled": false
+				},
+				"ldap": {
+					"baseDn": "",
+					"bindDn": "",
+					"bindPassword": "",
+					"emailAttribute": "",
+					"enabled": false,
+					"fieldsMapping": null,
+					"url": "",
+					"userFilter": ""
+				},
 				"manageRule": "created > 0",
+				"maxRecords": 0,
 				"minPasswordLength": 20,
+				"moderation": {
+					"action": "",
+					"bannedWords": null,
+					"checkDuplicates": false,
+					"enabled": false,
+					"fields": null,
+					"maxLinks": 0,
+					"verdictField": ""
+				},
 				"onlyEmailDomains": null,
-				"requireEmail": false
-			}
+				"presets": {
+					"only": false
+				},
+				"requireEmail": false,
+				"saml": {
+					"emailAttribute": "",
+					"enabled": false,
+					"fieldsMapping": null,
+					"idpCertificate": "",
+					"idpEntityId": "",
+					"idpSsoUrl": "",
+					"redirectUrls": null
+				},
+				"sessions": {
+					"accessTokenDuration": 0,
+					"enabled": false,
+					"refreshTokenDuration": 0
+				},
+				"softDelete": {
+					"enabled": false
+				},
+				"transaction": {
+					"enabled": false
+				},
+				"uniqueConstraints": null,
+				"versioning": {
+					"enabled": false
+				}
+			},
+			"version": 0
 		}` + "`" + `
 
 		collection := &models.Collection{}
@@ -206,16 +348,87 @@ migrate((db) => {
     "createRule": null,
     "updateRule": null,
     "deleteRule": null,
+    "subscribeRule": null,
+    "ruleMacros": {},
     "options": {
+      "allowAnonymousAuth": false,
       "allowEmailAuth": false,
+      "allowLoginLinkAuth": false,
       "allowOAuth2Auth": false,
+      "allowPhoneAuth": false,
       "allowUsernameAuth": false,
+      "allowWebauthnAuth": false,
+      "botProtection": {
+        "honeypotFields": null,
+        "ipCooldown": 0,
+        "minSubmitTime": 0,
+        "submitTimeField": ""
+      },
+      "createRateLimit": 0,
+      "createRateWindow": 0,
+      "encryption": {
+        "enabled": false
+      },
       "exceptEmailDomains": null,
+      "externalId": {
+        "enabled": false
+      },
+      "history": {
+        "enabled": false
+      },
+      "ldap": {
+        "baseDn": "",
+        "bindDn": "",
+        "bindPassword": "",
+        "emailAttribute": "",
+        "enabled": false,
+        "fieldsMapping": null,
+        "url": "",
+        "userFilter": ""
+      },
       "manageRule": "created > 0",
+      "maxRecords": 0,
       "minPasswordLength": 20,
+      "moderation": {
+        "action": "",
+        "bannedWords": null,
+        "checkDuplicates": false,
+        "enabled": false,
+        "fields": null,
+        "maxLinks": 0,
+        "verdictField": ""
+      },
       "onlyEmailDomains": null,
-      "requireEmail": false
-    }
+      "presets": {
+        "only": false
+      },
+      "requireEmail": false,
+      "saml": {
+        "emailAttribute": "",
+        "enabled": false,
+        "fieldsMapping": null,
+        "idpCertificate": "",
+        "idpEntityId": "",
+        "idpSsoUrl": "",
+        "redirectUrls": null
+      },
+      "sessions": {
+        "accessTokenDuration": 0,
+        "enabled": false,
+        "refreshTokenDuration": 0
+      },
+      "softDelete": {
+        "enabled": false
+      },
+      "transaction": {
+        "enabled": false
+      },
+      "uniqueConstraints": null,
+      "versioning": {
+        "enabled": false
+      }
+    },
+    "version": 0
   });
 
   return Dao(db).saveCollection(collection);
@@ -260,16 +473,87 @@ func init() {
 			"createRule": null,
 			"updateRule": null,
 			"deleteRule": null,
+			"subscribeRule": null,
+			"ruleMacros": {},
 			"options": {
+				"allowAnonymousAuth": false,
 				"allowEmailAuth": false,
+				"allowLoginLinkAuth": false,
 				"allowOAuth2Auth": false,
+				"allowPhoneAuth": false,
 				"allowUsernameAuth": false,
+				"allowWebauthnAuth": false,
+				"botProtection": {
+					"honeypotFields": null,
+					"ipCooldown": 0,
+					"minSubmitTime": 0,
+					"submitTimeField": ""
+				},
+				"createRateLimit": 0,
+				"createRateWindow": 0,
+				"encryption": {
+					"enabled": false
+				},
 				"exceptEmailDomains": null,
+				"externalId": {
+					"enabled": false
+				},
+				"history": {
+					"enabled": false
+				},
+				"ldap": {
+					"baseDn": "",
+					"bindDn": "",
+					"bindPassword": "",
+					"emailAttribute": "",
+					"enabled": false,
+					"fieldsMapping": null,
+					"url": "",
+					"userFilter": ""
+				},
 				"manageRule": "created > 0",
+				"maxRecords": 0,
 				"minPasswordLength": 20,
+				"moderation": {
+					"action": "",
+					"bannedWords": null,
+					"checkDuplicates": false,
+					"enabled": false,
+					"fields": null,
+					"maxLinks": 0,
+					"verdictField": ""
+				},
 				"onlyEmailDomains": null,
-				"requireEmail": false
-			}
+				"presets": {
+					"only": false
+				},
+				"requireEmail": false,
+				"saml": {
+					"emailAttribute": "",
+					"enabled": false,
+					"fieldsMapping": null,
+					"idpCertificate": "",
+					"idpEntityId": "",
+					"idpSsoUrl": "",
+					"redirectUrls": null
+				},
+				"sessions": {
+					"accessTokenDuration": 0,
+					"enabled": false,
+					"refreshTokenDuration": 0
+				},
+				"softDelete": {
+					"enabled": false
+				},
+				"transaction": {
+					"enabled": false
+				},
+				"uniqueConstraints": null,
+				"versioning": {
+					"enabled": false
+				}
+			},
+			"version": 0
 		}` + "`" + `
 
 		collection := &models.Collection{}
@@ -366,7 +650,54 @@ migrate((db) => {
   collection.type = "base"
   collection.listRule = null
   collection.deleteRule = "updated > 0 && @request.auth.id != ''"
-  collection.options = {}
+  collection.subscribeRule = "@request.auth.id != ''"
+  collection.ruleMacros = {
+    "isOwner": "owner = @request.auth.id"
+  }
+  collection.options = {
+    "botProtection": {
+      "honeypotFields": null,
+      "ipCooldown": 0,
+      "minSubmitTime": 0,
+      "submitTimeField": ""
+    },
+    "createRateLimit": 0,
+    "createRateWindow": 0,
+    "database": "",
+    "encryption": {
+      "enabled": false
+    },
+    "external": false,
+    "externalId": {
+      "enabled": false
+    },
+    "history": {
+      "enabled": false
+    },
+    "maxRecords": 0,
+    "moderation": {
+      "action": "",
+      "bannedWords": null,
+      "checkDuplicates": false,
+      "enabled": false,
+      "fields": null,
+      "maxLinks": 0,
+      "verdictField": ""
+    },
+    "presets": {
+      "only": false
+    },
+    "softDelete": {
+      "enabled": false
+    },
+    "transaction": {
+      "enabled": false
+    },
+    "uniqueConstraints": null,
+    "versioning": {
+      "enabled": false
+    }
+  }
 
   // remove
   collection.schema.removeField("f3_id")
@@ -409,15 +740,85 @@ migrate((db) => {
   collection.type = "auth"
   collection.listRule = "@request.auth.id != '' && created > 0"
   collection.deleteRule = null
+  collection.subscribeRule = null
+  collection.ruleMacros = {}
   collection.options = {
+    "allowAnonymousAuth": false,
     "allowEmailAuth": false,
+    "allowLoginLinkAuth": false,
     "allowOAuth2Auth": false,
+    "allowPhoneAuth": false,
     "allowUsernameAuth": false,
+    "allowWebauthnAuth": false,
+    "botProtection": {
+      "honeypotFields": null,
+      "ipCooldown": 0,
+      "minSubmitTime": 0,
+      "submitTimeField": ""
+    },
+    "createRateLimit": 0,
+    "createRateWindow": 0,
+    "encryption": {
+      "enabled": false
+    },
     "exceptEmailDomains": null,
+    "externalId": {
+      "enabled": false
+    },
+    "history": {
+      "enabled": false
+    },
+    "ldap": {
+      "baseDn": "",
+      "bindDn": "",
+      "bindPassword": "",
+      "emailAttribute": "",
+      "enabled": false,
+      "fieldsMapping": null,
+      "url": "",
+      "userFilter": ""
+    },
     "manageRule": "created > 0",
+    "maxRecords": 0,
     "minPasswordLength": 20,
+    "moderation": {
+      "action": "",
+      "bannedWords": null,
+      "checkDuplicates": false,
+      "enabled": false,
+      "fields": null,
+      "maxLinks": 0,
+      "verdictField": ""
+    },
     "onlyEmailDomains": null,
-    "requireEmail": false
+    "presets": {
+      "only": false
+    },
+    "requireEmail": false,
+    "saml": {
+      "emailAttribute": "",
+      "enabled": false,
+      "fieldsMapping": null,
+      "idpCertificate": "",
+      "idpEntityId": "",
+      "idpSsoUrl": "",
+      "redirectUrls": null
+    },
+    "sessions": {
+      "accessTokenDuration": 0,
+      "enabled": false,
+      "refreshTokenDuration": 0
+    },
+    "softDelete": {
+      "enabled": false
+    },
+    "transaction": {
+      "enabled": false
+    },
+    "uniqueConstraints": null,
+    "versioning": {
+      "enabled": false
+    }
   }
 
   // add
@@ -484,8 +885,59 @@ func init() {
 
 		collection.DeleteRule = types.Pointer("updated > 0 && @request.auth.id != ''")
 
+		collection.SubscribeRule = types.Pointer("@request.auth.id != ''")
+
+		ruleMacros := map[string]any{}
+		json.Unmarshal([]byte(` + "`" + `{
+			"isOwner": "owner = @request.auth.id"
+		}` + "`" + `), &ruleMacros)
+		collection.RuleMacros = ruleMacros
+
 		options := map[string]any{}
-		json.Unmarshal([]byte(` + "`" + `{}` + "`" + `), &options)
+		json.Unmarshal([]byte(` + "`" + `{
+			"botProtection": {
+				"honeypotFields": null,
+				"ipCooldown": 0,
+				"minSubmitTime": 0,
+				"submitTimeField": ""
+			},
+			"createRateLimit": 0,
+			"createRateWindow": 0,
+			"database": "",
+			"encryption": {
+				"enabled": false
+			},
+			"external": false,
+			"externalId": {
+				"enabled": false
+			},
+			"history": {
+				"enabled": false
+			},
+			"maxRecords": 0,
+			"moderation": {
+				"action": "",
+				"bannedWords": null,
+				"checkDuplicates": false,
+				"enabled": false,
+				"fields": null,
+				"maxLinks": 0,
+				"verdictField": ""
+			},
+			"presets": {
+				"only": false
+			},
+			"softDelete": {
+				"enabled": false
+			},
+			"transaction": {
+				"enabled": false
+			},
+			"uniqueConstraints": null,
+			"versioning": {
+				"enabled": false
+			}
+		}` + "`" + `), &options)
 		collection.SetOptions(options)
 
 		// remove
@@ -541,16 +993,90 @@ func init() {
 
 		collection.DeleteRule = nil
 
+		collection.SubscribeRule = nil
+
+		ruleMacros := map[string]any{}
+		json.Unmarshal([]byte(` + "`" + `{}` + "`" + `), &ruleMacros)
+		collection.RuleMacros = ruleMacros
+
 		options := map[string]any{}
 		json.Unmarshal([]byte(` + "`" + `{
+			"allowAnonymousAuth": false,
 			"allowEmailAuth": false,
+			"allowLoginLinkAuth": false,
 			"allowOAuth2Auth": false,
+			"allowPhoneAuth": false,
 			"allowUsernameAuth": false,
+			"allowWebauthnAuth": false,
+			"botProtection": {
+				"honeypotFields": null,
+				"ipCooldown": 0,
+				"minSubmitTime": 0,
+				"submitTimeField": ""
+			},
+			"createRateLimit": 0,
+			"createRateWindow": 0,
+			"encryption": {
+				"enabled": false
+			},
 			"exceptEmailDomains": null,
+			"externalId": {
+				"enabled": false
+			},
+			"history": {
+				"enabled": false
+			},
+			"ldap": {
+				"baseDn": "",
+				"bindDn": "",
+				"bindPassword": "",
+				"emailAttribute": "",
+				"enabled": false,
+				"fieldsMapping": null,
+				"url": "",
+				"userFilter": ""
+			},
 			"manageRule": "created > 0",
+			"maxRecords": 0,
 			"minPasswordLength": 20,
+			"moderation": {
+				"action": "",
+				"bannedWords": null,
+				"checkDuplicates": false,
+				"enabled": false,
+				"fields": null,
+				"maxLinks": 0,
+				"verdictField": ""
+			},
 			"onlyEmailDomains": null,
-			"requireEmail": false
+			"presets": {
+				"only": false
+			},
+			"requireEmail": false,
+			"saml": {
+				"emailAttribute": "",
+				"enabled": false,
+				"fieldsMapping": null,
+				"idpCertificate": "",
+				"idpEntityId": "",
+				"idpSsoUrl": "",
+				"redirectUrls": null
+			},
+			"sessions": {
+				"accessTokenDuration": 0,
+				"enabled": false,
+				"refreshTokenDuration": 0
+			},
+			"softDelete": {
+				"enabled": false
+			},
+			"transaction": {
+				"enabled": false
+			},
+			"uniqueConstraints": null,
+			"versioning": {
+				"enabled": false
+			}
 		}` + "`" + `), &options)
 		collection.SetOptions(options)
 
@@ -651,6 +1177,8 @@ func init() {
 		collection.Name = "test456_update"
 		collection.Type = models.CollectionTypeBase
 		collection.DeleteRule = types.Pointer(`updated > 0 && @request.auth.id != ''`)
+		collection.SubscribeRule = types.Pointer(`@request.auth.id != ''`)
+		collection.RuleMacros = types.JsonMap{"isOwner": "owner = @request.auth.id"}
 		collection.ListRule = nil
 		collection.NormalizeOptions()
 		collection.Schema.RemoveField("f3_id")
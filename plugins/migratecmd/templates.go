@@ -182,6 +182,30 @@ func (p *plugin) jsDiffTemplate(new *models.Collection, old *models.Collection)
 		}
 	}
 
+	if old.SubscribeRule != new.SubscribeRule {
+		if old.SubscribeRule != nil && new.SubscribeRule == nil {
+			upParts = append(upParts, fmt.Sprintf("%s.subscribeRule = null", varName))
+			downParts = append(downParts, fmt.Sprintf("%s.subscribeRule = %s", varName, strconv.Quote(*old.SubscribeRule)))
+		} else if old.SubscribeRule == nil && new.SubscribeRule != nil || *old.SubscribeRule != *new.SubscribeRule {
+			upParts = append(upParts, fmt.Sprintf("%s.subscribeRule = %s", varName, strconv.Quote(*new.SubscribeRule)))
+			downParts = append(downParts, fmt.Sprintf("%s.subscribeRule = null", varName))
+		}
+	}
+
+	// RuleMacros
+	rawNewRuleMacros, err := marhshalWithoutEscape(new.RuleMacros, "  ", "  ")
+	if err != nil {
+		return "", err
+	}
+	rawOldRuleMacros, err := marhshalWithoutEscape(old.RuleMacros, "  ", "  ")
+	if err != nil {
+		return "", err
+	}
+	if !bytes.Equal(rawNewRuleMacros, rawOldRuleMacros) {
+		upParts = append(upParts, fmt.Sprintf("%s.ruleMacros = %s", varName, rawNewRuleMacros))
+		downParts = append(downParts, fmt.Sprintf("%s.ruleMacros = %s", varName, rawOldRuleMacros))
+	}
+
 	// Options
 	rawNewOptions, err := marhshalWithoutEscape(new.Options, "  ", "  ")
 	if err != nil {
@@ -552,6 +576,35 @@ func (p *plugin) goDiffTemplate(new *models.Collection, old *models.Collection)
 		}
 	}
 
+	if old.SubscribeRule != new.SubscribeRule {
+		if old.SubscribeRule != nil && new.SubscribeRule == nil {
+			upParts = append(upParts, fmt.Sprintf("%s.SubscribeRule = nil\n", varName))
+			downParts = append(downParts, fmt.Sprintf("%s.SubscribeRule = types.Pointer(%s)\n", varName, strconv.Quote(*old.SubscribeRule)))
+		} else if old.SubscribeRule == nil && new.SubscribeRule != nil || *old.SubscribeRule != *new.SubscribeRule {
+			upParts = append(upParts, fmt.Sprintf("%s.SubscribeRule = types.Pointer(%s)\n", varName, strconv.Quote(*new.SubscribeRule)))
+			downParts = append(downParts, fmt.Sprintf("%s.SubscribeRule = nil\n", varName))
+		}
+	}
+
+	// RuleMacros
+	rawNewRuleMacros, err := marhshalWithoutEscape(new.RuleMacros, "\t\t", "\t")
+	if err != nil {
+		return "", err
+	}
+	rawOldRuleMacros, err := marhshalWithoutEscape(old.RuleMacros, "\t\t", "\t")
+	if err != nil {
+		return "", err
+	}
+	if !bytes.Equal(rawNewRuleMacros, rawOldRuleMacros) {
+		upParts = append(upParts, "ruleMacros := map[string]any{}")
+		upParts = append(upParts, fmt.Sprintf("json.Unmarshal([]byte(`%s`), &ruleMacros)", escapeBacktick(string(rawNewRuleMacros))))
+		upParts = append(upParts, fmt.Sprintf("%s.RuleMacros = ruleMacros\n", varName))
+		// ---
+		downParts = append(downParts, "ruleMacros := map[string]any{}")
+		downParts = append(downParts, fmt.Sprintf("json.Unmarshal([]byte(`%s`), &ruleMacros)", escapeBacktick(string(rawOldRuleMacros))))
+		downParts = append(downParts, fmt.Sprintf("%s.RuleMacros = ruleMacros\n", varName))
+	}
+
 	// Options
 	rawNewOptions, err := marhshalWithoutEscape(new.Options, "\t\t", "\t")
 	if err != nil {
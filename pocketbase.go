@@ -56,6 +56,14 @@ type Config struct {
 	DataMaxIdleConns int // default to core.DefaultDataMaxIdleConns
 	LogsMaxOpenConns int // default to core.DefaultLogsMaxOpenConns
 	LogsMaxIdleConns int // default to core.DefaultLogsMaxIdleConns
+
+	// optional list with names of additional "pb_data/{name}.db"
+	// databases to attach (see the base collection "database" option)
+	AttachedDatabases []string
+
+	// optional flag to enable the sharded local storage layout
+	// (use the "storage layout" command to move the existing files)
+	ShardedStorage bool
 }
 
 // New creates a new PocketBase instance with the default configuration.
@@ -124,6 +132,9 @@ func NewWithConfig(config *Config) *PocketBase {
 		DataMaxIdleConns: config.DataMaxIdleConns,
 		LogsMaxOpenConns: config.LogsMaxOpenConns,
 		LogsMaxIdleConns: config.LogsMaxIdleConns,
+
+		AttachedDatabases: config.AttachedDatabases,
+		ShardedStorage:    config.ShardedStorage,
 	})}
 
 	// hide the default help command (allow only `--help` flag)
@@ -138,6 +149,13 @@ func (pb *PocketBase) Start() error {
 	// register system commands
 	pb.RootCmd.AddCommand(cmd.NewServeCommand(pb, !pb.hideStartBanner))
 	pb.RootCmd.AddCommand(cmd.NewTempUpgradeCommand(pb))
+	pb.RootCmd.AddCommand(cmd.NewAdminCommand(pb))
+	pb.RootCmd.AddCommand(cmd.NewSettingsCommand(pb))
+	pb.RootCmd.AddCommand(cmd.NewCollectionsCommand(pb))
+	pb.RootCmd.AddCommand(cmd.NewRecordsCommand(pb))
+	pb.RootCmd.AddCommand(cmd.NewScaffoldCommand(pb, Version))
+	pb.RootCmd.AddCommand(cmd.NewBenchCommand(pb))
+	pb.RootCmd.AddCommand(cmd.NewStorageCommand(pb))
 
 	return pb.Execute()
 }
@@ -199,7 +217,7 @@ func (pb *PocketBase) eagerParseFlags(config *Config) error {
 		&pb.encryptionEnvFlag,
 		"encryptionEnv",
 		config.DefaultEncryptionEnv,
-		"the env variable whose value of 32 characters will be used \nas encryption key for the app settings (default none)",
+		"the env variable whose value of 32 random characters will be used \nas encryption key for the app settings and records (default none)",
 	)
 
 	pb.RootCmd.PersistentFlags().BoolVar(
@@ -0,0 +1,532 @@
+// Package reports implements the generation and the scheduled delivery
+// (via email and/or webhook) of the admin defined collection reports.
+package reports
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/mails"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/resolvers"
+	"github.com/pocketbase/pocketbase/tools/cron"
+	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/signature"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/spf13/cast"
+)
+
+// MaxRows is the max number of rows of a single report.
+const MaxRows = 10000
+
+// MaxRunsHistory is the max number of the stored runs of a single report.
+const MaxRunsHistory = 100
+
+// aggregateFunctions lists the functions allowed in the report aggregate fields.
+var aggregateFunctions = []string{"count", "sum", "avg", "min", "max"}
+
+// httpClient is the http client used for the report webhook requests.
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// Result defines a generated report data.
+type Result struct {
+	// Keys are the report column names (in the order of their appearance).
+	Keys []string `json:"keys"`
+
+	Rows []map[string]any `json:"rows"`
+}
+
+// RunDue runs all enabled reports whose schedule matches
+// the minute of the provided time and that weren't already run
+// within the same minute.
+//
+// It is usually called once per minute by the serve command.
+func RunDue(app core.App, now time.Time) error {
+	reports, err := app.Dao().FindEnabledReports()
+	if err != nil {
+		return err
+	}
+
+	minute := now.UTC().Truncate(time.Minute)
+
+	var errs []string
+
+	for _, report := range reports {
+		schedule, err := cron.NewSchedule(report.Schedule)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", report.Name, err))
+			continue
+		}
+
+		if !schedule.IsDue(minute) || !report.LastRunAt.Time().Before(minute) {
+			continue
+		}
+
+		if _, err := Run(app, report); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", report.Name, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// Run generates, renders and delivers the provided report and
+// stores the run result in the report run history.
+//
+// The stored run is returned even on failure (with the run error).
+func Run(app core.App, report *models.Report) (*models.ReportRun, error) {
+	run := &models.ReportRun{ReportId: report.Id}
+
+	runErr := func() error {
+		result, err := Generate(app.Dao(), report)
+		if err != nil {
+			return err
+		}
+		run.Rows = len(result.Rows)
+
+		content, err := Render(result, report.Format)
+		if err != nil {
+			return err
+		}
+
+		return deliver(app, report, content, run.Rows)
+	}()
+
+	if runErr != nil {
+		run.Status = models.ReportRunStatusFailure
+		run.Error = runErr.Error()
+	} else {
+		run.Status = models.ReportRunStatusSuccess
+	}
+
+	saveErr := app.Dao().RunInTransaction(func(txDao *daos.Dao) error {
+		if err := txDao.SaveReportRun(run); err != nil {
+			return err
+		}
+
+		report.LastRunAt = types.NowDateTime()
+		if err := txDao.SaveReport(report); err != nil {
+			return err
+		}
+
+		return txDao.DeleteOldReportRuns(report.Id, MaxRunsHistory)
+	})
+	if saveErr != nil {
+		if app.IsDebug() {
+			log.Println("Failed to store the report run:", saveErr)
+		}
+		if runErr == nil {
+			runErr = saveErr
+		}
+	}
+
+	return run, runErr
+}
+
+// Generate executes the report query and returns its result.
+func Generate(dao *daos.Dao, report *models.Report) (*Result, error) {
+	collection, err := dao.FindCollectionByNameOrId(report.CollectionId)
+	if err != nil {
+		return nil, err
+	}
+
+	query, keys, err := buildQuery(dao, collection, report)
+	if err != nil {
+		return nil, err
+	}
+
+	if keys != nil {
+		rows, err := scanAggregateRows(query, keys)
+		if err != nil {
+			return nil, err
+		}
+		return &Result{Keys: keys, Rows: rows}, nil
+	}
+
+	return scanRecordRows(query, collection)
+}
+
+// ValidateQuery checks whether the filter, groupBy, aggregate and
+// sort fields of the provided report are valid for the specified collection.
+func ValidateQuery(dao *daos.Dao, collection *models.Collection, report *models.Report) error {
+	_, _, err := buildQuery(dao, collection, report)
+
+	return err
+}
+
+// Render serializes the report result in the specified format.
+func Render(result *Result, format string) ([]byte, error) {
+	switch format {
+	case models.ReportFormatJson:
+		return json.Marshal(result.Rows)
+	case models.ReportFormatCsv:
+		var buf bytes.Buffer
+
+		w := csv.NewWriter(&buf)
+
+		if err := w.Write(result.Keys); err != nil {
+			return nil, err
+		}
+
+		for _, row := range result.Rows {
+			line := make([]string, len(result.Keys))
+			for i, key := range result.Keys {
+				line[i] = csvValue(row[key])
+			}
+			if err := w.Write(line); err != nil {
+				return nil, err
+			}
+		}
+
+		w.Flush()
+
+		return buf.Bytes(), w.Error()
+	}
+
+	return nil, fmt.Errorf("Unsupported report format %q.", format)
+}
+
+// Filename returns the report attachment filename
+// (eg. "weekly_orders_20230102.csv").
+func Filename(report *models.Report, date time.Time) string {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' {
+			return r
+		}
+		return '_'
+	}, report.Name)
+
+	return fmt.Sprintf("%s_%s.%s", name, date.UTC().Format("20060102"), report.Format)
+}
+
+// -------------------------------------------------------------------
+
+// deliver sends the rendered report content to the report
+// email recipients and webhook url (if any).
+func deliver(app core.App, report *models.Report, content []byte, rows int) error {
+	var errs []string
+
+	if len(report.Emails) > 0 {
+		if err := mails.SendReport(app, report, Filename(report, time.Now()), content, rows); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if report.WebhookUrl != "" {
+		if err := sendWebhook(report, content); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// sendWebhook sends the rendered report content as POST request body
+// to the report webhook url.
+func sendWebhook(report *models.Report, content []byte) error {
+	req, err := http.NewRequest(http.MethodPost, report.WebhookUrl, bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+
+	if report.Format == models.ReportFormatCsv {
+		req.Header.Set("Content-Type", "text/csv")
+	} else {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("X-Report-Id", report.Id)
+	if report.WebhookSecret != "" {
+		req.Header.Set(signature.Header, signature.Sign(report.WebhookSecret, content, time.Now()))
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		resBody, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("Failed to send the report webhook (%d): %s", res.StatusCode, resBody)
+	}
+
+	return nil
+}
+
+// buildQuery builds the report select query.
+//
+// The returned keys are the aggregate result column names
+// or nil for the plain records reports.
+func buildQuery(dao *daos.Dao, collection *models.Collection, report *models.Report) (*dbx.SelectQuery, []string, error) {
+	tableAlias := dao.Columnify(collection.Name)
+
+	// filter the records in a subquery to prevent the filter joins
+	// from duplicating the report rows
+	// ---
+	filterQuery := dao.RecordQuery(collection).Select(tableAlias + ".id")
+
+	if report.Filter != "" {
+		filterResolver := resolvers.NewRecordFieldResolver(dao, collection, nil, true)
+
+		expr, err := search.FilterData(report.Filter).BuildExpr(filterResolver)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Invalid filter: %w", err)
+		}
+		filterQuery.AndWhere(expr)
+
+		if err := filterResolver.UpdateQuery(filterQuery); err != nil {
+			return nil, nil, fmt.Errorf("Invalid filter: %w", err)
+		}
+	}
+
+	builtFilterQuery := filterQuery.Build()
+	// ---
+
+	resolver := resolvers.NewRecordFieldResolver(dao, collection, nil, true)
+
+	sortFields := []search.SortField{}
+	if report.Sort != "" {
+		sortFields = search.ParseSortFromString(report.Sort)
+	}
+
+	query := dao.RecordQuery(collection).
+		AndWhere(dbx.NewExp(
+			fmt.Sprintf("[[%s.id]] IN (%s)", tableAlias, builtFilterQuery.SQL()),
+			builtFilterQuery.Params(),
+		)).
+		Limit(MaxRows)
+
+	var keys []string
+
+	if report.GroupBy == "" && report.Aggregate == "" {
+		for _, sortField := range sortFields {
+			expr, err := sortField.BuildExpr(resolver)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Invalid sort: %w", err)
+			}
+			query.AndOrderBy(expr)
+		}
+
+		// the sort joins may duplicate the rows
+		query.Distinct(true)
+	} else {
+		selects := []string{}
+		groupBy := []string{}
+		params := dbx.Params{}
+		aliases := map[string]string{}
+		keys = []string{}
+
+		addSelect := func(rawField string, alias string) error {
+			if _, exists := aliases[rawField]; exists {
+				return fmt.Errorf("Duplicated field %q.", rawField)
+			}
+
+			// forbid @request and @collection fields
+			if strings.Contains(rawField, "@") {
+				return fmt.Errorf("Invalid field %q.", rawField)
+			}
+
+			expr, exprParams, err := search.ResolveAggregateField(resolver, rawField)
+			if err != nil {
+				return err
+			}
+
+			for k, v := range exprParams {
+				params[k] = v
+			}
+
+			selects = append(selects, fmt.Sprintf("%s AS [[%s]]", expr, alias))
+			aliases[rawField] = alias
+			keys = append(keys, rawField)
+
+			return nil
+		}
+
+		for i, rawField := range splitFields(report.GroupBy) {
+			if isAggregateFunction(rawField) {
+				return nil, nil, fmt.Errorf("Invalid groupBy: aggregate functions are not allowed in %q.", rawField)
+			}
+
+			alias := "g" + strconv.Itoa(i)
+
+			if err := addSelect(rawField, alias); err != nil {
+				return nil, nil, fmt.Errorf("Invalid groupBy: %w", err)
+			}
+
+			groupBy = append(groupBy, alias)
+		}
+
+		rawAggregate := report.Aggregate
+		if rawAggregate == "" {
+			rawAggregate = "count(*)"
+		}
+
+		for i, rawField := range splitFields(rawAggregate) {
+			if !isAggregateFunction(rawField) {
+				return nil, nil, fmt.Errorf("Invalid aggregate: missing aggregate function in %q.", rawField)
+			}
+
+			if err := addSelect(rawField, "a"+strconv.Itoa(i)); err != nil {
+				return nil, nil, fmt.Errorf("Invalid aggregate: %w", err)
+			}
+		}
+
+		for _, sortField := range sortFields {
+			alias, ok := aliases[sortField.Name]
+			if !ok {
+				return nil, nil, fmt.Errorf(
+					"Invalid sort: only the groupBy and aggregate fields are sortable, got %q.",
+					sortField.Name,
+				)
+			}
+			query.AndOrderBy(fmt.Sprintf("[[%s]] %s", alias, sortField.Direction))
+		}
+
+		query.Select(selects...).GroupBy(groupBy...).Bind(params)
+	}
+
+	if err := resolver.UpdateQuery(query); err != nil {
+		return nil, nil, err
+	}
+
+	return query, keys, nil
+}
+
+// scanRecordRows executes the records query and returns
+// the exported records as report result.
+func scanRecordRows(query *dbx.SelectQuery, collection *models.Collection) (*Result, error) {
+	rows := []dbx.NullStringMap{}
+	if err := query.All(&rows); err != nil {
+		return nil, err
+	}
+
+	keys := []string{schema.FieldNameId, schema.FieldNameCreated, schema.FieldNameUpdated}
+	for _, field := range collection.Schema.Fields() {
+		keys = append(keys, field.Name)
+	}
+	if collection.IsAuth() {
+		keys = append(
+			keys,
+			schema.FieldNameUsername,
+			schema.FieldNameEmail,
+			schema.FieldNameEmailVisibility,
+			schema.FieldNameVerified,
+		)
+	}
+
+	result := &Result{Keys: keys, Rows: make([]map[string]any, 0, len(rows))}
+
+	for _, record := range models.NewRecordsFromNullStringMaps(collection, rows) {
+		record.IgnoreEmailVisibility(true)
+
+		exported := record.PublicExport()
+
+		row := make(map[string]any, len(keys))
+		for _, key := range keys {
+			row[key] = exported[key]
+		}
+
+		result.Rows = append(result.Rows, row)
+	}
+
+	return result, nil
+}
+
+// scanAggregateRows executes the aggregate query and returns its rows
+// as list of maps with the provided keys (in the selected columns order).
+func scanAggregateRows(query *dbx.SelectQuery, keys []string) ([]map[string]any, error) {
+	rows, err := query.Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []map[string]any{}
+
+	for rows.Next() {
+		values := make([]any, len(keys))
+		pointers := make([]any, len(keys))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		item := make(map[string]any, len(keys))
+		for i, key := range keys {
+			if v, ok := values[i].([]byte); ok {
+				item[key] = string(v)
+			} else {
+				item[key] = values[i]
+			}
+		}
+
+		result = append(result, item)
+	}
+
+	return result, rows.Err()
+}
+
+// csvValue returns the string representation of a single report value
+// (the slices and maps are serialized as JSON).
+func csvValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case types.DateTime:
+		return v.String()
+	case []string, []any, map[string]any, types.JsonArray, types.JsonMap, types.JsonRaw:
+		raw, _ := json.Marshal(v)
+		return string(raw)
+	}
+
+	return cast.ToString(value)
+}
+
+// splitFields splits the comma separated raw fields list.
+func splitFields(raw string) []string {
+	result := []string{}
+
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field != "" {
+			result = append(result, field)
+		}
+	}
+
+	return result
+}
+
+// isAggregateFunction checks whether the raw field is a call
+// to one of the aggregateFunctions (eg. "sum(total)").
+func isAggregateFunction(rawField string) bool {
+	fn, _, ok := strings.Cut(rawField, "(")
+
+	return ok && list.ExistInSlice(strings.ToLower(fn), aggregateFunctions)
+}
@@ -0,0 +1,309 @@
+package reports_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/reports"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/signature"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestGenerate(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name         string
+		report       *models.Report
+		expectError  bool
+		expectedKeys []string
+		expectedRows []string
+	}{
+		{
+			"missing collection",
+			&models.Report{CollectionId: "missing"},
+			true,
+			nil,
+			nil,
+		},
+		{
+			"invalid filter",
+			&models.Report{CollectionId: "demo2", Filter: "missing = 1"},
+			true,
+			nil,
+			nil,
+		},
+		{
+			"plain records",
+			&models.Report{CollectionId: "demo2", Filter: "active = true", Sort: "-title"},
+			false,
+			[]string{"id", "created", "updated", "title", "active"},
+			[]string{"0yxhwia2amd8gec", "achvryl401bhse3"},
+		},
+		{
+			"auth records",
+			&models.Report{CollectionId: "users", Filter: "id = '4q1xlclmfloku33'"},
+			false,
+			[]string{"id", "created", "updated", "name", "avatar", "file", "rel", "username", "email", "emailVisibility", "verified"},
+			[]string{"4q1xlclmfloku33"},
+		},
+		{
+			"aggregate",
+			&models.Report{CollectionId: "demo2", GroupBy: "active", Aggregate: "count(*)", Sort: "-active"},
+			false,
+			[]string{"active", "count(*)"},
+			[]string{"true:2", "false:1"},
+		},
+		{
+			"aggregate with filter and default count",
+			&models.Report{CollectionId: "demo2", Filter: "title != 'test1'", GroupBy: "active"},
+			false,
+			[]string{"active", "count(*)"},
+			[]string{"true:2"},
+		},
+	}
+
+	for _, s := range scenarios {
+		result, err := reports.Generate(app.Dao(), s.report)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		if strings.Join(result.Keys, ",") != strings.Join(s.expectedKeys, ",") {
+			t.Errorf("[%s] Expected keys %v, got %v", s.name, s.expectedKeys, result.Keys)
+		}
+
+		rows := make([]string, len(result.Rows))
+		for i, row := range result.Rows {
+			if _, ok := row["id"]; ok {
+				rows[i] = row["id"].(string)
+			} else {
+				rows[i] = fmt.Sprintf("%v:%v", row["active"], row["count(*)"])
+			}
+		}
+
+		if len(rows) != len(s.expectedRows) {
+			t.Errorf("[%s] Expected %d rows, got %d (%v)", s.name, len(s.expectedRows), len(rows), rows)
+			continue
+		}
+
+		for i, row := range rows {
+			if row != s.expectedRows[i] {
+				t.Errorf("[%s] Expected row %d to be %s, got %s", s.name, i, s.expectedRows[i], row)
+			}
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	result := &reports.Result{
+		Keys: []string{"a", "b", "c"},
+		Rows: []map[string]any{
+			{"a": "test", "b": 1, "c": []string{"x", "y"}},
+			{"a": "with, comma", "b": nil, "c": true},
+		},
+	}
+
+	scenarios := []struct {
+		format      string
+		expectError bool
+		expected    string
+	}{
+		{"", true, ""},
+		{"xml", true, ""},
+		{
+			models.ReportFormatCsv,
+			false,
+			"a,b,c\ntest,1,\"[\"\"x\"\",\"\"y\"\"]\"\n\"with, comma\",,true\n",
+		},
+		{
+			models.ReportFormatJson,
+			false,
+			`[{"a":"test","b":1,"c":["x","y"]},{"a":"with, comma","b":null,"c":true}]`,
+		},
+	}
+
+	for _, s := range scenarios {
+		content, err := reports.Render(result, s.format)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%q) Expected hasErr %v, got %v (%v)", s.format, s.expectError, hasErr, err)
+			continue
+		}
+
+		if string(content) != s.expected {
+			t.Errorf("(%q) Expected\n%s\ngot\n%s", s.format, s.expected, content)
+		}
+	}
+}
+
+func TestFilename(t *testing.T) {
+	report := &models.Report{Name: "weekly orders/all", Format: models.ReportFormatCsv}
+
+	date := time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	result := reports.Filename(report, date)
+
+	expected := "weekly_orders_all_20230102.csv"
+	if result != expected {
+		t.Fatalf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestRun(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	var webhookBody string
+	var webhookHeaders http.Header
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		webhookBody = string(raw)
+		webhookHeaders = r.Header
+
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	report, err := app.Dao().FindReportById("rp1activedemo20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report.WebhookUrl = server.URL
+	report.WebhookSecret = "test_secret"
+
+	run, err := reports.Run(app, report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if run.Status != models.ReportRunStatusSuccess || run.Rows != 2 || run.Error != "" {
+		t.Fatalf("Unexpected run %v", run)
+	}
+
+	// email
+	if app.TestMailer.TotalSend != 2 {
+		t.Fatalf("Expected 2 sent emails, got %d", app.TestMailer.TotalSend)
+	}
+	message := app.TestMailer.LastMessage
+	if message.To.Address != "test2@example.com" {
+		t.Fatalf("Expected the last email to be sent to test2@example.com, got %q", message.To.Address)
+	}
+	if !strings.Contains(message.Subject, "activeDemo2") {
+		t.Fatalf("Expected the email subject to contain the report name, got %q", message.Subject)
+	}
+	if len(message.Attachments) != 1 {
+		t.Fatalf("Expected 1 email attachment, got %d", len(message.Attachments))
+	}
+	for name, r := range message.Attachments {
+		if !strings.HasPrefix(name, "activeDemo2_") || !strings.HasSuffix(name, ".csv") {
+			t.Fatalf("Unexpected attachment name %q", name)
+		}
+		raw, _ := io.ReadAll(r)
+		if !strings.HasPrefix(string(raw), "id,created,updated,title,active\n") {
+			t.Fatalf("Unexpected attachment content %s", raw)
+		}
+	}
+
+	// webhook
+	if webhookHeaders.Get("Content-Type") != "text/csv" {
+		t.Fatalf("Expected text/csv webhook content type, got %q", webhookHeaders.Get("Content-Type"))
+	}
+	if !strings.Contains(webhookBody, "achvryl401bhse3") || !strings.Contains(webhookBody, "0yxhwia2amd8gec") {
+		t.Fatalf("Unexpected webhook body %s", webhookBody)
+	}
+	if err := signature.Verify("test_secret", []byte(webhookBody), webhookHeaders.Get(signature.Header), time.Minute); err != nil {
+		t.Fatalf("Expected valid webhook signature, got %v", err)
+	}
+
+	// history
+	runs, err := app.Dao().FindReportRuns(report.Id, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 3 || runs[0].Id != run.Id {
+		t.Fatalf("Expected the run to be stored as the latest, got %v", runs)
+	}
+
+	updated, err := app.Dao().FindReportById(report.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(updated.LastRunAt.Time()) > time.Minute {
+		t.Fatalf("Expected lastRunAt to be updated, got %v", updated.LastRunAt)
+	}
+
+	// failure
+	report.Emails = nil
+	report.WebhookUrl = server.URL + "/fail"
+
+	failedRun, err := reports.Run(app, report)
+	if err == nil {
+		t.Fatal("Expected the run to fail")
+	}
+	if failedRun.Status != models.ReportRunStatusFailure || !strings.Contains(failedRun.Error, "500") {
+		t.Fatalf("Unexpected failed run %v", failedRun)
+	}
+	if runs, _ := app.Dao().FindReportRuns(report.Id, 0); len(runs) != 4 || runs[0].Id != failedRun.Id {
+		t.Fatalf("Expected the failed run to be stored as the latest, got %v", runs)
+	}
+}
+
+func TestRunDue(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	// 2023-01-02 is Monday
+	monday := time.Date(2023, 1, 2, 9, 0, 30, 0, time.UTC)
+
+	// not due
+	if err := reports.RunDue(app, monday.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if app.TestMailer.TotalSend != 0 {
+		t.Fatalf("Expected no sent emails, got %d", app.TestMailer.TotalSend)
+	}
+
+	// due (the disabled countsDemo2 report should be skipped)
+	if err := reports.RunDue(app, monday); err != nil {
+		t.Fatal(err)
+	}
+	if app.TestMailer.TotalSend != 2 {
+		t.Fatalf("Expected 2 sent emails, got %d", app.TestMailer.TotalSend)
+	}
+	if runs, _ := app.Dao().FindReportRuns("rp2countsdemo20", 0); len(runs) != 0 {
+		t.Fatalf("Expected the disabled report to be skipped, got %v", runs)
+	}
+
+	// already run within the same minute
+	report, _ := app.Dao().FindReportById("rp1activedemo20")
+	report.LastRunAt, _ = types.ParseDateTime(monday)
+	if err := app.Dao().SaveReport(report); err != nil {
+		t.Fatal(err)
+	}
+	if err := reports.RunDue(app, monday); err != nil {
+		t.Fatal(err)
+	}
+	if app.TestMailer.TotalSend != 2 {
+		t.Fatalf("Expected no new emails, got %d", app.TestMailer.TotalSend)
+	}
+}
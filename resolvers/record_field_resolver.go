@@ -3,6 +3,7 @@ package resolvers
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -10,15 +11,15 @@ import (
 	"github.com/pocketbase/pocketbase/daos"
 	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/models/schema"
-	"github.com/pocketbase/pocketbase/tools/inflector"
 	"github.com/pocketbase/pocketbase/tools/list"
 	"github.com/pocketbase/pocketbase/tools/search"
 	"github.com/pocketbase/pocketbase/tools/security"
 	"github.com/spf13/cast"
 )
 
-// ensure that `search.FieldResolver` interface is implemented
+// ensure that `search.FieldResolver` and `search.SortFieldResolver` interfaces are implemented
 var _ search.FieldResolver = (*RecordFieldResolver)(nil)
+var _ search.SortFieldResolver = (*RecordFieldResolver)(nil)
 
 // list of auth filter fields that don't require join with the auth
 // collection or any other extra checks to be resolved
@@ -34,6 +35,9 @@ var plainRequestAuthFields = []string{
 	"@request.auth." + schema.FieldNameUpdated,
 }
 
+// collectionAliasRegex is the allowed @collection join alias format.
+var collectionAliasRegex = regexp.MustCompile(`^\w+$`)
+
 type join struct {
 	id    string
 	table string
@@ -62,6 +66,12 @@ type RecordFieldResolver struct {
 	exprs             []dbx.Expression
 	requestData       *models.RequestData
 	staticRequestData map[string]any
+	authPrefs         map[string]any // lazy loaded on first @request.auth.prefs.* field
+	maxNestedRels     int
+	maxJoins          int
+	multiMatchFields  map[string]struct{} // resolved fields that could match more than one joined row
+	restrictedFields  []string            // resolved field paths with schema field read rule
+	groupByBase       bool
 }
 
 // NewRecordFieldResolver creates and initializes a new `RecordFieldResolver`.
@@ -78,6 +88,7 @@ func NewRecordFieldResolver(
 		allowHiddenFields: allowHiddenFields,
 		joins:             []join{},
 		exprs:             []dbx.Expression{},
+		multiMatchFields:  map[string]struct{}{},
 		loadedCollections: []*models.Collection{baseCollection},
 		allowedFields: []string{
 			`^\w+[\w\.]*$`,
@@ -85,7 +96,8 @@ func NewRecordFieldResolver(
 			`^\@request\.auth\.\w+[\w\.]*$`,
 			`^\@request\.data\.\w+[\w\.]*$`,
 			`^\@request\.query\.\w+[\w\.]*$`,
-			`^\@collection\.\w+\.\w+[\w\.]*$`,
+			`^\@request\.apiKey\.\w+$`,
+			`^\@collection\.\w+(\:\w+)?\.\w+[\w\.]*$`,
 		},
 	}
 
@@ -101,11 +113,69 @@ func NewRecordFieldResolver(
 			r.staticRequestData["auth"] = r.requestData.AuthRecord.PublicExport()
 			r.requestData.AuthRecord.IgnoreEmailVisibility(false)
 		}
+		r.staticRequestData["apiKey"] = nil
+		if r.requestData.ApiKey != nil {
+			scope, _ := r.requestData.ApiKey.Scope(baseCollection.Id)
+			r.staticRequestData["apiKey"] = map[string]any{
+				"id":    r.requestData.ApiKey.Id,
+				"name":  r.requestData.ApiKey.Name,
+				"scope": scope,
+			}
+		}
 	}
 
 	return r
 }
 
+// SetMaxNestedRels sets the max allowed number of nested relations
+// in a single resolved field path (eg. "a.b.c" has 2 nested relations).
+//
+// Zero or negative value means no limit (default).
+func (r *RecordFieldResolver) SetMaxNestedRels(max int) *RecordFieldResolver {
+	r.maxNestedRels = max
+	return r
+}
+
+// SetMaxJoins sets the max allowed number of unique table joins
+// that the resolver could register for all resolved fields.
+//
+// Zero or negative value means no limit (default).
+func (r *RecordFieldResolver) SetMaxJoins(max int) *RecordFieldResolver {
+	r.maxJoins = max
+	return r
+}
+
+// RestrictedFields returns the resolved field paths (eg. "author.salary")
+// that contain at least one schema field with read rule.
+func (r *RecordFieldResolver) RestrictedFields() []string {
+	return r.restrictedFields
+}
+
+// RandomSource implements the optional `search.RandomSourceFieldResolver`
+// interface and returns the resolver dao random source (if any).
+func (r *RecordFieldResolver) RandomSource() security.RandomSource {
+	return r.dao.RandomSource()
+}
+
+// Macros implements the optional `search.MacrosFieldResolver` interface
+// and returns the app level rule macros merged with the base collection ones
+// (the collection macros take precedence).
+func (r *RecordFieldResolver) Macros() map[string]string {
+	result := map[string]string{}
+
+	for name, rule := range r.dao.RuleMacros() {
+		result[name] = rule
+	}
+
+	for name, rule := range r.baseCollection.RuleMacros {
+		if str, ok := rule.(string); ok {
+			result[name] = str
+		}
+	}
+
+	return result
+}
+
 // UpdateQuery implements `search.FieldResolver` interface.
 //
 // Conditionally updates the provided search query based on the
@@ -119,6 +189,11 @@ func (r *RecordFieldResolver) UpdateQuery(query *dbx.SelectQuery) error {
 		}
 	}
 
+	// group the joined rows per base record to allow aggregated sort expressions
+	if r.groupByBase {
+		query.GroupBy(r.dao.Columnify(r.baseCollection.Name) + ".id")
+	}
+
 	for _, expr := range r.exprs {
 		if expr != nil {
 			query.AndWhere(expr)
@@ -136,6 +211,8 @@ func (r *RecordFieldResolver) UpdateQuery(query *dbx.SelectQuery) error {
 //	@request.status
 //	@request.auth.someRelation.name
 //	@collection.product.name
+//	@collection.product:alias.name
+//	updatedBy (for collections with enabled history)
 func (r *RecordFieldResolver) Resolve(fieldName string) (resultName string, placeholderParams dbx.Params, err error) {
 	if len(r.allowedFields) > 0 && !list.ExistInSliceWithRegex(fieldName, r.allowedFields) {
 		return "", nil, fmt.Errorf("Failed to resolve field %q", fieldName)
@@ -143,8 +220,17 @@ func (r *RecordFieldResolver) Resolve(fieldName string) (resultName string, plac
 
 	props := strings.Split(fieldName, ".")
 
+	// flag indicating whether the field could match more than one row
+	// (eg. when a multiple relation or @collection join is involved)
+	multiMatch := false
+	defer func() {
+		if multiMatch && err == nil {
+			r.multiMatchFields[fieldName] = struct{}{}
+		}
+	}()
+
 	currentCollectionName := r.baseCollection.Name
-	currentTableAlias := inflector.Columnify(currentCollectionName)
+	currentTableAlias := r.dao.Columnify(currentCollectionName)
 
 	// flag indicating whether to return null on missing field or return on an error
 	nullifyMisingField := false
@@ -152,14 +238,33 @@ func (r *RecordFieldResolver) Resolve(fieldName string) (resultName string, plac
 	allowHiddenFields := r.allowHiddenFields
 
 	// check for @collection field (aka. non-relational join)
-	// must be in the format "@collection.COLLECTION_NAME.FIELD[.FIELD2....]"
+	// must be in the format "@collection.COLLECTION_NAME[:ALIAS].FIELD[.FIELD2....]"
 	if props[0] == "@collection" {
 		if len(props) < 3 {
 			return "", nil, fmt.Errorf("Invalid @collection field path in %q.", fieldName)
 		}
 
-		currentCollectionName = props[1]
-		currentTableAlias = inflector.Columnify("__collection_" + currentCollectionName)
+		collectionName, alias, hasAlias := strings.Cut(props[1], ":")
+		if hasAlias && !collectionAliasRegex.MatchString(alias) {
+			return "", nil, fmt.Errorf("Invalid @collection alias in %q.", fieldName)
+		}
+
+		currentCollectionName = collectionName
+		currentTableAlias = r.dao.Columnify("__collection_" + currentCollectionName)
+
+		// aliased references are joined independently from the
+		// non-aliased ones (eg. to allow self-joins with different conditions)
+		//
+		// the alias length prefix ensures that different (alias, collection)
+		// pairs can't produce the same table alias (eg. "a_b"+"c" and "a"+"b_c")
+		if hasAlias {
+			currentTableAlias = r.dao.Columnify(fmt.Sprintf(
+				"__collection_alias_%d_%s_%s",
+				len(alias),
+				alias,
+				currentCollectionName,
+			))
+		}
 
 		collection, err := r.loadCollection(currentCollectionName)
 		if err != nil {
@@ -169,7 +274,11 @@ func (r *RecordFieldResolver) Resolve(fieldName string) (resultName string, plac
 		// always allow hidden fields since the @collection.* filter is a system one
 		allowHiddenFields = true
 
-		r.registerJoin(inflector.Columnify(collection.Name), currentTableAlias, nil)
+		multiMatch = true
+
+		if err := r.registerJoin(r.dao.Columnify(collection.QualifiedTableName()), currentTableAlias, nil); err != nil {
+			return "", nil, err
+		}
 
 		props = props[2:] // leave only the collection fields
 	} else if props[0] == "@request" {
@@ -181,6 +290,11 @@ func (r *RecordFieldResolver) Resolve(fieldName string) (resultName string, plac
 			return "NULL", nil, nil
 		}
 
+		// auth record preferences field
+		if len(props) > 3 && props[1] == "auth" && props[2] == "prefs" && !r.hasAuthPrefsSchemaField() {
+			return r.resolveAuthPrefsField(props[3:]...)
+		}
+
 		// plain @request.* field
 		if !strings.HasPrefix(fieldName, "@request.auth.") || list.ExistInSlice(fieldName, plainRequestAuthFields) {
 			return r.resolveStaticRequestField(props[1:]...)
@@ -203,28 +317,32 @@ func (r *RecordFieldResolver) Resolve(fieldName string) (resultName string, plac
 		r.loadedCollections = append(r.loadedCollections, collection)
 
 		currentCollectionName = collection.Name
-		currentTableAlias = "__auth_" + inflector.Columnify(currentCollectionName)
+		currentTableAlias = "__auth_" + r.dao.Columnify(currentCollectionName)
 
-		authIdParamKey := "auth" + security.PseudorandomString(5)
+		authIdParamKey := "auth" + r.dao.RandomString(5)
 		authIdParams := dbx.Params{authIdParamKey: r.requestData.AuthRecord.Id}
 		// ---
 
 		// join the auth collection
-		r.registerJoin(
-			inflector.Columnify(collection.Name),
+		joinErr := r.registerJoin(
+			r.dao.Columnify(collection.QualifiedTableName()),
 			currentTableAlias,
 			dbx.NewExp(fmt.Sprintf(
 				// aka. __auth_users.id = :userId
 				"[[%s.id]] = {:%s}",
-				inflector.Columnify(currentTableAlias),
+				r.dao.Columnify(currentTableAlias),
 				authIdParamKey,
 			), authIdParams),
 		)
+		if joinErr != nil {
+			return "", nil, joinErr
+		}
 
 		props = props[2:] // leave only the auth relation fields
 	}
 
 	totalProps := len(props)
+	totalNestedRels := 0
 
 	for i, prop := range props {
 		collection, err := r.loadCollection(currentCollectionName)
@@ -242,6 +360,9 @@ func (r *RecordFieldResolver) Resolve(fieldName string) (resultName string, plac
 				schema.FieldNameEmail,
 			)
 		}
+		if collection.TreeField() != nil {
+			systemFieldNames = append(systemFieldNames, schema.FieldNamePath)
+		}
 
 		// internal model prop (always available but not part of the collection schema)
 		if list.ExistInSlice(prop, systemFieldNames) {
@@ -250,15 +371,22 @@ func (r *RecordFieldResolver) Resolve(fieldName string) (resultName string, plac
 				r.registerExpr(dbx.NewExp(fmt.Sprintf(
 					"[[%s.%s]] = TRUE",
 					currentTableAlias,
-					inflector.Columnify(schema.FieldNameEmailVisibility),
+					r.dao.Columnify(schema.FieldNameEmailVisibility),
 				)))
 			}
 
-			return fmt.Sprintf("[[%s.%s]]", currentTableAlias, inflector.Columnify(prop)), nil, nil
+			return fmt.Sprintf("[[%s.%s]]", currentTableAlias, r.dao.Columnify(prop)), nil, nil
 		}
 
 		field := collection.Schema.GetFieldByName(prop)
 		if field == nil {
+			// virtual record history field (eg. "updatedBy")
+			if i == totalProps-1 &&
+				collection.HistoryOptions().Enabled &&
+				list.ExistInSlice(prop, historyMetaFieldNames) {
+				return r.resolveHistoryMetaField(collection, currentTableAlias, prop)
+			}
+
 			if nullifyMisingField {
 				return "NULL", nil, nil
 			}
@@ -266,9 +394,65 @@ func (r *RecordFieldResolver) Resolve(fieldName string) (resultName string, plac
 			return "", nil, fmt.Errorf("Unrecognized field %q.", prop)
 		}
 
+		if field.ReadRule != "" && !list.ExistInSlice(fieldName, r.restrictedFields) {
+			r.restrictedFields = append(r.restrictedFields, fieldName)
+		}
+
 		// last prop
 		if i == totalProps-1 {
-			return fmt.Sprintf("[[%s.%s]]", currentTableAlias, inflector.Columnify(prop)), nil, nil
+			// the encrypted values can't be compared at db level
+			if daos.IsEncryptedRecordField(collection, field) {
+				return "", nil, fmt.Errorf("Encrypted field %q cannot be filtered or sorted.", prop)
+			}
+
+			// computed fields are resolved to their expression
+			if field.Type == schema.FieldTypeComputed {
+				expr, err := collection.ComputedFieldExpr(field, currentTableAlias)
+				if err != nil {
+					return "", nil, err
+				}
+				return "(" + expr + ")", nil, nil
+			}
+
+			return fmt.Sprintf("[[%s.%s]]", currentTableAlias, r.dao.Columnify(prop)), nil, nil
+		}
+
+		// check if it is a geo point coordinate (eg. "location.lon")
+		if field.Type == schema.FieldTypeGeoPoint {
+			coord := props[i+1]
+			if i != totalProps-2 || (coord != "lon" && coord != "lat") {
+				return "", nil, fmt.Errorf("Invalid geo point field %q (expected %s.lon or %s.lat).", fieldName, prop, prop)
+			}
+
+			if daos.IsEncryptedRecordField(collection, field) {
+				return "", nil, fmt.Errorf("Encrypted field %q cannot be filtered or sorted.", prop)
+			}
+
+			return fmt.Sprintf(
+				"JSON_EXTRACT([[%s.%s]], '$.%s')",
+				currentTableAlias,
+				r.dao.Columnify(prop),
+				coord,
+			), nil, nil
+		}
+
+		// check if it is a document content prop (eg. "content.text")
+		if field.Type == schema.FieldTypeDocument {
+			docProp := props[i+1]
+			if i != totalProps-2 || (docProp != "html" && docProp != "text") {
+				return "", nil, fmt.Errorf("Invalid document field %q (expected %s.html or %s.text).", fieldName, prop, prop)
+			}
+
+			if daos.IsEncryptedRecordField(collection, field) {
+				return "", nil, fmt.Errorf("Encrypted field %q cannot be filtered or sorted.", prop)
+			}
+
+			return fmt.Sprintf(
+				"JSON_EXTRACT([[%s.%s]], '$.%s')",
+				currentTableAlias,
+				r.dao.Columnify(prop),
+				docProp,
+			), nil, nil
 		}
 
 		// check if it is a json field
@@ -278,17 +462,17 @@ func (r *RecordFieldResolver) Resolve(fieldName string) (resultName string, plac
 			for _, p := range props[i+1:] {
 				if _, err := strconv.Atoi(p); err == nil {
 					jsonPath.WriteString("[")
-					jsonPath.WriteString(inflector.Columnify(p))
+					jsonPath.WriteString(r.dao.Columnify(p))
 					jsonPath.WriteString("]")
 				} else {
 					jsonPath.WriteString(".")
-					jsonPath.WriteString(inflector.Columnify(p))
+					jsonPath.WriteString(r.dao.Columnify(p))
 				}
 			}
 			return fmt.Sprintf(
 				"JSON_EXTRACT([[%s.%s]], '%s')",
 				currentTableAlias,
-				inflector.Columnify(prop),
+				r.dao.Columnify(prop),
 				jsonPath.String(),
 			), nil, nil
 		}
@@ -298,6 +482,11 @@ func (r *RecordFieldResolver) Resolve(fieldName string) (resultName string, plac
 			return "", nil, fmt.Errorf("Field %q is not a valid relation.", prop)
 		}
 
+		totalNestedRels++
+		if r.maxNestedRels > 0 && totalNestedRels > r.maxNestedRels {
+			return "", nil, fmt.Errorf("Max nested relations limit of %d reached in %q.", r.maxNestedRels, fieldName)
+		}
+
 		// auto join the relation
 		// ---
 		field.InitOptions()
@@ -306,19 +495,23 @@ func (r *RecordFieldResolver) Resolve(fieldName string) (resultName string, plac
 			return "", nil, fmt.Errorf("Failed to initialize field %q options.", prop)
 		}
 
+		if options.MaxSelect == nil || *options.MaxSelect != 1 {
+			multiMatch = true
+		}
+
 		relCollection, relErr := r.loadCollection(options.CollectionId)
 		if relErr != nil {
 			return "", nil, fmt.Errorf("Failed to find field %q collection.", prop)
 		}
 
-		cleanFieldName := inflector.Columnify(field.Name)
+		cleanFieldName := r.dao.Columnify(field.Name)
 		newCollectionName := relCollection.Name
 		newTableAlias := currentTableAlias + "_" + cleanFieldName
 
 		jeTable := currentTableAlias + "_" + cleanFieldName + "_je"
 		jePair := currentTableAlias + "." + cleanFieldName
 
-		r.registerJoin(
+		jeErr := r.registerJoin(
 			fmt.Sprintf(
 				// note: the case is used to normalize value access for single and multiple relations.
 				`json_each(CASE WHEN json_valid([[%s]]) THEN [[%s]] ELSE json_array([[%s]]) END)`,
@@ -327,11 +520,18 @@ func (r *RecordFieldResolver) Resolve(fieldName string) (resultName string, plac
 			jeTable,
 			nil,
 		)
-		r.registerJoin(
-			inflector.Columnify(newCollectionName),
+		if jeErr != nil {
+			return "", nil, jeErr
+		}
+
+		relErr = r.registerJoin(
+			r.dao.Columnify(relCollection.QualifiedTableName()),
 			newTableAlias,
 			dbx.NewExp(fmt.Sprintf("[[%s.id]] = [[%s.value]]", newTableAlias, jeTable)),
 		)
+		if relErr != nil {
+			return "", nil, relErr
+		}
 
 		currentCollectionName = newCollectionName
 		currentTableAlias = newTableAlias
@@ -340,11 +540,71 @@ func (r *RecordFieldResolver) Resolve(fieldName string) (resultName string, plac
 	return "", nil, fmt.Errorf("Failed to resolve field %q.", fieldName)
 }
 
+// ResolveSort implements the `search.SortFieldResolver` interface.
+//
+// Fields that could match more than one joined row (eg. from a multiple
+// relation) are aggregated per record using MIN for ASC and MAX for DESC
+// direction, so that the sort order is deterministic.
+func (r *RecordFieldResolver) ResolveSort(fieldName string, direction string) (resultName string, placeholderParams dbx.Params, err error) {
+	name, params, err := r.Resolve(fieldName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if _, ok := r.multiMatchFields[fieldName]; !ok {
+		return name, params, nil
+	}
+
+	r.groupByBase = true
+
+	if strings.EqualFold(direction, search.SortDesc) {
+		return fmt.Sprintf("MAX(%s)", name), params, nil
+	}
+
+	return fmt.Sprintf("MIN(%s)", name), params, nil
+}
+
+// hasAuthPrefsSchemaField checks whether the request auth collection has
+// its own "prefs" schema field (it has precedence over the preferences).
+func (r *RecordFieldResolver) hasAuthPrefsSchemaField() bool {
+	if r.requestData.AuthRecord == nil || r.requestData.AuthRecord.Collection() == nil {
+		return false
+	}
+
+	return r.requestData.AuthRecord.Collection().Schema.GetFieldByName("prefs") != nil
+}
+
+// resolveAuthPrefsField resolves the value of the request auth record
+// preference as static param (missing preferences are resolved as NULL).
+func (r *RecordFieldResolver) resolveAuthPrefsField(path ...string) (resultName string, placeholderParams dbx.Params, err error) {
+	if r.requestData.AuthRecord == nil || r.requestData.AuthRecord.Collection() == nil {
+		return "NULL", nil, nil
+	}
+
+	if r.authPrefs == nil {
+		prefs, err := r.dao.FindResolvedRecordPreferences(r.requestData.AuthRecord)
+		if err != nil {
+			return "", nil, err
+		}
+		r.authPrefs = prefs
+	}
+
+	// ignore error because the preference may not be declared
+	resultVal, _ := extractNestedMapVal(r.authPrefs, path...)
+
+	return r.resolveStaticValue(resultVal)
+}
+
 func (r *RecordFieldResolver) resolveStaticRequestField(path ...string) (resultName string, placeholderParams dbx.Params, err error) {
 	// ignore error because requestData is dynamic and some of the
 	// lookup keys may not be defined for the request
 	resultVal, _ := extractNestedMapVal(r.staticRequestData, path...)
 
+	return r.resolveStaticValue(resultVal)
+}
+
+// resolveStaticValue registers the provided plain value as placeholder param.
+func (r *RecordFieldResolver) resolveStaticValue(resultVal any) (resultName string, placeholderParams dbx.Params, err error) {
 	switch v := resultVal.(type) {
 	case nil:
 		return "NULL", nil, nil
@@ -366,7 +626,7 @@ func (r *RecordFieldResolver) resolveStaticRequestField(path ...string) (resultN
 		resultVal = val
 	}
 
-	placeholder := "f" + security.PseudorandomString(5)
+	placeholder := "f" + r.dao.RandomString(5)
 	name := fmt.Sprintf("{:%s}", placeholder)
 	params := dbx.Params{placeholder: resultVal}
 
@@ -414,7 +674,7 @@ func (r *RecordFieldResolver) loadCollection(collectionNameOrId string) (*models
 	return collection, nil
 }
 
-func (r *RecordFieldResolver) registerJoin(tableName string, tableAlias string, on dbx.Expression) {
+func (r *RecordFieldResolver) registerJoin(tableName string, tableAlias string, on dbx.Expression) error {
 	tableExpr := (tableName + " " + tableAlias)
 
 	join := join{
@@ -427,12 +687,18 @@ func (r *RecordFieldResolver) registerJoin(tableName string, tableAlias string,
 	for i, j := range r.joins {
 		if j.id == join.id {
 			r.joins[i] = join
-			return
+			return nil
 		}
 	}
 
+	if r.maxJoins > 0 && len(r.joins) >= r.maxJoins {
+		return fmt.Errorf("Max joins limit of %d reached.", r.maxJoins)
+	}
+
 	// register new join
 	r.joins = append(r.joins, join)
+
+	return nil
 }
 
 func (r *RecordFieldResolver) registerExpr(expr dbx.Expression) {
@@ -0,0 +1,134 @@
+package resolvers
+
+import (
+	"fmt"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+// ensure that `search.HistoryFieldResolver` interface is implemented
+var _ search.HistoryFieldResolver = (*RecordFieldResolver)(nil)
+
+// historyMetaFieldNames is the list with the virtual record history fields
+// (they are resolvable only for collections with enabled history).
+var historyMetaFieldNames = []string{
+	schema.FieldNameCreatedBy,
+	schema.FieldNameUpdatedBy,
+}
+
+// historyTableAlias is the table alias of the record history entries subqueries.
+const historyTableAlias = "__history"
+
+// resolveHistoryMetaField resolves the "createdBy" and "updatedBy" virtual
+// fields to the author id of the record create and last change history entry.
+func (r *RecordFieldResolver) resolveHistoryMetaField(
+	collection *models.Collection,
+	tableAlias string,
+	prop string,
+) (resultName string, placeholderParams dbx.Params, err error) {
+	where, params := r.historyEntriesCondition(collection, tableAlias, "")
+
+	if prop == schema.FieldNameCreatedBy {
+		where += fmt.Sprintf(" AND [[%s.action]] = '%s'", historyTableAlias, models.RecordHistoryActionCreate)
+	}
+
+	return r.historyAuthIdSubquery(where), params, nil
+}
+
+// ResolveChangedSince implements the optional `search.HistoryFieldResolver` interface.
+//
+// Resolves to 1 if the base collection record has a history entry with
+// change of the specified field created after since, otherwise to 0.
+func (r *RecordFieldResolver) ResolveChangedSince(field string, since string) (string, dbx.Params, error) {
+	where, params, err := r.fieldChangesCondition(field)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return fmt.Sprintf(
+		"EXISTS (SELECT 1 FROM {{%s}} [[%s]] WHERE %s AND [[%s.created]] > %s)",
+		(&models.RecordHistory{}).TableName(),
+		historyTableAlias,
+		where,
+		historyTableAlias,
+		since,
+	), params, nil
+}
+
+// ResolveChangedBy implements the optional `search.HistoryFieldResolver` interface.
+//
+// Resolves to the author id of the last base collection record
+// history entry with change of the specified field.
+func (r *RecordFieldResolver) ResolveChangedBy(field string) (string, dbx.Params, error) {
+	where, params, err := r.fieldChangesCondition(field)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return r.historyAuthIdSubquery(where), params, nil
+}
+
+// fieldChangesCondition returns the history entries condition that matches
+// the base collection record changes of the specified schema field.
+func (r *RecordFieldResolver) fieldChangesCondition(fieldName string) (string, dbx.Params, error) {
+	collection := r.baseCollection
+
+	if !collection.HistoryOptions().Enabled {
+		return "", nil, fmt.Errorf("The %q collection history is not enabled.", collection.Name)
+	}
+
+	field := collection.Schema.GetFieldByName(fieldName)
+	if field == nil || !daos.IsRecordHistoryField(field) {
+		return "", nil, fmt.Errorf("The changes of field %q are not tracked by the records history.", fieldName)
+	}
+
+	where, params := r.historyEntriesCondition(collection, r.dao.Columnify(collection.Name), fieldName)
+
+	return where, params, nil
+}
+
+// historyEntriesCondition returns the condition that matches the
+// history entries of the tableAlias record (optionally only those
+// with change of the specified field).
+func (r *RecordFieldResolver) historyEntriesCondition(
+	collection *models.Collection,
+	tableAlias string,
+	changedField string,
+) (string, dbx.Params) {
+	collectionParam := "history" + r.dao.RandomString(5)
+	params := dbx.Params{collectionParam: collection.Id}
+
+	where := fmt.Sprintf(
+		"[[%s.collectionId]] = {:%s} AND [[%s.recordId]] = [[%s.id]]",
+		historyTableAlias,
+		collectionParam,
+		historyTableAlias,
+		tableAlias,
+	)
+
+	if changedField != "" {
+		pathParam := "history" + r.dao.RandomString(5)
+		params[pathParam] = fmt.Sprintf(`$."%s"`, changedField)
+
+		where += fmt.Sprintf(" AND json_type([[%s.changes]], {:%s}) IS NOT NULL", historyTableAlias, pathParam)
+	}
+
+	return where, params
+}
+
+// historyAuthIdSubquery returns a subquery that selects the author id
+// of the last history entry matching the provided condition.
+func (r *RecordFieldResolver) historyAuthIdSubquery(where string) string {
+	return fmt.Sprintf(
+		"(SELECT [[%s.authId]] FROM {{%s}} [[%s]] WHERE %s ORDER BY [[%s.version]] DESC LIMIT 1)",
+		historyTableAlias,
+		(&models.RecordHistory{}).TableName(),
+		historyTableAlias,
+		where,
+		historyTableAlias,
+	)
+}
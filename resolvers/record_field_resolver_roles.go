@@ -0,0 +1,40 @@
+package resolvers
+
+import (
+	"fmt"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+// ensure that `search.RoleFieldResolver` interface is implemented
+var _ search.RoleFieldResolver = (*RecordFieldResolver)(nil)
+
+// ResolveAuthHasRole implements the optional `search.RoleFieldResolver` interface.
+//
+// Resolves to 1 if the request auth record has a role with the
+// specified name, otherwise to 0 (including for guests and admins).
+func (r *RecordFieldResolver) ResolveAuthHasRole(role string) (string, dbx.Params, error) {
+	if r.requestData == nil || r.requestData.AuthRecord == nil {
+		return "0", nil, nil
+	}
+
+	authRecord := r.requestData.AuthRecord
+
+	collectionParam := "role" + r.dao.RandomString(5)
+	recordParam := "role" + r.dao.RandomString(5)
+
+	return fmt.Sprintf(
+		"EXISTS (SELECT 1 FROM {{%s}} [[__authRoles]] INNER JOIN {{%s}} [[__roles]] ON [[__roles.id]] = [[__authRoles.roleId]] "+
+			"WHERE [[__authRoles.collectionId]] = {:%s} AND [[__authRoles.recordId]] = {:%s} AND [[__roles.name]] = %s)",
+		(&models.AuthRole{}).TableName(),
+		(&models.Role{}).TableName(),
+		collectionParam,
+		recordParam,
+		role,
+	), dbx.Params{
+		collectionParam: authRecord.Collection().Id,
+		recordParam:     authRecord.Id,
+	}, nil
+}
@@ -3,12 +3,20 @@ package resolvers_test
 import (
 	"encoding/json"
 	"regexp"
+	"sort"
+	"strings"
 	"testing"
 
+	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/schema"
+	"github.com/pocketbase/pocketbase/models/settings"
 	"github.com/pocketbase/pocketbase/resolvers"
 	"github.com/pocketbase/pocketbase/tests"
 	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
 )
 
 func TestRecordFieldResolverUpdateQuery(t *testing.T) {
@@ -108,6 +116,13 @@ func TestRecordFieldResolverUpdateQuery(t *testing.T) {
 			false,
 			"SELECT DISTINCT `demo4`.* FROM `demo4` LEFT JOIN `demo1` `__collection_demo1` LEFT JOIN `demo2` `__collection_demo2`",
 		},
+		{
+			"@collection join with aliases",
+			"demo4",
+			[]string{"@collection.demo1.text", "@collection.demo1:a.text", "@collection.demo1:b.text", "@collection.demo1:a.file_one"},
+			false,
+			"SELECT DISTINCT `demo4`.* FROM `demo4` LEFT JOIN `demo1` `__collection_demo1` LEFT JOIN `demo1` `__collection_alias_1_a_demo1` LEFT JOIN `demo1` `__collection_alias_1_b_demo1`",
+		},
 		{
 			"@request.auth fields",
 			"demo4",
@@ -186,6 +201,12 @@ func TestRecordFieldResolverResolveSchemaFields(t *testing.T) {
 		AuthRecord: authRecord,
 	}
 
+	collection.Schema.AddField(&schema.SchemaField{
+		Name:    "computed",
+		Type:    schema.FieldTypeComputed,
+		Options: &schema.ComputedOptions{Expression: "title * 2"},
+	})
+
 	r := resolvers.NewRecordFieldResolver(app.Dao(), collection, requestData, true)
 
 	scenarios := []struct {
@@ -202,6 +223,9 @@ func TestRecordFieldResolverResolveSchemaFields(t *testing.T) {
 		{"updated", false, "[[demo4.updated]]"},
 		{"title", false, "[[demo4.title]]"},
 		{"title.test", true, ""},
+		{"computed", false, "(([[demo4.title]] * 2))"},
+		{"computed.test", true, ""},
+		{"self_rel_one.computed", false, "(([[demo4_self_rel_one.title]] * 2))"},
 		{"self_rel_many", false, "[[demo4.self_rel_many]]"},
 		{"self_rel_many.", true, ""},
 		{"self_rel_many.unknown", true, ""},
@@ -225,6 +249,11 @@ func TestRecordFieldResolverResolveSchemaFields(t *testing.T) {
 		{"@collection.demo4.id", false, "[[__collection_demo4.id]]"},
 		{"@collection.demo4.created", false, "[[__collection_demo4.created]]"},
 		{"@collection.demo4.updated", false, "[[__collection_demo4.updated]]"},
+		{"@collection.demo4:.title", true, ""},
+		{"@collection.demo4:alias", true, ""},
+		{"@collection.demo4:alias-x.title", true, ""},
+		{"@collection.demo4:alias.title", false, "[[__collection_alias_5_alias_demo4.title]]"},
+		{"@collection.demo4:alias.self_rel_one.title", false, "[[__collection_alias_5_alias_demo4_self_rel_one.title]]"},
 		{"@collection.demo4.self_rel_many.missing", true, ""},
 		{"@collection.demo4.self_rel_many.self_rel_one.self_rel_many.self_rel_one.title", false, "[[__collection_demo4_self_rel_many_self_rel_one_self_rel_many_self_rel_one.title]]"},
 	}
@@ -349,3 +378,799 @@ func TestRecordFieldResolverResolveStaticRequestDataFields(t *testing.T) {
 		}
 	}
 }
+
+func TestRecordFieldResolverResolveAuthPrefsFields(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().Preferences.Keys = []settings.PreferenceKey{
+		{Key: "theme", Type: settings.PreferenceTypeSelect, Values: []string{"light", "dark"}, Default: "light"},
+		{Key: "pageSize", Type: settings.PreferenceTypeNumber, Default: 20},
+	}
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo4")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authRecord, err := app.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prefs, err := app.Dao().FindRecordPreferences(authRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefs.Data["theme"] = "dark"
+	if err := app.Dao().SaveRecordPreferences(prefs); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name             string
+		authRecord       *models.Record
+		fieldName        string
+		expectParamValue string // encoded json
+	}{
+		{"guest", nil, "@request.auth.prefs.theme", ""},
+		{"stored value", authRecord, "@request.auth.prefs.theme", `"dark"`},
+		{"default value", authRecord, "@request.auth.prefs.pageSize", `20`},
+		{"undeclared key", authRecord, "@request.auth.prefs.missing", ""},
+	}
+
+	for _, s := range scenarios {
+		r := resolvers.NewRecordFieldResolver(app.Dao(), collection, &models.RequestData{AuthRecord: s.authRecord}, true)
+
+		name, params, err := r.Resolve(s.fieldName)
+		if err != nil {
+			t.Errorf("[%s] Unexpected error %v", s.name, err)
+			continue
+		}
+
+		if s.expectParamValue == "" {
+			if name != "NULL" || len(params) != 0 {
+				t.Errorf("[%s] Expected NULL, got %q (%v)", s.name, name, params)
+			}
+			continue
+		}
+
+		if len(params) != 1 {
+			t.Errorf("[%s] Expected 1 placeholder parameter, got %v", s.name, params)
+			continue
+		}
+
+		for _, v := range params {
+			encoded, _ := json.Marshal(v)
+			if string(encoded) != s.expectParamValue {
+				t.Errorf("[%s] Expected param %s, got %s", s.name, s.expectParamValue, encoded)
+			}
+		}
+	}
+}
+
+func TestRecordFieldResolverResolveLimits(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo4")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		maxNestedRels int
+		maxJoins      int
+		fields        []string
+		expectError   bool
+	}{
+		// no limits
+		{0, 0, []string{"self_rel_many.self_rel_one.self_rel_many.title"}, false},
+		{-1, -1, []string{"self_rel_many.self_rel_one.self_rel_many.title"}, false},
+		// nested relations limit
+		{1, 0, []string{"self_rel_many.title"}, false},
+		{1, 0, []string{"self_rel_many.self_rel_one.title"}, true},
+		{2, 0, []string{"self_rel_many.self_rel_one.title"}, false},
+		{2, 0, []string{"self_rel_many.title", "self_rel_one.title"}, false},
+		{2, 0, []string{"self_rel_many.title", "self_rel_one.title", "self_rel_many.self_rel_one.title"}, false},
+		// joins limit
+		{0, 2, []string{"self_rel_many.title"}, false},
+		{0, 1, []string{"self_rel_many.title"}, true},
+		{0, 2, []string{"self_rel_many.title", "self_rel_many.title"}, false},
+		{0, 2, []string{"self_rel_many.title", "self_rel_one.title"}, true},
+	}
+
+	for i, s := range scenarios {
+		r := resolvers.NewRecordFieldResolver(app.Dao(), collection, nil, true).
+			SetMaxNestedRels(s.maxNestedRels).
+			SetMaxJoins(s.maxJoins)
+
+		var resolveErr error
+		for _, field := range s.fields {
+			if _, _, resolveErr = r.Resolve(field); resolveErr != nil {
+				break
+			}
+		}
+
+		hasErr := resolveErr != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, resolveErr)
+		}
+	}
+}
+
+func TestRecordFieldResolverResolveSort(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo4")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		fieldName     string
+		direction     string
+		expectError   bool
+		expectName    string
+		expectGroupBy bool
+	}{
+		{"", search.SortAsc, true, "", false},
+		{"missing", search.SortAsc, true, "", false},
+		{"title", search.SortAsc, false, "[[demo4.title]]", false},
+		{"title", search.SortDesc, false, "[[demo4.title]]", false},
+		{"json_object.a.b", search.SortAsc, false, "JSON_EXTRACT([[demo4.json_object]], '$.a.b')", false},
+		{"self_rel_one.title", search.SortDesc, false, "[[demo4_self_rel_one.title]]", false},
+		{"self_rel_many.title", search.SortAsc, false, "MIN([[demo4_self_rel_many.title]])", true},
+		{"self_rel_many.title", search.SortDesc, false, "MAX([[demo4_self_rel_many.title]])", true},
+		{"self_rel_one.self_rel_many.json_object.a", search.SortDesc, false, "MAX(JSON_EXTRACT([[demo4_self_rel_one_self_rel_many.json_object]], '$.a'))", true},
+		{"self_rel_many.self_rel_one.title", search.SortAsc, false, "MIN([[demo4_self_rel_many_self_rel_one.title]])", true},
+	}
+
+	for i, s := range scenarios {
+		r := resolvers.NewRecordFieldResolver(app.Dao(), collection, nil, true)
+
+		name, params, err := r.ResolveSort(s.fieldName, s.direction)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if name != s.expectName {
+			t.Errorf("(%d) Expected name %q, got %q", i, s.expectName, name)
+		}
+
+		if len(params) != 0 {
+			t.Errorf("(%d) Expected 0 params, got %v", i, params)
+		}
+
+		query := app.Dao().RecordQuery(collection)
+		if err := r.UpdateQuery(query); err != nil {
+			t.Errorf("(%d) UpdateQuery failed with error %v", i, err)
+			continue
+		}
+
+		hasGroupBy := len(query.Info().GroupBy) > 0
+		if hasGroupBy != s.expectGroupBy {
+			t.Errorf("(%d) Expected hasGroupBy %v, got %v", i, s.expectGroupBy, hasGroupBy)
+		}
+	}
+}
+
+func TestRecordFieldResolverSortWithProvider(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		sort        string
+		expectedIds []string
+	}{
+		{"-rel_one.text,id", []string{"al1h9ijdeojtsjy", "84nmscqy84lsi1t", "imy661ixudk5izi"}},
+		{"-json.0,id", []string{"84nmscqy84lsi1t", "al1h9ijdeojtsjy", "imy661ixudk5izi"}},
+		{"-rel_many.username,id", []string{"al1h9ijdeojtsjy", "84nmscqy84lsi1t", "imy661ixudk5izi"}},
+		{"rel_many.username,-id", []string{"imy661ixudk5izi", "al1h9ijdeojtsjy", "84nmscqy84lsi1t"}},
+		{"computed,id", []string{"84nmscqy84lsi1t", "al1h9ijdeojtsjy", "imy661ixudk5izi"}},
+		{"-computed,id", []string{"imy661ixudk5izi", "al1h9ijdeojtsjy", "84nmscqy84lsi1t"}},
+	}
+
+	collection.Schema.AddField(&schema.SchemaField{
+		Name:    "computed",
+		Type:    schema.FieldTypeComputed,
+		Options: &schema.ComputedOptions{Expression: "-number"},
+	})
+
+	for _, s := range scenarios {
+		r := resolvers.NewRecordFieldResolver(app.Dao(), collection, nil, true)
+
+		rows := []dbx.NullStringMap{}
+
+		result, err := search.NewProvider(r).
+			Query(app.Dao().RecordQuery(collection)).
+			Sort(search.ParseSortFromString(s.sort)).
+			Exec(&rows)
+		if err != nil {
+			t.Errorf("[%s] Failed to execute the search provider: %v", s.sort, err)
+			continue
+		}
+
+		if result.TotalItems != len(s.expectedIds) {
+			t.Errorf("[%s] Expected %d total items, got %d", s.sort, len(s.expectedIds), result.TotalItems)
+		}
+
+		records := models.NewRecordsFromNullStringMaps(collection, rows)
+
+		ids := make([]string, len(records))
+		for i, record := range records {
+			ids[i] = record.Id
+		}
+
+		encodedIds, _ := json.Marshal(ids)
+		encodedExpectedIds, _ := json.Marshal(s.expectedIds)
+		if string(encodedIds) != string(encodedExpectedIds) {
+			t.Errorf("[%s] Expected ids %s, got %s", s.sort, encodedExpectedIds, encodedIds)
+		}
+	}
+}
+
+func TestRecordFieldResolverSortWithCursorProvider(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		sort        string
+		expectedIds []string
+	}{
+		{"-rel_one.text", []string{"al1h9ijdeojtsjy", "84nmscqy84lsi1t", "imy661ixudk5izi"}},
+		{"-json.0,id", []string{"84nmscqy84lsi1t", "al1h9ijdeojtsjy", "imy661ixudk5izi"}},
+		{"-rel_many.username", []string{"al1h9ijdeojtsjy", "84nmscqy84lsi1t", "imy661ixudk5izi"}},
+		{"rel_many.username,-id", []string{"imy661ixudk5izi", "al1h9ijdeojtsjy", "84nmscqy84lsi1t"}},
+	}
+
+	for _, s := range scenarios {
+		ids := []string{}
+
+		// fetch the records one by one
+		cursor := ""
+		for i := 0; i < len(s.expectedIds)+1; i++ {
+			r := resolvers.NewRecordFieldResolver(app.Dao(), collection, nil, true)
+
+			rows := []dbx.NullStringMap{}
+
+			result, err := search.NewProvider(r).
+				Query(app.Dao().RecordQuery(collection)).
+				Sort(search.ParseSortFromString(s.sort)).
+				PerPage(1).
+				Cursor(cursor).
+				Exec(&rows)
+			if err != nil {
+				t.Fatalf("[%s] Failed to execute the search provider: %v", s.sort, err)
+			}
+
+			for _, record := range models.NewRecordsFromNullStringMaps(collection, rows) {
+				ids = append(ids, record.Id)
+			}
+
+			cursor = *result.Cursor
+			if cursor == "" {
+				break
+			}
+		}
+
+		encodedIds, _ := json.Marshal(ids)
+		encodedExpectedIds, _ := json.Marshal(s.expectedIds)
+		if string(encodedIds) != string(encodedExpectedIds) {
+			t.Errorf("[%s] Expected ids %s, got %s", s.sort, encodedExpectedIds, encodedIds)
+		}
+	}
+}
+
+func TestRecordFieldResolverEncryptedFields(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection.Options["encryption"] = map[string]any{"enabled": true}
+
+	r := resolvers.NewRecordFieldResolver(app.Dao(), collection, &models.RequestData{}, true)
+
+	scenarios := []struct {
+		fieldName   string
+		expectError bool
+	}{
+		{"id", false},
+		{"created", false},
+		{"text", true},
+		{"number", true},
+		{"rel_one", false},
+		{"file_one", false},
+	}
+
+	for _, s := range scenarios {
+		_, _, err := r.Resolve(s.fieldName)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%q) Expected hasErr %v, got %v (%v)", s.fieldName, s.expectError, hasErr, err)
+		}
+	}
+}
+
+func TestRecordFieldResolverRestrictedFields(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection.Schema.GetFieldByName("text").ReadRule = `@request.auth.id != ""`
+
+	r := resolvers.NewRecordFieldResolver(app.Dao(), collection, &models.RequestData{}, false)
+
+	for _, fieldName := range []string{"id", "text", "number", "text"} {
+		if _, _, err := r.Resolve(fieldName); err != nil {
+			t.Fatalf("(%q) Failed to resolve the field: %v", fieldName, err)
+		}
+	}
+
+	restricted := r.RestrictedFields()
+	if len(restricted) != 1 || restricted[0] != "text" {
+		t.Fatalf("Expected only the text field to be restricted, got %v", restricted)
+	}
+}
+
+func TestRecordFieldResolverGeoPointFields(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection.Schema.AddField(&schema.SchemaField{
+		Name: "location",
+		Type: schema.FieldTypeGeoPoint,
+	})
+
+	r := resolvers.NewRecordFieldResolver(app.Dao(), collection, &models.RequestData{}, true)
+
+	scenarios := []struct {
+		fieldName    string
+		expectError  bool
+		expectedName string
+	}{
+		{"location", false, "[[demo1.location]]"},
+		{"location.lon", false, "JSON_EXTRACT([[demo1.location]], '$.lon')"},
+		{"location.lat", false, "JSON_EXTRACT([[demo1.location]], '$.lat')"},
+		{"location.alt", true, ""},
+		{"location.lon.a", true, ""},
+	}
+
+	for _, s := range scenarios {
+		name, _, err := r.Resolve(s.fieldName)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%q) Expected hasErr %v, got %v (%v)", s.fieldName, s.expectError, hasErr, err)
+			continue
+		}
+
+		if name != s.expectedName {
+			t.Errorf("(%q) Expected name %q, got %q", s.fieldName, s.expectedName, name)
+		}
+	}
+
+}
+
+func TestRecordFieldResolverDocumentFields(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection.Schema.AddField(&schema.SchemaField{
+		Name: "content",
+		Type: schema.FieldTypeDocument,
+	})
+
+	r := resolvers.NewRecordFieldResolver(app.Dao(), collection, &models.RequestData{}, true)
+
+	scenarios := []struct {
+		fieldName    string
+		expectError  bool
+		expectedName string
+	}{
+		{"content", false, "[[demo1.content]]"},
+		{"content.html", false, "JSON_EXTRACT([[demo1.content]], '$.html')"},
+		{"content.text", false, "JSON_EXTRACT([[demo1.content]], '$.text')"},
+		{"content.title", true, ""},
+		{"content.text.a", true, ""},
+	}
+
+	for _, s := range scenarios {
+		name, _, err := r.Resolve(s.fieldName)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%q) Expected hasErr %v, got %v (%v)", s.fieldName, s.expectError, hasErr, err)
+			continue
+		}
+
+		if name != s.expectedName {
+			t.Errorf("(%q) Expected name %q, got %q", s.fieldName, s.expectedName, name)
+		}
+	}
+}
+
+func TestRecordFieldResolverGeoPointBoundingBox(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection.Schema.AddField(&schema.SchemaField{
+		Name: "location",
+		Type: schema.FieldTypeGeoPoint,
+	})
+	if err := app.Dao().SaveCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	locations := map[string]types.GeoPoint{
+		"llvuca81nly1qls": {Lon: 23.32, Lat: 42.69},
+		"achvryl401bhse3": {Lon: 2.35, Lat: 48.85},
+		"0yxhwia2amd8gec": {Lon: -74, Lat: 40.71},
+	}
+	for id, location := range locations {
+		record, err := app.Dao().FindRecordById(collection.Id, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		record.Set("location", location)
+		if err := app.Dao().SaveRecord(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := resolvers.NewRecordFieldResolver(app.Dao(), collection, &models.RequestData{}, true)
+
+	expr, err := search.FilterData("location.lon >= 0 && location.lon <= 30 && location.lat >= 40 && location.lat <= 45").BuildExpr(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := app.Dao().RecordQuery(collection).AndWhere(expr)
+	r.UpdateQuery(query)
+
+	rows := []dbx.NullStringMap{}
+	if err := query.All(&rows); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != 1 || rows[0]["id"].String != "llvuca81nly1qls" {
+		t.Fatalf("Expected only record llvuca81nly1qls, got %v", rows)
+	}
+}
+
+func TestRecordFieldResolverRandomSource(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo4")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authRecord, err := app.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requestData := &models.RequestData{
+		Data:       map[string]any{"a": 123},
+		AuthRecord: authRecord,
+	}
+
+	filter := search.FilterData(`title = "test" && created < @now && @request.data.a = 123 && @request.auth.rel.title != {:title}`)
+
+	buildQuery := func(seed int64) (string, string) {
+		app.Dao().SetRandomSource(security.NewSeededRandomSource(seed))
+		defer app.Dao().SetRandomSource(nil)
+
+		r := resolvers.NewRecordFieldResolver(app.Dao(), collection, requestData, true)
+
+		expr, err := filter.BuildExprWithParams(r, dbx.Params{"title": "abc"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		query := app.Dao().RecordQuery(collection).AndWhere(expr)
+		if err := r.UpdateQuery(query); err != nil {
+			t.Fatal(err)
+		}
+
+		built := query.Build()
+
+		// compare only the param names since the @now value is time dependent
+		paramNames := make([]string, 0, len(built.Params()))
+		for name := range built.Params() {
+			paramNames = append(paramNames, name)
+		}
+		sort.Strings(paramNames)
+
+		return built.SQL(), strings.Join(paramNames, ",")
+	}
+
+	sql1, params1 := buildQuery(1)
+	sql2, params2 := buildQuery(1)
+	sql3, _ := buildQuery(2)
+
+	if sql1 != sql2 {
+		t.Fatalf("Expected the same seeded source to generate the same query, got\n%s\n%s", sql1, sql2)
+	}
+
+	if params1 != params2 {
+		t.Fatalf("Expected the same seeded source to generate the same params, got\n%s\n%s", params1, params2)
+	}
+
+	if sql1 == sql3 {
+		t.Fatalf("Expected different seeded sources to generate different queries, got\n%s", sql1)
+	}
+}
+
+func TestRecordFieldResolverHistory(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	// history is not enabled
+	demo1, err := app.Dao().FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	demo1Resolver := resolvers.NewRecordFieldResolver(app.Dao(), demo1, &models.RequestData{}, true)
+	for _, filter := range []string{"updatedBy = 'a'", "changedBy(text) = 'a'"} {
+		if _, err := search.FilterData(filter).BuildExpr(demo1Resolver); err == nil {
+			t.Fatalf("Expected %q to fail for collection without history", filter)
+		}
+	}
+
+	collection := tests.ToggleCollectionOption(t, app.Dao(), "demo2", "history", true)
+
+	record := models.NewRecord(collection)
+	record.Set("title", "history")
+	record.SetHistoryAuth(models.RecordHistoryAuthRecord, "user1")
+	if err := app.Dao().SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	record.Set("active", true)
+	record.SetHistoryAuth(models.RecordHistoryAuthRecord, "user2")
+	if err := app.Dao().SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	record.Set("title", "history_new")
+	record.SetHistoryAuth(models.RecordHistoryAuthRecord, "user3")
+	if err := app.Dao().SaveRecord(record); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		filter      string
+		expectError bool
+		expectIds   []string
+	}{
+		{"changedBy(missing) = 'user1'", true, nil},
+		{"changedBy('title') = 'user1'", true, nil},
+		{"changedSince(title) = true", true, nil},
+		{"createdBy = 'user1'", false, []string{record.Id}},
+		{"createdBy = 'user3'", false, []string{}},
+		{"updatedBy = 'user3'", false, []string{record.Id}},
+		{"changedBy(active) = 'user2'", false, []string{record.Id}},
+		{"changedBy(title) = 'user2'", false, []string{}},
+		{"changedBy(title) = 'user3'", false, []string{record.Id}},
+		{"changedSince(title, '2000-01-01') = true", false, []string{record.Id}},
+		{"changedSince(active, @now + '1d') = true", false, []string{}},
+	}
+
+	for _, s := range scenarios {
+		r := resolvers.NewRecordFieldResolver(app.Dao(), collection, &models.RequestData{}, true)
+
+		expr, err := search.FilterData(s.filter).BuildExpr(r)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%q) Expected hasErr %v, got %v (%v)", s.filter, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		query := app.Dao().RecordQuery(collection).AndWhere(expr)
+		r.UpdateQuery(query)
+
+		rows := []dbx.NullStringMap{}
+		if err := query.All(&rows); err != nil {
+			t.Errorf("(%q) Failed to execute the query: %v", s.filter, err)
+			continue
+		}
+
+		ids := make([]string, 0, len(rows))
+		for _, row := range rows {
+			ids = append(ids, row["id"].String)
+		}
+
+		if len(ids) != len(s.expectIds) || (len(ids) > 0 && ids[0] != s.expectIds[0]) {
+			t.Errorf("(%q) Expected ids %v, got %v", s.filter, s.expectIds, ids)
+		}
+	}
+}
+
+func TestRecordFieldResolverAuthHasRole(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	editor := &models.Role{Name: "editor"}
+	if err := app.Dao().SaveRole(editor); err != nil {
+		t.Fatal(err)
+	}
+
+	user1, err := app.Dao().FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Dao().AssignAuthRecordRole(user1, editor); err != nil {
+		t.Fatal(err)
+	}
+
+	user2, err := app.Dao().FindRecordById("users", "oap640cot4yru2s")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name        string
+		authRecord  *models.Record
+		filter      string
+		expectError bool
+		expectTotal int
+	}{
+		{"guest", nil, "@request.auth.hasRole('editor') = true", false, 0},
+		{"user without the role", user2, "@request.auth.hasRole('editor') = true", false, 0},
+		{"user with the role", user1, "@request.auth.hasRole('editor') = true", false, 3},
+		{"user with the role (negated)", user1, "@request.auth.hasRole('editor') = false", false, 0},
+		{"user with a missing role", user1, "@request.auth.hasRole('missing') = true", false, 0},
+		{"role from a record field", user1, "@request.auth.hasRole(title) = true", false, 0},
+		{"role from the request data", user1, "@request.auth.hasRole(@request.data.role) = true && active = true", false, 2},
+		{"invalid arg", user1, "@request.auth.hasRole(missing) = true", true, 0},
+	}
+
+	for _, s := range scenarios {
+		requestData := &models.RequestData{
+			AuthRecord: s.authRecord,
+			Data:       map[string]any{"role": "editor"},
+		}
+
+		r := resolvers.NewRecordFieldResolver(app.Dao(), collection, requestData, true)
+
+		expr, err := search.FilterData(s.filter).BuildExpr(r)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		query := app.Dao().RecordQuery(collection).AndWhere(expr)
+		r.UpdateQuery(query)
+
+		rows := []dbx.NullStringMap{}
+		if err := query.All(&rows); err != nil {
+			t.Errorf("[%s] Failed to execute the query: %v", s.name, err)
+			continue
+		}
+
+		if len(rows) != s.expectTotal {
+			t.Errorf("[%s] Expected %d records, got %d", s.name, s.expectTotal, len(rows))
+		}
+	}
+}
+
+func TestRecordFieldResolverApiKey(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.Dao().FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	readKey := &models.ApiKey{Name: "reader", Scopes: types.JsonMap{collection.Id: "read"}}
+	readKey.Id = "apikey_read"
+
+	otherKey := &models.ApiKey{Name: "other", Scopes: types.JsonMap{"wsmn24bux7wo113": "read"}}
+	otherKey.Id = "apikey_other"
+
+	scenarios := []struct {
+		name        string
+		apiKey      *models.ApiKey
+		filter      string
+		expectError bool
+		expectTotal int
+	}{
+		{"guest", nil, `@request.apiKey.scope = "read"`, false, 0},
+		{"guest (null check)", nil, `@request.apiKey.id = ""`, false, 3},
+		{"api key with the scope", readKey, `@request.apiKey.scope = "read"`, false, 3},
+		{"api key with different scope", readKey, `@request.apiKey.scope = "write"`, false, 0},
+		{"api key scoped for another collection", otherKey, `@request.apiKey.scope = "read"`, false, 0},
+		{"api key id and name", readKey, `@request.apiKey.id = "apikey_read" && @request.apiKey.name = "reader" && active = true`, false, 2},
+		{"missing api key field", readKey, `@request.apiKey.missing = ""`, false, 3},
+		{"nested api key field", readKey, `@request.apiKey.scope.test = ""`, true, 0},
+	}
+
+	for _, s := range scenarios {
+		requestData := &models.RequestData{ApiKey: s.apiKey}
+
+		r := resolvers.NewRecordFieldResolver(app.Dao(), collection, requestData, true)
+
+		expr, err := search.FilterData(s.filter).BuildExpr(r)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		query := app.Dao().RecordQuery(collection).AndWhere(expr)
+		r.UpdateQuery(query)
+
+		rows := []dbx.NullStringMap{}
+		if err := query.All(&rows); err != nil {
+			t.Errorf("[%s] Failed to execute the query: %v", s.name, err)
+			continue
+		}
+
+		if len(rows) != s.expectTotal {
+			t.Errorf("[%s] Expected %d records, got %d", s.name, s.expectTotal, len(rows))
+		}
+	}
+}
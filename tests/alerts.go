@@ -0,0 +1,26 @@
+package tests
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/alert"
+	"github.com/pocketbase/pocketbase/tools/hook"
+)
+
+// EnableAlerts enables the app alerts with a single test recipient.
+func EnableAlerts(app *TestApp) {
+	app.Settings().Alerts.Enabled = true
+	app.Settings().Alerts.Emails = []string{"test@example.com"}
+}
+
+// CaptureAlerts registers a hook that collects the sent alerts
+// (without sending them to the default notifier).
+func CaptureAlerts(app *TestApp) chan *alert.Alert {
+	sent := make(chan *alert.Alert, 10)
+
+	app.OnBeforeAlertSend().Add(func(e *core.AlertEvent) error {
+		sent <- e.Alert
+		return hook.StopPropagation
+	})
+
+	return sent
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tools/mailer"
+	"github.com/pocketbase/pocketbase/tools/sms"
 )
 
 // TestApp is a wrapper app instance used for testing.
@@ -29,6 +30,8 @@ type TestApp struct {
 	EventCalls map[string]int
 
 	TestMailer *TestMailer
+
+	TestSmsSender *TestSmsSender
 }
 
 // Cleanup resets the test application state and removes the test
@@ -53,6 +56,15 @@ func (t *TestApp) NewMailClient() mailer.Mailer {
 	return t.TestMailer
 }
 
+func (t *TestApp) NewSmsClient() sms.SMSSender {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	t.TestSmsSender.Reset()
+
+	return t.TestSmsSender
+}
+
 // ResetEventCalls resets the EventCalls counter.
 func (t *TestApp) ResetEventCalls() {
 	t.mux.Lock()
@@ -109,9 +121,10 @@ func NewTestApp(optTestDataDir ...string) (*TestApp, error) {
 	app.Settings().Logs.MaxDays = 0
 
 	t := &TestApp{
-		BaseApp:    app,
-		EventCalls: make(map[string]int),
-		TestMailer: &TestMailer{},
+		BaseApp:       app,
+		EventCalls:    make(map[string]int),
+		TestMailer:    &TestMailer{},
+		TestSmsSender: &TestSmsSender{},
 	}
 
 	t.OnBeforeApiError().Add(func(e *core.ApiErrorEvent) error {
@@ -122,6 +135,14 @@ func NewTestApp(optTestDataDir ...string) (*TestApp, error) {
 		return t.registerEventCall("OnAfterApiError")
 	})
 
+	t.OnBeforeErrorReport().Add(func(e *core.ErrorReportEvent) error {
+		return t.registerEventCall("OnBeforeErrorReport")
+	})
+
+	t.OnBeforeAlertSend().Add(func(e *core.AlertEvent) error {
+		return t.registerEventCall("OnBeforeAlertSend")
+	})
+
 	t.OnModelBeforeCreate().Add(func(e *core.ModelEvent) error {
 		return t.registerEventCall("OnModelBeforeCreate")
 	})
@@ -178,6 +199,10 @@ func NewTestApp(optTestDataDir ...string) (*TestApp, error) {
 		return t.registerEventCall("OnRecordAfterDeleteRequest")
 	})
 
+	t.OnRecordContentModeration().Add(func(e *core.RecordContentModerationEvent) error {
+		return t.registerEventCall("OnRecordContentModeration")
+	})
+
 	t.OnRecordAuthRequest().Add(func(e *core.RecordAuthEvent) error {
 		return t.registerEventCall("OnRecordAuthRequest")
 	})
@@ -274,6 +299,26 @@ func NewTestApp(optTestDataDir ...string) (*TestApp, error) {
 		return t.registerEventCall("OnMailerAfterRecordChangeEmailSend")
 	})
 
+	t.OnMailerBeforeRecordLoginLinkSend().Add(func(e *core.MailerRecordEvent) error {
+		return t.registerEventCall("OnMailerBeforeRecordLoginLinkSend")
+	})
+
+	t.OnMailerAfterRecordLoginLinkSend().Add(func(e *core.MailerRecordEvent) error {
+		return t.registerEventCall("OnMailerAfterRecordLoginLinkSend")
+	})
+
+	t.OnSmsBeforeRecordOtpSend().Add(func(e *core.SmsRecordEvent) error {
+		return t.registerEventCall("OnSmsBeforeRecordOtpSend")
+	})
+
+	t.OnSmsAfterRecordOtpSend().Add(func(e *core.SmsRecordEvent) error {
+		return t.registerEventCall("OnSmsAfterRecordOtpSend")
+	})
+
+	t.OnLoginLockout().Add(func(e *core.LoginLockoutEvent) error {
+		return t.registerEventCall("OnLoginLockout")
+	})
+
 	t.OnRealtimeConnectRequest().Add(func(e *core.RealtimeConnectEvent) error {
 		return t.registerEventCall("OnRealtimeConnectRequest")
 	})
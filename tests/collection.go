@@ -0,0 +1,28 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// ToggleCollectionOption sets the `enabled` state of the specified
+// collection option group (eg. "softDelete", "history", "encryption")
+// and persists the collection with the provided dao.
+func ToggleCollectionOption(t testing.TB, dao *daos.Dao, collectionNameOrId string, option string, enable bool) *models.Collection {
+	t.Helper()
+
+	collection, err := dao.FindCollectionByNameOrId(collectionNameOrId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection.Options[option] = map[string]any{"enabled": enable}
+
+	if err := dao.SaveCollection(collection); err != nil {
+		t.Fatalf("Failed to toggle the %q collection %s option: %v", collectionNameOrId, option, err)
+	}
+
+	return collection
+}
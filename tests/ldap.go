@@ -0,0 +1,258 @@
+package tests
+
+import (
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/pocketbase/pocketbase/tools/ldap"
+)
+
+// TestLdapEntry defines a single [TestLdapServer] directory entry.
+type TestLdapEntry struct {
+	Dn         string
+	Password   string
+	Attributes map[string][]string
+}
+
+// TestLdapServer is a minimal in-memory LDAP server
+// that supports only the simple bind and search operations.
+type TestLdapServer struct {
+	// Url is the "ldap://" url of the server.
+	Url string
+
+	mux        sync.Mutex
+	entries    []*TestLdapEntry
+	totalBinds int
+	listener   net.Listener
+}
+
+// NewTestLdapServer starts a new [TestLdapServer] with the provided
+// entries on a random local port.
+//
+// After this call, you should also call [TestLdapServer.Close()].
+func NewTestLdapServer(entries ...*TestLdapEntry) (*TestLdapServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	server := &TestLdapServer{
+		Url:      "ldap://" + listener.Addr().String(),
+		entries:  entries,
+		listener: listener,
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return // closed
+			}
+			go server.handle(conn)
+		}
+	}()
+
+	return server, nil
+}
+
+// Close stops the server.
+func (s *TestLdapServer) Close() error {
+	return s.listener.Close()
+}
+
+// TotalBinds returns the number of the received bind requests.
+func (s *TestLdapServer) TotalBinds() int {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return s.totalBinds
+}
+
+func (s *TestLdapServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		message, err := ldap.ReadPacket(conn)
+		if err != nil || len(message.Children) < 2 {
+			return
+		}
+
+		messageId, _ := message.Children[0].Int()
+		op := message.Children[1]
+
+		switch {
+		case op.Is(ldap.ClassApplication, ldap.ApplicationBindRequest):
+			code := s.bind(op)
+			s.reply(conn, messageId, ldap.ApplicationBindResponse, code)
+		case op.Is(ldap.ClassApplication, ldap.ApplicationSearchRequest):
+			entries, code := s.search(op)
+			for _, entry := range entries {
+				conn.Write(testLdapMessage(messageId, testLdapEntryPacket(entry)).Bytes())
+			}
+			s.reply(conn, messageId, ldap.ApplicationSearchResultDone, code)
+		default:
+			return // unbind or unsupported
+		}
+	}
+}
+
+func (s *TestLdapServer) reply(conn net.Conn, messageId int64, tag int, code int) {
+	result := ldap.NewConstructed(
+		ldap.ClassApplication,
+		tag,
+		ldap.NewInteger(ldap.ClassUniversal, ldap.TagEnumerated, int64(code)),
+		ldap.NewString(ldap.ClassUniversal, ldap.TagOctetString, ""),
+		ldap.NewString(ldap.ClassUniversal, ldap.TagOctetString, ""),
+	)
+
+	conn.Write(testLdapMessage(messageId, result).Bytes())
+}
+
+func (s *TestLdapServer) bind(op *ldap.Packet) int {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.totalBinds++
+
+	if len(op.Children) < 3 {
+		return ldap.ResultUnwillingToPerform
+	}
+
+	dn := op.Children[1].String()
+	password := op.Children[2].String()
+
+	// anonymous bind
+	if dn == "" && password == "" {
+		return ldap.ResultSuccess
+	}
+
+	for _, entry := range s.entries {
+		if strings.EqualFold(entry.Dn, dn) && entry.Password != "" && entry.Password == password {
+			return ldap.ResultSuccess
+		}
+	}
+
+	return ldap.ResultInvalidCredentials
+}
+
+func (s *TestLdapServer) search(op *ldap.Packet) ([]*TestLdapEntry, int) {
+	if len(op.Children) < 7 {
+		return nil, ldap.ResultUnwillingToPerform
+	}
+
+	baseDn := strings.ToLower(op.Children[0].String())
+	sizeLimit, _ := op.Children[3].Int()
+	filter := op.Children[6]
+
+	result := []*TestLdapEntry{}
+
+	for _, entry := range s.entries {
+		dn := strings.ToLower(entry.Dn)
+		if baseDn != "" && dn != baseDn && !strings.HasSuffix(dn, ","+baseDn) {
+			continue
+		}
+
+		if !testLdapMatch(entry, filter) {
+			continue
+		}
+
+		if sizeLimit > 0 && len(result) >= int(sizeLimit) {
+			return result, ldap.ResultSizeLimitExceeded
+		}
+
+		result = append(result, entry)
+	}
+
+	return result, ldap.ResultSuccess
+}
+
+// testLdapMatch checks whether the entry matches the filter packet
+// (only the and, or, not, equality, present and substrings filters are supported).
+func testLdapMatch(entry *TestLdapEntry, filter *ldap.Packet) bool {
+	values := func(name string) []string {
+		for k, v := range entry.Attributes {
+			if strings.EqualFold(k, name) {
+				return v
+			}
+		}
+		return nil
+	}
+
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !testLdapMatch(entry, child) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, child := range filter.Children {
+			if testLdapMatch(entry, child) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterNot:
+		return len(filter.Children) == 1 && !testLdapMatch(entry, filter.Children[0])
+	case ldap.FilterPresent:
+		return len(values(filter.String())) > 0
+	case ldap.FilterEqualityMatch:
+		for _, v := range values(filter.Children[0].String()) {
+			if strings.EqualFold(v, filter.Children[1].String()) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterSubstrings:
+		for _, v := range values(filter.Children[0].String()) {
+			v = strings.ToLower(v)
+			matched := true
+			for _, part := range filter.Children[1].Children {
+				sub := strings.ToLower(part.String())
+				switch part.Tag {
+				case ldap.SubstringInitial:
+					matched = matched && strings.HasPrefix(v, sub)
+				case ldap.SubstringFinal:
+					matched = matched && strings.HasSuffix(v, sub)
+				default:
+					matched = matched && strings.Contains(v, sub)
+				}
+			}
+			if matched {
+				return true
+			}
+		}
+		return false
+	}
+
+	return false
+}
+
+func testLdapMessage(messageId int64, op *ldap.Packet) *ldap.Packet {
+	return ldap.NewSequence(ldap.NewInteger(ldap.ClassUniversal, ldap.TagInteger, messageId), op)
+}
+
+func testLdapEntryPacket(entry *TestLdapEntry) *ldap.Packet {
+	attributes := ldap.NewSequence()
+
+	for name, values := range entry.Attributes {
+		set := ldap.NewConstructed(ldap.ClassUniversal, ldap.TagSet)
+		for _, v := range values {
+			set.Children = append(set.Children, ldap.NewString(ldap.ClassUniversal, ldap.TagOctetString, v))
+		}
+
+		attributes.Children = append(attributes.Children, ldap.NewSequence(
+			ldap.NewString(ldap.ClassUniversal, ldap.TagOctetString, name),
+			set,
+		))
+	}
+
+	return ldap.NewConstructed(
+		ldap.ClassApplication,
+		ldap.ApplicationSearchResultEntry,
+		ldap.NewString(ldap.ClassUniversal, ldap.TagOctetString, entry.Dn),
+		attributes,
+	)
+}
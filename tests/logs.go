@@ -10,6 +10,7 @@ func MockRequestLogsData(app *TestApp) error {
 			[[method]],
 			[[status]],
 			[[auth]],
+			[[authId]],
 			[[userIp]],
 			[[remoteIp]],
 			[[referer]],
@@ -25,6 +26,7 @@ func MockRequestLogsData(app *TestApp) error {
 			"get",
 			200,
 			"guest",
+			"",
 			"127.0.0.1",
 			"127.0.0.1",
 			"",
@@ -39,6 +41,7 @@ func MockRequestLogsData(app *TestApp) error {
 			"post",
 			400,
 			"admin",
+			"sywbhecnh46rhm0",
 			"127.0.0.1",
 			"127.0.0.1",
 			"",
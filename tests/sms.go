@@ -0,0 +1,27 @@
+package tests
+
+import (
+	"github.com/pocketbase/pocketbase/tools/sms"
+)
+
+var _ sms.SMSSender = (*TestSmsSender)(nil)
+
+// TestSmsSender is a mock `sms.SMSSender` implementation.
+type TestSmsSender struct {
+	TotalSend   int
+	LastMessage sms.Message
+}
+
+// Reset clears any previously test collected data.
+func (m *TestSmsSender) Reset() {
+	m.TotalSend = 0
+	m.LastMessage = sms.Message{}
+}
+
+// Send implements `sms.SMSSender` interface.
+func (c *TestSmsSender) Send(m *sms.Message) error {
+	c.TotalSend++
+	c.LastMessage = *m
+
+	return nil
+}
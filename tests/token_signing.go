@@ -0,0 +1,17 @@
+package tests
+
+import (
+	"testing"
+	"time"
+)
+
+// EnableTokenSigning enables the app asymmetric token signing
+// and generates its first signing key.
+func EnableTokenSigning(t testing.TB, app *TestApp) {
+	t.Helper()
+
+	app.Settings().TokenSigning.Enabled = true
+	if err := app.Settings().TokenSigning.Rotate(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+}
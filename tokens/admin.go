@@ -8,11 +8,14 @@ import (
 )
 
 // NewAdminAuthToken generates and returns a new admin authentication token.
+//
+// The token is asymmetrically signed if the settings token signing is enabled.
 func NewAdminAuthToken(app core.App, admin *models.Admin) (string, error) {
-	return security.NewToken(
+	return newAuthToken(
+		app,
 		jwt.MapClaims{"id": admin.Id, "type": TypeAdmin},
-		(admin.TokenKey + app.Settings().AdminAuthToken.Secret),
-		app.Settings().AdminAuthToken.Duration,
+		admin.TokenKey,
+		app.Settings().AdminAuthToken,
 	)
 }
 
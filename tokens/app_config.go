@@ -0,0 +1,84 @@
+package tokens
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+const (
+	// TypeAppConfig is the "type" claim of the app-config signature tokens.
+	TypeAppConfig = "appConfig"
+
+	// AppConfigDigestClaim is the app-config token claim that stores
+	// the hex encoded SHA-256 digest of the signed app-config payload.
+	AppConfigDigestClaim = "sha256"
+
+	// AppConfigTokenDuration is the app-config tokens duration in seconds.
+	AppConfigTokenDuration = 86400 // 1 day
+)
+
+// NewAppConfigToken generates and returns a new app-config signature
+// token for the provided payload, signed with the active token signing key.
+//
+// The token could be verified by the client applications with the
+// published JWKS and its digest claim compared with the received payload.
+func NewAppConfigToken(app core.App, payload []byte) (string, error) {
+	signing := app.Settings().TokenSigning
+	if !signing.Enabled {
+		return "", errors.New("The asymmetric token signing is disabled.")
+	}
+
+	key := signing.ActiveKey()
+	if key == nil {
+		return "", errors.New("Missing active token signing key.")
+	}
+
+	signer, err := key.Signer()
+	if err != nil {
+		return "", err
+	}
+
+	return security.NewAsymmetricToken(
+		jwt.MapClaims{
+			"type":               TypeAppConfig,
+			"iss":                strings.TrimSuffix(app.Settings().Meta.AppUrl, "/"),
+			AppConfigDigestClaim: appConfigDigest(payload),
+		},
+		signer,
+		key.Id,
+		AppConfigTokenDuration,
+	)
+}
+
+// VerifyAppConfigToken checks whether the provided app-config
+// token is valid and matches the app-config payload.
+func VerifyAppConfigToken(app core.App, token string, payload []byte) error {
+	claims, err := parseAsymmetricToken(app, token)
+	if err != nil {
+		return err
+	}
+
+	if claims["type"] != TypeAppConfig {
+		return errors.New("Missing or invalid token claims.")
+	}
+
+	digest, _ := claims[AppConfigDigestClaim].(string)
+	if subtle.ConstantTimeCompare([]byte(digest), []byte(appConfigDigest(payload))) != 1 {
+		return errors.New("The token doesn't match the app-config payload.")
+	}
+
+	return nil
+}
+
+func appConfigDigest(payload []byte) string {
+	hash := sha256.Sum256(payload)
+
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,55 @@
+package tokens_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tokens"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+func TestAppConfigToken(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	payload := []byte(`{"flags":{"test":true}}`)
+
+	// disabled token signing
+	if _, err := tokens.NewAppConfigToken(app, payload); err == nil {
+		t.Fatal("Expected error with disabled token signing")
+	}
+
+	tests.EnableTokenSigning(t, app)
+
+	token, err := tokens.NewAppConfigToken(app, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, _ := security.ParseUnverifiedJWT(token)
+	if claims["type"] != tokens.TypeAppConfig {
+		t.Fatalf("Expected type claim %q, got %v", tokens.TypeAppConfig, claims["type"])
+	}
+
+	if err := tokens.VerifyAppConfigToken(app, token, payload); err != nil {
+		t.Fatalf("Expected the token to be valid, got %v", err)
+	}
+
+	// modified payload
+	if err := tokens.VerifyAppConfigToken(app, token, []byte(`{"flags":{"test":false}}`)); err == nil {
+		t.Fatal("Expected the token to not match the modified payload")
+	}
+
+	// auth tokens shouldn't be accepted as app-config tokens
+	admin, err := app.Dao().FindAdminByEmail("test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	adminToken, err := tokens.NewAdminAuthToken(app, admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tokens.VerifyAppConfigToken(app, adminToken, payload); err == nil {
+		t.Fatal("Expected the admin auth token to be rejected")
+	}
+}
@@ -0,0 +1,242 @@
+package tokens
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/models/settings"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+// KeyHashClaim is the asymmetric auth tokens claim that stores the hash
+// of the auth model token key and the token secret.
+//
+// It allows invalidating the already issued asymmetric tokens
+// the same way as the HMAC ones (eg. on password change).
+const KeyHashClaim = "keyHash"
+
+// newAuthToken generates and returns a new auth token signed either with
+// the HMAC tokenKey+secret or, if enabled, with the active token signing key.
+func newAuthToken(app core.App, payload jwt.MapClaims, tokenKey string, config settings.TokenConfig) (string, error) {
+	claimsConfig := app.Settings().AuthTokenClaims
+	if claimsConfig.Issuer != "" {
+		payload["iss"] = claimsConfig.Issuer
+	}
+	switch len(claimsConfig.Audience) {
+	case 0:
+		// no audience
+	case 1:
+		payload["aud"] = claimsConfig.Audience[0]
+	default:
+		payload["aud"] = claimsConfig.Audience
+	}
+
+	signing := app.Settings().TokenSigning
+
+	if !signing.Enabled {
+		return security.NewToken(payload, (tokenKey + config.Secret), config.Duration)
+	}
+
+	key := signing.ActiveKey()
+	if key == nil {
+		return "", errors.New("Missing active token signing key.")
+	}
+
+	signer, err := key.Signer()
+	if err != nil {
+		return "", err
+	}
+
+	if _, ok := payload["iss"]; !ok {
+		payload["iss"] = strings.TrimSuffix(app.Settings().Meta.AppUrl, "/")
+	}
+	payload[KeyHashClaim] = authKeyHash(tokenKey, config.Secret)
+
+	return security.NewAsymmetricToken(payload, signer, key.Id, config.Duration)
+}
+
+// FindAdminByAuthToken finds the admin associated with
+// the provided HMAC or asymmetric signed admin auth token.
+func FindAdminByAuthToken(app core.App, token string) (*models.Admin, error) {
+	if !isAsymmetricToken(token) {
+		admin, err := app.Dao().FindAdminByToken(token, app.Settings().AdminAuthToken.Secret)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := checkScopeClaims(app, unverifiedClaims(token)); err != nil {
+			return nil, err
+		}
+
+		return admin, nil
+	}
+
+	claims, err := parseAsymmetricToken(app, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkScopeClaims(app, claims); err != nil {
+		return nil, err
+	}
+
+	id, _ := claims["id"].(string)
+	if id == "" || claims["type"] != TypeAdmin {
+		return nil, errors.New("Missing or invalid token claims.")
+	}
+
+	admin, err := app.Dao().FindAdminById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !checkAuthKeyHash(claims, admin.TokenKey, app.Settings().AdminAuthToken.Secret) {
+		return nil, errors.New("The token is no longer valid.")
+	}
+
+	return admin, nil
+}
+
+// FindAuthRecordByAuthToken finds the auth record associated with
+// the provided HMAC or asymmetric signed record auth token.
+func FindAuthRecordByAuthToken(app core.App, token string) (*models.Record, error) {
+	if !isAsymmetricToken(token) {
+		record, err := app.Dao().FindAuthRecordByToken(token, app.Settings().RecordAuthToken.Secret)
+		if err != nil {
+			return nil, err
+		}
+
+		claims := unverifiedClaims(token)
+
+		if err := checkScopeClaims(app, claims); err != nil {
+			return nil, err
+		}
+
+		if err := checkSessionClaims(app, record, claims); err != nil {
+			return nil, err
+		}
+
+		return record, nil
+	}
+
+	claims, err := parseAsymmetricToken(app, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkScopeClaims(app, claims); err != nil {
+		return nil, err
+	}
+
+	id, _ := claims["id"].(string)
+	collectionId, _ := claims["collectionId"].(string)
+	if id == "" || collectionId == "" || claims["type"] != TypeAuthRecord {
+		return nil, errors.New("Missing or invalid token claims.")
+	}
+
+	record, err := app.Dao().FindRecordById(collectionId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !record.Collection().IsAuth() {
+		return nil, errors.New("The token is not associated to an auth collection record.")
+	}
+
+	if !checkAuthKeyHash(claims, record.TokenKey(), app.Settings().RecordAuthToken.Secret) {
+		return nil, errors.New("The token is no longer valid.")
+	}
+
+	if err := checkSessionClaims(app, record, claims); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// isAsymmetricToken checks whether the provided token is RS256 or EdDSA signed.
+//
+// All other tokens are verified with the HMAC token secrets for
+// backward compatibility with the tokens issued before enabling
+// the asymmetric token signing.
+func isAsymmetricToken(token string) bool {
+	parsed, _, err := new(jwt.Parser).ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		return false
+	}
+
+	return parsed.Method == jwt.SigningMethodRS256 || parsed.Method == jwt.SigningMethodEdDSA
+}
+
+// parseAsymmetricToken verifies the provided asymmetric token with the
+// published (active or retired) token signing keys.
+func parseAsymmetricToken(app core.App, token string) (jwt.MapClaims, error) {
+	signing := app.Settings().TokenSigning
+	if !signing.Enabled {
+		return nil, errors.New("The asymmetric token signing is disabled.")
+	}
+
+	return security.ParseAsymmetricJWT(token, func(kid string) (crypto.PublicKey, error) {
+		key := signing.FindKey(kid)
+		if key == nil {
+			return nil, errors.New("Unknown token signing key.")
+		}
+
+		signer, err := key.Signer()
+		if err != nil {
+			return nil, err
+		}
+
+		return signer.Public(), nil
+	})
+}
+
+// checkScopeClaims checks the "iss" and "aud" token claims
+// against the configured settings auth token claims.
+func checkScopeClaims(app core.App, claims jwt.MapClaims) error {
+	config := app.Settings().AuthTokenClaims
+
+	if config.Issuer != "" && !claims.VerifyIssuer(config.Issuer, true) {
+		return errors.New("Invalid or missing token issuer.")
+	}
+
+	if len(config.Audience) > 0 {
+		for _, aud := range config.Audience {
+			if claims.VerifyAudience(aud, true) {
+				return nil
+			}
+		}
+
+		return errors.New("Invalid or missing token audience.")
+	}
+
+	return nil
+}
+
+// unverifiedClaims returns the claims of an already verified token.
+func unverifiedClaims(token string) jwt.MapClaims {
+	claims := jwt.MapClaims{}
+
+	new(jwt.Parser).ParseUnverified(token, claims)
+
+	return claims
+}
+
+func authKeyHash(tokenKey string, secret string) string {
+	hash := sha256.Sum256([]byte(tokenKey + secret))
+
+	return hex.EncodeToString(hash[:])
+}
+
+func checkAuthKeyHash(claims jwt.MapClaims, tokenKey string, secret string) bool {
+	hash, _ := claims[KeyHashClaim].(string)
+
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(authKeyHash(tokenKey, secret))) == 1
+}
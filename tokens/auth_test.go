@@ -0,0 +1,195 @@
+package tokens_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pocketbase/pocketbase/models/settings"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tokens"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+func TestAsymmetricAdminAuthToken(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	tests.EnableTokenSigning(t, app)
+
+	admin, err := app.Dao().FindAdminByEmail("test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := tokens.NewAdminAuthToken(app, admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, _ := security.ParseUnverifiedJWT(token)
+	if claims["iss"] != app.Settings().Meta.AppUrl {
+		t.Fatalf("Expected iss claim %q, got %v", app.Settings().Meta.AppUrl, claims["iss"])
+	}
+
+	tokenAdmin, err := tokens.FindAdminByAuthToken(app, token)
+	if err != nil || tokenAdmin.Id != admin.Id {
+		t.Fatalf("Expected admin %v, got %v (%v)", admin, tokenAdmin, err)
+	}
+
+	// the token should be rejected by the record auth lookup
+	if record, err := tokens.FindAuthRecordByAuthToken(app, token); err == nil {
+		t.Fatalf("Expected the admin token to not match an auth record, got %v", record)
+	}
+
+	// remains valid after key rotation
+	if err := app.Settings().TokenSigning.Rotate(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tokens.FindAdminByAuthToken(app, token); err != nil {
+		t.Fatalf("Expected the token to be valid after rotation, got %v", err)
+	}
+
+	// invalidated on token key change
+	admin.RefreshTokenKey()
+	if err := app.Dao().SaveAdmin(admin); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tokens.FindAdminByAuthToken(app, token); err == nil {
+		t.Fatal("Expected the token to be invalidated after token key change")
+	}
+}
+
+func TestAsymmetricRecordAuthToken(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	// HMAC token issued before enabling the token signing
+	user, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hmacToken, _ := tokens.NewRecordAuthToken(app, user)
+
+	tests.EnableTokenSigning(t, app)
+
+	token, err := tokens.NewRecordAuthToken(app, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tk := range []string{hmacToken, token} {
+		tokenRecord, err := tokens.FindAuthRecordByAuthToken(app, tk)
+		if err != nil || tokenRecord.Id != user.Id {
+			t.Fatalf("Expected auth record %v, got %v (%v)", user, tokenRecord, err)
+		}
+	}
+
+	// rotating with zero retention removes the key that signed the token
+	if err := app.Settings().TokenSigning.Rotate(0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tokens.FindAuthRecordByAuthToken(app, token); err == nil {
+		t.Fatal("Expected the token to be invalid after its signing key removal")
+	}
+
+	// rejected when the token signing is disabled
+	token, _ = tokens.NewRecordAuthToken(app, user)
+	app.Settings().TokenSigning.Enabled = false
+	if _, err := tokens.FindAuthRecordByAuthToken(app, token); err == nil {
+		t.Fatal("Expected the token to be rejected when the token signing is disabled")
+	}
+}
+
+func TestAsymmetricEdDSARecordAuthToken(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	tests.EnableTokenSigning(t, app)
+
+	user, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsaToken, _ := tokens.NewRecordAuthToken(app, user)
+
+	// switch the signing algorithm
+	app.Settings().TokenSigning.Algorithm = settings.TokenSigningAlgorithmEdDSA
+	if err := app.Settings().RotateTokenSigningKeys(); err != nil {
+		t.Fatal(err)
+	}
+
+	edToken, err := tokens.NewRecordAuthToken(app, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, _, err := new(jwt.Parser).ParseUnverified(edToken, jwt.MapClaims{})
+	if err != nil || parsed.Method != jwt.SigningMethodEdDSA {
+		t.Fatalf("Expected EdDSA signed token, got %v (%v)", parsed, err)
+	}
+
+	// both the old RS256 and the new EdDSA tokens should be valid
+	for _, tk := range []string{rsaToken, edToken} {
+		tokenRecord, err := tokens.FindAuthRecordByAuthToken(app, tk)
+		if err != nil || tokenRecord.Id != user.Id {
+			t.Fatalf("Expected auth record %v, got %v (%v)", user, tokenRecord, err)
+		}
+	}
+}
+
+func TestAuthTokenScopeClaims(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	admin, err := app.Dao().FindAdminByEmail("test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unscopedToken, _ := tokens.NewAdminAuthToken(app, admin)
+
+	app.Settings().AuthTokenClaims.Issuer = "test_issuer"
+	app.Settings().AuthTokenClaims.Audience = []string{"service1", "service2"}
+
+	hmacToken, _ := tokens.NewAdminAuthToken(app, admin)
+
+	tests.EnableTokenSigning(t, app)
+
+	asymmetricToken, _ := tokens.NewAdminAuthToken(app, admin)
+
+	claims, _ := security.ParseUnverifiedJWT(asymmetricToken)
+	if claims["iss"] != "test_issuer" {
+		t.Fatalf("Expected iss claim %q, got %v", "test_issuer", claims["iss"])
+	}
+
+	scenarios := []struct {
+		name        string
+		token       string
+		issuer      string
+		audience    []string
+		expectError bool
+	}{
+		{"unscoped token with configured claims", unscopedToken, "test_issuer", []string{"service1"}, true},
+		{"unscoped token without configured claims", unscopedToken, "", nil, false},
+		{"hmac token with matching claims", hmacToken, "test_issuer", []string{"service2", "service3"}, false},
+		{"hmac token with different issuer", hmacToken, "other_issuer", nil, true},
+		{"hmac token with different audience", hmacToken, "", []string{"service3"}, true},
+		{"asymmetric token with matching claims", asymmetricToken, "test_issuer", []string{"service1"}, false},
+		{"asymmetric token with different issuer", asymmetricToken, "other_issuer", nil, true},
+		{"asymmetric token with different audience", asymmetricToken, "", []string{"service3"}, true},
+	}
+
+	for _, s := range scenarios {
+		app.Settings().AuthTokenClaims.Issuer = s.issuer
+		app.Settings().AuthTokenClaims.Audience = s.audience
+
+		_, err := tokens.FindAdminByAuthToken(app, s.token)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+		}
+	}
+}
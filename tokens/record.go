@@ -10,19 +10,22 @@ import (
 )
 
 // NewRecordAuthToken generates and returns a new auth record authentication token.
+//
+// The token is asymmetrically signed if the settings token signing is enabled.
 func NewRecordAuthToken(app core.App, record *models.Record) (string, error) {
 	if !record.Collection().IsAuth() {
 		return "", errors.New("The record is not from an auth collection.")
 	}
 
-	return security.NewToken(
+	return newAuthToken(
+		app,
 		jwt.MapClaims{
 			"id":           record.Id,
 			"type":         TypeAuthRecord,
 			"collectionId": record.Collection().Id,
 		},
-		(record.TokenKey() + app.Settings().RecordAuthToken.Secret),
-		app.Settings().RecordAuthToken.Duration,
+		record.TokenKey(),
+		app.Settings().RecordAuthToken,
 	)
 }
 
@@ -76,3 +79,23 @@ func NewRecordChangeEmailToken(app core.App, record *models.Record, newEmail str
 		app.Settings().RecordEmailChangeToken.Duration,
 	)
 }
+
+// NewRecordLoginLinkToken generates and returns a new auth record
+// one-time passwordless login token for the provided login link.
+func NewRecordLoginLinkToken(app core.App, record *models.Record, link *models.LoginLink) (string, error) {
+	if !record.Collection().IsAuth() {
+		return "", errors.New("The record is not from an auth collection.")
+	}
+
+	return security.NewToken(
+		jwt.MapClaims{
+			"id":           record.Id,
+			"type":         TypeAuthRecord,
+			"collectionId": record.Collection().Id,
+			"email":        record.Email(),
+			"linkId":       link.Id,
+		},
+		(record.TokenKey() + app.Settings().RecordLoginLinkToken.Secret),
+		app.Settings().RecordLoginLinkToken.Duration,
+	)
+}
@@ -3,8 +3,10 @@ package tokens_test
 import (
 	"testing"
 
+	"github.com/pocketbase/pocketbase/models"
 	"github.com/pocketbase/pocketbase/tests"
 	"github.com/pocketbase/pocketbase/tokens"
+	"github.com/pocketbase/pocketbase/tools/security"
 )
 
 func TestNewRecordAuthToken(t *testing.T) {
@@ -98,3 +100,34 @@ func TestNewRecordChangeEmailToken(t *testing.T) {
 		t.Fatalf("Expected auth record %v, got %v", user, tokenRecord)
 	}
 }
+
+func TestNewRecordLoginLinkToken(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	link := &models.LoginLink{CollectionId: user.Collection().Id, RecordId: user.Id}
+	link.RefreshId()
+
+	token, err := tokens.NewRecordLoginLinkToken(app, user, link)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenRecord, _ := app.Dao().FindAuthRecordByToken(
+		token,
+		app.Settings().RecordLoginLinkToken.Secret,
+	)
+	if tokenRecord == nil || tokenRecord.Id != user.Id {
+		t.Fatalf("Expected auth record %v, got %v", user, tokenRecord)
+	}
+
+	claims, _ := security.ParseUnverifiedJWT(token)
+	if claims["linkId"] != link.Id {
+		t.Fatalf("Expected linkId claim %q, got %v", link.Id, claims["linkId"])
+	}
+}
@@ -0,0 +1,70 @@
+package tokens
+
+import (
+	"errors"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// Session bound auth record tokens claims.
+const (
+	SessionIdClaim       = "sessionId"
+	SessionRotationClaim = "sessionRotation"
+)
+
+// NewRecordSessionAuthToken generates and returns a new short-lived
+// auth record (access) token bound to the provided session.
+//
+// The token duration is the auth collection sessions access token
+// duration and it is invalidated with the session revocation or with
+// the next rotation of the session refresh token.
+func NewRecordSessionAuthToken(app core.App, record *models.Record, session *models.AuthSession) (string, error) {
+	if !record.Collection().IsAuth() {
+		return "", errors.New("The record is not from an auth collection.")
+	}
+
+	if !session.HasAuthRecord(record) {
+		return "", errors.New("The session is not associated to the auth record.")
+	}
+
+	config := app.Settings().RecordAuthToken
+	config.Duration = record.Collection().AuthOptions().Sessions.AccessTokenDuration
+
+	return newAuthToken(
+		app,
+		jwt.MapClaims{
+			"id":                 record.Id,
+			"type":               TypeAuthRecord,
+			"collectionId":       record.Collection().Id,
+			SessionIdClaim:       session.Id,
+			SessionRotationClaim: session.Rotation,
+		},
+		record.TokenKey(),
+		config,
+	)
+}
+
+// checkSessionClaims checks whether the session of a session
+// bound auth record token is still active (the regular tokens
+// without session claims are always valid).
+func checkSessionClaims(app core.App, record *models.Record, claims jwt.MapClaims) error {
+	sessionId, _ := claims[SessionIdClaim].(string)
+	if sessionId == "" {
+		return nil // not a session token
+	}
+
+	session, err := app.Dao().FindAuthSessionById(sessionId)
+	if err != nil {
+		return errors.New("The token session is missing or was revoked.")
+	}
+
+	rotation, _ := claims[SessionRotationClaim].(float64)
+
+	if session.IsExpired() || !session.HasAuthRecord(record) || int(rotation) != session.Rotation {
+		return errors.New("The token session is no longer valid.")
+	}
+
+	return nil
+}
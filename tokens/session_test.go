@@ -0,0 +1,91 @@
+package tokens_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/models"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tokens"
+)
+
+func TestNewRecordSessionAuthToken(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.Dao().FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := user.Collection().AuthOptions()
+	options.Sessions = models.CollectionSessionsOptions{
+		Enabled:              true,
+		AccessTokenDuration:  100,
+		RefreshTokenDuration: 1000,
+	}
+	user.Collection().SetOptions(options)
+
+	// session of another record
+	otherSession, err := app.Dao().FindAuthSessionById("authsess0000002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tokens.NewRecordSessionAuthToken(app, user, otherSession); err == nil {
+		t.Fatal("Expected error for session of another record")
+	}
+
+	// expired session
+	expiredSession, err := app.Dao().FindAuthSessionById("authsess0000003")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiredToken, err := tokens.NewRecordSessionAuthToken(app, user, expiredSession)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tokens.FindAuthRecordByAuthToken(app, expiredToken); err == nil {
+		t.Fatal("Expected the expired session token to be invalid")
+	}
+
+	session, err := app.Dao().FindAuthSessionById("authsess0000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := tokens.NewRecordSessionAuthToken(app, user, session)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenRecord, err := tokens.FindAuthRecordByAuthToken(app, token)
+	if err != nil || tokenRecord.Id != user.Id {
+		t.Fatalf("Expected auth record %v, got %v (%v)", user, tokenRecord, err)
+	}
+
+	// invalidated on refresh token rotation
+	session.RefreshToken()
+	if err := app.Dao().SaveAuthSession(session); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tokens.FindAuthRecordByAuthToken(app, token); err == nil {
+		t.Fatal("Expected the token to be invalidated after the session rotation")
+	}
+
+	// new asymmetric token of the rotated session
+	tests.EnableTokenSigning(t, app)
+	token, err = tokens.NewRecordSessionAuthToken(app, user, session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tokens.FindAuthRecordByAuthToken(app, token); err != nil {
+		t.Fatalf("Expected the asymmetric session token to be valid, got %v", err)
+	}
+
+	// invalidated on session revocation
+	if err := app.Dao().DeleteAuthSession(session); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tokens.FindAuthRecordByAuthToken(app, token); err == nil {
+		t.Fatal("Expected the token to be invalidated after the session revocation")
+	}
+}
@@ -0,0 +1,64 @@
+// Package alert implements a pluggable operator alerts notifier
+// interface together with email, webhook and Slack compatible clients.
+package alert
+
+import (
+	"errors"
+	"time"
+)
+
+// Alert defines a single operator alert.
+type Alert struct {
+	// Type is the alert type identifier (eg. "lowDiskSpace").
+	Type string `json:"type"`
+
+	Subject   string         `json:"subject"`
+	Message   string         `json:"message"`
+	Timestamp time.Time      `json:"timestamp"`
+	Data      map[string]any `json:"data"`
+}
+
+// New creates a new Alert with the provided type, subject and message.
+func New(alertType string, subject string, message string) *Alert {
+	return &Alert{
+		Type:      alertType,
+		Subject:   subject,
+		Message:   message,
+		Timestamp: time.Now().UTC(),
+		Data:      map[string]any{},
+	}
+}
+
+// Notifier defines a base alerts notifier interface.
+type Notifier interface {
+	// Notify sends the provided alert to the notifier channel.
+	Notify(alert *Alert) error
+}
+
+var _ Notifier = (MultiNotifier)(nil)
+
+// MultiNotifier is a [Notifier] that sends the alerts to all of its notifiers.
+type MultiNotifier []Notifier
+
+// Notify implements [Notifier.Notify] interface method.
+//
+// The alert is sent to all notifiers, even if some of them fail,
+// and the returned error (if any) joins the messages of the failed ones.
+func (m MultiNotifier) Notify(alert *Alert) error {
+	var errMsg string
+
+	for _, n := range m {
+		if err := n.Notify(alert); err != nil {
+			if errMsg != "" {
+				errMsg += "; "
+			}
+			errMsg += err.Error()
+		}
+	}
+
+	if errMsg != "" {
+		return errors.New(errMsg)
+	}
+
+	return nil
+}
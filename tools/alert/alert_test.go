@@ -0,0 +1,83 @@
+package alert_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/alert"
+)
+
+func TestNew(t *testing.T) {
+	before := time.Now().UTC()
+
+	a := alert.New("test_type", "test_subject", "test_message")
+
+	if a.Type != "test_type" {
+		t.Fatalf("Expected type %q, got %q", "test_type", a.Type)
+	}
+
+	if a.Subject != "test_subject" {
+		t.Fatalf("Expected subject %q, got %q", "test_subject", a.Subject)
+	}
+
+	if a.Message != "test_message" {
+		t.Fatalf("Expected message %q, got %q", "test_message", a.Message)
+	}
+
+	if a.Timestamp.Before(before) {
+		t.Fatalf("Expected timestamp after %v, got %v", before, a.Timestamp)
+	}
+
+	if a.Data == nil {
+		t.Fatal("Expected non-nil data map")
+	}
+}
+
+func TestMultiNotifierNotify(t *testing.T) {
+	n1 := &testNotifier{}
+	n2 := &testNotifier{err: errors.New("test_error2")}
+	n3 := &testNotifier{}
+	n4 := &testNotifier{err: errors.New("test_error4")}
+
+	scenarios := []struct {
+		notifier    alert.MultiNotifier
+		expectError string
+	}{
+		{alert.MultiNotifier{}, ""},
+		{alert.MultiNotifier{n1, n3}, ""},
+		{alert.MultiNotifier{n1, n2, n3, n4}, "test_error2; test_error4"},
+	}
+
+	for i, s := range scenarios {
+		n1.total, n2.total, n3.total, n4.total = 0, 0, 0, 0
+
+		err := s.notifier.Notify(alert.New("test", "test", "test"))
+
+		errMsg := ""
+		if err != nil {
+			errMsg = err.Error()
+		}
+		if errMsg != s.expectError {
+			t.Errorf("(%d) Expected error %q, got %q", i, s.expectError, errMsg)
+		}
+
+		for j, n := range s.notifier {
+			if n.(*testNotifier).total != 1 {
+				t.Errorf("(%d) Expected notifier %d to be called once, got %d", i, j, n.(*testNotifier).total)
+			}
+		}
+	}
+}
+
+// -------------------------------------------------------------------
+
+type testNotifier struct {
+	total int
+	err   error
+}
+
+func (n *testNotifier) Notify(a *alert.Alert) error {
+	n.total++
+	return n.err
+}
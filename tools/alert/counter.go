@@ -0,0 +1,68 @@
+package alert
+
+import (
+	"sync"
+	"time"
+)
+
+// WindowCounter is a concurrent safe sliding time window hits counter
+// (eg. used to detect errors spikes).
+type WindowCounter struct {
+	mux    sync.Mutex
+	window time.Duration
+	hits   []time.Time
+}
+
+// NewWindowCounter creates a new WindowCounter with the specified window duration.
+func NewWindowCounter(window time.Duration) *WindowCounter {
+	return &WindowCounter{window: window}
+}
+
+// Window returns the counter window duration.
+func (c *WindowCounter) Window() time.Duration {
+	return c.window
+}
+
+// Hit registers a new hit and returns the total number
+// of hits within the counter window (including the new one).
+func (c *WindowCounter) Hit() int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	now := time.Now()
+
+	c.hits = append(c.active(now), now)
+
+	return len(c.hits)
+}
+
+// Total returns the number of hits within the counter window.
+func (c *WindowCounter) Total() int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.hits = c.active(time.Now())
+
+	return len(c.hits)
+}
+
+// Reset removes all registered hits.
+func (c *WindowCounter) Reset() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.hits = nil
+}
+
+// active returns the hits slice without the expired ones.
+func (c *WindowCounter) active(now time.Time) []time.Time {
+	minTime := now.Add(-c.window)
+
+	for i, t := range c.hits {
+		if t.After(minTime) {
+			return c.hits[i:]
+		}
+	}
+
+	return c.hits[:0]
+}
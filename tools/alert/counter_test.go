@@ -0,0 +1,42 @@
+package alert_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/alert"
+)
+
+func TestWindowCounter(t *testing.T) {
+	c := alert.NewWindowCounter(50 * time.Millisecond)
+
+	if c.Window() != 50*time.Millisecond {
+		t.Fatalf("Expected 50ms window, got %v", c.Window())
+	}
+
+	if total := c.Total(); total != 0 {
+		t.Fatalf("Expected 0 hits, got %d", total)
+	}
+
+	for i := 1; i <= 3; i++ {
+		if total := c.Hit(); total != i {
+			t.Fatalf("Expected %d hits, got %d", i, total)
+		}
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	if total := c.Total(); total != 0 {
+		t.Fatalf("Expected the hits to expire, got %d", total)
+	}
+
+	if total := c.Hit(); total != 1 {
+		t.Fatalf("Expected 1 hit, got %d", total)
+	}
+
+	c.Reset()
+
+	if total := c.Total(); total != 0 {
+		t.Fatalf("Expected 0 hits after reset, got %d", total)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !freebsd
+
+package alert
+
+import "errors"
+
+// FreeDiskSpace returns the available (to unprivileged users)
+// disk space in bytes of the filesystem containing path.
+//
+// Not supported on the current platform.
+func FreeDiskSpace(path string) (uint64, error) {
+	return 0, errors.New("The disk space check is not supported on the current platform.")
+}
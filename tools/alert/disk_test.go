@@ -0,0 +1,24 @@
+//go:build linux || darwin || freebsd
+
+package alert_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/alert"
+)
+
+func TestFreeDiskSpace(t *testing.T) {
+	if _, err := alert.FreeDiskSpace("/missing/test/dir"); err == nil {
+		t.Fatal("Expected error for missing path, got nil")
+	}
+
+	free, err := alert.FreeDiskSpace(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if free == 0 {
+		t.Fatal("Expected non-zero free disk space")
+	}
+}
@@ -0,0 +1,17 @@
+//go:build linux || darwin || freebsd
+
+package alert
+
+import "syscall"
+
+// FreeDiskSpace returns the available (to unprivileged users)
+// disk space in bytes of the filesystem containing path.
+func FreeDiskSpace(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
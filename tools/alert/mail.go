@@ -0,0 +1,42 @@
+package alert
+
+import (
+	"fmt"
+	"html"
+	"net/mail"
+
+	"github.com/pocketbase/pocketbase/tools/mailer"
+)
+
+var _ Notifier = (*MailClient)(nil)
+
+// MailClient defines a [Notifier] that sends the alerts as
+// separate email messages to each of the specified recipients.
+type MailClient struct {
+	Mailer mailer.Mailer
+	From   mail.Address
+	To     []string
+}
+
+// Notify implements [Notifier.Notify] interface method.
+func (c *MailClient) Notify(alert *Alert) error {
+	body := fmt.Sprintf(
+		"<p>%s</p>\n<p><small>%s</small></p>",
+		html.EscapeString(alert.Message),
+		alert.Timestamp.UTC().Format("2006-01-02 15:04:05 MST"),
+	)
+
+	for _, to := range c.To {
+		err := c.Mailer.Send(&mailer.Message{
+			From:    c.From,
+			To:      mail.Address{Address: to},
+			Subject: alert.Subject,
+			HTML:    body,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
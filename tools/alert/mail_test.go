@@ -0,0 +1,57 @@
+package alert_test
+
+import (
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/alert"
+	"github.com/pocketbase/pocketbase/tools/mailer"
+)
+
+func TestMailClientNotify(t *testing.T) {
+	testMailer := &testMailer{}
+
+	client := &alert.MailClient{
+		Mailer: testMailer,
+		From:   mail.Address{Name: "Test", Address: "from@example.com"},
+		To:     []string{"to1@example.com", "to2@example.com"},
+	}
+
+	if err := client.Notify(alert.New("test_type", "test_subject", "<test_message>")); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(testMailer.messages) != 2 {
+		t.Fatalf("Expected 2 sent messages, got %d", len(testMailer.messages))
+	}
+
+	for i, m := range testMailer.messages {
+		if m.From.Address != "from@example.com" {
+			t.Errorf("(%d) Expected from address %q, got %q", i, "from@example.com", m.From.Address)
+		}
+
+		if m.To.Address != client.To[i] {
+			t.Errorf("(%d) Expected to address %q, got %q", i, client.To[i], m.To.Address)
+		}
+
+		if m.Subject != "test_subject" {
+			t.Errorf("(%d) Expected subject %q, got %q", i, "test_subject", m.Subject)
+		}
+
+		if !strings.Contains(m.HTML, "&lt;test_message&gt;") {
+			t.Errorf("(%d) Expected escaped message in %q", i, m.HTML)
+		}
+	}
+}
+
+// -------------------------------------------------------------------
+
+type testMailer struct {
+	messages []*mailer.Message
+}
+
+func (m *testMailer) Send(message *mailer.Message) error {
+	m.messages = append(m.messages, message)
+	return nil
+}
@@ -0,0 +1,50 @@
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"text/template"
+)
+
+// templateFuncs is the list with the helper functions
+// available in the alert payload templates.
+var templateFuncs = template.FuncMap{
+	// json encodes the provided value as JSON
+	// (eg. {"text": {{json .Message}}}).
+	"json": func(value any) (string, error) {
+		raw, err := json.Marshal(value)
+		return string(raw), err
+	},
+}
+
+// ParseTemplate parses the provided alert payload template.
+//
+// The template is a Go [text/template] that has access to the alert
+// fields (.Type, .Subject, .Message, .Timestamp, .Data) and to a "json"
+// helper function for encoding values as JSON, eg.:
+//
+//	{"content": {{json .Subject}}, "embeds": [{"description": {{json .Message}}}]}
+func ParseTemplate(text string) (*template.Template, error) {
+	return template.New("payload").Funcs(templateFuncs).Option("missingkey=zero").Parse(text)
+}
+
+// RenderTemplate renders the provided alert payload template
+// and checks whether the result is a valid JSON.
+func RenderTemplate(text string, alert *Alert) ([]byte, error) {
+	tmpl, err := ParseTemplate(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, alert); err != nil {
+		return nil, err
+	}
+
+	if !json.Valid(buf.Bytes()) {
+		return nil, errors.New("The rendered alert payload template is not a valid JSON.")
+	}
+
+	return buf.Bytes(), nil
+}
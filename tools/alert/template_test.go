@@ -0,0 +1,58 @@
+package alert_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/alert"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	a := alert.New("test_type", "test_subject", `test "message"`)
+	a.Data["host"] = "example.com"
+
+	scenarios := []struct {
+		name        string
+		template    string
+		expected    string
+		expectError bool
+	}{
+		{
+			"invalid template syntax",
+			`{"text": {{json .Message}`,
+			"",
+			true,
+		},
+		{
+			"invalid rendered json",
+			`{"text": {{.Message}}}`,
+			"",
+			true,
+		},
+		{
+			"missing alert field",
+			`{"text": {{json .Missing}}}`,
+			"",
+			true,
+		},
+		{
+			"valid template",
+			`{"content": {{json .Subject}}, "text": {{json .Message}}, "host": {{json .Data.host}}, "missing": {{json .Data.missing}}}`,
+			`{"content": "test_subject", "text": "test \"message\"", "host": "example.com", "missing": null}`,
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		result, err := alert.RenderTemplate(s.template, a)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if string(result) != s.expected {
+			t.Errorf("[%s] Expected \n%s, got \n%s", s.name, s.expected, result)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/signature"
+)
+
+var _ Notifier = (*WebhookClient)(nil)
+
+// WebhookClient defines a [Notifier] that sends the alerts
+// as JSON POST request to a custom webhook url.
+type WebhookClient struct {
+	Url string
+
+	// Secret is an optional HMAC secret used to sign the alerts payload
+	// (the signature is sent with the [signature.Header] header).
+	Secret string
+
+	// Template is an optional payload template (see [ParseTemplate])
+	// that could be used to shape the alert JSON for receivers that
+	// require a specific format (eg. Discord or Microsoft Teams).
+	//
+	// Fallbacks to the plain JSON serialized [Alert] if empty.
+	Template string
+
+	// HttpClient is an optional custom http client
+	// (fallbacks to http.Client with 10s timeout).
+	HttpClient *http.Client
+}
+
+// Notify implements [Notifier.Notify] interface method.
+func (c *WebhookClient) Notify(alert *Alert) error {
+	if c.Template == "" {
+		return postJson(c.HttpClient, c.Url, c.Secret, alert)
+	}
+
+	body, err := RenderTemplate(c.Template, alert)
+	if err != nil {
+		return err
+	}
+
+	return postBody(c.HttpClient, c.Url, c.Secret, body)
+}
+
+// -------------------------------------------------------------------
+
+var _ Notifier = (*SlackClient)(nil)
+
+// SlackClient defines a [Notifier] that sends the alerts to a Slack
+// compatible incoming webhook url (Slack, Mattermost, Rocket.Chat, etc.).
+type SlackClient struct {
+	Url string
+
+	// HttpClient is an optional custom http client
+	// (fallbacks to http.Client with 10s timeout).
+	HttpClient *http.Client
+}
+
+// Notify implements [Notifier.Notify] interface method.
+func (c *SlackClient) Notify(alert *Alert) error {
+	payload := map[string]any{
+		"text": fmt.Sprintf("*%s*\n%s", alert.Subject, alert.Message),
+	}
+
+	return postJson(c.HttpClient, c.Url, "", payload)
+}
+
+// -------------------------------------------------------------------
+
+func postJson(httpClient *http.Client, url string, secret string, data any) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return postBody(httpClient, url, secret, body)
+}
+
+func postBody(httpClient *http.Client, url string, secret string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set(signature.Header, signature.Sign(secret, body, time.Now()))
+	}
+
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		resBody, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("Failed to send the alert (%d): %s", res.StatusCode, resBody)
+	}
+
+	return nil
+}
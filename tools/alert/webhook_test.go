@@ -0,0 +1,152 @@
+package alert_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/alert"
+	"github.com/pocketbase/pocketbase/tools/signature"
+)
+
+func TestWebhookClientNotify(t *testing.T) {
+	var body map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		json.Unmarshal(raw, &body)
+
+		if r.URL.Path == "/error" {
+			w.WriteHeader(400)
+			return
+		}
+	}))
+	defer server.Close()
+
+	a := alert.New("test_type", "test_subject", "test_message")
+	a.Data["test"] = 123
+
+	// error response
+	if err := (&alert.WebhookClient{Url: server.URL + "/error"}).Notify(a); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	// success response
+	body = nil
+	if err := (&alert.WebhookClient{Url: server.URL + "/hook"}).Notify(a); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]any{
+		"type":    "test_type",
+		"subject": "test_subject",
+		"message": "test_message",
+	}
+	for k, v := range expected {
+		if body[k] != v {
+			t.Errorf("Expected %s %v, got %v", k, v, body[k])
+		}
+	}
+	if data, _ := body["data"].(map[string]any); data["test"] != float64(123) {
+		t.Errorf("Expected data %v, got %v", a.Data, body["data"])
+	}
+}
+
+func TestWebhookClientNotifyTemplate(t *testing.T) {
+	var body map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		json.Unmarshal(raw, &body)
+	}))
+	defer server.Close()
+
+	a := alert.New("test_type", "test_subject", "test_message")
+
+	// invalid template
+	client := &alert.WebhookClient{Url: server.URL, Template: `{"content": {{.Message}}}`}
+	if err := client.Notify(a); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if body != nil {
+		t.Fatalf("Expected the alert to not be sent, got %v", body)
+	}
+
+	// valid template
+	client.Template = `{"content": {{json .Subject}}, "embeds": [{"description": {{json .Message}}}]}`
+	if err := client.Notify(a); err != nil {
+		t.Fatal(err)
+	}
+
+	if body["content"] != "test_subject" {
+		t.Fatalf("Expected content %q, got %v", "test_subject", body["content"])
+	}
+	if len(body) != 2 {
+		t.Fatalf("Expected only the template payload fields, got %v", body)
+	}
+}
+
+func TestWebhookClientNotifySigned(t *testing.T) {
+	var verifyErr error
+	var rawSignature string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		rawSignature = r.Header.Get(signature.Header)
+		verifyErr = signature.Verify("test_secret", raw, rawSignature, time.Minute)
+	}))
+	defer server.Close()
+
+	a := alert.New("test_type", "test_subject", "test_message")
+
+	// without secret
+	if err := (&alert.WebhookClient{Url: server.URL}).Notify(a); err != nil {
+		t.Fatal(err)
+	}
+	if rawSignature != "" {
+		t.Fatalf("Expected no signature header, got %q", rawSignature)
+	}
+
+	// with secret
+	if err := (&alert.WebhookClient{Url: server.URL, Secret: "test_secret"}).Notify(a); err != nil {
+		t.Fatal(err)
+	}
+	if verifyErr != nil {
+		t.Fatalf("Expected valid signature, got %q (%v)", rawSignature, verifyErr)
+	}
+}
+
+func TestSlackClientNotify(t *testing.T) {
+	var body map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		json.Unmarshal(raw, &body)
+
+		if r.URL.Path == "/error" {
+			w.WriteHeader(500)
+			return
+		}
+	}))
+	defer server.Close()
+
+	a := alert.New("test_type", "test_subject", "test_message")
+
+	// error response
+	if err := (&alert.SlackClient{Url: server.URL + "/error"}).Notify(a); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	// success response
+	body = nil
+	if err := (&alert.SlackClient{Url: server.URL + "/hook"}).Notify(a); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(body) != 1 || body["text"] != "*test_subject*\ntest_message" {
+		t.Fatalf("Expected only the slack text payload, got %v", body)
+	}
+}
@@ -0,0 +1,295 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"golang.org/x/oauth2"
+)
+
+var _ Provider = (*Apple)(nil)
+
+// NameApple is the unique name of the Apple provider.
+const NameApple string = "apple"
+
+// AppleIssuer is the "iss" claim of the Apple signed tokens
+// (and the "aud" claim of the generated client secrets).
+const AppleIssuer string = "https://appleid.apple.com"
+
+// appleKeysCacheDuration is the duration for which
+// the fetched Apple public keys are reused.
+const appleKeysCacheDuration = 1 * time.Hour
+
+// appleKeysMinRefetchInterval is the min interval between two
+// Apple public keys fetches caused by an unknown token kid
+// (aka. the keys are not refetched for every forged token).
+const appleKeysMinRefetchInterval = 1 * time.Minute
+
+type appleKeysCacheItem struct {
+	keys    map[string]*rsa.PublicKey
+	fetched time.Time
+}
+
+var appleKeysCache = struct {
+	sync.Mutex
+	items map[string]appleKeysCacheItem
+}{items: map[string]appleKeysCacheItem{}}
+
+// Apple allows authentication via Sign in with Apple.
+//
+// Apple doesn't have a userinfo endpoint and the user claims are read
+// from the returned id_token, verified with the Apple public keys
+// (the provider UserApiUrl).
+type Apple struct {
+	*baseProvider
+
+	relayedUser map[string]any
+}
+
+// NewAppleProvider creates new Apple provider instance with some defaults.
+func NewAppleProvider() *Apple {
+	return &Apple{baseProvider: &baseProvider{
+		scopes:     []string{"name", "email"},
+		authUrl:    "https://appleid.apple.com/auth/authorize",
+		tokenUrl:   "https://appleid.apple.com/auth/token",
+		userApiUrl: "https://appleid.apple.com/auth/keys",
+	}}
+}
+
+// SetRelayedUser sets the user data relayed by the client from the
+// Apple authorization response (aka. the `user` form_post param).
+//
+// Apple sends the user name only with the first authorization of the app.
+func (p *Apple) SetRelayedUser(user map[string]any) {
+	p.relayedUser = user
+}
+
+// BuildAuthUrl implements Provider.BuildAuthUrl interface.
+//
+// Apple requires the form_post response mode when the name or email scopes are requested.
+func (p *Apple) BuildAuthUrl(state string, opts ...oauth2.AuthCodeOption) string {
+	opts = append(opts, oauth2.SetAuthURLParam("response_mode", "form_post"))
+
+	return p.baseProvider.BuildAuthUrl(state, opts...)
+}
+
+// FetchRawUserData implements Provider.FetchRawUserData interface.
+//
+// It returns the verified id_token claims and the relayed user data (if any) as "user".
+func (p *Apple) FetchRawUserData(token *oauth2.Token) ([]byte, error) {
+	idToken, _ := token.Extra("id_token").(string)
+	if idToken == "" {
+		return nil, errors.New("Missing Apple id_token.")
+	}
+
+	claims, err := p.VerifyToken(idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(p.relayedUser) > 0 {
+		claims["user"] = p.relayedUser
+	}
+
+	return json.Marshal(claims)
+}
+
+// FetchAuthUser returns an AuthUser instance based on the Apple id_token claims.
+//
+// The relayed user email is ignored since only the id_token claims are signed.
+//
+// API reference: https://developer.apple.com/documentation/sign_in_with_apple/sign_in_with_apple_rest_api/authenticating_users_with_sign_in_with_apple
+func (p *Apple) FetchAuthUser(token *oauth2.Token) (*AuthUser, error) {
+	data, err := p.FetchRawUserData(token)
+	if err != nil {
+		return nil, err
+	}
+
+	rawUser := map[string]any{}
+	if err := json.Unmarshal(data, &rawUser); err != nil {
+		return nil, err
+	}
+
+	extracted := struct {
+		Id            string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified any    `json:"email_verified"` // could be string or bool
+		User          struct {
+			Name struct {
+				FirstName string `json:"firstName"`
+				LastName  string `json:"lastName"`
+			} `json:"name"`
+		} `json:"user"`
+	}{}
+	if err := json.Unmarshal(data, &extracted); err != nil {
+		return nil, err
+	}
+
+	user := &AuthUser{
+		Id:          extracted.Id,
+		Name:        strings.TrimSpace(extracted.User.Name.FirstName + " " + extracted.User.Name.LastName),
+		RawUser:     rawUser,
+		AccessToken: token.AccessToken,
+	}
+
+	if v := fmt.Sprint(extracted.EmailVerified); v == "true" {
+		user.Email = extracted.Email
+	}
+
+	return user, nil
+}
+
+// VerifyToken verifies the signature of the provided Apple signed
+// token (eg. id_token or server-to-server notification payload) and
+// returns its claims.
+//
+// The token must be issued by Apple for the provider client id.
+func (p *Apple) VerifyToken(token string) (jwt.MapClaims, error) {
+	claims, err := security.ParseAsymmetricJWT(token, p.publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if !claims.VerifyIssuer(AppleIssuer, true) {
+		return nil, errors.New("Invalid Apple token issuer.")
+	}
+
+	if p.clientId == "" || !claims.VerifyAudience(p.clientId, true) {
+		return nil, errors.New("Invalid Apple token audience.")
+	}
+
+	return claims, nil
+}
+
+// publicKey returns the Apple public key with the specified kid.
+//
+// The public keys are cached for 1 hour and refetched earlier only
+// for an unknown kid (at most once per minute).
+func (p *Apple) publicKey(kid string) (crypto.PublicKey, error) {
+	appleKeysCache.Lock()
+	defer appleKeysCache.Unlock()
+
+	item, ok := appleKeysCache.items[p.userApiUrl]
+	if ok && time.Since(item.fetched) < appleKeysCacheDuration {
+		if key, exists := item.keys[kid]; exists {
+			return key, nil
+		}
+
+		if time.Since(item.fetched) < appleKeysMinRefetchInterval {
+			return nil, fmt.Errorf("Missing Apple public key %q.", kid)
+		}
+	}
+
+	keys, err := fetchApplePublicKeys(p.userApiUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	appleKeysCache.items[p.userApiUrl] = appleKeysCacheItem{
+		keys:    keys,
+		fetched: time.Now(),
+	}
+
+	if key, exists := keys[kid]; exists {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("Missing Apple public key %q.", kid)
+}
+
+// fetchApplePublicKeys fetches the Apple JWKS from the
+// specified url and returns its RSA keys grouped by their kid.
+func fetchApplePublicKeys(url string) (map[string]*rsa.PublicKey, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	response, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode >= 400 {
+		return nil, fmt.Errorf("Failed to fetch the Apple public keys (%d):\n%s", response.StatusCode, string(body))
+	}
+
+	jwks := struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}{}
+	if err := json.Unmarshal(body, &jwks); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+
+	for _, key := range jwks.Keys {
+		if key.Kty != "RSA" {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil {
+			return nil, err
+		}
+
+		result[key.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	return result, nil
+}
+
+// NewAppleClientSecret generates a new ES256 signed client secret JWT
+// from the Apple Developer account team id, the Sign in with Apple
+// private key (and its key id) and the Services ID (aka. client id).
+//
+// Apple doesn't accept client secrets valid for more than 6 months.
+//
+// API reference: https://developer.apple.com/documentation/accountorganizationaldatasharing/creating-a-client-secret
+func NewAppleClientSecret(clientId, teamId, keyId, privateKey string, duration time.Duration) (string, error) {
+	key, err := jwt.ParseECPrivateKeyFromPEM([]byte(privateKey))
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": teamId,
+		"iat": now.Unix(),
+		"exp": now.Add(duration).Unix(),
+		"aud": AppleIssuer,
+		"sub": clientId,
+	})
+	token.Header["kid"] = keyId
+
+	return token.SignedString(key)
+}
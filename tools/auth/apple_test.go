@@ -0,0 +1,288 @@
+package auth_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pocketbase/pocketbase/tools/auth"
+	"golang.org/x/oauth2"
+)
+
+// newAppleKeysServer starts a test server that serves the public part
+// of the returned RSA key in the Apple JWKS format (with kid "test").
+//
+// The returned counter holds the total number of the served requests.
+func newAppleKeysServer(t *testing.T) (*httptest.Server, *rsa.PrivateKey, *int) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+
+	var totalRequests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		totalRequests++
+		w.Write([]byte(`{"keys":[{"kty":"RSA","kid":"test","use":"sig","alg":"RS256","n":"` + n + `","e":"` + e + `"}]}`))
+	}))
+
+	return server, key, &totalRequests
+}
+
+func signAppleToken(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed
+}
+
+func TestAppleBuildAuthUrl(t *testing.T) {
+	p := auth.NewAppleProvider()
+
+	url := p.BuildAuthUrl("test")
+
+	if !strings.Contains(url, "response_mode=form_post") {
+		t.Fatalf("Expected form_post response mode, got %s", url)
+	}
+}
+
+func TestAppleVerifyToken(t *testing.T) {
+	server, key, _ := newAppleKeysServer(t)
+	defer server.Close()
+
+	exp := time.Now().Add(time.Hour).Unix()
+
+	scenarios := []struct {
+		name        string
+		token       string
+		expectError bool
+	}{
+		{
+			"invalid kid",
+			signAppleToken(t, key, "missing", jwt.MapClaims{"iss": auth.AppleIssuer, "aud": "test_client", "exp": exp}),
+			true,
+		},
+		{
+			"invalid issuer",
+			signAppleToken(t, key, "test", jwt.MapClaims{"iss": "https://example.com", "aud": "test_client", "exp": exp}),
+			true,
+		},
+		{
+			"invalid audience",
+			signAppleToken(t, key, "test", jwt.MapClaims{"iss": auth.AppleIssuer, "aud": "other_client", "exp": exp}),
+			true,
+		},
+		{
+			"expired",
+			signAppleToken(t, key, "test", jwt.MapClaims{"iss": auth.AppleIssuer, "aud": "test_client", "exp": time.Now().Add(-time.Hour).Unix()}),
+			true,
+		},
+		{
+			"valid",
+			signAppleToken(t, key, "test", jwt.MapClaims{"iss": auth.AppleIssuer, "aud": "test_client", "exp": exp, "sub": "123"}),
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		p := auth.NewAppleProvider()
+		p.SetClientId("test_client")
+		p.SetUserApiUrl(server.URL)
+
+		claims, err := p.VerifyToken(s.token)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if !hasErr && claims["sub"] != "123" {
+			t.Errorf("[%s] Expected sub claim 123, got %v", s.name, claims["sub"])
+		}
+	}
+}
+
+func TestAppleVerifyTokenKeysCache(t *testing.T) {
+	server, key, totalRequests := newAppleKeysServer(t)
+	defer server.Close()
+
+	exp := time.Now().Add(time.Hour).Unix()
+
+	valid := signAppleToken(t, key, "test", jwt.MapClaims{"iss": auth.AppleIssuer, "aud": "test_client", "exp": exp, "sub": "123"})
+
+	p := auth.NewAppleProvider()
+	p.SetClientId("test_client")
+	p.SetUserApiUrl(server.URL)
+
+	for i := 0; i < 3; i++ {
+		if _, err := p.VerifyToken(valid); err != nil {
+			t.Fatalf("(%d) Expected nil error, got %v", i, err)
+		}
+	}
+
+	// unknown kids shouldn't trigger a new fetch right after the last one
+	for i := 0; i < 3; i++ {
+		unknown := signAppleToken(t, key, fmt.Sprintf("unknown%d", i), jwt.MapClaims{"iss": auth.AppleIssuer, "aud": "test_client", "exp": exp})
+		if _, err := p.VerifyToken(unknown); err == nil {
+			t.Fatalf("(%d) Expected error for unknown kid, got nil", i)
+		}
+	}
+
+	if *totalRequests != 1 {
+		t.Fatalf("Expected 1 public keys request, got %d", *totalRequests)
+	}
+}
+
+func TestAppleFetchAuthUser(t *testing.T) {
+	server, key, _ := newAppleKeysServer(t)
+	defer server.Close()
+
+	exp := time.Now().Add(time.Hour).Unix()
+
+	scenarios := []struct {
+		name          string
+		idToken       string
+		relayedUser   map[string]any
+		expectError   bool
+		expectedName  string
+		expectedEmail string
+	}{
+		{
+			"missing id_token",
+			"",
+			nil,
+			true,
+			"",
+			"",
+		},
+		{
+			"unverified email without relayed user",
+			signAppleToken(t, key, "test", jwt.MapClaims{
+				"iss":            auth.AppleIssuer,
+				"aud":            "test_client",
+				"exp":            exp,
+				"sub":            "123",
+				"email":          "test@example.com",
+				"email_verified": "false",
+			}),
+			nil,
+			false,
+			"",
+			"",
+		},
+		{
+			"verified email with relayed user",
+			signAppleToken(t, key, "test", jwt.MapClaims{
+				"iss":            auth.AppleIssuer,
+				"aud":            "test_client",
+				"exp":            exp,
+				"sub":            "123",
+				"email":          "test@privaterelay.appleid.com",
+				"email_verified": true,
+			}),
+			map[string]any{
+				"name":  map[string]any{"firstName": "John", "lastName": "Doe"},
+				"email": "relayed@example.com",
+			},
+			false,
+			"John Doe",
+			"test@privaterelay.appleid.com",
+		},
+	}
+
+	for _, s := range scenarios {
+		p := auth.NewAppleProvider()
+		p.SetClientId("test_client")
+		p.SetUserApiUrl(server.URL)
+		p.SetRelayedUser(s.relayedUser)
+
+		token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"id_token": s.idToken})
+
+		user, err := p.FetchAuthUser(token)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		if user.Id != "123" {
+			t.Errorf("[%s] Expected id 123, got %q", s.name, user.Id)
+		}
+
+		if user.Name != s.expectedName {
+			t.Errorf("[%s] Expected name %q, got %q", s.name, s.expectedName, user.Name)
+		}
+
+		if user.Email != s.expectedEmail {
+			t.Errorf("[%s] Expected email %q, got %q", s.name, s.expectedEmail, user.Email)
+		}
+
+		if _, ok := user.RawUser["user"]; ok != (s.relayedUser != nil) {
+			t.Errorf("[%s] Expected the relayed user to be in the raw user data only if set, got %v", s.name, user.RawUser)
+		}
+	}
+}
+
+func TestNewAppleClientSecret(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+
+	if _, err := auth.NewAppleClientSecret("test_client", "test_team", "test_key", "invalid", time.Hour); err == nil {
+		t.Fatal("Expected invalid private key error, got nil")
+	}
+
+	secret, err := auth.NewAppleClientSecret("test_client", "test_team", "test_key", privateKey, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := jwt.MapClaims{}
+	parsed, err := jwt.ParseWithClaims(secret, claims, func(t *jwt.Token) (any, error) {
+		return &key.PublicKey, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed.Method.Alg() != "ES256" || parsed.Header["kid"] != "test_key" {
+		t.Fatalf("Unexpected client secret header %v", parsed.Header)
+	}
+
+	if claims["iss"] != "test_team" || claims["sub"] != "test_client" || claims["aud"] != auth.AppleIssuer {
+		t.Fatalf("Unexpected client secret claims %v", claims)
+	}
+}
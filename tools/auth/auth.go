@@ -109,6 +109,10 @@ func NewProviderByName(name string) (Provider, error) {
 		return NewStravaProvider(), nil
 	case NameGitee:
 		return NewGiteeProvider(), nil
+	case NameOIDC:
+		return NewOIDCProvider(), nil
+	case NameApple:
+		return NewAppleProvider(), nil
 	default:
 		return nil, errors.New("Missing provider " + name)
 	}
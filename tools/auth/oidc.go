@@ -0,0 +1,184 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+var _ Provider = (*OIDC)(nil)
+
+// NameOIDC is the unique name of the generic OpenID Connect provider.
+const NameOIDC string = "oidc"
+
+// oidcDiscoveryCacheDuration is the duration for which the fetched
+// OpenID Connect discovery documents are reused.
+const oidcDiscoveryCacheDuration = 1 * time.Hour
+
+// OIDCDiscovery defines the used fields of an OpenID Connect
+// provider configuration (aka. discovery) document.
+type OIDCDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+type oidcDiscoveryCacheItem struct {
+	discovery *OIDCDiscovery
+	expires   time.Time
+}
+
+var oidcDiscoveryCache = struct {
+	sync.Mutex
+	items map[string]oidcDiscoveryCacheItem
+}{items: map[string]oidcDiscoveryCacheItem{}}
+
+// OIDC allows authentication via any OpenID Connect compliant identity provider.
+//
+// The provider endpoints could be either set explicitly or
+// loaded from the issuer discovery document with [OIDC.Discover()].
+type OIDC struct {
+	*baseProvider
+}
+
+// NewOIDCProvider creates new OpenID Connect provider instance with some defaults.
+func NewOIDCProvider() *OIDC {
+	return &OIDC{&baseProvider{
+		scopes: []string{"openid", "email", "profile"},
+	}}
+}
+
+// Discover fetches the issuer discovery document
+// (`{issuer}/.well-known/openid-configuration`) and loads
+// its authorization, token and userinfo endpoints.
+//
+// The discovery documents are cached for 1 hour.
+func (p *OIDC) Discover(issuer string) error {
+	discovery, err := FetchOIDCDiscovery(issuer)
+	if err != nil {
+		return err
+	}
+
+	p.SetAuthUrl(discovery.AuthorizationEndpoint)
+	p.SetTokenUrl(discovery.TokenEndpoint)
+	p.SetUserApiUrl(discovery.UserinfoEndpoint)
+
+	return nil
+}
+
+// FetchOIDCDiscovery returns the (cached) OpenID Connect
+// discovery document of the specified issuer.
+//
+// API reference: https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderConfig
+func FetchOIDCDiscovery(issuer string) (*OIDCDiscovery, error) {
+	issuer = strings.TrimSuffix(issuer, "/")
+	if issuer == "" {
+		return nil, errors.New("Missing OpenID Connect issuer.")
+	}
+
+	oidcDiscoveryCache.Lock()
+	defer oidcDiscoveryCache.Unlock()
+
+	if item, ok := oidcDiscoveryCache.items[issuer]; ok && time.Now().Before(item.expires) {
+		return item.discovery, nil
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	response, err := client.Get(issuer + "/.well-known/openid-configuration")
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode >= 400 {
+		return nil, fmt.Errorf(
+			"Failed to fetch the OpenID Connect discovery document of %s (%d):\n%s",
+			issuer,
+			response.StatusCode,
+			string(body),
+		)
+	}
+
+	discovery := &OIDCDiscovery{}
+	if err := json.Unmarshal(body, discovery); err != nil {
+		return nil, err
+	}
+
+	// the returned issuer must be identical to the requested one
+	if strings.TrimSuffix(discovery.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("The discovery document issuer %q doesn't match with %q.", discovery.Issuer, issuer)
+	}
+
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.UserinfoEndpoint == "" {
+		return nil, errors.New("The discovery document is missing a required endpoint.")
+	}
+
+	oidcDiscoveryCache.items[issuer] = oidcDiscoveryCacheItem{
+		discovery: discovery,
+		expires:   time.Now().Add(oidcDiscoveryCacheDuration),
+	}
+
+	return discovery, nil
+}
+
+// FetchAuthUser returns an AuthUser instance based on the provider userinfo endpoint.
+//
+// Unverified emails (aka. `email_verified: false`) are ignored.
+//
+// API reference: https://openid.net/specs/openid-connect-core-1_0.html#StandardClaims
+func (p *OIDC) FetchAuthUser(token *oauth2.Token) (*AuthUser, error) {
+	data, err := p.FetchRawUserData(token)
+	if err != nil {
+		return nil, err
+	}
+
+	rawUser := map[string]any{}
+	if err := json.Unmarshal(data, &rawUser); err != nil {
+		return nil, err
+	}
+
+	extracted := struct {
+		Id            string `json:"sub"`
+		Name          string `json:"name"`
+		Username      string `json:"preferred_username"`
+		Email         string `json:"email"`
+		EmailVerified *bool  `json:"email_verified"`
+		Picture       string `json:"picture"`
+	}{}
+	if err := json.Unmarshal(data, &extracted); err != nil {
+		return nil, err
+	}
+
+	if extracted.Id == "" {
+		return nil, errors.New("Missing required userinfo sub claim.")
+	}
+
+	user := &AuthUser{
+		Id:          extracted.Id,
+		Name:        extracted.Name,
+		Username:    extracted.Username,
+		AvatarUrl:   extracted.Picture,
+		RawUser:     rawUser,
+		AccessToken: token.AccessToken,
+	}
+
+	if extracted.EmailVerified == nil || *extracted.EmailVerified {
+		user.Email = extracted.Email
+	}
+
+	return user, nil
+}
@@ -0,0 +1,155 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/auth"
+	"golang.org/x/oauth2"
+)
+
+func TestOIDCDiscover(t *testing.T) {
+	var totalRequests int
+	var issuer string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		totalRequests++
+
+		switch r.URL.Path {
+		case "/valid/.well-known/openid-configuration":
+			w.Write([]byte(`{
+				"issuer":"` + issuer + `/valid",
+				"authorization_endpoint":"https://example.com/auth",
+				"token_endpoint":"https://example.com/token",
+				"userinfo_endpoint":"https://example.com/userinfo"
+			}`))
+		case "/mismatch/.well-known/openid-configuration":
+			w.Write([]byte(`{
+				"issuer":"https://example.com",
+				"authorization_endpoint":"https://example.com/auth",
+				"token_endpoint":"https://example.com/token",
+				"userinfo_endpoint":"https://example.com/userinfo"
+			}`))
+		case "/incomplete/.well-known/openid-configuration":
+			w.Write([]byte(`{"issuer":"` + issuer + `/incomplete","authorization_endpoint":"https://example.com/auth"}`))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	issuer = server.URL
+
+	scenarios := []struct {
+		issuer      string
+		expectError bool
+	}{
+		{"", true},
+		{issuer + "/missing", true},
+		{issuer + "/mismatch", true},
+		{issuer + "/incomplete", true},
+		{issuer + "/valid", false},
+		{issuer + "/valid/", false},
+	}
+
+	for i, s := range scenarios {
+		p := auth.NewOIDCProvider()
+
+		err := p.Discover(s.issuer)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		if p.AuthUrl() != "https://example.com/auth" {
+			t.Errorf("(%d) Expected AuthUrl %q, got %q", i, "https://example.com/auth", p.AuthUrl())
+		}
+		if p.TokenUrl() != "https://example.com/token" {
+			t.Errorf("(%d) Expected TokenUrl %q, got %q", i, "https://example.com/token", p.TokenUrl())
+		}
+		if p.UserApiUrl() != "https://example.com/userinfo" {
+			t.Errorf("(%d) Expected UserApiUrl %q, got %q", i, "https://example.com/userinfo", p.UserApiUrl())
+		}
+	}
+
+	// the last valid discovery should have been loaded from the cache
+	if totalRequests != 4 {
+		t.Fatalf("Expected 4 discovery requests, got %d", totalRequests)
+	}
+}
+
+func TestOIDCFetchAuthUser(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test_token" {
+			w.WriteHeader(401)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/verified":
+			w.Write([]byte(`{
+				"sub":"123",
+				"name":"test name",
+				"preferred_username":"test",
+				"email":"test@example.com",
+				"email_verified":true,
+				"picture":"https://example.com/avatar.png",
+				"groups":["a","b"]
+			}`))
+		case "/unverified":
+			w.Write([]byte(`{"sub":"456","email":"test@example.com","email_verified":false}`))
+		case "/missing-sub":
+			w.Write([]byte(`{"email":"test@example.com"}`))
+		}
+	}))
+	defer server.Close()
+
+	token := &oauth2.Token{AccessToken: "test_token", TokenType: "Bearer"}
+
+	scenarios := []struct {
+		path          string
+		expectError   bool
+		expectedId    string
+		expectedEmail string
+	}{
+		{"/missing-sub", true, "", ""},
+		{"/unverified", false, "456", ""},
+		{"/verified", false, "123", "test@example.com"},
+	}
+
+	for i, s := range scenarios {
+		p := auth.NewOIDCProvider()
+		p.SetUserApiUrl(server.URL + s.path)
+
+		user, err := p.FetchAuthUser(token)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		if user.Id != s.expectedId {
+			t.Errorf("(%d) Expected id %q, got %q", i, s.expectedId, user.Id)
+		}
+
+		if user.Email != s.expectedEmail {
+			t.Errorf("(%d) Expected email %q, got %q", i, s.expectedEmail, user.Email)
+		}
+
+		if user.RawUser["sub"] != s.expectedId {
+			t.Errorf("(%d) Expected the raw claims to be loaded, got %v", i, user.RawUser)
+		}
+	}
+}
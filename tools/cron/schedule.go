@@ -0,0 +1,188 @@
+// Package cron implements a minimal parser and matcher of the
+// standard 5 fields cron expressions.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// macros lists the supported cron expression shortcuts.
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Schedule defines a parsed cron expression.
+type Schedule struct {
+	Minutes    map[int]struct{}
+	Hours      map[int]struct{}
+	Days       map[int]struct{}
+	Months     map[int]struct{}
+	DaysOfWeek map[int]struct{}
+
+	// restricted days and days of week flags
+	// (used to apply the standard "either" matching when both are set)
+	daysRestricted       bool
+	daysOfWeekRestricted bool
+}
+
+// NewSchedule parses the provided 5 fields cron expression
+// ("minute hour day month dayOfWeek") or one of the supported macros.
+//
+// Each field could be "*", a single number, a range ("1-5"),
+// a list ("1,3,5") and each of them could have a step ("*/15", "1-30/2").
+// The day of week 7 is treated as Sunday (0).
+//
+// Example:
+//
+//	NewSchedule("*/30 9-17 * * 1-5") // every 30 minutes during the work hours
+//	NewSchedule("@weekly")           // every Sunday at 00:00
+func NewSchedule(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+
+	if macro, ok := macros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q (expected 5 fields, got %d)", expr, len(fields))
+	}
+
+	var err error
+
+	s := &Schedule{}
+
+	if s.Minutes, err = parseField(fields[0], 0, 59); err != nil {
+		return nil, err
+	}
+
+	if s.Hours, err = parseField(fields[1], 0, 23); err != nil {
+		return nil, err
+	}
+
+	if s.Days, err = parseField(fields[2], 1, 31); err != nil {
+		return nil, err
+	}
+
+	if s.Months, err = parseField(fields[3], 1, 12); err != nil {
+		return nil, err
+	}
+
+	if s.DaysOfWeek, err = parseField(fields[4], 0, 7); err != nil {
+		return nil, err
+	}
+	if _, ok := s.DaysOfWeek[7]; ok {
+		delete(s.DaysOfWeek, 7)
+		s.DaysOfWeek[0] = struct{}{}
+	}
+
+	s.daysRestricted = !strings.HasPrefix(fields[2], "*")
+	s.daysOfWeekRestricted = !strings.HasPrefix(fields[4], "*")
+
+	return s, nil
+}
+
+// IsDue checks whether the schedule matches the minute of the provided time.
+//
+// Similar to the other cron implementations, if both the day and day of week
+// fields are restricted, the time matches if either of them matches.
+func (s *Schedule) IsDue(t time.Time) bool {
+	if !has(s.Minutes, t.Minute()) || !has(s.Hours, t.Hour()) || !has(s.Months, int(t.Month())) {
+		return false
+	}
+
+	dayMatch := has(s.Days, t.Day())
+	dayOfWeekMatch := has(s.DaysOfWeek, int(t.Weekday()))
+
+	if s.daysRestricted && s.daysOfWeekRestricted {
+		return dayMatch || dayOfWeekMatch
+	}
+
+	return dayMatch && dayOfWeekMatch
+}
+
+func has(values map[int]struct{}, v int) bool {
+	_, ok := values[v]
+	return ok
+}
+
+// parseField parses a single cron expression field
+// and returns the set of its matching values.
+func parseField(field string, min int, max int) (map[int]struct{}, error) {
+	result := map[int]struct{}{}
+
+	for _, segment := range strings.Split(field, ",") {
+		rangePart, rawStep, hasStep := strings.Cut(segment, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(rawStep)
+			if err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid cron step in %q", segment)
+			}
+		}
+
+		start, end := min, max
+
+		if rangePart != "*" {
+			rawStart, rawEnd, isRange := strings.Cut(rangePart, "-")
+
+			var err error
+			start, err = parseValue(rawStart, min, max)
+			if err != nil {
+				return nil, err
+			}
+
+			switch {
+			case isRange:
+				end, err = parseValue(rawEnd, min, max)
+				if err != nil {
+					return nil, err
+				}
+			case hasStep:
+				// "N/step" is a shorthand for "N-max/step"
+				end = max
+			default:
+				end = start
+			}
+
+			if start > end {
+				return nil, fmt.Errorf("invalid cron range %q", rangePart)
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			result[v] = struct{}{}
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, errors.New("empty cron field")
+	}
+
+	return result, nil
+}
+
+func parseValue(raw string, min int, max int) (int, error) {
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cron value %q", raw)
+	}
+
+	if v < min || v > max {
+		return 0, fmt.Errorf("the cron value %d is out of range [%d, %d]", v, min, max)
+	}
+
+	return v, nil
+}
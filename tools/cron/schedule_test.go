@@ -0,0 +1,107 @@
+package cron_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/cron"
+)
+
+func TestNewSchedule(t *testing.T) {
+	scenarios := []struct {
+		expr        string
+		expectError bool
+	}{
+		{"", true},
+		{"* * * *", true},
+		{"* * * * * *", true},
+		{"60 * * * *", true},
+		{"* 24 * * *", true},
+		{"* * 0 * *", true},
+		{"* * * 13 *", true},
+		{"* * * * 8", true},
+		{"a * * * *", true},
+		{"*/0 * * * *", true},
+		{"*/a * * * *", true},
+		{"5-1 * * * *", true},
+		{"1-a * * * *", true},
+		{"1,,2 * * * *", true},
+		{"@unknown", true},
+		{"* * * * *", false},
+		{" 0  9 * * 1-5 ", false},
+		{"*/15 0-23/2 1,15 1-12 0,7", false},
+		{"5/10 * * * *", false},
+		{"@daily", false},
+		{"@WEEKLY", false},
+	}
+
+	for _, s := range scenarios {
+		_, err := cron.NewSchedule(s.expr)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%q) Expected hasErr %v, got %v (%v)", s.expr, s.expectError, hasErr, err)
+		}
+	}
+}
+
+func TestScheduleIsDue(t *testing.T) {
+	// 2023-01-02 is Monday
+	date := func(raw string) time.Time {
+		d, err := time.Parse("2006-01-02 15:04", raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	scenarios := []struct {
+		expr     string
+		time     string
+		expected bool
+	}{
+		{"* * * * *", "2023-01-02 10:11", true},
+		{"0 9 * * 1", "2023-01-02 09:00", true},
+		{"0 9 * * 1", "2023-01-02 09:01", false},
+		{"0 9 * * 1", "2023-01-03 09:00", false},
+		{"*/15 * * * *", "2023-01-02 10:45", true},
+		{"*/15 * * * *", "2023-01-02 10:46", false},
+		{"5/10 * * * *", "2023-01-02 10:25", true},
+		{"5/10 * * * *", "2023-01-02 10:20", false},
+		{"0 9-17/4 * * *", "2023-01-02 13:00", true},
+		{"0 9-17/4 * * *", "2023-01-02 15:00", false},
+		{"0 0 * * 7", "2023-01-01 00:00", true},
+		{"0 0 1 * *", "2023-02-01 00:00", true},
+		{"0 0 1 * *", "2023-02-02 00:00", false},
+		{"0 0 * 3 *", "2023-02-01 00:00", false},
+		// restricted day and day of week (either matches)
+		{"0 0 15 * 1", "2023-01-02 00:00", true},
+		{"0 0 15 * 1", "2023-01-15 00:00", true},
+		{"0 0 15 * 1", "2023-01-03 00:00", false},
+		// step day with restricted day of week (both should match)
+		{"0 0 */2 * 1", "2023-01-03 00:00", false},
+		{"0 0 */2 * 1", "2023-01-09 00:00", true},
+		// macros
+		{"@hourly", "2023-01-02 10:00", true},
+		{"@hourly", "2023-01-02 10:30", false},
+		{"@daily", "2023-01-02 00:00", true},
+		{"@weekly", "2023-01-01 00:00", true},
+		{"@weekly", "2023-01-02 00:00", false},
+		{"@monthly", "2023-03-01 00:00", true},
+		{"@yearly", "2023-01-01 00:00", true},
+		{"@yearly", "2023-02-01 00:00", false},
+	}
+
+	for _, s := range scenarios {
+		schedule, err := cron.NewSchedule(s.expr)
+		if err != nil {
+			t.Errorf("(%q) Failed to parse the expression: %v", s.expr, err)
+			continue
+		}
+
+		result := schedule.IsDue(date(s.time))
+		if result != s.expected {
+			t.Errorf("(%q, %s) Expected %v, got %v", s.expr, s.time, s.expected, result)
+		}
+	}
+}
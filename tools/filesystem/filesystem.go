@@ -29,6 +29,10 @@ import (
 type System struct {
 	ctx    context.Context
 	bucket *blob.Bucket
+
+	// sharded indicates whether the files are stored
+	// in the sharded directory layout (see [NewLocalSharded]).
+	sharded bool
 }
 
 // NewS3 initializes an S3 filesystem instance.
@@ -83,6 +87,15 @@ func NewLocal(dirPath string) (*System, error) {
 	return &System{ctx: ctx, bucket: bucket}, nil
 }
 
+// key returns the storage key of the provided file key.
+func (s *System) key(fileKey string) string {
+	if !s.sharded {
+		return fileKey
+	}
+
+	return ShardedKey(fileKey)
+}
+
 // Close releases any resources used for the related filesystem.
 func (s *System) Close() error {
 	return s.bucket.Close()
@@ -90,12 +103,12 @@ func (s *System) Close() error {
 
 // Exists checks if file with fileKey path exists or not.
 func (s *System) Exists(fileKey string) (bool, error) {
-	return s.bucket.Exists(s.ctx, fileKey)
+	return s.bucket.Exists(s.ctx, s.key(fileKey))
 }
 
 // Attributes returns the attributes for the file with fileKey path.
 func (s *System) Attributes(fileKey string) (*blob.Attributes, error) {
-	return s.bucket.Attributes(s.ctx, fileKey)
+	return s.bucket.Attributes(s.ctx, s.key(fileKey))
 }
 
 // Upload writes content into the fileKey location.
@@ -104,7 +117,7 @@ func (s *System) Upload(content []byte, fileKey string) error {
 		ContentType: mimetype.Detect(content).String(),
 	}
 
-	w, writerErr := s.bucket.NewWriter(s.ctx, fileKey, opts)
+	w, writerErr := s.bucket.NewWriter(s.ctx, s.key(fileKey), opts)
 	if writerErr != nil {
 		return writerErr
 	}
@@ -146,7 +159,7 @@ func (s *System) UploadFile(file *File, fileKey string) error {
 		},
 	}
 
-	w, err := s.bucket.NewWriter(s.ctx, fileKey, opts)
+	w, err := s.bucket.NewWriter(s.ctx, s.key(fileKey), opts)
 	if err != nil {
 		return err
 	}
@@ -188,7 +201,7 @@ func (s *System) UploadMultipart(fh *multipart.FileHeader, fileKey string) error
 		},
 	}
 
-	w, err := s.bucket.NewWriter(s.ctx, fileKey, opts)
+	w, err := s.bucket.NewWriter(s.ctx, s.key(fileKey), opts)
 	if err != nil {
 		return err
 	}
@@ -201,9 +214,63 @@ func (s *System) UploadMultipart(fh *multipart.FileHeader, fileKey string) error
 	return w.Close()
 }
 
+// Copy copies the stored file at srcKey location to dstKey location.
+func (s *System) Copy(srcKey, dstKey string) error {
+	return s.bucket.Copy(s.ctx, s.key(dstKey), s.key(srcKey), nil)
+}
+
+// Rename moves the stored file at srcKey location to dstKey location.
+func (s *System) Rename(srcKey, dstKey string) error {
+	if err := s.Copy(srcKey, dstKey); err != nil {
+		return err
+	}
+
+	return s.Delete(srcKey)
+}
+
+// List returns the stored files (excluding the dir objects)
+// whose keys start with the specified prefix, ordered by their key.
+//
+// The returned objects keys are always in the non-sharded
+// "collectionId/recordId/..." format (for the sharded storage the prefix
+// is expected to be a collection dir or a full record dir path).
+func (s *System) List(prefix string) ([]*blob.ListObject, error) {
+	result := []*blob.ListObject{}
+
+	iter := s.bucket.List(&blob.ListOptions{
+		Prefix: s.key(prefix),
+	})
+	for {
+		obj, err := iter.Next(s.ctx)
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if obj.IsDir {
+			continue
+		}
+
+		if s.sharded {
+			obj.Key = unshardedKey(obj.Key)
+		}
+
+		result = append(result, obj)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+
+	return result, nil
+}
+
 // Delete deletes stored file at fileKey location.
 func (s *System) Delete(fileKey string) error {
-	return s.bucket.Delete(s.ctx, fileKey)
+	return s.bucket.Delete(s.ctx, s.key(fileKey))
 }
 
 // DeletePrefix deletes everything starting with the specified prefix.
@@ -215,13 +282,22 @@ func (s *System) DeletePrefix(prefix string) []error {
 		return failed
 	}
 
+	storagePrefix := s.key(prefix)
+
 	dirsMap := map[string]struct{}{}
-	dirsMap[prefix] = struct{}{}
+	dirsMap[storagePrefix] = struct{}{}
+
+	// include the parent shard dirs so that they could be also removed if empty
+	if s.sharded {
+		for _, dir := range shardDirs(prefix) {
+			dirsMap[dir] = struct{}{}
+		}
+	}
 
 	// delete all files with the prefix
 	// ---
 	iter := s.bucket.List(&blob.ListOptions{
-		Prefix: prefix,
+		Prefix: storagePrefix,
 	})
 	for {
 		obj, err := iter.Next(s.ctx)
@@ -234,7 +310,7 @@ func (s *System) DeletePrefix(prefix string) []error {
 			continue
 		}
 
-		if err := s.Delete(obj.Key); err != nil {
+		if err := s.bucket.Delete(s.ctx, obj.Key); err != nil {
 			failed = append(failed, err)
 		} else {
 			dirsMap[filepath.Dir(obj.Key)] = struct{}{}
@@ -259,7 +335,7 @@ func (s *System) DeletePrefix(prefix string) []error {
 	// delete dirs
 	for _, d := range dirs {
 		if d != "" {
-			s.Delete(d)
+			s.bucket.Delete(s.ctx, d)
 		}
 	}
 	// ---
@@ -287,7 +363,7 @@ var manualExtensionContentTypes = map[string]string{
 
 // Serve serves the file at fileKey location to an HTTP response.
 func (s *System) Serve(res http.ResponseWriter, req *http.Request, fileKey string, name string) error {
-	br, readErr := s.bucket.NewReader(s.ctx, fileKey, nil)
+	br, readErr := s.bucket.NewReader(s.ctx, s.key(fileKey), nil)
 	if readErr != nil {
 		return readErr
 	}
@@ -364,7 +440,7 @@ func (s *System) CreateThumb(originalKey string, thumbKey, thumbSize string) err
 	}
 
 	// fetch the original
-	r, readErr := s.bucket.NewReader(s.ctx, originalKey, nil)
+	r, readErr := s.bucket.NewReader(s.ctx, s.key(originalKey), nil)
 	if readErr != nil {
 		return readErr
 	}
@@ -400,7 +476,7 @@ func (s *System) CreateThumb(originalKey string, thumbKey, thumbSize string) err
 	}
 
 	// open a thumb storage writer (aka. prepare for upload)
-	w, writerErr := s.bucket.NewWriter(s.ctx, thumbKey, nil)
+	w, writerErr := s.bucket.NewWriter(s.ctx, s.key(thumbKey), nil)
 	if writerErr != nil {
 		return writerErr
 	}
@@ -101,6 +101,96 @@ func TestFileSystemDelete(t *testing.T) {
 	}
 }
 
+func TestFileSystemCopy(t *testing.T) {
+	dir := createTestDir(t)
+	defer os.RemoveAll(dir)
+
+	fs, err := filesystem.NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	if err := fs.Copy("missing.txt", "new.txt"); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if err := fs.Copy("image.png", "copy/image.png"); err != nil {
+		t.Fatalf("Expected nil, got error %v", err)
+	}
+
+	for _, key := range []string{"image.png", "copy/image.png"} {
+		if exists, _ := fs.Exists(key); !exists {
+			t.Fatalf("Expected %s to exist", key)
+		}
+	}
+}
+
+func TestFileSystemRename(t *testing.T) {
+	dir := createTestDir(t)
+	defer os.RemoveAll(dir)
+
+	fs, err := filesystem.NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	if err := fs.Rename("missing.txt", "new.txt"); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if err := fs.Rename("test/sub1.txt", "renamed/sub1.txt"); err != nil {
+		t.Fatalf("Expected nil, got error %v", err)
+	}
+
+	if exists, _ := fs.Exists("test/sub1.txt"); exists {
+		t.Fatal("Expected test/sub1.txt to be moved")
+	}
+
+	if exists, _ := fs.Exists("renamed/sub1.txt"); !exists {
+		t.Fatal("Expected renamed/sub1.txt to exist")
+	}
+}
+
+func TestFileSystemList(t *testing.T) {
+	dir := createTestDir(t)
+	defer os.RemoveAll(dir)
+
+	fs, err := filesystem.NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	scenarios := []struct {
+		prefix       string
+		expectedKeys []string
+	}{
+		{"missing", []string{}},
+		{"test/", []string{"test/sub1.txt", "test/sub2.txt"}},
+		{"image", []string{"image.png", "image.svg", "image_! noext"}},
+		{"", []string{"image.png", "image.svg", "image_! noext", "style.css", "test/sub1.txt", "test/sub2.txt"}},
+	}
+
+	for i, s := range scenarios {
+		objs, err := fs.List(s.prefix)
+		if err != nil {
+			t.Errorf("(%d) Expected nil, got error %v", i, err)
+			continue
+		}
+
+		keys := make([]string, len(objs))
+		for j, obj := range objs {
+			keys[j] = obj.Key
+		}
+
+		if strings.Join(keys, ",") != strings.Join(s.expectedKeys, ",") {
+			t.Errorf("(%d) Expected keys %v, got %v", i, s.expectedKeys, keys)
+		}
+	}
+}
+
 func TestFileSystemDeletePrefix(t *testing.T) {
 	dir := createTestDir(t)
 	defer os.RemoveAll(dir)
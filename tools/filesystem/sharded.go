@@ -0,0 +1,239 @@
+package filesystem
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// NewLocalSharded initializes a new local filesystem instance that
+// stores the files in the sharded directory layout (see [ShardedKey]).
+//
+// The sharded layout prevents having hundreds of thousands of record
+// directories in a single collection directory (which degrades the
+// performance of most filesystems).
+//
+// NB! Make sure to call `Close()` after you are done working with it.
+func NewLocalSharded(dirPath string) (*System, error) {
+	s, err := NewLocal(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	s.sharded = true
+
+	return s, nil
+}
+
+// ShardedKey converts the provided "collectionId/recordId/..." file key
+// to its sharded "collectionId/ab/cd/recordId/..." equivalent, where
+// "ab/cd" are the first 4 characters of the record id sha256 hex hash.
+//
+// Keys with less than 2 path segments are returned unchanged.
+func ShardedKey(fileKey string) string {
+	parts := strings.SplitN(fileKey, "/", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return fileKey
+	}
+
+	parts[1] = shardPath(parts[1]) + "/" + parts[1]
+
+	return strings.Join(parts, "/")
+}
+
+// unshardedKey converts the provided sharded "collectionId/ab/cd/recordId/..."
+// storage key back to its "collectionId/recordId/..." file key equivalent.
+//
+// Keys that are not in the sharded format are returned unchanged.
+func unshardedKey(storageKey string) string {
+	parts := strings.SplitN(storageKey, "/", 5)
+	if len(parts) < 4 || !isShardDirName(parts[1]) || !isShardDirName(parts[2]) {
+		return storageKey
+	}
+
+	key := parts[0] + "/" + strings.Join(parts[3:], "/")
+
+	// ensure that the shard dirs match the record id
+	if ShardedKey(key) != storageKey {
+		return storageKey
+	}
+
+	return key
+}
+
+// shardPath returns the "ab/cd" shard path of the provided record id.
+func shardPath(recordId string) string {
+	hash := sha256.Sum256([]byte(recordId))
+	str := hex.EncodeToString(hash[:])
+
+	return str[0:2] + "/" + str[2:4]
+}
+
+// shardDirs returns the parent shard dirs of the provided
+// file key or prefix (the deepest first).
+func shardDirs(fileKey string) []string {
+	parts := strings.SplitN(fileKey, "/", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return nil
+	}
+
+	shard := shardPath(parts[1])
+
+	return []string{
+		parts[0] + "/" + shard,
+		parts[0] + "/" + shard[0:2],
+	}
+}
+
+// isShardDirName checks whether name is a valid shard dir name (2 lowercase hex characters).
+func isShardDirName(name string) bool {
+	if len(name) != 2 {
+		return false
+	}
+
+	_, err := hex.DecodeString(name)
+
+	return err == nil && strings.ToLower(name) == name
+}
+
+// MigrateLocalLayout moves the record directories of the local storage
+// at dirPath from the flat "collectionId/recordId" layout to the sharded
+// "collectionId/ab/cd/recordId" one (or vice versa if sharded is false).
+//
+// Already migrated record directories are skipped, so it is safe
+// to rerun the migration after an interruption.
+//
+// Returns the number of the moved record directories.
+func MigrateLocalLayout(dirPath string, sharded bool) (int, error) {
+	collectionDirs, err := subDirs(dirPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	var moved int
+
+	for _, collectionDir := range collectionDirs {
+		var total int
+		var err error
+
+		if sharded {
+			total, err = shardCollectionDir(filepath.Join(dirPath, collectionDir))
+		} else {
+			total, err = unshardCollectionDir(filepath.Join(dirPath, collectionDir))
+		}
+
+		moved += total
+
+		if err != nil {
+			return moved, err
+		}
+	}
+
+	return moved, nil
+}
+
+func shardCollectionDir(collectionPath string) (int, error) {
+	recordDirs, err := subDirs(collectionPath)
+	if err != nil {
+		return 0, err
+	}
+
+	var moved int
+
+	for _, recordDir := range recordDirs {
+		if isShardDirName(recordDir) {
+			continue // already sharded
+		}
+
+		dst := filepath.Join(collectionPath, filepath.FromSlash(shardPath(recordDir)), recordDir)
+
+		if err := moveDir(filepath.Join(collectionPath, recordDir), dst); err != nil {
+			return moved, err
+		}
+
+		moved++
+	}
+
+	return moved, nil
+}
+
+func unshardCollectionDir(collectionPath string) (int, error) {
+	firstLevelDirs, err := subDirs(collectionPath)
+	if err != nil {
+		return 0, err
+	}
+
+	var moved int
+
+	for _, first := range firstLevelDirs {
+		if !isShardDirName(first) {
+			continue // not a shard dir
+		}
+
+		firstPath := filepath.Join(collectionPath, first)
+
+		secondLevelDirs, err := subDirs(firstPath)
+		if err != nil {
+			return moved, err
+		}
+
+		for _, second := range secondLevelDirs {
+			secondPath := filepath.Join(firstPath, second)
+
+			recordDirs, err := subDirs(secondPath)
+			if err != nil {
+				return moved, err
+			}
+
+			for _, recordDir := range recordDirs {
+				if err := moveDir(filepath.Join(secondPath, recordDir), filepath.Join(collectionPath, recordDir)); err != nil {
+					return moved, err
+				}
+
+				moved++
+			}
+
+			// remove the empty shard dir (the error is ignored in case it is not empty)
+			os.Remove(secondPath)
+		}
+
+		os.Remove(firstPath)
+	}
+
+	return moved, nil
+}
+
+func moveDir(src, dst string) error {
+	if _, err := os.Stat(dst); err == nil {
+		return fmt.Errorf("Failed to move %s: the destination %s already exists.", src, dst)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+
+	return os.Rename(src, dst)
+}
+
+// subDirs returns the names of the direct subdirectories of dirPath.
+func subDirs(dirPath string) ([]string, error) {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			result = append(result, entry.Name())
+		}
+	}
+
+	return result, nil
+}
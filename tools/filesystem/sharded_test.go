@@ -0,0 +1,194 @@
+package filesystem_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+)
+
+func TestShardedKey(t *testing.T) {
+	scenarios := []struct {
+		key      string
+		expected string
+	}{
+		{"", ""},
+		{"col", "col"},
+		{"col/", "col/"},
+		{"/rec", "/rec"},
+		{"col/rec", "col/cb/41/rec"},
+		{"col/rec/", "col/cb/41/rec/"},
+		{"col/rec/test.txt", "col/cb/41/rec/test.txt"},
+		{"col/rec/thumbs_test.png/100x100_test.png", "col/cb/41/rec/thumbs_test.png/100x100_test.png"},
+	}
+
+	for _, s := range scenarios {
+		result := filesystem.ShardedKey(s.key)
+		if result != s.expected {
+			t.Errorf("(%q) Expected %q, got %q", s.key, s.expected, result)
+		}
+	}
+}
+
+func TestLocalShardedFileSystem(t *testing.T) {
+	dir, err := os.MkdirTemp("", "pb_sharded_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fs, err := filesystem.NewLocalSharded(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	if err := fs.Upload([]byte("test"), "col/rec/test.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "col/cb/41/rec/test.txt")); err != nil {
+		t.Fatalf("Expected the file to be stored in the sharded dir: %v", err)
+	}
+
+	if exists, _ := fs.Exists("col/rec/test.txt"); !exists {
+		t.Fatal("Expected the file to exist")
+	}
+
+	if err := fs.Copy("col/rec/test.txt", "col/rec2/test.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if exists, _ := fs.Exists("col/rec2/test.txt"); !exists {
+		t.Fatal("Expected the copied file to exist")
+	}
+
+	objs, err := fs.List("col/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objs) != 2 || objs[0].Key != "col/rec/test.txt" || objs[1].Key != "col/rec2/test.txt" {
+		t.Fatalf("Expected the listed keys to be unsharded, got %v", objs)
+	}
+
+	if objs, _ := fs.List("col/rec2"); len(objs) != 1 || objs[0].Key != "col/rec2/test.txt" {
+		t.Fatalf("Expected the single col/rec2 file to be listed, got %v", objs)
+	}
+
+	if errs := fs.DeletePrefix("col/rec"); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	if exists, _ := fs.Exists("col/rec/test.txt"); exists {
+		t.Fatal("Expected the file to be deleted")
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "col/cb")); !os.IsNotExist(err) {
+		t.Fatalf("Expected the empty shard dirs to be deleted, got %v", err)
+	}
+
+	// the whole collection
+	if errs := fs.DeletePrefix("col"); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	if exists, _ := fs.Exists("col/rec2/test.txt"); exists {
+		t.Fatal("Expected the copied file to be deleted")
+	}
+}
+
+func TestMigrateLocalLayout(t *testing.T) {
+	dir, err := os.MkdirTemp("", "pb_sharded_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// missing dir
+	if moved, err := filesystem.MigrateLocalLayout(filepath.Join(dir, "missing"), true); err != nil || moved != 0 {
+		t.Fatalf("Expected 0 moved and no error for missing dir, got %d (%v)", moved, err)
+	}
+
+	flatFs, err := filesystem.NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer flatFs.Close()
+
+	shardedFs, err := filesystem.NewLocalSharded(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shardedFs.Close()
+
+	files := []string{
+		"col1/rec1/a.txt",
+		"col1/rec2/b.txt",
+		"col1/rec2/thumbs_b.txt/c.txt",
+		"col2/rec3/d.txt",
+	}
+
+	for _, f := range files {
+		if err := flatFs.Upload([]byte("test"), f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	moved, err := filesystem.MigrateLocalLayout(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved != 3 {
+		t.Fatalf("Expected 3 moved record dirs, got %d", moved)
+	}
+
+	for _, f := range files {
+		if exists, _ := shardedFs.Exists(f); !exists {
+			t.Errorf("Expected %s to exist in the sharded layout", f)
+		}
+		if exists, _ := flatFs.Exists(f); exists {
+			t.Errorf("Expected %s to not exist in the flat layout", f)
+		}
+	}
+
+	// rerun
+	moved, err = filesystem.MigrateLocalLayout(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved != 0 {
+		t.Fatalf("Expected 0 moved record dirs on rerun, got %d", moved)
+	}
+
+	// revert
+	moved, err = filesystem.MigrateLocalLayout(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved != 3 {
+		t.Fatalf("Expected 3 moved record dirs on revert, got %d", moved)
+	}
+
+	for _, f := range files {
+		if exists, _ := flatFs.Exists(f); !exists {
+			t.Errorf("Expected %s to exist in the flat layout", f)
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "col1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected only the 2 record dirs to remain, got %d entries", len(entries))
+	}
+
+	// destination conflict
+	if err := shardedFs.Upload([]byte("test"), "col1/rec1/a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := filesystem.MigrateLocalLayout(dir, true); err == nil {
+		t.Fatal("Expected error for existing destination record dir")
+	}
+}
@@ -0,0 +1,153 @@
+package filesystem
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+// svgDeniedElements is a list with the (lowercased) svg elements that
+// are removed together with their content during the svg sanitization.
+var svgDeniedElements = []string{
+	"script",
+	"foreignobject",
+	"iframe",
+	"embed",
+	"object",
+	"handler",
+	"listener",
+}
+
+// svgDeniedValuePrefixes is a list with the (lowercased) attribute
+// value prefixes that are removed during the svg sanitization.
+var svgDeniedValuePrefixes = []string{
+	"javascript:",
+	"vbscript:",
+	"data:text/html",
+}
+
+// SanitizeSvg removes the potentially dangerous parts of the provided
+// svg content, aka. scripts and other embedded documents, event handler
+// attributes, "javascript:" links, comments, DOCTYPE declarations, etc.
+//
+// Returns an error if the content is not a well-formed xml document.
+func SanitizeSvg(content []byte) ([]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+
+	var result bytes.Buffer
+	encoder := xml.NewEncoder(&result)
+
+	// the depth of the currently skipped denied element (if any)
+	skipDepth := 0
+	hasRoot := false
+
+	for {
+		// the raw tokens are used to preserve the namespace prefixes
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if skipDepth > 0 {
+			switch token.(type) {
+			case xml.StartElement:
+				skipDepth++
+			case xml.EndElement:
+				skipDepth--
+			}
+			continue
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if isSvgDeniedElement(t.Name) {
+				skipDepth = 1
+				continue
+			}
+
+			hasRoot = true
+
+			attrs := make([]xml.Attr, 0, len(t.Attr))
+			for _, attr := range t.Attr {
+				if isSvgDeniedAttr(attr) {
+					continue
+				}
+				attrs = append(attrs, xml.Attr{Name: rawXmlName(attr.Name), Value: attr.Value})
+			}
+
+			token = xml.StartElement{Name: rawXmlName(t.Name), Attr: attrs}
+		case xml.EndElement:
+			token = xml.EndElement{Name: rawXmlName(t.Name)}
+		case xml.ProcInst:
+			if t.Target != "xml" {
+				continue // eg. external stylesheets
+			}
+		case xml.Comment, xml.Directive:
+			continue
+		}
+
+		if err := encoder.EncodeToken(xml.CopyToken(token)); err != nil {
+			return nil, err
+		}
+	}
+
+	if !hasRoot {
+		return nil, errors.New("missing svg root element")
+	}
+
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+
+	return result.Bytes(), nil
+}
+
+// rawXmlName returns a name with the namespace prefix inlined in its local part
+// (this is to prevent the encoder from generating new namespace declarations).
+func rawXmlName(name xml.Name) xml.Name {
+	if name.Space == "" {
+		return name
+	}
+
+	return xml.Name{Local: name.Space + ":" + name.Local}
+}
+
+func isSvgDeniedElement(name xml.Name) bool {
+	local := strings.ToLower(name.Local)
+
+	for _, denied := range svgDeniedElements {
+		if local == denied {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isSvgDeniedAttr(attr xml.Attr) bool {
+	// event handlers (eg. onload, onclick)
+	if attr.Name.Space == "" && strings.HasPrefix(strings.ToLower(attr.Name.Local), "on") {
+		return true
+	}
+
+	// normalize the value since browsers ignore the whitespaces and control chars in urls
+	value := strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(attr.Value))
+
+	for _, prefix := range svgDeniedValuePrefixes {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
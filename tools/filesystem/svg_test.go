@@ -0,0 +1,77 @@
+package filesystem_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+)
+
+func TestSanitizeSvg(t *testing.T) {
+	scenarios := []struct {
+		name        string
+		content     string
+		expectError bool
+		expected    string
+	}{
+		{
+			"empty",
+			``,
+			true,
+			``,
+		},
+		{
+			"malformed xml",
+			`<svg><g></svg>`,
+			true,
+			``,
+		},
+		{
+			"only denied elements",
+			`<script>alert(1)</script>`,
+			true,
+			``,
+		},
+		{
+			"safe svg",
+			`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" viewBox="0 0 10 10"><a xlink:href="https://example.com"><text x="1">a &amp; b</text></a></svg>`,
+			false,
+			`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" viewBox="0 0 10 10"><a xlink:href="https://example.com"><text x="1">a &amp; b</text></a></svg>`,
+		},
+		{
+			"dangerous svg",
+			`<?xml version="1.0"?>` +
+				`<?xml-stylesheet href="https://example.com/style.css"?>` +
+				`<!DOCTYPE svg [<!ENTITY test "test">]>` +
+				`<svg xmlns="http://www.w3.org/2000/svg" onload="alert(1)" ONCLICK="alert(2)">` +
+				`<!-- comment -->` +
+				`<script>alert(3)</script>` +
+				`<SCRIPT type="text/javascript"><![CDATA[alert(4)]]></SCRIPT>` +
+				`<foreignObject><iframe src="https://example.com"></iframe></foreignObject>` +
+				`<a href=" java&#x09;script:alert(5)" target="_blank"><circle r="1"/></a>` +
+				`<a xlink:href="data:text/html;base64,PHNjcmlwdD4="><rect/></a>` +
+				`<set attributeName="href" to="javascript:alert(6)"/>` +
+				`</svg>`,
+			false,
+			`<?xml version="1.0"?>` +
+				`<svg xmlns="http://www.w3.org/2000/svg">` +
+				`<a target="_blank"><circle r="1"></circle></a>` +
+				`<a><rect></rect></a>` +
+				`<set attributeName="href"></set>` +
+				`</svg>`,
+		},
+	}
+
+	for _, s := range scenarios {
+		result, err := filesystem.SanitizeSvg([]byte(s.content))
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if string(result) != s.expected {
+			t.Errorf("[%s] Expected \n%s, \ngot \n%s", s.name, s.expected, result)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package inflector
+
+// ColumnNamer defines a db identifiers normalization strategy
+// that could be used as a replacement of the default [Columnify]
+// (eg. to apply a custom reserved words handling).
+type ColumnNamer interface {
+	// Columnify normalizes str into a db identifier.
+	Columnify(str string) string
+}
+
+// ColumnNamerFunc is an adapter to allow the use
+// of ordinary functions as [ColumnNamer].
+type ColumnNamerFunc func(str string) string
+
+// Columnify implements the [ColumnNamer] interface.
+func (f ColumnNamerFunc) Columnify(str string) string {
+	return f(str)
+}
+
+// DefaultColumnNamer is the default [ColumnNamer] that uses [Columnify].
+var DefaultColumnNamer ColumnNamer = ColumnNamerFunc(Columnify)
@@ -49,6 +49,20 @@ func TestColumnify(t *testing.T) {
 	}
 }
 
+func TestColumnNamer(t *testing.T) {
+	if result := inflector.DefaultColumnNamer.Columnify("@test!abc"); result != "@testabc" {
+		t.Fatalf("Expected the default namer to fallback to Columnify, got %q", result)
+	}
+
+	namer := inflector.ColumnNamerFunc(func(str string) string {
+		return "col_" + inflector.Columnify(str)
+	})
+
+	if result := namer.Columnify("test!abc"); result != "col_testabc" {
+		t.Fatalf("Expected col_testabc, got %q", result)
+	}
+}
+
 func TestSentenize(t *testing.T) {
 	scenarios := []struct {
 		val      string
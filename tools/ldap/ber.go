@@ -0,0 +1,268 @@
+package ldap
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MaxPacketSize is the max allowed size of a single read BER packet.
+const MaxPacketSize = 10 << 20
+
+// maxPacketDepth is the max allowed nesting level of the BER packets.
+const maxPacketDepth = 32
+
+// BER identifier classes.
+const (
+	ClassUniversal   byte = 0x00
+	ClassApplication byte = 0x40
+	ClassContext     byte = 0x80
+)
+
+// BER universal tags.
+const (
+	TagBoolean     = 0x01
+	TagInteger     = 0x02
+	TagOctetString = 0x04
+	TagNull        = 0x05
+	TagEnumerated  = 0x0a
+	TagSequence    = 0x10
+	TagSet         = 0x11
+)
+
+// Packet defines a single BER (Basic Encoding Rules) encoded element
+// (only the low tag numbers and definite lengths are supported).
+type Packet struct {
+	Class       byte
+	Constructed bool
+	Tag         int
+
+	// Value is the raw content of the primitive packets.
+	Value []byte
+
+	// Children are the nested packets of the constructed packets.
+	Children []*Packet
+}
+
+// NewConstructed creates a new constructed packet with the provided children.
+func NewConstructed(class byte, tag int, children ...*Packet) *Packet {
+	return &Packet{Class: class, Constructed: true, Tag: tag, Children: children}
+}
+
+// NewSequence creates a new universal SEQUENCE packet.
+func NewSequence(children ...*Packet) *Packet {
+	return NewConstructed(ClassUniversal, TagSequence, children...)
+}
+
+// NewPrimitive creates a new primitive packet with the provided raw value.
+func NewPrimitive(class byte, tag int, value []byte) *Packet {
+	return &Packet{Class: class, Tag: tag, Value: value}
+}
+
+// NewString creates a new primitive packet with the provided string value.
+func NewString(class byte, tag int, value string) *Packet {
+	return NewPrimitive(class, tag, []byte(value))
+}
+
+// NewInteger creates a new primitive packet with the two's complement
+// encoded integer value.
+func NewInteger(class byte, tag int, value int64) *Packet {
+	result := []byte{byte(value)}
+
+	for v := value >> 8; ; v >>= 8 {
+		// stop when the remaining bytes are only the sign extension
+		if (v == 0 && result[0]&0x80 == 0) || (v == -1 && result[0]&0x80 != 0) {
+			break
+		}
+		result = append([]byte{byte(v)}, result...)
+	}
+
+	return NewPrimitive(class, tag, result)
+}
+
+// NewBoolean creates a new primitive packet with the provided boolean value.
+func NewBoolean(class byte, tag int, value bool) *Packet {
+	if value {
+		return NewPrimitive(class, tag, []byte{0xff})
+	}
+	return NewPrimitive(class, tag, []byte{0x00})
+}
+
+// Is checks whether the packet has the specified class and tag.
+func (p *Packet) Is(class byte, tag int) bool {
+	return p.Class == class && p.Tag == tag
+}
+
+// String returns the packet raw value as string.
+func (p *Packet) String() string {
+	return string(p.Value)
+}
+
+// Int decodes the packet two's complement integer value.
+func (p *Packet) Int() (int64, error) {
+	if p.Constructed || len(p.Value) == 0 || len(p.Value) > 8 {
+		return 0, errors.New("invalid integer packet")
+	}
+
+	// sign extend the first byte
+	result := int64(int8(p.Value[0]))
+	for _, b := range p.Value[1:] {
+		result = result<<8 | int64(b)
+	}
+
+	return result, nil
+}
+
+// Bool decodes the packet boolean value.
+func (p *Packet) Bool() (bool, error) {
+	if p.Constructed || len(p.Value) != 1 {
+		return false, errors.New("invalid boolean packet")
+	}
+
+	return p.Value[0] != 0x00, nil
+}
+
+// Bytes returns the BER encoded packet.
+func (p *Packet) Bytes() []byte {
+	identifier := p.Class | byte(p.Tag&0x1f)
+
+	content := p.Value
+	if p.Constructed {
+		identifier |= 0x20
+
+		content = []byte{}
+		for _, child := range p.Children {
+			content = append(content, child.Bytes()...)
+		}
+	}
+
+	result := []byte{identifier}
+	result = append(result, encodeLength(len(content))...)
+
+	return append(result, content...)
+}
+
+// ReadPacket reads and decodes a single BER packet from r.
+func ReadPacket(r io.Reader) (*Packet, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	raw := header
+	length := int(header[1])
+
+	if header[1]&0x80 != 0 {
+		numBytes := int(header[1] & 0x7f)
+		if numBytes == 0 || numBytes > 4 {
+			return nil, errors.New("unsupported BER length")
+		}
+
+		lengthBytes := make([]byte, numBytes)
+		if _, err := io.ReadFull(r, lengthBytes); err != nil {
+			return nil, err
+		}
+		raw = append(raw, lengthBytes...)
+
+		length = 0
+		for _, b := range lengthBytes {
+			length = length<<8 | int(b)
+		}
+	}
+
+	if length > MaxPacketSize {
+		return nil, fmt.Errorf("the BER packet size %d exceeds the max allowed %d", length, MaxPacketSize)
+	}
+
+	content := make([]byte, length)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return nil, err
+	}
+
+	packet, rest, err := parsePacket(append(raw, content...), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rest) > 0 {
+		return nil, errors.New("unexpected trailing BER data")
+	}
+
+	return packet, nil
+}
+
+// parsePacket decodes the first BER packet from data and
+// returns it together with the remaining data.
+func parsePacket(data []byte, depth int) (*Packet, []byte, error) {
+	if depth > maxPacketDepth {
+		return nil, nil, errors.New("the BER packet is too deeply nested")
+	}
+
+	if len(data) < 2 {
+		return nil, nil, errors.New("truncated BER packet")
+	}
+
+	identifier := data[0]
+	if identifier&0x1f == 0x1f {
+		return nil, nil, errors.New("unsupported BER high tag number")
+	}
+
+	packet := &Packet{
+		Class:       identifier & 0xc0,
+		Constructed: identifier&0x20 != 0,
+		Tag:         int(identifier & 0x1f),
+	}
+
+	offset := 2
+	length := int(data[1])
+
+	if data[1]&0x80 != 0 {
+		numBytes := int(data[1] & 0x7f)
+		if numBytes == 0 || numBytes > 4 || len(data) < 2+numBytes {
+			return nil, nil, errors.New("invalid BER length")
+		}
+
+		length = 0
+		for _, b := range data[2 : 2+numBytes] {
+			length = length<<8 | int(b)
+		}
+		offset += numBytes
+	}
+
+	if length < 0 || len(data)-offset < length {
+		return nil, nil, errors.New("truncated BER packet")
+	}
+
+	content := data[offset : offset+length]
+	rest := data[offset+length:]
+
+	if !packet.Constructed {
+		packet.Value = content
+		return packet, rest, nil
+	}
+
+	for len(content) > 0 {
+		child, childRest, err := parsePacket(content, depth+1)
+		if err != nil {
+			return nil, nil, err
+		}
+		packet.Children = append(packet.Children, child)
+		content = childRest
+	}
+
+	return packet, rest, nil
+}
+
+// encodeLength returns the BER definite form of the provided length.
+func encodeLength(length int) []byte {
+	if length < 0x80 {
+		return []byte{byte(length)}
+	}
+
+	result := []byte{}
+	for v := length; v > 0; v >>= 8 {
+		result = append([]byte{byte(v)}, result...)
+	}
+
+	return append([]byte{0x80 | byte(len(result))}, result...)
+}
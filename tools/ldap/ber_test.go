@@ -0,0 +1,102 @@
+package ldap_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/ldap"
+)
+
+func TestNewInteger(t *testing.T) {
+	scenarios := []struct {
+		value    int64
+		expected string
+	}{
+		{0, "020100"},
+		{1, "020101"},
+		{127, "02017f"},
+		{128, "02020080"},
+		{256, "02020100"},
+		{-1, "0201ff"},
+		{-128, "020180"},
+		{-129, "0202ff7f"},
+	}
+
+	for _, s := range scenarios {
+		packet := ldap.NewInteger(ldap.ClassUniversal, ldap.TagInteger, s.value)
+
+		encoded := hex.EncodeToString(packet.Bytes())
+		if encoded != s.expected {
+			t.Errorf("(%d) Expected %s, got %s", s.value, s.expected, encoded)
+			continue
+		}
+
+		decoded, err := packet.Int()
+		if err != nil || decoded != s.value {
+			t.Errorf("(%d) Expected the same decoded value, got %d (%v)", s.value, decoded, err)
+		}
+	}
+}
+
+func TestPacketRoundtrip(t *testing.T) {
+	packet := ldap.NewSequence(
+		ldap.NewInteger(ldap.ClassUniversal, ldap.TagInteger, 5),
+		ldap.NewConstructed(
+			ldap.ClassApplication,
+			3,
+			ldap.NewString(ldap.ClassUniversal, ldap.TagOctetString, string(bytes.Repeat([]byte("a"), 300))),
+			ldap.NewBoolean(ldap.ClassUniversal, ldap.TagBoolean, true),
+			ldap.NewString(ldap.ClassContext, 7, "objectClass"),
+		),
+	)
+
+	encoded := packet.Bytes()
+
+	decoded, err := ldap.ReadPacket(bytes.NewReader(encoded))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(decoded.Bytes(), encoded) {
+		t.Fatalf("Expected the decoded packet to match the original one")
+	}
+
+	op := decoded.Children[1]
+	if !op.Is(ldap.ClassApplication, 3) || !op.Constructed || len(op.Children) != 3 {
+		t.Fatalf("Expected constructed application 3 packet with 3 children, got %v", op)
+	}
+
+	if v := op.Children[0].String(); len(v) != 300 {
+		t.Fatalf("Expected 300 characters long string, got %d", len(v))
+	}
+
+	if v, err := op.Children[1].Bool(); err != nil || !v {
+		t.Fatalf("Expected true boolean, got %v (%v)", v, err)
+	}
+
+	if !op.Children[2].Is(ldap.ClassContext, 7) || op.Children[2].String() != "objectClass" {
+		t.Fatalf("Expected context 7 objectClass packet, got %v", op.Children[2])
+	}
+}
+
+func TestReadPacketInvalid(t *testing.T) {
+	scenarios := []string{
+		"",
+		"30",
+		"3005020101",     // truncated content
+		"3f0100",         // high tag number
+		"3080",           // indefinite length
+		"30850100000000", // too long length
+		"3003020201",     // truncated child
+		"308401000000",   // exceeds the max size
+	}
+
+	for _, s := range scenarios {
+		raw, _ := hex.DecodeString(s)
+
+		if _, err := ldap.ReadPacket(bytes.NewReader(raw)); err == nil {
+			t.Errorf("(%s) Expected error, got nil", s)
+		}
+	}
+}
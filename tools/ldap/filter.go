@@ -0,0 +1,271 @@
+package ldap
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// search filter choice tags (RFC 4511 section 4.5.1.7)
+const (
+	FilterAnd            = 0
+	FilterOr             = 1
+	FilterNot            = 2
+	FilterEqualityMatch  = 3
+	FilterSubstrings     = 4
+	FilterGreaterOrEqual = 5
+	FilterLessOrEqual    = 6
+	FilterPresent        = 7
+	FilterApproxMatch    = 8
+)
+
+// substrings filter item tags
+const (
+	SubstringInitial = 0
+	SubstringAny     = 1
+	SubstringFinal   = 2
+)
+
+// maxFilterDepth is the max allowed nesting level of the search filters.
+const maxFilterDepth = 10
+
+// EscapeFilter escapes the special characters of the provided
+// search filter assertion value (RFC 4515).
+//
+// Example:
+//
+//	EscapeFilter("a*(b)") // "a\2a\28b\29"
+func EscapeFilter(value string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '\\', c == '*', c == '(', c == ')', c == 0x00, c >= 0x80:
+			sb.WriteString(fmt.Sprintf(`\%02x`, c))
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return sb.String()
+}
+
+// CompileFilter compiles the string representation of a search
+// filter (RFC 4515) into its BER packet.
+//
+// Example:
+//
+//	CompileFilter("(&(objectClass=person)(|(uid=test)(mail=test*)))")
+func CompileFilter(filter string) (*Packet, error) {
+	packet, rest, err := compileFilter(filter, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if rest != "" {
+		return nil, fmt.Errorf("unexpected trailing filter characters %q", rest)
+	}
+
+	return packet, nil
+}
+
+func compileFilter(filter string, depth int) (*Packet, string, error) {
+	if depth > maxFilterDepth {
+		return nil, "", errors.New("the filter is too deeply nested")
+	}
+
+	if !strings.HasPrefix(filter, "(") {
+		return nil, "", errors.New("missing filter opening parenthesis")
+	}
+	filter = filter[1:]
+
+	if filter == "" {
+		return nil, "", errors.New("unexpected filter end")
+	}
+
+	switch filter[0] {
+	case '&', '|':
+		tag := FilterAnd
+		if filter[0] == '|' {
+			tag = FilterOr
+		}
+
+		packet := NewConstructed(ClassContext, tag)
+		rest := filter[1:]
+
+		for !strings.HasPrefix(rest, ")") {
+			child, childRest, err := compileFilter(rest, depth+1)
+			if err != nil {
+				return nil, "", err
+			}
+			packet.Children = append(packet.Children, child)
+			rest = childRest
+		}
+
+		if len(packet.Children) == 0 {
+			return nil, "", errors.New("empty filter set")
+		}
+
+		return packet, rest[1:], nil
+	case '!':
+		child, rest, err := compileFilter(filter[1:], depth+1)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if !strings.HasPrefix(rest, ")") {
+			return nil, "", errors.New("missing filter closing parenthesis")
+		}
+
+		return NewConstructed(ClassContext, FilterNot, child), rest[1:], nil
+	}
+
+	end := strings.IndexByte(filter, ')')
+	if end < 0 {
+		return nil, "", errors.New("missing filter closing parenthesis")
+	}
+
+	packet, err := compileFilterItem(filter[:end])
+	if err != nil {
+		return nil, "", err
+	}
+
+	return packet, filter[end+1:], nil
+}
+
+// compileFilterItem compiles a single "attr op value" filter item.
+func compileFilterItem(item string) (*Packet, error) {
+	eqIndex := strings.IndexByte(item, '=')
+	if eqIndex <= 0 {
+		return nil, fmt.Errorf("invalid filter item %q", item)
+	}
+
+	attr := item[:eqIndex]
+	rawValue := item[eqIndex+1:]
+
+	tag := FilterEqualityMatch
+	switch attr[len(attr)-1] {
+	case '>':
+		tag = FilterGreaterOrEqual
+	case '<':
+		tag = FilterLessOrEqual
+	case '~':
+		tag = FilterApproxMatch
+	}
+	if tag != FilterEqualityMatch {
+		attr = attr[:len(attr)-1]
+	}
+
+	if !isValidAttributeName(attr) {
+		return nil, fmt.Errorf("invalid filter attribute %q", attr)
+	}
+
+	if tag == FilterEqualityMatch && rawValue == "*" {
+		return NewString(ClassContext, FilterPresent, attr), nil
+	}
+
+	parts := strings.Split(rawValue, "*")
+
+	if tag != FilterEqualityMatch || len(parts) == 1 {
+		if len(parts) > 1 {
+			return nil, fmt.Errorf("invalid wildcard in filter item %q", item)
+		}
+
+		value, err := unescapeFilterValue(rawValue)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewConstructed(
+			ClassContext,
+			tag,
+			NewString(ClassUniversal, TagOctetString, attr),
+			NewString(ClassUniversal, TagOctetString, value),
+		), nil
+	}
+
+	substrings := NewSequence()
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+
+		value, err := unescapeFilterValue(part)
+		if err != nil {
+			return nil, err
+		}
+
+		partTag := SubstringAny
+		if i == 0 {
+			partTag = SubstringInitial
+		} else if i == len(parts)-1 {
+			partTag = SubstringFinal
+		}
+
+		substrings.Children = append(substrings.Children, NewString(ClassContext, partTag, value))
+	}
+
+	if len(substrings.Children) == 0 {
+		return nil, fmt.Errorf("invalid wildcard in filter item %q", item)
+	}
+
+	return NewConstructed(
+		ClassContext,
+		FilterSubstrings,
+		NewString(ClassUniversal, TagOctetString, attr),
+		substrings,
+	), nil
+}
+
+// unescapeFilterValue decodes the "\XX" hex escaped filter value characters.
+func unescapeFilterValue(value string) (string, error) {
+	if !strings.Contains(value, `\`) {
+		if strings.ContainsAny(value, "()") {
+			return "", fmt.Errorf("unescaped special character in filter value %q", value)
+		}
+		return value, nil
+	}
+
+	var sb strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '(', ')':
+			return "", fmt.Errorf("unescaped special character in filter value %q", value)
+		case '\\':
+			if i+3 > len(value) {
+				return "", fmt.Errorf("invalid escape sequence in filter value %q", value)
+			}
+
+			decoded, err := hex.DecodeString(value[i+1 : i+3])
+			if err != nil {
+				return "", fmt.Errorf("invalid escape sequence in filter value %q", value)
+			}
+
+			sb.Write(decoded)
+			i += 2
+		default:
+			sb.WriteByte(value[i])
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// isValidAttributeName checks whether name is a valid attribute
+// description (aka. a descriptor or a numeric oid with optional options).
+func isValidAttributeName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '.' || c == ';') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,87 @@
+package ldap_test
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/ldap"
+)
+
+func TestEscapeFilter(t *testing.T) {
+	scenarios := []struct {
+		value    string
+		expected string
+	}{
+		{"", ""},
+		{"test", "test"},
+		{`a*(b)\c`, `a\2a\28b\29\5cc`},
+		{"a\x00b", `a\00b`},
+		{"ä", `\c3\a4`},
+	}
+
+	for _, s := range scenarios {
+		result := ldap.EscapeFilter(s.value)
+		if result != s.expected {
+			t.Errorf("(%q) Expected %q, got %q", s.value, s.expected, result)
+		}
+	}
+}
+
+func TestCompileFilter(t *testing.T) {
+	scenarios := []struct {
+		filter      string
+		expected    string
+		expectError bool
+	}{
+		{"", "", true},
+		{"uid=test", "", true},
+		{"(uid=test", "", true},
+		{"(uid=test))", "", true},
+		{"(=test)", "", true},
+		{"(u id=test)", "", true},
+		{"(uid=te(st)", "", true},
+		{`(uid=\zz)`, "", true},
+		{`(uid=\a)`, "", true},
+		{"(uid>=te*st)", "", true},
+		{"(uid=**)", "", true},
+		{"(&)", "", true},
+		{"(!(uid=a)(uid=b))", "", true},
+		{strings.Repeat("(!", 12) + "(uid=a)" + strings.Repeat(")", 12), "", true},
+		// equalityMatch
+		{"(uid=test)", "a30b0403756964040474657374", false},
+		// escaped value
+		{`(uid=a\2ab)`, "a30a04037569640403612a62", false},
+		// present
+		{"(uid=*)", "8703756964", false},
+		// greaterOrEqual and lessOrEqual
+		{"(age>=5)", "a5080403616765040135", false},
+		{"(age<=5)", "a6080403616765040135", false},
+		// approxMatch
+		{"(cn~=a)", "a8070402636e040161", false},
+		// substrings
+		{"(cn=a*b*c)", "a40f0402636e3009800161810162820163", false},
+		{"(cn=*b)", "a4090402636e3003820162", false},
+		// and, or, not
+		{"(&(a=1)(|(b=2)(!(c=3))))", "a01ca306040161040131a112a306040162040132a208a306040163040133", false},
+	}
+
+	for _, s := range scenarios {
+		packet, err := ldap.CompileFilter(s.filter)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%s) Expected hasErr %v, got %v (%v)", s.filter, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		encoded := hex.EncodeToString(packet.Bytes())
+		if encoded != s.expected {
+			t.Errorf("(%s) Expected %s, got %s", s.filter, s.expected, encoded)
+		}
+	}
+}
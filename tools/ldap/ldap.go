@@ -0,0 +1,367 @@
+// Package ldap implements a minimal LDAPv3 client (RFC 4511) with
+// support only for the simple bind and search operations.
+package ldap
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout is the default connection and single operation timeout.
+const DefaultTimeout = 10 * time.Second
+
+// protocol operation tags (RFC 4511 section 4.2-4.5)
+const (
+	ApplicationBindRequest           = 0
+	ApplicationBindResponse          = 1
+	ApplicationUnbindRequest         = 2
+	ApplicationSearchRequest         = 3
+	ApplicationSearchResultEntry     = 4
+	ApplicationSearchResultDone      = 5
+	ApplicationSearchResultReference = 19
+)
+
+// common LDAP result codes
+const (
+	ResultSuccess            = 0
+	ResultSizeLimitExceeded  = 4
+	ResultNoSuchObject       = 32
+	ResultInvalidCredentials = 49
+	ResultUnwillingToPerform = 53
+)
+
+// search request scopes
+const (
+	ScopeBaseObject   = 0
+	ScopeSingleLevel  = 1
+	ScopeWholeSubtree = 2
+)
+
+// Error defines a LDAP operation result error.
+type Error struct {
+	ResultCode int
+	Message    string
+}
+
+// Error implements the [error] interface.
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("LDAP result code %d", e.ResultCode)
+	}
+
+	return fmt.Sprintf("LDAP result code %d: %s", e.ResultCode, e.Message)
+}
+
+// IsResultCode checks whether err is a LDAP [Error] with the specified result code.
+func IsResultCode(err error, code int) bool {
+	var ldapErr *Error
+
+	return errors.As(err, &ldapErr) && ldapErr.ResultCode == code
+}
+
+// Entry defines a single search result entry.
+type Entry struct {
+	Dn         string
+	Attributes map[string][]string
+}
+
+// Attribute returns the first value of the specified
+// attribute (the name is case-insensitive).
+func (e *Entry) Attribute(name string) string {
+	values := e.AttributeValues(name)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// AttributeValues returns all values of the specified
+// attribute (the name is case-insensitive).
+func (e *Entry) AttributeValues(name string) []string {
+	for k, v := range e.Attributes {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// SearchRequest defines the search operation parameters.
+type SearchRequest struct {
+	BaseDn     string
+	Scope      int
+	Filter     string
+	Attributes []string
+
+	// SizeLimit is the max number of returned entries (0 means no limit).
+	SizeLimit int
+}
+
+// Conn defines a single LDAP server connection.
+type Conn struct {
+	conn      net.Conn
+	timeout   time.Duration
+	messageId int64
+}
+
+// Dial connects to the LDAP server at the specified "ldap://host[:port]"
+// or "ldaps://host[:port]" url.
+//
+// tlsConfig is optional and it is used only for the ldaps connections.
+func Dial(rawUrl string, tlsConfig *tls.Config) (*Conn, error) {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Hostname() == "" {
+		return nil, errors.New("missing LDAP server host")
+	}
+
+	dialer := &net.Dialer{Timeout: DefaultTimeout}
+
+	var conn net.Conn
+
+	switch strings.ToLower(u.Scheme) {
+	case "ldap":
+		conn, err = dialer.Dial("tcp", hostWithPort(u, "389"))
+	case "ldaps":
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+		if tlsConfig.ServerName == "" {
+			tlsConfig = tlsConfig.Clone()
+			tlsConfig.ServerName = u.Hostname()
+		}
+		conn, err = tls.DialWithDialer(dialer, "tcp", hostWithPort(u, "636"), tlsConfig)
+	default:
+		return nil, fmt.Errorf("unsupported LDAP url scheme %q", u.Scheme)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewConn(conn), nil
+}
+
+// NewConn creates a new LDAP connection from an already established one.
+func NewConn(conn net.Conn) *Conn {
+	return &Conn{conn: conn, timeout: DefaultTimeout}
+}
+
+// SetTimeout changes the default single operation timeout.
+func (c *Conn) SetTimeout(timeout time.Duration) {
+	c.timeout = timeout
+}
+
+// Close sends an unbind request and closes the connection.
+func (c *Conn) Close() error {
+	// the unbind request has no response so the error is ignored
+	c.send(NewPrimitive(ClassApplication, ApplicationUnbindRequest, nil))
+
+	return c.conn.Close()
+}
+
+// Bind performs a simple bind (aka. authenticates the connection)
+// with the provided distinguished name and password.
+//
+// Empty password is not allowed because most servers treat it
+// as an unauthenticated (aka. successful anonymous) bind.
+func (c *Conn) Bind(dn string, password string) error {
+	if password == "" {
+		return &Error{ResultCode: ResultUnwillingToPerform, Message: "empty password"}
+	}
+
+	return c.bind(dn, password)
+}
+
+// AnonymousBind performs an anonymous simple bind.
+func (c *Conn) AnonymousBind() error {
+	return c.bind("", "")
+}
+
+func (c *Conn) bind(dn string, password string) error {
+	messageId, err := c.send(NewConstructed(
+		ClassApplication,
+		ApplicationBindRequest,
+		NewInteger(ClassUniversal, TagInteger, 3),
+		NewString(ClassUniversal, TagOctetString, dn),
+		NewString(ClassContext, 0, password),
+	))
+	if err != nil {
+		return err
+	}
+
+	op, err := c.receive(messageId)
+	if err != nil {
+		return err
+	}
+
+	if !op.Is(ClassApplication, ApplicationBindResponse) {
+		return fmt.Errorf("unexpected LDAP bind response tag %d", op.Tag)
+	}
+
+	return resultError(op)
+}
+
+// Search performs a search operation and returns the found entries
+// (the search result references are ignored).
+func (c *Conn) Search(req *SearchRequest) ([]*Entry, error) {
+	filter, err := CompileFilter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := NewSequence()
+	for _, attr := range req.Attributes {
+		attributes.Children = append(attributes.Children, NewString(ClassUniversal, TagOctetString, attr))
+	}
+
+	messageId, err := c.send(NewConstructed(
+		ClassApplication,
+		ApplicationSearchRequest,
+		NewString(ClassUniversal, TagOctetString, req.BaseDn),
+		NewInteger(ClassUniversal, TagEnumerated, int64(req.Scope)),
+		NewInteger(ClassUniversal, TagEnumerated, 0), // never deref aliases
+		NewInteger(ClassUniversal, TagInteger, int64(req.SizeLimit)),
+		NewInteger(ClassUniversal, TagInteger, int64(c.timeout/time.Second)),
+		NewBoolean(ClassUniversal, TagBoolean, false),
+		filter,
+		attributes,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []*Entry{}
+
+	for {
+		op, err := c.receive(messageId)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case op.Is(ClassApplication, ApplicationSearchResultEntry):
+			entry, err := parseEntry(op)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+		case op.Is(ClassApplication, ApplicationSearchResultReference):
+			// not followed
+		case op.Is(ClassApplication, ApplicationSearchResultDone):
+			return entries, resultError(op)
+		default:
+			return nil, fmt.Errorf("unexpected LDAP search response tag %d", op.Tag)
+		}
+	}
+}
+
+// send writes the protocol operation as a new LDAP message
+// and returns its message id.
+func (c *Conn) send(op *Packet) (int64, error) {
+	c.messageId++
+
+	message := NewSequence(
+		NewInteger(ClassUniversal, TagInteger, c.messageId),
+		op,
+	)
+
+	if err := c.conn.SetWriteDeadline(time.Now().Add(c.timeout)); err != nil {
+		return 0, err
+	}
+
+	if _, err := c.conn.Write(message.Bytes()); err != nil {
+		return 0, err
+	}
+
+	return c.messageId, nil
+}
+
+// receive reads the next LDAP message and returns its protocol operation.
+func (c *Conn) receive(messageId int64) (*Packet, error) {
+	if err := c.conn.SetReadDeadline(time.Now().Add(c.timeout)); err != nil {
+		return nil, err
+	}
+
+	message, err := ReadPacket(c.conn)
+	if err != nil {
+		return nil, err
+	}
+
+	if !message.Is(ClassUniversal, TagSequence) || len(message.Children) < 2 {
+		return nil, errors.New("invalid LDAP message")
+	}
+
+	id, err := message.Children[0].Int()
+	if err != nil {
+		return nil, err
+	}
+
+	if id != messageId {
+		return nil, fmt.Errorf("unexpected LDAP message id %d (expected %d)", id, messageId)
+	}
+
+	return message.Children[1], nil
+}
+
+// resultError returns the LDAPResult operation error (if any).
+func resultError(op *Packet) error {
+	if len(op.Children) < 3 {
+		return errors.New("invalid LDAP result")
+	}
+
+	code, err := op.Children[0].Int()
+	if err != nil {
+		return err
+	}
+
+	if code == ResultSuccess {
+		return nil
+	}
+
+	return &Error{ResultCode: int(code), Message: op.Children[2].String()}
+}
+
+// parseEntry parses a search result entry operation.
+func parseEntry(op *Packet) (*Entry, error) {
+	if len(op.Children) < 2 {
+		return nil, errors.New("invalid LDAP search result entry")
+	}
+
+	entry := &Entry{
+		Dn:         op.Children[0].String(),
+		Attributes: map[string][]string{},
+	}
+
+	for _, attr := range op.Children[1].Children {
+		if len(attr.Children) < 2 {
+			return nil, errors.New("invalid LDAP search result entry attribute")
+		}
+
+		name := attr.Children[0].String()
+		for _, value := range attr.Children[1].Children {
+			entry.Attributes[name] = append(entry.Attributes[name], value.String())
+		}
+	}
+
+	return entry, nil
+}
+
+// hostWithPort returns the url host with the default port if missing.
+func hostWithPort(u *url.URL, defaultPort string) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+
+	return net.JoinHostPort(u.Hostname(), defaultPort)
+}
@@ -0,0 +1,165 @@
+package ldap_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/ldap"
+)
+
+func TestDialInvalidUrl(t *testing.T) {
+	scenarios := []string{
+		"",
+		"ldap://",
+		"http://127.0.0.1",
+		"ldap://127.0.0.1:1",
+	}
+
+	for _, s := range scenarios {
+		if conn, err := ldap.Dial(s, nil); err == nil {
+			conn.Close()
+			t.Errorf("(%s) Expected error, got nil", s)
+		}
+	}
+}
+
+func TestConnBindAndSearch(t *testing.T) {
+	server, err := tests.NewTestLdapServer(
+		&tests.TestLdapEntry{
+			Dn:       "uid=test1,ou=people,dc=example,dc=com",
+			Password: "123456",
+			Attributes: map[string][]string{
+				"uid":  {"test1"},
+				"mail": {"test1@example.com"},
+				"cn":   {"Test 1"},
+			},
+		},
+		&tests.TestLdapEntry{
+			Dn:       "uid=test2,ou=people,dc=example,dc=com",
+			Password: "654321",
+			Attributes: map[string][]string{
+				"uid":         {"test2"},
+				"memberOf":    {"admins", "users"},
+				"objectClass": {"person"},
+			},
+		},
+		&tests.TestLdapEntry{
+			Dn: "uid=test3,ou=other,dc=example,dc=com",
+			Attributes: map[string][]string{
+				"uid": {"test3"},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	conn, err := ldap.Dial(server.Url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// empty password
+	if err := conn.Bind("uid=test3,ou=other,dc=example,dc=com", ""); !ldap.IsResultCode(err, ldap.ResultUnwillingToPerform) {
+		t.Fatalf("Expected empty password bind error, got %v", err)
+	}
+
+	// invalid password
+	if err := conn.Bind("uid=test1,ou=people,dc=example,dc=com", "invalid"); !ldap.IsResultCode(err, ldap.ResultInvalidCredentials) {
+		t.Fatalf("Expected invalid credentials error, got %v", err)
+	}
+
+	if err := conn.Bind("uid=test1,ou=people,dc=example,dc=com", "123456"); err != nil {
+		t.Fatalf("Expected successful bind, got %v", err)
+	}
+
+	if err := conn.AnonymousBind(); err != nil {
+		t.Fatalf("Expected successful anonymous bind, got %v", err)
+	}
+
+	scenarios := []struct {
+		request     *ldap.SearchRequest
+		expectedDns []string
+		expectError bool
+	}{
+		{
+			&ldap.SearchRequest{BaseDn: "dc=example,dc=com", Filter: "invalid"},
+			nil,
+			true,
+		},
+		{
+			&ldap.SearchRequest{BaseDn: "dc=example,dc=com", Filter: "(uid=missing)"},
+			[]string{},
+			false,
+		},
+		{
+			&ldap.SearchRequest{BaseDn: "dc=example,dc=com", Filter: "(uid=" + ldap.EscapeFilter("test*") + ")"},
+			[]string{},
+			false,
+		},
+		{
+			&ldap.SearchRequest{BaseDn: "ou=people,dc=example,dc=com", Filter: "(uid=test*)"},
+			[]string{"uid=test1,ou=people,dc=example,dc=com", "uid=test2,ou=people,dc=example,dc=com"},
+			false,
+		},
+		{
+			&ldap.SearchRequest{BaseDn: "dc=example,dc=com", Filter: "(&(uid=*)(!(objectClass=person)))"},
+			[]string{"uid=test1,ou=people,dc=example,dc=com", "uid=test3,ou=other,dc=example,dc=com"},
+			false,
+		},
+		{
+			&ldap.SearchRequest{BaseDn: "dc=example,dc=com", Filter: "(uid=*)", SizeLimit: 1},
+			nil,
+			true,
+		},
+	}
+
+	for i, s := range scenarios {
+		entries, err := conn.Search(s.request)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		if len(entries) != len(s.expectedDns) {
+			t.Errorf("(%d) Expected %d entries, got %d", i, len(s.expectedDns), len(entries))
+			continue
+		}
+
+		for j, entry := range entries {
+			if entry.Dn != s.expectedDns[j] {
+				t.Errorf("(%d) Expected entry %d dn %q, got %q", i, j, s.expectedDns[j], entry.Dn)
+			}
+		}
+	}
+
+	entries, err := conn.Search(&ldap.SearchRequest{
+		BaseDn:     "dc=example,dc=com",
+		Scope:      ldap.ScopeWholeSubtree,
+		Filter:     "(uid=test2)",
+		Attributes: []string{"memberOf"},
+	})
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("Expected single entry, got %v (%v)", entries, err)
+	}
+
+	if v := entries[0].Attribute("MEMBEROF"); v != "admins" {
+		t.Fatalf("Expected the first memberOf value admins, got %q", v)
+	}
+
+	if v := entries[0].AttributeValues("memberof"); len(v) != 2 {
+		t.Fatalf("Expected 2 memberOf values, got %v", v)
+	}
+
+	if v := entries[0].Attribute("missing"); v != "" {
+		t.Fatalf("Expected empty missing attribute value, got %q", v)
+	}
+}
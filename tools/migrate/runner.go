@@ -1,7 +1,10 @@
 package migrate
 
 import (
+	"errors"
 	"fmt"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/AlecAivazis/survey/v2"
@@ -14,17 +17,20 @@ const DefaultMigrationsTable = "_migrations"
 
 // Runner defines a simple struct for managing the execution of db migrations.
 type Runner struct {
-	db             *dbx.DB
-	migrationsList MigrationsList
-	tableName      string
+	db                 *dbx.DB
+	migrationsList     MigrationsList
+	tableName          string
+	snapshotsTableName string
+	snapshotsDisabled  bool
 }
 
 // NewRunner creates and initializes a new db migrations Runner instance.
 func NewRunner(db *dbx.DB, migrationsList MigrationsList) (*Runner, error) {
 	runner := &Runner{
-		db:             db,
-		migrationsList: migrationsList,
-		tableName:      DefaultMigrationsTable,
+		db:                 db,
+		migrationsList:     migrationsList,
+		tableName:          DefaultMigrationsTable,
+		snapshotsTableName: DefaultSnapshotsTable,
 	}
 
 	if err := runner.createMigrationsTable(); err != nil {
@@ -34,11 +40,21 @@ func NewRunner(db *dbx.DB, migrationsList MigrationsList) (*Runner, error) {
 	return runner, nil
 }
 
+// DisableSnapshots disables (or enables back) the automatic
+// pre-rollback tables snapshots (enabled by default).
+func (r *Runner) DisableSnapshots(disable bool) {
+	r.snapshotsDisabled = disable
+}
+
 // Run interactively executes the current runner with the provided args.
 //
 // The following commands are supported:
-// - up       - applies all migrations
-// - down [n] - reverts the last n applied migrations
+// - up                - applies all migrations
+// - down [n]          - reverts the last n applied migrations
+// - down --to=NAME    - reverts all applied migrations after the NAME one
+// - down --no-snapshot - reverts without creating pre-rollback tables snapshots
+// - restore           - restores the last rollback snapshot
+// - verify            - checks whether the applied migrations are reversible
 func (r *Runner) Run(args ...string) error {
 	cmd := "up"
 	if len(args) > 0 {
@@ -64,17 +80,51 @@ func (r *Runner) Run(args ...string) error {
 		return nil
 	case "down":
 		toRevertCount := 1
-		if len(args) > 1 {
-			toRevertCount = cast.ToInt(args[1])
-			if toRevertCount < 0 {
-				// revert all applied migrations
-				toRevertCount = len(r.migrationsList.Items())
+		toFile := ""
+		for _, arg := range args[1:] {
+			switch {
+			case strings.HasPrefix(arg, "--to="):
+				toFile = strings.TrimPrefix(arg, "--to=")
+			case arg == "--no-snapshot":
+				r.DisableSnapshots(true)
+			default:
+				toRevertCount = cast.ToInt(arg)
+				if toRevertCount < 0 {
+					// revert all applied migrations
+					toRevertCount = len(r.migrationsList.Items())
+				}
 			}
 		}
 
+		var toRevert []*Migration
+		if toFile != "" {
+			var err error
+			toRevert, err = r.appliedAfter(r.db, toFile)
+			if err != nil {
+				color.Red(err.Error())
+				return err
+			}
+		} else {
+			toRevert = r.lastApplied(r.db, toRevertCount)
+		}
+
+		if len(toRevert) == 0 {
+			color.Green("No migrations to revert.")
+			return nil
+		}
+
+		irreversible, err := r.verifyReversible(toRevert)
+		if err != nil {
+			color.Red(err.Error())
+			return err
+		}
+		for _, file := range irreversible {
+			color.Yellow("Migration %s doesn't seem to be reversible (its Down action doesn't revert all of its Up schema changes).", file)
+		}
+
 		confirm := false
 		prompt := &survey.Confirm{
-			Message: fmt.Sprintf("Do you really want to revert the last %d applied migration(s)?", toRevertCount),
+			Message: fmt.Sprintf("Do you really want to revert the last %d applied migration(s)?", len(toRevert)),
 		}
 		survey.AskOne(prompt, &confirm)
 		if !confirm {
@@ -82,7 +132,12 @@ func (r *Runner) Run(args ...string) error {
 			return nil
 		}
 
-		reverted, err := r.Down(toRevertCount)
+		var reverted []string
+		if toFile != "" {
+			reverted, err = r.DownTo(toFile)
+		} else {
+			reverted, err = r.Down(toRevertCount)
+		}
 		if err != nil {
 			color.Red(err.Error())
 			return err
@@ -97,6 +152,43 @@ func (r *Runner) Run(args ...string) error {
 		}
 
 		return nil
+	case "restore":
+		confirm := false
+		prompt := &survey.Confirm{
+			Message: "Do you really want to restore the last rollback snapshot?",
+		}
+		survey.AskOne(prompt, &confirm)
+		if !confirm {
+			fmt.Println("The command has been cancelled")
+			return nil
+		}
+
+		restored, err := r.RestoreLastSnapshot()
+		if err != nil {
+			color.Red(err.Error())
+			return err
+		}
+
+		color.Green("Restored %s", restored)
+
+		return nil
+	case "verify":
+		irreversible, err := r.VerifyReversible()
+		if err != nil {
+			color.Red(err.Error())
+			return err
+		}
+
+		if len(irreversible) == 0 {
+			color.Green("All applied migrations are reversible.")
+			return nil
+		}
+
+		for _, file := range irreversible {
+			color.Yellow("Irreversible %s", file)
+		}
+
+		return fmt.Errorf("Found %d irreversible migration(s).", len(irreversible))
 	default:
 		return fmt.Errorf("Unsupported command: %q\n", cmd)
 	}
@@ -140,30 +232,117 @@ func (r *Runner) Up() ([]string, error) {
 
 // Down reverts the last `toRevertCount` applied migrations.
 //
+// Unless disabled with [Runner.DisableSnapshots], a snapshot of the
+// tables affected by each rollback is stored in the db and could be
+// restored with [Runner.RestoreLastSnapshot].
+//
 // On success returns list with the reverted migrations file names.
 func (r *Runner) Down(toRevertCount int) ([]string, error) {
-	reverted := make([]string, 0, toRevertCount)
+	return r.revert(func(tx dbx.Builder) ([]*Migration, error) {
+		return r.lastApplied(tx, toRevertCount), nil
+	})
+}
+
+// DownTo reverts all applied migrations after the specified one
+// (the file name could be also provided without its extension).
+//
+// The same as [Runner.Down], a snapshot of the affected tables
+// is stored before each rollback (unless disabled).
+//
+// On success returns list with the reverted migrations file names.
+func (r *Runner) DownTo(file string) ([]string, error) {
+	return r.revert(func(tx dbx.Builder) ([]*Migration, error) {
+		return r.appliedAfter(tx, file)
+	})
+}
+
+// VerifyReversible checks whether the Down action of each applied
+// migration reverts the db schema changes of its Up action
+// (eg. the collection tables and indexes).
+//
+// The check is performed in a transaction that is always rolled back.
+//
+// Returns list with the irreversible migrations file names.
+func (r *Runner) VerifyReversible() ([]string, error) {
+	return r.verifyReversible(r.lastApplied(r.db, len(r.migrationsList.Items())))
+}
+
+// errVerifyRollback is used to rollback the verification transaction.
+var errVerifyRollback = errors.New("verify rollback")
+
+// verifyReversible checks the provided applied migrations
+// (expected to be sorted from the most recent to the oldest).
+func (r *Runner) verifyReversible(applied []*Migration) ([]string, error) {
+	irreversible := []string{}
 
 	err := r.db.Transactional(func(tx *dbx.Tx) error {
-		for i := len(r.migrationsList.Items()) - 1; i >= 0; i-- {
-			m := r.migrationsList.Item(i)
+		for _, m := range applied {
+			before, err := r.tablesState(tx)
+			if err != nil {
+				return err
+			}
 
-			// skip unapplied
-			if !r.isMigrationApplied(tx, m.File) {
-				continue
+			if m.Down != nil {
+				if err := m.Down(tx); err != nil {
+					return fmt.Errorf("Failed to revert migration %s: %w", m.File, err)
+				}
 			}
 
-			// revert limit reached
-			if toRevertCount-len(reverted) <= 0 {
-				break
+			if m.Up != nil {
+				if err := m.Up(tx); err != nil {
+					// the Down action doesn't revert the Up changes (eg. a missing Down table drop)
+					irreversible = append(irreversible, m.File)
+					continue
+				}
+			}
+
+			after, err := r.tablesState(tx)
+			if err != nil {
+				return err
 			}
 
-			// ignore empty Down action
+			if !sameTablesState(before, after) {
+				irreversible = append(irreversible, m.File)
+			}
+
+			// revert again to verify the previous migrations
 			if m.Down != nil {
 				if err := m.Down(tx); err != nil {
 					return fmt.Errorf("Failed to revert migration %s: %w", m.File, err)
 				}
 			}
+		}
+
+		return errVerifyRollback
+	})
+
+	if err != nil && !errors.Is(err, errVerifyRollback) {
+		return nil, err
+	}
+	return irreversible, nil
+}
+
+func (r *Runner) revert(toRevertFunc func(tx dbx.Builder) ([]*Migration, error)) ([]string, error) {
+	reverted := []string{}
+
+	err := r.db.Transactional(func(tx *dbx.Tx) error {
+		toRevert, err := toRevertFunc(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range toRevert {
+			if r.snapshotsDisabled {
+				// ignore empty Down action
+				if m.Down != nil {
+					err = m.Down(tx)
+				}
+			} else {
+				err = r.revertWithSnapshot(tx, m)
+			}
+			if err != nil {
+				return fmt.Errorf("Failed to revert migration %s: %w", m.File, err)
+			}
 
 			if err := r.saveRevertedMigration(tx, m.File); err != nil {
 				return fmt.Errorf("Failed to save reverted migration info for %s: %w", m.File, err)
@@ -181,6 +360,48 @@ func (r *Runner) Down(toRevertCount int) ([]string, error) {
 	return reverted, nil
 }
 
+// lastApplied returns the last `limit` applied migrations
+// (sorted from the most recent to the oldest).
+func (r *Runner) lastApplied(db dbx.Builder, limit int) []*Migration {
+	result := []*Migration{}
+
+	for i := len(r.migrationsList.Items()) - 1; i >= 0 && len(result) < limit; i-- {
+		m := r.migrationsList.Item(i)
+
+		// skip unapplied
+		if !r.isMigrationApplied(db, m.File) {
+			continue
+		}
+
+		result = append(result, m)
+	}
+
+	return result
+}
+
+// appliedAfter returns the applied migrations registered after the
+// specified one (sorted from the most recent to the oldest).
+func (r *Runner) appliedAfter(db dbx.Builder, file string) ([]*Migration, error) {
+	result := []*Migration{}
+
+	for i := len(r.migrationsList.Items()) - 1; i >= 0; i-- {
+		m := r.migrationsList.Item(i)
+
+		if m.File == file || strings.TrimSuffix(m.File, filepath.Ext(m.File)) == file {
+			return result, nil
+		}
+
+		// skip unapplied
+		if !r.isMigrationApplied(db, m.File) {
+			continue
+		}
+
+		result = append(result, m)
+	}
+
+	return nil, fmt.Errorf("Missing migration %q.", file)
+}
+
 func (r *Runner) createMigrationsTable() error {
 	rawQuery := fmt.Sprintf(
 		"CREATE TABLE IF NOT EXISTS %v (file VARCHAR(255) PRIMARY KEY NOT NULL, applied INTEGER NOT NULL)",
@@ -0,0 +1,433 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+)
+
+// DefaultSnapshotsTable is the name of the table that stores the
+// info about the tables snapshots created before a migration rollback.
+const DefaultSnapshotsTable = "_migrationsSnapshots"
+
+// snapshotTablePrefix is the name prefix of the tables with the snapshot data.
+const snapshotTablePrefix = "_snapshot_"
+
+// Snapshot defines a single table snapshot created before a migration rollback.
+//
+// An empty SnapshotTable means that the table didn't exist before the
+// rollback (aka. it was created by the migration Down action).
+//
+// An empty TableName is used as a marker for a rollback without any affected tables.
+type Snapshot struct {
+	Id            string `db:"snapshot" json:"id"`
+	File          string `db:"file" json:"file"`
+	TableName     string `db:"tableName" json:"tableName"`
+	SnapshotTable string `db:"snapshotTable" json:"snapshotTable"`
+	TableSql      string `db:"tableSql" json:"tableSql"`
+	IndexesSql    string `db:"indexesSql" json:"indexesSql"`
+	Created       int64  `db:"created" json:"created"`
+}
+
+// Snapshots returns all stored rollback snapshots (the most recent first).
+func (r *Runner) Snapshots() ([]*Snapshot, error) {
+	if err := r.createSnapshotsTable(r.db); err != nil {
+		return nil, err
+	}
+
+	return r.findSnapshots(r.db, "")
+}
+
+// RestoreLastSnapshot restores the tables of the most recently
+// reverted migration to their pre-rollback state and marks
+// the migration as applied.
+//
+// On success returns the restored migration file name.
+func (r *Runner) RestoreLastSnapshot() (string, error) {
+	var file string
+
+	err := r.db.Transactional(func(tx *dbx.Tx) error {
+		if err := r.createSnapshotsTable(tx); err != nil {
+			return err
+		}
+
+		var lastId string
+		err := tx.Select("snapshot").
+			From(r.snapshotsTableName).
+			OrderBy("created DESC", "snapshot DESC").
+			Limit(1).
+			Row(&lastId)
+		if err != nil {
+			return errors.New("There are no snapshots to restore.")
+		}
+
+		snapshots, err := r.findSnapshots(tx, lastId)
+		if err != nil {
+			return err
+		}
+
+		file = snapshots[0].File
+
+		if r.isMigrationApplied(tx, file) {
+			return fmt.Errorf("Migration %s is already applied and the snapshot %s cannot be restored.", file, lastId)
+		}
+
+		for _, s := range snapshots {
+			if err := r.restoreSnapshotTable(tx, s); err != nil {
+				return fmt.Errorf("Failed to restore table %s: %w", s.TableName, err)
+			}
+		}
+
+		if err := r.saveAppliedMigration(tx, file); err != nil {
+			return err
+		}
+
+		_, err = tx.Delete(r.snapshotsTableName, dbx.HashExp{"snapshot": lastId}).Execute()
+
+		return err
+	})
+
+	if err != nil {
+		return "", err
+	}
+	return file, nil
+}
+
+func (r *Runner) restoreSnapshotTable(tx dbx.Builder, s *Snapshot) error {
+	if s.TableName == "" {
+		return nil // nothing to restore
+	}
+
+	_, err := tx.NewQuery(fmt.Sprintf("DROP TABLE IF EXISTS %s", tx.QuoteSimpleTableName(s.TableName))).Execute()
+	if err != nil {
+		return err
+	}
+
+	// the table was created by the rollback
+	if s.SnapshotTable == "" {
+		return nil
+	}
+
+	if _, err := tx.NewQuery(s.TableSql).Execute(); err != nil {
+		return err
+	}
+
+	_, err = tx.NewQuery(fmt.Sprintf(
+		"INSERT INTO %s SELECT * FROM %s",
+		tx.QuoteSimpleTableName(s.TableName),
+		tx.QuoteSimpleTableName(s.SnapshotTable),
+	)).Execute()
+	if err != nil {
+		return err
+	}
+
+	for _, indexSql := range splitIndexesSql(s.IndexesSql) {
+		if _, err := tx.NewQuery(indexSql).Execute(); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.NewQuery(fmt.Sprintf("DROP TABLE %s", tx.QuoteSimpleTableName(s.SnapshotTable))).Execute()
+
+	return err
+}
+
+// revertWithSnapshot executes the Down action of the provided migration
+// and stores a snapshot of the tables whose schema or data was changed.
+func (r *Runner) revertWithSnapshot(tx dbx.Builder, m *Migration) error {
+	if err := r.createSnapshotsTable(tx); err != nil {
+		return err
+	}
+
+	id := fmt.Sprintf("%d", time.Now().UnixNano())
+
+	before, err := r.tablesState(tx)
+	if err != nil {
+		return err
+	}
+
+	snapshotTables := make(map[string]string, len(before))
+	for name := range before {
+		snapshotTable := snapshotTablePrefix + id + "_" + name
+
+		_, err := tx.NewQuery(fmt.Sprintf(
+			"CREATE TABLE %s AS SELECT * FROM %s",
+			tx.QuoteSimpleTableName(snapshotTable),
+			tx.QuoteSimpleTableName(name),
+		)).Execute()
+		if err != nil {
+			return err
+		}
+
+		snapshotTables[name] = snapshotTable
+	}
+
+	if m.Down != nil {
+		if err := m.Down(tx); err != nil {
+			return err
+		}
+	}
+
+	after, err := r.tablesState(tx)
+	if err != nil {
+		return err
+	}
+
+	snapshots := []*Snapshot{}
+
+	for name, state := range before {
+		snapshotTable := snapshotTables[name]
+
+		if state.equal(after[name]) {
+			same, err := sameTablesData(tx, name, snapshotTable)
+			if err != nil {
+				return err
+			}
+
+			if same {
+				// unaffected table
+				_, err := tx.NewQuery(fmt.Sprintf("DROP TABLE %s", tx.QuoteSimpleTableName(snapshotTable))).Execute()
+				if err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		snapshots = append(snapshots, &Snapshot{
+			TableName:     name,
+			SnapshotTable: snapshotTable,
+			TableSql:      state.sql,
+			IndexesSql:    strings.Join(state.indexes, ";\n"),
+		})
+	}
+
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			snapshots = append(snapshots, &Snapshot{TableName: name})
+		}
+	}
+
+	// store a marker to preserve the rollbacks order
+	if len(snapshots) == 0 {
+		snapshots = append(snapshots, &Snapshot{})
+	}
+
+	created := time.Now().Unix()
+
+	for _, s := range snapshots {
+		s.Id = id
+		s.File = m.File
+		s.Created = created
+
+		_, err := tx.Insert(r.snapshotsTableName, dbx.Params{
+			"snapshot":      s.Id,
+			"file":          s.File,
+			"tableName":     s.TableName,
+			"snapshotTable": s.SnapshotTable,
+			"tableSql":      s.TableSql,
+			"indexesSql":    s.IndexesSql,
+			"created":       s.Created,
+		}).Execute()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Runner) createSnapshotsTable(db dbx.Builder) error {
+	rawQuery := fmt.Sprintf(
+		`CREATE TABLE IF NOT EXISTS %v (
+			snapshot      TEXT NOT NULL,
+			file          TEXT NOT NULL,
+			tableName     TEXT NOT NULL,
+			snapshotTable TEXT NOT NULL,
+			tableSql      TEXT NOT NULL,
+			indexesSql    TEXT NOT NULL,
+			created       INTEGER NOT NULL,
+			PRIMARY KEY (snapshot, tableName)
+		)`,
+		db.QuoteSimpleTableName(r.snapshotsTableName),
+	)
+
+	_, err := db.NewQuery(rawQuery).Execute()
+
+	return err
+}
+
+func (r *Runner) findSnapshots(db dbx.Builder, id string) ([]*Snapshot, error) {
+	snapshots := []*Snapshot{}
+
+	query := db.Select("*").
+		From(r.snapshotsTableName).
+		OrderBy("created DESC", "snapshot DESC", "tableName ASC")
+
+	if id != "" {
+		query.AndWhere(dbx.HashExp{"snapshot": id})
+	}
+
+	if err := query.All(&snapshots); err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+// tableState defines the schema state of a single db table.
+type tableState struct {
+	// sql is the raw table definition (used to recreate the table).
+	sql string
+
+	// columns is a normalized columns definition (sorted by name)
+	// that ignores the columns order and the sql formatting differences.
+	columns []string
+
+	indexes []string
+}
+
+// sameTablesState checks whether the 2 tables states are the same.
+func sameTablesState(a, b map[string]*tableState) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for name, state := range a {
+		if !state.equal(b[name]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *tableState) equal(other *tableState) bool {
+	if other == nil ||
+		len(s.columns) != len(other.columns) ||
+		len(s.indexes) != len(other.indexes) {
+		return false
+	}
+
+	for i, column := range s.columns {
+		if column != other.columns[i] {
+			return false
+		}
+	}
+
+	for i, index := range s.indexes {
+		if normalizeSql(index) != normalizeSql(other.indexes[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// tablesState returns the schema state of the db tables
+// (excluding the sqlite and the migrations internal tables).
+func (r *Runner) tablesState(db dbx.Builder) (map[string]*tableState, error) {
+	rows := []struct {
+		Type    string `db:"type"`
+		Name    string `db:"name"`
+		TblName string `db:"tbl_name"`
+		Sql     string `db:"sql"`
+	}{}
+
+	err := db.NewQuery(
+		"SELECT type, name, tbl_name, IFNULL(sql, '') as sql FROM sqlite_master WHERE type IN ('table', 'index')",
+	).All(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]*tableState{}
+
+	for _, row := range rows {
+		if row.Type == "table" && !r.isInternalTable(row.Name) {
+			result[row.Name] = &tableState{sql: row.Sql}
+		}
+	}
+
+	for _, row := range rows {
+		// autoindexes don't have sql definition and are part of the table schema
+		if row.Type != "index" || row.Sql == "" {
+			continue
+		}
+
+		if state, ok := result[row.TblName]; ok {
+			state.indexes = append(state.indexes, row.Sql)
+		}
+	}
+
+	for name, state := range result {
+		columns := []struct {
+			Name    string `db:"name"`
+			Type    string `db:"type"`
+			NotNull bool   `db:"notnull"`
+			Default string `db:"dflt_value"`
+			Pk      int    `db:"pk"`
+		}{}
+
+		err := db.NewQuery(
+			"SELECT name, type, [[notnull]], IFNULL(dflt_value, '') as dflt_value, pk FROM pragma_table_info({:table}) ORDER BY name",
+		).Bind(dbx.Params{"table": name}).All(&columns)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range columns {
+			state.columns = append(state.columns, fmt.Sprintf("%s %s %v %s %d", c.Name, c.Type, c.NotNull, c.Default, c.Pk))
+		}
+
+		sort.Strings(state.indexes)
+	}
+
+	return result, nil
+}
+
+func (r *Runner) isInternalTable(name string) bool {
+	return strings.HasPrefix(name, "sqlite_") ||
+		strings.HasPrefix(name, snapshotTablePrefix) ||
+		name == r.tableName ||
+		name == r.snapshotsTableName
+}
+
+// sameTablesData checks whether the 2 tables (with the same columns) have the same rows.
+func sameTablesData(db dbx.Builder, tableA, tableB string) (bool, error) {
+	a := db.QuoteSimpleTableName(tableA)
+	b := db.QuoteSimpleTableName(tableB)
+
+	var different bool
+
+	err := db.NewQuery(fmt.Sprintf(
+		`SELECT (SELECT count(*) FROM %[1]s) != (SELECT count(*) FROM %[2]s)
+		OR EXISTS (SELECT * FROM %[1]s EXCEPT SELECT * FROM %[2]s)
+		OR EXISTS (SELECT * FROM %[2]s EXCEPT SELECT * FROM %[1]s)`,
+		a, b,
+	)).Row(&different)
+
+	return !different, err
+}
+
+// normalizeSql collapses the whitespaces of the provided sql definition
+// (eg. to ignore indentation differences in the migrations queries).
+func normalizeSql(sql string) string {
+	return strings.Join(strings.Fields(sql), " ")
+}
+
+func splitIndexesSql(indexesSql string) []string {
+	result := []string{}
+
+	for _, part := range strings.Split(indexesSql, ";\n") {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+
+	return result
+}
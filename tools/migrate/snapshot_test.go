@@ -0,0 +1,272 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+)
+
+// newSnapshotsTestRunner creates a new runner with the following migrations:
+//   - 1_test - creates the "a" table
+//   - 2_test - creates the "b" table with an index
+//   - 3_test - inserts a new "a" row
+func newSnapshotsTestRunner(t *testing.T, db *dbx.DB) *Runner {
+	l := MigrationsList{}
+	l.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery("CREATE TABLE a (id TEXT PRIMARY KEY, title TEXT)").Execute()
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.NewQuery("DROP TABLE a").Execute()
+		return err
+	}, "1_test.go")
+	l.Register(func(db dbx.Builder) error {
+		if _, err := db.NewQuery("CREATE TABLE b (id TEXT PRIMARY KEY, title TEXT)").Execute(); err != nil {
+			return err
+		}
+		_, err := db.NewQuery("CREATE INDEX b_title_idx ON b (title)").Execute()
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.NewQuery("DROP TABLE b").Execute()
+		return err
+	}, "2_test.go")
+	l.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery("INSERT INTO a (id, title) VALUES ('a2', 'new')").Execute()
+		return err
+	}, func(db dbx.Builder) error {
+		_, err := db.NewQuery("DELETE FROM a WHERE id = 'a2'").Execute()
+		return err
+	}, "3_test.go")
+
+	r, err := NewRunner(db, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Up(); err != nil {
+		t.Fatal(err)
+	}
+
+	// data not related to the migrations
+	if _, err := db.NewQuery("INSERT INTO a (id, title) VALUES ('a1', 'old')").Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.NewQuery("INSERT INTO b (id, title) VALUES ('b1', 'old')").Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	return r
+}
+
+func snapshotsSummary(t *testing.T, r *Runner) string {
+	snapshots, err := r.Snapshots()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := make([]string, len(snapshots))
+	for i, s := range snapshots {
+		parts[i] = s.File + ":" + s.TableName
+		if s.SnapshotTable != "" {
+			parts[i] += "*"
+		}
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func totalRows(t *testing.T, db *dbx.DB, table string) int {
+	var total int
+	if err := db.Select("count(*)").From(table).Row(&total); err != nil {
+		t.Fatal(err)
+	}
+	return total
+}
+
+func TestRunnerDownSnapshotsAndRestore(t *testing.T) {
+	testDB, err := createTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.Close()
+	testDB.DB.DB().SetMaxOpenConns(1)
+
+	r := newSnapshotsTestRunner(t, testDB.DB)
+
+	if _, err := r.RestoreLastSnapshot(); err == nil {
+		t.Fatal("Expected error when there are no snapshots")
+	}
+
+	reverted, err := r.DownTo("1_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if str := strings.Join(reverted, ","); str != "3_test.go,2_test.go" {
+		t.Fatalf("Expected reverted 3_test.go,2_test.go, got %q", str)
+	}
+
+	// only the affected tables should be stored
+	if summary := snapshotsSummary(t, r); summary != "2_test.go:b*,3_test.go:a*" {
+		t.Fatalf("Unexpected snapshots %q", summary)
+	}
+
+	if total := totalRows(t, testDB.DB, "a"); total != 1 {
+		t.Fatalf("Expected 1 a row after the rollback, got %d", total)
+	}
+
+	// restore 2_test
+	restored, err := r.RestoreLastSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored != "2_test.go" {
+		t.Fatalf("Expected restored 2_test.go, got %q", restored)
+	}
+	if !r.isMigrationApplied(testDB.DB, "2_test.go") {
+		t.Fatal("Expected 2_test.go to be marked as applied")
+	}
+	if total := totalRows(t, testDB.DB, "b"); total != 1 {
+		t.Fatalf("Expected the b rows to be restored, got %d", total)
+	}
+	state, err := r.tablesState(testDB.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state["b"].indexes) != 1 {
+		t.Fatalf("Expected the b index to be restored, got %v", state["b"].indexes)
+	}
+
+	// restore 3_test
+	restored, err = r.RestoreLastSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored != "3_test.go" {
+		t.Fatalf("Expected restored 3_test.go, got %q", restored)
+	}
+	if total := totalRows(t, testDB.DB, "a"); total != 2 {
+		t.Fatalf("Expected the a rows to be restored, got %d", total)
+	}
+
+	if summary := snapshotsSummary(t, r); summary != "" {
+		t.Fatalf("Expected the restored snapshots to be deleted, got %q", summary)
+	}
+
+	// the snapshot data tables should be deleted too
+	for name := range state {
+		if strings.HasPrefix(name, snapshotTablePrefix) {
+			t.Fatalf("Unexpected snapshot table %q", name)
+		}
+	}
+	var totalSnapshotTables int
+	err = testDB.Select("count(*)").
+		From("sqlite_master").
+		Where(dbx.NewExp("name GLOB '_snapshot_*'")).
+		Row(&totalSnapshotTables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totalSnapshotTables != 0 {
+		t.Fatalf("Expected the snapshot tables to be deleted, got %d", totalSnapshotTables)
+	}
+}
+
+func TestRunnerRestoreAppliedMigration(t *testing.T) {
+	testDB, err := createTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.Close()
+	testDB.DB.DB().SetMaxOpenConns(1)
+
+	r := newSnapshotsTestRunner(t, testDB.DB)
+
+	if _, err := r.Down(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Up(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.RestoreLastSnapshot(); err == nil {
+		t.Fatal("Expected error when restoring a snapshot of an applied migration")
+	}
+}
+
+func TestRunnerDownWithoutSnapshots(t *testing.T) {
+	testDB, err := createTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.Close()
+	testDB.DB.DB().SetMaxOpenConns(1)
+
+	r := newSnapshotsTestRunner(t, testDB.DB)
+	r.DisableSnapshots(true)
+
+	if _, err := r.Down(2); err != nil {
+		t.Fatal(err)
+	}
+
+	if summary := snapshotsSummary(t, r); summary != "" {
+		t.Fatalf("Expected no snapshots, got %q", summary)
+	}
+}
+
+func TestRunnerVerifyReversible(t *testing.T) {
+	testDB, err := createTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.Close()
+	testDB.DB.DB().SetMaxOpenConns(1)
+
+	r := newSnapshotsTestRunner(t, testDB.DB)
+
+	// irreversible migration
+	r.migrationsList.Register(func(db dbx.Builder) error {
+		_, err := db.NewQuery("CREATE TABLE c (id TEXT PRIMARY KEY)").Execute()
+		return err
+	}, nil, "4_test.go")
+	if _, err := r.Up(); err != nil {
+		t.Fatal(err)
+	}
+
+	irreversible, err := r.VerifyReversible()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if str := strings.Join(irreversible, ","); str != "4_test.go" {
+		t.Fatalf("Expected irreversible 4_test.go, got %q", str)
+	}
+
+	// the verification changes should be rolled back
+	for _, table := range []string{"a", "b", "c"} {
+		totalRows(t, testDB.DB, table)
+	}
+	for _, m := range r.migrationsList.Items() {
+		if !r.isMigrationApplied(testDB.DB, m.File) {
+			t.Fatalf("Expected %s to remain applied", m.File)
+		}
+	}
+}
+
+func TestRunnerDownToMissingMigration(t *testing.T) {
+	testDB, err := createTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.Close()
+	testDB.DB.DB().SetMaxOpenConns(1)
+
+	r := newSnapshotsTestRunner(t, testDB.DB)
+
+	if _, err := r.DownTo("missing"); err == nil {
+		t.Fatal("Expected error for missing migration")
+	}
+
+	if !r.isMigrationApplied(testDB.DB, "3_test.go") {
+		t.Fatal("Expected 3_test.go to remain applied")
+	}
+}
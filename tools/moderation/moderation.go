@@ -0,0 +1,86 @@
+// Package moderation implements a content moderation verdict type
+// together with simple spam detection heuristics (links density,
+// banned words, etc.).
+package moderation
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Built-in moderation reason codes.
+const (
+	ReasonTooManyLinks = "tooManyLinks"
+	ReasonBannedWords  = "bannedWords"
+	ReasonDuplicate    = "duplicate"
+)
+
+// Reason defines a single moderation verdict reason.
+type Reason struct {
+	// Code is the reason type identifier (eg. "bannedWords").
+	Code string `json:"code"`
+
+	// Field is the name of the field that triggered the reason (if any).
+	Field string `json:"field"`
+
+	Message string `json:"message"`
+}
+
+// Verdict defines a content moderation verdict.
+type Verdict struct {
+	Flagged   bool      `json:"flagged"`
+	Rejected  bool      `json:"rejected"`
+	Reasons   []Reason  `json:"reasons"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// NewVerdict creates a new empty (aka. not flagged) Verdict.
+func NewVerdict() *Verdict {
+	return &Verdict{
+		Reasons:   []Reason{},
+		CheckedAt: time.Now().UTC(),
+	}
+}
+
+// Flag marks the verdict as flagged and registers the provided reason.
+func (v *Verdict) Flag(code string, field string, message string) {
+	v.Flagged = true
+	v.Reasons = append(v.Reasons, Reason{
+		Code:    code,
+		Field:   field,
+		Message: message,
+	})
+}
+
+var linkRegex = regexp.MustCompile(`(?i)\b(?:https?://|ftp://|www\.)[^\s<>"']+`)
+
+// CountLinks returns the number of links (urls and "www." prefixed
+// hosts) in the provided text.
+func CountLinks(text string) int {
+	return len(linkRegex.FindAllStringIndex(text, -1))
+}
+
+// FindBannedWords returns the list of the provided banned words and
+// phrases that are found in text (case-insensitive, whole words only).
+func FindBannedWords(text string, bannedWords []string) []string {
+	result := []string{}
+
+	if text == "" {
+		return result
+	}
+
+	for _, word := range bannedWords {
+		word = strings.TrimSpace(word)
+		if word == "" {
+			continue
+		}
+
+		pattern := regexp.MustCompile(`(?i)(^|\W)` + regexp.QuoteMeta(word) + `($|\W)`)
+		if pattern.MatchString(text) {
+			result = append(result, word)
+		}
+	}
+
+	return result
+}
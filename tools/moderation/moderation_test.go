@@ -0,0 +1,84 @@
+package moderation_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/moderation"
+)
+
+func TestVerdictFlag(t *testing.T) {
+	v := moderation.NewVerdict()
+
+	if v.Flagged || len(v.Reasons) != 0 {
+		t.Fatalf("Expected a new empty verdict, got %v", v)
+	}
+
+	if v.CheckedAt.IsZero() {
+		t.Fatal("Expected CheckedAt to be set")
+	}
+
+	v.Flag(moderation.ReasonBannedWords, "title", "test1")
+	v.Flag(moderation.ReasonDuplicate, "", "test2")
+
+	if !v.Flagged {
+		t.Fatal("Expected the verdict to be flagged")
+	}
+
+	if v.Rejected {
+		t.Fatal("Expected the verdict to not be rejected")
+	}
+
+	if len(v.Reasons) != 2 {
+		t.Fatalf("Expected 2 reasons, got %v", v.Reasons)
+	}
+
+	if r := v.Reasons[0]; r.Code != moderation.ReasonBannedWords || r.Field != "title" || r.Message != "test1" {
+		t.Fatalf("Unexpected first reason %v", r)
+	}
+}
+
+func TestCountLinks(t *testing.T) {
+	scenarios := []struct {
+		text   string
+		expect int
+	}{
+		{"", 0},
+		{"lorem ipsum", 0},
+		{"example.com", 0},
+		{"http://example.com", 1},
+		{"Visit https://example.com/a?b=c and www.test.com!", 2},
+		{"HTTPS://EXAMPLE.COM ftp://files.example.com <a href=\"http://x.com\">x</a>", 3},
+	}
+
+	for i, s := range scenarios {
+		if result := moderation.CountLinks(s.text); result != s.expect {
+			t.Errorf("(%d) Expected %d links, got %d", i, s.expect, result)
+		}
+	}
+}
+
+func TestFindBannedWords(t *testing.T) {
+	scenarios := []struct {
+		text   string
+		words  []string
+		expect []string
+	}{
+		{"", []string{"test"}, []string{}},
+		{"lorem ipsum", nil, []string{}},
+		{"lorem ipsum", []string{"", " "}, []string{}},
+		{"lorem ipsum", []string{"lorem"}, []string{"lorem"}},
+		{"Lorem Ipsum", []string{"lorem", "IPSUM", "dolor"}, []string{"lorem", "IPSUM"}},
+		{"loremipsum", []string{"lorem"}, []string{}},
+		{"buy cheap pills now!", []string{"cheap pills", "now"}, []string{"cheap pills", "now"}},
+		{"a.b (test)", []string{"a.b", "(test)"}, []string{"a.b", "(test)"}},
+	}
+
+	for i, s := range scenarios {
+		result := moderation.FindBannedWords(s.text, s.words)
+
+		if strings.Join(result, "|") != strings.Join(s.expect, "|") {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expect, result)
+		}
+	}
+}
@@ -0,0 +1,431 @@
+// Package parquet implements a minimal dependency free Apache Parquet
+// file writer for flat tables.
+//
+// All columns are written as OPTIONAL (aka. nullable), PLAIN encoded
+// and uncompressed, with a single data page per column chunk.
+// Each [Writer.Flush] call produces a new row group.
+//
+// Example:
+//
+//	w := parquet.NewWriter(file, []parquet.Column{
+//		{Name: "id", Type: parquet.ColumnTypeString},
+//		{Name: "total", Type: parquet.ColumnTypeDouble},
+//	})
+//	w.Write([]any{"abc", 12.5})
+//	w.Write([]any{"def", nil})
+//	err := w.Close()
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+const magic = "PAR1"
+
+// ColumnType defines the value type of a single Parquet column.
+type ColumnType int
+
+const (
+	// ColumnTypeString is stored as UTF8 annotated BYTE_ARRAY.
+	ColumnTypeString ColumnType = iota
+
+	// ColumnTypeBool is stored as BOOLEAN.
+	ColumnTypeBool
+
+	// ColumnTypeInt64 is stored as INT64.
+	ColumnTypeInt64
+
+	// ColumnTypeDouble is stored as DOUBLE.
+	ColumnTypeDouble
+
+	// ColumnTypeTimestamp is stored as TIMESTAMP_MILLIS annotated INT64.
+	ColumnTypeTimestamp
+)
+
+// parquet physical types
+const (
+	physicalTypeBoolean   int32 = 0
+	physicalTypeInt64     int32 = 2
+	physicalTypeDouble    int32 = 5
+	physicalTypeByteArray int32 = 6
+)
+
+// parquet converted (logical) types
+const (
+	convertedTypeUTF8            int32 = 0
+	convertedTypeTimestampMillis int32 = 9
+)
+
+// parquet encodings, repetition types, codecs and page types
+const (
+	encodingPlain          int32 = 0
+	encodingRLE            int32 = 3
+	repetitionTypeOptional int32 = 1
+	codecUncompressed      int32 = 0
+	pageTypeDataPage       int32 = 0
+)
+
+// Column describes a single Parquet column.
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+func (c Column) physicalType() int32 {
+	switch c.Type {
+	case ColumnTypeBool:
+		return physicalTypeBoolean
+	case ColumnTypeInt64, ColumnTypeTimestamp:
+		return physicalTypeInt64
+	case ColumnTypeDouble:
+		return physicalTypeDouble
+	default:
+		return physicalTypeByteArray
+	}
+}
+
+type columnChunk struct {
+	dataPageOffset int64
+	totalSize      int64
+	numValues      int64
+}
+
+type rowGroup struct {
+	columns []columnChunk
+	numRows int64
+}
+
+// Writer writes rows into a single Parquet file.
+type Writer struct {
+	out       io.Writer
+	columns   []Column
+	offset    int64
+	rows      [][]any
+	rowGroups []rowGroup
+	closed    bool
+}
+
+// NewWriter creates a new Parquet Writer that writes to out
+// using the provided columns schema.
+func NewWriter(out io.Writer, columns []Column) *Writer {
+	return &Writer{
+		out:     out,
+		columns: columns,
+	}
+}
+
+// Write normalizes and buffers a single row.
+//
+// The row must have exactly one value per column (in the same order).
+// Nil values (or zero time.Time for timestamp columns) are stored as NULL.
+func (w *Writer) Write(row []any) error {
+	if w.closed {
+		return errors.New("The writer is already closed.")
+	}
+
+	if len(row) != len(w.columns) {
+		return fmt.Errorf("Expected %d row values, got %d.", len(w.columns), len(row))
+	}
+
+	normalized := make([]any, len(row))
+
+	for i, v := range row {
+		nv, err := normalizeValue(w.columns[i].Type, v)
+		if err != nil {
+			return fmt.Errorf("Invalid %q value: %w", w.columns[i].Name, err)
+		}
+		normalized[i] = nv
+	}
+
+	w.rows = append(w.rows, normalized)
+
+	return nil
+}
+
+// Flush writes all buffered rows as a new row group.
+//
+// It is a no-op if there are no buffered rows.
+func (w *Writer) Flush() error {
+	if w.closed {
+		return errors.New("The writer is already closed.")
+	}
+
+	if err := w.writeMagicOnce(); err != nil {
+		return err
+	}
+
+	if len(w.rows) == 0 {
+		return nil
+	}
+
+	group := rowGroup{numRows: int64(len(w.rows))}
+
+	for i, column := range w.columns {
+		chunk, err := w.writeColumnChunk(i, column)
+		if err != nil {
+			return err
+		}
+		group.columns = append(group.columns, chunk)
+	}
+
+	w.rowGroups = append(w.rowGroups, group)
+	w.rows = nil
+
+	return nil
+}
+
+// Close flushes the buffered rows and writes the file footer.
+//
+// Close doesn't close the underlying io.Writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	w.closed = true
+
+	meta := w.fileMetaData()
+
+	footer := make([]byte, 0, len(meta)+8)
+	footer = append(footer, meta...)
+	footer = appendUint32(footer, uint32(len(meta)))
+	footer = append(footer, magic...)
+
+	return w.write(footer)
+}
+
+func (w *Writer) write(data []byte) error {
+	n, err := w.out.Write(data)
+	w.offset += int64(n)
+	return err
+}
+
+func (w *Writer) writeMagicOnce() error {
+	if w.offset > 0 {
+		return nil
+	}
+
+	return w.write([]byte(magic))
+}
+
+func (w *Writer) writeColumnChunk(columnIndex int, column Column) (columnChunk, error) {
+	defLevels := make([]bool, len(w.rows))
+	values := &bytes.Buffer{}
+	bools := []bool{}
+
+	for i, row := range w.rows {
+		v := row[columnIndex]
+		if v == nil {
+			continue
+		}
+
+		defLevels[i] = true
+
+		switch val := v.(type) {
+		case bool:
+			bools = append(bools, val)
+		case int64:
+			binary.Write(values, binary.LittleEndian, val)
+		case float64:
+			binary.Write(values, binary.LittleEndian, math.Float64bits(val))
+		case string:
+			binary.Write(values, binary.LittleEndian, uint32(len(val)))
+			values.WriteString(val)
+		}
+	}
+
+	if column.Type == ColumnTypeBool {
+		values.Write(packBools(bools))
+	}
+
+	levels := encodeDefLevels(defLevels)
+
+	page := make([]byte, 0, 4+len(levels)+values.Len())
+	page = appendUint32(page, uint32(len(levels)))
+	page = append(page, levels...)
+	page = append(page, values.Bytes()...)
+
+	header := &thriftWriter{}
+	header.structBegin()
+	header.i32Field(1, pageTypeDataPage)
+	header.i32Field(2, int32(len(page))) // uncompressed_page_size
+	header.i32Field(3, int32(len(page))) // compressed_page_size
+	header.structField(5)                // data_page_header
+	header.i32Field(1, int32(len(w.rows)))
+	header.i32Field(2, encodingPlain)
+	header.i32Field(3, encodingRLE) // definition levels
+	header.i32Field(4, encodingRLE) // repetition levels
+	header.structEnd()
+	header.structEnd()
+
+	chunk := columnChunk{
+		dataPageOffset: w.offset,
+		totalSize:      int64(len(header.Bytes()) + len(page)),
+		numValues:      int64(len(w.rows)),
+	}
+
+	if err := w.write(header.Bytes()); err != nil {
+		return chunk, err
+	}
+
+	if err := w.write(page); err != nil {
+		return chunk, err
+	}
+
+	return chunk, nil
+}
+
+func (w *Writer) fileMetaData() []byte {
+	var numRows int64
+	for _, g := range w.rowGroups {
+		numRows += g.numRows
+	}
+
+	t := &thriftWriter{}
+	t.structBegin()
+
+	t.i32Field(1, 1) // version
+
+	// schema (flat root + columns)
+	t.listField(2, thriftTypeStruct, len(w.columns)+1)
+	t.structBegin()
+	t.stringField(4, "schema")
+	t.i32Field(5, int32(len(w.columns)))
+	t.structEnd()
+	for _, c := range w.columns {
+		t.structBegin()
+		t.i32Field(1, c.physicalType())
+		t.i32Field(3, repetitionTypeOptional)
+		t.stringField(4, c.Name)
+		switch c.Type {
+		case ColumnTypeString:
+			t.i32Field(6, convertedTypeUTF8)
+		case ColumnTypeTimestamp:
+			t.i32Field(6, convertedTypeTimestampMillis)
+		}
+		t.structEnd()
+	}
+
+	t.i64Field(3, numRows)
+
+	// row groups
+	t.listField(4, thriftTypeStruct, len(w.rowGroups))
+	for _, g := range w.rowGroups {
+		var totalSize int64
+
+		t.structBegin()
+		t.listField(1, thriftTypeStruct, len(g.columns))
+		for i, chunk := range g.columns {
+			totalSize += chunk.totalSize
+
+			t.structBegin()
+			t.i64Field(2, chunk.dataPageOffset) // file_offset
+			t.structField(3)                    // meta_data
+			t.i32Field(1, w.columns[i].physicalType())
+			t.listField(2, thriftTypeI32, 2)
+			t.writeI32(encodingPlain)
+			t.writeI32(encodingRLE)
+			t.listField(3, thriftTypeBinary, 1)
+			t.writeString(w.columns[i].Name)
+			t.i32Field(4, codecUncompressed)
+			t.i64Field(5, chunk.numValues)
+			t.i64Field(6, chunk.totalSize) // total_uncompressed_size
+			t.i64Field(7, chunk.totalSize) // total_compressed_size
+			t.i64Field(9, chunk.dataPageOffset)
+			t.structEnd()
+			t.structEnd()
+		}
+		t.i64Field(2, totalSize)
+		t.i64Field(3, g.numRows)
+		t.structEnd()
+	}
+
+	t.stringField(6, "pocketbase") // created_by
+
+	t.structEnd()
+
+	return t.Bytes()
+}
+
+// encodeDefLevels encodes the definition levels (max level 1)
+// using the RLE/bit-packing hybrid encoding (only RLE runs).
+func encodeDefLevels(levels []bool) []byte {
+	result := []byte{}
+
+	for i := 0; i < len(levels); {
+		j := i
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+
+		var header [binary.MaxVarintLen64]byte
+		n := binary.PutUvarint(header[:], uint64(j-i)<<1)
+		result = append(result, header[:n]...)
+		if levels[i] {
+			result = append(result, 1)
+		} else {
+			result = append(result, 0)
+		}
+
+		i = j
+	}
+
+	return result
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var le [4]byte
+	binary.LittleEndian.PutUint32(le[:], v)
+	return append(b, le[:]...)
+}
+
+// packBools bit-packs the provided bools (LSB first).
+func packBools(values []bool) []byte {
+	result := make([]byte, (len(values)+7)/8)
+
+	for i, v := range values {
+		if v {
+			result[i/8] |= 1 << (i % 8)
+		}
+	}
+
+	return result
+}
+
+func normalizeValue(columnType ColumnType, v any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	switch columnType {
+	case ColumnTypeBool:
+		return cast.ToBoolE(v)
+	case ColumnTypeInt64:
+		return cast.ToInt64E(v)
+	case ColumnTypeDouble:
+		return cast.ToFloat64E(v)
+	case ColumnTypeTimestamp:
+		t, err := cast.ToTimeE(v)
+		if err != nil {
+			return nil, err
+		}
+		if t.IsZero() {
+			return nil, nil
+		}
+		return t.UnixNano() / int64(time.Millisecond), nil
+	default:
+		return cast.ToStringE(v)
+	}
+}
@@ -0,0 +1,348 @@
+package parquet_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/parquet"
+)
+
+func TestWriterWriteInvalidRow(t *testing.T) {
+	w := parquet.NewWriter(&bytes.Buffer{}, []parquet.Column{
+		{Name: "a", Type: parquet.ColumnTypeString},
+		{Name: "b", Type: parquet.ColumnTypeInt64},
+	})
+
+	scenarios := []struct {
+		row         []any
+		expectError bool
+	}{
+		{[]any{}, true},
+		{[]any{"test"}, true},
+		{[]any{"test", 1, 2}, true},
+		{[]any{"test", "invalid"}, true},
+		{[]any{nil, nil}, false},
+		{[]any{"test", "123"}, false},
+		{[]any{"test", 123}, false},
+	}
+
+	for i, s := range scenarios {
+		err := w.Write(s.row)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+		}
+	}
+}
+
+func TestWriterClosed(t *testing.T) {
+	w := parquet.NewWriter(&bytes.Buffer{}, []parquet.Column{
+		{Name: "a", Type: parquet.ColumnTypeString},
+	})
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Write([]any{"test"}); err == nil {
+		t.Fatal("Expected Write error after close")
+	}
+
+	if err := w.Flush(); err == nil {
+		t.Fatal("Expected Flush error after close")
+	}
+
+	// multiple close calls are allowed
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWriterEmpty(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	w := parquet.NewWriter(buf, []parquet.Column{
+		{Name: "a", Type: parquet.ColumnTypeString},
+	})
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	meta := readFileMetaData(t, buf.Bytes())
+
+	if v := meta[3]; v != int64(0) {
+		t.Fatalf("Expected 0 num_rows, got %v", v)
+	}
+
+	if v := meta[4].([]any); len(v) != 0 {
+		t.Fatalf("Expected 0 row groups, got %v", v)
+	}
+}
+
+func TestWriterClose(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	w := parquet.NewWriter(buf, []parquet.Column{
+		{Name: "str", Type: parquet.ColumnTypeString},
+		{Name: "bool", Type: parquet.ColumnTypeBool},
+		{Name: "int", Type: parquet.ColumnTypeInt64},
+		{Name: "double", Type: parquet.ColumnTypeDouble},
+		{Name: "timestamp", Type: parquet.ColumnTypeTimestamp},
+	})
+
+	date := time.Date(2022, 1, 2, 3, 4, 5, 6000000, time.UTC)
+
+	rows := [][]any{
+		{"a", true, 1, 1.5, date},
+		{nil, nil, nil, nil, nil},
+		{"ccc", false, -3, -3.5, time.Time{}},
+	}
+
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 1st row group
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 2nd row group
+	if err := w.Write([]any{"d", true, 4, 4.5, date}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+
+	if string(data[:4]) != "PAR1" {
+		t.Fatalf("Expected PAR1 header magic, got %q", data[:4])
+	}
+
+	meta := readFileMetaData(t, data)
+
+	if v := meta[3]; v != int64(4) {
+		t.Fatalf("Expected 4 num_rows, got %v", v)
+	}
+
+	// schema
+	// ---
+	schema := meta[2].([]any)
+	expectedSchema := []struct {
+		name          string
+		physicalType  any
+		convertedType any
+	}{
+		{"schema", nil, nil},
+		{"str", int64(6), int64(0)},
+		{"bool", int64(0), nil},
+		{"int", int64(2), nil},
+		{"double", int64(5), nil},
+		{"timestamp", int64(2), int64(9)},
+	}
+	if len(schema) != len(expectedSchema) {
+		t.Fatalf("Expected %d schema elements, got %d", len(expectedSchema), len(schema))
+	}
+	for i, expected := range expectedSchema {
+		elem := schema[i].(map[int16]any)
+		if elem[4] != expected.name {
+			t.Errorf("(%d) Expected name %q, got %v", i, expected.name, elem[4])
+		}
+		if elem[1] != expected.physicalType {
+			t.Errorf("(%d) Expected physical type %v, got %v", i, expected.physicalType, elem[1])
+		}
+		if elem[6] != expected.convertedType {
+			t.Errorf("(%d) Expected converted type %v, got %v", i, expected.convertedType, elem[6])
+		}
+	}
+
+	// row groups
+	// ---
+	rowGroups := meta[4].([]any)
+	if len(rowGroups) != 2 {
+		t.Fatalf("Expected 2 row groups, got %d", len(rowGroups))
+	}
+	for i, expectedRows := range []int64{3, 1} {
+		if v := rowGroups[i].(map[int16]any)[3]; v != expectedRows {
+			t.Errorf("(%d) Expected %d row group rows, got %v", i, expectedRows, v)
+		}
+	}
+
+	// decode the 1st row group column values
+	// ---
+	expectedValues := [][]any{
+		{"a", nil, "ccc"},
+		{true, nil, false},
+		{int64(1), nil, int64(-3)},
+		{1.5, nil, -3.5},
+		{date.UnixMilli(), nil, nil},
+	}
+	chunks := rowGroups[0].(map[int16]any)[1].([]any)
+	for i, chunk := range chunks {
+		columnMeta := chunk.(map[int16]any)[3].(map[int16]any)
+		offset := columnMeta[9].(int64)
+		values := readPageValues(t, data[offset:], columnMeta[1].(int64))
+
+		if len(values) != len(expectedValues[i]) {
+			t.Errorf("(%d) Expected values %v, got %v", i, expectedValues[i], values)
+			continue
+		}
+		for j, v := range values {
+			if v != expectedValues[i][j] {
+				t.Errorf("(%d:%d) Expected value %v, got %v", i, j, expectedValues[i][j], v)
+			}
+		}
+	}
+}
+
+// -------------------------------------------------------------------
+// helpers
+// -------------------------------------------------------------------
+
+func readFileMetaData(t *testing.T, data []byte) map[int16]any {
+	if string(data[len(data)-4:]) != "PAR1" {
+		t.Fatalf("Expected PAR1 footer magic, got %q", data[len(data)-4:])
+	}
+
+	size := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	r := &compactReader{data: data[len(data)-8-size : len(data)-8]}
+
+	return r.readStruct()
+}
+
+// readPageValues decodes the values of a single (optional, PLAIN encoded) data page.
+func readPageValues(t *testing.T, data []byte, physicalType int64) []any {
+	r := &compactReader{data: data}
+	header := r.readStruct()
+
+	pageHeader := header[5].(map[int16]any)
+	numValues := int(pageHeader[1].(int64))
+
+	page := data[r.pos : r.pos+int(header[3].(int64))]
+
+	// definition levels (RLE runs only)
+	levelsSize := int(binary.LittleEndian.Uint32(page))
+	levels := &compactReader{data: page[4 : 4+levelsSize]}
+	defined := []bool{}
+	for levels.pos < len(levels.data) {
+		run := int(levels.readVarint() >> 1)
+		v := levels.data[levels.pos]
+		levels.pos++
+		for i := 0; i < run; i++ {
+			defined = append(defined, v == 1)
+		}
+	}
+	if len(defined) != numValues {
+		t.Fatalf("Expected %d definition levels, got %d", numValues, len(defined))
+	}
+
+	values := page[4+levelsSize:]
+	result := make([]any, 0, numValues)
+	bitIndex := 0
+	for _, isDefined := range defined {
+		if !isDefined {
+			result = append(result, nil)
+			continue
+		}
+
+		switch physicalType {
+		case 0: // boolean
+			result = append(result, values[bitIndex/8]&(1<<(bitIndex%8)) != 0)
+			bitIndex++
+		case 2: // int64
+			result = append(result, int64(binary.LittleEndian.Uint64(values)))
+			values = values[8:]
+		case 5: // double
+			result = append(result, math.Float64frombits(binary.LittleEndian.Uint64(values)))
+			values = values[8:]
+		case 6: // byte array
+			size := int(binary.LittleEndian.Uint32(values))
+			result = append(result, string(values[4:4+size]))
+			values = values[4+size:]
+		}
+	}
+
+	return result
+}
+
+// compactReader is a minimal Thrift compact protocol decoder.
+type compactReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *compactReader) readVarint() uint64 {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *compactReader) readZigzag() int64 {
+	v := r.readVarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *compactReader) readValue(fieldType byte) any {
+	switch fieldType {
+	case 1:
+		return true
+	case 2:
+		return false
+	case 5, 6:
+		return r.readZigzag()
+	case 8:
+		size := int(r.readVarint())
+		v := string(r.data[r.pos : r.pos+size])
+		r.pos += size
+		return v
+	case 9:
+		header := r.data[r.pos]
+		r.pos++
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(r.readVarint())
+		}
+		list := make([]any, size)
+		for i := range list {
+			list[i] = r.readValue(header & 0x0f)
+		}
+		return list
+	case 12:
+		return r.readStruct()
+	default:
+		panic("unsupported thrift type")
+	}
+}
+
+func (r *compactReader) readStruct() map[int16]any {
+	result := map[int16]any{}
+
+	var lastId int16
+	for {
+		header := r.data[r.pos]
+		r.pos++
+
+		if header == 0 {
+			return result
+		}
+
+		fieldType := header & 0x0f
+		if delta := int16(header >> 4); delta != 0 {
+			lastId += delta
+		} else {
+			lastId = int16(r.readZigzag())
+		}
+
+		result[lastId] = r.readValue(fieldType)
+	}
+}
@@ -0,0 +1,105 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// thrift compact protocol field types
+const (
+	thriftTypeI32    byte = 5
+	thriftTypeI64    byte = 6
+	thriftTypeBinary byte = 8
+	thriftTypeList   byte = 9
+	thriftTypeStruct byte = 12
+)
+
+// thriftWriter is a minimal write-only implementation of the Thrift
+// compact protocol, sufficient for encoding the Parquet metadata structs.
+type thriftWriter struct {
+	buf bytes.Buffer
+
+	// stack with the last written field id of each opened struct
+	lastFieldIds []int16
+}
+
+func (t *thriftWriter) Bytes() []byte {
+	return t.buf.Bytes()
+}
+
+func (t *thriftWriter) structBegin() {
+	t.lastFieldIds = append(t.lastFieldIds, 0)
+}
+
+func (t *thriftWriter) structEnd() {
+	t.buf.WriteByte(0) // stop field
+	t.lastFieldIds = t.lastFieldIds[:len(t.lastFieldIds)-1]
+}
+
+func (t *thriftWriter) fieldHeader(id int16, fieldType byte) {
+	last := len(t.lastFieldIds) - 1
+
+	delta := id - t.lastFieldIds[last]
+	if delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		t.buf.WriteByte(fieldType)
+		t.writeVarint(zigzag(int64(id)))
+	}
+
+	t.lastFieldIds[last] = id
+}
+
+func (t *thriftWriter) i32Field(id int16, v int32) {
+	t.fieldHeader(id, thriftTypeI32)
+	t.writeI32(v)
+}
+
+func (t *thriftWriter) i64Field(id int16, v int64) {
+	t.fieldHeader(id, thriftTypeI64)
+	t.writeI64(v)
+}
+
+func (t *thriftWriter) stringField(id int16, v string) {
+	t.fieldHeader(id, thriftTypeBinary)
+	t.writeString(v)
+}
+
+func (t *thriftWriter) structField(id int16) {
+	t.fieldHeader(id, thriftTypeStruct)
+	t.structBegin()
+}
+
+func (t *thriftWriter) listField(id int16, elemType byte, size int) {
+	t.fieldHeader(id, thriftTypeList)
+
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elemType)
+	} else {
+		t.buf.WriteByte(0xf0 | elemType)
+		t.writeVarint(uint64(size))
+	}
+}
+
+func (t *thriftWriter) writeI32(v int32) {
+	t.writeVarint(zigzag(int64(v)))
+}
+
+func (t *thriftWriter) writeI64(v int64) {
+	t.writeVarint(zigzag(v))
+}
+
+func (t *thriftWriter) writeString(v string) {
+	t.writeVarint(uint64(len(v)))
+	t.buf.WriteString(v)
+}
+
+func (t *thriftWriter) writeVarint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	t.buf.Write(b[:n])
+}
+
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
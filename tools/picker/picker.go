@@ -0,0 +1,130 @@
+// Package picker implements a JSON fields projection helper that
+// returns only the selected (nested) fields of an arbitrary value.
+package picker
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// Wildcard is the fields path segment that matches all keys of the current level.
+const Wildcard = "*"
+
+// Fields defines a parsed fields projection tree, where each key is
+// a field name and its value is the projection of the field nested
+// keys (nil means that the whole field value will be picked).
+type Fields map[string]Fields
+
+// ParseFields parses the provided comma separated fields paths
+// (eg. "id,title,expand.author.name") into a Fields tree.
+//
+// If both a field and some of its nested paths are specified,
+// the field takes precedence (aka. the whole field value is picked).
+func ParseFields(rawFields string) Fields {
+	result := Fields{}
+
+	for _, path := range strings.Split(rawFields, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+
+		current := result
+		parts := strings.Split(path, ".")
+
+		for i, part := range parts {
+			part = strings.TrimSpace(part)
+
+			sub, exists := current[part]
+
+			if i == len(parts)-1 {
+				current[part] = nil // pick the whole value
+				break
+			}
+
+			if exists && sub == nil {
+				break // the whole value is already picked
+			}
+
+			if sub == nil {
+				sub = Fields{}
+				current[part] = sub
+			}
+
+			current = sub
+		}
+	}
+
+	return result
+}
+
+// Pick converts data into a generic JSON value (aka. map[string]any,
+// []any, etc.) and returns a copy of it containing only the provided
+// comma separated fields paths.
+//
+// When a path segment points to an array, the rest of the path
+// is applied to each of the array items.
+//
+// Example:
+//
+//	// {"a": 1, "b": {"c": 2, "d": 3}, "e": [{"f": 4, "g": 5}]}
+//	result, err := picker.Pick(data, "a,b.c,e.f")
+//	// {"a": 1, "b": {"c": 2}, "e": [{"f": 4}]}
+func Pick(data any, rawFields string) (any, error) {
+	fields := ParseFields(rawFields)
+	if len(fields) == 0 {
+		return data, nil // nothing to pick
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded any
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber() // preserve the original numbers precision
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+
+	return fields.pick(decoded), nil
+}
+
+// pick returns the projection of the generic JSON value v.
+func (f Fields) pick(v any) any {
+	if f == nil {
+		return v
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(f))
+
+		if wildcard, ok := f[Wildcard]; ok {
+			for k, item := range val {
+				result[k] = wildcard.pick(item)
+			}
+		}
+
+		for k, sub := range f {
+			if item, ok := val[k]; ok && k != Wildcard {
+				result[k] = sub.pick(item)
+			}
+		}
+
+		return result
+	case []any:
+		result := make([]any, len(val))
+
+		for i, item := range val {
+			result[i] = f.pick(item)
+		}
+
+		return result
+	default:
+		return v
+	}
+}
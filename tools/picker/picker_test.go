@@ -0,0 +1,103 @@
+package picker_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/picker"
+)
+
+func TestParseFields(t *testing.T) {
+	scenarios := []struct {
+		raw      string
+		expected string
+	}{
+		{"", `{}`},
+		{" , ", `{}`},
+		{"a", `{"a":null}`},
+		{"a, b ,c", `{"a":null,"b":null,"c":null}`},
+		{"a.b.c,a.d", `{"a":{"b":{"c":null},"d":null}}`},
+		{"a,a.b", `{"a":null}`},
+		{"a.b,a", `{"a":null}`},
+		{"a.*,b", `{"a":{"*":null},"b":null}`},
+	}
+
+	for i, s := range scenarios {
+		encoded, _ := json.Marshal(picker.ParseFields(s.raw))
+
+		if string(encoded) != s.expected {
+			t.Errorf("(%d) Expected %s, got %s", i, s.expected, encoded)
+		}
+	}
+}
+
+func TestPick(t *testing.T) {
+	data := map[string]any{
+		"a": 1,
+		"b": map[string]any{
+			"c": 2,
+			"d": []int{3, 4},
+		},
+		"e": []map[string]any{
+			{"f": 5, "g": 6},
+			{"f": 7, "g": 8},
+		},
+		"h": "test",
+		"i": 12345678901234567,
+	}
+
+	scenarios := []struct {
+		fields   string
+		expected string
+	}{
+		{"", `{"a":1,"b":{"c":2,"d":[3,4]},"e":[{"f":5,"g":6},{"f":7,"g":8}],"h":"test","i":12345678901234567}`},
+		{"missing", `{}`},
+		{"a,h,i", `{"a":1,"h":"test","i":12345678901234567}`},
+		{"b", `{"b":{"c":2,"d":[3,4]}}`},
+		{"b.c,b.missing", `{"b":{"c":2}}`},
+		{"e.f", `{"e":[{"f":5},{"f":7}]}`},
+		{"h.missing", `{"h":"test"}`},
+		{"*", `{"a":1,"b":{"c":2,"d":[3,4]},"e":[{"f":5,"g":6},{"f":7,"g":8}],"h":"test","i":12345678901234567}`},
+		{"*,b.c", `{"a":1,"b":{"c":2},"e":[{"f":5,"g":6},{"f":7,"g":8}],"h":"test","i":12345678901234567}`},
+		{"b.*", `{"b":{"c":2,"d":[3,4]}}`},
+	}
+
+	for i, s := range scenarios {
+		result, err := picker.Pick(data, s.fields)
+		if err != nil {
+			t.Errorf("(%d) Unexpected error %v", i, err)
+			continue
+		}
+
+		encoded, _ := json.Marshal(result)
+
+		if string(encoded) != s.expected {
+			t.Errorf("(%d) Expected %s, got %s", i, s.expected, encoded)
+		}
+	}
+}
+
+func TestPickArray(t *testing.T) {
+	data := []map[string]any{
+		{"a": 1, "b": 2},
+		{"a": 3, "b": 4},
+	}
+
+	result, err := picker.Pick(data, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, _ := json.Marshal(result)
+
+	expected := `[{"a":1},{"a":3}]`
+	if string(encoded) != expected {
+		t.Fatalf("Expected %s, got %s", expected, encoded)
+	}
+}
+
+func TestPickInvalidData(t *testing.T) {
+	if _, err := picker.Pick(make(chan int), "a"); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+}
@@ -0,0 +1,131 @@
+// Package ratelimit implements a concurrent safe sliding window
+// rate limiter for arbitrary keys (eg. per user actions).
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// cleanupThreshold is the number of hits after which
+// the expired hits of all keys are removed.
+const cleanupThreshold = 1000
+
+// Limiter defines a sliding window rate limiter that tracks the
+// hits of each key separately.
+//
+// Example:
+//
+//	limiter := ratelimit.New()
+//
+//	if ok, retryAfter := limiter.Check("user1", 10, 24*time.Hour); !ok {
+//		return fmt.Errorf("Too many requests, try again after %v.", retryAfter)
+//	}
+//
+//	// ... perform the action
+//
+//	limiter.Hit("user1", 24*time.Hour)
+type Limiter struct {
+	mux            sync.Mutex
+	hits           map[string][]time.Time
+	hitsSinceClean int
+	maxWindow      time.Duration
+}
+
+// New creates a new empty Limiter.
+func New() *Limiter {
+	return &Limiter{
+		hits: map[string][]time.Time{},
+	}
+}
+
+// Check checks whether a new hit of key is allowed, aka. whether there
+// are less than max hits within the specified window.
+//
+// A non-positive max means no limit.
+//
+// If the hit is not allowed, retryAfter is the duration after which
+// the oldest hit within the window will expire.
+func (l *Limiter) Check(key string, max int, window time.Duration) (ok bool, retryAfter time.Duration) {
+	if max <= 0 {
+		return true, 0
+	}
+
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	now := time.Now()
+
+	hits := l.active(key, now, window)
+	if len(hits) < max {
+		return true, 0
+	}
+
+	// the oldest hit that has to expire to allow a new one
+	oldest := hits[len(hits)-max]
+
+	return false, oldest.Add(window).Sub(now)
+}
+
+// Hit registers a new hit of key.
+//
+// window is used only to remove the expired hits of key.
+func (l *Limiter) Hit(key string, window time.Duration) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	now := time.Now()
+
+	if window > l.maxWindow {
+		l.maxWindow = window
+	}
+
+	l.hits[key] = append(l.active(key, now, window), now)
+
+	l.hitsSinceClean++
+	if l.hitsSinceClean >= cleanupThreshold {
+		l.cleanup(now)
+	}
+}
+
+// Total returns the number of hits of key within the specified window.
+func (l *Limiter) Total(key string, window time.Duration) int {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	return len(l.active(key, time.Now(), window))
+}
+
+// Reset removes all registered hits of key.
+func (l *Limiter) Reset(key string) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	delete(l.hits, key)
+}
+
+// active returns the hits of key without the expired ones.
+func (l *Limiter) active(key string, now time.Time, window time.Duration) []time.Time {
+	hits := l.hits[key]
+	minTime := now.Add(-window)
+
+	for i, t := range hits {
+		if t.After(minTime) {
+			return hits[i:]
+		}
+	}
+
+	return hits[:0]
+}
+
+// cleanup removes the keys whose hits have all expired
+// (based on the largest registered window).
+func (l *Limiter) cleanup(now time.Time) {
+	l.hitsSinceClean = 0
+
+	for key := range l.hits {
+		if len(l.active(key, now, l.maxWindow)) == 0 {
+			delete(l.hits, key)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package ratelimit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/ratelimit"
+)
+
+func TestLimiterCheckAndHit(t *testing.T) {
+	l := ratelimit.New()
+
+	window := 50 * time.Millisecond
+
+	// no limit
+	if ok, _ := l.Check("test", 0, window); !ok {
+		t.Fatal("Expected non-positive max to always be allowed")
+	}
+
+	for i := 0; i < 2; i++ {
+		if ok, retryAfter := l.Check("test", 2, window); !ok || retryAfter != 0 {
+			t.Fatalf("(%d) Expected the hit to be allowed, got %v (%v)", i, ok, retryAfter)
+		}
+		l.Hit("test", window)
+	}
+
+	ok, retryAfter := l.Check("test", 2, window)
+	if ok {
+		t.Fatal("Expected the hit to not be allowed")
+	}
+	if retryAfter <= 0 || retryAfter > window {
+		t.Fatalf("Expected retryAfter to be within (0, %v], got %v", window, retryAfter)
+	}
+
+	// other keys are not affected
+	if ok, _ := l.Check("other", 2, window); !ok {
+		t.Fatal("Expected the other key hit to be allowed")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	if ok, _ := l.Check("test", 2, window); !ok {
+		t.Fatal("Expected the hit to be allowed after the window expiration")
+	}
+}
+
+func TestLimiterTotalAndReset(t *testing.T) {
+	l := ratelimit.New()
+
+	window := time.Minute
+
+	if total := l.Total("test", window); total != 0 {
+		t.Fatalf("Expected 0 hits, got %d", total)
+	}
+
+	l.Hit("test", window)
+	l.Hit("test", window)
+	l.Hit("other", window)
+
+	if total := l.Total("test", window); total != 2 {
+		t.Fatalf("Expected 2 hits, got %d", total)
+	}
+
+	l.Reset("test")
+
+	if total := l.Total("test", window); total != 0 {
+		t.Fatalf("Expected 0 hits after reset, got %d", total)
+	}
+
+	if total := l.Total("other", window); total != 1 {
+		t.Fatalf("Expected the other key hits to be preserved, got %d", total)
+	}
+}
@@ -0,0 +1,164 @@
+// Package reporter implements a pluggable errors and panics reporter
+// interface together with a Sentry compatible client.
+package reporter
+
+import (
+	"net/url"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// list with the supported event levels
+const (
+	LevelFatal   string = "fatal"
+	LevelError   string = "error"
+	LevelWarning string = "warning"
+	LevelInfo    string = "info"
+)
+
+// FilteredValue is the placeholder of the scrubbed event values.
+const FilteredValue = "[Filtered]"
+
+// DefaultScrubFields is a list with the default (case-insensitive)
+// request headers, query parameters, tags and extra keys that
+// are considered personally identifiable information.
+var DefaultScrubFields = []string{
+	"authorization",
+	"cookie",
+	"set-cookie",
+	"x-forwarded-for",
+	"x-real-ip",
+	"fly-client-ip",
+	"cf-connecting-ip",
+	"token",
+	"password",
+	"passwordConfirm",
+	"oldPassword",
+	"authId",
+}
+
+// Reporter defines a base errors reporter interface.
+type Reporter interface {
+	// Report sends the provided event to the reporting service.
+	Report(event *Event) error
+}
+
+// Event defines a single report event.
+type Event struct {
+	Level     string
+	Message   string
+	Error     error
+	Timestamp time.Time
+
+	// Stacktrace holds the event stack frames (innermost first).
+	Stacktrace []Frame
+
+	// Request holds the related HTTP request info (if any).
+	Request *Request
+
+	Tags  map[string]string
+	Extra map[string]any
+}
+
+// Frame defines a single stacktrace frame.
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// Request defines the HTTP request info of an Event.
+type Request struct {
+	Method  string
+	Url     string
+	Ip      string
+	Headers map[string]string
+}
+
+// NewEvent creates a new Event with the provided level and message.
+func NewEvent(level string, message string) *Event {
+	return &Event{
+		Level:     level,
+		Message:   message,
+		Timestamp: time.Now().UTC(),
+		Tags:      map[string]string{},
+		Extra:     map[string]any{},
+	}
+}
+
+// NewStacktrace returns the stack frames of the calling goroutine.
+//
+// The argument skip is the number of stack frames to skip before
+// recording, with 0 identifying the caller of NewStacktrace.
+func NewStacktrace(skip int) []Frame {
+	pcs := make([]uintptr, 50)
+	n := runtime.Callers(skip+2, pcs)
+
+	result := make([]Frame, 0, n)
+
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+
+		result = append(result, Frame{
+			Function: frame.Function,
+			File:     frame.File,
+			Line:     frame.Line,
+		})
+
+		if !more {
+			break
+		}
+	}
+
+	return result
+}
+
+// Scrub removes the personally identifiable information from the event,
+// aka. the request ip and all request headers, query parameters, tags
+// and extra values which keys match one of the provided fields
+// (case-insensitive).
+func (e *Event) Scrub(fields ...string) {
+	isScrubbed := func(key string) bool {
+		for _, f := range fields {
+			if strings.EqualFold(f, key) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if e.Request != nil {
+		e.Request.Ip = ""
+
+		for k := range e.Request.Headers {
+			if isScrubbed(k) {
+				e.Request.Headers[k] = FilteredValue
+			}
+		}
+
+		if u, err := url.Parse(e.Request.Url); err == nil && u.RawQuery != "" {
+			query := u.Query()
+			for k := range query {
+				if isScrubbed(k) {
+					query.Set(k, FilteredValue)
+				}
+			}
+			u.RawQuery = query.Encode()
+			e.Request.Url = u.String()
+		}
+	}
+
+	for k := range e.Tags {
+		if isScrubbed(k) {
+			e.Tags[k] = FilteredValue
+		}
+	}
+
+	for k := range e.Extra {
+		if isScrubbed(k) {
+			e.Extra[k] = FilteredValue
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package reporter_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/reporter"
+)
+
+func TestNewEvent(t *testing.T) {
+	event := reporter.NewEvent(reporter.LevelWarning, "test")
+
+	if event.Level != reporter.LevelWarning {
+		t.Fatalf("Expected level %q, got %q", reporter.LevelWarning, event.Level)
+	}
+
+	if event.Message != "test" {
+		t.Fatalf("Expected message %q, got %q", "test", event.Message)
+	}
+
+	if event.Timestamp.IsZero() {
+		t.Fatal("Expected non-zero timestamp")
+	}
+
+	if event.Tags == nil || event.Extra == nil {
+		t.Fatal("Expected initialized tags and extra maps")
+	}
+}
+
+func TestNewStacktrace(t *testing.T) {
+	frames := reporter.NewStacktrace(0)
+
+	if len(frames) == 0 {
+		t.Fatal("Expected non-empty stacktrace")
+	}
+
+	if !strings.HasSuffix(frames[0].Function, "TestNewStacktrace") {
+		t.Fatalf("Expected the first frame to be the caller, got %v", frames[0])
+	}
+
+	if !strings.HasSuffix(frames[0].File, "reporter_test.go") || frames[0].Line == 0 {
+		t.Fatalf("Expected valid frame file and line, got %v", frames[0])
+	}
+}
+
+func TestEventScrub(t *testing.T) {
+	event := reporter.NewEvent(reporter.LevelError, "test")
+	event.Request = &reporter.Request{
+		Method: "GET",
+		Url:    "http://example.com/test?token=abc&a=123",
+		Ip:     "127.0.0.1",
+		Headers: map[string]string{
+			"Authorization": "abc",
+			"Cookie":        "abc",
+			"Accept":        "text/plain",
+		},
+	}
+	event.Tags["authId"] = "abc"
+	event.Tags["requestId"] = "abc"
+	event.Extra["password"] = "abc"
+	event.Extra["test"] = "abc"
+
+	event.Scrub(reporter.DefaultScrubFields...)
+
+	if event.Request.Ip != "" {
+		t.Fatalf("Expected the request ip to be scrubbed, got %q", event.Request.Ip)
+	}
+
+	expectedUrl := "http://example.com/test?a=123&token=%5BFiltered%5D"
+	if event.Request.Url != expectedUrl {
+		t.Fatalf("Expected url %q, got %q", expectedUrl, event.Request.Url)
+	}
+
+	scenarios := []struct {
+		name     string
+		values   map[string]string
+		key      string
+		expected string
+	}{
+		{"header", event.Request.Headers, "Authorization", reporter.FilteredValue},
+		{"header", event.Request.Headers, "Cookie", reporter.FilteredValue},
+		{"header", event.Request.Headers, "Accept", "text/plain"},
+		{"tag", event.Tags, "authId", reporter.FilteredValue},
+		{"tag", event.Tags, "requestId", "abc"},
+	}
+
+	for _, s := range scenarios {
+		if v := s.values[s.key]; v != s.expected {
+			t.Errorf("Expected %s %q value %q, got %q", s.name, s.key, s.expected, v)
+		}
+	}
+
+	if v := event.Extra["password"]; v != reporter.FilteredValue {
+		t.Errorf("Expected extra password to be scrubbed, got %v", v)
+	}
+
+	if v := event.Extra["test"]; v != "abc" {
+		t.Errorf("Expected extra test to be unchanged, got %v", v)
+	}
+}
@@ -0,0 +1,192 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+var _ Reporter = (*SentryClient)(nil)
+
+// SentryClient defines a Sentry compatible (aka. using the Sentry
+// store endpoint) client that implements [Reporter] interface.
+type SentryClient struct {
+	// Dsn is the Sentry project client key url
+	// (eg. "https://public_key@sentry.example.com/123").
+	Dsn string
+
+	Environment string
+	Release     string
+	ServerName  string
+
+	// HttpClient is an optional custom http client
+	// (fallbacks to http.Client with 10s timeout).
+	HttpClient *http.Client
+}
+
+// ParseSentryDsn parses the provided Sentry DSN and returns
+// its store endpoint url and public key.
+func ParseSentryDsn(dsn string) (endpoint string, publicKey string, err error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", "", err
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", "", errors.New("Invalid DSN scheme.")
+	}
+
+	if u.User == nil || u.User.Username() == "" {
+		return "", "", errors.New("Missing DSN public key.")
+	}
+
+	path := strings.Trim(u.Path, "/")
+	lastSlash := strings.LastIndex(path, "/")
+	projectId := path[lastSlash+1:]
+	if projectId == "" {
+		return "", "", errors.New("Missing DSN project id.")
+	}
+
+	endpoint = fmt.Sprintf("%s://%s/%sapi/%s/store/", u.Scheme, u.Host, path[:lastSlash+1], projectId)
+
+	return endpoint, u.User.Username(), nil
+}
+
+// Report implements [Reporter.Report] interface method.
+func (c *SentryClient) Report(event *Event) error {
+	endpoint, publicKey, err := ParseSentryDsn(c.Dsn)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(c.payload(event))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf(
+		"Sentry sentry_version=7, sentry_client=pocketbase/1.0, sentry_key=%s",
+		publicKey,
+	))
+
+	httpClient := c.HttpClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		resBody, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("Failed to send the report event (%d): %s", res.StatusCode, resBody)
+	}
+
+	return nil
+}
+
+// payload builds the Sentry event payload.
+func (c *SentryClient) payload(event *Event) map[string]any {
+	timestamp := event.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now().UTC()
+	}
+
+	level := event.Level
+	if level == "" {
+		level = LevelError
+	}
+
+	result := map[string]any{
+		"event_id":  security.RandomStringWithAlphabet(32, "0123456789abcdef"),
+		"timestamp": timestamp.UTC().Format(time.RFC3339),
+		"level":     level,
+		"platform":  "go",
+		"logger":    "pocketbase",
+	}
+
+	if c.Environment != "" {
+		result["environment"] = c.Environment
+	}
+
+	if c.Release != "" {
+		result["release"] = c.Release
+	}
+
+	if c.ServerName != "" {
+		result["server_name"] = c.ServerName
+	}
+
+	if event.Message != "" {
+		result["message"] = event.Message
+	}
+
+	if len(event.Tags) > 0 {
+		result["tags"] = event.Tags
+	}
+
+	if len(event.Extra) > 0 {
+		result["extra"] = event.Extra
+	}
+
+	var stacktrace map[string]any
+	if len(event.Stacktrace) > 0 {
+		// sentry expects the frames from oldest to newest
+		frames := make([]map[string]any, len(event.Stacktrace))
+		for i, f := range event.Stacktrace {
+			frames[len(frames)-1-i] = map[string]any{
+				"function": f.Function,
+				"filename": f.File,
+				"lineno":   f.Line,
+			}
+		}
+		stacktrace = map[string]any{"frames": frames}
+	}
+
+	if event.Error != nil {
+		exception := map[string]any{
+			"type":  fmt.Sprintf("%T", event.Error),
+			"value": event.Error.Error(),
+		}
+		if stacktrace != nil {
+			exception["stacktrace"] = stacktrace
+		}
+		result["exception"] = map[string]any{
+			"values": []any{exception},
+		}
+	} else if stacktrace != nil {
+		result["stacktrace"] = stacktrace
+	}
+
+	if event.Request != nil {
+		result["request"] = map[string]any{
+			"method":  event.Request.Method,
+			"url":     event.Request.Url,
+			"headers": event.Request.Headers,
+		}
+
+		if event.Request.Ip != "" {
+			result["user"] = map[string]any{
+				"ip_address": event.Request.Ip,
+			}
+		}
+	}
+
+	return result
+}
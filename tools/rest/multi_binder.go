@@ -37,6 +37,17 @@ func BindBody(c echo.Context, i interface{}) error {
 // CopyJsonBody reads the request body into i by
 // creating a copy of `r.Body` to allow multiple reads.
 func CopyJsonBody(r *http.Request, i interface{}) error {
+	return copyJsonBody(r, i, false)
+}
+
+// CopyJsonBodyUseNumber is similar to [CopyJsonBody] but decodes
+// the JSON numbers as [json.Number] instead of float64
+// (to preserve the precision of the big integers).
+func CopyJsonBodyUseNumber(r *http.Request, i interface{}) error {
+	return copyJsonBody(r, i, true)
+}
+
+func copyJsonBody(r *http.Request, i interface{}, useNumber bool) error {
 	body := r.Body
 
 	// this usually shouldn't be needed because the Server calls close for us
@@ -50,7 +61,12 @@ func CopyJsonBody(r *http.Request, i interface{}) error {
 		return readErr
 	}
 
-	err := json.NewDecoder(bytes.NewReader(bodyBytes)).Decode(i)
+	decoder := json.NewDecoder(bytes.NewReader(bodyBytes))
+	if useNumber {
+		decoder.UseNumber()
+	}
+
+	err := decoder.Decode(i)
 
 	// set new body reader
 	r.Body = io.NopCloser(bytes.NewReader(bodyBytes))
@@ -0,0 +1,176 @@
+package saml
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+)
+
+// canonicalize returns the Exclusive XML Canonicalization (without comments)
+// of the element subtree.
+//
+// The optional exclude element and its subtree are omitted from the output
+// (aka. the enveloped signature transform).
+//
+// inclusivePrefixes is the InclusiveNamespaces PrefixList of the transform
+// ("#default" for the default namespace).
+//
+// @see https://www.w3.org/TR/xml-exc-c14n/
+func canonicalize(el *xmlElement, exclude *xmlElement, inclusivePrefixes []string) []byte {
+	inclusive := make(map[string]struct{}, len(inclusivePrefixes))
+	for _, p := range inclusivePrefixes {
+		if p == "#default" {
+			p = ""
+		}
+		inclusive[p] = struct{}{}
+	}
+
+	buf := new(bytes.Buffer)
+
+	writeCanonical(buf, el, exclude, map[string]string{}, inclusive)
+
+	return buf.Bytes()
+}
+
+type canonicalAttr struct {
+	namespace string
+	name      string
+	value     string
+}
+
+func writeCanonical(
+	buf *bytes.Buffer,
+	el *xmlElement,
+	exclude *xmlElement,
+	rendered map[string]string,
+	inclusive map[string]struct{},
+) {
+	// collect the visibly utilized namespace prefixes
+	utilized := map[string]struct{}{el.prefix: {}}
+	for _, attr := range el.attrs {
+		if attr.Name.Space != "" {
+			utilized[attr.Name.Space] = struct{}{}
+		}
+	}
+	for p := range inclusive {
+		if _, ok := el.lookupNamespace(p); ok {
+			utilized[p] = struct{}{}
+		}
+	}
+
+	// namespace declarations that are not already rendered by an output ancestor
+	prefixes := make([]string, 0, len(utilized))
+	childRendered := make(map[string]string, len(rendered)+len(utilized))
+	for p, ns := range rendered {
+		childRendered[p] = ns
+	}
+	for p := range utilized {
+		if p == "xml" {
+			continue
+		}
+
+		ns, ok := el.lookupNamespace(p)
+		if !ok && p != "" {
+			continue
+		}
+
+		// note: a missing rendered default namespace is the same as an empty one
+		if rendered[p] == ns && (p == "" || hasKey(rendered, p)) {
+			continue
+		}
+
+		prefixes = append(prefixes, p)
+		childRendered[p] = ns
+	}
+	sort.Strings(prefixes)
+
+	// regular attributes sorted by their namespace and local name
+	attrs := make([]canonicalAttr, len(el.attrs))
+	for i, attr := range el.attrs {
+		name := attr.Name.Local
+		var ns string
+		if attr.Name.Space != "" {
+			name = attr.Name.Space + ":" + name
+			ns, _ = el.lookupNamespace(attr.Name.Space)
+		}
+		attrs[i] = canonicalAttr{namespace: ns, name: name, value: attr.Value}
+	}
+	sort.SliceStable(attrs, func(i, j int) bool {
+		if attrs[i].namespace != attrs[j].namespace {
+			return attrs[i].namespace < attrs[j].namespace
+		}
+		return localName(attrs[i].name) < localName(attrs[j].name)
+	})
+
+	name := el.local
+	if el.prefix != "" {
+		name = el.prefix + ":" + name
+	}
+
+	buf.WriteString("<")
+	buf.WriteString(name)
+	for _, p := range prefixes {
+		if p == "" {
+			buf.WriteString(` xmlns="`)
+		} else {
+			buf.WriteString(` xmlns:` + p + `="`)
+		}
+		buf.WriteString(escapeCanonicalAttr(childRendered[p]))
+		buf.WriteString(`"`)
+	}
+	for _, attr := range attrs {
+		buf.WriteString(" " + attr.name + `="`)
+		buf.WriteString(escapeCanonicalAttr(attr.value))
+		buf.WriteString(`"`)
+	}
+	buf.WriteString(">")
+
+	for _, child := range el.children {
+		switch c := child.(type) {
+		case string:
+			buf.WriteString(escapeCanonicalText(c))
+		case *xmlElement:
+			if c != exclude {
+				writeCanonical(buf, c, exclude, childRendered, inclusive)
+			}
+		}
+	}
+
+	buf.WriteString("</" + name + ">")
+}
+
+func hasKey(m map[string]string, key string) bool {
+	_, ok := m[key]
+	return ok
+}
+
+func localName(name string) string {
+	if i := strings.IndexByte(name, ':'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+var canonicalTextReplacer = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	"\r", "&#xD;",
+)
+
+func escapeCanonicalText(s string) string {
+	return canonicalTextReplacer.Replace(s)
+}
+
+var canonicalAttrReplacer = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	`"`, "&quot;",
+	"\t", "&#x9;",
+	"\n", "&#xA;",
+	"\r", "&#xD;",
+)
+
+func escapeCanonicalAttr(s string) string {
+	return canonicalAttrReplacer.Replace(s)
+}
@@ -0,0 +1,167 @@
+package saml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseXml(t *testing.T) {
+	scenarios := []struct {
+		xml         string
+		expectError bool
+	}{
+		{``, true},
+		{`<a>`, true},
+		{`<a></b>`, true},
+		{`<a/><b/>`, true},
+		{`text<a/>`, true},
+		{`<!DOCTYPE a [<!ENTITY x "y">]><a>&x;</a>`, true},
+		{`<a><b ID="1"/><c ID="1"/></a>`, true},
+		{strings.Repeat("<a>", maxXmlDepth+1) + strings.Repeat("</a>", maxXmlDepth+1), true},
+		{`<?xml version="1.0"?><a><!-- comment --><b ID="1"/><c ID="2"/></a>`, false},
+		{strings.Repeat("<a>", maxXmlDepth) + strings.Repeat("</a>", maxXmlDepth), false},
+	}
+
+	for i, s := range scenarios {
+		_, err := parseXml([]byte(s.xml))
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%d] Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+		}
+	}
+}
+
+func TestXmlElementHelpers(t *testing.T) {
+	root, err := parseXml([]byte(`<r xmlns="urn:r" xmlns:x="urn:x" a="1"><x:c> t1 </x:c><x:c>t2</x:c><c>t3</c></r>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !root.is("urn:r", "r") {
+		t.Fatalf("Expected the root to be in the urn:r namespace, got %q", root.namespace())
+	}
+
+	if !root.hasAttr("a") || root.attr("a") != "1" {
+		t.Fatalf("Expected attribute a=1, got %q", root.attr("a"))
+	}
+
+	if root.hasAttr("missing") || root.attr("missing") != "" {
+		t.Fatal("Expected missing attribute")
+	}
+
+	if children := root.childElements("urn:x", "c"); len(children) != 2 {
+		t.Fatalf("Expected 2 urn:x children, got %d", len(children))
+	}
+
+	if text := root.childElement("urn:x", "c").text(); text != "t1" {
+		t.Fatalf("Expected trimmed text t1, got %q", text)
+	}
+
+	if text := root.childElement("urn:r", "c").text(); text != "t3" {
+		t.Fatalf("Expected text t3, got %q", text)
+	}
+
+	if child := root.childElement("urn:missing", "c"); child != nil {
+		t.Fatalf("Expected nil child, got %v", child)
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	scenarios := []struct {
+		name              string
+		xml               string
+		subtree           bool // canonicalize the first child element
+		excludeFirst      bool // exclude the first child element
+		inclusivePrefixes []string
+		expected          string
+	}{
+		{
+			"unused namespaces and attributes order",
+			`<a:root xmlns:a="urn:a" xmlns:b="urn:b" z="1" a:y="2"><b:child>t&amp;&lt;x&gt;</b:child><c/></a:root>`,
+			false,
+			false,
+			nil,
+			`<a:root xmlns:a="urn:a" z="1" a:y="2"><b:child xmlns:b="urn:b">t&amp;&lt;x&gt;</b:child><c></c></a:root>`,
+		},
+		{
+			"default namespace reset",
+			`<root xmlns="urn:x"><child xmlns=""/></root>`,
+			false,
+			false,
+			nil,
+			`<root xmlns="urn:x"><child xmlns=""></child></root>`,
+		},
+		{
+			"redundant namespace redeclaration",
+			`<a:r xmlns:a="urn:a"><a:c xmlns:a="urn:a"/></a:r>`,
+			false,
+			false,
+			nil,
+			`<a:r xmlns:a="urn:a"><a:c></a:c></a:r>`,
+		},
+		{
+			"subtree with ancestor namespaces",
+			`<a:r xmlns:a="urn:a" xmlns:b="urn:b" xmlns:c="urn:c"><a:c b:x="1">v</a:c></a:r>`,
+			true,
+			false,
+			nil,
+			`<a:c xmlns:a="urn:a" xmlns:b="urn:b" b:x="1">v</a:c>`,
+		},
+		{
+			"inclusive prefixes",
+			`<a:r xmlns:a="urn:a" xmlns:b="urn:b" xmlns="urn:d"/>`,
+			false,
+			false,
+			[]string{"b", "#default", "missing"},
+			`<a:r xmlns="urn:d" xmlns:a="urn:a" xmlns:b="urn:b"></a:r>`,
+		},
+		{
+			"attributes escaping",
+			`<r b="x&quot;&#9;&lt;&gt;&#10;" a="&amp;"/>`,
+			false,
+			false,
+			nil,
+			`<r a="&amp;" b="x&quot;&#x9;&lt;>&#xA;"></r>`,
+		},
+		{
+			"excluded element and comments",
+			`<r><s>x</s><!-- comment --><k/></r>`,
+			false,
+			true,
+			nil,
+			`<r><k></k></r>`,
+		},
+	}
+
+	for _, s := range scenarios {
+		root, err := parseXml([]byte(s.xml))
+		if err != nil {
+			t.Errorf("[%s] Failed to parse the xml: %v", s.name, err)
+			continue
+		}
+
+		var firstChild *xmlElement
+		for _, child := range root.children {
+			if c, ok := child.(*xmlElement); ok {
+				firstChild = c
+				break
+			}
+		}
+
+		el := root
+		if s.subtree {
+			el = firstChild
+		}
+
+		var exclude *xmlElement
+		if s.excludeFirst {
+			exclude = firstChild
+		}
+
+		result := string(canonicalize(el, exclude, s.inclusivePrefixes))
+		if result != s.expected {
+			t.Errorf("[%s] Expected \n%s, \ngot \n%s", s.name, s.expected, result)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package saml
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Supported XML signature algorithms.
+const (
+	dsigNamespace = "http://www.w3.org/2000/09/xmldsig#"
+
+	algExcC14N             = "http://www.w3.org/2001/10/xml-exc-c14n#"
+	algEnvelopedSignature  = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+	algSha256              = "http://www.w3.org/2001/04/xmlenc#sha256"
+	algRsaSha256           = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"
+	algEcdsaSha256         = "http://www.w3.org/2001/04/xmldsig-more#ecdsa-sha256"
+	inclusiveNamespacesTag = "InclusiveNamespaces"
+)
+
+var errMissingSignature = errors.New("missing signature")
+
+// verifyEnvelopedSignature verifies the enveloped XML signature
+// (the direct ds:Signature child) of the provided element.
+//
+// Only a single same document reference to the element itself with
+// exclusive canonicalization and SHA-256 digest is supported.
+func verifyEnvelopedSignature(el *xmlElement, cert *x509.Certificate) error {
+	signatures := el.childElements(dsigNamespace, "Signature")
+	if len(signatures) == 0 {
+		return errMissingSignature
+	}
+	if len(signatures) > 1 {
+		return errors.New("multiple signatures")
+	}
+	signature := signatures[0]
+
+	signedInfos := signature.childElements(dsigNamespace, "SignedInfo")
+	if len(signedInfos) != 1 {
+		return errors.New("invalid signature SignedInfo")
+	}
+	signedInfo := signedInfos[0]
+
+	// canonicalization method
+	c14nMethod := signedInfo.childElement(dsigNamespace, "CanonicalizationMethod")
+	if c14nMethod == nil || c14nMethod.attr("Algorithm") != algExcC14N {
+		return errors.New("unsupported signature canonicalization method")
+	}
+
+	// signature method
+	signatureMethod := signedInfo.childElement(dsigNamespace, "SignatureMethod")
+	if signatureMethod == nil {
+		return errors.New("missing signature method")
+	}
+
+	// reference
+	references := signedInfo.childElements(dsigNamespace, "Reference")
+	if len(references) != 1 {
+		return errors.New("the signature must have exactly one reference")
+	}
+	reference := references[0]
+
+	id := el.attr("ID")
+	if id == "" || reference.attr("URI") != "#"+id {
+		return errors.New("the signature reference doesn't match the signed element")
+	}
+
+	// reference transforms
+	var hasEnveloped, hasExcC14N bool
+	var referencePrefixes []string
+	if transforms := reference.childElement(dsigNamespace, "Transforms"); transforms != nil {
+		for _, transform := range transforms.childElements(dsigNamespace, "Transform") {
+			switch transform.attr("Algorithm") {
+			case algEnvelopedSignature:
+				hasEnveloped = true
+			case algExcC14N:
+				hasExcC14N = true
+				referencePrefixes = inclusiveNamespacePrefixes(transform)
+			default:
+				return fmt.Errorf("unsupported signature transform %q", transform.attr("Algorithm"))
+			}
+		}
+	}
+	if !hasEnveloped || !hasExcC14N {
+		return errors.New("the signature must be enveloped and exclusively canonicalized")
+	}
+
+	// digest
+	digestMethod := reference.childElement(dsigNamespace, "DigestMethod")
+	if digestMethod == nil || digestMethod.attr("Algorithm") != algSha256 {
+		return errors.New("unsupported signature digest method")
+	}
+
+	digestValue := reference.childElement(dsigNamespace, "DigestValue")
+	if digestValue == nil {
+		return errors.New("missing signature digest value")
+	}
+
+	expectedDigest, err := decodeBase64(digestValue.text())
+	if err != nil {
+		return err
+	}
+
+	digest := sha256.Sum256(canonicalize(el, signature, referencePrefixes))
+	if subtle.ConstantTimeCompare(digest[:], expectedDigest) != 1 {
+		return errors.New("invalid signature digest")
+	}
+
+	// signature value
+	signatureValue := signature.childElement(dsigNamespace, "SignatureValue")
+	if signatureValue == nil {
+		return errors.New("missing signature value")
+	}
+
+	sig, err := decodeBase64(signatureValue.text())
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.Sum256(canonicalize(signedInfo, nil, inclusiveNamespacePrefixes(c14nMethod)))
+
+	switch signatureMethod.attr("Algorithm") {
+	case algRsaSha256:
+		key, ok := cert.PublicKey.(*rsa.PublicKey)
+		if !ok || rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], sig) != nil {
+			return errors.New("invalid signature")
+		}
+	case algEcdsaSha256:
+		key, ok := cert.PublicKey.(*ecdsa.PublicKey)
+		// the XML signature ECDSA value is the r and s concatenation
+		if !ok || len(sig) == 0 || len(sig)%2 != 0 {
+			return errors.New("invalid signature")
+		}
+		r := new(big.Int).SetBytes(sig[:len(sig)/2])
+		s := new(big.Int).SetBytes(sig[len(sig)/2:])
+		if !ecdsa.Verify(key, hash[:], r, s) {
+			return errors.New("invalid signature")
+		}
+	default:
+		return fmt.Errorf("unsupported signature method %q", signatureMethod.attr("Algorithm"))
+	}
+
+	return nil
+}
+
+// inclusiveNamespacePrefixes returns the InclusiveNamespaces PrefixList
+// of the provided exclusive canonicalization element (if any).
+func inclusiveNamespacePrefixes(el *xmlElement) []string {
+	inclusive := el.childElement(algExcC14N, inclusiveNamespacesTag)
+	if inclusive == nil {
+		return nil
+	}
+
+	return strings.Fields(inclusive.attr("PrefixList"))
+}
+
+// decodeBase64 decodes a standard base64 string ignoring the whitespaces.
+func decodeBase64(s string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(s), ""))
+}
@@ -0,0 +1,436 @@
+// Package saml implements a minimal SAML 2.0 Web Browser SSO service provider
+// (SP initiated login with HTTP-Redirect AuthnRequest and HTTP-POST Response).
+//
+// Only signed (the Response and/or the Assertion) and not encrypted
+// assertions are supported. The XML signatures must use exclusive
+// canonicalization with RSA or ECDSA SHA-256 (the default of most IdPs).
+//
+// Example:
+//
+//	sp := &saml.ServiceProvider{
+//		EntityId:       "https://example.com/saml/metadata",
+//		AcsUrl:         "https://example.com/saml/acs",
+//		IdpEntityId:    "http://www.okta.com/abc",
+//		IdpSsoUrl:      "https://example.okta.com/app/abc/sso/saml",
+//		IdpCertificate: cert,
+//	}
+//
+//	// redirect the user to the IdP
+//	requestId := saml.NewRequestId()
+//	redirectUrl, err := sp.AuthnRequestUrl(requestId, relayState)
+//
+//	// validate the IdP response posted to the AcsUrl
+//	assertion, err := sp.ParseResponse(r.FormValue("SAMLResponse"), requestId)
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SAML namespaces and identifiers.
+const (
+	NamespaceAssertion = "urn:oasis:names:tc:SAML:2.0:assertion"
+	NamespaceProtocol  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	NamespaceMetadata  = "urn:oasis:names:tc:SAML:2.0:metadata"
+
+	BindingHttpPost     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	BindingHttpRedirect = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+
+	NameIdFormatEmail       = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+	NameIdFormatUnspecified = "urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified"
+
+	StatusSuccess = "urn:oasis:names:tc:SAML:2.0:status:Success"
+
+	subjectConfirmationBearer = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+)
+
+// MaxResponseSize is the max allowed size of the decoded SAML response.
+const MaxResponseSize = 1 << 20
+
+// ClockSkew is the allowed clock difference with the IdP
+// when checking the assertion validity time.
+const ClockSkew = 3 * time.Minute
+
+// ServiceProvider defines a SAML service provider (aka. the app)
+// together with its trusted identity provider.
+type ServiceProvider struct {
+	// EntityId is the service provider entity id (usually its metadata url).
+	EntityId string
+
+	// AcsUrl is the service provider Assertion Consumer Service url
+	// where the IdP will POST its responses.
+	AcsUrl string
+
+	// IdpEntityId is the trusted identity provider entity id (aka. the issuer).
+	IdpEntityId string
+
+	// IdpSsoUrl is the identity provider Single Sign-On HTTP-Redirect url.
+	IdpSsoUrl string
+
+	// IdpCertificate is the identity provider signing certificate.
+	IdpCertificate *x509.Certificate
+
+	// Now is an optional time function used for the validity
+	// checks (mostly for testing; default to time.Now).
+	Now func() time.Time
+}
+
+// Assertion defines the verified data of a SAML assertion.
+type Assertion struct {
+	NameId       string              `json:"nameId"`
+	NameIdFormat string              `json:"nameIdFormat"`
+	SessionIndex string              `json:"sessionIndex"`
+	Attributes   map[string][]string `json:"attributes"`
+}
+
+// Attribute returns the first value of the specified assertion attribute.
+func (a *Assertion) Attribute(name string) string {
+	if values := a.Attributes[name]; len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// ParseCertificate parses a PEM or a plain base64 encoded DER
+// X.509 certificate (as found in the IdP metadata).
+func ParseCertificate(raw string) (*x509.Certificate, error) {
+	raw = strings.TrimSpace(raw)
+
+	if block, _ := pem.Decode([]byte(raw)); block != nil {
+		return x509.ParseCertificate(block.Bytes)
+	}
+
+	der, err := decodeBase64(raw)
+	if err != nil {
+		return nil, errors.New("invalid certificate encoding")
+	}
+
+	return x509.ParseCertificate(der)
+}
+
+// NewRequestId generates a new random AuthnRequest id.
+func NewRequestId() string {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	// the ids must start with a letter or underscore (xs:ID)
+	return "_" + hex.EncodeToString(b)
+}
+
+type metadataAcs struct {
+	Binding  string `xml:"Binding,attr"`
+	Location string `xml:"Location,attr"`
+	Index    int    `xml:"index,attr"`
+}
+
+type metadataSpSso struct {
+	AuthnRequestsSigned        bool          `xml:"AuthnRequestsSigned,attr"`
+	WantAssertionsSigned       bool          `xml:"WantAssertionsSigned,attr"`
+	ProtocolSupportEnumeration string        `xml:"protocolSupportEnumeration,attr"`
+	NameIdFormats              []string      `xml:"md:NameIDFormat"`
+	AssertionConsumerServices  []metadataAcs `xml:"md:AssertionConsumerService"`
+}
+
+type metadataEntity struct {
+	XMLName   xml.Name      `xml:"md:EntityDescriptor"`
+	Namespace string        `xml:"xmlns:md,attr"`
+	EntityId  string        `xml:"entityID,attr"`
+	SpSso     metadataSpSso `xml:"md:SPSSODescriptor"`
+}
+
+// Metadata returns the service provider metadata xml document.
+func (sp *ServiceProvider) Metadata() ([]byte, error) {
+	entity := metadataEntity{
+		Namespace: NamespaceMetadata,
+		EntityId:  sp.EntityId,
+		SpSso: metadataSpSso{
+			AuthnRequestsSigned:        false,
+			WantAssertionsSigned:       true,
+			ProtocolSupportEnumeration: NamespaceProtocol,
+			NameIdFormats:              []string{NameIdFormatEmail, NameIdFormatUnspecified},
+			AssertionConsumerServices: []metadataAcs{
+				{Binding: BindingHttpPost, Location: sp.AcsUrl, Index: 0},
+			},
+		},
+	}
+
+	raw, err := xml.MarshalIndent(entity, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), raw...), nil
+}
+
+// AuthnRequestUrl returns the IdP SSO url with the encoded
+// HTTP-Redirect binding AuthnRequest with the specified id.
+func (sp *ServiceProvider) AuthnRequestUrl(requestId string, relayState string) (string, error) {
+	ssoUrl, err := url.Parse(sp.IdpSsoUrl)
+	if err != nil {
+		return "", err
+	}
+
+	request := fmt.Sprintf(
+		`<samlp:AuthnRequest xmlns:samlp="%s" xmlns:saml="%s" ID="%s" Version="2.0" IssueInstant="%s" Destination="%s" AssertionConsumerServiceURL="%s" ProtocolBinding="%s">`+
+			`<saml:Issuer>%s</saml:Issuer>`+
+			`<samlp:NameIDPolicy AllowCreate="true"/>`+
+			`</samlp:AuthnRequest>`,
+		NamespaceProtocol,
+		NamespaceAssertion,
+		escapeXml(requestId),
+		sp.now().UTC().Format(time.RFC3339),
+		escapeXml(sp.IdpSsoUrl),
+		escapeXml(sp.AcsUrl),
+		BindingHttpPost,
+		escapeXml(sp.EntityId),
+	)
+
+	buf := new(bytes.Buffer)
+	w, err := flate.NewWriter(buf, flate.DefaultCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.Write([]byte(request)); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	query := ssoUrl.Query()
+	query.Set("SAMLRequest", base64.StdEncoding.EncodeToString(buf.Bytes()))
+	if relayState != "" {
+		query.Set("RelayState", relayState)
+	}
+	ssoUrl.RawQuery = query.Encode()
+
+	return ssoUrl.String(), nil
+}
+
+// ParseResponse decodes and validates the base64 encoded HTTP-POST
+// binding SAML response of the AuthnRequest with the specified id
+// and returns its verified assertion data.
+func (sp *ServiceProvider) ParseResponse(encodedResponse string, requestId string) (*Assertion, error) {
+	if sp.IdpCertificate == nil {
+		return nil, errors.New("missing IdP certificate")
+	}
+
+	if requestId == "" {
+		return nil, errors.New("missing request id")
+	}
+
+	if len(encodedResponse) > MaxResponseSize*4/3+4 {
+		return nil, errors.New("too large SAML response")
+	}
+
+	raw, err := decodeBase64(encodedResponse)
+	if err != nil {
+		return nil, errors.New("invalid SAML response encoding")
+	}
+
+	response, err := parseXml(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.is(NamespaceProtocol, "Response") {
+		return nil, errors.New("invalid SAML response root element")
+	}
+
+	if response.attr("Version") != "2.0" {
+		return nil, errors.New("unsupported SAML response version")
+	}
+
+	if response.hasAttr("Destination") && response.attr("Destination") != sp.AcsUrl {
+		return nil, errors.New("invalid SAML response destination")
+	}
+
+	if response.attr("InResponseTo") != requestId {
+		return nil, errors.New("the SAML response doesn't match the authentication request")
+	}
+
+	if issuer := response.childElement(NamespaceAssertion, "Issuer"); issuer != nil && issuer.text() != sp.IdpEntityId {
+		return nil, errors.New("invalid SAML response issuer")
+	}
+
+	status := response.childElement(NamespaceProtocol, "Status")
+	if status == nil {
+		return nil, errors.New("missing SAML response status")
+	}
+	statusCode := status.childElement(NamespaceProtocol, "StatusCode")
+	if statusCode == nil || statusCode.attr("Value") != StatusSuccess {
+		return nil, errors.New("unsuccessful SAML response status")
+	}
+
+	if len(response.childElements(NamespaceAssertion, "EncryptedAssertion")) > 0 {
+		return nil, errors.New("encrypted assertions are not supported")
+	}
+
+	assertions := response.childElements(NamespaceAssertion, "Assertion")
+	if len(assertions) != 1 {
+		return nil, errors.New("the SAML response must have exactly one assertion")
+	}
+	assertion := assertions[0]
+
+	// the assertion data is read only from the verified element
+	// (either the signed response or the signed assertion itself)
+	responseErr := verifyEnvelopedSignature(response, sp.IdpCertificate)
+	if responseErr != nil && responseErr != errMissingSignature {
+		return nil, fmt.Errorf("invalid SAML response signature: %w", responseErr)
+	}
+	assertionErr := verifyEnvelopedSignature(assertion, sp.IdpCertificate)
+	if assertionErr != nil && (assertionErr != errMissingSignature || responseErr != nil) {
+		return nil, fmt.Errorf("invalid SAML assertion signature: %w", assertionErr)
+	}
+
+	return sp.parseAssertion(assertion, requestId)
+}
+
+// parseAssertion validates the verified assertion element
+// and extracts its subject and attributes.
+func (sp *ServiceProvider) parseAssertion(assertion *xmlElement, requestId string) (*Assertion, error) {
+	now := sp.now()
+
+	issuer := assertion.childElement(NamespaceAssertion, "Issuer")
+	if issuer == nil || issuer.text() != sp.IdpEntityId {
+		return nil, errors.New("invalid SAML assertion issuer")
+	}
+
+	// subject
+	subject := assertion.childElement(NamespaceAssertion, "Subject")
+	if subject == nil {
+		return nil, errors.New("missing SAML assertion subject")
+	}
+
+	nameId := subject.childElement(NamespaceAssertion, "NameID")
+	if nameId == nil || nameId.text() == "" {
+		return nil, errors.New("missing SAML assertion subject NameID")
+	}
+
+	var hasValidConfirmation bool
+	for _, confirmation := range subject.childElements(NamespaceAssertion, "SubjectConfirmation") {
+		if confirmation.attr("Method") != subjectConfirmationBearer {
+			continue
+		}
+
+		data := confirmation.childElement(NamespaceAssertion, "SubjectConfirmationData")
+		if data == nil ||
+			data.attr("Recipient") != sp.AcsUrl ||
+			(data.hasAttr("InResponseTo") && data.attr("InResponseTo") != requestId) ||
+			!isBefore(now, data.attr("NotOnOrAfter"), ClockSkew) {
+			continue
+		}
+
+		hasValidConfirmation = true
+		break
+	}
+	if !hasValidConfirmation {
+		return nil, errors.New("missing valid SAML assertion bearer subject confirmation")
+	}
+
+	// conditions
+	conditions := assertion.childElement(NamespaceAssertion, "Conditions")
+	if conditions == nil {
+		return nil, errors.New("missing SAML assertion conditions")
+	}
+
+	if conditions.hasAttr("NotBefore") && !isAfter(now, conditions.attr("NotBefore"), ClockSkew) {
+		return nil, errors.New("the SAML assertion is not yet valid")
+	}
+
+	if conditions.hasAttr("NotOnOrAfter") && !isBefore(now, conditions.attr("NotOnOrAfter"), ClockSkew) {
+		return nil, errors.New("the SAML assertion is expired")
+	}
+
+	restrictions := conditions.childElements(NamespaceAssertion, "AudienceRestriction")
+	if len(restrictions) == 0 {
+		return nil, errors.New("missing SAML assertion audience restriction")
+	}
+	for _, restriction := range restrictions {
+		var found bool
+		for _, audience := range restriction.childElements(NamespaceAssertion, "Audience") {
+			if audience.text() == sp.EntityId {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.New("invalid SAML assertion audience")
+		}
+	}
+
+	result := &Assertion{
+		NameId:       nameId.text(),
+		NameIdFormat: nameId.attr("Format"),
+		Attributes:   map[string][]string{},
+	}
+
+	if authn := assertion.childElement(NamespaceAssertion, "AuthnStatement"); authn != nil {
+		result.SessionIndex = authn.attr("SessionIndex")
+	}
+
+	for _, statement := range assertion.childElements(NamespaceAssertion, "AttributeStatement") {
+		for _, attr := range statement.childElements(NamespaceAssertion, "Attribute") {
+			name := attr.attr("Name")
+			if name == "" {
+				continue
+			}
+			for _, value := range attr.childElements(NamespaceAssertion, "AttributeValue") {
+				result.Attributes[name] = append(result.Attributes[name], value.text())
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (sp *ServiceProvider) now() time.Time {
+	if sp.Now != nil {
+		return sp.Now()
+	}
+
+	return time.Now()
+}
+
+// isBefore checks whether t is before the specified xs:dateTime value
+// extended with the provided skew duration.
+func isBefore(t time.Time, value string, skew time.Duration) bool {
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return false
+	}
+
+	return t.Before(parsed.Add(skew))
+}
+
+// isAfter checks whether t is on or after the specified xs:dateTime value
+// reduced with the provided skew duration.
+func isAfter(t time.Time, value string, skew time.Duration) bool {
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return false
+	}
+
+	return !t.Before(parsed.Add(-skew))
+}
+
+func escapeXml(s string) string {
+	buf := new(bytes.Buffer)
+	xml.EscapeText(buf, []byte(s))
+	return buf.String()
+}
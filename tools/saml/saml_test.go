@@ -0,0 +1,651 @@
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2023, 1, 20, 10, 0, 0, 0, time.UTC)
+
+const testRequestId = "_test_request_id"
+
+const testSignatureTemplate = `<ds:Signature xmlns:ds="http://www.w3.org/2000/09/xmldsig#">` +
+	`<ds:SignedInfo>` +
+	`<ds:CanonicalizationMethod Algorithm="http://www.w3.org/2001/10/xml-exc-c14n#"/>` +
+	`<ds:SignatureMethod Algorithm="{algorithm}"/>` +
+	`<ds:Reference URI="#{id}">` +
+	`<ds:Transforms>` +
+	`<ds:Transform Algorithm="http://www.w3.org/2000/09/xmldsig#enveloped-signature"/>` +
+	`<ds:Transform Algorithm="http://www.w3.org/2001/10/xml-exc-c14n#"><ec:InclusiveNamespaces xmlns:ec="http://www.w3.org/2001/10/xml-exc-c14n#" PrefixList="xs"/></ds:Transform>` +
+	`</ds:Transforms>` +
+	`<ds:DigestMethod Algorithm="http://www.w3.org/2001/04/xmlenc#sha256"/>` +
+	`<ds:DigestValue>DIGEST_VALUE_{id}</ds:DigestValue>` +
+	`</ds:Reference>` +
+	`</ds:SignedInfo>` +
+	`<ds:SignatureValue>SIGNATURE_VALUE_{id}</ds:SignatureValue>` +
+	`</ds:Signature>`
+
+const testResponseTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" xmlns:xs="http://www.w3.org/2001/XMLSchema" ID="_response1" Version="2.0" IssueInstant="{now}" Destination="{destination}" InResponseTo="{requestId}">
+	<saml:Issuer>{issuer}</saml:Issuer>
+	{responseSignature}
+	<samlp:Status><samlp:StatusCode Value="{status}"/></samlp:Status>
+	<saml:Assertion ID="_assertion1" Version="2.0" IssueInstant="{now}">
+		<saml:Issuer>{issuer}</saml:Issuer>
+		{assertionSignature}
+		<saml:Subject>
+			<saml:NameID Format="urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress">test@example.com</saml:NameID>
+			<saml:SubjectConfirmation Method="urn:oasis:names:tc:SAML:2.0:cm:bearer">
+				<saml:SubjectConfirmationData InResponseTo="{requestId}" Recipient="{recipient}" NotOnOrAfter="{notOnOrAfter}"/>
+			</saml:SubjectConfirmation>
+		</saml:Subject>
+		<saml:Conditions NotBefore="{notBefore}" NotOnOrAfter="{notOnOrAfter}">
+			<saml:AudienceRestriction><saml:Audience>{audience}</saml:Audience></saml:AudienceRestriction>
+		</saml:Conditions>
+		<saml:AuthnStatement AuthnInstant="{now}" SessionIndex="_session1"/>
+		<saml:AttributeStatement>
+			<saml:Attribute Name="email"><saml:AttributeValue xsi:type="xs:string" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">test@example.com</saml:AttributeValue></saml:Attribute>
+			<saml:Attribute Name="groups"><saml:AttributeValue>a</saml:AttributeValue><saml:AttributeValue>b &amp; c</saml:AttributeValue></saml:Attribute>
+		</saml:AttributeStatement>
+	</saml:Assertion>
+</samlp:Response>`
+
+func newTestCertificate(t *testing.T, key crypto.Signer) *x509.Certificate {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test idp"},
+		NotBefore:    testNow.Add(-time.Hour),
+		NotAfter:     testNow.Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert
+}
+
+func newTestServiceProvider(cert *x509.Certificate) *ServiceProvider {
+	return &ServiceProvider{
+		EntityId:       "https://example.com/saml/metadata",
+		AcsUrl:         "https://example.com/saml/acs",
+		IdpEntityId:    "https://idp.example.com",
+		IdpSsoUrl:      "https://idp.example.com/sso?app=test",
+		IdpCertificate: cert,
+		Now: func() time.Time {
+			return testNow
+		},
+	}
+}
+
+func findTestElement(el *xmlElement, id string) *xmlElement {
+	if el.attr("ID") == id {
+		return el
+	}
+
+	for _, child := range el.children {
+		if c, ok := child.(*xmlElement); ok {
+			if found := findTestElement(c, id); found != nil {
+				return found
+			}
+		}
+	}
+
+	return nil
+}
+
+// signTestElement fills the signature template placeholders
+// of the element with the specified id.
+func signTestElement(t *testing.T, doc string, id string, key crypto.Signer) string {
+	root, err := parseXml([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	el := findTestElement(root, id)
+	signature := el.childElement(dsigNamespace, "Signature")
+
+	digest := sha256.Sum256(canonicalize(el, signature, []string{"xs"}))
+	doc = strings.Replace(doc, "DIGEST_VALUE_"+id, base64.StdEncoding.EncodeToString(digest[:]), 1)
+
+	// reparse to canonicalize the SignedInfo with the updated digest
+	root, err = parseXml([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signedInfo := findTestElement(root, id).
+		childElement(dsigNamespace, "Signature").
+		childElement(dsigNamespace, "SignedInfo")
+
+	hash := sha256.Sum256(canonicalize(signedInfo, nil, nil))
+
+	var sig []byte
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, hash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig = make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+	default:
+		sig, err = key.Sign(rand.Reader, hash[:], crypto.SHA256)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return strings.Replace(doc, "SIGNATURE_VALUE_"+id, base64.StdEncoding.EncodeToString(sig), 1)
+}
+
+type testResponseOptions struct {
+	replacements  map[string]string
+	signResponse  bool
+	signAssertion bool
+	key           crypto.Signer
+	assertionKey  crypto.Signer // default to key
+	afterSignFunc func(doc string) string
+}
+
+func testSignatureAlgorithm(key crypto.Signer) string {
+	if _, ok := key.(*ecdsa.PrivateKey); ok {
+		return algEcdsaSha256
+	}
+
+	return algRsaSha256
+}
+
+func newTestResponse(t *testing.T, opts testResponseOptions) string {
+	assertionKey := opts.assertionKey
+	if assertionKey == nil {
+		assertionKey = opts.key
+	}
+
+	values := map[string]string{
+		"{now}":          testNow.Format(time.RFC3339),
+		"{destination}":  "https://example.com/saml/acs",
+		"{recipient}":    "https://example.com/saml/acs",
+		"{requestId}":    testRequestId,
+		"{issuer}":       "https://idp.example.com",
+		"{status}":       StatusSuccess,
+		"{audience}":     "https://example.com/saml/metadata",
+		"{notBefore}":    testNow.Add(-time.Minute).Format(time.RFC3339),
+		"{notOnOrAfter}": testNow.Add(5 * time.Minute).Format(time.RFC3339),
+	}
+	for k, v := range opts.replacements {
+		values[k] = v
+	}
+
+	values["{responseSignature}"] = ""
+	if opts.signResponse {
+		values["{responseSignature}"] = strings.NewReplacer("{algorithm}", testSignatureAlgorithm(opts.key), "{id}", "_response1").Replace(testSignatureTemplate)
+	}
+
+	values["{assertionSignature}"] = ""
+	if opts.signAssertion {
+		values["{assertionSignature}"] = strings.NewReplacer("{algorithm}", testSignatureAlgorithm(assertionKey), "{id}", "_assertion1").Replace(testSignatureTemplate)
+	}
+
+	pairs := make([]string, 0, len(values)*2)
+	for k, v := range values {
+		pairs = append(pairs, k, v)
+	}
+	doc := strings.NewReplacer(pairs...).Replace(testResponseTemplate)
+
+	// the inner assertion must be signed first
+	if opts.signAssertion {
+		doc = signTestElement(t, doc, "_assertion1", assertionKey)
+	}
+	if opts.signResponse {
+		doc = signTestElement(t, doc, "_response1", opts.key)
+	}
+
+	if opts.afterSignFunc != nil {
+		doc = opts.afterSignFunc(doc)
+	}
+
+	return base64.StdEncoding.EncodeToString([]byte(doc))
+}
+
+func TestParseCertificate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := newTestCertificate(t, key)
+
+	pemCert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	base64Cert := base64.StdEncoding.EncodeToString(cert.Raw)
+
+	scenarios := []struct {
+		raw         string
+		expectError bool
+	}{
+		{"", true},
+		{"invalid", true},
+		{base64.StdEncoding.EncodeToString([]byte("invalid")), true},
+		{pemCert, false},
+		{"\n" + base64Cert[:10] + "\n  " + base64Cert[10:] + "\n", false},
+	}
+
+	for i, s := range scenarios {
+		result, err := ParseCertificate(s.raw)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%d] Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if !hasErr && !result.Equal(cert) {
+			t.Errorf("[%d] The parsed certificate doesn't match the original one", i)
+		}
+	}
+}
+
+func TestNewRequestId(t *testing.T) {
+	id1 := NewRequestId()
+	id2 := NewRequestId()
+
+	if !strings.HasPrefix(id1, "_") || len(id1) != 41 {
+		t.Fatalf("Invalid request id %q", id1)
+	}
+
+	if id1 == id2 {
+		t.Fatalf("Expected different request ids, got %q", id1)
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	sp := newTestServiceProvider(nil)
+
+	raw, err := sp.Metadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := parseXml(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !root.is(NamespaceMetadata, "EntityDescriptor") || root.attr("entityID") != sp.EntityId {
+		t.Fatalf("Invalid entity descriptor: \n%s", raw)
+	}
+
+	spSso := root.childElement(NamespaceMetadata, "SPSSODescriptor")
+	if spSso == nil || spSso.attr("WantAssertionsSigned") != "true" {
+		t.Fatalf("Invalid SPSSODescriptor: \n%s", raw)
+	}
+
+	acs := spSso.childElement(NamespaceMetadata, "AssertionConsumerService")
+	if acs == nil || acs.attr("Location") != sp.AcsUrl || acs.attr("Binding") != BindingHttpPost {
+		t.Fatalf("Invalid AssertionConsumerService: \n%s", raw)
+	}
+}
+
+func TestAuthnRequestUrl(t *testing.T) {
+	sp := newTestServiceProvider(nil)
+
+	rawUrl, err := sp.AuthnRequestUrl("_abc", "test_state")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u.Host != "idp.example.com" || u.Path != "/sso" || u.Query().Get("app") != "test" {
+		t.Fatalf("Expected the IdP SSO url, got %q", rawUrl)
+	}
+
+	if relayState := u.Query().Get("RelayState"); relayState != "test_state" {
+		t.Fatalf("Expected RelayState test_state, got %q", relayState)
+	}
+
+	compressed, err := base64.StdEncoding.DecodeString(u.Query().Get("SAMLRequest"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := io.ReadAll(flate.NewReader(bytes.NewReader(compressed)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request, err := parseXml(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !request.is(NamespaceProtocol, "AuthnRequest") {
+		t.Fatalf("Expected AuthnRequest, got \n%s", raw)
+	}
+
+	expectedAttrs := map[string]string{
+		"ID":                          "_abc",
+		"Version":                     "2.0",
+		"IssueInstant":                "2023-01-20T10:00:00Z",
+		"Destination":                 sp.IdpSsoUrl,
+		"AssertionConsumerServiceURL": sp.AcsUrl,
+		"ProtocolBinding":             BindingHttpPost,
+	}
+	for name, value := range expectedAttrs {
+		if v := request.attr(name); v != value {
+			t.Errorf("Expected %s %q, got %q", name, value, v)
+		}
+	}
+
+	issuer := request.childElement(NamespaceAssertion, "Issuer")
+	if issuer == nil || issuer.text() != sp.EntityId {
+		t.Fatalf("Invalid AuthnRequest issuer: \n%s", raw)
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaCert := newTestCertificate(t, rsaKey)
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaCert := newTestCertificate(t, ecdsaKey)
+
+	scenarios := []struct {
+		name        string
+		cert        *x509.Certificate
+		requestId   string
+		opts        testResponseOptions
+		encoded     string // if set, opts are ignored
+		expectError bool
+	}{
+		{
+			name:        "invalid base64",
+			cert:        rsaCert,
+			requestId:   testRequestId,
+			encoded:     "!invalid",
+			expectError: true,
+		},
+		{
+			name:        "invalid xml",
+			cert:        rsaCert,
+			requestId:   testRequestId,
+			encoded:     base64.StdEncoding.EncodeToString([]byte("<a>")),
+			expectError: true,
+		},
+		{
+			name:        "unsigned response and assertion",
+			cert:        rsaCert,
+			requestId:   testRequestId,
+			opts:        testResponseOptions{key: rsaKey},
+			expectError: true,
+		},
+		{
+			name:        "missing certificate",
+			requestId:   testRequestId,
+			opts:        testResponseOptions{key: rsaKey, signAssertion: true},
+			expectError: true,
+		},
+		{
+			name:        "different certificate",
+			cert:        ecdsaCert,
+			requestId:   testRequestId,
+			opts:        testResponseOptions{key: rsaKey, signAssertion: true},
+			expectError: true,
+		},
+		{
+			name:        "different request id",
+			cert:        rsaCert,
+			requestId:   "_other",
+			opts:        testResponseOptions{key: rsaKey, signAssertion: true},
+			expectError: true,
+		},
+		{
+			name:      "invalid destination",
+			cert:      rsaCert,
+			requestId: testRequestId,
+			opts: testResponseOptions{
+				key:           rsaKey,
+				signAssertion: true,
+				replacements:  map[string]string{"{destination}": "https://example.com/other"},
+			},
+			expectError: true,
+		},
+		{
+			name:      "invalid issuer",
+			cert:      rsaCert,
+			requestId: testRequestId,
+			opts: testResponseOptions{
+				key:           rsaKey,
+				signAssertion: true,
+				replacements:  map[string]string{"{issuer}": "https://other.example.com"},
+			},
+			expectError: true,
+		},
+		{
+			name:      "unsuccessful status",
+			cert:      rsaCert,
+			requestId: testRequestId,
+			opts: testResponseOptions{
+				key:           rsaKey,
+				signAssertion: true,
+				replacements:  map[string]string{"{status}": "urn:oasis:names:tc:SAML:2.0:status:Requester"},
+			},
+			expectError: true,
+		},
+		{
+			name:      "invalid recipient",
+			cert:      rsaCert,
+			requestId: testRequestId,
+			opts: testResponseOptions{
+				key:           rsaKey,
+				signAssertion: true,
+				replacements:  map[string]string{"{recipient}": "https://example.com/other"},
+			},
+			expectError: true,
+		},
+		{
+			name:      "invalid audience",
+			cert:      rsaCert,
+			requestId: testRequestId,
+			opts: testResponseOptions{
+				key:           rsaKey,
+				signAssertion: true,
+				replacements:  map[string]string{"{audience}": "https://example.com/other"},
+			},
+			expectError: true,
+		},
+		{
+			name:      "not yet valid assertion",
+			cert:      rsaCert,
+			requestId: testRequestId,
+			opts: testResponseOptions{
+				key:           rsaKey,
+				signAssertion: true,
+				replacements:  map[string]string{"{notBefore}": testNow.Add(5 * time.Minute).Format(time.RFC3339)},
+			},
+			expectError: true,
+		},
+		{
+			name:      "expired assertion",
+			cert:      rsaCert,
+			requestId: testRequestId,
+			opts: testResponseOptions{
+				key:           rsaKey,
+				signAssertion: true,
+				replacements:  map[string]string{"{notOnOrAfter}": testNow.Add(-5 * time.Minute).Format(time.RFC3339)},
+			},
+			expectError: true,
+		},
+		{
+			name:      "tampered signed assertion",
+			cert:      rsaCert,
+			requestId: testRequestId,
+			opts: testResponseOptions{
+				key:           rsaKey,
+				signAssertion: true,
+				afterSignFunc: func(doc string) string {
+					return strings.Replace(doc, "test@example.com</saml:NameID>", "admin@example.com</saml:NameID>", 1)
+				},
+			},
+			expectError: true,
+		},
+		{
+			name:      "tampered signed response",
+			cert:      rsaCert,
+			requestId: testRequestId,
+			opts: testResponseOptions{
+				key:          rsaKey,
+				signResponse: true,
+				afterSignFunc: func(doc string) string {
+					return strings.Replace(doc, "test@example.com</saml:NameID>", "admin@example.com</saml:NameID>", 1)
+				},
+			},
+			expectError: true,
+		},
+		{
+			name:      "valid signed response with invalid assertion signature",
+			cert:      rsaCert,
+			requestId: testRequestId,
+			opts: testResponseOptions{
+				key:           rsaKey,
+				assertionKey:  ecdsaKey,
+				signResponse:  true,
+				signAssertion: true,
+			},
+			expectError: true,
+		},
+		{
+			name:      "injected second assertion",
+			cert:      rsaCert,
+			requestId: testRequestId,
+			opts: testResponseOptions{
+				key:           rsaKey,
+				signAssertion: true,
+				afterSignFunc: func(doc string) string {
+					return strings.Replace(doc, "</samlp:Response>", `<saml:Assertion ID="_assertion2"/></samlp:Response>`, 1)
+				},
+			},
+			expectError: true,
+		},
+		{
+			name:      "encrypted assertion",
+			cert:      rsaCert,
+			requestId: testRequestId,
+			opts: testResponseOptions{
+				key:           rsaKey,
+				signAssertion: true,
+				afterSignFunc: func(doc string) string {
+					return strings.Replace(doc, "</samlp:Response>", `<saml:EncryptedAssertion/></samlp:Response>`, 1)
+				},
+			},
+			expectError: true,
+		},
+		{
+			name:        "signed assertion (rsa)",
+			cert:        rsaCert,
+			requestId:   testRequestId,
+			opts:        testResponseOptions{key: rsaKey, signAssertion: true},
+			expectError: false,
+		},
+		{
+			name:        "signed response (rsa)",
+			cert:        rsaCert,
+			requestId:   testRequestId,
+			opts:        testResponseOptions{key: rsaKey, signResponse: true},
+			expectError: false,
+		},
+		{
+			name:        "signed response and assertion (ecdsa)",
+			cert:        ecdsaCert,
+			requestId:   testRequestId,
+			opts:        testResponseOptions{key: ecdsaKey, signResponse: true, signAssertion: true},
+			expectError: false,
+		},
+		{
+			name:      "expired assertion within the allowed clock skew",
+			cert:      rsaCert,
+			requestId: testRequestId,
+			opts: testResponseOptions{
+				key:           rsaKey,
+				signAssertion: true,
+				replacements:  map[string]string{"{notOnOrAfter}": testNow.Add(-time.Minute).Format(time.RFC3339)},
+			},
+			expectError: false,
+		},
+	}
+
+	for _, s := range scenarios {
+		encoded := s.encoded
+		if encoded == "" {
+			encoded = newTestResponse(t, s.opts)
+		}
+
+		sp := newTestServiceProvider(s.cert)
+
+		assertion, err := sp.ParseResponse(encoded, s.requestId)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		if assertion.NameId != "test@example.com" {
+			t.Errorf("[%s] Expected NameId test@example.com, got %q", s.name, assertion.NameId)
+		}
+
+		if assertion.NameIdFormat != NameIdFormatEmail {
+			t.Errorf("[%s] Expected NameIdFormat %q, got %q", s.name, NameIdFormatEmail, assertion.NameIdFormat)
+		}
+
+		if assertion.SessionIndex != "_session1" {
+			t.Errorf("[%s] Expected SessionIndex _session1, got %q", s.name, assertion.SessionIndex)
+		}
+
+		if v := assertion.Attribute("email"); v != "test@example.com" {
+			t.Errorf("[%s] Expected email attribute test@example.com, got %q", s.name, v)
+		}
+
+		if v := assertion.Attributes["groups"]; len(v) != 2 || v[0] != "a" || v[1] != "b & c" {
+			t.Errorf("[%s] Expected groups attribute [a, b & c], got %v", s.name, v)
+		}
+
+		if v := assertion.Attribute("missing"); v != "" {
+			t.Errorf("[%s] Expected empty missing attribute, got %q", s.name, v)
+		}
+	}
+}
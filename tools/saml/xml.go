@@ -0,0 +1,208 @@
+package saml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// xmlNamespace is the implicitly declared "xml" prefix namespace.
+const xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+
+// maxXmlDepth is the max allowed nesting of the parsed xml elements.
+const maxXmlDepth = 64
+
+// xmlElement defines a single parsed xml element that keeps
+// the raw (aka. prefixed) element and attribute names
+// as required by the xml signature canonicalization.
+type xmlElement struct {
+	parent *xmlElement
+
+	prefix string
+	local  string
+
+	// attrs are the regular (non namespace declaration) attributes
+	attrs []xml.Attr
+
+	// nsDecls are the namespace declarations of the element ("" for the default one)
+	nsDecls map[string]string
+
+	// children holds the child *xmlElement and string (character data) nodes
+	children []any
+}
+
+// parseXml parses the provided xml document and returns its root element.
+//
+// Document type declarations are not allowed and the comments
+// and processing instructions are ignored.
+func parseXml(data []byte) (*xmlElement, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = true
+
+	var root, current *xmlElement
+	var depth int
+	ids := map[string]struct{}{}
+
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if root != nil && current == nil {
+				return nil, errors.New("xml: multiple root elements")
+			}
+
+			depth++
+			if depth > maxXmlDepth {
+				return nil, errors.New("xml: max nesting depth reached")
+			}
+
+			el := &xmlElement{
+				parent:  current,
+				prefix:  t.Name.Space,
+				local:   t.Name.Local,
+				nsDecls: map[string]string{},
+			}
+
+			for _, attr := range t.Attr {
+				switch {
+				case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+					el.nsDecls[""] = attr.Value
+				case attr.Name.Space == "xmlns":
+					el.nsDecls[attr.Name.Local] = attr.Value
+				default:
+					// the signature references rely on unique ids
+					if attr.Name.Space == "" && attr.Name.Local == "ID" {
+						if _, ok := ids[attr.Value]; ok {
+							return nil, fmt.Errorf("xml: duplicated ID %q", attr.Value)
+						}
+						ids[attr.Value] = struct{}{}
+					}
+					el.attrs = append(el.attrs, attr)
+				}
+			}
+
+			if current == nil {
+				root = el
+			} else {
+				current.children = append(current.children, el)
+			}
+			current = el
+		case xml.EndElement:
+			if current == nil || current.prefix != t.Name.Space || current.local != t.Name.Local {
+				return nil, errors.New("xml: unexpected end element")
+			}
+			current = current.parent
+			depth--
+		case xml.CharData:
+			if current != nil {
+				current.children = append(current.children, string(t))
+			} else if len(bytes.TrimSpace(t)) > 0 {
+				return nil, errors.New("xml: unexpected character data outside of the root element")
+			}
+		case xml.Directive:
+			return nil, errors.New("xml: document type declarations are not allowed")
+		}
+	}
+
+	if root == nil || current != nil {
+		return nil, errors.New("xml: incomplete document")
+	}
+
+	return root, nil
+}
+
+// lookupNamespace returns the in-scope namespace of the specified prefix.
+func (el *xmlElement) lookupNamespace(prefix string) (string, bool) {
+	if prefix == "xml" {
+		return xmlNamespace, true
+	}
+
+	for e := el; e != nil; e = e.parent {
+		if ns, ok := e.nsDecls[prefix]; ok {
+			return ns, true
+		}
+	}
+
+	return "", false
+}
+
+// namespace returns the element namespace.
+func (el *xmlElement) namespace() string {
+	ns, _ := el.lookupNamespace(el.prefix)
+	return ns
+}
+
+// is checks whether the element has the specified namespace and local name.
+func (el *xmlElement) is(namespace string, local string) bool {
+	return el.local == local && el.namespace() == namespace
+}
+
+// attr returns the value of the specified unqualified attribute.
+func (el *xmlElement) attr(local string) string {
+	for _, attr := range el.attrs {
+		if attr.Name.Space == "" && attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+// hasAttr checks whether the element has the specified unqualified attribute.
+func (el *xmlElement) hasAttr(local string) bool {
+	for _, attr := range el.attrs {
+		if attr.Name.Space == "" && attr.Name.Local == local {
+			return true
+		}
+	}
+
+	return false
+}
+
+// childElements returns the direct child elements with
+// the specified namespace and local name.
+func (el *xmlElement) childElements(namespace string, local string) []*xmlElement {
+	result := []*xmlElement{}
+
+	for _, child := range el.children {
+		if c, ok := child.(*xmlElement); ok && c.is(namespace, local) {
+			result = append(result, c)
+		}
+	}
+
+	return result
+}
+
+// childElement returns the first direct child element with
+// the specified namespace and local name (or nil if missing).
+func (el *xmlElement) childElement(namespace string, local string) *xmlElement {
+	children := el.childElements(namespace, local)
+	if len(children) == 0 {
+		return nil
+	}
+
+	return children[0]
+}
+
+// text returns the trimmed concatenated character data of the element.
+func (el *xmlElement) text() string {
+	var sb strings.Builder
+
+	for _, child := range el.children {
+		if s, ok := child.(string); ok {
+			sb.WriteString(s)
+		}
+	}
+
+	return strings.TrimSpace(sb.String())
+}
@@ -0,0 +1,246 @@
+package sanitizer
+
+import (
+	"strings"
+
+	"github.com/pocketbase/pocketbase/tools/list"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// DefaultHtmlAllowedTags is the list with the (lowercased) html elements
+// that are preserved by default during the html sanitization.
+var DefaultHtmlAllowedTags = []string{
+	"p", "br", "hr", "span", "div",
+	"h1", "h2", "h3", "h4", "h5", "h6",
+	"b", "strong", "i", "em", "u", "s", "strike", "sub", "sup", "mark", "small",
+	"a", "img",
+	"ul", "ol", "li",
+	"blockquote", "code", "pre",
+	"table", "thead", "tbody", "tfoot", "tr", "th", "td",
+}
+
+// DefaultHtmlAllowedAttributes is the list with the (lowercased) html
+// attributes that are preserved by default during the html sanitization.
+var DefaultHtmlAllowedAttributes = []string{
+	"href", "src", "alt", "title",
+}
+
+// HtmlDeniedTags is a list with the (lowercased) html elements that
+// are always removed together with their content (even if allowed).
+var HtmlDeniedTags = []string{
+	"script",
+	"style",
+	"iframe",
+	"frame",
+	"frameset",
+	"object",
+	"embed",
+	"applet",
+	"template",
+	"noscript",
+	"base",
+	"meta",
+	"link",
+}
+
+// htmlDeniedValuePrefixes is a list with the (lowercased) attribute
+// value prefixes that are removed during the html sanitization.
+var htmlDeniedValuePrefixes = []string{
+	"javascript:",
+	"vbscript:",
+	"data:text/html",
+}
+
+// htmlBlockTags is a list with the (lowercased) html elements
+// that are separated with a whitespace in the plain text content.
+var htmlBlockTags = []string{
+	"p", "br", "hr", "div", "li", "blockquote", "pre", "tr", "th", "td",
+	"h1", "h2", "h3", "h4", "h5", "h6",
+}
+
+// SanitizeHtml removes from the provided html content all elements and
+// attributes that are not in the allowedTags and allowedAttributes lists.
+//
+// The content of the not allowed elements is preserved, except for the
+// [HtmlDeniedTags] (scripts, styles, embedded documents, etc.) that are
+// always removed together with their content.
+//
+// Event handler attributes, "javascript:" links and comments are
+// always removed regardless of the allow lists.
+func SanitizeHtml(content string, allowedTags []string, allowedAttributes []string) (string, error) {
+	nodes, err := parseHtmlFragment(content)
+	if err != nil {
+		return "", err
+	}
+
+	tags := toLowerSet(allowedTags)
+	attrs := toLowerSet(allowedAttributes)
+
+	var b strings.Builder
+
+	for _, node := range nodes {
+		writeSanitizedNode(&b, node, tags, attrs)
+	}
+
+	return b.String(), nil
+}
+
+// HtmlToText extracts the plain text content of the provided html
+// (excluding the content of the [HtmlDeniedTags]) with normalized whitespaces.
+func HtmlToText(content string) (string, error) {
+	nodes, err := parseHtmlFragment(content)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+
+	for _, node := range nodes {
+		writeTextNode(&b, node)
+	}
+
+	return strings.Join(strings.Fields(b.String()), " "), nil
+}
+
+func parseHtmlFragment(content string) ([]*html.Node, error) {
+	return html.ParseFragment(strings.NewReader(content), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+}
+
+func writeSanitizedNode(b *strings.Builder, node *html.Node, tags map[string]struct{}, attrs map[string]struct{}) {
+	switch node.Type {
+	case html.TextNode:
+		b.WriteString(html.EscapeString(node.Data))
+		return
+	case html.ElementNode:
+		// handled below
+	case html.CommentNode, html.DoctypeNode:
+		return
+	default:
+		writeSanitizedChildren(b, node, tags, attrs)
+		return
+	}
+
+	tag := strings.ToLower(node.Data)
+
+	if isHtmlDeniedTag(tag) {
+		return
+	}
+
+	if _, ok := tags[tag]; !ok {
+		// unwrap the not allowed element
+		writeSanitizedChildren(b, node, tags, attrs)
+		return
+	}
+
+	b.WriteString("<")
+	b.WriteString(tag)
+	for _, attr := range node.Attr {
+		key := strings.ToLower(attr.Key)
+		if _, ok := attrs[key]; !ok || attr.Namespace != "" || isHtmlDeniedAttr(key, attr.Val) {
+			continue
+		}
+		b.WriteString(" ")
+		b.WriteString(key)
+		b.WriteString(`="`)
+		b.WriteString(html.EscapeString(attr.Val))
+		b.WriteString(`"`)
+	}
+	b.WriteString(">")
+
+	if isHtmlVoidTag(tag) {
+		return
+	}
+
+	writeSanitizedChildren(b, node, tags, attrs)
+
+	b.WriteString("</")
+	b.WriteString(tag)
+	b.WriteString(">")
+}
+
+func writeSanitizedChildren(b *strings.Builder, node *html.Node, tags map[string]struct{}, attrs map[string]struct{}) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		writeSanitizedNode(b, child, tags, attrs)
+	}
+}
+
+func writeTextNode(b *strings.Builder, node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		b.WriteString(node.Data)
+		return
+	case html.CommentNode, html.DoctypeNode:
+		return
+	}
+
+	tag := strings.ToLower(node.Data)
+
+	if node.Type == html.ElementNode && isHtmlDeniedTag(tag) {
+		return
+	}
+
+	isBlock := node.Type == html.ElementNode && list.ExistInSlice(tag, htmlBlockTags)
+
+	if isBlock {
+		b.WriteString(" ")
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		writeTextNode(b, child)
+	}
+
+	if isBlock {
+		b.WriteString(" ")
+	}
+}
+
+func isHtmlDeniedTag(tag string) bool {
+	return list.ExistInSlice(tag, HtmlDeniedTags)
+}
+
+func isHtmlVoidTag(tag string) bool {
+	switch tag {
+	case "area", "br", "col", "hr", "img", "input", "source", "track", "wbr":
+		return true
+	}
+
+	return false
+}
+
+func isHtmlDeniedAttr(key string, value string) bool {
+	// event handlers (eg. onload, onclick)
+	if strings.HasPrefix(key, "on") {
+		return true
+	}
+
+	// normalize the value since browsers ignore the whitespaces and control chars in urls
+	normalized := strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(value))
+
+	for _, prefix := range htmlDeniedValuePrefixes {
+		if strings.HasPrefix(normalized, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func toLowerSet(items []string) map[string]struct{} {
+	result := make(map[string]struct{}, len(items))
+
+	for _, item := range items {
+		result[strings.ToLower(item)] = struct{}{}
+	}
+
+	return result
+}
@@ -0,0 +1,98 @@
+package sanitizer_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/sanitizer"
+)
+
+func TestSanitizeHtml(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		content  string
+		tags     []string
+		attrs    []string
+		expected string
+	}{
+		{
+			"empty",
+			``,
+			sanitizer.DefaultHtmlAllowedTags,
+			sanitizer.DefaultHtmlAllowedAttributes,
+			``,
+		},
+		{
+			"plain text",
+			`a < b & "c"`,
+			sanitizer.DefaultHtmlAllowedTags,
+			sanitizer.DefaultHtmlAllowedAttributes,
+			`a &lt; b &amp; &#34;c&#34;`,
+		},
+		{
+			"safe html",
+			`<h1>Title</h1><p>Hello <a href="https://example.com" title="test">world</a><br>` +
+				`<img src="/test.png" alt="test"></p>`,
+			sanitizer.DefaultHtmlAllowedTags,
+			sanitizer.DefaultHtmlAllowedAttributes,
+			`<h1>Title</h1><p>Hello <a href="https://example.com" title="test">world</a><br>` +
+				`<img src="/test.png" alt="test"></p>`,
+		},
+		{
+			"dangerous html",
+			`<!-- comment --><P ONCLICK="alert(1)" class="test">a</P>` +
+				`<script>alert(2)</script>` +
+				`<STYLE>body{}</STYLE>` +
+				`<iframe src="https://example.com">b</iframe>` +
+				`<a href=" java&#x09;script:alert(3)">c</a>` +
+				`<img src="data:text/html;base64,PHNjcmlwdD4=" onerror="alert(4)">` +
+				`<custom>d<b>e</b></custom>`,
+			sanitizer.DefaultHtmlAllowedTags,
+			sanitizer.DefaultHtmlAllowedAttributes,
+			`<p>a</p><a>c</a><img>d<b>e</b>`,
+		},
+		{
+			"custom allow lists",
+			`<h1 class="a" id="b">Title</h1><p class="c">Text <script>alert(1)</script></p>`,
+			[]string{"P", "script"},
+			[]string{"CLASS", "onclick"},
+			`Title<p class="c">Text </p>`,
+		},
+	}
+
+	for _, s := range scenarios {
+		result, err := sanitizer.SanitizeHtml(s.content, s.tags, s.attrs)
+		if err != nil {
+			t.Errorf("[%s] Unexpected error %v", s.name, err)
+			continue
+		}
+
+		if result != s.expected {
+			t.Errorf("[%s] Expected \n%s, \ngot \n%s", s.name, s.expected, result)
+		}
+	}
+}
+
+func TestHtmlToText(t *testing.T) {
+	scenarios := []struct {
+		content  string
+		expected string
+	}{
+		{``, ``},
+		{`test`, `test`},
+		{`<p>Hello <b>world</b>!</p><p>Second   line</p>`, `Hello world! Second line`},
+		{`<ul><li>a</li><li>b</li></ul><br>c &amp; d`, `a b c & d`},
+		{`<p>a</p><script>alert(1)</script><style>b{}</style><!-- c -->`, `a`},
+	}
+
+	for i, s := range scenarios {
+		result, err := sanitizer.HtmlToText(s.content)
+		if err != nil {
+			t.Errorf("(%d) Unexpected error %v", i, err)
+			continue
+		}
+
+		if result != s.expected {
+			t.Errorf("(%d) Expected %q, got %q", i, s.expected, result)
+		}
+	}
+}
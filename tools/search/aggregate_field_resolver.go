@@ -0,0 +1,98 @@
+package search
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+)
+
+var aggregateFieldRegex = regexp.MustCompile(`^(\w+)\(([\w\.\*]*)\)$`)
+
+// aggregateFunctions is a list with the supported AggregateFieldResolver
+// functions and their db expression template ("{field}" is replaced with
+// the resolved function argument).
+var aggregateFunctions = map[string]string{
+	// aggregates
+	"count": "COUNT({field})",
+	"sum":   "SUM({field})",
+	"avg":   "AVG({field})",
+	"min":   "MIN({field})",
+	"max":   "MAX({field})",
+
+	// date groups
+	"hour":  "strftime('%Y-%m-%d %H:00:00', {field})",
+	"day":   "strftime('%Y-%m-%d 00:00:00', {field})",
+	"month": "strftime('%Y-%m-01 00:00:00', {field})",
+
+	// url path (aka. without the query parameters)
+	"path": "(CASE WHEN instr({field}, '?') > 0 THEN substr({field}, 1, instr({field}, '?') - 1) ELSE {field} END)",
+}
+
+// NewAggregateFieldResolver creates a new `AggregateFieldResolver` with the
+// provided `allowedFields`.
+//
+// Each `allowedFields` could be a plain string (eg. "name")
+// or a regexp pattern (eg. `^\w+[\w\.]*$`).
+func NewAggregateFieldResolver(allowedFields ...string) *AggregateFieldResolver {
+	return &AggregateFieldResolver{
+		SimpleFieldResolver: NewSimpleFieldResolver(allowedFields...),
+	}
+}
+
+// AggregateFieldResolver extends the `SimpleFieldResolver` with support
+// for aggregate and grouping functions applied over its allowed fields.
+//
+// Supported functions:
+//
+//	count(field), count(*), sum(field), avg(field), min(field), max(field)
+//	hour(field), day(field), month(field) - date groups
+//	path(field) - url path without the query parameters
+type AggregateFieldResolver struct {
+	*SimpleFieldResolver
+}
+
+// Resolve implements `search.Resolve` interface.
+//
+// Returns error if `field` is not in `r.allowedFields` or
+// the applied function is not supported.
+func (r *AggregateFieldResolver) Resolve(field string) (resultName string, placeholderParams dbx.Params, err error) {
+	return ResolveAggregateField(r.SimpleFieldResolver, field)
+}
+
+// ResolveAggregateField resolves the provided field with the specified
+// resolver, applying the aggregate or grouping function (if any)
+// over the resolved function argument.
+//
+// See [AggregateFieldResolver] for the list with the supported functions.
+func ResolveAggregateField(resolver FieldResolver, field string) (resultName string, placeholderParams dbx.Params, err error) {
+	matches := aggregateFieldRegex.FindStringSubmatch(field)
+	if len(matches) == 0 {
+		return resolver.Resolve(field)
+	}
+
+	fn := strings.ToLower(matches[1])
+	arg := matches[2]
+
+	template, ok := aggregateFunctions[fn]
+	if !ok {
+		return "", nil, fmt.Errorf("Unsupported function in %q.", field)
+	}
+
+	var name string
+
+	if arg == "" || arg == "*" {
+		if fn != "count" {
+			return "", nil, fmt.Errorf("Missing function argument in %q.", field)
+		}
+		name = "*"
+	} else {
+		name, placeholderParams, err = resolver.Resolve(arg)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	return strings.ReplaceAll(template, "{field}", name), placeholderParams, nil
+}
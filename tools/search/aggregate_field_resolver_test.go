@@ -0,0 +1,90 @@
+package search_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+func TestAggregateFieldResolverResolve(t *testing.T) {
+	r := search.NewAggregateFieldResolver("test", "created", "url")
+
+	scenarios := []struct {
+		fieldName   string
+		expectError bool
+		expectName  string
+	}{
+		{"", true, ""},
+		{"unknown", true, ""},
+		{"test", false, "[[test]]"},
+		{"count()", false, "COUNT(*)"},
+		{"count(*)", false, "COUNT(*)"},
+		{"COUNT(test)", false, "COUNT([[test]])"},
+		{"sum(test)", false, "SUM([[test]])"},
+		{"avg(test)", false, "AVG([[test]])"},
+		{"min(test)", false, "MIN([[test]])"},
+		{"max(test)", false, "MAX([[test]])"},
+		{"sum()", true, ""},
+		{"sum(*)", true, ""},
+		{"sum(unknown)", true, ""},
+		{"unknown(test)", true, ""},
+		{"sum(count(test))", true, ""},
+		{"hour(created)", false, "strftime('%Y-%m-%d %H:00:00', [[created]])"},
+		{"day(created)", false, "strftime('%Y-%m-%d 00:00:00', [[created]])"},
+		{"month(created)", false, "strftime('%Y-%m-01 00:00:00', [[created]])"},
+		{"path(url)", false, "(CASE WHEN instr([[url]], '?') > 0 THEN substr([[url]], 1, instr([[url]], '?') - 1) ELSE [[url]] END)"},
+	}
+
+	for i, s := range scenarios {
+		name, params, err := r.Resolve(s.fieldName)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if name != s.expectName {
+			t.Errorf("(%d) Expected name %q, got %q", i, s.expectName, name)
+		}
+
+		// params should be empty
+		if len(params) != 0 {
+			t.Errorf("(%d) Expected 0 params, got %v", i, params)
+		}
+	}
+}
+
+func TestResolveAggregateField(t *testing.T) {
+	r := search.NewSimpleFieldResolver("title", `^rel\.\w+$`)
+
+	scenarios := []struct {
+		fieldName   string
+		expectError bool
+		expectName  string
+	}{
+		{"", true, ""},
+		{"unknown", true, ""},
+		{"title", false, "[[title]]"},
+		{"rel.title", false, "[[rel.title]]"},
+		{"count(*)", false, "COUNT(*)"},
+		{"sum(rel.title)", false, "SUM([[rel.title]])"},
+		{"day(title)", false, "strftime('%Y-%m-%d 00:00:00', [[title]])"},
+		{"avg(unknown)", true, ""},
+		{"unknown(title)", true, ""},
+	}
+
+	for i, s := range scenarios {
+		name, _, err := search.ResolveAggregateField(r, s.fieldName)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if name != s.expectName {
+			t.Errorf("(%d) Expected name %q, got %q", i, s.expectName, name)
+		}
+	}
+}
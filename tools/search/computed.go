@@ -0,0 +1,253 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ComputedExpr is an arithmetic expression over field identifiers and
+// number literals (eg. "price * quantity - discount").
+//
+// Supported operators are "+", "-", "*", "/", "%", the unary "-"
+// and parenthesized sub-expressions.
+//
+// Example:
+//
+//	var expr ComputedExpr = "price * (1 + vat / 100)"
+//	sql, err := expr.Build(func(identifier string) (string, error) {
+//		return "[[products." + identifier + "]]", nil
+//	})
+type ComputedExpr string
+
+// Identifiers returns the unique field identifiers used in the
+// current expression (in order of appearance).
+//
+// Returns an error if the expression is not valid.
+func (e ComputedExpr) Identifiers() ([]string, error) {
+	result := []string{}
+
+	_, err := e.Build(func(identifier string) (string, error) {
+		for _, existing := range result {
+			if existing == identifier {
+				return identifier, nil
+			}
+		}
+		result = append(result, identifier)
+		return identifier, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Build parses the current expression and returns its raw db expression
+// with each identifier replaced by the result of resolve.
+func (e ComputedExpr) Build(resolve func(identifier string) (string, error)) (string, error) {
+	tokens, err := scanComputedExpr(string(e))
+	if err != nil {
+		return "", err
+	}
+
+	if len(tokens) == 0 {
+		return "", errors.New("Empty computed expression.")
+	}
+
+	p := &computedParser{tokens: tokens, resolve: resolve}
+
+	result, err := p.parseExpr()
+	if err != nil {
+		return "", err
+	}
+
+	if p.pos < len(p.tokens) {
+		return "", fmt.Errorf("Unexpected token %q.", p.tokens[p.pos].literal)
+	}
+
+	return result, nil
+}
+
+// -------------------------------------------------------------------
+
+const (
+	computedTokenNumber     = "number"
+	computedTokenIdentifier = "identifier"
+	computedTokenOperator   = "operator"
+)
+
+type computedToken struct {
+	kind    string
+	literal string
+}
+
+func isComputedIdentifierRune(r rune, first bool) bool {
+	if r == '_' || r == '@' || unicode.IsLetter(r) {
+		return true
+	}
+
+	return !first && (r == '.' || unicode.IsDigit(r))
+}
+
+func scanComputedExpr(raw string) ([]computedToken, error) {
+	tokens := []computedToken{}
+	runes := []rune(raw)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.ContainsRune("+-*/%()", r):
+			tokens = append(tokens, computedToken{computedTokenOperator, string(r)})
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			literal := string(runes[start:i])
+			if _, err := strconv.ParseFloat(literal, 64); err != nil {
+				return nil, fmt.Errorf("Invalid number %q.", literal)
+			}
+			tokens = append(tokens, computedToken{computedTokenNumber, literal})
+		case isComputedIdentifierRune(r, true):
+			start := i
+			for i < len(runes) && isComputedIdentifierRune(runes[i], false) {
+				i++
+			}
+			tokens = append(tokens, computedToken{computedTokenIdentifier, string(runes[start:i])})
+		default:
+			return nil, fmt.Errorf("Unexpected character %q.", r)
+		}
+	}
+
+	return tokens, nil
+}
+
+// computedParser is a simple recursive descent parser for the
+// ComputedExpr grammar:
+//
+//	expr    = term { ("+" | "-") term }
+//	term    = unary { ("*" | "/" | "%") unary }
+//	unary   = "-" unary | primary
+//	primary = number | identifier | "(" expr ")"
+type computedParser struct {
+	tokens  []computedToken
+	pos     int
+	resolve func(identifier string) (string, error)
+}
+
+func (p *computedParser) peekOperator(ops ...string) (string, bool) {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != computedTokenOperator {
+		return "", false
+	}
+
+	for _, op := range ops {
+		if p.tokens[p.pos].literal == op {
+			return op, true
+		}
+	}
+
+	return "", false
+}
+
+func (p *computedParser) parseExpr() (string, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		op, ok := p.peekOperator("+", "-")
+		if !ok {
+			return left, nil
+		}
+		p.pos++
+
+		right, err := p.parseTerm()
+		if err != nil {
+			return "", err
+		}
+
+		left = fmt.Sprintf("(%s %s %s)", left, op, right)
+	}
+}
+
+func (p *computedParser) parseTerm() (string, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		op, ok := p.peekOperator("*", "/", "%")
+		if !ok {
+			return left, nil
+		}
+		p.pos++
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return "", err
+		}
+
+		if op == "/" {
+			// force real division (sqlite performs integer division for integer operands)
+			left = fmt.Sprintf("(%s * 1.0 / %s)", left, right)
+		} else {
+			left = fmt.Sprintf("(%s %s %s)", left, op, right)
+		}
+	}
+}
+
+func (p *computedParser) parseUnary() (string, error) {
+	if _, ok := p.peekOperator("-"); ok {
+		p.pos++
+
+		operand, err := p.parseUnary()
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("(-%s)", operand), nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *computedParser) parsePrimary() (string, error) {
+	if p.pos >= len(p.tokens) {
+		return "", errors.New("Unexpected end of the computed expression.")
+	}
+
+	token := p.tokens[p.pos]
+	p.pos++
+
+	switch token.kind {
+	case computedTokenNumber:
+		return token.literal, nil
+	case computedTokenIdentifier:
+		return p.resolve(token.literal)
+	}
+
+	if token.literal != "(" {
+		return "", fmt.Errorf("Unexpected token %q.", token.literal)
+	}
+
+	result, err := p.parseExpr()
+	if err != nil {
+		return "", err
+	}
+
+	if _, ok := p.peekOperator(")"); !ok {
+		return "", errors.New("Missing closing parenthesis.")
+	}
+	p.pos++
+
+	return result, nil
+}
@@ -0,0 +1,83 @@
+package search_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+func TestComputedExprBuild(t *testing.T) {
+	resolve := func(identifier string) (string, error) {
+		if identifier == "missing" {
+			return "", errors.New("test")
+		}
+		return "[[" + identifier + "]]", nil
+	}
+
+	scenarios := []struct {
+		expr        search.ComputedExpr
+		expectError bool
+		expectSql   string
+	}{
+		{"", true, ""},
+		{"   ", true, ""},
+		{"a +", true, ""},
+		{"a b", true, ""},
+		{"(a + b", true, ""},
+		{"a + b)", true, ""},
+		{"a = b", true, ""},
+		{"a + 'test'", true, ""},
+		{"1.2.3", true, ""},
+		{"a * missing", true, ""},
+		{"a", false, "[[a]]"},
+		{"10.5", false, "10.5"},
+		{"price * quantity", false, "([[price]] * [[quantity]])"},
+		{"a + b * c - d", false, "(([[a]] + ([[b]] * [[c]])) - [[d]])"},
+		{"(a + b) * c", false, "(([[a]] + [[b]]) * [[c]])"},
+		{"a / 2 % b", false, "(([[a]] * 1.0 / 2) % [[b]])"},
+		{"-a - -1", false, "((-[[a]]) - (-1))"},
+		{"rel.total_1 + a", false, "([[rel.total_1]] + [[a]])"},
+	}
+
+	for i, s := range scenarios {
+		result, err := s.expr.Build(resolve)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if result != s.expectSql {
+			t.Errorf("(%d) Expected %q, got %q", i, s.expectSql, result)
+		}
+	}
+}
+
+func TestComputedExprIdentifiers(t *testing.T) {
+	scenarios := []struct {
+		expr        search.ComputedExpr
+		expectError bool
+		expected    []string
+	}{
+		{"a +", true, nil},
+		{"1 + 2", false, []string{}},
+		{"a * (b - a) / c", false, []string{"a", "b", "c"}},
+	}
+
+	for i, s := range scenarios {
+		result, err := s.expr.Identifiers()
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if strings.Join(result, ",") != strings.Join(s.expected, ",") {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+}
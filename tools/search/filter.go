@@ -3,10 +3,12 @@ package search
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/ganigeorgiev/fexpr"
 	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/list"
 	"github.com/pocketbase/pocketbase/tools/security"
 	"github.com/pocketbase/pocketbase/tools/store"
 	"github.com/pocketbase/pocketbase/tools/types"
@@ -22,17 +24,87 @@ import (
 //	expr, err := filter.BuildExpr(resolver)
 type FilterData string
 
+// List of supported multiple filters conjunctions.
+const (
+	FilterConjunctionAnd string = "and"
+	FilterConjunctionOr  string = "or"
+)
+
+// JoinFilters combines the provided non-empty filters into a single
+// filter expression with the specified conjunction
+// (empty string is the same as [FilterConjunctionAnd]).
+//
+// Each filter is grouped so that it is evaluated independently from the others.
+//
+// Example:
+//
+//	filter, err := search.JoinFilters(search.FilterConjunctionOr, "a > 1", "b = 2 && c = 3")
+//	// "(a > 1) || (b = 2 && c = 3)"
+func JoinFilters(conjunction string, filters ...FilterData) (FilterData, error) {
+	var op string
+	switch strings.ToLower(conjunction) {
+	case "", FilterConjunctionAnd:
+		op = " && "
+	case FilterConjunctionOr:
+		op = " || "
+	default:
+		return "", fmt.Errorf("invalid filter conjunction %q", conjunction)
+	}
+
+	parts := make([]string, 0, len(filters))
+	for _, f := range filters {
+		if strings.TrimSpace(string(f)) != "" {
+			parts = append(parts, string(f))
+		}
+	}
+
+	if len(parts) == 1 {
+		return FilterData(parts[0]), nil
+	}
+
+	for i, part := range parts {
+		parts[i] = "(" + part + ")"
+	}
+
+	return FilterData(strings.Join(parts, op)), nil
+}
+
 // parsedFilterData holds a cache with previously parsed filter data expressions
 // (initialized with some preallocated empty data map)
 var parsedFilterData = store.New(make(map[string][]fexpr.ExprGroup, 50))
 
+// identifierAliasSeparator is the identifier rune used to attach an
+// alias to a filter field (eg. "@collection.posts:alias.title").
+const identifierAliasSeparator = ':'
+
+// escapedAliasSeparator is a placeholder for identifierAliasSeparator
+// during parsing since the fexpr scanner doesn't recognize ':' as
+// a valid identifier rune.
+const escapedAliasSeparator = "##"
+
 // BuildExpr parses the current filter data and returns a new db WHERE expression.
 func (f FilterData) BuildExpr(fieldResolver FieldResolver) (dbx.Expression, error) {
 	raw := string(f)
+
+	// expand the "@macro.name" references (if supported by the resolver)
+	if macrosResolver, ok := fieldResolver.(MacrosFieldResolver); ok {
+		expanded, err := expandFilterMacros(raw, macrosResolver.Macros())
+		if err != nil {
+			return nil, err
+		}
+		raw = expanded
+	}
+
+	// the escape marker is reserved to prevent ambiguous identifiers
+	// (eg. "a##b" would be otherwise resolved as the aliased "a:b")
+	if containsEscapedAliasSeparator(raw) {
+		return nil, fmt.Errorf("Invalid filter identifier characters %q.", escapedAliasSeparator)
+	}
+
 	if parsedFilterData.Has(raw) {
 		return f.build(parsedFilterData.Get(raw), fieldResolver)
 	}
-	data, err := fexpr.Parse(raw)
+	data, err := fexpr.Parse(escapeFilterArithmetic(escapeFilterFunctions(escapeIdentifierAliases(raw))))
 	if err != nil {
 		return nil, err
 	}
@@ -42,6 +114,43 @@ func (f FilterData) BuildExpr(fieldResolver FieldResolver) (dbx.Expression, erro
 	return f.build(data, fieldResolver)
 }
 
+// BuildExprWithParams parses the current filter data and returns a new
+// db WHERE expression with the filter "{:name}" placeholders bound to
+// the provided params values.
+//
+// The params values are always passed to the db as bound query
+// params (aka. they can't change the structure of the filter expression).
+//
+// Example:
+//
+//	var filter FilterData = "created >= {:date} && status = {:status}"
+//	expr, err := filter.BuildExprWithParams(resolver, dbx.Params{"date": "2022-01-01", "status": true})
+func (f FilterData) BuildExprWithParams(fieldResolver FieldResolver, params dbx.Params) (dbx.Expression, error) {
+	raw := replaceFilterPlaceholders(string(f), func(name string) string {
+		return paramIdentifierPrefix + name
+	})
+
+	return FilterData(raw).BuildExpr(&paramsFieldResolver{
+		FieldResolver: fieldResolver,
+		params:        params,
+	})
+}
+
+// Placeholders returns the names of the unique "{:name}"
+// placeholders in the current filter data (in order of appearance).
+func (f FilterData) Placeholders() []string {
+	result := []string{}
+
+	replaceFilterPlaceholders(string(f), func(name string) string {
+		if !list.ExistInSlice(name, result) {
+			result = append(result, name)
+		}
+		return ""
+	})
+
+	return result
+}
+
 func (f FilterData) build(data []fexpr.ExprGroup, fieldResolver FieldResolver) (dbx.Expression, error) {
 	if len(data) == 0 {
 		return nil, errors.New("Empty filter expression.")
@@ -85,6 +194,8 @@ func (f FilterData) build(data []fexpr.ExprGroup, fieldResolver FieldResolver) (
 }
 
 func (f FilterData) resolveTokenizedExpr(expr fexpr.Expr, fieldResolver FieldResolver) (dbx.Expression, error) {
+	expr = normalizeIntervalOperands(expr)
+
 	lName, lParams, lErr := f.resolveToken(expr.Left, fieldResolver)
 	if lName == "" || lErr != nil {
 		return nil, fmt.Errorf("Invalid left operand %q - %v.", expr.Left.Literal, lErr)
@@ -136,10 +247,22 @@ func (f FilterData) resolveTokenizedExpr(expr fexpr.Expr, fieldResolver FieldRes
 func (f FilterData) resolveToken(token fexpr.Token, fieldResolver FieldResolver) (name string, params dbx.Params, err error) {
 	switch token.Type {
 	case fexpr.TokenIdentifier:
+		// date function call (eg. "date(created, 'Europe/Paris')")
+		// ---
+		if strings.HasPrefix(token.Literal, filterFunctionPrefix) {
+			return f.resolveFunction(token.Literal, fieldResolver)
+		}
+
+		// interval arithmetic (eg. `@now - "7d"`)
+		// ---
+		if strings.HasPrefix(token.Literal, filterArithmeticPrefix) {
+			return f.resolveArithmetic(token.Literal, fieldResolver)
+		}
+
 		// current datetime constant
 		// ---
 		if token.Literal == "@now" {
-			placeholder := "t" + security.PseudorandomString(8)
+			placeholder := randomPlaceholder("t", fieldResolver)
 			name := fmt.Sprintf("{:%s}", placeholder)
 			params := dbx.Params{placeholder: types.NowDateTime().String()}
 
@@ -148,7 +271,7 @@ func (f FilterData) resolveToken(token fexpr.Token, fieldResolver FieldResolver)
 
 		// custom resolver
 		// ---
-		name, params, err := fieldResolver.Resolve(token.Literal)
+		name, params, err := fieldResolver.Resolve(unescapeIdentifierAliases(token.Literal))
 
 		if name == "" || err != nil {
 			m := map[string]string{
@@ -167,15 +290,15 @@ func (f FilterData) resolveToken(token fexpr.Token, fieldResolver FieldResolver)
 
 		return name, params, err
 	case fexpr.TokenText:
-		placeholder := "t" + security.PseudorandomString(8)
+		placeholder := randomPlaceholder("t", fieldResolver)
 		name := fmt.Sprintf("{:%s}", placeholder)
 		params := dbx.Params{placeholder: token.Literal}
 
 		return name, params, nil
 	case fexpr.TokenNumber:
-		placeholder := "t" + security.PseudorandomString(8)
+		placeholder := randomPlaceholder("t", fieldResolver)
 		name := fmt.Sprintf("{:%s}", placeholder)
-		params := dbx.Params{placeholder: cast.ToFloat64(token.Literal)}
+		params := dbx.Params{placeholder: numberLiteral(token.Literal)}
 
 		return name, params, nil
 	}
@@ -183,6 +306,125 @@ func (f FilterData) resolveToken(token fexpr.Token, fieldResolver FieldResolver)
 	return "", nil, errors.New("Unresolvable token type.")
 }
 
+// numberLiteral returns the number token value as int64 if it is an integer
+// (to allow exact comparison with the big integer columns) or as float64.
+func numberLiteral(literal string) any {
+	if n, err := strconv.ParseInt(literal, 10, 64); err == nil {
+		return n
+	}
+
+	return cast.ToFloat64(literal)
+}
+
+// escapeIdentifierAliases replaces the alias separator of all
+// identifiers in the raw filter string with escapedAliasSeparator
+// (quoted text literals are left untouched).
+func escapeIdentifierAliases(raw string) string {
+	if !strings.ContainsRune(raw, identifierAliasSeparator) {
+		return raw // nothing to escape
+	}
+
+	var result strings.Builder
+	var quote rune
+
+	runes := []rune(raw)
+	for i, ch := range runes {
+		switch {
+		case quote != 0:
+			if ch == quote && (i == 0 || runes[i-1] != '\\') {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == identifierAliasSeparator &&
+			i > 0 && isIdentifierRune(runes[i-1]) &&
+			i < len(runes)-1 && isIdentifierRune(runes[i+1]):
+			result.WriteString(escapedAliasSeparator)
+			continue
+		}
+
+		result.WriteRune(ch)
+	}
+
+	return result.String()
+}
+
+// containsEscapedAliasSeparator checks whether the raw filter string
+// contains escapedAliasSeparator outside of the quoted text literals.
+func containsEscapedAliasSeparator(raw string) bool {
+	if !strings.Contains(raw, escapedAliasSeparator) {
+		return false
+	}
+
+	var quote, prev rune
+
+	for i, ch := range raw {
+		switch {
+		case quote != 0:
+			if ch == quote && prev != '\\' {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case strings.HasPrefix(raw[i:], escapedAliasSeparator):
+			return true
+		}
+		prev = ch
+	}
+
+	return false
+}
+
+// replaceFilterPlaceholders replaces all "{:name}" placeholders in the
+// raw filter string with the result of replaceFunc
+// (quoted text literals are left untouched).
+func replaceFilterPlaceholders(raw string, replaceFunc func(name string) string) string {
+	if !strings.Contains(raw, "{:") {
+		return raw // no placeholders
+	}
+
+	var result strings.Builder
+	var quote rune
+
+	runes := []rune(raw)
+	for i := 0; i < len(runes); i++ {
+		ch := runes[i]
+
+		switch {
+		case quote != 0:
+			if ch == quote && (i == 0 || runes[i-1] != '\\') {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '{' && i < len(runes)-1 && runes[i+1] == ':':
+			end := i + 2
+			for end < len(runes) && isIdentifierRune(runes[end]) {
+				end++
+			}
+			if end > i+2 && end < len(runes) && runes[end] == '}' {
+				result.WriteString(replaceFunc(string(runes[i+2 : end])))
+				i = end
+				continue
+			}
+		}
+
+		result.WriteRune(ch)
+	}
+
+	return result.String()
+}
+
+// unescapeIdentifierAliases restores the escaped alias separators
+// of a single identifier literal.
+func unescapeIdentifierAliases(identifier string) string {
+	return strings.ReplaceAll(identifier, escapedAliasSeparator, string(identifierAliasSeparator))
+}
+
+func isIdentifierRune(ch rune) bool {
+	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9') || ch == '_'
+}
+
 // mergeParams returns new dbx.Params where each provided params item
 // is merged in the order they are specified.
 func mergeParams(params ...dbx.Params) dbx.Params {
@@ -267,3 +509,15 @@ func (e *concatExpr) Build(db *dbx.DB, params dbx.Params) string {
 
 	return "(" + strings.Join(stringParts, e.separator) + ")"
 }
+
+// randomPlaceholder generates a new random placeholder name with the
+// specified prefix using the field resolver random source (if any).
+func randomPlaceholder(prefix string, fieldResolver FieldResolver) string {
+	var source security.RandomSource
+
+	if r, ok := fieldResolver.(RandomSourceFieldResolver); ok {
+		source = r.RandomSource()
+	}
+
+	return prefix + security.PseudorandomStringWithSource(source, 8)
+}
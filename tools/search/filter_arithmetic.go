@@ -0,0 +1,304 @@
+package search
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ganigeorgiev/fexpr"
+	"github.com/pocketbase/dbx"
+)
+
+// filterArithmeticPrefix is the identifier prefix of the escaped
+// filter arithmetic expressions (eg. `@now - "7d"`).
+const filterArithmeticPrefix = "#ar_"
+
+// arithmeticDatetimeFormat is the SQLite strftime format of the
+// datetime arithmetic results (the same as the stored datetime values).
+const arithmeticDatetimeFormat = "%Y-%m-%d %H:%M:%fZ"
+
+// intervalUnits defines the supported interval literal units and their seconds.
+//
+// Months and years are not supported because they don't have a fixed length.
+var intervalUnits = map[string]float64{
+	"ms": 0.001,
+	"s":  1,
+	"m":  60,
+	"h":  3600,
+	"d":  86400,
+	"w":  604800,
+}
+
+var intervalRegex = regexp.MustCompile(`^(?:\d+(?:\.\d+)?(?:ms|s|m|h|d|w))+$`)
+var intervalPartRegex = regexp.MustCompile(`(\d+(?:\.\d+)?)(ms|s|m|h|d|w)`)
+
+// parseInterval parses an interval literal (eg. "7d", "1h30m", "500ms")
+// and returns its total number of seconds.
+func parseInterval(literal string) (float64, bool) {
+	literal = strings.TrimSpace(literal)
+	if !intervalRegex.MatchString(literal) {
+		return 0, false
+	}
+
+	var result float64
+	for _, match := range intervalPartRegex.FindAllStringSubmatch(literal, -1) {
+		n, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return 0, false
+		}
+		result += n * intervalUnits[match[2]]
+	}
+
+	return result, true
+}
+
+// isIntervalToken checks whether the token is an interval text literal.
+func isIntervalToken(token fexpr.Token) bool {
+	if token.Type != fexpr.TokenText {
+		return false
+	}
+
+	_, ok := parseInterval(token.Literal)
+
+	return ok
+}
+
+// isDurationToken checks whether the token is an escaped arithmetic
+// expression that results in a duration (eg. `due - created`).
+func isDurationToken(token fexpr.Token) bool {
+	if token.Type != fexpr.TokenIdentifier || !strings.HasPrefix(token.Literal, filterArithmeticPrefix) {
+		return false
+	}
+
+	operands, operators, err := parseArithmetic(token.Literal)
+	if err != nil {
+		return false
+	}
+
+	isDuration := isIntervalToken(operands[0])
+	for i, op := range operators {
+		right := isIntervalToken(operands[i+1])
+		switch {
+		case !isDuration && !right && op == '-':
+			isDuration = true // datetime - datetime
+		case isDuration && !right:
+			isDuration = false // duration + datetime
+		}
+	}
+
+	return isDuration
+}
+
+// normalizeIntervalOperands converts the interval text operand of a
+// duration comparison (eg. `due - created < "48h"`) to its number of seconds.
+func normalizeIntervalOperands(expr fexpr.Expr) fexpr.Expr {
+	toSeconds := func(token fexpr.Token) fexpr.Token {
+		seconds, _ := parseInterval(token.Literal)
+		return fexpr.Token{Type: fexpr.TokenNumber, Literal: strconv.FormatFloat(seconds, 'f', -1, 64)}
+	}
+
+	if isDurationToken(expr.Left) && isIntervalToken(expr.Right) {
+		expr.Right = toSeconds(expr.Right)
+	} else if isDurationToken(expr.Right) && isIntervalToken(expr.Left) {
+		expr.Left = toSeconds(expr.Left)
+	}
+
+	return expr
+}
+
+// escapeFilterArithmetic replaces the "+" and "-" arithmetic expressions
+// in the raw filter string with a single escaped identifier so that they
+// could be parsed by the fexpr scanner (quoted text literals are left untouched).
+func escapeFilterArithmetic(raw string) string {
+	if !strings.ContainsAny(raw, "+-") {
+		return raw // no arithmetic operators
+	}
+
+	var result strings.Builder
+
+	runes := []rune(raw)
+	for i := 0; i < len(runes); i++ {
+		end := operandEnd(runes, i)
+		if end < 0 {
+			result.WriteRune(runes[i])
+			continue
+		}
+
+		// extend the chain with the following "+ operand" and "- operand" pairs
+		chainEnd := end
+		for {
+			next := skipWhitespace(runes, chainEnd)
+			if next >= len(runes) || (runes[next] != '+' && runes[next] != '-') {
+				break
+			}
+
+			operandStart := skipWhitespace(runes, next+1)
+			nextEnd := operandEnd(runes, operandStart)
+			if nextEnd < 0 {
+				break
+			}
+
+			chainEnd = nextEnd
+		}
+
+		if chainEnd == end {
+			result.WriteString(string(runes[i:end]))
+		} else {
+			result.WriteString(filterArithmeticPrefix)
+			result.WriteString(hex.EncodeToString([]byte(string(runes[i:chainEnd]))))
+		}
+
+		i = chainEnd - 1
+	}
+
+	return result.String()
+}
+
+// operandEnd returns the end index of the identifier, number or quoted
+// text operand starting at start (or -1 if there is no operand).
+func operandEnd(runes []rune, start int) int {
+	if start >= len(runes) {
+		return -1
+	}
+
+	ch := runes[start]
+
+	if ch == '\'' || ch == '"' {
+		for i := start + 1; i < len(runes); i++ {
+			if runes[i] == ch && runes[i-1] != '\\' {
+				return i + 1
+			}
+		}
+		return -1
+	}
+
+	if !isFilterIdentifierRune(ch) {
+		return -1
+	}
+
+	end := start
+	for end < len(runes) && isFilterIdentifierRune(runes[end]) {
+		end++
+	}
+
+	return end
+}
+
+func skipWhitespace(runes []rune, start int) int {
+	for start < len(runes) && (runes[start] == ' ' || runes[start] == '\t' || runes[start] == '\n') {
+		start++
+	}
+
+	return start
+}
+
+// parseArithmetic splits a single escaped arithmetic expression
+// identifier into its operand tokens and "+"/"-" operators.
+func parseArithmetic(literal string) ([]fexpr.Token, []rune, error) {
+	raw, err := hex.DecodeString(strings.TrimPrefix(literal, filterArithmeticPrefix))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	runes := []rune(string(raw))
+	operands := []fexpr.Token{}
+	operators := []rune{}
+
+	for i := skipWhitespace(runes, 0); i < len(runes); {
+		end := operandEnd(runes, i)
+		if end < 0 {
+			return nil, nil, fmt.Errorf("invalid arithmetic expression %q", string(runes))
+		}
+
+		token, err := fexpr.NewScanner(strings.NewReader(string(runes[i:end]))).Scan()
+		if err != nil {
+			return nil, nil, err
+		}
+		operands = append(operands, token)
+
+		i = skipWhitespace(runes, end)
+		if i < len(runes) {
+			operators = append(operators, runes[i])
+			i = skipWhitespace(runes, i+1)
+		}
+	}
+
+	if len(operands) < 2 || len(operands) != len(operators)+1 {
+		return nil, nil, fmt.Errorf("invalid arithmetic expression %q", string(runes))
+	}
+
+	return operands, operators, nil
+}
+
+// resolveArithmetic resolves a single escaped arithmetic expression identifier.
+//
+// The text operands with interval format (eg. "7d", "48h", "1h30m") are
+// treated as intervals and all other operands as datetimes:
+//   - datetime ± interval results in a datetime
+//   - datetime - datetime results in a duration (in seconds)
+//   - duration ± interval results in a duration
+func (f FilterData) resolveArithmetic(literal string, fieldResolver FieldResolver) (string, dbx.Params, error) {
+	operands, operators, err := parseArithmetic(literal)
+	if err != nil {
+		return "", nil, err
+	}
+
+	resolveOperand := func(token fexpr.Token) (string, dbx.Params, bool, error) {
+		if seconds, ok := parseInterval(token.Literal); ok && token.Type == fexpr.TokenText {
+			placeholder := randomPlaceholder("t", fieldResolver)
+			return fmt.Sprintf("{:%s}", placeholder), dbx.Params{placeholder: seconds}, true, nil
+		}
+
+		if token.Type == fexpr.TokenNumber {
+			return "", nil, false, fmt.Errorf("Unsupported arithmetic number operand %q (use an interval literal, eg. \"7d\").", token.Literal)
+		}
+
+		name, params, err := f.resolveToken(token, fieldResolver)
+		if name == "" || err != nil {
+			return "", nil, false, fmt.Errorf("Invalid arithmetic operand %q - %v.", token.Literal, err)
+		}
+
+		return name, params, false, nil
+	}
+
+	name, params, isDuration, err := resolveOperand(operands[0])
+	if err != nil {
+		return "", nil, err
+	}
+
+	for i, op := range operators {
+		rName, rParams, rIsDuration, err := resolveOperand(operands[i+1])
+		if err != nil {
+			return "", nil, err
+		}
+
+		params = mergeParams(params, rParams)
+
+		switch {
+		case isDuration && rIsDuration:
+			name = fmt.Sprintf("(%s %c %s)", name, op, rName)
+		case !isDuration && rIsDuration:
+			if op == '-' {
+				rName = "-" + rName
+			}
+			name = fmt.Sprintf("strftime('%s', %s, (%s) || ' seconds')", arithmeticDatetimeFormat, name, rName)
+		case isDuration && !rIsDuration:
+			if op == '-' {
+				return "", nil, errors.New("A datetime cannot be subtracted from an interval.")
+			}
+			name = fmt.Sprintf("strftime('%s', %s, (%s) || ' seconds')", arithmeticDatetimeFormat, rName, name)
+			isDuration = false
+		default: // datetime and datetime
+			if op == '+' {
+				return "", nil, errors.New("Two datetimes cannot be added together.")
+			}
+			name = fmt.Sprintf("ROUND((julianday(%s) - julianday(%s)) * 86400, 3)", name, rName)
+			isDuration = true
+		}
+	}
+
+	return name, params, nil
+}
@@ -0,0 +1,288 @@
+package search
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ganigeorgiev/fexpr"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// TimezoneFuncName is the name of the custom db function used by the
+// filter date functions to convert a datetime value to a specific timezone.
+//
+// The db function must be registered by the db driver
+// with [ConvertTimezone] as its implementation.
+const TimezoneFuncName = "pb_timezone"
+
+// filterFunctionPrefix is the identifier prefix of the escaped filter
+// function calls (the "#" rune is a valid fexpr identifier start rune).
+const filterFunctionPrefix = "#fn_"
+
+// filterFunctions defines the supported filter date functions and their
+// SQLite format (the "%s" placeholder is replaced with the datetime argument).
+//
+// Each function accepts a datetime operand and an optional IANA
+// timezone name, eg. "date(created, 'Europe/Paris')".
+var filterFunctions = map[string]string{
+	"date":      "date(%s)",
+	"year":      "CAST(strftime('%%Y', %s) AS INTEGER)",
+	"month":     "CAST(strftime('%%m', %s) AS INTEGER)",
+	"day":       "CAST(strftime('%%d', %s) AS INTEGER)",
+	"hour":      "CAST(strftime('%%H', %s) AS INTEGER)",
+	"dayOfWeek": "CAST(strftime('%%w', %s) AS INTEGER)", // 0 = Sunday
+}
+
+var timezonesCache sync.Map
+
+// loadTimezone returns the cached location with the provided IANA timezone name.
+func loadTimezone(name string) (*time.Location, error) {
+	if loc, ok := timezonesCache.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+
+	timezonesCache.Store(name, loc)
+
+	return loc, nil
+}
+
+// ConvertTimezone converts the provided datetime value to the local time
+// of the specified IANA timezone and returns it in a SQLite date functions
+// compatible format (it is used as [TimezoneFuncName] db function implementation).
+//
+// Returns nil for empty or invalid datetime values.
+func ConvertTimezone(value any, timezone string) (any, error) {
+	loc, err := loadTimezone(timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	dt, err := types.ParseDateTime(value)
+	if err != nil || dt.IsZero() {
+		return nil, nil
+	}
+
+	return dt.Time().In(loc).Format("2006-01-02 15:04:05.000"), nil
+}
+
+// escapeFilterFunctions replaces the supported function calls in the
+// raw filter string with a single escaped identifier so that they could be
+// parsed by the fexpr scanner (quoted text literals are left untouched).
+func escapeFilterFunctions(raw string) string {
+	if !strings.ContainsRune(raw, '(') {
+		return raw // no function calls
+	}
+
+	var result strings.Builder
+	var quote rune
+
+	runes := []rune(raw)
+	for i := 0; i < len(runes); i++ {
+		ch := runes[i]
+
+		switch {
+		case quote != 0:
+			if ch == quote && runes[i-1] != '\\' {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '@' && (i == 0 || !isFilterIdentifierRune(runes[i-1])) && hasAuthHasRoleCallAt(runes, i):
+			end := i + len([]rune(authHasRoleFunction))
+
+			if callEnd := findCallEnd(runes, end); callEnd > 0 {
+				result.WriteString(filterFunctionPrefix)
+				result.WriteString(hex.EncodeToString([]byte(string(runes[i : callEnd+1]))))
+				i = callEnd
+				continue
+			}
+		case isIdentifierRune(ch) && (i == 0 || !isFilterIdentifierRune(runes[i-1])):
+			end := i
+			for end < len(runes) && isIdentifierRune(runes[end]) {
+				end++
+			}
+
+			if isFilterFunction(string(runes[i:end])) {
+				if callEnd := findCallEnd(runes, end); callEnd > 0 {
+					result.WriteString(filterFunctionPrefix)
+					result.WriteString(hex.EncodeToString([]byte(string(runes[i : callEnd+1]))))
+					i = callEnd
+					continue
+				}
+			}
+
+			result.WriteString(string(runes[i:end]))
+			i = end - 1
+			continue
+		}
+
+		result.WriteRune(ch)
+	}
+
+	return result.String()
+}
+
+// isFilterFunction checks whether name is one of the supported filter functions.
+func isFilterFunction(name string) bool {
+	if _, ok := filterFunctions[name]; ok {
+		return true
+	}
+
+	_, ok := historyFilterFunctions[name]
+
+	return ok
+}
+
+// isFilterIdentifierRune checks whether ch could be part of a filter
+// identifier (including the "@" and "." runes of the special identifiers).
+func isFilterIdentifierRune(ch rune) bool {
+	return isIdentifierRune(ch) || ch == '@' || ch == '.' || ch == '#'
+}
+
+// findCallEnd returns the index of the closing parenthesis of a
+// function call whose name ends at start (or -1 if there is no call).
+func findCallEnd(runes []rune, start int) int {
+	i := start
+	for i < len(runes) && (runes[i] == ' ' || runes[i] == '\t' || runes[i] == '\n') {
+		i++
+	}
+
+	if i >= len(runes) || runes[i] != '(' {
+		return -1
+	}
+
+	var quote rune
+	for ; i < len(runes); i++ {
+		ch := runes[i]
+		switch {
+		case quote != 0:
+			if ch == quote && runes[i-1] != '\\' {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == ')':
+			return i
+		}
+	}
+
+	return -1
+}
+
+// resolveFunction resolves a single escaped filter function call identifier.
+func (f FilterData) resolveFunction(literal string, fieldResolver FieldResolver) (string, dbx.Params, error) {
+	raw, err := hex.DecodeString(strings.TrimPrefix(literal, filterFunctionPrefix))
+	if err != nil {
+		return "", nil, err
+	}
+
+	call := string(raw)
+	open := strings.IndexRune(call, '(')
+	name := strings.TrimSpace(call[:open])
+
+	args, err := scanFunctionArgs(call[open+1 : len(call)-1])
+	if err != nil {
+		return "", nil, fmt.Errorf("Invalid %s() arguments - %v.", name, err)
+	}
+
+	if name == authHasRoleFunction {
+		return f.resolveRoleFunction(args, fieldResolver)
+	}
+
+	if _, ok := historyFilterFunctions[name]; ok {
+		return f.resolveHistoryFunction(name, args, fieldResolver)
+	}
+
+	format, ok := filterFunctions[name]
+	if !ok {
+		return "", nil, fmt.Errorf("Unknown filter function %q.", name)
+	}
+
+	if len(args) < 1 || len(args) > 2 {
+		return "", nil, fmt.Errorf("%s() expects a datetime and an optional timezone argument.", name)
+	}
+
+	operand, params, err := f.resolveToken(args[0], fieldResolver)
+	if operand == "" || err != nil {
+		return "", nil, fmt.Errorf("Invalid %s() datetime argument %q - %v.", name, args[0].Literal, err)
+	}
+
+	if len(args) == 2 {
+		if args[1].Type != fexpr.TokenText {
+			return "", nil, fmt.Errorf("The %s() timezone argument must be a quoted text.", name)
+		}
+
+		if _, err := loadTimezone(args[1].Literal); err != nil {
+			return "", nil, fmt.Errorf("Invalid %s() timezone %q.", name, args[1].Literal)
+		}
+
+		placeholder := randomPlaceholder("t", fieldResolver)
+		operand = fmt.Sprintf("%s(%s, {:%s})", TimezoneFuncName, operand, placeholder)
+		params = mergeParams(params, dbx.Params{placeholder: args[1].Literal})
+	}
+
+	return fmt.Sprintf(format, operand), params, nil
+}
+
+// scanFunctionArgs splits the raw comma separated function
+// arguments and scans each of them as a single fexpr token.
+func scanFunctionArgs(raw string) ([]fexpr.Token, error) {
+	parts := []string{}
+
+	var quote rune
+	var start int
+
+	runes := []rune(raw)
+	for i, ch := range runes {
+		switch {
+		case quote != 0:
+			if ch == quote && runes[i-1] != '\\' {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == ',':
+			parts = append(parts, string(runes[start:i]))
+			start = i + 1
+		}
+	}
+	parts = append(parts, string(runes[start:]))
+
+	result := make([]fexpr.Token, 0, len(parts))
+
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, errors.New("empty argument")
+		}
+
+		scanner := fexpr.NewScanner(strings.NewReader(escapeFilterArithmetic(part)))
+
+		token, err := scanner.Scan()
+		if err != nil {
+			return nil, err
+		}
+
+		// each argument must be a single identifier, text or number token
+		next, _ := scanner.Scan()
+		if next.Type != fexpr.TokenEOF || (token.Type != fexpr.TokenIdentifier &&
+			token.Type != fexpr.TokenText &&
+			token.Type != fexpr.TokenNumber) {
+			return nil, fmt.Errorf("invalid argument %q", part)
+		}
+
+		result = append(result, token)
+	}
+
+	return result, nil
+}
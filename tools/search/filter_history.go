@@ -0,0 +1,67 @@
+package search
+
+import (
+	"fmt"
+
+	"github.com/ganigeorgiev/fexpr"
+	"github.com/pocketbase/dbx"
+)
+
+// historyFilterFunctions defines the supported record change history
+// filter functions and their number of arguments:
+//   - changedSince(field, datetime) - 1 if the field was changed after the datetime, otherwise 0
+//   - changedBy(field) - the author id of the last field change (NULL if there are no changes)
+//
+// Example:
+//
+//	changedSince(status, @now - "1d") = true && changedBy(status) != @request.auth.id
+var historyFilterFunctions = map[string]int{
+	"changedSince": 2,
+	"changedBy":    1,
+}
+
+// HistoryFieldResolver defines an optional FieldResolver interface
+// for resolving the record change history filter functions.
+//
+// The field argument is the plain name of the checked field.
+type HistoryFieldResolver interface {
+	// ResolveChangedSince resolves the "changedSince(field, datetime)"
+	// filter function (since is the already resolved datetime operand).
+	ResolveChangedSince(field string, since string) (string, dbx.Params, error)
+
+	// ResolveChangedBy resolves the "changedBy(field)" filter function.
+	ResolveChangedBy(field string) (string, dbx.Params, error)
+}
+
+// resolveHistoryFunction resolves a single record change history
+// filter function call with its already scanned arguments.
+func (f FilterData) resolveHistoryFunction(name string, args []fexpr.Token, fieldResolver FieldResolver) (string, dbx.Params, error) {
+	historyResolver, ok := fieldResolver.(HistoryFieldResolver)
+	if !ok {
+		return "", nil, fmt.Errorf("The %s() function is not supported.", name)
+	}
+
+	if len(args) != historyFilterFunctions[name] {
+		return "", nil, fmt.Errorf("%s() expects %d argument(s).", name, historyFilterFunctions[name])
+	}
+
+	if args[0].Type != fexpr.TokenIdentifier {
+		return "", nil, fmt.Errorf("The %s() first argument must be a field name.", name)
+	}
+
+	if name == "changedBy" {
+		return historyResolver.ResolveChangedBy(args[0].Literal)
+	}
+
+	since, params, err := f.resolveToken(args[1], fieldResolver)
+	if since == "" || err != nil {
+		return "", nil, fmt.Errorf("Invalid %s() datetime argument %q - %v.", name, args[1].Literal, err)
+	}
+
+	expr, exprParams, err := historyResolver.ResolveChangedSince(args[0].Literal, since)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return expr, mergeParams(params, exprParams), nil
+}
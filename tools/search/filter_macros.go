@@ -0,0 +1,95 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+)
+
+// macroIdentifierPrefix is the identifier prefix of the rule macros
+// references (eg. "@macro.isOwner").
+const macroIdentifierPrefix = "@macro."
+
+// maxMacrosDepth is the max allowed number of nested macros references.
+const maxMacrosDepth = 10
+
+// MacrosFieldResolver defines an optional FieldResolver interface
+// for providing named filter fragments (aka. rule macros) that could be
+// referenced inside the filter expression as "@macro.name".
+//
+// If implemented, the macro references are expanded by `FilterData.BuildExpr`
+// before the filter parsing (each fragment is wrapped in parenthesis).
+type MacrosFieldResolver interface {
+	// Macros returns the available macros (name => filter fragment).
+	Macros() map[string]string
+}
+
+// expandFilterMacros replaces all "@macro.name" references in the raw
+// filter string with their parenthesized filter fragment
+// (quoted text literals are left untouched).
+//
+// Returns an error for unknown, circular or too deeply nested macros.
+func expandFilterMacros(raw string, macros map[string]string) (string, error) {
+	return expandFilterMacrosWithStack(raw, macros, nil)
+}
+
+func expandFilterMacrosWithStack(raw string, macros map[string]string, stack []string) (string, error) {
+	if !strings.Contains(raw, macroIdentifierPrefix) {
+		return raw, nil // no macros
+	}
+
+	if len(stack) > maxMacrosDepth {
+		return "", fmt.Errorf("Max %d nested macros are allowed.", maxMacrosDepth)
+	}
+
+	var result strings.Builder
+	var quote rune
+
+	runes := []rune(raw)
+	prefix := []rune(macroIdentifierPrefix)
+
+	for i := 0; i < len(runes); i++ {
+		ch := runes[i]
+
+		switch {
+		case quote != 0:
+			if ch == quote && runes[i-1] != '\\' {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '@' &&
+			(i == 0 || !isFilterIdentifierRune(runes[i-1])) &&
+			strings.HasPrefix(string(runes[i:]), macroIdentifierPrefix):
+			end := i + len(prefix)
+			for end < len(runes) && isIdentifierRune(runes[end]) {
+				end++
+			}
+
+			name := string(runes[i+len(prefix) : end])
+
+			fragment, ok := macros[name]
+			if !ok || strings.TrimSpace(fragment) == "" {
+				return "", fmt.Errorf("Unknown macro %q.", name)
+			}
+
+			for _, parent := range stack {
+				if parent == name {
+					return "", fmt.Errorf("Circular macro reference %q.", name)
+				}
+			}
+
+			expanded, err := expandFilterMacrosWithStack(fragment, macros, append(stack, name))
+			if err != nil {
+				return "", err
+			}
+
+			result.WriteString("(" + expanded + ")")
+			i = end - 1
+			continue
+		}
+
+		result.WriteRune(ch)
+	}
+
+	return result.String(), nil
+}
@@ -0,0 +1,63 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ganigeorgiev/fexpr"
+	"github.com/pocketbase/dbx"
+)
+
+// authHasRoleFunction is the name of the filter function that checks
+// whether the request auth record has the specified role, eg.:
+//
+//	@request.auth.hasRole("editor") = true || @request.auth.hasRole(requiredRole) = true
+const authHasRoleFunction = "@request.auth.hasRole"
+
+// RoleFieldResolver defines an optional FieldResolver interface
+// for resolving the "@request.auth.hasRole(role)" filter function.
+type RoleFieldResolver interface {
+	// ResolveAuthHasRole resolves the "@request.auth.hasRole(role)"
+	// filter function (role is the already resolved role name operand).
+	ResolveAuthHasRole(role string) (string, dbx.Params, error)
+}
+
+// hasAuthHasRoleCallAt checks whether the runes at index i
+// start with the "@request.auth.hasRole" function name.
+func hasAuthHasRoleCallAt(runes []rune, i int) bool {
+	name := []rune(authHasRoleFunction)
+
+	end := i + len(name)
+	if end > len(runes) || string(runes[i:end]) != authHasRoleFunction {
+		return false
+	}
+
+	return end == len(runes) || !isIdentifierRune(runes[end])
+}
+
+// resolveRoleFunction resolves a single "@request.auth.hasRole(role)"
+// filter function call with its already scanned arguments.
+func (f FilterData) resolveRoleFunction(args []fexpr.Token, fieldResolver FieldResolver) (string, dbx.Params, error) {
+	name := strings.TrimPrefix(authHasRoleFunction, "@request.auth.")
+
+	roleResolver, ok := fieldResolver.(RoleFieldResolver)
+	if !ok {
+		return "", nil, fmt.Errorf("The %s() function is not supported.", name)
+	}
+
+	if len(args) != 1 {
+		return "", nil, fmt.Errorf("%s() expects a single role name argument.", name)
+	}
+
+	role, params, err := f.resolveToken(args[0], fieldResolver)
+	if role == "" || err != nil {
+		return "", nil, fmt.Errorf("Invalid %s() role argument %q - %v.", name, args[0].Literal, err)
+	}
+
+	expr, exprParams, err := roleResolver.ResolveAuthHasRole(role)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return expr, mergeParams(params, exprParams), nil
+}
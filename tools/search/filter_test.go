@@ -1,6 +1,7 @@
 package search_test
 
 import (
+	"errors"
 	"regexp"
 	"testing"
 
@@ -9,7 +10,7 @@ import (
 )
 
 func TestFilterDataBuildExpr(t *testing.T) {
-	resolver := search.NewSimpleFieldResolver("test1", "test2", "test3", "test4.sub")
+	resolver := search.NewSimpleFieldResolver("test1", "test2", "test3", "test4.sub", "test5:alias.sub")
 
 	scenarios := []struct {
 		name          string
@@ -97,6 +98,38 @@ func TestFilterDataBuildExpr(t *testing.T) {
 				regexp.QuoteMeta("} ESCAPE '\\'") +
 				"$",
 		},
+		{
+			"aliased identifier",
+			"test5:alias.sub = 'a:b' && test1 != \"c:d\"", false,
+			"^" +
+				regexp.QuoteMeta("(COALESCE([[test5alias.sub]], '') = COALESCE({:") +
+				".+" +
+				regexp.QuoteMeta("}, '') AND COALESCE([[test1]], '') != COALESCE({:") +
+				".+" +
+				regexp.QuoteMeta("}, ''))") +
+				"$",
+		},
+		{
+			"invalid alias separator position",
+			"test5: = 'a'",
+			true,
+			"",
+		},
+		{
+			"reserved alias escape marker in identifier",
+			"test5##alias.sub = 'a'",
+			true,
+			"",
+		},
+		{
+			"reserved alias escape marker in text literal",
+			"test1 = 'a##b'", false,
+			"^" +
+				regexp.QuoteMeta("COALESCE([[test1]], '') = COALESCE({:") +
+				".+" +
+				regexp.QuoteMeta("}, '')") +
+				"$",
+		},
 		{
 			"current datetime constant",
 			"test1 > @now", false,
@@ -176,3 +209,716 @@ func TestFilterDataBuildExpr(t *testing.T) {
 		}
 	}
 }
+
+func TestFilterDataBuildExprFunctions(t *testing.T) {
+	resolver := search.NewSimpleFieldResolver("test1", "test2")
+
+	scenarios := []struct {
+		name          string
+		filterData    search.FilterData
+		expectError   bool
+		expectPattern string
+	}{
+		{
+			"unknown function",
+			"unknown(test1) = 1",
+			true,
+			"",
+		},
+		{
+			"missing datetime argument",
+			"year() = 2022",
+			true,
+			"",
+		},
+		{
+			"too many arguments",
+			"year(test1, 'UTC', 'UTC') = 2022",
+			true,
+			"",
+		},
+		{
+			"unknown datetime field",
+			"year(unknown) = 2022",
+			true,
+			"",
+		},
+		{
+			"non text timezone argument",
+			"year(test1, test2) = 2022",
+			true,
+			"",
+		},
+		{
+			"invalid timezone",
+			"year(test1, 'Europe/Missing') = 2022",
+			true,
+			"",
+		},
+		{
+			"function name inside text literal",
+			"test1 = 'year(test2)'",
+			false,
+			"^" +
+				regexp.QuoteMeta("COALESCE([[test1]], '') = COALESCE({:") +
+				".+" +
+				regexp.QuoteMeta("}, '')") +
+				"$",
+		},
+		{
+			"function name as field name prefix",
+			"test1 = year",
+			true,
+			"",
+		},
+		{
+			"function without timezone",
+			"year(test1) > 2022",
+			false,
+			"^" +
+				regexp.QuoteMeta("CAST(strftime('%Y', [[test1]]) AS INTEGER) > {:") +
+				".+" +
+				regexp.QuoteMeta("}") +
+				"$",
+		},
+		{
+			"functions with timezone",
+			"date(test1, 'Europe/Paris') >= '2022-01-01' && dayOfWeek( test2 , \"America/New_York\") < month(@now)",
+			false,
+			"^" +
+				regexp.QuoteMeta("(date(pb_timezone([[test1]], {:") +
+				".+" +
+				regexp.QuoteMeta("})) >= {:") +
+				".+" +
+				regexp.QuoteMeta("} AND CAST(strftime('%w', pb_timezone([[test2]], {:") +
+				".+" +
+				regexp.QuoteMeta("})) AS INTEGER) < CAST(strftime('%m', {:") +
+				".+" +
+				regexp.QuoteMeta("}) AS INTEGER))") +
+				"$",
+		},
+	}
+
+	for _, s := range scenarios {
+		expr, err := s.filterData.BuildExpr(resolver)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		dummyDB := &dbx.DB{}
+		rawSql := expr.Build(dummyDB, map[string]any{})
+
+		pattern := regexp.MustCompile(s.expectPattern)
+		if !pattern.MatchString(rawSql) {
+			t.Errorf("[%s] Pattern %v don't match with expression: \n%v", s.name, s.expectPattern, rawSql)
+		}
+	}
+}
+
+func TestFilterDataBuildExprArithmetic(t *testing.T) {
+	resolver := search.NewSimpleFieldResolver("test1", "test2")
+
+	scenarios := []struct {
+		name          string
+		filterData    search.FilterData
+		expectError   bool
+		expectPattern string
+	}{
+		{
+			"number operand",
+			"test1 - 1 > test2",
+			true,
+			"",
+		},
+		{
+			"unknown operand field",
+			`unknown - "1d" > test2`,
+			true,
+			"",
+		},
+		{
+			"datetimes addition",
+			"test1 + test2 > @now",
+			true,
+			"",
+		},
+		{
+			"datetime subtracted from interval",
+			`"1d" - test1 > @now`,
+			true,
+			"",
+		},
+		{
+			"arithmetic operators inside text literal",
+			"test1 = 'a - b' && test2 = \"c + d\"",
+			false,
+			"^" +
+				regexp.QuoteMeta("(COALESCE([[test1]], '') = COALESCE({:") +
+				".+" +
+				regexp.QuoteMeta("}, '') AND COALESCE([[test2]], '') = COALESCE({:") +
+				".+" +
+				regexp.QuoteMeta("}, ''))") +
+				"$",
+		},
+		{
+			"datetime minus interval",
+			`test1 >= @now - "7d"`,
+			false,
+			"^" +
+				regexp.QuoteMeta("[[test1]] >= strftime('%Y-%m-%d %H:%M:%fZ', {:") +
+				".+" +
+				regexp.QuoteMeta("}, (-{:") +
+				".+" +
+				regexp.QuoteMeta("}) || ' seconds')") +
+				"$",
+		},
+		{
+			"interval plus datetime",
+			`"1h30m"+test1 < test2`,
+			false,
+			"^" +
+				regexp.QuoteMeta("strftime('%Y-%m-%d %H:%M:%fZ', [[test1]], ({:") +
+				".+" +
+				regexp.QuoteMeta("}) || ' seconds') < [[test2]]") +
+				"$",
+		},
+		{
+			"datetimes difference compared with interval",
+			`test2 - test1 - "1h" < "48h"`,
+			false,
+			"^" +
+				regexp.QuoteMeta("(ROUND((julianday([[test2]]) - julianday([[test1]])) * 86400, 3) - {:") +
+				".+" +
+				regexp.QuoteMeta("}) < {:") +
+				".+" +
+				regexp.QuoteMeta("}") +
+				"$",
+		},
+		{
+			"interval compared with datetimes difference",
+			`"48h" > test2 - test1`,
+			false,
+			"^" +
+				regexp.QuoteMeta("{:") +
+				".+" +
+				regexp.QuoteMeta("} > ROUND((julianday([[test2]]) - julianday([[test1]])) * 86400, 3)") +
+				"$",
+		},
+		{
+			"arithmetic in function argument",
+			`date(@now - "1d") = date(test1)`,
+			false,
+			"^" +
+				regexp.QuoteMeta("COALESCE(date(strftime('%Y-%m-%d %H:%M:%fZ', {:") +
+				".+" +
+				regexp.QuoteMeta("}, (-{:") +
+				".+" +
+				regexp.QuoteMeta("}) || ' seconds')), '') = COALESCE(date([[test1]]), '')") +
+				"$",
+		},
+	}
+
+	for _, s := range scenarios {
+		expr, err := s.filterData.BuildExpr(resolver)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		dummyDB := &dbx.DB{}
+		rawSql := expr.Build(dummyDB, map[string]any{})
+
+		pattern := regexp.MustCompile(s.expectPattern)
+		if !pattern.MatchString(rawSql) {
+			t.Errorf("[%s] Pattern %v don't match with expression: \n%v", s.name, s.expectPattern, rawSql)
+		}
+	}
+}
+
+type testMacrosResolver struct {
+	*search.SimpleFieldResolver
+
+	macros map[string]string
+}
+
+func (r *testMacrosResolver) Macros() map[string]string {
+	return r.macros
+}
+
+func TestFilterDataBuildExprMacros(t *testing.T) {
+	resolver := &testMacrosResolver{
+		SimpleFieldResolver: search.NewSimpleFieldResolver("test1", "test2"),
+		macros: map[string]string{
+			"isTest1":   "test1 = 'a' || test1 = 'b'",
+			"isTest2":   "test2 > 1",
+			"nested":    "@macro.isTest1 && @macro.isTest2",
+			"empty":     " ",
+			"invalid":   "missing = 1",
+			"circularA": "test1 = 1 && @macro.circularB",
+			"circularB": "@macro.circularA",
+		},
+	}
+
+	scenarios := []struct {
+		name          string
+		filterData    search.FilterData
+		expectError   bool
+		expectPattern string
+	}{
+		{
+			"unknown macro",
+			"@macro.missing",
+			true,
+			"",
+		},
+		{
+			"empty macro",
+			"@macro.empty",
+			true,
+			"",
+		},
+		{
+			"macro with invalid filter",
+			"test1 = 1 && @macro.invalid",
+			true,
+			"",
+		},
+		{
+			"circular macros",
+			"@macro.circularA",
+			true,
+			"",
+		},
+		{
+			"macro reference inside text literal",
+			"test1 = '@macro.missing'",
+			false,
+			"^" +
+				regexp.QuoteMeta("COALESCE([[test1]], '') = COALESCE({:") +
+				".+" +
+				regexp.QuoteMeta("}, '')") +
+				"$",
+		},
+		{
+			"single macro",
+			"@macro.isTest2",
+			false,
+			"^" +
+				regexp.QuoteMeta("[[test2]] > {:") +
+				".+" +
+				regexp.QuoteMeta("}") +
+				"$",
+		},
+		{
+			"nested macros",
+			"test1 = test2 && @macro.nested",
+			false,
+			"^" +
+				regexp.QuoteMeta("(COALESCE([[test1]], '') = COALESCE([[test2]], '') AND ((COALESCE([[test1]], '') = COALESCE({:") +
+				".+" +
+				regexp.QuoteMeta("}, '') OR COALESCE([[test1]], '') = COALESCE({:") +
+				".+" +
+				regexp.QuoteMeta("}, '')) AND [[test2]] > {:") +
+				".+" +
+				regexp.QuoteMeta("}))") +
+				"$",
+		},
+	}
+
+	for _, s := range scenarios {
+		expr, err := s.filterData.BuildExpr(resolver)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		dummyDB := &dbx.DB{}
+		rawSql := expr.Build(dummyDB, map[string]any{})
+
+		pattern := regexp.MustCompile(s.expectPattern)
+		if !pattern.MatchString(rawSql) {
+			t.Errorf("[%s] Pattern %v don't match with expression: \n%v", s.name, s.expectPattern, rawSql)
+		}
+	}
+
+	// macros without a macros resolver
+	if _, err := search.FilterData("@macro.isTest1").BuildExpr(search.NewSimpleFieldResolver("test1")); err == nil {
+		t.Fatal("Expected the macro reference to be unresolvable without a macros resolver")
+	}
+}
+
+type testHistoryResolver struct {
+	*search.SimpleFieldResolver
+}
+
+func (r *testHistoryResolver) ResolveChangedSince(field string, since string) (string, dbx.Params, error) {
+	if field != "test1" {
+		return "", nil, errors.New("untracked field")
+	}
+	return "EXISTS (" + field + " > " + since + ")", nil, nil
+}
+
+func (r *testHistoryResolver) ResolveChangedBy(field string) (string, dbx.Params, error) {
+	if field != "test1" {
+		return "", nil, errors.New("untracked field")
+	}
+	return "(SELECT " + field + ")", nil, nil
+}
+
+func TestFilterDataBuildExprHistoryFunctions(t *testing.T) {
+	resolver := &testHistoryResolver{search.NewSimpleFieldResolver("test1", "test2")}
+
+	scenarios := []struct {
+		name          string
+		filterData    search.FilterData
+		expectError   bool
+		expectPattern string
+	}{
+		{"changedBy without args", "changedBy() = 1", true, ""},
+		{"changedBy with too many args", "changedBy(test1, test2) = 1", true, ""},
+		{"changedBy with non identifier arg", "changedBy('test1') = 1", true, ""},
+		{"changedBy with untracked field", "changedBy(test2) = 1", true, ""},
+		{"changedSince with missing datetime", "changedSince(test1) = true", true, ""},
+		{"changedSince with untracked field", "changedSince(test2, '2023-01-01') = true", true, ""},
+		{
+			"changedBy",
+			"changedBy(test1) = 'abc'",
+			false,
+			"^" +
+				regexp.QuoteMeta("COALESCE((SELECT test1), '') = COALESCE({:") +
+				".+" +
+				regexp.QuoteMeta("}, '')") +
+				"$",
+		},
+		{
+			"changedSince",
+			"changedSince(test1, '2023-01-01') = true",
+			false,
+			"^" +
+				regexp.QuoteMeta("COALESCE(EXISTS (test1 > {:") +
+				".+" +
+				regexp.QuoteMeta("}), '') = COALESCE(1, '')") +
+				"$",
+		},
+	}
+
+	for _, s := range scenarios {
+		expr, err := s.filterData.BuildExpr(resolver)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		dummyDB := &dbx.DB{}
+		rawSql := expr.Build(dummyDB, map[string]any{})
+
+		pattern := regexp.MustCompile(s.expectPattern)
+		if !pattern.MatchString(rawSql) {
+			t.Errorf("[%s] Pattern %v don't match with expression: \n%v", s.name, s.expectPattern, rawSql)
+		}
+	}
+
+	// history functions without a history resolver
+	if _, err := search.FilterData("changedBy(test1) = 1").BuildExpr(search.NewSimpleFieldResolver("test1")); err == nil {
+		t.Fatal("Expected the history function to be unresolvable without a history resolver")
+	}
+}
+
+type testRoleResolver struct {
+	*search.SimpleFieldResolver
+}
+
+func (r *testRoleResolver) ResolveAuthHasRole(role string) (string, dbx.Params, error) {
+	return "HAS_ROLE(" + role + ")", nil, nil
+}
+
+func TestFilterDataBuildExprRoleFunction(t *testing.T) {
+	resolver := &testRoleResolver{search.NewSimpleFieldResolver("test1")}
+
+	scenarios := []struct {
+		name          string
+		filterData    search.FilterData
+		expectError   bool
+		expectPattern string
+	}{
+		{"without args", "@request.auth.hasRole() = true", true, ""},
+		{"with too many args", "@request.auth.hasRole('a', 'b') = true", true, ""},
+		{"with unknown field arg", "@request.auth.hasRole(missing) = true", true, ""},
+		{"not a function call", "@request.auth.hasRole = true", true, ""},
+		{
+			"quoted function call",
+			"test1 = '@request.auth.hasRole(\"editor\")'",
+			false,
+			"^" +
+				regexp.QuoteMeta("COALESCE([[test1]], '') = COALESCE({:") +
+				".+" +
+				regexp.QuoteMeta("}, '')") +
+				"$",
+		},
+		{
+			"text arg",
+			"@request.auth.hasRole(\"editor\") = true",
+			false,
+			"^" +
+				regexp.QuoteMeta("COALESCE(HAS_ROLE({:") +
+				".+" +
+				regexp.QuoteMeta("}), '') = COALESCE(1, '')") +
+				"$",
+		},
+		{
+			"field arg",
+			"@request.auth.hasRole(test1) = false",
+			false,
+			"^" +
+				regexp.QuoteMeta("COALESCE(HAS_ROLE([[test1]]), '') = COALESCE(0, '')") +
+				"$",
+		},
+	}
+
+	for _, s := range scenarios {
+		expr, err := s.filterData.BuildExpr(resolver)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		dummyDB := &dbx.DB{}
+		rawSql := expr.Build(dummyDB, map[string]any{})
+
+		pattern := regexp.MustCompile(s.expectPattern)
+		if !pattern.MatchString(rawSql) {
+			t.Errorf("[%s] Pattern %v don't match with expression: \n%v", s.name, s.expectPattern, rawSql)
+		}
+	}
+
+	// role function without a role resolver
+	if _, err := search.FilterData("@request.auth.hasRole('editor') = true").BuildExpr(search.NewSimpleFieldResolver("test1")); err == nil {
+		t.Fatal("Expected the role function to be unresolvable without a role resolver")
+	}
+}
+
+func TestConvertTimezone(t *testing.T) {
+	scenarios := []struct {
+		value       any
+		timezone    string
+		expected    any
+		expectError bool
+	}{
+		{"2023-07-01 22:30:00.000Z", "missing", nil, true},
+		{"", "UTC", nil, false},
+		{"invalid", "UTC", nil, false},
+		{"2023-07-01 22:30:00.000Z", "UTC", "2023-07-01 22:30:00.000", false},
+		{"2023-07-01 22:30:00.000Z", "Europe/Paris", "2023-07-02 00:30:00.000", false},
+		{"2023-01-01 03:00:00.123Z", "America/New_York", "2022-12-31 22:00:00.123", false},
+	}
+
+	for i, s := range scenarios {
+		result, err := search.ConvertTimezone(s.value, s.timezone)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if result != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+}
+
+func TestFilterDataBuildExprWithParams(t *testing.T) {
+	resolver := search.NewSimpleFieldResolver("test1", "test2")
+
+	params := dbx.Params{
+		"a": "example' OR 1=1 --",
+		"b": 123,
+	}
+
+	scenarios := []struct {
+		name         string
+		filterData   search.FilterData
+		expectError  bool
+		expectSql    string
+		expectValues []any
+	}{
+		{
+			"missing param",
+			"test1 = {:missing}",
+			true,
+			"",
+			nil,
+		},
+		{
+			"unknown field",
+			"unknown = {:a}",
+			true,
+			"",
+			nil,
+		},
+		{
+			"without placeholders",
+			"test1 = test2",
+			false,
+			"COALESCE([[test1]], '') = COALESCE([[test2]], '')",
+			nil,
+		},
+		{
+			"with placeholders",
+			"test1 = {:a} && (test2 > {:b} || test2 < {:b})",
+			false,
+			"(COALESCE([[test1]], '') = COALESCE({:p}, '') AND ([[test2]] > {:p} OR [[test2]] < {:p}))",
+			[]any{"example' OR 1=1 --", 123, 123},
+		},
+		{
+			"placeholders in quoted text",
+			"test1 = '{:a}' && test2 = \"{:b}\"",
+			false,
+			"(COALESCE([[test1]], '') = COALESCE({:p}, '') AND COALESCE([[test2]], '') = COALESCE({:p}, ''))",
+			[]any{"{:a}", "{:b}"},
+		},
+	}
+
+	placeholderRegex := regexp.MustCompile(`\{:(\w+)\}`)
+
+	for _, s := range scenarios {
+		expr, err := s.filterData.BuildExprWithParams(resolver, params)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		exprParams := dbx.Params{}
+		rawSql := expr.Build(&dbx.DB{}, exprParams)
+
+		values := []any{}
+		normalizedSql := placeholderRegex.ReplaceAllStringFunc(rawSql, func(m string) string {
+			values = append(values, exprParams[placeholderRegex.FindStringSubmatch(m)[1]])
+			return "{:p}"
+		})
+
+		if normalizedSql != s.expectSql {
+			t.Errorf("[%s] Expected sql \n%v, \ngot \n%v", s.name, s.expectSql, normalizedSql)
+		}
+
+		if len(values) != len(s.expectValues) {
+			t.Errorf("[%s] Expected values %v, got %v", s.name, s.expectValues, values)
+			continue
+		}
+
+		for i, v := range s.expectValues {
+			if values[i] != v {
+				t.Errorf("[%s] Expected value %v at %d, got %v", s.name, v, i, values[i])
+			}
+		}
+	}
+
+	// role function with a params wrapped resolver
+	roleResolver := &testRoleResolver{search.NewSimpleFieldResolver("test1")}
+	expr, err := search.FilterData("@request.auth.hasRole({:a}) = true").BuildExprWithParams(roleResolver, params)
+	if err != nil {
+		t.Fatalf("Expected the role function to be resolved, got %v", err)
+	}
+	rawSql := expr.Build(&dbx.DB{}, dbx.Params{})
+	if !regexp.MustCompile(`HAS_ROLE\(\{:\w+\}\)`).MatchString(rawSql) {
+		t.Fatalf("Expected the role function expression, got %s", rawSql)
+	}
+}
+
+func TestFilterDataPlaceholders(t *testing.T) {
+	scenarios := []struct {
+		filterData search.FilterData
+		expected   []string
+	}{
+		{"", []string{}},
+		{"test1 = 123", []string{}},
+		{"test1 = {:} || test2 = {:a-b} || test3 = {:a", []string{}},
+		{"test1 = '{:a}' || test2 = \"{:b}\"", []string{}},
+		{"test1 = {:a} && test2 = '{:b}' && (test3 > {:c} || test3 < {:a})", []string{"a", "c"}},
+	}
+
+	for i, s := range scenarios {
+		result := s.filterData.Placeholders()
+
+		if len(result) != len(s.expected) {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+			continue
+		}
+
+		for j, name := range s.expected {
+			if result[j] != name {
+				t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+				break
+			}
+		}
+	}
+}
+
+func TestJoinFilters(t *testing.T) {
+	scenarios := []struct {
+		conjunction string
+		filters     []search.FilterData
+		expectError bool
+		expected    search.FilterData
+	}{
+		{"", nil, false, ""},
+		{"or", []search.FilterData{"", " "}, false, ""},
+		{"", []search.FilterData{"a > 1"}, false, "a > 1"},
+		{"or", []search.FilterData{"", "a > 1"}, false, "a > 1"},
+		{"", []search.FilterData{"a > 1", "b = 2 || c = 3"}, false, "(a > 1) && (b = 2 || c = 3)"},
+		{"AND", []search.FilterData{"a > 1", "b = 2"}, false, "(a > 1) && (b = 2)"},
+		{"or", []search.FilterData{"a > 1", "", "b = 2 && c = 3"}, false, "(a > 1) || (b = 2 && c = 3)"},
+		{"xor", []search.FilterData{"a > 1", "b = 2"}, true, ""},
+	}
+
+	for i, s := range scenarios {
+		result, err := search.JoinFilters(s.conjunction, s.filters...)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if result != s.expected {
+			t.Errorf("(%d) Expected %q, got %q", i, s.expected, result)
+		}
+	}
+}
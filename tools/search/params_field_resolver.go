@@ -0,0 +1,92 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+// paramIdentifierPrefix is the identifier prefix used to
+// replace the filter "{:name}" placeholders during parsing.
+const paramIdentifierPrefix = "@param."
+
+// paramsFieldResolver wraps a FieldResolver and resolves the
+// filter placeholder identifiers to their bound params values.
+type paramsFieldResolver struct {
+	FieldResolver
+
+	params dbx.Params
+}
+
+// Resolve implements `search.Resolve` interface.
+//
+// Returns error if the placeholder identifier doesn't have a param value.
+func (r *paramsFieldResolver) Resolve(field string) (resultName string, placeholderParams dbx.Params, err error) {
+	if !strings.HasPrefix(field, paramIdentifierPrefix) {
+		return r.FieldResolver.Resolve(field)
+	}
+
+	name := strings.TrimPrefix(field, paramIdentifierPrefix)
+
+	value, ok := r.params[name]
+	if !ok {
+		return "", nil, fmt.Errorf("Missing filter param %q.", name)
+	}
+
+	placeholder := randomPlaceholder("p", r.FieldResolver)
+
+	return fmt.Sprintf("{:%s}", placeholder), dbx.Params{placeholder: value}, nil
+}
+
+// RandomSource implements the optional `search.RandomSourceFieldResolver`
+// interface by forwarding the call to the wrapped resolver (if supported).
+func (r *paramsFieldResolver) RandomSource() security.RandomSource {
+	if sourceResolver, ok := r.FieldResolver.(RandomSourceFieldResolver); ok {
+		return sourceResolver.RandomSource()
+	}
+
+	return nil
+}
+
+// Macros implements the optional `search.MacrosFieldResolver`
+// interface by forwarding the call to the wrapped resolver (if supported).
+func (r *paramsFieldResolver) Macros() map[string]string {
+	if macrosResolver, ok := r.FieldResolver.(MacrosFieldResolver); ok {
+		return macrosResolver.Macros()
+	}
+
+	return nil
+}
+
+// ResolveChangedSince implements the optional `search.HistoryFieldResolver`
+// interface by forwarding the call to the wrapped resolver (if supported).
+func (r *paramsFieldResolver) ResolveChangedSince(field string, since string) (string, dbx.Params, error) {
+	if historyResolver, ok := r.FieldResolver.(HistoryFieldResolver); ok {
+		return historyResolver.ResolveChangedSince(field, since)
+	}
+
+	return "", nil, errors.New("The changedSince() function is not supported.")
+}
+
+// ResolveChangedBy implements the optional `search.HistoryFieldResolver`
+// interface by forwarding the call to the wrapped resolver (if supported).
+func (r *paramsFieldResolver) ResolveChangedBy(field string) (string, dbx.Params, error) {
+	if historyResolver, ok := r.FieldResolver.(HistoryFieldResolver); ok {
+		return historyResolver.ResolveChangedBy(field)
+	}
+
+	return "", nil, errors.New("The changedBy() function is not supported.")
+}
+
+// ResolveAuthHasRole implements the optional `search.RoleFieldResolver`
+// interface by forwarding the call to the wrapped resolver (if supported).
+func (r *paramsFieldResolver) ResolveAuthHasRole(role string) (string, dbx.Params, error) {
+	if roleResolver, ok := r.FieldResolver.(RoleFieldResolver); ok {
+		return roleResolver.ResolveAuthHasRole(role)
+	}
+
+	return "", nil, errors.New("The hasRole() function is not supported.")
+}
@@ -1,9 +1,13 @@
 package search
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"math"
 	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -18,19 +22,59 @@ const MaxPerPage int = 500
 
 // url search query params
 const (
-	PageQueryParam    string = "page"
-	PerPageQueryParam string = "perPage"
-	SortQueryParam    string = "sort"
-	FilterQueryParam  string = "filter"
+	PageQueryParam      string = "page"
+	PerPageQueryParam   string = "perPage"
+	SortQueryParam      string = "sort"
+	FilterQueryParam    string = "filter"
+	CursorQueryParam    string = "cursor"
+	SkipTotalQueryParam string = "skipTotal"
+
+	// FiltersQueryParam is an array alternative of the repeatable FilterQueryParam.
+	FiltersQueryParam string = "filters[]"
+
+	// FilterConjunctionQueryParam specifies how multiple query filters
+	// are combined (see [FilterConjunctionAnd] and [FilterConjunctionOr]).
+	FilterConjunctionQueryParam string = "filterConjunction"
 )
 
+var plainColumnRegex = regexp.MustCompile(`^[\w\.]+$`)
+
+var orderDirectionRegex = regexp.MustCompile(`(?i)\s+(ASC|DESC)$`)
+
 // Result defines the returned search result structure.
+//
+// In cursor pagination mode (see [Provider.Cursor]) the result is
+// serialized only with its "perPage", "cursor" and "items" fields.
 type Result struct {
 	Page       int `json:"page"`
 	PerPage    int `json:"perPage"`
 	TotalItems int `json:"totalItems"`
 	TotalPages int `json:"totalPages"`
 	Items      any `json:"items"`
+
+	// Cursor is the opaque cursor of the next page items
+	// (empty string if there are no more items).
+	//
+	// It is nil if the cursor pagination mode is not enabled.
+	Cursor *string `json:"cursor,omitempty"`
+}
+
+// MarshalJSON implements the [json.Marshaler] interface.
+func (r Result) MarshalJSON() ([]byte, error) {
+	if r.Cursor == nil {
+		type alias Result
+		return json.Marshal(alias(r))
+	}
+
+	return json.Marshal(struct {
+		PerPage int    `json:"perPage"`
+		Cursor  string `json:"cursor"`
+		Items   any    `json:"items"`
+	}{
+		PerPage: r.PerPage,
+		Cursor:  *r.Cursor,
+		Items:   r.Items,
+	})
 }
 
 // Provider represents a single configured search provider instance.
@@ -41,6 +85,9 @@ type Provider struct {
 	perPage       int
 	sort          []SortField
 	filter        []FilterData
+	cursor        string
+	cursorMode    bool
+	skipTotal     bool
 }
 
 // NewProvider creates and returns a new search provider.
@@ -86,6 +133,28 @@ func (s *Provider) PerPage(perPage int) *Provider {
 	return s
 }
 
+// Cursor enables the cursor (aka. keyset) pagination mode and sets
+// the opaque cursor of the items to fetch (empty string for the first page).
+//
+// In cursor pagination mode the `page` field is ignored and no total items
+// count is performed. Instead the items after the provided cursor (based on
+// the search sort fields and the base table `id` as tie breaker) are
+// returned together with the cursor of the next page.
+func (s *Provider) Cursor(cursor string) *Provider {
+	s.cursor = cursor
+	s.cursorMode = true
+	return s
+}
+
+// SkipTotal changes the `skipTotal` field of the current search provider.
+//
+// When enabled the total items count query is not performed and
+// the result `TotalItems` and `TotalPages` fields are set to -1.
+func (s *Provider) SkipTotal(skipTotal bool) *Provider {
+	s.skipTotal = skipTotal
+	return s
+}
+
 // Sort sets the `sort` field of the current search provider.
 func (s *Provider) Sort(sort []SortField) *Provider {
 	s.sort = sort
@@ -140,19 +209,57 @@ func (s *Provider) Parse(urlQuery string) error {
 		s.PerPage(perPage)
 	}
 
+	if rawSkipTotal := params.Get(SkipTotalQueryParam); rawSkipTotal != "" {
+		skipTotal, err := strconv.ParseBool(rawSkipTotal)
+		if err != nil {
+			return err
+		}
+		s.SkipTotal(skipTotal)
+	}
+
+	if params.Has(CursorQueryParam) {
+		s.Cursor(params.Get(CursorQueryParam))
+	}
+
 	if rawSort := params.Get(SortQueryParam); rawSort != "" {
 		for _, sortField := range ParseSortFromString(rawSort) {
 			s.AddSort(sortField)
 		}
 	}
 
-	if rawFilter := params.Get(FilterQueryParam); rawFilter != "" {
-		s.AddFilter(FilterData(rawFilter))
+	filter, err := ParseFilterFromQuery(params)
+	if err != nil {
+		return err
 	}
+	s.AddFilter(filter)
 
 	return nil
 }
 
+// ParseFilterFromQuery combines all "filter" and "filters[]" query params
+// into a single filter expression using the "filterConjunction" query param
+// (default to [FilterConjunctionAnd]).
+//
+// Returns an empty FilterData if there are no query filters.
+//
+// Example:
+//
+//	params, _ := url.ParseQuery("filter=a>1&filter=b>2&filterConjunction=or")
+//	filter, err := search.ParseFilterFromQuery(params) // "(a>1) || (b>2)"
+func ParseFilterFromQuery(params url.Values) (FilterData, error) {
+	filters := make([]FilterData, 0, len(params[FilterQueryParam])+len(params[FiltersQueryParam]))
+
+	for _, raw := range params[FilterQueryParam] {
+		filters = append(filters, FilterData(raw))
+	}
+
+	for _, raw := range params[FiltersQueryParam] {
+		filters = append(filters, FilterData(raw))
+	}
+
+	return JoinFilters(params.Get(FilterConjunctionQueryParam), filters...)
+}
+
 // Exec executes the search provider and fills/scans
 // the provided `items` slice with the found models.
 func (s *Provider) Exec(items any) (*Result, error) {
@@ -190,22 +297,6 @@ func (s *Provider) Exec(items any) (*Result, error) {
 		return nil, err
 	}
 
-	queryInfo := modelsQuery.Info()
-
-	// count
-	var totalCount int64
-	var baseTable string
-	if len(queryInfo.From) > 0 {
-		baseTable = queryInfo.From[0]
-	}
-	countQuery := modelsQuery
-	rawCountQuery := countQuery.Select(strings.Join([]string{baseTable, "id"}, ".")).OrderBy().Build().SQL()
-	wrappedCountQuery := queryInfo.Builder.NewQuery("SELECT COUNT(*) FROM (" + rawCountQuery + ")")
-	wrappedCountQuery.Bind(countQuery.Build().Params())
-	if err := wrappedCountQuery.Row(&totalCount); err != nil {
-		return nil, err
-	}
-
 	// normalize perPage
 	if s.perPage <= 0 {
 		s.perPage = DefaultPerPage
@@ -213,13 +304,42 @@ func (s *Provider) Exec(items any) (*Result, error) {
 		s.perPage = MaxPerPage
 	}
 
-	totalPages := int(math.Ceil(float64(totalCount) / float64(s.perPage)))
+	if s.cursorMode {
+		return s.execCursor(&modelsQuery, items)
+	}
+
+	totalCount := int64(-1)
+	totalPages := -1
+
+	if s.skipTotal {
+		// normalize page without the total count
+		if s.page <= 0 {
+			s.page = 1
+		}
+	} else {
+		queryInfo := modelsQuery.Info()
+
+		// count
+		var baseTable string
+		if len(queryInfo.From) > 0 {
+			baseTable = queryInfo.From[0]
+		}
+		countQuery := modelsQuery
+		rawCountQuery := countQuery.Select(strings.Join([]string{baseTable, "id"}, ".")).OrderBy().Build().SQL()
+		wrappedCountQuery := queryInfo.Builder.NewQuery("SELECT COUNT(*) FROM (" + rawCountQuery + ")")
+		wrappedCountQuery.Bind(countQuery.Build().Params())
+		if err := wrappedCountQuery.Row(&totalCount); err != nil {
+			return nil, err
+		}
 
-	// normalize page according to the total count
-	if s.page <= 0 || totalCount == 0 {
-		s.page = 1
-	} else if s.page > totalPages {
-		s.page = totalPages
+		totalPages = int(math.Ceil(float64(totalCount) / float64(s.perPage)))
+
+		// normalize page according to the total count
+		if s.page <= 0 || totalCount == 0 {
+			s.page = 1
+		} else if s.page > totalPages {
+			s.page = totalPages
+		}
 	}
 
 	// apply pagination
@@ -240,6 +360,116 @@ func (s *Provider) Exec(items any) (*Result, error) {
 	}, nil
 }
 
+// execCursor fetches the page items after the provider's cursor.
+func (s *Provider) execCursor(modelsQuery *dbx.SelectQuery, items any) (*Result, error) {
+	queryInfo := modelsQuery.Info()
+
+	idColumn := "id"
+	if len(queryInfo.From) > 0 {
+		idColumn = queryInfo.From[0] + ".id"
+	}
+
+	// the keyset of a non-deterministic order couldn't be restored
+	for _, col := range queryInfo.OrderBy {
+		if strings.Contains(strings.ToUpper(col), "RANDOM()") {
+			return nil, errors.New("The unseeded random sort is not supported in cursor mode.")
+		}
+	}
+
+	// ensure that the items order is deterministic
+	orderBy := queryInfo.OrderBy
+	hasIdOrder := false
+	for _, col := range orderBy {
+		name := strings.Trim(orderDirectionRegex.ReplaceAllString(col, ""), "[]`\"")
+		if name == idColumn || name == "id" {
+			hasIdOrder = true
+			break
+		}
+	}
+	if !hasIdOrder {
+		orderBy = append(orderBy, idColumn+" "+SortAsc)
+		modelsQuery.AndOrderBy(idColumn + " " + SortAsc)
+	}
+
+	keys := make([]keysetColumn, len(orderBy))
+	for i, col := range orderBy {
+		keys[i] = newKeysetColumn(col)
+	}
+
+	if s.cursor != "" {
+		values, err := decodeCursor(s.cursor)
+		if err != nil || len(values) != len(keys) {
+			return nil, errors.New("Invalid cursor.")
+		}
+
+		expr := keysetExpr(keys, values)
+
+		// aggregated sort expressions can be filtered only with HAVING
+		if len(queryInfo.GroupBy) > 0 {
+			modelsQuery.AndHaving(expr)
+		} else {
+			modelsQuery.AndWhere(expr)
+		}
+	}
+
+	// fetch the sort values of the page items
+	// (+1 to check whether there are more items)
+	selects := make([]string, len(keys))
+	for i, key := range keys {
+		selects[i] = key.expr
+	}
+	keysQuery := *modelsQuery
+	keysQuery.Select(selects...).Limit(int64(s.perPage + 1))
+
+	rows, err := keysQuery.Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var total int
+	var lastValues []any
+	for rows.Next() {
+		total++
+		if total > s.perPage {
+			break
+		}
+
+		lastValues = make([]any, len(keys))
+		dest := make([]any, len(keys))
+		for i := range lastValues {
+			dest[i] = &lastValues[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	var nextCursor string
+	if total > s.perPage {
+		nextCursor, err = encodeCursor(lastValues)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// fetch models
+	modelsQuery.Limit(int64(s.perPage))
+	if err := modelsQuery.All(items); err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		PerPage: s.perPage,
+		Cursor:  &nextCursor,
+		Items:   items,
+	}, nil
+}
+
 // ParseAndExec is a short convenient method to trigger both
 // `Parse()` and `Exec()` in a single call.
 func (s *Provider) ParseAndExec(urlQuery string, modelsSlice any) (*Result, error) {
@@ -249,3 +479,119 @@ func (s *Provider) ParseAndExec(urlQuery string, modelsSlice any) (*Result, erro
 
 	return s.Exec(modelsSlice)
 }
+
+// -------------------------------------------------------------------
+
+// keysetColumn defines a single cursor pagination sort expression.
+type keysetColumn struct {
+	expr string
+	desc bool
+}
+
+// newKeysetColumn parses a raw "expr [ASC|DESC]" order by column.
+func newKeysetColumn(col string) keysetColumn {
+	col = strings.TrimSpace(col)
+
+	result := keysetColumn{expr: col}
+
+	if matches := orderDirectionRegex.FindStringSubmatch(col); len(matches) > 0 {
+		result.expr = col[:len(col)-len(matches[0])]
+		result.desc = strings.ToUpper(matches[1]) == SortDesc
+	}
+
+	if plainColumnRegex.MatchString(result.expr) {
+		result.expr = "[[" + result.expr + "]]"
+	}
+
+	return result
+}
+
+// keysetExpr builds a db expression that matches all items positioned
+// after the provided keys values.
+//
+// NULL values are treated as the smallest ones (aka. the default SQLite order).
+func keysetExpr(keys []keysetColumn, values []any) dbx.Expression {
+	params := dbx.Params{}
+	parts := []string{}
+
+	for i, key := range keys {
+		param := fmt.Sprintf("keyset%d", i)
+		params[param] = values[i]
+
+		var cmp string
+		switch {
+		case key.desc && values[i] == nil:
+			cmp = "" // nothing is after NULL
+		case key.desc:
+			cmp = fmt.Sprintf("(%s < {:%s} OR %s IS NULL)", key.expr, param, key.expr)
+		case values[i] == nil:
+			cmp = fmt.Sprintf("%s IS NOT NULL", key.expr)
+		default:
+			cmp = fmt.Sprintf("%s > {:%s}", key.expr, param)
+		}
+
+		if cmp != "" {
+			conds := make([]string, 0, i+1)
+			for j := 0; j < i; j++ {
+				conds = append(conds, fmt.Sprintf("%s IS {:keyset%d}", keys[j].expr, j))
+			}
+			conds = append(conds, cmp)
+			parts = append(parts, "("+strings.Join(conds, " AND ")+")")
+		}
+	}
+
+	if len(parts) == 0 {
+		return dbx.NewExp("1=0")
+	}
+
+	return dbx.NewExp(strings.Join(parts, " OR "), params)
+}
+
+func encodeCursor(values []any) (string, error) {
+	normalized := make([]any, len(values))
+	for i, v := range values {
+		if b, ok := v.([]byte); ok {
+			v = string(b)
+		}
+		normalized[i] = v
+	}
+
+	raw, err := json.Marshal(normalized)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeCursor(cursor string) ([]any, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(raw)))
+	decoder.UseNumber()
+
+	values := []any{}
+	if err := decoder.Decode(&values); err != nil {
+		return nil, err
+	}
+
+	for i, v := range values {
+		switch val := v.(type) {
+		case json.Number:
+			if n, err := val.Int64(); err == nil {
+				values[i] = n
+			} else {
+				values[i], _ = val.Float64()
+			}
+		case string, bool, nil:
+			// scalar
+		default:
+			return nil, errors.New("Invalid cursor value.")
+		}
+	}
+
+	return values, nil
+}
@@ -60,6 +60,40 @@ func TestProviderPerPage(t *testing.T) {
 	}
 }
 
+func TestProviderCursor(t *testing.T) {
+	r := &testFieldResolver{}
+	p := NewProvider(r)
+
+	if p.cursorMode {
+		t.Fatal("Expected cursorMode to be disabled by default")
+	}
+
+	p.Cursor("test")
+
+	if !p.cursorMode {
+		t.Fatal("Expected cursorMode to be enabled")
+	}
+
+	if p.cursor != "test" {
+		t.Fatalf("Expected cursor %q, got %q", "test", p.cursor)
+	}
+}
+
+func TestProviderSkipTotal(t *testing.T) {
+	r := &testFieldResolver{}
+	p := NewProvider(r)
+
+	if p.skipTotal {
+		t.Fatal("Expected skipTotal to be disabled by default")
+	}
+
+	p.SkipTotal(true)
+
+	if !p.skipTotal {
+		t.Fatal("Expected skipTotal to be enabled")
+	}
+}
+
 func TestProviderSort(t *testing.T) {
 	initialSort := []SortField{{"test1", SortAsc}, {"test2", SortAsc}}
 	r := &testFieldResolver{}
@@ -149,6 +183,33 @@ func TestProviderParse(t *testing.T) {
 			`[{"name":"test1","direction":"ASC"},{"name":"test2","direction":"ASC"},{"name":"a","direction":"DESC"},{"name":"b","direction":"ASC"},{"name":"c","direction":"ASC"}]`,
 			`["test1","test2","test3"]`,
 		},
+		// multiple filters with the default conjunction
+		{
+			"filter=test3&filter=&filters[]=test4",
+			false,
+			initialPage,
+			initialPerPage,
+			`[{"name":"test1","direction":"ASC"},{"name":"test2","direction":"ASC"}]`,
+			`["test1","test2","(test3) \u0026\u0026 (test4)"]`,
+		},
+		// multiple filters with "or" conjunction
+		{
+			"filters[]=test3&filters[]=test4&filterConjunction=or",
+			false,
+			initialPage,
+			initialPerPage,
+			`[{"name":"test1","direction":"ASC"},{"name":"test2","direction":"ASC"}]`,
+			`["test1","test2","(test3) || (test4)"]`,
+		},
+		// invalid filter conjunction
+		{
+			"filter=test3&filter=test4&filterConjunction=xor",
+			true,
+			initialPage,
+			initialPerPage,
+			`[{"name":"test1","direction":"ASC"},{"name":"test2","direction":"ASC"}]`,
+			`["test1","test2"]`,
+		},
 	}
 
 	for i, s := range scenarios {
@@ -351,6 +412,219 @@ func TestProviderExecNonEmptyQuery(t *testing.T) {
 	}
 }
 
+func TestProviderExecCursor(t *testing.T) {
+	testDB, err := createTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.Close()
+
+	query := testDB.Select("*").
+		From("test").
+		Where(dbx.Not(dbx.HashExp{"test1": nil})).
+		OrderBy("test1 ASC")
+
+	scenarios := []struct {
+		name          string
+		cursor        string
+		perPage       int
+		sort          []SortField
+		expectError   bool
+		expectResult  string
+		expectQueries []string
+	}{
+		{
+			"invalid cursor encoding",
+			"invalid!",
+			1,
+			[]SortField{},
+			true,
+			"",
+			nil,
+		},
+		{
+			"invalid cursor keys",
+			"WzFd", // [1]
+			1,
+			[]SortField{},
+			true,
+			"",
+			nil,
+		},
+		{
+			"unseeded random sort",
+			"",
+			1,
+			[]SortField{{RandomSortField, SortAsc}},
+			true,
+			"",
+			nil,
+		},
+		{
+			"first page",
+			"",
+			1,
+			[]SortField{},
+			false,
+			`{"perPage":1,"cursor":"WzEsMV0","items":[{"test1":1,"test2":"test2.1","test3":""}]}`,
+			[]string{
+				"SELECT [[test1]], [[test.id]] FROM `test` WHERE NOT (`test1` IS NULL) ORDER BY `test1` ASC, `test`.`id` ASC LIMIT 2",
+				"SELECT * FROM `test` WHERE NOT (`test1` IS NULL) ORDER BY `test1` ASC, `test`.`id` ASC LIMIT 1",
+			},
+		},
+		{
+			"last page",
+			"WzEsMV0", // [1,1]
+			1,
+			[]SortField{},
+			false,
+			`{"perPage":1,"cursor":"","items":[{"test1":2,"test2":"test2.2","test3":""}]}`,
+			[]string{
+				"SELECT [[test1]], [[test.id]] FROM `test` WHERE (NOT (`test1` IS NULL)) AND (([[test1]] > 1) OR ([[test1]] IS 1 AND [[test.id]] > 1)) ORDER BY `test1` ASC, `test`.`id` ASC LIMIT 2",
+				"SELECT * FROM `test` WHERE (NOT (`test1` IS NULL)) AND (([[test1]] > 1) OR ([[test1]] IS 1 AND [[test.id]] > 1)) ORDER BY `test1` ASC, `test`.`id` ASC LIMIT 1",
+			},
+		},
+		{
+			"with sort fields (perPage normalization)",
+			"",
+			0,
+			[]SortField{{"test2", SortDesc}},
+			false,
+			`{"perPage":30,"cursor":"","items":[{"test1":1,"test2":"test2.1","test3":""},{"test1":2,"test2":"test2.2","test3":""}]}`,
+			[]string{
+				"SELECT [[test1]], [[test2]], [[test.id]] FROM `test` WHERE NOT (`test1` IS NULL) ORDER BY `test1` ASC, `test2` DESC, `test`.`id` ASC LIMIT 31",
+				"SELECT * FROM `test` WHERE NOT (`test1` IS NULL) ORDER BY `test1` ASC, `test2` DESC, `test`.`id` ASC LIMIT 30",
+			},
+		},
+		{
+			"with sort fields and cursor",
+			"WzEsInRlc3QyLjEiLDFd", // [1,"test2.1",1]
+			10,
+			[]SortField{{"test2", SortDesc}},
+			false,
+			`{"perPage":10,"cursor":"","items":[{"test1":2,"test2":"test2.2","test3":""}]}`,
+			[]string{
+				"SELECT [[test1]], [[test2]], [[test.id]] FROM `test` WHERE (NOT (`test1` IS NULL)) AND (([[test1]] > 1) OR ([[test1]] IS 1 AND ([[test2]] < 'test2.1' OR [[test2]] IS NULL)) OR ([[test1]] IS 1 AND [[test2]] IS 'test2.1' AND [[test.id]] > 1)) ORDER BY `test1` ASC, `test2` DESC, `test`.`id` ASC LIMIT 11",
+				"SELECT * FROM `test` WHERE (NOT (`test1` IS NULL)) AND (([[test1]] > 1) OR ([[test1]] IS 1 AND ([[test2]] < 'test2.1' OR [[test2]] IS NULL)) OR ([[test1]] IS 1 AND [[test2]] IS 'test2.1' AND [[test.id]] > 1)) ORDER BY `test1` ASC, `test2` DESC, `test`.`id` ASC LIMIT 10",
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		testDB.CalledQueries = []string{} // reset
+
+		testResolver := &testFieldResolver{}
+		p := NewProvider(testResolver).
+			Query(query).
+			Page(2). // should be ignored
+			PerPage(s.perPage).
+			Sort(s.sort).
+			Cursor(s.cursor)
+
+		result, err := p.Exec(&[]testTableStruct{})
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		encoded, _ := json.Marshal(result)
+		if string(encoded) != s.expectResult {
+			t.Errorf("[%s] Expected result %v, got \n%v", s.name, s.expectResult, string(encoded))
+		}
+
+		if len(s.expectQueries) != len(testDB.CalledQueries) {
+			t.Errorf("[%s] Expected %d queries, got %d: \n%v", s.name, len(s.expectQueries), len(testDB.CalledQueries), testDB.CalledQueries)
+			continue
+		}
+
+		for _, q := range testDB.CalledQueries {
+			if !list.ExistInSliceWithRegex(q, s.expectQueries) {
+				t.Errorf("[%s] Didn't expect query \n%v in \n%v", s.name, q, testDB.CalledQueries)
+			}
+		}
+	}
+}
+
+func TestProviderExecSkipTotal(t *testing.T) {
+	testDB, err := createTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.Close()
+
+	query := testDB.Select("*").
+		From("test").
+		Where(dbx.Not(dbx.HashExp{"test1": nil})).
+		OrderBy("test1 ASC")
+
+	scenarios := []struct {
+		name          string
+		queryString   string
+		expectResult  string
+		expectQueries []string
+	}{
+		{
+			"skipTotal=0",
+			"skipTotal=0&perPage=1",
+			`{"page":1,"perPage":1,"totalItems":2,"totalPages":2,"items":[{"test1":1,"test2":"test2.1","test3":""}]}`,
+			[]string{
+				"SELECT COUNT(*) FROM (SELECT `test`.`id` FROM `test` WHERE NOT (`test1` IS NULL))",
+				"SELECT * FROM `test` WHERE NOT (`test1` IS NULL) ORDER BY `test1` ASC LIMIT 1",
+			},
+		},
+		{
+			"skipTotal=1 (page normalization)",
+			"skipTotal=1&perPage=1&page=-1",
+			`{"page":1,"perPage":1,"totalItems":-1,"totalPages":-1,"items":[{"test1":1,"test2":"test2.1","test3":""}]}`,
+			[]string{
+				"SELECT * FROM `test` WHERE NOT (`test1` IS NULL) ORDER BY `test1` ASC LIMIT 1",
+			},
+		},
+		{
+			"skipTotal=1 (out of range page)",
+			"skipTotal=true&perPage=1&page=3",
+			`{"page":3,"perPage":1,"totalItems":-1,"totalPages":-1,"items":[]}`,
+			[]string{
+				"SELECT * FROM `test` WHERE NOT (`test1` IS NULL) ORDER BY `test1` ASC LIMIT 1 OFFSET 2",
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		testDB.CalledQueries = []string{} // reset
+
+		result, err := NewProvider(&testFieldResolver{}).
+			Query(query).
+			ParseAndExec(s.queryString, &[]testTableStruct{})
+		if err != nil {
+			t.Errorf("[%s] Unexpected error %v", s.name, err)
+			continue
+		}
+
+		encoded, _ := json.Marshal(result)
+		if string(encoded) != s.expectResult {
+			t.Errorf("[%s] Expected result %v, got \n%v", s.name, s.expectResult, string(encoded))
+		}
+
+		if len(s.expectQueries) != len(testDB.CalledQueries) {
+			t.Errorf("[%s] Expected %d queries, got %d: \n%v", s.name, len(s.expectQueries), len(testDB.CalledQueries), testDB.CalledQueries)
+			continue
+		}
+
+		for _, q := range testDB.CalledQueries {
+			if !list.ExistInSliceWithRegex(q, s.expectQueries) {
+				t.Errorf("[%s] Didn't expect query \n%v in \n%v", s.name, q, testDB.CalledQueries)
+			}
+		}
+	}
+}
+
 func TestProviderParseAndExec(t *testing.T) {
 	testDB, err := createTestDB()
 	if err != nil {
@@ -404,6 +678,24 @@ func TestProviderParseAndExec(t *testing.T) {
 			true,
 			"",
 		},
+		// invalid skipTotal
+		{
+			"skipTotal=a",
+			true,
+			"",
+		},
+		// invalid cursor
+		{
+			"cursor=invalid",
+			true,
+			"",
+		},
+		// cursor mode
+		{
+			"perPage=1&cursor=",
+			false,
+			`{"perPage":1,"cursor":"WzEsInRlc3QyLjEiLDFd","items":[{"test1":1,"test2":"test2.1","test3":""}]}`,
+		},
 		// valid query params
 		{
 			"page=3&perPage=9999&filter=test1>1&sort=-test2,test3",
@@ -6,6 +6,7 @@ import (
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/tools/inflector"
 	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/security"
 )
 
 // FieldResolver defines an interface for managing search fields.
@@ -21,6 +22,28 @@ type FieldResolver interface {
 	Resolve(field string) (name string, placeholderParams dbx.Params, err error)
 }
 
+// SortFieldResolver defines an optional FieldResolver interface
+// for resolving sort fields that need special handling
+// (eg. aggregating fields that could match more than one row).
+//
+// If implemented, it is used by `SortField.BuildExpr` instead of `Resolve`.
+type SortFieldResolver interface {
+	// ResolveSort parses the provided sort field and direction and returns
+	// a properly formatted db sort identifier (without the direction).
+	ResolveSort(field string, direction string) (name string, placeholderParams dbx.Params, err error)
+}
+
+// RandomSourceFieldResolver defines an optional FieldResolver interface
+// for providing a custom random source for the generated filter
+// placeholders (eg. a seeded source for deterministic tests).
+//
+// If implemented and non-nil, it is used by `FilterData.BuildExpr`
+// instead of the default pseudorandom generator.
+type RandomSourceFieldResolver interface {
+	// RandomSource returns the random source of the resolver (could be nil).
+	RandomSource() security.RandomSource
+}
+
 // NewSimpleFieldResolver creates a new `SimpleFieldResolver` with the
 // provided `allowedFields`.
 //
@@ -2,7 +2,10 @@ package search
 
 import (
 	"fmt"
+	"hash/fnv"
 	"strings"
+
+	"github.com/pocketbase/dbx"
 )
 
 // sort field directions
@@ -11,6 +14,17 @@ const (
 	SortDesc string = "DESC"
 )
 
+// RandomSortField is the name of the pseudo sort field that randomizes
+// the results order.
+//
+// It also supports a seeded variant ("@random:seed123") that produces
+// the same pseudo random order for the same seed, allowing a stable
+// pagination of the randomized results.
+const RandomSortField string = "@random"
+
+// randomSortModulus is the (prime) modulus of the seeded random sort expression.
+const randomSortModulus uint64 = 2147483647
+
 // SortField defines a single search sort field.
 type SortField struct {
 	Name      string `json:"name"`
@@ -19,7 +33,19 @@ type SortField struct {
 
 // BuildExpr resolves the sort field into a valid db sort expression.
 func (s *SortField) BuildExpr(fieldResolver FieldResolver) (string, error) {
-	name, params, err := fieldResolver.Resolve(s.Name)
+	if s.Name == RandomSortField || strings.HasPrefix(s.Name, RandomSortField+":") {
+		return s.buildRandomExpr(fieldResolver)
+	}
+
+	var name string
+	var params dbx.Params
+	var err error
+
+	if sortResolver, ok := fieldResolver.(SortFieldResolver); ok {
+		name, params, err = sortResolver.ResolveSort(s.Name, s.Direction)
+	} else {
+		name, params, err = fieldResolver.Resolve(s.Name)
+	}
 
 	// invalidate empty fields and non-column identifiers
 	if err != nil || len(params) > 0 || name == "" || strings.ToLower(name) == "null" {
@@ -29,6 +55,43 @@ func (s *SortField) BuildExpr(fieldResolver FieldResolver) (string, error) {
 	return fmt.Sprintf("%s %s", name, s.Direction), nil
 }
 
+// buildRandomExpr resolves the random sort field into a db sort expression.
+//
+// The seeded variant requires the "id" field to be resolvable and
+// orders the rows by a seed dependent permutation of their rowid.
+func (s *SortField) buildRandomExpr(fieldResolver FieldResolver) (string, error) {
+	if s.Name == RandomSortField {
+		return "RANDOM()", nil
+	}
+
+	seed := strings.TrimPrefix(s.Name, RandomSortField+":")
+	if seed == "" {
+		return "", fmt.Errorf("Invalid sort field %q.", s.Name)
+	}
+
+	idName, params, err := fieldResolver.Resolve("id")
+	if err != nil || len(params) > 0 || !strings.HasSuffix(idName, "id]]") {
+		return "", fmt.Errorf("Invalid sort field %q.", s.Name)
+	}
+	rowidName := strings.TrimSuffix(idName, "id]]") + "rowid]]"
+
+	h := fnv.New64a()
+	h.Write([]byte(seed))
+	sum := h.Sum64()
+
+	multiplier := sum%(randomSortModulus-1) + 1
+	increment := (sum >> 32) % randomSortModulus
+
+	return fmt.Sprintf(
+		"((%s * %d + %d) %% %d) %s",
+		rowidName,
+		multiplier,
+		increment,
+		randomSortModulus,
+		s.Direction,
+	), nil
+}
+
 // ParseSortFromString parses the provided string expression
 // into a slice of SortFields.
 //
@@ -2,8 +2,10 @@ package search_test
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 
+	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/tools/search"
 )
 
@@ -44,6 +46,75 @@ func TestSortFieldBuildExpr(t *testing.T) {
 	}
 }
 
+func TestSortFieldBuildExprRandom(t *testing.T) {
+	scenarios := []struct {
+		resolver         search.FieldResolver
+		sortField        search.SortField
+		expectError      bool
+		expectExpression string
+	}{
+		// unseeded (the direction is ignored)
+		{search.NewSimpleFieldResolver(), search.SortField{"@random", search.SortDesc}, false, "RANDOM()"},
+		// empty seed
+		{search.NewSimpleFieldResolver("id"), search.SortField{"@random:", search.SortAsc}, true, ""},
+		// seeded with non-resolvable id
+		{search.NewSimpleFieldResolver("test1"), search.SortField{"@random:test", search.SortAsc}, true, ""},
+		// seeded - asc
+		{search.NewSimpleFieldResolver("id"), search.SortField{"@random:test", search.SortAsc}, false, "(([[rowid]] * 18335474 + 2045175536) % 2147483647) ASC"},
+		// seeded - desc
+		{search.NewSimpleFieldResolver("id"), search.SortField{"@random:test", search.SortDesc}, false, "(([[rowid]] * 18335474 + 2045175536) % 2147483647) DESC"},
+		// different seed
+		{search.NewSimpleFieldResolver("id"), search.SortField{"@random:123", search.SortAsc}, false, "(([[rowid]] * 769772864 + 1164952088) % 2147483647) ASC"},
+	}
+
+	for i, s := range scenarios {
+		result, err := s.sortField.BuildExpr(s.resolver)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if result != s.expectExpression {
+			t.Errorf("(%d) Expected expression %v, got %v", i, s.expectExpression, result)
+		}
+	}
+}
+
+func TestSortFieldBuildExprWithSortFieldResolver(t *testing.T) {
+	resolver := &testSortFieldResolver{search.NewSimpleFieldResolver("test1")}
+
+	scenarios := []struct {
+		sortField        search.SortField
+		expectError      bool
+		expectExpression string
+	}{
+		// unknown field
+		{search.SortField{"unknown", search.SortAsc}, true, ""},
+		// resolve error
+		{search.SortField{"test1", "error"}, true, ""},
+		// allowed field - asc
+		{search.SortField{"test1", search.SortAsc}, false, "ASC([[test1]]) ASC"},
+		// allowed field - desc
+		{search.SortField{"test1", search.SortDesc}, false, "DESC([[test1]]) DESC"},
+	}
+
+	for i, s := range scenarios {
+		result, err := s.sortField.BuildExpr(resolver)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if result != s.expectExpression {
+			t.Errorf("(%d) Expected expression %v, got %v", i, s.expectExpression, result)
+		}
+	}
+}
+
 func TestParseSortFromString(t *testing.T) {
 	scenarios := []struct {
 		value        string
@@ -65,3 +136,22 @@ func TestParseSortFromString(t *testing.T) {
 		}
 	}
 }
+
+// ---
+
+type testSortFieldResolver struct {
+	*search.SimpleFieldResolver
+}
+
+func (r *testSortFieldResolver) ResolveSort(field string, direction string) (string, dbx.Params, error) {
+	if direction == "error" {
+		return "", nil, errors.New("test error")
+	}
+
+	name, params, err := r.Resolve(field)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return direction + "(" + name + ")", params, nil
+}
@@ -0,0 +1,134 @@
+package security
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// GenerateRSAPrivateKeyPEM generates a new RSA private key with
+// the specified bits size and returns it as PKCS#8 PEM string.
+func GenerateRSAPrivateKeyPEM(bits int) (string, error) {
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return "", err
+	}
+
+	return encodePrivateKeyPEM(key)
+}
+
+// GenerateEd25519PrivateKeyPEM generates a new Ed25519 private key
+// and returns it as PKCS#8 PEM string.
+func GenerateEd25519PrivateKeyPEM() (string, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", err
+	}
+
+	return encodePrivateKeyPEM(key)
+}
+
+func encodePrivateKeyPEM(key any) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+}
+
+// ParsePrivateKeyPEM parses a PKCS#1 (RSA) or PKCS#8 (RSA or Ed25519)
+// PEM encoded private key.
+func ParsePrivateKeyPEM(str string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(str))
+	if block == nil {
+		return nil, errors.New("Invalid PEM encoded key.")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		return key, nil
+	case ed25519.PrivateKey:
+		return key, nil
+	default:
+		return nil, errors.New("The key is not a RSA or Ed25519 private key.")
+	}
+}
+
+// AsymmetricSigningMethod returns the JWT signing method
+// for the provided private key (RS256 or EdDSA).
+func AsymmetricSigningMethod(key crypto.Signer) (jwt.SigningMethod, error) {
+	switch key.(type) {
+	case *rsa.PrivateKey:
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PrivateKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, errors.New("Unsupported signing key type.")
+	}
+}
+
+// NewAsymmetricToken generates and returns new RS256 or EdDSA
+// (based on the signing key type) signed JWT token with kid
+// as the key identifier header.
+func NewAsymmetricToken(payload jwt.MapClaims, signingKey crypto.Signer, kid string, secondsDuration int64) (string, error) {
+	method, err := AsymmetricSigningMethod(signingKey)
+	if err != nil {
+		return "", err
+	}
+
+	seconds := time.Duration(secondsDuration) * time.Second
+
+	claims := jwt.MapClaims{
+		"exp": time.Now().Add(seconds).Unix(),
+	}
+
+	for k, v := range payload {
+		claims[k] = v
+	}
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+
+	return token.SignedString(signingKey)
+}
+
+// ParseAsymmetricJWT verifies and parses RS256 or EdDSA JWT token and returns its claims.
+//
+// The verification public key is resolved from the token "kid" header with keyFunc.
+func ParseAsymmetricJWT(token string, keyFunc func(kid string) (crypto.PublicKey, error)) (jwt.MapClaims, error) {
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{
+		jwt.SigningMethodRS256.Alg(),
+		jwt.SigningMethodEdDSA.Alg(),
+	}))
+
+	parsedToken, err := parser.Parse(token, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return keyFunc(kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := parsedToken.Claims.(jwt.MapClaims); ok && parsedToken.Valid {
+		return claims, nil
+	}
+
+	return nil, errors.New("Unable to parse token.")
+}
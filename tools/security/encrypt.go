@@ -3,11 +3,16 @@ package security
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	crand "crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"io"
 	"strings"
+
+	"golang.org/x/crypto/hkdf"
 )
 
 // S256Challenge creates base64 encoded sha256 challenge string derived from code.
@@ -65,6 +70,36 @@ func Decrypt(cipherText string, key string) ([]byte, error) {
 		return nil, err
 	}
 
+	if len(cipherByte) < nonceSize {
+		return nil, errors.New("invalid cipher text")
+	}
+
 	nonce, cipherByteClean := cipherByte[:nonceSize], cipherByte[nonceSize:]
 	return gcm.Open(nil, nonce, cipherByteClean, nil)
 }
+
+// DeriveKey derives a new 32 bytes key (usable with [Encrypt] and [Decrypt])
+// from the provided master key and context info (eg. "collection:123")
+// using HKDF-SHA256.
+//
+// Note that the returned key contains the raw derived bytes
+// (aka. it is not a printable string).
+func DeriveKey(masterKey string, info string) string {
+	key := make([]byte, 32)
+
+	// reading 32 bytes from the HKDF-SHA256 stream can't fail
+	io.ReadFull(hkdf.New(sha256.New, []byte(masterKey), nil, []byte(info)), key)
+
+	return string(key)
+}
+
+// DeriveLegacyKey derives a 32 chars key from the provided master key
+// and context info by truncating the hex encoded HMAC-SHA256 of info.
+//
+// Deprecated: The truncated hex key carries only 128 bits of the HMAC output.
+// It is kept only to decrypt the data encrypted before [DeriveKey].
+func DeriveLegacyKey(masterKey string, info string) string {
+	h := hmac.New(sha256.New, []byte(masterKey))
+	h.Write([]byte(info))
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
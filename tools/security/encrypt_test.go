@@ -1,6 +1,7 @@
 package security_test
 
 import (
+	"encoding/hex"
 	"testing"
 
 	"github.com/pocketbase/pocketbase/tools/security"
@@ -72,6 +73,7 @@ func TestDecrypt(t *testing.T) {
 		{"", "", true, ""},
 		{"123", "test", true, ""}, // key must be valid 32 char aes string
 		{"8kcEqilvvYKYcfnSr0aSC54gmnQCsB02SaB8ATlnA==", "abcdabcdabcdabcdabcdabcdabcdabcd", true, ""}, // illegal base64 encoded cipherText
+		{"MTIz", "abcdabcdabcdabcdabcdabcdabcdabcd", true, ""},                                        // too short cipherText
 		{"8kcEqilvv+YKYcfnSr0aSC54gmnQCsB02SaB8ATlnA==", "abcdabcdabcdabcdabcdabcdabcdabcd", false, "123"},
 	}
 
@@ -91,3 +93,48 @@ func TestDecrypt(t *testing.T) {
 		}
 	}
 }
+
+func TestDeriveKey(t *testing.T) {
+	key1 := security.DeriveKey("abcdabcdabcdabcdabcdabcdabcdabcd", "test1")
+	key2 := security.DeriveKey("abcdabcdabcdabcdabcdabcdabcdabcd", "test2")
+	key3 := security.DeriveKey("bcdabcdabcdabcdabcdabcdabcdabcda", "test1")
+
+	if len(key1) != 32 {
+		t.Fatalf("Expected 32 bytes key, got %q", key1)
+	}
+
+	if key1 == key2 || key1 == key3 {
+		t.Fatalf("Expected different keys, got %q, %q, %q", key1, key2, key3)
+	}
+
+	// HKDF-SHA256 (empty salt) output
+	expected := "5678f70a122aa6bcc51a3fddb723dac65929ba1fb95454cf08e263eb4ff09783"
+	if hex.EncodeToString([]byte(key1)) != expected {
+		t.Fatalf("Expected key bytes %s, got %x", expected, key1)
+	}
+
+	if again := security.DeriveKey("abcdabcdabcdabcdabcdabcdabcdabcd", "test1"); again != key1 {
+		t.Fatalf("Expected the derived key to be deterministic, got %q vs %q", key1, again)
+	}
+
+	encrypted, err := security.Encrypt([]byte("123"), key1)
+	if err != nil {
+		t.Fatalf("Expected the derived key to be a valid aes key, got %v", err)
+	}
+
+	if _, err := security.Decrypt(encrypted, key2); err == nil {
+		t.Fatal("Expected the decryption with a different derived key to fail")
+	}
+}
+
+func TestDeriveLegacyKey(t *testing.T) {
+	key := security.DeriveLegacyKey("abcdabcdabcdabcdabcdabcdabcdabcd", "test1")
+
+	if key != "562498b65af07d3ae8cb6094b21791bb" {
+		t.Fatalf("Expected the truncated hex HMAC-SHA256 key, got %q", key)
+	}
+
+	if _, err := security.Encrypt([]byte("123"), key); err != nil {
+		t.Fatalf("Expected the legacy key to be a valid aes key, got %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	cryptoRand "crypto/rand"
 	"math/big"
 	mathRand "math/rand"
+	"sync"
 	"time"
 )
 
@@ -62,3 +63,65 @@ func PseudorandomStringWithAlphabet(length int, alphabet string) string {
 
 	return string(b)
 }
+
+// RandomSource defines a random numbers generator that could be used
+// as a replacement of the default random strings source
+// (eg. to generate deterministic ids and placeholders in tests).
+type RandomSource interface {
+	// Intn returns a non-negative random number in [0,n).
+	Intn(n int) int
+}
+
+// NewSeededRandomSource creates a new concurrent safe pseudorandom
+// source initialized with the provided seed.
+//
+// Sources with the same seed produce the same sequence of numbers.
+func NewSeededRandomSource(seed int64) RandomSource {
+	return &seededRandomSource{rand: mathRand.New(mathRand.NewSource(seed))}
+}
+
+type seededRandomSource struct {
+	mux  sync.Mutex
+	rand *mathRand.Rand
+}
+
+// Intn implements [RandomSource.Intn].
+func (s *seededRandomSource) Intn(n int) int {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return s.rand.Intn(n)
+}
+
+// PseudorandomStringWithSource generates a pseudorandom string with
+// the specified length using the provided random source.
+//
+// The generated string matches [A-Za-z0-9]+ and it's transparent to URL-encoding.
+//
+// Fallbacks to PseudorandomString if source is nil.
+func PseudorandomStringWithSource(source RandomSource, length int) string {
+	if source == nil {
+		return PseudorandomString(length)
+	}
+
+	return RandomStringWithSource(source, length, defaultRandomAlphabet)
+}
+
+// RandomStringWithSource generates a random string with the specified
+// length and characters set using the provided random source.
+//
+// Fallbacks to the cryptographically random RandomStringWithAlphabet if source is nil.
+func RandomStringWithSource(source RandomSource, length int, alphabet string) string {
+	if source == nil {
+		return RandomStringWithAlphabet(length, alphabet)
+	}
+
+	b := make([]byte, length)
+	max := len(alphabet)
+
+	for i := range b {
+		b[i] = alphabet[source.Intn(max)]
+	}
+
+	return string(b)
+}
@@ -23,6 +23,39 @@ func TestPseudorandomStringWithAlphabet(t *testing.T) {
 	testRandomStringWithAlphabet(t, security.PseudorandomStringWithAlphabet)
 }
 
+func TestRandomStringWithSource(t *testing.T) {
+	// nil source
+	testRandomStringWithAlphabet(t, func(n int, alphabet string) string {
+		return security.RandomStringWithSource(nil, n, alphabet)
+	})
+
+	// custom source
+	source := security.NewSeededRandomSource(1)
+	testRandomStringWithAlphabet(t, func(n int, alphabet string) string {
+		return security.RandomStringWithSource(source, n, alphabet)
+	})
+}
+
+func TestNewSeededRandomSource(t *testing.T) {
+	source1 := security.NewSeededRandomSource(123)
+	source2 := security.NewSeededRandomSource(123)
+	source3 := security.NewSeededRandomSource(456)
+
+	for i := 0; i < 10; i++ {
+		str1 := security.RandomStringWithSource(source1, 10, "abcdef123")
+		str2 := security.RandomStringWithSource(source2, 10, "abcdef123")
+		str3 := security.RandomStringWithSource(source3, 10, "abcdef123")
+
+		if str1 != str2 {
+			t.Fatalf("(%d) Expected the same seed sources to generate the same strings, got %q and %q", i, str1, str2)
+		}
+
+		if str1 == str3 {
+			t.Fatalf("(%d) Expected the different seed sources to generate different strings, got %q", i, str1)
+		}
+	}
+}
+
 // -------------------------------------------------------------------
 
 func testRandomStringWithAlphabet(t *testing.T, randomFunc func(n int, alphabet string) string) {
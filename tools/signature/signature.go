@@ -0,0 +1,139 @@
+// Package signature implements HMAC-SHA256 signing and verification
+// helpers for the outbound payloads (webhooks, export files, etc.).
+//
+// The payload signatures have the format "t={unixTimestamp},v1={hexHmac}",
+// where the signed content is "{unixTimestamp}.{payload}".
+//
+// Example verification of a received webhook request:
+//
+//	body, _ := io.ReadAll(r.Body)
+//	err := signature.Verify(secret, body, r.Header.Get(signature.Header), 5*time.Minute)
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header is the name of the http header with the payload signature.
+const Header = "X-PocketBase-Signature"
+
+// FileExt is the extension of the detached file signatures
+// (eg. "export.parquet" -> "export.parquet.sig").
+const FileExt = ".sig"
+
+// ErrInvalidSignature is returned when the signature doesn't match the signed data.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// ErrExpiredSignature is returned when the signature timestamp is outside of the allowed tolerance.
+var ErrExpiredSignature = errors.New("expired signature")
+
+// Sign creates a new timestamped signature of the provided payload.
+func Sign(secret string, payload []byte, timestamp time.Time) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+
+	return "t=" + t + ",v1=" + compute(secret, []byte(t+"."), payload)
+}
+
+// Verify checks whether the provided timestamped signature is valid for the payload.
+//
+// If tolerance is positive, signatures with timestamp older (or newer)
+// than the specified duration are also rejected.
+func Verify(secret string, payload []byte, signature string, tolerance time.Duration) error {
+	var t, v1 string
+
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			t = value
+		case "v1":
+			v1 = value
+		}
+	}
+
+	timestamp, err := strconv.ParseInt(t, 10, 64)
+	if err != nil || v1 == "" {
+		return ErrInvalidSignature
+	}
+
+	if !hmac.Equal([]byte(v1), []byte(compute(secret, []byte(t+"."), payload))) {
+		return ErrInvalidSignature
+	}
+
+	if tolerance > 0 {
+		diff := time.Since(time.Unix(timestamp, 0))
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > tolerance {
+			return ErrExpiredSignature
+		}
+	}
+
+	return nil
+}
+
+// SignFile creates the hex encoded HMAC-SHA256 signature of the file content.
+func SignFile(secret string, path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := hmac.New(sha256.New, []byte(secret))
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// WriteFileSignature signs the file content and stores the
+// signature in a new detached "{path}.sig" file.
+func WriteFileSignature(secret string, path string) error {
+	sig, err := SignFile(secret, path)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path+FileExt, []byte(sig), 0644)
+}
+
+// VerifyFile checks whether the file content matches
+// with its detached "{path}.sig" signature file.
+func VerifyFile(secret string, path string) error {
+	expected, err := os.ReadFile(path + FileExt)
+	if err != nil {
+		return err
+	}
+
+	sig, err := SignFile(secret, path)
+	if err != nil {
+		return err
+	}
+
+	if !hmac.Equal([]byte(strings.TrimSpace(string(expected))), []byte(sig)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+func compute(secret string, parts ...[]byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+
+	for _, p := range parts {
+		h.Write(p)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,93 @@
+package signature_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/signature"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	now := time.Now()
+	payload := []byte(`{"test":123}`)
+
+	sig := signature.Sign("secret", payload, now)
+	if !strings.HasPrefix(sig, "t=") || !strings.Contains(sig, ",v1=") {
+		t.Fatalf("Unexpected signature format %q", sig)
+	}
+
+	// deterministic
+	if sig2 := signature.Sign("secret", payload, now); sig != sig2 {
+		t.Fatalf("Expected %q, got %q", sig, sig2)
+	}
+
+	old := signature.Sign("secret", payload, now.Add(-1*time.Hour))
+
+	scenarios := []struct {
+		secret    string
+		payload   []byte
+		signature string
+		tolerance time.Duration
+		expectErr error
+	}{
+		{"secret", payload, sig, 0, nil},
+		{"secret", payload, sig, time.Minute, nil},
+		{"secret", payload, " v1=" + strings.Split(sig, ",v1=")[1] + ", " + strings.Split(sig, ",")[0], time.Minute, nil},
+		{"secret", payload, old, 0, nil},
+		{"secret", payload, old, time.Minute, signature.ErrExpiredSignature},
+		{"secret2", payload, sig, 0, signature.ErrInvalidSignature},
+		{"secret", []byte(`{"test":1234}`), sig, 0, signature.ErrInvalidSignature},
+		{"secret", payload, "", 0, signature.ErrInvalidSignature},
+		{"secret", payload, "t=abc,v1=123", 0, signature.ErrInvalidSignature},
+		{"secret", payload, strings.Split(sig, ",")[0], 0, signature.ErrInvalidSignature},
+	}
+
+	for i, s := range scenarios {
+		err := signature.Verify(s.secret, s.payload, s.signature, s.tolerance)
+		if !errors.Is(err, s.expectErr) {
+			t.Errorf("(%d) Expected error %v, got %v", i, s.expectErr, err)
+		}
+	}
+}
+
+func TestFileSignature(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.txt")
+
+	if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// missing signature file
+	if err := signature.VerifyFile("secret", path); err == nil {
+		t.Fatal("Expected error for missing signature file")
+	}
+
+	if err := signature.WriteFileSignature("secret", path); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(path + signature.FileExt); err != nil {
+		t.Fatalf("Expected the signature file to be created: %v", err)
+	}
+
+	if err := signature.VerifyFile("secret", path); err != nil {
+		t.Fatalf("Expected valid signature, got %v", err)
+	}
+
+	if err := signature.VerifyFile("secret2", path); !errors.Is(err, signature.ErrInvalidSignature) {
+		t.Fatalf("Expected invalid signature error for different secret, got %v", err)
+	}
+
+	// modify the file
+	if err := os.WriteFile(path, []byte("test2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := signature.VerifyFile("secret", path); !errors.Is(err, signature.ErrInvalidSignature) {
+		t.Fatalf("Expected invalid signature error for modified file, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+// Package sms implements a minimal SMS sending abstraction
+// together with some common SMS gateway adapters.
+package sms
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Supported SMS gateway providers.
+const (
+	ProviderTwilio = "twilio"
+	ProviderVonage = "vonage"
+)
+
+// Message defines a generic SMS message struct.
+type Message struct {
+	// From is the sender phone number or alphanumeric id.
+	From string
+
+	// To is the recipient phone number in E.164 format (eg. "+359888123456").
+	To string
+
+	// Body is the plain text message content.
+	Body string
+}
+
+// SMSSender defines a base SMS gateway client interface.
+type SMSSender interface {
+	// Send sends an SMS with the provided Message.
+	Send(message *Message) error
+}
+
+// postForm sends an url encoded form POST request and returns the
+// response body or an error if the response status is not successful.
+func postForm(
+	httpClient *http.Client,
+	endpoint string,
+	data url.Values,
+	modifyRequest func(req *http.Request),
+) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	if modifyRequest != nil {
+		modifyRequest(req)
+	}
+
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode >= 300 {
+		if len(body) > 1024 {
+			body = body[:1024]
+		}
+		return nil, fmt.Errorf("Failed to send the SMS (%d): %s", res.StatusCode, body)
+	}
+
+	return body, nil
+}
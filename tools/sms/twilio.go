@@ -0,0 +1,53 @@
+package sms
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var _ SMSSender = (*Twilio)(nil)
+
+// DefaultTwilioBaseUrl is the default Twilio REST api base url.
+const DefaultTwilioBaseUrl = "https://api.twilio.com"
+
+// Twilio defines a Twilio Programmable Messaging SMS client.
+type Twilio struct {
+	AccountSid string
+	AuthToken  string
+
+	// BaseUrl is an optional custom api base url
+	// (fallbacks to [DefaultTwilioBaseUrl]).
+	BaseUrl string
+
+	// HttpClient is an optional custom http client
+	// (fallbacks to http.Client with 10s timeout).
+	HttpClient *http.Client
+}
+
+// Send implements [SMSSender.Send] interface method.
+func (c *Twilio) Send(message *Message) error {
+	if c.AccountSid == "" || c.AuthToken == "" {
+		return errors.New("Missing Twilio account credentials.")
+	}
+
+	baseUrl := c.BaseUrl
+	if baseUrl == "" {
+		baseUrl = DefaultTwilioBaseUrl
+	}
+
+	endpoint := strings.TrimSuffix(baseUrl, "/") +
+		"/2010-04-01/Accounts/" + url.PathEscape(c.AccountSid) + "/Messages.json"
+
+	data := url.Values{}
+	data.Set("From", message.From)
+	data.Set("To", message.To)
+	data.Set("Body", message.Body)
+
+	_, err := postForm(c.HttpClient, endpoint, data, func(req *http.Request) {
+		req.SetBasicAuth(c.AccountSid, c.AuthToken)
+	})
+
+	return err
+}
@@ -0,0 +1,79 @@
+package sms_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/sms"
+)
+
+func TestTwilioSend(t *testing.T) {
+	var lastRequest *http.Request
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		lastRequest = r
+
+		user, pass, _ := r.BasicAuth()
+		if user != "test_sid" || pass != "test_token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"code":20003,"message":"Authenticate"}`))
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"sid":"SM123","status":"queued"}`))
+	}))
+	defer server.Close()
+
+	message := &sms.Message{From: "+15005550006", To: "+359888123456", Body: "test"}
+
+	scenarios := []struct {
+		name        string
+		client      *sms.Twilio
+		expectError bool
+	}{
+		{
+			"missing credentials",
+			&sms.Twilio{BaseUrl: server.URL},
+			true,
+		},
+		{
+			"invalid credentials",
+			&sms.Twilio{AccountSid: "test_sid", AuthToken: "invalid", BaseUrl: server.URL},
+			true,
+		},
+		{
+			"valid credentials",
+			&sms.Twilio{AccountSid: "test_sid", AuthToken: "test_token", BaseUrl: server.URL + "/"},
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		lastRequest = nil
+
+		err := s.client.Send(message)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		if lastRequest.URL.Path != "/2010-04-01/Accounts/test_sid/Messages.json" {
+			t.Errorf("[%s] Unexpected request path %q", s.name, lastRequest.URL.Path)
+		}
+
+		if lastRequest.PostForm.Get("From") != message.From ||
+			lastRequest.PostForm.Get("To") != message.To ||
+			lastRequest.PostForm.Get("Body") != message.Body {
+			t.Errorf("[%s] Unexpected request form %v", s.name, lastRequest.PostForm)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package sms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var _ SMSSender = (*Vonage)(nil)
+
+// DefaultVonageBaseUrl is the default Vonage SMS api base url.
+const DefaultVonageBaseUrl = "https://rest.nexmo.com"
+
+// Vonage defines a Vonage (formerly Nexmo) SMS api client.
+type Vonage struct {
+	ApiKey    string
+	ApiSecret string
+
+	// BaseUrl is an optional custom api base url
+	// (fallbacks to [DefaultVonageBaseUrl]).
+	BaseUrl string
+
+	// HttpClient is an optional custom http client
+	// (fallbacks to http.Client with 10s timeout).
+	HttpClient *http.Client
+}
+
+// Send implements [SMSSender.Send] interface method.
+func (c *Vonage) Send(message *Message) error {
+	if c.ApiKey == "" || c.ApiSecret == "" {
+		return errors.New("Missing Vonage api credentials.")
+	}
+
+	baseUrl := c.BaseUrl
+	if baseUrl == "" {
+		baseUrl = DefaultVonageBaseUrl
+	}
+
+	data := url.Values{}
+	data.Set("api_key", c.ApiKey)
+	data.Set("api_secret", c.ApiSecret)
+	data.Set("from", message.From)
+	// Vonage expects the numbers without the leading "+"
+	data.Set("to", strings.TrimPrefix(message.To, "+"))
+	data.Set("text", message.Body)
+	data.Set("type", "unicode")
+
+	body, err := postForm(c.HttpClient, strings.TrimSuffix(baseUrl, "/")+"/sms/json", data, nil)
+	if err != nil {
+		return err
+	}
+
+	// the api responds with 200 even on failure and
+	// reports the status of each message part separately
+	result := struct {
+		Messages []struct {
+			Status    string `json:"status"`
+			ErrorText string `json:"error-text"`
+		} `json:"messages"`
+	}{}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return err
+	}
+
+	if len(result.Messages) == 0 {
+		return errors.New("Failed to send the SMS: empty Vonage response.")
+	}
+
+	for _, m := range result.Messages {
+		if m.Status != "0" {
+			return fmt.Errorf("Failed to send the SMS (%s): %s", m.Status, m.ErrorText)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package sms_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/sms"
+)
+
+func TestVonageSend(t *testing.T) {
+	var lastRequest *http.Request
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		lastRequest = r
+
+		switch r.PostForm.Get("api_secret") {
+		case "test_secret":
+			w.Write([]byte(`{"message-count":"1","messages":[{"status":"0","message-id":"123"}]}`))
+		case "empty":
+			w.Write([]byte(`{"message-count":"0","messages":[]}`))
+		case "error":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.Write([]byte(`{"message-count":"1","messages":[{"status":"4","error-text":"Bad Credentials"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	message := &sms.Message{From: "Test", To: "+359888123456", Body: "test"}
+
+	scenarios := []struct {
+		name        string
+		client      *sms.Vonage
+		expectError bool
+	}{
+		{
+			"missing credentials",
+			&sms.Vonage{BaseUrl: server.URL},
+			true,
+		},
+		{
+			"invalid credentials",
+			&sms.Vonage{ApiKey: "test_key", ApiSecret: "invalid", BaseUrl: server.URL},
+			true,
+		},
+		{
+			"empty response messages",
+			&sms.Vonage{ApiKey: "test_key", ApiSecret: "empty", BaseUrl: server.URL},
+			true,
+		},
+		{
+			"server error",
+			&sms.Vonage{ApiKey: "test_key", ApiSecret: "error", BaseUrl: server.URL},
+			true,
+		},
+		{
+			"valid credentials",
+			&sms.Vonage{ApiKey: "test_key", ApiSecret: "test_secret", BaseUrl: server.URL},
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		lastRequest = nil
+
+		err := s.client.Send(message)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		if lastRequest.URL.Path != "/sms/json" {
+			t.Errorf("[%s] Unexpected request path %q", s.name, lastRequest.URL.Path)
+		}
+
+		if lastRequest.PostForm.Get("api_key") != "test_key" ||
+			lastRequest.PostForm.Get("from") != message.From ||
+			lastRequest.PostForm.Get("to") != "359888123456" ||
+			lastRequest.PostForm.Get("text") != message.Body {
+			t.Errorf("[%s] Unexpected request form %v", s.name, lastRequest.PostForm)
+		}
+	}
+}
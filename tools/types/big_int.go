@@ -0,0 +1,158 @@
+package types
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MaxSafeInteger is the max integer that could be represented
+// exactly as float64 (aka. the JSON number type of most clients).
+const MaxSafeInteger = 1<<53 - 1
+
+// BigInt defines an int64 value type that is serialized as JSON string
+// (to prevent the precision loss in the clients that handle
+// all JSON numbers as float64, eg. snowflake ids in JavaScript).
+type BigInt int64
+
+// ParseBigInt creates a new BigInt instance from the provided value
+// (could be BigInt, int, float64 with integer value, numeric string, json.Number, etc.).
+func ParseBigInt(value any) (BigInt, error) {
+	result := BigInt(0)
+	err := result.Scan(value)
+	return result, err
+}
+
+// String returns the current BigInt as decimal string.
+func (b BigInt) String() string {
+	return strconv.FormatInt(int64(b), 10)
+}
+
+// MarshalJSON implements the [json.Marshaler] interface.
+func (b BigInt) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.String())
+}
+
+// UnmarshalJSON implements the [json.Unmarshaler] interface
+// (both JSON strings and numbers are accepted).
+func (b *BigInt) UnmarshalJSON(data []byte) error {
+	str := string(data)
+
+	if unquoted, err := strconv.Unquote(str); err == nil {
+		str = unquoted
+	}
+
+	return b.Scan(str)
+}
+
+// Value implements the [driver.Valuer] interface.
+func (b BigInt) Value() (driver.Value, error) {
+	return int64(b), nil
+}
+
+// Scan implements [sql.Scanner] interface to scan the provided value
+// into the current BigInt instance.
+func (b *BigInt) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*b = 0
+	case BigInt:
+		*b = v
+	case int:
+		*b = BigInt(v)
+	case int32:
+		*b = BigInt(v)
+	case int64:
+		*b = BigInt(v)
+	case uint32:
+		*b = BigInt(v)
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) >= math.MaxInt64 {
+			return fmt.Errorf("Invalid BigInt value %v.", v)
+		}
+		*b = BigInt(v)
+	case json.Number:
+		return b.Scan(string(v))
+	case []byte:
+		return b.Scan(string(v))
+	case string:
+		v = strings.TrimSpace(v)
+		if v == "" {
+			*b = 0
+			return nil
+		}
+
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			// eg. "1e3" or "10.0"
+			f, floatErr := strconv.ParseFloat(v, 64)
+			if floatErr != nil {
+				return fmt.Errorf("Invalid BigInt value %q.", v)
+			}
+			return b.Scan(f)
+		}
+		*b = BigInt(n)
+	default:
+		return fmt.Errorf("Unsupported BigInt value type %T.", value)
+	}
+
+	return nil
+}
+
+// StringifyBigInts returns a copy of the provided JSON value
+// with all integers outside of the [MaxSafeInteger] range
+// converted to strings.
+func StringifyBigInts(raw JsonRaw) (JsonRaw, error) {
+	if len(raw) == 0 {
+		return raw, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var data any
+	if err := decoder.Decode(&data); err != nil {
+		return nil, err
+	}
+
+	result, err := json.Marshal(stringifyBigInts(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return JsonRaw(result), nil
+}
+
+func stringifyBigInts(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		n, err := strconv.ParseInt(string(v), 10, 64)
+		if err != nil {
+			// float or integer outside of the int64 range
+			if strings.ContainsAny(string(v), ".eE") {
+				return v
+			}
+			return string(v)
+		}
+		if n > MaxSafeInteger || n < -MaxSafeInteger {
+			return string(v)
+		}
+		return v
+	case map[string]any:
+		for key, item := range v {
+			v[key] = stringifyBigInts(item)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = stringifyBigInts(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
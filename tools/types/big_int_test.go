@@ -0,0 +1,114 @@
+package types_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestParseBigInt(t *testing.T) {
+	scenarios := []struct {
+		value       any
+		expectError bool
+		expected    int64
+	}{
+		{nil, false, 0},
+		{"", false, 0},
+		{" 123 ", false, 123},
+		{"-1234567890123456789", false, -1234567890123456789},
+		{"1e3", false, 1000},
+		{"1.5", true, 0},
+		{"test", true, 0},
+		{[]byte("9223372036854775807"), false, 9223372036854775807},
+		{json.Number("1234567890123456789"), false, 1234567890123456789},
+		{123, false, 123},
+		{int64(1234567890123456789), false, 1234567890123456789},
+		{10.0, false, 10},
+		{10.5, true, 0},
+		{types.BigInt(5), false, 5},
+		{true, true, 0},
+	}
+
+	for i, s := range scenarios {
+		result, err := types.ParseBigInt(s.value)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if int64(result) != s.expected {
+			t.Errorf("(%d) Expected %d, got %d", i, s.expected, result)
+		}
+	}
+}
+
+func TestBigIntJson(t *testing.T) {
+	raw, err := json.Marshal(types.BigInt(1234567890123456789))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(raw) != `"1234567890123456789"` {
+		t.Fatalf("Expected the BigInt to be serialized as string, got %s", raw)
+	}
+
+	for _, data := range []string{`"1234567890123456789"`, `1234567890123456789`} {
+		var b types.BigInt
+		if err := json.Unmarshal([]byte(data), &b); err != nil {
+			t.Fatal(err)
+		}
+
+		if b != 1234567890123456789 {
+			t.Fatalf("Expected %s to be unmarshalled as 1234567890123456789, got %d", data, b)
+		}
+	}
+}
+
+func TestBigIntValue(t *testing.T) {
+	val, err := types.BigInt(1234567890123456789).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, ok := val.(int64); !ok || v != 1234567890123456789 {
+		t.Fatalf("Expected int64 1234567890123456789, got %T %v", val, val)
+	}
+}
+
+func TestStringifyBigInts(t *testing.T) {
+	scenarios := []struct {
+		raw         types.JsonRaw
+		expectError bool
+		expected    string
+	}{
+		{nil, false, ``},
+		{types.JsonRaw(`{"invalid"`), true, ``},
+		{types.JsonRaw(`"test"`), false, `"test"`},
+		{types.JsonRaw(`9007199254740991`), false, `9007199254740991`},
+		{types.JsonRaw(`9007199254740992`), false, `"9007199254740992"`},
+		{types.JsonRaw(`99999999999999999999`), false, `"99999999999999999999"`},
+		{types.JsonRaw(`1.5e300`), false, `1.5e300`},
+		{
+			types.JsonRaw(`{"a":[1,-1234567890123456789,{"b":1234567890123456789}],"c":null}`),
+			false,
+			`{"a":[1,"-1234567890123456789",{"b":"1234567890123456789"}],"c":null}`,
+		},
+	}
+
+	for i, s := range scenarios {
+		result, err := types.StringifyBigInts(s.raw)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if result.String() != s.expected {
+			t.Errorf("(%d) Expected %s, got %s", i, s.expected, result)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Document defines a rich text document (html content and its
+// optional plain text representation) that is safe for json and db read/write.
+type Document struct {
+	Html string `form:"html" json:"html"`
+	Text string `form:"text" json:"text"`
+}
+
+// IsEmpty checks whether the document doesn't have any html content.
+func (d Document) IsEmpty() bool {
+	return strings.TrimSpace(d.Html) == ""
+}
+
+// ParseDocument creates a new Document from the provided value
+// (could be Document, *Document, map, json string or bytes).
+//
+// Plain strings that are not a json object with "html" key
+// are treated as the document html content.
+func ParseDocument(value any) (Document, error) {
+	var data []byte
+
+	// whether to fallback to the raw value as html content
+	isRaw := false
+
+	switch v := value.(type) {
+	case Document:
+		return v, nil
+	case *Document:
+		if v == nil {
+			return Document{}, errors.New("Missing document value.")
+		}
+		return *v, nil
+	case nil:
+		return Document{}, nil
+	case []byte:
+		data = v
+		isRaw = true
+	case string:
+		data = []byte(v)
+		isRaw = true
+	default:
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return Document{}, err
+		}
+		data = raw
+	}
+
+	raw := struct {
+		Html *string `json:"html"`
+		Text string  `json:"text"`
+	}{}
+
+	if err := json.Unmarshal(data, &raw); err != nil || raw.Html == nil {
+		if isRaw {
+			return Document{Html: string(data)}, nil
+		}
+
+		if err == nil {
+			err = errors.New("Missing document html content.")
+		}
+
+		return Document{}, err
+	}
+
+	return Document{Html: *raw.Html, Text: raw.Text}, nil
+}
+
+// Value implements the [driver.Valuer] interface.
+func (d Document) Value() (driver.Value, error) {
+	data, err := json.Marshal(d)
+
+	return string(data), err
+}
+
+// Scan implements [sql.Scanner] interface to scan the provided value
+// into the current `Document` instance.
+func (d *Document) Scan(value any) error {
+	doc, err := ParseDocument(value)
+	if err != nil {
+		return err
+	}
+
+	*d = doc
+
+	return nil
+}
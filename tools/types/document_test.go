@@ -0,0 +1,108 @@
+package types_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestParseDocument(t *testing.T) {
+	scenarios := []struct {
+		value       any
+		expectError bool
+		expected    string
+	}{
+		{nil, false, `{"html":"","text":""}`},
+		{"", false, `{"html":"","text":""}`},
+		{"test", false, `{"html":"test","text":""}`},
+		{`{"text":"test"}`, false, `{"html":"{\"text\":\"test\"}","text":""}`},
+		{`{"html":"a","text":"b"}`, false, `{"html":"a","text":"b"}`},
+		{[]byte(`{"html":"c"}`), false, `{"html":"c","text":""}`},
+		{map[string]any{"text": "test"}, true, ""},
+		{map[string]any{"html": "d", "text": "e"}, false, `{"html":"d","text":"e"}`},
+		{123, true, ""},
+		{(*types.Document)(nil), true, ""},
+		{types.Document{Html: "f"}, false, `{"html":"f","text":""}`},
+		{&types.Document{Html: "g", Text: "h"}, false, `{"html":"g","text":"h"}`},
+	}
+
+	for i, s := range scenarios {
+		doc, err := types.ParseDocument(s.value)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		raw, _ := json.Marshal(doc)
+		if string(raw) != s.expected {
+			t.Errorf("(%d) Expected %s, got %s", i, s.expected, raw)
+		}
+	}
+}
+
+func TestDocumentIsEmpty(t *testing.T) {
+	scenarios := []struct {
+		doc      types.Document
+		expected bool
+	}{
+		{types.Document{}, true},
+		{types.Document{Html: " \n", Text: "test"}, true},
+		{types.Document{Html: "test"}, false},
+	}
+
+	for i, s := range scenarios {
+		if result := s.doc.IsEmpty(); result != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, result)
+		}
+	}
+}
+
+func TestDocumentValue(t *testing.T) {
+	doc := types.Document{Html: "<b>test</b>", Text: "test"}
+
+	result, err := doc.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"html":"\u003cb\u003etest\u003c/b\u003e","text":"test"}`
+	if result != expected {
+		t.Fatalf("Expected %s, got %v", expected, result)
+	}
+}
+
+func TestDocumentScan(t *testing.T) {
+	scenarios := []struct {
+		value       any
+		expectError bool
+		expected    types.Document
+	}{
+		{nil, false, types.Document{}},
+		{map[string]any{"text": "test"}, true, types.Document{}},
+		{`{"html":"a","text":"b"}`, false, types.Document{Html: "a", Text: "b"}},
+		{[]byte(`{"html":"c"}`), false, types.Document{Html: "c"}},
+	}
+
+	for i, s := range scenarios {
+		doc := types.Document{}
+
+		err := doc.Scan(s.value)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if doc != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, doc)
+		}
+	}
+}
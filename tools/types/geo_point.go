@@ -0,0 +1,78 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// GeoPoint defines a longitude/latitude pair that is safe for json and db read/write.
+type GeoPoint struct {
+	Lon float64 `form:"lon" json:"lon"`
+	Lat float64 `form:"lat" json:"lat"`
+}
+
+// ParseGeoPoint creates a new GeoPoint from the provided value
+// (could be GeoPoint, *GeoPoint, map, json string or bytes).
+//
+// Returns an error if the value is missing the "lon" or "lat" key.
+func ParseGeoPoint(value any) (GeoPoint, error) {
+	var data []byte
+
+	switch v := value.(type) {
+	case GeoPoint:
+		return v, nil
+	case *GeoPoint:
+		if v == nil {
+			return GeoPoint{}, errors.New("Missing geo point value.")
+		}
+		return *v, nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return GeoPoint{}, err
+		}
+		data = raw
+	}
+
+	// use pointers to distinguish between zero and missing coordinates
+	raw := struct {
+		Lon *float64 `json:"lon"`
+		Lat *float64 `json:"lat"`
+	}{}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return GeoPoint{}, err
+	}
+
+	if raw.Lon == nil || raw.Lat == nil {
+		return GeoPoint{}, fmt.Errorf("Invalid geo point value %q.", data)
+	}
+
+	return GeoPoint{Lon: *raw.Lon, Lat: *raw.Lat}, nil
+}
+
+// Value implements the [driver.Valuer] interface.
+func (p GeoPoint) Value() (driver.Value, error) {
+	data, err := json.Marshal(p)
+
+	return string(data), err
+}
+
+// Scan implements [sql.Scanner] interface to scan the provided value
+// into the current `GeoPoint` instance.
+func (p *GeoPoint) Scan(value any) error {
+	point, err := ParseGeoPoint(value)
+	if err != nil {
+		return err
+	}
+
+	*p = point
+
+	return nil
+}
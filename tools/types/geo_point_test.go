@@ -0,0 +1,91 @@
+package types_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestParseGeoPoint(t *testing.T) {
+	scenarios := []struct {
+		value       any
+		expectError bool
+		expected    string
+	}{
+		{nil, true, ""},
+		{"", true, ""},
+		{"invalid", true, ""},
+		{`{"lon":1}`, true, ""},
+		{`{"lat":1}`, true, ""},
+		{(*types.GeoPoint)(nil), true, ""},
+		{`{"lon":0,"lat":0}`, false, `{"lon":0,"lat":0}`},
+		{`{"lon":-12.5,"lat":40.1}`, false, `{"lon":-12.5,"lat":40.1}`},
+		{[]byte(`{"lon":1,"lat":2}`), false, `{"lon":1,"lat":2}`},
+		{map[string]any{"lon": 3, "lat": 4.5}, false, `{"lon":3,"lat":4.5}`},
+		{types.GeoPoint{Lon: 5, Lat: 6}, false, `{"lon":5,"lat":6}`},
+		{&types.GeoPoint{Lon: 7, Lat: 8}, false, `{"lon":7,"lat":8}`},
+	}
+
+	for i, s := range scenarios {
+		point, err := types.ParseGeoPoint(s.value)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		raw, _ := json.Marshal(point)
+		if string(raw) != s.expected {
+			t.Errorf("(%d) Expected %s, got %s", i, s.expected, raw)
+		}
+	}
+}
+
+func TestGeoPointValue(t *testing.T) {
+	point := types.GeoPoint{Lon: -1.5, Lat: 2}
+
+	result, err := point.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"lon":-1.5,"lat":2}`
+	if result != expected {
+		t.Fatalf("Expected %s, got %v", expected, result)
+	}
+}
+
+func TestGeoPointScan(t *testing.T) {
+	scenarios := []struct {
+		value       any
+		expectError bool
+		expected    types.GeoPoint
+	}{
+		{nil, true, types.GeoPoint{}},
+		{`{"lon":1}`, true, types.GeoPoint{}},
+		{`{"lon":1,"lat":2}`, false, types.GeoPoint{Lon: 1, Lat: 2}},
+		{[]byte(`{"lon":3,"lat":4}`), false, types.GeoPoint{Lon: 3, Lat: 4}},
+	}
+
+	for i, s := range scenarios {
+		point := types.GeoPoint{}
+
+		err := point.Scan(s.value)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("(%d) Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if point != s.expected {
+			t.Errorf("(%d) Expected %v, got %v", i, s.expected, point)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// maxCborDepth is the max allowed nesting of the decoded CBOR arrays and maps.
+const maxCborDepth = 16
+
+var errCborUnexpectedEnd = errors.New("cbor: unexpected end of data")
+
+// decodeCbor decodes a single CBOR data item from the beginning
+// of data and returns it together with the remaining bytes.
+//
+// Only the definite length subset used by the WebAuthn attestation
+// objects and COSE keys is supported. The decoded values are:
+//   - int64 for the unsigned and negative integers
+//   - []byte for the byte strings
+//   - string for the text strings
+//   - []any for the arrays
+//   - map[any]any for the maps (with int64 or string keys)
+//   - bool, float64 or nil for the simple values
+//
+// The semantic tags are skipped and only their content is returned.
+func decodeCbor(data []byte) (any, []byte, error) {
+	return decodeCborItem(data, 0)
+}
+
+func decodeCborItem(data []byte, depth int) (any, []byte, error) {
+	if depth > maxCborDepth {
+		return nil, nil, errors.New("cbor: max nesting depth reached")
+	}
+
+	if len(data) == 0 {
+		return nil, nil, errCborUnexpectedEnd
+	}
+
+	major := data[0] >> 5
+	info := data[0] & 0x1f
+
+	// simple values and floats
+	if major == 7 {
+		return decodeCborSimple(info, data[1:])
+	}
+
+	arg, rest, err := decodeCborArgument(info, data[1:])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch major {
+	case 0: // unsigned integer
+		if arg > math.MaxInt64 {
+			return nil, nil, errors.New("cbor: integer overflow")
+		}
+		return int64(arg), rest, nil
+	case 1: // negative integer
+		if arg > math.MaxInt64 {
+			return nil, nil, errors.New("cbor: integer overflow")
+		}
+		return -1 - int64(arg), rest, nil
+	case 2, 3: // byte and text string
+		if arg > uint64(len(rest)) {
+			return nil, nil, errCborUnexpectedEnd
+		}
+		value := make([]byte, arg)
+		copy(value, rest[:arg])
+		if major == 3 {
+			return string(value), rest[arg:], nil
+		}
+		return value, rest[arg:], nil
+	case 4: // array
+		// each item is at least 1 byte long
+		if arg > uint64(len(rest)) {
+			return nil, nil, errCborUnexpectedEnd
+		}
+		items := make([]any, 0, arg)
+		for i := uint64(0); i < arg; i++ {
+			var item any
+			item, rest, err = decodeCborItem(rest, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			items = append(items, item)
+		}
+		return items, rest, nil
+	case 5: // map
+		// each key-value pair is at least 2 bytes long
+		if arg > uint64(len(rest))/2 {
+			return nil, nil, errCborUnexpectedEnd
+		}
+		items := make(map[any]any, arg)
+		for i := uint64(0); i < arg; i++ {
+			var key, value any
+			key, rest, err = decodeCborItem(rest, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, nil, fmt.Errorf("cbor: unsupported map key type %T", key)
+			}
+			value, rest, err = decodeCborItem(rest, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			items[key] = value
+		}
+		return items, rest, nil
+	default: // tag
+		return decodeCborItem(rest, depth+1)
+	}
+}
+
+// decodeCborArgument decodes the data item header argument
+// based on its additional info bits.
+func decodeCborArgument(info byte, data []byte) (uint64, []byte, error) {
+	switch {
+	case info < 24:
+		return uint64(info), data, nil
+	case info == 24:
+		if len(data) < 1 {
+			return 0, nil, errCborUnexpectedEnd
+		}
+		return uint64(data[0]), data[1:], nil
+	case info == 25:
+		if len(data) < 2 {
+			return 0, nil, errCborUnexpectedEnd
+		}
+		return uint64(binary.BigEndian.Uint16(data)), data[2:], nil
+	case info == 26:
+		if len(data) < 4 {
+			return 0, nil, errCborUnexpectedEnd
+		}
+		return uint64(binary.BigEndian.Uint32(data)), data[4:], nil
+	case info == 27:
+		if len(data) < 8 {
+			return 0, nil, errCborUnexpectedEnd
+		}
+		return binary.BigEndian.Uint64(data), data[8:], nil
+	case info == 31:
+		return 0, nil, errors.New("cbor: indefinite length items are not supported")
+	default:
+		return 0, nil, fmt.Errorf("cbor: invalid additional info %d", info)
+	}
+}
+
+// decodeCborSimple decodes a major type 7 data item (simple value or float).
+func decodeCborSimple(info byte, data []byte) (any, []byte, error) {
+	switch info {
+	case 20:
+		return false, data, nil
+	case 21:
+		return true, data, nil
+	case 22, 23: // null and undefined
+		return nil, data, nil
+	case 25:
+		if len(data) < 2 {
+			return nil, nil, errCborUnexpectedEnd
+		}
+		return float16ToFloat64(binary.BigEndian.Uint16(data)), data[2:], nil
+	case 26:
+		if len(data) < 4 {
+			return nil, nil, errCborUnexpectedEnd
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(data))), data[4:], nil
+	case 27:
+		if len(data) < 8 {
+			return nil, nil, errCborUnexpectedEnd
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(data)), data[8:], nil
+	default:
+		return nil, nil, fmt.Errorf("cbor: unsupported simple value %d", info)
+	}
+}
+
+// float16ToFloat64 converts an IEEE 754 half-precision float bits to float64.
+func float16ToFloat64(bits uint16) float64 {
+	sign := 1.0
+	if bits&0x8000 != 0 {
+		sign = -1.0
+	}
+
+	exp := int(bits>>10) & 0x1f
+	frac := float64(bits & 0x3ff)
+
+	switch exp {
+	case 0:
+		return sign * math.Ldexp(frac, -24)
+	case 0x1f:
+		if frac == 0 {
+			return math.Inf(int(sign))
+		}
+		return math.NaN()
+	default:
+		return sign * math.Ldexp(frac+1024, exp-25)
+	}
+}
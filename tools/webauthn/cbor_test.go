@@ -0,0 +1,117 @@
+package webauthn
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+)
+
+func TestDecodeCbor(t *testing.T) {
+	scenarios := []struct {
+		hex         string
+		expectError bool
+		expected    string // json serialized
+	}{
+		// invalid
+		{"", true, ``},
+		{"18", true, ``},
+		{"43aabb", true, ``},
+		{"5f", true, ``},
+		{"1c", true, ``},
+		{"1bffffffffffffffff", true, ``},
+		{"82aa", true, ``},
+		{"a1f401", true, ``},
+		{"ff", true, ``},
+		// integers
+		{"00", false, `0`},
+		{"17", false, `23`},
+		{"1818", false, `24`},
+		{"1903e8", false, `1000`},
+		{"1a000f4240", false, `1000000`},
+		{"20", false, `-1`},
+		{"3903e7", false, `-1000`},
+		// strings
+		{"4401020304", false, `"AQIDBA=="`},
+		{"6449455446", false, `"IETF"`},
+		// simple values and floats
+		{"f4", false, `false`},
+		{"f5", false, `true`},
+		{"f6", false, `null`},
+		{"f93e00", false, `1.5`},
+		{"fa47c35000", false, `100000`},
+		{"fb3ff199999999999a", false, `1.1`},
+		// tag
+		{"c11a514b67b0", false, `1363896240`},
+		// arrays and maps
+		{"83010203", false, `[1,2,3]`},
+		{"a26161016162820203", false, `{"a":1,"b":[2,3]}`},
+	}
+
+	for i, s := range scenarios {
+		data, err := hex.DecodeString(s.hex)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, rest, err := decodeCbor(data)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%d] Expected hasErr %v, got %v (%v)", i, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		if len(rest) != 0 {
+			t.Errorf("[%d] Expected no remaining bytes, got %v", i, rest)
+		}
+
+		if m, ok := result.(map[any]any); ok {
+			// convert to json serializable map
+			converted := map[string]any{}
+			for k, v := range m {
+				converted[k.(string)] = v
+			}
+			result = converted
+		}
+
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(encoded) != s.expected {
+			t.Errorf("[%d] Expected %s, got %s", i, s.expected, encoded)
+		}
+	}
+}
+
+func TestDecodeCborRemainingBytes(t *testing.T) {
+	result, rest, err := decodeCbor([]byte{0x01, 0x02, 0x03})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result != int64(1) {
+		t.Fatalf("Expected 1, got %v", result)
+	}
+
+	if len(rest) != 2 || rest[0] != 0x02 || rest[1] != 0x03 {
+		t.Fatalf("Expected remaining bytes [2 3], got %v", rest)
+	}
+}
+
+func TestDecodeCborMaxDepth(t *testing.T) {
+	data := []byte{}
+	for i := 0; i <= maxCborDepth+1; i++ {
+		data = append(data, 0x81) // array with 1 item
+	}
+	data = append(data, 0x00)
+
+	if _, _, err := decodeCbor(data); err == nil {
+		t.Fatal("Expected max depth error, got nil")
+	}
+}
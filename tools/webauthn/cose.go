@@ -0,0 +1,133 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Supported COSE signature algorithms.
+const (
+	AlgES256 = -7
+	AlgEdDSA = -8
+	AlgRS256 = -257
+)
+
+// SupportedAlgorithms lists the supported COSE signature algorithms
+// in order of preference.
+var SupportedAlgorithms = []int{AlgES256, AlgEdDSA, AlgRS256}
+
+// COSE key parameters and values (RFC 8152).
+const (
+	coseKeyKty = 1
+	coseKeyAlg = 3
+
+	coseKeyCrv = -1 // EC2 and OKP
+	coseKeyX   = -2 // EC2 and OKP
+	coseKeyY   = -3 // EC2
+	coseKeyN   = -1 // RSA
+	coseKeyE   = -2 // RSA
+
+	coseKtyOKP = 1
+	coseKtyEC2 = 2
+	coseKtyRSA = 3
+
+	coseCrvP256    = 1
+	coseCrvEd25519 = 6
+)
+
+// parseCoseKey converts the provided decoded COSE key map into
+// a public key and returns it together with its signature algorithm.
+func parseCoseKey(key map[any]any) (crypto.PublicKey, int, error) {
+	kty, _ := key[int64(coseKeyKty)].(int64)
+	alg, ok := key[int64(coseKeyAlg)].(int64)
+	if !ok {
+		return nil, 0, errors.New("missing COSE key algorithm")
+	}
+
+	switch {
+	case kty == coseKtyEC2 && alg == AlgES256:
+		crv, _ := key[int64(coseKeyCrv)].(int64)
+		x, _ := key[int64(coseKeyX)].([]byte)
+		y, _ := key[int64(coseKeyY)].([]byte)
+		if crv != coseCrvP256 || len(x) != 32 || len(y) != 32 {
+			return nil, 0, errors.New("invalid EC2 COSE key")
+		}
+
+		pub := &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, 0, errors.New("invalid EC2 COSE key point")
+		}
+
+		return pub, AlgES256, nil
+	case kty == coseKtyOKP && alg == AlgEdDSA:
+		crv, _ := key[int64(coseKeyCrv)].(int64)
+		x, _ := key[int64(coseKeyX)].([]byte)
+		if crv != coseCrvEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, 0, errors.New("invalid OKP COSE key")
+		}
+
+		return ed25519.PublicKey(x), AlgEdDSA, nil
+	case kty == coseKtyRSA && alg == AlgRS256:
+		n, _ := key[int64(coseKeyN)].([]byte)
+		e, _ := key[int64(coseKeyE)].([]byte)
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, 0, errors.New("invalid RSA COSE key")
+		}
+
+		pub := &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+
+		return pub, AlgRS256, nil
+	default:
+		return nil, 0, fmt.Errorf("unsupported COSE key type %d with algorithm %d", kty, alg)
+	}
+}
+
+// verifySignature checks whether sig is a valid alg signature
+// of the provided data with the public key.
+func verifySignature(pub crypto.PublicKey, alg int, data []byte, sig []byte) error {
+	invalidErr := errors.New("invalid signature")
+
+	switch alg {
+	case AlgES256:
+		key, ok := pub.(*ecdsa.PublicKey)
+		if !ok {
+			return invalidErr
+		}
+		hash := sha256.Sum256(data)
+		if !ecdsa.VerifyASN1(key, hash[:], sig) {
+			return invalidErr
+		}
+	case AlgEdDSA:
+		key, ok := pub.(ed25519.PublicKey)
+		if !ok || !ed25519.Verify(key, data, sig) {
+			return invalidErr
+		}
+	case AlgRS256:
+		key, ok := pub.(*rsa.PublicKey)
+		if !ok {
+			return invalidErr
+		}
+		hash := sha256.Sum256(data)
+		if rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], sig) != nil {
+			return invalidErr
+		}
+	default:
+		return fmt.Errorf("unsupported signature algorithm %d", alg)
+	}
+
+	return nil
+}
@@ -0,0 +1,342 @@
+// Package webauthn implements the server side verification of the
+// WebAuthn (aka. passkeys) registration and authentication ceremonies.
+//
+// Only the "none" and "packed" attestation statement formats are
+// supported and the attestation certificates chain is not verified
+// (the registration options should request "none" attestation conveyance).
+//
+// Example:
+//
+//	rp := &webauthn.RelyingParty{Id: "example.com", Origins: []string{"https://example.com"}}
+//
+//	// registration
+//	credential, err := rp.VerifyRegistration(challenge, clientDataJSON, attestationObject)
+//
+//	// authentication
+//	signCount, err := rp.VerifyAssertion(credential, challenge, clientDataJSON, authenticatorData, signature)
+package webauthn
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Client data types of the WebAuthn ceremonies.
+const (
+	ClientDataTypeCreate = "webauthn.create"
+	ClientDataTypeGet    = "webauthn.get"
+)
+
+// Authenticator data flags.
+const (
+	flagUserPresent          byte = 0x01
+	flagUserVerified         byte = 0x04
+	flagAttestedCredential   byte = 0x40
+	flagExtensionDataPresent byte = 0x80
+)
+
+// ChallengeLength is the number of random bytes of the generated challenges.
+const ChallengeLength = 32
+
+// RelyingParty defines a WebAuthn relying party (aka. the app).
+type RelyingParty struct {
+	// Id is the relying party identifier - the app domain (eg. "example.com").
+	Id string
+
+	// Origins is a list with the allowed client origins (eg. "https://example.com").
+	Origins []string
+}
+
+// Credential defines a verified public key credential.
+type Credential struct {
+	// Id is the raw credential id generated by the authenticator.
+	Id []byte
+
+	// PublicKey is the credential public key in PKIX, ASN.1 DER form.
+	PublicKey []byte
+
+	// Algorithm is the COSE identifier of the credential signature algorithm.
+	Algorithm int
+
+	// SignCount is the last known authenticator signature counter.
+	SignCount uint32
+}
+
+// NewChallenge generates a new random base64url encoded challenge.
+func NewChallenge() (string, error) {
+	b := make([]byte, ChallengeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return EncodeBase64(b), nil
+}
+
+// EncodeBase64 encodes b as unpadded base64url string
+// (the binary fields encoding of the WebAuthn JSON structures).
+func EncodeBase64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeBase64 decodes a base64url string (with or without padding).
+func DecodeBase64(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// VerifyRegistration verifies the authenticator response of a
+// registration ceremony started with the provided challenge
+// and returns the new credential.
+func (rp *RelyingParty) VerifyRegistration(challenge string, clientDataJSON []byte, attestationObject []byte) (*Credential, error) {
+	if err := rp.verifyClientData(clientDataJSON, ClientDataTypeCreate, challenge); err != nil {
+		return nil, err
+	}
+
+	rawAttestation, rest, err := decodeCbor(attestationObject)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("unexpected attestation object trailing data")
+	}
+
+	attestation, _ := rawAttestation.(map[any]any)
+	format, _ := attestation["fmt"].(string)
+	statement, _ := attestation["attStmt"].(map[any]any)
+	rawAuthData, _ := attestation["authData"].([]byte)
+
+	authData, err := rp.parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+
+	if authData.flags&flagAttestedCredential == 0 || len(authData.credentialId) == 0 {
+		return nil, errors.New("missing attested credential data")
+	}
+
+	pub, alg, err := parseCoseKey(authData.credentialKey)
+	if err != nil {
+		return nil, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signedData := append(append([]byte{}, rawAuthData...), clientDataHash[:]...)
+
+	if err := verifyAttestationStatement(format, statement, pub, alg, signedData); err != nil {
+		return nil, err
+	}
+
+	rawPub, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Credential{
+		Id:        authData.credentialId,
+		PublicKey: rawPub,
+		Algorithm: alg,
+		SignCount: authData.signCount,
+	}, nil
+}
+
+// VerifyAssertion verifies the authenticator response of an authentication
+// ceremony started with the provided challenge against the stored credential.
+//
+// On success it returns the new authenticator signature counter
+// that should be stored for the next assertion checks.
+func (rp *RelyingParty) VerifyAssertion(
+	credential *Credential,
+	challenge string,
+	clientDataJSON []byte,
+	authenticatorData []byte,
+	signature []byte,
+) (uint32, error) {
+	if err := rp.verifyClientData(clientDataJSON, ClientDataTypeGet, challenge); err != nil {
+		return 0, err
+	}
+
+	authData, err := rp.parseAuthenticatorData(authenticatorData)
+	if err != nil {
+		return 0, err
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(credential.PublicKey)
+	if err != nil {
+		return 0, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signedData := append(append([]byte{}, authenticatorData...), clientDataHash[:]...)
+
+	if err := verifySignature(pub, credential.Algorithm, signedData, signature); err != nil {
+		return 0, err
+	}
+
+	// authenticators without counter support always return 0
+	if (authData.signCount != 0 || credential.SignCount != 0) && authData.signCount <= credential.SignCount {
+		return 0, errors.New("invalid signature counter (possibly cloned authenticator)")
+	}
+
+	return authData.signCount, nil
+}
+
+// clientData defines the relevant CollectedClientData fields.
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+// verifyClientData checks the type, challenge and origin of the client data.
+func (rp *RelyingParty) verifyClientData(raw []byte, expectedType string, challenge string) error {
+	data := clientData{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return fmt.Errorf("invalid client data: %w", err)
+	}
+
+	if data.Type != expectedType {
+		return fmt.Errorf("invalid client data type %q", data.Type)
+	}
+
+	if challenge == "" || subtle.ConstantTimeCompare(
+		[]byte(strings.TrimRight(data.Challenge, "=")),
+		[]byte(strings.TrimRight(challenge, "=")),
+	) != 1 {
+		return errors.New("invalid client data challenge")
+	}
+
+	for _, origin := range rp.Origins {
+		if data.Origin == origin {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid client data origin %q", data.Origin)
+}
+
+// authenticatorData defines the parsed authenticator data fields.
+type authenticatorData struct {
+	flags         byte
+	signCount     uint32
+	credentialId  []byte
+	credentialKey map[any]any
+}
+
+// parseAuthenticatorData parses the raw authenticator data and checks
+// its relying party id hash and the user presence and verification flags.
+func (rp *RelyingParty) parseAuthenticatorData(data []byte) (*authenticatorData, error) {
+	// rpIdHash (32) + flags (1) + signCount (4)
+	if len(data) < 37 {
+		return nil, errors.New("invalid authenticator data length")
+	}
+
+	rpIdHash := sha256.Sum256([]byte(rp.Id))
+	if !bytes.Equal(data[:32], rpIdHash[:]) {
+		return nil, errors.New("invalid authenticator data relying party id hash")
+	}
+
+	result := &authenticatorData{
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+
+	if result.flags&flagUserPresent == 0 {
+		return nil, errors.New("the user is not present")
+	}
+
+	if result.flags&flagUserVerified == 0 {
+		return nil, errors.New("the user is not verified")
+	}
+
+	rest := data[37:]
+
+	if result.flags&flagAttestedCredential != 0 {
+		// aaguid (16) + credentialIdLength (2)
+		if len(rest) < 18 {
+			return nil, errors.New("invalid attested credential data length")
+		}
+
+		idLength := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if idLength == 0 || idLength > 1023 || len(rest) < idLength {
+			return nil, errors.New("invalid attested credential id length")
+		}
+
+		result.credentialId = append([]byte{}, rest[:idLength]...)
+
+		rawKey, keyRest, err := decodeCbor(rest[idLength:])
+		if err != nil {
+			return nil, err
+		}
+		result.credentialKey, _ = rawKey.(map[any]any)
+		if result.credentialKey == nil {
+			return nil, errors.New("invalid attested credential public key")
+		}
+		rest = keyRest
+	}
+
+	if result.flags&flagExtensionDataPresent != 0 {
+		var err error
+		if _, rest, err = decodeCbor(rest); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(rest) > 0 {
+		return nil, errors.New("unexpected authenticator data trailing bytes")
+	}
+
+	return result, nil
+}
+
+// verifyAttestationStatement verifies the attestation statement
+// of the specified format against the signed attestation data.
+func verifyAttestationStatement(
+	format string,
+	statement map[any]any,
+	credentialKey crypto.PublicKey,
+	credentialAlg int,
+	signedData []byte,
+) error {
+	switch format {
+	case "none":
+		if len(statement) > 0 {
+			return errors.New("unexpected none attestation statement")
+		}
+		return nil
+	case "packed":
+		alg, _ := statement["alg"].(int64)
+		sig, _ := statement["sig"].([]byte)
+		if len(sig) == 0 {
+			return errors.New("missing packed attestation signature")
+		}
+
+		x5c, _ := statement["x5c"].([]any)
+		if len(x5c) == 0 {
+			// self attestation
+			if int(alg) != credentialAlg {
+				return errors.New("packed self attestation algorithm mismatch")
+			}
+			return verifySignature(credentialKey, credentialAlg, signedData, sig)
+		}
+
+		rawCert, _ := x5c[0].([]byte)
+		cert, err := x509.ParseCertificate(rawCert)
+		if err != nil {
+			return fmt.Errorf("invalid packed attestation certificate: %w", err)
+		}
+
+		return verifySignature(cert.PublicKey, int(alg), signedData, sig)
+	default:
+		return fmt.Errorf("unsupported attestation format %q", format)
+	}
+}
@@ -0,0 +1,447 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const (
+	testRpId   = "example.com"
+	testOrigin = "https://example.com"
+)
+
+func testRelyingParty() *RelyingParty {
+	return &RelyingParty{Id: testRpId, Origins: []string{testOrigin}}
+}
+
+// testEncodeCbor is a minimal CBOR encoder used to build the test payloads.
+func testEncodeCbor(v any) []byte {
+	header := func(major byte, n int) []byte {
+		switch {
+		case n < 24:
+			return []byte{major<<5 | byte(n)}
+		case n < 256:
+			return []byte{major<<5 | 24, byte(n)}
+		default:
+			b := []byte{major<<5 | 25, 0, 0}
+			binary.BigEndian.PutUint16(b[1:], uint16(n))
+			return b
+		}
+	}
+
+	switch val := v.(type) {
+	case int:
+		if val < 0 {
+			return header(1, -1-val)
+		}
+		return header(0, val)
+	case []byte:
+		return append(header(2, len(val)), val...)
+	case string:
+		return append(header(3, len(val)), val...)
+	case []any:
+		result := header(4, len(val))
+		for _, item := range val {
+			result = append(result, testEncodeCbor(item)...)
+		}
+		return result
+	case map[any]any:
+		result := header(5, len(val))
+		for k, item := range val {
+			result = append(result, testEncodeCbor(k)...)
+			result = append(result, testEncodeCbor(item)...)
+		}
+		return result
+	default:
+		panic("unsupported test cbor value")
+	}
+}
+
+type testAuthenticator struct {
+	credentialId []byte
+	key          crypto.Signer
+	coseKey      map[any]any
+	alg          int
+}
+
+func newTestAuthenticator(t *testing.T, alg int) *testAuthenticator {
+	result := &testAuthenticator{credentialId: []byte("test_credential_id"), alg: alg}
+
+	switch alg {
+	case AlgES256:
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result.key = key
+		result.coseKey = map[any]any{
+			coseKeyKty: coseKtyEC2,
+			coseKeyAlg: AlgES256,
+			coseKeyCrv: coseCrvP256,
+			coseKeyX:   key.X.FillBytes(make([]byte, 32)),
+			coseKeyY:   key.Y.FillBytes(make([]byte, 32)),
+		}
+	case AlgEdDSA:
+		pub, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result.key = key
+		result.coseKey = map[any]any{
+			coseKeyKty: coseKtyOKP,
+			coseKeyAlg: AlgEdDSA,
+			coseKeyCrv: coseCrvEd25519,
+			coseKeyX:   []byte(pub),
+		}
+	}
+
+	return result
+}
+
+func (a *testAuthenticator) authData(rpId string, flags byte, signCount uint32, attested bool) []byte {
+	rpIdHash := sha256.Sum256([]byte(rpId))
+
+	data := append([]byte{}, rpIdHash[:]...)
+	data = append(data, flags)
+	data = append(data, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(data[len(data)-4:], signCount)
+
+	if attested {
+		data = append(data, make([]byte, 16)...) // aaguid
+		data = append(data, 0, 0)
+		binary.BigEndian.PutUint16(data[len(data)-2:], uint16(len(a.credentialId)))
+		data = append(data, a.credentialId...)
+		data = append(data, testEncodeCbor(a.coseKey)...)
+	}
+
+	return data
+}
+
+func (a *testAuthenticator) sign(t *testing.T, authData []byte, clientDataJSON []byte) []byte {
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	data := append(append([]byte{}, authData...), clientDataHash[:]...)
+
+	var sig []byte
+	var err error
+	if a.alg == AlgEdDSA {
+		sig, err = a.key.Sign(rand.Reader, data, crypto.Hash(0))
+	} else {
+		hash := sha256.Sum256(data)
+		sig, err = a.key.Sign(rand.Reader, hash[:], crypto.SHA256)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return sig
+}
+
+func testClientData(typ, challenge, origin string) []byte {
+	raw, _ := json.Marshal(map[string]string{
+		"type":      typ,
+		"challenge": challenge,
+		"origin":    origin,
+	})
+	return raw
+}
+
+func TestNewChallenge(t *testing.T) {
+	c1, err := NewChallenge()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c2, err := NewChallenge()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c1 == c2 {
+		t.Fatalf("Expected unique challenges, got %q twice", c1)
+	}
+
+	decoded, err := DecodeBase64(c1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded) != ChallengeLength {
+		t.Fatalf("Expected %d challenge bytes, got %d", ChallengeLength, len(decoded))
+	}
+}
+
+func TestDecodeBase64(t *testing.T) {
+	for _, s := range []string{"dGVzdA", "dGVzdA=="} {
+		result, err := DecodeBase64(s)
+		if err != nil {
+			t.Fatalf("[%s] %v", s, err)
+		}
+		if string(result) != "test" {
+			t.Fatalf("[%s] Expected test, got %q", s, result)
+		}
+	}
+
+	if _, err := DecodeBase64("dGV+zdA"); err == nil {
+		t.Fatal("Expected error for non-url base64 string")
+	}
+}
+
+func TestVerifyRegistration(t *testing.T) {
+	rp := testRelyingParty()
+	es256 := newTestAuthenticator(t, AlgES256)
+	eddsa := newTestAuthenticator(t, AlgEdDSA)
+	challenge := "test_challenge"
+	validFlags := flagUserPresent | flagUserVerified | flagAttestedCredential
+
+	attestation := func(format string, statement map[any]any, authData []byte) []byte {
+		return testEncodeCbor(map[any]any{
+			"fmt":      format,
+			"attStmt":  statement,
+			"authData": authData,
+		})
+	}
+
+	validClientData := testClientData(ClientDataTypeCreate, challenge, testOrigin)
+	validAuthData := es256.authData(testRpId, validFlags, 0, true)
+
+	scenarios := []struct {
+		name              string
+		authenticator     *testAuthenticator
+		clientData        []byte
+		attestationObject []byte
+		expectError       bool
+	}{
+		{
+			"invalid client data type",
+			es256,
+			testClientData(ClientDataTypeGet, challenge, testOrigin),
+			attestation("none", map[any]any{}, validAuthData),
+			true,
+		},
+		{
+			"invalid client data challenge",
+			es256,
+			testClientData(ClientDataTypeCreate, "other", testOrigin),
+			attestation("none", map[any]any{}, validAuthData),
+			true,
+		},
+		{
+			"invalid client data origin",
+			es256,
+			testClientData(ClientDataTypeCreate, challenge, "https://evil.com"),
+			attestation("none", map[any]any{}, validAuthData),
+			true,
+		},
+		{
+			"invalid relying party id",
+			es256,
+			validClientData,
+			attestation("none", map[any]any{}, es256.authData("evil.com", validFlags, 0, true)),
+			true,
+		},
+		{
+			"missing user verification flag",
+			es256,
+			validClientData,
+			attestation("none", map[any]any{}, es256.authData(testRpId, flagUserPresent|flagAttestedCredential, 0, true)),
+			true,
+		},
+		{
+			"missing attested credential data",
+			es256,
+			validClientData,
+			attestation("none", map[any]any{}, es256.authData(testRpId, flagUserPresent|flagUserVerified, 0, false)),
+			true,
+		},
+		{
+			"unsupported attestation format",
+			es256,
+			validClientData,
+			attestation("tpm", map[any]any{}, validAuthData),
+			true,
+		},
+		{
+			"none attestation with statement",
+			es256,
+			validClientData,
+			attestation("none", map[any]any{"alg": AlgES256}, validAuthData),
+			true,
+		},
+		{
+			"packed self attestation with invalid signature",
+			es256,
+			validClientData,
+			attestation("packed", map[any]any{"alg": AlgES256, "sig": []byte("invalid")}, validAuthData),
+			true,
+		},
+		{
+			"packed self attestation with algorithm mismatch",
+			es256,
+			validClientData,
+			attestation("packed", map[any]any{
+				"alg": AlgRS256,
+				"sig": es256.sign(t, validAuthData, validClientData),
+			}, validAuthData),
+			true,
+		},
+		{
+			"valid none attestation (ES256)",
+			es256,
+			validClientData,
+			attestation("none", map[any]any{}, validAuthData),
+			false,
+		},
+		{
+			"valid none attestation (EdDSA)",
+			eddsa,
+			validClientData,
+			attestation("none", map[any]any{}, eddsa.authData(testRpId, validFlags, 0, true)),
+			false,
+		},
+		{
+			"valid packed self attestation",
+			es256,
+			validClientData,
+			attestation("packed", map[any]any{
+				"alg": AlgES256,
+				"sig": es256.sign(t, validAuthData, validClientData),
+			}, validAuthData),
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		credential, err := rp.VerifyRegistration(challenge, s.clientData, s.attestationObject)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if hasErr {
+			continue
+		}
+
+		if string(credential.Id) != string(s.authenticator.credentialId) {
+			t.Errorf("[%s] Expected credential id %q, got %q", s.name, s.authenticator.credentialId, credential.Id)
+		}
+
+		if credential.Algorithm != s.authenticator.alg {
+			t.Errorf("[%s] Expected algorithm %d, got %d", s.name, s.authenticator.alg, credential.Algorithm)
+		}
+
+		if len(credential.PublicKey) == 0 {
+			t.Errorf("[%s] Expected non-empty public key", s.name)
+		}
+	}
+}
+
+func TestVerifyAssertion(t *testing.T) {
+	rp := testRelyingParty()
+	authenticator := newTestAuthenticator(t, AlgES256)
+	challenge := "test_challenge"
+
+	// register the credential
+	regClientData := testClientData(ClientDataTypeCreate, challenge, testOrigin)
+	credential, err := rp.VerifyRegistration(challenge, regClientData, testEncodeCbor(map[any]any{
+		"fmt":      "none",
+		"attStmt":  map[any]any{},
+		"authData": authenticator.authData(testRpId, flagUserPresent|flagUserVerified|flagAttestedCredential, 5, true),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validFlags := flagUserPresent | flagUserVerified
+	validClientData := testClientData(ClientDataTypeGet, challenge, testOrigin)
+
+	scenarios := []struct {
+		name              string
+		clientData        []byte
+		authData          []byte
+		signature         []byte
+		expectError       bool
+		expectedSignCount uint32
+	}{
+		{
+			name:        "invalid client data type",
+			clientData:  regClientData,
+			authData:    authenticator.authData(testRpId, validFlags, 6, false),
+			expectError: true,
+		},
+		{
+			name:        "invalid signature",
+			clientData:  validClientData,
+			authData:    authenticator.authData(testRpId, validFlags, 6, false),
+			signature:   []byte("invalid"),
+			expectError: true,
+		},
+		{
+			name:        "signature of different data",
+			clientData:  validClientData,
+			authData:    authenticator.authData(testRpId, validFlags, 6, false),
+			signature:   authenticator.sign(t, authenticator.authData(testRpId, validFlags, 7, false), validClientData),
+			expectError: true,
+		},
+		{
+			name:        "not increased sign counter",
+			clientData:  validClientData,
+			authData:    authenticator.authData(testRpId, validFlags, 5, false),
+			expectError: true,
+		},
+		{
+			name:        "missing user presence",
+			clientData:  validClientData,
+			authData:    authenticator.authData(testRpId, flagUserVerified, 6, false),
+			expectError: true,
+		},
+		{
+			name:              "valid assertion",
+			clientData:        validClientData,
+			authData:          authenticator.authData(testRpId, validFlags, 6, false),
+			expectedSignCount: 6,
+		},
+	}
+
+	for _, s := range scenarios {
+		signature := s.signature
+		if signature == nil {
+			signature = authenticator.sign(t, s.authData, s.clientData)
+		}
+
+		signCount, err := rp.VerifyAssertion(credential, challenge, s.clientData, s.authData, signature)
+
+		hasErr := err != nil
+		if hasErr != s.expectError {
+			t.Errorf("[%s] Expected hasErr %v, got %v (%v)", s.name, s.expectError, hasErr, err)
+			continue
+		}
+
+		if !hasErr && signCount != s.expectedSignCount {
+			t.Errorf("[%s] Expected sign count %d, got %d", s.name, s.expectedSignCount, signCount)
+		}
+	}
+}
+
+func TestVerifyClientDataChallengePadding(t *testing.T) {
+	rp := testRelyingParty()
+
+	err := rp.verifyClientData(testClientData(ClientDataTypeGet, "dGVzdA", testOrigin), ClientDataTypeGet, "dGVzdA==")
+	if err != nil {
+		t.Fatalf("Expected the padded challenge to match, got %v", err)
+	}
+
+	err = rp.verifyClientData(testClientData(ClientDataTypeGet, "", testOrigin), ClientDataTypeGet, "")
+	if err == nil || !strings.Contains(err.Error(), "challenge") {
+		t.Fatalf("Expected empty challenge error, got %v", err)
+	}
+}
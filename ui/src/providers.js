@@ -1,5 +1,6 @@
 import SelfHostedOptions from "@/components/settings/providers/SelfHostedOptions.svelte";
 import MicrosoftOptions  from "@/components/settings/providers/MicrosoftOptions.svelte";
+import OIDCOptions       from "@/components/settings/providers/OIDCOptions.svelte";
 
 // Object list with all supported OAuth2 providers in the format:
 // ```
@@ -60,4 +61,13 @@ export default {
         title: "Gitee",
         icon:  "ri-git-repository-fill",
     },
+    oidcAuth: {
+        title: "OpenID Connect",
+        icon:  "ri-openid-fill",
+        optionsComponent: OIDCOptions,
+    },
+    appleAuth: {
+        title: "Apple",
+        icon:  "ri-apple-fill",
+    },
 };